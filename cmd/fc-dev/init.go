@@ -29,7 +29,6 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/seed"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/database"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
@@ -243,24 +242,11 @@ func runInit(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
-	// Client secrets are stored reversibly-encrypted (Rust parity:
-	// client_secret_ref), so an app key must exist. Generate + persist one
-	// when absent so a fresh dev box works without manual setup.
-	appKey := os.Getenv("FLOWCATALYST_APP_KEY")
-	if appKey == "" {
-		appKey, err = encryption.GenerateKey()
-		if err != nil {
-			return fmt.Errorf("generate app key: %w", err)
-		}
-		_ = os.Setenv("FLOWCATALYST_APP_KEY", appKey)
-	}
-	enc, err := encryption.FromEnv()
-	if err != nil {
-		return fmt.Errorf("init encryption: %w", err)
-	}
-	secretRef, err := enc.Encrypt(clientSecretPlain)
+	// Only the argon2id hash is stored; the plaintext goes to .env below
+	// and is never recoverable from the database.
+	secretRef, err := passwordhash.Hash(clientSecretPlain)
 	if err != nil {
-		return fmt.Errorf("encrypt client secret: %w", err)
+		return fmt.Errorf("hash client secret: %w", err)
 	}
 	oauthClient := auth.NewOAuthClient(publicClientID, appName+" Service Account Client", auth.OAuthClientConfidential)
 	oauthClient.SecretRef = &secretRef
@@ -292,9 +278,6 @@ func runInit(cmd *cobra.Command, _ []string) error {
 		{"FLOWCATALYST_APP_CODE", appCode},
 		{"FLOWCATALYST_CLIENT_ID", publicClientID},
 		{"FLOWCATALYST_CLIENT_SECRET", clientSecretPlain},
-		// Persist the app key so the same encrypted client_secret_ref stays
-		// decryptable across restarts (the /oauth/token verify path reads it).
-		{"FLOWCATALYST_APP_KEY", appKey},
 	}
 	if err := writeEnvUpdates(envPath, updates); err != nil {
 		return fmt.Errorf("write .env: %w", err)
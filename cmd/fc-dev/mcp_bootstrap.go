@@ -12,6 +12,7 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/mcp"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
@@ -31,8 +32,8 @@ const mcpLocalClientID = "flowcatalyst-mcp-local"
 // ~/Library/Caches, Linux ~/.cache; see mcp.CredentialsPath), mode 0600.
 //
 // Dev-only: it is called only from `fc-dev start`, which is the dev monolith.
-// Requires a stable FLOWCATALYST_APP_KEY (ensured by start.go) so the stored
-// encrypted secret stays decryptable at /oauth/token across restarts.
+// Only the argon2id hash of the secret is stored; /oauth/token verifies
+// against it.
 func bootstrapMCPCredentials(ctx context.Context, pool *pgxpool.Pool, baseURL string) error {
 	authRepo := auth.NewRepository(pool)
 
@@ -46,21 +47,13 @@ func bootstrapMCPCredentials(ctx context.Context, pool *pgxpool.Pool, baseURL st
 		return nil
 	}
 
-	enc, err := encryption.FromEnv()
-	if err != nil {
-		return fmt.Errorf("init encryption: %w", err)
-	}
-	if enc == nil {
-		return fmt.Errorf("FLOWCATALYST_APP_KEY not set; cannot encrypt MCP client secret")
-	}
-
 	secret, err := generateSecret()
 	if err != nil {
 		return err
 	}
-	secretRef, err := enc.Encrypt(secret)
+	secretRef, err := passwordhash.Hash(secret)
 	if err != nil {
-		return fmt.Errorf("encrypt MCP client secret: %w", err)
+		return fmt.Errorf("hash MCP client secret: %w", err)
 	}
 
 	sa := serviceaccount.New("mcp:local", "fc-mcp local")
@@ -110,9 +103,8 @@ func bootstrapMCPCredentials(ctx context.Context, pool *pgxpool.Pool, baseURL st
 }
 
 // ensureAppKeyFile reads, or generates and persists (0600), the field-encryption
-// key (FLOWCATALYST_APP_KEY) so OAuth client secrets — including the bootstrapped
-// MCP client — stay decryptable across restarts. Analogous to the JWT signing
-// key file. fc-server requires operators to supply the key via env instead.
+// key (FLOWCATALYST_APP_KEY) so encrypted fields stay decryptable across
+// restarts. Analogous to the JWT signing key file. fc-server requires operators to supply the key via env instead.
 func ensureAppKeyFile(path string) (string, error) {
 	if b, err := os.ReadFile(path); err == nil {
 		if k := strings.TrimSpace(string(b)); k != "" {
@@ -12,6 +12,7 @@ import (
 	gomysql "github.com/go-sql-driver/mysql"
 	"github.com/spf13/cobra"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
 	outboxmongo "github.com/flowcatalyst/flowcatalyst-go/internal/outbox/mongo"
 	outboxpg "github.com/flowcatalyst/flowcatalyst-go/internal/outbox/postgres"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/database"
@@ -144,7 +145,7 @@ func createOutboxMySQL(ctx context.Context, out io.Writer, dbURL string) error {
 }
 
 func createOutboxMongo(ctx context.Context, out io.Writer, uri, dbName string) error {
-	repo, err := outboxmongo.Connect(ctx, uri, dbName)
+	repo, err := outboxmongo.Connect(ctx, uri, dbName, mongoconn.ConfigFromEnv())
 	if err != nil {
 		return fmt.Errorf("connect mongodb: %w", err)
 	}
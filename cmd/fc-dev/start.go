@@ -24,12 +24,12 @@ import (
 // startOpts captures the flag set for `fc-dev start`. Defaults match
 // the Rust fc-dev so existing dev workflows transfer 1:1.
 type startOpts struct {
-	APIPort          int
-	MetricsPort      int
-	EmbeddedDB       bool
-	EmbeddedDBPort   int
-	EmbeddedDBPath   string
-	EmbeddedDBReset  bool
+	APIPort             int
+	MetricsPort         int
+	EmbeddedDB          bool
+	EmbeddedDBPort      int
+	EmbeddedDBPath      string
+	EmbeddedDBReset     bool
 	DatabaseURL         string
 	SchedulerEnabled    bool
 	ScheduledJobEnabled bool
@@ -71,12 +71,12 @@ func optsFromFlags(cmd *cobra.Command) startOpts {
 	getStr := func(k string) string { v, _ := cmd.Flags().GetString(k); return v }
 	getBool := func(k string) bool { v, _ := cmd.Flags().GetBool(k); return v }
 	return startOpts{
-		APIPort:          getInt("api-port"),
-		MetricsPort:      getInt("metrics-port"),
-		EmbeddedDB:       getBool("embedded-db"),
-		EmbeddedDBPort:   getInt("embedded-db-port"),
-		EmbeddedDBPath:   getStr("embedded-db-path"),
-		EmbeddedDBReset:  getBool("embedded-db-reset"),
+		APIPort:             getInt("api-port"),
+		MetricsPort:         getInt("metrics-port"),
+		EmbeddedDB:          getBool("embedded-db"),
+		EmbeddedDBPort:      getInt("embedded-db-port"),
+		EmbeddedDBPath:      getStr("embedded-db-path"),
+		EmbeddedDBReset:     getBool("embedded-db-reset"),
 		DatabaseURL:         getStr("database-url"),
 		SchedulerEnabled:    getBool("scheduler"),
 		ScheduledJobEnabled: getBool("scheduled-job"),
@@ -154,6 +154,10 @@ func runStart(cmd *cobra.Command, _ []string) error {
 	setEnvDefault(seed.EnvBootstrapPassword, "DevPassword123!")
 	setEnvDefault(seed.EnvBootstrapName, "Local Admin")
 
+	// Local webhook receivers live on localhost; fc-server refuses private
+	// targets unless the operator opts in.
+	setEnvDefault("FC_EGRESS_ALLOW_PRIVATE", "true")
+
 	// Ensure a persistent JWT signing key exists so tokens survive a
 	// restart. fc-dev stores it under ~/.flowcatalyst/jwt-signing-key.pem
 	// (0600). fc-server requires operators to supply one via env.
@@ -166,15 +170,15 @@ func runStart(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
-	// Persist the field-encryption key so OAuth client secrets (incl. the
-	// bootstrapped MCP client) stay decryptable across restarts. fc-server
-	// requires operators to supply FLOWCATALYST_APP_KEY via env.
+	// Persist the field-encryption key so encrypted fields (target auth
+	// credentials, ingestion secrets) stay decryptable across restarts.
+	// fc-server requires operators to supply FLOWCATALYST_APP_KEY via env.
 	if os.Getenv("FLOWCATALYST_APP_KEY") == "" {
 		keyPath := filepath.Join(filepath.Dir(opts.EmbeddedDBPath), "app-key")
 		if key, err := ensureAppKeyFile(keyPath); err == nil {
 			_ = os.Setenv("FLOWCATALYST_APP_KEY", key)
 		} else {
-			slog.Warn("unable to persist app encryption key — OAuth client secrets won't survive restart", "err", err)
+			slog.Warn("unable to persist app encryption key — encrypted fields won't survive restart", "err", err)
 		}
 	}
 
@@ -6,11 +6,24 @@
 // contract. fc-dev wraps the same `server.Run` orchestrator with
 // embedded-Postgres + dev defaults for local work.
 //
+// Flags:
+//
+//	--config <file>   YAML file of VARIABLE: value pairs layered under the
+//	                  environment (also FC_CONFIG_FILE); real env vars win.
+//	--print-config    print the effective configuration, secrets
+//	                  redacted, and exit.
+//
+// SIGHUP re-reads the config file and applies the settings that can change
+// at runtime (log level, router timeouts and config sync interval, rate
+// limits) without a restart.
+//
 // See internal/server/envcfg.go for the full env-var list.
 package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -28,8 +41,47 @@ import (
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("FC_CONFIG_FILE"), "YAML config file layered under the environment")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration (secrets redacted) and exit")
+	flag.Parse()
+
+	// The file has to be in the environment before anything reads it —
+	// logging.Init included.
+	var file *server.ConfigFile
+	if *configPath != "" {
+		f, err := server.ReadConfigFile(*configPath)
+		if err == nil {
+			err = f.Apply()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		file = f
+	}
+
 	logging.Init()
 	cfg := server.LoadEnv()
+	invalid := cfg.Validate()
+
+	if *printConfig {
+		if err := server.PrintConfig(os.Stdout, cfg, file); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if invalid != nil {
+			fmt.Fprintln(os.Stderr, invalid)
+			os.Exit(1)
+		}
+		return
+	}
+	if invalid != nil {
+		slog.Error("configuration rejected", "err", invalid)
+		os.Exit(1)
+	}
+	if file != nil {
+		slog.Info("config file loaded", "path", file.Path, "keys", len(file.Values))
+	}
 
 	slog.Info("starting fc-server",
 		"platform", cfg.PlatformEnabled,
@@ -121,7 +173,7 @@ func main() {
 	// "/" redirects to an OIDC flow that instance can't satisfy; the router's own
 	// UI/API lives under the /router prefix (basic-auth). No-op when dist wasn't
 	// embedded.
-	runOpts := server.RunOptions{}
+	runOpts := server.RunOptions{Reload: reloadOnSIGHUP(file)}
 	if cfg.PlatformEnabled && frontend.IsAvailable() {
 		runOpts.Fallback = frontend.Handler()
 		slog.Info("embedded Vue SPA available")
@@ -132,3 +184,28 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// reloadOnSIGHUP re-reads file (when there is one) on every SIGHUP and
+// signals server.Run to apply it. A file that no longer parses is logged
+// and leaves the running settings alone.
+func reloadOnSIGHUP(file *server.ConfigFile) <-chan struct{} {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	reload := make(chan struct{}, 1)
+	go func() {
+		for range hup {
+			slog.Info("SIGHUP received; reloading configuration")
+			if file != nil {
+				if err := file.Reload(); err != nil {
+					slog.Error("config reload rejected; keeping the running settings", "err", err)
+					continue
+				}
+			}
+			select {
+			case reload <- struct{}{}:
+			default: // a reload is already pending
+			}
+		}
+	}()
+	return reload
+}
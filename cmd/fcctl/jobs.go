@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/client"
+)
+
+// failedStatus is the terminal status of a dispatch job that ran out of
+// retries — the platform's dead-letter queue.
+const failedStatus = "FAILED"
+
+// listPageSize is the server's maximum dispatch-job page.
+const listPageSize = 1000
+
+// requeueBatchSize bounds one requeue call's id list.
+const requeueBatchSize = 500
+
+// failedJobFilterFlags adds the filters shared by jobs tail and dlq replay.
+func failedJobFilterFlags(cmd *cobra.Command, since time.Duration) {
+	cmd.Flags().String("subscription", "", "only jobs of this subscription id")
+	cmd.Flags().String("client", "", "only jobs of this client id")
+	cmd.Flags().String("code", "", "only jobs for this event type code")
+	cmd.Flags().Duration("since", since, "only jobs created within this window")
+}
+
+func failedJobFilters(cmd *cobra.Command) *client.DispatchJobFilters {
+	f := &client.DispatchJobFilters{Status: failedStatus}
+	f.SubscriptionID, _ = cmd.Flags().GetString("subscription")
+	f.ClientID, _ = cmd.Flags().GetString("client")
+	f.Code, _ = cmd.Flags().GetString("code")
+	since, _ := cmd.Flags().GetDuration("since")
+	f.Since = time.Now().Add(-since).UTC().Format(time.RFC3339)
+	return f
+}
+
+func newJobsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "jobs", Short: "Inspect dispatch jobs"}
+
+	tail := &cobra.Command{
+		Use:   "tail",
+		Short: "Show failed dispatch jobs, optionally following new failures",
+		Long: `Prints the most recent FAILED dispatch jobs, oldest first, as
+UPDATED  ID  STATUS  CODE  ATTEMPTS  SUBSCRIPTION. With --follow it keeps
+polling and prints each newly failed job once.`,
+		Args: cobra.NoArgs,
+		RunE: runJobsTail,
+	}
+	failedJobFilterFlags(tail, time.Hour)
+	tail.Flags().IntP("lines", "n", 20, "failures to show before following")
+	tail.Flags().BoolP("follow", "f", false, "keep polling for new failures")
+	tail.Flags().Duration("interval", 5*time.Second, "poll interval with --follow")
+
+	cmd.AddCommand(tail)
+	return cmd
+}
+
+func runJobsTail(cmd *cobra.Command, _ []string) error {
+	lines, _ := cmd.Flags().GetInt("lines")
+	follow, _ := cmd.Flags().GetBool("follow")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return errors.New("--interval must be > 0")
+	}
+	c, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	filters := failedJobFilters(cmd)
+	filters.Limit = max(lines, 1)
+	jobs, err := c.DispatchJobs().List(ctx, filters)
+	if err != nil {
+		return err
+	}
+	// The list is newest first; print oldest first, like tail.
+	slices.Reverse(jobs)
+	if err := printJobs(cmd, jobs); err != nil {
+		return err
+	}
+	if !follow {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		seen[j.ID] = true
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		filters := failedJobFilters(cmd)
+		filters.Limit = listPageSize
+		jobs, err := c.DispatchJobs().List(ctx, filters)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		var fresh []client.DispatchJobResponse
+		for _, j := range jobs {
+			if !seen[j.ID] {
+				seen[j.ID] = true
+				fresh = append(fresh, j)
+			}
+		}
+		slices.Reverse(fresh)
+		if err := printJobs(cmd, fresh); err != nil {
+			return err
+		}
+	}
+}
+
+func newDLQCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "dlq", Short: "Work the dead-letter queue (FAILED dispatch jobs)"}
+
+	replay := &cobra.Command{
+		Use:   "replay [job-id...]",
+		Short: "Re-dispatch failed jobs",
+		Long: `Resets FAILED dispatch jobs to PENDING so the scheduler dispatches them
+again. Either name the jobs, or select them with the filters; with filters
+the matching jobs are counted and confirmed first.`,
+		RunE: runDLQReplay,
+	}
+	failedJobFilterFlags(replay, 24*time.Hour)
+	replay.Flags().Int("max", 10000, "refuse to replay more jobs than this")
+	replay.Flags().Bool("dry-run", false, "only count the jobs that would be replayed")
+	replay.Flags().Bool("yes", false, "skip the confirmation prompt")
+
+	cmd.AddCommand(replay)
+	return cmd
+}
+
+func runDLQReplay(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("max")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+	c, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+
+	ids := args
+	if len(ids) == 0 {
+		// Collect every match before requeueing: requeued jobs leave the
+		// FAILED set, which would shift the pages under an offset scan.
+		filters := failedJobFilters(cmd)
+		filters.Limit = listPageSize
+		for {
+			page, err := c.DispatchJobs().List(ctx, filters)
+			if err != nil {
+				return err
+			}
+			for _, j := range page {
+				ids = append(ids, j.ID)
+			}
+			if len(ids) > limit {
+				return fmt.Errorf("more than %d failed jobs match; narrow the filters or raise --max", limit)
+			}
+			if len(page) < listPageSize {
+				break
+			}
+			filters.Offset += listPageSize
+		}
+	}
+	if len(ids) == 0 {
+		fmt.Fprintln(out, "No failed jobs match")
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(out, "%d failed job(s) would be replayed\n", len(ids))
+		return nil
+	}
+	if !yes && !confirm(cmd, fmt.Sprintf("Replay %d failed job(s)?", len(ids))) {
+		return errors.New("aborted")
+	}
+
+	var requeued int64
+	for batch := range slices.Chunk(ids, requeueBatchSize) {
+		res, err := c.DispatchJobs().Requeue(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("after %d requeued: %w", requeued, err)
+		}
+		requeued += res.Requeued
+	}
+	fmt.Fprintf(out, "Requeued %d of %d job(s)\n", requeued, len(ids))
+	return nil
+}
+
+func confirm(cmd *cobra.Command, prompt string) bool {
+	fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N] ", prompt)
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(line), "y")
+}
@@ -0,0 +1,135 @@
+// Command fcctl drives the common platform administration flows through
+// the platform API.
+//
+//	fcctl login
+//	fcctl clients create --identifier acme --name "Acme Corp"
+//	fcctl subscriptions create --code orders-hook --name "Orders" --endpoint https://... --event-type orders:*:*:*
+//	fcctl subscriptions pause sub_0HZ... sub_0J1...
+//	fcctl jobs tail --follow
+//	fcctl dlq replay --subscription sub_0HZ... --since 24h
+//	fcctl projections rebuild --projection dispatch_job_projection
+//
+// Authentication, first match wins: --token / FC_TOKEN (a bearer token),
+// --client-id + --client-secret / FC_CLIENT_ID + FC_CLIENT_SECRET (a
+// service account's credentials, client_credentials grant), else the
+// credentials saved by `fcctl login` (OAuth device flow). login needs a
+// public OAuth client allowed the device_code grant — "fcctl" unless
+// --device-client-id says otherwise.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/client"
+)
+
+func main() {
+	logging.Init()
+
+	root := &cobra.Command{
+		Use:   "fcctl",
+		Short: "Administer a FlowCatalyst platform",
+		// API errors are reported by main; the usage dump would bury them.
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	f := root.PersistentFlags()
+	f.String("url", envOr("FC_URL", "http://localhost:8080"), "platform base URL (env FC_URL)")
+	f.String("router-url", os.Getenv("FC_ROUTER_URL"), "message-router base URL, when not co-located (env FC_ROUTER_URL)")
+	f.String("token", os.Getenv("FC_TOKEN"), "bearer token (env FC_TOKEN)")
+	f.String("client-id", os.Getenv("FC_CLIENT_ID"), "service-account client id (env FC_CLIENT_ID)")
+	f.String("client-secret", os.Getenv("FC_CLIENT_SECRET"), "service-account client secret (env FC_CLIENT_SECRET)")
+	f.String("device-client-id", envOr("FC_DEVICE_CLIENT_ID", "fcctl"), "OAuth client used by login (env FC_DEVICE_CLIENT_ID)")
+	f.Bool("json", false, "print raw JSON instead of tables")
+
+	root.AddCommand(newLoginCmd(), newLogoutCmd())
+	root.AddCommand(newClientsCmd())
+	root.AddCommand(newSubscriptionsCmd())
+	root.AddCommand(newJobsCmd())
+	root.AddCommand(newDLQCmd())
+	root.AddCommand(newProjectionsCmd())
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := root.ExecuteContext(ctx)
+	cancel()
+	if err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.IsUnauthorized() {
+			err = fmt.Errorf("%w (run `fcctl login`, or pass --token / --client-id)", err)
+		}
+		slog.Error("fcctl failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func baseURL(cmd *cobra.Command) string {
+	u, _ := cmd.Flags().GetString("url")
+	return strings.TrimRight(u, "/")
+}
+
+// newClient builds the platform API client with the first configured
+// credential: static token, service-account credentials, or the saved
+// device-flow login.
+func newClient(cmd *cobra.Command) (*client.FlowCatalystClient, error) {
+	base := baseURL(cmd)
+	opts := []client.Option{}
+	if u, _ := cmd.Flags().GetString("router-url"); u != "" {
+		opts = append(opts, client.WithRouterBaseURL(u))
+	}
+
+	token, _ := cmd.Flags().GetString("token")
+	clientID, _ := cmd.Flags().GetString("client-id")
+	clientSecret, _ := cmd.Flags().GetString("client-secret")
+	switch {
+	case token != "":
+		opts = append(opts, client.WithToken(token))
+	case clientID != "" && clientSecret != "":
+		cc := auth.NewClientCredentialsProvider(auth.ClientCredentialsConfig{
+			IssuerURL:    base,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+		})
+		opts = append(opts, client.WithTokenProvider(cc.Token))
+	case clientID != "" || clientSecret != "":
+		return nil, errors.New("--client-id and --client-secret must be given together")
+	default:
+		src, err := loadSession(base, deviceOAuthClient(cmd))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithTokenProvider(src.Token))
+	}
+	return client.New(base, opts...), nil
+}
+
+// printJSON writes v indented. Used for --json and for single results
+// that have no table form.
+func printJSON(cmd *cobra.Command, v any) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func jsonOutput(cmd *cobra.Command) bool {
+	v, _ := cmd.Flags().GetBool("json")
+	return v
+}
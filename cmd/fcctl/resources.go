@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/client"
+)
+
+// ─── clients ─────────────────────────────────────────────────────────
+
+func newClientsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "clients", Short: "Manage clients (tenants)"}
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a client",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			identifier, _ := cmd.Flags().GetString("identifier")
+			name, _ := cmd.Flags().GetString("name")
+			c, err := newClient(cmd)
+			if err != nil {
+				return err
+			}
+			res, err := c.Clients().Create(cmd.Context(), &client.CreateClientRequest{Identifier: identifier, Name: name})
+			if err != nil {
+				return err
+			}
+			if jsonOutput(cmd) {
+				return printJSON(cmd, res)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created client %s (%s)\n", res.ID, identifier)
+			return nil
+		},
+	}
+	create.Flags().String("identifier", "", "unique client identifier (slug)")
+	create.Flags().String("name", "", "display name")
+	_ = create.MarkFlagRequired("identifier")
+	_ = create.MarkFlagRequired("name")
+
+	cmd.AddCommand(create)
+	return cmd
+}
+
+// ─── subscriptions ───────────────────────────────────────────────────
+
+func newSubscriptionsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "subscriptions", Aliases: []string{"subs"}, Short: "Manage subscriptions"}
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a subscription",
+		Long: `Creates a subscription delivering the given event types to an endpoint.
+--event-type is repeatable and takes a code or pattern (segments may be *),
+optionally followed by "=<filter expression>".`,
+		Args: cobra.NoArgs,
+		RunE: runSubscriptionCreate,
+	}
+	f := create.Flags()
+	f.String("code", "", "subscription code")
+	f.String("name", "", "display name")
+	f.String("description", "", "description")
+	f.String("endpoint", "", "target URL")
+	f.StringArray("event-type", nil, "event type code or pattern, optionally code=filter (repeatable)")
+	f.String("client", "", "client id (omit for an anchor-level subscription)")
+	f.String("dispatch-pool", "", "dispatch pool id")
+	f.String("connection", "", "connection id")
+	f.String("mode", "", "dispatch mode")
+	_ = create.MarkFlagRequired("code")
+	_ = create.MarkFlagRequired("name")
+	_ = create.MarkFlagRequired("endpoint")
+	_ = create.MarkFlagRequired("event-type")
+
+	pause := &cobra.Command{
+		Use:   "pause <id>...",
+		Short: "Pause subscriptions (events keep being recorded, nothing is dispatched)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setSubscriptionsPaused(cmd, args, true)
+		},
+	}
+	resume := &cobra.Command{
+		Use:   "resume <id>...",
+		Short: "Resume paused subscriptions",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setSubscriptionsPaused(cmd, args, false)
+		},
+	}
+
+	cmd.AddCommand(create, pause, resume)
+	return cmd
+}
+
+func runSubscriptionCreate(cmd *cobra.Command, _ []string) error {
+	f := cmd.Flags()
+	req := &client.CreateSubscriptionRequest{}
+	req.Code, _ = f.GetString("code")
+	req.Name, _ = f.GetString("name")
+	req.Description, _ = f.GetString("description")
+	req.Endpoint, _ = f.GetString("endpoint")
+	req.ClientID, _ = f.GetString("client")
+	req.DispatchPoolID, _ = f.GetString("dispatch-pool")
+	req.ConnectionID, _ = f.GetString("connection")
+	req.Mode, _ = f.GetString("mode")
+	eventTypes, _ := f.GetStringArray("event-type")
+	for _, et := range eventTypes {
+		code, filter, _ := strings.Cut(et, "=")
+		if code == "" {
+			return fmt.Errorf("--event-type %q: missing event type code", et)
+		}
+		req.EventTypes = append(req.EventTypes, client.EventTypeBinding{EventTypeCode: code, Filter: filter})
+	}
+
+	c, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	sub, err := c.Subscriptions().Create(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
+	if jsonOutput(cmd) {
+		return printJSON(cmd, sub)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Created subscription %s (%s, %s)\n", sub.ID, sub.Code, sub.Status)
+	return nil
+}
+
+// setSubscriptionsPaused pauses or resumes each id, carrying on past
+// failures so one bad id doesn't leave the rest untouched.
+func setSubscriptionsPaused(cmd *cobra.Command, ids []string, pause bool) error {
+	c, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	verb, call := "resumed", c.Subscriptions().Resume
+	if pause {
+		verb, call = "paused", c.Subscriptions().Pause
+	}
+	var errs []error
+	for _, id := range ids {
+		if err := call(cmd.Context(), id); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", id, verb)
+	}
+	return errors.Join(errs...)
+}
+
+// ─── projections ─────────────────────────────────────────────────────
+
+func newProjectionsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "projections", Short: "Manage stream processor projections"}
+
+	rebuild := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild a projection's read table from its source table",
+		Long: `Asks the router's co-tenanted stream processor to clear the projection's
+read table and replay every source row. The read table is incomplete until
+the replay finishes. For offline repair against the database directly, use
+streamctl.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			projection, _ := cmd.Flags().GetString("projection")
+			yes, _ := cmd.Flags().GetBool("yes")
+			if !yes && !confirm(cmd, "Truncate and rebuild "+projection+"?") {
+				return errors.New("aborted")
+			}
+			c, err := newClient(cmd)
+			if err != nil {
+				return err
+			}
+			res, err := c.Router().RebuildProjection(cmd.Context(), projection)
+			if err != nil {
+				return err
+			}
+			if jsonOutput(cmd) {
+				return printJSON(cmd, res)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: read table cleared, %d source rows re-queued\n", res.Projection, res.Requeued)
+			return nil
+		},
+	}
+	rebuild.Flags().String("projection", "", "projection to rebuild, e.g. event_projection or dispatch_job_projection")
+	rebuild.Flags().Bool("yes", false, "skip the confirmation prompt")
+	_ = rebuild.MarkFlagRequired("projection")
+
+	cmd.AddCommand(rebuild)
+	return cmd
+}
+
+// printJobs renders dispatch jobs as a table (or JSON with --json).
+func printJobs(cmd *cobra.Command, jobs []client.DispatchJobResponse) error {
+	if jsonOutput(cmd) {
+		return printJSON(cmd, jobs)
+	}
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, j := range jobs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", j.UpdatedAt, j.ID, j.Status, j.Code, j.AttemptCount, j.SubscriptionID)
+	}
+	return tw.Flush()
+}
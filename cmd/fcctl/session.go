@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/auth"
+)
+
+// deviceScopes asks for a refresh token so a login outlives the one-hour
+// access token.
+var deviceScopes = []string{"openid", "offline_access"}
+
+// tokenRefreshSkew refreshes this long before the access token expires,
+// so it never lapses between the check and the call.
+const tokenRefreshSkew = 60 * time.Second
+
+// storedSession is one platform's saved login.
+type storedSession struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// credentialsPath is $XDG_CONFIG_HOME/fcctl/credentials.json (or the OS
+// equivalent). The file maps platform base URL → storedSession, so one
+// machine can be logged in to several environments.
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate config dir: %w", err)
+	}
+	return filepath.Join(dir, "fcctl", "credentials.json"), nil
+}
+
+func readSessions() (map[string]storedSession, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]storedSession{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sessions := map[string]storedSession{}
+	if err := json.Unmarshal(raw, &sessions); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return sessions, nil
+}
+
+// writeSessions replaces the credentials file, owner-readable only.
+func writeSessions(sessions map[string]storedSession) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func saveSession(base string, tok *auth.TokenResponse) error {
+	sessions, err := readSessions()
+	if err != nil {
+		return err
+	}
+	sessions[base] = storedSession{
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second),
+	}
+	return writeSessions(sessions)
+}
+
+// sessionTokenSource serves the saved access token, refreshing it (and
+// persisting the rotated refresh token) when it is about to expire.
+type sessionTokenSource struct {
+	base  string
+	oauth *auth.OAuthClient
+
+	mu      sync.Mutex
+	session storedSession
+}
+
+func loadSession(base string, oauth *auth.OAuthClient) (*sessionTokenSource, error) {
+	sessions, err := readSessions()
+	if err != nil {
+		return nil, err
+	}
+	s, ok := sessions[base]
+	if !ok {
+		return nil, fmt.Errorf("not logged in to %s: run `fcctl login`, or pass --token / --client-id", base)
+	}
+	return &sessionTokenSource{base: base, oauth: oauth, session: s}, nil
+}
+
+// Token implements client.TokenProvider.
+func (s *sessionTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Until(s.session.ExpiresAt) > tokenRefreshSkew {
+		return s.session.AccessToken, nil
+	}
+	if s.session.RefreshToken == "" {
+		return "", fmt.Errorf("login to %s has expired: run `fcctl login`", s.base)
+	}
+	tok, err := s.oauth.RefreshToken(ctx, s.session.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("refresh login (run `fcctl login` if it was revoked): %w", err)
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = s.session.RefreshToken
+	}
+	if err := saveSession(s.base, tok); err != nil {
+		return "", fmt.Errorf("save refreshed login: %w", err)
+	}
+	s.session = storedSession{
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second),
+	}
+	return s.session.AccessToken, nil
+}
+
+func deviceOAuthClient(cmd *cobra.Command) *auth.OAuthClient {
+	clientID, _ := cmd.Flags().GetString("device-client-id")
+	return auth.NewOAuthClient(auth.OAuthConfig{
+		IssuerURL: baseURL(cmd),
+		ClientID:  clientID,
+		Scopes:    deviceScopes,
+	})
+}
+
+func newLoginCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "login",
+		Short: "Sign in with the OAuth device flow and save the session",
+		Long: `Starts an OAuth device authorization, prints the verification URL and code
+to enter in a browser, and waits for approval. The resulting tokens are
+saved per platform URL and refreshed automatically.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			oauth := deviceOAuthClient(cmd)
+			da, err := oauth.StartDeviceAuthorization(cmd.Context())
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Open %s and enter the code %s\n", da.VerificationURI, da.UserCode)
+			if da.VerificationURIComplete != "" {
+				fmt.Fprintf(out, "  (or go straight to %s)\n", da.VerificationURIComplete)
+			}
+			fmt.Fprintln(out, "Waiting for approval...")
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), time.Duration(da.ExpiresIn)*time.Second)
+			defer cancel()
+			tok, err := oauth.PollDeviceToken(ctx, da)
+			if err != nil {
+				return err
+			}
+			if err := saveSession(baseURL(cmd), tok); err != nil {
+				return fmt.Errorf("save login: %w", err)
+			}
+			fmt.Fprintf(out, "Logged in to %s\n", baseURL(cmd))
+			return nil
+		},
+	}
+}
+
+func newLogoutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke and forget the saved session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			base := baseURL(cmd)
+			sessions, err := readSessions()
+			if err != nil {
+				return err
+			}
+			s, ok := sessions[base]
+			if !ok {
+				fmt.Fprintf(cmd.OutOrStdout(), "Not logged in to %s\n", base)
+				return nil
+			}
+			// Best-effort: the local copy goes regardless, so a dead
+			// platform can't leave a login stuck on disk.
+			if s.RefreshToken != "" {
+				if err := deviceOAuthClient(cmd).RevokeToken(cmd.Context(), s.RefreshToken); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not revoke the session: %v\n", err)
+				}
+			}
+			delete(sessions, base)
+			if err := writeSessions(sessions); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged out of %s\n", base)
+			return nil
+		},
+	}
+}
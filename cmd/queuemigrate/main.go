@@ -0,0 +1,138 @@
+// Command queuemigrate drains a queue on one broker into a queue on
+// another, for broker switch-overs:
+//
+//	queuemigrate --from https://sqs.eu-west-1.amazonaws.com/123/fc-dispatch.fifo \
+//	             --to 'nats://nats:4222?stream=FLOWCATALYST' --rate 200
+//
+// Messages are republished unchanged (message group, deduplication ID and
+// dispatch metadata included) and acked on the source only once
+// published, in the order the source delivers them. --checkpoint records
+// every publish, so a run stopped by a crash or Ctrl-C can be rerun with
+// the same file without duplicating what it already moved. Queue URIs use
+// the router's syntax (sqs, nats, postgres).
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue/migrate"
+
+	_ "github.com/flowcatalyst/flowcatalyst-go/internal/queue/nats"
+	_ "github.com/flowcatalyst/flowcatalyst-go/internal/queue/postgres"
+	_ "github.com/flowcatalyst/flowcatalyst-go/internal/queue/sqs"
+)
+
+func main() {
+	logging.Init()
+
+	cmd := &cobra.Command{
+		Use:   "queuemigrate",
+		Short: "Move queued dispatch messages from one broker to another",
+		Long: `Drains --from into --to until the source stays empty, then exits.
+
+Switch the scheduler and router over to the new broker first, so nothing new
+lands on the old queue, then run this to move what is left. A router still
+consuming the old queue is harmless: each message is taken by one or the
+other. A failed publish hands the message back to the source and stops the
+run with a non-zero exit; rerun with the same --checkpoint to resume.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          run,
+	}
+	f := cmd.Flags()
+	f.String("from", "", "source queue URI")
+	f.String("to", "", "destination queue URI")
+	f.String("checkpoint", "queuemigrate.checkpoint", `file recording published messages, for resuming ("" to disable)`)
+	f.Float64("rate", 0, "most messages published per second (0: unlimited)")
+	f.Int("limit", 0, "stop after this many messages (0: drain)")
+	f.Uint32("batch", 10, "messages requested per poll")
+	f.Int("idle-polls", 3, "consecutive empty polls that mean the source is drained")
+	f.Uint32("visibility-timeout", 120, "seconds a polled message stays hidden on the source")
+	f.String("compression", "", "body encoding for the destination: gzip, zstd or empty")
+	f.String("large-payload-bucket", "", "S3 bucket for destination bodies over 256 KiB (SQS only)")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := cmd.ExecuteContext(ctx)
+	cancel()
+	if err != nil {
+		slog.Error("queuemigrate failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	f := cmd.Flags()
+	from, _ := f.GetString("from")
+	to, _ := f.GetString("to")
+	cpPath, _ := f.GetString("checkpoint")
+	visibility, _ := f.GetUint32("visibility-timeout")
+	compression, _ := f.GetString("compression")
+	bucket, _ := f.GetString("large-payload-bucket")
+
+	opts := migrate.Options{}
+	opts.Rate, _ = f.GetFloat64("rate")
+	opts.Limit, _ = f.GetInt("limit")
+	opts.BatchSize, _ = f.GetUint32("batch")
+	opts.IdlePolls, _ = f.GetInt("idle-polls")
+
+	src, err := queue.NewConsumer(ctx, common.QueueConfig{Name: from, URI: from, Connections: 1, VisibilityTimeout: visibility})
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	defer src.Stop()
+	dst, err := queue.NewPublisher(ctx, common.QueueConfig{Name: to, URI: to, Connections: 1, VisibilityTimeout: visibility, Compression: compression, LargePayloadBucket: bucket})
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+	if e, ok := dst.(queue.Embedded); ok {
+		if err := e.InitSchema(ctx); err != nil {
+			return fmt.Errorf("destination schema: %w", err)
+		}
+	}
+
+	if cpPath != "" {
+		cp, err := migrate.OpenCheckpoint(cpPath)
+		if err != nil {
+			return err
+		}
+		defer cp.Close()
+		if n := cp.Len(); n > 0 {
+			slog.Info("resuming from checkpoint", "path", cpPath, "published", n)
+		}
+		opts.Checkpoint = cp
+	}
+
+	start := time.Now()
+	last := start
+	opts.Progress = func(st migrate.Stats) {
+		if time.Since(last) >= 5*time.Second {
+			last = time.Now()
+			slog.Info("migrating", "moved", st.Moved, "skipped", st.Skipped,
+				"rate", fmt.Sprintf("%.1f/s", float64(st.Moved)/time.Since(start).Seconds()))
+		}
+	}
+
+	slog.Info("migration started", "from", src.Identifier(), "to", dst.Identifier())
+	st, err := migrate.Run(ctx, src, dst, opts)
+	slog.Info("migration stopped", "moved", st.Moved, "skipped", st.Skipped, "elapsed", time.Since(start).Round(time.Second))
+	if errors.Is(err, context.Canceled) {
+		return errors.New("interrupted; rerun with the same --checkpoint to resume")
+	}
+	return err
+}
@@ -0,0 +1,249 @@
+// Command streamctl checks and repairs the stream processor's read
+// projections (msg_events_read, msg_dispatch_jobs_read, and any
+// registered through FC_STREAM_PROJECTIONS) against their source tables.
+//
+//	streamctl verify  --projection event_projection --since 168h --bucket day
+//	streamctl rebuild --projection dispatch_job_projection
+//
+// verify groups both sides by created_at bucket and compares row counts
+// and checksums of the projected columns; it exits non-zero when any
+// bucket has drifted. rebuild truncates the read table, re-queues every
+// source row and (unless --no-drain) projects the backlog in-process,
+// reporting progress. The database is resolved like fc-server's:
+// --database-url, else FC_DATABASE_URL / DATABASE_URL, DB_SECRET_ARN, or
+// DB_HOST + credentials.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/database"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/server"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/stream"
+)
+
+func main() {
+	logging.Init()
+	// Projections registered through fc-server's FC_STREAM_PROJECTIONS are
+	// verified and rebuilt like the built-ins.
+	if err := stream.RegisterConfigured(os.Getenv("FC_STREAM_PROJECTIONS")); err != nil {
+		slog.Error("streamctl failed", "err", fmt.Errorf("FC_STREAM_PROJECTIONS: %w", err))
+		os.Exit(1)
+	}
+
+	root := &cobra.Command{
+		Use:   "streamctl",
+		Short: "Verify and rebuild stream processor projections",
+		// Drift and DB errors are reported by main; the usage dump would
+		// bury them.
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().String("database-url", "", "Postgres URL (default: resolved from the fc-server environment)")
+	root.AddCommand(newVerifyCmd())
+	root.AddCommand(newRebuildCmd())
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := root.ExecuteContext(ctx)
+	cancel()
+	if err != nil {
+		slog.Error("streamctl failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+func projectionFlag(cmd *cobra.Command) {
+	cmd.Flags().String("projection", "", "projection to act on: "+strings.Join(stream.RebuildableProjections(), ", "))
+	_ = cmd.MarkFlagRequired("projection")
+}
+
+// connect resolves the database URL the way fc-server does and opens a
+// small pool.
+func connect(cmd *cobra.Command) (*pgxpool.Pool, error) {
+	ctx := cmd.Context()
+	dbURL, _ := cmd.Flags().GetString("database-url")
+	if dbURL == "" {
+		secretURL, ok, err := server.ResolveDBSecretURL(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("resolve DB secret: %w", err)
+		}
+		dbURL = server.ResolveDatabaseURL()
+		if ok {
+			dbURL = secretURL
+		}
+	}
+	pool, err := database.NewPool(ctx, database.Config{URL: dbURL})
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	return pool, nil
+}
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Compare a projection with its source table per time bucket",
+		Long: `Groups source and read rows by created_at bucket and compares row counts and
+an md5 over the projected columns. Buckets are reported as OK, LAGGING (the
+sides differ but source rows are still awaiting projection) or DRIFT. Exits
+non-zero when any bucket drifted.`,
+		Args: cobra.NoArgs,
+		RunE: runVerify,
+	}
+	projectionFlag(cmd)
+	cmd.Flags().Duration("since", 7*24*time.Hour, "how far back to check")
+	cmd.Flags().String("from", "", "start of the range, RFC 3339 (overrides --since)")
+	cmd.Flags().String("to", "", "end of the range, RFC 3339 (default: now)")
+	cmd.Flags().String("bucket", "day", "bucket size: "+strings.Join(stream.BucketUnits, ", "))
+	cmd.Flags().Bool("all", false, "list every bucket, not only the ones that differ")
+	return cmd
+}
+
+func runVerify(cmd *cobra.Command, _ []string) error {
+	projection, _ := cmd.Flags().GetString("projection")
+	since, _ := cmd.Flags().GetDuration("since")
+	bucket, _ := cmd.Flags().GetString("bucket")
+	all, _ := cmd.Flags().GetBool("all")
+
+	opts := stream.VerifyOptions{To: time.Now(), Bucket: bucket}
+	if s, _ := cmd.Flags().GetString("to"); s != "" {
+		to, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+		opts.To = to
+	}
+	opts.From = opts.To.Add(-since)
+	if s, _ := cmd.Flags().GetString("from"); s != "" {
+		from, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("--from: %w", err)
+		}
+		opts.From = from
+	}
+
+	pool, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	report, err := stream.Verify(cmd.Context(), pool, projection, opts)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	counts := map[stream.BucketState]int{}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUCKET\tSTATE\tSOURCE\tREAD\tPENDING")
+	for _, b := range report.Buckets {
+		st := b.State()
+		counts[st]++
+		if st == stream.BucketOK && !all {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", b.Start.UTC().Format(time.RFC3339), st, b.SourceRows, b.ReadRows, b.Pending)
+	}
+	_ = tw.Flush()
+	fmt.Fprintf(out, "%s: %d buckets — %d ok, %d lagging, %d drift\n", projection,
+		len(report.Buckets), counts[stream.BucketOK], counts[stream.BucketLagging], counts[stream.BucketDrift])
+
+	if n := counts[stream.BucketDrift]; n > 0 {
+		return fmt.Errorf("%d bucket(s) drifted; rebuild with: streamctl rebuild --projection %s", n, projection)
+	}
+	return nil
+}
+
+func newRebuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild a projection's read table from scratch",
+		Long: `Truncates the read table and marks every source row unprojected, in one
+transaction. Unless --no-drain is set, the backlog is then projected
+in-process with progress reporting; a running stream processor shares the
+work safely (both claim with SKIP LOCKED). With --no-drain the running stream
+processor does the replay. The read table is incomplete until the replay
+finishes.
+
+event_fan_out cannot be rebuilt: replaying it would re-create dispatch jobs.`,
+		Args: cobra.NoArgs,
+		RunE: runRebuild,
+	}
+	projectionFlag(cmd)
+	cmd.Flags().Int("batch-size", 1000, "rows projected per transaction while draining")
+	cmd.Flags().Bool("no-drain", false, "only reset; leave the replay to the stream processor")
+	cmd.Flags().Bool("yes", false, "skip the confirmation prompt")
+	return cmd
+}
+
+func runRebuild(cmd *cobra.Command, _ []string) error {
+	projection, _ := cmd.Flags().GetString("projection")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	noDrain, _ := cmd.Flags().GetBool("no-drain")
+	yes, _ := cmd.Flags().GetBool("yes")
+	if batchSize <= 0 {
+		return errors.New("--batch-size must be > 0")
+	}
+	if !yes && !confirm(cmd, "Truncate and rebuild "+projection+"?") {
+		return errors.New("aborted")
+	}
+
+	pool, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	res, err := stream.Rebuild(ctx, pool, projection)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s: read table cleared, %d source rows re-queued\n", projection, res.Requeued)
+	if noDrain {
+		return nil
+	}
+
+	start := time.Now()
+	var last time.Time
+	total, err := stream.Drain(ctx, pool, projection, batchSize, func(projected int64) {
+		if time.Since(last) < 2*time.Second {
+			return
+		}
+		last = time.Now()
+		rate := float64(projected) / time.Since(start).Seconds()
+		fmt.Fprintf(out, "  %d/%d projected (%.0f rows/s)\n", projected, res.Requeued, rate)
+	})
+	if err != nil {
+		return fmt.Errorf("drain after %d rows: %w", total, err)
+	}
+	pending, err := stream.Pending(ctx, pool, projection)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s: projected %d rows in %s; %d still pending\n",
+		projection, total, time.Since(start).Round(time.Second), pending)
+	return nil
+}
+
+func confirm(cmd *cobra.Command, prompt string) bool {
+	fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N] ", prompt)
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(line), "y")
+}
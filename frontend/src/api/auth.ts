@@ -172,6 +172,15 @@ export function redirectAfterLogin(): void {
 		window.location.href = oauthAuthorizeUrl((f) => urlParams.get(f));
 		return;
 	}
+	// Device sign-in (RFC 8628): back to the verification page, carrying the
+	// user code the person arrived with.
+	if (urlParams.get("device") === "true") {
+		const userCode = urlParams.get("user_code");
+		window.location.href = userCode
+			? `/oauth/device?user_code=${encodeURIComponent(userCode)}`
+			: "/oauth/device";
+		return;
+	}
 	// The most capable page the user can reach: dashboard (anchor/admin), else a
 	// client-administrator's user-management page, else their profile.
 	const authStore = useAuthStore();
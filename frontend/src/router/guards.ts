@@ -95,6 +95,15 @@ export async function guestGuard(
 			return;
 		}
 
+		// Device sign-in - back to the verification page with the user code.
+		if (to.query['device'] === "true") {
+			const userCode = to.query['user_code'];
+			window.location.href = typeof userCode === "string" && userCode
+				? `/oauth/device?user_code=${encodeURIComponent(userCode)}`
+				: "/oauth/device";
+			return;
+		}
+
 		// Normal case - go to the dashboard, or the profile if the user has no
 		// roles (replace to avoid a back-button loop).
 		next({ path: landingPath(authStore.user), replace: true });
@@ -40,6 +40,14 @@ const router = createRouter({
 						import("@/pages/auth/ResetPasswordPage.vue"),
 					beforeEnter: guestGuard,
 				},
+				// OAuth consent for third-party clients. Reached mid-flow from
+				// /oauth/authorize with a session, so not guest-guarded; the page
+				// handles an ended session itself.
+				{
+					path: "consent",
+					name: "oauth-consent",
+					component: () => import("@/pages/auth/ConsentPage.vue"),
+				},
 				{
 					path: "",
 					redirect: "/auth/login",
@@ -0,0 +1,350 @@
+// Package cloudevents reads and writes CloudEvents 1.0 over the HTTP
+// protocol binding, in both content modes:
+//
+//   - structured: the whole event is the body, Content-Type
+//     application/cloudevents+json;
+//   - binary: attributes travel as ce-* headers, the body is the event
+//     data and Content-Type is its datacontenttype.
+//
+// Only what the platform needs is implemented: JSON event format, string
+// attribute values, no batch mode. Event ingest (POST /api/events) parses
+// with FromRequest; webhook delivery renders with SetHeaders or
+// MarshalStructured.
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SpecVersion is the only CloudEvents version accepted and produced.
+const SpecVersion = "1.0"
+
+// ContentTypeStructured is the structured-mode media type (JSON format).
+const ContentTypeStructured = "application/cloudevents+json"
+
+// headerPrefix marks a binary-mode attribute header.
+const headerPrefix = "ce-"
+
+// Event is one CloudEvent. Data holds the raw data bytes — JSON text when
+// DataContentType is JSON (or empty, which the spec treats as JSON in the
+// JSON format).
+type Event struct {
+	ID              string
+	Source          string
+	Type            string
+	Subject         string
+	Time            time.Time // zero when absent
+	DataContentType string
+	DataSchema      string
+	Data            []byte
+	// Extensions holds every other attribute by its (lower-case) name.
+	Extensions map[string]string
+}
+
+// IsStructured reports whether contentType is the structured JSON mode.
+func IsStructured(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	return mt == ContentTypeStructured
+}
+
+// IsBinary reports whether h carries a binary-mode event.
+func IsBinary(h http.Header) bool {
+	return h.Get(headerPrefix+"specversion") != ""
+}
+
+// IsJSON reports whether a datacontenttype denotes JSON data. An empty
+// type counts: the JSON event format defaults data to application/json.
+func IsJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || mt == "text/json" || strings.HasSuffix(mt, "+json")
+}
+
+// FromRequest parses a structured- or binary-mode event from a request's
+// headers and body. ok is false when the request is neither.
+func FromRequest(h http.Header, body []byte) (ev *Event, ok bool, err error) {
+	switch {
+	case IsStructured(h.Get("Content-Type")):
+		ev, err = ParseStructured(body)
+	case IsBinary(h):
+		ev, err = ParseBinary(h, body)
+	default:
+		return nil, false, nil
+	}
+	return ev, true, err
+}
+
+// ParseStructured decodes a JSON-format event.
+func ParseStructured(body []byte) (*Event, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("cloudevent is not a JSON object: %w", err)
+	}
+	attrs := make(map[string]string, len(raw))
+	var data, dataBase64 json.RawMessage
+	for name, v := range raw {
+		switch name {
+		case "data":
+			data = v
+			continue
+		case "data_base64":
+			dataBase64 = v
+			continue
+		}
+		s, err := attributeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		attrs[name] = s
+	}
+	ev, err := fromAttributes(attrs)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case data != nil && dataBase64 != nil:
+		return nil, errors.New("data and data_base64 are mutually exclusive")
+	case dataBase64 != nil:
+		var enc string
+		if err := json.Unmarshal(dataBase64, &enc); err != nil {
+			return nil, errors.New("data_base64 must be a string")
+		}
+		if ev.Data, err = base64.StdEncoding.DecodeString(enc); err != nil {
+			return nil, fmt.Errorf("data_base64: %w", err)
+		}
+	case data != nil && string(data) != "null":
+		if IsJSON(ev.DataContentType) {
+			ev.Data = data
+			break
+		}
+		// Non-JSON data in the JSON format is carried as a string.
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("data of type %s must be a JSON string", ev.DataContentType)
+		}
+		ev.Data = []byte(s)
+	}
+	return ev, nil
+}
+
+// ParseBinary decodes a binary-mode event: ce-* headers plus the body as
+// data.
+func ParseBinary(h http.Header, body []byte) (*Event, error) {
+	attrs := make(map[string]string)
+	for key, vals := range h {
+		name, ok := cutPrefixFold(key, headerPrefix)
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		v, err := url.PathUnescape(vals[0])
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", key, err)
+		}
+		attrs[strings.ToLower(name)] = v
+	}
+	// datacontenttype is the Content-Type header in binary mode.
+	if ct := h.Get("Content-Type"); ct != "" {
+		attrs["datacontenttype"] = ct
+	}
+	ev, err := fromAttributes(attrs)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		ev.Data = body
+	}
+	return ev, nil
+}
+
+func fromAttributes(attrs map[string]string) (*Event, error) {
+	if v := attrs["specversion"]; v != SpecVersion {
+		return nil, fmt.Errorf("unsupported specversion %q (want %s)", v, SpecVersion)
+	}
+	ev := &Event{
+		ID:              attrs["id"],
+		Source:          attrs["source"],
+		Type:            attrs["type"],
+		Subject:         attrs["subject"],
+		DataContentType: attrs["datacontenttype"],
+		DataSchema:      attrs["dataschema"],
+	}
+	for _, req := range []struct{ name, val string }{{"id", ev.ID}, {"source", ev.Source}, {"type", ev.Type}} {
+		if req.val == "" {
+			return nil, fmt.Errorf("required attribute %q is missing", req.name)
+		}
+	}
+	if t := attrs["time"]; t != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return nil, fmt.Errorf("time %q is not RFC 3339", t)
+		}
+		ev.Time = parsed.UTC()
+	}
+	for name, v := range attrs {
+		switch name {
+		case "specversion", "id", "source", "type", "subject", "time", "datacontenttype", "dataschema":
+			continue
+		}
+		if !validName(name) {
+			return nil, fmt.Errorf("attribute name %q must be lower-case letters and digits", name)
+		}
+		if ev.Extensions == nil {
+			ev.Extensions = make(map[string]string)
+		}
+		ev.Extensions[name] = v
+	}
+	return ev, nil
+}
+
+// SetHeaders writes ev as a binary-mode request: Content-Type is the data
+// content type and every attribute becomes a ce-* header.
+func (ev *Event) SetHeaders(h http.Header) {
+	ct := ev.DataContentType
+	if ct == "" {
+		ct = "application/json"
+	}
+	h.Set("Content-Type", ct)
+	set := func(name, v string) {
+		if v != "" {
+			h.Set(headerPrefix+name, encodeHeaderValue(v))
+		}
+	}
+	set("specversion", SpecVersion)
+	set("id", ev.ID)
+	set("source", ev.Source)
+	set("type", ev.Type)
+	set("subject", ev.Subject)
+	if !ev.Time.IsZero() {
+		set("time", ev.Time.UTC().Format(time.RFC3339Nano))
+	}
+	set("dataschema", ev.DataSchema)
+	for name, v := range ev.Extensions {
+		set(name, v)
+	}
+}
+
+// MarshalStructured renders ev in the JSON event format. JSON data is
+// embedded as-is, other text as a string and anything that isn't valid
+// UTF-8 as data_base64.
+func (ev *Event) MarshalStructured() ([]byte, error) {
+	out := map[string]any{
+		"specversion": SpecVersion,
+		"id":          ev.ID,
+		"source":      ev.Source,
+		"type":        ev.Type,
+	}
+	put := func(name, v string) {
+		if v != "" {
+			out[name] = v
+		}
+	}
+	put("subject", ev.Subject)
+	if !ev.Time.IsZero() {
+		out["time"] = ev.Time.UTC().Format(time.RFC3339Nano)
+	}
+	put("datacontenttype", ev.DataContentType)
+	put("dataschema", ev.DataSchema)
+	for name, v := range ev.Extensions {
+		put(name, v)
+	}
+	if len(ev.Data) > 0 {
+		switch {
+		case IsJSON(ev.DataContentType) && json.Valid(ev.Data):
+			out["data"] = json.RawMessage(ev.Data)
+		case utf8.Valid(ev.Data):
+			out["data"] = string(ev.Data)
+		default:
+			out["data_base64"] = base64.StdEncoding.EncodeToString(ev.Data)
+		}
+	}
+	// No HTML escaping: string data (XML, HTML) should arrive as sent.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(out); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// ExtensionNames returns ev's extension names, sorted.
+func (ev *Event) ExtensionNames() []string {
+	names := make([]string, 0, len(ev.Extensions))
+	for n := range ev.Extensions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// attributeString flattens a JSON attribute value to its canonical string
+// form. CloudEvents attributes are strings, integers or booleans on the
+// wire; all of them are kept as strings here.
+func attributeString(v json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return s, nil
+	}
+	var b bool
+	if err := json.Unmarshal(v, &b); err == nil {
+		return strconv.FormatBool(b), nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(v, &n); err == nil {
+		return n.String(), nil
+	}
+	return "", errors.New("must be a string, number or boolean")
+}
+
+// validName: CloudEvents attribute names are lower-case ASCII letters and
+// digits.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeHeaderValue percent-encodes the characters the HTTP binding
+// requires: space, double quote, percent and anything outside printable
+// ASCII.
+func encodeHeaderValue(v string) string {
+	var b strings.Builder
+	for _, c := range []byte(v) {
+		if c <= ' ' || c >= 0x7f || c == '"' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
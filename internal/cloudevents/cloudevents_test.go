@@ -0,0 +1,102 @@
+package cloudevents
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructured(t *testing.T) {
+	ev, err := ParseStructured([]byte(`{
+		"specversion": "1.0", "id": "A-1", "source": "/orders", "type": "com.example.order.created",
+		"subject": "o-1", "time": "2026-03-01T10:00:00+01:00", "datacontenttype": "application/json",
+		"correlationid": "c-1", "sequence": 7, "data": {"total": 12}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "A-1", ev.ID)
+	assert.Equal(t, "o-1", ev.Subject)
+	assert.Equal(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), ev.Time)
+	assert.JSONEq(t, `{"total":12}`, string(ev.Data))
+	assert.Equal(t, map[string]string{"correlationid": "c-1", "sequence": "7"}, ev.Extensions)
+
+	ev, err = ParseStructured([]byte(`{"specversion":"1.0","id":"1","source":"s","type":"t","data_base64":"aGk="}`))
+	require.NoError(t, err)
+	assert.Equal(t, "hi", string(ev.Data))
+
+	for name, body := range map[string]string{
+		"wrong version":  `{"specversion":"0.3","id":"1","source":"s","type":"t"}`,
+		"missing source": `{"specversion":"1.0","id":"1","type":"t"}`,
+		"bad time":       `{"specversion":"1.0","id":"1","source":"s","type":"t","time":"yesterday"}`,
+		"bad extension":  `{"specversion":"1.0","id":"1","source":"s","type":"t","Trace-Id":"x"}`,
+		"both data":      `{"specversion":"1.0","id":"1","source":"s","type":"t","data":{},"data_base64":"e30="}`,
+	} {
+		_, err := ParseStructured([]byte(body))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseBinary(t *testing.T) {
+	h := http.Header{}
+	h.Set("Ce-Specversion", "1.0")
+	h.Set("Ce-Id", "A-1")
+	h.Set("Ce-Source", "/orders")
+	h.Set("Ce-Type", "com.example.order.created")
+	h.Set("Ce-Subject", "order%20one")
+	h.Set("Ce-Traceparent", "00-abc")
+	h.Set("Content-Type", "application/json")
+
+	ev, ok, err := FromRequest(h, []byte(`{"total":12}`))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "order one", ev.Subject, "header values are percent-decoded")
+	assert.Equal(t, "application/json", ev.DataContentType)
+	assert.Equal(t, map[string]string{"traceparent": "00-abc"}, ev.Extensions)
+
+	_, ok, err = FromRequest(http.Header{"Content-Type": {"application/json"}}, []byte(`{}`))
+	assert.NoError(t, err)
+	assert.False(t, ok, "a plain JSON request is not a CloudEvent")
+}
+
+func TestRenderRoundTrips(t *testing.T) {
+	ev := &Event{
+		ID: "dsj_1", Source: "/orders", Type: "orders:order:created", Subject: "o 1",
+		Time:            time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		DataContentType: "application/json",
+		Data:            []byte(`{"total":12}`),
+		Extensions:      map[string]string{"correlationid": "c-1"},
+	}
+
+	h := http.Header{}
+	ev.SetHeaders(h)
+	assert.Equal(t, "o%201", h.Get("ce-subject"))
+	back, err := ParseBinary(h, ev.Data)
+	require.NoError(t, err)
+	assert.Equal(t, ev, back)
+
+	body, err := ev.MarshalStructured()
+	require.NoError(t, err)
+	back, err = ParseStructured(body)
+	require.NoError(t, err)
+	assert.Equal(t, ev, back)
+
+	ev.DataContentType, ev.Data = "application/octet-stream", []byte{0xff, 0x00}
+	body, err = ev.MarshalStructured()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"data_base64":"/wA="`)
+}
+
+func TestIsJSON(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"":                                  true,
+		"application/json; charset=utf-8":   true,
+		"application/vnd.order+json":        true,
+		"application/xml":                   false,
+		"text/plain":                        false,
+		"application/cloudevents+json;bad=": false,
+	} {
+		assert.Equal(t, want, IsJSON(ct), ct)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 
 	"github.com/google/uuid"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
 )
 
 // PoolConfig is the per-pool routing configuration.
@@ -11,6 +13,92 @@ type PoolConfig struct {
 	Code               string  `json:"code"`
 	Concurrency        uint32  `json:"concurrency"`
 	RateLimitPerMinute *uint32 `json:"rateLimitPerMinute,omitempty"`
+	// MaxInFlightPerHost and MaxInFlightPerSubscription cap how many of
+	// the pool's workers one receiver host or one subscription can hold,
+	// so a slow endpoint can't starve the others sharing the pool. nil
+	// (absent) or 0 = no cap. Go-only; other routers ignore them.
+	MaxInFlightPerHost         *uint32 `json:"maxInFlightPerHost,omitempty"`
+	MaxInFlightPerSubscription *uint32 `json:"maxInFlightPerSubscription,omitempty"`
+	// AdaptiveConcurrency lets the router size the pool itself between
+	// bounds, from delivery latency and error rate. Concurrency is then
+	// only the starting point. nil = fixed concurrency. Go-only.
+	AdaptiveConcurrency *AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty"`
+	// Quarantine pauses the pool for a cool-down when its deliveries keep
+	// failing, instead of retrying against a dead receiver. nil = never.
+	// Go-only.
+	Quarantine *PoolQuarantine `json:"quarantine,omitempty"`
+}
+
+// AdaptiveConcurrency bounds and tunes a pool's AIMD concurrency control.
+type AdaptiveConcurrency struct {
+	MinConcurrency uint32 `json:"minConcurrency"`
+	MaxConcurrency uint32 `json:"maxConcurrency"`
+	// TargetLatencyMs shrinks the pool while mean delivery latency is
+	// above it. 0 = error rate only.
+	TargetLatencyMs uint32 `json:"targetLatencyMs,omitempty"`
+	// MaxErrorRate is the share of 5xx, timeout, connection-error and 429
+	// outcomes tolerated before shrinking. 0 (absent) = 0.1.
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty"`
+}
+
+// Normalized applies the defaults: a minimum of at least 1, a maximum of
+// at least the minimum, and MaxErrorRate 0.1.
+func (a AdaptiveConcurrency) Normalized() AdaptiveConcurrency {
+	a.MinConcurrency = max(a.MinConcurrency, 1)
+	a.MaxConcurrency = max(a.MaxConcurrency, a.MinConcurrency)
+	if a.MaxErrorRate <= 0 {
+		a.MaxErrorRate = 0.1
+	}
+	return a
+}
+
+// Equal compares two optional adaptive configs.
+func (a *AdaptiveConcurrency) Equal(b *AdaptiveConcurrency) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// PoolQuarantine tunes a pool's auto-quarantine: once at least
+// MinDeliveries have been attempted within WindowSeconds and the share
+// of 5xx, timeout and connection-error outcomes among them reaches
+// FailureRate, the pool stops dispatching for CooldownSeconds.
+type PoolQuarantine struct {
+	// FailureRate is the tripping share of failed deliveries. 0 (absent)
+	// = 0.5.
+	FailureRate float64 `json:"failureRate,omitempty"`
+	// MinDeliveries a window needs before it is judged. 0 = 20.
+	MinDeliveries uint32 `json:"minDeliveries,omitempty"`
+	// WindowSeconds is the length of the failure window. 0 = 60.
+	WindowSeconds uint32 `json:"windowSeconds,omitempty"`
+	// CooldownSeconds is how long a tripped pool stays paused. 0 = 300.
+	CooldownSeconds uint32 `json:"cooldownSeconds,omitempty"`
+}
+
+// Normalized applies the defaults.
+func (q PoolQuarantine) Normalized() PoolQuarantine {
+	if q.FailureRate <= 0 {
+		q.FailureRate = 0.5
+	}
+	if q.MinDeliveries == 0 {
+		q.MinDeliveries = 20
+	}
+	if q.WindowSeconds == 0 {
+		q.WindowSeconds = 60
+	}
+	if q.CooldownSeconds == 0 {
+		q.CooldownSeconds = 300
+	}
+	return q
+}
+
+// Equal compares two optional quarantine configs.
+func (q *PoolQuarantine) Equal(o *PoolQuarantine) bool {
+	if q == nil || o == nil {
+		return q == o
+	}
+	return *q == *o
 }
 
 // QueueConfig is the per-queue connection configuration.
@@ -27,6 +115,25 @@ type QueueConfig struct {
 	URI               string `json:"queueUri"`
 	Connections       uint32 `json:"connections"`
 	VisibilityTimeout uint32 `json:"visibilityTimeout"`
+	// ContentBasedDeduplication mirrors the SQS FIFO queue attribute of the
+	// same name: when set, publishers leave MessageDeduplicationId to SQS's
+	// body hash. Ignored for non-FIFO queues and other backends.
+	ContentBasedDeduplication bool `json:"contentBasedDeduplication,omitempty"`
+	// Weight is the queue's share of polls while every processing pool is
+	// saturated: a weight-3 queue is polled three times for each poll of a
+	// weight-1 queue, so an urgent queue isn't starved by a flooded bulk
+	// one. 0 (absent) means 1. Go-only; other routers ignore it.
+	Weight uint32 `json:"weight,omitempty"`
+	// Compression is the content encoding publishers apply to message
+	// bodies: "gzip", "zstd" or "" (plain JSON). Consumers decode whatever
+	// encoding a message carries, so this is publish-side only. Honoured by
+	// the SQS and NATS backends; the Postgres queue stores plain JSON.
+	Compression string `json:"compression,omitempty"`
+	// LargePayloadBucket is the S3 bucket SQS publishers offload bodies
+	// over the SQS size limit to, in the AWS Extended Client format.
+	// Empty sends every body inline. Consumers resolve offloaded bodies
+	// regardless, so this too is publish-side only. SQS only.
+	LargePayloadBucket string `json:"largePayloadBucket,omitempty"`
 }
 
 // UnmarshalJSON accepts both the canonical camelCase keys (queueName,
@@ -42,6 +149,11 @@ func (q *QueueConfig) UnmarshalJSON(data []byte) error {
 		URI               *string `json:"uri"`
 		Connections       *uint32 `json:"connections"`
 		VisibilityTimeout *uint32 `json:"visibilityTimeout"`
+
+		ContentBasedDeduplication bool   `json:"contentBasedDeduplication"`
+		Weight                    uint32 `json:"weight"`
+		Compression               string `json:"compression"`
+		LargePayloadBucket        string `json:"largePayloadBucket"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
@@ -74,6 +186,10 @@ func (q *QueueConfig) UnmarshalJSON(data []byte) error {
 	} else {
 		q.VisibilityTimeout = 120
 	}
+	q.ContentBasedDeduplication = raw.ContentBasedDeduplication
+	q.Weight = raw.Weight
+	q.Compression = raw.Compression
+	q.LargePayloadBucket = raw.LargePayloadBucket
 	return nil
 }
 
@@ -85,19 +201,36 @@ type RouterConfig struct {
 
 // LeaderElectionConfig is the unified leader-election configuration
 // shared by fc-outbox and fc-standby in Rust.
+//
+// Backend selects the lease store: "redis" (default; SET NX PX on
+// RedisURL) or "mongo" (a TTL lease document in MongoDatabase on
+// MongoURI).
+//
+// Region, when set, gates the election on the multi-region handover
+// record (kept in MongoDatabase on MongoURI whatever the lease backend):
+// the lease is only taken while Region is the active region.
+// InitialActiveRegion seeds that record on first use.
 type LeaderElectionConfig struct {
 	Enabled                  bool
+	Backend                  string
 	RedisURL                 string
+	MongoURI                 string
+	MongoDatabase            string
 	LockKey                  string
 	LockTTLSeconds           uint64
 	HeartbeatIntervalSeconds uint64
 	InstanceID               string
+	Region                   string
+	InitialActiveRegion      string
+	// Mongo holds the client pool and timeout options for MongoURI.
+	Mongo mongoconn.Config
 }
 
-// NewLeaderElectionConfig creates a config with sane defaults.
+// NewLeaderElectionConfig creates a Redis-backed config with sane defaults.
 func NewLeaderElectionConfig(redisURL string) LeaderElectionConfig {
 	return LeaderElectionConfig{
 		Enabled:                  true,
+		Backend:                  "redis",
 		RedisURL:                 redisURL,
 		LockKey:                  "fc:leader",
 		LockTTLSeconds:           30,
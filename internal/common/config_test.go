@@ -2,6 +2,7 @@ package common
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -72,6 +73,25 @@ func TestQueueConfigMarshal_EmitsCamelCase(t *testing.T) {
 	}
 }
 
+// TestQueueConfigWeight decodes the Go-only weight and keeps it off the wire
+// when unset, so a default config is byte-identical for a Rust router.
+func TestQueueConfigWeight(t *testing.T) {
+	var q QueueConfig
+	if err := json.Unmarshal([]byte(`{"queueUri":"sqs://urgent","weight":3}`), &q); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if q.Weight != 3 {
+		t.Fatalf("Weight = %d, want 3", q.Weight)
+	}
+	b, err := json.Marshal(QueueConfig{Name: "orders", URI: "sqs://orders"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(b), "weight") {
+		t.Fatalf("unset weight emitted: %s", b)
+	}
+}
+
 // TestRouterConfigUnmarshal_FullWire exercises the full config-service payload
 // shape end to end.
 func TestRouterConfigUnmarshal_FullWire(t *testing.T) {
@@ -0,0 +1,26 @@
+package common
+
+// Environment is the space a client's configuration and traffic live in.
+// Every client has a SANDBOX and a PRODUCTION space: API keys,
+// subscriptions and events in one never see the other. Rows written before
+// environments existed are PRODUCTION.
+type Environment string
+
+const (
+	EnvironmentSandbox    Environment = "SANDBOX"
+	EnvironmentProduction Environment = "PRODUCTION"
+)
+
+// ParseEnvironment is the lenient parser: unknown input maps to
+// Production.
+func ParseEnvironment(s string) Environment {
+	if s == string(EnvironmentSandbox) {
+		return EnvironmentSandbox
+	}
+	return EnvironmentProduction
+}
+
+// Valid reports whether e is one of the defined environments.
+func (e Environment) Valid() bool {
+	return e == EnvironmentSandbox || e == EnvironmentProduction
+}
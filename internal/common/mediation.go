@@ -1,5 +1,12 @@
 package common
 
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // MediationResult classifies the outcome of an attempt to deliver a
 // message to its target endpoint.
 type MediationResult int
@@ -13,13 +20,17 @@ const (
 	MediationErrorProcess
 	// MediationErrorConnection is a connection failure — NACK for retry.
 	MediationErrorConnection
-	// MediationRateLimited is HTTP 429. NACK with Retry-After delay, but
-	// don't count toward circuit-breaker failures: destination is
-	// healthy, just throttling us.
+	// MediationRateLimited is HTTP 429, or a 503 carrying an honoured
+	// Retry-After. NACK with Retry-After delay, but don't count toward
+	// circuit-breaker failures: destination is healthy, just throttling us.
 	MediationRateLimited
 	// MediationCircuitOpen means the per-endpoint breaker is open; no HTTP
 	// call was attempted. DEFER (not a failure) until the breaker may probe.
 	MediationCircuitOpen
+	// MediationShed means the mediator turned the delivery away to protect
+	// receivers — its in-flight ceiling was reached, or the retry budget is
+	// spent; no HTTP call was attempted. DEFER, not a failure.
+	MediationShed
 )
 
 // MediationOutcome carries the result plus optional retry-after delay.
@@ -74,3 +85,29 @@ func RateLimited(retryAfterSec int) MediationOutcome {
 func CircuitOpen(delaySec int) MediationOutcome {
 	return MediationOutcome{Result: MediationCircuitOpen, DelaySeconds: delaySec}
 }
+
+// Shed builds a shed outcome: the mediator declined to make the call (see
+// MediationShed). The pool DEFERS with delaySec, as for CircuitOpen.
+func Shed(delaySec int, msg string) MediationOutcome {
+	return MediationOutcome{Result: MediationShed, DelaySeconds: delaySec, ErrorMessage: msg}
+}
+
+// ParseRetryAfter reads a Retry-After header value, delta-seconds or an
+// HTTP-date, as a delay from now. ok is false when v is empty or
+// malformed.
+func ParseRetryAfter(v string, now time.Time) (d time.Duration, ok bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		if n < 0 {
+			return 0, false
+		}
+		return time.Duration(n) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -45,9 +45,63 @@ func (d DispatchMode) RequiresOrdering() bool {
 	return d == DispatchNextOnError || d == DispatchBlockOnError
 }
 
+// Priority is a dispatch job's delivery priority, inherited from its
+// subscription. The scheduler publishes higher priorities first (and can
+// route them to their own queue); the router gives HIGH messages first
+// claim on free workers.
+type Priority string
+
+const (
+	PriorityHigh   Priority = "HIGH"
+	PriorityNormal Priority = "NORMAL"
+	PriorityLow    Priority = "LOW"
+)
+
+// ParsePriority is the lenient parser: unknown input maps to Normal.
+func ParsePriority(s string) Priority {
+	switch s {
+	case "HIGH":
+		return PriorityHigh
+	case "LOW":
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
+
+// Valid reports whether p is one of the defined priorities.
+func (p Priority) Valid() bool {
+	return p == PriorityHigh || p == PriorityNormal || p == PriorityLow
+}
+
+// Rank orders priorities for sorting: lower is more urgent.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Message pointer wire versions. Version 1 is Java's (and Rust's)
+// MessagePointer, which carries no version field; version 2 is the Go
+// shape, which stamps one. Consumers decode the current version and the
+// one before it, so either side of a rolling upgrade reads the other.
+const (
+	MessageVersionLegacy  uint32 = 1
+	MessageVersionCurrent uint32 = 2
+)
+
 // Message is the core message structure that flows through the system.
 // Compatible with Java's MessagePointer.
 type Message struct {
+	// Version is the wire format version. queue.EncodeMessage stamps
+	// MessageVersionCurrent when it is unset; queue.DecodeMessage reports
+	// the version the producer wrote (MessageVersionLegacy when absent).
+	Version         uint32        `json:"version,omitempty"`
 	ID              string        `json:"id"`
 	PoolCode        string        `json:"poolCode,omitempty"`
 	AuthToken       *string       `json:"authToken,omitempty"`
@@ -57,6 +111,32 @@ type Message struct {
 	MessageGroupID  *string       `json:"messageGroupId,omitempty"`
 	HighPriority    bool          `json:"highPriority,omitempty"`
 	DispatchMode    DispatchMode  `json:"dispatchMode,omitempty"`
+	// DeduplicationID identifies one publish of the message. FIFO queues
+	// drop a repeat within their dedup window, so a re-dispatch of the same
+	// job must carry a fresh one. Part of the body on purpose: it also
+	// distinguishes re-dispatches under content-based dedup.
+	DeduplicationID *string `json:"deduplicationId,omitempty"`
+	// TimeoutSeconds overrides the mediator's request timeout for this
+	// message (0 = the mediator's configured Timeout). The scheduler sets
+	// it from the job's own timeout so slow targets aren't cut off early
+	// and fast-failing ones aren't waited on for the full default.
+	TimeoutSeconds uint32 `json:"timeoutSeconds,omitempty"`
+	// WindowClosesAt is when the subscription's delivery window closes.
+	// The router acks rather than delivers or retries past it; the job is
+	// then recovered to PENDING and the scheduler holds it until the
+	// window reopens. nil = no window.
+	WindowClosesAt *time.Time `json:"windowClosesAt,omitempty"`
+	// SubscriptionID and TargetHost identify the receiver when
+	// MediationTarget is the platform's processing callback rather than
+	// the receiver itself; the router's per-subscription and per-host
+	// in-flight caps key on them. An empty TargetHost falls back to
+	// MediationTarget's host.
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	TargetHost     string `json:"targetHost,omitempty"`
+	// SubscriptionWeight is the subscription's share of the pool's workers
+	// while other subscriptions have messages waiting too: the pool serves
+	// them weighted round-robin. 0 is weight 1.
+	SubscriptionWeight uint32 `json:"subscriptionWeight,omitempty"`
 }
 
 // QueuedMessage is a Message received from a queue with broker tracking.
@@ -74,6 +154,10 @@ type QueuedMessage struct {
 	// retry so it can recognise a re-dispatch (skip re-tracking) and grow the
 	// backoff. Internal-only; never crosses the wire.
 	Attempts uint
+	// Failures counts the attempts among them that reached the receiver and
+	// failed (5xx, timeout, connection error) — the ones a re-dispatch
+	// retries. Internal-only.
+	Failures uint
 }
 
 // InFlightMessage tracks a message currently being processed.
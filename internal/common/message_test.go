@@ -83,6 +83,16 @@ func TestDispatchModeRequiresOrdering(t *testing.T) {
 	assert.True(t, common.DispatchBlockOnError.RequiresOrdering())
 }
 
+func TestPriorityParseLenient(t *testing.T) {
+	assert.Equal(t, common.PriorityNormal, common.ParsePriority(""))
+	assert.Equal(t, common.PriorityNormal, common.ParsePriority("URGENT"))
+	assert.Equal(t, common.PriorityHigh, common.ParsePriority("HIGH"))
+	assert.Equal(t, common.PriorityLow, common.ParsePriority("LOW"))
+	assert.False(t, common.Priority("high").Valid())
+	assert.Less(t, common.PriorityHigh.Rank(), common.PriorityNormal.Rank())
+	assert.Less(t, common.PriorityNormal.Rank(), common.PriorityLow.Rank())
+}
+
 func TestDispatchStatusLifecycle(t *testing.T) {
 	assert.True(t, common.DispatchCompleted.IsTerminal())
 	assert.True(t, common.DispatchCompleted.IsSuccessful())
@@ -39,4 +39,13 @@ type EnhancedPoolMetrics struct {
 	ProcessingTime   ProcessingTimeMetrics `json:"processingTime"`
 	Last5Min         WindowedMetrics       `json:"last5Min"`
 	Last30Min        WindowedMetrics       `json:"last30Min"`
+
+	// TotalPushback429 / TotalPushback503 count receivers answering 429 /
+	// 503 — how often targets push back, independent of our own limiter.
+	TotalPushback429 uint64 `json:"totalPushback429"`
+	TotalPushback503 uint64 `json:"totalPushback503"`
+
+	// TotalRejected counts messages NACKed back to the broker because the
+	// pool's buffer was full.
+	TotalRejected uint64 `json:"totalRejected"`
 }
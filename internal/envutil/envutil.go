@@ -8,6 +8,7 @@ package envutil
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Or returns the variable's value, or def when unset/empty.
@@ -49,3 +50,15 @@ func Uint(name string) (uint64, bool) {
 	}
 	return n, true
 }
+
+// Bool parses the variable as a boolean (1/true/yes/on, 0/false/no/off,
+// case-insensitive), returning def when unset or unrecognised.
+func Bool(name string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(name))) {
+	case "1", "true", "yes", "on":
+		return true
+	case "0", "false", "no", "off":
+		return false
+	}
+	return def
+}
@@ -69,3 +69,22 @@ func TestUint(t *testing.T) {
 		t.Error("Uint(unset) ok = true, want false")
 	}
 }
+
+func TestBool(t *testing.T) {
+	t.Setenv("ENVUTIL_T_BOOL_ON", "On")
+	t.Setenv("ENVUTIL_T_BOOL_OFF", "0")
+	t.Setenv("ENVUTIL_T_BOOL_BAD", "maybe")
+
+	if !Bool("ENVUTIL_T_BOOL_ON", false) {
+		t.Error("Bool(On) = false, want true")
+	}
+	if Bool("ENVUTIL_T_BOOL_OFF", true) {
+		t.Error("Bool(0) = true, want false")
+	}
+	if !Bool("ENVUTIL_T_BOOL_BAD", true) {
+		t.Error("Bool(garbage) should fall back to default")
+	}
+	if Bool("ENVUTIL_T_UNSET", false) {
+		t.Error("Bool(unset) should fall back to default")
+	}
+}
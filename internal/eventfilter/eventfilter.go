@@ -0,0 +1,361 @@
+// Package eventfilter is the predicate language for subscription event
+// filters. A filter is a boolean expression over the event:
+//
+//	payload.country == "AU" && payload.amount > 100
+//	payload.status in ["PAID", "REFUNDED"] || !has(payload.legacy)
+//	event.subject.startsWith("orders.") && payload.lines.count >= 1
+//
+// The grammar is a small, CEL-shaped subset:
+//
+//	expr    := or
+//	or      := and ( "||" and )*
+//	and     := unary ( "&&" unary )*
+//	unary   := "!" unary | cmp
+//	cmp     := primary ( ("=="|"!="|"<"|"<="|">"|">=") primary | "in" list )?
+//	primary := literal | list | path | path "." method "(" expr ")"
+//	         | "has" "(" path ")" | "(" expr ")"
+//	path    := ident ( "." ident )*
+//	literal := string | number | true | false | null
+//
+// Roots are `payload` (the decoded event data) and `event` (the envelope:
+// type, source, subject, clientId, messageGroup). Methods are startsWith,
+// endsWith and contains on strings.
+//
+// Evaluation is total and fail-closed: a missing field reads as null,
+// ordering comparisons across types or against null are false, and a
+// non-boolean result is an error the caller treats as "no match".
+package eventfilter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxLength bounds filter source so a stored filter can't make fan-out
+// parse megabytes per subscription refresh.
+const MaxLength = 2000
+
+// Program is a compiled filter. Safe for concurrent use.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses src. The error message points at the offending column.
+func Compile(src string) (*Program, error) {
+	if len(src) > MaxLength {
+		return nil, fmt.Errorf("filter exceeds %d characters", MaxLength)
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at column %d", t.text, t.pos+1)
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String returns the source the program was compiled from.
+func (p *Program) String() string { return p.src }
+
+// Event is the envelope a filter runs against. Payload is the raw event
+// data.
+type Event struct {
+	Type         string
+	Source       string
+	Subject      *string
+	ClientID     *string
+	MessageGroup *string
+	Payload      json.RawMessage
+}
+
+// Input is an Event with its payload decoded, so one event can be matched
+// against many programs without re-parsing the JSON.
+type Input struct {
+	env map[string]any
+}
+
+// NewInput decodes ev's payload. A malformed payload is an error; an
+// empty one reads as null.
+func NewInput(ev Event) (*Input, error) {
+	var payload any
+	if len(ev.Payload) > 0 {
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("decode payload: %w", err)
+		}
+	}
+	return &Input{env: map[string]any{
+		"payload": payload,
+		"event": map[string]any{
+			"type":         ev.Type,
+			"source":       ev.Source,
+			"subject":      deref(ev.Subject),
+			"clientId":     deref(ev.ClientID),
+			"messageGroup": deref(ev.MessageGroup),
+		},
+	}}, nil
+}
+
+// Eval runs the program against ev. Prefer NewInput + Match when the same
+// event is matched against several programs.
+func (p *Program) Eval(ev Event) (bool, error) {
+	in, err := NewInput(ev)
+	if err != nil {
+		return false, err
+	}
+	return p.Match(in)
+}
+
+// Match runs the program against a decoded input.
+func (p *Program) Match(in *Input) (bool, error) {
+	v, err := p.root.eval(in.env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.New("filter did not evaluate to a boolean")
+	}
+	return b, nil
+}
+
+func deref(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// ── AST ──────────────────────────────────────────────────────────────────
+
+type node interface {
+	eval(env map[string]any) (any, error)
+}
+
+type literal struct{ v any }
+
+func (n literal) eval(map[string]any) (any, error) { return n.v, nil }
+
+type listLit struct{ items []node }
+
+func (n listLit) eval(env map[string]any) (any, error) {
+	out := make([]any, 0, len(n.items))
+	for _, it := range n.items {
+		v, err := it.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+type path struct{ segs []string }
+
+// lookup walks the path; found is false when any segment is absent.
+func (n path) lookup(env map[string]any) (v any, found bool) {
+	var cur any = env
+	for _, s := range n.segs {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[s]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func (n path) eval(env map[string]any) (any, error) {
+	v, _ := n.lookup(env)
+	return v, nil
+}
+
+type has struct{ p path }
+
+func (n has) eval(env map[string]any) (any, error) {
+	_, found := n.p.lookup(env)
+	return found, nil
+}
+
+type not struct{ x node }
+
+func (n not) eval(env map[string]any) (any, error) {
+	b, err := evalBool(n.x, env)
+	return !b, err
+}
+
+type logical struct {
+	and  bool
+	l, r node
+}
+
+func (n logical) eval(env map[string]any) (any, error) {
+	l, err := evalBool(n.l, env)
+	if err != nil {
+		return nil, err
+	}
+	if l != n.and {
+		// short-circuit: false && …, true || …
+		return l, nil
+	}
+	return evalBool(n.r, env)
+}
+
+type compare struct {
+	op   string
+	l, r node
+}
+
+func (n compare) eval(env map[string]any) (any, error) {
+	l, err := n.l.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.r.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		list, _ := r.([]any)
+		for _, it := range list {
+			if equal(l, it) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	c, ok := order(l, r)
+	if !ok {
+		return false, nil
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default: // ">="
+		return c >= 0, nil
+	}
+}
+
+type method struct {
+	name string
+	recv node
+	arg  node
+}
+
+func (n method) eval(env map[string]any) (any, error) {
+	recv, err := n.recv.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	arg, err := n.arg.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	s, ok1 := recv.(string)
+	a, ok2 := arg.(string)
+	if !ok1 || !ok2 {
+		return false, nil
+	}
+	switch n.name {
+	case "startsWith":
+		return strings.HasPrefix(s, a), nil
+	case "endsWith":
+		return strings.HasSuffix(s, a), nil
+	default: // "contains"
+		return strings.Contains(s, a), nil
+	}
+}
+
+func evalBool(n node, env map[string]any) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+// equal compares JSON-decoded values. Numbers compare numerically; other
+// types must match exactly. Lists and objects never compare equal.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case nil:
+		return b == nil
+	case float64:
+		y, ok := b.(float64)
+		return ok && x == y
+	case string:
+		y, ok := b.(string)
+		return ok && x == y
+	case bool:
+		y, ok := b.(bool)
+		return ok && x == y
+	default:
+		return false
+	}
+}
+
+// order compares two numbers or two strings; ok is false for anything else.
+func order(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	default:
+		return 0, false
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "list"
+	default:
+		return "object"
+	}
+}
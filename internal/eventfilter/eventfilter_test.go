@@ -0,0 +1,87 @@
+package eventfilter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func evalSrc(t *testing.T, src string, payload string) (bool, error) {
+	t.Helper()
+	p, err := Compile(src)
+	if err != nil {
+		t.Fatalf("compile %q: %v", src, err)
+	}
+	subject := "orders.123"
+	return p.Eval(Event{Type: "orders:sales:order:created", Subject: &subject, Payload: json.RawMessage(payload)})
+}
+
+func TestEval(t *testing.T) {
+	payload := `{"country":"AU","amount":250,"paid":true,"customer":{"tier":"gold"},"note":null}`
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{`payload.country == "AU" && payload.amount > 100`, true},
+		{`payload.country == "AU" && payload.amount > 300`, false},
+		{`payload.country != 'NZ'`, true},
+		{`payload.amount >= 250 && payload.amount <= 250`, true},
+		{`payload.customer.tier in ["gold", "platinum"]`, true},
+		{`payload.country in ["NZ"] || payload.paid`, true},
+		{`!(payload.paid)`, false},
+		{`has(payload.note) && payload.note == null`, true},
+		{`has(payload.missing)`, false},
+		{`payload.missing == null`, true},
+		{`payload.missing > 1`, false},
+		{`payload.country > 1`, false},
+		{`event.type.startsWith("orders:") && event.subject.endsWith(".123")`, true},
+		{`payload.customer.tier.contains("ol")`, true},
+		{`payload.amount == 250.0`, true},
+		{`payload.amount > -1`, true},
+	}
+	for _, tc := range cases {
+		got, err := evalSrc(t, tc.src, payload)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.src, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s = %v, want %v", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestEvalNonBooleanFailsClosed(t *testing.T) {
+	for _, src := range []string{`payload.country`, `payload.amount && true`, `!payload.country`} {
+		if ok, err := evalSrc(t, src, `{"country":"AU","amount":1}`); err == nil || ok {
+			t.Errorf("%s: want error and false, got %v, %v", src, ok, err)
+		}
+	}
+}
+
+func TestEvalShortCircuits(t *testing.T) {
+	// The right-hand side would error (non-boolean) if evaluated.
+	if ok, err := evalSrc(t, `false && payload.country`, `{"country":"AU"}`); err != nil || ok {
+		t.Errorf("&&: got %v, %v", ok, err)
+	}
+	if ok, err := evalSrc(t, `true || payload.country`, `{"country":"AU"}`); err != nil || !ok {
+		t.Errorf("||: got %v, %v", ok, err)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`payload.country ==`,
+		`payload.country == "AU`,
+		`country == "AU"`,
+		`payload.a in "AU"`,
+		`payload.a.lower()`,
+		`(payload.a == 1`,
+		`payload.a == 1 payload.b`,
+		`payload.a # 1`,
+	} {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%q) succeeded, want error", src)
+		}
+	}
+}
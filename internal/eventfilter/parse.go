@@ -0,0 +1,289 @@
+package eventfilter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp    // == != < <= > >= && || !
+	tokPunct // ( ) [ ] , .
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at column %d", i+1)
+			}
+			raw := src[i : j+1]
+			if c == '\'' {
+				raw = `"` + strings.ReplaceAll(raw[1:len(raw)-1], `"`, `\"`) + `"`
+			}
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at column %d", i+1)
+			}
+			toks = append(toks, token{tokString, s, i})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == 'e' || src[j] == 'E') {
+				j++
+			}
+			toks = append(toks, token{tokNumber, src[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, src[i:j], i})
+			i = j
+		default:
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					toks = append(toks, token{tokOp, two, i})
+					i += 2
+					continue
+				}
+			}
+			switch c {
+			case '<', '>', '!':
+				toks = append(toks, token{tokOp, string(c), i})
+			case '(', ')', '[', ']', ',', '.':
+				toks = append(toks, token{tokPunct, string(c), i})
+			default:
+				return nil, fmt.Errorf("unexpected character %q at column %d", c, i+1)
+			}
+			i++
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) accept(kind tokKind, text string) bool {
+	if t := p.peek(); t.kind == kind && t.text == text {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokKind, text string) error {
+	if p.accept(kind, text) {
+		return nil
+	}
+	t := p.peek()
+	if t.kind == tokEOF {
+		return fmt.Errorf("expected %q at end of filter", text)
+	}
+	return fmt.Errorf("expected %q at column %d, got %q", text, t.pos+1, t.text)
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokOp, "||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{and: false, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokOp, "&&") {
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{and: true, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept(tokOp, "!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not{x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && t.text != "&&" && t.text != "||" && t.text != "!":
+		p.next()
+		r, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return compare{op: t.text, l: l, r: r}, nil
+	case t.kind == tokIdent && t.text == "in":
+		p.next()
+		if p.peek().text != "[" {
+			return nil, fmt.Errorf("expected a list after 'in' at column %d", p.peek().pos+1)
+		}
+		r, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return compare{op: "in", l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return literal{t.text}, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at column %d", t.text, t.pos+1)
+		}
+		return literal{f}, nil
+	case tokPunct:
+		switch t.text {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(tokPunct, ")")
+		case "[":
+			var items []node
+			for !p.accept(tokPunct, "]") {
+				if len(items) > 0 {
+					if err := p.expect(tokPunct, ","); err != nil {
+						return nil, err
+					}
+				}
+				it, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, it)
+			}
+			return listLit{items}, nil
+		}
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		case "has":
+			if err := p.expect(tokPunct, "("); err != nil {
+				return nil, err
+			}
+			x, err := p.parsePath(p.next())
+			if err != nil {
+				return nil, err
+			}
+			return has{x}, p.expect(tokPunct, ")")
+		}
+		return p.parsePathOrMethod(t)
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+	return nil, fmt.Errorf("unexpected %q at column %d", t.text, t.pos+1)
+}
+
+func (p *parser) parsePath(first token) (path, error) {
+	if first.kind != tokIdent || (first.text != "payload" && first.text != "event") {
+		return path{}, fmt.Errorf("field paths must start with payload or event (column %d)", first.pos+1)
+	}
+	segs := []string{first.text}
+	for p.peek().text == "." && p.toks[p.i+1].kind == tokIdent && p.toks[p.i+2].text != "(" {
+		p.next()
+		segs = append(segs, p.next().text)
+	}
+	return path{segs}, nil
+}
+
+func (p *parser) parsePathOrMethod(first token) (node, error) {
+	recv, err := p.parsePath(first)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(tokPunct, ".") {
+		return recv, nil
+	}
+	name := p.next()
+	switch name.text {
+	case "startsWith", "endsWith", "contains":
+	default:
+		return nil, fmt.Errorf("unknown method %q at column %d", name.text, name.pos+1)
+	}
+	if err := p.expect(tokPunct, "("); err != nil {
+		return nil, err
+	}
+	arg, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return method{name: name.text, recv: recv, arg: arg}, p.expect(tokPunct, ")")
+}
@@ -21,27 +21,50 @@ const (
 	executionIDKey
 )
 
+// level is shared by every handler Init builds, so SetLevel changes the
+// threshold of the running logger in place.
+var level slog.LevelVar
+
 // Init configures the default slog logger with JSON output to stderr.
 // Level is read from FC_LOG_LEVEL (default info).
 func Init() {
-	lvl := slog.LevelInfo
-	switch os.Getenv("FC_LOG_LEVEL") {
+	level.Set(ParseLevel(os.Getenv("FC_LOG_LEVEL")))
+	h := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &level})
+	slog.SetDefault(slog.New(h))
+}
+
+// ParseLevel maps an FC_LOG_LEVEL value to a level; anything unrecognised
+// (including empty) is info.
+func ParseLevel(s string) slog.Level {
+	switch s {
 	case "debug", "DEBUG":
-		lvl = slog.LevelDebug
+		return slog.LevelDebug
 	case "warn", "WARN", "warning", "WARNING":
-		lvl = slog.LevelWarn
+		return slog.LevelWarn
 	case "error", "ERROR":
-		lvl = slog.LevelError
+		return slog.LevelError
 	}
-	h := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: lvl})
-	slog.SetDefault(slog.New(h))
+	return slog.LevelInfo
 }
 
+// Level returns the current threshold.
+func Level() slog.Level { return level.Level() }
+
+// SetLevel changes the threshold of the logger Init installed (config
+// reload).
+func SetLevel(l slog.Level) { level.Set(l) }
+
 // WithCorrelationID stores a correlation ID on the context for log enrichment.
 func WithCorrelationID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, correlationIDKey, id)
 }
 
+// CorrelationID returns the correlation ID stored on ctx, or "".
+func CorrelationID(ctx context.Context) string {
+	v, _ := ctx.Value(correlationIDKey).(string)
+	return v
+}
+
 // WithCausationID stores a causation ID on the context.
 func WithCausationID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, causationIDKey, id)
@@ -0,0 +1,38 @@
+-- +goose Up
+-- FlowCatalyst — sampled API activity log
+--
+-- One row per *recorded* authenticated API call. Backs the admin API usage
+-- explorer (/bff/api-activity/*): which endpoints each principal / service
+-- account calls, at what rate, and with what error ratio — so stale
+-- integrations and misbehaving automation can be spotted before credentials
+-- are rotated.
+--
+-- Privacy: only the chi route PATTERN is stored (`/api/clients/{id}`), never
+-- the concrete path, query string, body, IP or user agent. The table answers
+-- "who calls what, how often, how badly" and nothing more.
+--
+-- Sampling: successful calls are recorded with probability p and carry
+-- sample_weight = round(1/p); failures (status >= 400) are always recorded
+-- with weight 1. SUM(sample_weight) therefore estimates the true call count.
+--
+-- Append-only like iam_rate_limit_events (migration 030); rows are reaped by
+-- the recorder's prune tick (FC_API_ACTIVITY_RETENTION_DAYS).
+
+CREATE TABLE IF NOT EXISTS iam_api_activity (
+    id             BIGSERIAL PRIMARY KEY,
+    principal_id   VARCHAR(17)  NOT NULL,
+    method         VARCHAR(10)  NOT NULL,
+    route          VARCHAR(255) NOT NULL,
+    status         SMALLINT     NOT NULL,
+    duration_ms    INTEGER      NOT NULL,
+    sample_weight  INTEGER      NOT NULL DEFAULT 1,
+    occurred_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Explorer hot path: per-principal aggregates / recent calls in a window.
+CREATE INDEX IF NOT EXISTS idx_iam_api_activity_principal
+    ON iam_api_activity (principal_id, occurred_at DESC);
+
+-- Reaper-friendly index: lets `DELETE … WHERE occurred_at < $1` walk by time.
+CREATE INDEX IF NOT EXISTS idx_iam_api_activity_occurred_at
+    ON iam_api_activity (occurred_at);
@@ -0,0 +1,32 @@
+-- +goose Up
+-- FlowCatalyst — managed schemas for subscription custom config
+--
+-- msg_subscription_custom_configs is free-form key/value. A config schema
+-- declares which keys a subscription may carry — typed, optionally
+-- required / secret / defaulted — so the UI can render a proper form and
+-- the router can trust the values it reads.
+--
+-- Scope: one schema per (application_code, mediation_type). A NULL
+-- application_code is the platform default for that mediation type and
+-- applies to subscriptions without an application-specific schema.
+-- Subscriptions with no matching schema stay free-form (legacy behaviour).
+--
+-- fields is a JSONB array of ConfigField objects (see
+-- internal/platform/subscription/configschema.go). Fields carry optional
+-- `aliases` so existing entries written under an older key are renamed on
+-- their next save instead of being rejected.
+
+CREATE TABLE IF NOT EXISTS msg_subscription_config_schemas (
+    id                VARCHAR(17)  PRIMARY KEY,
+    application_code  VARCHAR(100),
+    mediation_type    VARCHAR(20)  NOT NULL DEFAULT 'HTTP',
+    description       TEXT,
+    fields            JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    created_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- One schema per scope; COALESCE folds the platform default (NULL app)
+-- into the uniqueness check.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_msg_subscription_config_schemas_scope
+    ON msg_subscription_config_schemas (COALESCE(application_code, ''), mediation_type);
@@ -0,0 +1,15 @@
+-- +goose Up
+-- FlowCatalyst — per-binding event filter expressions
+--
+-- EventTypeBinding.Filter has always been on the wire (SDK sync, admin
+-- API) but was dropped on persist: there was no column for it. Filters are
+-- predicates over the event payload/envelope in the internal/eventfilter
+-- language, e.g.
+--
+--     payload.country == "AU" && payload.amount > 100
+--
+-- The stream fan-out evaluates a binding's filter before creating a
+-- dispatch job; NULL means "every event of the bound type".
+
+ALTER TABLE msg_subscription_event_types
+    ADD COLUMN IF NOT EXISTS filter TEXT;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription outbound authentication
+--
+-- Beyond the scheduler-signed bearer, a subscription may declare how the
+-- platform authenticates to its target when delivering a dispatch job:
+--
+--   AWS_SIGV4                  sign the request with SigV4, optionally as a
+--                              role assumed per subscription (API Gateway /
+--                              Lambda function URLs behind IAM auth)
+--   OAUTH2_CLIENT_CREDENTIALS  fetch (and cache) a bearer from the partner's
+--                              token endpoint
+--
+-- One row per subscription; no row means no target auth. config is the
+-- JSON form of subscription.TargetAuth. The OAuth2 client secret inside it
+-- is encrypted with FLOWCATALYST_APP_KEY (shared/encryption) and is never
+-- returned by the API.
+
+CREATE TABLE IF NOT EXISTS msg_subscription_target_auth (
+    subscription_id  VARCHAR(17)  PRIMARY KEY,
+    auth_type        VARCHAR(40)  NOT NULL,
+    config           JSONB        NOT NULL DEFAULT '{}'::jsonb,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,18 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription payload transformation
+--
+-- By default a delivery carries the raw event data (data_only) or the
+-- platform envelope. A transform maps that envelope to the receiver's own
+-- shape with a Go text/template, rendered by the dispatch-processing
+-- endpoint just before the POST (see internal/platform/subscription/transform).
+--
+-- One row per subscription; no row means no transform. engine is reserved
+-- for other expression languages; only GO_TEMPLATE exists today.
+
+CREATE TABLE IF NOT EXISTS msg_subscription_transforms (
+    subscription_id  VARCHAR(17)   PRIMARY KEY,
+    engine           VARCHAR(20)   NOT NULL DEFAULT 'GO_TEMPLATE',
+    template         TEXT          NOT NULL,
+    content_type     VARCHAR(100)  NOT NULL DEFAULT 'application/json',
+    updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
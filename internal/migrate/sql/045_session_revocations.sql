@@ -0,0 +1,24 @@
+-- +goose Up
+-- FlowCatalyst — session / token revocation list
+--
+-- Checked on every platform JWT validation (session cookies and OAuth
+-- access tokens alike). Two kinds of row share the table, keyed by
+-- (principal_id, token_id):
+--
+--   token_id = ''     "revoke all": every token for the principal issued at
+--                     or before revoked_at is rejected. Written by the admin
+--                     revoke-sessions endpoint (offboarding, suspected
+--                     compromise). One row per principal, moved forward on
+--                     each revoke; never expires.
+--   token_id = <jti>  one token (logout of a single session). expires_at is
+--                     the token's own expiry, after which the row is dead
+--                     weight and is pruned on the principal's next revoke.
+
+CREATE TABLE IF NOT EXISTS iam_session_revocations (
+    principal_id  VARCHAR(17)  NOT NULL,
+    token_id      VARCHAR(64)  NOT NULL DEFAULT '',
+    revoked_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ,
+    revoked_by    VARCHAR(17),
+    PRIMARY KEY (principal_id, token_id)
+);
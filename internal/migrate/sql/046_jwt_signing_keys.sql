@@ -0,0 +1,26 @@
+-- +goose Up
+-- FlowCatalyst — rotated JWT signing keys
+--
+-- Written by the signing-key rotator when FC_JWT_KEY_ROTATION_DAYS is set.
+-- The configured key (FLOWCATALYST_JWT_PRIVATE_KEY & co.) is never stored
+-- here; it signs until the first row activates, then overlaps like any
+-- superseded key.
+--
+--   created_at    when the key was generated; drives the rotation cadence.
+--   activates_at  when it starts signing. Until then it is only published
+--                 in the JWKS, so consumers fetch it before seeing tokens.
+--
+-- private_key_enc is the PKCS#1 PEM encrypted with FLOWCATALYST_APP_KEY.
+-- A row is deleted once its successor has been signing for longer than
+-- the overlap window.
+
+CREATE TABLE IF NOT EXISTS iam_jwt_signing_keys (
+    kid              VARCHAR(64)  PRIMARY KEY,
+    public_key_pem   TEXT         NOT NULL,
+    private_key_enc  TEXT         NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    activates_at     TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_iam_jwt_signing_keys_activates_at
+    ON iam_jwt_signing_keys (activates_at);
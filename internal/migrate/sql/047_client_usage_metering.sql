@@ -0,0 +1,34 @@
+-- +goose Up
+-- FlowCatalyst — per-client usage metering + monthly quotas
+--
+-- msg_client_usage_daily is the billing ledger: one row per (client, UTC
+-- day), incremented by the metering flush loop with additive upserts.
+--
+--   events_ingested  events accepted by POST /api/events[/batch]
+--   deliveries       webhook deliveries that completed with a 2xx
+--
+-- Platform-scoped traffic (no client_id) is not metered.
+--
+-- msg_client_quotas holds per-client overrides of the platform-wide
+-- monthly limits (FC_METERING_DEFAULT_MONTHLY_*). A NULL column falls back
+-- to the default; 0 means unlimited. No row = defaults for both.
+
+CREATE TABLE IF NOT EXISTS msg_client_usage_daily (
+    client_id        VARCHAR(17)  NOT NULL,
+    day              DATE         NOT NULL,
+    events_ingested  BIGINT       NOT NULL DEFAULT 0,
+    deliveries       BIGINT       NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (client_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_client_usage_daily_day
+    ON msg_client_usage_daily (day);
+
+CREATE TABLE IF NOT EXISTS msg_client_quotas (
+    client_id           VARCHAR(17)  PRIMARY KEY,
+    monthly_events      BIGINT,
+    monthly_deliveries  BIGINT,
+    created_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
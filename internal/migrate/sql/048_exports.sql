@@ -0,0 +1,38 @@
+-- +goose Up
+-- FlowCatalyst — bulk exports of events / dispatch jobs to object storage
+--
+-- A row is written (through the UoW) by POST /api/exports and picked up by
+-- the export runner on any instance:
+--
+--   PENDING    queued; claimed with FOR UPDATE SKIP LOCKED
+--   RUNNING    streaming to the bucket; heartbeat_at is bumped while it
+--              runs, and a RUNNING row whose heartbeat goes stale (the
+--              instance died) is claimed again from scratch
+--   COMPLETED  object_key / row_count / byte_size are set
+--   FAILED     error holds the reason
+--
+-- filter is the JSON-encoded export.Filter, including the requesting
+-- principal's accessible-client scope captured at request time.
+
+CREATE TABLE IF NOT EXISTS msg_exports (
+    id            VARCHAR(17)   PRIMARY KEY,
+    kind          VARCHAR(20)   NOT NULL,
+    format        VARCHAR(20)   NOT NULL,
+    filter        JSONB         NOT NULL DEFAULT '{}'::jsonb,
+    status        VARCHAR(20)   NOT NULL DEFAULT 'PENDING',
+    object_key    TEXT,
+    row_count     BIGINT        NOT NULL DEFAULT 0,
+    byte_size     BIGINT        NOT NULL DEFAULT 0,
+    error         TEXT,
+    requested_by  VARCHAR(17),
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    started_at    TIMESTAMPTZ,
+    heartbeat_at  TIMESTAMPTZ,
+    completed_at  TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_exports_status
+    ON msg_exports (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_msg_exports_requested_by
+    ON msg_exports (requested_by, created_at DESC);
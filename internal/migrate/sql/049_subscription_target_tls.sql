@@ -0,0 +1,22 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription TLS material for deliveries
+--
+-- A subscription may carry a client certificate (mutual TLS) and/or a CA
+-- bundle trusted, alongside the system roots, for its target's server
+-- certificate. One row per subscription; no row means default TLS.
+--
+-- client_key_enc is the PEM private key encrypted with FLOWCATALYST_APP_KEY
+-- (shared/encryption) and is never returned by the API. The subject and
+-- expiry columns are parsed at upload so expiry reporting needs neither the
+-- key nor a PEM parse per row.
+
+CREATE TABLE IF NOT EXISTS msg_subscription_target_tls (
+    subscription_id  VARCHAR(17)  PRIMARY KEY,
+    client_cert_pem  TEXT,
+    client_key_enc   TEXT,
+    ca_bundle_pem    TEXT,
+    cert_subject     TEXT,
+    cert_not_after   TIMESTAMPTZ,
+    ca_not_after     TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,17 @@
+-- +goose Up
+-- FlowCatalyst — delivery priority on subscriptions and dispatch jobs
+--
+-- HIGH / NORMAL / LOW. The fan-out copies a subscription's priority onto
+-- every job it creates. The scheduler claims and publishes higher
+-- priorities first and can route them to their own queue
+-- (FC_SCHEDULER_QUEUE_ROUTES, priority:HIGH=...); the router lets HIGH
+-- messages take a free worker ahead of the rest. Existing rows are NORMAL.
+--
+-- The claim index leads with status + message_group, so the priority sort
+-- happens over the already-narrowed PENDING set.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL';
+
+ALTER TABLE msg_dispatch_jobs
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL';
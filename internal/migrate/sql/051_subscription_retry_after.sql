@@ -0,0 +1,12 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription Retry-After handling
+--
+-- When a receiver answers 429 or 503 with a Retry-After header, dispatch
+-- processing reschedules the job that far out (capped by
+-- FC_ROUTER_MAX_RETRY_AFTER_SECONDS). honor_retry_after = FALSE ignores the
+-- header and uses the default delay instead — for receivers that send
+-- unreasonable values. It is read at delivery time, so a change applies to
+-- jobs already pending. Existing rows keep honouring it.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS honor_retry_after BOOLEAN NOT NULL DEFAULT TRUE;
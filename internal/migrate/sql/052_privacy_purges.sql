@@ -0,0 +1,43 @@
+-- +goose Up
+-- FlowCatalyst — right-to-erasure purges by subject identifier
+--
+-- A row is written (through the UoW) by POST /api/admin/platform/privacy/purge
+-- and picked up by the purge runner on any instance:
+--
+--   PENDING    queued; claimed with FOR UPDATE SKIP LOCKED
+--   RUNNING    purging in batches; every batch bumps heartbeat_at and
+--              stores the running totals in report. A RUNNING row whose
+--              heartbeat goes stale is claimed again and carries on — a
+--              batch only ever touches rows that still match, so resuming
+--              is safe
+--   COMPLETED  report holds the final per-table counts
+--   FAILED     error holds the reason
+--
+-- subject_key is the raw identifier being erased. It is kept only while
+-- the purge is outstanding and set to NULL when it finishes either way;
+-- subject_hash (hex SHA-256) is what stays behind to tie the purge to a
+-- later request. subject_paths is the event type → JSON path map in force
+-- when the purge was requested ("*" = every other type).
+
+CREATE TABLE IF NOT EXISTS msg_privacy_purges (
+    id             VARCHAR(17)   PRIMARY KEY,
+    mode           VARCHAR(20)   NOT NULL,
+    subject_key    TEXT,
+    subject_hash   VARCHAR(64)   NOT NULL,
+    subject_paths  JSONB         NOT NULL DEFAULT '{}'::jsonb,
+    reason         TEXT,
+    status         VARCHAR(20)   NOT NULL DEFAULT 'PENDING',
+    report         JSONB         NOT NULL DEFAULT '{}'::jsonb,
+    error          TEXT,
+    requested_by   VARCHAR(17),
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    started_at     TIMESTAMPTZ,
+    heartbeat_at   TIMESTAMPTZ,
+    completed_at   TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_privacy_purges_status
+    ON msg_privacy_purges (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_msg_privacy_purges_subject_hash
+    ON msg_privacy_purges (subject_hash);
@@ -0,0 +1,11 @@
+-- +goose Up
+-- FlowCatalyst — before/after diffs on audit rows
+--
+-- aud_logs.changes holds what an aggregate write changed, as a JSON array
+-- of {path, before, after} entries sorted by path (before absent on a
+-- create, after absent on a delete). Credential-like fields are recorded
+-- as "[REDACTED]". NULL for rows with no aggregate change (emitted-only
+-- events, CommitAll summaries, SDK batch-ingested rows) and for rows
+-- written before this migration.
+
+ALTER TABLE aud_logs ADD COLUMN IF NOT EXISTS changes JSONB;
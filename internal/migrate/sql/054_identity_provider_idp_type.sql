@@ -0,0 +1,12 @@
+-- +goose Up
+-- FlowCatalyst — identity provider adapter selection
+--
+-- oauth_identity_providers.idp_type picks the provider-specific login
+-- adapter (internal/platform/idp) an OIDC provider is handled by:
+-- 'oidc' (generic, the behaviour every existing row keeps), 'keycloak',
+-- 'entra', 'google' (Google Workspace: hd claim enforcement, groups via
+-- Cloud Identity) or 'okta' (groups via the Okta API when the claim is
+-- missing or truncated). Ignored for INTERNAL providers.
+
+ALTER TABLE oauth_identity_providers
+    ADD COLUMN IF NOT EXISTS idp_type VARCHAR(20) NOT NULL DEFAULT 'oidc';
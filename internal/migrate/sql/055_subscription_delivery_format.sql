@@ -0,0 +1,12 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription webhook delivery format
+--
+-- FLOWCATALYST is the platform's own body (the event envelope, or the raw
+-- payload for data-only subscriptions). CLOUDEVENTS_BINARY sends the event
+-- data as the body with the CloudEvents attributes as ce-* headers;
+-- CLOUDEVENTS_STRUCTURED sends the whole event as
+-- application/cloudevents+json. Read at delivery time, so a change applies
+-- to jobs already pending. Existing rows keep the native format.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS delivery_format VARCHAR(30) NOT NULL DEFAULT 'FLOWCATALYST';
@@ -0,0 +1,70 @@
+-- +goose Up
+-- FlowCatalyst — inbound webhook gateway
+--
+-- A source is one third-party sender posting to /ingest/{code}. Requests
+-- are verified against signing_secret in the provider's style (STRIPE,
+-- GITHUB, SHOPIFY) and queued in msg_ingest_deliveries; the ingest runner
+-- turns each into an event of the mapped type:
+--
+--   event_type_mappings  provider event name → event type code
+--                        (e.g. {"invoice.paid": "billing:stripe:invoice:paid"})
+--   event_type_code      fallback when no mapping matches; NULL skips the
+--                        delivery instead
+--   transform_template   optional Go template rendering the event data
+--                        from {source, providerEvent, data}
+--
+-- signing_secret is encrypted with FLOWCATALYST_APP_KEY.
+--
+-- Delivery lifecycle:
+--
+--   PENDING     queued, or waiting for next_attempt_at after a failure
+--   PROCESSING  claimed with FOR UPDATE SKIP LOCKED; a stale heartbeat
+--               puts it back in play
+--   COMPLETED   event_id is the event written
+--   SKIPPED     no event type mapped for provider_event
+--   FAILED      error holds the reason
+--
+-- (source_id, external_id) is unique so provider retries of the same
+-- delivery are acknowledged without being queued twice.
+
+CREATE TABLE IF NOT EXISTS msg_ingest_sources (
+    id                   VARCHAR(17)   PRIMARY KEY,
+    code                 VARCHAR(100)  NOT NULL UNIQUE,
+    name                 VARCHAR(255)  NOT NULL,
+    description          TEXT,
+    provider             VARCHAR(20)   NOT NULL,
+    signing_secret       TEXT          NOT NULL,
+    tolerance_seconds    INTEGER       NOT NULL DEFAULT 300,
+    event_type_code      VARCHAR(255),
+    event_type_mappings  JSONB         NOT NULL DEFAULT '{}'::jsonb,
+    event_source         VARCHAR(255),
+    transform_template   TEXT,
+    client_id            VARCHAR(17),
+    status               VARCHAR(20)   NOT NULL DEFAULT 'ACTIVE',
+    created_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS msg_ingest_deliveries (
+    id               VARCHAR(17)   PRIMARY KEY,
+    source_id        VARCHAR(17)   NOT NULL REFERENCES msg_ingest_sources(id) ON DELETE CASCADE,
+    external_id      VARCHAR(255)  NOT NULL,
+    provider_event   VARCHAR(255),
+    body             TEXT          NOT NULL,
+    status           VARCHAR(20)   NOT NULL DEFAULT 'PENDING',
+    attempts         INTEGER       NOT NULL DEFAULT 0,
+    error            TEXT,
+    event_id         VARCHAR(17),
+    received_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    next_attempt_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    heartbeat_at     TIMESTAMPTZ,
+    completed_at     TIMESTAMPTZ,
+    UNIQUE (source_id, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_ingest_deliveries_due
+    ON msg_ingest_deliveries (next_attempt_at)
+    WHERE status IN ('PENDING', 'PROCESSING');
+
+CREATE INDEX IF NOT EXISTS idx_msg_ingest_deliveries_source
+    ON msg_ingest_deliveries (source_id, received_at DESC);
@@ -0,0 +1,17 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription delivery windows
+--
+-- delivery_window is the subscription's delivery calendar as JSON
+-- (subscription/deliverywindow.Schedule):
+--
+--   {"timezone": "Europe/Berlin",
+--    "windows":   [{"days": ["MON","TUE","WED","THU","FRI"], "start": "08:00", "end": "18:00"}],
+--    "blackouts": [{"start": "2026-12-24T00:00:00Z", "end": "2026-12-27T00:00:00Z", "reason": "freeze"}]}
+--
+-- The scheduler moves a PENDING job claimed outside the calendar to its
+-- next opening (scheduled_for) instead of dispatching it, and stamps the
+-- window's close on the queue message so the router doesn't start a
+-- delivery after it. NULL (every existing row) is always open.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS delivery_window JSONB;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- FlowCatalyst — index for upcoming scheduled dispatch jobs
+--
+-- POST /api/dispatch-jobs accepts scheduledAt / delaySeconds, stored in
+-- the existing scheduled_for column; the scheduler poll already skips
+-- PENDING rows whose scheduled_for is in the future. GET
+-- /api/dispatch-jobs/scheduled lists those rows soonest-first straight
+-- from the write table. Migration 015 dropped the general scheduled_for
+-- index to keep writes lean, so this one is partial: only PENDING rows
+-- that actually carry a schedule.
+
+CREATE INDEX IF NOT EXISTS idx_dispatch_jobs_scheduled_pending
+    ON msg_dispatch_jobs (scheduled_for)
+    WHERE status = 'PENDING' AND scheduled_for IS NOT NULL;
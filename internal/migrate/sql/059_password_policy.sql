@@ -0,0 +1,18 @@
+-- +goose Up
+-- Local-auth password policy + brute-force hardening.
+--
+--   1. iam_principals.password_changed_at backs FC_PASSWORD_MAX_AGE_DAYS:
+--      set whenever a user sets or changes their password (not on the
+--      transparent legacy-hash rehash at login). Existing password rows are
+--      backfilled from updated_at, the closest record we have, so enabling
+--      expiry doesn't lock everyone out at once — nor exempt them forever.
+--   2. A partial index for the per-IP login ceiling (loginattempt.go
+--      FailureCountByIPSince), the IP-keyed twin of migration 037's
+--      identifier-keyed throttle index.
+
+ALTER TABLE iam_principals ADD COLUMN password_changed_at TIMESTAMPTZ;
+UPDATE iam_principals SET password_changed_at = updated_at WHERE password_hash IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_iam_login_attempts_ip_failure_throttle
+    ON iam_login_attempts (ip_address, attempted_at)
+    WHERE outcome = 'FAILURE';
@@ -0,0 +1,25 @@
+-- +goose Up
+-- FlowCatalyst — dispatch job search: keyset pagination + saved filters
+--
+--   1. GET /api/dispatch-jobs/search pages msg_dispatch_jobs_read by
+--      (sort column, id) instead of OFFSET, so page N costs the same as
+--      page 1. The default sort (created_at DESC) seeks on this index;
+--      id breaks ties between rows sharing a timestamp.
+--   2. msg_dispatch_job_saved_filters holds the search criteria a user
+--      saved from the dispatch-job list. Filters are personal: every row
+--      belongs to one principal and names are unique per principal.
+--      criteria is the search query (statuses, clientIds, sort, …) as
+--      JSON so the SPA can replay it.
+
+CREATE INDEX IF NOT EXISTS idx_msg_dispatch_jobs_read_created_id
+    ON msg_dispatch_jobs_read (created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS msg_dispatch_job_saved_filters (
+    id            VARCHAR(17)   PRIMARY KEY,
+    principal_id  VARCHAR(17)   NOT NULL,
+    name          VARCHAR(100)  NOT NULL,
+    criteria      JSONB         NOT NULL DEFAULT '{}'::jsonb,
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (principal_id, name)
+);
@@ -0,0 +1,14 @@
+-- +goose Up
+-- FlowCatalyst — room for KMS envelope ciphertexts
+--
+-- With FC_ENCRYPTION_KMS_KEY_ID set, encrypted fields are stored as v2
+-- envelopes that carry their KMS-wrapped data key (~190 bytes before
+-- base64), so a long OIDC client secret no longer fits the 500-character
+-- reference columns. VARCHAR → TEXT is a catalog-only change in Postgres;
+-- no table rewrite.
+
+ALTER TABLE oauth_clients ALTER COLUMN client_secret_ref TYPE TEXT;
+ALTER TABLE oauth_identity_providers ALTER COLUMN oidc_client_secret_ref TYPE TEXT;
+ALTER TABLE tnt_client_auth_configs ALTER COLUMN oidc_client_secret_ref TYPE TEXT;
+ALTER TABLE iam_service_accounts ALTER COLUMN wh_auth_token_ref TYPE TEXT;
+ALTER TABLE iam_service_accounts ALTER COLUMN wh_signing_secret_ref TYPE TEXT;
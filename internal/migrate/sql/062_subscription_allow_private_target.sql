@@ -0,0 +1,11 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription egress override
+--
+-- Deliveries are refused to private, loopback, link-local and other
+-- reserved ranges (shared/egress). allow_private_target exempts one
+-- subscription from that check; only a holder of
+-- platform:messaging:subscription:egress-override may set it. Cloud
+-- metadata endpoints and the deployment denylist still apply.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS allow_private_target BOOLEAN NOT NULL DEFAULT FALSE;
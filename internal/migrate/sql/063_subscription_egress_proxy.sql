@@ -0,0 +1,10 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription outbound proxy
+--
+-- egress_proxy names one of the deployment's FC_EGRESS_PROXIES; deliveries
+-- for the subscription leave through it instead of FC_EGRESS_PROXY_URL.
+-- A name, not a URL, so a tenant can only pick a proxy the operator
+-- configured. NULL: the deployment default.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS egress_proxy VARCHAR(100);
@@ -0,0 +1,41 @@
+-- +goose Up
+-- FlowCatalyst — dispatch-job status callbacks
+--
+-- A producer that passes statusCallbackUrl when creating a dispatch job is
+-- POSTed a signed status notification once the job is terminal, instead of
+-- polling GET /api/dispatch-jobs/{id}. One row per job that asked for one;
+-- the job tables stay untouched.
+--
+-- Callback lifecycle:
+--
+--   WAITING     registered with the job; the job is not terminal yet
+--   PENDING     the job went terminal (job_status); due at next_attempt_at
+--   PROCESSING  claimed with FOR UPDATE SKIP LOCKED; a stale claimed_at
+--               puts it back in play
+--   DELIVERED   the producer answered 2xx
+--   FAILED      attempts exhausted; error holds the last reason
+--
+-- A job that goes terminal again (requeued by an operator) re-arms its
+-- callback, so the producer hears about the final outcome too.
+
+CREATE TABLE IF NOT EXISTS msg_dispatch_job_callbacks (
+    job_id           VARCHAR(13)   PRIMARY KEY,
+    url              TEXT          NOT NULL,
+    client_id        VARCHAR(17),
+    state            VARCHAR(20)   NOT NULL DEFAULT 'WAITING',
+    job_status       VARCHAR(20),
+    attempts         INTEGER       NOT NULL DEFAULT 0,
+    error            TEXT,
+    next_attempt_at  TIMESTAMPTZ,
+    claimed_at       TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    completed_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_dispatch_job_callbacks_due
+    ON msg_dispatch_job_callbacks (next_attempt_at)
+    WHERE state IN ('PENDING', 'PROCESSING');
+
+CREATE INDEX IF NOT EXISTS idx_msg_dispatch_job_callbacks_completed
+    ON msg_dispatch_job_callbacks (completed_at)
+    WHERE state IN ('DELIVERED', 'FAILED');
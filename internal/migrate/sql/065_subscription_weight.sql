@@ -0,0 +1,11 @@
+-- +goose Up
+-- FlowCatalyst — per-subscription dispatch weight
+--
+-- Within a dispatch pool the router serves waiting subscriptions weighted
+-- round-robin: weight messages from one subscription, then the next, so a
+-- chatty subscription can't starve the others sharing its pool. The
+-- scheduler reads it at dispatch time and carries it on the queue message.
+-- Existing rows get an equal share.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS weight INTEGER NOT NULL DEFAULT 1;
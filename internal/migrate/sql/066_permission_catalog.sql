@@ -0,0 +1,16 @@
+-- +goose Up
+-- FlowCatalyst — per-application permission catalog
+--
+-- iam_permissions becomes the catalog roles are validated against. The
+-- platform's built-in permissions are seeded at startup (source CODE);
+-- applications register theirs through
+-- POST /api/admin/platform/applications/{id}/permissions (source SDK);
+-- rows created from the dashboard stay DATABASE. category is a free-form
+-- grouping label for the permission pickers.
+
+ALTER TABLE iam_permissions
+    ADD COLUMN IF NOT EXISTS application_id VARCHAR(17),
+    ADD COLUMN IF NOT EXISTS category       VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS source         VARCHAR(20) NOT NULL DEFAULT 'DATABASE';
+
+CREATE INDEX IF NOT EXISTS idx_iam_permissions_application ON iam_permissions (application_id);
@@ -0,0 +1,13 @@
+-- +goose Up
+-- FlowCatalyst — client-access grant expiry
+--
+-- A grant may now lapse. NULL keeps the old open-ended behaviour. Reads
+-- that resolve a principal's clients ignore grants past expires_at; the
+-- platform's purger deletes them (emitting client-access-expired) shortly
+-- after, finding them through the partial index.
+
+ALTER TABLE iam_client_access_grants
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_iam_client_access_grants_expires
+    ON iam_client_access_grants (expires_at) WHERE expires_at IS NOT NULL;
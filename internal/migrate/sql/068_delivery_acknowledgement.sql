@@ -0,0 +1,19 @@
+-- +goose Up
+-- FlowCatalyst — receiver-acknowledged deliveries
+--
+-- A subscription with ack_timeout_seconds set doesn't count a 2xx as done:
+-- each delivery carries a signed delivery token (X-Delivery-Token) and the
+-- job waits for POST /api/dispatch/deliveries/{token}/ack. A job still
+-- unacknowledged at ack_deadline is delivered again with the same token,
+-- so the receiver can suppress the duplicate, until its retries run out.
+-- NULL (the default, and every existing row) keeps 2xx-is-done delivery.
+--
+-- acked_at is stamped once, by the first acknowledgement; a repeated ack
+-- of the same token is recognised by it and changes nothing.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS ack_timeout_seconds INTEGER;
+
+ALTER TABLE msg_dispatch_jobs
+    ADD COLUMN IF NOT EXISTS ack_deadline TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS acked_at     TIMESTAMPTZ;
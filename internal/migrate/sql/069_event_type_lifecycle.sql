@@ -0,0 +1,27 @@
+-- +goose Up
+-- FlowCatalyst — event-type deprecation and usage
+--
+-- An event type can be DEPRECATED (status) ahead of its removal: producers
+-- publishing it get Deprecation / Sunset response headers, and no new
+-- subscription may bind it. sunset_at is the announced removal date, NULL
+-- when none has been set; deprecated_at is when it was deprecated.
+--
+-- msg_event_type_producers records who publishes each event type and schema
+-- version, so an owner can see who is still affected before a sunset. Counts
+-- are buffered on the platform instance and added here periodically.
+
+ALTER TABLE msg_event_types
+    ADD COLUMN IF NOT EXISTS deprecated_at    TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS sunset_at        TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deprecation_note TEXT;
+
+CREATE TABLE IF NOT EXISTS msg_event_type_producers (
+    event_type_code VARCHAR(255) NOT NULL,
+    spec_version    VARCHAR(50)  NOT NULL,
+    principal_id    VARCHAR(17)  NOT NULL,
+    event_count     BIGINT       NOT NULL DEFAULT 0,
+    first_seen_at   TIMESTAMPTZ  NOT NULL,
+    last_seen_at    TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (event_type_code, spec_version, principal_id)
+);
+
@@ -0,0 +1,18 @@
+-- +goose Up
+-- FlowCatalyst — platform maintenance mode
+--
+-- While enabled, the platform API answers write requests with 503 and a
+-- Retry-After header; reads, health and the router keep working. One row,
+-- read by every platform instance on a short poll so a toggle through one
+-- instance reaches the rest. FC_MAINTENANCE_MODE forces the mode on
+-- regardless of this row (for when the database itself is going away).
+
+CREATE TABLE IF NOT EXISTS app_maintenance_mode (
+    id                  SMALLINT     PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    enabled             BOOLEAN      NOT NULL DEFAULT FALSE,
+    message             TEXT,
+    retry_after_seconds INTEGER,
+    updated_by          VARCHAR(17),
+    created_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
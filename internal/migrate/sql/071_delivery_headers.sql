@@ -0,0 +1,18 @@
+-- +goose Up
+-- FlowCatalyst — standard delivery headers
+--
+-- Every delivery can carry a standard set of X-FlowCatalyst-* headers
+-- (delivery and event ids, event type, attempt number, first attempt
+-- time, subscription id, signature, trace context) so receivers can
+-- de-duplicate and debug without parsing the body. delivery_headers
+-- picks which a subscription sends; NULL (the default, and every
+-- existing row) sends all of them.
+--
+-- first_attempt_at is stamped by the first delivery attempt and kept
+-- through retries; it backs X-FlowCatalyst-First-Attempted-At.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS delivery_headers TEXT[];
+
+ALTER TABLE msg_dispatch_jobs
+    ADD COLUMN IF NOT EXISTS first_attempt_at TIMESTAMPTZ;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- FlowCatalyst — OAuth client authentication methods
+--
+-- token_endpoint_auth_method is how a confidential client authenticates
+-- at /oauth/token: NULL (every existing row) accepts client_secret_basic
+-- and client_secret_post; 'private_key_jwt' accepts only an RFC 7523
+-- client assertion signed by a key in jwks, the client's public JWK Set.
+--
+-- client_secret_ref now holds an argon2id PHC hash for secrets minted or
+-- rotated from here on. Existing encrypted refs keep verifying until the
+-- client's secret is next rotated.
+
+ALTER TABLE oauth_clients
+    ADD COLUMN IF NOT EXISTS token_endpoint_auth_method VARCHAR(30),
+    ADD COLUMN IF NOT EXISTS jwks TEXT;
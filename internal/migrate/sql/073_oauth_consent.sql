@@ -0,0 +1,37 @@
+-- +goose Up
+-- FlowCatalyst — OAuth consent
+--
+--   1. oauth_clients.first_party marks the platform's own clients, which
+--      skip the consent screen. Every existing client is first-party (they
+--      were all auto-approved until now); partner apps are registered with
+--      first_party = FALSE.
+--   2. oauth_scopes holds the display text the consent screen shows for a
+--      scope. The standard OIDC scopes have built-in text; a row here
+--      overrides it.
+--   3. oauth_consents records what a user approved for a third-party
+--      client: one row per (principal, client_id), scopes being the union
+--      of everything approved so far. /oauth/authorize skips the consent
+--      screen while the requested scopes are covered. Pending consent
+--      requests live in oauth_oidc_payloads (type = 'PendingConsent').
+
+ALTER TABLE oauth_clients
+    ADD COLUMN IF NOT EXISTS first_party BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS oauth_scopes (
+    id          VARCHAR(17)   PRIMARY KEY,
+    name        VARCHAR(100)  NOT NULL UNIQUE,
+    description VARCHAR(500)  NOT NULL,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    principal_id VARCHAR(17)   NOT NULL,
+    client_id    VARCHAR(100)  NOT NULL,
+    scopes       TEXT[]        NOT NULL DEFAULT '{}',
+    granted_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (principal_id, client_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_consents_client_id ON oauth_consents (client_id);
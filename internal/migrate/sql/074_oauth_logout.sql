@@ -0,0 +1,21 @@
+-- +goose Up
+-- FlowCatalyst — OIDC logout
+--
+--   1. oauth_clients.backchannel_logout_uri is where a client receives an
+--      OIDC Back-Channel Logout token. NULL opts the client out.
+--   2. oauth_client_logins records which clients a principal has signed in
+--      to (an authorization_code or device_code token), one row per
+--      (principal, client_id). When the principal logs out or has their
+--      sessions revoked, every client listed here with a back-channel URI
+--      is notified and the rows are removed.
+
+ALTER TABLE oauth_clients
+    ADD COLUMN IF NOT EXISTS backchannel_logout_uri VARCHAR(1000);
+
+CREATE TABLE IF NOT EXISTS oauth_client_logins (
+    principal_id   VARCHAR(17)   NOT NULL,
+    client_id      VARCHAR(100)  NOT NULL,
+    first_login_at TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    last_login_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (principal_id, client_id)
+);
@@ -0,0 +1,24 @@
+-- +goose Up
+-- FlowCatalyst — event ingest deduplication window
+--
+-- A producer that re-sends the same logical event tags both sends with a
+-- sourceEventId. While FC_EVENT_DEDUP_WINDOW_SECS is set, POST /api/events
+-- claims (client_id, event_type, source_event_id) here before inserting;
+-- a second send inside the window gets the first event back instead of a
+-- new one. event_created_at locates the event in the partitioned
+-- msg_events. Rows past expires_at no longer match and are purged by the
+-- platform's dedup loop (the index on expires_at serves the purge).
+-- client_id is '' for platform-scoped events so the key has no NULLs.
+
+CREATE TABLE IF NOT EXISTS msg_event_dedup (
+    client_id        VARCHAR(17)   NOT NULL DEFAULT '',
+    event_type       VARCHAR(200)  NOT NULL,
+    source_event_id  VARCHAR(200)  NOT NULL,
+    event_id         VARCHAR(13)   NOT NULL,
+    event_created_at TIMESTAMPTZ   NOT NULL,
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    expires_at       TIMESTAMPTZ   NOT NULL,
+    PRIMARY KEY (client_id, event_type, source_event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_event_dedup_expires ON msg_event_dedup (expires_at);
@@ -0,0 +1,23 @@
+-- +goose Up
+-- FlowCatalyst — subscription approval decisions
+--
+-- A client with the CLIENT-scoped platform config
+-- platform:subscriptions:approval-required = "true" is regulated: a
+-- subscription created there by a principal without the
+-- platform:messaging:subscription:approve permission starts as
+-- PENDING_APPROVAL and gets no dispatches (fan-out only matches ACTIVE).
+-- An approver's verdict moves it to ACTIVE or REJECTED and is recorded
+-- here. Rows are append-only and outlive the subscription: they are the
+-- audit trail of who approved what, when and why.
+
+CREATE TABLE IF NOT EXISTS msg_subscription_approvals (
+    id              VARCHAR(17)  PRIMARY KEY,
+    subscription_id VARCHAR(17)  NOT NULL,
+    decision        VARCHAR(20)  NOT NULL,
+    decided_by      VARCHAR(17)  NOT NULL,
+    reason          TEXT,
+    decided_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_msg_subscription_approvals_subscription
+    ON msg_subscription_approvals (subscription_id, decided_at DESC);
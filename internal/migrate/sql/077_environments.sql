@@ -0,0 +1,38 @@
+-- +goose Up
+-- FlowCatalyst — sandbox and production environments per client
+--
+-- Every client has a SANDBOX and a PRODUCTION space. A service account
+-- (API key) belongs to one of them and its tokens carry it; events it
+-- ingests are stamped with it, and fan-out only matches subscriptions of
+-- the same environment. Event types created in a sandbox stay there until
+-- promoted. Existing rows are production, so today's behaviour is
+-- unchanged. A subscription code is unique per client AND environment so
+-- promotion can copy a sandbox subscription to production under the same
+-- code.
+
+ALTER TABLE iam_service_accounts
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+
+ALTER TABLE msg_event_types
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+
+ALTER TABLE msg_events
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+
+ALTER TABLE msg_events_read
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+
+DROP INDEX IF EXISTS idx_msg_subscriptions_code_client;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_msg_subscriptions_code_client_env
+    ON msg_subscriptions (code, client_id, environment);
+
+-- Ingest dedup keys are per environment too: a sandbox replaying
+-- production source event IDs must not get production events back.
+ALTER TABLE msg_event_dedup
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+ALTER TABLE msg_event_dedup DROP CONSTRAINT IF EXISTS msg_event_dedup_pkey;
+ALTER TABLE msg_event_dedup
+    ADD PRIMARY KEY (client_id, environment, event_type, source_event_id);
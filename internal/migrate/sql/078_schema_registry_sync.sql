@@ -0,0 +1,22 @@
+-- +goose Up
+-- FlowCatalyst — sync of finalised schemas to an external schema registry
+--
+-- With FC_SCHEMA_REGISTRY_URL set, a runner publishes every finalised
+-- (non-FINALISING) schema version of a production event type to a
+-- Confluent Schema Registry or Apicurio Registry, so consumers outside
+-- FlowCatalyst can discover event contracts. One row per version and
+-- registry records the outcome: SYNCED with the registry's ID for it,
+-- FAILED (retried until attempts run out) or SKIPPED when the registry
+-- can't hold that schema language. Versions with no row are pending.
+
+CREATE TABLE IF NOT EXISTS msg_schema_registry_sync (
+    spec_version_id VARCHAR(17) NOT NULL,
+    registry VARCHAR(500) NOT NULL,
+    state VARCHAR(20) NOT NULL,
+    external_id VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    synced_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (spec_version_id, registry)
+);
@@ -0,0 +1,12 @@
+-- +goose Up
+-- FlowCatalyst — tap subscriptions
+--
+-- A tap is a debugging subscription: fan-out hands it only a sample of
+-- its matching events (tap_sample_percent, 0.01-100), its deliveries
+-- don't count against the client's metered delivery quota, and it stops
+-- receiving events at tap_expires_at. NULL in both columns (the default,
+-- and every existing row) is an ordinary full-volume subscription.
+
+ALTER TABLE msg_subscriptions
+    ADD COLUMN IF NOT EXISTS tap_sample_percent DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS tap_expires_at     TIMESTAMPTZ;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- FlowCatalyst — dispatch jobs carry their environment
+--
+-- A job is stamped with its subscription's SANDBOX or PRODUCTION
+-- environment when fan-out creates it (SDK-created jobs take the calling
+-- key's), and the projection copies it to the read table so the dispatch
+-- job API can keep a sandbox key out of production jobs. Existing rows
+-- are production, matching 077's default for their subscriptions.
+
+ALTER TABLE msg_dispatch_jobs
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
+
+ALTER TABLE msg_dispatch_jobs_read
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) NOT NULL DEFAULT 'PRODUCTION';
@@ -0,0 +1,116 @@
+package mongoconn
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	openDesc = prometheus.NewDesc("fc_mongo_pool_connections",
+		"Open connections in the client's pools.", []string{"client"}, nil)
+	inUseDesc = prometheus.NewDesc("fc_mongo_pool_connections_in_use",
+		"Connections currently checked out of the client's pools.", []string{"client"}, nil)
+	waitingDesc = prometheus.NewDesc("fc_mongo_pool_checkouts_waiting",
+		"Operations waiting for a connection to become available.", []string{"client"}, nil)
+	maxDesc = prometheus.NewDesc("fc_mongo_pool_max_size",
+		"Configured per-server connection pool cap.", []string{"client"}, nil)
+	failedDesc = prometheus.NewDesc("fc_mongo_pool_checkout_failures_total",
+		"Connection checkouts that failed, by driver reason (timeout = pool exhausted).", []string{"client", "reason"}, nil)
+	clearedDesc = prometheus.NewDesc("fc_mongo_pool_cleared_total",
+		"Times a pool was cleared after a server error.", []string{"client"}, nil)
+)
+
+// poolStats tracks one named client's pools from driver pool events.
+type poolStats struct {
+	maxSize atomic.Uint64
+	open    atomic.Int64
+	inUse   atomic.Int64
+	waiting atomic.Int64
+	cleared atomic.Uint64
+
+	mu       sync.Mutex
+	failures map[string]uint64
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[string]*poolStats{}
+)
+
+// track returns the stats for name, creating them on first use.
+func track(name string, maxSize uint64) *poolStats {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	s, ok := pools[name]
+	if !ok {
+		s = &poolStats{failures: map[string]uint64{}}
+		pools[name] = s
+	}
+	s.maxSize.Store(maxSize)
+	return s
+}
+
+func (s *poolStats) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: s.observe}
+}
+
+func (s *poolStats) observe(e *event.PoolEvent) {
+	switch e.Type {
+	case event.ConnectionCreated:
+		s.open.Add(1)
+	case event.ConnectionClosed:
+		s.open.Add(-1)
+	case event.GetStarted:
+		s.waiting.Add(1)
+	case event.GetSucceeded:
+		s.waiting.Add(-1)
+		s.inUse.Add(1)
+	case event.GetFailed:
+		s.waiting.Add(-1)
+		s.mu.Lock()
+		s.failures[e.Reason]++
+		s.mu.Unlock()
+	case event.ConnectionReturned:
+		s.inUse.Add(-1)
+	case event.PoolCleared:
+		s.cleared.Add(1)
+	}
+}
+
+// Collector exports every client's pool stats. It is process-wide:
+// register it once on the metrics registry.
+type Collector struct{}
+
+// Describe is a no-op (unchecked const-metric collector).
+func (Collector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits the gauges and counters per client name.
+func (Collector) Collect(ch chan<- prometheus.Metric) {
+	poolsMu.Lock()
+	names := make([]string, 0, len(pools))
+	snapshot := make(map[string]*poolStats, len(pools))
+	for n, s := range pools {
+		names = append(names, n)
+		snapshot[n] = s
+	}
+	poolsMu.Unlock()
+	sort.Strings(names)
+
+	for _, n := range names {
+		s := snapshot[n]
+		ch <- prometheus.MustNewConstMetric(openDesc, prometheus.GaugeValue, float64(s.open.Load()), n)
+		ch <- prometheus.MustNewConstMetric(inUseDesc, prometheus.GaugeValue, float64(s.inUse.Load()), n)
+		ch <- prometheus.MustNewConstMetric(waitingDesc, prometheus.GaugeValue, float64(s.waiting.Load()), n)
+		ch <- prometheus.MustNewConstMetric(maxDesc, prometheus.GaugeValue, float64(s.maxSize.Load()), n)
+		ch <- prometheus.MustNewConstMetric(clearedDesc, prometheus.CounterValue, float64(s.cleared.Load()), n)
+		s.mu.Lock()
+		for reason, v := range s.failures {
+			ch <- prometheus.MustNewConstMetric(failedDesc, prometheus.CounterValue, float64(v), n, reason)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,150 @@
+// Package mongoconn is the one place MongoDB clients are dialled. Every
+// store that talks to Mongo (standby leases and the region record, router
+// warnings and shards, the outbox) connects through Connect, so they all
+// share the pool and timeout settings from Config and report their
+// connection pools to Prometheus under a per-client name.
+package mongoconn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// defaultMaxPoolSize is the driver's own cap, reported when neither the
+// config nor the URI sets one.
+const defaultMaxPoolSize = 100
+
+// Config holds the client options. Zero fields leave the driver default —
+// or whatever the connection string sets — in place; a set field wins
+// over the same option in the URI.
+type Config struct {
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+	MaxConnIdleTime        time.Duration
+	ConnectTimeout         time.Duration
+	SocketTimeout          time.Duration
+	ServerSelectionTimeout time.Duration
+	// ReadPreference is a mode name: primary, primaryPreferred,
+	// secondary, secondaryPreferred or nearest.
+	ReadPreference string
+	// WriteConcern is "majority" or a node count ("1", "2", ...).
+	WriteConcern string
+	// Compressors lists wire compressors in preference order
+	// (zstd, snappy, zlib).
+	Compressors []string
+}
+
+// ConfigFromEnv reads the FC_MONGO_* variables. Durations are whole
+// milliseconds.
+func ConfigFromEnv() Config {
+	c := Config{
+		MaxConnIdleTime:        millis("FC_MONGO_MAX_CONN_IDLE_MS"),
+		ConnectTimeout:         millis("FC_MONGO_CONNECT_TIMEOUT_MS"),
+		SocketTimeout:          millis("FC_MONGO_SOCKET_TIMEOUT_MS"),
+		ServerSelectionTimeout: millis("FC_MONGO_SERVER_SELECTION_TIMEOUT_MS"),
+		ReadPreference:         envutil.Or("FC_MONGO_READ_PREFERENCE", ""),
+		WriteConcern:           envutil.Or("FC_MONGO_WRITE_CONCERN", ""),
+	}
+	c.MaxPoolSize, _ = envutil.Uint("FC_MONGO_MAX_POOL_SIZE")
+	c.MinPoolSize, _ = envutil.Uint("FC_MONGO_MIN_POOL_SIZE")
+	for _, s := range strings.Split(envutil.Or("FC_MONGO_COMPRESSORS", ""), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			c.Compressors = append(c.Compressors, s)
+		}
+	}
+	return c
+}
+
+func millis(name string) time.Duration {
+	n, _ := envutil.Uint(name)
+	return time.Duration(n) * time.Millisecond
+}
+
+// ClientOptions builds the driver options for uri with c applied on top.
+func (c Config) ClientOptions(uri string) (*options.ClientOptions, error) {
+	o := options.Client().ApplyURI(uri)
+	if c.MaxPoolSize > 0 {
+		o.SetMaxPoolSize(c.MaxPoolSize)
+	}
+	if c.MinPoolSize > 0 {
+		o.SetMinPoolSize(c.MinPoolSize)
+	}
+	if c.MaxConnIdleTime > 0 {
+		o.SetMaxConnIdleTime(c.MaxConnIdleTime)
+	}
+	if c.ConnectTimeout > 0 {
+		o.SetConnectTimeout(c.ConnectTimeout)
+	}
+	if c.SocketTimeout > 0 {
+		o.SetSocketTimeout(c.SocketTimeout)
+	}
+	if c.ServerSelectionTimeout > 0 {
+		o.SetServerSelectionTimeout(c.ServerSelectionTimeout)
+	}
+	if c.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(c.ReadPreference)
+		if err != nil {
+			return nil, fmt.Errorf("mongo read preference: %w", err)
+		}
+		rp, err := readpref.New(mode)
+		if err != nil {
+			return nil, fmt.Errorf("mongo read preference: %w", err)
+		}
+		o.SetReadPreference(rp)
+	}
+	if c.WriteConcern != "" {
+		wc, err := parseWriteConcern(c.WriteConcern)
+		if err != nil {
+			return nil, err
+		}
+		o.SetWriteConcern(wc)
+	}
+	if len(c.Compressors) > 0 {
+		o.SetCompressors(c.Compressors)
+	}
+	if c.MinPoolSize > 0 && o.MaxPoolSize != nil && *o.MaxPoolSize > 0 && c.MinPoolSize > *o.MaxPoolSize {
+		return nil, fmt.Errorf("mongo min pool size %d exceeds max pool size %d", c.MinPoolSize, *o.MaxPoolSize)
+	}
+	return o, o.Validate()
+}
+
+func parseWriteConcern(s string) (*writeconcern.WriteConcern, error) {
+	if strings.EqualFold(s, "majority") {
+		return writeconcern.Majority(), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("mongo write concern %q: want majority or a node count", s)
+	}
+	return &writeconcern.WriteConcern{W: n}, nil
+}
+
+// Connect dials uri with c applied and registers the client's pool under
+// name for the fc_mongo_pool_* series. Clients sharing a name (one per
+// subsystem election, say) are summed.
+func Connect(ctx context.Context, name, uri string, c Config) (*mongo.Client, error) {
+	o, err := c.ClientOptions(uri)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := uint64(defaultMaxPoolSize)
+	if o.MaxPoolSize != nil {
+		maxSize = *o.MaxPoolSize
+	}
+	o.SetPoolMonitor(track(name, maxSize).monitor())
+	client, err := mongo.Connect(ctx, o)
+	if err != nil {
+		return nil, fmt.Errorf("mongo connect: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,77 @@
+package mongoconn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("FC_MONGO_MAX_POOL_SIZE", "250")
+	t.Setenv("FC_MONGO_MIN_POOL_SIZE", "10")
+	t.Setenv("FC_MONGO_SOCKET_TIMEOUT_MS", "1500")
+	t.Setenv("FC_MONGO_READ_PREFERENCE", "secondaryPreferred")
+	t.Setenv("FC_MONGO_WRITE_CONCERN", "majority")
+	t.Setenv("FC_MONGO_COMPRESSORS", "zstd, snappy,")
+
+	c := ConfigFromEnv()
+	assert.Equal(t, uint64(250), c.MaxPoolSize)
+	assert.Equal(t, uint64(10), c.MinPoolSize)
+	assert.Equal(t, 1500*time.Millisecond, c.SocketTimeout)
+	assert.Zero(t, c.ConnectTimeout)
+	assert.Equal(t, []string{"zstd", "snappy"}, c.Compressors)
+
+	o, err := c.ClientOptions("mongodb://localhost:27017/?maxPoolSize=5")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(250), *o.MaxPoolSize, "config overrides the URI")
+	assert.Equal(t, readpref.SecondaryPreferredMode, o.ReadPreference.Mode())
+	assert.Nil(t, o.ConnectTimeout, "unset fields keep the driver default")
+}
+
+func TestClientOptions_Invalid(t *testing.T) {
+	for name, c := range map[string]Config{
+		"read preference": {ReadPreference: "closest"},
+		"write concern":   {WriteConcern: "all"},
+		"min above max":   {MinPoolSize: 20, MaxPoolSize: 10},
+	} {
+		_, err := c.ClientOptions("mongodb://localhost:27017")
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCollector_PoolEvents(t *testing.T) {
+	s := track("test-client", 42)
+	for _, typ := range []string{event.ConnectionCreated, event.ConnectionCreated, event.GetStarted, event.GetSucceeded, event.GetStarted} {
+		s.observe(&event.PoolEvent{Type: typ})
+	}
+	s.observe(&event.PoolEvent{Type: event.GetFailed, Reason: event.ReasonTimedOut})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(Collector{})
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP fc_mongo_pool_connections Open connections in the client's pools.
+# TYPE fc_mongo_pool_connections gauge
+fc_mongo_pool_connections{client="test-client"} 2
+# HELP fc_mongo_pool_connections_in_use Connections currently checked out of the client's pools.
+# TYPE fc_mongo_pool_connections_in_use gauge
+fc_mongo_pool_connections_in_use{client="test-client"} 1
+# HELP fc_mongo_pool_checkouts_waiting Operations waiting for a connection to become available.
+# TYPE fc_mongo_pool_checkouts_waiting gauge
+fc_mongo_pool_checkouts_waiting{client="test-client"} 0
+# HELP fc_mongo_pool_max_size Configured per-server connection pool cap.
+# TYPE fc_mongo_pool_max_size gauge
+fc_mongo_pool_max_size{client="test-client"} 42
+# HELP fc_mongo_pool_checkout_failures_total Connection checkouts that failed, by driver reason (timeout = pool exhausted).
+# TYPE fc_mongo_pool_checkout_failures_total counter
+fc_mongo_pool_checkout_failures_total{client="test-client",reason="timeout"} 1
+`), "fc_mongo_pool_connections", "fc_mongo_pool_connections_in_use", "fc_mongo_pool_checkouts_waiting",
+		"fc_mongo_pool_max_size", "fc_mongo_pool_checkout_failures_total")
+	require.NoError(t, err)
+}
@@ -1,59 +1,268 @@
 package outbox
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
 
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/swaggerui"
 )
 
+// AdminVersion is the outbox admin API version published in its spec.
+var AdminVersion = "dev"
+
+// AdminConfig is the huma config the admin API is served with. Shared with
+// tools/dump-spec so api/outbox-openapi.lock.json matches the live
+// /openapi.json.
+func AdminConfig() huma.Config {
+	cfg := huma.DefaultConfig("FlowCatalyst Outbox Admin API", AdminVersion)
+	// No $schema links: the bodies stay exactly what operators' scripts
+	// have always parsed.
+	cfg.SchemasPath = ""
+	cfg.CreateHooks = nil
+	return cfg
+}
+
 // AdminHandler returns an HTTP handler exposing the operational state machine so
 // an operator can inspect message-group states and pause / resume / unblock /
-// skip a group. StartOutboxProcessor serves it on FC_OUTBOX_ADMIN_PORT when set
-// (the Rust equivalent is the GroupDistributor's programmatic controls — no
-// HTTP — so this is a Go convenience that makes them operable):
+// skip a group, and manage the polled outbox databases. StartOutboxProcessor
+// serves it on FC_OUTBOX_ADMIN_PORT when set (the Rust equivalent is the
+// GroupDistributor's programmatic controls — no HTTP — so this is a Go
+// convenience that makes them operable):
 //
-//	GET  /outbox/groups               — non-default (Paused/Blocked) group states
-//	GET  /outbox/groups/blocked       — Blocked groups only
-//	POST /outbox/groups/{group}/pause
-//	POST /outbox/groups/{group}/resume
-//	POST /outbox/groups/{group}/unblock  — clear + re-queue the poison (retry)
-//	POST /outbox/groups/{group}/skip     — clear + leave the poison failed
-func (p *Processor) AdminHandler() http.Handler {
+//	GET    /outbox/groups               — non-default (Paused/Blocked) group states
+//	GET    /outbox/groups/blocked       — Blocked groups only
+//	POST   /outbox/groups/{group}/pause
+//	POST   /outbox/groups/{group}/resume
+//	POST   /outbox/groups/{group}/unblock  — clear + re-queue the poison (retry)
+//	POST   /outbox/groups/{group}/skip     — clear + leave the poison failed
+//	GET    /outbox/sources              — every source's health and counters
+//	POST   /outbox/sources              — start polling another database
+//	DELETE /outbox/sources/{name}       — stop polling it
+//
+// Group routes act on DefaultSource unless ?source= names another.
+// The spec is served at /openapi.json and browsable at /swagger.
+func (s *Sources) AdminHandler() http.Handler {
 	r := chi.NewRouter()
-	r.Get("/outbox/groups", func(w http.ResponseWriter, _ *http.Request) {
-		writeAdminJSON(w, http.StatusOK, map[string]any{"groups": p.GroupStates()})
+	RegisterAdmin(humachi.New(r, AdminConfig()), s)
+	r.Method(http.MethodGet, "/swagger", swaggerui.Handler("FlowCatalyst Outbox Admin API", "/openapi.json"))
+	return r
+}
+
+const (
+	tagOutboxGroups  = "outbox-groups"
+	tagOutboxSources = "outbox-sources"
+)
+
+const errSourceNotFound = "source not found"
+
+// RegisterAdmin mounts the admin operations on api.
+func RegisterAdmin(api huma.API, s *Sources) {
+	registerGroups(api, s)
+	registerSources(api, s)
+}
+
+func registerGroups(api huma.API, s *Sources) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listOutboxGroups", Method: http.MethodGet, Path: "/outbox/groups",
+		Summary: "List Paused and Blocked message groups", Tags: []string{tagOutboxGroups}, DefaultStatus: http.StatusOK,
+		Responses: sourceNotFoundResponse(),
+	}, func(_ context.Context, in *sourceInput) (*groupsOutput, error) {
+		p := sourceProcessor(s, in.Source)
+		if p == nil {
+			return &groupsOutput{Status: http.StatusNotFound, Body: groupsBody{Error: errSourceNotFound}}, nil
+		}
+		return &groupsOutput{Status: http.StatusOK, Body: groupsBody{Groups: p.GroupStates()}}, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "listBlockedOutboxGroups", Method: http.MethodGet, Path: "/outbox/groups/blocked",
+		Summary: "List Blocked message groups", Tags: []string{tagOutboxGroups}, DefaultStatus: http.StatusOK,
+		Responses: sourceNotFoundResponse(),
+	}, func(_ context.Context, in *sourceInput) (*blockedOutput, error) {
+		p := sourceProcessor(s, in.Source)
+		if p == nil {
+			return &blockedOutput{Status: http.StatusNotFound, Body: blockedBody{Error: errSourceNotFound}}, nil
+		}
+		return &blockedOutput{Status: http.StatusOK, Body: blockedBody{Blocked: p.BlockedGroups()}}, nil
 	})
-	r.Get("/outbox/groups/blocked", func(w http.ResponseWriter, _ *http.Request) {
-		writeAdminJSON(w, http.StatusOK, map[string]any{"blocked": p.BlockedGroups()})
+	huma.Register(api, huma.Operation{
+		OperationID: "pauseOutboxGroup", Method: http.MethodPost, Path: "/outbox/groups/{group}/pause",
+		Summary: "Pause a message group", Tags: []string{tagOutboxGroups}, DefaultStatus: http.StatusOK,
+		Responses: sourceNotFoundResponse(),
+	}, func(_ context.Context, in *groupInput) (*groupActionOutput, error) {
+		p := sourceProcessor(s, in.Source)
+		if p == nil {
+			return groupActionError(errSourceNotFound), nil
+		}
+		p.PauseGroup(in.Group)
+		return groupAction("PAUSED"), nil
 	})
-	r.Post("/outbox/groups/{group}/pause", func(w http.ResponseWriter, req *http.Request) {
-		p.PauseGroup(chi.URLParam(req, "group"))
-		writeAdminJSON(w, http.StatusOK, map[string]string{"status": "PAUSED"})
+	huma.Register(api, huma.Operation{
+		OperationID: "resumeOutboxGroup", Method: http.MethodPost, Path: "/outbox/groups/{group}/resume",
+		Summary: "Resume a Paused message group", Tags: []string{tagOutboxGroups}, DefaultStatus: http.StatusOK,
+		Responses: sourceNotFoundResponse(),
+	}, func(_ context.Context, in *groupInput) (*groupActionOutput, error) {
+		p := sourceProcessor(s, in.Source)
+		if p == nil {
+			return groupActionError(errSourceNotFound), nil
+		}
+		p.ResumeGroup(in.Group)
+		return groupAction("RUNNING"), nil
 	})
-	r.Post("/outbox/groups/{group}/resume", func(w http.ResponseWriter, req *http.Request) {
-		p.ResumeGroup(chi.URLParam(req, "group"))
-		writeAdminJSON(w, http.StatusOK, map[string]string{"status": "RUNNING"})
+	huma.Register(api, huma.Operation{
+		OperationID: "unblockOutboxGroup", Method: http.MethodPost, Path: "/outbox/groups/{group}/unblock",
+		Summary: "Unblock a message group, re-queuing its poison item",
+		Tags:    []string{tagOutboxGroups}, DefaultStatus: http.StatusOK,
+		Responses: notBlockedResponse(),
+	}, func(ctx context.Context, in *groupInput) (*groupActionOutput, error) {
+		p := sourceProcessor(s, in.Source)
+		if p == nil {
+			return groupActionError(errSourceNotFound), nil
+		}
+		if !p.UnblockGroup(ctx, in.Group) {
+			return groupNotBlocked(), nil
+		}
+		return groupAction("UNBLOCKED"), nil
 	})
-	r.Post("/outbox/groups/{group}/unblock", func(w http.ResponseWriter, req *http.Request) {
-		if p.UnblockGroup(req.Context(), chi.URLParam(req, "group")) {
-			writeAdminJSON(w, http.StatusOK, map[string]string{"status": "UNBLOCKED"})
-			return
+	huma.Register(api, huma.Operation{
+		OperationID: "skipOutboxGroup", Method: http.MethodPost, Path: "/outbox/groups/{group}/skip",
+		Summary: "Unblock a message group, leaving its poison item failed",
+		Tags:    []string{tagOutboxGroups}, DefaultStatus: http.StatusOK,
+		Responses: notBlockedResponse(),
+	}, func(_ context.Context, in *groupInput) (*groupActionOutput, error) {
+		p := sourceProcessor(s, in.Source)
+		if p == nil {
+			return groupActionError(errSourceNotFound), nil
+		}
+		if !p.SkipGroup(in.Group) {
+			return groupNotBlocked(), nil
 		}
-		writeAdminJSON(w, http.StatusNotFound, map[string]string{"error": "group not blocked"})
+		return groupAction("SKIPPED"), nil
 	})
-	r.Post("/outbox/groups/{group}/skip", func(w http.ResponseWriter, req *http.Request) {
-		if p.SkipGroup(chi.URLParam(req, "group")) {
-			writeAdminJSON(w, http.StatusOK, map[string]string{"status": "SKIPPED"})
-			return
+}
+
+func registerSources(api huma.API, s *Sources) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listOutboxSources", Method: http.MethodGet, Path: "/outbox/sources",
+		Summary: "List polled outbox databases with their health and counters", Tags: []string{tagOutboxSources}, DefaultStatus: http.StatusOK,
+	}, func(ctx context.Context, _ *struct{}) (*sourcesOutput, error) {
+		return &sourcesOutput{Body: sourcesBody{Sources: s.Status(ctx)}}, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "addOutboxSource", Method: http.MethodPost, Path: "/outbox/sources",
+		Summary: "Start polling another outbox database, until restart",
+		Tags:    []string{tagOutboxSources}, DefaultStatus: http.StatusCreated,
+		Responses: map[string]*huma.Response{
+			"400": {Description: "The source is invalid or its database can't be reached"},
+			"409": {Description: "A source with that name exists"},
+		},
+	}, func(ctx context.Context, in *addSourceInput) (*groupActionOutput, error) {
+		switch err := s.Add(ctx, in.Body); {
+		case errors.Is(err, ErrSourceExists):
+			return &groupActionOutput{Status: http.StatusConflict, Body: groupActionBody{Error: err.Error()}}, nil
+		case err != nil:
+			return &groupActionOutput{Status: http.StatusBadRequest, Body: groupActionBody{Error: err.Error()}}, nil
 		}
-		writeAdminJSON(w, http.StatusNotFound, map[string]string{"error": "group not blocked"})
+		return &groupActionOutput{Status: http.StatusCreated, Body: groupActionBody{Status: "POLLING"}}, nil
 	})
-	return r
+	huma.Register(api, huma.Operation{
+		OperationID: "removeOutboxSource", Method: http.MethodDelete, Path: "/outbox/sources/{name}",
+		Summary: "Stop polling an outbox database", Tags: []string{tagOutboxSources}, DefaultStatus: http.StatusOK,
+		Responses: sourceNotFoundResponse(),
+	}, func(_ context.Context, in *sourceNameInput) (*groupActionOutput, error) {
+		if err := s.Remove(in.Name); err != nil {
+			return groupActionError(errSourceNotFound), nil
+		}
+		return groupAction("REMOVED"), nil
+	})
+}
+
+type sourceInput struct {
+	Source string `query:"source" doc:"Outbox source; default when omitted"`
+}
+
+// sourceProcessor resolves a group route's ?source=, DefaultSource when
+// omitted; nil when there is no such source.
+func sourceProcessor(s *Sources, name string) *Processor {
+	if name == "" {
+		name = DefaultSource
+	}
+	return s.Processor(name)
+}
+
+type groupInput struct {
+	Source string `query:"source" doc:"Outbox source; default when omitted"`
+	Group  string `path:"group" doc:"Message group"`
+}
+
+type sourceNameInput struct {
+	Name string `path:"name" doc:"Source name"`
+}
+
+type addSourceInput struct {
+	Body SourceSpec
+}
+
+type sourcesBody struct {
+	Sources []SourceStatus `json:"sources"`
+}
+
+type sourcesOutput struct{ Body sourcesBody }
+
+type groupsBody struct {
+	Groups []GroupInfo `json:"groups"`
+	Error  string      `json:"error,omitempty"`
+}
+
+type groupsOutput struct {
+	Status int
+	Body   groupsBody
+}
+
+type blockedBody struct {
+	Blocked []GroupInfo `json:"blocked"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type blockedOutput struct {
+	Status int
+	Body   blockedBody
+}
+
+// groupActionBody is {"status": …} on success and {"error": …} when the
+// group isn't Blocked — the shapes the admin API has always answered.
+type groupActionBody struct {
+	Status string `json:"status,omitempty" doc:"The group's status after the action"`
+	Error  string `json:"error,omitempty"`
+}
+
+type groupActionOutput struct {
+	Status int
+	Body   groupActionBody
+}
+
+func groupAction(status string) *groupActionOutput {
+	return &groupActionOutput{Status: http.StatusOK, Body: groupActionBody{Status: status}}
+}
+
+func groupActionError(msg string) *groupActionOutput {
+	return &groupActionOutput{Status: http.StatusNotFound, Body: groupActionBody{Error: msg}}
+}
+
+func groupNotBlocked() *groupActionOutput { return groupActionError("group not blocked") }
+
+func notBlockedResponse() map[string]*huma.Response {
+	return map[string]*huma.Response{
+		"404": {Description: "The group is not Blocked, or the source doesn't exist"},
+	}
 }
 
-func writeAdminJSON(w http.ResponseWriter, status int, v any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(v)
+func sourceNotFoundResponse() map[string]*huma.Response {
+	return map[string]*huma.Response{
+		"404": {Description: "The source doesn't exist"},
+	}
 }
@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func adminDo(t *testing.T, h http.Handler, method, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec.Code, strings.TrimSpace(rec.Body.String())
+}
+
+// The admin API answers the same JSON it did before it was described in
+// OpenAPI, and serves that description next to it.
+func TestAdminHandlerShapes(t *testing.T) {
+	s := NewSources(Config{}, nil)
+	if err := s.Attach(context.Background(), SourceSpec{Name: DefaultSource}, &stubRepo{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	s.Processor(DefaultSource).groups.Block("g1", "item-1", "boom")
+	h := s.AdminHandler()
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/outbox/groups/blocked", 200, `{"blocked":[{"group":"g1","status":"BLOCKED","blockedItemId":"item-1","error":"boom"}]}`},
+		{http.MethodPost, "/outbox/groups/g2/pause", 200, `{"status":"PAUSED"}`},
+		{http.MethodPost, "/outbox/groups/g2/resume", 200, `{"status":"RUNNING"}`},
+		{http.MethodPost, "/outbox/groups/g1/skip", 200, `{"status":"SKIPPED"}`},
+		{http.MethodPost, "/outbox/groups/g1/unblock", 404, `{"error":"group not blocked"}`},
+		{http.MethodGet, "/outbox/groups?source=default", 200, `{"groups":[]}`},
+		{http.MethodGet, "/outbox/groups/blocked?source=tenant-a", 404, `{"blocked":null,"error":"source not found"}`},
+		{http.MethodPost, "/outbox/groups/g1/pause?source=tenant-a", 404, `{"error":"source not found"}`},
+		{http.MethodDelete, "/outbox/sources/tenant-a", 404, `{"error":"source not found"}`},
+	}
+	for _, c := range cases {
+		code, body := adminDo(t, h, c.method, c.path)
+		if code != c.code || body != c.body {
+			t.Errorf("%s %s = %d %s, want %d %s", c.method, c.path, code, body, c.code, c.body)
+		}
+	}
+
+	if code, body := adminDo(t, h, http.MethodGet, "/openapi.json"); code != 200 || !strings.Contains(body, "/outbox/groups/{group}/unblock") {
+		t.Errorf("GET /openapi.json = %d, want the admin spec", code)
+	}
+	if code, body := adminDo(t, h, http.MethodGet, "/swagger"); code != 200 || !strings.Contains(body, `"/openapi.json"`) {
+		t.Errorf("GET /swagger = %d, want the Swagger UI page", code)
+	}
+}
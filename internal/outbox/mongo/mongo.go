@@ -32,6 +32,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/outbox"
 )
 
@@ -51,12 +52,12 @@ func New(client *mongo.Client, dbName string) *Repository {
 	}
 }
 
-// Connect dials the supplied URI and returns a repository. The caller owns
-// the returned client's lifetime via Close.
-func Connect(ctx context.Context, uri, dbName string) (*Repository, error) {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+// Connect dials the supplied URI with mc and returns a repository. The
+// caller owns the returned client's lifetime via Close.
+func Connect(ctx context.Context, uri, dbName string, mc mongoconn.Config) (*Repository, error) {
+	client, err := mongoconn.Connect(ctx, "outbox", uri, mc)
 	if err != nil {
-		return nil, fmt.Errorf("mongo connect: %w", err)
+		return nil, err
 	}
 	return New(client, dbName), nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
 )
 
 // Config tunes the outbox processor.
@@ -38,6 +39,11 @@ type Config struct {
 	// releasing the rest to re-run in order behind it (OB4 ordering guarantee).
 	// Default true, matching Rust block_on_error. Ungrouped items are unaffected.
 	BlockOnError bool
+	// Publisher, when non-nil, switches on direct queue mode: dispatch jobs
+	// are published to the router's queue as message pointers instead of
+	// posted to the platform (see QueueDispatcher). Events and audit logs
+	// still use PlatformURL.
+	Publisher queue.Publisher
 }
 
 // DefaultConfig matches the Rust outbox defaults.
@@ -63,7 +69,7 @@ func DefaultConfig() Config {
 type Processor struct {
 	cfg          Config
 	repo         Repository
-	dispatcher   *HTTPDispatcher
+	dispatcher   Dispatcher
 	distributor  *GroupDistributor
 	groups       *GroupStateManager
 	inFlight     atomic.Int64
@@ -79,8 +85,12 @@ type Processor struct {
 
 // NewProcessor wires a processor.
 func NewProcessor(cfg Config, repo Repository) *Processor {
-	d := NewHTTPDispatcher(cfg.PlatformURL, cfg.AuthToken, cfg.HTTPTimeout)
-	d.tokenSource = cfg.TokenSource
+	h := NewHTTPDispatcher(cfg.PlatformURL, cfg.AuthToken, cfg.HTTPTimeout)
+	h.tokenSource = cfg.TokenSource
+	var d Dispatcher = h
+	if cfg.Publisher != nil {
+		d = NewQueueDispatcher(cfg.Publisher, h)
+	}
 	return &Processor{
 		cfg:         cfg,
 		repo:        repo,
@@ -0,0 +1,161 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// Dispatcher sends outbox items on. HTTPDispatcher posts them to the
+// platform API; QueueDispatcher publishes dispatch jobs to the router's
+// queue directly.
+type Dispatcher interface {
+	// SendBatch sends items of the SAME ItemType and returns an outcome
+	// keyed by outbox item id for every one of them.
+	SendBatch(ctx context.Context, items []Item) map[string]DispatchOutcome
+	// Send sends one item.
+	Send(ctx context.Context, item Item) DispatchOutcome
+}
+
+// QueueDispatcher is direct queue mode: DISPATCH_JOB items are published as
+// message pointers to the queue the router consumes, skipping the platform
+// hop, for a processor inside the platform's trust boundary. The router
+// POSTs {messageId} to the job's targetUrl as it does for any pointer.
+//
+// Events and audit logs are records the platform stores, so they still go
+// through the platform API (fallback). A direct-mode job has no platform
+// row, so the features that hang off one — a payload, delayed dispatch,
+// status callbacks — are refused as BAD_REQUEST rather than silently
+// dropped.
+type QueueDispatcher struct {
+	publisher queue.Publisher
+	fallback  Dispatcher
+}
+
+// NewQueueDispatcher wires direct queue mode over publisher, sending
+// non-dispatch-job items through fallback.
+func NewQueueDispatcher(publisher queue.Publisher, fallback Dispatcher) *QueueDispatcher {
+	return &QueueDispatcher{publisher: publisher, fallback: fallback}
+}
+
+// SendBatch publishes a dispatch-job batch in one PublishBatch call. A
+// publish error fails the batch retryably (GATEWAY_ERROR), like a platform
+// transport failure.
+func (d *QueueDispatcher) SendBatch(ctx context.Context, items []Item) map[string]DispatchOutcome {
+	if len(items) == 0 {
+		return map[string]DispatchOutcome{}
+	}
+	if items[0].ItemType != common.OutboxItemDispatchJob {
+		return d.fallback.SendBatch(ctx, items)
+	}
+	out := make(map[string]DispatchOutcome, len(items))
+	var (
+		publish []Item
+		msgs    []common.Message
+	)
+	for _, it := range items {
+		msg, err := messagePointer(it)
+		if err != nil {
+			out[it.ID] = DispatchOutcome{Status: common.OutboxBadRequest, Message: err.Error()}
+			continue
+		}
+		publish = append(publish, it)
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return out
+	}
+	if _, err := d.publisher.PublishBatch(ctx, msgs); err != nil {
+		for id, o := range failAll(publish, common.OutboxGatewayError, "publish: "+err.Error()) {
+			out[id] = o
+		}
+		return out
+	}
+	for _, it := range publish {
+		out[it.ID] = DispatchOutcome{Status: common.OutboxSuccess}
+	}
+	return out
+}
+
+// Send publishes a single item through SendBatch.
+func (d *QueueDispatcher) Send(ctx context.Context, item Item) DispatchOutcome {
+	if o, ok := d.SendBatch(ctx, []Item{item})[item.ID]; ok {
+		return o
+	}
+	return DispatchOutcome{Status: common.OutboxInternalError, Message: "no outcome for item"}
+}
+
+// pointerJob is the part of a DISPATCH_JOB payload (the platform's
+// dispatch-jobs batch item) a message pointer can carry, plus the fields
+// it can't, so they are refused rather than lost.
+type pointerJob struct {
+	ID                *string `json:"id"`
+	TargetURL         string  `json:"targetUrl"`
+	MessageGroup      *string `json:"messageGroup"`
+	Mode              string  `json:"mode"`
+	Priority          string  `json:"priority"`
+	TimeoutSeconds    uint32  `json:"timeoutSeconds"`
+	SubscriptionID    *string `json:"subscriptionId"`
+	Payload           *string `json:"payload"`
+	StatusCallbackURL *string `json:"statusCallbackUrl"`
+	ScheduledAt       *string `json:"scheduledAt"`
+	DelaySeconds      *uint32 `json:"delaySeconds"`
+}
+
+// messagePointer renders a DISPATCH_JOB item as the router's queue message.
+// The pointer id is the job's own id when the app set one, else the outbox
+// row's; the deduplication id is per attempt, as the scheduler's is, so a
+// re-publish after a failed attempt isn't dropped by a FIFO queue.
+func messagePointer(it Item) (common.Message, error) {
+	var job pointerJob
+	if err := json.Unmarshal(it.Payload, &job); err != nil {
+		return common.Message{}, errors.New("payload is not a dispatch job: " + err.Error())
+	}
+	switch {
+	case job.TargetURL == "":
+		return common.Message{}, errors.New("targetUrl is required")
+	case job.Payload != nil && *job.Payload != "":
+		return common.Message{}, errors.New("queue mode delivers message pointers; a payload can't be carried")
+	case job.StatusCallbackURL != nil:
+		return common.Message{}, errors.New("queue mode doesn't support statusCallbackUrl")
+	case job.ScheduledAt != nil || job.DelaySeconds != nil:
+		return common.Message{}, errors.New("queue mode doesn't support delayed dispatch")
+	}
+	u, err := url.Parse(job.TargetURL)
+	if err != nil || u.Host == "" {
+		return common.Message{}, errors.New("targetUrl is not an absolute URL")
+	}
+
+	id := it.ID
+	if job.ID != nil && *job.ID != "" {
+		id = *job.ID
+	}
+	dedupID := id + "-" + strconv.Itoa(it.AttemptCount)
+	msg := common.Message{
+		ID:              id,
+		MediationType:   common.MediationTypeHTTP,
+		MediationTarget: job.TargetURL,
+		DeduplicationID: &dedupID,
+		DispatchMode:    common.ParseDispatchMode(job.Mode),
+		HighPriority:    common.ParsePriority(job.Priority) == common.PriorityHigh,
+		TimeoutSeconds:  job.TimeoutSeconds,
+		TargetHost:      u.Host,
+	}
+	switch {
+	case job.MessageGroup != nil && *job.MessageGroup != "":
+		group := *job.MessageGroup
+		msg.MessageGroupID = &group
+	case it.MessageGroup != nil && *it.MessageGroup != "":
+		group := *it.MessageGroup
+		msg.MessageGroupID = &group
+	}
+	if job.SubscriptionID != nil {
+		msg.SubscriptionID = *job.SubscriptionID
+	}
+	return msg, nil
+}
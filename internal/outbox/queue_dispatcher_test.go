@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// recordingPublisher records published messages, or fails every publish.
+type recordingPublisher struct {
+	msgs []common.Message
+	err  error
+}
+
+func (p *recordingPublisher) Identifier() string { return "test-queue" }
+
+func (p *recordingPublisher) Publish(ctx context.Context, m common.Message) (string, error) {
+	ids, err := p.PublishBatch(ctx, []common.Message{m})
+	if err != nil {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+func (p *recordingPublisher) PublishBatch(_ context.Context, msgs []common.Message) ([]string, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.msgs = append(p.msgs, msgs...)
+	ids := make([]string, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.ID
+	}
+	return ids, nil
+}
+
+// recordingDispatcher stands in for the platform API.
+type recordingDispatcher struct{ sent []Item }
+
+func (d *recordingDispatcher) SendBatch(_ context.Context, items []Item) map[string]DispatchOutcome {
+	d.sent = append(d.sent, items...)
+	return failAll(items, common.OutboxSuccess, "")
+}
+
+func (d *recordingDispatcher) Send(ctx context.Context, item Item) DispatchOutcome {
+	return d.SendBatch(ctx, []Item{item})[item.ID]
+}
+
+func jobItem(id, payload string) Item {
+	return Item{ID: id, ItemType: common.OutboxItemDispatchJob, Payload: json.RawMessage(payload), AttemptCount: 1}
+}
+
+func TestQueueDispatcherPublishesPointers(t *testing.T) {
+	pub := &recordingPublisher{}
+	platform := &recordingDispatcher{}
+	d := NewQueueDispatcher(pub, platform)
+
+	group := "order-1"
+	grouped := jobItem("ob2", `{"targetUrl":"https://app.internal/hooks/x","priority":"HIGH","mode":"BLOCK_ON_ERROR","timeoutSeconds":10}`)
+	grouped.MessageGroup = &group
+	out := d.SendBatch(context.Background(), []Item{
+		jobItem("ob1", `{"id":"job-1","targetUrl":"https://app.internal/hooks/x","subscriptionId":"sub-1"}`),
+		grouped,
+		jobItem("ob3", `{"targetUrl":"https://app.internal/hooks/x","payload":"{}"}`),
+		jobItem("ob4", `{"targetUrl":"https://app.internal/hooks/x","delaySeconds":30}`),
+		jobItem("ob5", `{"targetUrl":"/relative"}`),
+	})
+
+	for id, want := range map[string]common.OutboxStatus{
+		"ob1": common.OutboxSuccess,
+		"ob2": common.OutboxSuccess,
+		"ob3": common.OutboxBadRequest,
+		"ob4": common.OutboxBadRequest,
+		"ob5": common.OutboxBadRequest,
+	} {
+		if out[id].Status != want {
+			t.Errorf("%s = %v (%s), want %v", id, out[id].Status, out[id].Message, want)
+		}
+	}
+	if len(pub.msgs) != 2 {
+		t.Fatalf("published %d messages, want 2", len(pub.msgs))
+	}
+	first, second := pub.msgs[0], pub.msgs[1]
+	if first.ID != "job-1" || *first.DeduplicationID != "job-1-1" || first.SubscriptionID != "sub-1" || first.MessageGroupID != nil {
+		t.Errorf("first pointer = %+v", first)
+	}
+	if first.MediationType != common.MediationTypeHTTP || first.MediationTarget != "https://app.internal/hooks/x" || first.TargetHost != "app.internal" {
+		t.Errorf("first pointer target = %+v", first)
+	}
+	if second.ID != "ob2" || !second.HighPriority || second.DispatchMode != common.DispatchBlockOnError ||
+		second.TimeoutSeconds != 10 || second.MessageGroupID == nil || *second.MessageGroupID != "order-1" {
+		t.Errorf("second pointer = %+v", second)
+	}
+	if len(platform.sent) != 0 {
+		t.Errorf("dispatch jobs reached the platform: %+v", platform.sent)
+	}
+}
+
+// Events and audit logs are stored by the platform, so they keep going
+// over HTTP.
+func TestQueueDispatcherSendsEventsToPlatform(t *testing.T) {
+	pub := &recordingPublisher{}
+	platform := &recordingDispatcher{}
+	d := NewQueueDispatcher(pub, platform)
+
+	ev := Item{ID: "ob1", ItemType: common.OutboxItemEvent, Payload: json.RawMessage(`{}`)}
+	if o := d.Send(context.Background(), ev); o.Status != common.OutboxSuccess {
+		t.Fatalf("event = %+v", o)
+	}
+	if len(platform.sent) != 1 || len(pub.msgs) != 0 {
+		t.Fatalf("platform got %d, queue got %d; want the event on the platform", len(platform.sent), len(pub.msgs))
+	}
+}
+
+func TestQueueDispatcherPublishFailureIsRetryable(t *testing.T) {
+	d := NewQueueDispatcher(&recordingPublisher{err: errors.New("queue unavailable")}, &recordingDispatcher{})
+	o := d.Send(context.Background(), jobItem("ob1", `{"targetUrl":"https://app.internal/hooks/x"}`))
+	if o.Status != common.OutboxGatewayError || !o.Status.IsRetryable() {
+		t.Fatalf("publish failure = %+v, want a retryable GATEWAY_ERROR", o)
+	}
+}
@@ -0,0 +1,313 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultSource names the outbox selected by FC_OUTBOX_BACKEND — the one
+// the processor has always polled. Tenant databases sit beside it.
+const DefaultSource = "default"
+
+// sourceHealthTimeout bounds one source's health probe.
+const sourceHealthTimeout = 2 * time.Second
+
+var sourceNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// SourceSpec describes one outbox database to poll. URL carries that
+// database's own credentials and is never reported back.
+type SourceSpec struct {
+	Name           string `json:"name" doc:"Unique source name, e.g. the tenant"`
+	URL            string `json:"url" doc:"Postgres connection string, or a secret reference resolving to one"`
+	PollIntervalMS int    `json:"pollIntervalMs,omitempty" doc:"Poll interval; the processor's when omitted"`
+}
+
+func (s SourceSpec) validate() error {
+	if !sourceNameRe.MatchString(s.Name) {
+		return fmt.Errorf("source name %q: want lowercase letters, digits, '-' or '_' (at most 63)", s.Name)
+	}
+	if s.PollIntervalMS < 0 {
+		return fmt.Errorf("source %q: pollIntervalMs must not be negative", s.Name)
+	}
+	return nil
+}
+
+// ParseSources reads FC_OUTBOX_SOURCES: a JSON array of SourceSpec, e.g.
+//
+//	[{"name":"tenant-a","url":"postgres://a@db-a/app","pollIntervalMs":500}]
+//
+// Empty means no extra sources.
+func ParseSources(spec string) ([]SourceSpec, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var out []SourceSpec
+	if err := json.Unmarshal([]byte(spec), &out); err != nil {
+		return nil, fmt.Errorf("want a JSON array of {name, url, pollIntervalMs}: %w", err)
+	}
+	seen := map[string]bool{DefaultSource: true}
+	for _, s := range out {
+		if err := s.validate(); err != nil {
+			return nil, err
+		}
+		if s.URL == "" {
+			return nil, fmt.Errorf("source %q: url is required", s.Name)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("source %q: %w", s.Name, ErrSourceExists)
+		}
+		seen[s.Name] = true
+	}
+	return out, nil
+}
+
+// Connector opens a source's repository. The returned func, when non-nil,
+// closes it.
+type Connector func(ctx context.Context, spec SourceSpec) (Repository, func(), error)
+
+var (
+	ErrSourceExists   = errors.New("source already exists")
+	ErrSourceNotFound = errors.New("source not found")
+)
+
+// Sources runs one Processor per outbox database, each on its own poll
+// loop with its own group states and counters, so a slow or unreachable
+// tenant database never holds up another. Sources can be added and removed
+// while running; those changes last until restart.
+type Sources struct {
+	base    Config
+	connect Connector
+
+	// IsLeader gates every source's polling; see Processor.IsLeader.
+	IsLeader func() bool
+
+	mu      sync.Mutex
+	runCtx  context.Context // non-nil while Run is active
+	sources map[string]*source
+}
+
+type source struct {
+	spec      SourceSpec
+	repo      Repository
+	proc      *Processor
+	closeRepo func()
+	addedAt   time.Time
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewSources wires an empty set. base configures every source's processor;
+// connect opens the sources Add is given.
+func NewSources(base Config, connect Connector) *Sources {
+	return &Sources{base: base, connect: connect, sources: map[string]*source{}}
+}
+
+// Add opens spec through the Connector and starts polling it.
+func (s *Sources) Add(ctx context.Context, spec SourceSpec) error {
+	if err := spec.validate(); err != nil {
+		return err
+	}
+	if spec.URL == "" {
+		return fmt.Errorf("source %q: url is required", spec.Name)
+	}
+	if s.connect == nil {
+		return errors.New("outbox sources: no connector configured")
+	}
+	// Refuse a duplicate before dialling; Attach checks again under lock.
+	if s.Processor(spec.Name) != nil {
+		return ErrSourceExists
+	}
+	repo, closeRepo, err := s.connect(ctx, spec)
+	if err != nil {
+		return fmt.Errorf("source %q: connect: %w", spec.Name, err)
+	}
+	return s.Attach(ctx, spec, repo, closeRepo)
+}
+
+// Attach starts polling an already-open repository under spec.Name
+// (spec.URL is unused). On error closeRepo has been called.
+func (s *Sources) Attach(ctx context.Context, spec SourceSpec, repo Repository, closeRepo func()) error {
+	fail := func(err error) error {
+		if closeRepo != nil {
+			closeRepo()
+		}
+		return err
+	}
+	if err := spec.validate(); err != nil {
+		return fail(err)
+	}
+	if err := repo.InitSchema(ctx); err != nil {
+		return fail(fmt.Errorf("source %q: init schema: %w", spec.Name, err))
+	}
+	cfg := s.base
+	if spec.PollIntervalMS > 0 {
+		cfg.PollInterval = time.Duration(spec.PollIntervalMS) * time.Millisecond
+	}
+	src := &source{spec: spec, repo: repo, proc: NewProcessor(cfg, repo), closeRepo: closeRepo, addedAt: time.Now()}
+	src.proc.IsLeader = s.isLeader
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sources[spec.Name]; ok {
+		return fail(ErrSourceExists)
+	}
+	s.sources[spec.Name] = src
+	if s.runCtx != nil {
+		src.start(s.runCtx)
+	}
+	return nil
+}
+
+// Remove stops polling the source, waits for its in-progress poll to
+// finish and closes its repository.
+func (s *Sources) Remove(name string) error {
+	s.mu.Lock()
+	src, ok := s.sources[name]
+	delete(s.sources, name)
+	s.mu.Unlock()
+	if !ok {
+		return ErrSourceNotFound
+	}
+	src.stop()
+	return nil
+}
+
+// Processor returns the named source's processor, or nil.
+func (s *Sources) Processor(name string) *Processor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src, ok := s.sources[name]; ok {
+		return src.proc
+	}
+	return nil
+}
+
+// Run polls every source until ctx is cancelled, then stops them and
+// closes their repositories.
+func (s *Sources) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.runCtx = ctx
+	for _, src := range s.sources {
+		src.start(ctx)
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	s.runCtx = nil
+	all := s.sources
+	s.sources = map[string]*source{}
+	s.mu.Unlock()
+	for _, src := range all {
+		src.stop()
+	}
+}
+
+func (s *Sources) isLeader() bool { return s.IsLeader == nil || s.IsLeader() }
+
+func (src *source) start(ctx context.Context) {
+	pctx, cancel := context.WithCancel(ctx)
+	src.cancel = cancel
+	src.done = make(chan struct{})
+	go func() {
+		defer close(src.done)
+		src.proc.Run(pctx)
+	}()
+}
+
+func (src *source) stop() {
+	if src.cancel != nil {
+		src.cancel()
+		<-src.done
+	}
+	if src.closeRepo != nil {
+		src.closeRepo()
+	}
+}
+
+// SourceStatus is one source's health and counters.
+type SourceStatus struct {
+	Name           string    `json:"name"`
+	PollIntervalMS int64     `json:"pollIntervalMs"`
+	Healthy        bool      `json:"healthy" doc:"Whether the source database answered a ping"`
+	InFlight       int64     `json:"inFlight"`
+	Succeeded      uint64    `json:"succeeded" doc:"Items delivered since the source was added"`
+	Failed         uint64    `json:"failed" doc:"Item failures since the source was added"`
+	BlockedGroups  int       `json:"blockedGroups"`
+	AddedAt        time.Time `json:"addedAt"`
+}
+
+// Status reports every source, sorted by name. Sources are probed
+// concurrently, each for at most sourceHealthTimeout.
+func (s *Sources) Status(ctx context.Context) []SourceStatus {
+	s.mu.Lock()
+	srcs := make([]*source, 0, len(s.sources))
+	for _, src := range s.sources {
+		srcs = append(srcs, src)
+	}
+	s.mu.Unlock()
+	slices.SortFunc(srcs, func(a, b *source) int { return strings.Compare(a.spec.Name, b.spec.Name) })
+
+	out := make([]SourceStatus, len(srcs))
+	var wg sync.WaitGroup
+	for i, src := range srcs {
+		ok, failed := src.proc.Totals()
+		out[i] = SourceStatus{
+			Name:           src.spec.Name,
+			PollIntervalMS: src.proc.cfg.PollInterval.Milliseconds(),
+			InFlight:       src.proc.InFlight(),
+			Succeeded:      ok,
+			Failed:         failed,
+			BlockedGroups:  len(src.proc.BlockedGroups()),
+			AddedAt:        src.addedAt,
+		}
+		wg.Go(func() {
+			hctx, cancel := context.WithTimeout(ctx, sourceHealthTimeout)
+			defer cancel()
+			out[i].Healthy = src.repo.Healthy(hctx)
+		})
+	}
+	wg.Wait()
+	return out
+}
+
+var (
+	sourceUpDesc = prometheus.NewDesc("fc_outbox_source_up",
+		"1 when the outbox source database answered a ping, else 0.", []string{"source"}, nil)
+	sourceInFlightDesc = prometheus.NewDesc("fc_outbox_source_in_flight",
+		"Outbox items claimed from the source and not yet settled.", []string{"source"}, nil)
+	sourceItemsDesc = prometheus.NewDesc("fc_outbox_source_items_total",
+		"Outbox items settled since the source was added, by result.", []string{"source", "result"}, nil)
+	sourceBlockedDesc = prometheus.NewDesc("fc_outbox_source_blocked_groups",
+		"Message groups currently Blocked in the source.", []string{"source"}, nil)
+)
+
+// Describe is a no-op (unchecked const-metric collector).
+func (s *Sources) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits one set of series per source.
+func (s *Sources) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, st := range s.Status(ctx) {
+		up := 0.0
+		if st.Healthy {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(sourceUpDesc, prometheus.GaugeValue, up, st.Name)
+		ch <- prometheus.MustNewConstMetric(sourceInFlightDesc, prometheus.GaugeValue, float64(st.InFlight), st.Name)
+		ch <- prometheus.MustNewConstMetric(sourceItemsDesc, prometheus.CounterValue, float64(st.Succeeded), st.Name, "succeeded")
+		ch <- prometheus.MustNewConstMetric(sourceItemsDesc, prometheus.CounterValue, float64(st.Failed), st.Name, "failed")
+		ch <- prometheus.MustNewConstMetric(sourceBlockedDesc, prometheus.GaugeValue, float64(st.BlockedGroups), st.Name)
+	}
+}
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseSources(t *testing.T) {
+	got, err := ParseSources(`[{"name":"tenant-a","url":"postgres://a@db-a/app","pollIntervalMs":500},{"name":"tenant-b","url":"vault://outbox/b"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "tenant-a" || got[0].PollIntervalMS != 500 || got[1].URL != "vault://outbox/b" {
+		t.Fatalf("ParseSources = %+v", got)
+	}
+	if got, err := ParseSources("  "); err != nil || got != nil {
+		t.Fatalf("empty spec = %v, %v; want none", got, err)
+	}
+
+	for name, spec := range map[string]string{
+		"not json":        `tenant-a=postgres://db-a`,
+		"bad name":        `[{"name":"Tenant A","url":"postgres://db-a"}]`,
+		"no url":          `[{"name":"tenant-a"}]`,
+		"negative poll":   `[{"name":"tenant-a","url":"postgres://db-a","pollIntervalMs":-1}]`,
+		"duplicate":       `[{"name":"tenant-a","url":"postgres://db-a"},{"name":"tenant-a","url":"postgres://db-b"}]`,
+		"shadows default": `[{"name":"default","url":"postgres://db-a"}]`,
+	} {
+		if _, err := ParseSources(spec); err == nil {
+			t.Errorf("%s: ParseSources(%s) accepted", name, spec)
+		}
+	}
+}
+
+// countingRepo is a stubRepo that counts polls and records being closed.
+type countingRepo struct {
+	stubRepo
+	claims  atomic.Int64
+	closed  atomic.Bool
+	healthy bool
+}
+
+func (r *countingRepo) ClaimPending(context.Context, int) ([]Item, error) {
+	r.claims.Add(1)
+	return nil, nil
+}
+
+func (r *countingRepo) Healthy(context.Context) bool { return r.healthy }
+
+// Each source polls on its own loop; removing one stops it and closes its
+// repository while the rest keep polling.
+func TestSourcesAddRemoveWhileRunning(t *testing.T) {
+	repos := map[string]*countingRepo{}
+	connect := func(_ context.Context, spec SourceSpec) (Repository, func(), error) {
+		if spec.URL == "postgres://down" {
+			return nil, nil, errors.New("connection refused")
+		}
+		r := &countingRepo{healthy: true}
+		repos[spec.Name] = r
+		return r, func() { r.closed.Store(true) }, nil
+	}
+	cfg := DefaultConfig()
+	cfg.PollInterval = 5 * time.Millisecond
+	s := NewSources(cfg, connect)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); s.Run(ctx) }()
+
+	if err := s.Add(ctx, SourceSpec{Name: "tenant-a", URL: "postgres://a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(ctx, SourceSpec{Name: "tenant-b", URL: "postgres://b", PollIntervalMS: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(ctx, SourceSpec{Name: "tenant-a", URL: "postgres://a"}); !errors.Is(err, ErrSourceExists) {
+		t.Fatalf("duplicate Add = %v, want ErrSourceExists", err)
+	}
+	if err := s.Add(ctx, SourceSpec{Name: "tenant-c", URL: "postgres://down"}); err == nil {
+		t.Fatal("Add of an unreachable source succeeded")
+	}
+
+	waitFor(t, func() bool { return repos["tenant-a"].claims.Load() > 0 && repos["tenant-b"].claims.Load() > 0 })
+
+	if err := s.Remove("tenant-a"); err != nil {
+		t.Fatal(err)
+	}
+	if !repos["tenant-a"].closed.Load() {
+		t.Fatal("removed source's repository not closed")
+	}
+	if err := s.Remove("tenant-a"); !errors.Is(err, ErrSourceNotFound) {
+		t.Fatalf("second Remove = %v, want ErrSourceNotFound", err)
+	}
+	stopped := repos["tenant-a"].claims.Load()
+	before := repos["tenant-b"].claims.Load()
+	waitFor(t, func() bool { return repos["tenant-b"].claims.Load() > before })
+	if n := repos["tenant-a"].claims.Load(); n != stopped {
+		t.Fatalf("removed source polled again (%d → %d)", stopped, n)
+	}
+
+	st := s.Status(ctx)
+	if len(st) != 1 || st[0].Name != "tenant-b" || st[0].PollIntervalMS != 10 || !st[0].Healthy {
+		t.Fatalf("Status = %+v, want tenant-b alone, healthy at 10ms", st)
+	}
+
+	cancel()
+	<-done
+	if !repos["tenant-b"].closed.Load() {
+		t.Fatal("Run returned without closing the remaining source")
+	}
+}
+
+func TestSourcesCollect(t *testing.T) {
+	s := NewSources(DefaultConfig(), nil)
+	ctx := context.Background()
+	if err := s.Attach(ctx, SourceSpec{Name: DefaultSource}, &countingRepo{healthy: true}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Attach(ctx, SourceSpec{Name: "tenant-a"}, &countingRepo{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	s.Processor("tenant-a").groups.Block("g1", "item-1", "boom")
+
+	want := `
+# HELP fc_outbox_source_blocked_groups Message groups currently Blocked in the source.
+# TYPE fc_outbox_source_blocked_groups gauge
+fc_outbox_source_blocked_groups{source="default"} 0
+fc_outbox_source_blocked_groups{source="tenant-a"} 1
+# HELP fc_outbox_source_up 1 when the outbox source database answered a ping, else 0.
+# TYPE fc_outbox_source_up gauge
+fc_outbox_source_up{source="default"} 1
+fc_outbox_source_up{source="tenant-a"} 0
+`
+	if err := testutil.CollectAndCompare(s, strings.NewReader(want), "fc_outbox_source_up", "fc_outbox_source_blocked_groups"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdminSources(t *testing.T) {
+	connect := func(context.Context, SourceSpec) (Repository, func(), error) {
+		return &countingRepo{healthy: true}, nil, nil
+	}
+	s := NewSources(DefaultConfig(), connect)
+	h := s.AdminHandler()
+
+	post := func(body string) (int, string) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/outbox/sources", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(rec, req)
+		return rec.Code, strings.TrimSpace(rec.Body.String())
+	}
+	if code, body := post(`{"name":"tenant-a","url":"postgres://a","pollIntervalMs":250}`); code != 201 || body != `{"status":"POLLING"}` {
+		t.Fatalf("add = %d %s", code, body)
+	}
+	if code, body := post(`{"name":"tenant-a","url":"postgres://a"}`); code != 409 || body != `{"error":"source already exists"}` {
+		t.Fatalf("duplicate add = %d %s", code, body)
+	}
+	if code, _ := post(`{"name":"Tenant A","url":"postgres://a"}`); code != 400 {
+		t.Fatalf("invalid add = %d, want 400", code)
+	}
+
+	code, body := adminDo(t, h, http.MethodGet, "/outbox/sources")
+	if code != 200 || !strings.Contains(body, `"name":"tenant-a","pollIntervalMs":250,"healthy":true`) || strings.Contains(body, "postgres://") {
+		t.Fatalf("list = %d %s", code, body)
+	}
+	if code, body := adminDo(t, h, http.MethodPost, "/outbox/groups/g1/pause?source=tenant-a"); code != 200 || body != `{"status":"PAUSED"}` {
+		t.Fatalf("pause in tenant-a = %d %s", code, body)
+	}
+	if code, body := adminDo(t, h, http.MethodDelete, "/outbox/sources/tenant-a"); code != 200 || body != `{"status":"REMOVED"}` {
+		t.Fatalf("remove = %d %s", code, body)
+	}
+	if code, body := adminDo(t, h, http.MethodGet, "/outbox/sources"); code != 200 || body != `{"sources":[]}` {
+		t.Fatalf("list after remove = %d %s", code, body)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 2s")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
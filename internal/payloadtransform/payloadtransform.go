@@ -0,0 +1,244 @@
+// Package payloadtransform renders a subscription's receiver-specific
+// payload from the platform event envelope. Templates are Go text/template
+// over the envelope the subscriber would otherwise receive:
+//
+//	{"orderId": {{ json .data.id }}, "kind": {{ json .type }},
+//	 "total": {{ .data.amount }}, "region": {{ json (default "AU" .data.region) }}}
+//
+// Envelope keys: id, type, source, subject, correlationId, messageGroup,
+// clientId, attemptNumber and data (the decoded event payload). Numbers
+// render exactly as they arrived. A missing key renders as the zero value;
+// wrap it in json to get null, or default to supply a fallback.
+//
+// Functions: json, default, upper, lower, trim, join.
+//
+// Templates come from tenants, so a render is bounded: range can't count
+// to an integer, output stops at MaxOutputSize, and execution is abandoned
+// after MaxRenderTime.
+package payloadtransform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Engine names the expression language. Only Go templates exist today; the
+// field is persisted so another language can be added without a migration.
+type Engine string
+
+const EngineGoTemplate Engine = "GO_TEMPLATE"
+
+// DefaultContentType is used when a transform doesn't set one.
+const DefaultContentType = "application/json"
+
+// MaxTemplateSize bounds stored template source.
+const MaxTemplateSize = 64 << 10
+
+// MaxOutputSize bounds a rendered body so a runaway range can't build an
+// unbounded request.
+const MaxOutputSize = 1 << 20
+
+// MaxRenderTime bounds one render, so a template looping over a large
+// input can't hold a delivery worker.
+const MaxRenderTime = time.Second
+
+// Program is a compiled transform. Safe for concurrent use.
+type Program struct {
+	tmpl        *template.Template
+	contentType string
+	wantJSON    bool
+}
+
+// Compile parses src for engine. contentType "" means DefaultContentType;
+// a JSON content type makes Render reject output that isn't valid JSON.
+func Compile(engine Engine, src, contentType string) (*Program, error) {
+	if engine != "" && engine != EngineGoTemplate {
+		return nil, fmt.Errorf("unsupported transform engine %q", engine)
+	}
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("template is empty")
+	}
+	if len(src) > MaxTemplateSize {
+		return nil, fmt.Errorf("template exceeds %d bytes", MaxTemplateSize)
+	}
+	if contentType == "" {
+		contentType = DefaultContentType
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type %q", contentType)
+	}
+	tmpl, err := template.New("transform").Option("missingkey=zero").Funcs(funcs).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if err := bound(t.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+	return &Program{
+		tmpl:        tmpl,
+		contentType: contentType,
+		wantJSON:    mt == "application/json" || strings.HasSuffix(mt, "+json"),
+	}, nil
+}
+
+// ContentType is the Content-Type the rendered body is sent with.
+func (p *Program) ContentType() string { return p.contentType }
+
+// Render executes the template against envelope. The envelope is
+// normalised through JSON first, so callers may pass json.RawMessage data.
+// A render still running after MaxRenderTime fails; its goroutine stops at
+// the next loop iteration.
+func (p *Program) Render(envelope map[string]any) ([]byte, error) {
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("encode envelope: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var input map[string]any
+	if err := dec.Decode(&input); err != nil {
+		return nil, fmt.Errorf("decode envelope: %w", err)
+	}
+
+	out := &limitedBuffer{max: MaxOutputSize}
+	done := make(chan error, 1)
+	go func() { done <- p.tmpl.Execute(out, input) }()
+	timer := time.NewTimer(MaxRenderTime)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+	case <-timer.C:
+		out.stopped.Store(true)
+		return nil, fmt.Errorf("render exceeded %s", MaxRenderTime)
+	}
+	body := out.Bytes()
+	if p.wantJSON && !json.Valid(body) {
+		return nil, errors.New("rendered body is not valid JSON")
+	}
+	return body, nil
+}
+
+var funcs = template.FuncMap{
+	// json renders v as a JSON literal: strings quoted and escaped, nil as
+	// null, objects and lists as-is.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// default returns def when v is nil or the empty string.
+	"default": func(def, v any) any {
+		if v == nil {
+			return def
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return def
+		}
+		return v
+	},
+	"upper": func(s any) string { return strings.ToUpper(str(s)) },
+	"lower": func(s any) string { return strings.ToLower(str(s)) },
+	"trim":  func(s any) string { return strings.TrimSpace(str(s)) },
+	"join": func(sep string, v any) string {
+		items, _ := v.([]any)
+		parts := make([]string, len(items))
+		for i, it := range items {
+			parts[i] = str(it)
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
+func str(v any) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// bound rejects range over an integer and starts every range body with
+// an empty text node. text/template writes a text node even when it is
+// empty, so each iteration reaches limitedBuffer.Write, which fails once
+// Render has given up: a loop that renders nothing still stops.
+func bound(n parse.Node) error {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := bound(c); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return boundBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return boundBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		for _, cmd := range n.Pipe.Cmds {
+			for _, arg := range cmd.Args {
+				if _, ok := arg.(*parse.NumberNode); ok {
+					return errors.New("range over an integer is not supported")
+				}
+			}
+		}
+		if err := boundBranch(&n.BranchNode); err != nil {
+			return err
+		}
+		if n.List != nil {
+			n.List.Nodes = append([]parse.Node{tick.Copy()}, n.List.Nodes...)
+		}
+	}
+	return nil
+}
+
+func boundBranch(b *parse.BranchNode) error {
+	if err := bound(b.List); err != nil {
+		return err
+	}
+	return bound(b.ElseList)
+}
+
+// tick is the empty text node bound inserts. parse has no exported
+// constructor, so it comes from a parsed template.
+var tick = func() parse.Node {
+	n := template.Must(template.New("").Parse(" ")).Tree.Root.Nodes[0].(*parse.TextNode)
+	n.Text = nil
+	return n
+}()
+
+type limitedBuffer struct {
+	bytes.Buffer
+	max     int
+	stopped atomic.Bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.stopped.Load() {
+		return 0, errors.New("render abandoned")
+	}
+	if b.Len()+len(p) > b.max {
+		return 0, fmt.Errorf("rendered body exceeds %d bytes", b.max)
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,102 @@
+package payloadtransform
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func envelope() map[string]any {
+	return map[string]any{
+		"id":   "dsj_1",
+		"type": "orders:sales:order:created",
+		"data": json.RawMessage(`{"id":"ord-9","amount":12345678901234567,"tags":["a","b"],"customer":{"name":"Ann \"A\""}}`),
+	}
+}
+
+func TestRender(t *testing.T) {
+	p, err := Compile(EngineGoTemplate,
+		`{"orderId":{{ json .data.id }},"total":{{ .data.amount }},"who":{{ json .data.customer.name }},`+
+			`"tags":{{ json (join "," .data.tags) }},"region":{{ json (default "AU" .data.region) }},"kind":{{ json (upper .type) }}}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Render(envelope())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"orderId":"ord-9","total":12345678901234567,"who":"Ann \"A\"","tags":"a,b","region":"AU","kind":"ORDERS:SALES:ORDER:CREATED"}`
+	if string(got) != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	if p.ContentType() != DefaultContentType {
+		t.Errorf("content type = %q", p.ContentType())
+	}
+}
+
+func TestRenderRejectsInvalidJSON(t *testing.T) {
+	p, err := Compile(EngineGoTemplate, `{"id": {{ .data.id }}}`, "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Render(envelope()); err == nil {
+		t.Fatal("unquoted string should not render as JSON")
+	}
+
+	// Non-JSON content types render as-is.
+	p, err = Compile(EngineGoTemplate, `id={{ .data.id }}`, "application/x-www-form-urlencoded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := p.Render(envelope()); err != nil || string(got) != "id=ord-9" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestRenderCapsOutput(t *testing.T) {
+	// 2^5 iterations of a 40 KiB literal: a small template, a >1 MiB body.
+	loop := strings.Repeat(`{{ range $.data.tags }}`, 5)
+	p, err := Compile(EngineGoTemplate, loop+strings.Repeat("x", 40<<10)+strings.Repeat(`{{ end }}`, 5), "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Render(envelope()); err == nil {
+		t.Fatal("expected the output cap to trip")
+	}
+}
+
+func TestRenderGivesUpAfterMaxRenderTime(t *testing.T) {
+	// 1000^4 iterations that write nothing.
+	loop := strings.Repeat(`{{ range $.data.items }}`, 4)
+	p, err := Compile(EngineGoTemplate, loop+strings.Repeat(`{{ end }}`, 4), "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := make([]int, 1000)
+	b, _ := json.Marshal(map[string]any{"items": items})
+	start := time.Now()
+	if _, err := p.Render(map[string]any{"data": json.RawMessage(b)}); err == nil {
+		t.Fatal("expected the render deadline to trip")
+	}
+	if d := time.Since(start); d > 2*MaxRenderTime {
+		t.Errorf("render took %s", d)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := []struct{ engine, src, ct string }{
+		{"JSONATA", `$.id`, ""},
+		{"", "  ", ""},
+		{"", `{{ .data.id `, ""},
+		{"", `{{ nosuchfunc .id }}`, ""},
+		{"", `{}`, "not a type;;"},
+		{"", `{{ range 1000000000 }}{{ end }}`, ""},
+		{"", `{{ if .id }}{{ range 10 }}x{{ end }}{{ end }}`, ""},
+	}
+	for _, tc := range cases {
+		if _, err := Compile(Engine(tc.engine), tc.src, tc.ct); err == nil {
+			t.Errorf("Compile(%q, %q, %q) succeeded", tc.engine, tc.src, tc.ct)
+		}
+	}
+}
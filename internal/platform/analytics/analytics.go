@@ -0,0 +1,271 @@
+// Package analytics answers the delivery-analytics queries behind the
+// admin dashboards (/bff/analytics/*): events ingested, dispatch jobs
+// created, their success rate and delivery latency, bucketed over time or
+// broken down by event type, subscription or client.
+//
+// Aggregates are computed on read from the msg_events_read and
+// msg_dispatch_jobs_read projections. Both are range-partitioned on
+// created_at and every query is bounded by one of the fixed Ranges, so a
+// request only scans the partitions it covers. Jobs are attributed to the
+// bucket they were created in; success rate and latency cover those of
+// them that have finished so far.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+)
+
+// Range is a selectable look-back window and its bucket width.
+type Range struct {
+	Name   string
+	Span   time.Duration
+	Bucket time.Duration
+}
+
+// Ranges are the windows the dashboards offer, keyed by name.
+var Ranges = map[string]Range{
+	"1h":  {Name: "1h", Span: time.Hour, Bucket: 5 * time.Minute},
+	"6h":  {Name: "6h", Span: 6 * time.Hour, Bucket: 15 * time.Minute},
+	"24h": {Name: "24h", Span: 24 * time.Hour, Bucket: time.Hour},
+	"7d":  {Name: "7d", Span: 7 * 24 * time.Hour, Bucket: 6 * time.Hour},
+	"30d": {Name: "30d", Span: 30 * 24 * time.Hour, Bucket: 24 * time.Hour},
+}
+
+// DefaultRange is used when none is selected.
+const DefaultRange = "24h"
+
+// Window returns the [from, to) interval r covers at now. The end is
+// rounded up to the next bucket boundary so every bucket is whole.
+func (r Range) Window(now time.Time) (from, to time.Time) {
+	to = now.UTC().Truncate(r.Bucket).Add(r.Bucket)
+	return to.Add(-r.Span), to
+}
+
+// Dimension is what a breakdown groups by.
+type Dimension string
+
+const (
+	ByEventType    Dimension = "eventType"
+	BySubscription Dimension = "subscription"
+	ByClient       Dimension = "client"
+)
+
+// column is the msg_dispatch_jobs_read column behind d; "" when unknown.
+// A job's code is the type of the event it delivers.
+func (d Dimension) column() string {
+	switch d {
+	case ByEventType:
+		return "code"
+	case BySubscription:
+		return "subscription_id"
+	case ByClient:
+		return "client_id"
+	}
+	return ""
+}
+
+// Valid reports whether d is a supported dimension.
+func (d Dimension) Valid() bool { return d.column() != "" }
+
+// Filter narrows every aggregate. SubscriptionID doesn't apply to events,
+// which aren't tied to a subscription until fan-out.
+type Filter struct {
+	ClientID       *string
+	EventType      *string
+	SubscriptionID *string
+
+	// AccessibleClientIDs: a non-nil pointer scopes results to
+	// platform-scoped rows (client_id IS NULL) plus rows whose client_id
+	// is in the set; nil means the calling request's scope
+	// (reqctx.ClientScope). Same contract as the dispatch-job and event
+	// lists.
+	AccessibleClientIDs *[]string
+}
+
+// JobStats aggregates a set of dispatch jobs.
+type JobStats struct {
+	Dispatched int64
+	Succeeded  int64
+	Failed     int64
+	// P50Ms/P95Ms are the delivery-duration percentiles of jobs with a
+	// recorded attempt; nil when there are none.
+	P50Ms *float64
+	P95Ms *float64
+}
+
+// SuccessRate is succeeded / (succeeded + failed); nil before any job
+// has finished.
+func (s JobStats) SuccessRate() *float64 {
+	done := s.Succeeded + s.Failed
+	if done == 0 {
+		return nil
+	}
+	v := float64(s.Succeeded) / float64(done)
+	return &v
+}
+
+// Bucket is one time slot of a series.
+type Bucket struct {
+	Start          time.Time
+	EventsIngested int64
+	JobStats
+}
+
+// Group is one row of a breakdown.
+type Group struct {
+	// Key is the event type code, subscription id or client id; nil for
+	// jobs without one (platform-scoped client, no subscription).
+	Key *string
+	JobStats
+}
+
+// Repository runs the aggregate queries.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+// jobAggregates are the JobStats columns, in scan order. FAILED and
+// EXPIRED count as failed deliveries; CANCELLED jobs were never a
+// receiver's fault and count for neither side of the success rate.
+const jobAggregates = `count(*),
+	count(*) FILTER (WHERE status = 'COMPLETED'),
+	count(*) FILTER (WHERE status IN ('FAILED', 'EXPIRED')),
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_millis),
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_millis)`
+
+// where renders f plus the [from, to) window. typeCol is the column
+// holding the event type code on the queried table; events skip the
+// subscription filter.
+func (f Filter) where(from, to time.Time, typeCol string, events bool) *repocommon.Filter {
+	var w repocommon.Filter
+	w.Clause("created_at >= $%d", from)
+	w.Clause("created_at < $%d", to)
+	w.EqPtr("client_id", f.ClientID)
+	if f.AccessibleClientIDs != nil {
+		w.Clause("(client_id IS NULL OR client_id = ANY($%d))", *f.AccessibleClientIDs)
+	}
+	w.EqPtr(typeCol, f.EventType)
+	if !events {
+		w.EqPtr("subscription_id", f.SubscriptionID)
+	}
+	return &w
+}
+
+// Series returns r's buckets, oldest first, with empty slots filled in.
+func (repo *Repository) Series(ctx context.Context, r Range, f Filter, now time.Time) ([]Bucket, error) {
+	f.AccessibleClientIDs = reqctx.ClientScope(ctx, f.AccessibleClientIDs)
+	from, to := r.Window(now)
+	n := int(r.Span / r.Bucket)
+	out := make([]Bucket, n)
+	for i := range out {
+		out[i].Start = from.Add(time.Duration(i) * r.Bucket)
+	}
+	slot := func(t time.Time) *Bucket {
+		i := int(t.Sub(from) / r.Bucket)
+		if i < 0 || i >= n {
+			return nil
+		}
+		return &out[i]
+	}
+
+	jw := f.where(from, to, "code", false)
+	secs, origin := jw.Arg(r.Bucket.Seconds()), jw.Arg(from)
+	rows, err := repo.pool.Query(ctx, fmt.Sprintf(
+		`SELECT date_bin($%d::float8 * interval '1 second', created_at, $%d), %s
+		   FROM msg_dispatch_jobs_read%s
+		  GROUP BY 1`, secs, origin, jobAggregates, jw.Where()), jw.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("analytics job series: %w", err)
+	}
+	var start time.Time
+	var s JobStats
+	if _, err := pgx.ForEachRow(rows, []any{&start, &s.Dispatched, &s.Succeeded, &s.Failed, &s.P50Ms, &s.P95Ms}, func() error {
+		if b := slot(start); b != nil {
+			b.JobStats = s
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("analytics job series: %w", err)
+	}
+
+	ew := f.where(from, to, "type", true)
+	secs, origin = ew.Arg(r.Bucket.Seconds()), ew.Arg(from)
+	rows, err = repo.pool.Query(ctx, fmt.Sprintf(
+		`SELECT date_bin($%d::float8 * interval '1 second', created_at, $%d), count(*)
+		   FROM msg_events_read%s
+		  GROUP BY 1`, secs, origin, ew.Where()), ew.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("analytics event series: %w", err)
+	}
+	var events int64
+	if _, err := pgx.ForEachRow(rows, []any{&start, &events}, func() error {
+		if b := slot(start); b != nil {
+			b.EventsIngested = events
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("analytics event series: %w", err)
+	}
+	return out, nil
+}
+
+// Totals aggregates the whole of r: events ingested plus job stats.
+// Percentiles are computed over the window, not averaged from buckets.
+func (repo *Repository) Totals(ctx context.Context, r Range, f Filter, now time.Time) (int64, JobStats, error) {
+	f.AccessibleClientIDs = reqctx.ClientScope(ctx, f.AccessibleClientIDs)
+	from, to := r.Window(now)
+	var s JobStats
+	jw := f.where(from, to, "code", false)
+	if err := repo.pool.QueryRow(ctx, `SELECT `+jobAggregates+` FROM msg_dispatch_jobs_read`+jw.Where(), jw.Args()...).
+		Scan(&s.Dispatched, &s.Succeeded, &s.Failed, &s.P50Ms, &s.P95Ms); err != nil {
+		return 0, s, fmt.Errorf("analytics job totals: %w", err)
+	}
+	var events int64
+	ew := f.where(from, to, "type", true)
+	if err := repo.pool.QueryRow(ctx, `SELECT count(*) FROM msg_events_read`+ew.Where(), ew.Args()...).Scan(&events); err != nil {
+		return 0, s, fmt.Errorf("analytics event totals: %w", err)
+	}
+	return events, s, nil
+}
+
+// Breakdown groups r's jobs by d, busiest first, at most limit groups.
+func (repo *Repository) Breakdown(ctx context.Context, r Range, d Dimension, f Filter, limit int, now time.Time) ([]Group, error) {
+	f.AccessibleClientIDs = reqctx.ClientScope(ctx, f.AccessibleClientIDs)
+	col := d.column()
+	if col == "" {
+		return nil, fmt.Errorf("analytics: dimension %q not allowed", d)
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	from, to := r.Window(now)
+	w := f.where(from, to, "code", false)
+	lim := w.Arg(limit)
+	rows, err := repo.pool.Query(ctx, fmt.Sprintf(
+		`SELECT %s, %s
+		   FROM msg_dispatch_jobs_read%s
+		  GROUP BY 1
+		  ORDER BY 2 DESC, 1
+		  LIMIT $%d`, col, jobAggregates, w.Where(), lim), w.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("analytics breakdown: %w", err)
+	}
+	out := []Group{}
+	var g Group
+	if _, err := pgx.ForEachRow(rows, []any{&g.Key, &g.Dispatched, &g.Succeeded, &g.Failed, &g.P50Ms, &g.P95Ms}, func() error {
+		out = append(out, g)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("analytics breakdown: %w", err)
+	}
+	return out, nil
+}
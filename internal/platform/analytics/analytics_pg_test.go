@@ -0,0 +1,213 @@
+//go:build integration
+
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/analytics"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+// TestSeriesAndBreakdown pins the bucketing, success rate and tenant
+// scoping over the read projections.
+func TestSeriesAndBreakdown(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := analytics.NewRepository(pool)
+
+	const (
+		code    = "analyticstest:jobs:delivered"
+		clientA = "clt_analytics001"
+		clientB = "clt_analytics002"
+	)
+	rng := analytics.Ranges["1h"]
+	now := time.Now().UTC()
+	from, _ := rng.Window(now)
+	at := func(bucket int) time.Time { return from.Add(time.Duration(bucket)*rng.Bucket + time.Second) }
+
+	seed := func(id, client, status string, durationMS int64, created time.Time) {
+		t.Helper()
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_dispatch_jobs_read
+			     (id, code, target_url, client_id, subscription_id, kind, protocol, mode, status,
+			      max_retries, duration_millis, updated_at, created_at)
+			 VALUES ($1, $2, 'http://example.invalid/hook', $3, 'sub_analytics001',
+			         'EVENT', 'HTTP_WEBHOOK', 'IMMEDIATE', $4, 3, $5, NOW(), $6)`,
+			id, code, client, status, durationMS, created)
+		require.NoError(t, err)
+	}
+	seed("djanalytics01", clientA, "COMPLETED", 100, at(0))
+	seed("djanalytics02", clientA, "COMPLETED", 300, at(0))
+	seed("djanalytics03", clientA, "FAILED", 900, at(0))
+	seed("djanalytics04", clientB, "PENDING", 0, at(2))
+	_, err := pool.Exec(ctx,
+		`INSERT INTO msg_events_read (id, type, source, time, client_id, created_at)
+		 VALUES ('evanalytics01', $1, 'test', NOW(), $2, $3)`, code, clientA, at(0))
+	require.NoError(t, err)
+
+	ev := code
+	f := analytics.Filter{EventType: &ev}
+	buckets, err := repo.Series(ctx, rng, f, now)
+	require.NoError(t, err)
+	require.Len(t, buckets, 12)
+	assert.Equal(t, int64(3), buckets[0].Dispatched)
+	assert.Equal(t, int64(1), buckets[0].EventsIngested)
+	require.NotNil(t, buckets[0].SuccessRate())
+	assert.InDelta(t, 2.0/3, *buckets[0].SuccessRate(), 1e-9)
+	require.NotNil(t, buckets[0].P50Ms)
+	assert.InDelta(t, 300, *buckets[0].P50Ms, 1e-9)
+	assert.Equal(t, int64(1), buckets[2].Dispatched)
+	assert.Nil(t, buckets[2].SuccessRate(), "a pending job hasn't finished")
+	assert.Zero(t, buckets[1].Dispatched, "empty slots are filled in")
+
+	events, totals, err := repo.Totals(ctx, rng, f, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), events)
+	assert.Equal(t, int64(4), totals.Dispatched)
+
+	groups, err := repo.Breakdown(ctx, rng, analytics.ByClient, f, 0, now)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, clientA, *groups[0].Key, "busiest first")
+
+	scoped := []string{clientB}
+	f.AccessibleClientIDs = &scoped
+	groups, err = repo.Breakdown(ctx, rng, analytics.ByClient, f, 0, now)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, clientB, *groups[0].Key, "another tenant's jobs stay hidden")
+}
+
+// TestSubscriptionHealth pins the failure streak, backlog and tenant
+// scoping of the health summary.
+func TestSubscriptionHealth(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := analytics.NewRepository(pool)
+
+	const (
+		subID   = "sub_health00001"
+		idleID  = "sub_health00002"
+		clientA = "clt_health00001"
+		clientB = "clt_health00002"
+	)
+	for _, s := range []struct{ id, client string }{{subID, clientA}, {idleID, clientB}} {
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_subscriptions (id, code, name, target, client_id)
+			 VALUES ($1, $1, 'Health IT', 'http://example.invalid/hook', $2)`, s.id, s.client)
+		require.NoError(t, err)
+	}
+
+	now := time.Now().UTC()
+	seed := func(id, status string, lastError *string, lastAttempt *time.Time) {
+		t.Helper()
+		var completed *time.Time
+		if status == "COMPLETED" || status == "FAILED" {
+			completed = lastAttempt
+		}
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_dispatch_jobs_read
+			     (id, code, target_url, client_id, subscription_id, kind, protocol, mode, status,
+			      max_retries, last_error, last_attempt_at, completed_at, updated_at, created_at)
+			 VALUES ($1, 'healthtest:jobs:delivered', 'http://example.invalid/hook', $2, $3,
+			         'EVENT', 'HTTP_WEBHOOK', 'IMMEDIATE', $4, 3, $5, $6, $7, NOW(), $8)`,
+			id, clientA, subID, status, lastError, lastAttempt, completed, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+	}
+	at := func(ago time.Duration) *time.Time { v := now.Add(-ago); return &v }
+	boom := "HTTP 500"
+	seed("djhealth00001", "FAILED", &boom, at(20*time.Minute))
+	seed("djhealth00002", "COMPLETED", nil, at(15*time.Minute))
+	seed("djhealth00003", "FAILED", &boom, at(10*time.Minute))
+	seed("djhealth00004", "PENDING", &boom, at(5*time.Minute))
+	seed("djhealth00005", "PENDING", nil, nil)
+
+	id := subID
+	got, err := repo.SubscriptionHealth(ctx, analytics.Ranges["1h"], analytics.SubscriptionFilter{SubscriptionID: &id}, now)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	h := got[0]
+	assert.Equal(t, int64(5), h.Dispatched)
+	assert.Equal(t, int64(1), h.Succeeded)
+	assert.Equal(t, int64(2), h.Failed)
+	assert.Equal(t, int64(2), h.ConsecutiveFailures, "only failures after the last success count")
+	assert.Equal(t, int64(2), h.Backlog)
+	require.NotNil(t, h.LastSuccessAt)
+	assert.WithinDuration(t, *at(15 * time.Minute), *h.LastSuccessAt, time.Millisecond)
+	assert.Equal(t, analytics.HealthDegraded, h.Health)
+
+	client := clientB
+	got, err = repo.SubscriptionHealth(ctx, analytics.Ranges["1h"], analytics.SubscriptionFilter{ClientID: &client}, now)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, analytics.HealthIdle, got[0].Health)
+
+	scoped := []string{clientA}
+	got, err = repo.SubscriptionHealth(ctx, analytics.Ranges["1h"],
+		analytics.SubscriptionFilter{ClientID: &client, AccessibleClientIDs: &scoped}, now)
+	require.NoError(t, err)
+	assert.Empty(t, got, "another tenant's subscriptions stay hidden")
+}
+
+// TestPoolLoad pins arrival and attempt rates, in-flight and the service
+// time distribution for one pool.
+func TestPoolLoad(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := analytics.NewRepository(pool)
+
+	const (
+		poolID  = "dpl_capacity001"
+		otherID = "dpl_capacity002"
+	)
+	rng := analytics.Ranges["1h"]
+	now := time.Now().UTC()
+	from, _ := rng.Window(now)
+	at := from.Add(time.Second)
+
+	seed := func(id, poolID string, durations ...int64) {
+		t.Helper()
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_dispatch_jobs_read
+			     (id, code, target_url, dispatch_pool_id, kind, protocol, mode, status,
+			      max_retries, updated_at, created_at)
+			 VALUES ($1, 'capacitytest:jobs:delivered', 'http://example.invalid/hook', $2,
+			         'EVENT', 'HTTP_WEBHOOK', 'IMMEDIATE', 'COMPLETED', 3, NOW(), $3)`,
+			id, poolID, at)
+		require.NoError(t, err)
+		for i, d := range durations {
+			_, err := pool.Exec(ctx,
+				`INSERT INTO msg_dispatch_job_attempts
+				     (id, dispatch_job_id, attempt_number, status, duration_millis, attempted_at, created_at)
+				 VALUES ($1, $2, $3, 'SUCCESS', $4, $5, $5)`,
+				id+string(rune('a'+i)), id, i+1, d, at)
+			require.NoError(t, err)
+		}
+	}
+	seed("djcapacity01", poolID, 100, 300)
+	seed("djcapacity02", poolID, 200)
+	seed("djcapacity03", otherID, 60000)
+
+	l, err := repo.PoolLoad(ctx, poolID, rng, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), l.Arrivals)
+	assert.Equal(t, int64(3), l.Attempts)
+	assert.InDelta(t, 1.5, l.AttemptsPerJob(), 1e-9)
+	bucketMin := rng.Bucket.Minutes()
+	assert.InDelta(t, 2/bucketMin, l.PeakArrivalsPerMin, 1e-9)
+	assert.InDelta(t, 3/bucketMin, l.PeakAttemptsPerMin, 1e-9)
+	assert.InDelta(t, 600/float64(rng.Bucket.Milliseconds()), l.PeakInFlight, 1e-9)
+	assert.Equal(t, int64(3), l.ServiceTime.Samples)
+	require.NotNil(t, l.ServiceTime.P50Ms)
+	assert.InDelta(t, 200, *l.ServiceTime.P50Ms, 1e-9)
+	require.NotNil(t, l.ServiceTime.MaxMs)
+	assert.InDelta(t, 300, *l.ServiceTime.MaxMs, 1e-9, "another pool's attempts stay out")
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeWindowCoversWholeBuckets(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 47, 12, 0, time.UTC)
+	for name, r := range Ranges {
+		from, to := r.Window(now)
+		assert.Equal(t, r.Span, to.Sub(from), name)
+		assert.True(t, to.After(now), "%s: the current bucket is included", name)
+		assert.Zero(t, from.Sub(from.Truncate(r.Bucket)), "%s: buckets start on a boundary", name)
+		assert.Zero(t, r.Span%r.Bucket, "%s: the span is a whole number of buckets", name)
+	}
+	from, to := Ranges["24h"].Window(now)
+	assert.Equal(t, time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), to)
+}
+
+func TestJobStatsSuccessRate(t *testing.T) {
+	assert.Nil(t, JobStats{Dispatched: 5}.SuccessRate(), "nothing finished yet")
+	rate := JobStats{Dispatched: 10, Succeeded: 3, Failed: 1}.SuccessRate()
+	require.NotNil(t, rate)
+	assert.InDelta(t, 0.75, *rate, 1e-9)
+}
+
+func TestSubscriptionHealthClassify(t *testing.T) {
+	cases := []struct {
+		name string
+		h    SubscriptionHealth
+		want Health
+	}{
+		{"paused wins", SubscriptionHealth{Status: "PAUSED", ConsecutiveFailures: 9}, HealthPaused},
+		{"failure streak", SubscriptionHealth{Status: "ACTIVE", ConsecutiveFailures: FailingAfter,
+			JobStats: JobStats{Succeeded: 100, Failed: 5}}, HealthFailing},
+		{"latest failed", SubscriptionHealth{Status: "ACTIVE", ConsecutiveFailures: 1,
+			JobStats: JobStats{Succeeded: 100, Failed: 1}}, HealthDegraded},
+		{"low success rate", SubscriptionHealth{Status: "ACTIVE",
+			JobStats: JobStats{Succeeded: 8, Failed: 2}}, HealthDegraded},
+		{"healthy", SubscriptionHealth{Status: "ACTIVE",
+			JobStats: JobStats{Succeeded: 99, Failed: 1}}, HealthHealthy},
+		{"waiting, nothing finished", SubscriptionHealth{Status: "ACTIVE", Backlog: 3}, HealthHealthy},
+		{"idle", SubscriptionHealth{Status: "ACTIVE"}, HealthIdle},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, c.h.Classify(), c.name)
+	}
+}
+
+func TestSortByHealthWorstFirst(t *testing.T) {
+	hs := []SubscriptionHealth{
+		{Code: "a", Health: HealthHealthy},
+		{Code: "b", Health: HealthFailing},
+		{Code: "c", Health: HealthIdle},
+		{Code: "d", Health: HealthHealthy},
+		{Code: "e", Health: HealthDegraded},
+	}
+	sortByHealth(hs)
+	var codes []string
+	for _, h := range hs {
+		codes = append(codes, h.Code)
+	}
+	assert.Equal(t, []string{"b", "e", "a", "d", "c"}, codes)
+	assert.True(t, HealthIdle.Valid())
+	assert.False(t, Health("SICK").Valid())
+}
+
+func TestRecommendPool(t *testing.T) {
+	ms := func(v float64) *float64 { return &v }
+	steady := func(peakPerMin, p95 float64) PoolLoad {
+		return PoolLoad{
+			Arrivals: 1000, Attempts: 1000, PeakArrivalsPerMin: peakPerMin,
+			ServiceTime: ServiceTime{Samples: 1000, P95Ms: ms(p95)},
+		}
+	}
+
+	rec := RecommendPool(10, nil, PoolLoad{ServiceTime: ServiceTime{Samples: MinCapacitySamples - 1, P95Ms: ms(100)}})
+	assert.True(t, rec.InsufficientData)
+	assert.Equal(t, int32(10), rec.Concurrency, "too little data leaves the settings alone")
+
+	// 10 jobs/s at 2 s each keeps 20 in flight; plus headroom.
+	rec = RecommendPool(10, nil, steady(600, 2000))
+	assert.False(t, rec.InsufficientData)
+	assert.Equal(t, int32(25), rec.Concurrency)
+	assert.Nil(t, rec.RateLimit, "a rate limit is never added")
+
+	rec = RecommendPool(10, nil, steady(60, 500))
+	assert.Equal(t, int32(1), rec.Concurrency, "an over-provisioned pool is sized down")
+
+	retried := steady(120, 1000)
+	retried.Attempts = 3000
+	assert.InDelta(t, 3, retried.AttemptsPerJob(), 1e-9)
+	rec = RecommendPool(10, nil, retried)
+	assert.Equal(t, int32(8), rec.Concurrency, "retries count as demand")
+	assert.Len(t, rec.Reasons, 2)
+
+	limit := int32(300)
+	rec = RecommendPool(25, &limit, steady(600, 2000))
+	require.NotNil(t, rec.RateLimit)
+	assert.Equal(t, int32(750), *rec.RateLimit, "a limit under peak demand is raised")
+	limit = 1000
+	rec = RecommendPool(25, &limit, steady(600, 2000))
+	assert.Equal(t, &limit, rec.RateLimit, "a limit above demand is kept")
+
+	load := steady(600, 2000)
+	load.MeanInFlight, load.PeakInFlight = 5, 20
+	rec = RecommendPool(10, nil, load)
+	assert.InDelta(t, 0.5, rec.MeanUtilization, 1e-9)
+	assert.InDelta(t, 2, rec.PeakUtilization, 1e-9)
+}
@@ -0,0 +1,207 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Capacity planning thresholds.
+const (
+	// MinCapacitySamples is how many timed attempts a pool needs in the
+	// range before RecommendPool suggests a change.
+	MinCapacitySamples = 50
+	// CapacityHeadroom is the margin recommendations leave above the
+	// observed peak.
+	CapacityHeadroom = 1.25
+)
+
+// ServiceTime is the distribution of a pool's attempt durations.
+// Percentiles are nil when no attempt recorded a duration.
+type ServiceTime struct {
+	Samples int64
+	MeanMs  *float64
+	P50Ms   *float64
+	P90Ms   *float64
+	P95Ms   *float64
+	P99Ms   *float64
+	MaxMs   *float64
+}
+
+// PoolLoad is a dispatch pool's throughput over a Range.
+//
+// Arrivals are the jobs routed to the pool that were created in the
+// range; Attempts are the delivery attempts made in it, retries
+// included. Peak rates are taken from the busiest bucket. In-flight is
+// the average number of concurrent deliveries (busy time over elapsed
+// time), attributing each attempt's whole duration to the bucket it
+// started in.
+type PoolLoad struct {
+	Arrivals           int64
+	Attempts           int64
+	MeanArrivalsPerMin float64
+	PeakArrivalsPerMin float64
+	PeakAttemptsPerMin float64
+	ServiceTime        ServiceTime
+	MeanInFlight       float64
+	PeakInFlight       float64
+}
+
+// AttemptsPerJob is the retry amplification: attempts per arriving job,
+// at least 1.
+func (l PoolLoad) AttemptsPerJob() float64 {
+	if l.Arrivals == 0 || l.Attempts <= l.Arrivals {
+		return 1
+	}
+	return float64(l.Attempts) / float64(l.Arrivals)
+}
+
+// PeakDemandPerMin is the attempt rate the pool must sustain at peak:
+// peak arrivals times retry amplification, or the observed attempt peak
+// when that is higher. Arrival-based demand isn't capped by the pool's
+// current settings the way observed attempts are.
+func (l PoolLoad) PeakDemandPerMin() float64 {
+	return math.Max(l.PeakArrivalsPerMin*l.AttemptsPerJob(), l.PeakAttemptsPerMin)
+}
+
+// PoolLoad measures poolID's throughput over r.
+func (repo *Repository) PoolLoad(ctx context.Context, poolID string, r Range, now time.Time) (PoolLoad, error) {
+	from, to := r.Window(now)
+	bucketMin := r.Bucket.Minutes()
+	var l PoolLoad
+
+	rows, err := repo.pool.Query(ctx,
+		`SELECT count(*)
+		   FROM msg_dispatch_jobs_read
+		  WHERE dispatch_pool_id = $1 AND created_at >= $2 AND created_at < $3
+		  GROUP BY date_bin($4::float8 * interval '1 second', created_at, $2)`,
+		poolID, from, to, r.Bucket.Seconds())
+	if err != nil {
+		return l, fmt.Errorf("analytics pool arrivals: %w", err)
+	}
+	var n int64
+	if _, err := pgx.ForEachRow(rows, []any{&n}, func() error {
+		l.Arrivals += n
+		l.PeakArrivalsPerMin = math.Max(l.PeakArrivalsPerMin, float64(n)/bucketMin)
+		return nil
+	}); err != nil {
+		return l, fmt.Errorf("analytics pool arrivals: %w", err)
+	}
+	l.MeanArrivalsPerMin = float64(l.Arrivals) / r.Span.Minutes()
+
+	// Attempts are bounded on their own created_at so only the range's
+	// partitions are scanned; the job is looked up by id for its pool.
+	const attempts = `FROM msg_dispatch_job_attempts a
+		   JOIN msg_dispatch_jobs_read j ON j.id = a.dispatch_job_id
+		  WHERE j.dispatch_pool_id = $1 AND a.created_at >= $2 AND a.created_at < $3`
+	rows, err = repo.pool.Query(ctx,
+		`SELECT count(*), COALESCE(sum(a.duration_millis), 0)::float8
+		   `+attempts+`
+		  GROUP BY date_bin($4::float8 * interval '1 second', a.created_at, $2)`,
+		poolID, from, to, r.Bucket.Seconds())
+	if err != nil {
+		return l, fmt.Errorf("analytics pool attempts: %w", err)
+	}
+	var busyMs, totalBusyMs float64
+	bucketMs := float64(r.Bucket.Milliseconds())
+	if _, err := pgx.ForEachRow(rows, []any{&n, &busyMs}, func() error {
+		l.Attempts += n
+		totalBusyMs += busyMs
+		l.PeakAttemptsPerMin = math.Max(l.PeakAttemptsPerMin, float64(n)/bucketMin)
+		l.PeakInFlight = math.Max(l.PeakInFlight, busyMs/bucketMs)
+		return nil
+	}); err != nil {
+		return l, fmt.Errorf("analytics pool attempts: %w", err)
+	}
+	l.MeanInFlight = totalBusyMs / float64(r.Span.Milliseconds())
+
+	st := &l.ServiceTime
+	if err := repo.pool.QueryRow(ctx,
+		`SELECT count(a.duration_millis), avg(a.duration_millis)::float8,
+		        percentile_cont(0.5) WITHIN GROUP (ORDER BY a.duration_millis),
+		        percentile_cont(0.9) WITHIN GROUP (ORDER BY a.duration_millis),
+		        percentile_cont(0.95) WITHIN GROUP (ORDER BY a.duration_millis),
+		        percentile_cont(0.99) WITHIN GROUP (ORDER BY a.duration_millis),
+		        max(a.duration_millis)::float8
+		   `+attempts, poolID, from, to).
+		Scan(&st.Samples, &st.MeanMs, &st.P50Ms, &st.P90Ms, &st.P95Ms, &st.P99Ms, &st.MaxMs); err != nil {
+		return l, fmt.Errorf("analytics pool service time: %w", err)
+	}
+	return l, nil
+}
+
+// PoolRecommendation is RecommendPool's suggested settings, with the
+// reasoning behind them.
+type PoolRecommendation struct {
+	Concurrency int32
+	// RateLimit is messages per minute; nil means no limit.
+	RateLimit *int32
+	// MeanUtilization/PeakUtilization are in-flight deliveries over the
+	// configured concurrency.
+	MeanUtilization float64
+	PeakUtilization float64
+	// InsufficientData: fewer than MinCapacitySamples timed attempts, so
+	// the current settings are returned unchanged.
+	InsufficientData bool
+	Reasons          []string
+}
+
+// RecommendPool sizes a pool currently set to concurrency and rateLimit
+// for the demand in l.
+//
+// Concurrency follows Little's law: peak demand (attempts per second)
+// times the p95 service time is the number of deliveries in flight at
+// once, plus CapacityHeadroom. It may come out below the current
+// setting for an over-provisioned pool.
+//
+// A rate limit protects the receivers, which throughput alone can't
+// judge, so one is never added or lowered; an existing limit below peak
+// demand is flagged and raised to cover it.
+func RecommendPool(concurrency int32, rateLimit *int32, l PoolLoad) PoolRecommendation {
+	rec := PoolRecommendation{Concurrency: concurrency, RateLimit: rateLimit}
+	if concurrency > 0 {
+		rec.MeanUtilization = l.MeanInFlight / float64(concurrency)
+		rec.PeakUtilization = l.PeakInFlight / float64(concurrency)
+	}
+	if l.ServiceTime.Samples < MinCapacitySamples || l.ServiceTime.P95Ms == nil {
+		rec.InsufficientData = true
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"only %d timed attempts in the range; at least %d are needed for a recommendation",
+			l.ServiceTime.Samples, MinCapacitySamples))
+		return rec
+	}
+
+	demand := l.PeakDemandPerMin()
+	p95 := *l.ServiceTime.P95Ms
+	need := int32(math.Max(1, math.Ceil(demand/60*p95/1000*CapacityHeadroom)))
+	rec.Concurrency = need
+	switch {
+	case need > concurrency:
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"peak demand of %.0f attempts/min at a p95 service time of %.0f ms needs about %d concurrent deliveries; %d are configured",
+			demand, p95, need, concurrency))
+	case need < concurrency:
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"peak demand of %.0f attempts/min at a p95 service time of %.0f ms needs about %d concurrent deliveries; %d are configured and peak utilization was %.0f%%",
+			demand, p95, need, concurrency, rec.PeakUtilization*100))
+	default:
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("concurrency %d fits peak demand", concurrency))
+	}
+	if l.AttemptsPerJob() > 1.5 {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"retries make up a large share of the load (%.1f attempts per job); fixing failing receivers frees capacity",
+			l.AttemptsPerJob()))
+	}
+
+	if rateLimit != nil && demand > float64(*rateLimit) {
+		raised := int32(math.Ceil(demand * CapacityHeadroom))
+		rec.RateLimit = &raised
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"peak demand of %.0f/min exceeds the rate limit of %d/min, so jobs queue behind it; raise it to %d if the receivers can take it",
+			demand, *rateLimit, raised))
+	}
+	return rec
+}
@@ -0,0 +1,194 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+)
+
+// Health is a subscription's delivery health over a Range.
+type Health string
+
+const (
+	// HealthFailing: FailingAfter or more deliveries in a row have failed.
+	HealthFailing Health = "FAILING"
+	// HealthDegraded: some recent deliveries failed, or the success rate
+	// is under DegradedBelow.
+	HealthDegraded Health = "DEGRADED"
+	HealthHealthy  Health = "HEALTHY"
+	// HealthIdle: nothing finished in the range and nothing is waiting.
+	HealthIdle Health = "IDLE"
+	// HealthPaused: the subscription is paused; its jobs wait.
+	HealthPaused Health = "PAUSED"
+)
+
+// Healths lists every Health, worst first — the order the health page
+// sorts by.
+var Healths = []Health{HealthFailing, HealthDegraded, HealthHealthy, HealthIdle, HealthPaused}
+
+// Valid reports whether h is a known Health.
+func (h Health) Valid() bool {
+	for _, x := range Healths {
+		if x == h {
+			return true
+		}
+	}
+	return false
+}
+
+// rank orders h worst first.
+func (h Health) rank() int {
+	for i, x := range Healths {
+		if x == h {
+			return i
+		}
+	}
+	return len(Healths)
+}
+
+// Classification thresholds.
+const (
+	FailingAfter  = 5
+	DegradedBelow = 0.95
+)
+
+// SubscriptionFilter narrows a health listing. AccessibleClientIDs has
+// Filter's contract.
+type SubscriptionFilter struct {
+	SubscriptionID      *string
+	ClientID            *string
+	Status              *string
+	AccessibleClientIDs *[]string
+}
+
+// SubscriptionHealth summarises one subscription's recent deliveries.
+//
+// JobStats and the delivery times cover jobs created in the range.
+// ConsecutiveFailures counts the jobs whose latest attempt failed after
+// the subscription's last success in the range. Backlog counts its
+// unfinished (PENDING, QUEUED, PROCESSING) jobs however old.
+//
+// There is no breaker state here: the router's circuit breakers are
+// keyed by mediation target, and every dispatch job's target is the
+// platform callback, so they don't tell subscriptions apart.
+// ConsecutiveFailures is the per-subscription signal instead.
+type SubscriptionHealth struct {
+	SubscriptionID   string
+	Code             string
+	Name             string
+	ClientID         *string
+	Status           string
+	DispatchPoolCode *string
+	JobStats
+	LastSuccessAt       *time.Time
+	LastAttemptAt       *time.Time
+	ConsecutiveFailures int64
+	Backlog             int64
+	OldestBacklogAt     *time.Time
+	Health              Health
+}
+
+// Classify derives Health from the other fields.
+func (h *SubscriptionHealth) Classify() Health {
+	switch {
+	case h.Status == "PAUSED":
+		return HealthPaused
+	case h.ConsecutiveFailures >= FailingAfter:
+		return HealthFailing
+	case h.ConsecutiveFailures > 0:
+		return HealthDegraded
+	}
+	rate := h.SuccessRate()
+	switch {
+	case rate == nil && h.Backlog == 0:
+		return HealthIdle
+	case rate != nil && *rate < DegradedBelow:
+		return HealthDegraded
+	}
+	return HealthHealthy
+}
+
+// SubscriptionHealth returns the health of every subscription f matches,
+// worst first, then by code.
+func (repo *Repository) SubscriptionHealth(ctx context.Context, r Range, f SubscriptionFilter, now time.Time) ([]SubscriptionHealth, error) {
+	f.AccessibleClientIDs = reqctx.ClientScope(ctx, f.AccessibleClientIDs)
+	from, to := r.Window(now)
+	var w repocommon.Filter
+	w.EqPtr("id", f.SubscriptionID)
+	w.EqPtr("client_id", f.ClientID)
+	w.EqPtr("status", f.Status)
+	if f.AccessibleClientIDs != nil {
+		w.Clause("(client_id IS NULL OR client_id = ANY($%d))", *f.AccessibleClientIDs)
+	}
+	pFrom, pTo := w.Arg(from), w.Arg(to)
+
+	// stats and fails scan the range's partitions of the read projection;
+	// backlog uses its status index across all of them.
+	rows, err := repo.pool.Query(ctx, fmt.Sprintf(
+		`WITH subs AS (
+		     SELECT id, code, name, client_id, status, dispatch_pool_code
+		       FROM msg_subscriptions%[1]s),
+		 stats (subscription_id, dispatched, succeeded, failed, p50, p95, last_success, last_attempt) AS (
+		     SELECT subscription_id, %[2]s,
+		            max(completed_at) FILTER (WHERE status = 'COMPLETED'),
+		            max(last_attempt_at)
+		       FROM msg_dispatch_jobs_read
+		      WHERE created_at >= $%[3]d AND created_at < $%[4]d
+		        AND subscription_id IN (SELECT id FROM subs)
+		      GROUP BY subscription_id),
+		 fails AS (
+		     SELECT j.subscription_id, count(*) AS n
+		       FROM msg_dispatch_jobs_read j
+		       JOIN stats s ON s.subscription_id = j.subscription_id
+		      WHERE j.created_at >= $%[3]d AND j.created_at < $%[4]d
+		        AND j.status <> 'COMPLETED' AND j.last_error IS NOT NULL
+		        AND j.last_attempt_at > COALESCE(s.last_success, '-infinity')
+		      GROUP BY j.subscription_id),
+		 backlog AS (
+		     SELECT subscription_id, count(*) AS n, min(created_at) AS oldest
+		       FROM msg_dispatch_jobs_read
+		      WHERE status IN ('PENDING', 'QUEUED', 'PROCESSING')
+		        AND subscription_id IN (SELECT id FROM subs)
+		      GROUP BY subscription_id)
+		 SELECT subs.id, subs.code, subs.name, subs.client_id, subs.status, subs.dispatch_pool_code,
+		        COALESCE(stats.dispatched, 0), COALESCE(stats.succeeded, 0), COALESCE(stats.failed, 0),
+		        stats.p50, stats.p95,
+		        stats.last_success, stats.last_attempt,
+		        COALESCE(fails.n, 0), COALESCE(backlog.n, 0), backlog.oldest
+		   FROM subs
+		   LEFT JOIN stats ON stats.subscription_id = subs.id
+		   LEFT JOIN fails ON fails.subscription_id = subs.id
+		   LEFT JOIN backlog ON backlog.subscription_id = subs.id
+		  ORDER BY subs.code`, w.Where(), jobAggregates, pFrom, pTo), w.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("analytics subscription health: %w", err)
+	}
+	var h SubscriptionHealth
+	var out []SubscriptionHealth
+	if _, err := pgx.ForEachRow(rows, []any{
+		&h.SubscriptionID, &h.Code, &h.Name, &h.ClientID, &h.Status, &h.DispatchPoolCode,
+		&h.Dispatched, &h.Succeeded, &h.Failed, &h.P50Ms, &h.P95Ms,
+		&h.LastSuccessAt, &h.LastAttemptAt,
+		&h.ConsecutiveFailures, &h.Backlog, &h.OldestBacklogAt,
+	}, func() error {
+		h.Health = h.Classify()
+		out = append(out, h)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("analytics subscription health: %w", err)
+	}
+	sortByHealth(out)
+	return out, nil
+}
+
+// sortByHealth orders hs worst first, keeping the code order within a
+// Health.
+func sortByHealth(hs []SubscriptionHealth) {
+	slices.SortStableFunc(hs, func(a, b SubscriptionHealth) int { return a.Health.rank() - b.Health.rank() })
+}
@@ -0,0 +1,209 @@
+// Package apiactivity records a sampled, privacy-aware log of authenticated
+// API calls — which route each principal (user or service account) hit,
+// the status it got back, and how long it took — and answers the per-
+// principal aggregate queries behind the admin API usage explorer
+// (/bff/api-activity/*).
+//
+// Writes are infrastructure-processing like loginattempt: the HTTP
+// middleware hands calls to an in-process Recorder which batches them into
+// iam_api_activity directly (no UoW commit, no domain event). Only the chi
+// route pattern is kept — never the concrete path, query string, body, IP
+// or user agent.
+package apiactivity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// Call is a single recorded API call.
+type Call struct {
+	PrincipalID  string    `json:"principalId"`
+	Method       string    `json:"method"`
+	Route        string    `json:"route"`
+	Status       int       `json:"status"`
+	DurationMS   int       `json:"durationMs"`
+	SampleWeight int       `json:"sampleWeight"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
+
+// IsError reports whether the call failed from the caller's point of view
+// (any 4xx/5xx).
+func (c Call) IsError() bool { return c.Status >= 400 }
+
+// Config holds the recorder knobs (all env-overridable).
+type Config struct {
+	// Enabled turns recording on. When false the middleware is a pass-through.
+	Enabled bool
+	// SampleRate is the probability (0..1] a successful call is recorded.
+	// Failed calls are always recorded.
+	SampleRate float64
+	// RetentionDays bounds how long rows are kept before the prune tick
+	// deletes them.
+	RetentionDays int
+	// BufferSize is the capacity of the in-process hand-off channel. Calls
+	// arriving while it is full are dropped rather than blocking a request.
+	BufferSize int
+	// FlushInterval is how often buffered calls are written.
+	FlushInterval time.Duration
+}
+
+// ConfigFromEnv builds a Config from FC_API_ACTIVITY_* env vars.
+func ConfigFromEnv() Config {
+	rate := float64(envutil.Int("FC_API_ACTIVITY_SAMPLE_PERCENT", 10)) / 100
+	if rate <= 0 || rate > 1 {
+		rate = 0.1
+	}
+	return Config{
+		Enabled:       envutil.Bool("FC_API_ACTIVITY_ENABLED", true),
+		SampleRate:    rate,
+		RetentionDays: envutil.Int("FC_API_ACTIVITY_RETENTION_DAYS", 14),
+		BufferSize:    envutil.Int("FC_API_ACTIVITY_BUFFER", 4096),
+		FlushInterval: 2 * time.Second,
+	}
+}
+
+// Repository writes/reads iam_api_activity. Direct writes (no UoW).
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+// InsertBatch persists calls in one round-trip.
+func (r *Repository) InsertBatch(ctx context.Context, calls []Call) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	rows := make([][]any, len(calls))
+	for i, c := range calls {
+		rows[i] = []any{c.PrincipalID, c.Method, c.Route, int16(c.Status), int32(c.DurationMS), int32(c.SampleWeight), c.OccurredAt}
+	}
+	_, err := r.pool.CopyFrom(ctx,
+		pgx.Identifier{"iam_api_activity"},
+		[]string{"principal_id", "method", "route", "status", "duration_ms", "sample_weight", "occurred_at"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("insert_batch: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes calls older than olderThan. Returns the number removed.
+func (r *Repository) Prune(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().UTC().Add(-olderThan)
+	tag, err := r.pool.Exec(ctx, `DELETE FROM iam_api_activity WHERE occurred_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PrincipalSummary is the per-principal aggregate for a window. Calls and
+// Errors are sample-weighted estimates. LastSeenAt spans the whole retained
+// history (not just the window) so a principal that has gone quiet still
+// shows up with calls=0 — the "stale integration" signal.
+type PrincipalSummary struct {
+	PrincipalID   string
+	PrincipalName *string
+	PrincipalType *string
+	Calls         int64
+	Errors        int64
+	LastSeenAt    time.Time
+}
+
+// SummarizeByPrincipal aggregates every principal with retained activity.
+func (r *Repository) SummarizeByPrincipal(ctx context.Context, since time.Time) ([]PrincipalSummary, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT a.principal_id, p.name, p.type,
+		        COALESCE(SUM(a.sample_weight) FILTER (WHERE a.occurred_at >= $1), 0),
+		        COALESCE(SUM(a.sample_weight) FILTER (WHERE a.occurred_at >= $1 AND a.status >= 400), 0),
+		        MAX(a.occurred_at)
+		   FROM iam_api_activity a
+		   LEFT JOIN iam_principals p ON p.id = a.principal_id
+		  GROUP BY a.principal_id, p.name, p.type
+		  ORDER BY 4 DESC, 6 DESC`, since)
+	if err != nil {
+		return nil, fmt.Errorf("summarize_by_principal: %w", err)
+	}
+	defer rows.Close()
+	var out []PrincipalSummary
+	for rows.Next() {
+		var s PrincipalSummary
+		if err := rows.Scan(&s.PrincipalID, &s.PrincipalName, &s.PrincipalType,
+			&s.Calls, &s.Errors, &s.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("summarize_by_principal: %w", err)
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// EndpointSummary is a principal's per-route aggregate for a window.
+type EndpointSummary struct {
+	Method        string
+	Route         string
+	Calls         int64
+	Errors        int64
+	AvgDurationMS float64
+	LastSeenAt    time.Time
+}
+
+// SummarizeEndpoints aggregates one principal's calls per (method, route).
+func (r *Repository) SummarizeEndpoints(ctx context.Context, principalID string, since time.Time) ([]EndpointSummary, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT method, route,
+		        SUM(sample_weight),
+		        COALESCE(SUM(sample_weight) FILTER (WHERE status >= 400), 0),
+		        AVG(duration_ms)::float8,
+		        MAX(occurred_at)
+		   FROM iam_api_activity
+		  WHERE principal_id = $1 AND occurred_at >= $2
+		  GROUP BY method, route
+		  ORDER BY 3 DESC`, principalID, since)
+	if err != nil {
+		return nil, fmt.Errorf("summarize_endpoints: %w", err)
+	}
+	defer rows.Close()
+	var out []EndpointSummary
+	for rows.Next() {
+		var s EndpointSummary
+		if err := rows.Scan(&s.Method, &s.Route, &s.Calls, &s.Errors, &s.AvgDurationMS, &s.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("summarize_endpoints: %w", err)
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// RecentCalls returns a principal's most recent recorded calls, optionally
+// restricted to failures.
+func (r *Repository) RecentCalls(ctx context.Context, principalID string, errorsOnly bool, limit int) ([]Call, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT principal_id, method, route, status, duration_ms, sample_weight, occurred_at
+		   FROM iam_api_activity
+		  WHERE principal_id = $1 AND ($2::bool = FALSE OR status >= 400)
+		  ORDER BY occurred_at DESC
+		  LIMIT $3`, principalID, errorsOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("recent_calls: %w", err)
+	}
+	defer rows.Close()
+	var out []Call
+	for rows.Next() {
+		var c Call
+		var status int16
+		var dur, weight int32
+		if err := rows.Scan(&c.PrincipalID, &c.Method, &c.Route, &status, &dur, &weight, &c.OccurredAt); err != nil {
+			return nil, fmt.Errorf("recent_calls: %w", err)
+		}
+		c.Status, c.DurationMS, c.SampleWeight = int(status), int(dur), int(weight)
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,172 @@
+package apiactivity
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+)
+
+// sink is the write side the Recorder flushes into. *Repository satisfies it;
+// tests substitute an in-memory fake.
+type sink interface {
+	InsertBatch(ctx context.Context, calls []Call) error
+	Prune(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// Recorder samples authenticated calls off the request path and writes them
+// in batches. Construct with NewRecorder, mount Middleware inside the auth
+// group, and run Run in its own goroutine.
+type Recorder struct {
+	cfg    Config
+	sink   sink
+	weight int
+	// calls is the hand-off from request goroutines to Run. Sends are
+	// non-blocking (drop on full); never closed — Run exits on ctx.
+	calls chan Call
+	// sample decides whether a successful call is kept. Swappable in tests.
+	sample func() bool
+}
+
+// NewRecorder wires a Recorder against repo.
+func NewRecorder(cfg Config, repo *Repository) *Recorder {
+	return newRecorder(cfg, repo)
+}
+
+func newRecorder(cfg Config, s sink) *Recorder {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 4096
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 2 * time.Second
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+	rate := cfg.SampleRate
+	return &Recorder{
+		cfg:    cfg,
+		sink:   s,
+		weight: int(math.Round(1 / rate)),
+		calls:  make(chan Call, cfg.BufferSize),
+		sample: func() bool { return rand.Float64() < rate }, //nolint:gosec // sampling, not security
+	}
+}
+
+// Middleware records the call after the handler returns, when the request
+// carried an AuthContext. Must run inside the Authenticator group so the
+// principal is resolved, and relies on chi having matched the route so the
+// pattern (not the concrete path) is what gets stored.
+func (rec *Recorder) Middleware(next http.Handler) http.Handler {
+	if rec == nil || !rec.cfg.Enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		ac := auth.FromContext(r.Context())
+		if ac == nil || ac.PrincipalID == "" {
+			return
+		}
+		route := ""
+		if rc := chi.RouteContext(r.Context()); rc != nil {
+			route = rc.RoutePattern()
+		}
+		if route == "" {
+			// Unmatched route — nothing meaningful (and nothing safe) to store.
+			return
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		rec.Observe(Call{
+			PrincipalID: ac.PrincipalID,
+			Method:      r.Method,
+			Route:       route,
+			Status:      status,
+			DurationMS:  int(time.Since(start).Milliseconds()),
+			OccurredAt:  time.Now().UTC(),
+		})
+	})
+}
+
+// Observe applies the sampling policy and queues the call. Failures are
+// always kept at weight 1; successes are kept with probability SampleRate
+// and weighted 1/SampleRate so aggregate sums estimate true volume.
+func (rec *Recorder) Observe(c Call) {
+	if c.IsError() {
+		c.SampleWeight = 1
+	} else {
+		if !rec.sample() {
+			return
+		}
+		c.SampleWeight = rec.weight
+	}
+	select {
+	case rec.calls <- c:
+	default:
+		// Buffer full — drop. Losing a sample is preferable to adding
+		// latency to the request path.
+	}
+}
+
+// Run drains the buffer every FlushInterval and prunes expired rows hourly.
+// Blocks until ctx is cancelled; a final flush runs on the way out.
+func (rec *Recorder) Run(ctx context.Context) {
+	if !rec.cfg.Enabled {
+		return
+	}
+	flush := time.NewTicker(rec.cfg.FlushInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(time.Hour)
+	defer prune.Stop()
+	slog.Info("api activity recorder started", "sample_rate", rec.cfg.SampleRate)
+	for {
+		select {
+		case <-ctx.Done():
+			// shutdown — write what's buffered on a fresh context.
+			fctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			rec.flush(fctx)
+			cancel()
+			slog.Info("api activity recorder stopped")
+			return
+		case <-flush.C:
+			// flush interval elapsed
+			rec.flush(ctx)
+		case <-prune.C:
+			// hourly retention sweep
+			if rec.cfg.RetentionDays <= 0 {
+				continue
+			}
+			if n, err := rec.sink.Prune(ctx, time.Duration(rec.cfg.RetentionDays)*24*time.Hour); err != nil {
+				slog.Warn("api activity prune failed", "err", err)
+			} else if n > 0 {
+				slog.Debug("api activity prune", "removed", n)
+			}
+		}
+	}
+}
+
+func (rec *Recorder) flush(ctx context.Context) {
+	n := len(rec.calls)
+	if n == 0 {
+		return
+	}
+	batch := make([]Call, 0, n)
+	for i := 0; i < n; i++ {
+		batch = append(batch, <-rec.calls)
+	}
+	if err := rec.sink.InsertBatch(ctx, batch); err != nil {
+		slog.Warn("api activity flush failed; batch dropped", "calls", len(batch), "err", err)
+	}
+}
@@ -0,0 +1,104 @@
+package apiactivity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+)
+
+type fakeSink struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (f *fakeSink) InsertBatch(_ context.Context, calls []Call) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, calls...)
+	return nil
+}
+
+func (f *fakeSink) Prune(context.Context, time.Duration) (int64, error) { return 0, nil }
+
+func newTestRecorder(keep bool) (*Recorder, *fakeSink) {
+	s := &fakeSink{}
+	rec := newRecorder(Config{Enabled: true, SampleRate: 0.25, BufferSize: 16}, s)
+	rec.sample = func() bool { return keep }
+	return rec, s
+}
+
+func serve(rec *Recorder, principal string, status int) {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if principal != "" {
+				req = req.WithContext(auth.WithContext(req.Context(), &auth.AuthContext{PrincipalID: principal}))
+			}
+			next.ServeHTTP(w, req)
+		})
+	})
+	r.Use(rec.Middleware)
+	r.Get("/api/clients/{id}", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(status) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/clients/clt_123?secret=x", nil))
+}
+
+func TestMiddlewareRecordsRoutePatternOnly(t *testing.T) {
+	rec, s := newTestRecorder(true)
+	serve(rec, "prn_1", http.StatusOK)
+	rec.flush(context.Background())
+
+	if len(s.calls) != 1 {
+		t.Fatalf("recorded %d calls, want 1", len(s.calls))
+	}
+	c := s.calls[0]
+	if c.Route != "/api/clients/{id}" {
+		t.Errorf("route = %q, want the chi pattern without concrete ids or query", c.Route)
+	}
+	if c.PrincipalID != "prn_1" || c.Method != http.MethodGet || c.Status != http.StatusOK {
+		t.Errorf("unexpected call %+v", c)
+	}
+	if c.SampleWeight != 4 {
+		t.Errorf("sample weight = %d, want 4 (1/0.25)", c.SampleWeight)
+	}
+}
+
+func TestMiddlewareSkipsUnauthenticated(t *testing.T) {
+	rec, s := newTestRecorder(true)
+	serve(rec, "", http.StatusOK)
+	rec.flush(context.Background())
+	if len(s.calls) != 0 {
+		t.Fatalf("recorded %d calls for an anonymous request, want 0", len(s.calls))
+	}
+}
+
+func TestObserveAlwaysKeepsFailures(t *testing.T) {
+	rec, s := newTestRecorder(false)
+	serve(rec, "prn_1", http.StatusOK)
+	serve(rec, "prn_1", http.StatusForbidden)
+	rec.flush(context.Background())
+
+	if len(s.calls) != 1 {
+		t.Fatalf("recorded %d calls, want only the failure", len(s.calls))
+	}
+	if s.calls[0].Status != http.StatusForbidden || s.calls[0].SampleWeight != 1 {
+		t.Errorf("failure should be kept at weight 1, got %+v", s.calls[0])
+	}
+}
+
+func TestObserveDropsWhenBufferFull(t *testing.T) {
+	rec, s := newTestRecorder(true)
+	for i := 0; i < 20; i++ {
+		rec.Observe(Call{PrincipalID: "prn_1", Status: 200})
+	}
+	rec.flush(context.Background())
+	if len(s.calls) != 16 {
+		t.Fatalf("recorded %d calls, want buffer capacity 16", len(s.calls))
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
@@ -119,7 +120,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateApplicationRe
 	if err := auth.CanWriteApplications(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateApplication(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -137,7 +138,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanWriteApplications(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateApplication(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -149,7 +150,7 @@ func (s *State) activate(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	if err := auth.CanWriteApplications(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ActivateApplication(s.Repo), operations.ActivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -161,7 +162,7 @@ func (s *State) deactivate(ctx context.Context, in *apicommon.IDInput) (*apicomm
 	if err := auth.CanWriteApplications(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeactivateApplication(s.Repo), operations.DeactivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -186,7 +187,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteApplications(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteApplication(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -203,7 +204,7 @@ func (s *State) attachServiceAccount(ctx context.Context, in *attachSAInput) (*a
 	if err := auth.RequireAnchor(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.AttachServiceAccount(s.Repo, s.Principals),
 		operations.AttachServiceAccountCommand{
 			ApplicationID:      in.ID,
@@ -238,7 +239,7 @@ func (s *State) enableForClient(ctx context.Context, in *clientToggleInput) (*ap
 	if err := auth.RequireAnchor(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.EnableApplicationForClient(s.Repo, s.ClientRepo, s.ClientConfigRepo),
 		operations.EnableForClientCommand{ApplicationID: in.ID, ClientID: in.ClientID}, ec); err != nil {
 		return nil, err
@@ -251,7 +252,7 @@ func (s *State) disableForClient(ctx context.Context, in *clientToggleInput) (*a
 	if err := auth.RequireAnchor(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DisableApplicationForClient(s.ClientConfigRepo),
 		operations.DisableForClientCommand{ApplicationID: in.ID, ClientID: in.ClientID}, ec); err != nil {
 		return nil, err
@@ -271,7 +272,7 @@ func (s *State) provisionServiceAccount(ctx context.Context, in *apicommon.IDInp
 	if err := auth.RequireAnchor(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	result, err := usecaseop.RunTx(ctx, s.UoW,
 		operations.ProvisionServiceAccount(s.Repo, s.ServiceAccounts, s.Principals, s.OAuthClients),
 		operations.ProvisionServiceAccountCommand{ApplicationID: in.ID}, ec)
@@ -322,7 +323,7 @@ func (s *State) provisionLoginClient(ctx context.Context, in *provisionLoginClie
 		clientType = "CONFIDENTIAL"
 	}
 
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	publicClientID := tsid.Generate(tsid.OAuthClient)
 	cmd := authops.CreateOAuthClientCommand{
 		ClientID:     publicClientID,
@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
 	"strings"
 	"time"
 
@@ -13,10 +12,10 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
 	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	authops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 	saops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount/operations"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -206,24 +205,16 @@ func ProvisionServiceAccount(
 	}
 }
 
-// generateClientSecret returns a fresh URL-safe secret + its encrypted
-// reference (client_secret_ref). Same scheme as
-// auth/operations.generateSecret — Rust parity: secrets are reversibly
-// encrypted, not hashed.
+// generateClientSecret returns a fresh URL-safe secret + its argon2id
+// hash (client_secret_ref). Same scheme as auth/operations.generateSecret:
+// the plaintext is disclosed once and only the hash is kept.
 func generateClientSecret() (plaintext, ref string, err error) {
 	b := make([]byte, 32)
 	if _, err = rand.Read(b); err != nil {
 		return "", "", err
 	}
 	plaintext = base64.RawURLEncoding.EncodeToString(b)
-	enc, err := encryption.FromEnv()
-	if err != nil {
-		return "", "", err
-	}
-	if enc == nil {
-		return "", "", errors.New("FLOWCATALYST_APP_KEY not configured; cannot encrypt client secret")
-	}
-	ref, err = enc.Encrypt(plaintext)
+	ref, err = passwordhash.Hash(plaintext)
 	if err != nil {
 		return "", "", err
 	}
@@ -28,6 +28,10 @@ type AuditLogResponse struct {
 	ApplicationID *string         `json:"applicationId,omitempty"`
 	ClientID      *string         `json:"clientId,omitempty"`
 	PerformedAt   httpcompat.Time `json:"performedAt"`
+	// Changes lists the fields the operation changed on its aggregate,
+	// before and after (credentials redacted). Absent for rows that
+	// record no aggregate change.
+	Changes []audit.Change `json:"changes,omitempty"`
 }
 
 func fromEntity(l *audit.Log) AuditLogResponse {
@@ -55,6 +59,7 @@ func fromEntity(l *audit.Log) AuditLogResponse {
 		ApplicationID: l.ApplicationID,
 		ClientID:      l.ClientID,
 		PerformedAt:   jsontime.New(l.PerformedAt),
+		Changes:       l.Changes,
 	}
 }
 
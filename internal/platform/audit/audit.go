@@ -33,6 +33,9 @@ type Log struct {
 	ApplicationID *string         `json:"applicationId,omitempty"`
 	ClientID      *string         `json:"clientId,omitempty"`
 	PerformedAt   time.Time       `json:"performedAt"`
+	// Changes is the before/after diff of the aggregate the operation
+	// wrote (see Diff); nil when the row records no aggregate change.
+	Changes []Change `json:"changes,omitempty"`
 }
 
 // Repository is the read-only audit log repo.
@@ -51,26 +54,46 @@ func NewRepository(pool *pgxpool.Pool) *Repository {
 // (platformsink.WriteAudit). This direct insert backs the SDK/outbox batch
 // audit-ingest endpoint (POST /api/audit-logs/batch), mirroring Rust
 // audit_log_repo.insert (a plain insert outside the UoW). The column set
-// matches WriteAudit + migrations 006/009.
+// matches WriteAudit + migrations 006/009/053.
 func (r *Repository) Insert(ctx context.Context, l *Log) error {
-	var opJSON any
+	var opJSON, changes any
 	if len(l.OperationJSON) > 0 {
 		opJSON = []byte(l.OperationJSON)
 	}
+	if len(l.Changes) > 0 {
+		raw, err := json.Marshal(l.Changes)
+		if err != nil {
+			return fmt.Errorf("insert aud_logs: %w", err)
+		}
+		changes = raw
+	}
 	_, err := r.pool.Exec(ctx,
 		`INSERT INTO aud_logs
 		     (id, entity_type, entity_id, operation,
 		      operation_json, principal_id, application_id,
-		      client_id, performed_at)
-		 VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7, $8, $9)`,
+		      client_id, performed_at, changes)
+		 VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7, $8, $9, $10::jsonb)`,
 		l.ID, l.EntityType, l.EntityID, l.Operation,
-		opJSON, l.PrincipalID, l.ApplicationID, l.ClientID, l.PerformedAt)
+		opJSON, l.PrincipalID, l.ApplicationID, l.ClientID, l.PerformedAt, changes)
 	if err != nil {
 		return fmt.Errorf("insert aud_logs: %w", err)
 	}
 	return nil
 }
 
+// decodeChanges reads aud_logs.changes. A row whose diff can't be
+// decoded is still returned, just without it.
+func decodeChanges(raw json.RawMessage) []Change {
+	if len(raw) == 0 {
+		return nil
+	}
+	var out []Change
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
 // FilterParams is the query DTO for list endpoints.
 type FilterParams struct {
 	EntityType  *string
@@ -132,7 +155,7 @@ func (r *Repository) FindWithCursor(ctx context.Context, p CursorFilterParams) (
 
 	q := `SELECT a.id, a.entity_type, a.entity_id, a.operation, a.operation_json,
        a.principal_id, p.name AS principal_name,
-       a.application_id, a.client_id, a.performed_at
+       a.application_id, a.client_id, a.performed_at, a.changes
 FROM aud_logs a
 LEFT JOIN iam_principals p ON p.id = a.principal_id` + f.Where() +
 		fmt.Sprintf(" ORDER BY a.performed_at DESC, a.id DESC LIMIT $%d", lim)
@@ -158,6 +181,7 @@ LEFT JOIN iam_principals p ON p.id = a.principal_id` + f.Where() +
 			ApplicationID: row.ApplicationID,
 			ClientID:      row.ClientID,
 			PerformedAt:   row.PerformedAt,
+			Changes:       decodeChanges(row.Changes),
 		})
 	}
 	return out, nil
@@ -196,6 +220,7 @@ func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Log
 			ApplicationID: row.ApplicationID,
 			ClientID:      row.ClientID,
 			PerformedAt:   row.PerformedAt,
+			Changes:       decodeChanges(row.Changes),
 		})
 	}
 	return out, nil
@@ -221,6 +246,7 @@ func (r *Repository) FindByID(ctx context.Context, id string) (*Log, error) {
 		ApplicationID: row.ApplicationID,
 		ClientID:      row.ClientID,
 		PerformedAt:   row.PerformedAt,
+		Changes:       decodeChanges(row.Changes),
 	}, nil
 }
 
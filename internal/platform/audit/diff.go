@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is one field that differs between an aggregate's before- and
+// after-image. Path is dotted for nested objects ("userIdentity.email");
+// arrays are compared whole. Before is absent for an added field, After
+// for a removed one.
+type Change struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// Redacted replaces the value of any field whose name looks like a
+// credential. The change itself is still recorded.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched case-insensitively as substrings of a JSON
+// field name. Aggregates serialize their secret refs and hashes (they are
+// not json:"-" because repositories round-trip them), so the diff must
+// not copy them into aud_logs.
+var sensitiveKeys = []string{"password", "secret", "token", "hash", "credential", "privatekey"}
+
+// Sensitive is implemented by aggregates with a field that only holds a
+// credential in some states (a SECRET platform-config value), which no
+// field name gives away. The named top-level fields are redacted too.
+type Sensitive interface {
+	AuditSensitive() []string
+}
+
+func sensitive(key string) bool {
+	k := strings.ToLower(key)
+	return slices.ContainsFunc(sensitiveKeys, func(s string) bool { return strings.Contains(k, s) })
+}
+
+// Diff compares the JSON documents of before and after (either may be
+// nil: a create or a delete) and returns the changed fields sorted by
+// path, with credential-like fields redacted.
+func Diff(before, after any) ([]Change, error) {
+	b, err := document(before)
+	if err != nil {
+		return nil, fmt.Errorf("diff before: %w", err)
+	}
+	a, err := document(after)
+	if err != nil {
+		return nil, fmt.Errorf("diff after: %w", err)
+	}
+	extra := append(sensitiveFields(before), sensitiveFields(after)...)
+	var out []Change
+	diffObjects("", b, a, extra, &out)
+	slices.SortFunc(out, func(x, y Change) int { return strings.Compare(x.Path, y.Path) })
+	return out, nil
+}
+
+// document round-trips v through JSON into a generic object; nil (or a
+// typed nil pointer) is the empty document.
+func document(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func sensitiveFields(v any) []string {
+	if s, ok := v.(Sensitive); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil
+		}
+		return s.AuditSensitive()
+	}
+	return nil
+}
+
+// diffObjects appends the differences between before and after under
+// prefix; extra names additional sensitive fields at this level.
+func diffObjects(prefix string, before, after map[string]any, extra []string, out *[]Change) {
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		bv, inB := before[k]
+		av, inA := after[k]
+		// Absent and null are the same state (omitempty vs a nil field).
+		if reflect.DeepEqual(bv, av) {
+			continue
+		}
+		if sensitive(k) || slices.Contains(extra, k) {
+			c := Change{Path: path}
+			if inB && bv != nil {
+				c.Before = Redacted
+			}
+			if inA && av != nil {
+				c.After = Redacted
+			}
+			*out = append(*out, c)
+			continue
+		}
+		bm, bObj := bv.(map[string]any)
+		am, aObj := av.(map[string]any)
+		if (bObj || !inB || bv == nil) && (aObj || !inA || av == nil) && (bObj || aObj) {
+			diffObjects(path, bm, am, nil, out)
+			continue
+		}
+		*out = append(*out, Change{Path: path, Before: redact(bv), After: redact(av)})
+	}
+}
+
+// redact masks sensitive fields anywhere inside v (a whole array of
+// objects recorded as one change, say).
+func redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			if sensitive(k) && e != nil {
+				out[k] = Redacted
+			} else {
+				out[k] = redact(e)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = redact(e)
+		}
+		return out
+	}
+	return v
+}
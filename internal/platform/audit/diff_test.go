@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profile struct {
+	Email string `json:"email"`
+	Phone string `json:"phone,omitempty"`
+}
+
+type account struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	PasswordHash *string  `json:"passwordHash,omitempty"`
+	Profile      profile  `json:"profile"`
+	Roles        []string `json:"roles"`
+	Secret       bool     `json:"-"`
+}
+
+func (a account) AuditSensitive() []string {
+	if a.Secret {
+		return []string{"name"}
+	}
+	return nil
+}
+
+func TestDiff_Update(t *testing.T) {
+	before := account{ID: "a1", Name: "Ada", Profile: profile{Email: "a@x"}, Roles: []string{"r1"}}
+	after := before
+	after.Name = "Ada L"
+	after.Profile.Phone = "555"
+	after.Roles = []string{"r1", "r2"}
+
+	changes, err := Diff(&before, &after)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Path: "name", Before: "Ada", After: "Ada L"},
+		{Path: "profile.phone", After: "555"},
+		{Path: "roles", Before: []any{"r1"}, After: []any{"r1", "r2"}},
+	}, changes)
+}
+
+func TestDiff_CreateAndDelete(t *testing.T) {
+	a := &account{ID: "a1", Name: "Ada", Profile: profile{Email: "a@x"}}
+
+	created, err := Diff(nil, a)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Path: "id", After: "a1"},
+		{Path: "name", After: "Ada"},
+		{Path: "profile.email", After: "a@x"},
+	}, created, "nested objects are flattened, null fields skipped")
+
+	var none *account
+	deleted, err := Diff(a, none)
+	require.NoError(t, err)
+	assert.Len(t, deleted, 3, "a typed nil after-image is a delete")
+	for _, c := range deleted {
+		assert.Nil(t, c.After)
+	}
+}
+
+func TestDiff_NoChange(t *testing.T) {
+	a := account{ID: "a1", Name: "Ada"}
+	changes, err := Diff(a, a)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiff_RedactsSensitiveFields(t *testing.T) {
+	h1, h2 := "hash-one", "hash-two"
+	before := account{ID: "a1", Name: "Ada", PasswordHash: &h1}
+	after := before
+	after.PasswordHash = &h2
+
+	changes, err := Diff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "passwordHash", Before: Redacted, After: Redacted}}, changes,
+		"the change is recorded, the values are not")
+
+	after.PasswordHash = nil
+	changes, err = Diff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "passwordHash", Before: Redacted}}, changes)
+}
+
+func TestDiff_RedactsInsideRecordedValues(t *testing.T) {
+	changes, err := Diff(
+		map[string]any{"keys": []any{map[string]any{"id": "k1", "clientSecret": "s1"}}},
+		map[string]any{"keys": []any{}},
+	)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, []any{map[string]any{"id": "k1", "clientSecret": Redacted}}, changes[0].Before)
+}
+
+func TestDiff_AggregateDeclaredSensitive(t *testing.T) {
+	before := account{ID: "a1", Name: "plain"}
+	after := account{ID: "a1", Name: "s3cr3t", Secret: true}
+
+	changes, err := Diff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "name", Before: Redacted, After: Redacted}}, changes,
+		"either image declaring the field sensitive redacts both sides")
+}
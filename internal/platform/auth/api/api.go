@@ -18,6 +18,7 @@ import (
 	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -98,6 +99,7 @@ const (
 	tagAnchorDomains  = "anchor-domains"
 	tagAuthConfigs    = "auth-configs"
 	tagIdpRoleMapping = "idp-role-mappings"
+	tagOAuthScopes    = "oauth-scopes"
 )
 
 // Register mounts the auth admin endpoints. Anchor-only.
@@ -106,6 +108,7 @@ func Register(api huma.API, s *State) {
 	gDomains := apiroute.New(api, tagAnchorDomains)
 	gConfigs := apiroute.New(api, tagAuthConfigs)
 	gMappings := apiroute.New(api, tagIdpRoleMapping)
+	gScopes := apiroute.New(api, tagOAuthScopes)
 
 	// OAuth clients
 	apiroute.Get(gClients, "listOAuthClients", "/api/oauth-clients", "List OAuth clients", s.listOAuthClients)
@@ -138,6 +141,12 @@ func Register(api huma.API, s *State) {
 	apiroute.Get(gMappings, "listIdpRoleMappings", "/api/idp-role-mappings", "List IDP role mappings", s.listIdpRoleMappings)
 	apiroute.Post(gMappings, "createIdpRoleMapping", "/api/idp-role-mappings", "Create an IDP role mapping", http.StatusCreated, s.createIdpRoleMapping)
 	apiroute.Delete(gMappings, "deleteIdpRoleMapping", "/api/idp-role-mappings/{id}", "Delete an IDP role mapping", http.StatusNoContent, s.deleteIdpRoleMapping)
+
+	// OAuth scope definitions (consent-screen text)
+	apiroute.Get(gScopes, "listOAuthScopes", "/api/oauth-scopes", "List OAuth scope definitions", s.listOAuthScopes)
+	apiroute.Post(gScopes, "createOAuthScope", "/api/oauth-scopes", "Define an OAuth scope", http.StatusCreated, s.createOAuthScope)
+	apiroute.Put(gScopes, "updateOAuthScope", "/api/oauth-scopes/{id}", "Update an OAuth scope definition", http.StatusNoContent, s.updateOAuthScope)
+	apiroute.Delete(gScopes, "deleteOAuthScope", "/api/oauth-scopes/{id}", "Delete an OAuth scope definition", http.StatusNoContent, s.deleteOAuthScope)
 }
 
 // ── shared helpers ────────────────────────────────────────────────────────
@@ -217,7 +226,7 @@ func (s *State) createOAuthClient(ctx context.Context, in *apicommon.In[CreateOA
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateOAuthClient(s.Repo.OAuthClients), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -251,7 +260,7 @@ func (s *State) updateOAuthClient(ctx context.Context, in *updateOAuthClientInpu
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateOAuthClient(s.Repo.OAuthClients), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -266,7 +275,7 @@ func (s *State) activateOAuthClient(ctx context.Context, in *apicommon.IDInput)
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ActivateOAuthClient(s.Repo.OAuthClients),
 		operations.ActivateOAuthClientCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
@@ -278,7 +287,7 @@ func (s *State) deactivateOAuthClient(ctx context.Context, in *apicommon.IDInput
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeactivateOAuthClient(s.Repo.OAuthClients),
 		operations.DeactivateOAuthClientCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
@@ -290,7 +299,7 @@ func (s *State) rotateOAuthClientSecret(ctx context.Context, in *apicommon.IDInp
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.RotateOAuthClientSecret(s.Repo.OAuthClients),
 		operations.RotateOAuthClientSecretCommand{ID: in.ID}, ec)
 	if err != nil {
@@ -316,7 +325,7 @@ func (s *State) deleteOAuthClient(ctx context.Context, in *apicommon.IDInput) (*
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteOAuthClient(s.Repo.OAuthClients),
 		operations.DeleteOAuthClientCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
@@ -342,7 +351,7 @@ func (s *State) createAnchorDomain(ctx context.Context, in *apicommon.In[CreateA
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateAnchorDomain(s.Repo.AnchorDomains), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -359,7 +368,7 @@ func (s *State) updateAnchorDomain(ctx context.Context, in *updateAnchorDomainIn
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateAnchorDomain(s.Repo.AnchorDomains), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -370,7 +379,7 @@ func (s *State) deleteAnchorDomain(ctx context.Context, in *apicommon.IDInput) (
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteAnchorDomain(s.Repo.AnchorDomains),
 		operations.DeleteAnchorDomainCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
@@ -401,7 +410,7 @@ func (s *State) createAuthConfig(ctx context.Context, in *apicommon.In[CreateAut
 		return nil, err
 	}
 	in.Body.OIDCClientSecretRef = secretRef
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateAuthConfig(s.Repo.ClientAuthConfigs), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -423,7 +432,7 @@ func (s *State) updateAuthConfig(ctx context.Context, in *updateAuthConfigInput)
 		return nil, err
 	}
 	in.Body.OIDCClientSecretRef = secretRef
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateAuthConfig(s.Repo.ClientAuthConfigs), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -434,7 +443,7 @@ func (s *State) deleteAuthConfig(ctx context.Context, in *apicommon.IDInput) (*a
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteAuthConfig(s.Repo.ClientAuthConfigs),
 		operations.DeleteAuthConfigCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
@@ -460,7 +469,7 @@ func (s *State) createIdpRoleMapping(ctx context.Context, in *apicommon.In[Creat
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateIdpRoleMapping(s.Repo.IdpRoleMappings), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -472,10 +481,64 @@ func (s *State) deleteIdpRoleMapping(ctx context.Context, in *apicommon.IDInput)
 	if _, err := authedAnchor(ctx); err != nil {
 		return nil, err
 	}
-	ec := platformauth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteIdpRoleMapping(s.Repo.IdpRoleMappings),
 		operations.DeleteIdpRoleMappingCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
 	return &apicommon.Empty{}, nil
 }
+
+// ── OAuthScope ────────────────────────────────────────────────────────────
+
+func (s *State) listOAuthScopes(ctx context.Context, _ *apicommon.Empty) (*apicommon.Out[OAuthScopeListResponse], error) {
+	if _, err := authedAnchor(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.OAuthScopes.FindAll(ctx)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_all failed", err)
+	}
+	out := apicommon.MapSlice(rows, oauthScopeFromEntity)
+	return &apicommon.Out[OAuthScopeListResponse]{Body: OAuthScopeListResponse{Items: out}}, nil
+}
+
+func (s *State) createOAuthScope(ctx context.Context, in *apicommon.In[CreateOAuthScopeRequest]) (*apicommon.Out[apicommon.CreatedResponse], error) {
+	if _, err := authedAnchor(ctx); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateOAuthScope(s.Repo.OAuthScopes), in.Body.toCommand(), ec)
+	if err != nil {
+		return nil, err
+	}
+	return &apicommon.Out[apicommon.CreatedResponse]{Body: apicommon.CreatedResponse{ID: event.ScopeID}}, nil
+}
+
+type updateOAuthScopeInput struct {
+	ID   string `path:"id"`
+	Body UpdateOAuthScopeRequest
+}
+
+func (s *State) updateOAuthScope(ctx context.Context, in *updateOAuthScopeInput) (*apicommon.Empty, error) {
+	if _, err := authedAnchor(ctx); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateOAuthScope(s.Repo.OAuthScopes), in.Body.toCommand(in.ID), ec); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
+
+func (s *State) deleteOAuthScope(ctx context.Context, in *apicommon.IDInput) (*apicommon.Empty, error) {
+	if _, err := authedAnchor(ctx); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteOAuthScope(s.Repo.OAuthScopes),
+		operations.DeleteOAuthScopeCommand{ID: in.ID}, ec); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
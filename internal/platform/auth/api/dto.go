@@ -2,6 +2,7 @@
 package api
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
@@ -40,6 +41,17 @@ type CreateOAuthClientRequest struct {
 	// ApplicationIDs scopes the client to specific applications (persisted).
 	ApplicationIDs []string `json:"applicationIds,omitempty"`
 	PrincipalID    *string  `json:"principalId,omitempty"`
+	// TokenEndpointAuthMethod picks how the client authenticates at
+	// /oauth/token. private_key_jwt clients register their public keys in
+	// JWKS and are not issued a secret.
+	TokenEndpointAuthMethod string          `json:"tokenEndpointAuthMethod,omitempty" enum:"client_secret_basic,client_secret_post,private_key_jwt"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty" doc:"JWK Set of the client's public signing keys (private_key_jwt)"`
+	// FirstParty marks the platform's own clients, which skip the consent
+	// screen. Defaults to true; register partner apps with false.
+	FirstParty *bool `json:"firstParty,omitempty"`
+	// BackchannelLogoutURI receives a logout token when a user who signed
+	// in to this client logs out (OIDC Back-Channel Logout).
+	BackchannelLogoutURI *string `json:"backchannelLogoutUri,omitempty" doc:"https URL notified with a logout token when a signed-in user logs out"`
 }
 
 func (r CreateOAuthClientRequest) toCommand() operations.CreateOAuthClientCommand {
@@ -60,6 +72,11 @@ func (r CreateOAuthClientRequest) toCommand() operations.CreateOAuthClientComman
 		ApplicationIDs:         r.ApplicationIDs,
 		PrincipalID:            r.PrincipalID,
 		PKCERequired:           r.PKCERequired,
+		// The method string is validated by the operation.
+		TokenEndpointAuthMethod: r.TokenEndpointAuthMethod,
+		JWKS:                    jwksString(r.JWKS),
+		FirstParty:              r.FirstParty,
+		BackchannelLogoutURI:    r.BackchannelLogoutURI,
 	}
 }
 
@@ -81,6 +98,13 @@ type UpdateOAuthClientRequest struct {
 	ApplicationIDs []string `json:"applicationIds,omitempty"`
 	// PKCERequired toggles whether /oauth/authorize demands a code_challenge.
 	PKCERequired *bool `json:"pkceRequired,omitempty"`
+	// Switching to private_key_jwt drops the client's secret.
+	TokenEndpointAuthMethod *string         `json:"tokenEndpointAuthMethod,omitempty" enum:"client_secret_basic,client_secret_post,private_key_jwt"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty" doc:"JWK Set of the client's public signing keys (private_key_jwt)"`
+	FirstParty              *bool           `json:"firstParty,omitempty"`
+	// BackchannelLogoutURI replaces the back-channel logout URI; send ""
+	// to clear it.
+	BackchannelLogoutURI *string `json:"backchannelLogoutUri,omitempty" doc:"https URL notified with a logout token when a signed-in user logs out; empty clears it"`
 }
 
 func (r UpdateOAuthClientRequest) toCommand(id string) operations.UpdateOAuthClientCommand {
@@ -89,16 +113,30 @@ func (r UpdateOAuthClientRequest) toCommand(id string) operations.UpdateOAuthCli
 		scopes = r.DefaultScopes
 	}
 	return operations.UpdateOAuthClientCommand{
-		ID:                     id,
-		ClientName:             r.ClientName,
-		RedirectURIs:           r.RedirectURIs,
-		PostLogoutRedirectURIs: r.PostLogoutRedirectURIs,
-		GrantTypes:             r.GrantTypes,
-		Scopes:                 scopes,
-		AllowedOrigins:         r.AllowedOrigins,
-		ApplicationIDs:         r.ApplicationIDs,
-		PKCERequired:           r.PKCERequired,
+		ID:                      id,
+		ClientName:              r.ClientName,
+		RedirectURIs:            r.RedirectURIs,
+		PostLogoutRedirectURIs:  r.PostLogoutRedirectURIs,
+		GrantTypes:              r.GrantTypes,
+		Scopes:                  scopes,
+		AllowedOrigins:          r.AllowedOrigins,
+		ApplicationIDs:          r.ApplicationIDs,
+		PKCERequired:            r.PKCERequired,
+		TokenEndpointAuthMethod: r.TokenEndpointAuthMethod,
+		JWKS:                    jwksString(r.JWKS),
+		FirstParty:              r.FirstParty,
+		BackchannelLogoutURI:    r.BackchannelLogoutURI,
+	}
+}
+
+// jwksString carries a JWK Set body through to the command as text; nil
+// when absent.
+func jwksString(raw json.RawMessage) *string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
 	}
+	s := string(raw)
+	return &s
 }
 
 // OAuthClientApplicationRef is the {id, name} shape the SPA's ApplicationRef
@@ -125,8 +163,13 @@ type OAuthClientResponse struct {
 	// DefaultScopes is the entity's Scopes slice (renamed for the SPA).
 	DefaultScopes []string `json:"defaultScopes"`
 	// PKCERequired mirrors the entity's pkce_required flag.
-	PKCERequired   bool     `json:"pkceRequired"`
-	ApplicationIDs []string `json:"applicationIds"`
+	PKCERequired            bool            `json:"pkceRequired"`
+	TokenEndpointAuthMethod string          `json:"tokenEndpointAuthMethod"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty" doc:"JWK Set of the client's public signing keys (private_key_jwt)"`
+	// FirstParty is false for third-party clients (consent required).
+	FirstParty           bool     `json:"firstParty"`
+	BackchannelLogoutURI *string  `json:"backchannelLogoutUri,omitempty"`
+	ApplicationIDs       []string `json:"applicationIds"`
 	// Applications is the {id, name} display form of ApplicationIDs,
 	// populated by State.fillApplicationRefs (a deleted application falls
 	// back to its id as the name). The SPA list page reads
@@ -164,6 +207,10 @@ func oauthClientFromEntity(c *auth.OAuthClient) OAuthClientResponse {
 	if appIDs == nil {
 		appIDs = []string{}
 	}
+	var jwks json.RawMessage
+	if c.JWKS != nil {
+		jwks = json.RawMessage(*c.JWKS)
+	}
 	return OAuthClientResponse{
 		ID:                        c.ID,
 		ClientID:                  c.ClientID,
@@ -175,6 +222,10 @@ func oauthClientFromEntity(c *auth.OAuthClient) OAuthClientResponse {
 		GrantTypes:                grants,
 		DefaultScopes:             scopes,
 		PKCERequired:              c.PKCERequired,
+		TokenEndpointAuthMethod:   string(c.TokenEndpointAuthMethod),
+		JWKS:                      jwks,
+		FirstParty:                c.FirstParty,
+		BackchannelLogoutURI:      c.BackchannelLogoutURI,
 		ApplicationIDs:            appIDs,
 		Applications:              []OAuthClientApplicationRef{},
 		Active:                    c.Active,
@@ -403,3 +454,49 @@ func idpRoleMappingFromEntity(m *auth.IdpRoleMapping) IdpRoleMappingResponse {
 type IdpRoleMappingListResponse struct {
 	Items []IdpRoleMappingResponse `json:"items"`
 }
+
+// ── OAuthScope ────────────────────────────────────────────────────────────
+
+// CreateOAuthScopeRequest is the wire body for POST /api/oauth-scopes.
+type CreateOAuthScopeRequest struct {
+	Name        string `json:"name" doc:"Scope token, e.g. orders:read"`
+	Description string `json:"description" doc:"Text shown on the consent screen"`
+}
+
+func (r CreateOAuthScopeRequest) toCommand() operations.CreateOAuthScopeCommand {
+	return operations.CreateOAuthScopeCommand{Name: r.Name, Description: r.Description}
+}
+
+// UpdateOAuthScopeRequest is the wire body for PUT /api/oauth-scopes/{id}.
+// The name is immutable.
+type UpdateOAuthScopeRequest struct {
+	Description string `json:"description"`
+}
+
+func (r UpdateOAuthScopeRequest) toCommand(id string) operations.UpdateOAuthScopeCommand {
+	return operations.UpdateOAuthScopeCommand{ID: id, Description: r.Description}
+}
+
+// OAuthScopeResponse mirrors auth.OAuthScope.
+type OAuthScopeResponse struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	CreatedAt   httpcompat.Time `json:"createdAt"`
+	UpdatedAt   httpcompat.Time `json:"updatedAt"`
+}
+
+func oauthScopeFromEntity(s *auth.OAuthScope) OAuthScopeResponse {
+	return OAuthScopeResponse{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+		CreatedAt:   jsontime.New(s.CreatedAt),
+		UpdatedAt:   jsontime.New(s.UpdatedAt),
+	}
+}
+
+// OAuthScopeListResponse is the wire shape for GET /api/oauth-scopes.
+type OAuthScopeListResponse struct {
+	Items []OAuthScopeResponse `json:"items"`
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/signingkey"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
@@ -99,6 +100,10 @@ type AccessTokenClaims struct {
 	// the application-axis analogue of the anchor tier. When true the
 	// Applications list is not a restriction.
 	AllApplications bool `json:"all_applications"`
+
+	// Environment pins a service account token to its SANDBOX or
+	// PRODUCTION environment. Omitted for unpinned tokens.
+	Environment string `json:"env,omitempty"`
 }
 
 // IDTokenClaims is the JWT payload for OIDC ID tokens. As in Rust, it
@@ -136,6 +141,27 @@ type IDTokenClaims struct {
 	Clients         []string `json:"clients"`
 }
 
+// BackchannelLogoutEvent is the events member that marks a JWT as an OIDC
+// Back-Channel Logout token.
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenClaims is the OIDC Back-Channel Logout 1.0 §2.4 logout token.
+// It names the principal (sub) being logged out of the client (aud); it
+// never carries a nonce, so it can't be replayed as an ID token.
+type LogoutTokenClaims struct {
+	jwt.RegisteredClaims
+
+	// Aud is the receiving client_id (bare string).
+	Aud string `json:"aud"`
+
+	// Events holds the single BackchannelLogoutEvent member.
+	Events map[string]struct{} `json:"events"`
+}
+
+// logoutTokenTTL bounds a logout token's exp; it is delivered as soon as
+// it is minted.
+const logoutTokenTTL = 2 * time.Minute
+
 // Config bundles the construction-time settings, mirroring Rust's
 // AuthConfig. TTLs are in seconds.
 type Config struct {
@@ -225,6 +251,10 @@ type AuthService struct {
 	rsaComponents *RsaPublicKeyComponents
 
 	previousKeys []keyEntry
+
+	// ring, when set (RS256 only), supersedes the static keys above for
+	// signing, validation and JWKS — see SetKeyRing.
+	ring *signingkey.Ring
 }
 
 // NewWithRSA builds an RS256 service from PEM key material.
@@ -327,14 +357,34 @@ func (s *AuthService) AddPreviousRSAKey(publicKeyPEM string) error {
 	return nil
 }
 
+// SetKeyRing moves an RS256 service onto a rotating key ring: tokens are
+// signed by the ring's current signer and verified against every key it
+// holds, and the JWKS publishes them all. Set once at startup, before the
+// service issues tokens. Ignored for HS256.
+func (s *AuthService) SetKeyRing(r *signingkey.Ring) {
+	if s.algorithm == "RS256" {
+		s.ring = r
+	}
+}
+
 // KeyID returns the current key id, or "" for HS256.
-func (s *AuthService) KeyID() string { return s.keyID }
+func (s *AuthService) KeyID() string {
+	if s.ring != nil {
+		return s.ring.Signer().ID
+	}
+	return s.keyID
+}
 
 // Algorithm returns "RS256" or "HS256".
 func (s *AuthService) Algorithm() string { return s.algorithm }
 
 // RSAComponents returns the current key's JWKS components, or nil for HS256.
-func (s *AuthService) RSAComponents() *RsaPublicKeyComponents { return s.rsaComponents }
+func (s *AuthService) RSAComponents() *RsaPublicKeyComponents {
+	if s.ring != nil {
+		return rsaComponentsOf(s.ring.Signer().Public)
+	}
+	return s.rsaComponents
+}
 
 // JWKSKey pairs a key id with its RSA components for the JWKS endpoint.
 type JWKSKey struct {
@@ -346,6 +396,12 @@ type JWKSKey struct {
 // Empty for HS256.
 func (s *AuthService) AllJWKSKeys() []JWKSKey {
 	var keys []JWKSKey
+	if s.ring != nil {
+		for _, k := range s.ring.Keys() {
+			keys = append(keys, JWKSKey{KeyID: k.ID, Components: *rsaComponentsOf(k.Public)})
+		}
+		return keys
+	}
 	if s.keyID != "" && s.rsaComponents != nil {
 		keys = append(keys, JWKSKey{KeyID: s.keyID, Components: *s.rsaComponents})
 	}
@@ -359,7 +415,7 @@ func (s *AuthService) AllJWKSKeys() []JWKSKey {
 
 // GenerateAccessToken mints a short-lived access token for API calls.
 func (s *AuthService) GenerateAccessToken(p *principal.Principal) (string, error) {
-	return s.generateTokenWithExpiry(p, s.config.AccessTokenExpirySecs, nil)
+	return s.generateTokenWithExpiry(p, s.config.AccessTokenExpirySecs, nil, "")
 }
 
 // GenerateAccessTokenWithScope mints a short-lived access token whose "scope"
@@ -368,15 +424,22 @@ func (s *AuthService) GenerateAccessToken(p *principal.Principal) (string, error
 // with any requested scope; passing nil/empty is equivalent to
 // GenerateAccessToken (no scope claim).
 func (s *AuthService) GenerateAccessTokenWithScope(p *principal.Principal, scope []string) (string, error) {
-	return s.generateTokenWithExpiry(p, s.config.AccessTokenExpirySecs, scope)
+	return s.generateTokenWithExpiry(p, s.config.AccessTokenExpirySecs, scope, "")
+}
+
+// GenerateAccessTokenInEnvironment is GenerateAccessTokenWithScope with an
+// "env" claim pinning the token to a service account's SANDBOX or
+// PRODUCTION environment. An empty env mints an unpinned token.
+func (s *AuthService) GenerateAccessTokenInEnvironment(p *principal.Principal, scope []string, env string) (string, error) {
+	return s.generateTokenWithExpiry(p, s.config.AccessTokenExpirySecs, scope, env)
 }
 
 // GenerateSessionToken mints a longer-lived token for cookie sessions.
 func (s *AuthService) GenerateSessionToken(p *principal.Principal) (string, error) {
-	return s.generateTokenWithExpiry(p, s.config.SessionTokenExpirySecs, nil)
+	return s.generateTokenWithExpiry(p, s.config.SessionTokenExpirySecs, nil, "")
 }
 
-func (s *AuthService) generateTokenWithExpiry(p *principal.Principal, expirySecs int64, scope []string) (string, error) {
+func (s *AuthService) generateTokenWithExpiry(p *principal.Principal, expirySecs int64, scope []string, env string) (string, error) {
 	now := time.Now().UTC()
 	exp := now.Add(time.Duration(expirySecs) * time.Second)
 
@@ -399,6 +462,7 @@ func (s *AuthService) generateTokenWithExpiry(p *principal.Principal, expirySecs
 		Roles:           roleNames(p),
 		Applications:    appAccessOf(p),
 		AllApplications: p.AllApplications,
+		Environment:     env,
 	}
 	return s.sign(claims)
 }
@@ -462,14 +526,45 @@ func (s *AuthService) generateIDToken(p *principal.Principal, clientID string, n
 	return s.sign(claims)
 }
 
+// GenerateLogoutToken mints a Back-Channel Logout token telling clientID
+// that subject's sessions have ended.
+func (s *AuthService) GenerateLogoutToken(subject, clientID string) (string, error) {
+	now := time.Now().UTC()
+	claims := LogoutTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.config.Issuer,
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(now.Add(logoutTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        tsid.GenerateUntyped(),
+		},
+		Aud:    clientID,
+		Events: map[string]struct{}{BackchannelLogoutEvent: {}},
+	}
+	return s.signTyped(claims, "logout+jwt")
+}
+
 // sign serializes and signs the supplied claims with the current key,
 // stamping the kid header when using RS256.
 func (s *AuthService) sign(claims jwt.Claims) (string, error) {
+	return s.signTyped(claims, "")
+}
+
+// signTyped is sign with an explicit typ header (empty keeps "JWT").
+func (s *AuthService) signTyped(claims jwt.Claims, typ string) (string, error) {
 	tok := jwt.NewWithClaims(s.signingMethod, claims)
-	if s.keyID != "" {
+	if typ != "" {
+		tok.Header["typ"] = typ
+	}
+	signKey := s.signKey
+	if s.ring != nil {
+		k := s.ring.Signer()
+		tok.Header["kid"] = k.ID
+		signKey = k.Private
+	} else if s.keyID != "" {
 		tok.Header["kid"] = s.keyID
 	}
-	signed, err := tok.SignedString(s.signKey)
+	signed, err := tok.SignedString(signKey)
 	if err != nil {
 		return "", fmt.Errorf("encode JWT: %w", err)
 	}
@@ -478,18 +573,12 @@ func (s *AuthService) sign(claims jwt.Claims) (string, error) {
 
 // ValidateToken verifies an access token's signature, issuer, audience,
 // and expiry, trying the current key first then previous keys (rotation).
+// With a key ring the token's kid selects the key instead.
 func (s *AuthService) ValidateToken(token string) (*AccessTokenClaims, error) {
-	verifyKeys := make([]any, 0, 1+len(s.previousKeys))
-	verifyKeys = append(verifyKeys, s.currentVerify)
-	for _, k := range s.previousKeys {
-		verifyKeys = append(verifyKeys, k.verifyKey)
-	}
-
 	var lastErr error
-	for _, vk := range verifyKeys {
+	for _, kf := range s.keyFuncs() {
 		claims := &AccessTokenClaims{}
-		_, err := jwt.ParseWithClaims(token, claims,
-			func(*jwt.Token) (any, error) { return vk, nil },
+		_, err := jwt.ParseWithClaims(token, claims, kf,
 			jwt.WithValidMethods([]string{s.algorithm}),
 			jwt.WithIssuer(s.config.Issuer),
 		)
@@ -510,6 +599,56 @@ func (s *AuthService) ValidateToken(token string) (*AccessTokenClaims, error) {
 	return nil, fmt.Errorf("%w: %v", ErrInvalidToken, lastErr)
 }
 
+// ValidateIDTokenHint verifies an id_token_hint's signature and issuer
+// and returns its claims. Expiry is deliberately not checked: RP-Initiated
+// Logout (§2) expects an OP to accept a hint for a session whose ID token
+// has already expired.
+func (s *AuthService) ValidateIDTokenHint(token string) (*IDTokenClaims, error) {
+	var lastErr error
+	for _, kf := range s.keyFuncs() {
+		claims := &IDTokenClaims{}
+		_, err := jwt.ParseWithClaims(token, claims, kf,
+			jwt.WithValidMethods([]string{s.algorithm}),
+			jwt.WithoutClaimsValidation(),
+		)
+		if err == nil {
+			// Claims validation is off, so the issuer is checked here.
+			if claims.Issuer != s.config.Issuer {
+				return nil, fmt.Errorf("%w: issuer mismatch", ErrInvalidToken)
+			}
+			return claims, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%w: %v", ErrInvalidToken, lastErr)
+}
+
+// keyFuncs lists the verification keys to try, current first.
+func (s *AuthService) keyFuncs() []jwt.Keyfunc {
+	if s.ring != nil {
+		return []jwt.Keyfunc{s.ringKey}
+	}
+	keyFuncs := []jwt.Keyfunc{staticKey(s.currentVerify)}
+	for _, k := range s.previousKeys {
+		keyFuncs = append(keyFuncs, staticKey(k.verifyKey))
+	}
+	return keyFuncs
+}
+
+func staticKey(k any) jwt.Keyfunc {
+	return func(*jwt.Token) (any, error) { return k, nil }
+}
+
+// ringKey resolves the verification key(s) for a token from its kid.
+func (s *AuthService) ringKey(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	set := jwt.VerificationKeySet{}
+	for _, pub := range s.ring.Candidates(kid) {
+		set.Keys = append(set.Keys, pub)
+	}
+	return set, nil
+}
+
 // HasClientAccess reports whether the claims grant access to clientID,
 // handling both plain ids and "id:identifier" pairs and the "*" wildcard.
 func (s *AuthService) HasClientAccess(claims *AccessTokenClaims, clientID string) bool {
@@ -616,10 +755,14 @@ func extractRSAComponents(publicKeyPEM string) (*RsaPublicKeyComponents, error)
 	if err != nil {
 		return nil, err
 	}
+	return rsaComponentsOf(pub), nil
+}
+
+func rsaComponentsOf(pub *rsa.PublicKey) *RsaPublicKeyComponents {
 	return &RsaPublicKeyComponents{
 		N: base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
 		E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
-	}, nil
+	}
 }
 
 func parseRSAPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
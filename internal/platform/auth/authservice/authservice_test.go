@@ -8,9 +8,13 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/signingkey"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 )
@@ -247,6 +251,61 @@ func TestKeyRotationValidatesPreviousKey(t *testing.T) {
 	}
 }
 
+func TestKeyRingSignsWithCurrentKeyAndValidatesRetired(t *testing.T) {
+	oldPriv, oldPub := genRSAPEMs(t)
+	newPriv, _ := genRSAPEMs(t)
+	parse := func(p string) signingkey.Key {
+		k, err := signingkey.ParsePrivateKeyPEM([]byte(p))
+		if err != nil {
+			t.Fatalf("parse key: %v", err)
+		}
+		return signingkey.NewKey(k)
+	}
+	oldKey, newKey := parse(oldPriv), parse(newPriv)
+
+	cfg := DefaultConfig()
+	cfg.RSAPrivateKeyPEM = oldPriv
+	svc, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if oldKey.ID != generateKeyID(oldPub) {
+		t.Fatalf("ring kid %q differs from the static kid %q", oldKey.ID, generateKeyID(oldPub))
+	}
+	ring := signingkey.NewRing(oldKey)
+	svc.SetKeyRing(ring)
+	oldToken, err := svc.GenerateAccessToken(anchorUser())
+	if err != nil {
+		t.Fatalf("generate old token: %v", err)
+	}
+
+	ring.Replace(newKey, []signingkey.Key{oldKey})
+	newToken, err := svc.GenerateAccessToken(anchorUser())
+	if err != nil {
+		t.Fatalf("generate new token: %v", err)
+	}
+	tok, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("parse header: %v", err)
+	}
+	if tok.Header["kid"] != newKey.ID {
+		t.Errorf("kid = %v, want the ring signer %q", tok.Header["kid"], newKey.ID)
+	}
+	for name, token := range map[string]string{"old": oldToken, "new": newToken} {
+		if _, err := svc.ValidateToken(token); err != nil {
+			t.Errorf("%s token should validate: %v", name, err)
+		}
+	}
+	if got := len(svc.AllJWKSKeys()); got != 2 {
+		t.Errorf("JWKS should publish both ring keys, got %d", got)
+	}
+
+	ring.Replace(newKey, nil)
+	if _, err := svc.ValidateToken(oldToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token signed by a dropped key should be invalid, got %v", err)
+	}
+}
+
 func TestKeyIDIsDeterministicSHA256Prefix(t *testing.T) {
 	_, pub := genRSAPEMs(t)
 	want := independentKeyID(pub)
@@ -336,6 +395,58 @@ func TestIDTokenExpiryIsFiveMinutesIndependentOfAccessToken(t *testing.T) {
 	}
 }
 
+func TestLogoutTokenShape(t *testing.T) {
+	svc := newRS256(t)
+	tok, err := svc.GenerateLogoutToken("prn_1", "clt_rp")
+	if err != nil {
+		t.Fatalf("generate logout token: %v", err)
+	}
+	payload := decodeJWTPayload(t, tok)
+	if payload["sub"] != "prn_1" || payload["aud"] != "clt_rp" {
+		t.Errorf("sub/aud = %v/%v", payload["sub"], payload["aud"])
+	}
+	if _, ok := payload["jti"]; !ok {
+		t.Error("logout token must carry jti")
+	}
+	if _, ok := payload["nonce"]; ok {
+		t.Error("logout token must not carry nonce")
+	}
+	events, _ := payload["events"].(map[string]any)
+	if _, ok := events[BackchannelLogoutEvent]; !ok {
+		t.Errorf("events = %v, want the back-channel logout event", payload["events"])
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(tok, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["typ"] != "logout+jwt" {
+		t.Errorf("typ = %v, want logout+jwt", parsed.Header["typ"])
+	}
+}
+
+func TestValidateIDTokenHintAcceptsExpiredButNotForeignTokens(t *testing.T) {
+	svc := newRS256(t)
+	svc.config.IDTokenExpirySecs = -60
+	user := anchorUser()
+	hint, err := svc.GenerateIDToken(user, "clt_rp", nil)
+	if err != nil {
+		t.Fatalf("generate id token: %v", err)
+	}
+	claims, err := svc.ValidateIDTokenHint(hint)
+	if err != nil {
+		t.Fatalf("expired hint rejected: %v", err)
+	}
+	if claims.Aud != "clt_rp" || claims.Subject != user.ID {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	other := newRS256(t)
+	foreign, _ := other.GenerateIDToken(user, "clt_rp", nil)
+	if _, err := svc.ValidateIDTokenHint(foreign); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("hint signed by another key: err = %v, want ErrInvalidToken", err)
+	}
+}
+
 // ─── helpers ────────────────────────────────────────────────────────────
 
 func numericClaim(t *testing.T, payload map[string]any, key string) int64 {
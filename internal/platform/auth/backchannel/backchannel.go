@@ -0,0 +1,142 @@
+// Package backchannel delivers OIDC Back-Channel Logout 1.0
+// notifications. When a principal logs out — of the console, through
+// /oauth/logout, or by an admin revoking their sessions — every OAuth
+// client they signed in to that registered a backchannel_logout_uri is
+// POSTed a signed logout token, so the integrated app can end its own
+// session for them.
+//
+// Which clients a principal signed in to is tracked in
+// oauth_client_logins (grantstore.ClientLoginRepository); taking the list
+// clears it, so a sign-in is logged out at most once. Delivery is
+// best-effort and off the request path: one attempt per client with a
+// short timeout, failures logged.
+package backchannel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+)
+
+// deliveryTimeout bounds one notification, connection included.
+const deliveryTimeout = 5 * time.Second
+
+// ClientFinder resolves a client's back-channel logout URI. Satisfied by
+// *auth.OAuthClientRepo.
+type ClientFinder interface {
+	FindByClientID(ctx context.Context, clientID string) (*auth.OAuthClient, error)
+}
+
+// LoginStore tracks client sign-ins. Satisfied by
+// *grantstore.ClientLoginRepository.
+type LoginStore interface {
+	Record(ctx context.Context, principalID, clientID string) error
+	TakeAll(ctx context.Context, principalID string) ([]string, error)
+}
+
+// TokenSigner mints logout tokens. Satisfied by *authservice.AuthService.
+type TokenSigner interface {
+	GenerateLogoutToken(subject, clientID string) (string, error)
+}
+
+// Notifier sends logout tokens.
+type Notifier struct {
+	clients ClientFinder
+	logins  LoginStore
+	tokens  TokenSigner
+	client  *http.Client
+
+	// wg tracks in-flight deliveries (Wait, for tests and shutdown).
+	wg sync.WaitGroup
+}
+
+// New builds a Notifier.
+func New(clients ClientFinder, logins LoginStore, tokens TokenSigner) *Notifier {
+	return &Notifier{
+		clients: clients,
+		logins:  logins,
+		tokens:  tokens,
+		client:  &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// SetEgress routes notifications through the outbound policy, as for
+// webhook deliveries: a logout URI is admin-supplied and must not reach
+// internal addresses the policy blocks.
+func (n *Notifier) SetEgress(p *egress.Policy) { n.client.Transport = p.Transport(nil) }
+
+// RecordLogin notes that principalID signed in to clientID. Best-effort:
+// a failure only means that client won't be notified on logout.
+func (n *Notifier) RecordLogin(ctx context.Context, principalID, clientID string) {
+	if err := n.logins.Record(ctx, principalID, clientID); err != nil {
+		slog.Warn("recording oauth client login failed", "principal", principalID, "client_id", clientID, "err", err)
+	}
+}
+
+// LoggedOut notifies every client principalID signed in to that has a
+// back-channel logout URI. It returns once the sign-ins are claimed;
+// delivery continues in the background, detached from ctx's
+// cancellation.
+func (n *Notifier) LoggedOut(ctx context.Context, principalID string) {
+	clientIDs, err := n.logins.TakeAll(ctx, principalID)
+	if err != nil {
+		slog.Warn("loading oauth client logins for back-channel logout failed", "principal", principalID, "err", err)
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, clientID := range clientIDs {
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			if err := n.notify(ctx, principalID, clientID); err != nil {
+				slog.Warn("back-channel logout failed", "principal", principalID, "client_id", clientID, "err", err)
+			}
+		}()
+	}
+}
+
+// Wait blocks until in-flight deliveries finish.
+func (n *Notifier) Wait() { n.wg.Wait() }
+
+// notify delivers one logout token (§2.5): a form POST of logout_token,
+// answered 200 or 204 by a client that processed it.
+func (n *Notifier) notify(ctx context.Context, principalID, clientID string) error {
+	client, err := n.clients.FindByClientID(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("load client: %w", err)
+	}
+	if client == nil || !client.Active || client.BackchannelLogoutURI == nil {
+		return nil
+	}
+	token, err := n.tokens.GenerateLogoutToken(principalID, clientID)
+	if err != nil {
+		return fmt.Errorf("mint logout token: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	body := url.Values{"logout_token": {token}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *client.BackchannelLogoutURI, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("client answered %d", resp.StatusCode)
+	}
+	return nil
+}
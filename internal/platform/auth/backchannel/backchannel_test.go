@@ -0,0 +1,94 @@
+package backchannel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+)
+
+type fakeClients map[string]*auth.OAuthClient
+
+func (f fakeClients) FindByClientID(_ context.Context, clientID string) (*auth.OAuthClient, error) {
+	return f[clientID], nil
+}
+
+// fakeLogins is an in-memory LoginStore.
+type fakeLogins struct {
+	mu   sync.Mutex
+	byID map[string][]string
+}
+
+func (f *fakeLogins) Record(_ context.Context, principalID, clientID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.byID[principalID] = append(f.byID[principalID], clientID)
+	return nil
+}
+
+func (f *fakeLogins) TakeAll(_ context.Context, principalID string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := f.byID[principalID]
+	delete(f.byID, principalID)
+	return out, nil
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) GenerateLogoutToken(subject, clientID string) (string, error) {
+	return "logout:" + subject + ":" + clientID, nil
+}
+
+func TestLoggedOut_NotifiesSignedInClientsOnce(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		mu.Lock()
+		got = append(got, r.PostFormValue("logout_token"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	uri := srv.URL + "/logout"
+	clients := fakeClients{
+		"rp":       {ClientID: "rp", Active: true, BackchannelLogoutURI: &uri},
+		"no-uri":   {ClientID: "no-uri", Active: true},
+		"inactive": {ClientID: "inactive", BackchannelLogoutURI: &uri},
+	}
+	logins := &fakeLogins{byID: map[string][]string{}}
+	n := New(clients, logins, fakeSigner{})
+	for _, c := range []string{"rp", "no-uri", "inactive", "deleted"} {
+		n.RecordLogin(context.Background(), "prn_1", c)
+	}
+
+	n.LoggedOut(context.Background(), "prn_1")
+	n.Wait()
+	n.LoggedOut(context.Background(), "prn_1")
+	n.Wait()
+
+	if len(got) != 1 || got[0] != "logout:prn_1:rp" {
+		t.Fatalf("notifications = %v, want one logout token for rp", got)
+	}
+}
+
+func TestNotify_ReportsRejection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	uri := srv.URL
+	n := New(fakeClients{"rp": {ClientID: "rp", Active: true, BackchannelLogoutURI: &uri}},
+		&fakeLogins{byID: map[string][]string{}}, fakeSigner{})
+	if err := n.notify(context.Background(), "prn_1", "rp"); err == nil {
+		t.Fatal("a 400 from the client was treated as delivered")
+	}
+}
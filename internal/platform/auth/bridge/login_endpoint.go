@@ -17,6 +17,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/oauthapi"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping"
+	idpadapter "github.com/flowcatalyst/flowcatalyst-go/internal/platform/idp"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	principalops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
@@ -377,11 +378,8 @@ func (e *LoginEndpoint) handleCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var claims struct {
-		Email             string   `json:"email"`
-		PreferredUsername string   `json:"preferred_username"`
-		Tid               string   `json:"tid"`
-		Nonce             string   `json:"nonce"`
-		Roles             []string `json:"roles"`
+		idpadapter.Claims
+		Nonce string `json:"nonce"`
 	}
 	if err := idToken.Claims(&claims); err != nil {
 		httperror.Write(w, httperror.BadRequest("OIDC_CLAIMS", "id_token claims malformed"))
@@ -432,6 +430,14 @@ func (e *LoginEndpoint) handleCallback(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Provider-specific trust rules on top of the above (e.g. the Google
+	// Workspace hosted-domain check).
+	adapter := resolved.Adapter()
+	if err := adapter.CheckClaims(&claims.Claims, loginState.EmailDomain); err != nil {
+		httperror.Write(w, usecase.Authorization("IDP_CLAIMS_REJECTED", err.Error()))
+		return
+	}
+
 	// Resolve or create the FlowCatalyst principal. Drop-in parity with
 	// Rust's sync_oidc_login_with_allowed_roles: lookup by email; if
 	// missing, auto-provision using the scope + primary-client-id from
@@ -457,7 +463,14 @@ func (e *LoginEndpoint) handleCallback(w http.ResponseWriter, r *http.Request) {
 		slog.Warn("OIDC login: email lowercase self-heal failed; continuing",
 			"principalId", p.ID, "err", herr)
 	}
-	if err := e.syncIdpRoles(r.Context(), p, claims.Roles, loginState.EmailDomainMappingID); err != nil {
+	roleNames, err := adapter.RoleNames(r.Context(), &claims.Claims, tok)
+	if err != nil {
+		// The IdP's group API failed; we don't know the user's groups, so
+		// leave their IDP-sourced roles as they are rather than dropping
+		// them all.
+		slog.Warn("OIDC role lookup failed; skipping role sync",
+			"principalId", p.ID, "idpType", adapter.Name(), "err", err)
+	} else if err := e.syncIdpRoles(r.Context(), p, roleNames, loginState.EmailDomainMappingID, adapter); err != nil {
 		// Role sync failure shouldn't block login — the principal is
 		// already valid. Log and continue with whatever role set is in
 		// place. Mirrors Rust's behaviour where the role sync is a
@@ -572,8 +585,8 @@ func (e *LoginEndpoint) autoProvision(ctx context.Context, email, mappingID stri
 	return created, nil
 }
 
-// syncIdpRoles translates the IDP `roles` claim through
-// oauth_idp_role_mappings, filters by the EmailDomainMapping's
+// syncIdpRoles translates the adapter's IDP role names through the
+// oauth_idp_role_mappings that apply to it (see idp.MappingApplies), filters by the EmailDomainMapping's
 // allowed_role_ids (when non-empty), and applies the resulting
 // platform-role set with source=IDP_SYNC. Preserves admin-assigned
 // roles untouched.
@@ -582,7 +595,7 @@ func (e *LoginEndpoint) autoProvision(ctx context.Context, email, mappingID stri
 // upstream, so all their IDP-sourced platform roles should drop. The
 // caller treats any error here as non-fatal — the principal is
 // already authenticated; we just log and continue.
-func (e *LoginEndpoint) syncIdpRoles(ctx context.Context, p *principal.Principal, idpRoles []string, mappingID string) error {
+func (e *LoginEndpoint) syncIdpRoles(ctx context.Context, p *principal.Principal, idpRoles []string, mappingID string, adapter idpadapter.Provider) error {
 	mapping, err := e.mappings.FindByID(ctx, mappingID)
 	if err != nil {
 		return usecase.Internal("REPO", "email_domain_mapping lookup failed", err)
@@ -594,15 +607,19 @@ func (e *LoginEndpoint) syncIdpRoles(ctx context.Context, p *principal.Principal
 		return e.applySyncIdpRoles(ctx, p, nil)
 	}
 
-	// Load every IDP role mapping; in-memory filter. Mirrors Rust's
-	// find_idp_role_mapping which doesn't filter by IDP type either.
+	// Load every IDP role mapping; in-memory filter. Rust's
+	// find_idp_role_mapping ignores the IDP type; here a typed mapping
+	// only applies to logins through an IdP of that type, so the same
+	// group name can map differently per IdP.
 	allMappings, err := e.idpMappings.FindAll(ctx)
 	if err != nil {
 		return usecase.Internal("REPO", "idp_role_mappings list failed", err)
 	}
 	byIdpRoleName := make(map[string]string, len(allMappings))
 	for _, m := range allMappings {
-		byIdpRoleName[m.IdpRoleName] = m.PlatformRoleName
+		if idpadapter.MappingApplies(adapter, m.IdpType) {
+			byIdpRoleName[m.IdpRoleName] = m.PlatformRoleName
+		}
 	}
 
 	allowed := mapping.AllowedRoleIDs
@@ -25,6 +25,7 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
+	idpadapter "github.com/flowcatalyst/flowcatalyst-go/internal/platform/idp"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 )
 
@@ -56,6 +57,10 @@ type resolved struct {
 	clientID      string
 	multiTenant   bool
 	issuerPattern *string
+
+	// adapter is the IdpType-specific behaviour: extra scopes, claim
+	// checks and where role names come from.
+	adapter idpadapter.Provider
 }
 
 // NewBridge wires the bridge. enc may be nil — confidential OIDC clients
@@ -96,7 +101,9 @@ func (b *Bridge) ResolveForEmail(ctx context.Context, email string) (*resolved,
 		return nil, idp, mapping, errors.New("OIDC config missing issuer or client ID")
 	}
 
-	key := *idp.OIDCIssuerURL + "|" + *idp.OIDCClientID
+	// The IdpType is part of the key: changing it changes the requested
+	// scopes, so a cached client for the old type must not be reused.
+	key := *idp.OIDCIssuerURL + "|" + *idp.OIDCClientID + "|" + string(idp.IdpType)
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	if r, ok := b.cache[key]; ok {
@@ -124,7 +131,9 @@ func (b *Bridge) ResolveForEmail(ctx context.Context, email string) (*resolved,
 	if err != nil {
 		return nil, idp, mapping, err
 	}
+	adapter := idpadapter.Resolve(idp)
 	r := &resolved{
+		adapter:       adapter,
 		provider:      provider,
 		verifier:      provider.Verifier(verifierCfg),
 		issuerURL:     *idp.OIDCIssuerURL,
@@ -135,13 +144,16 @@ func (b *Bridge) ResolveForEmail(ctx context.Context, email string) (*resolved,
 			ClientID:     *idp.OIDCClientID,
 			ClientSecret: clientSecret,
 			Endpoint:     provider.Endpoint(),
-			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+			Scopes:       append([]string{oidc.ScopeOpenID, "profile", "email"}, adapter.Scopes()...),
 		},
 	}
 	b.cache[key] = r
 	return r, idp, mapping, nil
 }
 
+// Adapter returns the IdpType-specific login adapter.
+func (r *resolved) Adapter() idpadapter.Provider { return r.adapter }
+
 // resolveClientSecret decrypts the IdP's OIDCClientSecretRef using the
 // configured encryption service. Empty ref → no secret (public client).
 // If a ref is present but no encryption service is configured, or
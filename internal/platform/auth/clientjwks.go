@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// ValidateClientJWKS checks a private_key_jwt client's JWK Set: valid
+// JSON, at least one key, and only public RSA, EC or OKP keys — a shared
+// (oct) key would let anyone holding it sign, and a private key doesn't
+// belong on the server.
+func ValidateClientJWKS(raw string) error {
+	set, err := jwk.Parse([]byte(raw))
+	if err != nil {
+		return fmt.Errorf("not a JWK Set: %w", err)
+	}
+	if set.Len() == 0 {
+		return errors.New("JWK Set has no keys")
+	}
+	for i := 0; i < set.Len(); i++ {
+		k, _ := set.Key(i)
+		// Private key types also satisfy the public interfaces, so they
+		// are matched first.
+		switch k.(type) {
+		case jwk.RSAPrivateKey, jwk.ECDSAPrivateKey, jwk.OKPPrivateKey:
+			return fmt.Errorf("key %d is a private key; register the public key only", i)
+		case jwk.RSAPublicKey, jwk.ECDSAPublicKey, jwk.OKPPublicKey:
+		default:
+			return fmt.Errorf("key %d has unsupported type %s; use RSA, EC or OKP", i, k.KeyType())
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -114,7 +115,11 @@ func (s *State) appendGrants(r *http.Request, ids []string, principalID string)
 	for _, id := range ids {
 		seen[id] = true
 	}
+	now := time.Now()
 	for i := range grants {
+		if grants[i].ExpiredAt(now) {
+			continue
+		}
 		if !seen[grants[i].ClientID] {
 			ids = append(ids, grants[i].ClientID)
 			seen[grants[i].ClientID] = true
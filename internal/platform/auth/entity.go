@@ -4,6 +4,7 @@
 //   - AnchorDomain (email domains that grant anchor scope on signup)
 //   - ClientAuthConfig (per-tenant auth configuration with optional IDP)
 //   - IdpRoleMapping (external IDP role name → platform role name)
+//   - OAuthScope (consent-screen text for a scope)
 //
 // Plus runtime adapters for:
 //
@@ -41,6 +42,27 @@ func ParseOAuthClientType(s string) OAuthClientType {
 	return OAuthClientPublic
 }
 
+// OAuthClientAuthMethod is how a CONFIDENTIAL client authenticates at
+// /oauth/token (OIDC token_endpoint_auth_method).
+type OAuthClientAuthMethod string
+
+const (
+	// AuthMethodClientSecret accepts the client secret in a Basic header
+	// (client_secret_basic) or the form body (client_secret_post).
+	AuthMethodClientSecret OAuthClientAuthMethod = "client_secret_basic"
+	// AuthMethodPrivateKeyJWT accepts only an RFC 7523 client assertion
+	// signed by a key in the client's JWKS.
+	AuthMethodPrivateKeyJWT OAuthClientAuthMethod = "private_key_jwt"
+)
+
+// ParseOAuthClientAuthMethod — lenient parser. Unknown → client secret.
+func ParseOAuthClientAuthMethod(s string) OAuthClientAuthMethod {
+	if s == string(AuthMethodPrivateKeyJWT) {
+		return AuthMethodPrivateKeyJWT
+	}
+	return AuthMethodClientSecret
+}
+
 // OAuthClient is a registered client of the OAuth provider.
 // Maps to oauth_clients.
 type OAuthClient struct {
@@ -48,18 +70,30 @@ type OAuthClient struct {
 	ClientID   string          `json:"clientId"`
 	ClientName string          `json:"clientName"`
 	ClientType OAuthClientType `json:"clientType"`
-	// SecretRef stores the client_secret for CONFIDENTIAL clients as a
-	// reversibly-encrypted blob (AES-GCM via the encryption package),
-	// matching Rust's oauth_clients.client_secret_ref. Verification
-	// decrypts and compares; nil for PUBLIC. Set via rotate-secret.
-	SecretRef    *string  `json:"-"`
+	// SecretRef stores the client_secret for CONFIDENTIAL clients as an
+	// argon2id PHC hash (passwordhash). Rows minted before hashing hold a
+	// reversibly-encrypted blob instead, verified by decrypt-and-compare
+	// until the next rotation. nil for PUBLIC. Set via rotate-secret.
+	SecretRef *string `json:"-"`
+	// TokenEndpointAuthMethod is how the client authenticates at
+	// /oauth/token. Maps to oauth_clients.token_endpoint_auth_method
+	// (NULL = client secret).
+	TokenEndpointAuthMethod OAuthClientAuthMethod `json:"tokenEndpointAuthMethod"`
+	// JWKS is the public JWK Set a private_key_jwt client signs its
+	// assertions with (oauth_clients.jwks).
+	JWKS         *string  `json:"jwks,omitempty"`
 	RedirectURIs []string `json:"redirectUris"`
 	// PostLogoutRedirectURIs is the OIDC RP-Initiated Logout whitelist
-	// (oauth_client_post_logout_redirect_uris). /auth/oidc/session/end
-	// validates a supplied post_logout_redirect_uri against this list.
+	// (oauth_client_post_logout_redirect_uris). /oauth/logout validates a
+	// supplied post_logout_redirect_uri against this list.
 	PostLogoutRedirectURIs []string `json:"postLogoutRedirectUris"`
-	GrantTypes             []string `json:"grantTypes"` // "authorization_code", "client_credentials", "refresh_token"
-	Scopes                 []string `json:"scopes"`
+	// BackchannelLogoutURI receives an OIDC Back-Channel Logout token
+	// when a principal who signed in to this client logs out or has
+	// their sessions revoked (oauth_clients.backchannel_logout_uri).
+	// nil opts the client out.
+	BackchannelLogoutURI *string  `json:"backchannelLogoutUri,omitempty"`
+	GrantTypes           []string `json:"grantTypes"` // "authorization_code", "client_credentials", "refresh_token"
+	Scopes               []string `json:"scopes"`
 	// AllowedOrigins is the CORS origin allowlist
 	// (oauth_client_allowed_origins).
 	AllowedOrigins []string `json:"allowedOrigins"`
@@ -68,11 +102,15 @@ type OAuthClient struct {
 	ApplicationIDs []string `json:"applicationIds"`
 	// PKCERequired gates whether /oauth/authorize demands a code_challenge.
 	// Maps to oauth_clients.pkce_required (DEFAULT TRUE).
-	PKCERequired bool      `json:"pkceRequired"`
-	Active       bool      `json:"active"`
-	PrincipalID  *string   `json:"principalId,omitempty"` // owning principal (for token-issued-on-behalf claims)
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	PKCERequired bool `json:"pkceRequired"`
+	// FirstParty clients are the platform's own and skip the consent
+	// screen on /oauth/authorize; third-party (partner) clients get it.
+	// Maps to oauth_clients.first_party (DEFAULT TRUE).
+	FirstParty  bool      `json:"firstParty"`
+	Active      bool      `json:"active"`
+	PrincipalID *string   `json:"principalId,omitempty"` // owning principal (for token-issued-on-behalf claims)
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 // IDStr satisfies usecase.HasID.
@@ -82,20 +120,22 @@ func (c OAuthClient) IDStr() string { return c.ID }
 func NewOAuthClient(clientID, name string, t OAuthClientType) *OAuthClient {
 	now := time.Now().UTC()
 	return &OAuthClient{
-		ID:                     tsid.Generate(tsid.OAuthClient),
-		ClientID:               clientID,
-		ClientName:             name,
-		ClientType:             t,
-		RedirectURIs:           []string{},
-		PostLogoutRedirectURIs: []string{},
-		GrantTypes:             []string{},
-		Scopes:                 []string{},
-		AllowedOrigins:         []string{},
-		ApplicationIDs:         []string{},
-		PKCERequired:           true,
-		Active:                 true,
-		CreatedAt:              now,
-		UpdatedAt:              now,
+		ID:                      tsid.Generate(tsid.OAuthClient),
+		ClientID:                clientID,
+		ClientName:              name,
+		ClientType:              t,
+		TokenEndpointAuthMethod: AuthMethodClientSecret,
+		RedirectURIs:            []string{},
+		PostLogoutRedirectURIs:  []string{},
+		GrantTypes:              []string{},
+		Scopes:                  []string{},
+		AllowedOrigins:          []string{},
+		ApplicationIDs:          []string{},
+		PKCERequired:            true,
+		FirstParty:              true,
+		Active:                  true,
+		CreatedAt:               now,
+		UpdatedAt:               now,
 	}
 }
 
@@ -235,6 +275,43 @@ func NewIdpRoleMapping(idpType, idpRoleName, platformRoleName string) *IdpRoleMa
 	}
 }
 
+// ── OAuthScope ────────────────────────────────────────────────────────────
+
+// OAuthScope is the text the consent screen shows for a scope. The
+// standard OIDC scopes have built-in text (StandardScopeDescriptions); a
+// definition overrides it. Maps to oauth_scopes.
+type OAuthScope struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"` // the scope token, e.g. "orders:read"
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (o OAuthScope) IDStr() string { return o.ID }
+
+// NewOAuthScope constructs an OAuthScope.
+func NewOAuthScope(name, description string) *OAuthScope {
+	now := time.Now().UTC()
+	return &OAuthScope{
+		ID:          tsid.Generate(tsid.OAuthScope),
+		Name:        name,
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// StandardScopeDescriptions is the consent-screen text for the OIDC
+// scopes every client may request.
+var StandardScopeDescriptions = map[string]string{
+	"openid":         "Sign you in with your FlowCatalyst account",
+	"profile":        "See your name and basic profile",
+	"email":          "See your email address",
+	"offline_access": "Stay signed in when you are not using the app",
+}
+
 // ── Lifecycle helpers ─────────────────────────────────────────────────────
 
 // Activate flips an OAuthClient to Active=true.
@@ -249,9 +326,8 @@ func (c *OAuthClient) Deactivate() {
 	c.UpdatedAt = time.Now().UTC()
 }
 
-// SetSecretRef records a rotated encrypted secret reference. The
-// plaintext lives only in memory long enough to return it once via the
-// rotate API.
+// SetSecretRef records a rotated secret hash. The plaintext lives only
+// in memory long enough to return it once via the rotate API.
 func (c *OAuthClient) SetSecretRef(ref string) {
 	c.SecretRef = &ref
 	c.UpdatedAt = time.Now().UTC()
@@ -0,0 +1,56 @@
+package grantstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Which OAuth clients a principal has signed in to (migration 074). One
+// oauth_client_logins row per (principal, client_id), touched whenever
+// /oauth/token issues user tokens (authorization_code, device_code).
+// Back-channel logout notifies the clients listed here and then clears
+// them.
+
+// ClientLoginRepository persists sign-ins in oauth_client_logins.
+type ClientLoginRepository struct{ pool *pgxpool.Pool }
+
+// NewClientLoginRepository wires the repo against pool.
+func NewClientLoginRepository(pool *pgxpool.Pool) *ClientLoginRepository {
+	return &ClientLoginRepository{pool: pool}
+}
+
+// Record notes that principalID signed in to clientID.
+func (r *ClientLoginRepository) Record(ctx context.Context, principalID, clientID string) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO oauth_client_logins (principal_id, client_id, first_login_at, last_login_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (principal_id, client_id) DO UPDATE SET last_login_at = NOW()`,
+		principalID, clientID)
+	if err != nil {
+		return fmt.Errorf("record client login: %w", err)
+	}
+	return nil
+}
+
+// TakeAll removes and returns the client_ids principalID is signed in to,
+// so each sign-in is logged out at most once.
+func (r *ClientLoginRepository) TakeAll(ctx context.Context, principalID string) ([]string, error) {
+	rows, err := r.pool.Query(ctx,
+		`DELETE FROM oauth_client_logins WHERE principal_id = $1 RETURNING client_id`,
+		principalID)
+	if err != nil {
+		return nil, fmt.Errorf("take client logins: %w", err)
+	}
+	defer rows.Close()
+	out := []string{}
+	for rows.Next() {
+		var clientID string
+		if err := rows.Scan(&clientID); err != nil {
+			return nil, fmt.Errorf("scan client login: %w", err)
+		}
+		out = append(out, clientID)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,124 @@
+package grantstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// User consent to third-party OAuth clients (migration 073). One
+// oauth_consents row per (principal, client_id); scopes is the union of
+// everything the user has approved for that client. /oauth/authorize
+// skips the consent screen while the requested scopes are a subset.
+
+// Consent is what a user has approved for one client.
+type Consent struct {
+	PrincipalID string
+	ClientID    string
+	Scopes      []string
+	GrantedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Covers reports whether every scope in requested has been approved.
+func (c *Consent) Covers(requested []string) bool {
+	for _, s := range requested {
+		if !slices.Contains(c.Scopes, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConsentRepository persists consents in oauth_consents.
+type ConsentRepository struct{ pool *pgxpool.Pool }
+
+// NewConsentRepository wires the repo against pool.
+func NewConsentRepository(pool *pgxpool.Pool) *ConsentRepository {
+	return &ConsentRepository{pool: pool}
+}
+
+const consentColumns = `principal_id, client_id, scopes, granted_at, updated_at`
+
+// Find loads the consent principalID gave clientID. Returns (nil, nil)
+// when there is none.
+func (r *ConsentRepository) Find(ctx context.Context, principalID, clientID string) (*Consent, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+consentColumns+` FROM oauth_consents
+		WHERE principal_id = $1 AND client_id = $2`,
+		principalID, clientID)
+	c, err := scanConsent(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// FindByPrincipal lists the clients principalID has consented to, most
+// recently updated first.
+func (r *ConsentRepository) FindByPrincipal(ctx context.Context, principalID string) ([]Consent, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+consentColumns+` FROM oauth_consents
+		WHERE principal_id = $1 ORDER BY updated_at DESC`,
+		principalID)
+	if err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	defer rows.Close()
+	out := []Consent{}
+	for rows.Next() {
+		c, err := scanConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// Grant records that principalID approved scopes for clientID, adding
+// them to anything approved before.
+func (r *ConsentRepository) Grant(ctx context.Context, principalID, clientID string, scopes []string) error {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO oauth_consents (principal_id, client_id, scopes, granted_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (principal_id, client_id) DO UPDATE SET
+			scopes = ARRAY(SELECT DISTINCT unnest(oauth_consents.scopes || EXCLUDED.scopes) ORDER BY 1),
+			updated_at = NOW()`,
+		principalID, clientID, scopes)
+	if err != nil {
+		return fmt.Errorf("grant consent: %w", err)
+	}
+	return nil
+}
+
+// Revoke deletes principalID's consent for clientID. Returns false when
+// there was none.
+func (r *ConsentRepository) Revoke(ctx context.Context, principalID, clientID string) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM oauth_consents WHERE principal_id = $1 AND client_id = $2`,
+		principalID, clientID)
+	if err != nil {
+		return false, fmt.Errorf("revoke consent: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func scanConsent(row pgx.Row) (*Consent, error) {
+	var c Consent
+	if err := row.Scan(&c.PrincipalID, &c.ClientID, &c.Scopes, &c.GrantedAt, &c.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("scan consent: %w", err)
+	}
+	if c.Scopes == nil {
+		c.Scopes = []string{}
+	}
+	return &c, nil
+}
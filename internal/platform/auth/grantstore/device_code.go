@@ -0,0 +1,285 @@
+package grantstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Device authorization grant (RFC 8628) state. A device authorization
+// lives in oauth_oidc_payloads (type = "DeviceCode", id
+// "DeviceCode:{HashToken(device_code)}") from /oauth/device_authorization
+// until the polling client redeems it at /oauth/token. The raw device code
+// is never stored — only its hash, like refresh tokens. The user code the
+// person types on the verification page goes in the table's user_code
+// column, normalised (upper case, no separator).
+
+const (
+	deviceCodePayloadType = "DeviceCode"
+
+	// DeviceCodeExpiry is how long the user has to approve a device.
+	DeviceCodeExpiry = 10 * time.Minute
+	// DeviceCodeInterval is the minimum polling interval handed to the
+	// device; polling faster earns slow_down.
+	DeviceCodeInterval = 5 * time.Second
+)
+
+// DeviceAuthorizationStatus is where a device authorization stands.
+type DeviceAuthorizationStatus string
+
+const (
+	DeviceAuthorizationPending  DeviceAuthorizationStatus = "PENDING"
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "APPROVED"
+	DeviceAuthorizationDenied   DeviceAuthorizationStatus = "DENIED"
+)
+
+// DeviceAuthorization is one outstanding device authorization request.
+type DeviceAuthorization struct {
+	DeviceCodeHash string
+	UserCode       string // normalised: see NormalizeUserCode
+	ClientID       string
+	Scope          *string
+	Status         DeviceAuthorizationStatus
+	// PrincipalID is the user who approved (or denied) the request.
+	PrincipalID  *string
+	LastPolledAt *time.Time
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// NewDeviceAuthorization builds a pending authorization for clientID,
+// returning it with the raw device code to hand to the device.
+func NewDeviceAuthorization(clientID string, scope *string) (rawDeviceCode string, d *DeviceAuthorization, err error) {
+	raw, err := GenerateRawToken()
+	if err != nil {
+		return "", nil, err
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now().UTC()
+	return raw, &DeviceAuthorization{
+		DeviceCodeHash: HashToken(raw),
+		UserCode:       userCode,
+		ClientID:       clientID,
+		Scope:          scope,
+		Status:         DeviceAuthorizationPending,
+		ExpiresAt:      now.Add(DeviceCodeExpiry),
+		CreatedAt:      now,
+	}, nil
+}
+
+// IsExpired reports whether the user ran out of time to approve.
+func (d *DeviceAuthorization) IsExpired() bool { return time.Now().UTC().After(d.ExpiresAt) }
+
+// userCodeAlphabet is RFC 8628 §6.1's suggested set: consonants only (no
+// vowels, so no accidental words) and nothing easily confused.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+const userCodeLength = 8
+
+// generateUserCode returns userCodeLength characters from
+// userCodeAlphabet. Rejection sampling keeps it unbiased.
+func generateUserCode() (string, error) {
+	out := make([]byte, 0, userCodeLength)
+	buf := make([]byte, 16)
+	limit := byte(256 - 256%len(userCodeAlphabet))
+	for len(out) < userCodeLength {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generate user code: %w", err)
+		}
+		for _, b := range buf {
+			if b >= limit || len(out) == userCodeLength {
+				continue
+			}
+			out = append(out, userCodeAlphabet[int(b)%len(userCodeAlphabet)])
+		}
+	}
+	return string(out), nil
+}
+
+// NormalizeUserCode upper-cases a typed user code and drops separators,
+// so "bcdf-ghjk", "BCDF GHJK" and "BCDFGHJK" all match.
+func NormalizeUserCode(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if r == '-' || r == ' ' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// FormatUserCode renders a normalised user code for display, split in
+// two halves ("BCDF-GHJK").
+func FormatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// deviceCodePayload is the camelCase JSONB stored under the row's payload
+// column.
+type deviceCodePayload struct {
+	ClientID     string  `json:"clientId"`
+	Scope        *string `json:"scope"`
+	Status       string  `json:"status"`
+	AccountID    *string `json:"accountId"`
+	LastPolledAt *string `json:"lastPolledAt"`
+	Kind         string  `json:"kind"`
+}
+
+// DeviceCodeRepository persists device authorizations in
+// oauth_oidc_payloads.
+type DeviceCodeRepository struct{ pool *pgxpool.Pool }
+
+// NewDeviceCodeRepository wires the repo against pool.
+func NewDeviceCodeRepository(pool *pgxpool.Pool) *DeviceCodeRepository {
+	return &DeviceCodeRepository{pool: pool}
+}
+
+func deviceCodeID(hash string) string { return deviceCodePayloadType + ":" + hash }
+
+// Insert stores a new pending authorization. It returns false, writing
+// nothing, when a live authorization already holds the same user code;
+// the caller retries with a fresh one.
+func (r *DeviceCodeRepository) Insert(ctx context.Context, d *DeviceAuthorization) (bool, error) {
+	payload, err := json.Marshal(deviceCodePayload{
+		ClientID: d.ClientID,
+		Scope:    d.Scope,
+		Status:   string(d.Status),
+		Kind:     deviceCodePayloadType,
+	})
+	if err != nil {
+		return false, fmt.Errorf("marshal device-code payload: %w", err)
+	}
+	tag, err := r.pool.Exec(ctx,
+		`INSERT INTO oauth_oidc_payloads
+			(id, type, payload, grant_id, user_code, uid, expires_at, consumed_at, created_at)
+		SELECT $1, $2, $3, NULL, $4, NULL, $5, NULL, $6
+		WHERE NOT EXISTS (
+			SELECT 1 FROM oauth_oidc_payloads
+			WHERE type = $2 AND user_code = $4 AND expires_at > NOW())`,
+		deviceCodeID(d.DeviceCodeHash), deviceCodePayloadType, payload, d.UserCode, d.ExpiresAt, d.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("insert device code: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FindPendingByUserCode loads the live, still-pending authorization for a
+// (normalised) user code. Returns (nil, nil) when there is none.
+func (r *DeviceCodeRepository) FindPendingByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT id, payload, user_code, expires_at, created_at
+		FROM oauth_oidc_payloads
+		WHERE type = $1 AND user_code = $2 AND expires_at > NOW()
+			AND payload->>'status' = $3`,
+		deviceCodePayloadType, userCode, string(DeviceAuthorizationPending))
+	return scanDeviceCode(row)
+}
+
+// Decide records the user's approval or denial of a pending
+// authorization. Returns false when the user code is unknown, expired or
+// already decided.
+func (r *DeviceCodeRepository) Decide(ctx context.Context, userCode, principalID string, approve bool) (bool, error) {
+	status := DeviceAuthorizationDenied
+	if approve {
+		status = DeviceAuthorizationApproved
+	}
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE oauth_oidc_payloads
+		SET payload = payload || jsonb_build_object('status', $3::text, 'accountId', $4::text)
+		WHERE type = $1 AND user_code = $2 AND expires_at > NOW()
+			AND payload->>'status' = $5`,
+		deviceCodePayloadType, userCode, string(status), principalID, string(DeviceAuthorizationPending))
+	if err != nil {
+		return false, fmt.Errorf("decide device code: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Poll records a token-endpoint poll for a raw device code and returns
+// the authorization as it stood before this poll (so LastPolledAt is the
+// previous poll, for slow_down). Expired rows are still returned — the
+// caller reports expired_token for them. Returns (nil, nil) for an
+// unknown or already-redeemed device code.
+func (r *DeviceCodeRepository) Poll(ctx context.Context, rawDeviceCode string) (*DeviceAuthorization, error) {
+	row := r.pool.QueryRow(ctx,
+		`WITH prev AS (
+			SELECT id, payload FROM oauth_oidc_payloads
+			WHERE id = $1 AND type = $2 AND consumed_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE oauth_oidc_payloads p
+		SET payload = p.payload || jsonb_build_object('lastPolledAt', $3::text)
+		FROM prev
+		WHERE p.id = prev.id
+		RETURNING p.id, prev.payload, p.user_code, p.expires_at, p.created_at`,
+		deviceCodeID(HashToken(rawDeviceCode)), deviceCodePayloadType,
+		time.Now().UTC().Format(time.RFC3339Nano))
+	return scanDeviceCode(row)
+}
+
+// Consume atomically deletes a decided (approved or denied) authorization
+// and returns it, so a device code is redeemed at most once. Returns
+// (nil, nil) when it is missing, still pending, or already redeemed.
+func (r *DeviceCodeRepository) Consume(ctx context.Context, rawDeviceCode string) (*DeviceAuthorization, error) {
+	row := r.pool.QueryRow(ctx,
+		`DELETE FROM oauth_oidc_payloads
+		WHERE id = $1 AND type = $2 AND payload->>'status' <> $3
+		RETURNING id, payload, user_code, expires_at, created_at`,
+		deviceCodeID(HashToken(rawDeviceCode)), deviceCodePayloadType, string(DeviceAuthorizationPending))
+	return scanDeviceCode(row)
+}
+
+func scanDeviceCode(row pgx.Row) (*DeviceAuthorization, error) {
+	var (
+		id        string
+		payload   []byte
+		userCode  *string
+		expiresAt *time.Time
+		createdAt time.Time
+	)
+	if err := row.Scan(&id, &payload, &userCode, &expiresAt, &createdAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan device code: %w", err)
+	}
+	var p deviceCodePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("unmarshal device-code payload: %w", err)
+	}
+	d := &DeviceAuthorization{
+		DeviceCodeHash: strings.TrimPrefix(id, deviceCodePayloadType+":"),
+		ClientID:       p.ClientID,
+		Scope:          p.Scope,
+		Status:         DeviceAuthorizationStatus(p.Status),
+		PrincipalID:    p.AccountID,
+		ExpiresAt:      createdAt.Add(DeviceCodeExpiry),
+		CreatedAt:      createdAt,
+	}
+	if userCode != nil {
+		d.UserCode = *userCode
+	}
+	if expiresAt != nil {
+		d.ExpiresAt = *expiresAt
+	}
+	if p.LastPolledAt != nil {
+		if t, err := time.Parse(time.RFC3339Nano, *p.LastPolledAt); err == nil {
+			d.LastPolledAt = &t
+		}
+	}
+	return d, nil
+}
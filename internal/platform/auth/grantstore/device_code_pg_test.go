@@ -0,0 +1,87 @@
+//go:build integration
+
+package grantstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+// TestDeviceCode_Lifecycle pins the RFC 8628 state machine against real
+// Postgres: pending → poll (last-poll tracking) → approve by user code →
+// single-use redemption.
+func TestDeviceCode_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := grantstore.NewDeviceCodeRepository(testpg.Pool(t))
+	scope := "openid offline_access"
+	raw, d, err := grantstore.NewDeviceAuthorization("oac_device_life", &scope)
+	require.NoError(t, err)
+	ok, err := repo.Insert(ctx, d)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// First poll: pending, never polled before.
+	got, err := repo.Poll(ctx, raw)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, grantstore.DeviceAuthorizationPending, got.Status)
+	assert.Nil(t, got.LastPolledAt)
+	assert.Equal(t, "oac_device_life", got.ClientID)
+
+	// Second poll sees the first poll's timestamp (drives slow_down).
+	got, err = repo.Poll(ctx, raw)
+	require.NoError(t, err)
+	require.NotNil(t, got.LastPolledAt)
+
+	// A pending authorization can't be redeemed.
+	consumed, err := repo.Consume(ctx, raw)
+	require.NoError(t, err)
+	assert.Nil(t, consumed)
+
+	// The user finds it by the code they typed (any case / separator).
+	typed := grantstore.FormatUserCode(d.UserCode)
+	pending, err := repo.FindPendingByUserCode(ctx, grantstore.NormalizeUserCode(typed))
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	assert.Equal(t, d.DeviceCodeHash, pending.DeviceCodeHash)
+
+	decided, err := repo.Decide(ctx, d.UserCode, "prn_device_life01", true)
+	require.NoError(t, err)
+	require.True(t, decided)
+	again, err := repo.Decide(ctx, d.UserCode, "prn_device_life01", false)
+	require.NoError(t, err)
+	assert.False(t, again, "a decided code can't be decided twice")
+
+	consumed, err = repo.Consume(ctx, raw)
+	require.NoError(t, err)
+	require.NotNil(t, consumed)
+	assert.Equal(t, grantstore.DeviceAuthorizationApproved, consumed.Status)
+	require.NotNil(t, consumed.PrincipalID)
+	assert.Equal(t, "prn_device_life01", *consumed.PrincipalID)
+	require.NotNil(t, consumed.Scope)
+	assert.Equal(t, scope, *consumed.Scope)
+
+	// Single use: gone for both redemption and polling.
+	consumed, err = repo.Consume(ctx, raw)
+	require.NoError(t, err)
+	assert.Nil(t, consumed)
+	got, err = repo.Poll(ctx, raw)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestDeviceCode_UserCodeShape(t *testing.T) {
+	_, d, err := grantstore.NewDeviceAuthorization("oac_device_shape", nil)
+	require.NoError(t, err)
+	assert.Len(t, d.UserCode, 8)
+	assert.Regexp(t, `^[BCDFGHJKLMNPQRSTVWXZ]{8}$`, d.UserCode)
+	assert.Equal(t, d.UserCode, grantstore.NormalizeUserCode(grantstore.FormatUserCode(d.UserCode)))
+	assert.Equal(t, "BCDFGHJK", grantstore.NormalizeUserCode("bcdf-ghjk"))
+	assert.Equal(t, "BCDFGHJK", grantstore.NormalizeUserCode(" BCDF GHJK "))
+}
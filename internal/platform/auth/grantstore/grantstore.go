@@ -454,6 +454,28 @@ func (r *RefreshTokenRepository) RevokeAllForPrincipal(ctx context.Context, prin
 	return tag.RowsAffected(), nil
 }
 
+// RevokeAllForPrincipalAndClient revokes a principal's active refresh
+// tokens issued to one client (consent withdrawn). Returns the number
+// revoked.
+func (r *RefreshTokenRepository) RevokeAllForPrincipalAndClient(ctx context.Context, principalID, clientID string) (int64, error) {
+	// $4/$5 split: see RevokeByHash.
+	now := time.Now().UTC()
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE oauth_oidc_payloads
+		SET payload = jsonb_set(
+			jsonb_set(payload, '{revoked}', 'true'::jsonb),
+			'{revokedAt}', to_jsonb($5::text)
+		), consumed_at = $4
+		WHERE type = $1 AND payload->>'accountId' = $2 AND payload->>'clientId' = $3
+		  AND consumed_at IS NULL AND expires_at > NOW()
+		  AND (payload->>'revoked' IS NULL OR payload->>'revoked' = 'false')`,
+		refreshTokenPayloadType, principalID, clientID, now, now.Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 // UpdateLastUsed stamps the lastUsedAt field for a token by id.
 func (r *RefreshTokenRepository) UpdateLastUsed(ctx context.Context, id string) (bool, error) {
 	tag, err := r.pool.Exec(ctx,
@@ -0,0 +1,138 @@
+package grantstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// An authorization request waiting on the user's consent lives in
+// oauth_oidc_payloads (type = "PendingConsent", id
+// "PendingConsent:{challenge}") from /oauth/authorize until the consent
+// screen posts the decision, and expires after 10 minutes. The challenge
+// is bound to the user who was signed in when it was issued.
+
+const (
+	pendingConsentPayloadType = "PendingConsent"
+	pendingConsentExpiry      = 10 * time.Minute
+)
+
+// PendingConsent is the authorization request held for the consent screen.
+type PendingConsent struct {
+	ClientID            string
+	RedirectURI         string
+	Scope               *string
+	CodeChallenge       *string
+	CodeChallengeMethod *string
+	Nonce               *string
+	State               *string
+	PrincipalID         string
+	CreatedAt           time.Time
+}
+
+type pendingConsentPayload struct {
+	ClientID            string  `json:"clientId"`
+	RedirectURI         string  `json:"redirectUri"`
+	Scope               *string `json:"scope"`
+	CodeChallenge       *string `json:"codeChallenge"`
+	CodeChallengeMethod *string `json:"codeChallengeMethod"`
+	Nonce               *string `json:"nonce"`
+	State               *string `json:"state"`
+	AccountID           string  `json:"accountId"`
+	CreatedAt           string  `json:"createdAt"`
+}
+
+// PendingConsentRepository persists pending consent requests in
+// oauth_oidc_payloads.
+type PendingConsentRepository struct{ pool *pgxpool.Pool }
+
+// NewPendingConsentRepository wires the repo against pool.
+func NewPendingConsentRepository(pool *pgxpool.Pool) *PendingConsentRepository {
+	return &PendingConsentRepository{pool: pool}
+}
+
+func pendingConsentID(challenge string) string { return pendingConsentPayloadType + ":" + challenge }
+
+// Insert stores a pending consent request under challenge.
+func (r *PendingConsentRepository) Insert(ctx context.Context, challenge string, p *PendingConsent) error {
+	now := time.Now().UTC()
+	payload, err := json.Marshal(pendingConsentPayload{
+		ClientID:            p.ClientID,
+		RedirectURI:         p.RedirectURI,
+		Scope:               p.Scope,
+		CodeChallenge:       p.CodeChallenge,
+		CodeChallengeMethod: p.CodeChallengeMethod,
+		Nonce:               p.Nonce,
+		State:               p.State,
+		AccountID:           p.PrincipalID,
+		CreatedAt:           p.CreatedAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal pending-consent payload: %w", err)
+	}
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO oauth_oidc_payloads (id, type, payload, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		pendingConsentID(challenge), pendingConsentPayloadType, payload, now.Add(pendingConsentExpiry), now)
+	if err != nil {
+		return fmt.Errorf("insert pending consent: %w", err)
+	}
+	return nil
+}
+
+// Find loads a still-valid pending consent request without consuming it
+// (the consent screen reads it before the user decides). Returns
+// (nil, nil) when missing or expired.
+func (r *PendingConsentRepository) Find(ctx context.Context, challenge string) (*PendingConsent, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT payload FROM oauth_oidc_payloads
+		WHERE id = $1 AND type = $2 AND expires_at > NOW()`,
+		pendingConsentID(challenge), pendingConsentPayloadType)
+	return scanPendingConsent(row)
+}
+
+// FindAndConsume atomically deletes and returns a still-valid pending
+// consent request, so a decision is applied at most once. Returns
+// (nil, nil) when missing, expired, or already consumed.
+func (r *PendingConsentRepository) FindAndConsume(ctx context.Context, challenge string) (*PendingConsent, error) {
+	row := r.pool.QueryRow(ctx,
+		`DELETE FROM oauth_oidc_payloads
+		WHERE id = $1 AND type = $2 AND expires_at > NOW()
+		RETURNING payload`,
+		pendingConsentID(challenge), pendingConsentPayloadType)
+	return scanPendingConsent(row)
+}
+
+func scanPendingConsent(row pgx.Row) (*PendingConsent, error) {
+	var payload []byte
+	if err := row.Scan(&payload); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan pending consent: %w", err)
+	}
+	var p pendingConsentPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("unmarshal pending-consent payload: %w", err)
+	}
+	createdAt := time.Now().UTC()
+	if t, err := time.Parse(time.RFC3339Nano, p.CreatedAt); err == nil {
+		createdAt = t.UTC()
+	}
+	return &PendingConsent{
+		ClientID:            p.ClientID,
+		RedirectURI:         p.RedirectURI,
+		Scope:               p.Scope,
+		CodeChallenge:       p.CodeChallenge,
+		CodeChallengeMethod: p.CodeChallengeMethod,
+		Nonce:               p.Nonce,
+		State:               p.State,
+		PrincipalID:         p.AccountID,
+		CreatedAt:           createdAt,
+	}, nil
+}
@@ -5,8 +5,10 @@ import (
 	"net/http"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordpolicy"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/mfa"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 )
 
 // handleChangePassword lets an authenticated internal user change their own
@@ -36,8 +38,8 @@ func (e *Endpoint) handleChangePassword(w http.ResponseWriter, r *http.Request)
 		writeJSON(w, http.StatusUnauthorized, errBody("INVALID_CURRENT_PASSWORD", "Your current password is incorrect."))
 		return
 	}
-	if len(req.NewPassword) < 8 {
-		writeJSON(w, http.StatusBadRequest, errBody("WEAK_PASSWORD", "New password must be at least 8 characters."))
+	if err := passwordpolicy.Current().Check(req.NewPassword); err != nil {
+		httperror.Write(w, err)
 		return
 	}
 
@@ -72,7 +74,7 @@ func (e *Endpoint) handleChangePassword(w http.ResponseWriter, r *http.Request)
 		writeServerError(w, "HASH_FAILED", "could not set the new password")
 		return
 	}
-	if err := e.cfg.Principals.UpdatePasswordHash(r.Context(), p.ID, newHash); err != nil {
+	if err := e.cfg.Principals.ChangePasswordHash(r.Context(), p.ID, newHash); err != nil {
 		writeServerError(w, "UPDATE_FAILED", "could not save the new password")
 		return
 	}
@@ -29,7 +29,9 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/loginbackoff"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/mfatoken"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordpolicy"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/provider"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/revocation"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/loginattempt"
@@ -40,6 +42,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	platformmw "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/middleware"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
 // SessionTTL is the cookie lifetime fc-server uses. Matches the Rust
@@ -73,21 +76,40 @@ type Config struct {
 	// MFA + MFATokens back the 2FA flow. When MFA or MFATokens is nil the
 	// /auth/2fa/* routes are not mounted and login never challenges (2FA
 	// disabled). A passkey does NOT exempt the password path: a passkey user
-	// who chooses to sign in with a password must still complete 2FA — so the
-	// webauthn repo plays no part here (passkey LOGIN bypasses 2FA on its own
-	// route).
+	// who chooses to sign in with a password must still complete 2FA (passkey
+	// LOGIN bypasses 2FA on its own route).
 	MFA       *mfa.Service
 	MFATokens *mfatoken.Issuer
+	// Passkeys (optional) lets a registered passkey answer the password
+	// login's 2FA challenge as the WEBAUTHN method. Nil leaves passkeys out
+	// of the password path entirely.
+	Passkeys PasskeyFactor
 	// Notifier sends best-effort 2FA security emails (enrolled, recovery codes,
 	// trusted device). Optional — nil is a safe no-op.
 	Notifier *notify.Notifier
 	// Audit (optional) records 2FA state changes (enroll / remove / regenerate)
-	// to the audit trail. Challenge success/failure is already in login attempts.
+	// and login lockouts to the audit trail. Individual attempts are already
+	// in login attempts.
 	Audit *audit.Repository
 	// PendingTokenTTL / EnrollTokenTTL bound the short-lived between-step
 	// tokens. Zero falls back to defaults (10m / 30m).
 	PendingTokenTTL time.Duration
 	EnrollTokenTTL  time.Duration
+	// Sessions (optional) revokes the presented session token on logout, so
+	// a copied cookie stops working too. Nil only clears the cookie.
+	Sessions *revocation.Checker
+	// Grants (optional) adds grant expiries to /auth/me's clientAccess.
+	// Nil reports the granted clients without them.
+	Grants *principal.ClientAccessGrantRepo
+	// Backchannel (optional) sends OIDC back-channel logout tokens to the
+	// OAuth clients the user signed in to when they log out here.
+	Backchannel LogoutNotifier
+}
+
+// LogoutNotifier is told when a principal logs out. Satisfied by
+// *backchannel.Notifier.
+type LogoutNotifier interface {
+	LoggedOut(ctx context.Context, principalID string)
 }
 
 // Endpoint is the bag of HTTP handlers.
@@ -403,9 +425,12 @@ func (e *Endpoint) handleLogin(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// rejectInvalid records a failed USER_LOGIN attempt and returns 401.
+	// rejectInvalid records a failed USER_LOGIN attempt, audits the lockout
+	// if this failure is the one that trips a ceiling, and returns 401.
+	var principalID *string
 	rejectInvalid := func() {
 		e.recordAttempt(r.Context(), loginattempt.OutcomeFailure, email, nil, ip, "Invalid credentials")
+		e.auditLockoutIfTripped(r.Context(), email, principalID, ip)
 		writeUnauthorized(w, "Invalid credentials")
 	}
 
@@ -414,6 +439,7 @@ func (e *Endpoint) handleLogin(w http.ResponseWriter, r *http.Request) {
 		rejectInvalid()
 		return
 	}
+	principalID = &p.ID
 	if p.UserIdentity == nil || p.UserIdentity.PasswordHash == nil {
 		rejectInvalid()
 		return
@@ -435,6 +461,17 @@ func (e *Endpoint) handleLogin(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Password expiry (FC_PASSWORD_MAX_AGE_DAYS). The password was right, so
+	// this isn't recorded as a failure; the user is sent to the reset flow,
+	// which sets a fresh password and restarts the clock.
+	if passwordpolicy.Current().Expired(p.UserIdentity.PasswordChangedAt, time.Now()) {
+		writeJSON(w, http.StatusForbidden, map[string]any{
+			"code":    "PASSWORD_EXPIRED",
+			"message": "Your password has expired. Use \"Forgot password\" to set a new one.",
+		})
+		return
+	}
+
 	// Same best-effort migration for a legacy mixed-case email: normalise it now
 	// that the user has authenticated. No-op when already lower-case.
 	if herr := e.cfg.Principals.LowercaseEmail(r.Context(), p); herr != nil {
@@ -514,6 +551,20 @@ func (e *Endpoint) completeLogin(w http.ResponseWriter, r *http.Request, p *prin
 // ── /auth/logout ─────────────────────────────────────────────────────────
 
 func (e *Endpoint) handleLogout(w http.ResponseWriter, r *http.Request) {
+	// Best-effort server-side revocation and back-channel logout: a
+	// failure is logged, and the cookie is cleared regardless.
+	if ck, err := r.Cookie(platformmw.SessionCookieName); err == nil && ck.Value != "" {
+		if c, verr := e.cfg.Provider.ValidateSessionToken(r.Context(), ck.Value); verr == nil {
+			if e.cfg.Sessions != nil && c.ID != "" {
+				if rerr := e.cfg.Sessions.RevokeToken(r.Context(), c.Subject, c.ID, c.ExpiresAt, nil); rerr != nil {
+					slog.Warn("session revocation on logout failed", "principal", c.Subject, "err", rerr)
+				}
+			}
+			if e.cfg.Backchannel != nil {
+				e.cfg.Backchannel.LoggedOut(r.Context(), c.Subject)
+			}
+		}
+	}
 	http.SetCookie(w, &http.Cookie{
 		Name:     platformmw.SessionCookieName,
 		Value:    "",
@@ -530,7 +581,13 @@ func (e *Endpoint) handleLogout(w http.ResponseWriter, r *http.Request) {
 
 // meResponse matches the LoginResponse shape — same fields the SPA
 // stores on first sign-in, so checkSession() returns directly comparable
-// data when the page is reloaded.
+// data when the page is reloaded — plus the principal's effective client
+// access, lapsed grants excluded.
+type meResponse struct {
+	loginResponse
+	ClientAccess principal.ClientAccess `json:"clientAccess"`
+}
+
 func (e *Endpoint) handleMe(w http.ResponseWriter, r *http.Request) {
 	ac := auth.FromContext(r.Context())
 	if ac == nil || ac.PrincipalID == "" {
@@ -560,16 +617,41 @@ func (e *Endpoint) handleMe(w http.ResponseWriter, r *http.Request) {
 	if p.UserIdentity != nil {
 		email = p.UserIdentity.Email
 	}
-	writeJSON(w, http.StatusOK, loginResponse{
-		PrincipalID: p.ID,
-		Name:        p.Name,
-		Email:       email,
-		Roles:       roles,
-		Permissions: buildPermissionList(claims),
-		ClientID:    p.ClientID,
+	access, err := e.clientAccess(r.Context(), p)
+	if err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, meResponse{
+		loginResponse: loginResponse{
+			PrincipalID: p.ID,
+			Name:        p.Name,
+			Email:       email,
+			Roles:       roles,
+			Permissions: buildPermissionList(claims),
+			ClientID:    p.ClientID,
+		},
+		ClientAccess: access,
 	})
 }
 
+// clientAccess resolves p's effective client access. Without the grant
+// repo it falls back to the (already expiry-filtered) AssignedClients.
+func (e *Endpoint) clientAccess(ctx context.Context, p *principal.Principal) (principal.ClientAccess, error) {
+	var grants []principal.ClientAccessGrant
+	if e.cfg.Grants != nil {
+		var err error
+		if grants, err = e.cfg.Grants.FindByPrincipal(ctx, p.ID); err != nil {
+			return principal.ClientAccess{}, err
+		}
+	} else {
+		for _, cid := range p.AssignedClients {
+			grants = append(grants, principal.ClientAccessGrant{PrincipalID: p.ID, ClientID: cid})
+		}
+	}
+	return principal.EffectiveClientAccess(p, grants, time.Now()), nil
+}
+
 // ── helpers ──────────────────────────────────────────────────────────────
 
 // writeJSON renders v as a JSON response body with the supplied status.
@@ -601,6 +683,44 @@ func (e *Endpoint) recordAttempt(ctx context.Context, outcome loginattempt.Outco
 	_ = e.cfg.LoginAttempts.Record(ctx, a)
 }
 
+// auditLockoutIfTripped re-runs the backoff check after a failure has been
+// recorded and, when that failure tripped a ceiling (per-account or per-IP),
+// writes a LoginLockout audit entry. Checking right after the tripping
+// failure — rather than on every rejected request during the lock — yields
+// one entry per lockout instead of one per blocked retry. Best-effort.
+func (e *Endpoint) auditLockoutIfTripped(ctx context.Context, email string, principalID *string, ip string) {
+	if e.cfg.Audit == nil || e.cfg.LoginAttempts == nil {
+		return
+	}
+	d, err := loginbackoff.Check(ctx, e.cfg.LoginAttempts, e.cfg.BackoffPolicy, email, ip)
+	if err != nil || d.Allowed || !d.Reason.Lockout() {
+		return
+	}
+	entityType, entityID := "LOGIN_IDENTIFIER", email
+	switch {
+	case d.Reason == loginbackoff.ReasonIPCeiling:
+		entityType, entityID = "LOGIN_IP", ip
+	case principalID != nil:
+		entityType, entityID = "PRINCIPAL", *principalID
+	}
+	detail, _ := json.Marshal(map[string]any{
+		"identifier":  email,
+		"ipAddress":   ip,
+		"reason":      d.Reason,
+		"lockSeconds": d.RetryAfterSecs,
+	})
+	slog.Warn("login lockout", "identifier", email, "ip", ip, "reason", d.Reason, "lock_secs", d.RetryAfterSecs)
+	_ = e.cfg.Audit.Insert(ctx, &audit.Log{
+		ID:            tsid.Generate(tsid.AuditLog),
+		EntityType:    entityType,
+		EntityID:      entityID,
+		Operation:     "LoginLockout",
+		OperationJSON: detail,
+		PrincipalID:   principalID,
+		PerformedAt:   time.Now().UTC(),
+	})
+}
+
 // clientIP extracts the best-effort client IP. Delegates to the canonical
 // ratelimit.ClientIP (rightmost X-Forwarded-For hop — see its doc for why
 // leftmost is spoofable) so the backoff keys and the per-IP rate limiter
@@ -20,6 +20,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/mfa"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 )
 
 // Token TTL fallbacks (overridable via Config).
@@ -36,6 +37,25 @@ const (
 	trustedDeviceCookieDev  = "fc_td"
 )
 
+// methodWebAuthn is the 2FA method name for a passkey assertion. It matches
+// emaildomainmapping.MFAWebAuthn so a domain's allow-list can permit it.
+const methodWebAuthn = string(emaildomainmapping.MFAWebAuthn)
+
+// PasskeyFactor verifies a passkey assertion as a second factor after the
+// password step. webauthn/api.SecondFactor implements it; the interface keeps
+// the login package free of the WebAuthn library.
+type PasskeyFactor interface {
+	// HasPasskey reports whether the principal has a registered passkey.
+	HasPasskey(ctx context.Context, principalID string) (bool, error)
+	// BeginAssertion starts an assertion ceremony over p's passkeys,
+	// returning its state id and the options the browser passes to
+	// navigator.credentials.get.
+	BeginAssertion(ctx context.Context, p *principal.Principal) (stateID string, options any, err error)
+	// FinishAssertion verifies the browser's assertion for stateID. An
+	// invalid assertion is (false, nil); errors are infrastructure failures.
+	FinishAssertion(ctx context.Context, p *principal.Principal, stateID string, credential json.RawMessage) (bool, error)
+}
+
 // twoFactorResponse is the body for the two pending outcomes of /auth/login.
 type twoFactorResponse struct {
 	Status string `json:"status"` // "mfa_required" | "enrollment_required"
@@ -60,6 +80,9 @@ func (e *Endpoint) RegisterTwoFactorRoutes(r chi.Router) {
 	}
 	r.Post("/auth/2fa/verify", e.handle2FAVerify)
 	r.Post("/auth/2fa/challenge/email", e.handle2FAChallengeEmail)
+	if e.cfg.Passkeys != nil {
+		r.Post("/auth/2fa/challenge/webauthn", e.handle2FAChallengeWebAuthn)
+	}
 	r.Post("/auth/2fa/enroll/totp/begin", e.handle2FAEnrollTOTPBegin)
 	r.Post("/auth/2fa/enroll/totp/confirm", e.handle2FAEnrollTOTPConfirm)
 	r.Post("/auth/2fa/enroll/email/begin", e.handle2FAEnrollEmailBegin)
@@ -92,9 +115,19 @@ func (e *Endpoint) maybeChallenge2FA(w http.ResponseWriter, r *http.Request, p *
 		return false, err
 	}
 
-	// Methods the user can actually challenge with: their confirmed factors,
-	// narrowed to the domain's allowed set when the domain enforces 2FA.
+	// Methods the user can actually challenge with: their confirmed factors
+	// plus a registered passkey, narrowed to the domain's allowed set when the
+	// domain enforces 2FA.
 	usable := methodStrings(confirmed)
+	if e.cfg.Passkeys != nil {
+		has, err := e.cfg.Passkeys.HasPasskey(r.Context(), p.ID)
+		if err != nil {
+			return false, err
+		}
+		if has {
+			usable = append(usable, methodWebAuthn)
+		}
+	}
 	if domainRequires {
 		usable = intersect(usable, mapping.Allowed2FAMethods)
 	}
@@ -126,8 +159,8 @@ func (e *Endpoint) maybeChallenge2FA(w http.ResponseWriter, r *http.Request, p *
 	}
 
 	// No usable factor. Only the domain can compel enrollment. A passkey does
-	// NOT exempt the password path: a passkey holder who chooses to sign in
-	// with a password must still complete 2FA — and therefore enroll a factor.
+	// NOT exempt the password path: a passkey holder whose domain doesn't
+	// allow WEBAUTHN must still complete 2FA — and therefore enroll a factor.
 	// (Signing in WITH the passkey skips all of this on its own route.)
 	if !domainRequires {
 		return false, nil
@@ -139,7 +172,7 @@ func (e *Endpoint) maybeChallenge2FA(w http.ResponseWriter, r *http.Request, p *
 	writeJSON(w, http.StatusOK, twoFactorResponse{
 		Status:         "enrollment_required",
 		EnrollToken:    tok,
-		AllowedMethods: mapping.Allowed2FAMethods,
+		AllowedMethods: enrollableMethods(mapping.Allowed2FAMethods),
 	})
 	return true, nil
 }
@@ -151,6 +184,10 @@ type verifyRequest struct {
 	Method         string `json:"method"`
 	Code           string `json:"code"`
 	RememberDevice bool   `json:"rememberDevice"`
+	// StateID + Credential carry a WEBAUTHN answer: the ceremony id from
+	// /auth/2fa/challenge/webauthn and the browser's assertion.
+	StateID    string          `json:"stateId,omitempty"`
+	Credential json.RawMessage `json:"credential,omitempty"`
 }
 
 func (e *Endpoint) handle2FAVerify(w http.ResponseWriter, r *http.Request) {
@@ -178,6 +215,12 @@ func (e *Endpoint) handle2FAVerify(w http.ResponseWriter, r *http.Request) {
 	var ok bool
 	var err error
 	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	// The challenge only offered the domain's allowed methods; an answer
+	// by any other factor doesn't satisfy it.
+	if !e.answerAllowed(r.Context(), p, method) {
+		writeMethodNotAllowed(w)
+		return
+	}
 	switch method {
 	case string(mfa.MethodTOTP):
 		ok, err = e.cfg.MFA.VerifyTOTP(r.Context(), p.ID, req.Code)
@@ -185,6 +228,12 @@ func (e *Endpoint) handle2FAVerify(w http.ResponseWriter, r *http.Request) {
 		ok, err = e.cfg.MFA.VerifyLoginEmailPin(r.Context(), p.ID, req.Code)
 	case "RECOVERY_CODE":
 		ok, err = e.cfg.MFA.VerifyRecoveryCode(r.Context(), p.ID, req.Code)
+	case methodWebAuthn:
+		if e.cfg.Passkeys == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": "INVALID_METHOD", "message": "unknown 2FA method"})
+			return
+		}
+		ok, err = e.cfg.Passkeys.FinishAssertion(r.Context(), p, req.StateID, req.Credential)
 	default:
 		writeJSON(w, http.StatusBadRequest, map[string]any{"code": "INVALID_METHOD", "message": "unknown 2FA method"})
 		return
@@ -237,6 +286,35 @@ func (e *Endpoint) handle2FAChallengeEmail(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, http.StatusOK, map[string]any{"message": "A verification code has been sent to your email."})
 }
 
+// handle2FAChallengeWebAuthn starts a passkey assertion for the pending
+// login; the browser's answer goes to /auth/2fa/verify as method WEBAUTHN.
+func (e *Endpoint) handle2FAChallengeWebAuthn(w http.ResponseWriter, r *http.Request) {
+	var req challengeEmailRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	p := e.principalFromToken(w, r, req.MFAToken, mfatoken.PurposePending)
+	if p == nil {
+		return
+	}
+	if !e.methodAllowed(r.Context(), p, methodWebAuthn) {
+		writeMethodNotAllowed(w)
+		return
+	}
+	stateID, options, err := e.cfg.Passkeys.BeginAssertion(r.Context(), p)
+	if err != nil {
+		var ue *usecase.Error
+		if errors.As(err, &ue) && ue.Kind == usecase.KindValidation {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": ue.Code, "message": ue.Message})
+			return
+		}
+		slog.Error("begin passkey challenge failed", "principal", p.ID, "err", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"code": "CHALLENGE_FAILED", "message": "could not start passkey challenge"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"stateId": stateID, "options": options})
+}
+
 // ── enrollment (gated by an enroll token) ──────────────────────────────────
 
 type enrollBeginRequest struct {
@@ -257,7 +335,7 @@ func (e *Endpoint) handle2FAEnrollTOTPBegin(w http.ResponseWriter, r *http.Reque
 	if p == nil {
 		return
 	}
-	if !e.methodAllowed(r.Context(), p, mfa.MethodTOTP) {
+	if !e.methodAllowed(r.Context(), p, string(mfa.MethodTOTP)) {
 		writeJSON(w, http.StatusForbidden, map[string]any{"code": "METHOD_NOT_ALLOWED", "message": "authenticator app is not permitted for this domain"})
 		return
 	}
@@ -301,7 +379,7 @@ func (e *Endpoint) handle2FAEnrollEmailBegin(w http.ResponseWriter, r *http.Requ
 	if p == nil {
 		return
 	}
-	if !e.methodAllowed(r.Context(), p, mfa.MethodEmailPin) {
+	if !e.methodAllowed(r.Context(), p, string(mfa.MethodEmailPin)) {
 		writeJSON(w, http.StatusForbidden, map[string]any{"code": "METHOD_NOT_ALLOWED", "message": "email codes are not permitted for this domain"})
 		return
 	}
@@ -410,14 +488,28 @@ func (e *Endpoint) domainPolicy(ctx context.Context, email string) (*emaildomain
 	return ev.Mapping, ev.Internal
 }
 
-// methodAllowed reports whether the user may enroll method t — always true
-// unless the domain enforces 2FA with a restricted allow-list.
-func (e *Endpoint) methodAllowed(ctx context.Context, p *principal.Principal, t mfa.MethodType) bool {
+// methodAllowed reports whether the user may use 2FA method t — always
+// true unless the domain enforces 2FA with a restricted allow-list.
+func (e *Endpoint) methodAllowed(ctx context.Context, p *principal.Principal, t string) bool {
 	mapping, internal := e.domainPolicy(ctx, emailOf(p))
 	if mapping == nil || !mapping.Require2FA || !internal {
 		return true
 	}
-	return containsString(mapping.Allowed2FAMethods, string(t))
+	return containsString(mapping.Allowed2FAMethods, t)
+}
+
+// answerAllowed is methodAllowed for a /auth/2fa/verify answer. A recovery
+// code stands in for the authenticator app it backs, so it is allowed
+// where TOTP is.
+func (e *Endpoint) answerAllowed(ctx context.Context, p *principal.Principal, method string) bool {
+	if method == "RECOVERY_CODE" {
+		method = string(mfa.MethodTOTP)
+	}
+	return e.methodAllowed(ctx, p, method)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeJSON(w, http.StatusForbidden, map[string]any{"code": "METHOD_NOT_ALLOWED", "message": "that 2FA method is not permitted for this domain"})
 }
 
 // rememberDevice issues a trusted-device token and sets the cookie, honouring
@@ -525,6 +617,19 @@ func containsMethodType(ms []mfa.MethodType, t mfa.MethodType) bool {
 	return false
 }
 
+// enrollableMethods drops WEBAUTHN from a domain allow-list for the
+// enrollment interstitial — passkeys are registered from the profile, not
+// mid-login.
+func enrollableMethods(allowed []string) []string {
+	out := make([]string, 0, len(allowed))
+	for _, m := range allowed {
+		if emaildomainmapping.EnrollableMFAMethod(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
 func intersect(a, allowed []string) []string {
 	out := make([]string, 0, len(a))
 	for _, x := range a {
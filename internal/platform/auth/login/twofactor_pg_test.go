@@ -0,0 +1,198 @@
+//go:build integration
+
+package login
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/mfatoken"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/provider"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/mfa"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+// fakePasskeys answers assertions whose state was begun for the same
+// principal, once.
+type fakePasskeys struct {
+	mu     sync.Mutex
+	states map[string]string // state id → principal id
+}
+
+func (f *fakePasskeys) HasPasskey(context.Context, string) (bool, error) { return true, nil }
+
+func (f *fakePasskeys) BeginAssertion(_ context.Context, p *principal.Principal) (string, any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := fmt.Sprintf("st_%d", len(f.states))
+	f.states[id] = p.ID
+	return id, map[string]any{"challenge": "c"}, nil
+}
+
+func (f *fakePasskeys) FinishAssertion(_ context.Context, p *principal.Principal, stateID string, _ json.RawMessage) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	owner, ok := f.states[stateID]
+	if !ok || owner != p.ID {
+		return false, nil
+	}
+	delete(f.states, stateID)
+	return true, nil
+}
+
+type twoFactorFixture struct {
+	srv    *httptest.Server
+	tokens *mfatoken.Issuer
+}
+
+func newTwoFactorFixture(t *testing.T, pool *pgxpool.Pool) twoFactorFixture {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	principals := principal.NewRepository(pool)
+	prov, err := provider.NewProvider(provider.Config{SigningKey: pemKey, Issuer: "fc-test"}, principals, role.NewRepository(pool))
+	require.NoError(t, err)
+	tokens := mfatoken.NewIssuer(key, "fc-test")
+
+	e := New(Config{
+		Provider:   prov,
+		Principals: principals,
+		Mappings:   emaildomainmapping.NewRepository(pool),
+		MFA:        mfa.NewService(mfa.NewRepository(pool), nil, nil, mfa.Config{}),
+		MFATokens:  tokens,
+		Passkeys:   &fakePasskeys{states: map[string]string{}},
+	})
+	r := chi.NewRouter()
+	e.RegisterTwoFactorRoutes(r)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return twoFactorFixture{srv: srv, tokens: tokens}
+}
+
+// seedUser creates a user on a fresh domain that requires 2FA with the
+// given allowed methods, and returns a pending-2FA token for them.
+func (f twoFactorFixture) seedUser(t *testing.T, pool *pgxpool.Pool, allowed ...string) string {
+	t.Helper()
+	ctx := context.Background()
+	id := tsid.Generate(tsid.Principal)
+	domain := strings.ToLower(id) + ".test"
+	_, err := pool.Exec(ctx,
+		`INSERT INTO iam_principals (id, type, scope, name, active, email)
+		 VALUES ($1, 'USER', 'ANCHOR', 'Test User', TRUE, $2)`, id, "user@"+domain)
+	require.NoError(t, err)
+
+	m := emaildomainmapping.New(domain, tsid.Generate(tsid.IdentityProvider), emaildomainmapping.ScopeAnchor)
+	m.Require2FA = true
+	m.Allowed2FAMethods = allowed
+	tx, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, emaildomainmapping.NewRepository(pool).Persist(ctx, m, usecasepgx.WrapTxForBootstrap(tx)))
+	require.NoError(t, tx.Commit(ctx))
+
+	tok, err := f.tokens.Mint(id, mfatoken.PurposePending, defaultPendingTokenTTL)
+	require.NoError(t, err)
+	return tok
+}
+
+func (f twoFactorFixture) post(t *testing.T, path string, body any) (int, map[string]any) {
+	t.Helper()
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	resp, err := http.Post(f.srv.URL+path, "application/json", bytes.NewReader(b))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func (f twoFactorFixture) challenge(t *testing.T, tok string) (int, string) {
+	t.Helper()
+	code, out := f.post(t, "/auth/2fa/challenge/webauthn", map[string]any{"mfaToken": tok})
+	stateID, _ := out["stateId"].(string)
+	return code, stateID
+}
+
+func (f twoFactorFixture) verifyPasskey(t *testing.T, tok, stateID string) (int, map[string]any) {
+	t.Helper()
+	return f.post(t, "/auth/2fa/verify", map[string]any{
+		"mfaToken": tok, "method": "WEBAUTHN", "stateId": stateID, "credential": map[string]any{"id": "cred"},
+	})
+}
+
+func TestTwoFactor_PasskeyAnswersChallengeOnce(t *testing.T) {
+	pool := testpg.Pool(t)
+	f := newTwoFactorFixture(t, pool)
+	tok := f.seedUser(t, pool, "WEBAUTHN", "TOTP")
+
+	code, stateID := f.challenge(t, tok)
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, stateID)
+
+	code, out := f.verifyPasskey(t, tok, stateID)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", out["status"])
+
+	code, _ = f.verifyPasskey(t, tok, stateID)
+	assert.Equal(t, http.StatusUnauthorized, code, "a consumed assertion state can't be replayed")
+}
+
+func TestTwoFactor_PasskeyStateIsBoundToPrincipal(t *testing.T) {
+	pool := testpg.Pool(t)
+	f := newTwoFactorFixture(t, pool)
+	alice := f.seedUser(t, pool, "WEBAUTHN")
+	bob := f.seedUser(t, pool, "WEBAUTHN")
+
+	code, bobState := f.challenge(t, bob)
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = f.verifyPasskey(t, alice, bobState)
+	assert.Equal(t, http.StatusUnauthorized, code, "another principal's assertion state doesn't verify")
+	code, _ = f.verifyPasskey(t, alice, "st_unknown")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestTwoFactor_DisallowedMethodIsRejected(t *testing.T) {
+	pool := testpg.Pool(t)
+	f := newTwoFactorFixture(t, pool)
+	tok := f.seedUser(t, pool, "TOTP")
+
+	code, out := f.post(t, "/auth/2fa/challenge/webauthn", map[string]any{"mfaToken": tok})
+	assert.Equal(t, http.StatusForbidden, code, "the domain doesn't allow passkeys")
+	assert.Equal(t, "METHOD_NOT_ALLOWED", out["code"])
+
+	// Even with a state begun elsewhere, a passkey can't answer.
+	other := f.seedUser(t, pool, "WEBAUTHN")
+	_, stateID := f.challenge(t, other)
+	code, out = f.verifyPasskey(t, tok, stateID)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "METHOD_NOT_ALLOWED", out["code"])
+
+	code, out = f.post(t, "/auth/2fa/verify", map[string]any{"mfaToken": tok, "method": "EMAIL_PIN", "code": "123456"})
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "METHOD_NOT_ALLOWED", out["code"])
+}
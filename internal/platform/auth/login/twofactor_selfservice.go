@@ -98,7 +98,7 @@ func (e *Endpoint) handle2FASelfTOTPBegin(w http.ResponseWriter, r *http.Request
 	if p == nil {
 		return
 	}
-	if !e.methodAllowed(r.Context(), p, mfa.MethodTOTP) {
+	if !e.methodAllowed(r.Context(), p, string(mfa.MethodTOTP)) {
 		writeJSON(w, http.StatusForbidden, errBody("METHOD_NOT_ALLOWED", "authenticator app is not permitted for this domain"))
 		return
 	}
@@ -140,7 +140,7 @@ func (e *Endpoint) handle2FASelfEmailBegin(w http.ResponseWriter, r *http.Reques
 	if p == nil {
 		return
 	}
-	if !e.methodAllowed(r.Context(), p, mfa.MethodEmailPin) {
+	if !e.methodAllowed(r.Context(), p, string(mfa.MethodEmailPin)) {
 		writeJSON(w, http.StatusForbidden, errBody("METHOD_NOT_ALLOWED", "email codes are not permitted for this domain"))
 		return
 	}
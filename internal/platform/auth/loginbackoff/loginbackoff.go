@@ -10,6 +10,9 @@
 //  2. Per-identifier global ceiling — caps total failures across all IPs in
 //     a sliding window, catching distributed attacks. A high threshold so
 //     it never trips on normal usage.
+//  3. Per-IP ceiling — caps failures from one source across all
+//     identifiers, catching credential stuffing that rotates accounts to
+//     stay under the per-pair curve. Off when IPCeiling is zero.
 //
 // Federated principals must be screened out before calling Check (the
 // email-domain gate redirects them to their IdP before any credential
@@ -33,6 +36,9 @@ type Policy struct {
 	GlobalWindowSecs int64  // window for the global ceiling
 	GlobalCeiling    int64  // failures (any IP) in-window that trigger a lock
 	GlobalLockSecs   int64  // lock duration when the ceiling trips
+	IPWindowSecs     int64  // window for the per-IP ceiling
+	IPCeiling        int64  // failures (any identifier) from one IP in-window that trigger a lock; 0 = off
+	IPLockSecs       int64  // lock duration when the per-IP ceiling trips
 }
 
 // PolicyFromEnv builds a Policy from FC_LOGIN_* env vars, falling back to
@@ -45,6 +51,9 @@ func PolicyFromEnv() Policy {
 		GlobalWindowSecs: int64(envutil.Int("FC_LOGIN_GLOBAL_WINDOW_SECS", 3600)),
 		GlobalCeiling:    int64(envutil.Int("FC_LOGIN_GLOBAL_CEILING", 100)),
 		GlobalLockSecs:   int64(envutil.Int("FC_LOGIN_GLOBAL_LOCK_SECS", 900)),
+		IPWindowSecs:     int64(envutil.Int("FC_LOGIN_IP_WINDOW_SECS", 900)),
+		IPCeiling:        int64(envutil.Int("FC_LOGIN_IP_CEILING", 50)),
+		IPLockSecs:       int64(envutil.Int("FC_LOGIN_IP_LOCK_SECS", 900)),
 	}
 }
 
@@ -72,8 +81,13 @@ type Reason string
 const (
 	ReasonPairBackoff   Reason = "pair_backoff"
 	ReasonGlobalCeiling Reason = "global_ceiling"
+	ReasonIPCeiling     Reason = "ip_ceiling"
 )
 
+// Lockout reports whether the reason is a hard lock (a ceiling tripped)
+// rather than the per-pair slow-down. Lockouts are audited.
+func (r Reason) Lockout() bool { return r == ReasonGlobalCeiling || r == ReasonIPCeiling }
+
 // Decision is the outcome of a Check. Allowed=false carries the seconds the
 // caller should wait (surfaced as a 429 + Retry-After).
 type Decision struct {
@@ -87,12 +101,14 @@ type statsRepo interface {
 	LastSuccessAt(ctx context.Context, identifier string) (*time.Time, error)
 	FailureStatsByIdentifierIPSince(ctx context.Context, identifier, ip string, since time.Time) (int, *time.Time, error)
 	FailureCountByIdentifierSince(ctx context.Context, identifier string, since time.Time) (int, error)
+	FailureCountByIPSince(ctx context.Context, ip string, since time.Time) (int, error)
 }
 
 var _ statsRepo = (*loginattempt.Repository)(nil)
 
-// Check runs the per-pair backoff + global ceiling. ip is best-effort —
-// pass "" when unknown (local dev) and only the global ceiling applies.
+// Check runs the per-pair backoff, the global ceiling and the per-IP
+// ceiling. ip is best-effort — pass "" when unknown (local dev) and only the
+// global ceiling applies.
 //
 // The identifier is lower-cased before querying: all current callers key on
 // an email, attempts are recorded lower-cased, and a raw `identifier = $1`
@@ -154,5 +170,21 @@ func Check(ctx context.Context, repo statsRepo, policy Policy, identifier, ip st
 		return Decision{Allowed: false, RetryAfterSecs: uint32(lock), Reason: ReasonGlobalCeiling}, nil
 	}
 
+	// Window 3: per-IP ceiling across identifiers. Not reset by a success —
+	// a stuffing run that hits one valid account keeps its other failures.
+	if ip != "" && policy.IPCeiling > 0 {
+		ipCount, err := repo.FailureCountByIPSince(ctx, ip, now.Add(-time.Duration(policy.IPWindowSecs)*time.Second))
+		if err != nil {
+			return Decision{}, err
+		}
+		if int64(ipCount) >= policy.IPCeiling {
+			lock := policy.IPLockSecs
+			if lock < 0 {
+				lock = 0
+			}
+			return Decision{Allowed: false, RetryAfterSecs: uint32(lock), Reason: ReasonIPCeiling}, nil
+		}
+	}
+
 	return Decision{Allowed: true}, nil
 }
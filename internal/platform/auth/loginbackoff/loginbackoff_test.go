@@ -14,6 +14,9 @@ func defaultPolicy() Policy {
 		GlobalWindowSecs: 3600,
 		GlobalCeiling:    100,
 		GlobalLockSecs:   900,
+		IPWindowSecs:     900,
+		IPCeiling:        50,
+		IPLockSecs:       600,
 	}
 }
 
@@ -40,6 +43,7 @@ type fakeRepo struct {
 	pairCount    int
 	pairLastFail *time.Time
 	globalCount  int
+	ipCount      int
 }
 
 func (f *fakeRepo) LastSuccessAt(context.Context, string) (*time.Time, error) {
@@ -54,6 +58,10 @@ func (f *fakeRepo) FailureCountByIdentifierSince(context.Context, string, time.T
 	return f.globalCount, nil
 }
 
+func (f *fakeRepo) FailureCountByIPSince(context.Context, string, time.Time) (int, error) {
+	return f.ipCount, nil
+}
+
 func TestCheckAllowsCleanIdentifier(t *testing.T) {
 	d, err := Check(context.Background(), &fakeRepo{}, defaultPolicy(), "a@b.com", "1.2.3.4")
 	if err != nil {
@@ -117,3 +125,31 @@ func TestCheckNoIPSkipsPairBackoff(t *testing.T) {
 		t.Errorf("empty IP should skip pair backoff, got %+v", d)
 	}
 }
+
+func TestCheckIPCeilingRejects(t *testing.T) {
+	d, err := Check(context.Background(), &fakeRepo{ipCount: 50}, defaultPolicy(), "fresh@b.com", "1.2.3.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Allowed || d.Reason != ReasonIPCeiling || !d.Reason.Lockout() {
+		t.Errorf("want ip_ceiling reject, got %+v", d)
+	}
+	if d.RetryAfterSecs != 600 {
+		t.Errorf("retry_after = %d, want 600", d.RetryAfterSecs)
+	}
+}
+
+func TestCheckIPCeilingDisabled(t *testing.T) {
+	p := defaultPolicy()
+	p.IPCeiling = 0
+	d, err := Check(context.Background(), &fakeRepo{ipCount: 10_000}, p, "a@b.com", "1.2.3.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Allowed {
+		t.Errorf("IPCeiling=0 should disable the per-IP gate, got %+v", d)
+	}
+	if ReasonPairBackoff.Lockout() {
+		t.Error("pair backoff is not a lockout")
+	}
+}
@@ -1,8 +1,10 @@
 package oauthapi
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -24,8 +26,10 @@ func (s *State) RegisterAuthorizeRoutes(r chi.Router) {
 
 // Authorize is GET /oauth/authorize (OAuth2 authorization-code flow with
 // PKCE), a 1:1 port of oauth_api.rs::authorize. When the caller already
-// has a valid session it issues a code and redirects to redirect_uri;
-// otherwise it stashes the request and redirects to the SPA login page.
+// has a valid session it issues a code and redirects to redirect_uri —
+// after the consent screen for a third-party client whose scopes the user
+// hasn't approved (see consent.go); otherwise it stashes the request and
+// redirects to the SPA login page.
 func (s *State) Authorize(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	responseType := q.Get("response_type")
@@ -48,7 +52,7 @@ func (s *State) Authorize(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Cluster-wide per-client_id throttle, before the DB lookup.
-	if rej := ratelimit.Enforce(r.Context(), s.RateLimit, ratelimit.BucketOAuthAuthorizeClient, clientID, s.RateLimitPolicies.OAuthAuthorizeClient); rej != nil {
+	if rej := ratelimit.Enforce(r.Context(), s.RateLimit, ratelimit.BucketOAuthAuthorizeClient, clientID, s.RateLimitPolicies.Get().OAuthAuthorizeClient); rej != nil {
 		ratelimit.WriteTooManyRequests(w, rej.RetryAfterSecs, "rate limit exceeded")
 		return
 	}
@@ -126,31 +130,40 @@ func (s *State) Authorize(w http.ResponseWriter, r *http.Request) {
 		forceLogin = true
 	}
 
-	// Authenticated, fresh session → issue the code immediately.
+	// Authenticated, fresh session → issue the code, unless a third-party
+	// client still needs the user's consent for these scopes.
 	if !forceLogin && sessOK && !sessionStale {
-		code := grantstore.NewAuthorizationCode(randomString(64), clientID, sessSubject, redirectURI)
-		code.Scope = strPtrOrNil(scope)
-		code.Nonce = strPtrOrNil(nonce)
-		code.State = strPtrOrNil(stateParam)
-		if codeChallenge != "" {
-			// RFC 7636 §4.3: a present code_challenge with an absent
-			// code_challenge_method defaults to "plain". Persist the binding
-			// unconditionally so PKCE can never be silently stripped by omitting
-			// the method — which would otherwise pass the PKCERequired gate
-			// above yet store nothing, letting an intercepted code be redeemed
-			// without a verifier.
-			method := codeChallengeMethod
-			if method == "" {
-				method = "plain"
+		req := authorizationRequest{
+			ClientID:            clientID,
+			RedirectURI:         redirectURI,
+			Scope:               scope,
+			State:               stateParam,
+			Nonce:               nonce,
+			CodeChallenge:       codeChallenge,
+			CodeChallengeMethod: codeChallengeMethod,
+		}
+		if !client.FirstParty {
+			needed, err := s.consentRequired(r.Context(), sessSubject, clientID, scope, prompt)
+			if err != nil {
+				slog.Error("authorize: consent lookup failed", "client_id", clientID, "err", err)
+				errorRedirect(w, r, redirectURI, "server_error", "Internal error", stateParam)
+				return
+			}
+			if needed {
+				// prompt=none forbids the consent screen too.
+				if prompt == "none" {
+					errorRedirect(w, r, redirectURI, "consent_required", "User consent is required", stateParam)
+					return
+				}
+				s.redirectToConsent(w, r, req, sessSubject)
+				return
 			}
-			code.CodeChallenge = &codeChallenge
-			code.CodeChallengeMethod = &method
 		}
-		if err := s.AuthCodes.Insert(r.Context(), code); err != nil {
+		redirectURL, err := s.authorizationCodeRedirect(r.Context(), req, sessSubject)
+		if err != nil {
 			errorRedirect(w, r, redirectURI, "server_error", "Failed to create authorization code", stateParam)
 			return
 		}
-		redirectURL := redirectURI + "?code=" + pctEncode(code.Code) + "&state=" + pctEncode(stateParam)
 		http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect) //nolint:gosec // G710: redirectURL is built from a redirect_uri already validated against the client's registered URIs
 		return
 	}
@@ -206,6 +219,46 @@ func (s *State) Authorize(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, loginURL, http.StatusTemporaryRedirect)
 }
 
+// authorizationRequest is a validated /oauth/authorize request, carried
+// through the consent screen when one is needed.
+type authorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// authorizationCodeRedirect mints a code for subject and returns the
+// client's (already validated) redirect_uri carrying it.
+func (s *State) authorizationCodeRedirect(ctx context.Context, req authorizationRequest, subject string) (string, error) {
+	code := grantstore.NewAuthorizationCode(randomString(64), req.ClientID, subject, req.RedirectURI)
+	code.Scope = strPtrOrNil(req.Scope)
+	code.Nonce = strPtrOrNil(req.Nonce)
+	code.State = strPtrOrNil(req.State)
+	if req.CodeChallenge != "" {
+		// RFC 7636 §4.3: a present code_challenge with an absent
+		// code_challenge_method defaults to "plain". Persist the binding
+		// unconditionally so PKCE can never be silently stripped by omitting
+		// the method — which would otherwise pass the PKCERequired gate
+		// yet store nothing, letting an intercepted code be redeemed
+		// without a verifier.
+		method := req.CodeChallengeMethod
+		if method == "" {
+			method = "plain"
+		}
+		challenge := req.CodeChallenge
+		code.CodeChallenge = &challenge
+		code.CodeChallengeMethod = &method
+	}
+	if err := s.AuthCodes.Insert(ctx, code); err != nil {
+		return "", err
+	}
+	return req.RedirectURI + "?code=" + pctEncode(code.Code) + "&state=" + pctEncode(req.State), nil
+}
+
 // sessionToken pulls the session JWT from the fc_session cookie, falling
 // back to the Authorization: Bearer header (cookie takes precedence, as
 // in Rust).
@@ -236,11 +289,16 @@ func maxAgeExceeded(maxAge string, issuedAt time.Time) bool {
 // errorRedirect bounces the user-agent back to redirect_uri with the OAuth
 // error params (302-equivalent temporary redirect, matching Rust).
 func errorRedirect(w http.ResponseWriter, r *http.Request, redirectURI, errCode, desc, state string) {
+	http.Redirect(w, r, errorRedirectURL(redirectURI, errCode, desc, state), http.StatusTemporaryRedirect) //nolint:gosec // G710: error redirect to the client's validated redirect_uri
+}
+
+// errorRedirectURL is redirect_uri carrying the OAuth error params.
+func errorRedirectURL(redirectURI, errCode, desc, state string) string {
 	url := redirectURI + "?error=" + pctEncode(errCode) + "&error_description=" + pctEncode(desc)
 	if state != "" {
 		url += "&state=" + pctEncode(state)
 	}
-	http.Redirect(w, r, url, http.StatusTemporaryRedirect) //nolint:gosec // G710: error redirect to the client's validated redirect_uri
+	return url
 }
 
 func invalidScopes(scope string, clientScopes []string) []string {
@@ -0,0 +1,192 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// clientAssertionJWTBearer is the only client_assertion_type accepted
+// (RFC 7523 §2.2).
+const clientAssertionJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// maxClientAssertionLifetime bounds how far in the future an assertion
+// may expire, which also bounds how long its jti is remembered.
+const maxClientAssertionLifetime = 10 * time.Minute
+
+// clientAssertionSkew is the clock skew tolerated on exp/nbf/iat.
+const clientAssertionSkew = 30 * time.Second
+
+// authenticateClientAssertion authenticates a private_key_jwt client
+// from the client_assertion form parameter. The assertion's iss names the
+// client; clientIDBody, when sent, must agree with it.
+func (s *State) authenticateClientAssertion(r *http.Request, clientIDBody string) (*auth.OAuthClient, *oauthError) {
+	if r.PostFormValue("client_assertion_type") != clientAssertionJWTBearer {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_request", "Unsupported client_assertion_type")
+	}
+	assertion := r.PostFormValue("client_assertion")
+	if assertion == "" {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_request", "Missing client_assertion")
+	}
+	// RFC 6749 §2.3: a request uses one authentication method.
+	if _, _, ok := basicAuthCreds(r); ok || r.PostFormValue("client_secret") != "" {
+		return nil, newOAuthError(http.StatusBadRequest, "invalid_request",
+			"client_assertion cannot be combined with a client secret")
+	}
+
+	unverified, err := jwt.ParseString(assertion, jwt.WithVerify(false), jwt.WithValidate(false))
+	if err != nil || unverified.Issuer() == "" {
+		return nil, s.clientAuthFailed(r, clientIDBody, nil, "malformed client assertion", "Invalid client assertion")
+	}
+	clientID := unverified.Issuer()
+	if clientIDBody != "" && clientIDBody != clientID {
+		return nil, s.clientAuthFailed(r, clientIDBody, nil, "client_id does not match the assertion issuer",
+			"client_id does not match the client assertion")
+	}
+
+	client, err := s.OAuthClients.FindByClientID(r.Context(), clientID)
+	if err != nil {
+		return nil, newOAuthError(http.StatusInternalServerError, "server_error", "")
+	}
+	if client == nil {
+		return nil, s.clientAuthFailed(r, clientID, nil, "unknown client", "Unknown client")
+	}
+	if !client.Active {
+		return nil, s.clientAuthFailed(r, clientID, client, "client inactive", "Client is not active")
+	}
+	if client.TokenEndpointAuthMethod != auth.AuthMethodPrivateKeyJWT {
+		return nil, s.clientAuthFailed(r, clientID, client, "assertion presented by a client-secret client",
+			"Client is not registered for private_key_jwt")
+	}
+	if err := s.verifyClientAssertion(client, assertion); err != nil {
+		return nil, s.clientAuthFailed(r, clientID, client, "invalid client assertion: "+err.Error(), "Invalid client assertion")
+	}
+	return client, nil
+}
+
+// verifyClientAssertion checks the assertion's signature against the
+// client's registered keys, and its claims per RFC 7523 §3: iss and sub
+// are the client_id, aud is this issuer or its token endpoint, exp is
+// present and near, and the jti hasn't been seen before.
+func (s *State) verifyClientAssertion(client *auth.OAuthClient, assertion string) error {
+	if client.JWKS == nil {
+		return errors.New("client has no registered keys")
+	}
+	set, err := jwk.Parse([]byte(*client.JWKS))
+	if err != nil {
+		return fmt.Errorf("registered keys: %w", err)
+	}
+	tok, err := jwt.ParseString(assertion,
+		jwt.WithKeySet(set, jws.WithInferAlgorithmFromKey(true), jws.WithRequireKid(false)),
+		jwt.WithIssuer(client.ClientID),
+		jwt.WithSubject(client.ClientID),
+		jwt.WithRequiredClaim(jwt.ExpirationKey),
+		jwt.WithRequiredClaim(jwt.JwtIDKey),
+		jwt.WithAcceptableSkew(clientAssertionSkew),
+	)
+	if err != nil {
+		return err
+	}
+	if s.BaseURL == "" || !slices.ContainsFunc(tok.Audience(), func(aud string) bool {
+		return aud == s.BaseURL || aud == s.BaseURL+"/oauth/token"
+	}) {
+		return errors.New("audience is not this token endpoint")
+	}
+	if time.Until(tok.Expiration()) > maxClientAssertionLifetime {
+		return errors.New("expires too far in the future")
+	}
+	if !s.assertionJTIs.claim(client.ClientID+" "+tok.JwtID(), tok.Expiration()) {
+		return errors.New("jti already used")
+	}
+	return nil
+}
+
+// jtiCache remembers the client assertions seen until they expire, so
+// each is accepted once. Per instance: a replay to another instance is
+// only bounded by maxClientAssertionLifetime.
+type jtiCache struct {
+	mu    sync.Mutex
+	seen  map[string]time.Time
+	swept time.Time
+}
+
+// claim records key until exp; false if it is already recorded.
+func (c *jtiCache) claim(key string, exp time.Time) bool {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seen == nil {
+		c.seen = make(map[string]time.Time)
+	}
+	if now.Sub(c.swept) > time.Minute {
+		for k, e := range c.seen {
+			if now.After(e.Add(clientAssertionSkew)) {
+				delete(c.seen, k)
+			}
+		}
+		c.swept = now
+	}
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	c.seen[key] = exp
+	return true
+}
+
+// clientAuthFailed logs and audits a failed client authentication, and
+// returns the invalid_client error to send. reason is for operators (the
+// audit trail); description goes to the caller. client is nil when the
+// client_id didn't resolve.
+func (s *State) clientAuthFailed(r *http.Request, clientID string, client *auth.OAuthClient, reason, description string) *oauthError {
+	method := string(auth.AuthMethodClientSecret)
+	if r.PostFormValue("client_assertion") != "" {
+		method = string(auth.AuthMethodPrivateKeyJWT)
+	}
+	ip := ratelimit.ClientIP(r)
+	slog.Warn("oauth client authentication failed", "client_id", clientID, "method", method, "reason", reason, "ip", ip)
+	s.auditClientAuthFailure(r.Context(), clientID, client, method, reason, ip)
+	return newOAuthError(http.StatusUnauthorized, "invalid_client", description)
+}
+
+// auditClientAuthFailure records a failed client authentication
+// (best-effort). The presented secret or assertion is never logged.
+func (s *State) auditClientAuthFailure(ctx context.Context, clientID string, client *auth.OAuthClient, method, reason, ip string) {
+	if s.Audit == nil {
+		return
+	}
+	entityID := clientID
+	if client != nil {
+		entityID = client.ID
+	}
+	opJSON, _ := json.Marshal(map[string]any{
+		"clientId":   clientID,
+		"authMethod": method,
+		"reason":     reason,
+		"ipAddress":  ip,
+	})
+	if err := s.Audit.Insert(ctx, &audit.Log{
+		ID:            tsid.Generate(tsid.AuditLog),
+		EntityType:    "OAuthClient",
+		EntityID:      entityID,
+		Operation:     "OAUTH_CLIENT_AUTH_FAILED",
+		OperationJSON: opJSON,
+		PerformedAt:   time.Now().UTC(),
+	}); err != nil {
+		slog.Warn("audit of client authentication failure failed", "client_id", clientID, "err", err)
+	}
+}
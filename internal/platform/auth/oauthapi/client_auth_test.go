@@ -0,0 +1,167 @@
+package oauthapi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+)
+
+const testIssuer = "https://fc.example.com"
+
+// postForm builds a /oauth/token request carrying form.
+func postForm(form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestAuthenticateClient_HashedSecret(t *testing.T) {
+	ref, err := passwordhash.Hash("s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &auth.OAuthClient{ClientID: "oac_1", Active: true, SecretRef: &ref,
+		TokenEndpointAuthMethod: auth.AuthMethodClientSecret}
+	s := &State{OAuthClients: fakeClientFinder{client: client}}
+
+	if _, oerr := s.authenticateClient(postForm(url.Values{}), "oac_1", "s3cr3t"); oerr != nil {
+		t.Fatalf("valid secret rejected: %s", oerr.Code)
+	}
+	if _, oerr := s.authenticateClient(postForm(url.Values{}), "oac_1", "wrong"); oerr == nil || oerr.Code != "invalid_client" {
+		t.Fatalf("wrong secret: got %+v, want invalid_client", oerr)
+	}
+}
+
+// keyClient registers a fresh RSA public key on a private_key_jwt client
+// and returns the client and the private key.
+func keyClient(t *testing.T) (*auth.OAuthClient, *rsa.PrivateKey) {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := jwk.FromRaw(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := jwk.NewSet()
+	_ = set.AddKey(pub)
+	raw, _ := json.Marshal(set)
+	jwks := string(raw)
+	if err := auth.ValidateClientJWKS(jwks); err != nil {
+		t.Fatalf("public JWKS rejected: %v", err)
+	}
+	return &auth.OAuthClient{ClientID: "oac_key", Active: true, ClientType: auth.OAuthClientConfidential,
+		TokenEndpointAuthMethod: auth.AuthMethodPrivateKeyJWT, JWKS: &jwks}, priv
+}
+
+func signAssertion(t *testing.T, priv *rsa.PrivateKey, clientID, aud, jti string, exp time.Time) string {
+	t.Helper()
+	tok := jwt.New()
+	_ = tok.Set(jwt.IssuerKey, clientID)
+	_ = tok.Set(jwt.SubjectKey, clientID)
+	_ = tok.Set(jwt.AudienceKey, aud)
+	_ = tok.Set(jwt.JwtIDKey, jti)
+	_ = tok.Set(jwt.ExpirationKey, exp)
+	signed, err := jwt.Sign(tok, jwt.WithKey(jwa.RS256, priv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(signed)
+}
+
+func assertionForm(assertion string) url.Values {
+	return url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {clientAssertionJWTBearer},
+		"client_assertion":      {assertion},
+	}
+}
+
+func TestAuthenticateClientAssertion(t *testing.T) {
+	client, priv := keyClient(t)
+	s := &State{OAuthClients: fakeClientFinder{client: client}, BaseURL: testIssuer}
+	exp := time.Now().Add(2 * time.Minute)
+
+	good := signAssertion(t, priv, "oac_key", testIssuer+"/oauth/token", "jti-1", exp)
+	got, oerr := s.authenticateClient(postForm(assertionForm(good)), "", "")
+	if oerr != nil {
+		t.Fatalf("valid assertion rejected: %s %s", oerr.Code, derefOr(oerr.Description, ""))
+	}
+	if got.ClientID != "oac_key" {
+		t.Fatalf("authenticated %q, want oac_key", got.ClientID)
+	}
+
+	if _, oerr := s.authenticateClient(postForm(assertionForm(good)), "", ""); oerr == nil || oerr.Code != "invalid_client" {
+		t.Fatalf("replayed jti: got %+v, want invalid_client", oerr)
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	cases := map[string]string{
+		"wrong key":       signAssertion(t, other, "oac_key", testIssuer, "jti-2", exp),
+		"wrong audience":  signAssertion(t, priv, "oac_key", "https://elsewhere.example.com", "jti-3", exp),
+		"expired":         signAssertion(t, priv, "oac_key", testIssuer, "jti-4", time.Now().Add(-time.Minute)),
+		"too long-lived":  signAssertion(t, priv, "oac_key", testIssuer, "jti-5", time.Now().Add(time.Hour)),
+		"issuer mismatch": signAssertion(t, priv, "oac_other", testIssuer, "jti-6", exp),
+	}
+	for name, assertion := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, oerr := s.authenticateClient(postForm(assertionForm(assertion)), "", ""); oerr == nil || oerr.Code != "invalid_client" {
+				t.Fatalf("got %+v, want invalid_client", oerr)
+			}
+		})
+	}
+}
+
+func TestAuthenticateClientAssertion_Rejections(t *testing.T) {
+	client, priv := keyClient(t)
+	s := &State{OAuthClients: fakeClientFinder{client: client}, BaseURL: testIssuer}
+	assertion := signAssertion(t, priv, "oac_key", testIssuer, "jti-r", time.Now().Add(time.Minute))
+
+	form := assertionForm(assertion)
+	form.Set("client_secret", "also-a-secret")
+	if _, oerr := s.authenticateClient(postForm(form), "", "also-a-secret"); oerr == nil || oerr.Code != "invalid_request" {
+		t.Fatalf("assertion plus secret: got %+v, want invalid_request", oerr)
+	}
+
+	form = assertionForm(assertion)
+	form.Set("client_assertion_type", "urn:example:other")
+	if _, oerr := s.authenticateClient(postForm(form), "", ""); oerr == nil || oerr.Code != "invalid_request" {
+		t.Fatalf("unsupported assertion type: got %+v, want invalid_request", oerr)
+	}
+
+	// A private_key_jwt client can't fall back to a secret.
+	if _, oerr := s.authenticateClient(postForm(url.Values{}), "oac_key", "guess"); oerr == nil || oerr.Code != "invalid_client" {
+		t.Fatalf("secret for key client: got %+v, want invalid_client", oerr)
+	}
+}
+
+func TestValidateClientJWKS_RejectsPrivateAndSharedKeys(t *testing.T) {
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	k, _ := jwk.FromRaw(priv)
+	set := jwk.NewSet()
+	_ = set.AddKey(k)
+	raw, _ := json.Marshal(set)
+	if err := auth.ValidateClientJWKS(string(raw)); err == nil {
+		t.Error("private key accepted")
+	}
+	if err := auth.ValidateClientJWKS(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`); err == nil {
+		t.Error("shared key accepted")
+	}
+	if err := auth.ValidateClientJWKS(`{"keys":[]}`); err == nil {
+		t.Error("empty set accepted")
+	}
+}
@@ -0,0 +1,409 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// Consent for third-party clients. First-party clients (the platform's
+// own) are trusted and skip this entirely. For a third-party client,
+// /oauth/authorize checks the user's stored consent: when it doesn't
+// cover the requested scopes (or prompt=consent), the request is parked
+// under a consent challenge and the user is sent to the SPA consent page,
+// which reads the request from GET /oauth/consent and posts the decision
+// to POST /oauth/consent. Approval records the scopes, so the next
+// authorization for the same scopes goes straight through.
+
+// consentPagePath is the SPA route that renders the consent screen.
+const consentPagePath = "/auth/consent"
+
+// ConsentStore persists what users approved for third-party clients.
+// Satisfied by *grantstore.ConsentRepository.
+type ConsentStore interface {
+	Find(ctx context.Context, principalID, clientID string) (*grantstore.Consent, error)
+	FindByPrincipal(ctx context.Context, principalID string) ([]grantstore.Consent, error)
+	Grant(ctx context.Context, principalID, clientID string, scopes []string) error
+	Revoke(ctx context.Context, principalID, clientID string) (bool, error)
+}
+
+// PendingConsentStore holds authorization requests waiting on the
+// consent screen. Satisfied by *grantstore.PendingConsentRepository.
+type PendingConsentStore interface {
+	Insert(ctx context.Context, challenge string, p *grantstore.PendingConsent) error
+	Find(ctx context.Context, challenge string) (*grantstore.PendingConsent, error)
+	FindAndConsume(ctx context.Context, challenge string) (*grantstore.PendingConsent, error)
+}
+
+// ScopeCatalog lists the admin-defined scope descriptions. Satisfied by
+// *auth.OAuthScopeRepo.
+type ScopeCatalog interface {
+	FindAll(ctx context.Context) ([]auth.OAuthScope, error)
+}
+
+// RegisterConsentRoutes mounts the consent endpoints. Like
+// /oauth/authorize they resolve the session themselves and MUST be
+// mounted outside the session-auth middleware.
+func (s *State) RegisterConsentRoutes(r chi.Router) {
+	r.Get("/oauth/consent", s.ConsentRequest)
+	r.Post("/oauth/consent", s.ConsentDecision)
+	r.Get("/oauth/consents", s.ListConsents)
+	r.Delete("/oauth/consents/{clientId}", s.RevokeConsent)
+}
+
+// consentRequired reports whether subject must see the consent screen
+// before clientID (a third-party client) gets a code for scope. Fails
+// closed when the consent stores aren't wired.
+func (s *State) consentRequired(ctx context.Context, subject, clientID, scope, prompt string) (bool, error) {
+	if s.Consents == nil || s.PendingConsents == nil {
+		return false, errors.New("consent stores not configured")
+	}
+	if prompt == "consent" {
+		return true, nil
+	}
+	c, err := s.Consents.Find(ctx, subject, clientID)
+	if err != nil {
+		return false, err
+	}
+	return c == nil || !c.Covers(strings.Fields(scope)), nil
+}
+
+// redirectToConsent parks req under a fresh consent challenge, bound to
+// subject, and sends the user-agent to the SPA consent page.
+func (s *State) redirectToConsent(w http.ResponseWriter, r *http.Request, req authorizationRequest, subject string) {
+	challenge := randomString(32)
+	pending := &grantstore.PendingConsent{
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               strPtrOrNil(req.Scope),
+		CodeChallenge:       strPtrOrNil(req.CodeChallenge),
+		CodeChallengeMethod: strPtrOrNil(req.CodeChallengeMethod),
+		Nonce:               strPtrOrNil(req.Nonce),
+		State:               strPtrOrNil(req.State),
+		PrincipalID:         subject,
+		CreatedAt:           time.Now().UTC(),
+	}
+	if err := s.PendingConsents.Insert(r.Context(), challenge, pending); err != nil {
+		slog.Error("authorize: store pending consent failed", "client_id", req.ClientID, "err", err)
+		errorRedirect(w, r, req.RedirectURI, "server_error", "Internal error", req.State)
+		return
+	}
+	http.Redirect(w, r, consentPagePath+"?consent_challenge="+pctEncode(challenge), http.StatusTemporaryRedirect)
+}
+
+// consentScope is one requested scope as the consent screen shows it.
+type consentScope struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	PreviouslyGranted bool   `json:"previouslyGranted"`
+}
+
+// consentRequestResponse is the body of GET /oauth/consent.
+type consentRequestResponse struct {
+	ClientID    string         `json:"clientId"`
+	ClientName  string         `json:"clientName"`
+	RedirectURI string         `json:"redirectUri"`
+	Scopes      []consentScope `json:"scopes"`
+}
+
+// ConsentRequest is GET /oauth/consent?consent_challenge=…: the pending
+// request the consent screen asks the signed-in user about.
+func (s *State) ConsentRequest(w http.ResponseWriter, r *http.Request) {
+	subject, ok := s.sessionSubject(r)
+	if !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "login_required", "User is not authenticated")
+		return
+	}
+	if s.PendingConsents == nil || s.Consents == nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	p, ok := s.pendingConsentFor(w, r, r.URL.Query().Get("consent_challenge"), subject, false)
+	if !ok {
+		return
+	}
+	clientName := p.ClientID
+	if c, err := s.OAuthClients.FindByClientID(r.Context(), p.ClientID); err == nil && c != nil && c.ClientName != "" {
+		clientName = c.ClientName
+	}
+	prior, err := s.Consents.Find(r.Context(), subject, p.ClientID)
+	if err != nil {
+		slog.Error("consent: lookup failed", "client_id", p.ClientID, "err", err)
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	scopes := s.describeScopes(r.Context(), strings.Fields(derefOr(p.Scope, "")), prior)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, consentRequestResponse{
+		ClientID:    p.ClientID,
+		ClientName:  clientName,
+		RedirectURI: p.RedirectURI,
+		Scopes:      scopes,
+	})
+}
+
+// consentDecisionRequest is the body of POST /oauth/consent.
+type consentDecisionRequest struct {
+	ConsentChallenge string `json:"consentChallenge"`
+	Approve          bool   `json:"approve"`
+}
+
+// consentDecisionResponse tells the SPA where to send the user-agent
+// next. The decision is a fetch() from the consent page, so a 3xx to the
+// client's redirect_uri would be followed by fetch rather than the
+// browser; the SPA navigates to redirectTo instead.
+type consentDecisionResponse struct {
+	RedirectTo string `json:"redirectTo"`
+}
+
+// ConsentDecision is POST /oauth/consent: the signed-in user approves or
+// denies a pending request. Approval records the scopes and issues the
+// authorization code; denial returns access_denied to the client.
+func (s *State) ConsentDecision(w http.ResponseWriter, r *http.Request) {
+	if !sameOriginPost(r) {
+		writeOAuthError(w, http.StatusForbidden, "access_denied", "Cross-origin request")
+		return
+	}
+	subject, ok := s.sessionSubject(r)
+	if !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "login_required", "User is not authenticated")
+		return
+	}
+	if s.PendingConsents == nil || s.Consents == nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	var body consentDecisionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
+		return
+	}
+	p, ok := s.pendingConsentFor(w, r, body.ConsentChallenge, subject, true)
+	if !ok {
+		return
+	}
+	state := derefOr(p.State, "")
+	if !body.Approve {
+		writeJSON(w, http.StatusOK, consentDecisionResponse{
+			RedirectTo: errorRedirectURL(p.RedirectURI, "access_denied", "The user denied the request", state),
+		})
+		return
+	}
+
+	// The client may have been deactivated or made first-party while the
+	// user looked at the screen; only the former matters.
+	client, err := s.OAuthClients.FindByClientID(r.Context(), p.ClientID)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	if client == nil || !client.Active {
+		writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", "Client is not active")
+		return
+	}
+	scope := derefOr(p.Scope, "")
+	if err := s.Consents.Grant(r.Context(), subject, p.ClientID, strings.Fields(scope)); err != nil {
+		slog.Error("consent: grant failed", "client_id", p.ClientID, "err", err)
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	s.auditConsent(r.Context(), "OAUTH_CONSENT_GRANTED", subject, client, map[string]any{
+		"clientId": p.ClientID,
+		"scopes":   strings.Fields(scope),
+	})
+	redirectURL, err := s.authorizationCodeRedirect(r.Context(), authorizationRequest{
+		ClientID:            p.ClientID,
+		RedirectURI:         p.RedirectURI,
+		Scope:               scope,
+		State:               state,
+		Nonce:               derefOr(p.Nonce, ""),
+		CodeChallenge:       derefOr(p.CodeChallenge, ""),
+		CodeChallengeMethod: derefOr(p.CodeChallengeMethod, ""),
+	}, subject)
+	if err != nil {
+		writeJSON(w, http.StatusOK, consentDecisionResponse{
+			RedirectTo: errorRedirectURL(p.RedirectURI, "server_error", "Failed to create authorization code", state),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, consentDecisionResponse{RedirectTo: redirectURL})
+}
+
+// pendingConsentFor loads the pending request for challenge, consuming it
+// when consume is set, and checks it belongs to subject. On failure it
+// writes the error and returns ok=false. Someone else's challenge is
+// reported as unknown, and is left in place.
+func (s *State) pendingConsentFor(w http.ResponseWriter, r *http.Request, challenge, subject string, consume bool) (*grantstore.PendingConsent, bool) {
+	if strings.TrimSpace(challenge) == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "consent_challenge is required")
+		return nil, false
+	}
+	p, err := s.PendingConsents.Find(r.Context(), challenge)
+	if err == nil && p != nil && p.PrincipalID == subject && consume {
+		p, err = s.PendingConsents.FindAndConsume(r.Context(), challenge)
+	}
+	if err != nil {
+		slog.Error("consent: pending lookup failed", "err", err)
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return nil, false
+	}
+	if p == nil || p.PrincipalID != subject {
+		writeOAuthError(w, http.StatusNotFound, "invalid_request", "Consent request is invalid or has expired")
+		return nil, false
+	}
+	return p, true
+}
+
+// describeScopes resolves the consent-screen text for each requested
+// scope: an admin definition, else the built-in text for a standard OIDC
+// scope, else the scope name itself.
+func (s *State) describeScopes(ctx context.Context, requested []string, prior *grantstore.Consent) []consentScope {
+	defined := map[string]string{}
+	if s.Scopes != nil {
+		all, err := s.Scopes.FindAll(ctx)
+		if err != nil {
+			slog.Warn("consent: scope definitions unavailable", "err", err)
+		}
+		for _, d := range all {
+			defined[d.Name] = d.Description
+		}
+	}
+	out := make([]consentScope, 0, len(requested))
+	for _, name := range requested {
+		desc, ok := defined[name]
+		if !ok {
+			desc, ok = auth.StandardScopeDescriptions[name]
+		}
+		if !ok {
+			desc = name
+		}
+		out = append(out, consentScope{
+			Name:              name,
+			Description:       desc,
+			PreviouslyGranted: prior != nil && prior.Covers([]string{name}),
+		})
+	}
+	return out
+}
+
+// consentEntry is one client in GET /oauth/consents.
+type consentEntry struct {
+	ClientID   string    `json:"clientId"`
+	ClientName string    `json:"clientName"`
+	Scopes     []string  `json:"scopes"`
+	GrantedAt  time.Time `json:"grantedAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ListConsents is GET /oauth/consents: the third-party clients the
+// signed-in user has approved.
+func (s *State) ListConsents(w http.ResponseWriter, r *http.Request) {
+	subject, ok := s.sessionSubject(r)
+	if !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "login_required", "User is not authenticated")
+		return
+	}
+	if s.Consents == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"consents": []consentEntry{}})
+		return
+	}
+	consents, err := s.Consents.FindByPrincipal(r.Context(), subject)
+	if err != nil {
+		slog.Error("consent: list failed", "err", err)
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	out := make([]consentEntry, 0, len(consents))
+	for _, c := range consents {
+		name := c.ClientID
+		if client, err := s.OAuthClients.FindByClientID(r.Context(), c.ClientID); err == nil && client != nil && client.ClientName != "" {
+			name = client.ClientName
+		}
+		out = append(out, consentEntry{
+			ClientID:   c.ClientID,
+			ClientName: name,
+			Scopes:     c.Scopes,
+			GrantedAt:  c.GrantedAt,
+			UpdatedAt:  c.UpdatedAt,
+		})
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]any{"consents": out})
+}
+
+// RevokeConsent is DELETE /oauth/consents/{clientId}: the signed-in user
+// withdraws a client's consent. The client's refresh tokens for the user
+// are revoked with it, so the next authorization asks again.
+func (s *State) RevokeConsent(w http.ResponseWriter, r *http.Request) {
+	if !sameOriginPost(r) {
+		writeOAuthError(w, http.StatusForbidden, "access_denied", "Cross-origin request")
+		return
+	}
+	subject, ok := s.sessionSubject(r)
+	if !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "login_required", "User is not authenticated")
+		return
+	}
+	if s.Consents == nil {
+		writeOAuthError(w, http.StatusNotFound, "invalid_request", "No consent for this client")
+		return
+	}
+	clientID := chi.URLParam(r, "clientId")
+	found, err := s.Consents.Revoke(r.Context(), subject, clientID)
+	if err != nil {
+		slog.Error("consent: revoke failed", "client_id", clientID, "err", err)
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Internal error")
+		return
+	}
+	if !found {
+		writeOAuthError(w, http.StatusNotFound, "invalid_request", "No consent for this client")
+		return
+	}
+	var revoked int64
+	if s.RefreshTokens != nil {
+		if revoked, err = s.RefreshTokens.RevokeAllForPrincipalAndClient(r.Context(), subject, clientID); err != nil {
+			slog.Warn("consent: refresh-token revocation failed", "client_id", clientID, "err", err)
+		}
+	}
+	client, _ := s.OAuthClients.FindByClientID(r.Context(), clientID)
+	s.auditConsent(r.Context(), "OAUTH_CONSENT_REVOKED", subject, client, map[string]any{
+		"clientId":             clientID,
+		"refreshTokensRevoked": revoked,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// auditConsent records a consent grant or revocation against the client
+// (best-effort).
+func (s *State) auditConsent(ctx context.Context, operation, subject string, client *auth.OAuthClient, detail map[string]any) {
+	if s.Audit == nil {
+		return
+	}
+	entityID, _ := detail["clientId"].(string)
+	if client != nil {
+		entityID = client.ID
+	}
+	opJSON, _ := json.Marshal(detail)
+	if err := s.Audit.Insert(ctx, &audit.Log{
+		ID:            tsid.Generate(tsid.AuditLog),
+		EntityType:    "OAuthClient",
+		EntityID:      entityID,
+		Operation:     operation,
+		OperationJSON: opJSON,
+		PrincipalID:   &subject,
+		PerformedAt:   time.Now().UTC(),
+	}); err != nil {
+		slog.Warn("audit of consent change failed", "operation", operation, "err", err)
+	}
+}
@@ -0,0 +1,209 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+)
+
+// fakeConsents is an in-memory ConsentStore keyed by principal+client.
+type fakeConsents map[string]*grantstore.Consent
+
+func (f fakeConsents) Find(_ context.Context, principalID, clientID string) (*grantstore.Consent, error) {
+	return f[principalID+" "+clientID], nil
+}
+
+func (f fakeConsents) FindByPrincipal(context.Context, string) ([]grantstore.Consent, error) {
+	return nil, nil
+}
+
+func (f fakeConsents) Grant(_ context.Context, principalID, clientID string, scopes []string) error {
+	f[principalID+" "+clientID] = &grantstore.Consent{PrincipalID: principalID, ClientID: clientID, Scopes: scopes}
+	return nil
+}
+
+func (f fakeConsents) Revoke(_ context.Context, principalID, clientID string) (bool, error) {
+	_, ok := f[principalID+" "+clientID]
+	delete(f, principalID+" "+clientID)
+	return ok, nil
+}
+
+// fakePendingConsents is an in-memory PendingConsentStore.
+type fakePendingConsents map[string]*grantstore.PendingConsent
+
+func (f fakePendingConsents) Insert(_ context.Context, challenge string, p *grantstore.PendingConsent) error {
+	f[challenge] = p
+	return nil
+}
+
+func (f fakePendingConsents) Find(_ context.Context, challenge string) (*grantstore.PendingConsent, error) {
+	return f[challenge], nil
+}
+
+func (f fakePendingConsents) FindAndConsume(_ context.Context, challenge string) (*grantstore.PendingConsent, error) {
+	p := f[challenge]
+	delete(f, challenge)
+	return p, nil
+}
+
+// consentState is a State with a signed-in "prn_user" and a third-party
+// client "partner" registered at https://partner/cb.
+func consentState(consents fakeConsents, pending fakePendingConsents) *State {
+	client := &auth.OAuthClient{ID: "oac_1", ClientID: "partner", ClientName: "Partner App", Active: true,
+		RedirectURIs: []string{"https://partner/cb"}, Scopes: []string{"orders:read"}}
+	return &State{
+		OAuthClients:    fakeClientFinder{client: client},
+		Consents:        consents,
+		PendingConsents: pending,
+		ValidateSession: func(string) (string, time.Time, bool) { return "prn_user", time.Now(), true },
+	}
+}
+
+func runAuthorize(s *State, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/oauth/authorize?response_type=code&client_id=partner&redirect_uri=https://partner/cb&state=xyz&"+query, nil)
+	req.Header.Set("Authorization", "Bearer session")
+	s.Authorize(rec, req)
+	return rec
+}
+
+func TestAuthorize_ThirdPartyWithoutConsentGoesToConsentScreen(t *testing.T) {
+	pending := fakePendingConsents{}
+	s := consentState(fakeConsents{}, pending)
+	rec := runAuthorize(s, "scope=openid%20orders:read&nonce=n1")
+
+	if rec.Code != 307 {
+		t.Fatalf("status = %d, want 307 (%s)", rec.Code, rec.Body.String())
+	}
+	loc, _ := url.Parse(rec.Header().Get("Location"))
+	if loc.Path != consentPagePath {
+		t.Fatalf("Location = %q, want the consent page", loc)
+	}
+	p := pending[loc.Query().Get("consent_challenge")]
+	if p == nil {
+		t.Fatal("no pending consent stored under the challenge")
+	}
+	if p.PrincipalID != "prn_user" || p.ClientID != "partner" || derefOr(p.Scope, "") != "openid orders:read" ||
+		derefOr(p.State, "") != "xyz" || derefOr(p.Nonce, "") != "n1" {
+		t.Errorf("pending consent = %+v", p)
+	}
+}
+
+func TestAuthorize_ThirdPartyConsentRequiredWithPromptNone(t *testing.T) {
+	consents := fakeConsents{"prn_user partner": {Scopes: []string{"openid"}}}
+	s := consentState(consents, fakePendingConsents{})
+	rec := runAuthorize(s, "scope=openid%20orders:read&prompt=none")
+
+	loc := rec.Header().Get("Location")
+	if !strings.HasPrefix(loc, "https://partner/cb?") || !strings.Contains(loc, "error=consent_required") {
+		t.Fatalf("Location = %q, want consent_required at the client", loc)
+	}
+}
+
+func TestAuthorize_ThirdPartyWithoutConsentStoresFailsClosed(t *testing.T) {
+	s := consentState(fakeConsents{}, fakePendingConsents{})
+	s.Consents, s.PendingConsents = nil, nil
+	rec := runAuthorize(s, "scope=openid")
+
+	if loc := rec.Header().Get("Location"); !strings.Contains(loc, "error=server_error") {
+		t.Fatalf("Location = %q, want server_error", loc)
+	}
+}
+
+func TestConsentRequired(t *testing.T) {
+	consents := fakeConsents{"prn_user partner": {Scopes: []string{"openid", "orders:read"}}}
+	s := consentState(consents, fakePendingConsents{})
+	cases := []struct {
+		subject, scope, prompt string
+		want                   bool
+	}{
+		{"prn_user", "openid orders:read", "", false},
+		{"prn_user", "openid", "", false},
+		{"prn_user", "openid orders:write", "", true},
+		{"prn_user", "openid", "consent", true},
+		{"prn_other", "", "", true},
+	}
+	for _, c := range cases {
+		got, err := s.consentRequired(context.Background(), c.subject, "partner", c.scope, c.prompt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("consentRequired(%q, %q, %q) = %v, want %v", c.subject, c.scope, c.prompt, got, c.want)
+		}
+	}
+}
+
+func TestConsentRequest_DescribesScopes(t *testing.T) {
+	consents := fakeConsents{"prn_user partner": {Scopes: []string{"openid"}}}
+	pending := fakePendingConsents{"ch1": {ClientID: "partner", RedirectURI: "https://partner/cb",
+		Scope: strPtrOrNil("openid orders:read"), PrincipalID: "prn_user"}}
+	s := consentState(consents, pending)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/oauth/consent?consent_challenge=ch1", nil)
+	req.Header.Set("Authorization", "Bearer session")
+	s.ConsentRequest(rec, req)
+	if rec.Code != 200 {
+		t.Fatalf("status = %d (%s)", rec.Code, rec.Body.String())
+	}
+	var body consentRequestResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.ClientName != "Partner App" || len(body.Scopes) != 2 {
+		t.Fatalf("body = %+v", body)
+	}
+	if got := body.Scopes[0]; got.Description != auth.StandardScopeDescriptions["openid"] || !got.PreviouslyGranted {
+		t.Errorf("openid = %+v", got)
+	}
+	if got := body.Scopes[1]; got.Description != "orders:read" || got.PreviouslyGranted {
+		t.Errorf("orders:read = %+v", got)
+	}
+}
+
+func TestConsentDecision_OtherUsersChallengeIsUnknown(t *testing.T) {
+	pending := fakePendingConsents{"ch1": {ClientID: "partner", RedirectURI: "https://partner/cb", PrincipalID: "prn_someone"}}
+	s := consentState(fakeConsents{}, pending)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/oauth/consent", strings.NewReader(`{"consentChallenge":"ch1","approve":true}`))
+	req.Header.Set("Authorization", "Bearer session")
+	s.ConsentDecision(rec, req)
+	if rec.Code != 404 {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if pending["ch1"] == nil {
+		t.Error("another user's challenge was consumed")
+	}
+}
+
+func TestConsentDecision_DenyReturnsAccessDenied(t *testing.T) {
+	pending := fakePendingConsents{"ch1": {ClientID: "partner", RedirectURI: "https://partner/cb",
+		State: strPtrOrNil("xyz"), PrincipalID: "prn_user"}}
+	consents := fakeConsents{}
+	s := consentState(consents, pending)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/oauth/consent", strings.NewReader(`{"consentChallenge":"ch1","approve":false}`))
+	req.Header.Set("Authorization", "Bearer session")
+	s.ConsentDecision(rec, req)
+	if rec.Code != 200 {
+		t.Fatalf("status = %d (%s)", rec.Code, rec.Body.String())
+	}
+	var body consentDecisionResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if !strings.HasPrefix(body.RedirectTo, "https://partner/cb?error=access_denied") || !strings.Contains(body.RedirectTo, "state=xyz") {
+		t.Errorf("redirectTo = %q", body.RedirectTo)
+	}
+	if len(consents) != 0 || len(pending) != 0 {
+		t.Errorf("deny left consents=%v pending=%v", consents, pending)
+	}
+}
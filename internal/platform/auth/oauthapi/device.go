@@ -0,0 +1,456 @@
+package oauthapi
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+)
+
+// Device authorization grant (RFC 8628), for CLI tooling that can't host
+// a redirect URI or keep a client secret:
+//
+//  1. The CLI POSTs /oauth/device_authorization and gets a device_code
+//     (kept secret) plus a short user_code and verification URI.
+//  2. The user opens /oauth/device in a browser, signs in as usual, and
+//     approves or denies the user code.
+//  3. Meanwhile the CLI polls /oauth/token with grant_type
+//     urn:ietf:params:oauth:grant-type:device_code, getting
+//     authorization_pending / slow_down until the user decides, then the
+//     same tokens an authorization_code grant would issue.
+//
+// State lives in oauth_oidc_payloads via grantstore.DeviceCodeRepository.
+
+// deviceCodeGrantType is the RFC 8628 grant_type value.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// deviceVerificationPath is the user-facing verification page.
+const deviceVerificationPath = "/oauth/device"
+
+// deviceUserCodeAttempts bounds retries when a freshly generated user code
+// collides with a live one.
+const deviceUserCodeAttempts = 3
+
+// deviceUserCodeMaxMisses is how many unknown user codes a principal may
+// enter within a code's lifetime (RFC 8628 §5.1). Past it their lookups
+// are refused, and a live code they do find is denied rather than shown:
+// it was likely guessed.
+const deviceUserCodeMaxMisses = 5
+
+var deviceUserCodeMissPolicy = ratelimit.Policy{Window: grantstore.DeviceCodeExpiry, Limit: deviceUserCodeMaxMisses}
+
+// deviceAuthorizationResponse is RFC 8628 §3.2's response body.
+type deviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// DeviceAuthorization is POST /oauth/device_authorization (RFC 8628 §3.1).
+// The client authenticates as it would at /oauth/token — public clients
+// by client_id alone — and must be allowed the device_code grant.
+func (s *State) DeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Malformed form body")
+		return
+	}
+	clientID := r.PostFormValue("client_id")
+	scope := r.PostFormValue("scope")
+
+	// Same per-client_id throttle as /oauth/token.
+	if clientID != "" {
+		if s.ClientGovernor != nil {
+			if ok, retry := s.ClientGovernor.Check(clientID); !ok {
+				writeOAuthRateLimited(w, retry, "this client_id has exceeded its token endpoint rate limit")
+				return
+			}
+		}
+		if rej := ratelimit.Enforce(r.Context(), s.RateLimit, ratelimit.BucketOAuthTokenClient, clientID, s.RateLimitPolicies.Get().OAuthTokenClient); rej != nil {
+			writeOAuthRateLimited(w, rej.RetryAfterSecs, "this client_id has exceeded its token endpoint rate limit")
+			return
+		}
+	}
+
+	client, errResp := s.authenticateClient(r, clientID, r.PostFormValue("client_secret"))
+	if errResp != nil {
+		errResp.write(w)
+		return
+	}
+	if !grantAllowed(client, deviceCodeGrantType) {
+		writeOAuthError(w, http.StatusBadRequest, "unauthorized_client",
+			"Client is not permitted to use the device_code grant type")
+		return
+	}
+	if scope != "" {
+		if invalid := invalidScopes(scope, client.Scopes); len(invalid) > 0 {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_scope",
+				"Invalid scope(s): "+strings.Join(invalid, ", "))
+			return
+		}
+	}
+
+	var (
+		raw string
+		d   *grantstore.DeviceAuthorization
+	)
+	for range deviceUserCodeAttempts {
+		var err error
+		raw, d, err = grantstore.NewDeviceAuthorization(client.ClientID, strPtrOrNil(scope))
+		if err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		inserted, err := s.DeviceCodes.Insert(r.Context(), d)
+		if err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		if inserted {
+			break
+		}
+		d = nil
+	}
+	if d == nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Could not allocate a user code")
+		return
+	}
+
+	userCode := grantstore.FormatUserCode(d.UserCode)
+	verificationURI := s.BaseURL + deviceVerificationPath
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	writeJSON(w, http.StatusOK, deviceAuthorizationResponse{
+		DeviceCode:              raw,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + pctEncode(userCode),
+		ExpiresIn:               int64(grantstore.DeviceCodeExpiry / time.Second),
+		Interval:                int64(grantstore.DeviceCodeInterval / time.Second),
+	})
+}
+
+// handleDeviceCodeGrant is the polling leg (RFC 8628 §3.4–3.5). The client
+// was authenticated by Token; the device code must have been issued to it.
+func (s *State) handleDeviceCodeGrant(w http.ResponseWriter, r *http.Request, req tokenRequest, client *auth.OAuthClient) {
+	if req.DeviceCode == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Missing device_code parameter")
+		return
+	}
+	d, err := s.DeviceCodes.Poll(r.Context(), req.DeviceCode)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+	if d == nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "Invalid device_code")
+		return
+	}
+	if client == nil || client.ClientID != d.ClientID {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "Client ID mismatch")
+		return
+	}
+	if d.IsExpired() {
+		writeOAuthError(w, http.StatusBadRequest, "expired_token", "The device code has expired")
+		return
+	}
+	if d.Status == grantstore.DeviceAuthorizationPending {
+		if d.LastPolledAt != nil && time.Since(*d.LastPolledAt) < grantstore.DeviceCodeInterval {
+			writeOAuthError(w, http.StatusBadRequest, "slow_down", "Polling too frequently")
+			return
+		}
+		writeOAuthError(w, http.StatusBadRequest, "authorization_pending", "The user has not yet approved the request")
+		return
+	}
+
+	// Decided: redeem it exactly once.
+	d, err = s.DeviceCodes.Consume(r.Context(), req.DeviceCode)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+	if d == nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "Invalid device_code")
+		return
+	}
+	if d.Status == grantstore.DeviceAuthorizationDenied || d.PrincipalID == nil {
+		writeOAuthError(w, http.StatusBadRequest, "access_denied", "The user denied the request")
+		return
+	}
+
+	p, err := s.Principals.FindByID(r.Context(), *d.PrincipalID)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+	if p == nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "Principal not found")
+		return
+	}
+	if !p.Active {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "Account is not active")
+		return
+	}
+	s.issueUserTokens(w, r, p, client, d.ClientID, derefOr(d.Scope, ""), nil)
+}
+
+// ─── verification page ──────────────────────────────────────────────────
+
+// RegisterDeviceVerificationRoutes mounts GET/POST /oauth/device. Like
+// /oauth/authorize it MUST be mounted OUTSIDE the session-auth
+// middleware: a missing session redirects to login rather than 401ing.
+func (s *State) RegisterDeviceVerificationRoutes(r chi.Router) {
+	r.Get(deviceVerificationPath, s.DeviceVerification)
+	r.Post(deviceVerificationPath, s.DeviceDecision)
+}
+
+// DeviceVerification is GET /oauth/device: the page where a signed-in
+// user enters (or, via verification_uri_complete, arrives with) a user
+// code and is shown what they are about to approve.
+func (s *State) DeviceVerification(w http.ResponseWriter, r *http.Request) {
+	userCode := r.URL.Query().Get("user_code")
+	subject, ok := s.sessionSubject(r)
+	if !ok {
+		s.redirectToLoginForDevice(w, r, userCode)
+		return
+	}
+	if strings.TrimSpace(userCode) == "" {
+		renderDevicePage(w, http.StatusOK, devicePage{Enter: true})
+		return
+	}
+	d, ok := s.findUserCode(w, r, subject, userCode)
+	if !ok {
+		return
+	}
+	clientName := d.ClientID
+	if c, err := s.OAuthClients.FindByClientID(r.Context(), d.ClientID); err == nil && c != nil && c.ClientName != "" {
+		clientName = c.ClientName
+	}
+	renderDevicePage(w, http.StatusOK, devicePage{
+		Confirm:    true,
+		UserCode:   grantstore.FormatUserCode(d.UserCode),
+		ClientName: clientName,
+		Scopes:     strings.Fields(derefOr(d.Scope, "")),
+	})
+}
+
+// DeviceDecision is POST /oauth/device: records the signed-in user's
+// approve/deny for a user code.
+func (s *State) DeviceDecision(w http.ResponseWriter, r *http.Request) {
+	if !sameOriginFormPost(r) {
+		renderDevicePage(w, http.StatusForbidden, devicePage{Message: "This request did not come from this site."})
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		renderDevicePage(w, http.StatusBadRequest, devicePage{Enter: true, Error: "Malformed request."})
+		return
+	}
+	userCode := r.PostFormValue("user_code")
+	subject, ok := s.sessionSubject(r)
+	if !ok {
+		s.redirectToLoginForDevice(w, r, userCode)
+		return
+	}
+	if _, ok := s.findUserCode(w, r, subject, userCode); !ok {
+		return
+	}
+	approve := r.PostFormValue("action") == "approve"
+	decided, err := s.DeviceCodes.Decide(r.Context(), grantstore.NormalizeUserCode(userCode), subject, approve)
+	if err != nil {
+		slog.Error("device verification: decision failed", "err", err)
+		renderDevicePage(w, http.StatusInternalServerError, devicePage{Message: "Something went wrong. Please try again."})
+		return
+	}
+	if !decided {
+		renderDevicePage(w, http.StatusNotFound, devicePage{Enter: true, Error: "That code is invalid, has expired, or was already used."})
+		return
+	}
+	if approve {
+		renderDevicePage(w, http.StatusOK, devicePage{Message: "Device approved. You can return to your device."})
+		return
+	}
+	renderDevicePage(w, http.StatusOK, devicePage{Message: "Request denied. The device was not signed in."})
+}
+
+// findUserCode looks up the pending authorization for a typed user code,
+// rendering the page and returning false when there is none to show.
+// Lookups are rate limited per IP and per principal, and each miss is
+// counted against the principal (see deviceUserCodeMaxMisses).
+func (s *State) findUserCode(w http.ResponseWriter, r *http.Request, subject, userCode string) (*grantstore.DeviceAuthorization, bool) {
+	ctx := r.Context()
+	policies := s.RateLimitPolicies.Get()
+	if ip := ratelimit.ClientIP(r); ip != "" {
+		if rej := ratelimit.Enforce(ctx, s.RateLimit, ratelimit.BucketDeviceVerifyIP, ip, policies.DeviceVerifyIP); rej != nil {
+			renderTooManyDeviceAttempts(w, rej.RetryAfterSecs)
+			return nil, false
+		}
+	}
+	if rej := ratelimit.Enforce(ctx, s.RateLimit, ratelimit.BucketDeviceVerifyPrincipal, subject, policies.DeviceVerifyPrincipal); rej != nil {
+		renderTooManyDeviceAttempts(w, rej.RetryAfterSecs)
+		return nil, false
+	}
+
+	code := grantstore.NormalizeUserCode(userCode)
+	d, err := s.DeviceCodes.FindPendingByUserCode(ctx, code)
+	if err != nil {
+		slog.Error("device verification: lookup failed", "err", err)
+		renderDevicePage(w, http.StatusInternalServerError, devicePage{Message: "Something went wrong. Please try again."})
+		return nil, false
+	}
+	if d == nil {
+		if rej := ratelimit.Enforce(ctx, s.RateLimit, ratelimit.BucketDeviceUserCodeMiss, subject, deviceUserCodeMissPolicy); rej != nil {
+			renderTooManyDeviceAttempts(w, rej.RetryAfterSecs)
+			return nil, false
+		}
+		renderDevicePage(w, http.StatusNotFound, devicePage{Enter: true, Error: "That code is invalid or has expired."})
+		return nil, false
+	}
+	if ratelimit.Exhausted(ctx, s.RateLimit, ratelimit.BucketDeviceUserCodeMiss, subject, deviceUserCodeMissPolicy) {
+		// Found only after repeated misses: deny it, so the device that
+		// asked is refused rather than signed in to a guesser.
+		if _, err := s.DeviceCodes.Decide(ctx, code, subject, false); err != nil {
+			slog.Error("device verification: invalidating guessed code failed", "err", err)
+		}
+		slog.Warn("device verification: user code found after repeated misses; denied",
+			"principal_id", subject, "client_id", d.ClientID)
+		renderTooManyDeviceAttempts(w, 0)
+		return nil, false
+	}
+	return d, true
+}
+
+// renderTooManyDeviceAttempts is the 429 page for a throttled lookup.
+func renderTooManyDeviceAttempts(w http.ResponseWriter, retryAfterSecs uint32) {
+	if retryAfterSecs > 0 {
+		w.Header().Set("Retry-After", strconv.FormatUint(uint64(retryAfterSecs), 10))
+	}
+	renderDevicePage(w, http.StatusTooManyRequests, devicePage{Message: "Too many attempts. Please wait a few minutes and start again from your device."})
+}
+
+// sessionSubject resolves the signed-in principal from the session cookie
+// (or bearer token), for the device and consent pages.
+func (s *State) sessionSubject(r *http.Request) (string, bool) {
+	tok := s.sessionToken(r)
+	if tok == "" || s.ValidateSession == nil {
+		return "", false
+	}
+	subject, _, ok := s.ValidateSession(tok)
+	return subject, ok
+}
+
+// redirectToLoginForDevice bounces to the SPA login, which returns to
+// the verification page (with the user code) after sign-in.
+func (s *State) redirectToLoginForDevice(w http.ResponseWriter, r *http.Request, userCode string) {
+	loginURL := "/auth/login?device=true"
+	if userCode != "" {
+		loginURL += "&user_code=" + pctEncode(userCode)
+	}
+	http.Redirect(w, r, loginURL, http.StatusSeeOther)
+}
+
+// sameOriginPost rejects a cross-site form POST. The session cookie is
+// SameSite=Lax, so browsers already withhold it from cross-site POSTs;
+// this is the belt to that pair of braces. A request without an Origin
+// header (older browsers, non-browser clients) is allowed.
+func sameOriginPost(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	return sameHost(origin, r)
+}
+
+// sameOriginFormPost is sameOriginPost for the device decision, which only
+// a browser form makes: the Origin header, or the Referer when a browser
+// sends no Origin, must name this host. A request with neither is refused.
+func sameOriginFormPost(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Header.Get("Referer")
+	}
+	return source != "" && sameHost(source, r)
+}
+
+func sameHost(rawURL string, r *http.Request) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// devicePage is the view model for devicePageTmpl.
+type devicePage struct {
+	Enter      bool
+	Confirm    bool
+	Error      string
+	Message    string
+	UserCode   string
+	ClientName string
+	Scopes     []string
+}
+
+var devicePageTmpl = template.Must(template.New("device").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Device sign-in · FlowCatalyst</title>
+<style>
+body{font-family:system-ui,sans-serif;background:#f6f7f9;color:#1f2933;display:flex;justify-content:center;padding:4rem 1rem}
+main{background:#fff;border-radius:8px;box-shadow:0 1px 3px rgba(0,0,0,.1);padding:2rem;max-width:26rem;width:100%}
+h1{font-size:1.25rem;margin:0 0 1rem}
+input{font-size:1.25rem;letter-spacing:.15em;text-transform:uppercase;padding:.5rem;width:100%;box-sizing:border-box}
+button{font-size:1rem;padding:.5rem 1rem;margin:1rem .5rem 0 0;cursor:pointer}
+.code{font-family:ui-monospace,monospace;font-size:1.5rem;letter-spacing:.15em}
+.error{color:#b42318}
+</style>
+</head>
+<body><main>
+<h1>Device sign-in</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{if .Enter}}
+<form method="get" action="/oauth/device">
+<label for="user_code">Enter the code shown on your device</label>
+<input id="user_code" name="user_code" autocomplete="off" autofocus required>
+<button type="submit">Continue</button>
+</form>
+{{end}}
+{{if .Confirm}}
+<p><strong>{{.ClientName}}</strong> is asking to sign in as you.</p>
+<p>Only continue if your device shows this code:</p>
+<p class="code">{{.UserCode}}</p>
+{{if .Scopes}}<p>Requested access:</p><ul>{{range .Scopes}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<form method="post" action="/oauth/device">
+<input type="hidden" name="user_code" value="{{.UserCode}}">
+<button type="submit" name="action" value="approve">Approve</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+{{end}}
+</main></body>
+</html>
+`))
+
+func renderDevicePage(w http.ResponseWriter, status int, p devicePage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// The consent form must not be framed by another site (clickjacking).
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'; frame-ancestors 'none'")
+	w.WriteHeader(status)
+	if err := devicePageTmpl.Execute(w, p); err != nil {
+		slog.Error("device verification: render failed", "err", err)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build integration
+
+package oauthapi
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// A live code entered only after a run of misses is treated as guessed:
+// it is denied, so the device polling it is refused.
+func TestDeviceVerification_CodeFoundAfterMissesIsDenied(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	codes := grantstore.NewDeviceCodeRepository(pool)
+	raw, d, err := grantstore.NewDeviceAuthorization("cli", nil)
+	require.NoError(t, err)
+	inserted, err := codes.Insert(ctx, d)
+	require.NoError(t, err)
+	require.True(t, inserted)
+
+	subject := tsid.Generate(tsid.Principal)
+	s := &State{
+		OAuthClients:      fakeClientFinder{},
+		DeviceCodes:       codes,
+		RateLimit:         ratelimit.NewPostgresStore(pool),
+		RateLimitPolicies: ratelimit.NewLivePolicies(ratelimit.PoliciesFromEnv()),
+		ValidateSession:   func(string) (string, time.Time, bool) { return subject, time.Now(), true },
+	}
+	lookup := func(code string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/oauth/device?user_code="+code, nil)
+		req.Header.Set("Authorization", "Bearer session")
+		s.DeviceVerification(rec, req)
+		return rec.Code
+	}
+
+	for range deviceUserCodeMaxMisses {
+		assert.Equal(t, 404, lookup("XXXX-XXXX"))
+	}
+	assert.Equal(t, 429, lookup(grantstore.FormatUserCode(d.UserCode)))
+
+	polled, err := codes.Poll(ctx, raw)
+	require.NoError(t, err)
+	require.NotNil(t, polled)
+	assert.Equal(t, grantstore.DeviceAuthorizationDenied, polled.Status)
+
+	assert.Equal(t, 429, lookup("XXXX-XXXX"), "further misses are refused")
+}
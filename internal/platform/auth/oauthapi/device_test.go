@@ -0,0 +1,175 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+)
+
+func oauthErrorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal %q: %v", rec.Body.String(), err)
+	}
+	code, _ := body["error"].(string)
+	return code
+}
+
+// The device_authorization leg rejects before touching the device-code
+// store: unknown clients, clients not allowed the grant, and scopes the
+// client doesn't carry.
+func TestDeviceAuthorization_Rejections(t *testing.T) {
+	publicCLI := &auth.OAuthClient{ClientID: "cli", Active: true, Scopes: []string{"platform:messaging:event:view"}}
+	codeOnly := &auth.OAuthClient{ClientID: "web", Active: true, GrantTypes: []string{"authorization_code"}}
+	cases := []struct {
+		name   string
+		client *auth.OAuthClient
+		form   url.Values
+		status int
+		code   string
+	}{
+		{"unknown client", nil, url.Values{"client_id": {"nope"}}, 401, "invalid_client"},
+		{"missing client", nil, url.Values{}, 401, "invalid_client"},
+		{"grant not allowed", codeOnly, url.Values{"client_id": {"web"}}, 400, "unauthorized_client"},
+		{"scope not on client", publicCLI, url.Values{"client_id": {"cli"}, "scope": {"openid platform:admin:all"}}, 400, "invalid_scope"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &State{OAuthClients: fakeClientFinder{client: tc.client}}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/oauth/device_authorization", strings.NewReader(tc.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			s.DeviceAuthorization(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tc.status, rec.Body.String())
+			}
+			if got := oauthErrorCode(t, rec); got != tc.code {
+				t.Errorf("error = %q, want %q", got, tc.code)
+			}
+		})
+	}
+}
+
+func TestDeviceCodeGrant_MissingDeviceCode(t *testing.T) {
+	s := &State{OAuthClients: fakeClientFinder{client: &auth.OAuthClient{ClientID: "cli", Active: true}}}
+	form := url.Values{"grant_type": {deviceCodeGrantType}, "client_id": {"cli"}}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.Token(rec, req)
+	if rec.Code != 400 || oauthErrorCode(t, rec) != "invalid_request" {
+		t.Fatalf("status = %d body = %s, want 400 invalid_request", rec.Code, rec.Body.String())
+	}
+}
+
+// Without a session the verification page sends the user to the SPA login,
+// which hands the user code back after sign-in.
+func TestDeviceVerification_NoSessionRedirectsToLogin(t *testing.T) {
+	s := &State{}
+	rec := httptest.NewRecorder()
+	s.DeviceVerification(rec, httptest.NewRequest("GET", "/oauth/device?user_code=BCDF-GHJK", nil))
+	if rec.Code != 303 {
+		t.Fatalf("status = %d, want 303", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/auth/login?device=true&user_code=BCDF-GHJK" {
+		t.Errorf("Location = %q", loc)
+	}
+}
+
+func TestDeviceVerification_EntryFormWhenNoCode(t *testing.T) {
+	s := &State{ValidateSession: func(string) (string, time.Time, bool) { return "prn_x", time.Now(), true }}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/oauth/device", nil)
+	req.Header.Set("Authorization", "Bearer session")
+	s.DeviceVerification(rec, req)
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `name="user_code"`) {
+		t.Errorf("entry form missing: %s", rec.Body.String())
+	}
+	if rec.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("verification page must not be frameable")
+	}
+}
+
+func TestDeviceDecision_RejectsCrossOriginPost(t *testing.T) {
+	s := &State{}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "https://fc.example/oauth/device", strings.NewReader("user_code=BCDF-GHJK&action=approve"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://evil.example")
+	s.DeviceDecision(rec, req)
+	if rec.Code != 403 {
+		t.Fatalf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestDeviceDecision_RequiresOriginOrReferer(t *testing.T) {
+	post := func(header, value string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "https://fc.example/oauth/device", strings.NewReader("user_code=BCDF-GHJK&action=approve"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		(&State{}).DeviceDecision(rec, req)
+		return rec.Code
+	}
+	if got := post("", ""); got != 403 {
+		t.Errorf("no Origin or Referer: status = %d, want 403", got)
+	}
+	if got := post("Referer", "https://evil.example/page"); got != 403 {
+		t.Errorf("cross-site Referer: status = %d, want 403", got)
+	}
+	// Same-site gets past the check (and, with no session, to login).
+	if got := post("Referer", "https://fc.example/oauth/device?user_code=BCDF-GHJK"); got != 303 {
+		t.Errorf("same-site Referer: status = %d, want 303", got)
+	}
+}
+
+// limitedStore rejects everything in one bucket.
+type limitedStore struct{ bucket ratelimit.Bucket }
+
+func (l limitedStore) decide(b ratelimit.Bucket) (ratelimit.Decision, error) {
+	return ratelimit.Decision{Allowed: b != l.bucket, RetryAfterSecs: 30}, nil
+}
+
+func (l limitedStore) CheckAndRecord(_ context.Context, b ratelimit.Bucket, _ string, _ ratelimit.Policy) (ratelimit.Decision, error) {
+	return l.decide(b)
+}
+
+func (l limitedStore) Check(_ context.Context, b ratelimit.Bucket, _ string, _ ratelimit.Policy) (ratelimit.Decision, error) {
+	return l.decide(b)
+}
+
+func (limitedStore) Prune(context.Context, time.Duration) (int64, error) { return 0, nil }
+
+// User-code lookups are throttled per IP and per principal before the
+// device-code store is touched.
+func TestDeviceVerification_RateLimited(t *testing.T) {
+	for _, bucket := range []ratelimit.Bucket{ratelimit.BucketDeviceVerifyIP, ratelimit.BucketDeviceVerifyPrincipal} {
+		s := &State{
+			ValidateSession: func(string) (string, time.Time, bool) { return "prn_x", time.Now(), true },
+			RateLimit:       limitedStore{bucket: bucket},
+		}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/oauth/device?user_code=BCDF-GHJK", nil)
+		req.Header.Set("Authorization", "Bearer session")
+		s.DeviceVerification(rec, req)
+		if rec.Code != 429 {
+			t.Fatalf("%s: status = %d, want 429", bucket, rec.Code)
+		}
+		if rec.Header().Get("Retry-After") != "30" {
+			t.Errorf("%s: Retry-After = %q", bucket, rec.Header().Get("Retry-After"))
+		}
+	}
+}
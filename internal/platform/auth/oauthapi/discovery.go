@@ -14,9 +14,11 @@ func (s *State) RegisterDiscoveryRoutes(r chi.Router) {
 
 // openIDConfiguration is the OIDC discovery document, transcribed
 // field-for-field from well_known_api.rs::OpenIdConfiguration. The
-// endpoint URLs + advertised capabilities mirror Rust exactly (including
-// end_session_endpoint and the hybrid response_types, which the token
-// endpoint itself does not implement — the advertised list matches Rust).
+// endpoint URLs + advertised capabilities mirror Rust (including the
+// hybrid response_types, which the token endpoint itself does not
+// implement — the advertised list matches Rust), except that
+// end_session_endpoint is /oauth/logout and back-channel logout is
+// advertised.
 type openIDConfiguration struct {
 	Issuer                            string   `json:"issuer"`
 	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
@@ -25,17 +27,21 @@ type openIDConfiguration struct {
 	EndSessionEndpoint                string   `json:"end_session_endpoint,omitempty"`
 	IntrospectionEndpoint             string   `json:"introspection_endpoint,omitempty"`
 	RevocationEndpoint                string   `json:"revocation_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint,omitempty"`
 	JwksURI                           string   `json:"jwks_uri"`
 	ResponseTypesSupported            []string `json:"response_types_supported"`
 	SubjectTypesSupported             []string `json:"subject_types_supported"`
 	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
 	ScopesSupported                   []string `json:"scopes_supported"`
 	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	TokenEndpointAuthSigningAlgValues []string `json:"token_endpoint_auth_signing_alg_values_supported"`
 	GrantTypesSupported               []string `json:"grant_types_supported"`
 	ClaimsSupported                   []string `json:"claims_supported"`
 	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
 	RequestParameterSupported         bool     `json:"request_parameter_supported"`
 	RequestURIParameterSupported      bool     `json:"request_uri_parameter_supported"`
+	BackchannelLogoutSupported        bool     `json:"backchannel_logout_supported"`
+	BackchannelLogoutSessionSupported bool     `json:"backchannel_logout_session_supported"`
 }
 
 // OpenIDConfiguration serves GET /.well-known/openid-configuration.
@@ -46,10 +52,12 @@ func (s *State) OpenIDConfiguration(w http.ResponseWriter, _ *http.Request) {
 		AuthorizationEndpoint: base + "/oauth/authorize",
 		TokenEndpoint:         base + "/oauth/token",
 		UserinfoEndpoint:      base + "/oauth/userinfo",
-		EndSessionEndpoint:    base + "/auth/oidc/session/end",
+		EndSessionEndpoint:    base + "/oauth/logout",
 		IntrospectionEndpoint: base + "/oauth/introspect",
 		RevocationEndpoint:    base + "/oauth/revoke",
-		JwksURI:               base + "/.well-known/jwks.json",
+		// RFC 8628 §4 — not in the Rust document.
+		DeviceAuthorizationEndpoint: base + "/oauth/device_authorization",
+		JwksURI:                     base + "/.well-known/jwks.json",
 		ResponseTypesSupported: []string{
 			"code", "token", "id_token",
 			"code token", "code id_token", "token id_token",
@@ -59,19 +67,28 @@ func (s *State) OpenIDConfiguration(w http.ResponseWriter, _ *http.Request) {
 		IDTokenSigningAlgValuesSupported: []string{"RS256"},
 		ScopesSupported:                  []string{"openid", "profile", "email", "offline_access"},
 		TokenEndpointAuthMethodsSupported: []string{
-			"client_secret_basic", "client_secret_post",
+			"client_secret_basic", "client_secret_post", "private_key_jwt",
+		},
+		// private_key_jwt assertions; asymmetric only (auth.ValidateClientJWKS).
+		TokenEndpointAuthSigningAlgValues: []string{
+			"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA",
 		},
 		GrantTypesSupported: []string{
 			"authorization_code", "refresh_token", "client_credentials",
+			deviceCodeGrantType,
 		},
 		ClaimsSupported: []string{
 			"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce",
-			"name", "email", "email_verified", "acr", "amr", "azp",
-			"type", "scope", "client_id", "roles", "applications", "clients",
+			"name", "given_name", "family_name", "picture", "updated_at",
+			"email", "email_verified", "acr", "amr", "azp",
+			"type", "scope", "client_id", "roles", RolesClaim, "applications", "clients",
 		},
 		CodeChallengeMethodsSupported: []string{"S256", "plain"},
 		RequestParameterSupported:     false,
 		RequestURIParameterSupported:  false,
+		// OIDC Back-Channel Logout 1.0: logout tokens carry sub, not sid.
+		BackchannelLogoutSupported:        true,
+		BackchannelLogoutSessionSupported: false,
 	})
 }
 
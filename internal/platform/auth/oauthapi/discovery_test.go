@@ -58,14 +58,23 @@ func TestOpenIDConfiguration(t *testing.T) {
 	if doc["userinfo_endpoint"] != "https://fc.example/oauth/userinfo" {
 		t.Errorf("userinfo_endpoint = %v", doc["userinfo_endpoint"])
 	}
-	if doc["end_session_endpoint"] != "https://fc.example/auth/oidc/session/end" {
+	if doc["end_session_endpoint"] != "https://fc.example/oauth/logout" {
 		t.Errorf("end_session_endpoint = %v", doc["end_session_endpoint"])
 	}
 
 	assertContains(t, doc, "grant_types_supported", "authorization_code")
 	assertContains(t, doc, "grant_types_supported", "refresh_token")
 	assertContains(t, doc, "grant_types_supported", "client_credentials")
+	assertContains(t, doc, "grant_types_supported", "urn:ietf:params:oauth:grant-type:device_code")
+	if doc["device_authorization_endpoint"] != "https://fc.example/oauth/device_authorization" {
+		t.Errorf("device_authorization_endpoint = %v", doc["device_authorization_endpoint"])
+	}
 	assertContains(t, doc, "scopes_supported", "offline_access")
+	assertContains(t, doc, "claims_supported", "updated_at")
+	assertContains(t, doc, "claims_supported", RolesClaim)
+	if doc["backchannel_logout_supported"] != true {
+		t.Errorf("backchannel_logout_supported = %v", doc["backchannel_logout_supported"])
+	}
 	assertContains(t, doc, "code_challenge_methods_supported", "S256")
 	assertContains(t, doc, "code_challenge_methods_supported", "plain")
 	assertContains(t, doc, "response_types_supported", "code id_token")
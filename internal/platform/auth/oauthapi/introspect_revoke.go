@@ -1,13 +1,20 @@
 package oauthapi
 
 import (
+	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/authservice"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
 // RegisterIntrospectRoutes mounts POST /oauth/introspect.
@@ -20,25 +27,91 @@ func (s *State) RegisterRevokeRoutes(r chi.Router) {
 	r.Post("/oauth/revoke", s.Revoke)
 }
 
+// oauthCaller is who called introspect/revoke: an authenticated OAuth
+// client, or the principal behind a Bearer access token.
+type oauthCaller struct {
+	client  *auth.OAuthClient
+	subject string
+}
+
+// principalID is the caller's principal for audit rows: the bearer's
+// subject, or the client's owning (service-account) principal.
+func (c oauthCaller) principalID() *string {
+	if c.subject != "" {
+		return &c.subject
+	}
+	if c.client != nil {
+		return c.client.PrincipalID
+	}
+	return nil
+}
+
+// mayUseRefreshToken reports whether the caller may introspect or revoke
+// a refresh token: a client only its own tokens (RFC 7009 §2.1), a
+// bearer only tokens issued to the same principal.
+func (c oauthCaller) mayUseRefreshToken(t *grantstore.RefreshToken) bool {
+	if c.client != nil {
+		return t.OAuthClientID == nil || *t.OAuthClientID == c.client.ClientID
+	}
+	return c.subject != "" && c.subject == t.PrincipalID
+}
+
+// mayRevokeAccessToken reports whether the caller may revoke an access
+// token. Access tokens don't record the OAuth client they were issued
+// to, so only the token's own principal may revoke it — as a bearer, or
+// through a client whose service account it is.
+func (c oauthCaller) mayRevokeAccessToken(claims *authservice.AccessTokenClaims) bool {
+	if c.subject != "" {
+		return c.subject == claims.Subject
+	}
+	return c.client != nil && c.client.PrincipalID != nil && *c.client.PrincipalID == claims.Subject
+}
+
 // authenticateClientOrBearer authorizes a call to a protected OAuth
 // endpoint (introspect/revoke) via either a valid Bearer access token or
 // client credentials (Basic header / body). Mirrors Rust's
-// authenticate_client_or_bearer; the resolved identity is unused by the
-// callers, so only the error (nil = authorized) is returned.
-func (s *State) authenticateClientOrBearer(r *http.Request, clientIDBody, clientSecretBody string) *oauthError {
+// authenticate_client_or_bearer, and returns who the caller is so the
+// endpoints can bind refresh tokens to the client they were issued to.
+func (s *State) authenticateClientOrBearer(r *http.Request, clientIDBody, clientSecretBody string) (oauthCaller, *oauthError) {
 	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
 		if token := authservice.ExtractBearerToken(authHeader); token != "" {
-			if _, err := s.Auth.ValidateToken(token); err != nil {
-				return newOAuthError(http.StatusUnauthorized, "invalid_token", "Token is invalid or expired")
+			claims, err := s.validateAccessToken(r.Context(), token)
+			if err != nil || claims == nil {
+				return oauthCaller{}, newOAuthError(http.StatusUnauthorized, "invalid_token", "Token is invalid or expired")
 			}
-			return nil
+			return oauthCaller{subject: claims.Subject}, nil
 		}
 		// A non-Bearer scheme (Basic ...) falls through to client auth.
 	}
-	if _, errResp := s.authenticateClient(r, clientIDBody, clientSecretBody); errResp != nil {
-		return errResp
+	client, errResp := s.authenticateClient(r, clientIDBody, clientSecretBody)
+	if errResp != nil {
+		return oauthCaller{}, errResp
 	}
-	return nil
+	return oauthCaller{client: client}, nil
+}
+
+// validateAccessToken verifies a platform JWT and, when the revocation
+// list is wired, rejects a revoked one. Returns (nil, nil) for a revoked
+// token; a revocation-store error is returned (fail closed).
+func (s *State) validateAccessToken(ctx context.Context, token string) (*authservice.AccessTokenClaims, error) {
+	claims, err := s.Auth.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if s.Revocations != nil {
+		var iat time.Time
+		if claims.IssuedAt != nil {
+			iat = claims.IssuedAt.Time
+		}
+		revoked, err := s.Revocations.Revoked(ctx, claims.Subject, claims.ID, iat)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, nil
+		}
+	}
+	return claims, nil
 }
 
 // introspectResponse is the RFC 7662 result. Every field except `active`
@@ -58,27 +131,53 @@ type introspectResponse struct {
 	TokenType     *string `json:"token_type,omitempty"`
 }
 
+// refreshTokenTypeHint is the RFC 7009 token_type_hint for refresh tokens.
+const refreshTokenTypeHint = "refresh_token"
+
 // Introspect is POST /oauth/introspect (RFC 7662). It authenticates the
-// caller, then echoes the token's claims when valid or {active:false}
-// otherwise — always 200.
+// caller, then describes the token — a platform JWT access token or an
+// opaque refresh token — when it is active, or answers {active:false}
+// otherwise. Always 200. A refresh token only introspects as active for
+// the client it was issued to (or a bearer of the same principal), so
+// one client can't probe another's tokens.
 func (s *State) Introspect(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Malformed form body")
 		return
 	}
-	if errResp := s.authenticateClientOrBearer(r, r.PostFormValue("client_id"), r.PostFormValue("client_secret")); errResp != nil {
+	caller, errResp := s.authenticateClientOrBearer(r, r.PostFormValue("client_id"), r.PostFormValue("client_secret"))
+	if errResp != nil {
 		errResp.write(w)
 		return
 	}
+	token := r.PostFormValue("token")
 
-	claims, err := s.Auth.ValidateToken(r.PostFormValue("token"))
-	if err != nil {
-		// RFC 7662: an inactive/unknown token is reported, not errored.
-		writeJSON(w, http.StatusOK, introspectResponse{Active: false})
-		return
+	// The hint only orders the lookups (RFC 7662 §2.1); both kinds are tried.
+	lookups := []func() *introspectResponse{
+		func() *introspectResponse { return s.introspectAccessToken(r.Context(), token) },
+		func() *introspectResponse { return s.introspectRefreshToken(r.Context(), caller, token) },
 	}
+	if r.PostFormValue("token_type_hint") == refreshTokenTypeHint {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+	for _, lookup := range lookups {
+		if resp := lookup(); resp != nil {
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+	}
+	// RFC 7662: an inactive/unknown token is reported, not errored.
+	writeJSON(w, http.StatusOK, introspectResponse{Active: false})
+}
 
-	resp := introspectResponse{
+// introspectAccessToken describes a valid, unrevoked platform JWT, or
+// returns nil.
+func (s *State) introspectAccessToken(ctx context.Context, token string) *introspectResponse {
+	claims, err := s.validateAccessToken(ctx, token)
+	if err != nil || claims == nil {
+		return nil
+	}
+	resp := &introspectResponse{
 		Active:        true,
 		Sub:           ptr(claims.Subject),
 		Tier:          ptr(claims.Tier),
@@ -101,30 +200,130 @@ func (s *State) Introspect(w http.ResponseWriter, r *http.Request) {
 	if claims.IssuedAt != nil {
 		resp.Iat = ptr(claims.IssuedAt.Unix())
 	}
-	writeJSON(w, http.StatusOK, resp)
+	return resp
 }
 
-// Revoke is POST /oauth/revoke (RFC 7009). It authenticates the caller
-// then best-effort revokes the token's refresh-token row (JWT access
-// tokens are stateless and can't be revoked server-side). Always 200.
+// introspectRefreshToken describes a live refresh token the caller may
+// see, or returns nil. token_type is "refresh_token" so a resource server
+// can't mistake it for a bearer credential.
+func (s *State) introspectRefreshToken(ctx context.Context, caller oauthCaller, token string) *introspectResponse {
+	if token == "" || s.RefreshTokens == nil {
+		return nil
+	}
+	stored, err := s.RefreshTokens.FindValidByHash(ctx, grantstore.HashToken(token))
+	if err != nil || stored == nil || !caller.mayUseRefreshToken(stored) {
+		return nil
+	}
+	resp := &introspectResponse{
+		Active:    true,
+		Sub:       ptr(stored.PrincipalID),
+		ClientID:  stored.OAuthClientID,
+		Exp:       ptr(stored.ExpiresAt.Unix()),
+		Iat:       ptr(stored.CreatedAt.Unix()),
+		Iss:       ptr(s.BaseURL),
+		TokenType: ptr(refreshTokenTypeHint),
+	}
+	if len(stored.Scopes) > 0 {
+		resp.Scope = ptr(strings.Join(stored.Scopes, " "))
+	}
+	return resp
+}
+
+// Revoke is POST /oauth/revoke (RFC 7009). It authenticates the caller,
+// then revokes the token: a refresh token by its hash, or a platform JWT
+// access token by putting its jti on the revocation list (when wired).
+// RFC 7009 mandates 200 for an unknown or already-invalid token; a token
+// the caller isn't allowed to revoke is refused with unauthorized_client.
+// Every revocation is written to the audit log.
 func (s *State) Revoke(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Malformed form body")
 		return
 	}
-	if errResp := s.authenticateClientOrBearer(r, r.PostFormValue("client_id"), r.PostFormValue("client_secret")); errResp != nil {
+	caller, errResp := s.authenticateClientOrBearer(r, r.PostFormValue("client_id"), r.PostFormValue("client_secret"))
+	if errResp != nil {
 		errResp.write(w)
 		return
 	}
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Missing token parameter")
+		return
+	}
 
-	// Whether or not the hint says refresh_token, the only revocable
-	// artifact is a refresh token (matched by hash). Best-effort; RFC 7009
-	// mandates 200 even when the token is unknown.
-	tokenHash := grantstore.HashToken(r.PostFormValue("token"))
-	_, _ = s.RefreshTokens.RevokeByHash(r.Context(), tokenHash)
+	// A refresh token is matched by hash first, whatever the hint says: an
+	// opaque token can never validate as a JWT, and a JWT never hashes to
+	// a stored refresh token, so the order only saves a lookup.
+	stored, err := s.RefreshTokens.FindByHash(r.Context(), grantstore.HashToken(token))
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+	if stored != nil {
+		if !caller.mayUseRefreshToken(stored) {
+			writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", "Token was not issued to this client")
+			return
+		}
+		revoked, err := s.RefreshTokens.RevokeByHash(r.Context(), stored.TokenHash)
+		if err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		if revoked {
+			s.auditRevocation(r.Context(), caller, "REFRESH_TOKEN", stored.ID, stored.PrincipalID, stored.OAuthClientID)
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if s.Revocations != nil {
+		if claims, err := s.validateAccessToken(r.Context(), token); err == nil && claims != nil && claims.ID != "" {
+			if !caller.mayRevokeAccessToken(claims) {
+				writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", "Token was not issued to this caller")
+				return
+			}
+			var exp time.Time
+			if claims.ExpiresAt != nil {
+				exp = claims.ExpiresAt.Time
+			}
+			if err := s.Revocations.RevokeToken(r.Context(), claims.Subject, claims.ID, exp, caller.principalID()); err != nil {
+				writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+				return
+			}
+			s.auditRevocation(r.Context(), caller, "ACCESS_TOKEN", claims.ID, claims.Subject, nil)
+		}
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
+// auditRevocation records a token revocation (best-effort: a logging miss
+// must not fail the revoke, which has already happened). The token itself
+// is never logged — only its row id (refresh) or jti (access).
+func (s *State) auditRevocation(ctx context.Context, caller oauthCaller, entityType, entityID, subject string, oauthClientID *string) {
+	if s.Audit == nil {
+		return
+	}
+	detail := map[string]any{"subject": subject}
+	if oauthClientID != nil {
+		detail["oauthClientId"] = *oauthClientID
+	}
+	if caller.client != nil {
+		detail["revokedByClientId"] = caller.client.ClientID
+	}
+	opJSON, _ := json.Marshal(detail)
+	if err := s.Audit.Insert(ctx, &audit.Log{
+		ID:            tsid.Generate(tsid.AuditLog),
+		EntityType:    entityType,
+		EntityID:      entityID,
+		Operation:     "OAUTH_TOKEN_REVOKED",
+		OperationJSON: opJSON,
+		PrincipalID:   caller.principalID(),
+		PerformedAt:   time.Now().UTC(),
+	}); err != nil {
+		slog.Warn("audit of token revocation failed", "entityType", entityType, "entityId", entityID, "err", err)
+	}
+}
+
 func ptr[T any](v T) *T { return &v }
 
 // writeJSON renders v with the given status and Content-Type, without the
@@ -0,0 +1,122 @@
+package oauthapi
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/authservice"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+)
+
+func introspect(t *testing.T, s *State, form url.Values, bearer string) (*httptest.ResponseRecorder, introspectResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/oauth/introspect", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	s.Introspect(rec, req)
+	var body introspectResponse
+	if rec.Code == 200 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("unmarshal %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec, body
+}
+
+func TestIntrospect_RequiresCallerAuthentication(t *testing.T) {
+	s := &State{Auth: testAuthService(t), OAuthClients: fakeClientFinder{}}
+	rec, _ := introspect(t, s, url.Values{"token": {"anything"}}, "")
+	if rec.Code != 401 || oauthErrorCode(t, rec) != "invalid_client" {
+		t.Fatalf("status = %d body = %s, want 401 invalid_client", rec.Code, rec.Body.String())
+	}
+	rec, _ = introspect(t, s, url.Values{"token": {"anything"}}, "not-a-jwt")
+	if rec.Code != 401 || oauthErrorCode(t, rec) != "invalid_token" {
+		t.Fatalf("status = %d body = %s, want 401 invalid_token", rec.Code, rec.Body.String())
+	}
+}
+
+func TestIntrospect_AccessTokenAndUnknownToken(t *testing.T) {
+	svc := testAuthService(t)
+	token, err := svc.GenerateAccessToken(principal.NewUser("u@example.com", principal.ScopeClient))
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	s := &State{Auth: svc, OAuthClients: fakeClientFinder{client: &auth.OAuthClient{ClientID: "rs", Active: true}}}
+
+	_, body := introspect(t, s, url.Values{"client_id": {"rs"}, "token": {token}}, "")
+	if !body.Active || body.TokenType == nil || *body.TokenType != "Bearer" {
+		t.Errorf("access token: %+v, want active Bearer", body)
+	}
+	// The hint only reorders lookups — a mis-hinted JWT still introspects.
+	_, body = introspect(t, s, url.Values{"client_id": {"rs"}, "token": {token}, "token_type_hint": {"refresh_token"}}, "")
+	if !body.Active {
+		t.Errorf("mis-hinted access token reported inactive")
+	}
+	_, body = introspect(t, s, url.Values{"client_id": {"rs"}, "token": {"opaque-unknown"}}, "")
+	if body.Active {
+		t.Errorf("unknown token reported active")
+	}
+}
+
+// A refresh token is only visible to (and revocable by) the client it was
+// issued to, or a bearer of the same principal.
+func TestOAuthCaller_RefreshTokenBinding(t *testing.T) {
+	owner := "oac_owner"
+	bound := &grantstore.RefreshToken{PrincipalID: "prn_a", OAuthClientID: &owner}
+	unbound := &grantstore.RefreshToken{PrincipalID: "prn_a"}
+	cases := []struct {
+		name   string
+		caller oauthCaller
+		token  *grantstore.RefreshToken
+		want   bool
+	}{
+		{"issuing client", oauthCaller{client: &auth.OAuthClient{ClientID: owner}}, bound, true},
+		{"other client", oauthCaller{client: &auth.OAuthClient{ClientID: "oac_other"}}, bound, false},
+		{"any client, unbound token", oauthCaller{client: &auth.OAuthClient{ClientID: "oac_other"}}, unbound, true},
+		{"same principal bearer", oauthCaller{subject: "prn_a"}, bound, true},
+		{"other principal bearer", oauthCaller{subject: "prn_b"}, bound, false},
+	}
+	for _, tc := range cases {
+		if got := tc.caller.mayUseRefreshToken(tc.token); got != tc.want {
+			t.Errorf("%s: mayUseRefreshToken = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOAuthCaller_AccessTokenRevocation(t *testing.T) {
+	claims := &authservice.AccessTokenClaims{}
+	claims.Subject = "prn_sa"
+	sa := "prn_sa"
+	other := "prn_other"
+	if !(oauthCaller{subject: "prn_sa"}).mayRevokeAccessToken(claims) {
+		t.Error("the token's own bearer must be able to revoke it")
+	}
+	if !(oauthCaller{client: &auth.OAuthClient{PrincipalID: &sa}}).mayRevokeAccessToken(claims) {
+		t.Error("the service account's client must be able to revoke its token")
+	}
+	if (oauthCaller{client: &auth.OAuthClient{PrincipalID: &other}}).mayRevokeAccessToken(claims) {
+		t.Error("another client must not revoke the token")
+	}
+	if (oauthCaller{client: &auth.OAuthClient{}}).mayRevokeAccessToken(claims) {
+		t.Error("a client without a principal must not revoke access tokens")
+	}
+}
+
+func TestRevoke_MissingToken(t *testing.T) {
+	s := &State{Auth: testAuthService(t), OAuthClients: fakeClientFinder{client: &auth.OAuthClient{ClientID: "rs", Active: true}}}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/oauth/revoke", strings.NewReader("client_id=rs"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.Revoke(rec, req)
+	if rec.Code != 400 || oauthErrorCode(t, rec) != "invalid_request" {
+		t.Fatalf("status = %d body = %s, want 400 invalid_request", rec.Code, rec.Body.String())
+	}
+}
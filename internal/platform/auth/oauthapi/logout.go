@@ -0,0 +1,150 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/authservice"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// LogoutNotifier is the back-channel logout hook: it learns which
+// clients a principal signs in to and tells them when the principal logs
+// out. Satisfied by *backchannel.Notifier.
+type LogoutNotifier interface {
+	RecordLogin(ctx context.Context, principalID, clientID string)
+	LoggedOut(ctx context.Context, principalID string)
+}
+
+// RegisterLogoutRoutes mounts GET+POST /oauth/logout, the OIDC
+// RP-Initiated Logout 1.0 end_session_endpoint. Mount it outside the
+// auth middleware: the browser arrives from the relying party, possibly
+// with an expired session.
+func (s *State) RegisterLogoutRoutes(r chi.Router) {
+	r.Get("/oauth/logout", s.Logout)
+	r.Post("/oauth/logout", s.Logout)
+}
+
+// Logout is GET/POST /oauth/logout.
+//
+// id_token_hint, when sent, must be an ID token this server signed (an
+// expired one is fine); its aud identifies the client, and an explicit
+// client_id must agree with it. post_logout_redirect_uri is only honoured
+// when it is registered for that client. The parameters are checked
+// before anything is ended, so a bad request leaves the session alone.
+//
+// The signed-in session is then ended (revoked server-side and the
+// cookie cleared) unless the hint names a different user, and the
+// clients the user signed in to are sent back-channel logout tokens.
+func (s *State) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		_ = r.ParseForm()
+	}
+	param := func(k string) string { return r.FormValue(k) }
+	redirectURI := param("post_logout_redirect_uri")
+	clientID := param("client_id")
+
+	var hint *authservice.IDTokenClaims
+	if raw := param("id_token_hint"); raw != "" {
+		c, err := s.Auth.ValidateIDTokenHint(raw)
+		if err != nil {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request", "id_token_hint is not a valid ID token from this issuer")
+			return
+		}
+		if clientID != "" && clientID != c.Aud {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request", "client_id does not match the id_token_hint audience")
+			return
+		}
+		clientID = c.Aud
+		hint = c
+	}
+
+	target := ""
+	if redirectURI != "" {
+		if clientID == "" {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request", "id_token_hint or client_id is required with post_logout_redirect_uri")
+			return
+		}
+		client, err := s.OAuthClients.FindByClientID(r.Context(), clientID)
+		if err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		if client == nil || !client.Active || !MatchRedirectURI(redirectURI, client.PostLogoutRedirectURIs) {
+			slog.Warn("rejected post_logout_redirect_uri", "client_id", clientID, "redirect_uri", redirectURI)
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request", "post_logout_redirect_uri is not registered for this client")
+			return
+		}
+		target = redirectURI
+		if state := param("state"); state != "" {
+			sep := "?"
+			if strings.Contains(target, "?") {
+				sep = "&"
+			}
+			target += sep + "state=" + url.QueryEscape(state)
+		}
+	}
+
+	if tok := s.sessionToken(r); tok != "" && s.EndSession != nil {
+		if hint != nil {
+			// Leave another user's session alone: the relying party is
+			// logging out someone who is no longer signed in here.
+			if subject, ok := s.sessionSubject(r); ok && subject != hint.Subject {
+				tok = ""
+			}
+		}
+		if tok != "" {
+			if subject, ok := s.EndSession(r.Context(), tok); ok {
+				s.auditLogout(r.Context(), subject, clientID)
+				if s.Backchannel != nil {
+					s.Backchannel.LoggedOut(r.Context(), subject)
+				}
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     "fc_session",
+				Value:    "",
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   s.CookieSecure,
+				SameSite: http.SameSiteLaxMode,
+				MaxAge:   -1,
+			})
+		}
+	}
+
+	if target == "" {
+		writeJSON(w, http.StatusOK, map[string]string{"message": "Session ended"})
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther) //nolint:gosec // G710: registered post_logout_redirect_uri
+}
+
+// auditLogout best-effort records an RP-initiated logout.
+func (s *State) auditLogout(ctx context.Context, subject, clientID string) {
+	if s.Audit == nil {
+		return
+	}
+	var opJSON []byte
+	if clientID != "" {
+		opJSON, _ = json.Marshal(map[string]string{"clientId": clientID})
+	}
+	if err := s.Audit.Insert(ctx, &audit.Log{
+		ID:            tsid.Generate(tsid.AuditLog),
+		EntityType:    "PRINCIPAL",
+		EntityID:      subject,
+		Operation:     "OAUTH_LOGOUT",
+		OperationJSON: opJSON,
+		PrincipalID:   &subject,
+		PerformedAt:   time.Now().UTC(),
+	}); err != nil {
+		slog.Warn("audit of oauth logout failed", "err", err)
+	}
+}
@@ -0,0 +1,112 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+)
+
+// logoutState is a State whose signed-in session belongs to user, with
+// client as the only registered client. ended counts EndSession calls.
+func logoutState(t *testing.T, user *principal.Principal, client *auth.OAuthClient, ended *int) *State {
+	t.Helper()
+	s := testState(t)
+	s.OAuthClients = fakeClientFinder{client: client}
+	s.ValidateSession = func(string) (string, time.Time, bool) { return user.ID, time.Now(), true }
+	s.EndSession = func(context.Context, string) (string, bool) {
+		*ended++
+		return user.ID, true
+	}
+	return s
+}
+
+func logout(s *State, query url.Values) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/oauth/logout?"+query.Encode(), nil)
+	req.AddCookie(&http.Cookie{Name: "fc_session", Value: "sess"})
+	s.Logout(rec, req)
+	return rec
+}
+
+// wantInvalidRequest checks a 400 invalid_request that neither redirects
+// nor ends the session.
+func wantInvalidRequest(t *testing.T, rec *httptest.ResponseRecorder, ended int) {
+	t.Helper()
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 (Location %q)", rec.Code, rec.Header().Get("Location"))
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error != "invalid_request" {
+		t.Errorf("body = %s, want error=invalid_request", rec.Body.String())
+	}
+	if ended != 0 {
+		t.Errorf("session ended %d times; a rejected logout must leave it alone", ended)
+	}
+}
+
+func TestLogout_HintAudienceMustMatchClientID(t *testing.T) {
+	user := principal.NewUser("u@example.com", principal.ScopeClient)
+	client := &auth.OAuthClient{ClientID: "clt_rp", Active: true, PostLogoutRedirectURIs: []string{"https://rp.example/bye"}}
+	var ended int
+	s := logoutState(t, user, client, &ended)
+	hint, err := s.Auth.GenerateIDToken(user, "clt_rp", nil)
+	if err != nil {
+		t.Fatalf("generate id token: %v", err)
+	}
+
+	rec := logout(s, url.Values{
+		"id_token_hint":            {hint},
+		"client_id":                {"clt_other"},
+		"post_logout_redirect_uri": {"https://rp.example/bye"},
+	})
+	wantInvalidRequest(t, rec, ended)
+
+	rec = logout(s, url.Values{
+		"id_token_hint":            {hint},
+		"post_logout_redirect_uri": {"https://rp.example/bye"},
+		"state":                    {"xyz"},
+	})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want 303", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "https://rp.example/bye?state=xyz" {
+		t.Errorf("Location = %q", loc)
+	}
+	if ended != 1 {
+		t.Errorf("session ended %d times, want 1", ended)
+	}
+}
+
+func TestLogout_UnregisteredRedirectURIIsRejected(t *testing.T) {
+	user := principal.NewUser("u@example.com", principal.ScopeClient)
+	client := &auth.OAuthClient{ClientID: "clt_rp", Active: true, PostLogoutRedirectURIs: []string{"https://rp.example/bye"}}
+	var ended int
+	s := logoutState(t, user, client, &ended)
+
+	for _, uri := range []string{"https://evil.example/bye", "https://rp.example/bye/../admin", "https://rp.example.evil/bye"} {
+		rec := logout(s, url.Values{"client_id": {"clt_rp"}, "post_logout_redirect_uri": {uri}})
+		wantInvalidRequest(t, rec, ended)
+	}
+
+	rec := logout(s, url.Values{"post_logout_redirect_uri": {"https://rp.example/bye"}})
+	wantInvalidRequest(t, rec, ended)
+}
+
+func TestLogout_InactiveClientCannotRedirect(t *testing.T) {
+	user := principal.NewUser("u@example.com", principal.ScopeClient)
+	client := &auth.OAuthClient{ClientID: "clt_rp", Active: false, PostLogoutRedirectURIs: []string{"https://rp.example/bye"}}
+	var ended int
+	s := logoutState(t, user, client, &ended)
+
+	rec := logout(s, url.Values{"client_id": {"clt_rp"}, "post_logout_redirect_uri": {"https://rp.example/bye"}})
+	wantInvalidRequest(t, rec, ended)
+}
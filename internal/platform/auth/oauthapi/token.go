@@ -25,9 +25,12 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/authservice"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/revocation"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/loginattempt"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	sharedauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
@@ -44,14 +47,22 @@ type OAuthClientFinder interface {
 	FindByClientID(ctx context.Context, clientID string) (*auth.OAuthClient, error)
 }
 
+// PrincipalFinder loads principals by id — *principal.Repository in
+// production, narrowed like OAuthClientFinder so the userinfo tests can
+// serve a principal without a database. A missing principal is (nil, nil).
+type PrincipalFinder interface {
+	FindByID(ctx context.Context, id string) (*principal.Principal, error)
+}
+
 // State bundles the dependencies the OAuth endpoints need.
 type State struct {
 	OAuthClients  OAuthClientFinder
-	Principals    *principal.Repository
+	Principals    PrincipalFinder
 	Auth          *authservice.AuthService
 	AuthCodes     *grantstore.AuthorizationCodeRepository
 	RefreshTokens *grantstore.RefreshTokenRepository
 	PendingAuth   *grantstore.PendingAuthRepository
+	DeviceCodes   *grantstore.DeviceCodeRepository
 	// ValidateSession resolves the principal id + token issue time from a
 	// session-cookie / bearer token on /oauth/authorize, returning ok=false
 	// when the token is absent, invalid, or expired (authorize then
@@ -73,9 +84,14 @@ type State struct {
 	// provider.FilterRolesForApplications; when nil, ID tokens always carry
 	// the principal's full (unfiltered) role list.
 	FilterRolesForApplications func(ctx context.Context, roleNames []string, appIDs []string) ([]string, error)
-	// Encryption verifies confidential-client secrets (decrypt + compare).
-	// May be nil when no app key is configured — confidential auth then
-	// fails closed.
+	// ServiceAccountEnvironment resolves the SANDBOX or PRODUCTION
+	// environment of a service account, which its client_credentials
+	// tokens are pinned to. Injected from the service account repository;
+	// when nil, service account tokens are unpinned.
+	ServiceAccountEnvironment func(ctx context.Context, serviceAccountID string) (string, error)
+	// Encryption verifies confidential-client secrets minted before they
+	// were hashed (decrypt + compare). May be nil when no app key is
+	// configured — such secrets then fail closed; hashed ones don't need it.
 	Encryption *encryption.Service
 	// BaseURL is the external issuer/base URL the discovery document
 	// advertises its endpoint URLs from (e.g. https://flowcatalyst.example).
@@ -87,11 +103,39 @@ type State struct {
 	// /oauth/{token,authorize}. Optional (nil disables it; the per-IP
 	// middleware layer still applies when mounted).
 	RateLimit         ratelimit.Store
-	RateLimitPolicies ratelimit.Policies
+	RateLimitPolicies *ratelimit.LivePolicies
 	// ClientGovernor is the per-instance in-memory per-client_id limiter
 	// checked before the distributed RateLimit on /oauth/token (sheds a
 	// flood locally before the network round-trip). Optional (nil skips it).
 	ClientGovernor *ratelimit.Governor
+	// Revocations is the per-token revocation list. Introspection reports
+	// a revoked access token as inactive and /oauth/revoke adds access
+	// tokens to it. Optional (nil: only refresh tokens are revocable).
+	Revocations *revocation.Checker
+	// Audit records token revocations, failed client authentication and
+	// consent changes. Optional (nil disables recording).
+	Audit *audit.Repository
+	// Consents and PendingConsents back the consent screen for
+	// third-party clients (see consent.go). Without them a third-party
+	// client's authorization fails closed with server_error. Scopes
+	// supplies the admin-defined scope text shown there; optional.
+	Consents        ConsentStore
+	PendingConsents PendingConsentStore
+	Scopes          ScopeCatalog
+	// EndSession revokes a session token for /oauth/logout and returns
+	// its principal, ok=false when the token is already invalid. Injected
+	// like ValidateSession. When nil, logout only validates and redirects.
+	EndSession func(ctx context.Context, token string) (subject string, ok bool)
+	// Backchannel records which clients a principal signs in to and sends
+	// them logout tokens when the principal logs out. Optional (nil
+	// disables back-channel logout).
+	Backchannel LogoutNotifier
+	// CookieSecure matches the Secure flag the session cookie was set
+	// with, so /oauth/logout's clear overwrites it.
+	CookieSecure bool
+
+	// assertionJTIs rejects a replayed private_key_jwt client assertion.
+	assertionJTIs jtiCache
 }
 
 // recordAttempt best-effort logs a login attempt; failures are swallowed
@@ -108,21 +152,27 @@ func (s *State) recordAttempt(ctx context.Context, t loginattempt.AttemptType, o
 	_ = s.LoginAttempts.Record(ctx, a)
 }
 
-// RegisterTokenRoutes mounts POST /oauth/token.
+// RegisterTokenRoutes mounts POST /oauth/token and POST
+// /oauth/device_authorization — the device flow's first leg is a
+// token-endpoint-style client call and shares its throttles.
 func (s *State) RegisterTokenRoutes(r chi.Router) {
 	r.Post("/oauth/token", s.Token)
+	r.Post("/oauth/device_authorization", s.DeviceAuthorization)
 }
 
 // tokenRequest mirrors the Rust TokenRequest (form-urlencoded).
 type tokenRequest struct {
-	GrantType    string
-	Code         string
-	RedirectURI  string
-	ClientID     string
-	ClientSecret string
-	CodeVerifier string
-	RefreshToken string
-	Scope        string
+	GrantType           string
+	Code                string
+	RedirectURI         string
+	ClientID            string
+	ClientSecret        string
+	ClientAssertionType string
+	ClientAssertion     string
+	CodeVerifier        string
+	RefreshToken        string
+	Scope               string
+	DeviceCode          string
 }
 
 func parseTokenRequest(r *http.Request) (tokenRequest, error) {
@@ -135,9 +185,13 @@ func parseTokenRequest(r *http.Request) (tokenRequest, error) {
 		RedirectURI:  r.PostFormValue("redirect_uri"),
 		ClientID:     r.PostFormValue("client_id"),
 		ClientSecret: r.PostFormValue("client_secret"),
-		CodeVerifier: r.PostFormValue("code_verifier"),
-		RefreshToken: r.PostFormValue("refresh_token"),
-		Scope:        r.PostFormValue("scope"),
+		// RFC 7523 §2.2 (private_key_jwt).
+		ClientAssertionType: r.PostFormValue("client_assertion_type"),
+		ClientAssertion:     r.PostFormValue("client_assertion"),
+		CodeVerifier:        r.PostFormValue("code_verifier"),
+		RefreshToken:        r.PostFormValue("refresh_token"),
+		Scope:               r.PostFormValue("scope"),
+		DeviceCode:          r.PostFormValue("device_code"),
 	}, nil
 }
 
@@ -173,13 +227,13 @@ func (s *State) Token(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
-		if rej := ratelimit.Enforce(r.Context(), s.RateLimit, ratelimit.BucketOAuthTokenClient, req.ClientID, s.RateLimitPolicies.OAuthTokenClient); rej != nil {
+		if rej := ratelimit.Enforce(r.Context(), s.RateLimit, ratelimit.BucketOAuthTokenClient, req.ClientID, s.RateLimitPolicies.Get().OAuthTokenClient); rej != nil {
 			writeOAuthRateLimited(w, rej.RetryAfterSecs, "this client_id has exceeded its token endpoint rate limit")
 			return
 		}
 	}
 
-	// Authenticate the client up-front for code/refresh grants;
+	// Authenticate the client up-front for code/refresh/device grants;
 	// client_credentials does its own auth (including the CONFIDENTIAL check).
 	var authenticatedClient *auth.OAuthClient
 	if req.GrantType != "client_credentials" {
@@ -191,7 +245,7 @@ func (s *State) Token(w http.ResponseWriter, r *http.Request) {
 		authenticatedClient = c
 	}
 
-	// Enforce the client's registered grant-type allowlist for the two
+	// Enforce the client's registered grant-type allowlist for the
 	// client-authenticated grants. client_credentials is checked inside its
 	// own handler (it authenticates there).
 	if authenticatedClient != nil && !grantAllowed(authenticatedClient, req.GrantType) {
@@ -218,6 +272,8 @@ func (s *State) Token(w http.ResponseWriter, r *http.Request) {
 		s.handleRefreshTokenGrant(w, r, req, authenticatedClient)
 	case "client_credentials":
 		s.handleClientCredentialsGrant(w, r, req)
+	case deviceCodeGrantType:
+		s.handleDeviceCodeGrant(w, r, req, authenticatedClient)
 	default:
 		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type",
 			"Grant type '"+req.GrantType+"' is not supported")
@@ -227,9 +283,13 @@ func (s *State) Token(w http.ResponseWriter, r *http.Request) {
 // ─── client authentication ──────────────────────────────────────────────
 
 // authenticateClient resolves the client from Basic auth or body params
-// and verifies the secret for confidential clients. Mirrors Rust's
-// authenticate_client.
+// and verifies the secret for confidential clients, or the client
+// assertion for private_key_jwt clients. Mirrors Rust's
+// authenticate_client. Failures are audited (see clientAuthFailed).
 func (s *State) authenticateClient(r *http.Request, clientIDBody, clientSecretBody string) (*auth.OAuthClient, *oauthError) {
+	if r.PostFormValue("client_assertion_type") != "" || r.PostFormValue("client_assertion") != "" {
+		return s.authenticateClientAssertion(r, clientIDBody)
+	}
 	clientID, clientSecret, ok := basicAuthCreds(r)
 	if !ok {
 		if clientIDBody == "" {
@@ -244,38 +304,47 @@ func (s *State) authenticateClient(r *http.Request, clientIDBody, clientSecretBo
 		return nil, newOAuthError(http.StatusInternalServerError, "server_error", "")
 	}
 	if client == nil {
-		return nil, newOAuthError(http.StatusUnauthorized, "invalid_client", "Unknown client")
+		return nil, s.clientAuthFailed(r, clientID, nil, "unknown client", "Unknown client")
 	}
 	if !client.Active {
-		return nil, newOAuthError(http.StatusUnauthorized, "invalid_client", "Client is not active")
+		return nil, s.clientAuthFailed(r, clientID, client, "client inactive", "Client is not active")
+	}
+	if client.TokenEndpointAuthMethod == auth.AuthMethodPrivateKeyJWT {
+		return nil, s.clientAuthFailed(r, clientID, client, "secret presented by a private_key_jwt client",
+			"Client must authenticate with private_key_jwt")
 	}
 
 	// Public client (no stored secret) must not present one.
 	if client.SecretRef == nil {
 		if clientSecret != "" {
-			return nil, newOAuthError(http.StatusUnauthorized, "invalid_client",
+			return nil, s.clientAuthFailed(r, clientID, client, "secret presented by a public client",
 				"Public clients must not provide a client_secret")
 		}
 		return client, nil
 	}
 
-	// Confidential client: verify the provided secret against the
-	// encrypted ref (decrypt + compare).
+	// Confidential client: verify the provided secret against the stored
+	// hash (or, for an older row, its encrypted ref).
 	if clientSecret == "" {
-		return nil, newOAuthError(http.StatusUnauthorized, "invalid_client",
+		return nil, s.clientAuthFailed(r, clientID, client, "missing client secret",
 			"Client secret required for confidential clients")
 	}
 	if !s.verifyClientSecret(*client.SecretRef, clientSecret) {
-		return nil, newOAuthError(http.StatusUnauthorized, "invalid_client", "Invalid client credentials")
+		return nil, s.clientAuthFailed(r, clientID, client, "invalid client secret", "Invalid client credentials")
 	}
 	return client, nil
 }
 
-// verifyClientSecret decrypts the stored ref and compares it to the
-// provided secret in constant time (a naive == short-circuits on the first
-// differing byte, leaking prefix length to a timing observer). Fails closed
-// when no encryption service is configured.
+// verifyClientSecret checks the provided secret against the stored ref:
+// an argon2id PHC hash (passwordhash.Verify, constant-time), or — for a
+// secret minted before hashing — an encrypted ref, decrypted and compared
+// in constant time (a naive == short-circuits on the first differing
+// byte, leaking prefix length to a timing observer). An encrypted ref
+// fails closed when no encryption service is configured.
 func (s *State) verifyClientSecret(secretRef, provided string) bool {
+	if passwordhash.IsHash(secretRef) {
+		return passwordhash.Verify(provided, secretRef) == nil
+	}
 	if s.Encryption == nil {
 		return false
 	}
@@ -321,6 +390,10 @@ func basicAuthCreds(r *http.Request) (id, secret string, ok bool) {
 // ─── client_credentials grant ───────────────────────────────────────────
 
 func (s *State) handleClientCredentialsGrant(w http.ResponseWriter, r *http.Request, req tokenRequest) {
+	if req.ClientAssertionType != "" || req.ClientAssertion != "" {
+		s.handleClientAssertionCredentialsGrant(w, r, req)
+		return
+	}
 	if req.ClientID == "" {
 		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "Missing client_id")
 		return
@@ -346,36 +419,70 @@ func (s *State) handleClientCredentialsGrant(w http.ResponseWriter, r *http.Requ
 			s.handleDeveloperCredentialGrant(w, r, req)
 			return
 		}
-		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", "Invalid client credentials")
+		s.clientAuthFailed(r, req.ClientID, nil, "unknown client", "Invalid client credentials").write(w)
 		return
 	}
 	if !client.Active {
-		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", "Invalid client credentials")
+		s.clientAuthFailed(r, req.ClientID, client, "client inactive", "Invalid client credentials").write(w)
 		return
 	}
-	if client.ClientType != auth.OAuthClientConfidential {
-		writeOAuthError(w, http.StatusUnauthorized, "unauthorized_client",
-			"Public clients cannot use client_credentials grant")
+	if !s.clientCredentialsAllowed(w, r, req.ClientID, client) {
 		return
 	}
-	if !grantAllowed(client, "client_credentials") {
-		reason := "client_credentials grant not permitted for this client"
-		s.recordAttempt(r.Context(), loginattempt.AttemptServiceAccountToken, loginattempt.OutcomeFailure, req.ClientID, nil, &reason)
-		writeOAuthError(w, http.StatusUnauthorized, "unauthorized_client",
-			"Client is not permitted to use the client_credentials grant type")
-		return
-	}
-	if client.SecretRef == nil {
-		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", "Invalid client credentials")
+	if client.SecretRef == nil || client.TokenEndpointAuthMethod == auth.AuthMethodPrivateKeyJWT {
+		s.clientAuthFailed(r, req.ClientID, client, "client does not authenticate with a secret", "Invalid client credentials").write(w)
 		return
 	}
 	if !s.verifyClientSecret(*client.SecretRef, req.ClientSecret) {
 		reason := "Invalid client secret"
 		s.recordAttempt(r.Context(), loginattempt.AttemptServiceAccountToken, loginattempt.OutcomeFailure, req.ClientID, nil, &reason)
-		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", "Invalid client credentials")
+		s.clientAuthFailed(r, req.ClientID, client, "invalid client secret", "Invalid client credentials").write(w)
+		return
+	}
+	s.mintServiceAccountToken(w, r, req, client)
+}
+
+// handleClientAssertionCredentialsGrant is client_credentials for a
+// private_key_jwt client: the assertion identifies and authenticates it,
+// and client_id may be omitted (RFC 7521 §4.2).
+func (s *State) handleClientAssertionCredentialsGrant(w http.ResponseWriter, r *http.Request, req tokenRequest) {
+	client, errResp := s.authenticateClientAssertion(r, req.ClientID)
+	if errResp != nil {
+		if errResp.Code == "invalid_client" {
+			reason := "Invalid client assertion"
+			s.recordAttempt(r.Context(), loginattempt.AttemptServiceAccountToken, loginattempt.OutcomeFailure, req.ClientID, nil, &reason)
+		}
+		errResp.write(w)
 		return
 	}
+	req.ClientID = client.ClientID
+	if !s.clientCredentialsAllowed(w, r, req.ClientID, client) {
+		return
+	}
+	s.mintServiceAccountToken(w, r, req, client)
+}
 
+// clientCredentialsAllowed writes the error and returns false when client
+// may not use the client_credentials grant.
+func (s *State) clientCredentialsAllowed(w http.ResponseWriter, r *http.Request, clientID string, client *auth.OAuthClient) bool {
+	if client.ClientType != auth.OAuthClientConfidential {
+		writeOAuthError(w, http.StatusUnauthorized, "unauthorized_client",
+			"Public clients cannot use client_credentials grant")
+		return false
+	}
+	if !grantAllowed(client, "client_credentials") {
+		reason := "client_credentials grant not permitted for this client"
+		s.recordAttempt(r.Context(), loginattempt.AttemptServiceAccountToken, loginattempt.OutcomeFailure, clientID, nil, &reason)
+		writeOAuthError(w, http.StatusUnauthorized, "unauthorized_client",
+			"Client is not permitted to use the client_credentials grant type")
+		return false
+	}
+	return true
+}
+
+// mintServiceAccountToken issues the token for an authenticated
+// client_credentials client, as its service account.
+func (s *State) mintServiceAccountToken(w http.ResponseWriter, r *http.Request, req tokenRequest, client *auth.OAuthClient) {
 	if client.PrincipalID == nil {
 		writeOAuthError(w, http.StatusInternalServerError, "server_error", "Client not properly configured")
 		return
@@ -394,7 +501,15 @@ func (s *State) handleClientCredentialsGrant(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	s.mintClientCredentialsToken(w, r, p, req, loginattempt.AttemptServiceAccountToken,
+	env := ""
+	if s.ServiceAccountEnvironment != nil && p.ServiceAccountID != nil {
+		if env, err = s.ServiceAccountEnvironment(r.Context(), *p.ServiceAccountID); err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+	}
+
+	s.mintClientCredentialsToken(w, r, p, req, env, loginattempt.AttemptServiceAccountToken,
 		"the service account's granted permissions")
 }
 
@@ -440,7 +555,7 @@ func (s *State) handleDeveloperCredentialGrant(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	s.mintClientCredentialsToken(w, r, p, req, loginattempt.AttemptDeveloperToken,
+	s.mintClientCredentialsToken(w, r, p, req, "", loginattempt.AttemptDeveloperToken,
 		"your granted permissions")
 }
 
@@ -451,9 +566,11 @@ func (s *State) handleDeveloperCredentialGrant(w http.ResponseWriter, r *http.Re
 // the principal loaded (and hence its type/roles/permissions) differs.
 // deniedScopeSubject customises the invalid_scope message's tail
 // ("the service account's granted permissions" vs "your granted permissions").
+// env pins a service account's token to its environment; empty for
+// developer credentials.
 func (s *State) mintClientCredentialsToken(
 	w http.ResponseWriter, r *http.Request,
-	p *principal.Principal, req tokenRequest,
+	p *principal.Principal, req tokenRequest, env string,
 	attemptType loginattempt.AttemptType, deniedScopeSubject string,
 ) {
 	granted, explicit, err := s.grantedScope(r.Context(), p, req.Scope)
@@ -470,7 +587,7 @@ func (s *State) mintClientCredentialsToken(
 			"Requested scope exceeds "+deniedScopeSubject)
 		return
 	}
-	accessToken, err := s.Auth.GenerateAccessTokenWithScope(p, granted)
+	accessToken, err := s.Auth.GenerateAccessTokenInEnvironment(p, granted, env)
 	if err != nil {
 		writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
 		return
@@ -542,7 +659,17 @@ func (s *State) handleAuthorizationCodeGrant(w http.ResponseWriter, r *http.Requ
 	if code.Scope != nil {
 		scope = *code.Scope
 	}
+	s.issueUserTokens(w, r, p, client, code.ClientID, scope, code.Nonce)
+}
 
+// issueUserTokens is the token-minting tail shared by the user-delegated
+// grants (authorization_code, device_code): an access token narrowed to
+// the granted scope, an ID token when scope has openid, and a new refresh
+// token family when it has offline_access.
+func (s *State) issueUserTokens(
+	w http.ResponseWriter, r *http.Request,
+	p *principal.Principal, client *auth.OAuthClient, clientID, scope string, nonce *string,
+) {
 	// Narrow the token's permission scope to the ceiling ∩ the scope captured
 	// at authorize time. Interactive logins commonly request only OIDC scopes
 	// (openid/profile/…), which leaves the permission request empty → the
@@ -560,7 +687,7 @@ func (s *State) handleAuthorizationCodeGrant(w http.ResponseWriter, r *http.Requ
 
 	var idToken *string
 	if scopeHas(scope, "openid") {
-		t, err := s.mintIDToken(r.Context(), p, code.ClientID, client, code.Nonce)
+		t, err := s.mintIDToken(r.Context(), p, clientID, client, nonce)
 		if err != nil {
 			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
 			return
@@ -575,7 +702,7 @@ func (s *State) handleAuthorizationCodeGrant(w http.ResponseWriter, r *http.Requ
 			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
 			return
 		}
-		cid := code.ClientID
+		cid := clientID
 		entity.OAuthClientID = &cid
 		entity.Scopes = strings.Fields(scope)
 		// Root a rotation family on this first token so every later rotation
@@ -590,13 +717,17 @@ func (s *State) handleAuthorizationCodeGrant(w http.ResponseWriter, r *http.Requ
 		refreshToken = &raw
 	}
 
+	if s.Backchannel != nil {
+		s.Backchannel.RecordLogin(r.Context(), p.ID, clientID)
+	}
+
 	writeToken(w, tokenResponse{
 		AccessToken:  accessToken,
 		TokenType:    "Bearer",
 		ExpiresIn:    3600,
 		RefreshToken: refreshToken,
 		IDToken:      idToken,
-		Scope:        code.Scope,
+		Scope:        strPtrOrNil(scope),
 	})
 }
 
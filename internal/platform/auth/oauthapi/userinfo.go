@@ -1,12 +1,14 @@
 package oauthapi
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/authservice"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 )
 
 // RegisterUserinfoRoutes mounts GET+POST /oauth/userinfo. Closes the
@@ -16,32 +18,50 @@ func (s *State) RegisterUserinfoRoutes(r chi.Router) {
 	r.Post("/oauth/userinfo", s.Userinfo)
 }
 
+// RolesClaim is the namespaced claim carrying the principal's role names
+// on the UserInfo response. OIDC reserves un-namespaced names for
+// registered claims, so off-the-shelf client libraries map roles from a
+// collision-resistant URI; the bare "roles" claim stays for existing
+// FlowCatalyst SDK consumers.
+const RolesClaim = "https://flowcatalyst.io/roles"
+
 // userInfoResponse is the OIDC UserInfo body. sub/name/tier/type and the
 // array claims are always present; scope carries the token's granted
-// permissions (empty for tokens without a scope claim); email and client_id
-// are omitted when absent.
+// permissions (empty for tokens without a scope claim); email, the
+// profile claims and client_id are omitted when absent. updated_at is
+// the principal record's last change, in epoch seconds (OIDC Core §5.1).
 type userInfoResponse struct {
 	Sub           string   `json:"sub"`
 	Email         *string  `json:"email,omitempty"`
+	EmailVerified *bool    `json:"email_verified,omitempty"`
 	Name          string   `json:"name"`
+	GivenName     *string  `json:"given_name,omitempty"`
+	FamilyName    *string  `json:"family_name,omitempty"`
+	Picture       *string  `json:"picture,omitempty"`
+	UpdatedAt     *int64   `json:"updated_at,omitempty"`
 	Tier          string   `json:"tier"`
 	Scope         string   `json:"scope"`
 	PrincipalType string   `json:"type"`
 	ClientID      *string  `json:"client_id,omitempty"`
 	Clients       []string `json:"clients"`
 	Roles         []string `json:"roles"`
+	NSRoles       []string `json:"https://flowcatalyst.io/roles"` // RolesClaim
 	Applications  []string `json:"applications"`
 }
 
 // Userinfo is GET/POST /oauth/userinfo (OIDC). It validates the bearer
-// access token and returns the identity claims subset.
+// access token (signature, expiry and the revocation list) and returns
+// the identity claims. When Principals is wired the profile claims come
+// from the live principal record, so a renamed user or changed email
+// shows up before the token expires, and a deactivated or deleted
+// principal's token is refused.
 func (s *State) Userinfo(w http.ResponseWriter, r *http.Request) {
 	claims, errResp := s.validateBearer(r)
 	if errResp != nil {
-		errResp.write(w)
+		writeBearerError(w, errResp)
 		return
 	}
-	writeJSON(w, http.StatusOK, userInfoResponse{
+	resp := userInfoResponse{
 		Sub:           claims.Subject,
 		Email:         claims.Email,
 		Name:          claims.Name,
@@ -51,12 +71,48 @@ func (s *State) Userinfo(w http.ResponseWriter, r *http.Request) {
 		ClientID:      userinfoClientID(claims.Clients),
 		Clients:       nonNil(claims.Clients),
 		Roles:         nonNil(claims.Roles),
+		NSRoles:       nonNil(claims.Roles),
 		Applications:  nonNil(claims.Applications),
-	})
+	}
+	if s.Principals != nil {
+		p, err := s.Principals.FindByID(r.Context(), claims.Subject)
+		if err != nil {
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "Failed to load principal")
+			return
+		}
+		if p == nil || !p.Active {
+			writeBearerError(w, newOAuthError(http.StatusUnauthorized, "invalid_token", "Principal not found or inactive"))
+			return
+		}
+		applyPrincipalClaims(&resp, p)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// applyPrincipalClaims overlays the standard profile claims from the
+// principal record. Roles, clients and applications stay as minted: they
+// are what the token actually grants.
+func applyPrincipalClaims(resp *userInfoResponse, p *principal.Principal) {
+	resp.Name = p.Name
+	updated := p.UpdatedAt.Unix()
+	resp.UpdatedAt = &updated
+	id := p.UserIdentity
+	if id == nil {
+		resp.Email, resp.EmailVerified = nil, nil
+		return
+	}
+	if id.Email != "" {
+		email, verified := id.Email, id.EmailVerified
+		resp.Email, resp.EmailVerified = &email, &verified
+	}
+	resp.GivenName = id.FirstName
+	resp.FamilyName = id.LastName
+	resp.Picture = id.PictureURL
 }
 
 // validateBearer extracts and validates the Authorization: Bearer access
-// token, mirroring Rust's extract_and_validate_token.
+// token, mirroring Rust's extract_and_validate_token. A revoked token is
+// rejected like an invalid one.
 func (s *State) validateBearer(r *http.Request) (*authservice.AccessTokenClaims, *oauthError) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -66,13 +122,24 @@ func (s *State) validateBearer(r *http.Request) (*authservice.AccessTokenClaims,
 	if token == "" {
 		return nil, newOAuthError(http.StatusUnauthorized, "invalid_request", "Invalid Authorization header format")
 	}
-	claims, err := s.Auth.ValidateToken(token)
-	if err != nil {
+	claims, err := s.validateAccessToken(r.Context(), token)
+	if err != nil || claims == nil {
 		return nil, newOAuthError(http.StatusUnauthorized, "invalid_token", "Token is invalid or expired")
 	}
 	return claims, nil
 }
 
+// writeBearerError writes a protected-resource error with the RFC 6750
+// §3 WWW-Authenticate challenge OIDC client libraries key off.
+func writeBearerError(w http.ResponseWriter, e *oauthError) {
+	challenge := fmt.Sprintf(`Bearer error="%s"`, e.Code)
+	if e.Description != nil {
+		challenge += fmt.Sprintf(`, error_description="%s"`, *e.Description)
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	e.write(w)
+}
+
 // userinfoClientID derives the client_id from the first client entry,
 // stripping the ":identifier" suffix. Returns nil for the anchor "*"
 // wildcard. Mirrors Rust's userinfo client_id logic.
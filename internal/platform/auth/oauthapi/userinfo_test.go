@@ -0,0 +1,119 @@
+package oauthapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
+)
+
+// fakePrincipals is an in-memory PrincipalFinder; err fails every lookup.
+type fakePrincipals struct {
+	byID map[string]*principal.Principal
+	err  error
+}
+
+func (f fakePrincipals) FindByID(_ context.Context, id string) (*principal.Principal, error) {
+	return f.byID[id], f.err
+}
+
+func userinfo(s *State, bearer string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/oauth/userinfo", nil)
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	s.Userinfo(rec, req)
+	return rec
+}
+
+func TestUserinfo_StandardAndNamespacedClaims(t *testing.T) {
+	svc := testAuthService(t)
+	p := principal.NewUser("u@example.com", principal.ScopeClient)
+	p.Name = "Old Name"
+	p.Roles = []serviceaccount.RoleAssignment{{Role: "orders:admin"}}
+	token, err := svc.GenerateAccessToken(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The record changed after the token was minted; userinfo reports it.
+	fresh := *p
+	identity := *p.UserIdentity
+	identity.EmailVerified = true
+	identity.FirstName = strPtrOrNil("Ursula")
+	fresh.UserIdentity = &identity
+	fresh.Name = "Ursula K"
+	fresh.UpdatedAt = time.Unix(1_700_000_000, 0)
+	s := &State{Auth: svc, Principals: fakePrincipals{byID: map[string]*principal.Principal{p.ID: &fresh}}}
+
+	rec := userinfo(s, token)
+	if rec.Code != 200 {
+		t.Fatalf("status = %d (%s)", rec.Code, rec.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"sub":            p.ID,
+		"email":          "u@example.com",
+		"email_verified": true,
+		"name":           "Ursula K",
+		"given_name":     "Ursula",
+		"updated_at":     float64(1_700_000_000),
+	}
+	for k, v := range want {
+		if body[k] != v {
+			t.Errorf("%s = %v, want %v", k, body[k], v)
+		}
+	}
+	roles, _ := body[RolesClaim].([]any)
+	if len(roles) != 1 || roles[0] != "orders:admin" {
+		t.Errorf("%s = %v", RolesClaim, body[RolesClaim])
+	}
+}
+
+func TestUserinfo_RejectsInactivePrincipal(t *testing.T) {
+	svc := testAuthService(t)
+	p := principal.NewUser("u@example.com", principal.ScopeClient)
+	token, _ := svc.GenerateAccessToken(p)
+	inactive := *p
+	inactive.Active = false
+
+	for name, byID := range map[string]map[string]*principal.Principal{
+		"inactive": {p.ID: &inactive},
+		"deleted":  {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := userinfo(&State{Auth: svc, Principals: fakePrincipals{byID: byID}}, token)
+			if rec.Code != 401 || !strings.Contains(rec.Header().Get("WWW-Authenticate"), `error="invalid_token"`) {
+				t.Fatalf("status = %d WWW-Authenticate = %q, want 401 invalid_token", rec.Code, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	rec := userinfo(&State{Auth: svc, Principals: fakePrincipals{err: errors.New("db down")}}, token)
+	if rec.Code != 500 {
+		t.Fatalf("lookup failure: status = %d, want 500", rec.Code)
+	}
+}
+
+func TestUserinfo_BearerChallenge(t *testing.T) {
+	s := &State{Auth: testAuthService(t)}
+
+	rec := userinfo(s, "")
+	if rec.Code != 401 || !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+		t.Fatalf("missing token: status = %d WWW-Authenticate = %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	rec = userinfo(s, "not-a-jwt")
+	if got := rec.Header().Get("WWW-Authenticate"); rec.Code != 401 || !strings.Contains(got, `error="invalid_token"`) {
+		t.Fatalf("bad token: status = %d WWW-Authenticate = %q", rec.Code, got)
+	}
+}
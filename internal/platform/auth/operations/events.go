@@ -1,4 +1,4 @@
-// Package operations holds the 13 auth subdomain admin use cases.
+// Package operations holds the 16 auth subdomain admin use cases.
 //
 // Each event type wires DomainEvent the standard way. To keep the file
 // terse, all events use the same Source + group/subject helpers.
@@ -33,6 +33,10 @@ const (
 
 	IdpRoleMappingCreatedType = "platform:admin:idp-role-mapping:created"
 	IdpRoleMappingDeletedType = "platform:admin:idp-role-mapping:deleted"
+
+	OAuthScopeCreatedType = "platform:admin:oauth-scope:created"
+	OAuthScopeUpdatedType = "platform:admin:oauth-scope:updated"
+	OAuthScopeDeletedType = "platform:admin:oauth-scope:deleted"
 )
 
 func oauthSubject(id string) string   { return "platform.oauthclient." + id }
@@ -43,6 +47,8 @@ func configSubject(id string) string  { return "platform.authconfig." + id }
 func configGroup(id string) string    { return "platform:authconfig:" + id }
 func mappingSubject(id string) string { return "platform.idprolemapping." + id }
 func mappingGroup(id string) string   { return "platform:idprolemapping:" + id }
+func scopeSubject(id string) string   { return "platform.oauthscope." + id }
+func scopeGroup(id string) string     { return "platform:oauthscope:" + id }
 
 // ── OAuthClient events ────────────────────────────────────────────────────
 
@@ -399,3 +405,81 @@ func (e IdpRoleMappingDeleted) ToDataJSON() ([]byte, error) {
 		IdpRoleName string `json:"idpRoleName"`
 	}{e.MappingID, e.IdpRoleName})
 }
+
+// ── OAuthScope events ─────────────────────────────────────────────────────
+
+type OAuthScopeCreated struct {
+	Metadata    usecase.EventMetadata
+	ScopeID     string
+	Name        string
+	Description string
+}
+
+func (e OAuthScopeCreated) EventID() string       { return e.Metadata.EventID }
+func (e OAuthScopeCreated) EventType() string     { return OAuthScopeCreatedType }
+func (e OAuthScopeCreated) SpecVersion() string   { return "1.0" }
+func (e OAuthScopeCreated) Source() string        { return Source }
+func (e OAuthScopeCreated) Subject() string       { return scopeSubject(e.ScopeID) }
+func (e OAuthScopeCreated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e OAuthScopeCreated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e OAuthScopeCreated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e OAuthScopeCreated) CausationID() string   { return e.Metadata.CausationID }
+func (e OAuthScopeCreated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e OAuthScopeCreated) MessageGroup() string  { return scopeGroup(e.ScopeID) }
+func (e OAuthScopeCreated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID          string `json:"scopeId"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{e.ScopeID, e.Name, e.Description})
+}
+
+type OAuthScopeUpdated struct {
+	Metadata    usecase.EventMetadata
+	ScopeID     string
+	Name        string
+	Description string
+}
+
+func (e OAuthScopeUpdated) EventID() string       { return e.Metadata.EventID }
+func (e OAuthScopeUpdated) EventType() string     { return OAuthScopeUpdatedType }
+func (e OAuthScopeUpdated) SpecVersion() string   { return "1.0" }
+func (e OAuthScopeUpdated) Source() string        { return Source }
+func (e OAuthScopeUpdated) Subject() string       { return scopeSubject(e.ScopeID) }
+func (e OAuthScopeUpdated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e OAuthScopeUpdated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e OAuthScopeUpdated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e OAuthScopeUpdated) CausationID() string   { return e.Metadata.CausationID }
+func (e OAuthScopeUpdated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e OAuthScopeUpdated) MessageGroup() string  { return scopeGroup(e.ScopeID) }
+func (e OAuthScopeUpdated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID          string `json:"scopeId"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{e.ScopeID, e.Name, e.Description})
+}
+
+type OAuthScopeDeleted struct {
+	Metadata usecase.EventMetadata
+	ScopeID  string
+	Name     string
+}
+
+func (e OAuthScopeDeleted) EventID() string       { return e.Metadata.EventID }
+func (e OAuthScopeDeleted) EventType() string     { return OAuthScopeDeletedType }
+func (e OAuthScopeDeleted) SpecVersion() string   { return "1.0" }
+func (e OAuthScopeDeleted) Source() string        { return Source }
+func (e OAuthScopeDeleted) Subject() string       { return scopeSubject(e.ScopeID) }
+func (e OAuthScopeDeleted) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e OAuthScopeDeleted) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e OAuthScopeDeleted) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e OAuthScopeDeleted) CausationID() string   { return e.Metadata.CausationID }
+func (e OAuthScopeDeleted) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e OAuthScopeDeleted) MessageGroup() string  { return scopeGroup(e.ScopeID) }
+func (e OAuthScopeDeleted) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID   string `json:"scopeId"`
+		Name string `json:"name"`
+	}{e.ScopeID, e.Name})
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
+	"net/url"
 	"strings"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -29,6 +29,16 @@ type CreateOAuthClientCommand struct {
 	ApplicationIDs         []string `json:"applicationIds,omitempty"`
 	PrincipalID            *string  `json:"principalId,omitempty"`
 	PKCERequired           *bool    `json:"pkceRequired,omitempty"`
+	// TokenEndpointAuthMethod is client_secret_basic (the default) or
+	// private_key_jwt; the latter needs a JWKS and gets no secret.
+	TokenEndpointAuthMethod string  `json:"tokenEndpointAuthMethod,omitempty"`
+	JWKS                    *string `json:"jwks,omitempty"`
+	// FirstParty defaults to true; third-party clients get the consent
+	// screen.
+	FirstParty *bool `json:"firstParty,omitempty"`
+	// BackchannelLogoutURI opts the client in to OIDC Back-Channel
+	// Logout notifications.
+	BackchannelLogoutURI *string `json:"backchannelLogoutUri,omitempty"`
 }
 
 // CreateOAuthClient validates the command, persists the OAuth client, and
@@ -43,7 +53,22 @@ func CreateOAuthClient(repo *auth.OAuthClientRepo) usecaseop.Operation[CreateOAu
 			if strings.TrimSpace(cmd.ClientName) == "" {
 				return usecase.Validation("CLIENT_NAME_REQUIRED", "clientName is required")
 			}
-			return nil
+			method, err := parseAuthMethod(cmd.TokenEndpointAuthMethod)
+			if err != nil {
+				return err
+			}
+			if method == auth.AuthMethodPrivateKeyJWT {
+				if auth.ParseOAuthClientType(cmd.ClientType) != auth.OAuthClientConfidential {
+					return usecase.Validation("INVALID_AUTH_METHOD", "private_key_jwt requires a CONFIDENTIAL client")
+				}
+				if cmd.JWKS == nil {
+					return usecase.Validation("JWKS_REQUIRED", "jwks is required for private_key_jwt")
+				}
+			}
+			if err := validateBackchannelLogoutURI(cmd.BackchannelLogoutURI); err != nil {
+				return err
+			}
+			return validateJWKS(cmd.JWKS)
 		},
 		Authorize: usecaseop.Public[CreateOAuthClientCommand],
 		Execute: func(ctx context.Context, cmd CreateOAuthClientCommand, ec usecase.ExecutionContext) (usecaseop.Plan[OAuthClientCreated], error) {
@@ -74,7 +99,13 @@ func CreateOAuthClient(repo *auth.OAuthClientRepo) usecaseop.Operation[CreateOAu
 			if cmd.PKCERequired != nil {
 				c.PKCERequired = *cmd.PKCERequired
 			}
-			if t == auth.OAuthClientConfidential {
+			if cmd.FirstParty != nil {
+				c.FirstParty = *cmd.FirstParty
+			}
+			c.BackchannelLogoutURI = nonEmpty(cmd.BackchannelLogoutURI)
+			c.TokenEndpointAuthMethod, _ = parseAuthMethod(cmd.TokenEndpointAuthMethod)
+			c.JWKS = cmd.JWKS
+			if t == auth.OAuthClientConfidential && c.TokenEndpointAuthMethod == auth.AuthMethodClientSecret {
 				plaintext, ref, err := generateSecret()
 				if err != nil {
 					return nil, usecase.Internal("SECRET", "generate client secret failed", err)
@@ -106,6 +137,14 @@ type UpdateOAuthClientCommand struct {
 	AllowedOrigins         []string `json:"allowedOrigins,omitempty"`
 	ApplicationIDs         []string `json:"applicationIds,omitempty"`
 	PKCERequired           *bool    `json:"pkceRequired,omitempty"`
+	// Switching to private_key_jwt drops the client's secret; switching
+	// back needs a RotateOAuthClientSecret to mint a new one.
+	TokenEndpointAuthMethod *string `json:"tokenEndpointAuthMethod,omitempty"`
+	JWKS                    *string `json:"jwks,omitempty"`
+	FirstParty              *bool   `json:"firstParty,omitempty"`
+	// BackchannelLogoutURI replaces the back-channel logout URI; an empty
+	// string clears it.
+	BackchannelLogoutURI *string `json:"backchannelLogoutUri,omitempty"`
 }
 
 // UpdateOAuthClient mutates the supplied fields and emits [OAuthClientUpdated].
@@ -120,7 +159,15 @@ func UpdateOAuthClient(repo *auth.OAuthClientRepo) usecaseop.Operation[UpdateOAu
 			if cmd.ClientName != nil && strings.TrimSpace(*cmd.ClientName) == "" {
 				return usecase.Validation("CLIENT_NAME_REQUIRED", "clientName cannot be empty")
 			}
-			return nil
+			if cmd.TokenEndpointAuthMethod != nil {
+				if _, err := parseAuthMethod(*cmd.TokenEndpointAuthMethod); err != nil {
+					return err
+				}
+			}
+			if err := validateBackchannelLogoutURI(cmd.BackchannelLogoutURI); err != nil {
+				return err
+			}
+			return validateJWKS(cmd.JWKS)
 		},
 		Authorize: usecaseop.Public[UpdateOAuthClientCommand],
 		Execute: func(ctx context.Context, cmd UpdateOAuthClientCommand, ec usecase.ExecutionContext) (usecaseop.Plan[OAuthClientUpdated], error) {
@@ -155,6 +202,28 @@ func UpdateOAuthClient(repo *auth.OAuthClientRepo) usecaseop.Operation[UpdateOAu
 			if cmd.PKCERequired != nil {
 				c.PKCERequired = *cmd.PKCERequired
 			}
+			if cmd.FirstParty != nil {
+				c.FirstParty = *cmd.FirstParty
+			}
+			if cmd.BackchannelLogoutURI != nil {
+				c.BackchannelLogoutURI = nonEmpty(cmd.BackchannelLogoutURI)
+			}
+			if cmd.JWKS != nil {
+				c.JWKS = cmd.JWKS
+			}
+			if cmd.TokenEndpointAuthMethod != nil {
+				method, _ := parseAuthMethod(*cmd.TokenEndpointAuthMethod)
+				if method == auth.AuthMethodPrivateKeyJWT && method != c.TokenEndpointAuthMethod {
+					if c.ClientType != auth.OAuthClientConfidential {
+						return nil, usecase.Validation("INVALID_AUTH_METHOD", "private_key_jwt requires a CONFIDENTIAL client")
+					}
+					c.SecretRef = nil
+				}
+				c.TokenEndpointAuthMethod = method
+			}
+			if c.TokenEndpointAuthMethod == auth.AuthMethodPrivateKeyJWT && c.JWKS == nil {
+				return nil, usecase.Validation("JWKS_REQUIRED", "jwks is required for private_key_jwt")
+			}
 
 			event := OAuthClientUpdated{
 				Metadata:      usecase.NewEventMetadata(ec, OAuthClientUpdatedType, Source, oauthSubject(c.ID)),
@@ -306,6 +375,9 @@ func RotateOAuthClientSecret(repo *auth.OAuthClientRepo) usecaseop.Operation[Rot
 			if c.ClientType != auth.OAuthClientConfidential {
 				return nil, usecase.Conflict("NOT_CONFIDENTIAL", "Only CONFIDENTIAL clients have rotatable secrets")
 			}
+			if c.TokenEndpointAuthMethod != auth.AuthMethodClientSecret {
+				return nil, usecase.Conflict("NOT_SECRET_AUTH", "Client authenticates with private_key_jwt and has no secret")
+			}
 			plaintext, ref, err := generateSecret()
 			if err != nil {
 				return nil, usecase.Internal("SECRET", "generate client secret failed", err)
@@ -325,32 +397,74 @@ func RotateOAuthClientSecret(repo *auth.OAuthClientRepo) usecaseop.Operation[Rot
 // ── helpers ───────────────────────────────────────────────────────────────
 
 // generateSecret mints a random client secret and returns it alongside
-// its encrypted reference (the value stored in client_secret_ref).
-// Mirrors Rust: the secret is reversibly encrypted with FLOWCATALYST_APP_KEY
-// and verified at /oauth/token by decrypt-and-compare. Fails if no app
-// key is configured rather than storing a plaintext or unverifiable secret.
+// the argon2id hash stored in client_secret_ref. The plaintext is shown
+// once (stashSecret) and never recoverable; /oauth/token verifies against
+// the hash.
 func generateSecret() (plaintext, ref string, err error) {
 	b := make([]byte, 32)
 	if _, err = rand.Read(b); err != nil {
 		return "", "", err
 	}
 	plaintext = base64.RawURLEncoding.EncodeToString(b)
-	enc, err := encryption.FromEnv()
+	ref, err = passwordhash.Hash(plaintext)
 	if err != nil {
 		return "", "", err
 	}
-	if enc == nil {
-		return "", "", errors.New("FLOWCATALYST_APP_KEY not configured; cannot encrypt client secret")
+	return plaintext, ref, nil
+}
+
+// parseAuthMethod accepts "" (the default), client_secret_basic,
+// client_secret_post (an alias: both are accepted at the token endpoint)
+// and private_key_jwt.
+func parseAuthMethod(s string) (auth.OAuthClientAuthMethod, error) {
+	switch strings.TrimSpace(s) {
+	case "", "client_secret_basic", "client_secret_post":
+		return auth.AuthMethodClientSecret, nil
+	case string(auth.AuthMethodPrivateKeyJWT):
+		return auth.AuthMethodPrivateKeyJWT, nil
 	}
-	encrypted, err := enc.Encrypt(plaintext)
-	if err != nil {
-		return "", "", err
+	return "", usecase.Validation("INVALID_AUTH_METHOD",
+		"tokenEndpointAuthMethod must be client_secret_basic, client_secret_post or private_key_jwt")
+}
+
+// validateJWKS checks a supplied JWK Set, if any.
+func validateJWKS(jwks *string) error {
+	if jwks == nil {
+		return nil
 	}
-	// Store with the "encrypted:" prefix so the persisted string is
-	// byte-identical to what Rust writes (oauth_clients_api.rs:
-	// format!("encrypted:{}", encrypted)). Decrypt strips the prefix on
-	// read, so verification is unaffected; this keeps client_secret_ref
-	// values uniform across a mixed Go/Rust deployment.
-	ref = "encrypted:" + encrypted
-	return plaintext, ref, nil
+	if err := auth.ValidateClientJWKS(*jwks); err != nil {
+		return usecase.Validation("INVALID_JWKS", "jwks: "+err.Error())
+	}
+	return nil
+}
+
+// validateBackchannelLogoutURI checks a supplied back-channel logout URI,
+// if any: an absolute https URL without a fragment (OIDC Back-Channel
+// Logout §2.2), or http on a loopback host for local development. An
+// empty string is allowed (it clears the URI on update).
+func validateBackchannelLogoutURI(uri *string) error {
+	if uri == nil || *uri == "" {
+		return nil
+	}
+	u, err := url.Parse(*uri)
+	if err != nil || u.Host == "" || u.Fragment != "" {
+		return usecase.Validation("INVALID_BACKCHANNEL_LOGOUT_URI", "backchannelLogoutUri must be an absolute URL without a fragment")
+	}
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if h := u.Hostname(); h == "localhost" || h == "127.0.0.1" || h == "::1" {
+			return nil
+		}
+	}
+	return usecase.Validation("INVALID_BACKCHANNEL_LOGOUT_URI", "backchannelLogoutUri must use https")
+}
+
+// nonEmpty maps an empty string to nil.
+func nonEmpty(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
 }
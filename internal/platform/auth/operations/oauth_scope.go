@@ -0,0 +1,148 @@
+package operations
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// scopeNamePattern is the RFC 6749 §3.3 scope-token alphabet, narrowed to
+// what the platform's scopes actually use.
+var scopeNamePattern = regexp.MustCompile(`^[A-Za-z0-9:._-]{1,100}$`)
+
+func validateScopeDescription(description string) error {
+	d := strings.TrimSpace(description)
+	if d == "" {
+		return usecase.Validation("DESCRIPTION_REQUIRED", "description is required")
+	}
+	if len(d) > 500 {
+		return usecase.Validation("DESCRIPTION_TOO_LONG", "description must be at most 500 characters")
+	}
+	return nil
+}
+
+// ── Create ────────────────────────────────────────────────────────────────
+
+type CreateOAuthScopeCommand struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CreateOAuthScope defines the consent-screen text for a scope and emits
+// [OAuthScopeCreated]. Scope definitions are platform-level config
+// (Authorize: Public); the controller gates writes with auth.RequireAnchor.
+func CreateOAuthScope(repo *auth.OAuthScopeRepo) usecaseop.Operation[CreateOAuthScopeCommand, OAuthScopeCreated] {
+	return usecaseop.Operation[CreateOAuthScopeCommand, OAuthScopeCreated]{
+		Name: "CreateOAuthScope",
+		Validate: func(_ context.Context, cmd CreateOAuthScopeCommand) error {
+			if !scopeNamePattern.MatchString(strings.TrimSpace(cmd.Name)) {
+				return usecase.Validation("INVALID_SCOPE_NAME",
+					"name must be 1-100 characters of letters, digits, ':', '.', '_' or '-'")
+			}
+			return validateScopeDescription(cmd.Description)
+		},
+		Authorize: usecaseop.Public[CreateOAuthScopeCommand],
+		Execute: func(ctx context.Context, cmd CreateOAuthScopeCommand, ec usecase.ExecutionContext) (usecaseop.Plan[OAuthScopeCreated], error) {
+			name := strings.TrimSpace(cmd.Name)
+			existing, err := repo.FindByName(ctx, name)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_name failed", err)
+			}
+			if existing != nil {
+				return nil, usecase.Conflict("SCOPE_EXISTS", "Scope '"+name+"' is already defined")
+			}
+			s := auth.NewOAuthScope(name, strings.TrimSpace(cmd.Description))
+			event := OAuthScopeCreated{
+				Metadata:    usecase.NewEventMetadata(ec, OAuthScopeCreatedType, Source, scopeSubject(s.ID)),
+				ScopeID:     s.ID,
+				Name:        s.Name,
+				Description: s.Description,
+			}
+			return usecaseop.Save(s, repo, event), nil
+		},
+	}
+}
+
+// ── Update ────────────────────────────────────────────────────────────────
+
+type UpdateOAuthScopeCommand struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// UpdateOAuthScope changes a scope's consent-screen text and emits
+// [OAuthScopeUpdated]. The name is immutable: clients and stored consents
+// refer to it. Platform-level config (Authorize: Public); the controller
+// gates on anchor.
+func UpdateOAuthScope(repo *auth.OAuthScopeRepo) usecaseop.Operation[UpdateOAuthScopeCommand, OAuthScopeUpdated] {
+	return usecaseop.Operation[UpdateOAuthScopeCommand, OAuthScopeUpdated]{
+		Name: "UpdateOAuthScope",
+		Validate: func(_ context.Context, cmd UpdateOAuthScopeCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			return validateScopeDescription(cmd.Description)
+		},
+		Authorize: usecaseop.Public[UpdateOAuthScopeCommand],
+		Execute: func(ctx context.Context, cmd UpdateOAuthScopeCommand, ec usecase.ExecutionContext) (usecaseop.Plan[OAuthScopeUpdated], error) {
+			s, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if s == nil {
+				return nil, httperror.NotFound("OAuthScope", cmd.ID)
+			}
+			s.Description = strings.TrimSpace(cmd.Description)
+			event := OAuthScopeUpdated{
+				Metadata:    usecase.NewEventMetadata(ec, OAuthScopeUpdatedType, Source, scopeSubject(s.ID)),
+				ScopeID:     s.ID,
+				Name:        s.Name,
+				Description: s.Description,
+			}
+			return usecaseop.Save(s, repo, event), nil
+		},
+	}
+}
+
+// ── Delete ────────────────────────────────────────────────────────────────
+
+type DeleteOAuthScopeCommand struct {
+	ID string `json:"id"`
+}
+
+// DeleteOAuthScope removes a scope definition and emits [OAuthScopeDeleted].
+// The consent screen falls back to the built-in text (standard scopes) or
+// the bare scope name. Platform-level config (Authorize: Public); the
+// controller gates on anchor.
+func DeleteOAuthScope(repo *auth.OAuthScopeRepo) usecaseop.Operation[DeleteOAuthScopeCommand, OAuthScopeDeleted] {
+	return usecaseop.Operation[DeleteOAuthScopeCommand, OAuthScopeDeleted]{
+		Name: "DeleteOAuthScope",
+		Validate: func(_ context.Context, cmd DeleteOAuthScopeCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[DeleteOAuthScopeCommand],
+		Execute: func(ctx context.Context, cmd DeleteOAuthScopeCommand, ec usecase.ExecutionContext) (usecaseop.Plan[OAuthScopeDeleted], error) {
+			s, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if s == nil {
+				return nil, httperror.NotFound("OAuthScope", cmd.ID)
+			}
+			event := OAuthScopeDeleted{
+				Metadata: usecase.NewEventMetadata(ec, OAuthScopeDeletedType, Source, scopeSubject(s.ID)),
+				ScopeID:  s.ID,
+				Name:     s.Name,
+			}
+			return usecaseop.Delete(s, repo, event), nil
+		},
+	}
+}
@@ -4,8 +4,6 @@ package operations_test
 
 import (
 	"context"
-	"os"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,33 +11,22 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/operations"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
-// TestMain seeds FLOWCATALYST_APP_KEY before the embedded-PG boot:
-// CONFIDENTIAL client creation / secret rotation encrypt the secret via
-// encryption.FromEnv, which reads the env at call time. os.Setenv (not
-// t.Setenv) because every test here runs t.Parallel().
-func TestMain(m *testing.M) {
-	key, err := encryption.GenerateKey()
-	if err != nil {
-		panic(err)
-	}
-	_ = os.Setenv("FLOWCATALYST_APP_KEY", key)
-	testpg.RunMain(m)
-}
+func TestMain(m *testing.M) { testpg.RunMain(m) }
 
 // runAuthorized drives op through the full use-case envelope (Validate →
 // Authorize → Execute → atomic commit) as an anchor principal. These auth
-// resources (OAuth clients, anchor domains, auth configs, IDP role mappings)
-// are platform-level config: the operations are intentionally open
-// (Authorize: Public) and the anchor gate lives on the controller, so there
-// is no use-case authorization-denial test here. It mirrors how the HTTP
-// handler runs the operation.
+// resources (OAuth clients, anchor domains, auth configs, IDP role mappings,
+// scope definitions) are platform-level config: the operations are
+// intentionally open (Authorize: Public) and the anchor gate lives on the
+// controller, so there is no use-case authorization-denial test here. It
+// mirrors how the HTTP handler runs the operation.
 func runAuthorized[C any, E usecase.DomainEvent](
 	uow *usecasepgx.UnitOfWork, op usecaseop.Operation[C, E], cmd C,
 ) (E, error) {
@@ -492,20 +479,15 @@ func TestCreateOAuthClient_ConfidentialSecretStash(t *testing.T) {
 	assert.False(t, ok, "second pop must miss — stash is one-shot")
 	assert.Empty(t, again)
 
-	// At rest: "encrypted:"-prefixed envelope (Rust wire parity) that
-	// decrypts back to the popped plaintext (decrypt-and-compare contract).
+	// At rest: an argon2id hash that verifies the popped plaintext; the
+	// plaintext itself is never stored.
 	got, err := repo.FindByID(ctx, ev.OAuthClientID)
 	require.NoError(t, err)
 	require.NotNil(t, got)
 	assert.Equal(t, auth.OAuthClientConfidential, got.ClientType)
 	require.NotNil(t, got.SecretRef)
-	assert.True(t, strings.HasPrefix(*got.SecretRef, "encrypted:"))
-	enc, err := encryption.FromEnv()
-	require.NoError(t, err)
-	require.NotNil(t, enc)
-	decrypted, err := enc.Decrypt(*got.SecretRef)
-	require.NoError(t, err)
-	assert.Equal(t, plaintext, decrypted)
+	assert.True(t, passwordhash.IsHash(*got.SecretRef))
+	assert.NoError(t, passwordhash.Verify(plaintext, *got.SecretRef))
 }
 
 func TestCreateOAuthClient_Validation(t *testing.T) {
@@ -536,6 +518,43 @@ func TestCreateOAuthClient_DuplicateClientID_Conflict(t *testing.T) {
 	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "CLIENT_ID_EXISTS")
 }
 
+func TestCreateOAuthClient_PrivateKeyJWT(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := auth.NewRepository(testpg.Pool(t)).OAuthClients
+	uow := testpg.NewUoW(t)
+	jwks := `{"keys":[{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}]}`
+
+	_, err := runAuthorized(uow, operations.CreateOAuthClient(repo), operations.CreateOAuthClientCommand{
+		ClientName: "No keys", ClientType: "CONFIDENTIAL", TokenEndpointAuthMethod: "private_key_jwt",
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "JWKS_REQUIRED")
+
+	bad := `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`
+	_, err = runAuthorized(uow, operations.CreateOAuthClient(repo), operations.CreateOAuthClientCommand{
+		ClientName: "Shared key", ClientType: "CONFIDENTIAL", TokenEndpointAuthMethod: "private_key_jwt", JWKS: &bad,
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "INVALID_JWKS")
+
+	ev, err := runAuthorized(uow, operations.CreateOAuthClient(repo), operations.CreateOAuthClientCommand{
+		ClientName: "Key client", ClientType: "CONFIDENTIAL", TokenEndpointAuthMethod: "private_key_jwt", JWKS: &jwks,
+	})
+	require.NoError(t, err)
+	_, ok := operations.PopStashedSecret(ev.OAuthClientID)
+	assert.False(t, ok, "a private_key_jwt client gets no secret")
+
+	got, err := repo.FindByID(ctx, ev.OAuthClientID)
+	require.NoError(t, err)
+	assert.Equal(t, auth.AuthMethodPrivateKeyJWT, got.TokenEndpointAuthMethod)
+	require.NotNil(t, got.JWKS)
+	assert.JSONEq(t, jwks, *got.JWKS)
+	assert.Nil(t, got.SecretRef)
+
+	_, err = runAuthorized(uow, operations.RotateOAuthClientSecret(repo),
+		operations.RotateOAuthClientSecretCommand{ID: ev.OAuthClientID})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "NOT_SECRET_AUTH")
+}
+
 func TestUpdateOAuthClient_HappyPath(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -695,13 +714,9 @@ func TestRotateOAuthClientSecret_HappyPathAndStash(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, after.SecretRef)
 	assert.NotEqual(t, oldRef, *after.SecretRef, "stored ref must change on rotation")
-	assert.True(t, strings.HasPrefix(*after.SecretRef, "encrypted:"))
-	enc, err := encryption.FromEnv()
-	require.NoError(t, err)
-	require.NotNil(t, enc)
-	decrypted, err := enc.Decrypt(*after.SecretRef)
-	require.NoError(t, err)
-	assert.Equal(t, rotated, decrypted)
+	assert.True(t, passwordhash.IsHash(*after.SecretRef))
+	assert.NoError(t, passwordhash.Verify(rotated, *after.SecretRef))
+	assert.Error(t, passwordhash.Verify(createSecret, *after.SecretRef), "the old secret must stop verifying")
 }
 
 func TestRotateOAuthClientSecret_PublicClient_Conflict(t *testing.T) {
@@ -728,3 +743,59 @@ func TestRotateOAuthClientSecret_Errors(t *testing.T) {
 		operations.RotateOAuthClientSecretCommand{ID: "oac_doesnotexist1"})
 	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "OAuthClient_NOT_FOUND")
 }
+
+// ══ OAuthScope ════════════════════════════════════════════════════════════
+
+func TestOAuthScope_CreateUpdateDelete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := auth.NewRepository(testpg.Pool(t)).OAuthScopes
+	uow := testpg.NewUoW(t)
+
+	ev, err := runAuthorized(uow, operations.CreateOAuthScope(repo),
+		operations.CreateOAuthScopeCommand{Name: " scopetest:orders:read ", Description: "Read your orders"})
+	require.NoError(t, err)
+	assert.Equal(t, "scopetest:orders:read", ev.Name, "name is trimmed")
+
+	_, err = runAuthorized(uow, operations.CreateOAuthScope(repo),
+		operations.CreateOAuthScopeCommand{Name: "scopetest:orders:read", Description: "Again"})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "SCOPE_EXISTS")
+
+	_, err = runAuthorized(uow, operations.UpdateOAuthScope(repo),
+		operations.UpdateOAuthScopeCommand{ID: ev.ScopeID, Description: "See your orders"})
+	require.NoError(t, err)
+	got, err := repo.FindByName(ctx, "scopetest:orders:read")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "See your orders", got.Description)
+
+	_, err = runAuthorized(uow, operations.DeleteOAuthScope(repo),
+		operations.DeleteOAuthScopeCommand{ID: ev.ScopeID})
+	require.NoError(t, err)
+	got, err = repo.FindByID(ctx, ev.ScopeID)
+	require.NoError(t, err)
+	assert.Nil(t, got, "deleted row must be gone")
+}
+
+func TestCreateOAuthScope_Validation(t *testing.T) {
+	t.Parallel()
+	repo := auth.NewRepository(testpg.Pool(t)).OAuthScopes
+	uow := testpg.NewUoW(t)
+
+	cases := []struct {
+		name string
+		cmd  operations.CreateOAuthScopeCommand
+		code string
+	}{
+		{"empty name", operations.CreateOAuthScopeCommand{Description: "x"}, "INVALID_SCOPE_NAME"},
+		{"space in name", operations.CreateOAuthScopeCommand{Name: "orders read", Description: "x"}, "INVALID_SCOPE_NAME"},
+		{"no description", operations.CreateOAuthScopeCommand{Name: "scopetest:nodesc"}, "DESCRIPTION_REQUIRED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := runAuthorized(uow, operations.CreateOAuthScope(repo), tc.cmd)
+			testpg.RequireUsecaseError(t, err, usecase.KindValidation, tc.code)
+		})
+	}
+}
@@ -19,9 +19,10 @@
 // backwards-compatible: each row carries the params it was hashed with.
 //
 // Callers are principal/user passwords (create, reset, seed, login
-// verify) — they go through `Hash` + `Verify`, there is no other path.
-// OAuth client secrets do NOT use this; they're reversibly encrypted via
-// shared/encryption (decrypt + compare), matching Rust.
+// verify) and OAuth client secrets (minted on create/rotate, verified at
+// /oauth/token) — both go through `Hash` + `Verify`. Client secrets
+// minted before hashing are still reversibly encrypted (shared/encryption);
+// `IsHash` tells the two apart.
 package passwordhash
 
 import (
@@ -115,6 +116,12 @@ func Verify(plaintext, encoded string) error {
 	return nil
 }
 
+// IsHash reports whether s is a hash this package can verify (an argon2
+// PHC string or bcrypt), as opposed to some other stored secret form.
+func IsHash(s string) bool {
+	return strings.HasPrefix(s, "$argon2") || isBcrypt(s)
+}
+
 // isBcrypt reports whether the encoded hash is a bcrypt string (the Laravel
 // default password algorithm).
 func isBcrypt(s string) bool {
@@ -0,0 +1,113 @@
+// Package passwordpolicy holds the local-auth password rules: complexity on
+// every path that sets a password (create user, admin reset, self-service
+// reset/invite, profile change) and the optional maximum password age the
+// login endpoint enforces. Port of the Rust PasswordPolicy, with the
+// complexity classes the Rust default carries made env-configurable.
+//
+// The policy is read once from FC_PASSWORD_* (see Current); federated users
+// never hit it — they have no local password.
+package passwordpolicy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// maxLength bounds the input argon2 has to chew through, so a multi-megabyte
+// "password" can't be used to burn CPU on the hash.
+const maxLength = 256
+
+// Policy is the set of rules a new password must satisfy.
+type Policy struct {
+	MinLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+	// MaxAge expires a password this long after it was set; zero never
+	// expires. Only enforced at password login.
+	MaxAge time.Duration
+}
+
+// Default is the strict policy with no env overrides: 8 characters, no
+// class requirements, no expiry (Rust PasswordPolicy::default min_length).
+func Default() Policy { return Policy{MinLength: 8} }
+
+// Relaxed is the opt-out used when a caller passes
+// enforcePasswordComplexity=false and owns its own policy (Rust
+// PasswordPolicy::relaxed): a 2-character floor and nothing else. Expiry
+// still follows the configured policy.
+func (p Policy) Relaxed() Policy { return Policy{MinLength: 2, MaxAge: p.MaxAge} }
+
+// PolicyFromEnv builds a Policy from FC_PASSWORD_* env vars.
+func PolicyFromEnv() Policy {
+	return Policy{
+		MinLength:      envutil.Int("FC_PASSWORD_MIN_LENGTH", 8),
+		RequireUpper:   envutil.Bool("FC_PASSWORD_REQUIRE_UPPERCASE", false),
+		RequireLower:   envutil.Bool("FC_PASSWORD_REQUIRE_LOWERCASE", false),
+		RequireDigit:   envutil.Bool("FC_PASSWORD_REQUIRE_DIGIT", false),
+		RequireSpecial: envutil.Bool("FC_PASSWORD_REQUIRE_SPECIAL", false),
+		MaxAge:         time.Duration(envutil.Int("FC_PASSWORD_MAX_AGE_DAYS", 0)) * 24 * time.Hour,
+	}
+}
+
+// Current is the process-wide policy, read from the environment on first
+// use. Operations call it rather than taking the policy as a dependency so
+// every password-setting path agrees without threading it through wiring.
+var Current = sync.OnceValue(PolicyFromEnv)
+
+// Check validates pw, returning a usecase validation error (400) naming
+// every unmet rule so the user can fix them in one go.
+func (p Policy) Check(pw string) error {
+	n := len([]rune(pw))
+	if n < p.MinLength {
+		return usecase.Validation("PASSWORD_TOO_SHORT",
+			fmt.Sprintf("password must be at least %d characters", p.MinLength))
+	}
+	if n > maxLength {
+		return usecase.Validation("PASSWORD_TOO_LONG",
+			fmt.Sprintf("password must be at most %d characters", maxLength))
+	}
+	var upper, lower, digit, special bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsSpace(r):
+			special = true
+		}
+	}
+	var missing []string
+	if p.RequireUpper && !upper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		missing = append(missing, "a digit")
+	}
+	if p.RequireSpecial && !special {
+		missing = append(missing, "a symbol")
+	}
+	if len(missing) > 0 {
+		return usecase.Validation("PASSWORD_TOO_WEAK", "password must contain "+strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Expired reports whether a password set at changedAt is past MaxAge. An
+// unknown change time (nil) never expires.
+func (p Policy) Expired(changedAt *time.Time, now time.Time) bool {
+	return p.MaxAge > 0 && changedAt != nil && now.Sub(*changedAt) > p.MaxAge
+}
@@ -0,0 +1,82 @@
+package passwordpolicy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+func code(err error) string {
+	var ue *usecase.Error
+	if errors.As(err, &ue) {
+		return ue.Code
+	}
+	return ""
+}
+
+func TestCheckLength(t *testing.T) {
+	p := Default()
+	if got := code(p.Check("short")); got != "PASSWORD_TOO_SHORT" {
+		t.Errorf("short: got %q", got)
+	}
+	if err := p.Check("longenough"); err != nil {
+		t.Errorf("8+ chars with no class rules should pass: %v", err)
+	}
+	if got := code(p.Check(strings.Repeat("a", maxLength+1))); got != "PASSWORD_TOO_LONG" {
+		t.Errorf("long: got %q", got)
+	}
+	if err := p.Relaxed().Check("ab"); err != nil {
+		t.Errorf("relaxed 2-char floor: %v", err)
+	}
+}
+
+func TestCheckClasses(t *testing.T) {
+	p := Policy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSpecial: true}
+	err := p.Check("alllowercase")
+	if code(err) != "PASSWORD_TOO_WEAK" {
+		t.Fatalf("got %v", err)
+	}
+	for _, want := range []string{"uppercase", "digit", "symbol"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("message %q should mention %s", err.Error(), want)
+		}
+	}
+	if strings.Contains(err.Error(), "lowercase letter") {
+		t.Errorf("message %q should not ask for a lowercase letter", err.Error())
+	}
+	if err := p.Check("Tr0ub4dor&3"); err != nil {
+		t.Errorf("compliant password rejected: %v", err)
+	}
+	if err := p.Relaxed().Check("ab"); err != nil {
+		t.Errorf("relaxed drops class rules: %v", err)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-91 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	p := Policy{MaxAge: 90 * 24 * time.Hour}
+	if !p.Expired(&old, now) {
+		t.Error("91-day-old password should be expired")
+	}
+	if p.Expired(&recent, now) || p.Expired(nil, now) {
+		t.Error("recent or unknown change time must not expire")
+	}
+	if Default().Expired(&old, now) {
+		t.Error("MaxAge=0 never expires")
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	t.Setenv("FC_PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("FC_PASSWORD_REQUIRE_DIGIT", "true")
+	t.Setenv("FC_PASSWORD_MAX_AGE_DAYS", "30")
+	p := PolicyFromEnv()
+	if p.MinLength != 12 || !p.RequireDigit || p.RequireUpper || p.MaxAge != 30*24*time.Hour {
+		t.Fatalf("unexpected policy: %+v", p)
+	}
+}
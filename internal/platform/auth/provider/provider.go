@@ -10,6 +10,7 @@
 //	Claims, BuildClaims — project a principal onto the JWT claim shape
 //	FlattenPermissions  — resolve role names → permission set
 //	Mint/ValidateSessionToken — /auth/login session cookies
+//	MintImpersonationToken — support staff acting as one client
 //	SigningKey, Issuer, AccessTokenTTL — shared config accessors
 package provider
 
@@ -22,7 +23,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/revocation"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/sessiontoken"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/signingkey"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
 )
@@ -228,8 +231,28 @@ type Provider struct {
 	// /auth/login cookies) and authservice (for /oauth/token JWTs) — all
 	// three sign with the same pair so JWKS + cookie validation line up.
 	signingKey *rsa.PrivateKey
+	// keys, when set, replaces signingKey for session tokens: mint with
+	// the ring's signer (kid-stamped), validate against every ring key.
+	// signingKey stays the configured base key (mfatoken derives from it).
+	keys *signingkey.Ring
+	// revocations, when set, rejects revoked tokens on validation.
+	revocations RevocationChecker
 }
 
+// SetKeyRing switches session tokens onto a rotating key ring. Set once
+// at startup, before the provider serves requests.
+func (p *Provider) SetKeyRing(r *signingkey.Ring) { p.keys = r }
+
+// RevocationChecker reports whether a validated token has since been
+// revoked. Satisfied by *revocation.Checker.
+type RevocationChecker interface {
+	Revoked(ctx context.Context, principalID, tokenID string, issuedAt time.Time) (bool, error)
+}
+
+// SetRevocations enables the revocation check in ValidateSessionToken. Set
+// once at startup, before the provider serves requests.
+func (p *Provider) SetRevocations(r RevocationChecker) { p.revocations = r }
+
 // NewProvider parses the RSA signing key and wires the claims/session
 // helpers. Returns an error if the RSA key is missing or malformed.
 func NewProvider(cfg Config, principals *principal.Repository, roles *role.Repository) (*Provider, error) {
@@ -294,10 +317,52 @@ func (p *Provider) MintSessionToken(ctx context.Context, principalID string, ttl
 	//   2. Size: the flattened permission set for a privileged principal can
 	//      push the JWT past the browser's ~4KB per-cookie limit, making the
 	//      browser silently DROP fc_session so the session never establishes.
-	return sessiontoken.Mint(sessiontoken.Claims{
+	sc := sessiontoken.Claims{
 		Subject: c.Subject,
 		Email:   c.Email,
-	}, p.signingKey, p.cfg.Issuer, ttl)
+	}
+	return p.mint(sc, ttl)
+}
+
+// MintImpersonationToken issues a bearer token that lets principalID act
+// as clientID: tier CLIENT, clients = [clientID], and the "impersonating"
+// claim naming the client. sub stays the support principal, so the audit
+// trail of every write made with it names them. Unlike the session cookie
+// it carries the principal's permissions — the middleware authorizes
+// impersonation tokens from their claims alone — so ttl should be short.
+// tokenID becomes the jti, tying the token to the audit record of its
+// minting; revoking the principal's sessions revokes it too.
+func (p *Provider) MintImpersonationToken(ctx context.Context, principalID, clientID, tokenID string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", errors.New("impersonation token needs a positive ttl")
+	}
+	c, err := BuildClaims(ctx, p.cfg, p.principals, p.roles, principalID)
+	if err != nil {
+		return "", fmt.Errorf("build claims: %w", err)
+	}
+	sc := sessiontoken.Claims{
+		Subject:         c.Subject,
+		Scope:           string(principal.ScopeClient),
+		Email:           c.Email,
+		Clients:         []string{clientID},
+		Roles:           c.Roles,
+		Applications:    c.Applications,
+		AllApplications: c.AllApplications,
+		Permissions:     c.Permissions,
+		ID:              tokenID,
+		Impersonating:   clientID,
+	}
+	return p.mint(sc, ttl)
+}
+
+// mint signs sc with the ring's current signer, or the base key when no
+// ring is set.
+func (p *Provider) mint(sc sessiontoken.Claims, ttl time.Duration) (string, error) {
+	if p.keys != nil {
+		k := p.keys.Signer()
+		return sessiontoken.MintWithKeyID(sc, k.Private, k.ID, p.cfg.Issuer, ttl)
+	}
+	return sessiontoken.Mint(sc, p.signingKey, p.cfg.Issuer, ttl)
 }
 
 // ValidateSessionToken verifies a session-cookie JWT (signature + std
@@ -308,11 +373,33 @@ func (p *Provider) MintSessionToken(ctx context.Context, principalID string, ttl
 // authservice for /oauth/token), so the signature path lines up — which is
 // exactly why the audience expectation matters: OIDC ID tokens minted for
 // third-party RPs share that key too and must not validate here.
-func (p *Provider) ValidateSessionToken(_ context.Context, token string) (*sessiontoken.Claims, error) {
-	return sessiontoken.Validate(token, &p.signingKey.PublicKey, sessiontoken.Expect{
+//
+// A token that verifies is then checked against the revocation list, when
+// one is wired; a failing lookup rejects the token rather than letting a
+// possibly-revoked session through.
+func (p *Provider) ValidateSessionToken(ctx context.Context, token string) (*sessiontoken.Claims, error) {
+	expect := sessiontoken.Expect{
 		Issuer:   p.cfg.Issuer,
 		Audience: p.cfg.Audience,
-	})
+	}
+	var c *sessiontoken.Claims
+	var err error
+	if p.keys != nil {
+		c, err = sessiontoken.ValidateWithKeys(token, p.keys, expect)
+	} else {
+		c, err = sessiontoken.Validate(token, &p.signingKey.PublicKey, expect)
+	}
+	if err != nil || p.revocations == nil {
+		return c, err
+	}
+	revoked, err := p.revocations.Revoked(ctx, c.Subject, c.ID, c.IssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("revocation check: %w", err)
+	}
+	if revoked {
+		return nil, revocation.ErrRevoked
+	}
+	return c, nil
 }
 
 // parseRSAPrivateKey accepts PKCS#1 or PKCS#8 PEM blocks.
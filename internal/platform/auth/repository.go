@@ -23,6 +23,7 @@ type Repository struct {
 	AnchorDomains     *AnchorDomainRepo
 	ClientAuthConfigs *ClientAuthConfigRepo
 	IdpRoleMappings   *IdpRoleMappingRepo
+	OAuthScopes       *OAuthScopeRepo
 }
 
 // NewRepository wires the bundle.
@@ -33,6 +34,7 @@ func NewRepository(pool *pgxpool.Pool) *Repository {
 		AnchorDomains:     &AnchorDomainRepo{q: q},
 		ClientAuthConfigs: &ClientAuthConfigRepo{q: q},
 		IdpRoleMappings:   &IdpRoleMappingRepo{q: q},
+		OAuthScopes:       &OAuthScopeRepo{q: q},
 	}
 }
 
@@ -44,10 +46,14 @@ func NewRepository(pool *pgxpool.Pool) *Repository {
 // oauth_client_allowed_origins + oauth_client_application_ids. The
 // post-logout, allowed-origins, and application-ids junctions are
 // loaded/persisted via raw pgx (they aren't wired through sqlc).
-// client_secret_ref holds the reversibly-encrypted client secret
-// (AES-256-GCM under FLOWCATALYST_APP_KEY, "encrypted:"-prefixed),
-// matching Rust; it is verified at /oauth/token by decrypt-and-compare,
-// NOT by hashing. See internal/platform/shared/encryption.
+// client_secret_ref holds the argon2id PHC hash of the client secret
+// (see internal/platform/auth/passwordhash); rows minted before hashing
+// hold the reversibly-encrypted secret ("encrypted:"-prefixed, as Rust
+// writes it), which /oauth/token still verifies by decrypt-and-compare.
+// token_endpoint_auth_method is NULL for the default client-secret
+// methods. first_party (migration 073) defaults TRUE; only third-party
+// clients go through the consent screen. backchannel_logout_uri
+// (migration 074) is NULL for clients without back-channel logout.
 
 type OAuthClientRepo struct {
 	q    *dbq.Queries
@@ -107,6 +113,10 @@ func (r *OAuthClientRepo) Persist(ctx context.Context, c *OAuthClient, tx *useca
 		Active:                    c.Active,
 		CreatedAt:                 c.CreatedAt,
 		UpdatedAt:                 now,
+		TokenEndpointAuthMethod:   authMethodColumn(c.TokenEndpointAuthMethod),
+		Jwks:                      c.JWKS,
+		FirstParty:                c.FirstParty,
+		BackchannelLogoutUri:      c.BackchannelLogoutURI,
 	}); err != nil {
 		return fmt.Errorf("oauth_client persist: %w", err)
 	}
@@ -316,6 +326,9 @@ func rowToOAuthClient(row dbq.OauthClient) *OAuthClient {
 		PKCERequired:           row.PkceRequired,
 		Active:                 row.Active,
 		PrincipalID:            row.ServiceAccountPrincipalID,
+		JWKS:                   row.Jwks,
+		FirstParty:             row.FirstParty,
+		BackchannelLogoutURI:   row.BackchannelLogoutUri,
 		CreatedAt:              row.CreatedAt,
 		UpdatedAt:              row.UpdatedAt,
 		RedirectURIs:           []string{},
@@ -325,6 +338,10 @@ func rowToOAuthClient(row dbq.OauthClient) *OAuthClient {
 		AllowedOrigins:         []string{},
 		ApplicationIDs:         []string{},
 	}
+	c.TokenEndpointAuthMethod = AuthMethodClientSecret
+	if row.TokenEndpointAuthMethod != nil {
+		c.TokenEndpointAuthMethod = ParseOAuthClientAuthMethod(*row.TokenEndpointAuthMethod)
+	}
 	if row.DefaultScopes != nil && *row.DefaultScopes != "" {
 		for _, s := range strings.Split(*row.DefaultScopes, ",") {
 			if s != "" {
@@ -335,6 +352,15 @@ func rowToOAuthClient(row dbq.OauthClient) *OAuthClient {
 	return &c
 }
 
+// authMethodColumn stores the default client-secret method as NULL.
+func authMethodColumn(m OAuthClientAuthMethod) *string {
+	if m == "" || m == AuthMethodClientSecret {
+		return nil
+	}
+	s := string(m)
+	return &s
+}
+
 // ── AnchorDomain repo ─────────────────────────────────────────────────────
 
 type AnchorDomainRepo struct{ q *dbq.Queries }
@@ -591,3 +617,68 @@ func rowToIdpMapping(row dbq.OauthIdpRoleMapping) *IdpRoleMapping {
 		UpdatedAt:        row.UpdatedAt,
 	}
 }
+
+// ── OAuthScope repo ───────────────────────────────────────────────────────
+//
+// oauth_scopes (migration 073) is Go-only: consent-screen text per scope
+// name. Scopes without a row fall back to StandardScopeDescriptions.
+
+type OAuthScopeRepo struct{ q *dbq.Queries }
+
+func (r *OAuthScopeRepo) FindByID(ctx context.Context, id string) (*OAuthScope, error) {
+	row, err := r.q.OAuthScopeFindByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oauth_scope repo: %w", err)
+	}
+	return rowToOAuthScope(row), nil
+}
+
+func (r *OAuthScopeRepo) FindByName(ctx context.Context, name string) (*OAuthScope, error) {
+	row, err := r.q.OAuthScopeFindByName(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oauth_scope repo: %w", err)
+	}
+	return rowToOAuthScope(row), nil
+}
+
+func (r *OAuthScopeRepo) FindAll(ctx context.Context) ([]OAuthScope, error) {
+	rows, err := r.q.OAuthScopeFindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]OAuthScope, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *rowToOAuthScope(row))
+	}
+	return out, nil
+}
+
+func (r *OAuthScopeRepo) Persist(ctx context.Context, s *OAuthScope, tx *usecasepgx.DbTx) error {
+	return r.q.WithTx(tx.Inner()).OAuthScopeUpsert(ctx, dbq.OAuthScopeUpsertParams{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   time.Now().UTC(),
+	})
+}
+
+func (r *OAuthScopeRepo) Delete(ctx context.Context, s *OAuthScope, tx *usecasepgx.DbTx) error {
+	return r.q.WithTx(tx.Inner()).OAuthScopeDelete(ctx, s.ID)
+}
+
+func rowToOAuthScope(row dbq.OauthScope) *OAuthScope {
+	return &OAuthScope{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+// Package revocation is the platform's token revocation list. Platform JWTs
+// (session cookies, OAuth access tokens) are validated statelessly, so a
+// token stays usable until it expires unless something here says otherwise:
+//
+//   - RevokeAll rejects every token a principal holds that was issued at or
+//     before now (offboarding, suspected compromise).
+//   - RevokeToken rejects one token by its jti (logout of one session).
+//
+// Rows live in iam_session_revocations (direct writes, no UoW — like
+// loginattempt, this is auth infrastructure rather than a domain
+// aggregate). Checker puts a short-TTL per-principal cache in front so the
+// per-request check rarely leaves the process.
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrRevoked is returned by token validation for a revoked token.
+var ErrRevoked = errors.New("token has been revoked")
+
+// Entry is a principal's revocation state.
+type Entry struct {
+	// RevokedBefore rejects tokens issued at or before it (second
+	// precision, matching iat). Zero when the principal was never revoked.
+	RevokedBefore time.Time
+	// TokenIDs holds individually revoked, not yet expired jtis.
+	TokenIDs map[string]struct{}
+}
+
+// Revokes reports whether a token with the given jti and iat is revoked.
+// A token without an iat can't prove it postdates a revoke-all, so it is
+// rejected once one exists.
+func (e *Entry) Revokes(tokenID string, issuedAt time.Time) bool {
+	if e == nil {
+		return false
+	}
+	if !e.RevokedBefore.IsZero() && !issuedAt.After(e.RevokedBefore) {
+		return true
+	}
+	_, ok := e.TokenIDs[tokenID]
+	return ok && tokenID != ""
+}
+
+// Store is the persistence contract. Satisfied by *Repository.
+type Store interface {
+	Load(ctx context.Context, principalID string) (*Entry, error)
+	RevokeAll(ctx context.Context, principalID string, at time.Time, revokedBy *string) error
+	RevokeToken(ctx context.Context, principalID, tokenID string, expiresAt time.Time, revokedBy *string) error
+}
+
+// Repository reads/writes iam_session_revocations.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+// Load returns the principal's revocation state; an empty Entry when there
+// is none.
+func (r *Repository) Load(ctx context.Context, principalID string) (*Entry, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT token_id, revoked_at FROM iam_session_revocations
+		  WHERE principal_id = $1 AND (expires_at IS NULL OR expires_at > NOW())`, principalID)
+	if err != nil {
+		return nil, fmt.Errorf("revocation load: %w", err)
+	}
+	defer rows.Close()
+	e := &Entry{TokenIDs: map[string]struct{}{}}
+	for rows.Next() {
+		var tokenID string
+		var at time.Time
+		if err := rows.Scan(&tokenID, &at); err != nil {
+			return nil, fmt.Errorf("revocation load: %w", err)
+		}
+		if tokenID == "" {
+			e.RevokedBefore = at.UTC()
+		} else {
+			e.TokenIDs[tokenID] = struct{}{}
+		}
+	}
+	return e, rows.Err()
+}
+
+// RevokeAll moves the principal's revoke-all cutoff to at, truncated to
+// the second: iat has second precision, so a token minted earlier in the
+// same second must not slip through. Expired single-token rows are pruned
+// in the same round trip.
+func (r *Repository) RevokeAll(ctx context.Context, principalID string, at time.Time, revokedBy *string) error {
+	_, err := r.pool.Exec(ctx,
+		`WITH pruned AS (
+		     DELETE FROM iam_session_revocations
+		      WHERE principal_id = $1 AND expires_at < NOW()
+		 )
+		 INSERT INTO iam_session_revocations (principal_id, token_id, revoked_at, revoked_by)
+		 VALUES ($1, '', $2, $3)
+		 ON CONFLICT (principal_id, token_id)
+		 DO UPDATE SET revoked_at = EXCLUDED.revoked_at, revoked_by = EXCLUDED.revoked_by`,
+		principalID, at.UTC().Truncate(time.Second), revokedBy)
+	if err != nil {
+		return fmt.Errorf("revocation revoke all: %w", err)
+	}
+	return nil
+}
+
+// RevokeToken revokes one token until its own expiry.
+func (r *Repository) RevokeToken(ctx context.Context, principalID, tokenID string, expiresAt time.Time, revokedBy *string) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO iam_session_revocations (principal_id, token_id, revoked_at, expires_at, revoked_by)
+		 VALUES ($1, $2, NOW(), $3, $4)
+		 ON CONFLICT (principal_id, token_id) DO NOTHING`,
+		principalID, tokenID, expiresAt.UTC(), revokedBy)
+	if err != nil {
+		return fmt.Errorf("revocation revoke token: %w", err)
+	}
+	return nil
+}
+
+// Checker is the per-request read path plus the write helpers that keep
+// this instance's cache coherent. Other instances see a revocation once
+// their cached entry ages out (ttl), which bounds propagation.
+type Checker struct {
+	store Store
+	local *lru.LRU[string, *Entry]
+}
+
+// NewChecker builds a Checker. size bounds the local cache; ttl <= 0
+// disables it (every check reads the store).
+func NewChecker(store Store, size int, ttl time.Duration) *Checker {
+	c := &Checker{store: store}
+	if ttl > 0 {
+		c.local = lru.NewLRU[string, *Entry](size, nil, ttl)
+	}
+	return c
+}
+
+// Revoked reports whether the token is revoked. Store errors are returned,
+// not swallowed: callers fail closed.
+func (c *Checker) Revoked(ctx context.Context, principalID, tokenID string, issuedAt time.Time) (bool, error) {
+	if c.local != nil {
+		if e, ok := c.local.Get(principalID); ok {
+			return e.Revokes(tokenID, issuedAt), nil
+		}
+	}
+	e, err := c.store.Load(ctx, principalID)
+	if err != nil {
+		return false, err
+	}
+	if c.local != nil {
+		c.local.Add(principalID, e)
+	}
+	return e.Revokes(tokenID, issuedAt), nil
+}
+
+// RevokeAll revokes every current token for the principal.
+func (c *Checker) RevokeAll(ctx context.Context, principalID string, revokedBy *string) error {
+	if err := c.store.RevokeAll(ctx, principalID, time.Now(), revokedBy); err != nil {
+		return err
+	}
+	c.evict(principalID)
+	return nil
+}
+
+// RevokeToken revokes one token. expiresAt is the token's exp; a token
+// without one is kept on the list for a day, beyond any platform TTL.
+func (c *Checker) RevokeToken(ctx context.Context, principalID, tokenID string, expiresAt time.Time, revokedBy *string) error {
+	if tokenID == "" {
+		return errors.New("revocation: token has no id")
+	}
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(24 * time.Hour)
+	}
+	if err := c.store.RevokeToken(ctx, principalID, tokenID, expiresAt, revokedBy); err != nil {
+		return err
+	}
+	c.evict(principalID)
+	return nil
+}
+
+func (c *Checker) evict(principalID string) {
+	if c.local != nil {
+		c.local.Remove(principalID)
+	}
+}
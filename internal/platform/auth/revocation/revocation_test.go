@@ -0,0 +1,96 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	entries map[string]*Entry
+	loads   int
+	err     error
+}
+
+func (f *fakeStore) Load(_ context.Context, principalID string) (*Entry, error) {
+	f.loads++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.entries[principalID], nil
+}
+
+func (f *fakeStore) entry(principalID string) *Entry {
+	if f.entries == nil {
+		f.entries = map[string]*Entry{}
+	}
+	e, ok := f.entries[principalID]
+	if !ok {
+		e = &Entry{TokenIDs: map[string]struct{}{}}
+		f.entries[principalID] = e
+	}
+	return e
+}
+
+func (f *fakeStore) RevokeAll(_ context.Context, principalID string, at time.Time, _ *string) error {
+	f.entry(principalID).RevokedBefore = at.Truncate(time.Second)
+	return nil
+}
+
+func (f *fakeStore) RevokeToken(_ context.Context, principalID, tokenID string, _ time.Time, _ *string) error {
+	f.entry(principalID).TokenIDs[tokenID] = struct{}{}
+	return nil
+}
+
+func TestEntry_Revokes(t *testing.T) {
+	cutoff := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := &Entry{RevokedBefore: cutoff, TokenIDs: map[string]struct{}{"jti-1": {}}}
+
+	assert.True(t, e.Revokes("x", cutoff.Add(-time.Hour)))
+	assert.True(t, e.Revokes("x", cutoff), "a token issued in the revoking second is revoked")
+	assert.False(t, e.Revokes("x", cutoff.Add(time.Second)))
+	assert.True(t, e.Revokes("jti-1", cutoff.Add(time.Hour)))
+	assert.True(t, e.Revokes("", time.Time{}), "no iat can't postdate a revoke-all")
+
+	assert.False(t, (&Entry{}).Revokes("", time.Time{}), "an empty jti never matches")
+	assert.False(t, (*Entry)(nil).Revokes("jti-1", cutoff))
+}
+
+func TestChecker_RevokeEvictsCache(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	c := NewChecker(store, 10, time.Minute)
+	issued := time.Now().Add(-time.Minute)
+
+	revoked, err := c.Revoked(ctx, "prn_1", "jti-1", issued)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	_, _ = c.Revoked(ctx, "prn_1", "jti-1", issued)
+	assert.Equal(t, 1, store.loads, "second check is served from cache")
+
+	require.NoError(t, c.RevokeToken(ctx, "prn_1", "jti-1", time.Time{}, nil))
+	revoked, err = c.Revoked(ctx, "prn_1", "jti-1", issued)
+	require.NoError(t, err)
+	assert.True(t, revoked, "revoking drops the stale cached entry")
+	revoked, _ = c.Revoked(ctx, "prn_1", "jti-2", issued)
+	assert.False(t, revoked, "other sessions survive a single-token revoke")
+
+	require.NoError(t, c.RevokeAll(ctx, "prn_1", nil))
+	revoked, _ = c.Revoked(ctx, "prn_1", "jti-2", issued)
+	assert.True(t, revoked)
+	revoked, _ = c.Revoked(ctx, "prn_1", "jti-3", time.Now().Add(time.Minute))
+	assert.False(t, revoked, "tokens issued after a revoke-all are valid")
+
+	assert.Error(t, c.RevokeToken(ctx, "prn_1", "", time.Time{}, nil))
+}
+
+func TestChecker_StoreErrorFailsClosed(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+	c := NewChecker(store, 10, 0)
+	_, err := c.Revoked(context.Background(), "prn_1", "jti-1", time.Now())
+	assert.Error(t, err)
+}
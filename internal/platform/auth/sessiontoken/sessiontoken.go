@@ -14,12 +14,14 @@
 //	  "iat":   <unix>,
 //	  "exp":   <unix>,
 //	  "nbf":   <unix>,
+//	  "jti":   <token id>,
 //	  "tier":  "ANCHOR" | "PARTNER" | "CLIENT",
 //	  "scope": "perm:a:b:c perm:d:e:f"   (space-delimited granted permissions),
 //	  "email": "...",
 //	  "clients":     [...],
 //	  "roles":       [...],
-//	  "applications": [...]
+//	  "applications": [...],
+//	  "impersonating": <client id>       (impersonation tokens only)
 //	}
 //
 // Same claim names + types the auth middleware reads, so session-cookie
@@ -37,6 +39,8 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
 // Claims is the payload sessiontoken mints + reads. Mirrors the
@@ -57,6 +61,19 @@ type Claims struct {
 	// IssuedAt is the token's `iat` (when it was minted ≈ login time).
 	// Zero if the token carried no iat. Used for OIDC max_age enforcement.
 	IssuedAt time.Time
+	// ID is the token's `jti`, the handle single-session revocation keys
+	// on. Mint uses it when set and generates one otherwise; empty for
+	// tokens minted before jti was added.
+	ID string
+	// ExpiresAt is the token's `exp`. Zero if it carried none.
+	ExpiresAt time.Time
+	// Impersonating is the client id of an impersonation token (the
+	// "impersonating" claim); empty for every other token. Subject is
+	// then the support principal acting as that client.
+	Impersonating string
+	// Environment is the SANDBOX or PRODUCTION environment a service
+	// account token is pinned to (the "env" claim); empty when unpinned.
+	Environment string
 }
 
 // Mint signs a JWT with the supplied claims using key. ttl == 0 mints a
@@ -64,6 +81,13 @@ type Claims struct {
 // already-expired token (also test-only). Session-cookie callers must
 // pass a positive ttl.
 func Mint(c Claims, key *rsa.PrivateKey, issuer string, ttl time.Duration) (string, error) {
+	return MintWithKeyID(c, key, "", issuer, ttl)
+}
+
+// MintWithKeyID is Mint with a "kid" header naming the signing key, so
+// validators holding several keys (rotation) pick the right one. An empty
+// kid omits the header.
+func MintWithKeyID(c Claims, key *rsa.PrivateKey, kid, issuer string, ttl time.Duration) (string, error) {
 	if key == nil {
 		return "", errors.New("sessiontoken: signing key is nil")
 	}
@@ -71,12 +95,17 @@ func Mint(c Claims, key *rsa.PrivateKey, issuer string, ttl time.Duration) (stri
 		return "", errors.New("sessiontoken: subject is required")
 	}
 
+	jti := c.ID
+	if jti == "" {
+		jti = tsid.GenerateUntyped()
+	}
 	now := time.Now().UTC()
 	mc := jwt.MapClaims{
 		"iss":  issuer,
 		"sub":  c.Subject,
 		"iat":  now.Unix(),
 		"nbf":  now.Unix(),
+		"jti":  jti,
 		"tier": c.Scope, // tenancy tier (ANCHOR|PARTNER|CLIENT)
 	}
 	if ttl != 0 {
@@ -95,6 +124,12 @@ func Mint(c Claims, key *rsa.PrivateKey, issuer string, ttl time.Duration) (stri
 		mc["applications"] = c.Applications
 	}
 	mc["all_applications"] = c.AllApplications
+	if c.Impersonating != "" {
+		mc["impersonating"] = c.Impersonating
+	}
+	if c.Environment != "" {
+		mc["env"] = c.Environment
+	}
 	// Granted permissions ride the OAuth "scope" claim as a space-delimited
 	// string (the standard scope wire form), not a JSON array.
 	if len(c.Permissions) > 0 {
@@ -102,6 +137,9 @@ func Mint(c Claims, key *rsa.PrivateKey, issuer string, ttl time.Duration) (stri
 	}
 
 	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, mc)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
 	signed, err := tok.SignedString(key)
 	if err != nil {
 		return "", fmt.Errorf("sessiontoken: sign: %w", err)
@@ -140,11 +178,36 @@ func Validate(token string, key *rsa.PublicKey, expect Expect) (*Claims, error)
 	if key == nil {
 		return nil, errors.New("sessiontoken: verification key is nil")
 	}
+	return ValidateWithKeys(token, singleKey{key}, expect)
+}
+
+// KeySet resolves the public keys a token may verify against from its
+// "kid" header (empty when absent). Satisfied by *signingkey.Ring.
+type KeySet interface {
+	Candidates(kid string) []*rsa.PublicKey
+}
+
+type singleKey struct{ key *rsa.PublicKey }
+
+func (k singleKey) Candidates(string) []*rsa.PublicKey { return []*rsa.PublicKey{k.key} }
+
+// ValidateWithKeys is Validate against a key set: the token verifies if
+// any candidate key for its kid does.
+func ValidateWithKeys(token string, keys KeySet, expect Expect) (*Claims, error) {
 	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
 		}
-		return key, nil
+		kid, _ := t.Header["kid"].(string)
+		cands := keys.Candidates(kid)
+		if len(cands) == 1 {
+			return cands[0], nil
+		}
+		set := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, 0, len(cands))}
+		for _, c := range cands {
+			set.Keys = append(set.Keys, c)
+		}
+		return set, nil
 	},
 		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
 	)
@@ -181,6 +244,11 @@ func Validate(token string, key *rsa.PublicKey, expect Expect) (*Claims, error)
 		// Granted permissions arrive on the space-delimited "scope" claim.
 		Permissions: strings.Fields(stringClaim(mc, "scope")),
 		IssuedAt:    unixClaim(mc, "iat"),
+		ID:          stringClaim(mc, "jti"),
+		ExpiresAt:   unixClaim(mc, "exp"),
+		// Impersonation tokens name the client they act as.
+		Impersonating: stringClaim(mc, "impersonating"),
+		Environment:   stringClaim(mc, "env"),
 	}
 	if out.Subject == "" {
 		return nil, errors.New("sessiontoken: token is missing sub claim")
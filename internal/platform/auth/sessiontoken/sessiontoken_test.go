@@ -63,6 +63,44 @@ func TestMintAndValidate_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestMintAndValidate_Impersonation(t *testing.T) {
+	key := mustKey(t)
+
+	in := sessiontoken.Claims{
+		Subject:       "prn_support",
+		Scope:         "CLIENT",
+		Clients:       []string{"clt_1"},
+		ID:            "imp_1",
+		Impersonating: "clt_1",
+	}
+	tok, err := sessiontoken.Mint(in, key, "iss", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	out, err := sessiontoken.Validate(tok, &key.PublicKey, sessiontoken.Expect{Issuer: "iss"})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if out.Impersonating != "clt_1" {
+		t.Errorf("Impersonating=%q want clt_1", out.Impersonating)
+	}
+	if out.ID != "imp_1" {
+		t.Errorf("ID=%q want the supplied jti imp_1", out.ID)
+	}
+
+	plain, err := sessiontoken.Mint(sessiontoken.Claims{Subject: "prn_abc"}, key, "iss", time.Hour)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	out, err = sessiontoken.Validate(plain, &key.PublicKey, sessiontoken.Expect{Issuer: "iss"})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if out.Impersonating != "" || out.ID == "" {
+		t.Errorf("plain token: Impersonating=%q ID=%q, want no impersonation and a generated jti", out.Impersonating, out.ID)
+	}
+}
+
 func TestValidate_RejectsBadSignature(t *testing.T) {
 	k1 := mustKey(t)
 	k2 := mustKey(t)
@@ -80,6 +118,55 @@ func TestValidate_RejectsBadSignature(t *testing.T) {
 	}
 }
 
+// keyMap is a kid → key KeySet; an unknown or absent kid tries every key.
+type keyMap map[string]*rsa.PublicKey
+
+func (m keyMap) Candidates(kid string) []*rsa.PublicKey {
+	if k, ok := m[kid]; ok {
+		return []*rsa.PublicKey{k}
+	}
+	out := make([]*rsa.PublicKey, 0, len(m))
+	for _, k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestValidateWithKeys_SelectsByKeyID(t *testing.T) {
+	current, previous, stranger := mustKey(t), mustKey(t), mustKey(t)
+	keys := keyMap{"cur": &current.PublicKey, "prev": &previous.PublicKey}
+
+	tok, err := sessiontoken.MintWithKeyID(sessiontoken.Claims{Subject: "prn_abc"}, current, "cur", "iss", time.Hour)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(tok, jwt.MapClaims{})
+	if err != nil || parsed.Header["kid"] != "cur" {
+		t.Fatalf("kid header = %v (err %v), want cur", parsed.Header["kid"], err)
+	}
+	if _, err := sessiontoken.ValidateWithKeys(tok, keys, sessiontoken.Expect{}); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	// A token without a kid (minted before rotation) still verifies against
+	// a retired key in the set.
+	legacy, err := sessiontoken.Mint(sessiontoken.Claims{Subject: "prn_abc"}, previous, "iss", time.Hour)
+	if err != nil {
+		t.Fatalf("mint legacy: %v", err)
+	}
+	if _, err := sessiontoken.ValidateWithKeys(legacy, keys, sessiontoken.Expect{}); err != nil {
+		t.Fatalf("validate legacy: %v", err)
+	}
+
+	forged, err := sessiontoken.MintWithKeyID(sessiontoken.Claims{Subject: "prn_abc"}, stranger, "cur", "iss", time.Hour)
+	if err != nil {
+		t.Fatalf("mint forged: %v", err)
+	}
+	if _, err := sessiontoken.ValidateWithKeys(forged, keys, sessiontoken.Expect{}); err == nil {
+		t.Fatalf("a token signed by a key outside the set must not validate")
+	}
+}
+
 func TestValidate_RejectsExpired(t *testing.T) {
 	key := mustKey(t)
 
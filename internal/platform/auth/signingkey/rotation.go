@@ -0,0 +1,307 @@
+package signingkey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// Config tunes automatic rotation.
+type Config struct {
+	// Every is the rotation cadence: a new key is generated once the
+	// newest one is this old. Zero disables rotation.
+	Every time.Duration
+	// PublishAhead is how long a new key sits in the JWKS before it starts
+	// signing, so consumers caching the JWKS pick it up first.
+	PublishAhead time.Duration
+	// Overlap is how long a superseded key keeps verifying. Must cover the
+	// longest-lived platform JWT (the session cookie).
+	Overlap time.Duration
+	// Refresh is how often the stored keys are re-read; bounds how long
+	// another instance's rotation takes to reach this one.
+	Refresh time.Duration
+}
+
+// ConfigFromEnv reads FC_JWT_KEY_ROTATION_DAYS (0 = off, the default),
+// FC_JWT_KEY_PUBLISH_AHEAD_SECS, FC_JWT_KEY_OVERLAP_SECS and
+// FC_JWT_KEY_REFRESH_SECS.
+func ConfigFromEnv() Config {
+	return Config{
+		Every:        time.Duration(envutil.Int("FC_JWT_KEY_ROTATION_DAYS", 0)) * 24 * time.Hour,
+		PublishAhead: time.Duration(envutil.Int("FC_JWT_KEY_PUBLISH_AHEAD_SECS", 3600)) * time.Second,
+		Overlap:      time.Duration(envutil.Int("FC_JWT_KEY_OVERLAP_SECS", 172800)) * time.Second,
+		Refresh:      time.Duration(envutil.Int("FC_JWT_KEY_REFRESH_SECS", 60)) * time.Second,
+	}
+}
+
+// Enabled reports whether rotation is switched on.
+func (c Config) Enabled() bool { return c.Every > 0 }
+
+// StoredKey is a rotated key as persisted. PrivateKeyPEM is plaintext;
+// the store encrypts it at rest.
+type StoredKey struct {
+	ID            string
+	PrivateKeyPEM string
+	CreatedAt     time.Time
+	ActivatesAt   time.Time
+}
+
+// Store is the persistence contract. Satisfied by *Repository.
+type Store interface {
+	List(ctx context.Context) ([]StoredKey, error)
+	// Create inserts k unless a key was created after createdAfter (another
+	// instance rotated first). Reports whether it inserted.
+	Create(ctx context.Context, k StoredKey, createdAfter time.Time) (bool, error)
+	Delete(ctx context.Context, ids []string) error
+}
+
+// Cipher encrypts stored private keys. Satisfied by *encryption.Service.
+type Cipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(encrypted string) (string, error)
+}
+
+// Repository reads/writes iam_jwt_signing_keys. Direct writes (no UoW).
+type Repository struct {
+	pool   *pgxpool.Pool
+	cipher Cipher
+}
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool, cipher Cipher) *Repository {
+	return &Repository{pool: pool, cipher: cipher}
+}
+
+// List returns every stored key, oldest activation first.
+func (r *Repository) List(ctx context.Context) ([]StoredKey, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT kid, private_key_enc, created_at, activates_at
+		FROM iam_jwt_signing_keys
+		ORDER BY activates_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list signing keys: %w", err)
+	}
+	defer rows.Close()
+	var out []StoredKey
+	for rows.Next() {
+		var k StoredKey
+		var enc string
+		if err := rows.Scan(&k.ID, &enc, &k.CreatedAt, &k.ActivatesAt); err != nil {
+			return nil, fmt.Errorf("scan signing key: %w", err)
+		}
+		if k.PrivateKeyPEM, err = r.cipher.Decrypt(enc); err != nil {
+			return nil, fmt.Errorf("decrypt signing key %s: %w", k.ID, err)
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+// Create inserts k under a transaction-scoped advisory lock, so
+// concurrent instances deciding to rotate at once produce one key.
+func (r *Repository) Create(ctx context.Context, k StoredKey, createdAfter time.Time) (bool, error) {
+	priv, err := ParsePrivateKeyPEM([]byte(k.PrivateKeyPEM))
+	if err != nil {
+		return false, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		return false, err
+	}
+	enc, err := r.cipher.Encrypt(k.PrivateKeyPEM)
+	if err != nil {
+		return false, fmt.Errorf("encrypt signing key: %w", err)
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('iam_jwt_signing_keys'))`); err != nil {
+		return false, fmt.Errorf("lock signing keys: %w", err)
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO iam_jwt_signing_keys (kid, public_key_pem, private_key_enc, created_at, activates_at)
+		SELECT $1, $2, $3, $4, $5
+		WHERE NOT EXISTS (SELECT 1 FROM iam_jwt_signing_keys WHERE created_at > $6)`,
+		k.ID, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), enc,
+		k.CreatedAt, k.ActivatesAt, createdAfter)
+	if err != nil {
+		return false, fmt.Errorf("insert signing key: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Delete removes keys that no longer verify anything.
+func (r *Repository) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.pool.Exec(ctx, `DELETE FROM iam_jwt_signing_keys WHERE kid = ANY($1)`, ids)
+	if err != nil {
+		return fmt.Errorf("delete signing keys: %w", err)
+	}
+	return nil
+}
+
+// Rotator keeps a Ring in step with the stored keys and generates new
+// ones on schedule. Every instance runs one; they converge on the same
+// ring because signer selection is a pure function of the stored rows.
+type Rotator struct {
+	store Store
+	ring  *Ring
+	cfg   Config
+	// base is the configured signing key: it signs until the first stored
+	// key activates. static are the configured validation-only keys, kept
+	// verifying regardless of rotation.
+	base   Key
+	static []Key
+	now    func() time.Time
+}
+
+// NewRotator builds a Rotator over ring, taking the ring's current
+// contents as the configured base key + static validation keys.
+func NewRotator(store Store, ring *Ring, cfg Config) *Rotator {
+	keys := ring.Keys()
+	return &Rotator{
+		store:  store,
+		ring:   ring,
+		cfg:    cfg,
+		base:   ring.Signer(),
+		static: append([]Key(nil), keys[1:]...),
+		now:    time.Now,
+	}
+}
+
+// Sync reads the stored keys, rotates if the newest is due, prunes keys
+// past their overlap, and swaps the result into the ring.
+func (r *Rotator) Sync(ctx context.Context) error {
+	stored, err := r.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := r.now()
+	if r.due(stored, now) {
+		k, err := generate(now, r.cfg.PublishAhead)
+		if err != nil {
+			return err
+		}
+		created, err := r.store.Create(ctx, k, now.Add(-r.cfg.Every))
+		if err != nil {
+			return err
+		}
+		if created {
+			slog.Info("generated JWT signing key", "kid", k.ID, "activates_at", k.ActivatesAt)
+		}
+		if stored, err = r.store.List(ctx); err != nil {
+			return err
+		}
+	}
+	signer, others, expired, err := r.resolve(stored, now)
+	if err != nil {
+		return err
+	}
+	r.ring.Replace(signer, others)
+	if len(expired) > 0 {
+		if err := r.store.Delete(ctx, expired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run calls Sync every Refresh until ctx is cancelled. Failures are
+// logged; the ring keeps its last good contents.
+func (r *Rotator) Run(ctx context.Context) {
+	t := time.NewTicker(r.cfg.Refresh)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.Sync(ctx); err != nil {
+				slog.Warn("JWT signing key sync failed", "err", err)
+			}
+		}
+	}
+}
+
+func (r *Rotator) due(stored []StoredKey, now time.Time) bool {
+	var newest time.Time
+	for _, k := range stored {
+		if k.CreatedAt.After(newest) {
+			newest = k.CreatedAt
+		}
+	}
+	return newest.IsZero() || now.Sub(newest) >= r.cfg.Every
+}
+
+// resolve picks the signer (the latest activated key, else base) and the
+// keys that still verify: pending successors, plus predecessors whose
+// successor activated within Overlap. expired lists stored keys past it.
+func (r *Rotator) resolve(stored []StoredKey, now time.Time) (Key, []Key, []string, error) {
+	type entry struct {
+		key       Key
+		activates time.Time
+		stored    bool
+	}
+	entries := []entry{{key: r.base}}
+	for _, s := range stored {
+		priv, err := ParsePrivateKeyPEM([]byte(s.PrivateKeyPEM))
+		if err != nil {
+			return Key{}, nil, nil, fmt.Errorf("signing key %s: %w", s.ID, err)
+		}
+		entries = append(entries, entry{key: NewKey(priv), activates: s.ActivatesAt, stored: true})
+	}
+	sort.SliceStable(entries[1:], func(i, j int) bool {
+		return entries[1+i].activates.Before(entries[1+j].activates)
+	})
+
+	signerIdx := 0
+	for i, e := range entries {
+		if !e.activates.After(now) {
+			signerIdx = i
+		}
+	}
+	others := append([]Key(nil), r.static...)
+	var expired []string
+	for i, e := range entries {
+		switch {
+		case i == signerIdx:
+		case i > signerIdx:
+			others = append(others, e.key)
+		case now.Sub(entries[i+1].activates) < r.cfg.Overlap:
+			others = append(others, e.key)
+		case e.stored:
+			expired = append(expired, e.key.ID)
+		}
+	}
+	return entries[signerIdx].key, others, expired, nil
+}
+
+func generate(now time.Time, publishAhead time.Duration) (StoredKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return StoredKey{}, fmt.Errorf("generate signing key: %w", err)
+	}
+	return StoredKey{
+		ID:            KeyID(&priv.PublicKey),
+		PrivateKeyPEM: encodePrivateKeyPEM(priv),
+		CreatedAt:     now,
+		ActivatesAt:   now.Add(publishAhead),
+	}, nil
+}
@@ -0,0 +1,175 @@
+// Package signingkey holds the RSA key ring every platform JWT is signed
+// and verified with: session cookies (sessiontoken via provider), OAuth
+// access/ID tokens (authservice) and the JWKS document that publishes the
+// public halves.
+//
+// A Ring has exactly one signer plus any number of validation-only keys
+// (pre-published successors and superseded predecessors). It is swapped
+// atomically, so the Rotator can replace keys under live traffic while
+// every token stays verifiable for as long as it can be presented.
+package signingkey
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// Key is one RSA key in the ring.
+type Key struct {
+	// ID is the JWT "kid" header value and JWKS key id.
+	ID string
+	// Private is nil for validation-only keys.
+	Private *rsa.PrivateKey
+	Public  *rsa.PublicKey
+}
+
+// NewKey wraps a private key as a signing-capable Key.
+func NewKey(priv *rsa.PrivateKey) Key {
+	return Key{ID: KeyID(&priv.PublicKey), Private: priv, Public: &priv.PublicKey}
+}
+
+// NewPublicKey wraps a public key as a validation-only Key.
+func NewPublicKey(pub *rsa.PublicKey) Key {
+	return Key{ID: KeyID(pub), Public: pub}
+}
+
+// KeyID derives the kid from the PKIX public-key PEM — the same value
+// authservice has always published for a key whose public half it
+// derived, so existing JWKS consumers see no kid change.
+func KeyID(pub *rsa.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	return base64.RawURLEncoding.EncodeToString(h[:16])
+}
+
+// Ring is the live key set. The zero value is not usable; build with
+// NewRing.
+type Ring struct {
+	state atomic.Pointer[ringState]
+}
+
+type ringState struct {
+	signer Key
+	// keys is every key that verifies: signer first, then the rest.
+	keys []Key
+}
+
+// NewRing builds a ring signing with signer and also accepting others.
+func NewRing(signer Key, others ...Key) *Ring {
+	r := &Ring{}
+	r.Replace(signer, others)
+	return r
+}
+
+// Replace atomically swaps the ring's contents. Duplicate ids (including
+// the signer's) in others are dropped.
+func (r *Ring) Replace(signer Key, others []Key) {
+	keys := make([]Key, 0, 1+len(others))
+	keys = append(keys, signer)
+	seen := map[string]bool{signer.ID: true}
+	for _, k := range others {
+		if k.Public == nil || seen[k.ID] {
+			continue
+		}
+		seen[k.ID] = true
+		keys = append(keys, k)
+	}
+	r.state.Store(&ringState{signer: signer, keys: keys})
+}
+
+// Signer returns the key new tokens are signed with.
+func (r *Ring) Signer() Key { return r.state.Load().signer }
+
+// Keys returns every key that currently verifies, signer first. This is
+// the JWKS key set.
+func (r *Ring) Keys() []Key { return r.state.Load().keys }
+
+// Candidates returns the public keys a token with the given kid header
+// may be verified against: just the matching key when the kid is known,
+// otherwise every key (tokens minted before kid headers, or by a peer
+// deriving kids differently, still verify by signature).
+func (r *Ring) Candidates(kid string) []*rsa.PublicKey {
+	keys := r.Keys()
+	if kid != "" {
+		for _, k := range keys {
+			if k.ID == kid {
+				return []*rsa.PublicKey{k.Public}
+			}
+		}
+	}
+	out := make([]*rsa.PublicKey, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k.Public)
+	}
+	return out
+}
+
+// ParsePrivateKeyPEM parses a PKCS#1 or PKCS#8 RSA private key.
+func ParsePrivateKeyPEM(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse pkcs8: %w", err)
+	}
+	rsaKey, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+// ParsePublicKeysPEM parses every PEM block in b as an RSA public key
+// (PKIX or PKCS#1). Several concatenated blocks let an operator keep more
+// than one retired key verifying.
+func ParsePublicKeysPEM(b []byte) ([]*rsa.PublicKey, error) {
+	var out []*rsa.PublicKey
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		pub, err := parsePublicBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %w", len(out)+1, err)
+		}
+		out = append(out, pub)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no PEM block found")
+	}
+	return out, nil
+}
+
+func parsePublicBlock(block *pem.Block) (*rsa.PublicKey, error) {
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+			return rsaPub, nil
+		}
+		return nil, errors.New("public key is not RSA")
+	}
+	if rsaPub, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return rsaPub, nil
+	}
+	return nil, errors.New("unparseable RSA public key")
+}
+
+// encodePrivateKeyPEM is the PKCS#1 form the Rotator stores.
+func encodePrivateKeyPEM(k *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}))
+}
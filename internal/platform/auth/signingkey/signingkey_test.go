@@ -0,0 +1,141 @@
+package signingkey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustKey(t *testing.T) Key {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return NewKey(k)
+}
+
+func TestRing_Candidates(t *testing.T) {
+	a, b := mustKey(t), mustKey(t)
+	r := NewRing(a, b, NewPublicKey(a.Public))
+
+	assert.Len(t, r.Keys(), 2, "a duplicate of the signer is dropped")
+	assert.Equal(t, []*rsa.PublicKey{b.Public}, r.Candidates(b.ID))
+	assert.Len(t, r.Candidates(""), 2, "no kid tries every key")
+	assert.Len(t, r.Candidates("unknown"), 2)
+}
+
+func TestParsePublicKeysPEM_MultipleBlocks(t *testing.T) {
+	a, b := mustKey(t), mustKey(t)
+	der, err := x509.MarshalPKIXPublicKey(a.Public)
+	require.NoError(t, err)
+	multi := append(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(b.Public)})...)
+
+	pubs, err := ParsePublicKeysPEM(multi)
+	require.NoError(t, err)
+	require.Len(t, pubs, 2)
+	assert.Equal(t, a.ID, KeyID(pubs[0]))
+	assert.Equal(t, b.ID, KeyID(pubs[1]), "kid is format-independent")
+
+	_, err = ParsePublicKeysPEM([]byte("not a pem"))
+	assert.Error(t, err)
+}
+
+type fakeStore struct {
+	keys    []StoredKey
+	deleted []string
+}
+
+func (f *fakeStore) List(context.Context) ([]StoredKey, error) {
+	return append([]StoredKey(nil), f.keys...), nil
+}
+
+func (f *fakeStore) Create(_ context.Context, k StoredKey, createdAfter time.Time) (bool, error) {
+	for _, e := range f.keys {
+		if e.CreatedAt.After(createdAfter) {
+			return false, nil
+		}
+	}
+	f.keys = append(f.keys, k)
+	return true, nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, ids []string) error {
+	f.deleted = append(f.deleted, ids...)
+	kept := f.keys[:0]
+	for _, k := range f.keys {
+		if !contains(ids, k.ID) {
+			kept = append(kept, k)
+		}
+	}
+	f.keys = kept
+	return nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func ids(keys []Key) []string {
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k.ID)
+	}
+	return out
+}
+
+func TestRotator_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	base, retired := mustKey(t), NewPublicKey(mustKey(t).Public)
+	ring := NewRing(base, retired)
+	store := &fakeStore{}
+	cfg := Config{Every: 30 * 24 * time.Hour, PublishAhead: time.Hour, Overlap: 48 * time.Hour}
+	rot := NewRotator(store, ring, cfg)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rot.now = func() time.Time { return now }
+
+	// First sync: a successor is generated and published, but the
+	// configured key keeps signing until it activates.
+	require.NoError(t, rot.Sync(ctx))
+	require.Len(t, store.keys, 1)
+	first := store.keys[0].ID
+	assert.Equal(t, base.ID, ring.Signer().ID)
+	assert.ElementsMatch(t, []string{base.ID, retired.ID, first}, ids(ring.Keys()))
+
+	// Syncing again before it's due does not generate another key.
+	require.NoError(t, rot.Sync(ctx))
+	assert.Len(t, store.keys, 1)
+
+	now = now.Add(time.Hour)
+	require.NoError(t, rot.Sync(ctx))
+	assert.Equal(t, first, ring.Signer().ID)
+	assert.NotNil(t, ring.Signer().Private)
+	assert.Contains(t, ids(ring.Keys()), base.ID, "the superseded key verifies through the overlap")
+
+	now = now.Add(48 * time.Hour)
+	require.NoError(t, rot.Sync(ctx))
+	assert.ElementsMatch(t, []string{first, retired.ID}, ids(ring.Keys()), "static validation keys are kept")
+
+	// Next rotation: the stored predecessor is deleted once its overlap ends.
+	now = now.Add(30 * 24 * time.Hour)
+	require.NoError(t, rot.Sync(ctx))
+	require.Len(t, store.keys, 2)
+	second := store.keys[1].ID
+	assert.Equal(t, first, ring.Signer().ID)
+
+	now = now.Add(time.Hour + 48*time.Hour)
+	require.NoError(t, rot.Sync(ctx))
+	assert.Equal(t, second, ring.Signer().ID)
+	assert.Equal(t, []string{first}, store.deleted)
+}
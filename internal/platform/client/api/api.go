@@ -9,24 +9,43 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
 	appops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/application/operations"
+	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
 // State bundles deps. Applications / ClientConfigs are optional — when
-// nil the client→application endpoints surface 501s instead.
+// nil the client→application endpoints surface 501s instead. The
+// onboarding endpoint additionally needs DispatchPools, Principals,
+// ServiceAccounts and OAuthClients; InviteEmailer is optional (no invite
+// is sent without it). The impersonation endpoint needs Impersonation;
+// environment promotion needs Subscriptions and EventTypes.
 type State struct {
-	Repo          *client.Repository
-	Applications  *application.Repository
-	ClientConfigs *application.ClientConfigRepo
-	UoW           *usecasepgx.UnitOfWork
+	Repo            *client.Repository
+	Applications    *application.Repository
+	ClientConfigs   *application.ClientConfigRepo
+	DispatchPools   *dispatchpool.Repository
+	Principals      *principal.Repository
+	ServiceAccounts *serviceaccount.Repository
+	OAuthClients    *platformauth.OAuthClientRepo
+	Subscriptions   *subscription.Repository
+	EventTypes      *eventtype.Repository
+	InviteEmailer   InviteEmailer
+	Impersonation   ImpersonationMinter
+	UoW             *usecasepgx.UnitOfWork
 }
 
 const tag = "clients"
@@ -54,6 +73,10 @@ func Register(api huma.API, s *State) {
 	apiroute.Get(g, "getClientApplications", "/api/clients/{id}/applications", "List applications and their enabled state for the client", s.getApplications)
 	apiroute.Put(g, "updateClientApplications", "/api/clients/{id}/applications", "Replace the client's enabled applications (bulk)", http.StatusNoContent, s.updateApplications)
 	apiroute.Post(g, "enableClientApplication", "/api/clients/{id}/applications/{applicationId}/enable", "Enable an application for the client", http.StatusNoContent, s.enableApplication)
+	apiroute.Post(g, "onboardClient", "/api/admin/platform/clients/onboard", "Create a client with its default dispatch pool, admin user, service account and OAuth client in one step", http.StatusCreated, s.onboard)
+	// Support staff acting as the client; see auth.CanImpersonateClient.
+	apiroute.Post(g, "impersonateClient", "/api/admin/platform/clients/{id}/impersonate", "Mint a short-lived token acting as the client", http.StatusOK, s.impersonate)
+	apiroute.Post(g, "promoteClientEnvironment", "/api/clients/{id}/environments/promote", "Copy the client's sandbox subscriptions and event types to production", http.StatusOK, s.promote)
 	apiroute.Post(g, "disableClientApplication", "/api/clients/{id}/applications/{applicationId}/disable", "Disable an application for the client", http.StatusNoContent, s.disableApplication)
 }
 
@@ -142,7 +165,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateClientRequest
 	if err := auth.CanCreateClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateClient(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -159,7 +182,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanUpdateClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateClient(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -170,7 +193,7 @@ func (s *State) activate(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	if err := auth.CanUpdateClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ActivateClient(s.Repo), operations.ActivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -186,7 +209,7 @@ func (s *State) suspend(ctx context.Context, in *suspendInput) (*apicommon.Out[a
 	if err := auth.CanUpdateClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.SuspendClient(s.Repo), operations.SuspendCommand{ID: in.ID, Reason: in.Body.Reason}, ec); err != nil {
 		return nil, err
 	}
@@ -202,7 +225,7 @@ func (s *State) addNote(ctx context.Context, in *addNoteInput) (*apicommon.Out[a
 	if err := auth.CanUpdateClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.AddNote(s.Repo), operations.AddNoteCommand{
 		ClientID: in.ID,
 		Category: in.Body.Category,
@@ -218,7 +241,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteClient(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -237,7 +260,7 @@ func (s *State) deactivate(ctx context.Context, in *deactivateInput) (*apicommon
 	if err := auth.CanDeleteClients(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteClient(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -309,7 +332,7 @@ func (s *State) updateApplications(ctx context.Context, in *updateApplicationsIn
 	if s.Applications == nil || s.ClientConfigs == nil {
 		return nil, usecase.Internal("WIRING", "application repos not configured", nil)
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	cmd := appops.UpdateClientApplicationsCommand{
 		ClientID:              in.ID,
 		EnabledApplicationIDs: in.Body.EnabledApplicationIDs,
@@ -333,7 +356,7 @@ func (s *State) enableApplication(ctx context.Context, in *appLinkInput) (*apico
 	if s.Applications == nil || s.ClientConfigs == nil {
 		return nil, usecase.Internal("WIRING", "application repos not configured", nil)
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	cmd := appops.EnableForClientCommand{
 		ApplicationID: in.ApplicationID,
 		ClientID:      in.ID,
@@ -352,7 +375,7 @@ func (s *State) disableApplication(ctx context.Context, in *appLinkInput) (*apic
 	if s.ClientConfigs == nil {
 		return nil, usecase.Internal("WIRING", "client_configs repo not configured", nil)
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	cmd := appops.DisableForClientCommand{
 		ApplicationID: in.ApplicationID,
 		ClientID:      in.ID,
@@ -2,6 +2,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
@@ -35,6 +37,58 @@ type SuspendClientRequest struct {
 	Reason string `json:"reason"`
 }
 
+// ImpersonateClientRequest is the wire body for
+// POST /api/admin/platform/clients/{id}/impersonate.
+type ImpersonateClientRequest struct {
+	Reason          string `json:"reason" doc:"Why the client is being impersonated; recorded in the audit log"`
+	DurationMinutes int    `json:"durationMinutes,omitempty" doc:"Token lifetime in minutes (default 15, at most 60)"`
+}
+
+func (r ImpersonateClientRequest) toCommand(id string) operations.ImpersonateCommand {
+	return operations.ImpersonateCommand{
+		ID:     id,
+		Reason: r.Reason,
+		TTL:    time.Duration(r.DurationMinutes) * time.Minute,
+	}
+}
+
+// ImpersonationTokenResponse carries a minted impersonation token. The
+// token acts as the client (CLIENT tier, that client only) on behalf of
+// the caller, who stays the token's subject.
+type ImpersonationTokenResponse struct {
+	AccessToken     string          `json:"accessToken"`
+	TokenType       string          `json:"tokenType"`
+	ExpiresIn       int64           `json:"expiresIn" doc:"Seconds until the token expires"`
+	ExpiresAt       httpcompat.Time `json:"expiresAt"`
+	ClientID        string          `json:"clientId"`
+	ImpersonationID string          `json:"impersonationId" doc:"The token's jti and the id of its client impersonated event"`
+}
+
+// PromoteEnvironmentRequest is the wire body for
+// POST /api/clients/{id}/environments/promote.
+type PromoteEnvironmentRequest struct {
+	Codes []string `json:"codes,omitempty" doc:"Sandbox subscription codes to promote; omit to promote all of them"`
+}
+
+func (r PromoteEnvironmentRequest) toCommand(id string) operations.PromoteCommand {
+	return operations.PromoteCommand{ClientID: id, Codes: r.Codes}
+}
+
+// PromotedSubscription is one production subscription a promotion wrote.
+type PromotedSubscription struct {
+	ID      string `json:"id" doc:"The production subscription's id"`
+	Code    string `json:"code"`
+	Status  string `json:"status"`
+	Created bool   `json:"created" doc:"True when production had no subscription with this code before"`
+}
+
+// PromoteEnvironmentResponse reports what a sandbox-to-production
+// promotion wrote.
+type PromoteEnvironmentResponse struct {
+	Subscriptions []PromotedSubscription `json:"subscriptions"`
+	EventTypes    []string               `json:"eventTypes" doc:"Event types promoted out of the sandbox"`
+}
+
 // AddNoteRequest is the wire body for POST /api/clients/{id}/notes.
 type AddNoteRequest struct {
 	Category string `json:"category"`
@@ -131,3 +185,85 @@ type ClientApplicationsResponse struct {
 type UpdateClientApplicationsRequest struct {
 	EnabledApplicationIDs []string `json:"enabledApplicationIds"`
 }
+
+// OnboardClientRequest is the body of POST /api/admin/platform/clients/onboard.
+type OnboardClientRequest struct {
+	Name           string                      `json:"name"`
+	Identifier     string                      `json:"identifier" doc:"URL-safe identifier (lowercase alphanumeric, hyphens)"`
+	DispatchPool   *OnboardDispatchPoolRequest `json:"dispatchPool,omitempty" doc:"Defaults to code 'default'"`
+	Admin          OnboardAdminRequest         `json:"admin"`
+	ServiceAccount *OnboardServiceAccount      `json:"serviceAccount,omitempty" doc:"Defaults to code '<identifier>-integration'"`
+}
+
+// OnboardDispatchPoolRequest overrides the default pool.
+type OnboardDispatchPoolRequest struct {
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Concurrency *int32 `json:"concurrency,omitempty"`
+	RateLimit   *int32 `json:"rateLimit,omitempty"`
+}
+
+// OnboardAdminRequest is the initial client administrator.
+type OnboardAdminRequest struct {
+	Email      string `json:"email"`
+	Name       string `json:"name,omitempty"`
+	Credential string `json:"credential,omitempty" enum:"INVITE,TEMP_PASSWORD" doc:"INVITE (default) emails a set-password link; TEMP_PASSWORD returns a generated password once"`
+}
+
+// OnboardServiceAccount overrides the default service account.
+type OnboardServiceAccount struct {
+	Code string `json:"code,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func (r OnboardClientRequest) toCommand() operations.OnboardCommand {
+	cmd := operations.OnboardCommand{
+		Name:            r.Name,
+		Identifier:      r.Identifier,
+		AdminEmail:      r.Admin.Email,
+		AdminName:       r.Admin.Name,
+		AdminCredential: r.Admin.Credential,
+	}
+	if p := r.DispatchPool; p != nil {
+		cmd.PoolCode, cmd.PoolName = p.Code, p.Name
+		cmd.PoolConcurrency, cmd.PoolRateLimit = p.Concurrency, p.RateLimit
+	}
+	if sa := r.ServiceAccount; sa != nil {
+		cmd.ServiceAccountCode, cmd.ServiceAccountName = sa.Code, sa.Name
+	}
+	return cmd
+}
+
+// OnboardClientResponse returns every created id and the one-time secrets.
+// The secrets cannot be retrieved again.
+type OnboardClientResponse struct {
+	Client         ClientResponse          `json:"client"`
+	DispatchPool   OnboardedDispatchPool   `json:"dispatchPool"`
+	Admin          OnboardedAdmin          `json:"admin"`
+	ServiceAccount OnboardedServiceAccount `json:"serviceAccount"`
+}
+
+// OnboardedDispatchPool identifies the created pool.
+type OnboardedDispatchPool struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+}
+
+// OnboardedAdmin identifies the created admin and how they sign in.
+type OnboardedAdmin struct {
+	PrincipalID       string  `json:"principalId"`
+	Email             string  `json:"email"`
+	TemporaryPassword *string `json:"temporaryPassword,omitempty"`
+	InviteSent        bool    `json:"inviteSent"`
+}
+
+// OnboardedServiceAccount carries the service account and its credentials.
+type OnboardedServiceAccount struct {
+	ID                string `json:"id"`
+	Code              string `json:"code"`
+	PrincipalID       string `json:"principalId"`
+	OAuthClientID     string `json:"oauthClientId"`
+	OAuthClientSecret string `json:"oauthClientSecret"`
+	WebhookAuthToken  string `json:"webhookAuthToken"`
+	SigningSecret     string `json:"signingSecret"`
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/jsontime"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// ImpersonationMinter signs impersonation tokens. *provider.Provider
+// satisfies it.
+type ImpersonationMinter interface {
+	MintImpersonationToken(ctx context.Context, principalID, clientID, tokenID string, ttl time.Duration) (string, error)
+}
+
+type impersonateInput struct {
+	ID   string `path:"id"`
+	Body ImpersonateClientRequest
+}
+
+// impersonate mints a short-lived bearer token acting as the client. The
+// impersonation is recorded (event + audit row) before the token is
+// signed, so no token exists without its record; a signing failure leaves
+// a record for a token that was never issued.
+func (s *State) impersonate(ctx context.Context, in *impersonateInput) (*apicommon.Out[ImpersonationTokenResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanImpersonateClient(ac); err != nil {
+		return nil, err
+	}
+	if s.Impersonation == nil {
+		return nil, usecase.Internal("WIRING", "impersonation token minter not configured", nil)
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.ImpersonateClient(s.Repo), in.Body.toCommand(in.ID), ec)
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Until(event.ExpiresAt)
+	token, err := s.Impersonation.MintImpersonationToken(ctx, ac.PrincipalID, event.ClientID, event.ImpersonationID, ttl)
+	if err != nil {
+		return nil, usecase.Internal("TOKEN", "mint impersonation token failed", err)
+	}
+	return &apicommon.Out[ImpersonationTokenResponse]{Body: ImpersonationTokenResponse{
+		AccessToken:     token,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(ttl / time.Second),
+		ExpiresAt:       jsontime.New(event.ExpiresAt),
+		ClientID:        event.ClientID,
+		ImpersonationID: event.ImpersonationID,
+	}}, nil
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// InviteEmailer mints a first-time set-password link for a new user. The
+// passwordreset principalEmailer satisfies it.
+type InviteEmailer interface {
+	SendInvite(ctx context.Context, p *principal.Principal) error
+}
+
+// onboard creates a client with its default dispatch pool, initial admin,
+// service account and OAuth client in one transaction. The invite email is
+// sent only after commit; a send failure is reported (inviteSent=false)
+// rather than undoing the onboarding — the admin can be re-invited.
+func (s *State) onboard(ctx context.Context, in *apicommon.In[OnboardClientRequest]) (*apicommon.Out[OnboardClientResponse], error) {
+	if err := auth.CanCreateClients(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if s.DispatchPools == nil || s.Principals == nil || s.ServiceAccounts == nil || s.OAuthClients == nil {
+		return nil, usecase.Internal("WIRING", "onboarding repos not configured", nil)
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	res, err := usecaseop.RunTx(ctx, s.UoW,
+		operations.OnboardClient(s.Repo, s.DispatchPools, s.Principals, s.ServiceAccounts, s.OAuthClients),
+		in.Body.toCommand(), ec)
+	if err != nil {
+		return nil, err
+	}
+
+	admin := OnboardedAdmin{PrincipalID: res.Admin.ID, Email: res.Admin.UserIdentity.Email}
+	if res.TempPassword != "" {
+		admin.TemporaryPassword = &res.TempPassword
+	} else if s.InviteEmailer != nil {
+		if err := s.InviteEmailer.SendInvite(ctx, res.Admin); err != nil {
+			slog.Warn("onboarding: send admin invite failed", "client", res.Client.ID, "principal", res.Admin.ID, "err", err)
+		} else {
+			admin.InviteSent = true
+		}
+	}
+	sa := res.ServiceAccount
+	return &apicommon.Out[OnboardClientResponse]{Body: OnboardClientResponse{
+		Client:       fromEntity(res.Client),
+		DispatchPool: OnboardedDispatchPool{ID: res.Pool.ID, Code: res.Pool.Code},
+		Admin:        admin,
+		ServiceAccount: OnboardedServiceAccount{
+			ID:                sa.ServiceAccount.ID,
+			Code:              sa.ServiceAccount.Code,
+			PrincipalID:       sa.PrincipalID,
+			OAuthClientID:     sa.OAuthClientID,
+			OAuthClientSecret: sa.OAuthClientSecret,
+			WebhookAuthToken:  sa.AuthToken,
+			SigningSecret:     sa.SigningSecret,
+		},
+	}}, nil
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"slices"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+type promoteInput struct {
+	ID   string `path:"id"`
+	Body PromoteEnvironmentRequest
+}
+
+// promote copies the client's sandbox configuration to production. Open
+// to whoever may write subscriptions; the use case checks the client and
+// refuses environment-pinned keys.
+func (s *State) promote(ctx context.Context, in *promoteInput) (*apicommon.Out[PromoteEnvironmentResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWriteSubscriptions(ac); err != nil {
+		return nil, err
+	}
+	if s.Subscriptions == nil || s.EventTypes == nil {
+		return nil, usecase.Internal("WIRING", "promotion repos not configured", nil)
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	cmd := in.Body.toCommand(in.ID)
+	cmd.Approver = auth.CanApproveSubscriptions(ac) == nil
+	res, err := usecaseop.RunTx(ctx, s.UoW,
+		operations.PromoteEnvironment(s.Repo, s.Subscriptions, s.EventTypes), cmd, ec)
+	if err != nil {
+		return nil, err
+	}
+	out := PromoteEnvironmentResponse{
+		Subscriptions: make([]PromotedSubscription, 0, len(res.Subscriptions)),
+		EventTypes:    res.EventTypes,
+	}
+	if out.EventTypes == nil {
+		out.EventTypes = []string{}
+	}
+	for _, sub := range res.Subscriptions {
+		out.Subscriptions = append(out.Subscriptions, PromotedSubscription{
+			ID:      sub.ID,
+			Code:    sub.Code,
+			Status:  string(sub.Status),
+			Created: slices.Contains(res.Created, sub.Code),
+		})
+	}
+	return &apicommon.Out[PromoteEnvironmentResponse]{Body: out}, nil
+}
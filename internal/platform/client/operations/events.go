@@ -15,6 +15,9 @@ const (
 	ClientNoteAddedType = "platform:admin:client:note-added"
 	ClientDeletedType   = "platform:admin:client:deleted"
 	Source              = "platform:admin"
+	// ClientImpersonatedType records support staff minting a token to act
+	// as the client.
+	ClientImpersonatedType = "platform:admin:client:impersonated"
 )
 
 func subjectFor(id string) string { return "platform.client." + id }
@@ -165,3 +168,34 @@ func (e ClientDeleted) ToDataJSON() ([]byte, error) {
 		Identifier string `json:"identifier"`
 	}{e.ClientID, e.Identifier})
 }
+
+// ClientImpersonated records an impersonation token minted for the client.
+// PrincipalID (on the metadata) is the support principal; ImpersonationID
+// is the token's jti.
+type ClientImpersonated struct {
+	Metadata        usecase.EventMetadata
+	ClientID        string
+	ImpersonationID string
+	Reason          string
+	ExpiresAt       time.Time
+}
+
+func (e ClientImpersonated) EventID() string       { return e.Metadata.EventID }
+func (e ClientImpersonated) EventType() string     { return ClientImpersonatedType }
+func (e ClientImpersonated) SpecVersion() string   { return "1.0" }
+func (e ClientImpersonated) Source() string        { return Source }
+func (e ClientImpersonated) Subject() string       { return subjectFor(e.ClientID) }
+func (e ClientImpersonated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ClientImpersonated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ClientImpersonated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ClientImpersonated) CausationID() string   { return e.Metadata.CausationID }
+func (e ClientImpersonated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ClientImpersonated) MessageGroup() string  { return groupFor(e.ClientID) }
+func (e ClientImpersonated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ClientID        string    `json:"clientId"`
+		ImpersonationID string    `json:"impersonationId"`
+		Reason          string    `json:"reason"`
+		ExpiresAt       time.Time `json:"expiresAt"`
+	}{e.ClientID, e.ImpersonationID, e.Reason, e.ExpiresAt})
+}
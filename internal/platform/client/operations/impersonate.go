@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// Impersonation token lifetimes.
+const (
+	DefaultImpersonationTTL = 15 * time.Minute
+	MaxImpersonationTTL     = time.Hour
+)
+
+// ImpersonateCommand is the input DTO. A zero TTL means
+// DefaultImpersonationTTL.
+type ImpersonateCommand struct {
+	ID     string        `json:"id"`
+	Reason string        `json:"reason"`
+	TTL    time.Duration `json:"ttl"`
+}
+
+// ImpersonateClient records that the caller is about to act as an active
+// client and emits [ClientImpersonated]; the UoW's audit row is the
+// impersonation's audit record. It changes no state — the controller mints
+// the token afterwards, using the event's ImpersonationID as its jti.
+//
+// Authorize is intentionally Public: the only caller is the admin endpoint,
+// gated by auth.CanImpersonateClient at the controller.
+func ImpersonateClient(repo *client.Repository) usecaseop.Operation[ImpersonateCommand, ClientImpersonated] {
+	return usecaseop.Operation[ImpersonateCommand, ClientImpersonated]{
+		Name: "ImpersonateClient",
+		Validate: func(_ context.Context, cmd ImpersonateCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			if strings.TrimSpace(cmd.Reason) == "" {
+				return usecase.Validation("REASON_REQUIRED", "reason is required")
+			}
+			if cmd.TTL < 0 || cmd.TTL > MaxImpersonationTTL {
+				return usecase.Validation("INVALID_DURATION", "an impersonation lasts at most 60 minutes")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[ImpersonateCommand],
+		Execute: func(ctx context.Context, cmd ImpersonateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ClientImpersonated], error) {
+			c, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if c == nil {
+				return nil, httperror.NotFound("Client", cmd.ID)
+			}
+			if c.Status != client.StatusActive {
+				return nil, usecase.BusinessRule("CLIENT_NOT_ACTIVE", "Only an active client can be impersonated")
+			}
+			ttl := cmd.TTL
+			if ttl == 0 {
+				ttl = DefaultImpersonationTTL
+			}
+			meta := usecase.NewEventMetadata(ec, ClientImpersonatedType, Source, subjectFor(c.ID))
+			event := ClientImpersonated{
+				Metadata:        meta,
+				ClientID:        c.ID,
+				ImpersonationID: meta.EventID,
+				Reason:          strings.TrimSpace(cmd.Reason),
+				ExpiresAt:       meta.OccurredAt.Add(ttl),
+			}
+			return usecaseop.Emit(event), nil
+		},
+	}
+}
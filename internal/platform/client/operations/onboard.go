@@ -0,0 +1,260 @@
+package operations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
+	dispatchpoolops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	principalops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
+	saops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/validate"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// clientAdminRoleName is the seeded role granted to the onboarded admin:
+// user management scoped to the admin's own client. See
+// internal/platform/seed/roles.go ("client-admin").
+const clientAdminRoleName = "platform:client-admin"
+
+// Admin credential modes for [OnboardCommand].
+const (
+	OnboardAdminInvite       = "INVITE"
+	OnboardAdminTempPassword = "TEMP_PASSWORD"
+)
+
+// OnboardCommand describes a new tenant and the resources it starts with.
+// Only Name, Identifier and AdminEmail are required; the rest default from
+// the identifier.
+type OnboardCommand struct {
+	Name       string `json:"name"`
+	Identifier string `json:"identifier"`
+
+	PoolCode        string `json:"poolCode,omitempty"`
+	PoolName        string `json:"poolName,omitempty"`
+	PoolConcurrency *int32 `json:"poolConcurrency,omitempty"`
+	PoolRateLimit   *int32 `json:"poolRateLimit,omitempty"`
+
+	AdminEmail string `json:"adminEmail"`
+	AdminName  string `json:"adminName,omitempty"`
+	// AdminCredential is INVITE (default; the caller emails a set-password
+	// link after commit) or TEMP_PASSWORD (a random password is returned).
+	AdminCredential string `json:"adminCredential,omitempty"`
+
+	ServiceAccountCode string `json:"serviceAccountCode,omitempty"`
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// OnboardResult carries every created identifier plus the one-time
+// plaintext secrets. Nothing here can be read back later.
+type OnboardResult struct {
+	Client         *client.Client
+	Pool           *dispatchpool.DispatchPool
+	Admin          *principal.Principal
+	TempPassword   string // empty for INVITE
+	ServiceAccount saops.CreateWithCredentialsResult
+}
+
+// normalised fills the defaults so Validate and Execute see the same values.
+func (c OnboardCommand) normalised() OnboardCommand {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Identifier = strings.ToLower(strings.TrimSpace(c.Identifier))
+	c.AdminEmail = strings.ToLower(strings.TrimSpace(c.AdminEmail))
+	c.AdminName = strings.TrimSpace(c.AdminName)
+	c.AdminCredential = strings.ToUpper(strings.TrimSpace(c.AdminCredential))
+	if c.AdminCredential == "" {
+		c.AdminCredential = OnboardAdminInvite
+	}
+	if c.PoolCode = strings.ToLower(strings.TrimSpace(c.PoolCode)); c.PoolCode == "" {
+		c.PoolCode = "default"
+	}
+	if c.PoolName = strings.TrimSpace(c.PoolName); c.PoolName == "" {
+		c.PoolName = c.Name + " Default"
+	}
+	if c.ServiceAccountCode = strings.ToLower(strings.TrimSpace(c.ServiceAccountCode)); c.ServiceAccountCode == "" {
+		c.ServiceAccountCode = c.Identifier + "-integration"
+	}
+	if c.ServiceAccountName = strings.TrimSpace(c.ServiceAccountName); c.ServiceAccountName == "" {
+		c.ServiceAccountName = c.Name + " Integration"
+	}
+	return c
+}
+
+func (c OnboardCommand) serviceAccountCommand(clientID string) saops.CreateCommand {
+	scope := "CLIENT"
+	return saops.CreateCommand{
+		Code:      c.ServiceAccountCode,
+		Name:      c.ServiceAccountName,
+		Scope:     &scope,
+		ClientIDs: []string{clientID},
+	}
+}
+
+// OnboardClient creates a client together with its default dispatch pool,
+// an initial client-admin user, and a service account with its SERVICE
+// principal and confidential OAuth client — all in one transaction, so a
+// failure at any step leaves nothing behind. Each aggregate emits its usual
+// created event. The coarse anchor-only requirement is enforced at the
+// controller; tenant management has no per-resource dimension, so the use
+// case is Public.
+func OnboardClient(
+	clients *client.Repository,
+	pools *dispatchpool.Repository,
+	principals *principal.Repository,
+	saRepo *serviceaccount.Repository,
+	oauthRepo *platformauth.OAuthClientRepo,
+) usecaseop.TxOperation[OnboardCommand, OnboardResult] {
+	createSA := saops.CreateServiceAccountWithCredentials(saRepo, principals, oauthRepo)
+	return usecaseop.TxOperation[OnboardCommand, OnboardResult]{
+		Name: "OnboardClient",
+		Validate: func(ctx context.Context, raw OnboardCommand) error {
+			cmd := raw.normalised()
+			if cmd.Name == "" {
+				return usecase.Validation("NAME_REQUIRED", "name is required")
+			}
+			if cmd.Identifier == "" {
+				return usecase.Validation("IDENTIFIER_REQUIRED", "identifier is required")
+			}
+			if !identifierPattern.MatchString(cmd.Identifier) {
+				return usecase.Validation("INVALID_IDENTIFIER",
+					"identifier must be lowercase alphanumeric with optional hyphens (URL-safe)")
+			}
+			if !validate.CodeUnderscorePattern.MatchString(cmd.PoolCode) {
+				return usecase.Validation("INVALID_POOL_CODE",
+					"poolCode must start with a lowercase letter and contain only lowercase alphanumeric, hyphens, underscores")
+			}
+			if cmd.PoolConcurrency != nil && *cmd.PoolConcurrency < 1 {
+				return usecase.Validation("INVALID_CONCURRENCY", "poolConcurrency must be >= 1")
+			}
+			if cmd.PoolRateLimit != nil && *cmd.PoolRateLimit < 0 {
+				return usecase.Validation("INVALID_RATE_LIMIT", "poolRateLimit cannot be negative")
+			}
+			if cmd.AdminEmail == "" {
+				return usecase.Validation("ADMIN_EMAIL_REQUIRED", "adminEmail is required")
+			}
+			if at := strings.IndexByte(cmd.AdminEmail, '@'); at <= 0 || at == len(cmd.AdminEmail)-1 {
+				return usecase.Validation("INVALID_EMAIL", "adminEmail must be a valid address")
+			}
+			switch cmd.AdminCredential {
+			case OnboardAdminInvite, OnboardAdminTempPassword:
+			default:
+				return usecase.Validation("INVALID_ADMIN_CREDENTIAL", "adminCredential must be INVITE or TEMP_PASSWORD")
+			}
+			if !validate.CodePattern.MatchString(cmd.ServiceAccountCode) {
+				return usecase.Validation("INVALID_SERVICE_ACCOUNT_CODE",
+					"serviceAccountCode must start with a lowercase letter and contain only lowercase alphanumeric and hyphens")
+			}
+			return createSA.Validate(ctx, cmd.serviceAccountCommand(""))
+		},
+		Authorize: usecaseop.Public[OnboardCommand],
+		Execute: func(ctx context.Context, s *usecasepgx.TxScopedUnitOfWork, raw OnboardCommand, ec usecase.ExecutionContext) (OnboardResult, error) {
+			var zero OnboardResult
+			cmd := raw.normalised()
+
+			// Uniqueness up-front so a conflict fails before any write.
+			if existing, err := clients.FindByIdentifier(ctx, cmd.Identifier); err != nil {
+				return zero, usecase.Internal("REPO", "find_by_identifier failed", err)
+			} else if existing != nil {
+				return zero, usecase.Conflict("IDENTIFIER_EXISTS", "Client with identifier '"+cmd.Identifier+"' already exists")
+			}
+			if existing, err := principals.FindByEmail(ctx, cmd.AdminEmail); err != nil {
+				return zero, usecase.Internal("REPO", "find_by_email failed", err)
+			} else if existing != nil {
+				return zero, usecase.Conflict("EMAIL_EXISTS", "User with email '"+cmd.AdminEmail+"' already exists")
+			}
+
+			// 1. Client.
+			c := client.New(cmd.Name, cmd.Identifier)
+			created := ClientCreated{
+				Metadata:   usecase.NewEventMetadata(ec, ClientCreatedType, Source, subjectFor(c.ID)),
+				ClientID:   c.ID,
+				Name:       c.Name,
+				Identifier: c.Identifier,
+			}
+			if r := usecasepgx.CommitScoped(ctx, s, c, clients, created, cmd); !usecase.IsSuccess(r) {
+				_, e := usecase.Into(r)
+				return zero, e
+			}
+
+			// 2. Default dispatch pool, bound to the client.
+			pool := dispatchpool.New(cmd.PoolCode, cmd.PoolName)
+			pool.ClientID = &c.ID
+			pool.RateLimit = cmd.PoolRateLimit
+			if cmd.PoolConcurrency != nil {
+				pool.Concurrency = *cmd.PoolConcurrency
+			}
+			if r := usecasepgx.CommitScoped(ctx, s, pool, pools,
+				dispatchpoolops.NewDispatchPoolCreatedEvent(ec, pool.ID, pool.Code, pool.Name), cmd); !usecase.IsSuccess(r) {
+				_, e := usecase.Into(r)
+				return zero, e
+			}
+
+			// 3. Initial admin: a CLIENT-scope user holding client-admin.
+			//    RolesPersister writes the row and the role junction together.
+			admin := principal.NewUser(cmd.AdminEmail, principal.ParseScope("CLIENT"))
+			admin.ClientID = &c.ID
+			if cmd.AdminName != "" {
+				admin.Name = cmd.AdminName
+			}
+			var tempPassword string
+			if cmd.AdminCredential == OnboardAdminTempPassword {
+				pw, err := generateTempPassword()
+				if err != nil {
+					return zero, usecase.Internal("SECRET", "generate temporary password failed", err)
+				}
+				hash, err := passwordhash.Hash(pw)
+				if err != nil {
+					return zero, usecase.Internal("HASH", "password hash failed", err)
+				}
+				admin.SetPasswordHash(hash)
+				tempPassword = pw
+			}
+			src := "ONBOARDING"
+			admin.Roles = []serviceaccount.RoleAssignment{{
+				Role:             clientAdminRoleName,
+				AssignmentSource: &src,
+				AssignedAt:       time.Now().UTC(),
+			}}
+			if r := usecasepgx.CommitScoped(ctx, s, admin, principal.RolesPersister{Repository: principals},
+				principalops.NewUserCreatedEvent(ec, admin.ID, cmd.AdminEmail), cmd); !usecase.IsSuccess(r) {
+				_, e := usecase.Into(r)
+				return zero, e
+			}
+
+			// 4. Service account + SERVICE principal + OAuth client, joining
+			//    this transaction.
+			sa, err := createSA.Execute(ctx, s, cmd.serviceAccountCommand(c.ID), ec)
+			if err != nil {
+				return zero, err
+			}
+
+			return OnboardResult{
+				Client:         c,
+				Pool:           pool,
+				Admin:          admin,
+				TempPassword:   tempPassword,
+				ServiceAccount: sa,
+			}, nil
+		},
+	}
+}
+
+// generateTempPassword returns a 24-character URL-safe random password
+// (144 bits), comfortably above any configured complexity minimum.
+func generateTempPassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -4,20 +4,39 @@ package operations_test
 
 import (
 	"context"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
+	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
+	saops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
-func TestMain(m *testing.M) { testpg.RunMain(m) }
+// TestMain seeds FLOWCATALYST_APP_KEY before the embedded-PG boot:
+// onboarding encrypts the service account's OAuth client secret via
+// encryption.FromEnv. os.Setenv (not t.Setenv) because tests run parallel.
+func TestMain(m *testing.M) {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("FLOWCATALYST_APP_KEY", key)
+	testpg.RunMain(m)
+}
 
 // runAuthorized drives op through the full use-case envelope (Validate →
 // Authorize → Execute → atomic commit) as an anchor principal — the common
@@ -265,6 +284,64 @@ func TestActivateClient_Errors(t *testing.T) {
 	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "Client_NOT_FOUND")
 }
 
+// ── Impersonate ───────────────────────────────────────────────────────────
+
+func TestImpersonateClient_RecordsAudit(t *testing.T) {
+	t.Parallel()
+	repo := client.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "Impersonate Me", "cl-imp-happy")
+
+	before := time.Now()
+	ev, err := runAuthorized(uow, operations.ImpersonateClient(repo), operations.ImpersonateCommand{
+		ID: seeded.ClientID, Reason: "  ticket 4711  ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, seeded.ClientID, ev.ClientID)
+	assert.Equal(t, "ticket 4711", ev.Reason)
+	assert.Equal(t, ev.EventID(), ev.ImpersonationID, "the token's jti is the event id")
+	assert.WithinDuration(t, before.Add(operations.DefaultImpersonationTTL), ev.ExpiresAt, 5*time.Second)
+
+	logs, err := audit.NewRepository(testpg.Pool(t)).FindWithFilters(context.Background(),
+		audit.FilterParams{EntityID: &seeded.ClientID, Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs)
+	assert.Equal(t, "ImpersonateCommand", logs[0].Operation, "newest audit row is the impersonation")
+	require.NotNil(t, logs[0].PrincipalID)
+	assert.Equal(t, testpg.TestEC().PrincipalID, *logs[0].PrincipalID)
+}
+
+func TestImpersonateClient_Errors(t *testing.T) {
+	t.Parallel()
+	repo := client.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	suspended := mustCreate(t, repo, uow, "Impersonate Suspended", "cl-imp-suspended")
+	_, err := runAuthorized(uow, operations.SuspendClient(repo), operations.SuspendCommand{
+		ID: suspended.ClientID, Reason: "closed",
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		cmd  operations.ImpersonateCommand
+		kind usecase.Kind
+		code string
+	}{
+		{"missing id", operations.ImpersonateCommand{Reason: "r"}, usecase.KindValidation, "ID_REQUIRED"},
+		{"missing reason", operations.ImpersonateCommand{ID: suspended.ClientID}, usecase.KindValidation, "REASON_REQUIRED"},
+		{"too long", operations.ImpersonateCommand{ID: suspended.ClientID, Reason: "r", TTL: 2 * time.Hour}, usecase.KindValidation, "INVALID_DURATION"},
+		{"unknown id", operations.ImpersonateCommand{ID: "cli_doesnotexist1", Reason: "r"}, usecase.KindNotFound, "Client_NOT_FOUND"},
+		{"suspended", operations.ImpersonateCommand{ID: suspended.ClientID, Reason: "r"}, usecase.KindBusinessRule, "CLIENT_NOT_ACTIVE"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := runAuthorized(uow, operations.ImpersonateClient(repo), tc.cmd)
+			testpg.RequireUsecaseError(t, err, tc.kind, tc.code)
+		})
+	}
+}
+
 // ── AddNote ───────────────────────────────────────────────────────────────
 
 func TestAddNote_HappyPath(t *testing.T) {
@@ -318,3 +395,79 @@ func TestAddNote_Errors(t *testing.T) {
 		})
 	}
 }
+
+// ── Onboard ───────────────────────────────────────────────────────────────
+
+func onboardOp(t *testing.T) (usecaseop.TxOperation[operations.OnboardCommand, operations.OnboardResult], *client.Repository, *dispatchpool.Repository, *principal.Repository, *serviceaccount.Repository) {
+	t.Helper()
+	pool := testpg.Pool(t)
+	clients := client.NewRepository(pool)
+	pools := dispatchpool.NewRepository(pool)
+	principals := principal.NewRepository(pool)
+	sas := serviceaccount.NewRepository(pool)
+	oauth := platformauth.NewRepository(pool).OAuthClients
+	return operations.OnboardClient(clients, pools, principals, sas, oauth), clients, pools, principals, sas
+}
+
+func TestOnboardClient_CreatesEverything(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	op, clients, pools, principals, sas := onboardOp(t)
+	uow := testpg.NewUoW(t)
+
+	res, err := usecaseop.RunTx(testpg.AnchorCtx(), uow, op, operations.OnboardCommand{
+		Name:            "Onboard Happy",
+		Identifier:      "onboard-happy",
+		AdminEmail:      "Admin@Onboard-Happy.example",
+		AdminCredential: "temp_password",
+	}, testpg.TestEC())
+	require.NoError(t, err)
+
+	c, err := clients.FindByIdentifier(ctx, "onboard-happy")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	p, err := pools.FindByCode(ctx, "default", &c.ID)
+	require.NoError(t, err)
+	require.NotNil(t, p, "default pool is bound to the client")
+
+	admin, err := principals.FindByEmail(ctx, "admin@onboard-happy.example")
+	require.NoError(t, err)
+	require.NotNil(t, admin)
+	assert.Equal(t, &c.ID, admin.ClientID)
+	require.Len(t, admin.Roles, 1)
+	assert.Equal(t, "platform:client-admin", admin.Roles[0].Role)
+	assert.NotEmpty(t, res.TempPassword)
+
+	sa, err := sas.FindByCode(ctx, "onboard-happy-integration")
+	require.NoError(t, err)
+	require.NotNil(t, sa)
+	assert.Equal(t, []string{c.ID}, sa.ClientIDs)
+	assert.NotEmpty(t, res.ServiceAccount.OAuthClientSecret)
+}
+
+func TestOnboardClient_RollsBackOnLateFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	op, clients, _, principals, sas := onboardOp(t)
+	uow := testpg.NewUoW(t)
+
+	// Take the service account code so the last step conflicts.
+	_, err := runAuthorized(uow, saops.CreateServiceAccount(sas),
+		saops.CreateCommand{Code: "onboard-rollback-integration", Name: "Taken"})
+	require.NoError(t, err)
+
+	_, err = usecaseop.RunTx(testpg.AnchorCtx(), uow, op, operations.OnboardCommand{
+		Name:       "Onboard Rollback",
+		Identifier: "onboard-rollback",
+		AdminEmail: "admin@onboard-rollback.example",
+	}, testpg.TestEC())
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "CODE_EXISTS")
+
+	c, err := clients.FindByIdentifier(ctx, "onboard-rollback")
+	require.NoError(t, err)
+	assert.Nil(t, c, "client write rolled back")
+	admin, err := principals.FindByEmail(ctx, "admin@onboard-rollback.example")
+	require.NoError(t, err)
+	assert.Nil(t, admin, "admin write rolled back")
+}
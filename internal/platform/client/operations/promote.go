@@ -0,0 +1,166 @@
+package operations
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	eventtypeops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	subscriptionops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// PromoteCommand promotes a client's sandbox configuration to production.
+// Codes limits it to those sandbox subscriptions; empty means all of them.
+// Approver, resolved by the handler, says the caller holds the subscription
+// approve permission.
+type PromoteCommand struct {
+	ClientID string   `json:"clientId"`
+	Codes    []string `json:"codes,omitempty"`
+	Approver bool     `json:"-"`
+}
+
+// PromoteResult is what a promotion wrote.
+type PromoteResult struct {
+	// Subscriptions are the production subscriptions written, in code order.
+	Subscriptions []*subscription.Subscription
+	// Created lists the codes that had no production subscription before.
+	Created []string
+	// EventTypes are the codes of the event types promoted out of the
+	// sandbox.
+	EventTypes []string
+}
+
+// PromoteEnvironment copies a client's SANDBOX subscriptions to
+// PRODUCTION under the same code, overwriting the production subscription
+// with that code if there is one (keeping its ID and status), and promotes
+// the SANDBOX event types they bind to. One transaction: a production
+// subscription never points at a type production can't see. Target TLS
+// material is not copied; production keeps its own, and tap
+// subscriptions are left behind. In a client that requires approval a
+// fresh production copy waits on an approver unless cmd.Approver, as a
+// created one would.
+//
+// The coarse "may write subscriptions" permission is the controller's; the
+// use case needs access to the client and to both environments, so a key
+// pinned to either can't promote.
+func PromoteEnvironment(
+	clients *client.Repository,
+	subs *subscription.Repository,
+	eventTypes *eventtype.Repository,
+) usecaseop.TxOperation[PromoteCommand, PromoteResult] {
+	return usecaseop.TxOperation[PromoteCommand, PromoteResult]{
+		Name: "PromoteEnvironment",
+		Validate: func(_ context.Context, cmd PromoteCommand) error {
+			if strings.TrimSpace(cmd.ClientID) == "" {
+				return usecase.Validation("CLIENT_ID_REQUIRED", "clientId is required")
+			}
+			return nil
+		},
+		Authorize: func(ctx context.Context, cmd PromoteCommand) error {
+			a := auth.FromContext(ctx)
+			if err := auth.CheckScopeAccess(a, &cmd.ClientID); err != nil {
+				return err
+			}
+			if err := auth.CheckEnvironmentAccess(a, common.EnvironmentSandbox); err != nil {
+				return err
+			}
+			return auth.CheckEnvironmentAccess(a, common.EnvironmentProduction)
+		},
+		Execute: func(ctx context.Context, s *usecasepgx.TxScopedUnitOfWork, cmd PromoteCommand, ec usecase.ExecutionContext) (PromoteResult, error) {
+			var zero PromoteResult
+			c, err := clients.FindByID(ctx, cmd.ClientID)
+			if err != nil {
+				return zero, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if c == nil {
+				return zero, httperror.NotFound("Client", cmd.ClientID)
+			}
+
+			env := string(common.EnvironmentSandbox)
+			sandbox, err := subs.FindWithFilters(ctx, nil, &c.ID, &env)
+			if err != nil {
+				return zero, usecase.Internal("REPO", "find_with_filters failed", err)
+			}
+			// Taps are debugging aids, not configuration: they stay in
+			// the sandbox.
+			sandbox = slices.DeleteFunc(sandbox, func(sb subscription.Subscription) bool { return sb.Tap != nil })
+			if len(cmd.Codes) > 0 {
+				sandbox = slices.DeleteFunc(sandbox, func(sb subscription.Subscription) bool {
+					return !slices.Contains(cmd.Codes, sb.Code)
+				})
+				for _, code := range cmd.Codes {
+					if !slices.ContainsFunc(sandbox, func(sb subscription.Subscription) bool { return sb.Code == code }) {
+						return zero, usecase.NotFound("SUBSCRIPTION_NOT_FOUND", "No sandbox subscription with code '"+code+"'")
+					}
+				}
+			}
+			if len(sandbox) == 0 {
+				return zero, usecase.Validation("NOTHING_TO_PROMOTE", "The client has no sandbox subscriptions")
+			}
+
+			// Event types first, so the subscriptions below bind to
+			// production-visible types.
+			var result PromoteResult
+			promoted := map[string]bool{}
+			for _, sb := range sandbox {
+				for _, b := range sb.EventTypes {
+					if promoted[b.EventTypeCode] || strings.Contains(b.EventTypeCode, "*") {
+						continue
+					}
+					promoted[b.EventTypeCode] = true
+					et, err := eventTypes.FindByCode(ctx, b.EventTypeCode)
+					if err != nil {
+						return zero, usecase.Internal("REPO", "find_by_code(event type) failed", err)
+					}
+					if et == nil || !et.Promote() {
+						continue
+					}
+					if r := usecasepgx.CommitScoped(ctx, s, et, eventTypes,
+						eventtypeops.NewEventTypePromotedEvent(ec, et.ID, et.Code), cmd); !usecase.IsSuccess(r) {
+						_, e := usecase.Into(r)
+						return zero, e
+					}
+					result.EventTypes = append(result.EventTypes, et.Code)
+				}
+			}
+
+			needsApproval := false
+			if !cmd.Approver {
+				if needsApproval, err = subs.ApprovalRequired(ctx, &c.ID); err != nil {
+					return zero, usecase.Internal("REPO", "approval_required failed", err)
+				}
+			}
+			for i := range sandbox {
+				sb := &sandbox[i]
+				current, err := subs.FindByCode(ctx, sb.Code, &c.ID, common.EnvironmentProduction)
+				if err != nil {
+					return zero, usecase.Internal("REPO", "find_by_code failed", err)
+				}
+				prod := sb.ProductionCopy(current)
+				if current == nil {
+					prod.CreatedBy = &ec.PrincipalID
+					if needsApproval {
+						prod.Status = subscription.StatusPendingApproval
+					}
+					result.Created = append(result.Created, prod.Code)
+				}
+				if r := usecasepgx.CommitScoped(ctx, s, prod, subs,
+					subscriptionops.NewSubscriptionPromotedEvent(ec, prod.ID, sb.ID, prod.Code, current == nil), cmd); !usecase.IsSuccess(r) {
+					_, e := usecase.Into(r)
+					return zero, e
+				}
+				result.Subscriptions = append(result.Subscriptions, prod)
+			}
+			return result, nil
+		},
+	}
+}
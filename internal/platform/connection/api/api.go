@@ -13,6 +13,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -87,7 +88,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateConnectionReq
 	if err := auth.CanCreateConnections(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateConnection(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -112,7 +113,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanUpdateConnections(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateConnection(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -123,7 +124,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteConnections(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteConnection(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -134,7 +135,7 @@ func (s *State) pause(ctx context.Context, in *apicommon.IDInput) (*apicommon.Ou
 	if err := auth.CanUpdateConnections(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.PauseConnection(s.Repo), operations.PauseCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -145,7 +146,7 @@ func (s *State) activate(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	if err := auth.CanUpdateConnections(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ActivateConnection(s.Repo), operations.ActivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -78,7 +79,7 @@ func (s *State) add(ctx context.Context, in *apicommon.In[AddOriginRequest]) (*a
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.AddOrigin(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -92,7 +93,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteOrigin(s.Repo), operations.DeleteCommand{OriginID: in.ID}, ec); err != nil {
 		return nil, err
 	}
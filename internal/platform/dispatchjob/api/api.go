@@ -13,13 +13,21 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/filtercache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/redact"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 )
 
 // State bundles deps.
 type State struct {
 	Repo *dispatchjob.Repository
+	// Redaction masks payloads, metadata values, response bodies and
+	// target URLs in the /bff views. Nil masks everything (redact.Default).
+	Redaction *redact.Policy
+	// FilterOptions caches the filter-options answer. Optional: when nil,
+	// every call queries the read table.
+	FilterOptions *filtercache.Cache[DispatchJobFilterOptionsResponse]
 }
 
 const (
@@ -33,7 +41,9 @@ func Register(api huma.API, s *State) {
 	g := apiroute.New(api, tag)
 	apiroute.Get(g, "listDispatchJobs", "/api/dispatch-jobs", "List dispatch jobs with filters", s.list)
 	apiroute.Get(g, "listDispatchJobsRaw", "/api/dispatch-jobs/list-raw", "List dispatch jobs (raw)", s.listRaw)
+	apiroute.Get(g, "searchDispatchJobs", "/api/dispatch-jobs/search", "Search dispatch jobs with cursor pagination", s.search)
 	apiroute.Get(g, "dispatchJobFilterOptions", "/api/dispatch-jobs/filter-options", "Distinct facet values for dispatch jobs", s.filterOptions)
+	apiroute.Get(g, "listScheduledDispatchJobs", "/api/dispatch-jobs/scheduled", "Upcoming scheduled (delayed) dispatch jobs, soonest first", s.scheduled)
 	apiroute.Get(g, "dispatchJobsByEvent", "/api/dispatch-jobs/event/{eventId}", "Dispatch jobs spawned by a specific event", s.byEvent)
 	apiroute.Get(g, "getDispatchJob", "/api/dispatch-jobs/{id}", "Get a dispatch job by id", s.getByID)
 	apiroute.Get(g, "getDispatchJobRaw", "/api/dispatch-jobs/{id}/raw", "Get a dispatch job (raw)", s.getRaw)
@@ -48,7 +58,7 @@ func Register(api huma.API, s *State) {
 	apiroute.Get(g, "listDispatchJobsRawAlias", "/api/dispatch-jobs/raw", "List dispatch jobs raw (SDK alias of /list-raw)", s.listRaw)
 
 	// BFF tier — /bff/dispatch-jobs mirrors the regular handlers under
-	// cookie-auth. Mirrors Rust.
+	// cookie-auth, with field redaction on the read views. Mirrors Rust.
 	registerBFF(api, s, "/bff/dispatch-jobs", "Bff", "bff-dispatch-jobs")
 
 	// /bff/debug/dispatch-jobs is a SEPARATE raw-job view (write-side
@@ -56,24 +66,33 @@ func Register(api huma.API, s *State) {
 	// array of the raw envelope shape, so it gets its own handler.
 	// Mirrors Rust's shared/debug_api.rs.
 	gd := apiroute.New(api, "bff-debug-dispatch-jobs")
-	apiroute.Get(gd, "listDebugDispatchJobs", "/bff/debug/dispatch-jobs", "List raw dispatch jobs (debug view of msg_dispatch_jobs)", s.listDebugRaw)
+	apiroute.Get(gd, "listDebugDispatchJobs", "/bff/debug/dispatch-jobs", "List raw dispatch jobs (debug view of msg_dispatch_jobs)",
+		redacted(s, s.listDebugRaw, each((*RawDispatchJobResponse).redact)))
 }
 
 // registerBFF dual-mounts the dispatch-job handlers under an alternate
 // base path so the SPA can hit /bff/dispatch-jobs with cookie-auth.
 func registerBFF(api huma.API, s *State, base, opPrefix, tag string) {
 	g := apiroute.New(api, tag)
-	apiroute.Get(g, "listDispatchJobs"+opPrefix, base, "List dispatch jobs", s.list)
-	apiroute.Get(g, "listDispatchJobsRaw"+opPrefix, base+"/list-raw", "List dispatch jobs with raw rows", s.listRaw)
+	readList := each((*DispatchJobRead).redact)
+	apiroute.Get(g, "listDispatchJobs"+opPrefix, base, "List dispatch jobs", redacted(s, s.list, readList))
+	apiroute.Get(g, "listDispatchJobsRaw"+opPrefix, base+"/list-raw", "List dispatch jobs with raw rows", redacted(s, s.listRaw, readList))
+	apiroute.Get(g, "searchDispatchJobs"+opPrefix, base+"/search", "Search dispatch jobs with cursor pagination", redacted(s, s.search, pageRedact(readList)))
+	apiroute.Get(g, "listDispatchJobSavedFilters"+opPrefix, base+"/saved-filters", "List the caller's saved dispatch-job filters", s.savedFilters)
+	apiroute.Post(g, "createDispatchJobSavedFilter"+opPrefix, base+"/saved-filters", "Save a dispatch-job filter", http.StatusCreated, s.createSavedFilter)
+	apiroute.Put(g, "updateDispatchJobSavedFilter"+opPrefix, base+"/saved-filters/{id}", "Update a saved dispatch-job filter", http.StatusOK, s.updateSavedFilter)
+	apiroute.Delete(g, "deleteDispatchJobSavedFilter"+opPrefix, base+"/saved-filters/{id}", "Delete a saved dispatch-job filter", http.StatusNoContent, s.deleteSavedFilter)
 	apiroute.Get(g, "dispatchJobFilterOptions"+opPrefix, base+"/filter-options", "Distinct filter values for dispatch jobs", s.filterOptions)
-	apiroute.Get(g, "listDispatchJobsByEvent"+opPrefix, base+"/event/{eventId}", "List dispatch jobs created by an event", s.byEvent)
-	apiroute.Get(g, "getDispatchJob"+opPrefix, base+"/{id}", "Get a dispatch job by id", s.getByID)
-	apiroute.Get(g, "getDispatchJobRaw"+opPrefix, base+"/{id}/raw", "Get a dispatch job with raw row", s.getRaw)
-	apiroute.Get(g, "listDispatchJobAttempts"+opPrefix, base+"/{id}/attempts", "List a dispatch job's attempt history", s.attempts)
+	apiroute.Get(g, "listScheduledDispatchJobs"+opPrefix, base+"/scheduled", "Upcoming scheduled dispatch jobs", redacted(s, s.scheduled, readList))
+	apiroute.Get(g, "listDispatchJobsByEvent"+opPrefix, base+"/event/{eventId}", "List dispatch jobs created by an event", redacted(s, s.byEvent, readList))
+	apiroute.Get(g, "getDispatchJob"+opPrefix, base+"/{id}", "Get a dispatch job by id", redacted(s, s.getByID, (*DispatchJobResponse).redact))
+	apiroute.Get(g, "getDispatchJobRaw"+opPrefix, base+"/{id}/raw", "Get a dispatch job with raw row", redacted(s, s.getRaw, (*DispatchJobResponse).redact))
+	apiroute.Get(g, "listDispatchJobAttempts"+opPrefix, base+"/{id}/attempts", "List a dispatch job's attempt history", redacted(s, s.attempts, each((*AttemptDTO).redact)))
 	apiroute.Post(g, "requeueDispatchJobs"+opPrefix, base+"/requeue", "Reset dispatch jobs to PENDING for re-dispatch", http.StatusOK, s.requeue)
 }
 
-type listInput struct {
+// FilterQuery holds the filters shared by the list and search endpoints.
+type FilterQuery struct {
 	Status         string `query:"status"`
 	ClientID       string `query:"clientId"`
 	DispatchPoolID string `query:"dispatchPoolId"`
@@ -81,12 +100,9 @@ type listInput struct {
 	Code           string `query:"code"`
 	Since          string `query:"since" doc:"RFC3339 timestamp"`
 	Until          string `query:"until" doc:"RFC3339 timestamp"`
-	Limit          int    `query:"limit"`
-	Offset         int    `query:"offset"`
 
-	// SPA params (dispatch-jobs.ts:35-44). `size` caps rows; the plural
-	// params are comma-separated multi-filters.
-	Size         int    `query:"size" doc:"Max rows (default 50, max 1000)"`
+	// SPA params (dispatch-jobs.ts:35-44). The plural params are
+	// comma-separated multi-filters.
 	ClientIDs    string `query:"clientIds" doc:"CSV of client ids"`
 	Statuses     string `query:"statuses" doc:"CSV of statuses"`
 	Applications string `query:"applications" doc:"CSV of application codes"`
@@ -96,6 +112,14 @@ type listInput struct {
 	Source       string `query:"source" doc:"Free-text source filter"`
 }
 
+type listInput struct {
+	FilterQuery
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+	// `size` (SPA) caps rows.
+	Size int `query:"size" doc:"Max rows (default 50, max 1000)"`
+}
+
 // splitCSV mirrors Rust's split_csv (dispatch_job/api.rs): trim, drop empties.
 func splitCSV(s string) []string {
 	if s == "" {
@@ -111,7 +135,7 @@ func splitCSV(s string) []string {
 	return out
 }
 
-func (in *listInput) toFilters() dispatchjob.FilterParams {
+func (in *FilterQuery) toFilters(ac *auth.AuthContext) dispatchjob.FilterParams {
 	ts := func(v string) *time.Time {
 		if v == "" {
 			return nil
@@ -121,11 +145,6 @@ func (in *listInput) toFilters() dispatchjob.FilterParams {
 		}
 		return nil
 	}
-	// `size` (SPA) and `limit` (SDK) both cap rows; size wins when set.
-	limit := in.Limit
-	if in.Size > 0 {
-		limit = in.Size
-	}
 	// `source` free-text reuses the singular Source filter.
 	src := apicommon.OptStr(in.Source)
 	return dispatchjob.FilterParams{
@@ -137,27 +156,34 @@ func (in *listInput) toFilters() dispatchjob.FilterParams {
 		Source:         src,
 		Since:          ts(in.Since),
 		Until:          ts(in.Until),
-		Limit:          limit,
-		Offset:         in.Offset,
 		ClientIDs:      splitCSV(in.ClientIDs),
 		Statuses:       splitCSV(in.Statuses),
 		Applications:   splitCSV(in.Applications),
 		Subdomains:     splitCSV(in.Subdomains),
 		Aggregates:     splitCSV(in.Aggregates),
 		Codes:          splitCSV(in.Codes),
+		Environment:    environmentScope(ac),
 	}
 }
 
-// scopeFilters applies SQL-side tenant scoping (anchor sees all → no
-// scoping). Without it a non-anchor holding dispatch-job:view could read any
-// tenant's jobs by passing arbitrary clientId/clientIds filters — the
-// caller-controlled filters must only narrow within the principal's own
-// tenants. Same pattern as scheduledjob's and event's list.
-func scopeFilters(ac *auth.AuthContext, f dispatchjob.FilterParams) dispatchjob.FilterParams {
-	if !ac.IsAnchor() {
-		clients := ac.Clients
-		f.AccessibleClientIDs = &clients
+// environmentScope is the environment filter for ac's reads: a principal
+// pinned to an environment only sees that environment's jobs.
+func environmentScope(ac *auth.AuthContext) *string {
+	if ac == nil || ac.Environment == "" {
+		return nil
 	}
+	e := string(ac.Environment)
+	return &e
+}
+
+func (in *listInput) toFilters(ac *auth.AuthContext) dispatchjob.FilterParams {
+	f := in.FilterQuery.toFilters(ac)
+	// `size` (SPA) and `limit` (SDK) both cap rows; size wins when set.
+	f.Limit = in.Limit
+	if in.Size > 0 {
+		f.Limit = in.Size
+	}
+	f.Offset = in.Offset
 	return f
 }
 
@@ -170,7 +196,7 @@ func (s *State) list(ctx context.Context, in *listInput) (*apicommon.Out[[]Dispa
 	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
 		return nil, err
 	}
-	rows, err := s.Repo.FindWithFilters(ctx, scopeFilters(ac, in.toFilters()))
+	rows, err := s.Repo.FindWithFilters(ctx, in.toFilters(ac))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find_with_filters failed", err)
 	}
@@ -183,7 +209,7 @@ func (s *State) listRaw(ctx context.Context, in *listInput) (*apicommon.Out[[]Di
 	if err := auth.CanWritePermission(ac, viewRawPerm); err != nil {
 		return nil, err
 	}
-	rows, err := s.Repo.FindWithFilters(ctx, scopeFilters(ac, in.toFilters()))
+	rows, err := s.Repo.FindWithFilters(ctx, in.toFilters(ac))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find_raw failed", err)
 	}
@@ -212,7 +238,7 @@ func (s *State) listDebugRaw(ctx context.Context, in *rawListInput) (*apicommon.
 	// the un-projected envelope (payload/metadata) the debug view needs — the
 	// read projection used by the regular list drops it. Returns the most-recent
 	// N jobs.
-	rows, err := s.Repo.FindRecentRaw(ctx, limit)
+	rows, err := s.Repo.FindRecentRaw(ctx, limit, environmentScope(ac))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find_recent_raw failed", err)
 	}
@@ -235,6 +261,9 @@ func (s *State) getByID(ctx context.Context, in *apicommon.IDInput) (*apicommon.
 	if err := auth.CheckScopeAccess(ac, j.ClientID); err != nil { // A2: per-resource client scope
 		return nil, err
 	}
+	if !ac.CanAccessEnvironment(j.Environment) {
+		return nil, httperror.NotFound("DispatchJob", in.ID)
+	}
 	return &apicommon.Out[DispatchJobResponse]{Body: fromEntity(j)}, nil
 }
 
@@ -253,6 +282,9 @@ func (s *State) getRaw(ctx context.Context, in *apicommon.IDInput) (*apicommon.O
 	if err := auth.CheckScopeAccess(ac, j.ClientID); err != nil { // A2: per-resource client scope
 		return nil, err
 	}
+	if !ac.CanAccessEnvironment(j.Environment) {
+		return nil, httperror.NotFound("DispatchJob", in.ID)
+	}
 	return &apicommon.Out[DispatchJobResponse]{Body: fromEntity(j)}, nil
 }
 
@@ -275,6 +307,9 @@ func (s *State) attempts(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	if err := auth.CheckScopeAccess(ac, j.ClientID); err != nil {
 		return nil, err
 	}
+	if !ac.CanAccessEnvironment(j.Environment) {
+		return nil, httperror.NotFound("DispatchJob", in.ID)
+	}
 	rows, err := s.Repo.AttemptsByJob(ctx, in.ID)
 	if err != nil {
 		return nil, usecase.Internal("REPO", "attempts failed", err)
@@ -283,6 +318,42 @@ func (s *State) attempts(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	return &apicommon.Out[[]AttemptDTO]{Body: out}, nil
 }
 
+type scheduledInput struct {
+	ClientID       string `query:"clientId"`
+	SubscriptionID string `query:"subscriptionId"`
+	Until          string `query:"until" doc:"RFC3339 timestamp; only jobs due at or before it"`
+	Limit          int    `query:"limit" doc:"Max rows (default 100, max 1000)"`
+}
+
+// scheduled lists PENDING jobs not yet due — delayed dispatches created
+// with scheduledAt / delaySeconds and retries waiting out their backoff —
+// soonest first. Bare array, like list. Tenant-scoped like list.
+func (s *State) scheduled(ctx context.Context, in *scheduledInput) (*apicommon.Out[[]DispatchJobRead], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
+		return nil, err
+	}
+	p := dispatchjob.ScheduledParams{
+		ClientID:       apicommon.OptStr(in.ClientID),
+		SubscriptionID: apicommon.OptStr(in.SubscriptionID),
+		Limit:          in.Limit,
+		Environment:    environmentScope(ac),
+	}
+	if in.Until != "" {
+		t, err := time.Parse(time.RFC3339, in.Until)
+		if err != nil {
+			return nil, httperror.BadRequest("VALIDATION", "until must be an RFC3339 timestamp")
+		}
+		p.Until = &t
+	}
+	rows, err := s.Repo.FindScheduled(ctx, p)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_scheduled failed", err)
+	}
+	out := apicommon.MapSlice(rows, readFromEntity)
+	return &apicommon.Out[[]DispatchJobRead]{Body: out}, nil
+}
+
 type byEventInput struct {
 	EventID string `path:"eventId"`
 }
@@ -301,7 +372,7 @@ func (s *State) byEvent(ctx context.Context, in *byEventInput) (*apicommon.Out[[
 		// A2: a non-anchor caller only sees jobs for clients it can access.
 		// NOTE: CanAccessScope (not FilterClientScoped) — platform-scoped
 		// jobs (nil client) are visible to anchors/super-admins only here.
-		if !auth.CanAccessScope(ac, rows[i].ClientID) {
+		if !auth.CanAccessScope(ac, rows[i].ClientID) || !ac.CanAccessEnvironment(rows[i].Environment) {
 			continue
 		}
 		out = append(out, readFromEntity(&rows[i]))
@@ -341,7 +412,7 @@ func (s *State) requeue(ctx context.Context, in *apicommon.In[RequeueRequest]) (
 		clients := ac.Clients
 		scope = &clients
 	}
-	n, err := s.Repo.Requeue(ctx, in.Body.IDs, scope)
+	n, err := s.Repo.Requeue(ctx, in.Body.IDs, scope, environmentScope(ac))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "requeue failed", err)
 	}
@@ -353,16 +424,25 @@ func (s *State) filterOptions(ctx context.Context, _ *apicommon.Empty) (*apicomm
 	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
 		return nil, err
 	}
-	q := func(col string) []string {
-		out, _ := s.Repo.DistinctValues(ctx, col, 200)
-		return out
-	}
-	return &apicommon.Out[DispatchJobFilterOptionsResponse]{Body: DispatchJobFilterOptionsResponse{
-		Statuses:        q("status"),
-		Codes:           q("code"),
-		ClientIDs:       q("client_id"),
-		DispatchPoolIDs: q("dispatch_pool_id"),
-		SubscriptionIDs: q("subscription_id"),
-		Kinds:           q("kind"),
-	}}, nil
+	// Unscoped like the query it replaces, so one cached answer serves
+	// all callers; an answer with a failed column isn't cached.
+	opts, _ := s.FilterOptions.Get(ctx, func(ctx context.Context) (DispatchJobFilterOptionsResponse, error) {
+		var firstErr error
+		q := func(col string) []string {
+			out, err := s.Repo.DistinctValues(ctx, col, 200)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			return out
+		}
+		return DispatchJobFilterOptionsResponse{
+			Statuses:        q("status"),
+			Codes:           q("code"),
+			ClientIDs:       q("client_id"),
+			DispatchPoolIDs: q("dispatch_pool_id"),
+			SubscriptionIDs: q("subscription_id"),
+			Kinds:           q("kind"),
+		}, firstErr
+	})
+	return &apicommon.Out[DispatchJobFilterOptionsResponse]{Body: opts}, nil
 }
@@ -0,0 +1,85 @@
+//go:build integration
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+// TestReads_StayInTheCallersEnvironment pins environment isolation on the
+// dispatch-job reads: a sandbox key neither lists nor fetches production
+// jobs, while an unpinned principal sees both.
+func TestReads_StayInTheCallersEnvironment(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	s := &State{Repo: dispatchjob.NewRepository(pool)}
+
+	const code = "envtest:jobs:read"
+	job := func(id string, env common.Environment) dispatchjob.DispatchJob {
+		return dispatchjob.DispatchJob{
+			ID: id, Kind: dispatchjob.KindEvent, Code: code,
+			TargetURL: "http://example.invalid/hook", Protocol: dispatchjob.ProtocolHTTPWebhook,
+			PayloadContentType: "application/json", Environment: env,
+			Mode: common.DispatchImmediate, Priority: common.PriorityNormal,
+			MaxRetries: 3, RetryStrategy: dispatchjob.RetryExponentialBackoff,
+			Status: common.DispatchPending,
+		}
+	}
+	require.NoError(t, s.Repo.InsertBatch(ctx, []dispatchjob.DispatchJob{
+		job("djenvtest0001", common.EnvironmentSandbox),
+		job("djenvtest0002", common.EnvironmentProduction),
+	}))
+	// The lists read the projection; the projector isn't running here.
+	_, err := pool.Exec(ctx,
+		`INSERT INTO msg_dispatch_jobs_read
+		     (id, code, target_url, kind, protocol, mode, status, max_retries,
+		      environment, created_at, updated_at)
+		 SELECT id, code, target_url, kind, protocol, mode, status, max_retries,
+		        environment, created_at, updated_at
+		   FROM msg_dispatch_jobs WHERE code = $1`, code)
+	require.NoError(t, err)
+
+	as := func(env common.Environment) context.Context {
+		return auth.WithContext(ctx, &auth.AuthContext{
+			PrincipalID: "p_djenv_test", Scope: auth.ScopeAnchor, Environment: env,
+		})
+	}
+	listed := func(ctx context.Context) []string {
+		t.Helper()
+		out, err := s.list(ctx, &listInput{FilterQuery: FilterQuery{Code: code}})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(out.Body))
+		for _, j := range out.Body {
+			ids = append(ids, j.ID)
+		}
+		return ids
+	}
+
+	sandbox := as(common.EnvironmentSandbox)
+	assert.Equal(t, []string{"djenvtest0001"}, listed(sandbox))
+	got, err := s.getByID(sandbox, &apicommon.IDInput{ID: "djenvtest0001"})
+	require.NoError(t, err)
+	assert.Equal(t, "djenvtest0001", got.Body.ID)
+	_, err = s.getByID(sandbox, &apicommon.IDInput{ID: "djenvtest0002"})
+	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "DispatchJob_NOT_FOUND")
+	_, err = s.getRaw(sandbox, &apicommon.IDInput{ID: "djenvtest0002"})
+	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "DispatchJob_NOT_FOUND")
+	_, err = s.attempts(sandbox, &apicommon.IDInput{ID: "djenvtest0002"})
+	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "DispatchJob_NOT_FOUND")
+
+	assert.Equal(t, []string{"djenvtest0002"}, listed(as(common.EnvironmentProduction)))
+	assert.ElementsMatch(t, []string{"djenvtest0001", "djenvtest0002"}, listed(as("")))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/jsontime"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 )
 
 // MetadataDTO mirrors dispatchjob.Metadata.
@@ -75,6 +76,7 @@ type DispatchJobResponse struct {
 	DispatchPoolID     *string             `json:"dispatchPoolId,omitempty"`
 	MessageGroup       *string             `json:"messageGroup,omitempty"`
 	Mode               common.DispatchMode `json:"mode"`
+	Priority           common.Priority     `json:"priority"`
 	Sequence           int32               `json:"sequence"`
 	TimeoutSeconds     uint32              `json:"timeoutSeconds"`
 	SchemaID           *string             `json:"schemaId,omitempty"`
@@ -93,6 +95,8 @@ type DispatchJobResponse struct {
 	LastAttemptAt      *httpcompat.Time    `json:"lastAttemptAt,omitempty"`
 	CompletedAt        *httpcompat.Time    `json:"completedAt,omitempty"`
 	DurationMillis     *int64              `json:"durationMillis,omitempty"`
+	AckDeadline        *httpcompat.Time    `json:"ackDeadline,omitempty" doc:"Set when the subscription requires receiver acknowledgement: when the latest delivery stops waiting for it"`
+	AckedAt            *httpcompat.Time    `json:"ackedAt,omitempty" doc:"When the receiver acknowledged the delivery token"`
 }
 
 func fromEntity(j *dispatchjob.DispatchJob) DispatchJobResponse {
@@ -104,7 +108,7 @@ func fromEntity(j *dispatchjob.DispatchJob) DispatchJobResponse {
 	for _, m := range j.Metadata {
 		meta = append(meta, MetadataDTO{Key: m.Key, Value: m.Value})
 	}
-	var sched, expires, lastAttempt, completed *httpcompat.Time
+	var sched, expires, lastAttempt, completed, ackDeadline, acked *httpcompat.Time
 	if j.ScheduledFor != nil {
 		v := jsontime.New(*j.ScheduledFor)
 		sched = &v
@@ -121,6 +125,14 @@ func fromEntity(j *dispatchjob.DispatchJob) DispatchJobResponse {
 		v := jsontime.New(*j.CompletedAt)
 		completed = &v
 	}
+	if j.AckDeadline != nil {
+		v := jsontime.New(*j.AckDeadline)
+		ackDeadline = &v
+	}
+	if j.AckedAt != nil {
+		v := jsontime.New(*j.AckedAt)
+		acked = &v
+	}
 	return DispatchJobResponse{
 		ID:                 j.ID,
 		ExternalID:         j.ExternalID,
@@ -141,6 +153,7 @@ func fromEntity(j *dispatchjob.DispatchJob) DispatchJobResponse {
 		DispatchPoolID:     j.DispatchPoolID,
 		MessageGroup:       j.MessageGroup,
 		Mode:               j.Mode,
+		Priority:           j.Priority,
 		Sequence:           j.Sequence,
 		TimeoutSeconds:     j.TimeoutSeconds,
 		SchemaID:           j.SchemaID,
@@ -159,6 +172,8 @@ func fromEntity(j *dispatchjob.DispatchJob) DispatchJobResponse {
 		LastAttemptAt:      lastAttempt,
 		CompletedAt:        completed,
 		DurationMillis:     j.DurationMillis,
+		AckDeadline:        ackDeadline,
+		AckedAt:            acked,
 	}
 }
 
@@ -349,3 +364,50 @@ type DispatchJobFilterOptionsResponse struct {
 	SubscriptionIDs []string `json:"subscriptionIds"`
 	Kinds           []string `json:"kinds"`
 }
+
+// SavedFilterRequest is the body of POST/PUT
+// /bff/dispatch-jobs/saved-filters.
+type SavedFilterRequest struct {
+	Name     string                          `json:"name" maxLength:"100"`
+	Criteria dispatchjob.SavedFilterCriteria `json:"criteria"`
+}
+
+// validate checks the request and returns the trimmed name.
+func (r *SavedFilterRequest) validate() (string, error) {
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return "", usecase.Validation("NAME_REQUIRED", "name is required")
+	}
+	if s := r.Criteria.Sort; s != "" && !dispatchjob.SortKey(s).Valid() {
+		return "", usecase.Validation("SORT", "sort must be one of createdAt, updatedAt, attemptCount, status, code")
+	}
+	if o := strings.ToLower(r.Criteria.Order); o != "" && o != "asc" && o != "desc" {
+		return "", usecase.Validation("ORDER", "order must be asc or desc")
+	}
+	return name, nil
+}
+
+// SavedFilterResponse is one saved dispatch-job search.
+type SavedFilterResponse struct {
+	ID        string                          `json:"id"`
+	Name      string                          `json:"name"`
+	Criteria  dispatchjob.SavedFilterCriteria `json:"criteria"`
+	CreatedAt httpcompat.Time                 `json:"createdAt"`
+	UpdatedAt httpcompat.Time                 `json:"updatedAt"`
+}
+
+func savedFilterFromEntity(f *dispatchjob.SavedFilter) SavedFilterResponse {
+	return SavedFilterResponse{
+		ID:        f.ID,
+		Name:      f.Name,
+		Criteria:  f.Criteria,
+		CreatedAt: jsontime.New(f.CreatedAt),
+		UpdatedAt: jsontime.New(f.UpdatedAt),
+	}
+}
+
+// SavedFilterListResponse is the wire shape for GET
+// /bff/dispatch-jobs/saved-filters.
+type SavedFilterListResponse struct {
+	Filters []SavedFilterResponse `json:"filters"`
+}
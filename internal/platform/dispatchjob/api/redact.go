@@ -0,0 +1,68 @@
+// redact.go applies the BFF field redaction (shared/redact) to the
+// dispatch-job wire shapes. Only the /bff mounts are wrapped.
+package api
+
+import (
+	"context"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/redact"
+)
+
+// redacted wraps a read handler so its response is masked for the caller.
+func redacted[I, T any](s *State, h func(context.Context, *I) (*apicommon.Out[T], error), apply func(*T, redact.Fields)) func(context.Context, *I) (*apicommon.Out[T], error) {
+	return func(ctx context.Context, in *I) (*apicommon.Out[T], error) {
+		out, err := h(ctx, in)
+		if err != nil || out == nil {
+			return out, err
+		}
+		apply(&out.Body, s.Redaction.For(auth.FromContext(ctx), redact.DispatchJobs))
+		return out, nil
+	}
+}
+
+// each lifts a per-item redaction to a bare-array body.
+func each[T any](apply func(*T, redact.Fields)) func(*[]T, redact.Fields) {
+	return func(items *[]T, f redact.Fields) {
+		for i := range *items {
+			apply(&(*items)[i], f)
+		}
+	}
+}
+
+func (j *DispatchJobResponse) redact(f redact.Fields) {
+	if f.Has(redact.FieldPayload) {
+		j.Payload = redact.String(j.Payload)
+	}
+	if f.Has(redact.FieldTargetURL) {
+		j.TargetURL = redact.URL(j.TargetURL)
+	}
+	if f.Has(redact.FieldMetadata) {
+		// Keys stay: which headers a job carried is useful for triage.
+		for i := range j.Metadata {
+			j.Metadata[i].Value = redact.Mask
+		}
+	}
+	for i := range j.Attempts {
+		j.Attempts[i].redact(f)
+	}
+}
+
+func (a *AttemptDTO) redact(f redact.Fields) {
+	if f.Has(redact.FieldResponseBody) {
+		a.ResponseBody = redact.String(a.ResponseBody)
+	}
+}
+
+func (j *DispatchJobRead) redact(f redact.Fields) {
+	if f.Has(redact.FieldTargetURL) {
+		j.TargetURL = redact.URL(j.TargetURL)
+	}
+}
+
+func (j *RawDispatchJobResponse) redact(f redact.Fields) {
+	if f.Has(redact.FieldTargetURL) {
+		j.TargetURL = redact.URL(j.TargetURL)
+	}
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/redact"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// searchInput is the cursor-paginated query for GET
+// /api/dispatch-jobs/search: the list filters plus after (opaque cursor),
+// size, sort and order. Unlike the list's offset, a deep page costs the
+// same as the first.
+type searchInput struct {
+	FilterQuery
+	After string `query:"after" doc:"Opaque cursor from a previous page's nextCursor"`
+	Size  int    `query:"size" doc:"Page size (default 50, max 1000)"`
+	Sort  string `query:"sort" doc:"Sort key: createdAt (default), updatedAt, attemptCount, status or code"`
+	Order string `query:"order" doc:"asc or desc (default)"`
+}
+
+// search pages dispatch jobs by keyset. A cursor is only valid for the
+// sort and order it was issued under; changing either restarts from the
+// first page. Tenant-scoped like list.
+func (s *State) search(ctx context.Context, in *searchInput) (*apicommon.Out[apicommon.CursorResponse[DispatchJobRead]], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
+		return nil, err
+	}
+	sort := dispatchjob.SortCreatedAt
+	if in.Sort != "" {
+		sort = dispatchjob.SortKey(in.Sort)
+	}
+	if !sort.Valid() {
+		return nil, usecase.Validation("SORT", "sort must be one of createdAt, updatedAt, attemptCount, status, code")
+	}
+	var asc bool
+	switch strings.ToLower(in.Order) {
+	case "", "desc":
+	case "asc":
+		asc = true
+	default:
+		return nil, usecase.Validation("ORDER", "order must be asc or desc")
+	}
+	size := in.Size
+	if size < 1 || size > 1000 {
+		size = 50
+	}
+
+	p := dispatchjob.SearchParams{
+		FilterParams: in.toFilters(ac),
+		Sort:         sort,
+		Ascending:    asc,
+	}
+	p.Limit = size + 1
+	if in.After != "" {
+		c, err := decodeCursor(in.After, sort, asc)
+		if err != nil {
+			return nil, usecase.Validation("CURSOR", "invalid cursor")
+		}
+		p.After = c
+	}
+
+	rows, err := s.Repo.Search(ctx, p)
+	if errors.Is(err, dispatchjob.ErrInvalidCursor) {
+		return nil, usecase.Validation("CURSOR", "invalid cursor")
+	}
+	if err != nil {
+		return nil, usecase.Internal("REPO", "search failed", err)
+	}
+	hasMore := len(rows) > size
+	if hasMore {
+		rows = rows[:size]
+	}
+	body := apicommon.CursorResponse[DispatchJobRead]{Items: apicommon.MapSlice(rows, readFromEntity), HasMore: hasMore}
+	if hasMore {
+		body.NextCursor = encodeCursor(sort, asc, sort.CursorOf(&rows[len(rows)-1]))
+	}
+	return &apicommon.Out[apicommon.CursorResponse[DispatchJobRead]]{Body: body}, nil
+}
+
+// encodeCursor serializes a keyset position into an opaque base64 token
+// of the form "<sort>|<order>|<id>|<value>". The value goes last because
+// text sort keys may themselves contain '|'.
+func encodeCursor(sort dispatchjob.SortKey, asc bool, c dispatchjob.Cursor) string {
+	order := "desc"
+	if asc {
+		order = "asc"
+	}
+	raw := string(sort) + "|" + order + "|" + c.ID + "|" + c.Value
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor reverses encodeCursor, rejecting a cursor issued under a
+// different sort or order.
+func decodeCursor(s string, sort dispatchjob.SortKey, asc bool) (*dispatchjob.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(string(b), "|", 4)
+	if len(parts) != 4 || parts[2] == "" {
+		return nil, errBadCursor
+	}
+	if parts[0] != string(sort) || (parts[1] == "asc") != asc {
+		return nil, errBadCursor
+	}
+	if _, err := sort.ParseValue(parts[3]); err != nil {
+		return nil, err
+	}
+	return &dispatchjob.Cursor{ID: parts[2], Value: parts[3]}, nil
+}
+
+var errBadCursor = errors.New("malformed cursor")
+
+// pageRedact lifts the per-item redaction to a search page.
+func pageRedact(items func(*[]DispatchJobRead, redact.Fields)) func(*apicommon.CursorResponse[DispatchJobRead], redact.Fields) {
+	return func(p *apicommon.CursorResponse[DispatchJobRead], f redact.Fields) { items(&p.Items, f) }
+}
+
+// ── saved filters ────────────────────────────────────────────────────────
+
+// savedFilters lists the caller's saved searches by name.
+func (s *State) savedFilters(ctx context.Context, _ *apicommon.Empty) (*apicommon.Out[SavedFilterListResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.SavedFilters(ctx, ac.PrincipalID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "saved_filters failed", err)
+	}
+	return &apicommon.Out[SavedFilterListResponse]{Body: SavedFilterListResponse{
+		Filters: apicommon.MapSlice(rows, savedFilterFromEntity),
+	}}, nil
+}
+
+// createSavedFilter keeps a new named search for the caller.
+func (s *State) createSavedFilter(ctx context.Context, in *apicommon.In[SavedFilterRequest]) (*apicommon.Out[SavedFilterResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
+		return nil, err
+	}
+	name, err := in.Body.validate()
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.Repo.CountSavedFilters(ctx, ac.PrincipalID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "count_saved_filters failed", err)
+	}
+	if n >= dispatchjob.MaxSavedFilters {
+		return nil, usecase.Validation("SAVED_FILTER_LIMIT", "saved filter limit reached; delete one first")
+	}
+	if err := s.ensureNameFree(ctx, ac.PrincipalID, name, ""); err != nil {
+		return nil, err
+	}
+	f := dispatchjob.NewSavedFilter(ac.PrincipalID, name, in.Body.Criteria)
+	if err := s.Repo.SaveFilter(ctx, f); err != nil {
+		return nil, usecase.Internal("REPO", "save_filter failed", err)
+	}
+	return &apicommon.Out[SavedFilterResponse]{Body: savedFilterFromEntity(f)}, nil
+}
+
+type savedFilterUpdateInput struct {
+	ID   string `path:"id"`
+	Body SavedFilterRequest
+}
+
+// updateSavedFilter renames a saved search or replaces its criteria.
+func (s *State) updateSavedFilter(ctx context.Context, in *savedFilterUpdateInput) (*apicommon.Out[SavedFilterResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
+		return nil, err
+	}
+	name, err := in.Body.validate()
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.ownSavedFilter(ctx, ac, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNameFree(ctx, ac.PrincipalID, name, f.ID); err != nil {
+		return nil, err
+	}
+	f.Name, f.Criteria = name, in.Body.Criteria
+	if err := s.Repo.SaveFilter(ctx, f); err != nil {
+		return nil, usecase.Internal("REPO", "save_filter failed", err)
+	}
+	return &apicommon.Out[SavedFilterResponse]{Body: savedFilterFromEntity(f)}, nil
+}
+
+// deleteSavedFilter drops one of the caller's saved searches.
+func (s *State) deleteSavedFilter(ctx context.Context, in *apicommon.IDInput) (*apicommon.Empty, error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, viewPerm); err != nil {
+		return nil, err
+	}
+	f, err := s.ownSavedFilter(ctx, ac, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.DeleteSavedFilter(ctx, f.ID); err != nil {
+		return nil, usecase.Internal("REPO", "delete_saved_filter failed", err)
+	}
+	return &apicommon.Empty{}, nil
+}
+
+// ownSavedFilter loads id for its owner. Another principal's filter is
+// reported as not found rather than forbidden, so ids can't be probed.
+func (s *State) ownSavedFilter(ctx context.Context, ac *auth.AuthContext, id string) (*dispatchjob.SavedFilter, error) {
+	f, err := s.Repo.SavedFilterByID(ctx, id)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "saved_filter_by_id failed", err)
+	}
+	if f == nil || f.PrincipalID != ac.PrincipalID {
+		return nil, httperror.NotFound("SavedFilter", id)
+	}
+	return f, nil
+}
+
+// ensureNameFree rejects a name the principal already uses on a filter
+// other than selfID.
+func (s *State) ensureNameFree(ctx context.Context, principalID, name, selfID string) error {
+	existing, err := s.Repo.SavedFilterByName(ctx, principalID, name)
+	if err != nil {
+		return usecase.Internal("REPO", "saved_filter_by_name failed", err)
+	}
+	if existing != nil && existing.ID != selfID {
+		return usecase.Conflict("SAVED_FILTER_EXISTS", "a saved filter named '"+name+"' already exists")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+)
+
+func TestSearchCursor_RoundTrip(t *testing.T) {
+	j := &dispatchjob.DispatchJob{ID: "djb_0000000000001", Code: "a|b:c", CreatedAt: time.Date(2026, 10, 16, 9, 30, 0, 123456000, time.UTC)}
+
+	for _, sort := range []dispatchjob.SortKey{dispatchjob.SortCreatedAt, dispatchjob.SortCode} {
+		tok := encodeCursor(sort, false, sort.CursorOf(j))
+		c, err := decodeCursor(tok, sort, false)
+		require.NoError(t, err, sort)
+		assert.Equal(t, sort.CursorOf(j), *c, sort)
+
+		_, err = decodeCursor(tok, sort, true)
+		assert.Error(t, err, "a cursor is bound to its order")
+	}
+
+	tok := encodeCursor(dispatchjob.SortCreatedAt, true, dispatchjob.SortCreatedAt.CursorOf(j))
+	_, err := decodeCursor(tok, dispatchjob.SortUpdatedAt, true)
+	assert.Error(t, err, "a cursor is bound to its sort key")
+
+	_, err = decodeCursor("not base64!", dispatchjob.SortCreatedAt, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package dispatchjob
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// BackoffPolicy turns a failed attempt into the delay before the scheduler
+// may re-dispatch the job. The delay is written to scheduled_for, so the
+// next retry time is visible on the job itself rather than hidden in a
+// broker visibility timeout.
+//
+// Per RetryStrategy:
+//   - exponential: Base·2^(attempt-1), capped at Max,
+//   - fixed:       Base every time,
+//   - immediate:   no delay (the next poll picks it up).
+//
+// Jitter spreads the exponential and fixed delays uniformly over
+// ±Jitter·delay so a burst of jobs failing together (a receiver outage)
+// doesn't come back as a synchronized burst. It is bounded rather than
+// full jitter so retry intervals stay predictable.
+type BackoffPolicy struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter float64 // 0..1
+}
+
+// DefaultBackoffPolicy is 5s doubling to a 2m cap with ±20% jitter:
+// roughly 5s, 10s, 20s, 40s, 80s, 120s.
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{Base: 5 * time.Second, Max: 2 * time.Minute, Jitter: 0.2}
+}
+
+// Delay returns the backoff after the given just-finished attempt
+// (1-based). rnd returns a value in [0,1); nil uses math/rand.
+func (p BackoffPolicy) Delay(strategy RetryStrategy, attempt int32, rnd func() float64) time.Duration {
+	var d time.Duration
+	switch strategy {
+	case RetryImmediate:
+		return 0
+	case RetryFixed:
+		d = p.Base
+	default:
+		d = p.Base
+		for i := int32(1); i < attempt && d < p.Max; i++ {
+			d *= 2
+		}
+	}
+	if p.Max > 0 && d > p.Max {
+		d = p.Max
+	}
+	if p.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	if rnd == nil {
+		rnd = rand.Float64
+	}
+	// Uniform in [d·(1-j), d·(1+j)).
+	factor := 1 + p.Jitter*(2*rnd()-1)
+	return time.Duration(float64(d) * factor)
+}
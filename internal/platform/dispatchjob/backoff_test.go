@@ -0,0 +1,35 @@
+package dispatchjob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffPolicy_ExponentialCapped(t *testing.T) {
+	p := BackoffPolicy{Base: 5 * time.Second, Max: 2 * time.Minute}
+	want := []time.Duration{5, 10, 20, 40, 80, 120, 120}
+	for i, w := range want {
+		assert.Equal(t, w*time.Second, p.Delay(RetryExponentialBackoff, int32(i+1), nil), "attempt %d", i+1)
+	}
+	// Absurd attempt counts don't overflow.
+	assert.Equal(t, 2*time.Minute, p.Delay(RetryExponentialBackoff, 1000, nil))
+}
+
+func TestBackoffPolicy_Strategies(t *testing.T) {
+	p := BackoffPolicy{Base: 5 * time.Second, Max: 2 * time.Minute, Jitter: 0.2}
+	assert.Zero(t, p.Delay(RetryImmediate, 3, func() float64 { return 0.9 }))
+	assert.Equal(t, 5*time.Second, p.Delay(RetryFixed, 4, func() float64 { return 0.5 }))
+}
+
+func TestBackoffPolicy_JitterBounded(t *testing.T) {
+	p := BackoffPolicy{Base: 10 * time.Second, Max: time.Minute, Jitter: 0.2}
+	assert.Equal(t, 8*time.Second, p.Delay(RetryFixed, 1, func() float64 { return 0 }))
+	assert.Equal(t, 10*time.Second, p.Delay(RetryFixed, 1, func() float64 { return 0.5 }))
+	for i := 0; i < 100; i++ {
+		d := p.Delay(RetryExponentialBackoff, 3, nil)
+		assert.GreaterOrEqual(t, d, 32*time.Second)
+		assert.Less(t, d, 48*time.Second)
+	}
+}
@@ -0,0 +1,162 @@
+// Package callback delivers dispatch-job status callbacks. A producer that
+// passes statusCallbackUrl when creating a job is registered here; once
+// the job is terminal (COMPLETED, or FAILED with its retries exhausted)
+// the processing callback arms the registration and the Runner POSTs a
+// signed Notification to the URL, retrying with backoff and rate limited
+// per host. Producers no longer need to poll GET /api/dispatch-jobs/{id}.
+//
+// Like dispatch jobs themselves this is an infrastructure path: direct
+// repository writes, no UoW and no domain events.
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+)
+
+// Signature headers — the same scheme the router uses for webhooks:
+// hex HMAC-SHA256 of the timestamp followed by the body.
+const (
+	SignatureHeader = "X-FLOWCATALYST-SIGNATURE"
+	TimestampHeader = "X-FLOWCATALYST-TIMESTAMP"
+)
+
+// State is a callback's position in its lifecycle.
+type State string
+
+const (
+	StateWaiting    State = "WAITING"    // job not terminal yet
+	StatePending    State = "PENDING"    // due at NextAttemptAt
+	StateProcessing State = "PROCESSING" // claimed by a runner
+	StateDelivered  State = "DELIVERED"
+	StateFailed     State = "FAILED" // attempts exhausted
+)
+
+// Callback is one job's registration and delivery state.
+type Callback struct {
+	JobID         string
+	URL           string
+	ClientID      *string
+	State         State
+	JobStatus     *common.DispatchStatus
+	Attempts      int32
+	Error         *string
+	NextAttemptAt *time.Time
+	CreatedAt     time.Time
+	CompletedAt   *time.Time
+}
+
+// Registration asks for a callback to URL when job JobID is terminal.
+type Registration struct {
+	JobID    string
+	URL      string
+	ClientID *string
+}
+
+// ValidateURL checks a statusCallbackUrl: an absolute http(s) URL. Where
+// it may point is checked at delivery, against the egress policy.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return errors.New("statusCallbackUrl is not a valid URL")
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("statusCallbackUrl must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// Notification is the body POSTed to a producer's callback URL.
+type Notification struct {
+	JobID          string                `json:"jobId"`
+	ExternalID     *string               `json:"externalId,omitempty"`
+	Code           string                `json:"code"`
+	Status         common.DispatchStatus `json:"status"`
+	AttemptCount   int32                 `json:"attemptCount"`
+	LastError      *string               `json:"lastError,omitempty"`
+	CompletedAt    *time.Time            `json:"completedAt,omitempty"`
+	DurationMillis *int64                `json:"durationMillis,omitempty"`
+	CorrelationID  *string               `json:"correlationId,omitempty"`
+	ClientID       *string               `json:"clientId,omitempty"`
+	SubscriptionID *string               `json:"subscriptionId,omitempty"`
+}
+
+// NewNotification renders job's outcome. status is the terminal status
+// the callback was armed with — the job row may have moved on since.
+func NewNotification(job *dispatchjob.DispatchJob, status common.DispatchStatus) Notification {
+	return Notification{
+		JobID:          job.ID,
+		ExternalID:     job.ExternalID,
+		Code:           job.Code,
+		Status:         status,
+		AttemptCount:   job.AttemptCount,
+		LastError:      job.LastError,
+		CompletedAt:    job.CompletedAt,
+		DurationMillis: job.DurationMillis,
+		CorrelationID:  job.CorrelationID,
+		ClientID:       job.ClientID,
+		SubscriptionID: job.SubscriptionID,
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of ts followed by body, under secret.
+// Producers verify a callback by recomputing it from the timestamp header
+// and the raw body.
+func Sign(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Config holds the callback knobs (all env-overridable).
+type Config struct {
+	// SigningSecret signs every notification. Empty disables callbacks:
+	// statusCallbackUrl is rejected and the runner doesn't start.
+	SigningSecret string
+	// PollInterval is how often an idle runner looks for due callbacks.
+	PollInterval time.Duration
+	// MaxAttempts is how many times a callback is tried before it FAILs.
+	MaxAttempts int
+	// RatePerHost caps callbacks per second to one host, across the
+	// runner's workers; a callback over the rate waits, without spending
+	// an attempt.
+	RatePerHost float64
+	// Timeout bounds one callback request.
+	Timeout time.Duration
+	// Workers is how many callbacks one instance sends at once.
+	Workers int
+	// StaleAfter is how long a PROCESSING callback may go unfinished
+	// before another instance picks it up.
+	StaleAfter time.Duration
+	// Retention is how long DELIVERED and FAILED callbacks are kept.
+	Retention time.Duration
+	// Backoff spaces retries (exponential).
+	Backoff dispatchjob.BackoffPolicy
+}
+
+// ConfigFromEnv builds a Config from FC_DISPATCH_CALLBACK_* env vars.
+func ConfigFromEnv() Config {
+	return Config{
+		SigningSecret: envutil.Or("FC_DISPATCH_CALLBACK_SIGNING_SECRET", ""),
+		PollInterval:  time.Duration(envutil.Int("FC_DISPATCH_CALLBACK_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		MaxAttempts:   envutil.Int("FC_DISPATCH_CALLBACK_MAX_ATTEMPTS", 8),
+		RatePerHost:   float64(envutil.Int("FC_DISPATCH_CALLBACK_RATE_PER_HOST", 10)),
+		Timeout:       10 * time.Second,
+		Workers:       4,
+		StaleAfter:    2 * time.Minute,
+		Retention:     7 * 24 * time.Hour,
+		Backoff:       dispatchjob.BackoffPolicy{Base: 5 * time.Second, Max: 10 * time.Minute, Jitter: 0.2},
+	}
+}
+
+// Enabled reports whether a signing secret is configured.
+func (c Config) Enabled() bool { return c.SigningSecret != "" }
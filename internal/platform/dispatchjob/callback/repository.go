@@ -0,0 +1,159 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// Repository reads/writes msg_dispatch_job_callbacks. Every transition is
+// a direct write.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+const selectCols = `job_id, url, client_id, state, job_status, attempts, error,
+	next_attempt_at, created_at, completed_at`
+
+func scanCallback(row pgx.Row) (*Callback, error) {
+	var c Callback
+	if err := row.Scan(&c.JobID, &c.URL, &c.ClientID, &c.State, &c.JobStatus, &c.Attempts,
+		&c.Error, &c.NextAttemptAt, &c.CreatedAt, &c.CompletedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindByJobID loads a job's callback. Returns (nil, nil) when the job
+// registered none.
+func (r *Repository) FindByJobID(ctx context.Context, jobID string) (*Callback, error) {
+	c, err := scanCallback(r.pool.QueryRow(ctx,
+		`SELECT `+selectCols+` FROM msg_dispatch_job_callbacks WHERE job_id = $1`, jobID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find_dispatch_job_callback: %w", err)
+	}
+	return c, nil
+}
+
+// Register records callbacks for jobs about to be inserted, in one
+// round-trip. A job already registered keeps its URL, so an SDK retry of
+// the same batch is harmless.
+func (r *Repository) Register(ctx context.Context, regs []Registration) error {
+	if len(regs) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, reg := range regs {
+		batch.Queue(
+			`INSERT INTO msg_dispatch_job_callbacks (job_id, url, client_id)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT (job_id) DO NOTHING`,
+			reg.JobID, reg.URL, reg.ClientID)
+	}
+	br := r.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range regs {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("register_dispatch_job_callback: %w", err)
+		}
+	}
+	return nil
+}
+
+// Arm makes a job's callback due now, carrying the terminal status it
+// reached. A no-op for a job that registered none.
+func (r *Repository) Arm(ctx context.Context, jobID string, status common.DispatchStatus) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_job_callbacks
+		    SET state = 'PENDING', job_status = $2, attempts = 0, error = NULL,
+		        next_attempt_at = NOW(), completed_at = NULL
+		  WHERE job_id = $1`, jobID, string(status))
+	if err != nil {
+		return fmt.Errorf("arm_dispatch_job_callback: %w", err)
+	}
+	return nil
+}
+
+// Claim moves the most overdue PENDING callback — or a PROCESSING one
+// claimed more than staleAfter ago — to PROCESSING, counting the attempt.
+// Returns (nil, nil) when nothing is due.
+func (r *Repository) Claim(ctx context.Context, staleAfter time.Duration) (*Callback, error) {
+	c, err := scanCallback(r.pool.QueryRow(ctx,
+		`UPDATE msg_dispatch_job_callbacks
+		    SET state = 'PROCESSING', attempts = attempts + 1, claimed_at = NOW()
+		  WHERE job_id = (
+		        SELECT job_id FROM msg_dispatch_job_callbacks
+		         WHERE (state = 'PENDING' AND next_attempt_at <= NOW())
+		            OR (state = 'PROCESSING' AND claimed_at < $1)
+		         ORDER BY next_attempt_at
+		         LIMIT 1
+		         FOR UPDATE SKIP LOCKED)
+		 RETURNING `+selectCols,
+		time.Now().Add(-staleAfter).UTC()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim_dispatch_job_callback: %w", err)
+	}
+	return c, nil
+}
+
+// Finish marks a callback DELIVERED or FAILED; reason is the failure, if
+// any.
+func (r *Repository) Finish(ctx context.Context, jobID string, state State, reason *string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_job_callbacks
+		    SET state = $2, error = $3, completed_at = NOW()
+		  WHERE job_id = $1 AND state = 'PROCESSING'`, jobID, string(state), reason)
+	if err != nil {
+		return fmt.Errorf("finish_dispatch_job_callback: %w", err)
+	}
+	return nil
+}
+
+// Retry puts a callback back to PENDING until next.
+func (r *Repository) Retry(ctx context.Context, jobID, reason string, next time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_job_callbacks
+		    SET state = 'PENDING', error = $2, next_attempt_at = $3
+		  WHERE job_id = $1 AND state = 'PROCESSING'`, jobID, reason, next.UTC())
+	if err != nil {
+		return fmt.Errorf("retry_dispatch_job_callback: %w", err)
+	}
+	return nil
+}
+
+// Defer puts a callback back to PENDING until next without spending the
+// attempt Claim counted — used when its host is over the rate limit.
+func (r *Repository) Defer(ctx context.Context, jobID string, next time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_job_callbacks
+		    SET state = 'PENDING', attempts = attempts - 1, next_attempt_at = $2
+		  WHERE job_id = $1 AND state = 'PROCESSING'`, jobID, next.UTC())
+	if err != nil {
+		return fmt.Errorf("defer_dispatch_job_callback: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes DELIVERED and FAILED callbacks finished before cutoff.
+func (r *Repository) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM msg_dispatch_job_callbacks
+		  WHERE state IN ('DELIVERED', 'FAILED') AND completed_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("prune_dispatch_job_callbacks: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
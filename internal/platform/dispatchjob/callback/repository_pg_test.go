@@ -0,0 +1,123 @@
+//go:build integration
+
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+func seedJob(t *testing.T, pool *pgxpool.Pool, id string) {
+	t.Helper()
+	_, err := pool.Exec(context.Background(),
+		`INSERT INTO msg_dispatch_jobs (id, external_id, code, target_url, status, attempt_count, completed_at)
+		 VALUES ($1, 'order-42', 'cb:test:evt', 'http://unused', 'COMPLETED', 1, NOW())`, id)
+	require.NoError(t, err)
+}
+
+func testConfig() Config {
+	cfg := ConfigFromEnv()
+	cfg.SigningSecret = "cb-secret"
+	cfg.MaxAttempts = 2
+	cfg.Backoff = dispatchjob.BackoffPolicy{} // retries are due at once
+	return cfg
+}
+
+// TestRunnerSendsArmedCallback covers the lifecycle: a registration waits
+// for its job, Arm makes it due, and the runner POSTs a notification the
+// producer can verify with the shared secret.
+func TestRunnerSendsArmedCallback(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := NewRepository(pool)
+
+	var got atomic.Value
+	producer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get(TimestampHeader)
+		assert.Equal(t, Sign("cb-secret", ts, body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "cbjob_ok01", r.Header.Get("X-Dispatch-Job-Id"))
+		got.Store(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(producer.Close)
+
+	seedJob(t, pool, "cbjob_ok01")
+	regs := []Registration{{JobID: "cbjob_ok01", URL: producer.URL}}
+	require.NoError(t, repo.Register(ctx, regs))
+	require.NoError(t, repo.Register(ctx, regs), "a retried create registers once")
+
+	r := NewRunner(testConfig(), repo, dispatchjob.NewRepository(pool))
+	ran, err := r.runOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, ran, "a waiting callback is not due")
+
+	require.NoError(t, repo.Arm(ctx, "cbjob_ok01", common.DispatchCompleted))
+	ran, err = r.runOnce(ctx)
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	c, err := repo.FindByJobID(ctx, "cbjob_ok01")
+	require.NoError(t, err)
+	assert.Equal(t, StateDelivered, c.State)
+	assert.EqualValues(t, 1, c.Attempts)
+
+	var n Notification
+	require.NoError(t, json.Unmarshal(got.Load().([]byte), &n))
+	assert.Equal(t, "cbjob_ok01", n.JobID)
+	assert.Equal(t, common.DispatchCompleted, n.Status)
+	assert.Equal(t, "order-42", *n.ExternalID)
+	assert.EqualValues(t, 1, n.AttemptCount)
+	assert.NotNil(t, n.CompletedAt)
+}
+
+// TestRunnerRetriesThenFails: a non-2xx answer retries until MaxAttempts,
+// then the callback is FAILED with the last error.
+func TestRunnerRetriesThenFails(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := NewRepository(pool)
+
+	var calls atomic.Int32
+	producer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(producer.Close)
+
+	seedJob(t, pool, "cbjob_fail1")
+	require.NoError(t, repo.Register(ctx, []Registration{{JobID: "cbjob_fail1", URL: producer.URL}}))
+	require.NoError(t, repo.Arm(ctx, "cbjob_fail1", common.DispatchFailed))
+
+	r := NewRunner(testConfig(), repo, dispatchjob.NewRepository(pool))
+	for range 2 {
+		ran, err := r.runOnce(ctx)
+		require.NoError(t, err)
+		assert.True(t, ran)
+	}
+	ran, err := r.runOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, ran, "nothing left to send")
+
+	c, err := repo.FindByJobID(ctx, "cbjob_fail1")
+	require.NoError(t, err)
+	assert.Equal(t, StateFailed, c.State)
+	assert.EqualValues(t, 2, c.Attempts)
+	assert.Contains(t, *c.Error, "503")
+	assert.EqualValues(t, 2, calls.Load())
+}
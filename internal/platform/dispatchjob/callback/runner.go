@@ -0,0 +1,219 @@
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+)
+
+// JobLoader loads the job a callback reports on. Satisfied by
+// *dispatchjob.Repository.
+type JobLoader interface {
+	FindByID(ctx context.Context, id string) (*dispatchjob.DispatchJob, error)
+}
+
+// pruneEvery is how often finished callbacks past Retention are deleted.
+const pruneEvery = time.Hour
+
+// Runner sends armed callbacks. Construct with NewRunner and run Run in
+// its own goroutine.
+type Runner struct {
+	cfg    Config
+	repo   *Repository
+	jobs   JobLoader
+	client *http.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter // by callback host
+}
+
+// NewRunner wires a runner.
+func NewRunner(cfg Config, repo *Repository, jobs JobLoader) *Runner {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	return &Runner{
+		cfg:  cfg,
+		repo: repo,
+		jobs: jobs,
+		// No redirect-following: a 3xx is not an acknowledgement.
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now:      time.Now,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// SetEgress sends callbacks through the egress policy, like deliveries:
+// the host is resolved, checked and dialled by address. Set once at
+// startup.
+func (r *Runner) SetEgress(p *egress.Policy) { r.client.Transport = p.Transport(nil) }
+
+// Run polls for due callbacks every PollInterval until ctx is cancelled,
+// draining them with Workers concurrent senders, and prunes finished ones
+// hourly.
+func (r *Runner) Run(ctx context.Context) {
+	slog.Info("dispatch callback runner starting", "interval", r.cfg.PollInterval, "workers", r.cfg.Workers)
+	var wg sync.WaitGroup
+	for range r.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	prune := time.NewTicker(pruneEvery)
+	defer prune.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			slog.Info("dispatch callback runner stopped")
+			return
+		case <-prune.C:
+			if n, err := r.repo.Prune(ctx, r.now().Add(-r.cfg.Retention)); err != nil {
+				slog.Warn("dispatch callback prune failed", "err", err)
+			} else if n > 0 {
+				slog.Info("dispatch callbacks pruned", "count", n)
+			}
+		}
+	}
+}
+
+func (r *Runner) work(ctx context.Context) {
+	t := time.NewTicker(r.cfg.PollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			for ctx.Err() == nil {
+				ran, err := r.runOnce(ctx)
+				if err != nil {
+					slog.Warn("dispatch callback runner error", "err", err)
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
+
+// runOnce claims and sends one callback. ran is false when nothing is due.
+func (r *Runner) runOnce(ctx context.Context) (ran bool, err error) {
+	c, err := r.repo.Claim(ctx, r.cfg.StaleAfter)
+	if err != nil || c == nil {
+		return false, err
+	}
+	if wait := r.reserve(c.URL); wait > 0 {
+		return true, r.repo.Defer(ctx, c.JobID, r.now().Add(wait))
+	}
+
+	job, err := r.jobs.FindByID(ctx, c.JobID)
+	if err != nil {
+		return true, r.retry(ctx, c, err)
+	}
+	if job == nil {
+		reason := "dispatch job no longer exists"
+		return true, r.repo.Finish(ctx, c.JobID, StateFailed, &reason)
+	}
+	status := job.Status
+	if c.JobStatus != nil {
+		status = *c.JobStatus
+	}
+
+	if err := r.send(ctx, c, NewNotification(job, status)); err != nil {
+		return true, r.retry(ctx, c, err)
+	}
+	slog.Debug("dispatch callback delivered", "job_id", c.JobID, "status", status, "attempt", c.Attempts)
+	return true, r.repo.Finish(ctx, c.JobID, StateDelivered, nil)
+}
+
+// retry schedules another attempt with backoff, or FAILs the callback
+// once MaxAttempts is spent.
+func (r *Runner) retry(ctx context.Context, c *Callback, cause error) error {
+	if int(c.Attempts) >= r.cfg.MaxAttempts {
+		slog.Warn("dispatch callback failed", "job_id", c.JobID, "attempts", c.Attempts, "err", cause)
+		reason := cause.Error()
+		return r.repo.Finish(ctx, c.JobID, StateFailed, &reason)
+	}
+	backoff := r.cfg.Backoff.Delay(dispatchjob.RetryExponentialBackoff, c.Attempts, nil)
+	return r.repo.Retry(ctx, c.JobID, cause.Error(), r.now().Add(backoff))
+}
+
+// send POSTs n to the callback URL, signed. Anything but a 2xx is an
+// error.
+func (r *Runner) send(ctx context.Context, c *Callback, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	// Millisecond-precision ISO8601 UTC, as the router's webhook signature.
+	ts := r.now().UTC().Format("2006-01-02T15:04:05.000Z")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(r.cfg.SigningSecret, ts, body))
+	req.Header.Set(TimestampHeader, ts)
+	req.Header.Set("X-Dispatch-Job-Id", n.JobID)
+	req.Header.Set("X-Callback-Attempt", strconv.Itoa(int(c.Attempts)))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %s", resp.Status)
+	}
+	return nil
+}
+
+// reserve takes a token from the callback host's limiter, returning how
+// long to wait instead when none is available.
+func (r *Runner) reserve(raw string) time.Duration {
+	if r.cfg.RatePerHost <= 0 {
+		return 0
+	}
+	host := raw
+	if u, err := url.Parse(raw); err == nil {
+		host = u.Host
+	}
+	r.mu.Lock()
+	lim, ok := r.limiters[host]
+	if !ok {
+		lim = rate.NewLimiter(rate.Limit(r.cfg.RatePerHost), max(1, int(r.cfg.RatePerHost)))
+		r.limiters[host] = lim
+	}
+	r.mu.Unlock()
+
+	res := lim.ReserveN(r.now(), 1)
+	wait := res.DelayFrom(r.now())
+	if wait > 0 {
+		res.CancelAt(r.now())
+	}
+	return wait
+}
@@ -0,0 +1,41 @@
+package callback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateURL(t *testing.T) {
+	assert.NoError(t, ValidateURL("https://producer.example.com/hooks/dispatch"))
+	assert.NoError(t, ValidateURL("http://producer.internal:8080/cb"))
+	assert.Error(t, ValidateURL("/relative/path"))
+	assert.Error(t, ValidateURL("ftp://producer.example.com/cb"))
+	assert.Error(t, ValidateURL("https://"))
+}
+
+func TestSignMatchesWebhookScheme(t *testing.T) {
+	// hex HMAC-SHA256("secret", timestamp + body), as router webhooks.
+	got := Sign("secret", "2026-01-02T03:04:05.000Z", []byte(`{"a":1}`))
+	assert.Equal(t, "94054e6dca97d1f3b09073a046f34d95d522e3a56d11228e09f914c97868b3f6", got)
+	assert.NotEqual(t, got, Sign("secret", "2026-01-02T03:04:05.001Z", []byte(`{"a":1}`)), "the timestamp is signed")
+}
+
+func TestReserveLimitsPerHost(t *testing.T) {
+	cfg := ConfigFromEnv()
+	cfg.RatePerHost = 2
+	r := NewRunner(cfg, nil, nil)
+	clock := time.Now()
+	r.now = func() time.Time { return clock }
+
+	assert.Zero(t, r.reserve("https://a.example.com/cb/1"))
+	assert.Zero(t, r.reserve("https://a.example.com/cb/2"))
+	wait := r.reserve("https://a.example.com/cb/3")
+	assert.Positive(t, wait, "the burst is spent")
+	assert.LessOrEqual(t, wait, 500*time.Millisecond)
+	assert.Zero(t, r.reserve("https://b.example.com/cb"), "hosts are limited separately")
+
+	clock = clock.Add(wait)
+	assert.Zero(t, r.reserve("https://a.example.com/cb/3"), "a deferral doesn't consume a token")
+}
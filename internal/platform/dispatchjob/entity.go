@@ -68,13 +68,16 @@ const (
 	ErrorTimeout    ErrorType = "TIMEOUT"
 	ErrorHTTPError  ErrorType = "HTTP_ERROR"
 	ErrorValidation ErrorType = "VALIDATION"
-	ErrorUnknown    ErrorType = "UNKNOWN"
+	// ErrorAuth — target credentials could not be obtained (token endpoint
+	// / STS refused them) or the target rejected them with 401/403.
+	ErrorAuth    ErrorType = "AUTH"
+	ErrorUnknown ErrorType = "UNKNOWN"
 )
 
 // ParseErrorType — lenient parser. Unknown → UNKNOWN.
 func ParseErrorType(s string) ErrorType {
 	switch s {
-	case string(ErrorConnection), string(ErrorTimeout), string(ErrorHTTPError), string(ErrorValidation):
+	case string(ErrorConnection), string(ErrorTimeout), string(ErrorHTTPError), string(ErrorValidation), string(ErrorAuth):
 		return ErrorType(s)
 	default:
 		return ErrorUnknown
@@ -160,6 +163,7 @@ type DispatchJob struct {
 	DispatchPoolID     *string               `json:"dispatchPoolId,omitempty"`
 	MessageGroup       *string               `json:"messageGroup,omitempty"`
 	Mode               common.DispatchMode   `json:"mode"`
+	Priority           common.Priority       `json:"priority"`
 	Sequence           int32                 `json:"sequence"`
 	TimeoutSeconds     uint32                `json:"timeoutSeconds"`
 	SchemaID           *string               `json:"schemaId,omitempty"`
@@ -178,6 +182,17 @@ type DispatchJob struct {
 	LastAttemptAt      *time.Time            `json:"lastAttemptAt,omitempty"`
 	CompletedAt        *time.Time            `json:"completedAt,omitempty"`
 	DurationMillis     *int64                `json:"durationMillis,omitempty"`
+	// AckDeadline is set on a job whose subscription requires receiver
+	// acknowledgement: when its latest delivery stops waiting for the ack.
+	AckDeadline *time.Time `json:"ackDeadline,omitempty"`
+	// AckedAt is when the receiver acknowledged the job's delivery token.
+	AckedAt *time.Time `json:"ackedAt,omitempty"`
+	// FirstAttemptAt is when the first delivery attempt started; kept
+	// through retries. nil until the job is first delivered.
+	FirstAttemptAt *time.Time `json:"firstAttemptAt,omitempty"`
+	// Environment is SANDBOX or PRODUCTION: the subscription's environment
+	// for fan-out jobs, the calling key's for SDK-created ones.
+	Environment common.Environment `json:"environment"`
 }
 
 // PayloadJSON returns the payload parsed as JSON when ContentType is
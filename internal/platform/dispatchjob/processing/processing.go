@@ -10,8 +10,20 @@
 //  3. delivers the real webhook to the subscriber's target_url,
 //  4. records the attempt in msg_dispatch_job_attempts,
 //  5. advances the job status (COMPLETED / retry-scheduled / FAILED),
+//     arming the producer's status callback once terminal (see callback),
 //  6. returns {"ack": true} so the router removes the queue message.
 //
+// A subscription that requires receiver acknowledgement (see SetAcks)
+// changes step 5 for a 2xx: the delivery carried a delivery token, and the
+// job waits PENDING until the receiver POSTs it to
+// /api/dispatch/deliveries/{token}/ack, which completes the job. One still
+// unacknowledged at its deadline is delivered again with the same token —
+// the receiver's de-duplication key — until its retries run out.
+//
+// A payload offloaded to object storage (a {"$payloadRef": …} document —
+// see payloadlimit) is claimed just before step 3 and, for a claim-checked
+// job payload, deleted once the job is terminal.
+//
 // Retries are driven by the scheduler poller via scheduled_for, NOT by the
 // queue: this endpoint always ACKs and reschedules failed jobs to
 // NOW()+backoff, so exactly one component re-dispatches a job (no queue-NACK
@@ -32,7 +44,16 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/cloudevents"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/payloadlimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliveryheaders"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 )
 
 // maxResponseBody caps how much of a subscriber response we read into the
@@ -42,16 +63,9 @@ const maxResponseBody = 64 << 10 // 64 KiB
 // defaultTimeout applies when a job carries no explicit timeout_seconds.
 const defaultTimeout = 30 * time.Second
 
-// retryBackoff maps a just-finished attempt number (1-based) to the delay
-// before the poller may re-dispatch. Index attemptNumber-1, clamped to the
-// last element. Exponential-ish, capped at 2m.
-var retryBackoff = []time.Duration{
-	5 * time.Second,
-	15 * time.Second,
-	30 * time.Second,
-	60 * time.Second,
-	120 * time.Second,
-}
+// quotaDeferral is how long a job for a client over its monthly delivery
+// quota waits before being checked again.
+const quotaDeferral = 5 * time.Minute
 
 // Verifier checks the HMAC bearer token the router forwards (the scheduler
 // signed the job id). Satisfied by *scheduler.DispatchAuthService.
@@ -59,11 +73,128 @@ type Verifier interface {
 	Verify(jobID, token string) bool
 }
 
+// TargetAuthenticator applies a subscription's outbound auth (SigV4,
+// OAuth2 client credentials) to a delivery. Satisfied by
+// *targetauth.Authenticator. Errors that implement Temporary() bool and
+// report true are treated as connection failures, anything else as an
+// auth failure.
+type TargetAuthenticator interface {
+	Authorize(ctx context.Context, subscriptionID string, req *http.Request, body []byte) (applied bool, err error)
+	Rejected(subscriptionID string)
+}
+
+// TargetTransport supplies a subscription's TLS-configured transport
+// (client certificate, extra trusted CAs). Satisfied by
+// *targetauth.Transports. A nil RoundTripper means the subscription has no
+// TLS material; errors are classified like TargetAuthenticator's.
+type TargetTransport interface {
+	Transport(ctx context.Context, subscriptionID string) (http.RoundTripper, error)
+}
+
+// PayloadTransformer renders a subscription's receiver-specific body from
+// the event envelope. Satisfied by *transform.Transformer. Errors that
+// implement Temporary() bool and report true are treated as connection
+// failures, anything else as a validation failure.
+type PayloadTransformer interface {
+	Transform(ctx context.Context, subscriptionID string, envelope map[string]any) (body []byte, contentType string, applied bool, err error)
+}
+
+// DeliveryFormats resolves a subscription's webhook format. Satisfied by
+// *deliverysettings.Cache. Errors are treated as connection failures.
+type DeliveryFormats interface {
+	DeliveryFormat(ctx context.Context, subscriptionID string) (subscription.DeliveryFormat, error)
+}
+
+// DeliveryHeaders stamps the standard X-FlowCatalyst-* headers a
+// subscription's deliveries carry. Satisfied by *deliveryheaders.Resolver.
+// Errors are treated as connection failures.
+type DeliveryHeaders interface {
+	Apply(ctx context.Context, header http.Header, d deliveryheaders.Delivery, body []byte) error
+}
+
+// DeliveryWindows resolves a subscription's delivery calendar. Satisfied
+// by *deliverysettings.Cache. A nil schedule is always open.
+type DeliveryWindows interface {
+	DeliveryWindow(ctx context.Context, subscriptionID string) (*deliverywindow.Schedule, error)
+}
+
+// DeliveryMeter counts successful deliveries per client and gates them on
+// the client's monthly quota. Satisfied by *metering.Meter.
+type DeliveryMeter interface {
+	AllowDelivery(ctx context.Context, clientID string) bool
+	RecordDelivery(clientID string)
+}
+
+// Taps reports whether a subscription is a tap, whose deliveries aren't
+// metered. Satisfied by *deliverysettings.Cache.
+type Taps interface {
+	IsTap(ctx context.Context, subscriptionID string) (bool, error)
+}
+
+// EgressOverrides resolves a subscription's egress settings: whether it
+// may deliver to private ranges, and through which proxy. Satisfied by
+// *deliverysettings.Cache. Errors are treated as connection failures.
+type EgressOverrides interface {
+	Egress(ctx context.Context, subscriptionID string) (egress.Settings, error)
+}
+
+// ClaimCheck resolves offloaded payloads. Satisfied by
+// *payloadlimit.Offloader. Claim errors are treated as connection
+// failures; Release errors are logged.
+type ClaimCheck interface {
+	Claim(ctx context.Context, payload string) (string, *payloadlimit.Ref, error)
+	Release(ctx context.Context, ref *payloadlimit.Ref) error
+}
+
+// StatusCallbacks arms a job's producer status callback once the job is
+// terminal. Satisfied by *callback.Repository; a job that registered no
+// callback is a no-op. Errors are logged.
+type StatusCallbacks interface {
+	Arm(ctx context.Context, jobID string, status common.DispatchStatus) error
+}
+
+// AckTimeouts resolves how long a subscription's deliveries wait for the
+// receiver's acknowledgement, zero when it requires none. Satisfied by
+// *deliverysettings.Cache.
+type AckTimeouts interface {
+	AckTimeout(ctx context.Context, subscriptionID string) (time.Duration, error)
+}
+
+// RetryAfterPolicy reports whether a subscription waits out its
+// receiver's Retry-After. Satisfied by *deliverysettings.Cache.
+type RetryAfterPolicy interface {
+	HonorRetryAfter(ctx context.Context, subscriptionID string) (bool, error)
+}
+
+// DeliveryTokens issues and checks the token a receiver acknowledges a
+// delivery with. Satisfied by *scheduler.DispatchAuthService.
+type DeliveryTokens interface {
+	DeliveryToken(jobID string) string
+	ParseDeliveryToken(token string) (jobID string, ok bool)
+}
+
 // Handler serves the dispatch-processing callback.
 type Handler struct {
-	repo     *dispatchjob.Repository
-	verifier Verifier
-	client   *http.Client
+	repo        *dispatchjob.Repository
+	verifier    Verifier
+	client      *http.Client
+	targetAuth  TargetAuthenticator // optional; set via SetTargetAuth
+	targetTLS   TargetTransport     // optional; set via SetTargetTLS
+	transformer PayloadTransformer  // optional; set via SetTransformer
+	formats     DeliveryFormats     // optional; set via SetDeliveryFormats
+	headers     DeliveryHeaders     // optional; set via SetDeliveryHeaders
+	windows     DeliveryWindows     // optional; set via SetDeliveryWindows
+	meter       DeliveryMeter       // optional; set via SetMeter
+	taps        Taps                // optional; set via SetTaps
+	claims      ClaimCheck          // optional; set via SetClaimCheck
+	egress      EgressOverrides     // optional; set via SetEgress
+	callbacks   StatusCallbacks     // optional; set via SetCallbacks
+	ackTimeouts AckTimeouts         // optional; set via SetAcks
+	tokens      DeliveryTokens      // optional; set via SetAcks
+	retryAfter  RetryAfterPolicy    // optional; set via SetRetryAfter
+	// maxRetryAfter caps the delay a receiver can ask for.
+	maxRetryAfter time.Duration
+	backoff       dispatchjob.BackoffPolicy
 }
 
 // New wires the handler. verifier may be nil (dev/no-auth), in which case the
@@ -71,8 +202,10 @@ type Handler struct {
 // deployment where the scheduler signs tokens, so callers should pass one.
 func New(repo *dispatchjob.Repository, verifier Verifier) *Handler {
 	return &Handler{
-		repo:     repo,
-		verifier: verifier,
+		repo:          repo,
+		verifier:      verifier,
+		maxRetryAfter: DefaultMaxRetryAfter,
+		backoff:       dispatchjob.DefaultBackoffPolicy(),
 		// Outer ceiling only; each delivery uses a per-job context timeout.
 		// No redirect-following: a 3xx from a webhook target is not a success.
 		client: &http.Client{
@@ -84,11 +217,92 @@ func New(repo *dispatchjob.Repository, verifier Verifier) *Handler {
 	}
 }
 
+// SetTargetAuth wires per-subscription outbound auth. Opt-in: when unset,
+// deliveries carry no target credentials. Set once at startup.
+func (h *Handler) SetTargetAuth(a TargetAuthenticator) { h.targetAuth = a }
+
+// SetTargetTLS wires per-subscription mTLS / custom CA trust. Opt-in:
+// when unset, every delivery uses the default transport. Set once at
+// startup.
+func (h *Handler) SetTargetTLS(t TargetTransport) { h.targetTLS = t }
+
+// SetTransformer wires per-subscription payload transforms. Opt-in: when
+// unset, deliveries carry the default envelope. Set once at startup.
+func (h *Handler) SetTransformer(t PayloadTransformer) { h.transformer = t }
+
+// SetDeliveryFormats wires per-subscription CloudEvents delivery. Opt-in:
+// when unset, every job is sent in the native format.
+func (h *Handler) SetDeliveryFormats(f DeliveryFormats) { h.formats = f }
+
+// SetDeliveryHeaders wires the standard delivery headers (ids, attempt,
+// signature, trace context). Opt-in: when unset, deliveries carry only
+// X-Dispatch-Job-Id and X-Event-Type.
+func (h *Handler) SetDeliveryHeaders(dh DeliveryHeaders) { h.headers = dh }
+
+// SetDeliveryWindows wires per-subscription delivery calendars. Opt-in:
+// when unset, jobs are delivered whenever they arrive.
+func (h *Handler) SetDeliveryWindows(dw DeliveryWindows) { h.windows = dw }
+
+// SetMeter wires per-client delivery metering and quota enforcement.
+// Opt-in: when unset, deliveries are unmetered. Set once at startup.
+func (h *Handler) SetMeter(m DeliveryMeter) { h.meter = m }
+
+// SetTaps exempts tap subscriptions' deliveries from metering. Opt-in:
+// when unset, they are metered like any other. Set once at startup.
+func (h *Handler) SetTaps(t Taps) { h.taps = t }
+
+// SetClaimCheck wires offloaded-payload resolution. Opt-in: when unset,
+// payloads are delivered exactly as stored. Set once at startup.
+func (h *Handler) SetClaimCheck(c ClaimCheck) { h.claims = c }
+
+// SetEgress enforces the egress policy on every delivery: the target is
+// resolved, checked and dialled by address from the configured source
+// addresses or proxy, and overrides says which subscriptions may reach
+// private ranges or use a named proxy (nil: none). Opt-in: when unset,
+// deliveries go wherever the target URL points. Set once at startup, and
+// route TLS transports and target auth through the same policy.
+func (h *Handler) SetEgress(p *egress.Policy, overrides EgressOverrides) {
+	h.client.Transport = p.Transport(nil)
+	h.egress = overrides
+}
+
+// SetCallbacks arms producer status callbacks when a job completes or
+// exhausts its retries. Opt-in: when unset, producers poll for the
+// outcome. Set once at startup.
+func (h *Handler) SetCallbacks(c StatusCallbacks) { h.callbacks = c }
+
+// SetAcks wires receiver acknowledgement for the subscriptions that
+// require it, and mounts its endpoint. Opt-in: when unset, a 2xx
+// completes every delivery. Set once at startup, before Mount.
+func (h *Handler) SetAcks(timeouts AckTimeouts, tokens DeliveryTokens) {
+	h.ackTimeouts = timeouts
+	h.tokens = tokens
+}
+
+// SetRetryAfter wires the per-subscription Retry-After switch and caps
+// the delay; max <= 0 keeps DefaultMaxRetryAfter. When unset, every
+// receiver's Retry-After is honoured up to the cap. Set once at startup.
+func (h *Handler) SetRetryAfter(p RetryAfterPolicy, max time.Duration) {
+	h.retryAfter = p
+	if max > 0 {
+		h.maxRetryAfter = max
+	}
+}
+
+// SetRetryBackoff overrides the retry backoff policy (default
+// dispatchjob.DefaultBackoffPolicy). Set once at startup.
+func (h *Handler) SetRetryBackoff(p dispatchjob.BackoffPolicy) { h.backoff = p }
+
 // Mount attaches POST /api/dispatch/process to the given (unauthenticated)
 // chi router. The handler self-verifies the scheduler HMAC bearer, so it must
-// live OUTSIDE the platform JWT middleware.
+// live OUTSIDE the platform JWT middleware. With SetAcks, POST
+// /api/dispatch/deliveries/{token}/ack is mounted alongside it; the signed
+// delivery token is its only credential.
 func (h *Handler) Mount(r chi.Router) {
 	r.Post("/api/dispatch/process", h.serve)
+	if h.tokens != nil {
+		r.Post("/api/dispatch/deliveries/{token}/ack", h.ack)
+	}
 }
 
 type processRequest struct {
@@ -151,13 +365,97 @@ func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Delivered its last attempt but never acknowledged: the ack deadline
+	// lapsed (that is what made it due again) with no retries left.
+	if job.AckDeadline != nil && job.AttemptCount >= int32(job.MaxRetries) {
+		errMsg := "Delivery not acknowledged by the receiver"
+		dur := int64(0)
+		if job.DurationMillis != nil {
+			dur = *job.DurationMillis
+		}
+		if err := h.repo.MarkFailed(ctx, jobID, &errMsg, dur); err != nil {
+			slog.Warn("dispatch process: mark failed failed", "job_id", jobID, "err", err)
+		}
+		slog.Warn("dispatch failed (not acknowledged)", "job_id", jobID, "attempts", job.AttemptCount, "max", job.MaxRetries)
+		h.armCallback(ctx, jobID, common.DispatchFailed)
+		h.release(ctx, job)
+		writeJSON(w, http.StatusOK, processResponse{Ack: true, Message: "not acknowledged"})
+		return
+	}
+
+	metered, err := h.metered(ctx, job)
+	if err != nil {
+		slog.Error("dispatch process: load tap failed", "job_id", jobID, "err", err)
+		writeJSON(w, http.StatusInternalServerError, processResponse{Ack: false, Message: "load failed"})
+		return
+	}
+	// Over the client's monthly delivery quota: park the job without an
+	// attempt (nothing was sent) and without spending its retry budget.
+	if metered && !h.meter.AllowDelivery(ctx, *job.ClientID) {
+		if err := h.repo.Reschedule(ctx, jobID, time.Now().Add(quotaDeferral)); err != nil {
+			slog.Warn("dispatch process: reschedule failed", "job_id", jobID, "err", err)
+		}
+		slog.Info("dispatch deferred", "job_id", jobID, "retry_after", quotaDeferral, "reason", "monthly delivery quota exceeded")
+		writeJSON(w, http.StatusOK, processResponse{Ack: true, Message: "delivery quota exceeded"})
+		return
+	}
+
+	// Outside the subscription's delivery window — the job waited in the
+	// queue past its close, or a blackout began: park it until the window
+	// reopens, again without an attempt. The scheduler defers closed jobs
+	// before publishing, so this only catches the in-flight ones.
+	if h.windows != nil && job.SubscriptionID != nil {
+		dw, err := h.windows.DeliveryWindow(ctx, *job.SubscriptionID)
+		if err != nil {
+			slog.Error("dispatch process: load delivery window failed", "job_id", jobID, "err", err)
+			writeJSON(w, http.StatusInternalServerError, processResponse{Ack: false, Message: "load failed"})
+			return
+		}
+		if now := time.Now(); !dw.Open(now) {
+			next, ok := dw.NextOpen(now)
+			if !ok {
+				next = now.Add(quotaDeferral)
+			}
+			if err := h.repo.Reschedule(ctx, jobID, next); err != nil {
+				slog.Warn("dispatch process: reschedule failed", "job_id", jobID, "err", err)
+			}
+			slog.Info("dispatch deferred", "job_id", jobID, "until", next, "reason", "outside delivery window")
+			writeJSON(w, http.StatusOK, processResponse{Ack: true, Message: "outside delivery window"})
+			return
+		}
+	}
+
+	var ackTimeout time.Duration
+	if h.ackTimeouts != nil && h.tokens != nil && job.SubscriptionID != nil {
+		if ackTimeout, err = h.ackTimeouts.AckTimeout(ctx, *job.SubscriptionID); err != nil {
+			slog.Error("dispatch process: load ack timeout failed", "job_id", jobID, "err", err)
+			writeJSON(w, http.StatusInternalServerError, processResponse{Ack: false, Message: "load failed"})
+			return
+		}
+	}
+
 	if err := h.repo.MarkInProgress(ctx, jobID); err != nil {
 		slog.Warn("dispatch process: mark in-progress failed", "job_id", jobID, "err", err)
 	}
+	// Make the token acknowledgeable before the request leaves: a receiver
+	// may ack before it answers.
+	token := ""
+	if ackTimeout > 0 {
+		if err := h.repo.ExpectAck(ctx, jobID, time.Now().Add(ackTimeout)); err != nil {
+			slog.Warn("dispatch process: expect ack failed", "job_id", jobID, "err", err)
+		}
+		token = h.tokens.DeliveryToken(jobID)
+	}
 
 	attemptNumber := job.AttemptCount + 1
 	attempt := dispatchjob.NewAttempt(attemptNumber)
-	res := h.deliver(ctx, job)
+	claimed, ref, err := h.claim(ctx, job)
+	var res deliveryResult
+	if err != nil {
+		res = deliveryResult{errMessage: "Claim-check payload unavailable: " + err.Error(), errType: dispatchjob.ErrorConnection}
+	} else {
+		res = h.deliver(ctx, claimed, token)
+	}
 
 	// Record the attempt (best-effort; a recording failure must not change
 	// the delivery decision).
@@ -170,13 +468,52 @@ func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
 		slog.Warn("dispatch process: record attempt failed", "job_id", jobID, "err", err)
 	}
 
-	h.advance(ctx, job, attemptNumber, res, attempt)
+	if terminal := h.advance(ctx, job, attemptNumber, res, attempt, ackTimeout); terminal && ref != nil {
+		if err := h.claims.Release(ctx, ref); err != nil {
+			slog.Warn("dispatch process: release claim-check payload failed", "job_id", jobID, "uri", ref.URI, "err", err)
+		}
+	}
+	if res.success && metered {
+		h.meter.RecordDelivery(*job.ClientID)
+	}
 
 	writeJSON(w, http.StatusOK, processResponse{Ack: true})
 }
 
-// advance transitions the job row based on the delivery result.
-func (h *Handler) advance(ctx context.Context, job *dispatchjob.DispatchJob, attemptNumber int32, res deliveryResult, attempt *dispatchjob.Attempt) {
+// metered reports whether the job's delivery counts against its client's
+// quota: metering is on, the job has a client, and its subscription isn't
+// a tap.
+func (h *Handler) metered(ctx context.Context, job *dispatchjob.DispatchJob) (bool, error) {
+	if h.meter == nil || job.ClientID == nil {
+		return false, nil
+	}
+	if h.taps == nil || job.SubscriptionID == nil {
+		return true, nil
+	}
+	tap, err := h.taps.IsTap(ctx, *job.SubscriptionID)
+	return !tap, err
+}
+
+// claim returns job with an offloaded payload swapped for the stored body,
+// plus the resolved reference (nil when the payload was inline).
+func (h *Handler) claim(ctx context.Context, job *dispatchjob.DispatchJob) (*dispatchjob.DispatchJob, *payloadlimit.Ref, error) {
+	if h.claims == nil || job.Payload == nil {
+		return job, nil, nil
+	}
+	body, ref, err := h.claims.Claim(ctx, *job.Payload)
+	if err != nil || ref == nil {
+		return job, nil, err
+	}
+	claimed := *job
+	claimed.Payload = &body
+	return &claimed, ref, nil
+}
+
+// advance transitions the job row based on the delivery result and
+// reports whether the job is now terminal (completed, or out of retries).
+// A non-zero ackTimeout means a 2xx only starts the wait for the
+// receiver's acknowledgement.
+func (h *Handler) advance(ctx context.Context, job *dispatchjob.DispatchJob, attemptNumber int32, res deliveryResult, attempt *dispatchjob.Attempt, ackTimeout time.Duration) bool {
 	jobID := job.ID
 	dur := int64(0)
 	if attempt.DurationMillis != nil {
@@ -184,11 +521,23 @@ func (h *Handler) advance(ctx context.Context, job *dispatchjob.DispatchJob, att
 	}
 
 	switch {
+	case res.success && ackTimeout > 0:
+		// Accepted, not yet acknowledged: park until the deadline, so the
+		// poller delivers it again unless the ack arrives first.
+		deadline := time.Now().Add(ackTimeout)
+		if err := h.repo.AwaitAck(ctx, jobID, deadline, dur); err != nil {
+			slog.Warn("dispatch process: await ack failed", "job_id", jobID, "err", err)
+		}
+		slog.Debug("dispatch delivered, awaiting ack", "job_id", jobID, "status", res.statusCode, "attempt", attemptNumber, "deadline", deadline)
+		return false
+
 	case res.success:
 		if err := h.repo.MarkCompleted(ctx, jobID, dur); err != nil {
 			slog.Warn("dispatch process: mark completed failed", "job_id", jobID, "err", err)
 		}
 		slog.Debug("dispatch delivered", "job_id", jobID, "status", res.statusCode, "attempt", attemptNumber)
+		h.armCallback(ctx, jobID, common.DispatchCompleted)
+		return true
 
 	case res.deferral:
 		// Cooperative back-pressure (ack=false or HTTP 429): retry later
@@ -197,6 +546,7 @@ func (h *Handler) advance(ctx context.Context, job *dispatchjob.DispatchJob, att
 			slog.Warn("dispatch process: reschedule failed", "job_id", jobID, "err", err)
 		}
 		slog.Info("dispatch deferred", "job_id", jobID, "retry_after", res.retryAfter, "reason", res.errMessage)
+		return false
 
 	case int(attemptNumber) >= int(job.MaxRetries):
 		// Out of retries → terminal failure.
@@ -205,26 +555,91 @@ func (h *Handler) advance(ctx context.Context, job *dispatchjob.DispatchJob, att
 			slog.Warn("dispatch process: mark failed failed", "job_id", jobID, "err", err)
 		}
 		slog.Warn("dispatch failed (retries exhausted)", "job_id", jobID, "attempts", attemptNumber, "max", job.MaxRetries, "err", errMsg)
+		h.armCallback(ctx, jobID, common.DispatchFailed)
+		return true
 
 	default:
-		// Retryable failure → schedule a backoff and let the poller pick it up.
+		// Retryable failure → park the row PENDING with scheduled_for set to
+		// the backoff, derived from the persisted attempt count and the
+		// job's retry strategy. The poller re-dispatches once it is due.
 		errMsg := res.errMessage
-		if err := h.repo.ScheduleRetry(ctx, jobID, time.Now().Add(backoffFor(attemptNumber)), &errMsg); err != nil {
+		backoff := h.backoff.Delay(job.RetryStrategy, attemptNumber, nil)
+		if err := h.repo.ScheduleRetry(ctx, jobID, time.Now().Add(backoff), &errMsg); err != nil {
 			slog.Warn("dispatch process: schedule retry failed", "job_id", jobID, "err", err)
 		}
-		slog.Info("dispatch retry scheduled", "job_id", jobID, "attempt", attemptNumber, "backoff", backoffFor(attemptNumber), "err", errMsg)
+		slog.Info("dispatch retry scheduled", "job_id", jobID, "attempt", attemptNumber, "strategy", job.RetryStrategy, "backoff", backoff, "err", errMsg)
+		return false
+	}
+}
+
+// release deletes job's claim-checked payload, if it has one. For a job
+// that went terminal outside a delivery, whose payload wasn't claimed.
+func (h *Handler) release(ctx context.Context, job *dispatchjob.DispatchJob) {
+	if h.claims == nil || job.Payload == nil {
+		return
+	}
+	if ref := payloadlimit.ParseRef(*job.Payload); ref != nil {
+		if err := h.claims.Release(ctx, ref); err != nil {
+			slog.Warn("dispatch process: release claim-check payload failed", "job_id", job.ID, "uri", ref.URI, "err", err)
+		}
 	}
 }
 
-func backoffFor(attemptNumber int32) time.Duration {
-	i := int(attemptNumber) - 1
-	if i < 0 {
-		i = 0
+// ackResponse is the body of a successful acknowledgement. Duplicate is
+// true when the token had already been acknowledged; nothing changed.
+type ackResponse struct {
+	Acknowledged bool `json:"acknowledged"`
+	Duplicate    bool `json:"duplicate"`
+}
+
+// ack serves POST /api/dispatch/deliveries/{token}/ack: the receiver
+// confirms it processed the delivery carrying token, completing the job.
+// Repeating the ack is harmless (duplicate: true). A token that doesn't
+// verify is indistinguishable from an unknown job (404); a job that isn't
+// waiting for an ack is 409.
+func (h *Handler) ack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID, ok := h.tokens.ParseDeliveryToken(chi.URLParam(r, "token"))
+	if !ok {
+		httperror.Write(w, usecase.NotFound("DELIVERY_NOT_FOUND", "Delivery not found"))
+		return
 	}
-	if i >= len(retryBackoff) {
-		i = len(retryBackoff) - 1
+	res, err := h.repo.Acknowledge(ctx, jobID)
+	if err != nil {
+		slog.Error("dispatch ack: acknowledge failed", "job_id", jobID, "err", err)
+		httperror.Write(w, usecase.Internal("REPO", "acknowledge failed", err))
+		return
+	}
+	switch res {
+	case dispatchjob.AckNotFound:
+		httperror.Write(w, usecase.NotFound("DELIVERY_NOT_FOUND", "Delivery not found"))
+		return
+	case dispatchjob.AckNotAwaiting:
+		httperror.Write(w, usecase.BusinessRule("DELIVERY_NOT_AWAITING_ACK", "The delivery is not waiting for an acknowledgement"))
+		return
+	case dispatchjob.AckAccepted:
+		slog.Debug("dispatch acknowledged", "job_id", jobID)
+		h.armCallback(ctx, jobID, common.DispatchCompleted)
+		if h.claims != nil {
+			if job, err := h.repo.FindByID(ctx, jobID); err != nil {
+				slog.Warn("dispatch ack: load job failed", "job_id", jobID, "err", err)
+			} else if job != nil {
+				h.release(ctx, job)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ackResponse{Acknowledged: true, Duplicate: res == dispatchjob.AckDuplicate})
+}
+
+func (h *Handler) armCallback(ctx context.Context, jobID string, status common.DispatchStatus) {
+	if h.callbacks == nil {
+		return
+	}
+	if err := h.callbacks.Arm(ctx, jobID, status); err != nil {
+		slog.Warn("dispatch process: arm status callback failed", "job_id", jobID, "err", err)
 	}
-	return retryBackoff[i]
 }
 
 // deliveryResult is the outcome of one webhook POST.
@@ -248,8 +663,9 @@ func (r deliveryResult) statusCodePtr() *int {
 }
 
 // deliver POSTs the real event to the subscriber's target_url and classifies
-// the response.
-func (h *Handler) deliver(ctx context.Context, job *dispatchjob.DispatchJob) deliveryResult {
+// the response. A non-empty token is sent as X-Delivery-Token for the
+// receiver to acknowledge.
+func (h *Handler) deliver(ctx context.Context, job *dispatchjob.DispatchJob, token string) deliveryResult {
 	timeout := defaultTimeout
 	if job.TimeoutSeconds > 0 {
 		timeout = time.Duration(job.TimeoutSeconds) * time.Second
@@ -257,16 +673,102 @@ func (h *Handler) deliver(ctx context.Context, job *dispatchjob.DispatchJob) del
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	body := buildPayload(job)
+	// Marks the context before target auth, so an OAuth2 token fetch gets
+	// the same override and proxy as the delivery.
+	if h.egress != nil && job.SubscriptionID != nil {
+		s, err := h.egress.Egress(ctx, *job.SubscriptionID)
+		if err != nil {
+			return deliveryResult{errMessage: "Egress settings unavailable: " + err.Error(), errType: dispatchjob.ErrorConnection}
+		}
+		ctx = s.Apply(ctx)
+	}
+
+	format := subscription.DeliveryFlowCatalyst
+	if h.formats != nil && job.SubscriptionID != nil {
+		f, err := h.formats.DeliveryFormat(ctx, *job.SubscriptionID)
+		if err != nil {
+			return deliveryResult{errMessage: "Delivery format unavailable: " + err.Error(), errType: dispatchjob.ErrorConnection}
+		}
+		format = f
+	}
+
+	body, contentType := buildPayload(job), "application/json"
+	transformed := false
+	// The transform runs before target auth: SigV4 signs the final body.
+	if h.transformer != nil && job.SubscriptionID != nil {
+		out, ct, applied, err := h.transformer.Transform(ctx, *job.SubscriptionID, envelope(job))
+		if err != nil {
+			return classifyTransformErr(err)
+		}
+		if applied {
+			body, contentType, transformed = out, ct, true
+		}
+	}
+
+	// CloudEvents formats carry the event data — the transform output if
+	// there is one, the raw payload otherwise; data-only doesn't apply.
+	var ce *cloudevents.Event
+	if format == subscription.DeliveryCloudEventsBinary || format == subscription.DeliveryCloudEventsStructured {
+		if !transformed {
+			body, contentType = []byte("{}"), "application/json"
+			if job.Payload != nil {
+				body = []byte(*job.Payload)
+				if job.PayloadContentType != "" {
+					contentType = job.PayloadContentType
+				}
+			}
+		}
+		ce = cloudEvent(job, body, contentType)
+		if format == subscription.DeliveryCloudEventsStructured {
+			structured, err := ce.MarshalStructured()
+			if err != nil {
+				return deliveryResult{errMessage: "render CloudEvent: " + err.Error(), errType: dispatchjob.ErrorValidation}
+			}
+			body, contentType, ce = structured, cloudevents.ContentTypeStructured, nil
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.TargetURL, bytes.NewReader(body))
 	if err != nil {
 		return deliveryResult{errMessage: "build request: " + err.Error(), errType: dispatchjob.ErrorConnection}
 	}
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	if ce != nil {
+		ce.SetHeaders(req.Header)
+	}
 	req.Header.Set("X-Dispatch-Job-Id", job.ID)
 	req.Header.Set("X-Event-Type", job.Code)
+	if token != "" {
+		req.Header.Set("X-Delivery-Token", token)
+	}
+	// Before target auth, so SigV4 covers them.
+	if h.headers != nil {
+		if err := h.headers.Apply(ctx, req.Header, deliveryHeaders(job), body); err != nil {
+			return deliveryResult{errMessage: "Delivery headers unavailable: " + err.Error(), errType: dispatchjob.ErrorConnection}
+		}
+	}
+
+	authApplied := false
+	if h.targetAuth != nil && job.SubscriptionID != nil {
+		if authApplied, err = h.targetAuth.Authorize(ctx, *job.SubscriptionID, req, body); err != nil {
+			return classifyAuthErr(err)
+		}
+	}
+
+	client := h.client
+	if h.targetTLS != nil && job.SubscriptionID != nil {
+		rt, err := h.targetTLS.Transport(ctx, *job.SubscriptionID)
+		if err != nil {
+			return classifyAuthErr(err)
+		}
+		if rt != nil {
+			c := *h.client
+			c.Transport = rt
+			client = &c
+		}
+	}
 
-	resp, err := h.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		msg, et := classifyTransportErr(err)
 		return deliveryResult{errMessage: msg, errType: et}
@@ -276,6 +778,11 @@ func (h *Handler) deliver(ctx context.Context, job *dispatchjob.DispatchJob) del
 	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
 	bodyStr := string(raw)
 	status := resp.StatusCode
+	var retryAfter time.Duration
+	var honoured bool
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		retryAfter, honoured = h.receiverRetryAfter(ctx, job, resp)
+	}
 
 	switch {
 	case status >= 200 && status < 300:
@@ -294,15 +801,42 @@ func (h *Handler) deliver(ctx context.Context, job *dispatchjob.DispatchJob) del
 		return deliveryResult{success: true, statusCode: status, hasStatus: true, body: &bodyStr}
 
 	case status == http.StatusTooManyRequests: // 429 → back-pressure, not a failure
+		if !honoured {
+			retryAfter = defaultRetryAfter
+		}
 		return deliveryResult{
 			deferral:   true,
-			retryAfter: retryAfterOrDefault(resp),
+			retryAfter: retryAfter,
 			statusCode: status,
 			hasStatus:  true,
 			body:       &bodyStr,
 			errMessage: "rate limited (429)",
 		}
 
+	case status == http.StatusServiceUnavailable && honoured:
+		// A 503 that says when to come back is pushback too; without a
+		// usable Retry-After it is an ordinary failure below.
+		return deliveryResult{
+			deferral:   true,
+			retryAfter: retryAfter,
+			statusCode: status,
+			hasStatus:  true,
+			body:       &bodyStr,
+			errMessage: "unavailable (503)",
+		}
+
+	case authApplied && (status == http.StatusUnauthorized || status == http.StatusForbidden):
+		// The target refused the credentials we attached: drop them so the
+		// retry re-authenticates, and classify as AUTH for the operator.
+		h.targetAuth.Rejected(*job.SubscriptionID)
+		return deliveryResult{
+			statusCode: status,
+			hasStatus:  true,
+			body:       &bodyStr,
+			errMessage: "HTTP " + resp.Status + " (target auth rejected)",
+			errType:    dispatchjob.ErrorAuth,
+		}
+
 	default: // 3xx / 4xx / 5xx → delivery failure
 		return deliveryResult{
 			statusCode: status,
@@ -323,7 +857,48 @@ func buildPayload(job *dispatchjob.DispatchJob) []byte {
 		}
 		return []byte("{}")
 	}
+	out, err := json.Marshal(envelope(job))
+	if err != nil {
+		return []byte("{}")
+	}
+	return out
+}
 
+// deliveryHeaders describes the attempt about to be made. A job loaded
+// before its first attempt has no FirstAttemptAt yet: this is it.
+func deliveryHeaders(job *dispatchjob.DispatchJob) deliveryheaders.Delivery {
+	d := deliveryheaders.Delivery{
+		ID:               job.ID,
+		EventType:        job.Code,
+		Attempt:          job.AttemptCount + 1,
+		FirstAttemptedAt: time.Now().UTC(),
+	}
+	if job.FirstAttemptAt != nil {
+		d.FirstAttemptedAt = *job.FirstAttemptAt
+	}
+	if job.EventID != nil {
+		d.EventID = *job.EventID
+	}
+	if job.SubscriptionID != nil {
+		d.SubscriptionID = *job.SubscriptionID
+	}
+	if job.ServiceAccountID != nil {
+		d.ServiceAccountID = *job.ServiceAccountID
+	}
+	for _, m := range job.Metadata {
+		switch m.Key {
+		case "traceparent":
+			d.Traceparent = m.Value
+		case "tracestate":
+			d.Tracestate = m.Value
+		}
+	}
+	return d
+}
+
+// envelope is the CloudEvents-style view of job. It is also the input to a
+// subscription's payload transform, whatever the data-only setting.
+func envelope(job *dispatchjob.DispatchJob) map[string]any {
 	env := map[string]any{
 		"id":            job.ID,
 		"type":          job.Code,
@@ -354,11 +929,39 @@ func buildPayload(job *dispatchjob.DispatchJob) []byte {
 			env["data"] = *job.Payload
 		}
 	}
-	out, err := json.Marshal(env)
-	if err != nil {
-		return []byte("{}")
+	return env
+}
+
+// cloudEvent maps job to a CloudEvent carrying data. The id is the job id,
+// as in the native envelope, so it is stable across retries; the platform
+// event id travels as the eventid extension.
+func cloudEvent(job *dispatchjob.DispatchJob, data []byte, contentType string) *cloudevents.Event {
+	ev := &cloudevents.Event{
+		ID:              job.ID,
+		Source:          "flowcatalyst",
+		Type:            job.Code,
+		Time:            job.CreatedAt,
+		DataContentType: contentType,
+		Data:            data,
+		Extensions:      map[string]string{},
 	}
-	return out
+	if job.Source != nil && *job.Source != "" {
+		ev.Source = *job.Source
+	}
+	if job.Subject != nil {
+		ev.Subject = *job.Subject
+	}
+	for name, v := range map[string]*string{
+		"eventid":       job.EventID,
+		"correlationid": job.CorrelationID,
+		"messagegroup":  job.MessageGroup,
+		"clientid":      job.ClientID,
+	} {
+		if v != nil && *v != "" {
+			ev.Extensions[name] = *v
+		}
+	}
+	return ev
 }
 
 // parseDeferral reports a 2xx body of the form {"ack": false} (optionally
@@ -381,16 +984,57 @@ func parseDeferral(body []byte) (time.Duration, bool) {
 	return d, true
 }
 
-func retryAfterOrDefault(resp *http.Response) time.Duration {
-	if v := resp.Header.Get("Retry-After"); v != "" {
-		if secs, err := time.ParseDuration(v + "s"); err == nil && secs > 0 {
-			return secs
+// DefaultMaxRetryAfter bounds a receiver's Retry-After unless
+// SetRetryAfter says otherwise.
+const DefaultMaxRetryAfter = 5 * time.Minute
+
+// defaultRetryAfter is the 429 delay when the receiver's Retry-After is
+// missing, unusable or not honoured.
+const defaultRetryAfter = 30 * time.Second
+
+// receiverRetryAfter reads the response's Retry-After, capped at
+// maxRetryAfter. ok is false when the header is absent or malformed, or
+// the subscription doesn't honour it. When the switch can't be read the
+// header is honoured; the cap still applies.
+func (h *Handler) receiverRetryAfter(ctx context.Context, job *dispatchjob.DispatchJob, resp *http.Response) (time.Duration, bool) {
+	d, ok := common.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+	if h.retryAfter != nil && job.SubscriptionID != nil {
+		if honor, err := h.retryAfter.HonorRetryAfter(ctx, *job.SubscriptionID); err == nil && !honor {
+			return 0, false
 		}
 	}
-	return 30 * time.Second
+	return min(d, h.maxRetryAfter), true
+}
+
+// classifyAuthErr maps a failure to obtain or apply target credentials.
+// An unreachable token endpoint / STS is a connection problem; a rejected
+// client secret or denied role is an auth problem.
+func classifyAuthErr(err error) deliveryResult {
+	var t interface{ Temporary() bool }
+	if errors.As(err, &t) && t.Temporary() {
+		return deliveryResult{errMessage: "Target auth unavailable: " + err.Error(), errType: dispatchjob.ErrorConnection}
+	}
+	return deliveryResult{errMessage: "Target auth failed: " + err.Error(), errType: dispatchjob.ErrorAuth}
+}
+
+// classifyTransformErr maps a payload transform failure. A template that
+// can't render this event is a validation problem the subscription owner
+// has to fix; a store outage is retried as a connection problem.
+func classifyTransformErr(err error) deliveryResult {
+	var t interface{ Temporary() bool }
+	if errors.As(err, &t) && t.Temporary() {
+		return deliveryResult{errMessage: "Payload transform unavailable: " + err.Error(), errType: dispatchjob.ErrorConnection}
+	}
+	return deliveryResult{errMessage: "Payload transform failed: " + err.Error(), errType: dispatchjob.ErrorValidation}
 }
 
 func classifyTransportErr(err error) (string, dispatchjob.ErrorType) {
+	if egress.IsBlocked(err) {
+		return "Target blocked by egress policy: " + err.Error(), dispatchjob.ErrorValidation
+	}
 	var netErr interface{ Timeout() bool }
 	if errors.As(err, &netErr) && netErr.Timeout() {
 		return "Connection timeout", dispatchjob.ErrorTimeout
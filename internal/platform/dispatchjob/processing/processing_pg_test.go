@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -18,9 +19,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/processing"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/payloadlimit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/scheduler"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 )
 
@@ -213,3 +218,395 @@ func TestProcess_AlreadyTerminalAcksWithoutRedelivery(t *testing.T) {
 	assert.Equal(t, true, out["ack"])
 	assert.EqualValues(t, 0, atomic.LoadInt32(&hits), "terminal job is not re-delivered")
 }
+
+type overQuotaMeter struct{ recorded int }
+
+func (m *overQuotaMeter) AllowDelivery(context.Context, string) bool { return false }
+func (m *overQuotaMeter) RecordDelivery(string)                      { m.recorded++ }
+
+func TestProcess_OverDeliveryQuotaDefersWithoutAttempt(t *testing.T) {
+	pool := testpg.Pool(t)
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	meter := &overQuotaMeter{}
+	h.SetMeter(meter)
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	var hits atomic.Int32
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	seedJob(t, pool, "djproc_quota", sub.URL, 3, 0)
+	_, err := pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET client_id = 'clt_quota' WHERE id = 'djproc_quota'`)
+	require.NoError(t, err)
+	code, out := callProcess(t, ts.URL, "djproc_quota", auth.Sign("djproc_quota"))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, out["ack"])
+	assert.Zero(t, hits.Load(), "nothing is sent over quota")
+	status, attempts, scheduled := jobRow(t, pool, "djproc_quota")
+	assert.Equal(t, "PENDING", status)
+	assert.EqualValues(t, 0, attempts)
+	assert.Zero(t, attemptCount(t, pool, "djproc_quota"))
+	require.NotNil(t, scheduled)
+	assert.True(t, scheduled.After(time.Now()))
+	assert.Zero(t, meter.recorded)
+}
+
+type allTaps struct{}
+
+func (allTaps) IsTap(context.Context, string) (bool, error) { return true, nil }
+
+func TestProcess_TapDeliveryIsNotMetered(t *testing.T) {
+	pool := testpg.Pool(t)
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	meter := &overQuotaMeter{}
+	h.SetMeter(meter)
+	h.SetTaps(allTaps{})
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	var hits atomic.Int32
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	seedJob(t, pool, "djproc_tap", sub.URL, 3, 0)
+	_, err := pool.Exec(context.Background(),
+		`UPDATE msg_dispatch_jobs SET client_id = 'clt_quota', subscription_id = 'sub_tap' WHERE id = 'djproc_tap'`)
+	require.NoError(t, err)
+	code, out := callProcess(t, ts.URL, "djproc_tap", auth.Sign("djproc_tap"))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, out["ack"])
+	assert.EqualValues(t, 1, hits.Load(), "a tap is delivered over quota")
+	status, _, _ := jobRow(t, pool, "djproc_tap")
+	assert.Equal(t, "COMPLETED", status)
+	assert.Zero(t, meter.recorded, "a tap delivery isn't counted")
+}
+
+// closedWindow is a calendar whose only window starts an hour from now.
+type closedWindow struct{ opens time.Time }
+
+func (c closedWindow) DeliveryWindow(context.Context, string) (*deliverywindow.Schedule, error) {
+	start := c.opens.UTC().Format("15:04")
+	end := c.opens.UTC().Add(time.Hour).Format("15:04")
+	return &deliverywindow.Schedule{Windows: []deliverywindow.Window{{Start: start, End: end}}}, nil
+}
+
+func TestProcess_OutsideDeliveryWindowDefersToOpening(t *testing.T) {
+	pool := testpg.Pool(t)
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	opens := time.Now().Add(time.Hour).Truncate(time.Minute)
+	h.SetDeliveryWindows(closedWindow{opens: opens})
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	var hits atomic.Int32
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	seedJob(t, pool, "djproc_window", sub.URL, 3, 0)
+	_, err := pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET subscription_id = 'sub_window' WHERE id = 'djproc_window'`)
+	require.NoError(t, err)
+	code, out := callProcess(t, ts.URL, "djproc_window", auth.Sign("djproc_window"))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, out["ack"])
+	assert.Zero(t, hits.Load(), "nothing is sent outside the window")
+	status, attempts, scheduled := jobRow(t, pool, "djproc_window")
+	assert.Equal(t, "PENDING", status)
+	assert.EqualValues(t, 0, attempts)
+	require.NotNil(t, scheduled)
+	assert.WithinDuration(t, opens, *scheduled, time.Second)
+}
+
+// fakeClaims resolves every reference to a fixed body and records releases.
+type fakeClaims struct {
+	body     string
+	err      error
+	released []string
+}
+
+func (f *fakeClaims) Claim(_ context.Context, payload string) (string, *payloadlimit.Ref, error) {
+	ref := payloadlimit.ParseRef(payload)
+	if ref == nil {
+		return payload, nil, nil
+	}
+	if f.err != nil {
+		return "", nil, f.err
+	}
+	return f.body, ref, nil
+}
+
+func (f *fakeClaims) Release(_ context.Context, ref *payloadlimit.Ref) error {
+	f.released = append(f.released, ref.URI)
+	return nil
+}
+
+func TestProcess_ClaimCheckPayload(t *testing.T) {
+	pool := testpg.Pool(t)
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	claims := &fakeClaims{body: `{"big":"payload"}`}
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	h.SetClaimCheck(claims)
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	var status atomic.Int32
+	status.Store(http.StatusBadGateway)
+	var gotBody atomic.Value
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody.Store(string(b))
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(sub.Close)
+
+	ref := `{"$payloadRef":{"uri":"s3://b/dispatch-jobs/2026/03/04/djproc_cc01","sizeBytes":17,"sha256":"","contentType":"application/json","deleteAfterDelivery":true}}`
+	seedJob(t, pool, "djproc_cc01", sub.URL, 3, 0)
+	_, err := pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET payload = $2 WHERE id = $1`, "djproc_cc01", ref)
+	require.NoError(t, err)
+
+	// A retryable failure keeps the object for the next attempt.
+	callProcess(t, ts.URL, "djproc_cc01", auth.Sign("djproc_cc01"))
+	assert.Empty(t, claims.released)
+
+	status.Store(http.StatusOK)
+	_, err = pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET status = 'QUEUED' WHERE id = $1`, "djproc_cc01")
+	require.NoError(t, err)
+	callProcess(t, ts.URL, "djproc_cc01", auth.Sign("djproc_cc01"))
+	st, _, _ := jobRow(t, pool, "djproc_cc01")
+	assert.Equal(t, "COMPLETED", st)
+	assert.Equal(t, []string{"s3://b/dispatch-jobs/2026/03/04/djproc_cc01"}, claims.released)
+
+	var env map[string]any
+	require.NoError(t, json.Unmarshal([]byte(gotBody.Load().(string)), &env))
+	assert.Equal(t, map[string]any{"big": "payload"}, env["data"], "the subscriber gets the claimed body, not the reference")
+}
+
+func TestProcess_ClaimCheckUnavailableRetries(t *testing.T) {
+	pool := testpg.Pool(t)
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	h.SetClaimCheck(&fakeClaims{err: errors.New("store down")})
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	var hits atomic.Int32
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { hits.Add(1) }))
+	t.Cleanup(sub.Close)
+
+	seedJob(t, pool, "djproc_cc02", sub.URL, 3, 0)
+	_, err := pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET payload = $2 WHERE id = $1`, "djproc_cc02",
+		`{"$payloadRef":{"uri":"s3://b/x","sizeBytes":1,"sha256":"","contentType":"application/json"}}`)
+	require.NoError(t, err)
+
+	callProcess(t, ts.URL, "djproc_cc02", auth.Sign("djproc_cc02"))
+	st, _, scheduled := jobRow(t, pool, "djproc_cc02")
+	assert.Equal(t, "PENDING", st)
+	assert.NotNil(t, scheduled)
+	assert.Zero(t, hits.Load(), "nothing is sent without the payload")
+	assert.Equal(t, 1, attemptCount(t, pool, "djproc_cc02"))
+}
+
+func TestProcess_TerminalOutcomeArmsStatusCallback(t *testing.T) {
+	pool := testpg.Pool(t)
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	callbacks := callback.NewRepository(pool)
+	h.SetCallbacks(callbacks)
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Dispatch-Job-Id") == "djproc_cb02" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	ctx := context.Background()
+	seedJob(t, pool, "djproc_cb01", sub.URL, 3, 0)
+	seedJob(t, pool, "djproc_cb02", sub.URL, 2, 0)
+	require.NoError(t, callbacks.Register(ctx, []callback.Registration{
+		{JobID: "djproc_cb01", URL: "https://producer.example.com/cb"},
+		{JobID: "djproc_cb02", URL: "https://producer.example.com/cb"},
+	}))
+
+	callProcess(t, ts.URL, "djproc_cb01", auth.Sign("djproc_cb01"))
+	callProcess(t, ts.URL, "djproc_cb02", auth.Sign("djproc_cb02"))
+
+	ok, err := callbacks.FindByJobID(ctx, "djproc_cb01")
+	require.NoError(t, err)
+	assert.Equal(t, callback.StatePending, ok.State)
+	assert.Equal(t, common.DispatchCompleted, *ok.JobStatus)
+
+	retrying, err := callbacks.FindByJobID(ctx, "djproc_cb02")
+	require.NoError(t, err)
+	assert.Equal(t, callback.StateWaiting, retrying.State, "a retry is not terminal")
+
+	_, err = pool.Exec(ctx, `UPDATE msg_dispatch_jobs SET status = 'QUEUED' WHERE id = 'djproc_cb02'`)
+	require.NoError(t, err)
+	callProcess(t, ts.URL, "djproc_cb02", auth.Sign("djproc_cb02"))
+	failed, err := callbacks.FindByJobID(ctx, "djproc_cb02")
+	require.NoError(t, err)
+	assert.Equal(t, callback.StatePending, failed.State)
+	assert.Equal(t, common.DispatchFailed, *failed.JobStatus)
+}
+
+type fixedAckTimeout time.Duration
+
+func (f fixedAckTimeout) AckTimeout(context.Context, string) (time.Duration, error) {
+	return time.Duration(f), nil
+}
+
+// ackHarness wires a handler whose subscriptions all require an ack.
+func ackHarness(t *testing.T, pool *pgxpool.Pool) (string, *scheduler.DispatchAuthService) {
+	t.Helper()
+	auth := scheduler.NewDispatchAuthService(testSecret)
+	h := processing.New(dispatchjob.NewRepository(pool), auth)
+	h.SetAcks(fixedAckTimeout(time.Minute), auth)
+	r := chi.NewRouter()
+	h.Mount(r)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts.URL, auth
+}
+
+func seedAckJob(t *testing.T, pool *pgxpool.Pool, id, targetURL string, maxRetries int) {
+	t.Helper()
+	seedJob(t, pool, id, targetURL, maxRetries, 0)
+	_, err := pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET subscription_id = 'sub_ack' WHERE id = $1`, id)
+	require.NoError(t, err)
+}
+
+func callAck(t *testing.T, baseURL, token string) (int, map[string]any) {
+	t.Helper()
+	resp, err := http.Post(baseURL+"/api/dispatch/deliveries/"+token+"/ack", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestProcess_AckModeAwaitsAcknowledgement(t *testing.T) {
+	pool := testpg.Pool(t)
+	base, auth := ackHarness(t, pool)
+
+	var token atomic.Value
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token.Store(r.Header.Get("X-Delivery-Token"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	seedAckJob(t, pool, "djproc_ack01", sub.URL, 3)
+	callProcess(t, base, "djproc_ack01", auth.Sign("djproc_ack01"))
+
+	assert.Equal(t, auth.DeliveryToken("djproc_ack01"), token.Load())
+	status, attempts, scheduled := jobRow(t, pool, "djproc_ack01")
+	assert.Equal(t, "PENDING", status, "a 2xx alone doesn't complete an ack-mode delivery")
+	assert.Equal(t, int32(1), attempts)
+	require.NotNil(t, scheduled)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *scheduled, 10*time.Second)
+
+	code, out := callAck(t, base, token.Load().(string))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{"acknowledged": true, "duplicate": false}, out)
+	status, _, _ = jobRow(t, pool, "djproc_ack01")
+	assert.Equal(t, "COMPLETED", status)
+
+	code, out = callAck(t, base, token.Load().(string))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, out["duplicate"], "a repeated ack changes nothing")
+
+	// A redelivery already in flight when the ack landed is dropped.
+	callProcess(t, base, "djproc_ack01", auth.Sign("djproc_ack01"))
+	assert.Equal(t, 1, attemptCount(t, pool, "djproc_ack01"))
+}
+
+func TestProcess_AckBeforeResponseWins(t *testing.T) {
+	pool := testpg.Pool(t)
+	base, auth := ackHarness(t, pool)
+
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := callAck(t, base, r.Header.Get("X-Delivery-Token"))
+		assert.Equal(t, http.StatusOK, code)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	seedAckJob(t, pool, "djproc_ack02", sub.URL, 3)
+	callProcess(t, base, "djproc_ack02", auth.Sign("djproc_ack02"))
+
+	status, _, _ := jobRow(t, pool, "djproc_ack02")
+	assert.Equal(t, "COMPLETED", status, "the 2xx arriving after the ack must not re-park the job")
+}
+
+func TestProcess_UnacknowledgedFailsOnceRetriesRunOut(t *testing.T) {
+	pool := testpg.Pool(t)
+	base, auth := ackHarness(t, pool)
+
+	var hits atomic.Int32
+	sub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(sub.Close)
+
+	seedAckJob(t, pool, "djproc_ack03", sub.URL, 2)
+	for range 3 {
+		_, err := pool.Exec(context.Background(), `UPDATE msg_dispatch_jobs SET status = 'QUEUED' WHERE id = 'djproc_ack03'`)
+		require.NoError(t, err)
+		callProcess(t, base, "djproc_ack03", auth.Sign("djproc_ack03"))
+	}
+
+	assert.Equal(t, int32(2), hits.Load(), "delivered once per retry, then given up on")
+	status, _, _ := jobRow(t, pool, "djproc_ack03")
+	assert.Equal(t, "FAILED", status)
+	code, _ := callAck(t, base, auth.DeliveryToken("djproc_ack03"))
+	assert.Equal(t, http.StatusConflict, code, "too late to acknowledge")
+}
+
+func TestAck_RejectsForgedAndUnexpectedTokens(t *testing.T) {
+	pool := testpg.Pool(t)
+	base, auth := ackHarness(t, pool)
+
+	code, _ := callAck(t, base, "djproc_ack04.deadbeef")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = callAck(t, base, auth.DeliveryToken("djproc_none"))
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// A job that was never delivered in ack mode isn't waiting for one.
+	seedJob(t, pool, "djproc_ack04", "http://127.0.0.1:1", 3, 0)
+	code, out := callAck(t, base, auth.DeliveryToken("djproc_ack04"))
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "DELIVERY_NOT_AWAITING_ACK", out["error"])
+}
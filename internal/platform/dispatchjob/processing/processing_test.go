@@ -1,14 +1,26 @@
 package processing
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliveryheaders"
 )
 
 func strp(s string) *string { return &s }
@@ -78,9 +90,377 @@ func TestParseDeferral(t *testing.T) {
 	assert.False(t, ok)
 }
 
-func TestBackoffFor(t *testing.T) {
-	assert.Equal(t, 5*time.Second, backoffFor(1))
-	assert.Equal(t, 15*time.Second, backoffFor(2))
-	assert.Equal(t, 120*time.Second, backoffFor(99), "clamps to the last backoff")
-	assert.Equal(t, 5*time.Second, backoffFor(0), "guards a non-positive attempt number")
+type fakeTargetAuth struct {
+	err      error
+	rejected []string
+}
+
+func (f *fakeTargetAuth) Authorize(_ context.Context, _ string, req *http.Request, _ []byte) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	req.Header.Set("Authorization", "Bearer tok")
+	return true, nil
+}
+
+func (f *fakeTargetAuth) Rejected(id string) { f.rejected = append(f.rejected, id) }
+
+type temporaryErr struct{ error }
+
+func (temporaryErr) Temporary() bool { return true }
+
+func TestDeliver_TargetAuth(t *testing.T) {
+	var gotAuth string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	job := &dispatchjob.DispatchJob{ID: "dsj_1", Code: "x", TargetURL: srv.URL, SubscriptionID: strp("sub_1")}
+	fa := &fakeTargetAuth{}
+	h := New(nil, nil)
+	h.SetTargetAuth(fa)
+
+	res := h.deliver(context.Background(), job, "")
+	assert.True(t, res.success)
+	assert.Equal(t, "Bearer tok", gotAuth)
+
+	status = http.StatusUnauthorized
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorAuth, res.errType)
+	assert.Equal(t, []string{"sub_1"}, fa.rejected, "a 401 drops the cached credentials")
+
+	fa.err = errors.New("invalid_client")
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorAuth, res.errType)
+	assert.False(t, res.hasStatus, "the target is never called without credentials")
+
+	fa.err = temporaryErr{errors.New("token endpoint unreachable")}
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorConnection, res.errType)
+}
+
+type fakeTransformer struct {
+	err     error
+	applied bool
+	gotData any
+}
+
+func (f *fakeTransformer) Transform(_ context.Context, _ string, env map[string]any) ([]byte, string, bool, error) {
+	f.gotData = env["data"]
+	if f.err != nil || !f.applied {
+		return nil, "", false, f.err
+	}
+	return []byte("<order/>"), "application/xml", true, nil
+}
+
+func TestDeliver_Transform(t *testing.T) {
+	var gotBody, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		gotBody, gotType = string(raw), r.Header.Get("Content-Type")
+	}))
+	defer srv.Close()
+
+	job := &dispatchjob.DispatchJob{
+		ID: "dsj_1", Code: "x", TargetURL: srv.URL, SubscriptionID: strp("sub_1"),
+		DataOnly: true, Payload: strp(`{"id":"o-1"}`),
+	}
+	ft := &fakeTransformer{applied: true}
+	h := New(nil, nil)
+	h.SetTransformer(ft)
+
+	res := h.deliver(context.Background(), job, "")
+	assert.True(t, res.success)
+	assert.Equal(t, "<order/>", gotBody)
+	assert.Equal(t, "application/xml", gotType)
+	assert.JSONEq(t, `{"id":"o-1"}`, string(ft.gotData.(json.RawMessage)), "data-only jobs still transform the full envelope")
+
+	ft.applied = false
+	res = h.deliver(context.Background(), job, "")
+	assert.True(t, res.success)
+	assert.Equal(t, `{"id":"o-1"}`, gotBody, "no transform sends the default body")
+	assert.Equal(t, "application/json", gotType)
+
+	gotBody = ""
+	ft.err = errors.New("render transform: invalid JSON")
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorValidation, res.errType)
+	assert.Empty(t, gotBody, "the target is never called with an unrenderable body")
+
+	ft.err = temporaryErr{errors.New("load transform: connection refused")}
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorConnection, res.errType)
+}
+
+type fakeFormats struct {
+	format subscription.DeliveryFormat
+	err    error
+}
+
+func (f *fakeFormats) DeliveryFormat(context.Context, string) (subscription.DeliveryFormat, error) {
+	return f.format, f.err
+}
+
+func TestDeliver_CloudEvents(t *testing.T) {
+	var (
+		gotBody string
+		gotHdr  http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		gotBody, gotHdr = string(raw), r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	job := &dispatchjob.DispatchJob{
+		ID: "dsj_1", Code: "orders:fulfilment:order:created", TargetURL: srv.URL, SubscriptionID: strp("sub_1"),
+		Source: strp("/orders"), Subject: strp("o-1"), EventID: strp("evt_1"), CorrelationID: strp("c-1"),
+		Payload: strp(`{"total":12}`), DataOnly: false,
+		CreatedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+	ff := &fakeFormats{format: subscription.DeliveryCloudEventsBinary}
+	h := New(nil, nil)
+	h.SetDeliveryFormats(ff)
+
+	res := h.deliver(context.Background(), job, "")
+	assert.True(t, res.success)
+	assert.Equal(t, `{"total":12}`, gotBody, "binary mode sends the bare data, whatever the data-only setting")
+	assert.Equal(t, "application/json", gotHdr.Get("Content-Type"))
+	assert.Equal(t, "1.0", gotHdr.Get("ce-specversion"))
+	assert.Equal(t, "dsj_1", gotHdr.Get("ce-id"))
+	assert.Equal(t, "/orders", gotHdr.Get("ce-source"))
+	assert.Equal(t, "orders:fulfilment:order:created", gotHdr.Get("ce-type"))
+	assert.Equal(t, "2026-03-01T09:00:00Z", gotHdr.Get("ce-time"))
+	assert.Equal(t, "evt_1", gotHdr.Get("ce-eventid"))
+	assert.Equal(t, "c-1", gotHdr.Get("ce-correlationid"))
+	assert.Equal(t, "dsj_1", gotHdr.Get("X-Dispatch-Job-Id"))
+
+	ff.format = subscription.DeliveryCloudEventsStructured
+	res = h.deliver(context.Background(), job, "")
+	assert.True(t, res.success)
+	assert.Equal(t, "application/cloudevents+json", gotHdr.Get("Content-Type"))
+	assert.Empty(t, gotHdr.Get("ce-id"))
+	assert.JSONEq(t, `{
+		"specversion":"1.0","id":"dsj_1","source":"/orders","type":"orders:fulfilment:order:created",
+		"subject":"o-1","time":"2026-03-01T09:00:00Z","datacontenttype":"application/json",
+		"eventid":"evt_1","correlationid":"c-1","data":{"total":12}
+	}`, gotBody)
+
+	h.SetTransformer(&fakeTransformer{applied: true})
+	res = h.deliver(context.Background(), job, "")
+	assert.True(t, res.success)
+	assert.Contains(t, gotBody, `"datacontenttype":"application/xml"`)
+	assert.Contains(t, gotBody, `"data":"<order/>"`, "a transform's output becomes the event data")
+
+	gotBody = ""
+	ff.err = errors.New("connection refused")
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorConnection, res.errType)
+	assert.Empty(t, gotBody)
+}
+
+type fakeHeaderStore struct {
+	headers []subscription.DeliveryHeader
+	err     error
+}
+
+func (f *fakeHeaderStore) DeliveryHeaders(context.Context, string) ([]subscription.DeliveryHeader, error) {
+	return f.headers, f.err
+}
+
+type fakeAccounts struct{ secret string }
+
+func (f fakeAccounts) FindByID(_ context.Context, id string) (*serviceaccount.ServiceAccount, error) {
+	return &serviceaccount.ServiceAccount{ID: id, WebhookCredentials: serviceaccount.WebhookCredentials{SigningSecret: &f.secret}}, nil
+}
+
+func TestDeliver_DeliveryHeaders(t *testing.T) {
+	var (
+		gotBody []byte
+		gotHdr  http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHdr = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	job := &dispatchjob.DispatchJob{
+		ID: "dsj_1", Code: "orders:fulfilment:order:created", TargetURL: srv.URL,
+		SubscriptionID: strp("sub_1"), ServiceAccountID: strp("sa_1"), EventID: strp("evt_1"),
+		AttemptCount: 2, FirstAttemptAt: &first, Payload: strp(`{"total":12}`),
+		Metadata: []dispatchjob.Metadata{
+			{Key: "traceparent", Value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			{Key: "tracestate", Value: "fc=1"},
+		},
+	}
+	store := &fakeHeaderStore{}
+	h := New(nil, nil)
+	h.SetDeliveryHeaders(deliveryheaders.New(store, fakeAccounts{secret: "s3cret"}))
+
+	require.True(t, h.deliver(context.Background(), job, "").success)
+	assert.Equal(t, "dsj_1", gotHdr.Get("X-FlowCatalyst-Delivery-Id"))
+	assert.Equal(t, "evt_1", gotHdr.Get("X-FlowCatalyst-Event-Id"))
+	assert.Equal(t, "orders:fulfilment:order:created", gotHdr.Get("X-FlowCatalyst-Event-Type"))
+	assert.Equal(t, "3", gotHdr.Get("X-FlowCatalyst-Delivery-Attempt"))
+	assert.Equal(t, "2026-03-01T09:00:00Z", gotHdr.Get("X-FlowCatalyst-First-Attempted-At"))
+	assert.Equal(t, "sub_1", gotHdr.Get("X-FlowCatalyst-Subscription-Id"))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", gotHdr.Get("traceparent"))
+	assert.Equal(t, "fc=1", gotHdr.Get("tracestate"))
+	ts := gotHdr.Get("X-FLOWCATALYST-TIMESTAMP")
+	require.NotEmpty(t, ts)
+	assert.Equal(t, callback.Sign("s3cret", ts, gotBody), gotHdr.Get("X-FLOWCATALYST-SIGNATURE"), "signed over the body as sent")
+	assert.Equal(t, "dsj_1", gotHdr.Get("X-Dispatch-Job-Id"), "the legacy headers stay")
+
+	// A change to the subscription's set reaches the next delivery.
+	store.headers = []subscription.DeliveryHeader{subscription.HeaderDeliveryID}
+	h.SetDeliveryHeaders(deliveryheaders.New(store, fakeAccounts{secret: "s3cret"}))
+	require.True(t, h.deliver(context.Background(), job, "").success)
+	assert.Equal(t, "dsj_1", gotHdr.Get("X-FlowCatalyst-Delivery-Id"))
+	assert.Empty(t, gotHdr.Get("X-FlowCatalyst-Event-Id"))
+	assert.Empty(t, gotHdr.Get("X-FLOWCATALYST-SIGNATURE"))
+	assert.Empty(t, gotHdr.Get("traceparent"))
+
+	gotHdr = nil
+	store.err = errors.New("db down")
+	h.SetDeliveryHeaders(deliveryheaders.New(store, nil))
+	res := h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorConnection, res.errType)
+	assert.Nil(t, gotHdr)
+}
+
+type fakeOverrides struct {
+	settings egress.Settings
+	err      error
+}
+
+func (f *fakeOverrides) Egress(context.Context, string) (egress.Settings, error) {
+	return f.settings, f.err
+}
+
+func TestDeliver_Egress(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { calls++ }))
+	defer srv.Close()
+
+	pol, err := egress.New(egress.Config{})
+	require.NoError(t, err)
+	fo := &fakeOverrides{}
+	job := &dispatchjob.DispatchJob{ID: "dsj_1", Code: "x", TargetURL: srv.URL, SubscriptionID: strp("sub_1")}
+	h := New(nil, nil)
+	h.SetEgress(pol, fo)
+
+	res := h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorValidation, res.errType, "a loopback target is refused at dial time")
+	assert.Contains(t, res.errMessage, "egress policy")
+	assert.Zero(t, calls)
+
+	fo.settings.AllowPrivate = true
+	res = h.deliver(context.Background(), job, "")
+	assert.True(t, res.success, "the subscription's override opens private ranges")
+	assert.Equal(t, 1, calls)
+
+	fo.err = errors.New("db down")
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, dispatchjob.ErrorConnection, res.errType)
+}
+
+func TestDeliver_DeliveryToken(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Values("X-Delivery-Token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	job := &dispatchjob.DispatchJob{ID: "dsj_1", Code: "x", TargetURL: srv.URL}
+	h := New(nil, nil)
+
+	assert.True(t, h.deliver(context.Background(), job, "dsj_1.abc").success)
+	assert.Equal(t, []string{"dsj_1.abc"}, got)
+
+	assert.True(t, h.deliver(context.Background(), job, "").success)
+	assert.Empty(t, got, "no token without acknowledgement")
+}
+
+type fakeTokens struct{}
+
+func (fakeTokens) DeliveryToken(jobID string) string { return jobID + ".ok" }
+
+func (fakeTokens) ParseDeliveryToken(token string) (string, bool) {
+	id, ok := strings.CutSuffix(token, ".ok")
+	return id, ok
+}
+
+func TestAck_MountedOnlyWithAcks(t *testing.T) {
+	r := chi.NewRouter()
+	New(nil, nil).Mount(r)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/dispatch/deliveries/dsj_1.ok/ack", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	h := New(nil, nil)
+	h.SetAcks(nil, fakeTokens{})
+	r = chi.NewRouter()
+	h.Mount(r)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/dispatch/deliveries/dsj_1.forged/ack", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "DELIVERY_NOT_FOUND", "a forged token never reaches the store")
+}
+
+type fakeRetryAfter struct{ honor bool }
+
+func (f *fakeRetryAfter) HonorRetryAfter(context.Context, string) (bool, error) { return f.honor, nil }
+
+func TestDeliver_RetryAfter(t *testing.T) {
+	status, header := http.StatusTooManyRequests, "45"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if header != "" {
+			w.Header().Set("Retry-After", header)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	job := &dispatchjob.DispatchJob{ID: "dsj_1", Code: "x", TargetURL: srv.URL, SubscriptionID: strp("sub_1")}
+	policy := &fakeRetryAfter{honor: true}
+	h := New(nil, nil)
+	h.SetRetryAfter(policy, time.Minute)
+
+	res := h.deliver(context.Background(), job, "")
+	assert.True(t, res.deferral, "429 is back-pressure")
+	assert.Equal(t, 45*time.Second, res.retryAfter)
+
+	header = "86400"
+	res = h.deliver(context.Background(), job, "")
+	assert.Equal(t, time.Minute, res.retryAfter, "capped")
+
+	header = time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat)
+	res = h.deliver(context.Background(), job, "")
+	assert.InDelta(t, 20*time.Second, res.retryAfter, float64(2*time.Second), "HTTP-date form")
+
+	policy.honor = false
+	header = "45"
+	res = h.deliver(context.Background(), job, "")
+	assert.True(t, res.deferral)
+	assert.Equal(t, defaultRetryAfter, res.retryAfter, "opted out: default delay")
+
+	status = http.StatusServiceUnavailable
+	res = h.deliver(context.Background(), job, "")
+	assert.False(t, res.deferral, "opted-out 503 is a failure")
+
+	policy.honor = true
+	res = h.deliver(context.Background(), job, "")
+	assert.True(t, res.deferral, "503 with an honoured Retry-After is pushback")
+	assert.Equal(t, 45*time.Second, res.retryAfter)
+
+	header = ""
+	res = h.deliver(context.Background(), job, "")
+	assert.False(t, res.deferral, "503 without Retry-After is a failure")
+	assert.Equal(t, http.StatusServiceUnavailable, res.statusCode)
 }
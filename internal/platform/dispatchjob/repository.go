@@ -3,6 +3,7 @@ package dispatchjob
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
@@ -23,10 +25,11 @@ import (
 // 015), so the user-facing list / by-event / filter-options reads go to the
 // projection — mirroring the events repo and Rust's DispatchJobReadResponse.
 // The detail view (FindByID) and the debug raw view (FindRecentRaw) stay on
-// the write table because they need the un-projected payload/metadata.
+// the write table because they need the un-projected payload/metadata, as
+// does FindScheduled because the projection lags scheduler reschedules.
 //
 // FindWithFilters + DistinctValues + FindByEventID + FindRecentRaw +
-// InsertBatch stay hand-rolled (dynamic SQL / pgx.Batch); everything else
+// FindScheduled + InsertBatch stay hand-rolled (dynamic SQL / pgx.Batch); everything else
 // goes through *dbq.Queries.
 type Repository struct {
 	pool *pgxpool.Pool // retained for FindWithFilters + DistinctValues + InsertBatch
@@ -67,11 +70,16 @@ type FilterParams struct {
 
 	// AccessibleClientIDs: a non-nil pointer scopes results to
 	// platform-scoped jobs (client_id IS NULL) plus jobs whose client_id is
-	// in the set; nil means no access scoping (anchor). Mirrors
+	// in the set; nil means the calling request's scope
+	// (reqctx.ClientScope — none for anchors and non-request callers). Mirrors
 	// scheduledjob/event FilterParams — enforced in SQL so the caller's
 	// clientId/clientIds filters can only narrow within the principal's own
 	// tenants, never reach across them.
 	AccessibleClientIDs *[]string
+
+	// Environment narrows to SANDBOX or PRODUCTION jobs; nil for both.
+	// Set from a pinned principal's environment by the list handlers.
+	Environment *string
 }
 
 // FindByID loads a single job (write table).
@@ -104,23 +112,95 @@ func (r *Repository) FindByEventID(ctx context.Context, eventID string) ([]Dispa
 	return out, nil
 }
 
-// readSelect is the slim projection column set shared by the filtered list
+// readColumns is the slim projection column set shared by the filtered list
 // and by-event reads. msg_dispatch_jobs_read omits payload / metadata /
 // schema_id / payload_content_type / data_only — the DispatchJobRead wire
 // shape doesn't surface them. Columns map to readRow by db tag (order cosmetic).
-const readSelect = `SELECT id, external_id, source, kind, code, subject,
+// The write table carries the same columns, so FindScheduled reuses them.
+const readColumns = `SELECT id, external_id, source, kind, code, subject,
 	event_id, correlation_id, target_url, protocol, service_account_id,
 	client_id, subscription_id, mode, dispatch_pool_id, message_group,
 	sequence, timeout_seconds, status, max_retries, retry_strategy,
 	scheduled_for, expires_at, attempt_count, last_attempt_at, completed_at,
-	duration_millis, last_error, idempotency_key, created_at, updated_at
-	FROM msg_dispatch_jobs_read`
+	duration_millis, last_error, idempotency_key, created_at, updated_at,
+	environment`
+
+const readSelect = readColumns + ` FROM msg_dispatch_jobs_read`
+
+// ScheduledParams is the query DTO for GET /api/dispatch-jobs/scheduled.
+// AccessibleClientIDs and Environment have the same meaning as on
+// FilterParams.
+type ScheduledParams struct {
+	ClientID            *string
+	SubscriptionID      *string
+	Until               *time.Time
+	Limit               int
+	AccessibleClientIDs *[]string
+	Environment         *string
+}
+
+// FindScheduled lists PENDING jobs whose scheduled_for is still in the
+// future — delayed dispatches and retries waiting out their backoff —
+// soonest first. Reads the write table (the projection can lag the
+// scheduler's reschedules), backed by idx_dispatch_jobs_scheduled_pending.
+// Hand-rolled dynamic query.
+func (r *Repository) FindScheduled(ctx context.Context, p ScheduledParams) ([]DispatchJob, error) {
+	p.AccessibleClientIDs = reqctx.ClientScope(ctx, p.AccessibleClientIDs)
+	var f repocommon.Filter
+	f.Eq("status", string(common.DispatchPending))
+	f.Clause("scheduled_for > $%d", time.Now().UTC())
+	f.EqPtr("client_id", p.ClientID)
+	if p.AccessibleClientIDs != nil {
+		f.Clause("(client_id IS NULL OR client_id = ANY($%d))", *p.AccessibleClientIDs)
+	}
+	f.EqPtr("subscription_id", p.SubscriptionID)
+	f.EqPtr("environment", p.Environment)
+	if p.Until != nil {
+		f.Clause("scheduled_for <= $%d", *p.Until)
+	}
+	limit := p.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	q := readColumns + ` FROM msg_dispatch_jobs` + f.Where() +
+		fmt.Sprintf(" ORDER BY scheduled_for ASC LIMIT $%d", f.Arg(limit))
+	rows, err := r.pool.Query(ctx, q, f.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	collected, err := pgx.CollectRows(rows, pgx.RowToStructByName[readRow])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]DispatchJob, 0, len(collected))
+	for _, rr := range collected {
+		out = append(out, *readRowToJob(rr))
+	}
+	return out, nil
+}
 
 // FindWithFilters returns dispatch jobs matching non-nil filters, ordered
 // most-recent first. Powers the frontend's job list view (GET
 // /api/dispatch-jobs). Reads the msg_dispatch_jobs_read projection — the write
 // table carries no query indexes (migration 015). Hand-rolled dynamic query.
 func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]DispatchJob, error) {
+	p.AccessibleClientIDs = reqctx.ClientScope(ctx, p.AccessibleClientIDs)
+	f := p.filter()
+	q := readSelect + f.Where() + " ORDER BY created_at DESC"
+	limit := p.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	q += fmt.Sprintf(" LIMIT $%d", f.Arg(limit))
+	if p.Offset > 0 {
+		q += fmt.Sprintf(" OFFSET $%d", f.Arg(p.Offset))
+	}
+	return r.queryRead(ctx, q, f.Args()...)
+}
+
+// filter renders p's conditions against the msg_dispatch_jobs_read
+// projection. Shared by FindWithFilters and Search.
+func (p FilterParams) filter() *repocommon.Filter {
 	var f repocommon.Filter
 	f.EqPtr("status", p.Status)
 	f.Any("status", p.Statuses)
@@ -137,6 +217,7 @@ func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Dis
 	f.EqPtr("code", p.Code)
 	f.Any("code", p.Codes)
 	f.EqPtr("source", p.Source)
+	f.EqPtr("environment", p.Environment)
 	// Facets filter the projection's real columns (split_part of code), backed
 	// by their own indexes — replacing the old leading-wildcard code LIKEs.
 	f.Any("application", p.Applications)
@@ -148,16 +229,12 @@ func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Dis
 	if p.Until != nil {
 		f.Clause("created_at <= $%d", *p.Until)
 	}
-	q := readSelect + f.Where() + " ORDER BY created_at DESC"
-	limit := p.Limit
-	if limit <= 0 || limit > 1000 {
-		limit = 100
-	}
-	q += fmt.Sprintf(" LIMIT $%d", f.Arg(limit))
-	if p.Offset > 0 {
-		q += fmt.Sprintf(" OFFSET $%d", f.Arg(p.Offset))
-	}
-	rows, err := r.pool.Query(ctx, q, f.Args()...)
+	return &f
+}
+
+// queryRead runs a readSelect query and maps the projection rows.
+func (r *Repository) queryRead(ctx context.Context, q string, args ...any) ([]DispatchJob, error) {
+	rows, err := r.pool.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -176,8 +253,9 @@ func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Dis
 // write-side msg_dispatch_jobs table, including payload + metadata. Powers the
 // debug raw-job view (GET /bff/debug/dispatch-jobs), which needs the
 // un-projected envelope the read projection drops. Mirrors the events repo's
-// FindRecentRaw. Ordered most-recent first.
-func (r *Repository) FindRecentRaw(ctx context.Context, limit int) ([]DispatchJob, error) {
+// FindRecentRaw. Ordered most-recent first. A non-nil env keeps only that
+// environment's jobs.
+func (r *Repository) FindRecentRaw(ctx context.Context, limit int, env *string) ([]DispatchJob, error) {
 	if limit <= 0 || limit > 1000 {
 		limit = 100
 	}
@@ -189,10 +267,12 @@ func (r *Repository) FindRecentRaw(ctx context.Context, limit int) ([]DispatchJo
 		        timeout_seconds, schema_id, status, max_retries, retry_strategy,
 		        scheduled_for, expires_at, attempt_count, last_attempt_at,
 		        completed_at, duration_millis, last_error, idempotency_key,
-		        created_at, updated_at
+		        created_at, updated_at, priority, ack_deadline, acked_at,
+		        first_attempt_at, environment
 		   FROM msg_dispatch_jobs
+		  WHERE $2::text IS NULL OR environment = $2
 		  ORDER BY created_at DESC
-		  LIMIT $1`, limit)
+		  LIMIT $1`, limit, env)
 	if err != nil {
 		return nil, err
 	}
@@ -292,6 +372,8 @@ func (r *Repository) Insert(ctx context.Context, j *DispatchJob) error {
 		IdempotencyKey:     j.IdempotencyKey,
 		CreatedAt:          j.CreatedAt,
 		UpdatedAt:          j.UpdatedAt,
+		Priority:           string(j.Priority),
+		Environment:        string(common.ParseEnvironment(string(j.Environment))),
 	})
 }
 
@@ -317,8 +399,9 @@ func (r *Repository) InsertBatch(ctx context.Context, jobs []DispatchJob) error
 			      service_account_id, client_id, subscription_id, mode, dispatch_pool_id,
 			      message_group, sequence, timeout_seconds, schema_id, status, max_retries,
 			      retry_strategy, scheduled_for, expires_at, attempt_count, last_attempt_at,
-			      completed_at, duration_millis, last_error, idempotency_key, created_at, updated_at)
-			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9::jsonb,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36)
+			      completed_at, duration_millis, last_error, idempotency_key, created_at, updated_at,
+			      priority, environment)
+			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9::jsonb,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38)
 			 ON CONFLICT (id, created_at) DO NOTHING`,
 			j.ID, j.ExternalID, j.Source, string(j.Kind), j.Code, j.Subject, j.EventID,
 			j.CorrelationID, metaJSON, j.TargetURL, string(j.Protocol), j.Payload,
@@ -327,7 +410,8 @@ func (r *Repository) InsertBatch(ctx context.Context, jobs []DispatchJob) error
 			j.Sequence, j.TimeoutSeconds, j.SchemaID, string(j.Status), j.MaxRetries,
 			string(j.RetryStrategy), j.ScheduledFor, j.ExpiresAt, j.AttemptCount,
 			j.LastAttemptAt, j.CompletedAt, j.DurationMillis, j.LastError,
-			j.IdempotencyKey, j.CreatedAt, now)
+			j.IdempotencyKey, j.CreatedAt, now, string(j.Priority),
+			string(common.ParseEnvironment(string(j.Environment))))
 	}
 	br := r.pool.SendBatch(ctx, batch)
 	defer br.Close()
@@ -384,38 +468,123 @@ func (r *Repository) Reschedule(ctx context.Context, id string, scheduledFor tim
 	_, err := r.pool.Exec(ctx,
 		`UPDATE msg_dispatch_jobs
 		    SET status = 'PENDING', scheduled_for = $2, updated_at = NOW()
-		  WHERE id = $1`, id, scheduledFor.UTC())
+		  WHERE id = $1 AND acked_at IS NULL`, id, scheduledFor.UTC())
+	return err
+}
+
+// ExpectAck stamps ack_deadline on a job about to be delivered to a
+// subscription that requires receiver acknowledgement, making its delivery
+// token acknowledgeable from the moment the request leaves — a receiver
+// may ack before it answers.
+func (r *Repository) ExpectAck(ctx context.Context, id string, deadline time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_jobs
+		    SET ack_deadline = $2, updated_at = NOW()
+		  WHERE id = $1 AND acked_at IS NULL`, id, deadline.UTC())
+	return err
+}
+
+// AwaitAck parks a job whose delivery the receiver accepted (2xx) but
+// hasn't acknowledged yet: PENDING until deadline, spending one attempt,
+// so the poller delivers it again if the ack never comes. A job
+// acknowledged in the meantime is left COMPLETED.
+func (r *Repository) AwaitAck(ctx context.Context, id string, deadline time.Time, durationMillis int64) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_jobs
+		    SET status = 'PENDING',
+		        attempt_count = attempt_count + 1,
+		        scheduled_for = $2,
+		        ack_deadline = $2,
+		        duration_millis = $3,
+		        last_error = NULL,
+		        updated_at = NOW()
+		  WHERE id = $1 AND acked_at IS NULL`, id, deadline.UTC(), durationMillis)
 	return err
 }
 
+// AckResult is the outcome of Acknowledge.
+type AckResult int
+
+const (
+	// AckAccepted: the job was awaiting its ack and is now COMPLETED.
+	AckAccepted AckResult = iota
+	// AckDuplicate: the job had already been acknowledged; nothing changed.
+	AckDuplicate
+	// AckNotAwaiting: the job exists but isn't waiting for an ack — its
+	// subscription doesn't require one, or it already ended otherwise.
+	AckNotAwaiting
+	// AckNotFound: no such job.
+	AckNotFound
+)
+
+// Acknowledge records the receiver's acknowledgement of a job's delivery
+// token and completes the job. Only the first ack of a job awaiting one
+// changes anything; the conditional update makes concurrent acks, and an
+// ack racing a redelivery, settle on exactly one transition.
+func (r *Repository) Acknowledge(ctx context.Context, id string) (AckResult, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE msg_dispatch_jobs
+		    SET status = 'COMPLETED',
+		        acked_at = NOW(),
+		        completed_at = NOW(),
+		        last_error = NULL,
+		        updated_at = NOW()
+		  WHERE id = $1
+		    AND ack_deadline IS NOT NULL
+		    AND acked_at IS NULL
+		    AND status IN ('PENDING', 'QUEUED', 'PROCESSING')`, id)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 1 {
+		return AckAccepted, nil
+	}
+	var ackedAt *time.Time
+	err = r.pool.QueryRow(ctx, `SELECT acked_at FROM msg_dispatch_jobs WHERE id = $1`, id).Scan(&ackedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return AckNotFound, nil
+	case err != nil:
+		return 0, err
+	case ackedAt != nil:
+		return AckDuplicate, nil
+	default:
+		return AckNotAwaiting, nil
+	}
+}
+
 // Requeue resets the given jobs to PENDING for a fresh delivery cycle:
-// clears scheduled_for (immediate eligibility), zeroes attempt_count so a
+// sets scheduled_for to now (immediately eligible, and a new queue
+// deduplication id for the re-publish), zeroes attempt_count so a
 // job that had exhausted its retries gets a full budget again, and clears
 // the terminal stamps. Operator action behind POST /bff/dispatch-jobs/requeue.
 //
 // accessibleClientIDs scopes the reset for non-anchor callers: when non-nil,
 // only rows whose client_id is in the set are touched (which also excludes
 // platform-scoped NULL-client jobs — correct, since a non-anchor can't reach
-// them). Pass nil for anchors (no scoping). Returns the rows actually reset.
-func (r *Repository) Requeue(ctx context.Context, ids []string, accessibleClientIDs *[]string) (int64, error) {
+// them). Pass nil for anchors (no scoping). A non-nil env likewise limits the
+// reset to that environment's jobs. Returns the rows actually reset.
+func (r *Repository) Requeue(ctx context.Context, ids []string, accessibleClientIDs *[]string, env *string) (int64, error) {
 	if len(ids) == 0 {
 		return 0, nil
 	}
 	const base = `UPDATE msg_dispatch_jobs
 		    SET status = 'PENDING',
-		        scheduled_for = NULL,
+		        scheduled_for = NOW(),
 		        attempt_count = 0,
 		        completed_at = NULL,
 		        duration_millis = NULL,
 		        last_error = NULL,
+		        ack_deadline = NULL,
+		        acked_at = NULL,
 		        updated_at = NOW()
-		  WHERE id = ANY($1)`
+		  WHERE id = ANY($1) AND ($2::text IS NULL OR environment = $2)`
 	var tag pgconn.CommandTag
 	var err error
 	if accessibleClientIDs == nil {
-		tag, err = r.pool.Exec(ctx, base, ids)
+		tag, err = r.pool.Exec(ctx, base, ids, env)
 	} else {
-		tag, err = r.pool.Exec(ctx, base+` AND client_id = ANY($2)`, ids, *accessibleClientIDs)
+		tag, err = r.pool.Exec(ctx, base+` AND client_id = ANY($3)`, ids, env, *accessibleClientIDs)
 	}
 	if err != nil {
 		return 0, err
@@ -499,7 +668,7 @@ func (r *Repository) AttemptsByJob(ctx context.Context, jobID string) ([]Attempt
 // ── row → entity adapters ──────────────────────────────────────────────
 
 func findByIDRowToJob(r dbq.DispatchJobFindByIDRow) *DispatchJob {
-	return rowToJob(rawRow{
+	j := rowToJob(rawRow{
 		ID: r.ID, ExternalID: r.ExternalID, Source: r.Source, Kind: r.Kind,
 		Code: r.Code, Subject: r.Subject, EventID: r.EventID,
 		CorrelationID: r.CorrelationID, Metadata: r.Metadata,
@@ -515,8 +684,11 @@ func findByIDRowToJob(r dbq.DispatchJobFindByIDRow) *DispatchJob {
 		LastAttemptAt: r.LastAttemptAt, CompletedAt: r.CompletedAt,
 		DurationMillis: r.DurationMillis, LastError: r.LastError,
 		IdempotencyKey: r.IdempotencyKey, CreatedAt: r.CreatedAt,
-		UpdatedAt: r.UpdatedAt,
+		UpdatedAt: r.UpdatedAt, Priority: r.Priority, Environment: r.Environment,
 	})
+	j.AckDeadline, j.AckedAt = r.AckDeadline, r.AckedAt
+	j.FirstAttemptAt = r.FirstAttemptAt
+	return j
 }
 
 // readRow is the slim msg_dispatch_jobs_read column set scanned by the
@@ -557,6 +729,7 @@ type readRow struct {
 	IdempotencyKey   *string    `db:"idempotency_key"`
 	CreatedAt        time.Time  `db:"created_at"`
 	UpdatedAt        time.Time  `db:"updated_at"`
+	Environment      string     `db:"environment"`
 }
 
 func readRowToJob(r readRow) *DispatchJob {
@@ -575,7 +748,7 @@ func readRowToJob(r readRow) *DispatchJob {
 		LastAttemptAt: r.LastAttemptAt, CompletedAt: r.CompletedAt,
 		DurationMillis: r.DurationMillis, LastError: r.LastError,
 		IdempotencyKey: r.IdempotencyKey, CreatedAt: r.CreatedAt,
-		UpdatedAt: r.UpdatedAt,
+		UpdatedAt: r.UpdatedAt, Environment: r.Environment,
 		// Payload / Metadata / SchemaID / PayloadContentType / DataOnly absent.
 	})
 }
@@ -619,6 +792,8 @@ type rawRow struct {
 	IdempotencyKey     *string
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	Priority           string
+	Environment        string
 }
 
 func rowToJob(r rawRow) *DispatchJob {
@@ -636,11 +811,13 @@ func rowToJob(r rawRow) *DispatchJob {
 		EventID:          r.EventID,
 		CorrelationID:    r.CorrelationID,
 		ClientID:         r.ClientID,
+		Environment:      common.ParseEnvironment(r.Environment),
 		SubscriptionID:   r.SubscriptionID,
 		ServiceAccountID: r.ServiceAccountID,
 		DispatchPoolID:   r.DispatchPoolID,
 		MessageGroup:     r.MessageGroup,
 		Mode:             common.ParseDispatchMode(r.Mode),
+		Priority:         common.ParsePriority(r.Priority),
 		Sequence:         r.Sequence,
 		TimeoutSeconds:   uint32(r.TimeoutSeconds),
 		SchemaID:         r.SchemaID,
@@ -4,11 +4,14 @@ package dispatchjob_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 )
@@ -76,3 +79,149 @@ func TestFindWithFilters_TenantScoping(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, ids(rows), "cross-tenant filter must not leak another tenant's jobs")
 }
+
+// TestFindScheduled_FutureOnlySoonestFirst pins the upcoming-jobs read:
+// only PENDING jobs with a future scheduled_for, soonest first.
+func TestFindScheduled_FutureOnlySoonestFirst(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := dispatchjob.NewRepository(pool)
+
+	const client = "clt_schedjob0001"
+	now := time.Now().UTC()
+	at := func(d time.Duration) *time.Time { v := now.Add(d); return &v }
+	job := func(id string, scheduledFor *time.Time) dispatchjob.DispatchJob {
+		c := client
+		return dispatchjob.DispatchJob{
+			ID: id, Kind: dispatchjob.KindEvent, Code: "schedtest:jobs:later",
+			TargetURL: "http://example.invalid/hook", Protocol: dispatchjob.ProtocolHTTPWebhook,
+			PayloadContentType: "application/json", ClientID: &c,
+			Mode: common.DispatchImmediate, Priority: common.PriorityNormal,
+			MaxRetries: 3, RetryStrategy: dispatchjob.RetryExponentialBackoff,
+			Status: common.DispatchPending, ScheduledFor: scheduledFor,
+		}
+	}
+	require.NoError(t, repo.InsertBatch(ctx, []dispatchjob.DispatchJob{
+		job("djschedtest01", at(2*time.Hour)),
+		job("djschedtest02", at(time.Hour)),
+		job("djschedtest03", at(-time.Minute)), // already due
+		job("djschedtest04", nil),              // immediate
+	}))
+
+	c := client
+	rows, err := repo.FindScheduled(ctx, dispatchjob.ScheduledParams{ClientID: &c})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "djschedtest02", rows[0].ID)
+	assert.Equal(t, "djschedtest01", rows[1].ID)
+
+	rows, err = repo.FindScheduled(ctx, dispatchjob.ScheduledParams{ClientID: &c, Until: at(90 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "djschedtest02", rows[0].ID)
+
+	other := []string{"clt_schedjob0002"}
+	rows, err = repo.FindScheduled(ctx, dispatchjob.ScheduledParams{ClientID: &c, AccessibleClientIDs: &other})
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+// TestSearch_SeeksThroughPages pins the keyset walk: pages neither skip
+// nor repeat rows, ties on the sort column break by id, and both
+// directions work.
+func TestSearch_SeeksThroughPages(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := dispatchjob.NewRepository(pool)
+
+	const code = "searchtest:jobs:page"
+	base := time.Now().UTC().Truncate(time.Second)
+	var want []string
+	for i := range 7 {
+		id := fmt.Sprintf("djsearchtst%02d", i)
+		// Pairs share a created_at so the id tie-breaker is exercised.
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_dispatch_jobs_read
+			     (id, code, target_url, kind, protocol, mode, status, max_retries, attempt_count, updated_at, created_at)
+			 VALUES ($1, $2, 'http://example.invalid/hook',
+			         'EVENT', 'HTTP_WEBHOOK', 'IMMEDIATE', 'PENDING', 3, $3, NOW(), $4)`,
+			id, code, i%3, base.Add(time.Duration(i/2)*time.Second))
+		require.NoError(t, err)
+		want = append(want, id)
+	}
+
+	walk := func(sort dispatchjob.SortKey, asc bool) []string {
+		t.Helper()
+		var got []string
+		var after *dispatchjob.Cursor
+		for range 10 {
+			rows, err := repo.Search(ctx, dispatchjob.SearchParams{
+				FilterParams: dispatchjob.FilterParams{Codes: []string{code}, Limit: 3},
+				Sort:         sort, Ascending: asc, After: after,
+			})
+			require.NoError(t, err)
+			if len(rows) == 0 {
+				return got
+			}
+			for i := range rows {
+				got = append(got, rows[i].ID)
+			}
+			c := sort.CursorOf(&rows[len(rows)-1])
+			after = &c
+		}
+		t.Fatal("search never ran out of rows")
+		return nil
+	}
+
+	assert.Equal(t, want, walk(dispatchjob.SortCreatedAt, true))
+	desc := walk(dispatchjob.SortCreatedAt, false)
+	require.Len(t, desc, len(want))
+	for i := range desc {
+		assert.Equal(t, want[len(want)-1-i], desc[i])
+	}
+	assert.Equal(t, []string{
+		"djsearchtst00", "djsearchtst03", "djsearchtst06",
+		"djsearchtst01", "djsearchtst04",
+		"djsearchtst02", "djsearchtst05",
+	}, walk(dispatchjob.SortAttemptCount, true))
+
+	_, err := repo.Search(ctx, dispatchjob.SearchParams{
+		Sort: dispatchjob.SortCreatedAt, After: &dispatchjob.Cursor{Value: "yesterday", ID: "x"},
+	})
+	assert.ErrorIs(t, err, dispatchjob.ErrInvalidCursor)
+}
+
+// TestSavedFilters_CRUD pins the per-principal saved-filter store.
+func TestSavedFilters_CRUD(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := dispatchjob.NewRepository(pool)
+
+	const owner = "prn_savedfilt001"
+	f := dispatchjob.NewSavedFilter(owner, "Failed orders", dispatchjob.SavedFilterCriteria{
+		Statuses: []string{"FAILED"}, Codes: []string{"orders:fulfilment:shipment:created"}, Sort: "updatedAt",
+	})
+	require.NoError(t, repo.SaveFilter(ctx, f))
+
+	got, err := repo.SavedFilterByName(ctx, owner, "Failed orders")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, f.Criteria, got.Criteria)
+
+	f.Name, f.Criteria.Order = "Failed orders (oldest)", "asc"
+	require.NoError(t, repo.SaveFilter(ctx, f))
+	list, err := repo.SavedFilters(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Failed orders (oldest)", list[0].Name)
+	assert.Equal(t, "asc", list[0].Criteria.Order)
+
+	others, err := repo.SavedFilters(ctx, "prn_savedfilt002")
+	require.NoError(t, err)
+	assert.Empty(t, others, "filters are personal")
+
+	require.NoError(t, repo.DeleteSavedFilter(ctx, f.ID))
+	n, err := repo.CountSavedFilters(ctx, owner)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
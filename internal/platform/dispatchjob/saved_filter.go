@@ -0,0 +1,154 @@
+package dispatchjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// MaxSavedFilters caps how many filters one principal can keep.
+const MaxSavedFilters = 50
+
+// SavedFilter is a named dispatch-job search a user kept from the list
+// view (table msg_dispatch_job_saved_filters). Filters are personal UI
+// state rather than platform configuration, so they're written straight
+// through the repository — no use case, no domain event.
+type SavedFilter struct {
+	ID          string
+	PrincipalID string
+	Name        string
+	Criteria    SavedFilterCriteria
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SavedFilterCriteria is the saved search, keyed like the search query
+// parameters so the SPA can replay it as-is. Time windows aren't kept:
+// an absolute since/until goes stale the day after it's saved.
+type SavedFilterCriteria struct {
+	Statuses       []string `json:"statuses,omitempty"`
+	ClientIDs      []string `json:"clientIds,omitempty"`
+	Applications   []string `json:"applications,omitempty"`
+	Subdomains     []string `json:"subdomains,omitempty"`
+	Aggregates     []string `json:"aggregates,omitempty"`
+	Codes          []string `json:"codes,omitempty"`
+	DispatchPoolID string   `json:"dispatchPoolId,omitempty"`
+	SubscriptionID string   `json:"subscriptionId,omitempty"`
+	Source         string   `json:"source,omitempty"`
+	Sort           string   `json:"sort,omitempty"`
+	Order          string   `json:"order,omitempty"`
+}
+
+// NewSavedFilter constructs a SavedFilter with a fresh TSID.
+func NewSavedFilter(principalID, name string, criteria SavedFilterCriteria) *SavedFilter {
+	now := time.Now().UTC()
+	return &SavedFilter{
+		ID:          tsid.Generate(tsid.DispatchJobSavedFilter),
+		PrincipalID: principalID,
+		Name:        name,
+		Criteria:    criteria,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+const savedFilterCols = `id, principal_id, name, criteria, created_at, updated_at`
+
+func scanSavedFilter(row pgx.Row) (*SavedFilter, error) {
+	var f SavedFilter
+	var criteria []byte
+	if err := row.Scan(&f.ID, &f.PrincipalID, &f.Name, &criteria, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(criteria, &f.Criteria); err != nil {
+		return nil, fmt.Errorf("decode criteria: %w", err)
+	}
+	return &f, nil
+}
+
+func (r *Repository) findSavedFilter(ctx context.Context, where string, args ...any) (*SavedFilter, error) {
+	f, err := scanSavedFilter(r.pool.QueryRow(ctx,
+		`SELECT `+savedFilterCols+` FROM msg_dispatch_job_saved_filters WHERE `+where, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find_saved_filter: %w", err)
+	}
+	return f, nil
+}
+
+// SavedFilterByID loads one saved filter. Returns (nil, nil) when not
+// found.
+func (r *Repository) SavedFilterByID(ctx context.Context, id string) (*SavedFilter, error) {
+	return r.findSavedFilter(ctx, `id = $1`, id)
+}
+
+// SavedFilterByName loads principalID's filter called name. Returns
+// (nil, nil) when not found.
+func (r *Repository) SavedFilterByName(ctx context.Context, principalID, name string) (*SavedFilter, error) {
+	return r.findSavedFilter(ctx, `principal_id = $1 AND name = $2`, principalID, name)
+}
+
+// SavedFilters lists principalID's filters by name.
+func (r *Repository) SavedFilters(ctx context.Context, principalID string) ([]SavedFilter, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+savedFilterCols+` FROM msg_dispatch_job_saved_filters
+		  WHERE principal_id = $1 ORDER BY name`, principalID)
+	if err != nil {
+		return nil, fmt.Errorf("list_saved_filters: %w", err)
+	}
+	defer rows.Close()
+	out := []SavedFilter{}
+	for rows.Next() {
+		f, err := scanSavedFilter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list_saved_filters: %w", err)
+		}
+		out = append(out, *f)
+	}
+	return out, rows.Err()
+}
+
+// CountSavedFilters is how many filters principalID keeps.
+func (r *Repository) CountSavedFilters(ctx context.Context, principalID string) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM msg_dispatch_job_saved_filters WHERE principal_id = $1`, principalID).Scan(&n)
+	return n, err
+}
+
+// SaveFilter inserts or updates f.
+func (r *Repository) SaveFilter(ctx context.Context, f *SavedFilter) error {
+	criteria, err := json.Marshal(f.Criteria)
+	if err != nil {
+		return fmt.Errorf("encode criteria: %w", err)
+	}
+	f.UpdatedAt = time.Now().UTC()
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO msg_dispatch_job_saved_filters (`+savedFilterCols+`)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (id) DO UPDATE SET
+		     name = EXCLUDED.name,
+		     criteria = EXCLUDED.criteria,
+		     updated_at = EXCLUDED.updated_at`,
+		f.ID, f.PrincipalID, f.Name, criteria, f.CreatedAt, f.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save_filter: %w", err)
+	}
+	return nil
+}
+
+// DeleteSavedFilter removes a saved filter.
+func (r *Repository) DeleteSavedFilter(ctx context.Context, id string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM msg_dispatch_job_saved_filters WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete_saved_filter: %w", err)
+	}
+	return nil
+}
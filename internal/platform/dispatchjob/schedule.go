@@ -0,0 +1,82 @@
+package dispatchjob
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Schedule is the optional delayed-dispatch request carried by the SDK
+// create endpoints. At most one of ScheduledAt / DelaySeconds may be set.
+//
+// ScheduledAt is either RFC 3339 with an offset ("2026-11-02T09:00:00+01:00")
+// or a local wall-clock time without one ("2026-11-02T09:00:00" or
+// "2026-11-02T09:00"), which is read in Timezone (IANA name, default UTC).
+// An explicit offset always wins over Timezone.
+type Schedule struct {
+	ScheduledAt  *string
+	Timezone     *string
+	DelaySeconds *uint32
+}
+
+// ErrInvalidSchedule is wrapped by Schedule.Resolve for every rejected
+// input; the HTTP layer maps it to 400.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// maxScheduleHorizon caps how far ahead a job may be scheduled. Jobs sit
+// PENDING on the write table until then, so an unbounded horizon would
+// let a typo ("2062" for "2026") park rows for decades.
+const maxScheduleHorizon = 366 * 24 * time.Hour
+
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// Resolve returns the UTC instant the job becomes eligible for dispatch,
+// or nil when no schedule was requested (dispatch immediately). A time in
+// the past is accepted and dispatches on the next scheduler poll.
+func (s Schedule) Resolve(now time.Time) (*time.Time, error) {
+	if s.ScheduledAt != nil && s.DelaySeconds != nil {
+		return nil, fmt.Errorf("%w: scheduledAt and delaySeconds are mutually exclusive", ErrInvalidSchedule)
+	}
+	if s.Timezone != nil && s.ScheduledAt == nil {
+		return nil, fmt.Errorf("%w: timezone requires scheduledAt", ErrInvalidSchedule)
+	}
+
+	var at time.Time
+	switch {
+	case s.DelaySeconds != nil:
+		at = now.Add(time.Duration(*s.DelaySeconds) * time.Second)
+	case s.ScheduledAt != nil:
+		t, err := parseScheduledAt(*s.ScheduledAt, s.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		at = t
+	default:
+		return nil, nil
+	}
+	if at.Sub(now) > maxScheduleHorizon {
+		return nil, fmt.Errorf("%w: cannot schedule more than %d days ahead", ErrInvalidSchedule, int(maxScheduleHorizon/(24*time.Hour)))
+	}
+	at = at.UTC()
+	return &at, nil
+}
+
+func parseScheduledAt(v string, tz *string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	loc := time.UTC
+	if tz != nil && *tz != "" {
+		l, err := time.LoadLocation(*tz)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, *tz)
+		}
+		loc = l
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: scheduledAt %q is not an RFC 3339 or local date-time", ErrInvalidSchedule, v)
+}
@@ -0,0 +1,59 @@
+package dispatchjob
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strp(s string) *string { return &s }
+func u32p(v uint32) *uint32 { return &v }
+
+var scheduleNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func TestSchedule_ResolveNoneDispatchesImmediately(t *testing.T) {
+	at, err := Schedule{}.Resolve(scheduleNow)
+	require.NoError(t, err)
+	assert.Nil(t, at)
+}
+
+func TestSchedule_ResolveDelay(t *testing.T) {
+	at, err := Schedule{DelaySeconds: u32p(90)}.Resolve(scheduleNow)
+	require.NoError(t, err)
+	assert.Equal(t, scheduleNow.Add(90*time.Second), *at)
+}
+
+func TestSchedule_ResolveScheduledAt(t *testing.T) {
+	// Explicit offset: timezone is ignored.
+	at, err := Schedule{ScheduledAt: strp("2026-10-17T09:00:00+02:00"), Timezone: strp("America/New_York")}.Resolve(scheduleNow)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC), *at)
+
+	// Local wall-clock in the supplied zone (Berlin is UTC+2 in October).
+	at, err = Schedule{ScheduledAt: strp("2026-10-17T09:00"), Timezone: strp("Europe/Berlin")}.Resolve(scheduleNow)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC), *at)
+	assert.Equal(t, time.UTC, at.Location())
+
+	// No zone → UTC.
+	at, err = Schedule{ScheduledAt: strp("2026-10-17T09:00:00")}.Resolve(scheduleNow)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), *at)
+}
+
+func TestSchedule_ResolveRejects(t *testing.T) {
+	cases := map[string]Schedule{
+		"both":             {ScheduledAt: strp("2026-10-17T09:00:00Z"), DelaySeconds: u32p(5)},
+		"tz without time":  {Timezone: strp("Europe/Berlin")},
+		"unknown timezone": {ScheduledAt: strp("2026-10-17T09:00"), Timezone: strp("Mars/Olympus")},
+		"garbage":          {ScheduledAt: strp("tomorrow")},
+		"beyond horizon":   {ScheduledAt: strp("2062-10-17T09:00:00Z")},
+	}
+	for name, s := range cases {
+		_, err := s.Resolve(scheduleNow)
+		assert.True(t, errors.Is(err, ErrInvalidSchedule), "%s: got %v", name, err)
+	}
+}
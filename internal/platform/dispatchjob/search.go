@@ -0,0 +1,148 @@
+package dispatchjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+)
+
+// SortKey names a msg_dispatch_jobs_read column Search can order by.
+type SortKey string
+
+const (
+	SortCreatedAt    SortKey = "createdAt"
+	SortUpdatedAt    SortKey = "updatedAt"
+	SortAttemptCount SortKey = "attemptCount"
+	SortStatus       SortKey = "status"
+	SortCode         SortKey = "code"
+)
+
+// SortKeys lists every supported SortKey, default first.
+var SortKeys = []SortKey{SortCreatedAt, SortUpdatedAt, SortAttemptCount, SortStatus, SortCode}
+
+// ErrInvalidCursor is returned for a cursor value that doesn't parse as
+// its sort key's type.
+var ErrInvalidCursor = errors.New("dispatch_job: invalid cursor")
+
+// column is the projection column behind k; "" for an unknown key. Only
+// NOT NULL columns are sortable — a NULL would fall out of the row
+// comparison the seek relies on.
+func (k SortKey) column() string {
+	switch k {
+	case SortCreatedAt:
+		return "created_at"
+	case SortUpdatedAt:
+		return "updated_at"
+	case SortAttemptCount:
+		return "attempt_count"
+	case SortStatus:
+		return "status"
+	case SortCode:
+		return "code"
+	}
+	return ""
+}
+
+// Valid reports whether k is a supported sort key.
+func (k SortKey) Valid() bool { return k.column() != "" }
+
+// FormatValue renders j's value for k as a cursor value.
+func (k SortKey) FormatValue(j *DispatchJob) string {
+	switch k {
+	case SortUpdatedAt:
+		return j.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortAttemptCount:
+		return strconv.Itoa(int(j.AttemptCount))
+	case SortStatus:
+		return string(j.Status)
+	case SortCode:
+		return j.Code
+	}
+	return j.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// ParseValue reverses FormatValue into the column's SQL type.
+func (k SortKey) ParseValue(v string) (any, error) {
+	switch k {
+	case SortCreatedAt, SortUpdatedAt:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	case SortAttemptCount:
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return int32(n), nil
+	case SortStatus, SortCode:
+		return v, nil
+	}
+	return nil, ErrInvalidCursor
+}
+
+// Cursor is a keyset position: the sort column's value on the last row
+// of the previous page, with the row's id breaking ties.
+type Cursor struct {
+	Value string
+	ID    string
+}
+
+// CursorOf is the position just past j under k.
+func (k SortKey) CursorOf(j *DispatchJob) Cursor {
+	return Cursor{Value: k.FormatValue(j), ID: j.ID}
+}
+
+// SearchParams is the query DTO for the cursor-paginated search. The
+// embedded filters behave as in FindWithFilters, except Offset, which is
+// ignored: pages are addressed by After (nil for the first page). Limit
+// is the fetch size and should already include the +1 over-fetch used to
+// compute hasMore.
+type SearchParams struct {
+	FilterParams
+	Sort      SortKey
+	Ascending bool
+	After     *Cursor
+}
+
+// Search returns dispatch jobs matching the filters, ordered by
+// (sort column, id) and starting strictly after the cursor. Seeking
+// rather than skipping keeps deep pages as cheap as the first one, which
+// FindWithFilters' OFFSET can't on a large projection.
+func (r *Repository) Search(ctx context.Context, p SearchParams) ([]DispatchJob, error) {
+	sort := p.Sort
+	if sort == "" {
+		sort = SortCreatedAt
+	}
+	col := sort.column()
+	if col == "" {
+		return nil, fmt.Errorf("dispatch_job repo: sort key %q not allowed", p.Sort)
+	}
+	dir, cmp := "DESC", "<"
+	if p.Ascending {
+		dir, cmp = "ASC", ">"
+	}
+
+	p.AccessibleClientIDs = reqctx.ClientScope(ctx, p.AccessibleClientIDs)
+	f := p.filter()
+	if p.After != nil {
+		v, err := sort.ParseValue(p.After.Value)
+		if err != nil {
+			return nil, err
+		}
+		ph := f.Arg(v)
+		f.Clause(fmt.Sprintf("(%s, id) %s ($%d, $%%d)", col, cmp, ph), p.After.ID)
+	}
+	limit := p.Limit
+	if limit <= 0 || limit > 1001 {
+		limit = 101
+	}
+	q := readSelect + f.Where() +
+		fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", col, dir, dir, f.Arg(limit))
+	return r.queryRead(ctx, q, f.Args()...)
+}
@@ -4,15 +4,18 @@ package api
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/analytics"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -22,6 +25,8 @@ import (
 type State struct {
 	Repo *dispatchpool.Repository
 	UoW  *usecasepgx.UnitOfWork
+	// Analytics backs the capacity recommendations.
+	Analytics *analytics.Repository
 }
 
 const tag = "dispatch-pools"
@@ -37,6 +42,7 @@ func Register(api huma.API, s *State) {
 	apiroute.Post(g, "suspendDispatchPool", "/api/dispatch-pools/{id}/suspend", "Suspend dispatch into a pool", http.StatusNoContent, s.suspend)
 	apiroute.Post(g, "activateDispatchPool", "/api/dispatch-pools/{id}/activate", "Resume a suspended dispatch pool", http.StatusNoContent, s.activate)
 	apiroute.Delete(g, "deleteDispatchPool", "/api/dispatch-pools/{id}", "Delete a dispatch pool", http.StatusNoContent, s.delete)
+	apiroute.Get(g, "getDispatchPoolRecommendations", "/api/admin/platform/dispatch-pools/{id}/recommendations", "Recommend a dispatch pool's concurrency and rate limit from its recent throughput", s.recommendations)
 }
 
 type listInput struct {
@@ -85,7 +91,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateDispatchPoolR
 	if err := auth.CanWriteDispatchPools(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateDispatchPool(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -102,7 +108,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanWriteDispatchPools(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateDispatchPool(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -113,7 +119,7 @@ func (s *State) archive(ctx context.Context, in *apicommon.IDInput) (*apicommon.
 	if err := auth.CanWriteDispatchPools(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ArchiveDispatchPool(s.Repo), operations.ArchiveCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -124,7 +130,7 @@ func (s *State) suspend(ctx context.Context, in *apicommon.IDInput) (*apicommon.
 	if err := auth.CanWriteDispatchPools(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.SuspendDispatchPool(s.Repo), operations.SuspendCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -135,7 +141,7 @@ func (s *State) activate(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	if err := auth.CanWriteDispatchPools(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ActivateDispatchPool(s.Repo), operations.ActivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -146,9 +152,47 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteDispatchPools(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteDispatchPool(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
 	return &apicommon.Empty{}, nil
 }
+
+type recommendationsInput struct {
+	ID    string `path:"id"`
+	Range string `query:"range" enum:"1h,6h,24h,7d,30d" doc:"Look-back window; defaults to 24h"`
+}
+
+// recommendations reports the pool's throughput over the range and the
+// settings analytics.RecommendPool derives from it. Anchor-only: sizing
+// pools is a platform operator's call. Nothing is changed; apply a
+// recommendation with PUT /api/dispatch-pools/{id}.
+func (s *State) recommendations(ctx context.Context, in *recommendationsInput) (*apicommon.Out[PoolRecommendationResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	name := in.Range
+	if name == "" {
+		name = analytics.DefaultRange
+	}
+	rng, ok := analytics.Ranges[name]
+	if !ok {
+		return nil, httperror.BadRequest("INVALID_RANGE", "range must be one of 1h, 6h, 24h, 7d, 30d")
+	}
+	p, err := s.Repo.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if p == nil {
+		return nil, httperror.NotFound("DispatchPool", in.ID)
+	}
+	now := time.Now()
+	load, err := s.Analytics.PoolLoad(ctx, p.ID, rng, now)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "pool_load failed", err)
+	}
+	rec := analytics.RecommendPool(p.Concurrency, p.RateLimit, load)
+	from, to := rng.Window(now)
+	return &apicommon.Out[PoolRecommendationResponse]{Body: toRecommendationResponse(p, rng, from, to, load, rec)}, nil
+}
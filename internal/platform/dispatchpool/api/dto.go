@@ -2,6 +2,9 @@
 package api
 
 import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/analytics"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
@@ -84,3 +87,80 @@ type DispatchPoolListResponse struct {
 	Pools []DispatchPoolResponse `json:"pools"`
 	Total int                    `json:"total"`
 }
+
+// PoolSettings is a pool's concurrency and rate limit.
+type PoolSettings struct {
+	Concurrency int32  `json:"concurrency"`
+	RateLimit   *int32 `json:"rateLimit,omitempty" doc:"Messages per minute (nil = no rate limit)"`
+}
+
+// ServiceTimeResponse is the attempt-duration distribution, in
+// milliseconds.
+type ServiceTimeResponse struct {
+	Samples int64    `json:"samples"`
+	Mean    *float64 `json:"mean,omitempty"`
+	P50     *float64 `json:"p50,omitempty"`
+	P90     *float64 `json:"p90,omitempty"`
+	P95     *float64 `json:"p95,omitempty"`
+	P99     *float64 `json:"p99,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+}
+
+// PoolLoadResponse mirrors analytics.PoolLoad plus utilization.
+type PoolLoadResponse struct {
+	Arrivals              int64               `json:"arrivals" doc:"Jobs routed to the pool and created in the range"`
+	Attempts              int64               `json:"attempts" doc:"Delivery attempts in the range, retries included"`
+	MeanArrivalsPerMinute float64             `json:"meanArrivalsPerMinute"`
+	PeakArrivalsPerMinute float64             `json:"peakArrivalsPerMinute"`
+	PeakAttemptsPerMinute float64             `json:"peakAttemptsPerMinute"`
+	ServiceTimeMs         ServiceTimeResponse `json:"serviceTimeMs"`
+	MeanInFlight          float64             `json:"meanInFlight" doc:"Average concurrent deliveries over the range"`
+	PeakInFlight          float64             `json:"peakInFlight" doc:"Average concurrent deliveries in the busiest bucket"`
+	MeanUtilization       float64             `json:"meanUtilization" doc:"meanInFlight over the configured concurrency"`
+	PeakUtilization       float64             `json:"peakUtilization" doc:"peakInFlight over the configured concurrency"`
+}
+
+// PoolRecommendationResponse is the wire shape for
+// GET /api/admin/platform/dispatch-pools/{id}/recommendations.
+type PoolRecommendationResponse struct {
+	PoolID           string           `json:"poolId"`
+	Code             string           `json:"code"`
+	Range            string           `json:"range"`
+	From             httpcompat.Time  `json:"from"`
+	To               httpcompat.Time  `json:"to"`
+	Current          PoolSettings     `json:"current"`
+	Recommended      PoolSettings     `json:"recommended"`
+	InsufficientData bool             `json:"insufficientData" doc:"Too few timed attempts to recommend a change; recommended equals current"`
+	Reasons          []string         `json:"reasons"`
+	Load             PoolLoadResponse `json:"load"`
+}
+
+func toRecommendationResponse(p *dispatchpool.DispatchPool, rng analytics.Range, from, to time.Time, l analytics.PoolLoad, rec analytics.PoolRecommendation) PoolRecommendationResponse {
+	st := l.ServiceTime
+	return PoolRecommendationResponse{
+		PoolID:           p.ID,
+		Code:             p.Code,
+		Range:            rng.Name,
+		From:             jsontime.New(from),
+		To:               jsontime.New(to),
+		Current:          PoolSettings{Concurrency: p.Concurrency, RateLimit: p.RateLimit},
+		Recommended:      PoolSettings{Concurrency: rec.Concurrency, RateLimit: rec.RateLimit},
+		InsufficientData: rec.InsufficientData,
+		Reasons:          rec.Reasons,
+		Load: PoolLoadResponse{
+			Arrivals:              l.Arrivals,
+			Attempts:              l.Attempts,
+			MeanArrivalsPerMinute: l.MeanArrivalsPerMin,
+			PeakArrivalsPerMinute: l.PeakArrivalsPerMin,
+			PeakAttemptsPerMinute: l.PeakAttemptsPerMin,
+			ServiceTimeMs: ServiceTimeResponse{
+				Samples: st.Samples, Mean: st.MeanMs, P50: st.P50Ms, P90: st.P90Ms,
+				P95: st.P95Ms, P99: st.P99Ms, Max: st.MaxMs,
+			},
+			MeanInFlight:    l.MeanInFlight,
+			PeakInFlight:    l.PeakInFlight,
+			MeanUtilization: rec.MeanUtilization,
+			PeakUtilization: rec.PeakUtilization,
+		},
+	}
+}
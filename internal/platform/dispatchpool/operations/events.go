@@ -21,6 +21,18 @@ const (
 func subjectFor(id string) string { return "platform.dispatchpool." + id }
 func groupFor(id string) string   { return "platform:dispatchpool:" + id }
 
+// NewDispatchPoolCreatedEvent builds the created event with the canonical
+// subject. Exported so cross-aggregate orchestrations (e.g. client
+// onboarding) can emit it inside their own transaction.
+func NewDispatchPoolCreatedEvent(ec usecase.ExecutionContext, poolID, code, name string) DispatchPoolCreated {
+	return DispatchPoolCreated{
+		Metadata: usecase.NewEventMetadata(ec, DispatchPoolCreatedType, Source, subjectFor(poolID)),
+		PoolID:   poolID,
+		Code:     code,
+		Name:     name,
+	}
+}
+
 type DispatchPoolCreated struct {
 	Metadata usecase.EventMetadata
 	PoolID   string
@@ -24,7 +24,7 @@ type CreateMappingRequest struct {
 	// 2FA enforcement for internal-auth users of this domain. All optional
 	// (default false / empty) — OIDC-domain creates omit them entirely.
 	Require2FA            bool     `json:"require2fa,omitempty"`
-	Allowed2FAMethods     []string `json:"allowed2faMethods,omitempty" doc:"Permitted 2FA methods (TOTP, EMAIL_PIN). ≥1 required when require2fa is set."`
+	Allowed2FAMethods     []string `json:"allowed2faMethods,omitempty" doc:"Permitted 2FA methods (TOTP, EMAIL_PIN, WEBAUTHN). When require2fa is set, at least one of TOTP or EMAIL_PIN is required."`
 	RememberDeviceEnabled bool     `json:"rememberDeviceEnabled,omitempty"`
 	RememberDeviceDays    int      `json:"rememberDeviceDays,omitempty"`
 }
@@ -38,18 +38,28 @@ const (
 	MFATOTP MFAMethod = "TOTP"
 	// MFAEmailPin is a one-time numeric PIN delivered by email.
 	MFAEmailPin MFAMethod = "EMAIL_PIN"
+	// MFAWebAuthn is a registered passkey answering an assertion challenge.
+	// Passkeys are registered from the profile screen, not enrolled
+	// mid-login, so it can't be a domain's only permitted method.
+	MFAWebAuthn MFAMethod = "WEBAUTHN"
 )
 
 // ValidMFAMethod reports whether s is a known second-factor mechanism.
 func ValidMFAMethod(s string) bool {
 	switch MFAMethod(s) {
-	case MFATOTP, MFAEmailPin:
+	case MFATOTP, MFAEmailPin, MFAWebAuthn:
 		return true
 	default:
 		return false
 	}
 }
 
+// EnrollableMFAMethod reports whether s can be set up during the
+// enrollment_required interstitial (TOTP or email PIN).
+func EnrollableMFAMethod(s string) bool {
+	return MFAMethod(s) == MFATOTP || MFAMethod(s) == MFAEmailPin
+}
+
 // EmailDomainMapping is the aggregate root.
 type EmailDomainMapping struct {
 	ID                   string    `json:"id"`
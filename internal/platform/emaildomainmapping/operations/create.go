@@ -28,18 +28,26 @@ type CreateCommand struct {
 }
 
 // validate2FA checks the 2FA fields: every method must be known, and at least
-// one method must be allowed when 2FA is required.
+// one method must be allowed when 2FA is required. WEBAUTHN alone isn't
+// enough — a user without a passkey must be able to enroll something at
+// their next login.
 func validate2FA(require2FA bool, methods []string) error {
+	enrollable := false
 	for _, m := range methods {
 		if !emaildomainmapping.ValidMFAMethod(m) {
 			return usecase.Validation("INVALID_2FA_METHOD",
-				"allowed2faMethods entries must be TOTP or EMAIL_PIN")
+				"allowed2faMethods entries must be TOTP, EMAIL_PIN or WEBAUTHN")
 		}
+		enrollable = enrollable || emaildomainmapping.EnrollableMFAMethod(m)
 	}
 	if require2FA && len(methods) == 0 {
 		return usecase.Validation("2FA_METHOD_REQUIRED",
 			"at least one 2FA method must be allowed when require2fa is set")
 	}
+	if require2FA && !enrollable {
+		return usecase.Validation("2FA_METHOD_REQUIRED",
+			"allowed2faMethods must include TOTP or EMAIL_PIN when require2fa is set")
+	}
 	return nil
 }
 
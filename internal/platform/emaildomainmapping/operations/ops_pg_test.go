@@ -134,6 +134,10 @@ func TestCreateMapping_Validation(t *testing.T) {
 			EmailDomain: "edmcrt-nomethod.example.com", IdentityProviderID: "idp_x", ScopeType: "ANCHOR",
 			Require2FA: true,
 		}, "2FA_METHOD_REQUIRED"},
+		{"require2fa with only webauthn", operations.CreateCommand{
+			EmailDomain: "edmcrt-passkeyonly.example.com", IdentityProviderID: "idp_x", ScopeType: "ANCHOR",
+			Require2FA: true, Allowed2FAMethods: []string{"WEBAUTHN"},
+		}, "2FA_METHOD_REQUIRED"},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
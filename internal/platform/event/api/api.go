@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -11,10 +12,16 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/payloadlimit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/filtercache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/redact"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 )
 
@@ -24,6 +31,28 @@ type State struct {
 	// Clients resolves a clientCode → client_id on ingest (client-centric
 	// linkage). Optional: when nil, clientCode is ignored.
 	Clients *client.Repository
+	// Meter counts ingested events per client and rejects ingest past the
+	// client's monthly quota with 429. Optional: when nil, ingest is
+	// unmetered.
+	Meter *metering.Meter
+	// Redaction masks event data and context values in the /bff views.
+	// Nil masks everything (redact.Default).
+	Redaction *redact.Policy
+	// Payloads enforces the per-client / per-event-type data size limits
+	// (413) and offloads large data to object storage. Optional: when nil,
+	// only the HTTP body limit applies.
+	Payloads *payloadlimit.Offloader
+	// EventTypes records who publishes each event type and schema version,
+	// and flags deprecated ones with warning headers on the response.
+	// Optional: when nil, ingest is untracked.
+	EventTypes *eventtype.UsageTracker
+	// FilterOptions caches the filter-options answer. Optional: when nil,
+	// every call queries the read table.
+	FilterOptions *filtercache.Cache[EventFilterOptionsResponse]
+	// Dedup answers a singular create that re-sends a sourceEventId inside
+	// the window with the first event. Optional: when nil or disabled,
+	// sourceEventId is ignored.
+	Dedup *event.Dedup
 }
 
 const tag = "events"
@@ -52,19 +81,21 @@ func Register(api huma.API, s *State) {
 	// from the regular list — so it gets its own handler returning a bare
 	// array of RawEventResponse. Mirrors Rust's shared/debug_api.rs.
 	gd := apiroute.New(api, "bff-debug-events")
-	apiroute.Get(gd, "listDebugEvents", "/bff/debug/events", "List raw events (debug view of msg_events)", s.listDebugRaw)
+	apiroute.Get(gd, "listDebugEvents", "/bff/debug/events", "List raw events (debug view of msg_events)",
+		redacted(s, s.listDebugRaw, redactRawList))
 }
 
 // registerBFF mirrors Register under a different base path. Used so the
 // SPA hits /bff/events with cookie-auth while SDK callers use /api/events
-// with bearer-auth — the handlers are the same; the auth layer differs.
+// with bearer-auth — the handlers are the same; the auth layer differs,
+// and the detail view is redacted (the list shape carries no payload).
 func registerBFF(api huma.API, s *State, base, opPrefix, tag string) {
 	g := apiroute.New(api, tag)
 	apiroute.Post(g, "batchIngestEvents"+opPrefix, base+"/batch", "Ingest a batch of events (SPA fan-out)", http.StatusCreated, s.batchIngest)
 	apiroute.Get(g, "eventFilterOptions"+opPrefix, base+"/filter-options", "Distinct event types/sources/clients for filter UI", s.filterOptions)
 	apiroute.Get(g, "listEventsRaw"+opPrefix, base+"/list-raw", "List events with raw JSONB rows", s.listRaw)
 	apiroute.Get(g, "listEvents"+opPrefix, base, "List events with filters", s.list)
-	apiroute.Get(g, "getEvent"+opPrefix, base+"/{id}", "Get an event by id", s.getByID)
+	apiroute.Get(g, "getEvent"+opPrefix, base+"/{id}", "Get an event by id", redacted(s, s.getByID, (*EventResponse).redact))
 }
 
 // ── singular create ──────────────────────────────────────────────────────
@@ -75,14 +106,15 @@ func registerBFF(api huma.API, s *State, base, opPrefix, tag string) {
 // (InsertBatch with one item — event ingest bypasses the UoW per
 // docs/conventions.md §3).
 //
-// Known divergence from Rust: the Go event repository deliberately has no
-// find_by_deduplication_id, so the idempotent dedup-hit path (200 +
-// isDuplicate=true returning the existing event) is not implemented —
-// every accepted request inserts and responds 201 with isDuplicate=false.
-// The Laravel SDK decodes CreateEventResponse on both 200 and 201, so the
-// contract holds either way. dispatchJobCount is always 0, exactly like
-// Rust (jobs are fanned out by the stream processor, not inline).
-func (s *State) create(ctx context.Context, in *apicommon.In[CreateEventRequest]) (*apicommon.Out[CreateEventResponse], error) {
+// The dedup-hit path (200 + isDuplicate=true returning the existing
+// event) is keyed by (client, eventType, sourceEventId) inside the
+// configured window rather than by Rust's deduplicationId lookup; without
+// a sourceEventId, or with the window off, every accepted request inserts
+// and responds 201 with isDuplicate=false. The Laravel SDK decodes
+// CreateEventResponse on both 200 and 201, so the contract holds either
+// way. dispatchJobCount is always 0, exactly like Rust (jobs are fanned
+// out by the stream processor, not inline).
+func (s *State) create(ctx context.Context, in *apicommon.In[CreateEventRequest]) (*IngestOutput[CreateEventResponse], error) {
 	ac := auth.FromContext(ctx)
 	if err := auth.CanWritePermission(ac, "platform:messaging:batch:events-write"); err != nil {
 		return nil, err
@@ -95,21 +127,30 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateEventRequest]
 		return nil, httperror.BadRequest("VALIDATION", "data is required")
 	}
 
-	// Client ID: explicit value wins; otherwise non-anchor callers default
-	// to their first accessible client (1:1 with Rust create_event).
+	// Client ID: explicit value wins; otherwise the request's client
+	// (non-anchor callers' first accessible client, 1:1 with Rust
+	// create_event).
 	clientID := req.ClientID
-	if clientID == nil && !ac.IsAnchor() && len(ac.Clients) > 0 {
-		clientID = &ac.Clients[0]
+	if rc := reqctx.From(ctx).ClientID; clientID == nil && rc != "" {
+		clientID = &rc
 	}
 	if clientID != nil && !ac.CanAccessClient(*clientID) {
 		return nil, httperror.Forbidden("No access to client: " + *clientID)
 	}
+	if tl := s.Payloads.Check(clientID, req.EventType, len(req.Data)); tl != nil {
+		return nil, tl
+	}
 
 	ev := event.New(req.EventType, req.Source, req.Subject, req.Data)
 	if req.DeduplicationID != "" {
 		ev.DeduplicationID = req.DeduplicationID
 	}
+	if req.Time != nil && !req.Time.IsZero() {
+		ev.Time = req.Time.UTC()
+	}
 	ev.ClientID = clientID
+	// A sandbox key's events stay in the sandbox.
+	ev.Environment = ac.EnvironmentFor("")
 	ev.MessageGroup = req.MessageGroup
 	ev.CorrelationID = req.CorrelationID
 	ev.CausationID = req.CausationID
@@ -117,19 +158,70 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateEventRequest]
 		ev.Context = append(ev.Context, event.ContextEntry{Key: c.Key, Value: c.Value})
 	}
 
+	if clientID != nil {
+		if err := s.checkQuota(ctx, map[string]int{*clientID: 1}); err != nil {
+			return nil, err
+		}
+	}
+	// Claimed before the offload so a re-send uploads nothing; released
+	// if this event doesn't make it in, so the producer's retry isn't
+	// answered with it.
+	var dedupKey *event.DedupKey
+	if req.SourceEventID != "" && s.Dedup.Enabled() {
+		k := event.DedupKey{Environment: ev.Environment, EventType: req.EventType, SourceEventID: req.SourceEventID}
+		if clientID != nil {
+			k.ClientID = *clientID
+		}
+		existing, err := s.Dedup.Claim(ctx, k, ev)
+		if errors.Is(err, event.ErrDedupInFlight) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		if err != nil {
+			return nil, usecase.Internal("REPO", "dedup claim failed", err)
+		}
+		if existing != nil {
+			out := ingestOutput(deprecationWarning{}, CreateEventResponse{
+				Event:       createdFromEntity(existing),
+				IsDuplicate: true,
+			})
+			out.Status = http.StatusOK
+			return out, nil
+		}
+		dedupKey = &k
+	}
+	if err := s.offload(ctx, ev); err != nil {
+		s.releaseDedup(dedupKey, ev.ID)
+		return nil, err
+	}
 	if _, err := s.Repo.InsertBatch(ctx, []event.Event{*ev}); err != nil {
+		s.releaseDedup(dedupKey, ev.ID)
 		return nil, usecase.Internal("REPO", "insert failed", err)
 	}
-	return &apicommon.Out[CreateEventResponse]{Body: CreateEventResponse{
+	if clientID != nil {
+		s.recordUsage(map[string]int{*clientID: 1})
+	}
+	warning := s.trackEventTypes(ctx, ac.PrincipalID, []event.Event{*ev})
+	return ingestOutput(warning, CreateEventResponse{
 		Event:            createdFromEntity(ev),
 		DispatchJobCount: 0,
 		IsDuplicate:      false,
-	}}, nil
+	}), nil
+}
+
+// releaseDedup drops a create's dedup claim after it failed. The
+// request's context may be what failed, so the release gets its own.
+func (s *State) releaseDedup(k *event.DedupKey, eventID string) {
+	if k == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.Dedup.Release(ctx, *k, eventID)
 }
 
 // ── batch ingest ─────────────────────────────────────────────────────────
 
-func (s *State) batchIngest(ctx context.Context, in *apicommon.In[BatchRequest]) (*apicommon.Out[BatchResponse], error) {
+func (s *State) batchIngest(ctx context.Context, in *apicommon.In[BatchRequest]) (*IngestOutput[BatchResponse], error) {
 	ac := auth.FromContext(ctx)
 	if err := auth.CanWritePermission(ac, "platform:messaging:batch:events-write"); err != nil {
 		return nil, err
@@ -141,7 +233,7 @@ func (s *State) batchIngest(ctx context.Context, in *apicommon.In[BatchRequest])
 	// Per-batch cache of clientCode → client_id (a batch usually shares one
 	// client). A nil entry means "looked up, not found" so we don't re-query.
 	clientByCode := map[string]*string{}
-	for _, it := range in.Body.Items {
+	for i, it := range in.Body.Items {
 		ev := event.New(it.Type, it.Source, it.Subject, it.Data)
 		if it.ID != "" {
 			ev.ID = it.ID
@@ -172,6 +264,12 @@ func (s *State) batchIngest(ctx context.Context, in *apicommon.In[BatchRequest])
 			}
 			ev.ClientID = id
 		}
+		// Checked after clientCode resolution so a per-client limit
+		// applies to code-addressed items too.
+		if tl := s.Payloads.Check(ev.ClientID, it.Type, len(it.Data)); tl != nil {
+			return nil, tl.At(i)
+		}
+		ev.Environment = ac.EnvironmentFor("")
 		ev.MessageGroup = it.MessageGroup
 		ev.CorrelationID = it.CorrelationID
 		ev.CausationID = it.CausationID
@@ -180,15 +278,67 @@ func (s *State) batchIngest(ctx context.Context, in *apicommon.In[BatchRequest])
 		}
 		events = append(events, *ev)
 	}
+	perClient := map[string]int{}
+	for i := range events {
+		if events[i].ClientID != nil {
+			perClient[*events[i].ClientID]++
+		}
+	}
+	// All-or-nothing like the insert: a batch touching a client over its
+	// quota is rejected whole.
+	if err := s.checkQuota(ctx, perClient); err != nil {
+		return nil, err
+	}
+	for i := range events {
+		if err := s.offload(ctx, &events[i]); err != nil {
+			return nil, err
+		}
+	}
 	if _, err := s.Repo.InsertBatch(ctx, events); err != nil {
 		return nil, usecase.Internal("REPO", "insert batch failed", err)
 	}
+	s.recordUsage(perClient)
+	warning := s.trackEventTypes(ctx, ac.PrincipalID, events)
 	// Per-item result list — 1:1 with the outbox/SDK contract. Insert is
 	// all-or-nothing here, so every persisted event reports SUCCESS.
 	results := apicommon.MapSlice(events, func(e *event.Event) BatchResultItem {
 		return BatchResultItem{ID: e.ID, Status: "SUCCESS"}
 	})
-	return &apicommon.Out[BatchResponse]{Body: BatchResponse{Results: results}}, nil
+	return ingestOutput(warning, BatchResponse{Results: results}), nil
+}
+
+// checkQuota rejects ingest that would take any client past its monthly
+// event quota.
+func (s *State) checkQuota(ctx context.Context, perClient map[string]int) error {
+	if s.Meter == nil {
+		return nil
+	}
+	for clientID, n := range perClient {
+		if err := s.Meter.CheckEvents(ctx, clientID, n); errors.Is(err, metering.ErrQuotaExceeded) {
+			return huma.Error429TooManyRequests("Monthly event quota exceeded for client: " + clientID)
+		}
+	}
+	return nil
+}
+
+// offload swaps a large event's data for an object-storage reference
+// before insert, so the row and its fanned-out dispatch jobs stay small.
+func (s *State) offload(ctx context.Context, ev *event.Event) error {
+	data, err := s.Payloads.OffloadEventData(ctx, ev.ID, ev.CreatedAt, ev.Data)
+	if err != nil {
+		return usecase.Internal("PAYLOAD_OFFLOAD_FAILED", "could not store event payload", err)
+	}
+	ev.Data = data
+	return nil
+}
+
+func (s *State) recordUsage(perClient map[string]int) {
+	if s.Meter == nil {
+		return
+	}
+	for clientID, n := range perClient {
+		s.Meter.RecordEvents(clientID, n)
+	}
 }
 
 // ── list / detail ────────────────────────────────────────────────────────
@@ -230,7 +380,9 @@ func splitCSV(s string) []string {
 	return out
 }
 
-func (in *listInput) toFilters() event.FilterParams {
+// toFilters builds the repository filters. A principal pinned to an
+// environment only sees that environment's events.
+func (in *listInput) toFilters(ac *auth.AuthContext) event.FilterParams {
 	ts := func(v string) *time.Time {
 		if v == "" {
 			return nil
@@ -245,6 +397,11 @@ func (in *listInput) toFilters() event.FilterParams {
 	if in.Size > 0 {
 		limit = in.Size
 	}
+	var env *string
+	if ac != nil && ac.Environment != "" {
+		e := string(ac.Environment)
+		env = &e
+	}
 	return event.FilterParams{
 		Type:          apicommon.OptStr(in.Type),
 		Source:        apicommon.OptStr(in.Source),
@@ -261,6 +418,7 @@ func (in *listInput) toFilters() event.FilterParams {
 		Subdomains:    splitCSV(in.Subdomains),
 		Aggregates:    splitCSV(in.Aggregates),
 		Types:         splitCSV(in.Types),
+		Environment:   env,
 	}
 }
 
@@ -268,25 +426,12 @@ func (in *listInput) toFilters() event.FilterParams {
 // binds the returned array directly to its DataTable, so {items:[...]} would
 // render zero rows. Mirrors Rust's list_events returning Vec<EventRead>.
 
-// scopeFilters applies SQL-side tenant scoping (anchor sees all → no
-// scoping). Without it a non-anchor holding event:view could read any
-// tenant's events by passing arbitrary clientId/clientIds filters — the
-// caller-controlled filters must only narrow within the principal's own
-// tenants. Same pattern as scheduledjob's list.
-func scopeFilters(ac *auth.AuthContext, f event.FilterParams) event.FilterParams {
-	if !ac.IsAnchor() {
-		clients := ac.Clients
-		f.AccessibleClientIDs = &clients
-	}
-	return f
-}
-
 func (s *State) list(ctx context.Context, in *listInput) (*apicommon.Out[[]EventRead], error) {
 	ac := auth.FromContext(ctx)
 	if err := auth.CanWritePermission(ac, "platform:messaging:event:view"); err != nil {
 		return nil, err
 	}
-	rows, err := s.Repo.FindWithFilters(ctx, scopeFilters(ac, in.toFilters()))
+	rows, err := s.Repo.FindWithFilters(ctx, in.toFilters(ac))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find_with_filters failed", err)
 	}
@@ -299,7 +444,7 @@ func (s *State) listRaw(ctx context.Context, in *listInput) (*apicommon.Out[[]Ev
 	if err := auth.CanWritePermission(ac, "platform:messaging:event:view-raw"); err != nil {
 		return nil, err
 	}
-	rows, err := s.Repo.FindWithFilters(ctx, scopeFilters(ac, in.toFilters()))
+	rows, err := s.Repo.FindWithFilters(ctx, in.toFilters(ac))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find_raw failed", err)
 	}
@@ -352,6 +497,9 @@ func (s *State) getByID(ctx context.Context, in *apicommon.IDInput) (*apicommon.
 	if ev.ClientID != nil && !ac.CanAccessClient(*ev.ClientID) {
 		return nil, httperror.Forbidden("No access to this event")
 	}
+	if !ac.CanAccessEnvironment(ev.Environment) {
+		return nil, httperror.NotFound("Event", in.ID)
+	}
 	return &apicommon.Out[EventResponse]{Body: fromEntity(ev)}, nil
 }
 
@@ -365,13 +513,23 @@ func (s *State) filterOptions(ctx context.Context, _ *apicommon.Empty) (*apicomm
 	if err := auth.CanWritePermission(ac, "platform:messaging:event:view"); err != nil {
 		return nil, err
 	}
-	q := func(col string) []EventFilterOption {
-		out, _ := s.Repo.DistinctValues(ctx, col, 200)
-		return toFilterOptions(out)
-	}
-	return &apicommon.Out[EventFilterOptionsResponse]{Body: EventFilterOptionsResponse{
-		Applications: q("application"),
-		Subdomains:   q("subdomain"),
-		EventTypes:   q("type"),
-	}}, nil
+	// The options span every client, so the one cached answer serves all
+	// callers. A failed column comes back empty, as before, but the answer
+	// isn't cached.
+	opts, _ := s.FilterOptions.Get(ctx, func(ctx context.Context) (EventFilterOptionsResponse, error) {
+		var firstErr error
+		q := func(col string) []EventFilterOption {
+			out, err := s.Repo.DistinctValues(ctx, col, 200)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			return toFilterOptions(out)
+		}
+		return EventFilterOptionsResponse{
+			Applications: q("application"),
+			Subdomains:   q("subdomain"),
+			EventTypes:   q("type"),
+		}, firstErr
+	})
+	return &apicommon.Out[EventFilterOptionsResponse]{Body: opts}, nil
 }
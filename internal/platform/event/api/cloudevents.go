@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/cloudevents"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// maxCloudEventBytes matches huma's default request body limit, which the
+// translated request still passes through.
+const maxCloudEventBytes = 1 << 20
+
+// cloudEventIDKey is the contextData key the CloudEvent's own id is kept
+// under; the platform event gets a fresh TSID like any other ingest.
+const cloudEventIDKey = "cloudEventId"
+
+// CloudEventsIngest lets POST /api/events accept a CloudEvent in either
+// HTTP content mode — structured (Content-Type application/cloudevents+json)
+// or binary (ce-* headers, body = data) — by rewriting it into the native
+// CreateEventRequest before huma decodes the body. Every other request
+// passes through untouched, so auth, quota and validation stay in create.
+//
+// Attribute mapping: type → eventType, source, subject, time; the
+// correlationid / causationid / messagegroup / clientid / deduplicationid
+// extensions → the matching fields; id, dataschema and any other extension
+// → contextData. Without a deduplicationid extension the dedup key is
+// derived from source + id, which the spec defines as the event's identity.
+// Data must be JSON; an event without data is stored with {}.
+func CloudEventsIngest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/events" ||
+			!(cloudevents.IsStructured(r.Header.Get("Content-Type")) || cloudevents.IsBinary(r.Header)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCloudEventBytes+1))
+		if err != nil {
+			httperror.Write(w, httperror.BadRequest("INVALID_CLOUDEVENT", "read body: "+err.Error()))
+			return
+		}
+		if len(body) > maxCloudEventBytes {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			_ = json.NewEncoder(w).Encode(httperror.Envelope{Code: "PAYLOAD_TOO_LARGE", Message: "CloudEvent exceeds " + strconv.Itoa(maxCloudEventBytes) + " bytes"})
+			return
+		}
+		ev, _, err := cloudevents.FromRequest(r.Header, body)
+		if err != nil {
+			httperror.Write(w, httperror.BadRequest("INVALID_CLOUDEVENT", err.Error()))
+			return
+		}
+		req, err := createRequestFromCloudEvent(ev)
+		if err != nil {
+			httperror.Write(w, err)
+			return
+		}
+		native, err := json.Marshal(req)
+		if err != nil {
+			httperror.Write(w, usecase.Internal("ENCODE", "encode translated event", err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(native))
+		r.ContentLength = int64(len(native))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Del("Content-Length")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func createRequestFromCloudEvent(ev *cloudevents.Event) (*CreateEventRequest, error) {
+	if !cloudevents.IsJSON(ev.DataContentType) {
+		return nil, usecase.Validation("UNSUPPORTED_DATA_CONTENT_TYPE",
+			"CloudEvent data must be JSON; got datacontenttype "+ev.DataContentType)
+	}
+	data := json.RawMessage(ev.Data)
+	if len(data) == 0 {
+		data = json.RawMessage(`{}`)
+	} else if !json.Valid(data) {
+		return nil, usecase.Validation("INVALID_CLOUDEVENT", "CloudEvent data is not valid JSON")
+	}
+
+	req := &CreateEventRequest{
+		EventType:   ev.Type,
+		Source:      ev.Source,
+		Subject:     ev.Subject,
+		Data:        data,
+		ContextData: []ContextEntryDTO{{Key: cloudEventIDKey, Value: ev.ID}},
+	}
+	if !ev.Time.IsZero() {
+		t := ev.Time
+		req.Time = &t
+	}
+	if ev.DataSchema != "" {
+		req.ContextData = append(req.ContextData, ContextEntryDTO{Key: "dataschema", Value: ev.DataSchema})
+	}
+	for _, name := range ev.ExtensionNames() {
+		v := ev.Extensions[name]
+		switch name {
+		case "correlationid":
+			req.CorrelationID = &v
+		case "causationid":
+			req.CausationID = &v
+		case "messagegroup":
+			req.MessageGroup = &v
+		case "clientid":
+			req.ClientID = &v
+		case "deduplicationid":
+			req.DeduplicationID = v
+		default:
+			req.ContextData = append(req.ContextData, ContextEntryDTO{Key: name, Value: v})
+		}
+	}
+	if req.DeduplicationID == "" {
+		sum := sha256.Sum256([]byte(ev.Source + "\n" + ev.ID))
+		req.DeduplicationID = "ce:" + hex.EncodeToString(sum[:])
+	}
+	return req, nil
+}
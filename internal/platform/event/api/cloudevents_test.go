@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ingest runs req through CloudEventsIngest and returns the request the
+// next handler saw (nil when the middleware answered itself).
+func ingest(t *testing.T, req *http.Request) (*CreateEventRequest, string, *httptest.ResponseRecorder) {
+	t.Helper()
+	var (
+		got *CreateEventRequest
+		ct  string
+	)
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		got, ct = &CreateEventRequest{}, r.Header.Get("Content-Type")
+		if err := json.Unmarshal(raw, got); err != nil {
+			got.Source = "<not a create request: " + string(raw) + ">"
+		}
+	})
+	rec := httptest.NewRecorder()
+	CloudEventsIngest(next).ServeHTTP(rec, req)
+	return got, ct, rec
+}
+
+func TestCloudEventsIngest_Structured(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(`{
+		"specversion": "1.0", "id": "A-1", "source": "/orders", "type": "orders:fulfilment:order:created",
+		"subject": "o-1", "time": "2026-03-01T09:00:00Z", "dataschema": "https://schemas.example/order",
+		"correlationid": "c-1", "messagegroup": "o-1", "traceparent": "00-abc", "data": {"total": 12}
+	}`))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	got, ct, _ := ingest(t, req)
+	require.NotNil(t, got)
+	assert.Equal(t, "application/json", ct)
+	assert.Equal(t, "orders:fulfilment:order:created", got.EventType)
+	assert.Equal(t, "/orders", got.Source)
+	assert.Equal(t, "o-1", got.Subject)
+	require.NotNil(t, got.Time)
+	assert.True(t, got.Time.Equal(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)))
+	assert.JSONEq(t, `{"total":12}`, string(got.Data))
+	assert.Equal(t, "c-1", *got.CorrelationID)
+	assert.Equal(t, "o-1", *got.MessageGroup)
+	assert.Equal(t, []ContextEntryDTO{
+		{Key: "cloudEventId", Value: "A-1"},
+		{Key: "dataschema", Value: "https://schemas.example/order"},
+		{Key: "traceparent", Value: "00-abc"},
+	}, got.ContextData)
+	assert.True(t, strings.HasPrefix(got.DeduplicationID, "ce:"), "redelivery of the same source+id dedups")
+}
+
+func TestCloudEventsIngest_Binary(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(`{"total":12}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", "A-1")
+	req.Header.Set("ce-source", "/orders")
+	req.Header.Set("ce-type", "orders:fulfilment:order:created")
+	req.Header.Set("ce-deduplicationid", "order-1-created")
+
+	got, _, _ := ingest(t, req)
+	require.NotNil(t, got)
+	assert.JSONEq(t, `{"total":12}`, string(got.Data))
+	assert.Equal(t, "order-1-created", got.DeduplicationID)
+	assert.Nil(t, got.Time)
+}
+
+func TestCloudEventsIngest_Rejects(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(`<order/>`))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", "A-1")
+	req.Header.Set("ce-source", "/orders")
+	req.Header.Set("ce-type", "t")
+	got, _, rec := ingest(t, req)
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "UNSUPPORTED_DATA_CONTENT_TYPE")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(`{"specversion":"1.0","id":"1"}`))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	got, _, rec = ingest(t, req)
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_CLOUDEVENT")
+}
+
+func TestCloudEventsIngest_PassesThroughNativeRequests(t *testing.T) {
+	body := `{"type":"t","source":"s","data":{}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	got, _, _ := ingest(t, req)
+	require.NotNil(t, got)
+	assert.Equal(t, "s", got.Source)
+	assert.Empty(t, got.DeduplicationID, "native requests are not rewritten")
+}
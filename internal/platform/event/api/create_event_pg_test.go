@@ -5,9 +5,11 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2/humatest"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -15,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/payloadlimit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
@@ -197,3 +200,69 @@ func TestCreateEvent_BadPayloadEnvelopeMatchesBatch(t *testing.T) {
 	assert.True(t, strings.Contains(senv.Message, "validation failed") || senv.Message != "",
 		"message must be populated")
 }
+
+// TestCreateEvent_PayloadTooLarge pins the 413 on both ingest paths: the
+// singular create and a batch item, with the batch naming the offending
+// item.
+func TestCreateEvent_PayloadTooLarge(t *testing.T) {
+	ctx := anchorCtx()
+	s := &State{
+		Repo: event.NewRepository(testpg.Pool(t)),
+		Payloads: payloadlimit.New(payloadlimit.Policy{
+			MaxBytes:    1024,
+			ByEventType: map[string]int{"it:limits:event:tiny": 8},
+		}, nil),
+	}
+
+	_, err := s.create(ctx, &apicommon.In[CreateEventRequest]{Body: CreateEventRequest{
+		EventType: "it:limits:event:tiny", Source: "test://limits",
+		Data: json.RawMessage(`{"k":"too long"}`),
+	}})
+	var tl *payloadlimit.TooLargeError
+	require.ErrorAs(t, err, &tl)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, tl.GetStatus())
+	assert.Equal(t, "eventType", tl.Details["scope"])
+
+	_, err = s.batchIngest(ctx, &apicommon.In[BatchRequest]{Body: BatchRequest{Items: []BatchEventItem{
+		{Type: "it:limits:event:other", Source: "test://limits", Data: json.RawMessage(`{"k":"fine"}`)},
+		{Type: "it:limits:event:tiny", Source: "test://limits", Data: json.RawMessage(`{"k":"too long"}`)},
+	}}})
+	require.ErrorAs(t, err, &tl)
+	assert.Equal(t, 1, tl.Details["index"])
+}
+
+// TestCreateEvent_SourceEventIDDedup pins the dedup-hit path: a re-send
+// with the same sourceEventId answers 200 + isDuplicate=true with the
+// first event and inserts nothing.
+func TestCreateEvent_SourceEventIDDedup(t *testing.T) {
+	ctx := anchorCtx()
+	pool := testpg.Pool(t)
+	s := &State{Repo: event.NewRepository(pool), Dedup: event.NewDedup(event.DedupConfig{Window: time.Hour}, pool)}
+
+	src := fmt.Sprintf("order-%d", time.Now().UnixNano())
+	send := func(data string) *IngestOutput[CreateEventResponse] {
+		t.Helper()
+		out, err := s.create(ctx, &apicommon.In[CreateEventRequest]{Body: CreateEventRequest{
+			EventType:     "it:singular:event:dedup",
+			Source:        "test://dedup",
+			Data:          json.RawMessage(data),
+			SourceEventID: src,
+		}})
+		require.NoError(t, err)
+		return out
+	}
+	first := send(`{"n":1}`)
+	assert.Equal(t, http.StatusCreated, first.Status)
+	assert.False(t, first.Body.IsDuplicate)
+
+	again := send(`{"n":2}`)
+	assert.Equal(t, http.StatusOK, again.Status)
+	assert.True(t, again.Body.IsDuplicate)
+	assert.Equal(t, first.Body.Event.ID, again.Body.Event.ID)
+	assert.JSONEq(t, `{"n":1}`, string(again.Body.Event.Data))
+
+	var n int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT count(*) FROM msg_events WHERE type = 'it:singular:event:dedup' AND data->>'n' = '2'`).Scan(&n))
+	assert.Zero(t, n, "the re-send is not inserted")
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+)
+
+// IngestOutput is an ingest response plus the deprecation warning headers,
+// set only when the request published a deprecated event type or schema
+// version. Producers keep working; the headers tell them to migrate.
+// Status is 201, or 200 when a deduplicated create returns an existing
+// event.
+type IngestOutput[T any] struct {
+	Status               int
+	Deprecation          string `header:"Deprecation" doc:"Earliest deprecation date among the published types, as @<unix seconds> (RFC 9745)"`
+	Sunset               string `header:"Sunset" doc:"Earliest sunset date among the published types, as an HTTP-date (RFC 8594)"`
+	DeprecatedEventTypes string `header:"X-Deprecated-Event-Types" doc:"Comma-separated deprecated code or code@version entries published by this request"`
+	Body                 T
+}
+
+// trackEventTypes records the caller as a producer of each published
+// (type, schema version) and returns the warning headers for any that are
+// deprecated. A no-op when no tracker is wired.
+func (s *State) trackEventTypes(ctx context.Context, principalID string, events []event.Event) deprecationWarning {
+	if s.EventTypes == nil {
+		return deprecationWarning{}
+	}
+	type key struct{ code, version string }
+	counts := map[key]int{}
+	for i := range events {
+		counts[key{events[i].Type, events[i].SpecVersion}]++
+	}
+	var deps []eventtype.Deprecation
+	for k, n := range counts {
+		s.EventTypes.Record(k.code, k.version, principalID, n)
+		if d := s.EventTypes.Deprecation(ctx, k.code, k.version); d != nil {
+			deps = append(deps, *d)
+		}
+	}
+	return warningFor(deps)
+}
+
+// deprecationWarning is the header values for IngestOutput.
+type deprecationWarning struct {
+	deprecation, sunset, types string
+}
+
+// warningFor folds the deprecations hit by one request into header values:
+// the earliest deprecation, the earliest sunset, and the sorted list of
+// offending entries.
+func warningFor(deps []eventtype.Deprecation) deprecationWarning {
+	if len(deps) == 0 {
+		return deprecationWarning{}
+	}
+	var since, sunset time.Time
+	names := make([]string, 0, len(deps))
+	for _, d := range deps {
+		if since.IsZero() || d.Since.Before(since) {
+			since = d.Since
+		}
+		if d.Sunset != nil && (sunset.IsZero() || d.Sunset.Before(sunset)) {
+			sunset = *d.Sunset
+		}
+		name := d.Code
+		if d.Version != "" {
+			name += "@" + d.Version
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := deprecationWarning{
+		deprecation: "@" + strconv.FormatInt(since.Unix(), 10),
+		types:       strings.Join(names, ", "),
+	}
+	if !sunset.IsZero() {
+		w.sunset = sunset.UTC().Format(http.TimeFormat)
+	}
+	return w
+}
+
+func ingestOutput[T any](w deprecationWarning, body T) *IngestOutput[T] {
+	return &IngestOutput[T]{
+		Status:               http.StatusCreated,
+		Deprecation:          w.deprecation,
+		Sunset:               w.sunset,
+		DeprecatedEventTypes: w.types,
+		Body:                 body,
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+)
+
+func TestWarningFor(t *testing.T) {
+	assert.Equal(t, deprecationWarning{}, warningFor(nil))
+
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sunset := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	later := sunset.AddDate(0, 1, 0)
+	w := warningFor([]eventtype.Deprecation{
+		{Code: "orders:sales:order:placed", Since: since.Add(time.Hour), Sunset: &later},
+		{Code: "orders:sales:order:shipped", Version: "1.0", Since: since},
+		{Code: "orders:sales:order:cancelled", Since: since.Add(2 * time.Hour), Sunset: &sunset},
+	})
+	assert.Equal(t, deprecationWarning{
+		deprecation: "@1767323045",
+		sunset:      "Mon, 01 Jun 2026 00:00:00 GMT",
+		types:       "orders:sales:order:cancelled, orders:sales:order:placed, orders:sales:order:shipped@1.0",
+	}, w)
+}
@@ -3,6 +3,7 @@ package api
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
@@ -185,10 +186,12 @@ type CreateEventRequest struct {
 	Source          string            `json:"source" doc:"Event source URI"`
 	Subject         string            `json:"subject,omitempty" doc:"Event subject (optional context)"`
 	Data            json.RawMessage   `json:"data" doc:"Event payload data"`
+	Time            *time.Time        `json:"time,omitempty" doc:"When the event occurred (RFC 3339); defaults to receipt time"`
 	MessageGroup    *string           `json:"messageGroup,omitempty" doc:"Message group for FIFO ordering"`
 	CorrelationID   *string           `json:"correlationId,omitempty" doc:"Correlation ID for request tracing"`
 	CausationID     *string           `json:"causationId,omitempty" doc:"Causation ID - the event that caused this event"`
 	DeduplicationID string            `json:"deduplicationId,omitempty" doc:"Deduplication ID for exactly-once delivery"`
+	SourceEventID   string            `json:"sourceEventId,omitempty" maxLength:"200" doc:"Producer's ID for the logical event. A re-send with the same clientId, eventType and sourceEventId inside the platform's dedup window returns the first event (200, isDuplicate=true) instead of creating another"`
 	ClientID        *string           `json:"clientId,omitempty" doc:"Client ID (optional, defaults to caller's client)"`
 	ContextData     []ContextEntryDTO `json:"contextData,omitempty" doc:"Context data for filtering/searching"`
 }
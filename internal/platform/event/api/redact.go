@@ -0,0 +1,56 @@
+// redact.go applies the BFF field redaction (shared/redact) to the event
+// wire shapes. Only the /bff mounts are wrapped.
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/redact"
+)
+
+// maskedData is the JSON string that stands in for a redacted payload.
+var maskedData, _ = json.Marshal(redact.Mask)
+
+// redacted wraps a read handler so its response is masked for the caller.
+func redacted[I, T any](s *State, h func(context.Context, *I) (*apicommon.Out[T], error), apply func(*T, redact.Fields)) func(context.Context, *I) (*apicommon.Out[T], error) {
+	return func(ctx context.Context, in *I) (*apicommon.Out[T], error) {
+		out, err := h(ctx, in)
+		if err != nil || out == nil {
+			return out, err
+		}
+		apply(&out.Body, s.Redaction.For(auth.FromContext(ctx), redact.Events))
+		return out, nil
+	}
+}
+
+func (e *EventResponse) redact(f redact.Fields) {
+	if f.Has(redact.FieldData) && len(e.Data) > 0 {
+		e.Data = maskedData
+	}
+	if f.Has(redact.FieldContextData) {
+		maskContext(e.Context)
+	}
+}
+
+func redactRawList(items *[]RawEventResponse, f redact.Fields) {
+	for i := range *items {
+		e := &(*items)[i]
+		if f.Has(redact.FieldData) && len(e.Data) > 0 {
+			e.Data = maskedData
+		}
+		if f.Has(redact.FieldContextData) {
+			maskContext(e.ContextData)
+		}
+	}
+}
+
+// maskContext keeps the keys (correlation hints useful for triage) and
+// masks the values.
+func maskContext(entries []ContextEntryDTO) {
+	for i := range entries {
+		entries[i].Value = redact.Mask
+	}
+}
@@ -0,0 +1,188 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// ErrDedupInFlight is Claim's answer when the key is held by an event
+// that isn't in msg_events: the first send is still being inserted.
+var ErrDedupInFlight = errors.New("event with this source event id is still being ingested")
+
+// DedupConfig holds the ingest deduplication knobs (env-overridable).
+type DedupConfig struct {
+	// Window is how long a source event ID keeps returning the first
+	// event. 0 disables deduplication.
+	Window time.Duration
+	// PurgeInterval is how often expired keys are deleted.
+	PurgeInterval time.Duration
+}
+
+// DedupConfigFromEnv reads FC_EVENT_DEDUP_WINDOW_SECS.
+func DedupConfigFromEnv() DedupConfig {
+	secs := envutil.Int("FC_EVENT_DEDUP_WINDOW_SECS", 0)
+	if secs < 0 {
+		secs = 0
+	}
+	return DedupConfig{Window: time.Duration(secs) * time.Second, PurgeInterval: time.Minute}
+}
+
+// DedupKey identifies a logical event across re-sends. ClientID is ""
+// for platform-scoped events. Environment keeps a sandbox re-play of
+// production IDs from being answered with production events; zero reads
+// as PRODUCTION.
+type DedupKey struct {
+	ClientID      string
+	Environment   common.Environment
+	EventType     string
+	SourceEventID string
+}
+
+func (k DedupKey) environment() string {
+	return string(common.ParseEnvironment(string(k.Environment)))
+}
+
+// Dedup suppresses re-sent events on ingest: the first event for a
+// DedupKey claims it in msg_event_dedup for the window, and later sends
+// get that event back instead of inserting another. The zero of *Dedup
+// (nil) is usable and deduplicates nothing.
+type Dedup struct {
+	cfg  DedupConfig
+	pool *pgxpool.Pool
+	repo *Repository
+	now  func() time.Time
+
+	hits, misses atomic.Uint64
+}
+
+// NewDedup wires deduplication over pool.
+func NewDedup(cfg DedupConfig, pool *pgxpool.Pool) *Dedup {
+	return &Dedup{cfg: cfg, pool: pool, repo: NewRepository(pool), now: time.Now}
+}
+
+// Enabled reports whether ingest deduplicates.
+func (d *Dedup) Enabled() bool { return d != nil && d.cfg.Window > 0 }
+
+// Claim claims k for ev, about to be inserted. It returns nil when the
+// claim is ev's (insert it, and Release the claim if that fails), or the
+// event already ingested under k inside the window. ErrDedupInFlight
+// means the claim's holder hasn't reached msg_events yet.
+func (d *Dedup) Claim(ctx context.Context, k DedupKey, ev *Event) (*Event, error) {
+	now := d.now().UTC()
+	// An expired claim is taken over in place; a live one is left alone
+	// and no row comes back.
+	const claimSQL = `INSERT INTO msg_event_dedup
+	     (client_id, event_type, source_event_id, event_id, event_created_at, created_at, expires_at, environment)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	 ON CONFLICT (client_id, environment, event_type, source_event_id) DO UPDATE
+	    SET event_id = EXCLUDED.event_id, event_created_at = EXCLUDED.event_created_at,
+	        created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+	  WHERE msg_event_dedup.expires_at <= EXCLUDED.created_at
+	 RETURNING event_id`
+	// The holder found on conflict can be purged before it is read; the
+	// claim is then retried.
+	for range 3 {
+		var claimed string
+		err := d.pool.QueryRow(ctx, claimSQL,
+			k.ClientID, k.EventType, k.SourceEventID, ev.ID, ev.CreatedAt, now, now.Add(d.cfg.Window), k.environment()).
+			Scan(&claimed)
+		if err == nil {
+			d.misses.Add(1)
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("event dedup claim: %w", err)
+		}
+		var id string
+		var createdAt time.Time
+		err = d.pool.QueryRow(ctx,
+			`SELECT event_id, event_created_at FROM msg_event_dedup
+			  WHERE client_id = $1 AND event_type = $2 AND source_event_id = $3 AND expires_at > $4
+			    AND environment = $5`,
+			k.ClientID, k.EventType, k.SourceEventID, now, k.environment()).Scan(&id, &createdAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event dedup lookup: %w", err)
+		}
+		d.hits.Add(1)
+		existing, err := d.repo.FindRawByID(ctx, id, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("event dedup lookup: %w", err)
+		}
+		if existing == nil {
+			return nil, ErrDedupInFlight
+		}
+		return existing, nil
+	}
+	return nil, fmt.Errorf("event dedup claim: key %s/%s kept changing hands", k.EventType, k.SourceEventID)
+}
+
+// Release drops eventID's claim on k, after its insert failed, so a
+// retry isn't answered with an event that doesn't exist.
+func (d *Dedup) Release(ctx context.Context, k DedupKey, eventID string) {
+	if _, err := d.pool.Exec(ctx,
+		`DELETE FROM msg_event_dedup
+		  WHERE client_id = $1 AND event_type = $2 AND source_event_id = $3 AND event_id = $4
+		    AND environment = $5`,
+		k.ClientID, k.EventType, k.SourceEventID, eventID, k.environment()); err != nil {
+		slog.Warn("event dedup: release failed", "event_id", eventID, "err", err)
+	}
+}
+
+// PurgeExpired deletes keys past their window.
+func (d *Dedup) PurgeExpired(ctx context.Context) (int64, error) {
+	tag, err := d.pool.Exec(ctx, `DELETE FROM msg_event_dedup WHERE expires_at <= $1`, d.now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Run purges expired keys every PurgeInterval until ctx is done. Keys
+// outlive a window shortened by a restart until then; they just can't
+// match.
+func (d *Dedup) Run(ctx context.Context) {
+	if !d.Enabled() {
+		return
+	}
+	t := time.NewTicker(d.cfg.PurgeInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if n, err := d.PurgeExpired(ctx); err != nil {
+				slog.Warn("event dedup: purge failed", "err", err)
+			} else if n > 0 {
+				slog.Debug("event dedup purge", "removed", n)
+			}
+		}
+	}
+}
+
+var dedupDesc = prometheus.NewDesc("fc_event_dedup_requests_total",
+	"Ingest requests carrying a source event ID, by result: hit (a re-send answered with the first event) or miss.",
+	[]string{"result"}, nil)
+
+// Describe is a no-op (unchecked const-metric collector).
+func (d *Dedup) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits the hit and miss counts.
+func (d *Dedup) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(dedupDesc, prometheus.CounterValue, float64(d.hits.Load()), "hit")
+	ch <- prometheus.MustNewConstMetric(dedupDesc, prometheus.CounterValue, float64(d.misses.Load()), "miss")
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+// TestDedup_ClaimLifecycle walks a key through a first claim, a re-send
+// racing the insert (in flight), a re-send after it (the first event
+// back), a released claim and an expired one.
+func TestDedup_ClaimLifecycle(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := event.NewRepository(pool)
+	d := event.NewDedup(event.DedupConfig{Window: time.Hour}, pool)
+	require.True(t, d.Enabled())
+
+	key := event.DedupKey{EventType: "dedup.test.event", SourceEventID: fmt.Sprintf("src-%d", time.Now().UnixNano())}
+	first := event.New(key.EventType, "test://dedup", "", json.RawMessage(`{"n":1}`))
+	existing, err := d.Claim(ctx, key, first)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "first send claims the key")
+
+	resend := event.New(key.EventType, "test://dedup", "", json.RawMessage(`{"n":2}`))
+	_, err = d.Claim(ctx, key, resend)
+	assert.ErrorIs(t, err, event.ErrDedupInFlight, "first event not inserted yet")
+
+	_, err = repo.InsertBatch(ctx, []event.Event{*first})
+	require.NoError(t, err)
+	existing, err = d.Claim(ctx, key, resend)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, first.ID, existing.ID)
+	assert.JSONEq(t, `{"n":1}`, string(existing.Data))
+
+	// Another client's event with the same source id is its own.
+	other := key
+	other.ClientID = "clt_dedupother01"
+	existing, err = d.Claim(ctx, other, resend)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	d.Release(ctx, other, resend.ID)
+	existing, err = d.Claim(ctx, other, resend)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "a released claim can be taken again")
+
+	short := event.NewDedup(event.DedupConfig{Window: time.Millisecond}, pool)
+	expKey := event.DedupKey{EventType: key.EventType, SourceEventID: key.SourceEventID + "-exp"}
+	_, err = short.Claim(ctx, expKey, first)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	existing, err = short.Claim(ctx, expKey, resend)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "an expired claim is taken over")
+	time.Sleep(5 * time.Millisecond)
+	n, err := short.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, int64(1))
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
@@ -41,10 +42,14 @@ type Event struct {
 	Context         []ContextEntry  `json:"context,omitempty"`
 	DeduplicationID string          `json:"deduplicationId"`
 	ClientID        *string         `json:"clientId,omitempty"`
-	MessageGroup    *string         `json:"messageGroup,omitempty"`
-	CorrelationID   *string         `json:"correlationId,omitempty"`
-	CausationID     *string         `json:"causationId,omitempty"`
-	CreatedAt       time.Time       `json:"createdAt"`
+	// Environment is SANDBOX or PRODUCTION: the environment of the API
+	// key that ingested the event. Fan-out only matches subscriptions in
+	// the same environment.
+	Environment   common.Environment `json:"environment"`
+	MessageGroup  *string            `json:"messageGroup,omitempty"`
+	CorrelationID *string            `json:"correlationId,omitempty"`
+	CausationID   *string            `json:"causationId,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
 
 	// Read-projection fields (msg_events_read). Empty/zero on the write
 	// side; populated by the read queries.
@@ -67,6 +72,7 @@ func New(eventType, source, subject string, data json.RawMessage) *Event {
 		Time:            now,
 		Data:            data,
 		Context:         []ContextEntry{},
+		Environment:     common.EnvironmentProduction,
 		DeduplicationID: eventType + "-" + tsid.GenerateUntyped(),
 		CreatedAt:       now,
 	}
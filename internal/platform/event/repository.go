@@ -10,7 +10,9 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 )
 
 // Repository owns the msg_events (write) + msg_events_read (read)
@@ -31,29 +33,11 @@ func (r *Repository) InsertBatch(ctx context.Context, events []Event) (int, erro
 	}
 	batch := &pgx.Batch{}
 	for _, e := range events {
-		ctxJSON, err := json.Marshal(e.Context)
+		args, err := insertArgs(&e)
 		if err != nil {
-			return 0, fmt.Errorf("marshal context: %w", err)
+			return 0, err
 		}
-		t := e.Time
-		if t.IsZero() {
-			t = e.CreatedAt
-		}
-		// Column set matches the corrected platformsink.Sink shape.
-		// No ON CONFLICT — dedup duplicates bubble as tx failures
-		// (matches Rust; the unique index is composite on
-		// (deduplication_id, created_at), which we can't always infer
-		// across migration profiles).
-		batch.Queue(
-			`INSERT INTO msg_events
-			     (id, spec_version, type, source, subject, time, data,
-			      correlation_id, causation_id, deduplication_id, message_group,
-			      client_id, context_data, created_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9, $10, $11, $12, $13::jsonb, $14)`,
-			e.ID, e.SpecVersion, e.Type, e.Source, e.Subject,
-			t, rawJSON(e.Data),
-			e.CorrelationID, e.CausationID, e.DeduplicationID, e.MessageGroup,
-			e.ClientID, ctxJSON, e.CreatedAt)
+		batch.Queue(insertSQL, args...)
 	}
 	br := r.pool.SendBatch(ctx, batch)
 	defer br.Close()
@@ -70,6 +54,45 @@ func (r *Repository) InsertBatch(ctx context.Context, events []Event) (int, erro
 	return inserted, nil
 }
 
+// InsertTx writes one event inside the caller's transaction, for writers
+// that must commit the event together with their own state (the ingest
+// runner marks its delivery completed in the same transaction).
+func (r *Repository) InsertTx(ctx context.Context, tx pgx.Tx, e *Event) error {
+	args, err := insertArgs(e)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, insertSQL, args...)
+	return err
+}
+
+// insertSQL's column set matches the corrected platformsink.Sink shape.
+// No ON CONFLICT — dedup duplicates bubble as tx failures (matches Rust;
+// the unique index is composite on (deduplication_id, created_at), which
+// we can't always infer across migration profiles).
+const insertSQL = `INSERT INTO msg_events
+     (id, spec_version, type, source, subject, time, data,
+      correlation_id, causation_id, deduplication_id, message_group,
+      client_id, context_data, created_at, environment)
+ VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9, $10, $11, $12, $13::jsonb, $14, $15)`
+
+func insertArgs(e *Event) ([]any, error) {
+	ctxJSON, err := json.Marshal(e.Context)
+	if err != nil {
+		return nil, fmt.Errorf("marshal context: %w", err)
+	}
+	t := e.Time
+	if t.IsZero() {
+		t = e.CreatedAt
+	}
+	return []any{
+		e.ID, e.SpecVersion, e.Type, e.Source, e.Subject,
+		t, rawJSON(e.Data),
+		e.CorrelationID, e.CausationID, e.DeduplicationID, e.MessageGroup,
+		e.ClientID, ctxJSON, e.CreatedAt, string(common.ParseEnvironment(string(e.Environment))),
+	}, nil
+}
+
 // FindByID loads an event from the read table. `context` isn't denormalised
 // into msg_events_read (only msg_events carries it) — Context comes back
 // as an empty slice. Use the dedicated raw endpoint if you need it.
@@ -78,7 +101,7 @@ func (r *Repository) FindByID(ctx context.Context, id string) (*Event, error) {
 		`SELECT id, spec_version, type, source, subject, time, data,
 		        deduplication_id, client_id, message_group, correlation_id,
 		        causation_id, created_at, application, subdomain, aggregate,
-		        projected_at
+		        projected_at, environment
 		   FROM msg_events_read WHERE id = $1`, id)
 }
 
@@ -110,16 +133,22 @@ type FilterParams struct {
 
 	// AccessibleClientIDs: a non-nil pointer scopes results to
 	// platform-scoped events (client_id IS NULL) plus events whose
-	// client_id is in the set; nil means no access scoping (anchor).
+	// client_id is in the set; nil means the calling request's scope
+	// (reqctx.ClientScope — none for anchors and non-request callers).
 	// Mirrors scheduledjob.FilterParams — enforced in SQL so the caller's
 	// clientId/clientIds filters can only ever narrow within the
 	// principal's tenants, never reach across them.
 	AccessibleClientIDs *[]string
+
+	// Environment narrows to SANDBOX or PRODUCTION events; nil for both.
+	// Set from a pinned principal's environment by the list handlers.
+	Environment *string
 }
 
 // FindWithFilters returns events from the read table matching non-nil
 // filters, ordered most-recent first.
 func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Event, error) {
+	p.AccessibleClientIDs = reqctx.ClientScope(ctx, p.AccessibleClientIDs)
 	var f repocommon.Filter
 	f.EqPtr("type", p.Type)
 	f.Any("type", p.Types)
@@ -135,6 +164,7 @@ func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Eve
 	f.Any("aggregate", p.Aggregates)
 	// PrincipalID filter dropped — no backing column on msg_events_read.
 	f.EqPtr("correlation_id", p.CorrelationID)
+	f.EqPtr("environment", p.Environment)
 	if p.Since != nil {
 		f.Clause("created_at >= $%d", *p.Since)
 	}
@@ -144,7 +174,7 @@ func (r *Repository) FindWithFilters(ctx context.Context, p FilterParams) ([]Eve
 	q := `SELECT id, spec_version, type, source, subject, time, data,
 		     deduplication_id, client_id, message_group, correlation_id,
 		     causation_id, created_at, application, subdomain, aggregate,
-		     projected_at
+		     projected_at, environment
 		  FROM msg_events_read` + f.Where() + " ORDER BY created_at DESC"
 	limit := p.Limit
 	if limit <= 0 || limit > 1000 {
@@ -200,6 +230,27 @@ func (r *Repository) FindRecentRaw(ctx context.Context, limit int) ([]Event, err
 	return out, rows.Err()
 }
 
+// FindRawByID loads an event from the write-side msg_events table,
+// including context_data, which sees it as soon as its insert commits
+// (the read table waits for the projection). createdAt prunes the scan
+// to the event's partition. Nil when absent.
+func (r *Repository) FindRawByID(ctx context.Context, id string, createdAt time.Time) (*Event, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, spec_version, type, source, subject, time, data,
+		        deduplication_id, client_id, message_group, correlation_id,
+		        causation_id, context_data, created_at
+		   FROM msg_events
+		  WHERE id = $1 AND created_at = $2`, id, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("event repo: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanRawRow(rows)
+}
+
 // scanRawRow scans a write-side msg_events row, including context_data.
 func scanRawRow(rows pgx.Rows) (*Event, error) {
 	var e Event
@@ -290,12 +341,14 @@ func scanRow(rows pgx.Rows) (*Event, error) {
 	// spec_version is nullable in the schema (like subject/dedup): scan via
 	// a pointer so one NULL row can't 500 the whole list query.
 	var specVersion, subject, dedupID *string
+	var env string
 	if err := rows.Scan(&e.ID, &specVersion, &e.Type, &e.Source, &subject,
 		&e.Time, &dataBytes, &dedupID, &e.ClientID, &e.MessageGroup,
 		&e.CorrelationID, &e.CausationID, &e.CreatedAt,
-		&e.Application, &e.Subdomain, &e.Aggregate, &e.ProjectedAt); err != nil {
+		&e.Application, &e.Subdomain, &e.Aggregate, &e.ProjectedAt, &env); err != nil {
 		return nil, err
 	}
+	e.Environment = common.ParseEnvironment(env)
 	if specVersion != nil {
 		e.SpecVersion = *specVersion
 	}
@@ -7,15 +7,19 @@ package api
 import (
 	"context"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -25,6 +29,9 @@ import (
 type State struct {
 	Repo *eventtype.Repository
 	UoW  *usecasepgx.UnitOfWork
+	// Usage, when set, has its cached lookup dropped after a deprecation
+	// or reinstatement so this instance's ingest warns straight away.
+	Usage *eventtype.UsageTracker
 }
 
 const tag = "event-types"
@@ -36,6 +43,8 @@ func Register(api huma.API, s *State) {
 	g := apiroute.New(api, tag)
 	apiroute.Get(g, "listEventTypes", "/api/event-types", "List event types", s.list)
 	apiroute.Post(g, "createEventType", "/api/event-types", "Create an event type", http.StatusCreated, s.create)
+	apiroute.Get(g, "exportEventTypeSchemas", "/api/event-types/schema-bundle", "Export event-type schemas as a bundle", s.exportSchemas)
+	apiroute.Post(g, "importEventTypeSchemas", "/api/event-types/schema-bundle", "Import a schema bundle exported from another environment", http.StatusOK, s.importSchemas)
 	apiroute.Get(g, "getEventType", "/api/event-types/{id}", "Get an event type by id", s.getByID)
 	apiroute.Get(g, "getEventTypeByCode", "/api/event-types/by-code/{code}", "Get an event type by code", s.getByCode)
 	apiroute.Put(g, "updateEventType", "/api/event-types/{id}", "Update an event type", http.StatusNoContent, s.update)
@@ -44,6 +53,9 @@ func Register(api huma.API, s *State) {
 	// /versions is the Rust-canonical path. Same handler; both paths
 	// remain registered so existing SPA clients on /schemas keep working.
 	apiroute.Post(g, "addEventTypeVersion", "/api/event-types/{id}/versions", "Add a schema version to an event type", http.StatusOK, s.addSchema)
+	apiroute.Get(g, "getEventTypeUsage", "/api/event-types/{id}/usage", "List the subscriptions and producers using an event type", s.usage)
+	apiroute.Post(g, "deprecateEventType", "/api/event-types/{id}/deprecate", "Deprecate an event type with an optional sunset date", http.StatusOK, s.deprecate)
+	apiroute.Post(g, "reinstateEventType", "/api/event-types/{id}/reinstate", "Return a deprecated event type to current", http.StatusOK, s.reinstate)
 }
 
 // ── Handlers ──────────────────────────────────────────────────────────────
@@ -51,7 +63,7 @@ func Register(api huma.API, s *State) {
 type listInput struct {
 	Application string `query:"application" doc:"Filter by application code"`
 	ClientID    string `query:"clientId" doc:"Filter by client id"`
-	Status      string `query:"status" doc:"Filter by status (CURRENT, ARCHIVED)"`
+	Status      string `query:"status" doc:"Filter by status (CURRENT, DEPRECATED, ARCHIVED)"`
 	Subdomain   string `query:"subdomain" doc:"Filter by subdomain"`
 	Aggregate   string `query:"aggregate" doc:"Filter by aggregate"`
 }
@@ -81,6 +93,49 @@ func (s *State) list(ctx context.Context, in *listInput) (*apicommon.Out[EventTy
 	return &apicommon.Out[EventTypeListResponse]{Body: EventTypeListResponse{Items: out}}, nil
 }
 
+type exportSchemasInput struct {
+	Application string `query:"application" doc:"Export only this application's event types"`
+	Status      string `query:"status" doc:"Export only event types in this status (CURRENT, DEPRECATED, ARCHIVED)"`
+}
+
+// exportSchemas bundles the platform-wide production event types. Client-
+// scoped and sandbox types stay behind: an import creates anchor-level
+// production types, so they wouldn't land as what they are.
+func (s *State) exportSchemas(ctx context.Context, in *exportSchemasInput) (*apicommon.Out[SchemaBundle], error) {
+	if err := auth.CanReadEventTypes(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.FindWithFilters(ctx, apicommon.OptStr(in.Application), nil, apicommon.OptStr(in.Status), nil, nil)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_with_filters failed", err)
+	}
+	rows = slices.DeleteFunc(rows, func(et eventtype.EventType) bool {
+		return et.ClientID != nil || et.Environment == common.EnvironmentSandbox
+	})
+	return &apicommon.Out[SchemaBundle]{Body: bundleFromEntities(rows, time.Now().UTC())}, nil
+}
+
+func (s *State) importSchemas(ctx context.Context, in *apicommon.In[ImportSchemaBundleRequest]) (*apicommon.Out[ImportSchemaBundleResponse], error) {
+	if err := auth.CanWriteEventTypes(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.ImportSchemaBundle(s.Repo), in.Body.toCommand(), ec)
+	if err != nil {
+		return nil, err
+	}
+	codes := event.ImportedCodes
+	if codes == nil {
+		codes = []string{}
+	}
+	return &apicommon.Out[ImportSchemaBundleResponse]{Body: ImportSchemaBundleResponse{
+		Created:       event.Created,
+		Updated:       event.Updated,
+		VersionsAdded: event.VersionsAdded,
+		ImportedCodes: codes,
+	}}, nil
+}
+
 type getByIDInput struct {
 	ID string `path:"id" doc:"Event type id (TSID)"`
 }
@@ -132,7 +187,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateEventTypeRequ
 	if err := auth.CanWriteEventTypes(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateEventType(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -149,7 +204,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanWriteEventTypes(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateEventType(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -160,7 +215,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteEventTypes(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteEventType(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -176,7 +231,7 @@ func (s *State) addSchema(ctx context.Context, in *addSchemaInput) (*apicommon.O
 	if err := auth.CanWriteEventTypes(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.AddSchema(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -190,3 +245,74 @@ func (s *State) addSchema(ctx context.Context, in *addSchemaInput) (*apicommon.O
 	// Return the updated event type (1:1 with Rust add_schema_version → EventTypeResponse).
 	return &apicommon.Out[EventTypeResponse]{Body: fromEntity(et)}, nil
 }
+
+func (s *State) usage(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[EventTypeUsageResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanReadEventTypes(ac); err != nil {
+		return nil, err
+	}
+	et, err := s.Repo.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if et == nil {
+		return nil, httperror.NotFound("EventType", in.ID)
+	}
+	if et.ClientID != nil && !ac.CanAccessClient(*et.ClientID) {
+		return nil, httperror.Forbidden("No access to this event type")
+	}
+	subs, err := s.Repo.FindSubscriptionUses(ctx, et.Code)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_subscription_uses failed", err)
+	}
+	producers, err := s.Repo.FindProducers(ctx, et.Code)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_producers failed", err)
+	}
+	visible := auth.FilterClientScoped(ac, subs, func(u *eventtype.SubscriptionUse) *string { return u.ClientID })
+	return &apicommon.Out[EventTypeUsageResponse]{Body: usageFromEntities(et.Code, visible, producers)}, nil
+}
+
+type deprecateInput struct {
+	ID   string `path:"id"`
+	Body DeprecateEventTypeRequest
+}
+
+func (s *State) deprecate(ctx context.Context, in *deprecateInput) (*apicommon.Out[EventTypeResponse], error) {
+	if err := auth.CanWriteEventTypes(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.DeprecateEventType(s.Repo), in.Body.toCommand(in.ID), ec)
+	if err != nil {
+		return nil, err
+	}
+	return s.reload(ctx, in.ID, event.Code)
+}
+
+func (s *State) reinstate(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[EventTypeResponse], error) {
+	if err := auth.CanWriteEventTypes(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.ReinstateEventType(s.Repo), operations.ReinstateCommand{ID: in.ID}, ec)
+	if err != nil {
+		return nil, err
+	}
+	return s.reload(ctx, in.ID, event.Code)
+}
+
+// reload drops code's cached lifecycle and returns the stored event type.
+func (s *State) reload(ctx context.Context, id, code string) (*apicommon.Out[EventTypeResponse], error) {
+	if s.Usage != nil {
+		s.Usage.Invalidate(code)
+	}
+	et, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if et == nil {
+		return nil, httperror.NotFound("EventType", id)
+	}
+	return &apicommon.Out[EventTypeResponse]{Body: fromEntity(et)}, nil
+}
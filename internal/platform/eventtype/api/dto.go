@@ -6,6 +6,7 @@ package api
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
@@ -20,6 +21,7 @@ type CreateEventTypeRequest struct {
 	Description *string         `json:"description,omitempty"`
 	ClientID    *string         `json:"clientId,omitempty" doc:"Optional client scope; absent means anchor-level"`
 	Schema      json.RawMessage `json:"schema,omitempty" doc:"Optional JSON Schema for the initial spec version"`
+	Environment string          `json:"environment,omitempty" enum:"SANDBOX,PRODUCTION" doc:"SANDBOX keeps the type out of production subscriptions until promoted; defaults to PRODUCTION"`
 }
 
 func (r CreateEventTypeRequest) toCommand() operations.CreateCommand {
@@ -29,6 +31,7 @@ func (r CreateEventTypeRequest) toCommand() operations.CreateCommand {
 		Description: r.Description,
 		ClientID:    r.ClientID,
 		Schema:      r.Schema,
+		Environment: r.Environment,
 	}
 }
 
@@ -53,6 +56,17 @@ func (r AddSchemaRequest) toCommand(id string) operations.AddSchemaCommand {
 	return operations.AddSchemaCommand{EventTypeID: id, Version: r.Version, Schema: r.Schema}
 }
 
+// DeprecateEventTypeRequest is the wire body for
+// POST /api/event-types/{id}/deprecate.
+type DeprecateEventTypeRequest struct {
+	SunsetAt *time.Time `json:"sunsetAt,omitempty" doc:"When publishing is expected to stop; must be in the future"`
+	Note     *string    `json:"note,omitempty" doc:"Migration guidance shown to producers and subscribers"`
+}
+
+func (r DeprecateEventTypeRequest) toCommand(id string) operations.DeprecateCommand {
+	return operations.DeprecateCommand{ID: id, SunsetAt: r.SunsetAt, Note: r.Note}
+}
+
 // EventTypeResponse is the wire shape for GET /api/event-types/{id}
 // and POST /api/event-types/{id}/schemas. Mirrors eventtype.EventType
 // with explicit JSON tags so the wire format is stable independent of
@@ -73,6 +87,11 @@ type EventTypeResponse struct {
 	CreatedAt    httpcompat.Time       `json:"createdAt"`
 	UpdatedAt    httpcompat.Time       `json:"updatedAt"`
 	SpecVersions []specVersionResponse `json:"specVersions"`
+
+	DeprecatedAt    *httpcompat.Time `json:"deprecatedAt,omitempty"`
+	SunsetAt        *httpcompat.Time `json:"sunsetAt,omitempty"`
+	DeprecationNote *string          `json:"deprecationNote,omitempty"`
+	Environment     string           `json:"environment"`
 }
 
 type specVersionResponse struct {
@@ -98,6 +117,11 @@ func fromEntity(et *eventtype.EventType) EventTypeResponse {
 		CreatedBy:   et.CreatedBy,
 		CreatedAt:   jsontime.New(et.CreatedAt),
 		UpdatedAt:   jsontime.New(et.UpdatedAt),
+
+		DeprecatedAt:    optTime(et.DeprecatedAt),
+		SunsetAt:        optTime(et.SunsetAt),
+		DeprecationNote: et.DeprecationNote,
+		Environment:     string(et.Environment),
 	}
 	resp.SpecVersions = make([]specVersionResponse, 0, len(et.SpecVersions))
 	for _, sv := range et.SpecVersions {
@@ -111,7 +135,166 @@ func fromEntity(et *eventtype.EventType) EventTypeResponse {
 	return resp
 }
 
+func optTime(t *time.Time) *httpcompat.Time {
+	if t == nil {
+		return nil
+	}
+	v := jsontime.New(*t)
+	return &v
+}
+
 // EventTypeListResponse is the wire shape for GET /api/event-types.
 type EventTypeListResponse struct {
 	Items []EventTypeResponse `json:"items"`
 }
+
+// EventTypeUsageResponse is the wire shape for
+// GET /api/event-types/{id}/usage.
+type EventTypeUsageResponse struct {
+	Code          string                      `json:"code"`
+	Subscriptions []SubscriptionUsageResponse `json:"subscriptions"`
+	Producers     []ProducerUsageResponse     `json:"producers"`
+}
+
+// SubscriptionUsageResponse is a subscription binding selecting the type.
+type SubscriptionUsageResponse struct {
+	SubscriptionID   string  `json:"subscriptionId"`
+	SubscriptionCode string  `json:"subscriptionCode"`
+	SubscriptionName string  `json:"subscriptionName"`
+	Status           string  `json:"status"`
+	Pattern          string  `json:"pattern" doc:"The bound event-type code; may contain * wildcards"`
+	SpecVersion      *string `json:"specVersion,omitempty"`
+}
+
+// ProducerUsageResponse is one principal publishing the type at a schema
+// version. Counts trail ingest by up to the tracker's flush interval.
+type ProducerUsageResponse struct {
+	PrincipalID string          `json:"principalId"`
+	SpecVersion string          `json:"specVersion"`
+	EventCount  int64           `json:"eventCount"`
+	FirstSeenAt httpcompat.Time `json:"firstSeenAt"`
+	LastSeenAt  httpcompat.Time `json:"lastSeenAt"`
+}
+
+func usageFromEntities(code string, subs []eventtype.SubscriptionUse, producers []eventtype.Producer) EventTypeUsageResponse {
+	resp := EventTypeUsageResponse{
+		Code:          code,
+		Subscriptions: make([]SubscriptionUsageResponse, 0, len(subs)),
+		Producers:     make([]ProducerUsageResponse, 0, len(producers)),
+	}
+	for _, u := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, SubscriptionUsageResponse{
+			SubscriptionID:   u.SubscriptionID,
+			SubscriptionCode: u.SubscriptionCode,
+			SubscriptionName: u.SubscriptionName,
+			Status:           u.Status,
+			Pattern:          u.Pattern,
+			SpecVersion:      u.SpecVersion,
+		})
+	}
+	for _, p := range producers {
+		resp.Producers = append(resp.Producers, ProducerUsageResponse{
+			PrincipalID: p.PrincipalID,
+			SpecVersion: p.SpecVersion,
+			EventCount:  p.EventCount,
+			FirstSeenAt: jsontime.New(p.FirstSeenAt),
+			LastSeenAt:  jsontime.New(p.LastSeenAt),
+		})
+	}
+	return resp
+}
+
+// SchemaBundle is the wire shape for GET /api/event-types/schema-bundle:
+// every exported event type with its schema versions, in a form
+// POST /api/event-types/schema-bundle accepts in another environment.
+type SchemaBundle struct {
+	FormatVersion int                     `json:"formatVersion" doc:"Bundle format; imports reject any other"`
+	ExportedAt    httpcompat.Time         `json:"exportedAt"`
+	EventTypes    []SchemaBundleEventType `json:"eventTypes"`
+}
+
+// SchemaBundleEventType is one event type in a schema bundle.
+type SchemaBundleEventType struct {
+	Code        string                `json:"code"`
+	Name        string                `json:"name"`
+	Description *string               `json:"description,omitempty"`
+	Versions    []SchemaBundleVersion `json:"versions"`
+}
+
+// SchemaBundleVersion is one schema version in a schema bundle.
+type SchemaBundleVersion struct {
+	Version    string          `json:"version"`
+	SchemaType string          `json:"schemaType" enum:"JSON_SCHEMA,XSD,PROTO"`
+	MimeType   string          `json:"mimeType"`
+	Status     string          `json:"status" enum:"FINALISING,CURRENT,DEPRECATED"`
+	Schema     json.RawMessage `json:"schema"`
+}
+
+func bundleFromEntities(ets []eventtype.EventType, exportedAt time.Time) SchemaBundle {
+	b := SchemaBundle{
+		FormatVersion: eventtype.SchemaBundleFormatVersion,
+		ExportedAt:    jsontime.New(exportedAt),
+		EventTypes:    make([]SchemaBundleEventType, 0, len(ets)),
+	}
+	for _, et := range ets {
+		item := SchemaBundleEventType{
+			Code:        et.Code,
+			Name:        et.Name,
+			Description: et.Description,
+			Versions:    make([]SchemaBundleVersion, 0, len(et.SpecVersions)),
+		}
+		for _, sv := range et.SpecVersions {
+			item.Versions = append(item.Versions, SchemaBundleVersion{
+				Version:    sv.Version,
+				SchemaType: string(sv.SchemaType),
+				MimeType:   sv.MimeType,
+				Status:     string(sv.Status),
+				Schema:     sv.SchemaContent,
+			})
+		}
+		b.EventTypes = append(b.EventTypes, item)
+	}
+	return b
+}
+
+// ImportSchemaBundleRequest is the wire body for
+// POST /api/event-types/schema-bundle: a bundle as exported.
+type ImportSchemaBundleRequest struct {
+	FormatVersion int                     `json:"formatVersion" doc:"Bundle format; must be 1"`
+	EventTypes    []SchemaBundleEventType `json:"eventTypes"`
+}
+
+func (r ImportSchemaBundleRequest) toCommand() operations.ImportSchemaBundleCommand {
+	cmd := operations.ImportSchemaBundleCommand{
+		FormatVersion: r.FormatVersion,
+		EventTypes:    make([]operations.ImportEventTypeInput, 0, len(r.EventTypes)),
+	}
+	for _, et := range r.EventTypes {
+		in := operations.ImportEventTypeInput{
+			Code:        et.Code,
+			Name:        et.Name,
+			Description: et.Description,
+			Versions:    make([]operations.ImportSchemaVersionInput, 0, len(et.Versions)),
+		}
+		for _, v := range et.Versions {
+			in.Versions = append(in.Versions, operations.ImportSchemaVersionInput{
+				Version:    v.Version,
+				SchemaType: eventtype.SchemaType(v.SchemaType),
+				MimeType:   v.MimeType,
+				Status:     eventtype.SpecVersionStatus(v.Status),
+				Schema:     v.Schema,
+			})
+		}
+		cmd.EventTypes = append(cmd.EventTypes, in)
+	}
+	return cmd
+}
+
+// ImportSchemaBundleResponse is the wire shape for
+// POST /api/event-types/schema-bundle.
+type ImportSchemaBundleResponse struct {
+	Created       uint32   `json:"created" doc:"Event types that didn't exist here"`
+	Updated       uint32   `json:"updated" doc:"Existing event types that gained schema versions"`
+	VersionsAdded uint32   `json:"versionsAdded"`
+	ImportedCodes []string `json:"importedCodes"`
+}
@@ -4,28 +4,37 @@
 package eventtype
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"strings"
 	"time"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
-// EventTypeStatus mirrors the Rust enum (CURRENT | ARCHIVED).
+// EventTypeStatus mirrors the Rust enum (CURRENT | ARCHIVED), plus
+// DEPRECATED: still published and delivered, but on its way out.
 type Status string
 
 const (
-	StatusCurrent  Status = "CURRENT"
-	StatusArchived Status = "ARCHIVED"
+	StatusCurrent    Status = "CURRENT"
+	StatusDeprecated Status = "DEPRECATED"
+	StatusArchived   Status = "ARCHIVED"
 )
 
 // ParseStatus is the lenient parser. Unknown → CURRENT.
 func ParseStatus(s string) Status {
-	if s == string(StatusArchived) {
+	switch s {
+	case string(StatusArchived):
 		return StatusArchived
+	case string(StatusDeprecated):
+		return StatusDeprecated
+	default:
+		return StatusCurrent
 	}
-	return StatusCurrent
 }
 
 // Source identifies where the event type was authored.
@@ -91,6 +100,23 @@ func ParseSchemaType(s string) SchemaType {
 	}
 }
 
+// DefaultMimeType is the mime type a schema of this language is stored
+// with when none is given.
+func (t SchemaType) DefaultMimeType() string {
+	switch t {
+	case SchemaXSD:
+		return "application/xml"
+	case SchemaProto:
+		return "application/x-protobuf"
+	default:
+		return "application/schema+json"
+	}
+}
+
+// SchemaBundleFormatVersion is the version of the schema bundle format
+// written by the export endpoint. Imports reject any other.
+const SchemaBundleFormatVersion = 1
+
 // SpecVersion is a schema version row.
 type SpecVersion struct {
 	ID            string            `json:"id"`
@@ -137,6 +163,30 @@ func majorVersion(v string) string {
 	return v
 }
 
+// SameSchema reports whether content is the version's schema. JSON
+// documents compare by value, since Postgres doesn't keep key order or
+// whitespace.
+func (s *SpecVersion) SameSchema(content json.RawMessage) bool {
+	var a, b any
+	if json.Unmarshal(s.SchemaContent, &a) != nil || json.Unmarshal(content, &b) != nil {
+		return bytes.Equal(s.SchemaContent, content)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// SchemaText is the schema document as text: the JSON itself for a JSON
+// Schema, the string it holds for XSD and proto sources (stored as a JSON
+// string).
+func (s *SpecVersion) SchemaText() string {
+	if s.SchemaType != SchemaJSON {
+		var text string
+		if json.Unmarshal(s.SchemaContent, &text) == nil {
+			return text
+		}
+	}
+	return string(s.SchemaContent)
+}
+
 // Major returns the leading numeric major segment of the version
 // string. Used by the finalise logic to find sibling CURRENT versions
 // that should be auto-deprecated. Exposed so use cases can match
@@ -161,6 +211,15 @@ type EventType struct {
 	CreatedBy    *string       `json:"createdBy,omitempty"`
 	CreatedAt    time.Time     `json:"createdAt"`
 	UpdatedAt    time.Time     `json:"updatedAt"`
+	// DeprecatedAt, SunsetAt and DeprecationNote are set while the type is
+	// DEPRECATED; SunsetAt is the announced removal date, if any.
+	DeprecatedAt    *time.Time `json:"deprecatedAt,omitempty"`
+	SunsetAt        *time.Time `json:"sunsetAt,omitempty"`
+	DeprecationNote *string    `json:"deprecationNote,omitempty"`
+	// Environment is where the type may be subscribed to: a SANDBOX type
+	// is visible only to sandbox subscriptions until [EventType.Promote];
+	// a PRODUCTION type is visible to both.
+	Environment common.Environment `json:"environment"`
 }
 
 // IDStr returns the aggregate ID. Method exists because usecase.HasID
@@ -197,17 +256,98 @@ func New(code, name string) (*EventType, error) {
 		EventName:    parts[3],
 		CreatedAt:    now,
 		UpdatedAt:    now,
+		Environment:  common.EnvironmentProduction,
 	}, nil
 }
 
+// AvailableIn reports whether subscriptions in env may bind to the type.
+func (e *EventType) AvailableIn(env common.Environment) bool {
+	return e.Environment != common.EnvironmentSandbox || env == common.EnvironmentSandbox
+}
+
+// Promote makes a sandbox type available to production. A no-op for a
+// type that already is; reports whether anything changed.
+func (e *EventType) Promote() bool {
+	if e.Environment != common.EnvironmentSandbox {
+		return false
+	}
+	e.Environment = common.EnvironmentProduction
+	e.UpdatedAt = time.Now().UTC()
+	return true
+}
+
 // Archive flips status to ARCHIVED and bumps UpdatedAt.
 func (e *EventType) Archive() {
 	e.Status = StatusArchived
 	e.UpdatedAt = time.Now().UTC()
 }
 
+// SpecVersionFor returns the schema version named version, or nil.
+func (e *EventType) SpecVersionFor(version string) *SpecVersion {
+	for i := range e.SpecVersions {
+		if e.SpecVersions[i].Version == version {
+			return &e.SpecVersions[i]
+		}
+	}
+	return nil
+}
+
 // AddSchemaVersion appends a schema version and bumps UpdatedAt.
 func (e *EventType) AddSchemaVersion(sv SpecVersion) {
 	e.SpecVersions = append(e.SpecVersions, sv)
 	e.UpdatedAt = time.Now().UTC()
 }
+
+// Deprecate flips status to DEPRECATED with an optional sunset date and
+// note. Deprecating an already-deprecated type reschedules it, keeping
+// the original DeprecatedAt.
+func (e *EventType) Deprecate(sunsetAt *time.Time, note *string) {
+	now := time.Now().UTC()
+	if e.Status != StatusDeprecated || e.DeprecatedAt == nil {
+		e.DeprecatedAt = &now
+	}
+	e.Status = StatusDeprecated
+	e.SunsetAt = sunsetAt
+	e.DeprecationNote = note
+	e.UpdatedAt = now
+}
+
+// Reinstate returns a deprecated type to CURRENT and clears its sunset.
+func (e *EventType) Reinstate() {
+	e.Status = StatusCurrent
+	e.DeprecatedAt = nil
+	e.SunsetAt = nil
+	e.DeprecationNote = nil
+	e.UpdatedAt = time.Now().UTC()
+}
+
+// Deprecation describes why publishing or subscribing to an event type
+// (at a schema version) is discouraged.
+type Deprecation struct {
+	Code string
+	// Version is set when only that schema version is deprecated, not
+	// the type.
+	Version string
+	Since   time.Time
+	Sunset  *time.Time
+	Note    *string
+}
+
+// DeprecationFor reports whether publishing the type at schema version
+// is deprecated: the type itself is DEPRECATED, or version names a
+// DEPRECATED schema version. nil when neither.
+func (e *EventType) DeprecationFor(version string) *Deprecation {
+	if e.Status == StatusDeprecated {
+		d := &Deprecation{Code: e.Code, Sunset: e.SunsetAt, Note: e.DeprecationNote, Since: e.UpdatedAt}
+		if e.DeprecatedAt != nil {
+			d.Since = *e.DeprecatedAt
+		}
+		return d
+	}
+	for _, sv := range e.SpecVersions {
+		if sv.Version == version && sv.IsDeprecated() {
+			return &Deprecation{Code: e.Code, Version: version, Since: sv.UpdatedAt}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
 )
 
@@ -67,8 +68,52 @@ func TestArchiveFlipsStatus(t *testing.T) {
 	assert.True(t, et.UpdatedAt.After(before))
 }
 
+func TestDeprecateKeepsOriginalDateAndReinstateClears(t *testing.T) {
+	et, _ := eventtype.New("a:b:c:d", "Name")
+	sunset := time.Now().Add(30 * 24 * time.Hour)
+	note := "use a:b:c:e"
+	et.Deprecate(&sunset, &note)
+	assert.Equal(t, eventtype.StatusDeprecated, et.Status)
+	require.NotNil(t, et.DeprecatedAt)
+	since := *et.DeprecatedAt
+
+	later := sunset.Add(24 * time.Hour)
+	time.Sleep(2 * time.Millisecond)
+	et.Deprecate(&later, nil)
+	assert.Equal(t, since, *et.DeprecatedAt, "rescheduling keeps the original deprecation date")
+	assert.Equal(t, later, *et.SunsetAt)
+	assert.Nil(t, et.DeprecationNote)
+
+	et.Reinstate()
+	assert.Equal(t, eventtype.StatusCurrent, et.Status)
+	assert.Nil(t, et.DeprecatedAt)
+	assert.Nil(t, et.SunsetAt)
+}
+
+func TestDeprecationFor(t *testing.T) {
+	et, _ := eventtype.New("a:b:c:d", "Name")
+	old := eventtype.NewSpecVersion(et.ID, "1.0", nil)
+	old.Status = eventtype.SpecDeprecated
+	et.AddSchemaVersion(old)
+	et.AddSchemaVersion(eventtype.NewSpecVersion(et.ID, "2.0", nil))
+
+	assert.Nil(t, et.DeprecationFor("2.0"))
+	d := et.DeprecationFor("1.0")
+	require.NotNil(t, d)
+	assert.Equal(t, eventtype.Deprecation{Code: "a:b:c:d", Version: "1.0", Since: old.UpdatedAt}, *d)
+
+	sunset := time.Now().Add(time.Hour)
+	et.Deprecate(&sunset, nil)
+	d = et.DeprecationFor("2.0")
+	require.NotNil(t, d, "a deprecated type covers every version")
+	assert.Empty(t, d.Version)
+	assert.Equal(t, &sunset, d.Sunset)
+	assert.Equal(t, *et.DeprecatedAt, d.Since)
+}
+
 func TestStatusRoundTripWithFallback(t *testing.T) {
 	assert.Equal(t, eventtype.StatusCurrent, eventtype.ParseStatus("CURRENT"))
+	assert.Equal(t, eventtype.StatusDeprecated, eventtype.ParseStatus("DEPRECATED"))
 	assert.Equal(t, eventtype.StatusArchived, eventtype.ParseStatus("ARCHIVED"))
 	assert.Equal(t, eventtype.StatusCurrent, eventtype.ParseStatus("UNKNOWN"))
 }
@@ -88,3 +133,40 @@ func TestSchemaTypeAcceptsAliases(t *testing.T) {
 	assert.Equal(t, eventtype.SchemaProto, eventtype.ParseSchemaType("PROTOBUF"))
 	assert.Equal(t, eventtype.SchemaJSON, eventtype.ParseSchemaType("UNKNOWN"))
 }
+
+func TestPromoteAndAvailability(t *testing.T) {
+	et, err := eventtype.New("orders:sales:order:created", "Order Created")
+	require.NoError(t, err)
+	assert.Equal(t, common.EnvironmentProduction, et.Environment)
+	assert.False(t, et.Promote(), "a production type has nothing to promote")
+
+	et.Environment = common.EnvironmentSandbox
+	assert.True(t, et.AvailableIn(common.EnvironmentSandbox))
+	assert.False(t, et.AvailableIn(common.EnvironmentProduction))
+
+	assert.True(t, et.Promote())
+	assert.Equal(t, common.EnvironmentProduction, et.Environment)
+	assert.True(t, et.AvailableIn(common.EnvironmentSandbox))
+	assert.True(t, et.AvailableIn(common.EnvironmentProduction))
+}
+
+func TestSameSchemaComparesJSONByValue(t *testing.T) {
+	sv := eventtype.NewSpecVersion("et_1", "1.0", []byte(`{"type": "object", "required": ["id"]}`))
+	assert.True(t, sv.SameSchema([]byte(`{"required":["id"],"type":"object"}`)), "key order and whitespace don't matter")
+	assert.False(t, sv.SameSchema([]byte(`{"type":"object"}`)))
+
+	et, err := eventtype.New("orders:fulfillment:shipment:shipped", "Shipment Shipped")
+	require.NoError(t, err)
+	et.AddSchemaVersion(sv)
+	require.NotNil(t, et.SpecVersionFor("1.0"))
+	assert.Nil(t, et.SpecVersionFor("2.0"))
+}
+
+func TestSchemaText(t *testing.T) {
+	js := eventtype.NewSpecVersion("et_1", "1.0", []byte(`{"type":"object"}`))
+	assert.Equal(t, `{"type":"object"}`, js.SchemaText())
+
+	xsd := eventtype.NewSpecVersion("et_1", "1.0", []byte(`"<xs:schema/>"`))
+	xsd.SchemaType = eventtype.SchemaXSD
+	assert.Equal(t, "<xs:schema/>", xsd.SchemaText(), "non-JSON sources are stored as a JSON string")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -19,6 +20,7 @@ type CreateCommand struct {
 	Description *string         `json:"description,omitempty"`
 	ClientID    *string         `json:"clientId,omitempty"`
 	Schema      json.RawMessage `json:"schema,omitempty"`
+	Environment string          `json:"environment,omitempty"`
 }
 
 // CreateEventType validates cmd, enforces per-resource client scope,
@@ -46,6 +48,9 @@ func CreateEventType(repo *eventtype.Repository) usecaseop.Operation[CreateComma
 						fmt.Sprintf("Event type code part '%s' cannot be empty", partNames[i]))
 				}
 			}
+			if cmd.Environment != "" && !common.Environment(cmd.Environment).Valid() {
+				return usecase.Validation("INVALID_ENVIRONMENT", "environment must be SANDBOX or PRODUCTION")
+			}
 			return nil
 		},
 		// Resource-level authorization (the coarse "may create event types"
@@ -53,9 +58,14 @@ func CreateEventType(repo *eventtype.Repository) usecaseop.Operation[CreateComma
 		// requested clientId (a cmd field, so it lives here): a non-anchor
 		// principal may only create event types within a client it can
 		// access, and anchor-level (nil clientId) creates are anchor-only.
-		// This is exactly auth.CheckScopeAccess on the target client.
+		// This is exactly auth.CheckScopeAccess on the target client. A key
+		// pinned to one environment may not create types in the other.
 		Authorize: func(ctx context.Context, cmd CreateCommand) error {
-			return auth.CheckScopeAccess(auth.FromContext(ctx), cmd.ClientID)
+			a := auth.FromContext(ctx)
+			if err := auth.CheckScopeAccess(a, cmd.ClientID); err != nil {
+				return err
+			}
+			return auth.CheckRequestedEnvironment(a, cmd.Environment)
 		},
 		Execute: func(ctx context.Context, cmd CreateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[EventTypeCreated], error) {
 			existing, err := repo.FindByCode(ctx, cmd.Code)
@@ -74,6 +84,7 @@ func CreateEventType(repo *eventtype.Repository) usecaseop.Operation[CreateComma
 			et.Description = cmd.Description
 			et.ClientID = cmd.ClientID
 			et.CreatedBy = &ec.PrincipalID
+			et.Environment = auth.FromContext(ctx).EnvironmentFor(cmd.Environment)
 			if len(cmd.Schema) > 0 {
 				et.AddSchemaVersion(eventtype.NewSpecVersion(et.ID, "1.0", cmd.Schema))
 			}
@@ -0,0 +1,119 @@
+package operations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// DeprecateCommand is the input DTO for DeprecateEventType.
+type DeprecateCommand struct {
+	ID       string     `json:"id"`
+	SunsetAt *time.Time `json:"sunsetAt,omitempty"`
+	Note     *string    `json:"note,omitempty"`
+}
+
+// DeprecateEventType transitions an event type CURRENT → DEPRECATED with
+// an optional sunset date and emits an [EventTypeDeprecated] event.
+// Deprecating an already-deprecated type reschedules its sunset. Archived
+// types can't be deprecated, and a sunset must lie in the future.
+func DeprecateEventType(repo *eventtype.Repository) usecaseop.Operation[DeprecateCommand, EventTypeDeprecated] {
+	return usecaseop.Operation[DeprecateCommand, EventTypeDeprecated]{
+		Name: "DeprecateEventType",
+		Validate: func(_ context.Context, cmd DeprecateCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			if cmd.SunsetAt != nil && !cmd.SunsetAt.After(time.Now()) {
+				return usecase.Validation("SUNSET_IN_PAST", "sunsetAt must be in the future")
+			}
+			return nil
+		},
+		// Per-resource authz runs post-load in Execute; the coarse write
+		// permission is on the controller.
+		Authorize: usecaseop.Public[DeprecateCommand],
+		Execute: func(ctx context.Context, cmd DeprecateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[EventTypeDeprecated], error) {
+			et, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if et == nil {
+				return nil, httperror.NotFound("EventType", cmd.ID)
+			}
+			if err := auth.CheckScopeAccess(auth.FromContext(ctx), et.ClientID); err != nil {
+				return nil, err
+			}
+			if et.Status == eventtype.StatusArchived {
+				return nil, usecase.Conflict("ARCHIVED",
+					"Event type '"+et.Code+"' is archived and cannot be deprecated")
+			}
+
+			var sunset *time.Time
+			if cmd.SunsetAt != nil {
+				t := cmd.SunsetAt.UTC()
+				sunset = &t
+			}
+			et.Deprecate(sunset, cmd.Note)
+
+			event := EventTypeDeprecated{
+				Metadata:    usecase.NewEventMetadata(ec, EventTypeDeprecatedType, EventTypeSourceConst, subjectFor(et.ID)),
+				EventTypeID: et.ID,
+				Code:        et.Code,
+				SunsetAt:    et.SunsetAt,
+				Note:        et.DeprecationNote,
+			}
+			return usecaseop.Save(et, repo, event), nil
+		},
+	}
+}
+
+// ReinstateCommand is the input DTO for ReinstateEventType.
+type ReinstateCommand struct {
+	ID string `json:"id"`
+}
+
+// ReinstateEventType returns a DEPRECATED event type to CURRENT, clearing
+// its sunset, and emits an [EventTypeReinstated] event.
+func ReinstateEventType(repo *eventtype.Repository) usecaseop.Operation[ReinstateCommand, EventTypeReinstated] {
+	return usecaseop.Operation[ReinstateCommand, EventTypeReinstated]{
+		Name: "ReinstateEventType",
+		Validate: func(_ context.Context, cmd ReinstateCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[ReinstateCommand],
+		Execute: func(ctx context.Context, cmd ReinstateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[EventTypeReinstated], error) {
+			et, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if et == nil {
+				return nil, httperror.NotFound("EventType", cmd.ID)
+			}
+			if err := auth.CheckScopeAccess(auth.FromContext(ctx), et.ClientID); err != nil {
+				return nil, err
+			}
+			if et.Status != eventtype.StatusDeprecated {
+				return nil, usecase.Conflict("NOT_DEPRECATED",
+					"Event type '"+et.Code+"' is not deprecated")
+			}
+
+			et.Reinstate()
+
+			event := EventTypeReinstated{
+				Metadata:    usecase.NewEventMetadata(ec, EventTypeReinstatedType, EventTypeSourceConst, subjectFor(et.ID)),
+				EventTypeID: et.ID,
+				Code:        et.Code,
+			}
+			return usecaseop.Save(et, repo, event), nil
+		},
+	}
+}
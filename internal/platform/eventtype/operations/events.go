@@ -58,10 +58,15 @@ const (
 	EventTypeUpdatedType          = "platform:admin:eventtype:updated"
 	EventTypeDeletedType          = "platform:admin:eventtype:deleted"
 	EventTypeArchivedType         = "platform:admin:eventtype:archived"
+	EventTypeDeprecatedType       = "platform:admin:eventtype:deprecated"
+	EventTypeReinstatedType       = "platform:admin:eventtype:reinstated"
+	EventTypePromotedType         = "platform:admin:eventtype:promoted"
 	EventTypeSchemaAddedType      = "platform:admin:eventtype:schema-added"
 	EventTypeSchemaFinalisedType  = "platform:admin:eventtype:schema-finalised"
 	EventTypeSchemaDeprecatedType = "platform:admin:eventtype:schema-deprecated"
+	EventTypeSchemasImportedType  = "platform:admin:eventtype:schemas-imported"
 	EventTypesSyncedType          = "platform:admin:eventtypes:synced"
+	EventTypesImportedType        = "platform:admin:eventtypes:imported"
 	EventTypeSourceConst          = "platform:admin"
 )
 
@@ -269,3 +274,157 @@ func (e EventTypeSchemaDeprecated) ToDataJSON() ([]byte, error) {
 		Version     string `json:"specVersion"`
 	}{e.EventTypeID, e.Version})
 }
+
+// EventTypeDeprecated is emitted when an event type transitions
+// CURRENT → DEPRECATED, or its sunset is rescheduled.
+type EventTypeDeprecated struct {
+	Metadata    usecase.EventMetadata
+	EventTypeID string
+	Code        string
+	SunsetAt    *time.Time
+	Note        *string
+}
+
+func (e EventTypeDeprecated) EventID() string       { return e.Metadata.EventID }
+func (e EventTypeDeprecated) EventType() string     { return EventTypeDeprecatedType }
+func (e EventTypeDeprecated) SpecVersion() string   { return "1.0" }
+func (e EventTypeDeprecated) Source() string        { return EventTypeSourceConst }
+func (e EventTypeDeprecated) Subject() string       { return subjectFor(e.EventTypeID) }
+func (e EventTypeDeprecated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e EventTypeDeprecated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e EventTypeDeprecated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e EventTypeDeprecated) CausationID() string   { return e.Metadata.CausationID }
+func (e EventTypeDeprecated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e EventTypeDeprecated) MessageGroup() string  { return e.Metadata.MessageGroup }
+func (e EventTypeDeprecated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		EventTypeID string     `json:"eventTypeId"`
+		Code        string     `json:"code"`
+		SunsetAt    *time.Time `json:"sunsetAt,omitempty"`
+		Note        *string    `json:"note,omitempty"`
+	}{e.EventTypeID, e.Code, e.SunsetAt, e.Note})
+}
+
+// EventTypeReinstated is emitted when a DEPRECATED event type returns to
+// CURRENT.
+type EventTypeReinstated struct {
+	Metadata    usecase.EventMetadata
+	EventTypeID string
+	Code        string
+}
+
+func (e EventTypeReinstated) EventID() string       { return e.Metadata.EventID }
+func (e EventTypeReinstated) EventType() string     { return EventTypeReinstatedType }
+func (e EventTypeReinstated) SpecVersion() string   { return "1.0" }
+func (e EventTypeReinstated) Source() string        { return EventTypeSourceConst }
+func (e EventTypeReinstated) Subject() string       { return subjectFor(e.EventTypeID) }
+func (e EventTypeReinstated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e EventTypeReinstated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e EventTypeReinstated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e EventTypeReinstated) CausationID() string   { return e.Metadata.CausationID }
+func (e EventTypeReinstated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e EventTypeReinstated) MessageGroup() string  { return e.Metadata.MessageGroup }
+func (e EventTypeReinstated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		EventTypeID string `json:"eventTypeId"`
+		Code        string `json:"code"`
+	}{e.EventTypeID, e.Code})
+}
+
+// EventTypePromoted is emitted when a SANDBOX event type becomes available
+// to production subscriptions.
+type EventTypePromoted struct {
+	Metadata    usecase.EventMetadata
+	EventTypeID string
+	Code        string
+}
+
+// NewEventTypePromotedEvent builds the promoted event with the canonical
+// subject. Exported for the client environment promotion, which promotes
+// event types inside its own transaction.
+func NewEventTypePromotedEvent(ec usecase.ExecutionContext, eventTypeID, code string) EventTypePromoted {
+	return EventTypePromoted{
+		Metadata:    usecase.NewEventMetadata(ec, EventTypePromotedType, EventTypeSourceConst, subjectFor(eventTypeID)),
+		EventTypeID: eventTypeID,
+		Code:        code,
+	}
+}
+
+func (e EventTypePromoted) EventID() string       { return e.Metadata.EventID }
+func (e EventTypePromoted) EventType() string     { return EventTypePromotedType }
+func (e EventTypePromoted) SpecVersion() string   { return "1.0" }
+func (e EventTypePromoted) Source() string        { return EventTypeSourceConst }
+func (e EventTypePromoted) Subject() string       { return subjectFor(e.EventTypeID) }
+func (e EventTypePromoted) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e EventTypePromoted) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e EventTypePromoted) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e EventTypePromoted) CausationID() string   { return e.Metadata.CausationID }
+func (e EventTypePromoted) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e EventTypePromoted) MessageGroup() string  { return e.Metadata.MessageGroup }
+func (e EventTypePromoted) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		EventTypeID string `json:"eventTypeId"`
+		Code        string `json:"code"`
+	}{e.EventTypeID, e.Code})
+}
+
+// EventTypeSchemasImported is emitted per existing event type a schema
+// bundle import added versions to.
+type EventTypeSchemasImported struct {
+	Metadata    usecase.EventMetadata
+	EventTypeID string
+	Code        string
+	Versions    []string
+}
+
+func (e EventTypeSchemasImported) EventID() string       { return e.Metadata.EventID }
+func (e EventTypeSchemasImported) EventType() string     { return EventTypeSchemasImportedType }
+func (e EventTypeSchemasImported) SpecVersion() string   { return "1.0" }
+func (e EventTypeSchemasImported) Source() string        { return EventTypeSourceConst }
+func (e EventTypeSchemasImported) Subject() string       { return subjectFor(e.EventTypeID) }
+func (e EventTypeSchemasImported) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e EventTypeSchemasImported) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e EventTypeSchemasImported) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e EventTypeSchemasImported) CausationID() string   { return e.Metadata.CausationID }
+func (e EventTypeSchemasImported) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e EventTypeSchemasImported) MessageGroup() string  { return e.Metadata.MessageGroup }
+func (e EventTypeSchemasImported) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		EventTypeID string   `json:"eventTypeId"`
+		Code        string   `json:"code"`
+		Versions    []string `json:"versions"`
+	}{e.EventTypeID, e.Code, e.Versions})
+}
+
+// EventTypesImported is the rollup event emitted by ImportSchemaBundle.
+type EventTypesImported struct {
+	Metadata      usecase.EventMetadata
+	Created       uint32
+	Updated       uint32
+	VersionsAdded uint32
+	ImportedCodes []string
+}
+
+func (e EventTypesImported) EventID() string       { return e.Metadata.EventID }
+func (e EventTypesImported) EventType() string     { return EventTypesImportedType }
+func (e EventTypesImported) SpecVersion() string   { return "1.0" }
+func (e EventTypesImported) Source() string        { return EventTypeSourceConst }
+func (e EventTypesImported) Subject() string       { return "platform.eventtypes.import" }
+func (e EventTypesImported) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e EventTypesImported) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e EventTypesImported) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e EventTypesImported) CausationID() string   { return e.Metadata.CausationID }
+func (e EventTypesImported) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e EventTypesImported) MessageGroup() string  { return "platform:eventtypes:import" }
+func (e EventTypesImported) ToDataJSON() ([]byte, error) {
+	importedCodes := e.ImportedCodes
+	if importedCodes == nil {
+		importedCodes = []string{}
+	}
+	return json.Marshal(struct {
+		Created       uint32   `json:"created"`
+		Updated       uint32   `json:"updated"`
+		VersionsAdded uint32   `json:"versionsAdded"`
+		ImportedCodes []string `json:"importedCodes"`
+	}{e.Created, e.Updated, e.VersionsAdded, importedCodes})
+}
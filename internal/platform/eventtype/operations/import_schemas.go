@@ -0,0 +1,190 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// ImportSchemaVersionInput is one schema version in an imported bundle.
+type ImportSchemaVersionInput struct {
+	Version    string                      `json:"version"`
+	SchemaType eventtype.SchemaType        `json:"schemaType"`
+	MimeType   string                      `json:"mimeType"`
+	Status     eventtype.SpecVersionStatus `json:"status"`
+	Schema     json.RawMessage             `json:"schema"`
+}
+
+// ImportEventTypeInput is one event type in an imported bundle.
+type ImportEventTypeInput struct {
+	Code        string                     `json:"code"`
+	Name        string                     `json:"name"`
+	Description *string                    `json:"description,omitempty"`
+	Versions    []ImportSchemaVersionInput `json:"versions"`
+}
+
+// ImportSchemaBundleCommand is the input DTO for ImportSchemaBundle.
+type ImportSchemaBundleCommand struct {
+	FormatVersion int                    `json:"formatVersion"`
+	EventTypes    []ImportEventTypeInput `json:"eventTypes"`
+}
+
+// ImportSchemaBundle loads a bundle exported from another environment.
+// Missing event types are created (anchor-level, PRODUCTION) and missing
+// schema versions are added with the status they had at the source, so a
+// version finalised there is CURRENT here too. Existing types keep their
+// name and description, and a version already present is left alone when
+// its schema matches; a differing schema under the same version fails the
+// whole import with SCHEMA_CONFLICT, since published versions are
+// immutable. Emits [EventTypeCreated] or [EventTypeSchemasImported] per
+// touched type plus one [EventTypesImported] rollup, atomically with the
+// writes via [usecaseop.Sync].
+func ImportSchemaBundle(repo *eventtype.Repository) usecaseop.Operation[ImportSchemaBundleCommand, EventTypesImported] {
+	return usecaseop.Operation[ImportSchemaBundleCommand, EventTypesImported]{
+		Name: "ImportSchemaBundle",
+		Validate: func(_ context.Context, cmd ImportSchemaBundleCommand) error {
+			if cmd.FormatVersion != eventtype.SchemaBundleFormatVersion {
+				return usecase.Validation("UNSUPPORTED_FORMAT_VERSION",
+					fmt.Sprintf("formatVersion must be %d", eventtype.SchemaBundleFormatVersion))
+			}
+			codes := make(map[string]bool, len(cmd.EventTypes))
+			for _, in := range cmd.EventTypes {
+				if codes[in.Code] {
+					return usecase.Validation("DUPLICATE_CODE", "Event type '"+in.Code+"' appears more than once")
+				}
+				codes[in.Code] = true
+				if strings.TrimSpace(in.Name) == "" {
+					return usecase.Validation("NAME_REQUIRED", "Event type '"+in.Code+"' has no name")
+				}
+				versions := make(map[string]bool, len(in.Versions))
+				for _, v := range in.Versions {
+					if strings.TrimSpace(v.Version) == "" {
+						return usecase.Validation("VERSION_REQUIRED", "Event type '"+in.Code+"' has a version with no version string")
+					}
+					if versions[v.Version] {
+						return usecase.Validation("DUPLICATE_VERSION",
+							"Event type '"+in.Code+"' lists version '"+v.Version+"' more than once")
+					}
+					versions[v.Version] = true
+					if len(v.Schema) == 0 {
+						return usecase.Validation("SCHEMA_REQUIRED",
+							"Event type '"+in.Code+"' version '"+v.Version+"' has no schema")
+					}
+				}
+			}
+			return nil
+		},
+		// Imported types are platform-wide, so the import is anchor-only.
+		// The coarse write permission is on the controller.
+		Authorize: func(ctx context.Context, _ ImportSchemaBundleCommand) error {
+			return auth.CheckScopeAccess(auth.FromContext(ctx), nil)
+		},
+		Execute: func(ctx context.Context, cmd ImportSchemaBundleCommand, ec usecase.ExecutionContext) (usecaseop.Plan[EventTypesImported], error) {
+			var (
+				saves         []usecasepgx.SyncSaveItem[eventtype.EventType]
+				importedCodes []string
+				created       int
+				updated       int
+				versionsAdded int
+			)
+			for _, in := range cmd.EventTypes {
+				cur, err := repo.FindByCode(ctx, in.Code)
+				if err != nil {
+					return nil, usecase.Internal("REPO", "find_by_code failed", err)
+				}
+				if cur == nil {
+					et, err := eventtype.New(in.Code, in.Name)
+					if err != nil {
+						return nil, usecase.Validation("INVALID_CODE",
+							fmt.Sprintf("%s (offending code: %q)", err.Error(), in.Code))
+					}
+					et.Description = in.Description
+					et.Source = eventtype.SourceAPI
+					et.CreatedBy = &ec.PrincipalID
+					for _, v := range in.Versions {
+						et.AddSchemaVersion(importedVersion(et.ID, v))
+					}
+					saves = append(saves, usecasepgx.SyncSaveItem[eventtype.EventType]{
+						Aggregate: et,
+						Event: EventTypeCreated{
+							Metadata:    usecase.NewEventMetadata(ec, EventTypeCreatedType, EventTypeSourceConst, subjectFor(et.ID)),
+							EventTypeID: et.ID,
+							Code:        et.Code,
+							Name:        et.Name,
+							Description: et.Description,
+							Application: et.Application,
+							Subdomain:   et.Subdomain,
+							Aggregate:   et.Aggregate,
+							EventName:   et.EventName,
+						},
+					})
+					importedCodes = append(importedCodes, et.Code)
+					created++
+					versionsAdded += len(in.Versions)
+					continue
+				}
+
+				var added []string
+				for _, v := range in.Versions {
+					existing := cur.SpecVersionFor(v.Version)
+					if existing == nil {
+						cur.AddSchemaVersion(importedVersion(cur.ID, v))
+						added = append(added, v.Version)
+						continue
+					}
+					if !existing.SameSchema(v.Schema) {
+						return nil, usecase.Conflict("SCHEMA_CONFLICT",
+							"Event type '"+in.Code+"' version '"+v.Version+"' already exists with a different schema")
+					}
+				}
+				if len(added) == 0 {
+					continue
+				}
+				saves = append(saves, usecasepgx.SyncSaveItem[eventtype.EventType]{
+					Aggregate: cur,
+					Event: EventTypeSchemasImported{
+						Metadata:    usecase.NewEventMetadata(ec, EventTypeSchemasImportedType, EventTypeSourceConst, subjectFor(cur.ID)),
+						EventTypeID: cur.ID,
+						Code:        cur.Code,
+						Versions:    added,
+					},
+				})
+				importedCodes = append(importedCodes, cur.Code)
+				updated++
+				versionsAdded += len(added)
+			}
+
+			rollup := EventTypesImported{
+				Metadata: usecase.NewEventMetadata(ec, EventTypesImportedType, EventTypeSourceConst,
+					"platform.eventtypes.import"),
+				Created:       uint32(created),
+				Updated:       uint32(updated),
+				VersionsAdded: uint32(versionsAdded),
+				ImportedCodes: importedCodes,
+			}
+			return usecaseop.Sync(repo, saves, nil, rollup), nil
+		},
+	}
+}
+
+func importedVersion(eventTypeID string, in ImportSchemaVersionInput) eventtype.SpecVersion {
+	sv := eventtype.NewSpecVersion(eventTypeID, in.Version, in.Schema)
+	if in.SchemaType != "" {
+		sv.SchemaType = eventtype.ParseSchemaType(string(in.SchemaType))
+		sv.MimeType = sv.SchemaType.DefaultMimeType()
+	}
+	if in.MimeType != "" {
+		sv.MimeType = in.MimeType
+	}
+	if in.Status != "" {
+		sv.Status = eventtype.ParseSpecVersionStatus(string(in.Status))
+	}
+	return sv
+}
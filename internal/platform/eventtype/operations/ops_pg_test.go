@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +14,8 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	subops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
@@ -530,3 +533,113 @@ func TestDeprecateEventTypeSchema_Errors(t *testing.T) {
 		operations.DeprecateSchemaCommand{EventTypeID: seeded.EventTypeID, Version: "9.9"})
 	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "SpecVersion_NOT_FOUND")
 }
+
+// ── Deprecate / Reinstate (type lifecycle) ────────────────────────────────
+
+func TestDeprecateReinstateEventType_RoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := eventtype.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "etlife:orders:order:created", "Lifecycle")
+
+	sunset := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Microsecond)
+	note := "publish etlife:orders:order:placed instead"
+	ev, err := runAuthorized(uow, operations.DeprecateEventType(repo),
+		operations.DeprecateCommand{ID: seeded.EventTypeID, SunsetAt: &sunset, Note: &note})
+	require.NoError(t, err)
+	assert.Equal(t, "etlife:orders:order:created", ev.Code)
+
+	got, err := repo.FindByID(ctx, seeded.EventTypeID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, eventtype.StatusDeprecated, got.Status)
+	require.NotNil(t, got.DeprecatedAt)
+	require.NotNil(t, got.SunsetAt)
+	assert.True(t, sunset.Equal(*got.SunsetAt))
+	assert.Equal(t, &note, got.DeprecationNote)
+
+	_, err = runAuthorized(uow, operations.ReinstateEventType(repo),
+		operations.ReinstateCommand{ID: seeded.EventTypeID})
+	require.NoError(t, err)
+	got, err = repo.FindByID(ctx, seeded.EventTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, eventtype.StatusCurrent, got.Status)
+	assert.Nil(t, got.DeprecatedAt)
+	assert.Nil(t, got.SunsetAt)
+
+	_, err = runAuthorized(uow, operations.ReinstateEventType(repo),
+		operations.ReinstateCommand{ID: seeded.EventTypeID})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "NOT_DEPRECATED")
+}
+
+func TestDeprecateEventType_Errors(t *testing.T) {
+	t.Parallel()
+	repo := eventtype.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	_, err := runAuthorized(uow, operations.DeprecateEventType(repo), operations.DeprecateCommand{})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "ID_REQUIRED")
+
+	past := time.Now().Add(-time.Hour)
+	_, err = runAuthorized(uow, operations.DeprecateEventType(repo),
+		operations.DeprecateCommand{ID: "evt_doesnotexist1", SunsetAt: &past})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "SUNSET_IN_PAST")
+
+	_, err = runAuthorized(uow, operations.DeprecateEventType(repo),
+		operations.DeprecateCommand{ID: "evt_doesnotexist1"})
+	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "EventType_NOT_FOUND")
+
+	seeded := mustCreate(t, repo, uow, "etlifeerr:orders:order:created", "Archived")
+	_, err = runAuthorized(uow, operations.ArchiveEventType(repo),
+		operations.ArchiveCommand{ID: seeded.EventTypeID})
+	require.NoError(t, err)
+	_, err = runAuthorized(uow, operations.DeprecateEventType(repo),
+		operations.DeprecateCommand{ID: seeded.EventTypeID})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "ARCHIVED")
+}
+
+// ── Usage (producers + subscription bindings) ─────────────────────────────
+
+func TestEventTypeUsage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := eventtype.NewRepository(pool)
+	uow := testpg.NewUoW(t)
+	const code = "etusage:orders:order:created"
+
+	seen := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, repo.AddProducers(ctx, []eventtype.Producer{
+		{EventTypeCode: code, SpecVersion: "1.0", PrincipalID: "svc_etusage1", EventCount: 2, LastSeenAt: seen},
+	}))
+	require.NoError(t, repo.AddProducers(ctx, []eventtype.Producer{
+		{EventTypeCode: code, SpecVersion: "1.0", PrincipalID: "svc_etusage1", EventCount: 3, LastSeenAt: seen.Add(time.Minute)},
+	}))
+	producers, err := repo.FindProducers(ctx, code)
+	require.NoError(t, err)
+	require.Len(t, producers, 1)
+	assert.Equal(t, int64(5), producers[0].EventCount)
+	assert.True(t, seen.Equal(producers[0].FirstSeenAt))
+	assert.True(t, seen.Add(time.Minute).Equal(producers[0].LastSeenAt))
+
+	subRepo := subscription.NewRepository(pool)
+	for subCode, pattern := range map[string]string{
+		"etusage-exact":    code,
+		"etusage-wildcard": "etusage:orders:*:*",
+		"etusage-other":    "etusage:orders:order:shipped",
+	} {
+		_, err := runAuthorized(uow, subops.CreateSubscription(subRepo), subops.CreateCommand{
+			Code: subCode, Name: subCode, Endpoint: "https://usage.example.test/" + subCode,
+			EventTypes: []subscription.EventTypeBinding{subscription.NewEventTypeBinding(pattern)},
+		})
+		require.NoError(t, err)
+	}
+	uses, err := repo.FindSubscriptionUses(ctx, code)
+	require.NoError(t, err)
+	var codes []string
+	for _, u := range uses {
+		codes = append(codes, u.SubscriptionCode)
+	}
+	assert.ElementsMatch(t, []string{"etusage-exact", "etusage-wildcard"}, codes)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -75,7 +76,8 @@ func (r *Repository) FindWithFilters(
 	_ = clientID // not a column on msg_event_types
 
 	q := `SELECT id, code, name, description, status, source, client_scoped,
-		         application, subdomain, aggregate, created_by, created_at, updated_at
+		         application, subdomain, aggregate, created_by, created_at, updated_at,
+		         deprecated_at, sunset_at, deprecation_note, environment
 		  FROM msg_event_types` + f.Where() + " ORDER BY code ASC"
 
 	rows, err := r.pool.Query(ctx, q, f.Args()...)
@@ -217,6 +219,11 @@ func rowToEventType(row dbq.MsgEventType) *EventType {
 		CreatedBy:    row.CreatedBy,
 		CreatedAt:    row.CreatedAt,
 		UpdatedAt:    row.UpdatedAt,
+
+		DeprecatedAt:    row.DeprecatedAt,
+		SunsetAt:        row.SunsetAt,
+		DeprecationNote: row.DeprecationNote,
+		Environment:     common.ParseEnvironment(row.Environment),
 	}
 	parts := strings.Split(et.Code, ":")
 	if len(parts) == 4 {
@@ -242,6 +249,11 @@ func eventTypeUpsertParams(et *EventType) dbq.EventTypeUpsertByIDParams {
 		CreatedBy:    et.CreatedBy,
 		CreatedAt:    et.CreatedAt,
 		UpdatedAt:    time.Now().UTC(),
+
+		DeprecatedAt:    et.DeprecatedAt,
+		SunsetAt:        et.SunsetAt,
+		DeprecationNote: et.DeprecationNote,
+		Environment:     string(et.Environment),
 	}
 }
 
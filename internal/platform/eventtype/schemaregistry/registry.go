@@ -0,0 +1,223 @@
+// Package schemaregistry publishes finalised event-type schemas to an
+// external schema registry — Confluent Schema Registry or Apicurio
+// Registry — so consumers outside FlowCatalyst can discover event
+// contracts. A Runner polls for production schema versions that are past
+// FINALISING and not yet synced, publishes each, and records the outcome
+// in msg_schema_registry_sync.
+//
+// Publishing is idempotent on both registries (Confluent returns the
+// existing ID for a schema it already holds; Apicurio is asked to return
+// the existing artifact), so several instances running the sync at once
+// is harmless and needs no claim.
+//
+// Like dispatch-job callbacks this is an infrastructure path: direct
+// repository writes, no UoW and no domain events.
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+)
+
+// Kind is the registry product.
+type Kind string
+
+const (
+	KindConfluent Kind = "CONFLUENT"
+	KindApicurio  Kind = "APICURIO"
+)
+
+// Config holds the registry knobs (all env-overridable).
+type Config struct {
+	// Kind selects the registry API. Unset disables the sync.
+	Kind Kind
+	// URL is the registry's base URL. Unset disables the sync.
+	URL string
+	// Username and Password, when set, are sent as HTTP basic auth.
+	Username string
+	Password string
+	// Group is the Apicurio artifact group; ignored for Confluent.
+	Group string
+	// Interval is how often the runner looks for unsynced versions.
+	Interval time.Duration
+	// MaxAttempts is how many failed publishes a version gets before the
+	// runner stops retrying it.
+	MaxAttempts int
+	// BatchSize caps the versions published per pass.
+	BatchSize int
+	// Timeout bounds one registry request.
+	Timeout time.Duration
+}
+
+// ConfigFromEnv builds a Config from FC_SCHEMA_REGISTRY_* env vars.
+func ConfigFromEnv() Config {
+	return Config{
+		Kind:        Kind(strings.ToUpper(envutil.Or("FC_SCHEMA_REGISTRY_KIND", ""))),
+		URL:         strings.TrimRight(envutil.Or("FC_SCHEMA_REGISTRY_URL", ""), "/"),
+		Username:    envutil.Or("FC_SCHEMA_REGISTRY_USERNAME", ""),
+		Password:    envutil.Or("FC_SCHEMA_REGISTRY_PASSWORD", ""),
+		Group:       envutil.Or("FC_SCHEMA_REGISTRY_GROUP", "default"),
+		Interval:    time.Duration(envutil.Int("FC_SCHEMA_REGISTRY_SYNC_INTERVAL_SECS", 60)) * time.Second,
+		MaxAttempts: envutil.Int("FC_SCHEMA_REGISTRY_MAX_ATTEMPTS", 10),
+		BatchSize:   100,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// Enabled reports whether a registry is configured.
+func (c Config) Enabled() bool { return c.URL != "" && c.Kind != "" }
+
+// Validate rejects a kind the sync can't talk to.
+func (c Config) Validate() error {
+	if c.Kind != "" && c.Kind != KindConfluent && c.Kind != KindApicurio {
+		return fmt.Errorf("FC_SCHEMA_REGISTRY_KIND must be CONFLUENT or APICURIO, got %q", c.Kind)
+	}
+	return nil
+}
+
+// Schema is one finalised schema version to publish.
+type Schema struct {
+	SpecVersionID string
+	Code          string
+	Version       string
+	SchemaType    eventtype.SchemaType
+	// Content is the schema document as text (see
+	// [eventtype.SpecVersion.SchemaText]).
+	Content string
+}
+
+// Subject names an event type in the registry: its code with the colons
+// turned to dots (orders:fulfillment:shipment:shipped →
+// orders.fulfillment.shipment.shipped), since colons aren't safe in
+// every registry's URL paths.
+func Subject(code string) string { return strings.ReplaceAll(code, ":", ".") }
+
+// ErrUnsupported reports a schema language the registry can't hold. The
+// version is recorded SKIPPED, not retried.
+var ErrUnsupported = errors.New("schema type not supported by the registry")
+
+// Client publishes a schema version and returns the registry's ID for it.
+type Client interface {
+	Publish(ctx context.Context, s Schema) (externalID string, err error)
+}
+
+// NewClient returns the client for cfg.Kind, sending through hc.
+func NewClient(cfg Config, hc *http.Client) (Client, error) {
+	switch cfg.Kind {
+	case KindConfluent:
+		return &confluent{cfg: cfg, hc: hc}, nil
+	case KindApicurio:
+		return &apicurio{cfg: cfg, hc: hc}, nil
+	default:
+		return nil, fmt.Errorf("unknown schema registry kind %q", cfg.Kind)
+	}
+}
+
+// confluent registers versions under one subject per event type.
+type confluent struct {
+	cfg Config
+	hc  *http.Client
+}
+
+func (c *confluent) Publish(ctx context.Context, s Schema) (string, error) {
+	var schemaType string
+	switch s.SchemaType {
+	case eventtype.SchemaJSON:
+		schemaType = "JSON"
+	case eventtype.SchemaProto:
+		schemaType = "PROTOBUF"
+	default:
+		return "", ErrUnsupported
+	}
+	body, err := json.Marshal(struct {
+		SchemaType string `json:"schemaType"`
+		Schema     string `json:"schema"`
+	}{schemaType, s.Content})
+	if err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	endpoint := c.cfg.URL + "/subjects/" + url.PathEscape(Subject(s.Code)) + "/versions"
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := do(ctx, c.hc, c.cfg, http.MethodPost, endpoint, body, map[string]string{
+		"Content-Type": "application/vnd.schemaregistry.v1+json",
+	}, &out); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(out.ID, 10), nil
+}
+
+// apicurio stores one artifact per event type with a version per schema
+// version, via the v2 core API.
+type apicurio struct {
+	cfg Config
+	hc  *http.Client
+}
+
+func (c *apicurio) Publish(ctx context.Context, s Schema) (string, error) {
+	var artifactType, contentType string
+	switch s.SchemaType {
+	case eventtype.SchemaXSD:
+		artifactType, contentType = "XSD", "application/xml"
+	case eventtype.SchemaProto:
+		artifactType, contentType = "PROTOBUF", "application/x-protobuf"
+	default:
+		artifactType, contentType = "JSON", "application/json"
+	}
+	endpoint := c.cfg.URL + "/apis/registry/v2/groups/" + url.PathEscape(c.cfg.Group) +
+		"/artifacts?ifExists=RETURN_OR_UPDATE"
+	var out struct {
+		GlobalID int64 `json:"globalId"`
+	}
+	if err := do(ctx, c.hc, c.cfg, http.MethodPost, endpoint, []byte(s.Content), map[string]string{
+		"Content-Type":            contentType,
+		"X-Registry-ArtifactId":   Subject(s.Code),
+		"X-Registry-ArtifactType": artifactType,
+		"X-Registry-Version":      s.Version,
+	}, &out); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(out.GlobalID, 10), nil
+}
+
+// do sends one request and decodes a 2xx JSON answer into out. Anything
+// else is an error carrying the start of the registry's reply.
+func do(ctx context.Context, hc *http.Client, cfg Config, method, endpoint string, body []byte, headers map[string]string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/json")
+	if cfg.Username != "" {
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %s: %s", resp.Status, strings.TrimSpace(string(raw[:min(len(raw), 512)])))
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode registry response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+)
+
+// State is a version's sync outcome for one registry.
+type State string
+
+const (
+	StateSynced  State = "SYNCED"
+	StateFailed  State = "FAILED"  // retried until MaxAttempts
+	StateSkipped State = "SKIPPED" // the registry can't hold the schema type
+)
+
+// Repository reads/writes msg_schema_registry_sync. Every transition is a
+// direct write.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+// Pending lists up to limit schema versions registry doesn't have yet:
+// finalised versions of production event types with no sync row, or a
+// FAILED one with attempts left, oldest first so a subject's versions
+// register in order.
+func (r *Repository) Pending(ctx context.Context, registry string, maxAttempts, limit int) ([]Schema, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT sv.id, et.code, sv.version, sv.schema_type, sv.schema_content
+		 FROM msg_event_type_spec_versions sv
+		 JOIN msg_event_types et ON et.id = sv.event_type_id
+		 LEFT JOIN msg_schema_registry_sync s
+		        ON s.spec_version_id = sv.id AND s.registry = $1
+		 WHERE sv.status <> 'FINALISING'
+		   AND et.environment = 'PRODUCTION'
+		   AND (s.spec_version_id IS NULL OR (s.state = 'FAILED' AND s.attempts < $2))
+		 ORDER BY sv.created_at, sv.id
+		 LIMIT $3`, registry, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("schema_registry pending: %w", err)
+	}
+	defer rows.Close()
+	var out []Schema
+	for rows.Next() {
+		var (
+			sv         eventtype.SpecVersion
+			code       string
+			schemaType string
+			content    []byte
+		)
+		if err := rows.Scan(&sv.ID, &code, &sv.Version, &schemaType, &content); err != nil {
+			return nil, fmt.Errorf("schema_registry pending scan: %w", err)
+		}
+		sv.SchemaType = eventtype.ParseSchemaType(schemaType)
+		sv.SchemaContent = content
+		out = append(out, Schema{
+			SpecVersionID: sv.ID,
+			Code:          code,
+			Version:       sv.Version,
+			SchemaType:    sv.SchemaType,
+			Content:       sv.SchemaText(),
+		})
+	}
+	return out, rows.Err()
+}
+
+// Record stores the outcome of one publish attempt, counting it.
+// externalID is the registry's ID on SYNCED; lastError explains FAILED
+// and SKIPPED.
+func (r *Repository) Record(ctx context.Context, specVersionID, registry string, state State, externalID, lastError *string) error {
+	var syncedAt *time.Time
+	if state == StateSynced {
+		now := time.Now().UTC()
+		syncedAt = &now
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO msg_schema_registry_sync
+		     (spec_version_id, registry, state, external_id, attempts, last_error, synced_at)
+		 VALUES ($1, $2, $3, $4, 1, $5, $6)
+		 ON CONFLICT (spec_version_id, registry) DO UPDATE
+		 SET state = EXCLUDED.state,
+		     external_id = EXCLUDED.external_id,
+		     attempts = msg_schema_registry_sync.attempts + 1,
+		     last_error = EXCLUDED.last_error,
+		     synced_at = EXCLUDED.synced_at,
+		     updated_at = NOW()`,
+		specVersionID, registry, string(state), externalID, lastError, syncedAt)
+	if err != nil {
+		return fmt.Errorf("schema_registry record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Store is where the runner finds unsynced versions and records outcomes.
+// Satisfied by *Repository.
+type Store interface {
+	Pending(ctx context.Context, registry string, maxAttempts, limit int) ([]Schema, error)
+	Record(ctx context.Context, specVersionID, registry string, state State, externalID, lastError *string) error
+}
+
+// Runner publishes unsynced schema versions every Interval. Construct with
+// NewRunner and run Run in its own goroutine; it is also the Prometheus
+// collector for the sync counts.
+type Runner struct {
+	cfg    Config
+	store  Store
+	client Client
+
+	synced  atomic.Int64
+	failed  atomic.Int64
+	skipped atomic.Int64
+}
+
+// NewRunner wires a runner for cfg's registry.
+func NewRunner(cfg Config, store Store) (*Runner, error) {
+	client, err := NewClient(cfg, &http.Client{Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{cfg: cfg, store: store, client: client}, nil
+}
+
+// Run syncs once at start, then every Interval until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	slog.Info("schema registry sync starting", "kind", r.cfg.Kind, "url", r.cfg.URL, "interval", r.cfg.Interval)
+	t := time.NewTicker(r.cfg.Interval)
+	defer t.Stop()
+	for {
+		if err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("schema registry sync error", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("schema registry sync stopped")
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce publishes one batch of pending versions. A failed publish is
+// recorded against the version and doesn't stop the batch.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	pending, err := r.store.Pending(ctx, r.cfg.URL, r.cfg.MaxAttempts, r.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, s := range pending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		id, err := r.client.Publish(ctx, s)
+		var (
+			state  = StateSynced
+			extID  *string
+			reason *string
+		)
+		switch {
+		case errors.Is(err, ErrUnsupported):
+			state = StateSkipped
+			msg := err.Error()
+			reason = &msg
+			r.skipped.Add(1)
+		case err != nil:
+			state = StateFailed
+			msg := err.Error()
+			reason = &msg
+			r.failed.Add(1)
+			slog.Warn("schema registry publish failed", "code", s.Code, "version", s.Version, "err", err)
+		default:
+			extID = &id
+			r.synced.Add(1)
+			slog.Debug("schema published to registry", "code", s.Code, "version", s.Version, "external_id", id)
+		}
+		if err := r.store.Record(ctx, s.SpecVersionID, r.cfg.URL, state, extID, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var syncDesc = prometheus.NewDesc("fc_schema_registry_sync_total",
+	"Schema versions published to the external schema registry, by result: synced, failed (retried) or skipped (type unsupported by the registry).",
+	[]string{"result"}, nil)
+
+// Describe is a no-op (unchecked const-metric collector).
+func (r *Runner) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits the per-result publish counts.
+func (r *Runner) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(syncDesc, prometheus.CounterValue, float64(r.synced.Load()), "synced")
+	ch <- prometheus.MustNewConstMetric(syncDesc, prometheus.CounterValue, float64(r.failed.Load()), "failed")
+	ch <- prometheus.MustNewConstMetric(syncDesc, prometheus.CounterValue, float64(r.skipped.Load()), "skipped")
+}
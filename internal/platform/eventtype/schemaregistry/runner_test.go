@@ -0,0 +1,130 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+)
+
+func TestSubject(t *testing.T) {
+	assert.Equal(t, "orders.fulfillment.shipment.shipped", Subject("orders:fulfillment:shipment:shipped"))
+}
+
+func TestConfluentPublish(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/orders.fulfillment.shipment.shipped/versions", r.URL.Path)
+		assert.Equal(t, "application/vnd.schemaregistry.v1+json", r.Header.Get("Content-Type"))
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "fc:secret", user+":"+pass)
+		var body struct {
+			SchemaType string `json:"schemaType"`
+			Schema     string `json:"schema"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "JSON", body.SchemaType)
+		assert.Equal(t, `{"type":"object"}`, body.Schema)
+		_, _ = io.WriteString(w, `{"id":42}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{Kind: KindConfluent, URL: srv.URL, Username: "fc", Password: "secret"}, srv.Client())
+	require.NoError(t, err)
+	id, err := c.Publish(context.Background(), Schema{
+		Code: "orders:fulfillment:shipment:shipped", Version: "1.0",
+		SchemaType: eventtype.SchemaJSON, Content: `{"type":"object"}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "42", id)
+
+	_, err = c.Publish(context.Background(), Schema{Code: "a:b:c:d", SchemaType: eventtype.SchemaXSD})
+	assert.ErrorIs(t, err, ErrUnsupported, "Confluent has no XSD support")
+}
+
+func TestApicurioPublish(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/registry/v2/groups/events/artifacts", r.URL.Path)
+		assert.Equal(t, "RETURN_OR_UPDATE", r.URL.Query().Get("ifExists"))
+		assert.Equal(t, "a.b.c.d", r.Header.Get("X-Registry-ArtifactId"))
+		assert.Equal(t, "XSD", r.Header.Get("X-Registry-ArtifactType"))
+		assert.Equal(t, "2.0", r.Header.Get("X-Registry-Version"))
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "<xs:schema/>", string(body))
+		_, _ = io.WriteString(w, `{"id":"a.b.c.d","version":"2.0","globalId":7}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{Kind: KindApicurio, URL: srv.URL, Group: "events"}, srv.Client())
+	require.NoError(t, err)
+	id, err := c.Publish(context.Background(), Schema{
+		Code: "a:b:c:d", Version: "2.0", SchemaType: eventtype.SchemaXSD, Content: "<xs:schema/>",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "7", id)
+}
+
+type record struct {
+	state State
+	extID *string
+}
+
+type fakeStore struct {
+	pending []Schema
+	records map[string]record
+}
+
+func (f *fakeStore) Pending(context.Context, string, int, int) ([]Schema, error) {
+	return f.pending, nil
+}
+
+func (f *fakeStore) Record(_ context.Context, id, _ string, state State, extID, _ *string) error {
+	f.records[id] = record{state, extID}
+	return nil
+}
+
+func TestRunOnceRecordsEachOutcome(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/subjects/a.b.c.bad/versions" {
+			http.Error(w, `{"error_code":409,"message":"incompatible"}`, http.StatusConflict)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id":1}`)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{
+		pending: []Schema{
+			{SpecVersionID: "sv_ok", Code: "a:b:c:ok", SchemaType: eventtype.SchemaJSON, Content: "{}"},
+			{SpecVersionID: "sv_bad", Code: "a:b:c:bad", SchemaType: eventtype.SchemaJSON, Content: "{}"},
+			{SpecVersionID: "sv_xsd", Code: "a:b:c:xsd", SchemaType: eventtype.SchemaXSD, Content: "<x/>"},
+		},
+		records: map[string]record{},
+	}
+	r, err := NewRunner(Config{Kind: KindConfluent, URL: srv.URL, MaxAttempts: 3, BatchSize: 10}, store)
+	require.NoError(t, err)
+	require.NoError(t, r.RunOnce(context.Background()))
+
+	require.NotNil(t, store.records["sv_ok"].extID)
+	assert.Equal(t, StateSynced, store.records["sv_ok"].state)
+	assert.Equal(t, "1", *store.records["sv_ok"].extID)
+	assert.Equal(t, StateFailed, store.records["sv_bad"].state, "a rejected publish is retried later")
+	assert.Equal(t, StateSkipped, store.records["sv_xsd"].state)
+	assert.Equal(t, int64(1), r.synced.Load())
+	assert.Equal(t, int64(1), r.failed.Load())
+	assert.Equal(t, int64(1), r.skipped.Load())
+}
+
+func TestNewClientRejectsUnknownKind(t *testing.T) {
+	_, err := NewClient(Config{Kind: "GLUE", URL: "http://registry"}, http.DefaultClient)
+	assert.Error(t, err)
+	assert.Error(t, Config{Kind: "GLUE"}.Validate())
+	assert.NoError(t, Config{}.Validate())
+}
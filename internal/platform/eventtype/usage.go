@@ -0,0 +1,290 @@
+package eventtype
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/jackc/pgx/v5"
+)
+
+// Producer is one principal's publishing of an event type at a schema
+// version, as recorded in msg_event_type_producers.
+type Producer struct {
+	EventTypeCode string
+	SpecVersion   string
+	PrincipalID   string
+	EventCount    int64
+	FirstSeenAt   time.Time
+	LastSeenAt    time.Time
+}
+
+// SubscriptionUse is a subscription binding that selects an event type,
+// either by its exact code or through a wildcard pattern.
+type SubscriptionUse struct {
+	SubscriptionID   string  `db:"id"`
+	SubscriptionCode string  `db:"code"`
+	SubscriptionName string  `db:"name"`
+	Status           string  `db:"status"`
+	ClientID         *string `db:"client_id"`
+	Pattern          string  `db:"event_type_code"`
+	SpecVersion      *string `db:"spec_version"`
+}
+
+// AddProducers adds each row's count onto its stored (code, version,
+// principal) in one round-trip, stamping first/last seen.
+func (r *Repository) AddProducers(ctx context.Context, rows []Producer) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	codes := make([]string, len(rows))
+	versions := make([]string, len(rows))
+	principals := make([]string, len(rows))
+	counts := make([]int64, len(rows))
+	seen := make([]time.Time, len(rows))
+	for i, p := range rows {
+		codes[i], versions[i], principals[i], counts[i], seen[i] =
+			p.EventTypeCode, p.SpecVersion, p.PrincipalID, p.EventCount, p.LastSeenAt
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO msg_event_type_producers
+		        (event_type_code, spec_version, principal_id, event_count, first_seen_at, last_seen_at)
+		 SELECT code, version, principal, n, seen, seen
+		   FROM unnest($1::varchar[], $2::varchar[], $3::varchar[], $4::bigint[], $5::timestamptz[])
+		        AS u(code, version, principal, n, seen)
+		 ON CONFLICT (event_type_code, spec_version, principal_id) DO UPDATE
+		    SET event_count  = msg_event_type_producers.event_count + EXCLUDED.event_count,
+		        last_seen_at = GREATEST(msg_event_type_producers.last_seen_at, EXCLUDED.last_seen_at)`,
+		codes, versions, principals, counts, seen)
+	if err != nil {
+		return fmt.Errorf("add_producers: %w", err)
+	}
+	return nil
+}
+
+// FindProducers lists who has published code, most recently active first.
+func (r *Repository) FindProducers(ctx context.Context, code string) ([]Producer, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT event_type_code, spec_version, principal_id, event_count, first_seen_at, last_seen_at
+		   FROM msg_event_type_producers
+		  WHERE event_type_code = $1
+		  ORDER BY last_seen_at DESC`, code)
+	if err != nil {
+		return nil, fmt.Errorf("event_types FindProducers: %w", err)
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Producer, error) {
+		var p Producer
+		err := row.Scan(&p.EventTypeCode, &p.SpecVersion, &p.PrincipalID, &p.EventCount, &p.FirstSeenAt, &p.LastSeenAt)
+		return p, err
+	})
+}
+
+// FindSubscriptionUses lists the subscription bindings that select code.
+// Wildcard bindings are fetched wholesale and matched here, segment by
+// segment, the same way dispatch matches them.
+func (r *Repository) FindSubscriptionUses(ctx context.Context, code string) ([]SubscriptionUse, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT s.id, s.code, s.name, s.status, s.client_id, b.event_type_code, b.spec_version
+		   FROM msg_subscription_event_types b
+		   JOIN msg_subscriptions s ON s.id = b.subscription_id
+		  WHERE b.event_type_code = $1 OR b.event_type_code LIKE '%*%'
+		  ORDER BY s.code ASC`, code)
+	if err != nil {
+		return nil, fmt.Errorf("event_types FindSubscriptionUses: %w", err)
+	}
+	all, err := pgx.CollectRows(rows, pgx.RowToStructByName[SubscriptionUse])
+	if err != nil {
+		return nil, err
+	}
+	uses := all[:0]
+	for _, u := range all {
+		if CodeMatches(u.Pattern, code) {
+			uses = append(uses, u)
+		}
+	}
+	return uses, nil
+}
+
+// CodeMatches reports whether a subscription binding pattern selects the
+// event-type code. Patterns are colon-separated; `*` matches any single
+// segment.
+func CodeMatches(pattern, code string) bool {
+	patternParts := strings.Split(pattern, ":")
+	codeParts := strings.Split(code, ":")
+	if len(patternParts) != len(codeParts) {
+		return false
+	}
+	for i, p := range patternParts {
+		if p != "*" && p != codeParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// usageStore is what the UsageTracker reads and flushes into.
+// *Repository satisfies it; tests substitute an in-memory fake.
+type usageStore interface {
+	AddProducers(ctx context.Context, rows []Producer) error
+	FindByCode(ctx context.Context, code string) (*EventType, error)
+}
+
+const (
+	usageFlushInterval = 10 * time.Second
+	usageCacheTTL      = 30 * time.Second
+	usageCacheSize     = 10000
+)
+
+// UsageTracker records who publishes which event types off the request
+// path, and answers deprecation lookups for ingest from a short-lived
+// cache. Construct with NewUsageTracker and run Run in its own goroutine.
+// Safe for concurrent use.
+//
+// Lookups are eventually consistent: a deprecation made through another
+// instance is seen here within the cache TTL.
+type UsageTracker struct {
+	store usageStore
+
+	mu sync.Mutex
+	// pending is counted but not yet flushed, keyed by (code, version,
+	// principal); each entry's LastSeenAt is the latest publish.
+	pending map[producerKey]*Producer
+	// types caches event types by code; a nil entry caches "no such type".
+	types *lru.LRU[string, *EventType]
+	now   func() time.Time
+}
+
+type producerKey struct {
+	code, version, principal string
+}
+
+// NewUsageTracker wires a UsageTracker against repo.
+func NewUsageTracker(repo *Repository) *UsageTracker {
+	return newUsageTracker(repo)
+}
+
+func newUsageTracker(s usageStore) *UsageTracker {
+	return &UsageTracker{
+		store:   s,
+		pending: map[producerKey]*Producer{},
+		types:   lru.NewLRU[string, *EventType](usageCacheSize, nil, usageCacheTTL),
+		now:     time.Now,
+	}
+}
+
+// Record counts n events of code at schema version published by
+// principalID. Anonymous publishes aren't attributed and are dropped.
+func (t *UsageTracker) Record(code, version, principalID string, n int) {
+	if code == "" || principalID == "" || n <= 0 {
+		return
+	}
+	now := t.now().UTC()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addPending(producerKey{code: code, version: version, principal: principalID}, int64(n), now)
+}
+
+// Deprecation reports whether publishing code at version is deprecated,
+// or nil when it isn't (or the type isn't registered). Fails open: a
+// lookup error is logged and treated as not deprecated — the warning
+// must not take ingest down.
+func (t *UsageTracker) Deprecation(ctx context.Context, code, version string) *Deprecation {
+	et, ok := t.types.Get(code)
+	if !ok {
+		var err error
+		et, err = t.store.FindByCode(ctx, code)
+		if err != nil {
+			slog.Warn("eventtype: deprecation lookup failed", "code", code, "err", err)
+			return nil
+		}
+		t.types.Add(code, et)
+	}
+	if et == nil {
+		return nil
+	}
+	return et.DeprecationFor(version)
+}
+
+// Invalidate drops code's cached event type so the next lookup reloads
+// it. Called after a deprecation or reinstatement.
+func (t *UsageTracker) Invalidate(code string) { t.types.Remove(code) }
+
+// Run flushes buffered counts every 10s until ctx is cancelled, then
+// flushes once more.
+func (t *UsageTracker) Run(ctx context.Context) {
+	tick := time.NewTicker(usageFlushInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := t.Flush(fctx); err != nil {
+				slog.Warn("eventtype: final usage flush failed", "err", err)
+			}
+			cancel()
+			return
+		case <-tick.C:
+			if err := t.Flush(ctx); err != nil {
+				slog.Warn("eventtype: usage flush failed", "err", err)
+			}
+		}
+	}
+}
+
+// Flush writes buffered counts. On failure they are kept and retried on
+// the next flush.
+func (t *UsageTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	if len(t.pending) == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	batch := t.pending
+	t.pending = map[producerKey]*Producer{}
+	t.mu.Unlock()
+
+	rows := make([]Producer, 0, len(batch))
+	for _, p := range batch {
+		rows = append(rows, *p)
+	}
+	// A stable order keeps concurrent flushes from deadlocking on the
+	// same rows.
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.EventTypeCode != b.EventTypeCode {
+			return a.EventTypeCode < b.EventTypeCode
+		}
+		if a.SpecVersion != b.SpecVersion {
+			return a.SpecVersion < b.SpecVersion
+		}
+		return a.PrincipalID < b.PrincipalID
+	})
+	err := t.store.AddProducers(ctx, rows)
+	if err == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k, p := range batch {
+		t.addPending(k, p.EventCount, p.LastSeenAt)
+	}
+	return err
+}
+
+// addPending must be called with mu held.
+func (t *UsageTracker) addPending(k producerKey, n int64, seen time.Time) {
+	p, ok := t.pending[k]
+	if !ok {
+		p = &Producer{EventTypeCode: k.code, SpecVersion: k.version, PrincipalID: k.principal}
+		t.pending[k] = p
+	}
+	p.EventCount += n
+	if seen.After(p.LastSeenAt) {
+		p.LastSeenAt = seen
+	}
+}
@@ -0,0 +1,86 @@
+package eventtype
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsageStore struct {
+	types   map[string]*EventType
+	lookups int
+	adds    [][]Producer
+	addErr  error
+}
+
+func (f *fakeUsageStore) AddProducers(_ context.Context, rows []Producer) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	f.adds = append(f.adds, rows)
+	return nil
+}
+
+func (f *fakeUsageStore) FindByCode(_ context.Context, code string) (*EventType, error) {
+	f.lookups++
+	return f.types[code], nil
+}
+
+func TestUsageTracker_FlushMergesAndRetries(t *testing.T) {
+	s := &fakeUsageStore{addErr: errors.New("db down")}
+	tr := newUsageTracker(s)
+	first := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return first }
+
+	tr.Record("a:b:c:d", "1.0", "svc_1", 2)
+	tr.Record("a:b:c:d", "1.0", "", 5) // anonymous: not attributed
+	tr.now = func() time.Time { return first.Add(time.Minute) }
+	tr.Record("a:b:c:d", "1.0", "svc_1", 1)
+	tr.Record("a:b:c:d", "2.0", "svc_1", 1)
+
+	require.Error(t, tr.Flush(context.Background()))
+	s.addErr = nil
+	require.NoError(t, tr.Flush(context.Background()), "failed counts are retried")
+	require.Len(t, s.adds, 1)
+	assert.Equal(t, []Producer{
+		{EventTypeCode: "a:b:c:d", SpecVersion: "1.0", PrincipalID: "svc_1", EventCount: 3, LastSeenAt: first.Add(time.Minute)},
+		{EventTypeCode: "a:b:c:d", SpecVersion: "2.0", PrincipalID: "svc_1", EventCount: 1, LastSeenAt: first.Add(time.Minute)},
+	}, s.adds[0])
+
+	require.NoError(t, tr.Flush(context.Background()))
+	assert.Len(t, s.adds, 1, "nothing pending, nothing written")
+}
+
+func TestUsageTracker_DeprecationIsCached(t *testing.T) {
+	et, err := New("a:b:c:d", "Name")
+	require.NoError(t, err)
+	s := &fakeUsageStore{types: map[string]*EventType{"a:b:c:d": et}}
+	tr := newUsageTracker(s)
+	ctx := context.Background()
+
+	assert.Nil(t, tr.Deprecation(ctx, "a:b:c:d", "1.0"))
+	assert.Nil(t, tr.Deprecation(ctx, "x:y:z:w", "1.0"), "unregistered types aren't deprecated")
+
+	deprecated := *et
+	deprecated.Deprecate(nil, nil)
+	s.types["a:b:c:d"] = &deprecated
+	assert.Nil(t, tr.Deprecation(ctx, "a:b:c:d", "1.0"), "served from cache")
+	assert.Nil(t, tr.Deprecation(ctx, "x:y:z:w", "1.0"))
+	assert.Equal(t, 2, s.lookups, "misses are cached too")
+
+	tr.Invalidate("a:b:c:d")
+	d := tr.Deprecation(ctx, "a:b:c:d", "1.0")
+	require.NotNil(t, d)
+	assert.Equal(t, "a:b:c:d", d.Code)
+}
+
+func TestCodeMatches(t *testing.T) {
+	assert.True(t, CodeMatches("a:b:c:d", "a:b:c:d"))
+	assert.True(t, CodeMatches("a:*:c:*", "a:b:c:d"))
+	assert.False(t, CodeMatches("a:b:c", "a:b:c:d"))
+	assert.False(t, CodeMatches("a:b:c:e", "a:b:c:d"))
+}
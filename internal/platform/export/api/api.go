@@ -0,0 +1,132 @@
+// Package api wires the bulk export endpoints via huma.
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// State bundles deps.
+type State struct {
+	Repo   *export.Repository
+	Config export.Config
+	// Store presigns download links; nil when no destination is configured.
+	Store *export.ObjectStore
+	UoW   *usecasepgx.UnitOfWork
+}
+
+const tag = "exports"
+
+// listLimit caps GET /api/exports; exports are few and short-lived.
+const listLimit = 100
+
+// Register mounts the export endpoints.
+func Register(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Post(g, "createExport", "/api/exports", "Queue a bulk export of events or dispatch jobs", http.StatusAccepted, s.create)
+	apiroute.Get(g, "listExports", "/api/exports", "List exports", s.list)
+	apiroute.Get(g, "getExport", "/api/exports/{id}", "Get an export's status and download link", s.get)
+}
+
+// rawPermFor is the permission an export of kind needs: exports carry
+// full payloads, so the raw-view grant rather than the list view.
+func rawPermFor(kind string) string {
+	if kind == string(export.KindDispatchJobs) {
+		return "platform:messaging:dispatch-job:view-raw"
+	}
+	return "platform:messaging:event:view-raw"
+}
+
+// create queues an export. A non-anchor may only name clients it can
+// access, and its tenant scope is captured on the export so the runner
+// never writes another tenant's rows.
+func (s *State) create(ctx context.Context, in *apicommon.In[CreateExportRequest]) (*apicommon.Out[ExportResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWritePermission(ac, rawPermFor(in.Body.Kind)); err != nil {
+		return nil, err
+	}
+	var accessible *[]string
+	if !ac.IsAnchor() {
+		for _, id := range in.Body.ClientIDs {
+			if !ac.CanAccessClient(id) {
+				return nil, httperror.Forbidden("no access to client " + id)
+			}
+		}
+		clients := ac.Clients
+		accessible = &clients
+	}
+	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateExport(s.Repo, s.Config), in.Body.toCommand(accessible), reqctx.ExecutionContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.Repo.FindByID(ctx, event.ExportID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_export failed", err)
+	}
+	if e == nil {
+		return nil, httperror.NotFound("Export", event.ExportID)
+	}
+	return &apicommon.Out[ExportResponse]{Body: exportFromEntity(e)}, nil
+}
+
+// list returns the newest exports: all of them for anchors, otherwise the
+// caller's own.
+func (s *State) list(ctx context.Context, _ *apicommon.Empty) (*apicommon.Out[ExportListResponse], error) {
+	ac := auth.FromContext(ctx)
+	if ac == nil {
+		return nil, usecase.Authorization("UNAUTHENTICATED", "authentication required")
+	}
+	var requestedBy *string
+	if !ac.IsAnchor() {
+		requestedBy = &ac.PrincipalID
+	}
+	rows, err := s.Repo.List(ctx, requestedBy, listLimit)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "list_exports failed", err)
+	}
+	return &apicommon.Out[ExportListResponse]{Body: ExportListResponse{
+		Items: apicommon.MapSlice(rows, func(e **export.Export) ExportResponse { return exportFromEntity(*e) }),
+	}}, nil
+}
+
+// get reports an export's status, with a presigned download link once it
+// has COMPLETED. The kind's raw-view permission is re-checked so a
+// revoked grant also revokes access to earlier exports.
+func (s *State) get(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[ExportResponse], error) {
+	ac := auth.FromContext(ctx)
+	if ac == nil {
+		return nil, usecase.Authorization("UNAUTHENTICATED", "authentication required")
+	}
+	e, err := s.Repo.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_export failed", err)
+	}
+	if e == nil || (!ac.IsAnchor() && (e.RequestedBy == nil || *e.RequestedBy != ac.PrincipalID)) {
+		return nil, httperror.NotFound("Export", in.ID)
+	}
+	if err := auth.CanWritePermission(ac, rawPermFor(string(e.Kind))); err != nil {
+		return nil, err
+	}
+	out := exportFromEntity(e)
+	if e.Status == export.StatusCompleted && e.ObjectKey != nil && s.Store != nil {
+		link, expires, err := s.Store.PresignGet(ctx, *e.ObjectKey)
+		if err != nil {
+			return nil, usecase.Internal("PRESIGN", "presign download failed", err)
+		}
+		out.DownloadURL, out.DownloadURLExpiresAt = &link, &expires
+	}
+	return &apicommon.Out[ExportResponse]{Body: out}, nil
+}
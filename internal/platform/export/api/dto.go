@@ -0,0 +1,84 @@
+// dto.go contains the wire-format types for the export API.
+package api
+
+import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export/operations"
+)
+
+// CreateExportRequest is the wire body for POST /api/exports.
+type CreateExportRequest struct {
+	Kind       string    `json:"kind" enum:"EVENTS,DISPATCH_JOBS"`
+	Format     string    `json:"format,omitempty" enum:"NDJSON,PARQUET" doc:"Defaults to NDJSON (gzipped)"`
+	From       time.Time `json:"from" doc:"Inclusive lower bound on createdAt"`
+	To         time.Time `json:"to" doc:"Exclusive upper bound on createdAt"`
+	EventTypes []string  `json:"eventTypes,omitempty" doc:"Event types (EVENTS) or job codes (DISPATCH_JOBS); empty = all"`
+	ClientIDs  []string  `json:"clientIds,omitempty" doc:"Empty = every client the caller can access"`
+}
+
+func (r CreateExportRequest) toCommand(accessible *[]string) operations.CreateExportCommand {
+	format := r.Format
+	if format == "" {
+		format = string(export.FormatNDJSON)
+	}
+	return operations.CreateExportCommand{
+		Kind:                r.Kind,
+		Format:              format,
+		From:                r.From,
+		To:                  r.To,
+		EventTypes:          r.EventTypes,
+		ClientIDs:           r.ClientIDs,
+		AccessibleClientIDs: accessible,
+	}
+}
+
+// ExportResponse is the wire shape of an export. DownloadURL is set only
+// on GET /api/exports/{id} once the export has COMPLETED.
+type ExportResponse struct {
+	ID                   string     `json:"id"`
+	Kind                 string     `json:"kind" enum:"EVENTS,DISPATCH_JOBS"`
+	Format               string     `json:"format" enum:"NDJSON,PARQUET"`
+	Status               string     `json:"status" enum:"PENDING,RUNNING,COMPLETED,FAILED"`
+	From                 time.Time  `json:"from"`
+	To                   time.Time  `json:"to"`
+	EventTypes           []string   `json:"eventTypes,omitempty"`
+	ClientIDs            []string   `json:"clientIds,omitempty"`
+	RowCount             int64      `json:"rowCount"`
+	ByteSize             int64      `json:"byteSize"`
+	ObjectKey            *string    `json:"objectKey,omitempty"`
+	Error                *string    `json:"error,omitempty"`
+	RequestedBy          *string    `json:"requestedBy,omitempty"`
+	CreatedAt            time.Time  `json:"createdAt"`
+	StartedAt            *time.Time `json:"startedAt,omitempty"`
+	CompletedAt          *time.Time `json:"completedAt,omitempty"`
+	DownloadURL          *string    `json:"downloadUrl,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"downloadUrlExpiresAt,omitempty"`
+}
+
+func exportFromEntity(e *export.Export) ExportResponse {
+	return ExportResponse{
+		ID:          e.ID,
+		Kind:        string(e.Kind),
+		Format:      string(e.Format),
+		Status:      string(e.Status),
+		From:        e.Filter.From,
+		To:          e.Filter.To,
+		EventTypes:  e.Filter.EventTypes,
+		ClientIDs:   e.Filter.ClientIDs,
+		RowCount:    e.RowCount,
+		ByteSize:    e.ByteSize,
+		ObjectKey:   e.ObjectKey,
+		Error:       e.Error,
+		RequestedBy: e.RequestedBy,
+		CreatedAt:   e.CreatedAt,
+		StartedAt:   e.StartedAt,
+		CompletedAt: e.CompletedAt,
+	}
+}
+
+// ExportListResponse wraps GET /api/exports.
+type ExportListResponse struct {
+	Items []ExportResponse `json:"items"`
+}
@@ -0,0 +1,168 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// colType is an exported column's value type. Every column is nullable.
+type colType int
+
+const (
+	colString colType = iota
+	// colJSON holds JSON text; NDJSON embeds it as a nested value.
+	colJSON
+	colInt64
+	colTime
+)
+
+// column is one exported field: its camelCase output name, the select
+// expression producing it, and its type.
+type column struct {
+	name string
+	sql  string
+	typ  colType
+}
+
+var eventColumns = []column{
+	{"id", "id", colString},
+	{"specVersion", "spec_version", colString},
+	{"type", "type", colString},
+	{"source", "source", colString},
+	{"subject", "subject", colString},
+	{"time", "time", colTime},
+	{"data", "data::text", colJSON},
+	{"deduplicationId", "deduplication_id", colString},
+	{"clientId", "client_id", colString},
+	{"messageGroup", "message_group", colString},
+	{"correlationId", "correlation_id", colString},
+	{"causationId", "causation_id", colString},
+	{"contextData", "context_data::text", colJSON},
+	{"createdAt", "created_at", colTime},
+}
+
+var dispatchJobColumns = []column{
+	{"id", "id", colString},
+	{"externalId", "external_id", colString},
+	{"kind", "kind", colString},
+	{"code", "code", colString},
+	{"source", "source", colString},
+	{"subject", "subject", colString},
+	{"eventId", "event_id", colString},
+	{"correlationId", "correlation_id", colString},
+	{"clientId", "client_id", colString},
+	{"subscriptionId", "subscription_id", colString},
+	{"dispatchPoolId", "dispatch_pool_id", colString},
+	{"messageGroup", "message_group", colString},
+	{"targetUrl", "target_url", colString},
+	{"status", "status", colString},
+	{"attemptCount", "attempt_count::bigint", colInt64},
+	{"maxRetries", "max_retries::bigint", colInt64},
+	{"lastError", "last_error", colString},
+	{"durationMillis", "duration_millis", colInt64},
+	{"payload", "payload", colString},
+	{"payloadContentType", "payload_content_type", colString},
+	{"scheduledFor", "scheduled_for", colTime},
+	{"completedAt", "completed_at", colTime},
+	{"createdAt", "created_at", colTime},
+	{"updatedAt", "updated_at", colTime},
+}
+
+// columnsFor returns the kind's export schema.
+func columnsFor(k Kind) []column {
+	if k == KindDispatchJobs {
+		return dispatchJobColumns
+	}
+	return eventColumns
+}
+
+// cell is one scanned value; the pointer matching the column's type is
+// set, or none of them for NULL.
+type cell struct {
+	s *string
+	i *int64
+	t *time.Time
+}
+
+func (c cell) null() bool { return c.s == nil && c.i == nil && c.t == nil }
+
+// scanTargets returns rows.Scan destinations writing into cells.
+func scanTargets(cols []column, cells []cell) []any {
+	dest := make([]any, len(cols))
+	for i, c := range cols {
+		switch c.typ {
+		case colInt64:
+			dest[i] = &cells[i].i
+		case colTime:
+			dest[i] = &cells[i].t
+		default:
+			dest[i] = &cells[i].s
+		}
+	}
+	return dest
+}
+
+// encoder writes rows in an export format. close flushes any buffered
+// rows and trailers; it does not close the underlying writer.
+type encoder interface {
+	write(cells []cell) error
+	close() error
+}
+
+func newEncoder(f Format, cols []column, w io.Writer) encoder {
+	if f == FormatParquet {
+		return newParquetWriter(w, cols)
+	}
+	return newNDJSONWriter(w, cols)
+}
+
+// ndjsonWriter writes one gzipped JSON object per line. Timestamps are
+// RFC 3339 UTC; JSON columns are embedded as-is.
+type ndjsonWriter struct {
+	cols []column
+	gz   *gzip.Writer
+	line bytes.Buffer
+}
+
+func newNDJSONWriter(w io.Writer, cols []column) *ndjsonWriter {
+	return &ndjsonWriter{cols: cols, gz: gzip.NewWriter(w)}
+}
+
+func (n *ndjsonWriter) write(cells []cell) error {
+	b := &n.line
+	b.Reset()
+	b.WriteByte('{')
+	for i, c := range n.cols {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Quote(c.name))
+		b.WriteByte(':')
+		v := cells[i]
+		switch {
+		case v.null():
+			b.WriteString("null")
+		case c.typ == colInt64:
+			b.WriteString(strconv.FormatInt(*v.i, 10))
+		case c.typ == colTime:
+			b.WriteString(strconv.Quote(v.t.UTC().Format(time.RFC3339Nano)))
+		case c.typ == colJSON && json.Valid([]byte(*v.s)):
+			b.WriteString(*v.s)
+		default:
+			enc, err := json.Marshal(*v.s)
+			if err != nil {
+				return err
+			}
+			b.Write(enc)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := n.gz.Write(b.Bytes())
+	return err
+}
+
+func (n *ndjsonWriter) close() error { return n.gz.Close() }
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCols = []column{
+	{"id", "id", colString},
+	{"data", "data::text", colJSON},
+	{"attempts", "attempts", colInt64},
+	{"createdAt", "created_at", colTime},
+}
+
+func strp(s string) *string { return &s }
+
+func testRows() [][]cell {
+	n := int64(3)
+	at := time.Date(2026, 3, 15, 12, 0, 0, 500_000_000, time.UTC)
+	return [][]cell{
+		{{s: strp("evt_1")}, {s: strp(`{"a":1}`)}, {i: &n}, {t: &at}},
+		{{s: strp("evt_\"2\"")}, {}, {}, {t: &at}},
+		{{s: strp("evt_3")}, {s: strp("not json")}, {}, {}},
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newNDJSONWriter(&buf, testCols)
+	for _, r := range testRows() {
+		require.NoError(t, enc.write(r))
+	}
+	require.NoError(t, enc.close())
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	var lines []map[string]any
+	sc := bufio.NewScanner(gz)
+	for sc.Scan() {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m), sc.Text())
+		lines = append(lines, m)
+	}
+	require.Len(t, lines, 3)
+	assert.Equal(t, map[string]any{"id": "evt_1", "data": map[string]any{"a": float64(1)}, "attempts": float64(3), "createdAt": "2026-03-15T12:00:00.5Z"}, lines[0])
+	assert.Equal(t, map[string]any{"id": `evt_"2"`, "data": nil, "attempts": nil, "createdAt": "2026-03-15T12:00:00.5Z"}, lines[1])
+	assert.Equal(t, "not json", lines[2]["data"], "invalid JSON text is exported as a string")
+}
+
+func TestObjectName(t *testing.T) {
+	e := &Export{ID: "exp_0ABC", Kind: KindDispatchJobs, Format: FormatParquet, CreatedAt: time.Date(2026, 3, 5, 23, 0, 0, 0, time.UTC)}
+	assert.Equal(t, "2026/03/05/exp_0ABC-dispatch_jobs.parquet", e.ObjectName())
+	e.Format = FormatNDJSON
+	assert.Equal(t, "2026/03/05/exp_0ABC-dispatch_jobs.ndjson.gz", e.ObjectName())
+}
@@ -0,0 +1,180 @@
+// Package export runs bulk exports of events and dispatch jobs to object
+// storage (S3, or GCS through its S3-interoperable XML API) for
+// compliance and offline analysis — volumes the paged BFF search
+// endpoints cap out on.
+//
+// POST /api/exports records an Export through the UoW (it is a
+// user-initiated request with an audit trail). The Runner then claims it
+// on any instance and streams the matching rows straight from Postgres
+// into a multipart upload, as gzipped NDJSON or Parquet. Progress
+// transitions are infrastructure-processing like the dispatch delivery
+// lifecycle: direct writes, no domain event. Once COMPLETED, the API
+// hands out short-lived presigned download links.
+package export
+
+import (
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// Kind is what is exported.
+type Kind string
+
+const (
+	KindEvents       Kind = "EVENTS"
+	KindDispatchJobs Kind = "DISPATCH_JOBS"
+)
+
+// ParseKind is the strict parser; ok is false for anything unknown.
+func ParseKind(s string) (Kind, bool) {
+	switch Kind(s) {
+	case KindEvents, KindDispatchJobs:
+		return Kind(s), true
+	}
+	return "", false
+}
+
+// Format is the object encoding.
+type Format string
+
+const (
+	// FormatNDJSON is gzipped newline-delimited JSON (.ndjson.gz).
+	FormatNDJSON  Format = "NDJSON"
+	FormatParquet Format = "PARQUET"
+)
+
+// ParseFormat is the strict parser; ok is false for anything unknown.
+func ParseFormat(s string) (Format, bool) {
+	switch Format(s) {
+	case FormatNDJSON, FormatParquet:
+		return Format(s), true
+	}
+	return "", false
+}
+
+// Extension is the object key suffix for the format.
+func (f Format) Extension() string {
+	if f == FormatParquet {
+		return ".parquet"
+	}
+	return ".ndjson.gz"
+}
+
+// ContentType is the uploaded object's Content-Type.
+func (f Format) ContentType() string {
+	if f == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "application/gzip"
+}
+
+// Status is the export lifecycle state.
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusRunning   Status = "RUNNING"
+	StatusCompleted Status = "COMPLETED"
+	StatusFailed    Status = "FAILED"
+)
+
+// Filter selects the exported rows. From/To bound created_at (half-open:
+// From inclusive, To exclusive).
+type Filter struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// EventTypes matches the event type (events) or the job code
+	// (dispatch jobs). Empty = all.
+	EventTypes []string `json:"eventTypes,omitempty"`
+	// ClientIDs narrows to these clients. Empty = all the requester may see.
+	ClientIDs []string `json:"clientIds,omitempty"`
+	// AccessibleClientIDs is the requester's tenant scope captured at
+	// request time; nil for anchors (no scoping). Client-scoped rows
+	// outside it are never exported.
+	AccessibleClientIDs *[]string `json:"accessibleClientIds,omitempty"`
+}
+
+// Export is the aggregate root.
+type Export struct {
+	ID          string     `json:"id"`
+	Kind        Kind       `json:"kind"`
+	Format      Format     `json:"format"`
+	Filter      Filter     `json:"filter"`
+	Status      Status     `json:"status"`
+	ObjectKey   *string    `json:"objectKey,omitempty"`
+	RowCount    int64      `json:"rowCount"`
+	ByteSize    int64      `json:"byteSize"`
+	Error       *string    `json:"error,omitempty"`
+	RequestedBy *string    `json:"requestedBy,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (e Export) IDStr() string { return e.ID }
+
+// New constructs a PENDING export.
+func New(kind Kind, format Format, filter Filter, requestedBy *string) *Export {
+	now := time.Now().UTC()
+	return &Export{
+		ID:          tsid.Generate(tsid.Export),
+		Kind:        kind,
+		Format:      format,
+		Filter:      filter,
+		Status:      StatusPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// ObjectName is the key the export is written under, below the
+// destination prefix.
+func (e *Export) ObjectName() string {
+	return e.CreatedAt.UTC().Format("2006/01/02/") + e.ID + "-" + strings.ToLower(string(e.Kind)) + e.Format.Extension()
+}
+
+// Config holds the export knobs (all env-overridable).
+type Config struct {
+	// Destination is s3://bucket[/prefix] or gs://bucket[/prefix]. Empty
+	// disables exports: requests are rejected and the runner idles.
+	Destination string
+	// Endpoint overrides the storage endpoint for S3-compatible stores
+	// (MinIO, LocalStack); objects are then addressed path-style.
+	Endpoint string
+	// Region is the S3 signing region; GCS always signs as "auto".
+	Region string
+	// AccessKeyID / SecretAccessKey are static credentials (GCS HMAC keys
+	// for gs://). When unset, s3:// uses the default AWS credential chain.
+	AccessKeyID     string
+	SecretAccessKey string
+	// URLTTL is how long a presigned download link stays valid.
+	URLTTL time.Duration
+	// PollInterval is how often an idle runner looks for queued exports.
+	PollInterval time.Duration
+	// StaleAfter is how long a RUNNING export may go without a heartbeat
+	// before another instance restarts it.
+	StaleAfter time.Duration
+}
+
+// ConfigFromEnv builds a Config from FC_EXPORT_* env vars.
+func ConfigFromEnv() Config {
+	return Config{
+		Destination:     envutil.Or("FC_EXPORT_DESTINATION", ""),
+		Endpoint:        envutil.Or("FC_EXPORT_ENDPOINT", ""),
+		Region:          envutil.Or("FC_EXPORT_REGION", envutil.Or("AWS_REGION", "us-east-1")),
+		AccessKeyID:     envutil.Or("FC_EXPORT_ACCESS_KEY_ID", ""),
+		SecretAccessKey: envutil.Or("FC_EXPORT_SECRET_ACCESS_KEY", ""),
+		URLTTL:          time.Duration(envutil.Int("FC_EXPORT_URL_TTL_SECS", 900)) * time.Second,
+		PollInterval:    5 * time.Second,
+		StaleAfter:      2 * time.Minute,
+	}
+}
+
+// Enabled reports whether a destination is configured.
+func (c Config) Enabled() bool { return c.Destination != "" }
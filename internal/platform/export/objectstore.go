@@ -0,0 +1,310 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// defaultPartSize is the multipart upload part size. S3 requires at least
+// 5 MiB for every part but the last.
+const defaultPartSize = 8 << 20
+
+// ErrNotFound is wrapped by the error for a 404: no such object (or
+// bucket).
+var ErrNotFound = errors.New("object not found")
+
+// ObjectStore writes export objects and presigns their download links
+// over the S3 REST API with SigV4. GCS is reached through its
+// S3-interoperable XML API (storage.googleapis.com with HMAC keys), so one
+// client covers both without pulling in either SDK's storage package.
+type ObjectStore struct {
+	bucket, prefix string
+	// base is the endpoint; with pathStyle the bucket is the first path
+	// segment, otherwise it is already part of the host.
+	base      string
+	pathStyle bool
+	region    string
+
+	creds    aws.CredentialsProvider
+	signer   *v4.Signer
+	client   *http.Client
+	urlTTL   time.Duration
+	partSize int
+}
+
+// NewObjectStore resolves cfg.Destination and its credentials.
+func NewObjectStore(ctx context.Context, cfg Config) (*ObjectStore, error) {
+	scheme, bucket, prefix, err := parseDestination(cfg.Destination)
+	if err != nil {
+		return nil, err
+	}
+	s := &ObjectStore{
+		bucket:   bucket,
+		prefix:   prefix,
+		region:   cfg.Region,
+		signer:   v4.NewSigner(func(o *v4.SignerOptions) { o.DisableURIPathEscaping = true }),
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		urlTTL:   cfg.URLTTL,
+		partSize: defaultPartSize,
+	}
+	switch {
+	case cfg.Endpoint != "":
+		s.base, s.pathStyle = strings.TrimRight(cfg.Endpoint, "/"), true
+	case scheme == "gs":
+		s.base, s.pathStyle = "https://storage.googleapis.com", true
+	default:
+		s.base = "https://" + bucket + ".s3." + cfg.Region + ".amazonaws.com"
+	}
+	if scheme == "gs" {
+		s.region = "auto"
+	}
+
+	var provider aws.CredentialsProvider
+	switch {
+	case cfg.AccessKeyID != "":
+		provider = credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+	case scheme == "gs":
+		return nil, errors.New("gs:// export destinations need FC_EXPORT_ACCESS_KEY_ID / FC_EXPORT_SECRET_ACCESS_KEY (GCS HMAC keys)")
+	default:
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+		if err != nil {
+			return nil, fmt.Errorf("aws config: %w", err)
+		}
+		provider = awsCfg.Credentials
+	}
+	s.creds = aws.NewCredentialsCache(provider)
+	return s, nil
+}
+
+// parseDestination splits s3://bucket/prefix or gs://bucket/prefix. A
+// non-empty prefix is returned with a trailing slash.
+func parseDestination(dest string) (scheme, bucket, prefix string, err error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return "", "", "", fmt.Errorf("export destination: %w", err)
+	}
+	if (u.Scheme != "s3" && u.Scheme != "gs") || u.Host == "" {
+		return "", "", "", fmt.Errorf("export destination %q: want s3://bucket[/prefix] or gs://bucket[/prefix]", dest)
+	}
+	prefix = strings.Trim(u.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return u.Scheme, u.Host, prefix, nil
+}
+
+// Key is the full object key for name under the destination prefix.
+func (s *ObjectStore) Key(name string) string { return s.prefix + name }
+
+func (s *ObjectStore) objectURL(key string, query url.Values) string {
+	segs := strings.Split(key, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	u := s.base + "/"
+	if s.pathStyle {
+		u += s.bucket + "/"
+	}
+	u += strings.Join(segs, "/")
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// Upload streams r to key and returns the bytes written. Input up to one
+// part goes up as a single PUT; anything larger as a multipart upload,
+// which is aborted if any step fails so no orphaned parts are billed.
+func (s *ObjectStore) Upload(ctx context.Context, key, contentType string, r io.Reader) (int64, error) {
+	buf := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		resp, err := s.do(ctx, http.MethodPut, key, nil, buf[:n], contentType)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return int64(n), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	uploadID, err := s.initiate(ctx, key, contentType)
+	if err != nil {
+		return 0, err
+	}
+	total, err := s.uploadParts(ctx, key, uploadID, buf, n, r)
+	if err != nil {
+		actx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if resp, aerr := s.do(actx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, ""); aerr == nil {
+			resp.Body.Close()
+		}
+		return 0, err
+	}
+	return total, nil
+}
+
+// Get reads the whole object at key.
+func (s *ObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Delete removes the object at key. S3 reports success for a missing key,
+// so a repeated delete is harmless.
+func (s *ObjectStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts sends the first n bytes of buf as part 1, then the rest of r
+// one part at a time, and completes the upload.
+func (s *ObjectStore) uploadParts(ctx context.Context, key, uploadID string, buf []byte, n int, r io.Reader) (int64, error) {
+	var parts []completedPart
+	var total int64
+	for n > 0 {
+		num := len(parts) + 1
+		resp, err := s.do(ctx, http.MethodPut, key,
+			url.Values{"partNumber": {strconv.Itoa(num)}, "uploadId": {uploadID}}, buf[:n], "")
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		parts = append(parts, completedPart{PartNumber: num, ETag: resp.Header.Get("ETag")})
+		total += int64(n)
+
+		n, err = io.ReadFull(r, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, err
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body, "application/xml")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// CompleteMultipartUpload can fail after the 200 status line has been
+	// sent; the error is then in the body.
+	var result struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err == nil && result.XMLName.Local == "Error" {
+		return 0, fmt.Errorf("complete multipart upload: %s: %s", result.Code, result.Message)
+	}
+	return total, nil
+}
+
+func (s *ObjectStore) initiate(ctx context.Context, key, contentType string) (string, error) {
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, contentType)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("initiate multipart upload: %w", err)
+	}
+	if result.UploadID == "" {
+		return "", errors.New("initiate multipart upload: no upload id")
+	}
+	return result.UploadID, nil
+}
+
+// do sends one signed request. A non-2xx response is returned as an
+// error carrying the start of the body.
+func (s *ObjectStore) do(ctx context.Context, method, key string, query url.Values, body []byte, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key, query), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", hash)
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("object store credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, hash, "s3", s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("sign request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("%s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+		if resp.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PresignGet returns a download URL for key valid until the returned time.
+func (s *ObjectStore) PresignGet(ctx context.Context, key string) (string, time.Time, error) {
+	now := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		s.objectURL(key, url.Values{"X-Amz-Expires": {strconv.Itoa(int(s.urlTTL.Seconds()))}}), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("object store credentials: %w", err)
+	}
+	signed, _, err := s.signer.PresignHTTP(ctx, creds, req, "UNSIGNED-PAYLOAD", "s3", s.region, now)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("presign: %w", err)
+	}
+	return signed, now.Add(s.urlTTL), nil
+}
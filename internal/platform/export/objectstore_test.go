@@ -0,0 +1,215 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 implements just enough of the S3 object API for the store:
+// PUT / GET / DELETE object, and multipart initiate / upload part / complete / abort.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	aborted []string
+	failOn  int // upload part number to reject
+	calls   []string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned", http.StatusForbidden)
+		return
+	}
+	key := r.URL.Path
+	q := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+	f.calls = append(f.calls, r.Method+" "+key)
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := fmt.Sprintf("up-%d", len(f.uploads)+1)
+		f.uploads[id] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == http.MethodPut && q.Has("partNumber"):
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		if n == f.failOn {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		f.uploads[q.Get("uploadId")][n] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var req struct {
+			Parts []completedPart `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var obj []byte
+		for i, p := range req.Parts {
+			if p.PartNumber != i+1 || p.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+				http.Error(w, "bad part list", http.StatusBadRequest)
+				return
+			}
+			obj = append(obj, f.uploads[q.Get("uploadId")][p.PartNumber]...)
+		}
+		f.objects[key] = obj
+		io.WriteString(w, "<CompleteMultipartUploadResult/>")
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		f.aborted = append(f.aborted, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+	case r.Method == http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(obj)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func testStore(t *testing.T, endpoint string) *ObjectStore {
+	s, err := NewObjectStore(context.Background(), Config{
+		Destination:     "s3://exports/fc",
+		Endpoint:        endpoint,
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		URLTTL:          10 * time.Minute,
+	})
+	require.NoError(t, err)
+	s.partSize = 4
+	return s
+}
+
+func TestObjectStore_SmallUploadIsSinglePut(t *testing.T) {
+	f, srv := newFakeS3(t)
+	s := testStore(t, srv.URL)
+
+	key := s.Key("2026/03/15/exp_1-events.ndjson.gz")
+	n, err := s.Upload(context.Background(), key, "application/gzip", strings.NewReader("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, []string{"PUT /exports/fc/2026/03/15/exp_1-events.ndjson.gz"}, f.calls)
+	assert.Equal(t, "abc", string(f.objects["/exports/fc/2026/03/15/exp_1-events.ndjson.gz"]))
+}
+
+func TestObjectStore_GetAndDelete(t *testing.T) {
+	f, srv := newFakeS3(t)
+	s := testStore(t, srv.URL)
+	ctx := context.Background()
+
+	key := s.Key("dispatch-jobs/2026/03/15/job_1")
+	_, err := s.Upload(ctx, key, "application/json", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	got, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(got))
+
+	require.NoError(t, s.Delete(ctx, key))
+	assert.Empty(t, f.objects)
+	_, err = s.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "404")
+	require.NoError(t, s.Delete(ctx, key), "deleting a missing key is not an error")
+}
+
+func TestObjectStore_MultipartUpload(t *testing.T) {
+	f, srv := newFakeS3(t)
+	s := testStore(t, srv.URL)
+
+	n, err := s.Upload(context.Background(), s.Key("big"), "application/gzip", strings.NewReader("0123456789"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+	assert.Equal(t, "0123456789", string(f.objects["/exports/fc/big"]))
+	assert.Len(t, f.calls, 5, "initiate, three parts, complete")
+	assert.Empty(t, f.aborted)
+}
+
+func TestObjectStore_FailedPartAbortsUpload(t *testing.T) {
+	f, srv := newFakeS3(t)
+	f.failOn = 2
+	s := testStore(t, srv.URL)
+
+	_, err := s.Upload(context.Background(), s.Key("big"), "application/gzip", bytes.NewReader(make([]byte, 10)))
+	require.Error(t, err)
+	assert.Equal(t, []string{"up-1"}, f.aborted)
+	assert.NotContains(t, f.objects, "/exports/fc/big")
+}
+
+func TestObjectStore_PresignGet(t *testing.T) {
+	s := testStore(t, "https://minio.internal:9000")
+	link, expires, err := s.PresignGet(context.Background(), s.Key("2026/03/15/exp_1-events.parquet"))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), expires, time.Minute)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "minio.internal:9000", u.Host)
+	assert.Equal(t, "/exports/fc/2026/03/15/exp_1-events.parquet", u.Path)
+	q := u.Query()
+	assert.Equal(t, "600", q.Get("X-Amz-Expires"))
+	assert.True(t, strings.HasPrefix(q.Get("X-Amz-Credential"), "AKID/"))
+	assert.NotEmpty(t, q.Get("X-Amz-Signature"))
+}
+
+func TestParseDestination(t *testing.T) {
+	_, bucket, prefix, err := parseDestination("gs://archive/flowcatalyst/exports/")
+	require.NoError(t, err)
+	assert.Equal(t, "archive", bucket)
+	assert.Equal(t, "flowcatalyst/exports/", prefix)
+
+	_, _, prefix, err = parseDestination("s3://archive")
+	require.NoError(t, err)
+	assert.Empty(t, prefix)
+
+	for _, bad := range []string{"archive", "https://archive/x", "s3:///x"} {
+		_, _, _, err := parseDestination(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestNewObjectStore_Endpoints(t *testing.T) {
+	s, err := NewObjectStore(context.Background(), Config{Destination: "s3://archive/x", Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "s"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://archive.s3.eu-west-1.amazonaws.com/x/k", s.objectURL(s.Key("k"), nil))
+
+	s, err = NewObjectStore(context.Background(), Config{Destination: "gs://archive", Region: "eu-west-1", AccessKeyID: "GOOG", SecretAccessKey: "s"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com/archive/k", s.objectURL(s.Key("k"), nil))
+	assert.Equal(t, "auto", s.region)
+
+	_, err = NewObjectStore(context.Background(), Config{Destination: "gs://archive"})
+	assert.Error(t, err, "GCS needs HMAC keys")
+}
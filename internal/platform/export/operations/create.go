@@ -0,0 +1,75 @@
+// Package operations holds the export use cases.
+package operations
+
+import (
+	"context"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// CreateExportCommand is the input DTO. AccessibleClientIDs is the
+// requester's tenant scope (nil for anchors), resolved by the handler.
+type CreateExportCommand struct {
+	Kind                string    `json:"kind"`
+	Format              string    `json:"format"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+	EventTypes          []string  `json:"eventTypes,omitempty"`
+	ClientIDs           []string  `json:"clientIds,omitempty"`
+	AccessibleClientIDs *[]string `json:"-"`
+}
+
+// CreateExport queues a PENDING export and emits [ExportRequested].
+func CreateExport(repo *export.Repository, cfg export.Config) usecaseop.Operation[CreateExportCommand, ExportRequested] {
+	return usecaseop.Operation[CreateExportCommand, ExportRequested]{
+		Name: "CreateExport",
+		Validate: func(_ context.Context, cmd CreateExportCommand) error {
+			if !cfg.Enabled() {
+				return usecase.Validation("EXPORTS_NOT_CONFIGURED", "no export destination is configured (FC_EXPORT_DESTINATION)")
+			}
+			if _, ok := export.ParseKind(cmd.Kind); !ok {
+				return usecase.Validation("INVALID_KIND", "kind must be EVENTS or DISPATCH_JOBS")
+			}
+			if _, ok := export.ParseFormat(cmd.Format); !ok {
+				return usecase.Validation("INVALID_FORMAT", "format must be NDJSON or PARQUET")
+			}
+			if cmd.From.IsZero() || cmd.To.IsZero() {
+				return usecase.Validation("TIME_RANGE_REQUIRED", "from and to are required")
+			}
+			if !cmd.From.Before(cmd.To) {
+				return usecase.Validation("INVALID_TIME_RANGE", "from must be before to")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[CreateExportCommand],
+		Execute: func(_ context.Context, cmd CreateExportCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ExportRequested], error) {
+			kind, _ := export.ParseKind(cmd.Kind)
+			format, _ := export.ParseFormat(cmd.Format)
+			var requestedBy *string
+			if ec.PrincipalID != "" {
+				requestedBy = &ec.PrincipalID
+			}
+			e := export.New(kind, format, export.Filter{
+				From:                cmd.From.UTC(),
+				To:                  cmd.To.UTC(),
+				EventTypes:          cmd.EventTypes,
+				ClientIDs:           cmd.ClientIDs,
+				AccessibleClientIDs: cmd.AccessibleClientIDs,
+			}, requestedBy)
+			event := ExportRequested{
+				Metadata:   usecase.NewEventMetadata(ec, ExportRequestedType, Source, subjectFor(e.ID)),
+				ExportID:   e.ID,
+				Kind:       string(e.Kind),
+				Format:     string(e.Format),
+				From:       e.Filter.From,
+				To:         e.Filter.To,
+				EventTypes: e.Filter.EventTypes,
+				ClientIDs:  e.Filter.ClientIDs,
+			}
+			return usecaseop.Save(e, repo, event), nil
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package operations
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+const (
+	ExportRequestedType = "platform:admin:export:requested"
+	Source              = "platform:admin"
+)
+
+func subjectFor(id string) string { return "platform.export." + id }
+func groupFor(id string) string   { return "platform:export:" + id }
+
+type ExportRequested struct {
+	Metadata   usecase.EventMetadata
+	ExportID   string
+	Kind       string
+	Format     string
+	From       time.Time
+	To         time.Time
+	EventTypes []string
+	ClientIDs  []string
+}
+
+func (e ExportRequested) EventID() string       { return e.Metadata.EventID }
+func (e ExportRequested) EventType() string     { return ExportRequestedType }
+func (e ExportRequested) SpecVersion() string   { return "1.0" }
+func (e ExportRequested) Source() string        { return Source }
+func (e ExportRequested) Subject() string       { return subjectFor(e.ExportID) }
+func (e ExportRequested) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ExportRequested) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ExportRequested) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ExportRequested) CausationID() string   { return e.Metadata.CausationID }
+func (e ExportRequested) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ExportRequested) MessageGroup() string  { return groupFor(e.ExportID) }
+func (e ExportRequested) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ExportID   string    `json:"exportId"`
+		Kind       string    `json:"kind"`
+		Format     string    `json:"format"`
+		From       time.Time `json:"from"`
+		To         time.Time `json:"to"`
+		EventTypes []string  `json:"eventTypes,omitempty"`
+		ClientIDs  []string  `json:"clientIds,omitempty"`
+	}{e.ExportID, e.Kind, e.Format, e.From, e.To, e.EventTypes, e.ClientIDs})
+}
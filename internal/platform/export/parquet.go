@@ -0,0 +1,367 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+)
+
+// parquetWriter is a minimal Apache Parquet writer covering what exports
+// need: a flat schema of OPTIONAL columns (UTF8 byte arrays, INT64, and
+// INT64 TIMESTAMP_MILLIS), one v1 data page per column chunk, PLAIN
+// values, RLE/bit-packed definition levels and GZIP compression. Rows are
+// buffered per column and flushed as a row group every rowGroupRows rows
+// or rowGroupBytes of buffered values, whichever comes first.
+type parquetWriter struct {
+	w    *countingWriter
+	cols []column
+
+	bufs    []parquetColumn
+	rows    int64 // rows buffered in the current group
+	buffed  int   // value bytes buffered in the current group
+	total   int64
+	groups  []rowGroupMeta
+	started bool
+}
+
+const (
+	rowGroupRows  = 100_000
+	rowGroupBytes = 64 << 20
+)
+
+// parquetColumn buffers one column of the current row group.
+type parquetColumn struct {
+	defs   []bool // per row: value present
+	values bytes.Buffer
+}
+
+type rowGroupMeta struct {
+	numRows   int64
+	totalSize int64
+	chunks    []chunkMeta
+}
+
+type chunkMeta struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// Parquet physical types, converted types and enums used below.
+const (
+	pqTypeInt64     = 2
+	pqTypeByteArray = 6
+
+	pqConvertedUTF8            = 0
+	pqConvertedTimestampMillis = 9
+
+	pqRepetitionOptional = 1
+
+	pqEncodingPlain = 0
+	pqEncodingRLE   = 3
+
+	pqCodecGzip = 2
+
+	pqPageData = 0
+)
+
+var parquetMagic = []byte("PAR1")
+
+func physicalType(c column) int32 {
+	if c.typ == colInt64 || c.typ == colTime {
+		return pqTypeInt64
+	}
+	return pqTypeByteArray
+}
+
+func newParquetWriter(w io.Writer, cols []column) *parquetWriter {
+	return &parquetWriter{w: &countingWriter{w: w}, cols: cols, bufs: make([]parquetColumn, len(cols))}
+}
+
+func (p *parquetWriter) write(cells []cell) error {
+	for i, c := range p.cols {
+		b := &p.bufs[i]
+		v := cells[i]
+		if v.null() {
+			b.defs = append(b.defs, false)
+			continue
+		}
+		b.defs = append(b.defs, true)
+		var scratch [8]byte
+		switch c.typ {
+		case colInt64:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(*v.i))
+			b.values.Write(scratch[:])
+			p.buffed += 8
+		case colTime:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(v.t.UnixMilli()))
+			b.values.Write(scratch[:])
+			p.buffed += 8
+		default:
+			binary.LittleEndian.PutUint32(scratch[:4], uint32(len(*v.s)))
+			b.values.Write(scratch[:4])
+			b.values.WriteString(*v.s)
+			p.buffed += 4 + len(*v.s)
+		}
+	}
+	p.rows++
+	if p.rows >= rowGroupRows || p.buffed >= rowGroupBytes {
+		return p.flush()
+	}
+	return nil
+}
+
+func (p *parquetWriter) close() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if !p.started {
+		if _, err := p.w.Write(parquetMagic); err != nil {
+			return err
+		}
+	}
+	footer := p.footer()
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	for _, b := range [][]byte{footer, size[:], parquetMagic} {
+		if _, err := p.w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush writes the buffered rows as one row group.
+func (p *parquetWriter) flush() error {
+	if p.rows == 0 {
+		return nil
+	}
+	if !p.started {
+		if _, err := p.w.Write(parquetMagic); err != nil {
+			return err
+		}
+		p.started = true
+	}
+	g := rowGroupMeta{numRows: p.rows, chunks: make([]chunkMeta, len(p.cols))}
+	for i := range p.cols {
+		chunk, err := p.writeChunk(&p.bufs[i])
+		if err != nil {
+			return err
+		}
+		g.chunks[i] = chunk
+		g.totalSize += chunk.uncompressedSize
+		p.bufs[i] = parquetColumn{}
+	}
+	p.groups = append(p.groups, g)
+	p.total += p.rows
+	p.rows, p.buffed = 0, 0
+	return nil
+}
+
+// writeChunk writes one column chunk as a single data page.
+func (p *parquetWriter) writeChunk(b *parquetColumn) (chunkMeta, error) {
+	var page bytes.Buffer
+	levels := encodeDefLevels(b.defs)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(levels)))
+	page.Write(size[:])
+	page.Write(levels)
+	page.Write(b.values.Bytes())
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(page.Bytes()); err != nil {
+		return chunkMeta{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return chunkMeta{}, err
+	}
+
+	var t thriftWriter
+	t.structBegin()
+	t.i32Field(1, pqPageData)
+	t.i32Field(2, int32(page.Len()))
+	t.i32Field(3, int32(compressed.Len()))
+	t.fieldHeader(5, thriftStruct)
+	t.structBegin()
+	t.i32Field(1, int32(len(b.defs)))
+	t.i32Field(2, pqEncodingPlain)
+	t.i32Field(3, pqEncodingRLE)
+	t.i32Field(4, pqEncodingRLE)
+	t.structEnd()
+	t.structEnd()
+
+	meta := chunkMeta{
+		offset:           p.w.n,
+		numValues:        int64(len(b.defs)),
+		uncompressedSize: int64(t.buf.Len() + page.Len()),
+		compressedSize:   int64(t.buf.Len() + compressed.Len()),
+	}
+	if _, err := p.w.Write(t.buf.Bytes()); err != nil {
+		return chunkMeta{}, err
+	}
+	if _, err := p.w.Write(compressed.Bytes()); err != nil {
+		return chunkMeta{}, err
+	}
+	return meta, nil
+}
+
+// encodeDefLevels encodes bit-width-1 definition levels as a single
+// bit-packed run of the RLE/bit-packing hybrid, padded to a multiple of 8.
+func encodeDefLevels(defs []bool) []byte {
+	groups := (len(defs) + 7) / 8
+	out := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	packed := make([]byte, groups)
+	for i, d := range defs {
+		if d {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(out, packed...)
+}
+
+// footer encodes the FileMetaData.
+func (p *parquetWriter) footer() []byte {
+	var t thriftWriter
+	t.structBegin()
+	t.i32Field(1, 1)
+
+	t.fieldHeader(2, thriftList)
+	t.listHeader(len(p.cols)+1, thriftStruct)
+	t.structBegin()
+	t.binaryField(4, "schema")
+	t.i32Field(5, int32(len(p.cols)))
+	t.structEnd()
+	for _, c := range p.cols {
+		t.structBegin()
+		t.i32Field(1, physicalType(c))
+		t.i32Field(3, pqRepetitionOptional)
+		t.binaryField(4, c.name)
+		switch c.typ {
+		case colTime:
+			t.i32Field(6, pqConvertedTimestampMillis)
+		case colString, colJSON:
+			t.i32Field(6, pqConvertedUTF8)
+		}
+		t.structEnd()
+	}
+
+	t.i64Field(3, p.total)
+
+	t.fieldHeader(4, thriftList)
+	t.listHeader(len(p.groups), thriftStruct)
+	for _, g := range p.groups {
+		t.structBegin()
+		t.fieldHeader(1, thriftList)
+		t.listHeader(len(g.chunks), thriftStruct)
+		for i, ch := range g.chunks {
+			c := p.cols[i]
+			t.structBegin()
+			t.i64Field(2, ch.offset)
+			t.fieldHeader(3, thriftStruct)
+			t.structBegin()
+			t.i32Field(1, physicalType(c))
+			t.fieldHeader(2, thriftList)
+			t.listHeader(2, thriftI32)
+			t.zigzag(pqEncodingPlain)
+			t.zigzag(pqEncodingRLE)
+			t.fieldHeader(3, thriftList)
+			t.listHeader(1, thriftBinary)
+			t.binary(c.name)
+			t.i32Field(4, pqCodecGzip)
+			t.i64Field(5, ch.numValues)
+			t.i64Field(6, ch.uncompressedSize)
+			t.i64Field(7, ch.compressedSize)
+			t.i64Field(9, ch.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64Field(2, g.totalSize)
+		t.i64Field(3, g.numRows)
+		t.structEnd()
+	}
+
+	t.binaryField(6, "flowcatalyst")
+	t.structEnd()
+	return t.buf.Bytes()
+}
+
+// countingWriter tracks the file offset column chunk metadata refers to.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// ── Thrift compact protocol (the subset Parquet metadata uses) ───────────
+
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // last field id per open struct
+}
+
+func (t *thriftWriter) structBegin() { t.last = append(t.last, 0) }
+
+func (t *thriftWriter) structEnd() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	top := &t.last[len(t.last)-1]
+	if delta := id - *top; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.zigzag(int64(id))
+	}
+	*top = id
+}
+
+func (t *thriftWriter) listHeader(n int, elem byte) {
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elem)
+		return
+	}
+	t.buf.WriteByte(0xF0 | elem)
+	t.varint(uint64(n))
+}
+
+func (t *thriftWriter) varint(v uint64) { t.buf.Write(binary.AppendUvarint(nil, v)) }
+
+func (t *thriftWriter) zigzag(v int64) { t.varint(uint64(v<<1) ^ uint64(v>>63)) }
+
+func (t *thriftWriter) binary(s string) {
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.zigzag(v)
+}
+
+func (t *thriftWriter) binaryField(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary(s)
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes the compact protocol into field-id keyed maps, so
+// the test checks the footer independently of the writer's helpers.
+type thriftReader struct {
+	t *testing.T
+	r *bytes.Reader
+}
+
+func (d thriftReader) uvarint() uint64 {
+	v, err := binary.ReadUvarint(d.r)
+	require.NoError(d.t, err)
+	return v
+}
+
+func (d thriftReader) zigzag() int64 {
+	v := d.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return d.zigzag()
+	case thriftBinary:
+		b := make([]byte, d.uvarint())
+		_, err := io.ReadFull(d.r, b)
+		require.NoError(d.t, err)
+		return string(b)
+	case thriftList:
+		h, err := d.r.ReadByte()
+		require.NoError(d.t, err)
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(d.uvarint())
+		}
+		out := make([]any, n)
+		for i := range out {
+			out[i] = d.value(h & 0x0F)
+		}
+		return out
+	case thriftStruct:
+		return d.structure()
+	}
+	d.t.Fatalf("unexpected thrift type %d", typ)
+	return nil
+}
+
+func (d thriftReader) structure() map[int16]any {
+	out := map[int16]any{}
+	var last int16
+	for {
+		h, err := d.r.ReadByte()
+		require.NoError(d.t, err)
+		if h == 0 {
+			return out
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(d.zigzag())
+		}
+		out[id] = d.value(h & 0x0F)
+		last = id
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newParquetWriter(&buf, testCols)
+	for _, r := range testRows() {
+		require.NoError(t, enc.write(r))
+	}
+	require.NoError(t, enc.close())
+
+	file := buf.Bytes()
+	require.Equal(t, "PAR1", string(file[:4]))
+	require.Equal(t, "PAR1", string(file[len(file)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := file[len(file)-8-footerLen : len(file)-8]
+	meta := thriftReader{t, bytes.NewReader(footer)}.structure()
+
+	assert.Equal(t, int64(3), meta[3], "num_rows")
+	schema := meta[2].([]any)
+	require.Len(t, schema, 5)
+	assert.Equal(t, int64(4), schema[0].(map[int16]any)[5], "root num_children")
+	var names []any
+	for _, el := range schema[1:] {
+		names = append(names, el.(map[int16]any)[4])
+	}
+	assert.Equal(t, []any{"id", "data", "attempts", "createdAt"}, names)
+	assert.Equal(t, int64(pqConvertedTimestampMillis), schema[4].(map[int16]any)[6])
+
+	groups := meta[4].([]any)
+	require.Len(t, groups, 1)
+	chunks := groups[0].(map[int16]any)[1].([]any)
+	require.Len(t, chunks, 4)
+
+	// Decode the "attempts" column: one value (3) in row 0, null after.
+	cm := chunks[2].(map[int16]any)[3].(map[int16]any)
+	assert.Equal(t, int64(3), cm[5], "num_values counts nulls")
+	r := bytes.NewReader(file[cm[9].(int64):])
+	page := thriftReader{t, r}.structure()
+	compressed := make([]byte, page[3].(int64))
+	_, err := io.ReadFull(r, compressed)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Len(t, raw, int(page[2].(int64)))
+
+	levelsLen := binary.LittleEndian.Uint32(raw)
+	levels := raw[4 : 4+levelsLen]
+	assert.Equal(t, []byte{1<<1 | 1, 0b001}, levels, "one bit-packed group: only row 0 defined")
+	values := raw[4+levelsLen:]
+	require.Len(t, values, 8)
+	assert.Equal(t, uint64(3), binary.LittleEndian.Uint64(values))
+}
+
+func TestParquetWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newParquetWriter(&buf, testCols)
+	require.NoError(t, enc.close())
+	file := buf.Bytes()
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	require.Equal(t, 4+footerLen+8, len(file))
+	meta := thriftReader{t, bytes.NewReader(file[4 : 4+footerLen])}.structure()
+	assert.Equal(t, int64(0), meta[3])
+	assert.Empty(t, meta[4])
+}
@@ -0,0 +1,210 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// Repository reads/writes msg_exports. New exports go through the UoW
+// via Persist; the runner's claim/heartbeat/finish transitions are
+// direct writes.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+const selectCols = `id, kind, format, filter, status, object_key, row_count, byte_size,
+	error, requested_by, created_at, started_at, completed_at, updated_at`
+
+func scanExport(row pgx.Row) (*Export, error) {
+	var e Export
+	var filter []byte
+	if err := row.Scan(&e.ID, &e.Kind, &e.Format, &filter, &e.Status, &e.ObjectKey,
+		&e.RowCount, &e.ByteSize, &e.Error, &e.RequestedBy,
+		&e.CreatedAt, &e.StartedAt, &e.CompletedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filter, &e.Filter); err != nil {
+		return nil, fmt.Errorf("decode filter: %w", err)
+	}
+	return &e, nil
+}
+
+// FindByID loads one export. Returns (nil, nil) when not found.
+func (r *Repository) FindByID(ctx context.Context, id string) (*Export, error) {
+	e, err := scanExport(r.pool.QueryRow(ctx, `SELECT `+selectCols+` FROM msg_exports WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find_export: %w", err)
+	}
+	return e, nil
+}
+
+// List returns exports newest first, narrowed to one requester when
+// requestedBy is non-nil.
+func (r *Repository) List(ctx context.Context, requestedBy *string, limit int) ([]*Export, error) {
+	var f repocommon.Filter
+	f.EqPtr("requested_by", requestedBy)
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+selectCols+` FROM msg_exports`+f.Where()+
+			fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d`, f.Arg(limit)),
+		f.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("list_exports: %w", err)
+	}
+	defer rows.Close()
+	var out []*Export
+	for rows.Next() {
+		e, err := scanExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list_exports: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Persist implements usecasepgx.Persist[Export].
+func (r *Repository) Persist(ctx context.Context, e *Export, tx *usecasepgx.DbTx) error {
+	filter, err := json.Marshal(e.Filter)
+	if err != nil {
+		return fmt.Errorf("persist export: %w", err)
+	}
+	_, err = tx.Inner().Exec(ctx,
+		`INSERT INTO msg_exports (id, kind, format, filter, status, requested_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (id) DO UPDATE
+		    SET status     = EXCLUDED.status,
+		        updated_at = EXCLUDED.updated_at`,
+		e.ID, e.Kind, e.Format, filter, e.Status, e.RequestedBy, e.CreatedAt, e.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("persist export: %w", err)
+	}
+	return nil
+}
+
+// Delete implements usecasepgx.Persist[Export].
+func (r *Repository) Delete(ctx context.Context, e *Export, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx, `DELETE FROM msg_exports WHERE id = $1`, e.ID)
+	return err
+}
+
+// Claim moves the oldest PENDING export — or a RUNNING one whose
+// heartbeat is older than staleAfter — to RUNNING and returns it.
+// Returns (nil, nil) when there is nothing to do.
+func (r *Repository) Claim(ctx context.Context, staleAfter time.Duration) (*Export, error) {
+	e, err := scanExport(r.pool.QueryRow(ctx,
+		`UPDATE msg_exports
+		    SET status = 'RUNNING', started_at = NOW(), heartbeat_at = NOW(), updated_at = NOW()
+		  WHERE id = (
+		        SELECT id FROM msg_exports
+		         WHERE status = 'PENDING'
+		            OR (status = 'RUNNING' AND heartbeat_at < $1)
+		         ORDER BY created_at
+		         LIMIT 1
+		         FOR UPDATE SKIP LOCKED)
+		 RETURNING `+selectCols,
+		time.Now().Add(-staleAfter).UTC()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim_export: %w", err)
+	}
+	return e, nil
+}
+
+// Heartbeat marks a RUNNING export as still in progress.
+func (r *Repository) Heartbeat(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_exports SET heartbeat_at = NOW() WHERE id = $1 AND status = 'RUNNING'`, id)
+	if err != nil {
+		return fmt.Errorf("heartbeat_export: %w", err)
+	}
+	return nil
+}
+
+// MarkCompleted records the written object.
+func (r *Repository) MarkCompleted(ctx context.Context, id, objectKey string, rowCount, byteSize int64) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_exports
+		    SET status = 'COMPLETED', object_key = $2, row_count = $3, byte_size = $4,
+		        error = NULL, completed_at = NOW(), updated_at = NOW()
+		  WHERE id = $1`,
+		id, objectKey, rowCount, byteSize)
+	if err != nil {
+		return fmt.Errorf("complete_export: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records why an export could not be written.
+func (r *Repository) MarkFailed(ctx context.Context, id, reason string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_exports
+		    SET status = 'FAILED', error = $2, completed_at = NOW(), updated_at = NOW()
+		  WHERE id = $1`,
+		id, reason)
+	if err != nil {
+		return fmt.Errorf("fail_export: %w", err)
+	}
+	return nil
+}
+
+// Stream runs the export's row query and calls fn once per row with the
+// scanned cells, in created_at order. The cells slice is reused between
+// calls. Reads the write-side tables so nothing is missed to projection
+// lag.
+func (r *Repository) Stream(ctx context.Context, kind Kind, filter Filter, fn func([]cell) error) error {
+	table, cols, typeCol := "msg_events", eventColumns, "type"
+	if kind == KindDispatchJobs {
+		table, cols, typeCol = "msg_dispatch_jobs", dispatchJobColumns, "code"
+	}
+	exprs := make([]string, len(cols))
+	for i, c := range cols {
+		exprs[i] = c.sql
+	}
+
+	var f repocommon.Filter
+	f.Clause("created_at >= $%d", filter.From)
+	f.Clause("created_at < $%d", filter.To)
+	f.Any(typeCol, filter.EventTypes)
+	f.Any("client_id", filter.ClientIDs)
+	if filter.AccessibleClientIDs != nil {
+		f.Clause("(client_id IS NULL OR client_id = ANY($%d))", *filter.AccessibleClientIDs)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+strings.Join(exprs, ", ")+` FROM `+table+f.Where()+` ORDER BY created_at, id`,
+		f.Args()...)
+	if err != nil {
+		return fmt.Errorf("stream %s: %w", table, err)
+	}
+	defer rows.Close()
+	cells := make([]cell, len(cols))
+	dest := scanTargets(cols, cells)
+	for rows.Next() {
+		for i := range cells {
+			cells[i] = cell{}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("stream %s: %w", table, err)
+		}
+		if err := fn(cells); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
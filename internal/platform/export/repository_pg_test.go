@@ -0,0 +1,107 @@
+//go:build integration
+
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+// TestStream_TenantScoping pins that an export only ever contains rows
+// inside the requester's captured scope, whatever clientIds it asked for.
+func TestStream_TenantScoping(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := NewRepository(pool)
+
+	const (
+		typ     = "export.test.event"
+		clientA = "clt_exporttest01"
+		clientB = "clt_exporttest02"
+	)
+	now := time.Now().UTC()
+	seed := func(id string, clientID *string) {
+		t.Helper()
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_events (id, type, source, time, data, client_id, created_at)
+			 VALUES ($1, $2, 'test://export', $3, '{"n":1}', $4, $3)`,
+			id, typ, now, clientID)
+		require.NoError(t, err)
+	}
+	a, b := clientA, clientB
+	seed("evtexporttst1", &a)
+	seed("evtexporttst2", &b)
+	seed("evtexporttst3", nil)
+
+	ids := func(f Filter) []string {
+		t.Helper()
+		var out []string
+		require.NoError(t, repo.Stream(ctx, KindEvents, f, func(cells []cell) error {
+			out = append(out, *cells[0].s)
+			return nil
+		}))
+		return out
+	}
+	base := Filter{From: now.Add(-time.Minute), To: now.Add(time.Minute), EventTypes: []string{typ}}
+
+	assert.Equal(t, []string{"evtexporttst1", "evtexporttst2", "evtexporttst3"}, ids(base))
+
+	scoped := base
+	accessible := []string{clientA}
+	scoped.AccessibleClientIDs = &accessible
+	assert.Equal(t, []string{"evtexporttst1", "evtexporttst3"}, ids(scoped))
+
+	scoped.ClientIDs = []string{clientB}
+	assert.Empty(t, ids(scoped), "a cross-tenant clientIds filter must not leak")
+
+	outside := base
+	outside.To = now.Add(-30 * time.Second)
+	assert.Empty(t, ids(outside))
+}
+
+// TestClaim_PendingThenStale covers the claim queue: a PENDING export is
+// claimed once, and a RUNNING one is reclaimed only after its heartbeat
+// goes stale.
+func TestClaim_PendingThenStale(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := NewRepository(pool)
+	_, err := pool.Exec(ctx, `DELETE FROM msg_exports`)
+	require.NoError(t, err)
+
+	e := New(KindEvents, FormatNDJSON, Filter{From: time.Now().Add(-time.Hour), To: time.Now()}, nil)
+	_, err = pool.Exec(ctx,
+		`INSERT INTO msg_exports (id, kind, format, filter, status, created_at, updated_at)
+		 VALUES ($1, $2, $3, '{}', 'PENDING', NOW(), NOW())`, e.ID, e.Kind, e.Format)
+	require.NoError(t, err)
+
+	got, err := repo.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, StatusRunning, got.Status)
+
+	again, err := repo.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, again, "a RUNNING export with a fresh heartbeat is not reclaimed")
+
+	_, err = pool.Exec(ctx, `UPDATE msg_exports SET heartbeat_at = NOW() - INTERVAL '2 minutes' WHERE id = $1`, e.ID)
+	require.NoError(t, err)
+	again, err = repo.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, again)
+	assert.Equal(t, e.ID, again.ID)
+
+	require.NoError(t, repo.MarkCompleted(ctx, e.ID, "k", 3, 42))
+	done, err := repo.FindByID(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, done.Status)
+	assert.Equal(t, int64(3), done.RowCount)
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// Runner claims queued exports and writes them to the object store, one
+// at a time per instance. Construct with NewRunner and run Run in its own
+// goroutine.
+type Runner struct {
+	cfg   Config
+	repo  *Repository
+	store *ObjectStore
+}
+
+// NewRunner wires a runner.
+func NewRunner(cfg Config, repo *Repository, store *ObjectStore) *Runner {
+	return &Runner{cfg: cfg, repo: repo, store: store}
+}
+
+// Run polls for work every PollInterval until ctx is cancelled, draining
+// the queue on each tick. An export interrupted by shutdown stays RUNNING
+// and is restarted by whichever instance next sees its heartbeat go stale.
+func (r *Runner) Run(ctx context.Context) {
+	t := time.NewTicker(r.cfg.PollInterval)
+	defer t.Stop()
+	slog.Info("export runner starting", "interval", r.cfg.PollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("export runner stopped")
+			return
+		case <-t.C:
+			for ctx.Err() == nil {
+				ran, err := r.runOnce(ctx)
+				if err != nil {
+					slog.Warn("export runner error", "err", err)
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
+
+// runOnce claims and runs one export. ran is false when the queue is empty.
+func (r *Runner) runOnce(ctx context.Context) (ran bool, err error) {
+	e, err := r.repo.Claim(ctx, r.cfg.StaleAfter)
+	if err != nil || e == nil {
+		return false, err
+	}
+	key := r.store.Key(e.ObjectName())
+	slog.Info("export started", "export_id", e.ID, "kind", e.Kind, "format", e.Format, "key", key)
+	rows, size, err := r.write(ctx, e, key)
+	if ctx.Err() != nil {
+		return true, nil
+	}
+	if err != nil {
+		slog.Warn("export failed", "export_id", e.ID, "err", err)
+		return true, r.repo.MarkFailed(ctx, e.ID, err.Error())
+	}
+	slog.Info("export completed", "export_id", e.ID, "rows", rows, "bytes", size)
+	return true, r.repo.MarkCompleted(ctx, e.ID, key, rows, size)
+}
+
+// write streams the export's rows through the encoder into an upload,
+// heartbeating while it runs.
+func (r *Runner) write(ctx context.Context, e *Export, key string) (rows, size int64, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go r.heartbeat(ctx, e.ID)
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		enc := newEncoder(e.Format, columnsFor(e.Kind), pw)
+		err := r.repo.Stream(ctx, e.Kind, e.Filter, func(cells []cell) error {
+			rows++
+			return enc.write(cells)
+		})
+		if err == nil {
+			err = enc.close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	size, err = r.store.Upload(ctx, key, e.Format.ContentType(), pr)
+	// Unblock and stop the producer if the upload gave up early.
+	pr.CloseWithError(err)
+	cancel()
+	<-done
+	return rows, size, err
+}
+
+func (r *Runner) heartbeat(ctx context.Context, id string) {
+	t := time.NewTicker(r.cfg.StaleAfter / 4)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.repo.Heartbeat(ctx, id); err != nil {
+				slog.Warn("export heartbeat failed", "export_id", id, "err", err)
+			}
+		}
+	}
+}
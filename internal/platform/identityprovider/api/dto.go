@@ -13,6 +13,7 @@ type CreateIdentityProviderRequest struct {
 	Code                string   `json:"code" doc:"IDP code (e.g. internal, entra)"`
 	Name                string   `json:"name" doc:"Display name"`
 	Type                string   `json:"type" doc:"IDP type (INTERNAL or OIDC)"`
+	IdpType             string   `json:"idpType,omitempty" doc:"OIDC login adapter: oidc (default), keycloak, entra, google, okta"`
 	OIDCIssuerURL       *string  `json:"oidcIssuerUrl,omitempty"`
 	OIDCClientID        *string  `json:"oidcClientId,omitempty"`
 	OIDCClientSecretRef *string  `json:"oidcClientSecretRef,omitempty"`
@@ -26,6 +27,7 @@ func (r CreateIdentityProviderRequest) toCommand() operations.CreateCommand {
 		Code:                r.Code,
 		Name:                r.Name,
 		Type:                r.Type,
+		IdpType:             r.IdpType,
 		OIDCIssuerURL:       r.OIDCIssuerURL,
 		OIDCClientID:        r.OIDCClientID,
 		OIDCClientSecretRef: r.OIDCClientSecretRef,
@@ -38,6 +40,7 @@ func (r CreateIdentityProviderRequest) toCommand() operations.CreateCommand {
 // UpdateIdentityProviderRequest is the wire body for PUT /api/identity-providers/{id}.
 type UpdateIdentityProviderRequest struct {
 	Name                *string  `json:"name,omitempty"`
+	IdpType             *string  `json:"idpType,omitempty" doc:"OIDC login adapter: oidc, keycloak, entra, google, okta"`
 	OIDCIssuerURL       *string  `json:"oidcIssuerUrl,omitempty"`
 	OIDCClientID        *string  `json:"oidcClientId,omitempty"`
 	OIDCClientSecretRef *string  `json:"oidcClientSecretRef,omitempty"`
@@ -50,6 +53,7 @@ func (r UpdateIdentityProviderRequest) toCommand(id string) operations.UpdateCom
 	return operations.UpdateCommand{
 		ID:                  id,
 		Name:                r.Name,
+		IdpType:             r.IdpType,
 		OIDCIssuerURL:       r.OIDCIssuerURL,
 		OIDCClientID:        r.OIDCClientID,
 		OIDCClientSecretRef: r.OIDCClientSecretRef,
@@ -67,6 +71,7 @@ type IdentityProviderResponse struct {
 	Code                string          `json:"code"`
 	Name                string          `json:"name"`
 	Type                string          `json:"type"`
+	IdpType             string          `json:"idpType"`
 	OIDCIssuerURL       *string         `json:"oidcIssuerUrl,omitempty"`
 	OIDCClientID        *string         `json:"oidcClientId,omitempty"`
 	HasClientSecret     bool            `json:"hasClientSecret"`
@@ -87,6 +92,7 @@ func fromEntity(ip *identityprovider.IdentityProvider) IdentityProviderResponse
 		Code:                ip.Code,
 		Name:                ip.Name,
 		Type:                string(ip.Type),
+		IdpType:             string(ip.IdpType),
 		OIDCIssuerURL:       ip.OIDCIssuerURL,
 		OIDCClientID:        ip.OIDCClientID,
 		HasClientSecret:     ip.HasClientSecret(),
@@ -4,6 +4,7 @@
 package identityprovider
 
 import (
+	"strings"
 	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
@@ -25,12 +26,38 @@ func ParseType(s string) Type {
 	return TypeInternal
 }
 
+// IdpType selects the provider-specific login adapter for an OIDC IdP
+// (internal/platform/idp). Go-only; Rust treats every OIDC IdP alike,
+// which is what IdpTypeOIDC keeps.
+type IdpType string
+
+const (
+	IdpTypeOIDC            IdpType = "oidc"
+	IdpTypeKeycloak        IdpType = "keycloak"
+	IdpTypeEntra           IdpType = "entra"
+	IdpTypeGoogleWorkspace IdpType = "google"
+	IdpTypeOkta            IdpType = "okta"
+)
+
+// ParseIdpType is the strict parser; ok is false for anything unknown.
+// Empty parses as IdpTypeOIDC.
+func ParseIdpType(s string) (IdpType, bool) {
+	switch t := IdpType(strings.ToLower(strings.TrimSpace(s))); t {
+	case "":
+		return IdpTypeOIDC, true
+	case IdpTypeOIDC, IdpTypeKeycloak, IdpTypeEntra, IdpTypeGoogleWorkspace, IdpTypeOkta:
+		return t, true
+	}
+	return "", false
+}
+
 // IdentityProvider is the aggregate root.
 type IdentityProvider struct {
 	ID                  string    `json:"id"`
 	Code                string    `json:"code"`
 	Name                string    `json:"name"`
 	Type                Type      `json:"type"`
+	IdpType             IdpType   `json:"idpType"`
 	OIDCIssuerURL       *string   `json:"oidcIssuerUrl,omitempty"`
 	OIDCClientID        *string   `json:"oidcClientId,omitempty"`
 	OIDCClientSecretRef *string   `json:"oidcClientSecretRef,omitempty"`
@@ -52,6 +79,7 @@ func New(code, name string, t Type) *IdentityProvider {
 		Code:                code,
 		Name:                name,
 		Type:                t,
+		IdpType:             IdpTypeOIDC,
 		AllowedEmailDomains: []string{},
 		CreatedAt:           now,
 		UpdatedAt:           now,
@@ -14,6 +14,7 @@ type CreateCommand struct {
 	Code                string   `json:"code"`
 	Name                string   `json:"name"`
 	Type                string   `json:"type"`
+	IdpType             string   `json:"idpType,omitempty"`
 	OIDCIssuerURL       *string  `json:"oidcIssuerUrl,omitempty"`
 	OIDCClientID        *string  `json:"oidcClientId,omitempty"`
 	OIDCClientSecretRef *string  `json:"oidcClientSecretRef,omitempty"`
@@ -35,6 +36,9 @@ func CreateIdentityProvider(repo *identityprovider.Repository) usecaseop.Operati
 			if strings.TrimSpace(cmd.Name) == "" {
 				return usecase.Validation("NAME_REQUIRED", "name is required")
 			}
+			if _, ok := identityprovider.ParseIdpType(cmd.IdpType); !ok {
+				return usecase.Validation("INVALID_IDP_TYPE", "idpType must be one of oidc, keycloak, entra, google, okta")
+			}
 			if identityprovider.ParseType(cmd.Type) == identityprovider.TypeOIDC {
 				if cmd.OIDCIssuerURL == nil || strings.TrimSpace(*cmd.OIDCIssuerURL) == "" {
 					return usecase.Validation("OIDC_ISSUER_REQUIRED", "OIDC IDPs require oidcIssuerUrl")
@@ -58,6 +62,7 @@ func CreateIdentityProvider(repo *identityprovider.Repository) usecaseop.Operati
 			}
 
 			ip := identityprovider.New(cmd.Code, cmd.Name, identityprovider.ParseType(cmd.Type))
+			ip.IdpType, _ = identityprovider.ParseIdpType(cmd.IdpType)
 			ip.OIDCIssuerURL = cmd.OIDCIssuerURL
 			ip.OIDCClientID = cmd.OIDCClientID
 			ip.OIDCClientSecretRef = cmd.OIDCClientSecretRef
@@ -65,6 +65,7 @@ func TestCreateIdentityProvider_HappyPath(t *testing.T) {
 		OIDCMultiTenant:     true,
 		OIDCIssuerPattern:   &pattern,
 		AllowedEmailDomains: []string{"idpcrt-a.example.com", "idpcrt-b.example.com"},
+		IdpType:             "Okta",
 	})
 	require.NoError(t, err)
 
@@ -87,6 +88,7 @@ func TestCreateIdentityProvider_HappyPath(t *testing.T) {
 	require.NotNil(t, got.OIDCIssuerPattern)
 	assert.Equal(t, pattern, *got.OIDCIssuerPattern)
 	assert.ElementsMatch(t, []string{"idpcrt-a.example.com", "idpcrt-b.example.com"}, got.AllowedEmailDomains)
+	assert.Equal(t, identityprovider.IdpTypeOkta, got.IdpType)
 }
 
 func TestCreateIdentityProvider_Validation(t *testing.T) {
@@ -108,6 +110,9 @@ func TestCreateIdentityProvider_Validation(t *testing.T) {
 		{"oidc without client id", operations.CreateCommand{
 			Code: "idpcrt-noclient", Name: "X", Type: "OIDC", OIDCIssuerURL: &issuer,
 		}, "OIDC_CLIENT_ID_REQUIRED"},
+		{"unknown idp type", operations.CreateCommand{
+			Code: "idpcrt-badtype", Name: "X", Type: "INTERNAL", IdpType: "ping",
+		}, "INVALID_IDP_TYPE"},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -143,12 +148,14 @@ func TestUpdateIdentityProvider_HappyPath(t *testing.T) {
 	newName := "  After  " // op must trim
 	issuer := "https://login.idpupd.example.com"
 	multiTenant := true
+	idpType := "google"
 	ev, err := runAuthorized(uow, operations.UpdateIdentityProvider(repo), operations.UpdateCommand{
 		ID:                  seeded.IdentityProviderID,
 		Name:                &newName,
 		OIDCIssuerURL:       &issuer,
 		OIDCMultiTenant:     &multiTenant,
 		AllowedEmailDomains: []string{"idpupd.example.com"},
+		IdpType:             &idpType,
 	})
 	require.NoError(t, err)
 	assert.Equal(t, seeded.IdentityProviderID, ev.IdentityProviderID)
@@ -163,6 +170,7 @@ func TestUpdateIdentityProvider_HappyPath(t *testing.T) {
 	assert.Equal(t, issuer, *got.OIDCIssuerURL)
 	assert.True(t, got.OIDCMultiTenant)
 	assert.ElementsMatch(t, []string{"idpupd.example.com"}, got.AllowedEmailDomains)
+	assert.Equal(t, identityprovider.IdpTypeGoogleWorkspace, got.IdpType)
 }
 
 func TestUpdateIdentityProvider_Errors(t *testing.T) {
@@ -14,6 +14,7 @@ import (
 type UpdateCommand struct {
 	ID                  string   `json:"id"`
 	Name                *string  `json:"name,omitempty"`
+	IdpType             *string  `json:"idpType,omitempty"`
 	OIDCIssuerURL       *string  `json:"oidcIssuerUrl,omitempty"`
 	OIDCClientID        *string  `json:"oidcClientId,omitempty"`
 	OIDCClientSecretRef *string  `json:"oidcClientSecretRef,omitempty"`
@@ -34,6 +35,11 @@ func UpdateIdentityProvider(repo *identityprovider.Repository) usecaseop.Operati
 			if cmd.Name != nil && strings.TrimSpace(*cmd.Name) == "" {
 				return usecase.Validation("NAME_REQUIRED", "name cannot be empty")
 			}
+			if cmd.IdpType != nil {
+				if _, ok := identityprovider.ParseIdpType(*cmd.IdpType); !ok {
+					return usecase.Validation("INVALID_IDP_TYPE", "idpType must be one of oidc, keycloak, entra, google, okta")
+				}
+			}
 			return nil
 		},
 		// The coarse "may write identity providers" permission (anchor-only) is
@@ -50,6 +56,9 @@ func UpdateIdentityProvider(repo *identityprovider.Repository) usecaseop.Operati
 			if cmd.Name != nil {
 				ip.Name = strings.TrimSpace(*cmd.Name)
 			}
+			if cmd.IdpType != nil {
+				ip.IdpType, _ = identityprovider.ParseIdpType(*cmd.IdpType)
+			}
 			if cmd.OIDCIssuerURL != nil {
 				ip.OIDCIssuerURL = cmd.OIDCIssuerURL
 			}
@@ -76,6 +76,7 @@ func (r *Repository) Persist(ctx context.Context, ip *IdentityProvider, tx *usec
 		OidcIssuerPattern:   ip.OIDCIssuerPattern,
 		CreatedAt:           ip.CreatedAt,
 		UpdatedAt:           time.Now().UTC(),
+		IdpType:             string(ip.IdpType),
 	}); err != nil {
 		return fmt.Errorf("identity_provider persist: %w", err)
 	}
@@ -143,6 +144,7 @@ func rowToIDP(row dbq.OauthIdentityProvider) *IdentityProvider {
 		Code:                row.Code,
 		Name:                row.Name,
 		Type:                ParseType(row.Type),
+		IdpType:             IdpType(row.IdpType),
 		OIDCIssuerURL:       row.OidcIssuerUrl,
 		OIDCClientID:        row.OidcClientID,
 		OIDCClientSecretRef: row.OidcClientSecretRef,
@@ -2,33 +2,37 @@ package idp
 
 import (
 	"context"
-	"errors"
+
+	"golang.org/x/oauth2"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
 )
 
-// EntraProvider integrates with Microsoft Entra ID (formerly Azure AD)
-// for app-role sync via Microsoft Graph. Mirrors fc-platform/src/idp/entra.rs.
-//
-// TODO(wave-3d-follow-up): wire against Microsoft Graph.
-// The Rust impl uses an MSAL-style client_credentials grant + GET
-// /users/{id}/appRoleAssignments. The Go port uses
-// github.com/coreos/go-oidc/v3 for the OIDC client and net/http for Graph.
+// EntraProvider handles Microsoft Entra ID (formerly Azure AD). Roles come
+// from the app-role `roles` claim; Entra's `groups` claim carries object
+// ids and overflows into a Graph link past 200 groups, so app roles are
+// the supported mapping source. Guest (#EXT#) rejection and tenant
+// pinning stay in the bridge, which applies them to every IdP as Rust
+// does. Mirrors fc-platform/src/idp/entra.rs.
 type EntraProvider struct {
 	cfg *identityprovider.IdentityProvider
 }
 
-// NewEntraProvider wires an Entra integration.
+// NewEntraProvider wires an Entra adapter.
 func NewEntraProvider(cfg *identityprovider.IdentityProvider) *EntraProvider {
 	return &EntraProvider{cfg: cfg}
 }
 
 // Name returns the provider type.
-func (*EntraProvider) Name() string { return "entra" }
+func (*EntraProvider) Name() string { return string(identityprovider.IdpTypeEntra) }
+
+// Scopes adds nothing.
+func (*EntraProvider) Scopes() []string { return nil }
+
+// CheckClaims adds nothing to the bridge's checks.
+func (*EntraProvider) CheckClaims(*Claims, string) error { return nil }
 
-// SyncRoles fetches the supplied user's app-role assignments from Graph.
-//
-// TODO(wave-3d-follow-up): implement.
-func (*EntraProvider) SyncRoles(_ context.Context, _ string) ([]string, error) {
-	return nil, errors.Join(ErrRoleSyncNotSupported, errors.New("entra.SyncRoles: wired in wave 3d follow-up"))
+// RoleNames returns the app-role assignments.
+func (*EntraProvider) RoleNames(_ context.Context, c *Claims, _ *oauth2.Token) ([]string, error) {
+	return c.Roles, nil
 }
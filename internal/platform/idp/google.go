@@ -0,0 +1,131 @@
+package idp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
+)
+
+// cloudIdentityGroupsScope lets the signed-in user list their own group
+// memberships. Workspace admins can pre-consent it for an internal app.
+const cloudIdentityGroupsScope = "https://www.googleapis.com/auth/cloud-identity.groups.readonly"
+
+// ErrHostedDomain rejects a Google account outside the configured
+// Workspace domain(s), including consumer (gmail.com) accounts, which
+// carry no hd claim.
+var ErrHostedDomain = errors.New("google: account is not in an allowed Workspace domain (hd claim)")
+
+// ErrEmailUnverified rejects a Google account whose email Google has not
+// verified.
+var ErrEmailUnverified = errors.New("google: email is not verified")
+
+// GoogleWorkspaceProvider handles Google Workspace sign-in. Google's ID
+// token proves the account, not the organisation: any Google account can
+// sign in to an OAuth client unless the hosted-domain (hd) claim is
+// checked, so CheckClaims pins it to the login domain or the IdP's
+// allowed email domains. Google puts no groups in the token; RoleNames
+// reads the user's direct group memberships from the Cloud Identity API
+// and returns the group email addresses (lowercased) for mapping.
+type GoogleWorkspaceProvider struct {
+	cfg     *identityprovider.IdentityProvider
+	apiBase string
+}
+
+// NewGoogleWorkspaceProvider wires a Google Workspace adapter.
+func NewGoogleWorkspaceProvider(cfg *identityprovider.IdentityProvider) *GoogleWorkspaceProvider {
+	return &GoogleWorkspaceProvider{cfg: cfg, apiBase: "https://cloudidentity.googleapis.com"}
+}
+
+// Name returns the provider type.
+func (*GoogleWorkspaceProvider) Name() string {
+	return string(identityprovider.IdpTypeGoogleWorkspace)
+}
+
+// Scopes adds the Cloud Identity group-membership scope.
+func (*GoogleWorkspaceProvider) Scopes() []string { return []string{cloudIdentityGroupsScope} }
+
+// CheckClaims requires a verified email and an hd claim equal to the login
+// domain or one of the IdP's allowed email domains.
+func (p *GoogleWorkspaceProvider) CheckClaims(c *Claims, loginDomain string) error {
+	if c.EmailVerified == nil || !*c.EmailVerified {
+		return ErrEmailUnverified
+	}
+	hd := strings.ToLower(c.HostedDomain)
+	if hd == "" {
+		return ErrHostedDomain
+	}
+	if hd == strings.ToLower(loginDomain) {
+		return nil
+	}
+	if p.cfg != nil && slices.ContainsFunc(p.cfg.AllowedEmailDomains, func(d string) bool { return strings.EqualFold(d, hd) }) {
+		return nil
+	}
+	return ErrHostedDomain
+}
+
+// RoleNames lists the user's direct group memberships, following pages.
+func (p *GoogleWorkspaceProvider) RoleNames(ctx context.Context, c *Claims, tok *oauth2.Token) ([]string, error) {
+	if tok == nil || c.Email == "" {
+		return nil, errors.New("google: no access token or email to look up groups")
+	}
+	client := apiClient(ctx, tok)
+	q := url.Values{"query": {fmt.Sprintf("member_key_id == '%s'", c.Email)}}
+	var out []string
+	for {
+		var page struct {
+			Memberships []struct {
+				GroupKey struct {
+					ID string `json:"id"`
+				} `json:"groupKey"`
+			} `json:"memberships"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		u := p.apiBase + "/v1/groups/-/memberships:searchDirectGroups?" + q.Encode()
+		if err := getJSON(ctx, client, u, &page); err != nil {
+			return nil, fmt.Errorf("google groups: %w", err)
+		}
+		for _, m := range page.Memberships {
+			if m.GroupKey.ID != "" {
+				out = append(out, strings.ToLower(m.GroupKey.ID))
+			}
+		}
+		if page.NextPageToken == "" {
+			return union(out), nil
+		}
+		q.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// getJSON GETs u and decodes a 200 response into v.
+func getJSON(ctx context.Context, client *http.Client, u string, v any) error {
+	_, err := getJSONWithHeaders(ctx, client, u, v)
+	return err
+}
+
+// getJSONWithHeaders is getJSON that also returns the response headers
+// (Okta paginates through Link).
+func getJSONWithHeaders(ctx context.Context, client *http.Client, u string, v any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: HTTP %d", req.URL.Path, resp.StatusCode)
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(v)
+}
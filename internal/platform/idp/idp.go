@@ -1,63 +1,115 @@
-// Package idp holds provider-specific OIDC integration glue (Keycloak,
-// Entra, Google, generic). Mirrors fc-platform/src/idp/.
+// Package idp holds the provider-specific OIDC login adapters (generic,
+// Keycloak, Entra, Google Workspace, Okta), selected by the identity
+// provider's IdpType. Mirrors fc-platform/src/idp/, extended Go-side with
+// the Google Workspace and Okta adapters.
 //
-// Phase 3d scope: the abstract Provider interface + a generic OIDC
-// implementation that delegates to go-oidc/v3. Provider-specific
-// integrations (Keycloak admin API, Entra Graph API for role sync)
-// are scaffolded but their bodies are TODOs — they're not on the
-// critical path for cutover, and they're well-isolated when the team
-// gets to them.
+// The auth bridge (internal/platform/auth/bridge) drives the OIDC
+// handshake itself; an adapter only contributes what differs per IdP:
+// extra authorize scopes, extra trust checks on the verified ID token, and
+// where the user's role/group names come from for the
+// oauth_idp_role_mappings translation.
 package idp
 
 import (
 	"context"
-	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
 )
 
-// Provider is the per-IDP integration surface. Each concrete impl
-// (KeycloakProvider, EntraProvider, GenericOIDCProvider) implements
-// this interface against the upstream IDP's API.
+// Claims is the subset of ID-token claims the adapters read. The bridge
+// decodes the verified token into it.
+type Claims struct {
+	Subject           string   `json:"sub"`
+	Email             string   `json:"email"`
+	EmailVerified     *bool    `json:"email_verified,omitempty"`
+	PreferredUsername string   `json:"preferred_username"`
+	Tid               string   `json:"tid"`
+	HostedDomain      string   `json:"hd"`
+	Roles             []string `json:"roles"`
+	// Groups is nil when the token carries no groups claim at all, and
+	// empty when it carries an empty one — Okta tells the two apart.
+	Groups      []string `json:"groups"`
+	RealmAccess struct {
+		Roles []string `json:"roles"`
+	} `json:"realm_access"`
+}
+
+// Provider is the per-IdP login surface.
 type Provider interface {
-	// Name is the provider type identifier (e.g. "keycloak", "entra").
+	// Name is the IdpType identifier (e.g. "keycloak", "okta"). It is also
+	// the oauth_idp_role_mappings.idp_type a mapping is scoped to.
 	Name() string
 
-	// SyncRoles fetches the user's roles from the IDP and returns the
-	// platform-side role names they should be granted.
-	//
-	// Optional — providers that don't support role sync return
-	// ErrRoleSyncNotSupported. The platform falls back to the role
-	// assignments configured locally on the EmailDomainMapping.
-	SyncRoles(ctx context.Context, externalUserID string) ([]string, error)
-}
+	// Scopes are requested on top of openid/profile/email.
+	Scopes() []string
 
-// ErrRoleSyncNotSupported is returned by providers that don't expose
-// a role-sync API.
-var ErrRoleSyncNotSupported = errors.New("idp: role sync not supported by this provider")
+	// CheckClaims applies provider-specific trust rules to a verified ID
+	// token. loginDomain is the email domain the login started from.
+	CheckClaims(c *Claims, loginDomain string) error
 
-// Resolve returns the right Provider for the supplied IdentityProvider
-// configuration. Caller passes the persisted IDP config; this function
-// inspects the type/code and returns the matching integration.
-//
-// TODO(wave-3d-follow-up): wire concrete providers.
+	// RoleNames returns the IdP-side role or group names to translate
+	// through oauth_idp_role_mappings. tok is the code-exchange token,
+	// for adapters that read groups from the IdP's API. An error means
+	// the names could not be determined; the caller leaves the user's
+	// IdP-sourced roles as they are rather than dropping them.
+	RoleNames(ctx context.Context, c *Claims, tok *oauth2.Token) ([]string, error)
+}
+
+// Resolve returns the adapter for the supplied identity provider.
+// Unknown and unset types get the generic OIDC adapter.
 func Resolve(ip *identityprovider.IdentityProvider) Provider {
-	// For Phase 3d: every IDP returns the generic OIDC provider that
-	// only does login. Keycloak/Entra-specific integrations (role sync,
-	// user provisioning) land as separate Provider impls once the
-	// underlying admin APIs are wired.
+	switch ip.IdpType {
+	case identityprovider.IdpTypeKeycloak:
+		return NewKeycloakProvider(ip)
+	case identityprovider.IdpTypeEntra:
+		return NewEntraProvider(ip)
+	case identityprovider.IdpTypeGoogleWorkspace:
+		return NewGoogleWorkspaceProvider(ip)
+	case identityprovider.IdpTypeOkta:
+		return NewOktaProvider(ip)
+	}
 	return &genericOIDCProvider{cfg: ip}
 }
 
-// genericOIDCProvider handles login-only flows for any RFC-compliant
-// OIDC provider. Role sync is unsupported here; subclass providers
-// (Keycloak, Entra) layer on top.
+// MappingApplies reports whether an oauth_idp_role_mappings row with the
+// given idp_type is used for logins through p. Untyped mappings apply
+// everywhere; the generic adapter keeps the pre-adapter behaviour of
+// applying every mapping regardless of type.
+func MappingApplies(p Provider, mappingIdpType string) bool {
+	if mappingIdpType == "" || p.Name() == string(identityprovider.IdpTypeOIDC) {
+		return true
+	}
+	return strings.EqualFold(mappingIdpType, p.Name())
+}
+
+// apiTimeout bounds each call an adapter makes to an IdP API during the
+// login callback.
+const apiTimeout = 10 * time.Second
+
+// apiClient authenticates requests with the user's access token.
+func apiClient(ctx context.Context, tok *oauth2.Token) *http.Client {
+	c := oauth2.NewClient(ctx, oauth2.StaticTokenSource(tok))
+	c.Timeout = apiTimeout
+	return c
+}
+
+// genericOIDCProvider handles any RFC-compliant OIDC provider: no extra
+// checks, roles from the `roles` claim.
 type genericOIDCProvider struct {
 	cfg *identityprovider.IdentityProvider
 }
 
-func (*genericOIDCProvider) Name() string { return "oidc" }
+func (*genericOIDCProvider) Name() string { return string(identityprovider.IdpTypeOIDC) }
+
+func (*genericOIDCProvider) Scopes() []string { return nil }
+
+func (*genericOIDCProvider) CheckClaims(*Claims, string) error { return nil }
 
-func (*genericOIDCProvider) SyncRoles(_ context.Context, _ string) ([]string, error) {
-	return nil, ErrRoleSyncNotSupported
+func (*genericOIDCProvider) RoleNames(_ context.Context, c *Claims, _ *oauth2.Token) ([]string, error) {
+	return c.Roles, nil
 }
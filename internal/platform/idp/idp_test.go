@@ -0,0 +1,168 @@
+package idp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestResolve(t *testing.T) {
+	cases := map[identityprovider.IdpType]string{
+		"":                                      "oidc",
+		identityprovider.IdpTypeOIDC:            "oidc",
+		identityprovider.IdpTypeKeycloak:        "keycloak",
+		identityprovider.IdpTypeEntra:           "entra",
+		identityprovider.IdpTypeGoogleWorkspace: "google",
+		identityprovider.IdpTypeOkta:            "okta",
+	}
+	for typ, want := range cases {
+		if got := Resolve(&identityprovider.IdentityProvider{IdpType: typ}).Name(); got != want {
+			t.Errorf("Resolve(%q).Name() = %q, want %q", typ, got, want)
+		}
+	}
+}
+
+func TestMappingApplies(t *testing.T) {
+	okta := &OktaProvider{}
+	generic := &genericOIDCProvider{}
+	for _, tc := range []struct {
+		p       Provider
+		mapping string
+		want    bool
+	}{
+		{okta, "", true},
+		{okta, "okta", true},
+		{okta, "OKTA", true},
+		{okta, "entra", false},
+		{generic, "entra", true},
+		{generic, "", true},
+	} {
+		if got := MappingApplies(tc.p, tc.mapping); got != tc.want {
+			t.Errorf("MappingApplies(%s, %q) = %v, want %v", tc.p.Name(), tc.mapping, got, tc.want)
+		}
+	}
+}
+
+func TestKeycloakRoleNames_UnionsRealmRoles(t *testing.T) {
+	c := &Claims{Roles: []string{"a", "b"}}
+	c.RealmAccess.Roles = []string{"b", "c"}
+	got, err := (&KeycloakProvider{}).RoleNames(context.Background(), c, nil)
+	if err != nil || !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("RoleNames = %v, %v", got, err)
+	}
+}
+
+func TestGoogleCheckClaims(t *testing.T) {
+	p := NewGoogleWorkspaceProvider(&identityprovider.IdentityProvider{AllowedEmailDomains: []string{"subsidiary.com"}})
+	for _, tc := range []struct {
+		name string
+		c    Claims
+		want error
+	}{
+		{"login domain", Claims{HostedDomain: "Acme.com", EmailVerified: ptr(true)}, nil},
+		{"allowed domain", Claims{HostedDomain: "subsidiary.com", EmailVerified: ptr(true)}, nil},
+		{"consumer account", Claims{EmailVerified: ptr(true)}, ErrHostedDomain},
+		{"other workspace", Claims{HostedDomain: "evil.com", EmailVerified: ptr(true)}, ErrHostedDomain},
+		{"unverified", Claims{HostedDomain: "acme.com", EmailVerified: ptr(false)}, ErrEmailUnverified},
+		{"no verified claim", Claims{HostedDomain: "acme.com"}, ErrEmailUnverified},
+	} {
+		if err := p.CheckClaims(&tc.c, "acme.com"); !errors.Is(err, tc.want) {
+			t.Errorf("%s: CheckClaims = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestGoogleRoleNames_PaginatesGroups(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if q := r.URL.Query().Get("query"); q != "member_key_id == 'jo@acme.com'" {
+			t.Errorf("query = %q", q)
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			fmt.Fprint(w, `{"memberships":[{"groupKey":{"id":"Admins@acme.com"}}],"nextPageToken":"p2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"memberships":[{"groupKey":{"id":"ops@acme.com"}}]}`)
+	}))
+	defer srv.Close()
+
+	p := NewGoogleWorkspaceProvider(&identityprovider.IdentityProvider{})
+	p.apiBase = srv.URL
+	got, err := p.RoleNames(context.Background(), &Claims{Email: "jo@acme.com"}, &oauth2.Token{AccessToken: "at"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"admins@acme.com", "ops@acme.com"}) {
+		t.Fatalf("RoleNames = %v", got)
+	}
+}
+
+func TestGoogleRoleNames_APIErrorSurfaces(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	p := NewGoogleWorkspaceProvider(&identityprovider.IdentityProvider{})
+	p.apiBase = srv.URL
+	if _, err := p.RoleNames(context.Background(), &Claims{Email: "jo@acme.com"}, &oauth2.Token{AccessToken: "at"}); err == nil {
+		t.Fatal("expected an error on HTTP 403")
+	}
+}
+
+func TestOktaRoleNames_UsesCompleteClaim(t *testing.T) {
+	p := NewOktaProvider(&identityprovider.IdentityProvider{OIDCIssuerURL: ptr("https://unreachable.invalid/oauth2/default")})
+	got, err := p.RoleNames(context.Background(), &Claims{Groups: []string{"Everyone", "Admins"}}, nil)
+	if err != nil || !slices.Equal(got, []string{"Everyone", "Admins"}) {
+		t.Fatalf("RoleNames = %v, %v", got, err)
+	}
+}
+
+func TestOktaRoleNames_TruncatedClaimFallsBackToAPI(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/me/groups" || r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Add("Link", fmt.Sprintf(`<%s/api/v1/users/me/groups?limit=200>; rel="self"`, srv.URL))
+			w.Header().Add("Link", fmt.Sprintf(`<%s/api/v1/users/me/groups?limit=200&after=g2>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `[{"profile":{"name":"Everyone"}},{"profile":{"name":"Admins"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"profile":{"name":"Ops"}}]`)
+	}))
+	defer srv.Close()
+
+	truncated := make([]string, oktaGroupsClaimLimit)
+	for i := range truncated {
+		truncated[i] = fmt.Sprintf("g%d", i)
+	}
+	p := NewOktaProvider(&identityprovider.IdentityProvider{OIDCIssuerURL: ptr(srv.URL + "/oauth2/default")})
+	for name, c := range map[string]*Claims{
+		"truncated": {Groups: truncated},
+		"absent":    {},
+	} {
+		got, err := p.RoleNames(context.Background(), c, &oauth2.Token{AccessToken: "at"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !slices.Equal(got, []string{"Everyone", "Admins", "Ops"}) {
+			t.Fatalf("%s: RoleNames = %v", name, got)
+		}
+	}
+}
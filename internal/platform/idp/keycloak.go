@@ -2,33 +2,51 @@ package idp
 
 import (
 	"context"
-	"errors"
+
+	"golang.org/x/oauth2"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
 )
 
-// KeycloakProvider integrates with the Keycloak admin REST API for
-// per-realm role sync. Mirrors fc-platform/src/idp/keycloak.rs.
-//
-// TODO(wave-3d-follow-up): wire against the Keycloak admin REST API.
-// The Rust impl uses reqwest + admin token; the Go port uses net/http
-// + go-oidc client_credentials grant against {realm}/protocol/openid-connect/token.
+// KeycloakProvider reads realm roles as well as a mapped `roles` claim:
+// Keycloak's default client scopes put realm roles under
+// realm_access.roles, and a "User Realm Role" mapper is needed to flatten
+// them into `roles`. Mirrors fc-platform/src/idp/keycloak.rs.
 type KeycloakProvider struct {
 	cfg *identityprovider.IdentityProvider
 }
 
-// NewKeycloakProvider wires a Keycloak integration.
+// NewKeycloakProvider wires a Keycloak adapter.
 func NewKeycloakProvider(cfg *identityprovider.IdentityProvider) *KeycloakProvider {
 	return &KeycloakProvider{cfg: cfg}
 }
 
 // Name returns the provider type.
-func (*KeycloakProvider) Name() string { return "keycloak" }
-
-// SyncRoles fetches realm-roles for the supplied user via the Keycloak
-// admin REST API: GET /admin/realms/{realm}/users/{id}/role-mappings/realm.
-//
-// TODO(wave-3d-follow-up): implement.
-func (*KeycloakProvider) SyncRoles(_ context.Context, _ string) ([]string, error) {
-	return nil, errors.Join(ErrRoleSyncNotSupported, errors.New("keycloak.SyncRoles: wired in wave 3d follow-up"))
+func (*KeycloakProvider) Name() string { return string(identityprovider.IdpTypeKeycloak) }
+
+// Scopes adds nothing.
+func (*KeycloakProvider) Scopes() []string { return nil }
+
+// CheckClaims adds nothing to the bridge's checks.
+func (*KeycloakProvider) CheckClaims(*Claims, string) error { return nil }
+
+// RoleNames is the union of `roles` and realm_access.roles.
+func (*KeycloakProvider) RoleNames(_ context.Context, c *Claims, _ *oauth2.Token) ([]string, error) {
+	return union(c.Roles, c.RealmAccess.Roles), nil
+}
+
+// union concatenates lists, dropping repeats and keeping first-seen order.
+func union(lists ...[]string) []string {
+	seen := map[string]struct{}{}
+	out := []string{}
+	for _, l := range lists {
+		for _, v := range l {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			out = append(out, v)
+		}
+	}
+	return out
 }
@@ -0,0 +1,99 @@
+package idp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"golang.org/x/oauth2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
+)
+
+// oktaGroupsClaimLimit is the most groups Okta puts in a groups claim; a
+// claim this long may have been cut short.
+const oktaGroupsClaimLimit = 100
+
+// oktaGroupsPageSize is the page size requested from the groups API.
+const oktaGroupsPageSize = 200
+
+// OktaProvider handles Okta. Roles come from the `groups` claim (an
+// authorization-server groups claim, requested with the `groups` scope).
+// When the claim is absent or at Okta's 100-group cap, RoleNames reads
+// the full list from GET /api/v1/users/me/groups with the user's own
+// access token (the okta.users.read.self scope) instead. Groups are
+// mapped by name.
+type OktaProvider struct {
+	cfg *identityprovider.IdentityProvider
+	// orgURL is the Okta org origin (scheme + host) derived from the
+	// issuer; the management API lives there, not under the
+	// authorization server path.
+	orgURL string
+}
+
+// NewOktaProvider wires an Okta adapter.
+func NewOktaProvider(cfg *identityprovider.IdentityProvider) *OktaProvider {
+	p := &OktaProvider{cfg: cfg}
+	if cfg != nil && cfg.OIDCIssuerURL != nil {
+		if u, err := url.Parse(*cfg.OIDCIssuerURL); err == nil && u.Host != "" {
+			p.orgURL = u.Scheme + "://" + u.Host
+		}
+	}
+	return p
+}
+
+// Name returns the provider type.
+func (*OktaProvider) Name() string { return string(identityprovider.IdpTypeOkta) }
+
+// Scopes asks for the groups claim and self-read on the users API.
+func (*OktaProvider) Scopes() []string { return []string{"groups", "okta.users.read.self"} }
+
+// CheckClaims adds nothing to the bridge's checks.
+func (*OktaProvider) CheckClaims(*Claims, string) error { return nil }
+
+// RoleNames returns the groups claim when it is complete, else the
+// groups API's answer.
+func (p *OktaProvider) RoleNames(ctx context.Context, c *Claims, tok *oauth2.Token) ([]string, error) {
+	if c.Groups != nil && len(c.Groups) < oktaGroupsClaimLimit {
+		return c.Groups, nil
+	}
+	if tok == nil || p.orgURL == "" {
+		return nil, errors.New("okta: groups claim incomplete and no access token or org URL to look them up")
+	}
+	client := apiClient(ctx, tok)
+	next := fmt.Sprintf("%s/api/v1/users/me/groups?limit=%d", p.orgURL, oktaGroupsPageSize)
+	var out []string
+	for next != "" {
+		var page []struct {
+			Profile struct {
+				Name string `json:"name"`
+			} `json:"profile"`
+		}
+		h, err := getJSONWithHeaders(ctx, client, next, &page)
+		if err != nil {
+			return nil, fmt.Errorf("okta groups: %w", err)
+		}
+		for _, g := range page {
+			if g.Profile.Name != "" {
+				out = append(out, g.Profile.Name)
+			}
+		}
+		next = nextLink(h.Values("Link"))
+	}
+	return union(out), nil
+}
+
+var linkNext = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink extracts the rel="next" target from Link headers (Okta sends
+// one header per relation).
+func nextLink(links []string) string {
+	for _, l := range links {
+		if m := linkNext.FindStringSubmatch(l); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+// Package api wires the inbound webhook gateway: the anchor-only source
+// admin endpoints via huma, and the public /ingest/{sourceCode} receiver
+// via chi (see ingest.go).
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// State bundles deps.
+type State struct {
+	Repo *ingestion.Repository
+	UoW  *usecasepgx.UnitOfWork
+}
+
+const tag = "ingestion"
+
+// deliveryListLimit caps GET .../sources/{id}/deliveries.
+const deliveryListLimit = 100
+
+// Register mounts the ingest source admin endpoints. All of them are
+// anchor-only: a source decides which client its events are billed to.
+func Register(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Get(g, "listIngestSources", "/api/admin/platform/ingest/sources", "List inbound webhook sources", s.list)
+	apiroute.Post(g, "createIngestSource", "/api/admin/platform/ingest/sources", "Create an inbound webhook source", http.StatusCreated, s.create)
+	apiroute.Get(g, "getIngestSource", "/api/admin/platform/ingest/sources/{id}", "Get an inbound webhook source", s.get)
+	apiroute.Put(g, "updateIngestSource", "/api/admin/platform/ingest/sources/{id}", "Update an inbound webhook source", http.StatusOK, s.update)
+	apiroute.Delete(g, "deleteIngestSource", "/api/admin/platform/ingest/sources/{id}", "Delete an inbound webhook source", http.StatusNoContent, s.delete)
+	apiroute.Get(g, "listIngestDeliveries", "/api/admin/platform/ingest/sources/{id}/deliveries", "List a source's recent inbound deliveries", s.deliveries)
+}
+
+func (s *State) list(ctx context.Context, _ *apicommon.Empty) (*apicommon.Out[SourceListResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.List(ctx)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "list_ingest_sources failed", err)
+	}
+	return &apicommon.Out[SourceListResponse]{Body: SourceListResponse{
+		Items: apicommon.MapSlice(rows, func(src **ingestion.Source) SourceResponse { return sourceFromEntity(*src) }),
+	}}, nil
+}
+
+func (s *State) create(ctx context.Context, in *apicommon.In[CreateSourceRequest]) (*apicommon.Out[SourceResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateSource(s.Repo), in.Body.toCommand(), reqctx.ExecutionContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, event.SourceID)
+}
+
+func (s *State) get(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[SourceResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return s.load(ctx, in.ID)
+}
+
+type updateInput struct {
+	ID   string `path:"id"`
+	Body UpdateSourceRequest
+}
+
+func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Out[SourceResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateSource(s.Repo), in.Body.toCommand(in.ID), reqctx.ExecutionContext(ctx)); err != nil {
+		return nil, err
+	}
+	return s.load(ctx, in.ID)
+}
+
+func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.Empty, error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteSource(s.Repo), operations.DeleteCommand{ID: in.ID}, reqctx.ExecutionContext(ctx)); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
+
+type deliveriesInput struct {
+	ID     string `path:"id"`
+	Status string `query:"status" enum:"PENDING,PROCESSING,COMPLETED,SKIPPED,FAILED" doc:"Only deliveries in this status"`
+}
+
+func (s *State) deliveries(ctx context.Context, in *deliveriesInput) (*apicommon.Out[DeliveryListResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if _, err := s.load(ctx, in.ID); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.ListDeliveries(ctx, in.ID, apicommon.OptStr(in.Status), deliveryListLimit)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "list_ingest_deliveries failed", err)
+	}
+	return &apicommon.Out[DeliveryListResponse]{Body: DeliveryListResponse{
+		Items: apicommon.MapSlice(rows, func(d **ingestion.Delivery) DeliveryResponse { return deliveryFromEntity(*d) }),
+	}}, nil
+}
+
+func (s *State) load(ctx context.Context, id string) (*apicommon.Out[SourceResponse], error) {
+	src, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_ingest_source failed", err)
+	}
+	if src == nil {
+		return nil, httperror.NotFound("IngestSource", id)
+	}
+	return &apicommon.Out[SourceResponse]{Body: sourceFromEntity(src)}, nil
+}
@@ -0,0 +1,158 @@
+// dto.go contains the wire-format types for the ingest source API.
+package api
+
+import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion/operations"
+)
+
+// CreateSourceRequest is the wire body for POST
+// /api/admin/platform/ingest/sources.
+type CreateSourceRequest struct {
+	Code              string            `json:"code" doc:"URL segment: requests are posted to /ingest/{code}"`
+	Name              string            `json:"name"`
+	Description       *string           `json:"description,omitempty"`
+	Provider          string            `json:"provider" enum:"STRIPE,GITHUB,SHOPIFY" doc:"Selects the signature scheme and where the provider event name and delivery id are read from"`
+	SigningSecret     string            `json:"signingSecret" doc:"The provider's webhook signing secret; stored encrypted and never returned"`
+	ToleranceSeconds  *int32            `json:"toleranceSeconds,omitempty" doc:"Maximum age of a signature timestamp (STRIPE only). Defaults to 300"`
+	EventTypeCode     *string           `json:"eventTypeCode,omitempty" doc:"Event type for provider events with no entry in eventTypeMappings"`
+	EventTypeMappings map[string]string `json:"eventTypeMappings,omitempty" doc:"Provider event name → event type code. A dotted name (pull_request.opened) also matches its prefix (pull_request)"`
+	EventSource       *string           `json:"eventSource,omitempty" doc:"source attribute of ingested events. Defaults to ingest:{code}"`
+	TransformTemplate *string           `json:"transformTemplate,omitempty" doc:"Go template rendering the event data from {source, providerEvent, data}; must produce JSON. Default: the body as-is"`
+	ClientID          *string           `json:"clientId,omitempty" doc:"Client the ingested events belong to and are metered against"`
+}
+
+func (r CreateSourceRequest) toCommand() operations.CreateCommand {
+	return operations.CreateCommand{
+		Code:              r.Code,
+		Name:              r.Name,
+		Description:       r.Description,
+		Provider:          r.Provider,
+		SigningSecret:     r.SigningSecret,
+		ToleranceSeconds:  r.ToleranceSeconds,
+		EventTypeCode:     r.EventTypeCode,
+		EventTypeMappings: r.EventTypeMappings,
+		EventSource:       r.EventSource,
+		TransformTemplate: r.TransformTemplate,
+		ClientID:          r.ClientID,
+	}
+}
+
+// UpdateSourceRequest is the wire body for PUT
+// /api/admin/platform/ingest/sources/{id}. Omitted fields are unchanged;
+// an empty string clears an optional field.
+type UpdateSourceRequest struct {
+	Name              *string           `json:"name,omitempty"`
+	Description       *string           `json:"description,omitempty"`
+	SigningSecret     *string           `json:"signingSecret,omitempty" doc:"A new signing secret. Omitted or \"********\" keeps the current one"`
+	ToleranceSeconds  *int32            `json:"toleranceSeconds,omitempty"`
+	EventTypeCode     *string           `json:"eventTypeCode,omitempty"`
+	EventTypeMappings map[string]string `json:"eventTypeMappings,omitempty" doc:"Replaces the mappings when given"`
+	EventSource       *string           `json:"eventSource,omitempty"`
+	TransformTemplate *string           `json:"transformTemplate,omitempty"`
+	Status            *string           `json:"status,omitempty" enum:"ACTIVE,PAUSED" doc:"A paused source answers 503 so the provider retries later"`
+}
+
+func (r UpdateSourceRequest) toCommand(id string) operations.UpdateCommand {
+	return operations.UpdateCommand{
+		ID:                id,
+		Name:              r.Name,
+		Description:       r.Description,
+		SigningSecret:     r.SigningSecret,
+		ToleranceSeconds:  r.ToleranceSeconds,
+		EventTypeCode:     r.EventTypeCode,
+		EventTypeMappings: r.EventTypeMappings,
+		EventSource:       r.EventSource,
+		TransformTemplate: r.TransformTemplate,
+		Status:            r.Status,
+	}
+}
+
+// SourceResponse is the wire shape of a source. The signing secret is
+// always masked.
+type SourceResponse struct {
+	ID                string            `json:"id"`
+	Code              string            `json:"code"`
+	Name              string            `json:"name"`
+	Description       *string           `json:"description,omitempty"`
+	Provider          string            `json:"provider" enum:"STRIPE,GITHUB,SHOPIFY"`
+	SigningSecret     string            `json:"signingSecret"`
+	ToleranceSeconds  int32             `json:"toleranceSeconds"`
+	EventTypeCode     *string           `json:"eventTypeCode,omitempty"`
+	EventTypeMappings map[string]string `json:"eventTypeMappings"`
+	EventSource       string            `json:"eventSource"`
+	TransformTemplate *string           `json:"transformTemplate,omitempty"`
+	ClientID          *string           `json:"clientId,omitempty"`
+	Status            string            `json:"status" enum:"ACTIVE,PAUSED"`
+	IngestPath        string            `json:"ingestPath" doc:"Path the provider posts to, relative to the platform's public URL"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+}
+
+func sourceFromEntity(s *ingestion.Source) SourceResponse {
+	mappings := s.EventTypeMappings
+	if mappings == nil {
+		mappings = map[string]string{}
+	}
+	return SourceResponse{
+		ID:                s.ID,
+		Code:              s.Code,
+		Name:              s.Name,
+		Description:       s.Description,
+		Provider:          string(s.Provider),
+		SigningSecret:     ingestion.MaskedValue,
+		ToleranceSeconds:  s.ToleranceSeconds,
+		EventTypeCode:     s.EventTypeCode,
+		EventTypeMappings: mappings,
+		EventSource:       s.EventSourceOrDefault(),
+		TransformTemplate: s.TransformTemplate,
+		ClientID:          s.ClientID,
+		Status:            string(s.Status),
+		IngestPath:        "/ingest/" + s.Code,
+		CreatedAt:         s.CreatedAt,
+		UpdatedAt:         s.UpdatedAt,
+	}
+}
+
+// SourceListResponse wraps GET /api/admin/platform/ingest/sources.
+type SourceListResponse struct {
+	Items []SourceResponse `json:"items"`
+}
+
+// DeliveryResponse is the wire shape of a queued inbound request. The
+// body is not returned.
+type DeliveryResponse struct {
+	ID            string     `json:"id"`
+	ExternalID    string     `json:"externalId"`
+	ProviderEvent *string    `json:"providerEvent,omitempty"`
+	Status        string     `json:"status" enum:"PENDING,PROCESSING,COMPLETED,SKIPPED,FAILED"`
+	Attempts      int32      `json:"attempts"`
+	Error         *string    `json:"error,omitempty"`
+	EventID       *string    `json:"eventId,omitempty"`
+	ReceivedAt    time.Time  `json:"receivedAt"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+}
+
+func deliveryFromEntity(d *ingestion.Delivery) DeliveryResponse {
+	return DeliveryResponse{
+		ID:            d.ID,
+		ExternalID:    d.ExternalID,
+		ProviderEvent: d.ProviderEvent,
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		Error:         d.Error,
+		EventID:       d.EventID,
+		ReceivedAt:    d.ReceivedAt,
+		NextAttemptAt: d.NextAttemptAt,
+		CompletedAt:   d.CompletedAt,
+	}
+}
+
+// DeliveryListResponse wraps GET
+// /api/admin/platform/ingest/sources/{id}/deliveries.
+type DeliveryListResponse struct {
+	Items []DeliveryResponse `json:"items"`
+}
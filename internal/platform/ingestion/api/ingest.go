@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+)
+
+// maxIngestBody caps an inbound request body. Stripe, GitHub and Shopify
+// payloads are well under this.
+const maxIngestBody = 1 << 20
+
+// Store is the persistence the receiver needs. Satisfied by
+// *ingestion.Repository.
+type Store interface {
+	FindByCode(ctx context.Context, code string) (*ingestion.Source, error)
+	Enqueue(ctx context.Context, d *ingestion.Delivery) (bool, error)
+}
+
+// QuotaChecker gates ingest on the source client's monthly event quota.
+// Satisfied by *metering.Meter.
+type QuotaChecker interface {
+	CheckEvents(ctx context.Context, clientID string, n int) error
+}
+
+// Decrypter opens a stored signing secret. Satisfied by
+// *encryption.Service.
+type Decrypter interface {
+	Decrypt(encrypted string) (string, error)
+}
+
+// IngestHandler serves POST /ingest/{sourceCode}: it verifies the
+// provider's signature, queues the body as a delivery and acks with 202.
+// The event is written later by ingestion.Runner, so a slow database
+// never holds up the provider past its webhook timeout.
+type IngestHandler struct {
+	store Store
+	enc   Decrypter
+	meter QuotaChecker // optional
+	now   func() time.Time
+}
+
+// NewIngestHandler wires the receiver. meter may be nil.
+func NewIngestHandler(store Store, enc Decrypter, meter QuotaChecker) *IngestHandler {
+	return &IngestHandler{store: store, enc: enc, meter: meter, now: time.Now}
+}
+
+// Mount registers the route. It MUST sit outside the bearer middleware:
+// providers authenticate with the request signature, not a platform JWT.
+func (h *IngestHandler) Mount(r chi.Router) {
+	r.Post("/ingest/{sourceCode}", h.serve)
+}
+
+// ingestResponse is the 202 body. duplicate is true when the provider
+// redelivered a request already queued; it is acked all the same, with no
+// id.
+type ingestResponse struct {
+	ID        string `json:"id,omitempty"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(httperror.Envelope{Code: code, Message: msg})
+}
+
+func (h *IngestHandler) serve(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	code := chi.URLParam(r, "sourceCode")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIngestBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "request body exceeds 1 MiB")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "could not read request body")
+		return
+	}
+
+	src, err := h.store.FindByCode(ctx, code)
+	if err != nil {
+		slog.Error("ingest: load source failed", "source", code, "err", err)
+		httperror.Write(w, err)
+		return
+	}
+	if src == nil {
+		httperror.Write(w, httperror.NotFound("IngestSource", code))
+		return
+	}
+	if src.Status == ingestion.StatusPaused {
+		writeError(w, http.StatusServiceUnavailable, "SOURCE_PAUSED", "ingest source is paused")
+		return
+	}
+
+	secret, err := h.enc.Decrypt(src.SigningSecret)
+	if err != nil {
+		slog.Error("ingest: decrypt signing secret failed", "source", code, "err", err)
+		httperror.Write(w, err)
+		return
+	}
+	tolerance := time.Duration(src.ToleranceSeconds) * time.Second
+	if err := ingestion.Verify(src.Provider, secret, r.Header, body, tolerance, h.now()); err != nil {
+		slog.Warn("ingest: signature rejected", "source", code, "err", err)
+		writeError(w, http.StatusUnauthorized, "INVALID_SIGNATURE", "request signature is missing or invalid")
+		return
+	}
+	if !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "request body must be JSON")
+		return
+	}
+	if h.meter != nil && src.ClientID != nil {
+		if err := h.meter.CheckEvents(ctx, *src.ClientID, 1); errors.Is(err, metering.ErrQuotaExceeded) {
+			writeError(w, http.StatusTooManyRequests, "QUOTA_EXCEEDED", "monthly event quota exceeded")
+			return
+		}
+	}
+
+	providerEvent, externalID := ingestion.Identify(src.Provider, r.Header, body)
+	d := ingestion.NewDelivery(src.ID, externalID, providerEvent, string(body))
+	queued, err := h.store.Enqueue(ctx, d)
+	if err != nil {
+		slog.Error("ingest: enqueue failed", "source", code, "err", err)
+		httperror.Write(w, err)
+		return
+	}
+
+	resp := ingestResponse{Duplicate: !queued}
+	if queued {
+		resp.ID = d.ID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+)
+
+type fakeStore struct {
+	src    *ingestion.Source
+	queued map[string]*ingestion.Delivery
+}
+
+func (f *fakeStore) FindByCode(_ context.Context, code string) (*ingestion.Source, error) {
+	if f.src != nil && f.src.Code == code {
+		return f.src, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeStore) Enqueue(_ context.Context, d *ingestion.Delivery) (bool, error) {
+	if _, dup := f.queued[d.ExternalID]; dup {
+		return false, nil
+	}
+	f.queued[d.ExternalID] = d
+	return true, nil
+}
+
+// plainText "decrypts" by returning the stored value as-is.
+type plainText struct{}
+
+func (plainText) Decrypt(s string) (string, error) { return s, nil }
+
+type quota struct{ over bool }
+
+func (q quota) CheckEvents(context.Context, string, int) error {
+	if q.over {
+		return metering.ErrQuotaExceeded
+	}
+	return nil
+}
+
+func newTestHandler(meter QuotaChecker) (*fakeStore, http.Handler) {
+	src := ingestion.New("gh", "GitHub", ingestion.ProviderGitHub)
+	src.SigningSecret = "s3cret"
+	src.ClientID = strPtr("clt_1")
+	store := &fakeStore{src: src, queued: map[string]*ingestion.Delivery{}}
+	r := chi.NewRouter()
+	NewIngestHandler(store, plainText{}, meter).Mount(r)
+	return store, r
+}
+
+func strPtr(s string) *string { return &s }
+
+func post(h http.Handler, path, body, secret string) *httptest.ResponseRecorder {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(m.Sum(nil)))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-GitHub-Delivery", "d-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIngest_QueuesVerifiedRequest(t *testing.T) {
+	store, h := newTestHandler(quota{})
+
+	rec := post(h, "/ingest/gh", `{"ref":"main"}`, "s3cret")
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var resp ingestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Duplicate)
+	d := store.queued["d-1"]
+	require.NotNil(t, d)
+	assert.Equal(t, d.ID, resp.ID)
+	assert.Equal(t, "push", *d.ProviderEvent)
+	assert.Equal(t, `{"ref":"main"}`, d.Body)
+
+	// The provider's retry is acked without a second delivery.
+	rec = post(h, "/ingest/gh", `{"ref":"main"}`, "s3cret")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var dup ingestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dup))
+	assert.True(t, dup.Duplicate)
+	assert.Empty(t, dup.ID)
+	assert.Len(t, store.queued, 1)
+}
+
+func TestIngest_Rejections(t *testing.T) {
+	store, h := newTestHandler(quota{})
+
+	assert.Equal(t, http.StatusNotFound, post(h, "/ingest/nope", `{}`, "s3cret").Code)
+	assert.Equal(t, http.StatusUnauthorized, post(h, "/ingest/gh", `{}`, "wrong").Code)
+	assert.Equal(t, http.StatusBadRequest, post(h, "/ingest/gh", `not json`, "s3cret").Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(h, "/ingest/gh", strings.Repeat("x", maxIngestBody+1), "s3cret").Code)
+
+	store.src.Status = ingestion.StatusPaused
+	assert.Equal(t, http.StatusServiceUnavailable, post(h, "/ingest/gh", `{}`, "s3cret").Code)
+	assert.Empty(t, store.queued)
+
+	_, h = newTestHandler(quota{over: true})
+	assert.Equal(t, http.StatusTooManyRequests, post(h, "/ingest/gh", `{}`, "s3cret").Code)
+}
@@ -0,0 +1,211 @@
+// Package ingestion is the inbound webhook gateway. A Source is a
+// third-party sender (Stripe, GitHub, Shopify, …) posting to
+// /ingest/{code}; the endpoint verifies the provider's signature, queues
+// the request as a Delivery and acks, and the Runner turns queued
+// deliveries into platform events of the mapped event type.
+package ingestion
+
+import (
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// Provider selects how a source's requests are signed and where the
+// provider's event name and delivery id are read from.
+type Provider string
+
+const (
+	// ProviderStripe: Stripe-Signature "t=<unix>,v1=<hex>", HMAC-SHA256
+	// over "<t>.<body>"; event name and id from the body's type / id.
+	ProviderStripe Provider = "STRIPE"
+	// ProviderGitHub: X-Hub-Signature-256 "sha256=<hex>" over the body;
+	// event name from X-GitHub-Event (plus the body's action), id from
+	// X-GitHub-Delivery.
+	ProviderGitHub Provider = "GITHUB"
+	// ProviderShopify: X-Shopify-Hmac-Sha256 base64 HMAC-SHA256 over the
+	// body; event name from X-Shopify-Topic, id from X-Shopify-Webhook-Id.
+	ProviderShopify Provider = "SHOPIFY"
+)
+
+// Valid reports whether p is a supported provider.
+func (p Provider) Valid() bool {
+	switch p {
+	case ProviderStripe, ProviderGitHub, ProviderShopify:
+		return true
+	}
+	return false
+}
+
+// Status is the source lifecycle state. A paused source rejects requests
+// with 503 so the provider retries later.
+type Status string
+
+const (
+	StatusActive Status = "ACTIVE"
+	StatusPaused Status = "PAUSED"
+)
+
+// ParseStatus is the lenient parser. Unknown → ACTIVE.
+func ParseStatus(s string) Status {
+	if s == string(StatusPaused) {
+		return StatusPaused
+	}
+	return StatusActive
+}
+
+// MaskedValue stands in for the signing secret on the wire. Sent back on
+// update it keeps the stored secret.
+const MaskedValue = "********"
+
+// DefaultToleranceSeconds bounds how old a Stripe signature timestamp may
+// be (Stripe's own default).
+const DefaultToleranceSeconds = 300
+
+// Source is the aggregate root. Stored in msg_ingest_sources.
+type Source struct {
+	ID                string            `json:"id"`
+	Code              string            `json:"code"`
+	Name              string            `json:"name"`
+	Description       *string           `json:"description,omitempty"`
+	Provider          Provider          `json:"provider"`
+	SigningSecret     string            `json:"-"` // ciphertext
+	ToleranceSeconds  int32             `json:"toleranceSeconds"`
+	EventTypeCode     *string           `json:"eventTypeCode,omitempty"`
+	EventTypeMappings map[string]string `json:"eventTypeMappings"`
+	EventSource       *string           `json:"eventSource,omitempty"`
+	TransformTemplate *string           `json:"transformTemplate,omitempty"`
+	ClientID          *string           `json:"clientId,omitempty"`
+	Status            Status            `json:"status"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (s Source) IDStr() string { return s.ID }
+
+// New constructs an active Source.
+func New(code, name string, provider Provider) *Source {
+	now := time.Now().UTC()
+	return &Source{
+		ID:                tsid.Generate(tsid.IngestSource),
+		Code:              code,
+		Name:              name,
+		Provider:          provider,
+		ToleranceSeconds:  DefaultToleranceSeconds,
+		EventTypeMappings: map[string]string{},
+		Status:            StatusActive,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+// Touch bumps UpdatedAt.
+func (s *Source) Touch() { s.UpdatedAt = time.Now().UTC() }
+
+// EventSourceOrDefault is the source attribute written on ingested
+// events: the configured one, else "ingest:<code>".
+func (s *Source) EventSourceOrDefault() string {
+	if s.EventSource != nil && strings.TrimSpace(*s.EventSource) != "" {
+		return *s.EventSource
+	}
+	return "ingest:" + s.Code
+}
+
+// ResolveEventType maps a provider event name to an event type code. For
+// a dotted name ("pull_request.opened") the full name is tried before its
+// prefix ("pull_request"), then the fallback. ok is false when nothing
+// matches and there is no fallback.
+func (s *Source) ResolveEventType(providerEvent string) (string, bool) {
+	if providerEvent != "" {
+		if code, ok := s.EventTypeMappings[providerEvent]; ok {
+			return code, true
+		}
+		if prefix, _, found := strings.Cut(providerEvent, "."); found {
+			if code, ok := s.EventTypeMappings[prefix]; ok {
+				return code, true
+			}
+		}
+	}
+	if s.EventTypeCode != nil && *s.EventTypeCode != "" {
+		return *s.EventTypeCode, true
+	}
+	return "", false
+}
+
+// CompileTransform parses the transform template. Nil when none is set.
+func (s *Source) CompileTransform() (*payloadtransform.Program, error) {
+	if s.TransformTemplate == nil || strings.TrimSpace(*s.TransformTemplate) == "" {
+		return nil, nil
+	}
+	return payloadtransform.Compile(payloadtransform.EngineGoTemplate, *s.TransformTemplate, payloadtransform.DefaultContentType)
+}
+
+// DeliveryStatus is where a queued inbound request is in its lifecycle.
+type DeliveryStatus string
+
+const (
+	DeliveryPending    DeliveryStatus = "PENDING"
+	DeliveryProcessing DeliveryStatus = "PROCESSING"
+	DeliveryCompleted  DeliveryStatus = "COMPLETED"
+	DeliverySkipped    DeliveryStatus = "SKIPPED"
+	DeliveryFailed     DeliveryStatus = "FAILED"
+)
+
+// Delivery is one verified inbound request. Stored in
+// msg_ingest_deliveries.
+type Delivery struct {
+	ID            string         `json:"id"`
+	SourceID      string         `json:"sourceId"`
+	ExternalID    string         `json:"externalId"`
+	ProviderEvent *string        `json:"providerEvent,omitempty"`
+	Body          string         `json:"-"`
+	Status        DeliveryStatus `json:"status"`
+	Attempts      int32          `json:"attempts"`
+	Error         *string        `json:"error,omitempty"`
+	EventID       *string        `json:"eventId,omitempty"`
+	ReceivedAt    time.Time      `json:"receivedAt"`
+	NextAttemptAt time.Time      `json:"nextAttemptAt"`
+	CompletedAt   *time.Time     `json:"completedAt,omitempty"`
+}
+
+// NewDelivery constructs a pending delivery for source.
+func NewDelivery(sourceID, externalID, providerEvent, body string) *Delivery {
+	now := time.Now().UTC()
+	d := &Delivery{
+		ID:            tsid.Generate(tsid.IngestDelivery),
+		SourceID:      sourceID,
+		ExternalID:    externalID,
+		Body:          body,
+		Status:        DeliveryPending,
+		ReceivedAt:    now,
+		NextAttemptAt: now,
+	}
+	if providerEvent != "" {
+		d.ProviderEvent = &providerEvent
+	}
+	return d
+}
+
+// Config holds the runner knobs (all env-overridable).
+type Config struct {
+	// PollInterval is how often an idle runner looks for due deliveries.
+	PollInterval time.Duration
+	// MaxAttempts is how many times a delivery is tried before it FAILs.
+	MaxAttempts int
+	// StaleAfter is how long a PROCESSING delivery may go unfinished
+	// before another instance picks it up.
+	StaleAfter time.Duration
+}
+
+// ConfigFromEnv builds a Config from FC_INGEST_* env vars.
+func ConfigFromEnv() Config {
+	return Config{
+		PollInterval: time.Duration(envutil.Int("FC_INGEST_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		MaxAttempts:  envutil.Int("FC_INGEST_MAX_ATTEMPTS", 5),
+		StaleAfter:   2 * time.Minute,
+	}
+}
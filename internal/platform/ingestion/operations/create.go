@@ -0,0 +1,168 @@
+// Package operations holds the ingest source use cases.
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/validate"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// CreateCommand is the input DTO. SigningSecret is excluded from JSON so
+// the audit row written from the command never holds it.
+type CreateCommand struct {
+	Code              string            `json:"code"`
+	Name              string            `json:"name"`
+	Description       *string           `json:"description,omitempty"`
+	Provider          string            `json:"provider"`
+	SigningSecret     string            `json:"-"`
+	ToleranceSeconds  *int32            `json:"toleranceSeconds,omitempty"`
+	EventTypeCode     *string           `json:"eventTypeCode,omitempty"`
+	EventTypeMappings map[string]string `json:"eventTypeMappings,omitempty"`
+	EventSource       *string           `json:"eventSource,omitempty"`
+	TransformTemplate *string           `json:"transformTemplate,omitempty"`
+	ClientID          *string           `json:"clientId,omitempty"`
+}
+
+// CreateSource validates cmd, enforces code uniqueness, encrypts the
+// signing secret, persists the source and emits [IngestSourceCreated].
+// Anchor-only; enforced at the controller.
+func CreateSource(repo *ingestion.Repository) usecaseop.Operation[CreateCommand, IngestSourceCreated] {
+	return usecaseop.Operation[CreateCommand, IngestSourceCreated]{
+		Name: "CreateIngestSource",
+		Validate: func(_ context.Context, cmd CreateCommand) error {
+			code := strings.ToLower(strings.TrimSpace(cmd.Code))
+			if code == "" {
+				return usecase.Validation("CODE_REQUIRED", "code is required")
+			}
+			if !validate.CodePattern.MatchString(code) {
+				return usecase.Validation("INVALID_CODE_FORMAT",
+					"Code must start with lowercase letter, contain only lowercase alphanumeric and hyphens")
+			}
+			if strings.TrimSpace(cmd.Name) == "" {
+				return usecase.Validation("NAME_REQUIRED", "name is required")
+			}
+			if !ingestion.Provider(cmd.Provider).Valid() {
+				return usecase.Validation("INVALID_PROVIDER", "provider must be one of STRIPE, GITHUB, SHOPIFY")
+			}
+			if strings.TrimSpace(cmd.SigningSecret) == "" || cmd.SigningSecret == ingestion.MaskedValue {
+				return usecase.Validation("SIGNING_SECRET_REQUIRED", "signingSecret is required")
+			}
+			if cmd.EventTypeCode == nil && len(cmd.EventTypeMappings) == 0 {
+				return usecase.Validation("EVENT_TYPE_REQUIRED",
+					"eventTypeCode or at least one eventTypeMappings entry is required")
+			}
+			return validateMapping(cmd.ToleranceSeconds, cmd.EventTypeCode, cmd.EventTypeMappings, cmd.TransformTemplate)
+		},
+		Authorize: usecaseop.Public[CreateCommand],
+		Execute: func(ctx context.Context, cmd CreateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[IngestSourceCreated], error) {
+			code := strings.ToLower(strings.TrimSpace(cmd.Code))
+			existing, err := repo.FindByCode(ctx, code)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_code failed", err)
+			}
+			if existing != nil {
+				return nil, usecase.Conflict("CODE_EXISTS", "Ingest source with code '"+code+"' already exists")
+			}
+
+			s := ingestion.New(code, strings.TrimSpace(cmd.Name), ingestion.Provider(cmd.Provider))
+			if s.SigningSecret, err = sealSecret(cmd.SigningSecret); err != nil {
+				return nil, err
+			}
+			s.Description = cmd.Description
+			if cmd.ToleranceSeconds != nil {
+				s.ToleranceSeconds = *cmd.ToleranceSeconds
+			}
+			s.EventTypeCode = trimmedOrNil(cmd.EventTypeCode)
+			if cmd.EventTypeMappings != nil {
+				s.EventTypeMappings = cmd.EventTypeMappings
+			}
+			s.EventSource = trimmedOrNil(cmd.EventSource)
+			s.TransformTemplate = trimmedOrNil(cmd.TransformTemplate)
+			s.ClientID = cmd.ClientID
+
+			event := IngestSourceCreated{
+				Metadata: usecase.NewEventMetadata(ec, IngestSourceCreatedType, Source, subjectFor(s.ID)),
+				SourceID: s.ID,
+				Code:     s.Code,
+				Provider: string(s.Provider),
+			}
+			return usecaseop.Save(s, repo, event), nil
+		},
+	}
+}
+
+// validateMapping checks the fields create and update share. Nil means
+// "not given".
+func validateMapping(tolerance *int32, eventTypeCode *string, mappings map[string]string, transform *string) error {
+	if tolerance != nil && (*tolerance < 0 || *tolerance > 3600) {
+		return usecase.Validation("INVALID_TOLERANCE", "toleranceSeconds must be between 0 and 3600")
+	}
+	if eventTypeCode != nil && strings.TrimSpace(*eventTypeCode) != "" && !validEventTypeCode(*eventTypeCode) {
+		return usecase.Validation("INVALID_EVENT_TYPE", "eventTypeCode must be application:subdomain:aggregate:event")
+	}
+	for name, code := range mappings {
+		if strings.TrimSpace(name) == "" {
+			return usecase.Validation("INVALID_EVENT_TYPE_MAPPING", "eventTypeMappings keys must not be blank")
+		}
+		if !validEventTypeCode(code) {
+			return usecase.Validation("INVALID_EVENT_TYPE_MAPPING",
+				fmt.Sprintf("eventTypeMappings[%q] must be application:subdomain:aggregate:event", name))
+		}
+	}
+	if transform != nil && strings.TrimSpace(*transform) != "" {
+		s := ingestion.Source{TransformTemplate: transform}
+		if _, err := s.CompileTransform(); err != nil {
+			return usecase.Validation("INVALID_TRANSFORM", fmt.Sprintf("invalid transform: %v", err))
+		}
+	}
+	return nil
+}
+
+// validEventTypeCode: four non-empty colon-separated segments, the event
+// type code shape.
+func validEventTypeCode(code string) bool {
+	parts := strings.Split(strings.TrimSpace(code), ":")
+	if len(parts) != 4 {
+		return false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// sealSecret encrypts a signing secret with FLOWCATALYST_APP_KEY.
+func sealSecret(secret string) (string, error) {
+	enc, err := encryption.FromEnv()
+	if err != nil {
+		return "", usecase.Internal("ENCRYPTION", "load encryption key failed", err)
+	}
+	if enc == nil {
+		return "", usecase.Validation("ENCRYPTION_UNAVAILABLE",
+			"FLOWCATALYST_APP_KEY is not configured; cannot store a signing secret")
+	}
+	sealed, err := enc.Encrypt(strings.TrimSpace(secret))
+	if err != nil {
+		return "", usecase.Internal("ENCRYPTION", "encrypt signing secret failed", err)
+	}
+	return sealed, nil
+}
+
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
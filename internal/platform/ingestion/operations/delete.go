@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// DeleteCommand is the input DTO.
+type DeleteCommand struct {
+	ID string `json:"id"`
+}
+
+// DeleteSource removes a source, with its queued deliveries, and emits
+// [IngestSourceDeleted]. Anchor-only; enforced at the controller.
+func DeleteSource(repo *ingestion.Repository) usecaseop.Operation[DeleteCommand, IngestSourceDeleted] {
+	return usecaseop.Operation[DeleteCommand, IngestSourceDeleted]{
+		Name: "DeleteIngestSource",
+		Validate: func(_ context.Context, cmd DeleteCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[DeleteCommand],
+		Execute: func(ctx context.Context, cmd DeleteCommand, ec usecase.ExecutionContext) (usecaseop.Plan[IngestSourceDeleted], error) {
+			s, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if s == nil {
+				return nil, httperror.NotFound("IngestSource", cmd.ID)
+			}
+			event := IngestSourceDeleted{
+				Metadata: usecase.NewEventMetadata(ec, IngestSourceDeletedType, Source, subjectFor(s.ID)),
+				SourceID: s.ID,
+				Code:     s.Code,
+			}
+			return usecaseop.Delete(s, repo, event), nil
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+const (
+	IngestSourceCreatedType = "platform:admin:ingest-source:created"
+	IngestSourceUpdatedType = "platform:admin:ingest-source:updated"
+	IngestSourceDeletedType = "platform:admin:ingest-source:deleted"
+	Source                  = "platform:admin"
+)
+
+func subjectFor(id string) string { return "platform.ingestsource." + id }
+func groupFor(id string) string   { return "platform:ingestsource:" + id }
+
+// IngestSourceCreated event.
+type IngestSourceCreated struct {
+	Metadata usecase.EventMetadata
+	SourceID string
+	Code     string
+	Provider string
+}
+
+func (e IngestSourceCreated) EventID() string       { return e.Metadata.EventID }
+func (e IngestSourceCreated) EventType() string     { return IngestSourceCreatedType }
+func (e IngestSourceCreated) SpecVersion() string   { return "1.0" }
+func (e IngestSourceCreated) Source() string        { return Source }
+func (e IngestSourceCreated) Subject() string       { return subjectFor(e.SourceID) }
+func (e IngestSourceCreated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e IngestSourceCreated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e IngestSourceCreated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e IngestSourceCreated) CausationID() string   { return e.Metadata.CausationID }
+func (e IngestSourceCreated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e IngestSourceCreated) MessageGroup() string  { return groupFor(e.SourceID) }
+func (e IngestSourceCreated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SourceID string `json:"sourceId"`
+		Code     string `json:"code"`
+		Provider string `json:"provider"`
+	}{e.SourceID, e.Code, e.Provider})
+}
+
+// IngestSourceUpdated event.
+type IngestSourceUpdated struct {
+	Metadata usecase.EventMetadata
+	SourceID string
+	Code     string
+	// SecretRotated is true when the update replaced the signing secret.
+	SecretRotated bool
+}
+
+func (e IngestSourceUpdated) EventID() string       { return e.Metadata.EventID }
+func (e IngestSourceUpdated) EventType() string     { return IngestSourceUpdatedType }
+func (e IngestSourceUpdated) SpecVersion() string   { return "1.0" }
+func (e IngestSourceUpdated) Source() string        { return Source }
+func (e IngestSourceUpdated) Subject() string       { return subjectFor(e.SourceID) }
+func (e IngestSourceUpdated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e IngestSourceUpdated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e IngestSourceUpdated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e IngestSourceUpdated) CausationID() string   { return e.Metadata.CausationID }
+func (e IngestSourceUpdated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e IngestSourceUpdated) MessageGroup() string  { return groupFor(e.SourceID) }
+func (e IngestSourceUpdated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SourceID      string `json:"sourceId"`
+		Code          string `json:"code"`
+		SecretRotated bool   `json:"secretRotated"`
+	}{e.SourceID, e.Code, e.SecretRotated})
+}
+
+// IngestSourceDeleted event.
+type IngestSourceDeleted struct {
+	Metadata usecase.EventMetadata
+	SourceID string
+	Code     string
+}
+
+func (e IngestSourceDeleted) EventID() string       { return e.Metadata.EventID }
+func (e IngestSourceDeleted) EventType() string     { return IngestSourceDeletedType }
+func (e IngestSourceDeleted) SpecVersion() string   { return "1.0" }
+func (e IngestSourceDeleted) Source() string        { return Source }
+func (e IngestSourceDeleted) Subject() string       { return subjectFor(e.SourceID) }
+func (e IngestSourceDeleted) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e IngestSourceDeleted) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e IngestSourceDeleted) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e IngestSourceDeleted) CausationID() string   { return e.Metadata.CausationID }
+func (e IngestSourceDeleted) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e IngestSourceDeleted) MessageGroup() string  { return groupFor(e.SourceID) }
+func (e IngestSourceDeleted) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SourceID string `json:"sourceId"`
+		Code     string `json:"code"`
+	}{e.SourceID, e.Code})
+}
@@ -0,0 +1,103 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// UpdateCommand is the input DTO. Nil fields are left unchanged; an empty
+// string clears an optional one and an empty eventTypeMappings object
+// clears the mappings. A SigningSecret of ingestion.MaskedValue keeps the
+// stored secret.
+type UpdateCommand struct {
+	ID                string            `json:"id"`
+	Name              *string           `json:"name,omitempty"`
+	Description       *string           `json:"description,omitempty"`
+	SigningSecret     *string           `json:"-"`
+	ToleranceSeconds  *int32            `json:"toleranceSeconds,omitempty"`
+	EventTypeCode     *string           `json:"eventTypeCode,omitempty"`
+	EventTypeMappings map[string]string `json:"eventTypeMappings,omitempty"`
+	EventSource       *string           `json:"eventSource,omitempty"`
+	TransformTemplate *string           `json:"transformTemplate,omitempty"`
+	Status            *string           `json:"status,omitempty"`
+}
+
+// UpdateSource mutates a source and emits [IngestSourceUpdated].
+// Anchor-only; enforced at the controller.
+func UpdateSource(repo *ingestion.Repository) usecaseop.Operation[UpdateCommand, IngestSourceUpdated] {
+	return usecaseop.Operation[UpdateCommand, IngestSourceUpdated]{
+		Name: "UpdateIngestSource",
+		Validate: func(_ context.Context, cmd UpdateCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			if cmd.Name != nil && strings.TrimSpace(*cmd.Name) == "" {
+				return usecase.Validation("NAME_REQUIRED", "name cannot be empty")
+			}
+			if cmd.Status != nil && *cmd.Status != string(ingestion.StatusActive) && *cmd.Status != string(ingestion.StatusPaused) {
+				return usecase.Validation("INVALID_STATUS", "status must be ACTIVE or PAUSED")
+			}
+			return validateMapping(cmd.ToleranceSeconds, cmd.EventTypeCode, cmd.EventTypeMappings, cmd.TransformTemplate)
+		},
+		Authorize: usecaseop.Public[UpdateCommand],
+		Execute: func(ctx context.Context, cmd UpdateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[IngestSourceUpdated], error) {
+			s, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if s == nil {
+				return nil, httperror.NotFound("IngestSource", cmd.ID)
+			}
+
+			rotated := false
+			if cmd.SigningSecret != nil && strings.TrimSpace(*cmd.SigningSecret) != "" && *cmd.SigningSecret != ingestion.MaskedValue {
+				if s.SigningSecret, err = sealSecret(*cmd.SigningSecret); err != nil {
+					return nil, err
+				}
+				rotated = true
+			}
+			if cmd.Name != nil {
+				s.Name = strings.TrimSpace(*cmd.Name)
+			}
+			if cmd.Description != nil {
+				s.Description = trimmedOrNil(cmd.Description)
+			}
+			if cmd.ToleranceSeconds != nil {
+				s.ToleranceSeconds = *cmd.ToleranceSeconds
+			}
+			if cmd.EventTypeCode != nil {
+				s.EventTypeCode = trimmedOrNil(cmd.EventTypeCode)
+			}
+			if cmd.EventTypeMappings != nil {
+				s.EventTypeMappings = cmd.EventTypeMappings
+			}
+			if cmd.EventSource != nil {
+				s.EventSource = trimmedOrNil(cmd.EventSource)
+			}
+			if cmd.TransformTemplate != nil {
+				s.TransformTemplate = trimmedOrNil(cmd.TransformTemplate)
+			}
+			if cmd.Status != nil {
+				s.Status = ingestion.ParseStatus(*cmd.Status)
+			}
+			if s.EventTypeCode == nil && len(s.EventTypeMappings) == 0 {
+				return nil, usecase.Validation("EVENT_TYPE_REQUIRED",
+					"eventTypeCode or at least one eventTypeMappings entry is required")
+			}
+			s.Touch()
+
+			event := IngestSourceUpdated{
+				Metadata:      usecase.NewEventMetadata(ec, IngestSourceUpdatedType, Source, subjectFor(s.ID)),
+				SourceID:      s.ID,
+				Code:          s.Code,
+				SecretRotated: rotated,
+			}
+			return usecaseop.Save(s, repo, event), nil
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// Repository reads/writes msg_ingest_sources and msg_ingest_deliveries.
+// Sources change through the UoW via Persist; deliveries are written
+// directly by the ingest endpoint and the runner.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+const sourceCols = `id, code, name, description, provider, signing_secret, tolerance_seconds,
+	event_type_code, event_type_mappings, event_source, transform_template, client_id,
+	status, created_at, updated_at`
+
+func scanSource(row pgx.Row) (*Source, error) {
+	var s Source
+	var mappings []byte
+	if err := row.Scan(&s.ID, &s.Code, &s.Name, &s.Description, &s.Provider, &s.SigningSecret,
+		&s.ToleranceSeconds, &s.EventTypeCode, &mappings, &s.EventSource, &s.TransformTemplate,
+		&s.ClientID, &s.Status, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(mappings, &s.EventTypeMappings); err != nil {
+		return nil, fmt.Errorf("decode event_type_mappings: %w", err)
+	}
+	if s.EventTypeMappings == nil {
+		s.EventTypeMappings = map[string]string{}
+	}
+	return &s, nil
+}
+
+func (r *Repository) findSource(ctx context.Context, where string, arg any) (*Source, error) {
+	s, err := scanSource(r.pool.QueryRow(ctx, `SELECT `+sourceCols+` FROM msg_ingest_sources WHERE `+where, arg))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find_ingest_source: %w", err)
+	}
+	return s, nil
+}
+
+// FindByID loads one source. Returns (nil, nil) when not found.
+func (r *Repository) FindByID(ctx context.Context, id string) (*Source, error) {
+	return r.findSource(ctx, `id = $1`, id)
+}
+
+// FindByCode loads one source by its code. Returns (nil, nil) when not
+// found.
+func (r *Repository) FindByCode(ctx context.Context, code string) (*Source, error) {
+	return r.findSource(ctx, `code = $1`, code)
+}
+
+// List returns every source, by code.
+func (r *Repository) List(ctx context.Context) ([]*Source, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+sourceCols+` FROM msg_ingest_sources ORDER BY code`)
+	if err != nil {
+		return nil, fmt.Errorf("list_ingest_sources: %w", err)
+	}
+	defer rows.Close()
+	var out []*Source
+	for rows.Next() {
+		s, err := scanSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list_ingest_sources: %w", err)
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// Persist implements usecasepgx.Persist[Source].
+func (r *Repository) Persist(ctx context.Context, s *Source, tx *usecasepgx.DbTx) error {
+	mappings, err := json.Marshal(s.EventTypeMappings)
+	if err != nil {
+		return fmt.Errorf("persist ingest source: %w", err)
+	}
+	_, err = tx.Inner().Exec(ctx,
+		`INSERT INTO msg_ingest_sources
+		     (id, code, name, description, provider, signing_secret, tolerance_seconds,
+		      event_type_code, event_type_mappings, event_source, transform_template, client_id,
+		      status, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		 ON CONFLICT (id) DO UPDATE
+		    SET name                = EXCLUDED.name,
+		        description         = EXCLUDED.description,
+		        provider            = EXCLUDED.provider,
+		        signing_secret      = EXCLUDED.signing_secret,
+		        tolerance_seconds   = EXCLUDED.tolerance_seconds,
+		        event_type_code     = EXCLUDED.event_type_code,
+		        event_type_mappings = EXCLUDED.event_type_mappings,
+		        event_source        = EXCLUDED.event_source,
+		        transform_template  = EXCLUDED.transform_template,
+		        status              = EXCLUDED.status,
+		        updated_at          = EXCLUDED.updated_at`,
+		s.ID, s.Code, s.Name, s.Description, s.Provider, s.SigningSecret, s.ToleranceSeconds,
+		s.EventTypeCode, mappings, s.EventSource, s.TransformTemplate, s.ClientID,
+		s.Status, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("persist ingest source: %w", err)
+	}
+	return nil
+}
+
+// Delete implements usecasepgx.Persist[Source]. Queued deliveries go with
+// it (ON DELETE CASCADE).
+func (r *Repository) Delete(ctx context.Context, s *Source, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx, `DELETE FROM msg_ingest_sources WHERE id = $1`, s.ID)
+	return err
+}
+
+const deliveryCols = `id, source_id, external_id, provider_event, body, status, attempts, error,
+	event_id, received_at, next_attempt_at, completed_at`
+
+func scanDelivery(row pgx.Row) (*Delivery, error) {
+	var d Delivery
+	if err := row.Scan(&d.ID, &d.SourceID, &d.ExternalID, &d.ProviderEvent, &d.Body, &d.Status,
+		&d.Attempts, &d.Error, &d.EventID, &d.ReceivedAt, &d.NextAttemptAt, &d.CompletedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Enqueue stores a verified delivery. queued is false when the source
+// already has one with the same external id (a provider retry), in which
+// case nothing is written.
+func (r *Repository) Enqueue(ctx context.Context, d *Delivery) (queued bool, err error) {
+	tag, err := r.pool.Exec(ctx,
+		`INSERT INTO msg_ingest_deliveries
+		     (id, source_id, external_id, provider_event, body, status, received_at, next_attempt_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (source_id, external_id) DO NOTHING`,
+		d.ID, d.SourceID, d.ExternalID, d.ProviderEvent, d.Body, d.Status, d.ReceivedAt, d.NextAttemptAt)
+	if err != nil {
+		return false, fmt.Errorf("enqueue_ingest_delivery: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListDeliveries returns a source's deliveries newest first.
+func (r *Repository) ListDeliveries(ctx context.Context, sourceID string, status *string, limit int) ([]*Delivery, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+deliveryCols+` FROM msg_ingest_deliveries
+		  WHERE source_id = $1 AND ($2::text IS NULL OR status = $2)
+		  ORDER BY received_at DESC LIMIT $3`, sourceID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list_ingest_deliveries: %w", err)
+	}
+	defer rows.Close()
+	var out []*Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list_ingest_deliveries: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// Claim moves the oldest due PENDING delivery — or a PROCESSING one whose
+// heartbeat is older than staleAfter — to PROCESSING, counting the
+// attempt. Returns (nil, nil) when there is nothing due.
+func (r *Repository) Claim(ctx context.Context, staleAfter time.Duration) (*Delivery, error) {
+	d, err := scanDelivery(r.pool.QueryRow(ctx,
+		`UPDATE msg_ingest_deliveries
+		    SET status = 'PROCESSING', attempts = attempts + 1, heartbeat_at = NOW()
+		  WHERE id = (
+		        SELECT id FROM msg_ingest_deliveries
+		         WHERE (status = 'PENDING' AND next_attempt_at <= NOW())
+		            OR (status = 'PROCESSING' AND heartbeat_at < $1)
+		         ORDER BY next_attempt_at
+		         LIMIT 1
+		         FOR UPDATE SKIP LOCKED)
+		 RETURNING `+deliveryCols,
+		time.Now().Add(-staleAfter).UTC()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim_ingest_delivery: %w", err)
+	}
+	return d, nil
+}
+
+// Complete runs write (the event insert) and marks the delivery COMPLETED
+// in one transaction, so a crash between the two can't write the event
+// twice.
+func (r *Repository) Complete(ctx context.Context, id, eventID string, write func(pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx,
+			`UPDATE msg_ingest_deliveries
+			    SET status = 'COMPLETED', event_id = $2, error = NULL, completed_at = NOW()
+			  WHERE id = $1`, id, eventID)
+		return err
+	})
+}
+
+// Finish records a terminal outcome: SKIPPED or FAILED with reason.
+func (r *Repository) Finish(ctx context.Context, id string, status DeliveryStatus, reason string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_ingest_deliveries
+		    SET status = $2, error = $3, completed_at = NOW()
+		  WHERE id = $1`, id, status, reason)
+	if err != nil {
+		return fmt.Errorf("finish_ingest_delivery: %w", err)
+	}
+	return nil
+}
+
+// Retry puts a delivery back to PENDING until next.
+func (r *Repository) Retry(ctx context.Context, id, reason string, next time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE msg_ingest_deliveries
+		    SET status = 'PENDING', error = $2, next_attempt_at = $3
+		  WHERE id = $1`, id, reason, next.UTC())
+	if err != nil {
+		return fmt.Errorf("retry_ingest_delivery: %w", err)
+	}
+	return nil
+}
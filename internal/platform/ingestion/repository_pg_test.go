@@ -0,0 +1,71 @@
+//go:build integration
+
+package ingestion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+// TestRunnerDeliversQueuedRequest covers the queue end to end: a provider
+// retry is not queued twice, the runner writes one event of the mapped
+// type and completes the delivery, and an unmapped provider event is
+// skipped.
+func TestRunnerDeliversQueuedRequest(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := NewRepository(pool)
+
+	src := New("stripe-it", "Stripe", ProviderStripe)
+	_, err := pool.Exec(ctx,
+		`INSERT INTO msg_ingest_sources (id, code, name, provider, signing_secret, event_type_mappings)
+		 VALUES ($1, $2, $3, $4, 'sealed', '{"invoice": "billing:stripe:invoice:changed"}')`,
+		src.ID, src.Code, src.Name, src.Provider)
+	require.NoError(t, err)
+
+	d := NewDelivery(src.ID, "evt_1", "invoice.paid", `{"id":"evt_1"}`)
+	queued, err := repo.Enqueue(ctx, d)
+	require.NoError(t, err)
+	assert.True(t, queued)
+	queued, err = repo.Enqueue(ctx, NewDelivery(src.ID, "evt_1", "invoice.paid", `{"id":"evt_1"}`))
+	require.NoError(t, err)
+	assert.False(t, queued, "a provider retry must not queue a second delivery")
+	skip := NewDelivery(src.ID, "evt_2", "customer.created", `{"id":"evt_2"}`)
+	_, err = repo.Enqueue(ctx, skip)
+	require.NoError(t, err)
+
+	r := NewRunner(Config{MaxAttempts: 3}, repo, event.NewRepository(pool), nil)
+	for range 2 {
+		ran, err := r.runOnce(ctx)
+		require.NoError(t, err)
+		assert.True(t, ran)
+	}
+	ran, err := r.runOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, ran, "queue drained")
+
+	rows, err := repo.ListDeliveries(ctx, src.ID, nil, 10)
+	require.NoError(t, err)
+	byExt := map[string]*Delivery{}
+	for _, row := range rows {
+		byExt[row.ExternalID] = row
+	}
+	require.Len(t, byExt, 2)
+	assert.Equal(t, DeliveryCompleted, byExt["evt_1"].Status)
+	require.NotNil(t, byExt["evt_1"].EventID)
+	assert.Equal(t, DeliverySkipped, byExt["evt_2"].Status)
+
+	var typ, dedup string
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT type, deduplication_id FROM msg_events WHERE id = $1`, *byExt["evt_1"].EventID).Scan(&typ, &dedup))
+	assert.Equal(t, "billing:stripe:invoice:changed", typ)
+	assert.Equal(t, "ingest:stripe-it:evt_1", dedup)
+}
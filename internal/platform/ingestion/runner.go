@@ -0,0 +1,192 @@
+package ingestion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+)
+
+// EventWriter writes an ingested event inside the delivery's completion
+// transaction. Satisfied by *event.Repository.
+type EventWriter interface {
+	InsertTx(ctx context.Context, tx pgx.Tx, e *event.Event) error
+}
+
+// UsageRecorder counts ingested events against the source's client.
+// Satisfied by *metering.Meter.
+type UsageRecorder interface {
+	RecordEvents(clientID string, n int)
+}
+
+// errNoMapping marks a delivery whose provider event maps to no event
+// type: it is SKIPPED, not FAILED.
+var errNoMapping = errors.New("no event type mapped")
+
+// maxRetryBackoff caps the delay between attempts of a delivery.
+const maxRetryBackoff = 5 * time.Minute
+
+// Runner turns queued deliveries into events, one at a time per
+// instance. Construct with NewRunner and run Run in its own goroutine.
+type Runner struct {
+	cfg    Config
+	repo   *Repository
+	events EventWriter
+	usage  UsageRecorder // optional
+}
+
+// NewRunner wires a runner. usage may be nil.
+func NewRunner(cfg Config, repo *Repository, events EventWriter, usage UsageRecorder) *Runner {
+	return &Runner{cfg: cfg, repo: repo, events: events, usage: usage}
+}
+
+// Run polls for due deliveries every PollInterval until ctx is cancelled,
+// draining the queue on each tick.
+func (r *Runner) Run(ctx context.Context) {
+	t := time.NewTicker(r.cfg.PollInterval)
+	defer t.Stop()
+	slog.Info("ingest runner starting", "interval", r.cfg.PollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("ingest runner stopped")
+			return
+		case <-t.C:
+			for ctx.Err() == nil {
+				ran, err := r.runOnce(ctx)
+				if err != nil {
+					slog.Warn("ingest runner error", "err", err)
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
+
+// runOnce claims and processes one delivery. ran is false when nothing is
+// due.
+func (r *Runner) runOnce(ctx context.Context) (ran bool, err error) {
+	d, err := r.repo.Claim(ctx, r.cfg.StaleAfter)
+	if err != nil || d == nil {
+		return false, err
+	}
+	src, err := r.repo.FindByID(ctx, d.SourceID)
+	if err != nil {
+		return true, r.retry(ctx, d, err)
+	}
+	if src == nil {
+		return true, r.repo.Finish(ctx, d.ID, DeliveryFailed, "source no longer exists")
+	}
+
+	ev, err := BuildEvent(src, d)
+	switch {
+	case errors.Is(err, errNoMapping):
+		return true, r.repo.Finish(ctx, d.ID, DeliverySkipped, err.Error())
+	case err != nil:
+		slog.Warn("ingest delivery failed", "delivery_id", d.ID, "source", src.Code, "err", err)
+		return true, r.repo.Finish(ctx, d.ID, DeliveryFailed, err.Error())
+	}
+
+	if err := r.repo.Complete(ctx, d.ID, ev.ID, func(tx pgx.Tx) error {
+		return r.events.InsertTx(ctx, tx, ev)
+	}); err != nil {
+		return true, r.retry(ctx, d, err)
+	}
+	if r.usage != nil && ev.ClientID != nil {
+		r.usage.RecordEvents(*ev.ClientID, 1)
+	}
+	return true, nil
+}
+
+// retry schedules another attempt with exponential backoff, or FAILs the
+// delivery once MaxAttempts is spent.
+func (r *Runner) retry(ctx context.Context, d *Delivery, cause error) error {
+	if int(d.Attempts) >= r.cfg.MaxAttempts {
+		slog.Warn("ingest delivery failed", "delivery_id", d.ID, "attempts", d.Attempts, "err", cause)
+		return r.repo.Finish(ctx, d.ID, DeliveryFailed, cause.Error())
+	}
+	return r.repo.Retry(ctx, d.ID, cause.Error(), time.Now().Add(retryBackoff(d.Attempts)))
+}
+
+// retryBackoff is 5s after the first attempt, doubling, capped at
+// maxRetryBackoff.
+func retryBackoff(attempts int32) time.Duration {
+	d := 5 * time.Second
+	for i := int32(1); i < attempts && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryBackoff)
+}
+
+// BuildEvent maps a delivery to the event it produces: the event type
+// from the source's mapping, the data from the body (through the
+// transform when there is one), deduplicated on the provider's delivery
+// id. Errors other than errNoMapping are permanent — retrying the same
+// body through the same template fails the same way.
+func BuildEvent(src *Source, d *Delivery) (*event.Event, error) {
+	providerEvent := ""
+	if d.ProviderEvent != nil {
+		providerEvent = *d.ProviderEvent
+	}
+	eventType, ok := src.ResolveEventType(providerEvent)
+	if !ok {
+		return nil, fmt.Errorf("%w for provider event %q", errNoMapping, providerEvent)
+	}
+
+	data := json.RawMessage(d.Body)
+	if !json.Valid(data) {
+		return nil, errors.New("body is not valid JSON")
+	}
+	prog, err := src.CompileTransform()
+	if err != nil {
+		return nil, fmt.Errorf("compile transform: %w", err)
+	}
+	if prog != nil {
+		out, err := prog.Render(map[string]any{
+			"source":        src.Code,
+			"providerEvent": providerEvent,
+			"data":          data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("render transform: %w", err)
+		}
+		if !json.Valid(out) {
+			return nil, errors.New("transform output is not valid JSON")
+		}
+		data = out
+	}
+
+	ev := event.New(eventType, src.EventSourceOrDefault(), "", data)
+	ev.DeduplicationID = dedupKey(src.Code, d.ExternalID)
+	ev.Time = d.ReceivedAt
+	ev.ClientID = src.ClientID
+	ev.Context = append(ev.Context,
+		event.ContextEntry{Key: "ingestSource", Value: src.Code},
+		event.ContextEntry{Key: "ingestDeliveryId", Value: d.ID},
+	)
+	if providerEvent != "" {
+		ev.Context = append(ev.Context, event.ContextEntry{Key: "providerEvent", Value: providerEvent})
+	}
+	return ev, nil
+}
+
+// dedupKey is "ingest:<code>:<external id>", hashed when that would
+// overflow msg_events.deduplication_id.
+func dedupKey(code, externalID string) string {
+	key := "ingest:" + code + ":" + externalID
+	if len(key) <= 200 {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "ingest:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestResolveEventType(t *testing.T) {
+	s := New("github", "GitHub", ProviderGitHub)
+	s.EventTypeMappings = map[string]string{
+		"pull_request.closed": "gh:repo:pull-request:merged",
+		"pull_request":        "gh:repo:pull-request:changed",
+	}
+
+	code, ok := s.ResolveEventType("pull_request.closed")
+	assert.True(t, ok)
+	assert.Equal(t, "gh:repo:pull-request:merged", code)
+
+	code, ok = s.ResolveEventType("pull_request.opened")
+	assert.True(t, ok)
+	assert.Equal(t, "gh:repo:pull-request:changed", code)
+
+	_, ok = s.ResolveEventType("push")
+	assert.False(t, ok)
+
+	s.EventTypeCode = strPtr("gh:repo:webhook:received")
+	code, ok = s.ResolveEventType("push")
+	assert.True(t, ok)
+	assert.Equal(t, "gh:repo:webhook:received", code)
+}
+
+func TestBuildEvent(t *testing.T) {
+	s := New("stripe", "Stripe", ProviderStripe)
+	s.EventTypeMappings = map[string]string{"invoice": "billing:stripe:invoice:changed"}
+	s.ClientID = strPtr("clt_1")
+	d := NewDelivery(s.ID, "evt_1", "invoice.paid", `{"id":"evt_1","amount":100}`)
+
+	ev, err := BuildEvent(s, d)
+	require.NoError(t, err)
+	assert.Equal(t, "billing:stripe:invoice:changed", ev.Type)
+	assert.Equal(t, "ingest:stripe", ev.Source)
+	assert.Equal(t, "ingest:stripe:evt_1", ev.DeduplicationID)
+	assert.JSONEq(t, d.Body, string(ev.Data))
+	assert.Equal(t, d.ReceivedAt, ev.Time)
+	assert.Equal(t, "clt_1", *ev.ClientID)
+	keys := map[string]string{}
+	for _, c := range ev.Context {
+		keys[c.Key] = c.Value
+	}
+	assert.Equal(t, map[string]string{"ingestSource": "stripe", "ingestDeliveryId": d.ID, "providerEvent": "invoice.paid"}, keys)
+
+	// Same delivery id → same dedup key, so a replay can't double-insert.
+	again, err := BuildEvent(s, d)
+	require.NoError(t, err)
+	assert.Equal(t, ev.DeduplicationID, again.DeduplicationID)
+}
+
+func TestBuildEvent_Transform(t *testing.T) {
+	s := New("stripe", "Stripe", ProviderStripe)
+	s.EventTypeCode = strPtr("billing:stripe:event:received")
+	s.TransformTemplate = strPtr(`{"kind":{{json .providerEvent}},"amount":{{json .data.amount}}}`)
+	d := NewDelivery(s.ID, "evt_1", "invoice.paid", `{"amount":100}`)
+
+	ev, err := BuildEvent(s, d)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"invoice.paid","amount":100}`, string(ev.Data))
+
+	s.TransformTemplate = strPtr(`not json`)
+	_, err = BuildEvent(s, d)
+	assert.ErrorContains(t, err, "not valid JSON")
+}
+
+func TestBuildEvent_Errors(t *testing.T) {
+	s := New("gh", "GitHub", ProviderGitHub)
+	s.EventTypeMappings = map[string]string{"push": "gh:repo:push:received"}
+
+	_, err := BuildEvent(s, NewDelivery(s.ID, "d-1", "issues.opened", `{}`))
+	assert.ErrorIs(t, err, errNoMapping)
+
+	_, err = BuildEvent(s, NewDelivery(s.ID, "d-2", "push", `not json`))
+	assert.ErrorContains(t, err, "not valid JSON")
+}
+
+func TestDedupKey_Long(t *testing.T) {
+	key := dedupKey("src", strings.Repeat("x", 300))
+	assert.LessOrEqual(t, len(key), 200)
+	assert.True(t, strings.HasPrefix(key, "ingest:"))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, retryBackoff(1))
+	assert.Equal(t, 10*time.Second, retryBackoff(2))
+	assert.Equal(t, 40*time.Second, retryBackoff(4))
+	assert.Equal(t, maxRetryBackoff, retryBackoff(20))
+}
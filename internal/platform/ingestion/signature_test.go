@@ -0,0 +1,95 @@
+package ingestion
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "whsec_test"
+
+func sign(msg string) []byte {
+	m := hmac.New(sha256.New, []byte(testSecret))
+	m.Write([]byte(msg))
+	return m.Sum(nil)
+}
+
+func TestVerify_Stripe(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"invoice.paid"}`)
+	now := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	good := hex.EncodeToString(sign(ts + "." + string(body)))
+	header := func(v string) http.Header { return http.Header{"Stripe-Signature": {v}} }
+
+	assert.NoError(t, Verify(ProviderStripe, testSecret, header("t="+ts+",v1="+good), body, 5*time.Minute, now))
+	// Any v1 may match: Stripe sends one per secret while rotating.
+	assert.NoError(t, Verify(ProviderStripe, testSecret, header("t="+ts+",v1=00ff,v1="+good), body, 5*time.Minute, now))
+
+	for name, h := range map[string]http.Header{
+		"missing":      {},
+		"no timestamp": header("v1=" + good),
+		"wrong sig":    header("t=" + ts + ",v1=" + hex.EncodeToString(sign("other"))),
+		"tampered ts":  header("t=" + strconv.FormatInt(now.Unix()+1, 10) + ",v1=" + good),
+	} {
+		assert.True(t, errors.Is(Verify(ProviderStripe, testSecret, h, body, 5*time.Minute, now), ErrSignature), name)
+	}
+
+	late := now.Add(6 * time.Minute)
+	assert.ErrorIs(t, Verify(ProviderStripe, testSecret, header("t="+ts+",v1="+good), body, 5*time.Minute, late), ErrSignature)
+	// Zero tolerance disables the age check.
+	assert.NoError(t, Verify(ProviderStripe, testSecret, header("t="+ts+",v1="+good), body, 0, late))
+}
+
+func TestVerify_GitHub(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	good := "sha256=" + hex.EncodeToString(sign(string(body)))
+
+	assert.NoError(t, Verify(ProviderGitHub, testSecret, http.Header{"X-Hub-Signature-256": {good}}, body, 0, time.Now()))
+	assert.ErrorIs(t, Verify(ProviderGitHub, testSecret, http.Header{"X-Hub-Signature-256": {good}}, []byte(`{}`), 0, time.Now()), ErrSignature)
+	assert.ErrorIs(t, Verify(ProviderGitHub, "other", http.Header{"X-Hub-Signature-256": {good}}, body, 0, time.Now()), ErrSignature)
+	assert.ErrorIs(t, Verify(ProviderGitHub, testSecret, http.Header{"X-Hub-Signature": {good}}, body, 0, time.Now()), ErrSignature)
+}
+
+func TestVerify_Shopify(t *testing.T) {
+	body := []byte(`{"id":42}`)
+	good := base64.StdEncoding.EncodeToString(sign(string(body)))
+
+	assert.NoError(t, Verify(ProviderShopify, testSecret, http.Header{"X-Shopify-Hmac-Sha256": {good}}, body, 0, time.Now()))
+	assert.ErrorIs(t, Verify(ProviderShopify, testSecret, http.Header{"X-Shopify-Hmac-Sha256": {good}}, []byte(`{"id":43}`), 0, time.Now()), ErrSignature)
+	assert.ErrorIs(t, Verify(ProviderShopify, testSecret, http.Header{"X-Shopify-Hmac-Sha256": {"not base64!"}}, body, 0, time.Now()), ErrSignature)
+	assert.ErrorIs(t, Verify(ProviderShopify, testSecret, http.Header{}, body, 0, time.Now()), ErrSignature)
+}
+
+func TestIdentify(t *testing.T) {
+	ev, id := Identify(ProviderStripe, http.Header{}, []byte(`{"id":"evt_1","type":"invoice.paid"}`))
+	assert.Equal(t, "invoice.paid", ev)
+	assert.Equal(t, "evt_1", id)
+
+	ev, id = Identify(ProviderGitHub, http.Header{
+		"X-Github-Event":    {"pull_request"},
+		"X-Github-Delivery": {"d-1"},
+	}, []byte(`{"action":"opened"}`))
+	assert.Equal(t, "pull_request.opened", ev)
+	assert.Equal(t, "d-1", id)
+
+	ev, id = Identify(ProviderShopify, http.Header{
+		"X-Shopify-Topic":      {"orders/create"},
+		"X-Shopify-Webhook-Id": {"w-1"},
+	}, []byte(`{}`))
+	assert.Equal(t, "orders/create", ev)
+	assert.Equal(t, "w-1", id)
+
+	// No provider id: the body hash stands in, stable across retries.
+	_, a := Identify(ProviderGitHub, http.Header{}, []byte(`{"x":1}`))
+	_, b := Identify(ProviderGitHub, http.Header{}, []byte(`{"x":1}`))
+	assert.Equal(t, a, b)
+	assert.Contains(t, a, "sha256:")
+}
@@ -164,6 +164,20 @@ func (r *Repository) FailureCountByIdentifierSince(ctx context.Context, identifi
 	return count, nil
 }
 
+// FailureCountByIPSince counts FAILURE attempts from one IP (across all
+// identifiers) since the cutoff. Drives the per-IP ceiling.
+func (r *Repository) FailureCountByIPSince(ctx context.Context, ip string, since time.Time) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM iam_login_attempts
+		   WHERE outcome = 'FAILURE' AND ip_address = $1 AND attempted_at >= $2`,
+		ip, since).Scan(&count)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failure_count_by_ip_since: %w", err)
+	}
+	return count, nil
+}
+
 // ListParams filters a cursor-paginated query. AfterTime+AfterID together
 // form the keyset cursor (exclusive) for the next page.
 type ListParams struct {
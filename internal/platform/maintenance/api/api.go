@@ -0,0 +1,76 @@
+// Package api wires the maintenance-mode admin endpoints via huma.
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// State bundles deps.
+type State struct {
+	Repo *maintenance.Repository
+	// Mode is this instance's view; a toggle is applied to it directly so
+	// the instance handling the request doesn't wait for its next poll.
+	Mode *maintenance.Mode
+	UoW  *usecasepgx.UnitOfWork
+}
+
+const tag = "maintenance"
+
+// Register mounts the maintenance endpoints. Anchor-only. The path is
+// exempt from the mode itself (maintenance.AdminPath).
+func Register(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Get(g, "getMaintenanceMode", maintenance.AdminPath, "Get the platform maintenance mode", s.get)
+	apiroute.Put(g, "setMaintenanceMode", maintenance.AdminPath, "Enable or disable the platform maintenance mode", http.StatusOK, s.set)
+}
+
+func (s *State) get(ctx context.Context, _ *apicommon.Empty) (*apicommon.Out[MaintenanceResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return &apicommon.Out[MaintenanceResponse]{Body: responseFrom(s.Mode.Status())}, nil
+}
+
+// set stores the new setting and applies it here at once. Enabling then
+// waits for this instance's in-flight writes to finish (up to the drain
+// timeout); other instances stop taking writes within one poll interval.
+func (s *State) set(ctx context.Context, in *apicommon.In[SetMaintenanceRequest]) (*apicommon.Out[MaintenanceResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.SetMaintenanceMode(s.Repo), in.Body.toCommand(), ec); err != nil {
+		return nil, err
+	}
+	if err := s.Mode.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	var drained *bool
+	if in.Body.Enabled {
+		timeout := s.Mode.Config().DrainTimeout
+		if d := in.Body.DrainTimeoutSeconds; d != nil {
+			timeout = time.Duration(*d) * time.Second
+		}
+		dctx, cancel := context.WithTimeout(ctx, timeout)
+		ok := s.Mode.Drain(dctx)
+		cancel()
+		drained = &ok
+	}
+	resp := responseFrom(s.Mode.Status())
+	resp.Drained = drained
+	return &apicommon.Out[MaintenanceResponse]{Body: resp}, nil
+}
@@ -0,0 +1,64 @@
+// dto.go contains the wire-format types for the maintenance API.
+package api
+
+import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance/operations"
+)
+
+// SetMaintenanceRequest is the body of PUT /api/admin/platform/maintenance.
+type SetMaintenanceRequest struct {
+	Enabled bool `json:"enabled"`
+	// Message replaces the default text of the 503 body.
+	Message           *string `json:"message,omitempty" maxLength:"500"`
+	RetryAfterSeconds *int32  `json:"retryAfterSeconds,omitempty" minimum:"1" maximum:"86400" doc:"Retry-After advertised on refused writes; defaults to FC_MAINTENANCE_RETRY_AFTER_SECS"`
+	// DrainTimeoutSeconds bounds how long enabling waits for this
+	// instance's in-flight writes before answering.
+	DrainTimeoutSeconds *int32 `json:"drainTimeoutSeconds,omitempty" minimum:"0" maximum:"120" doc:"How long enabling waits for in-flight writes to finish (default 30)"`
+}
+
+func (r SetMaintenanceRequest) toCommand() operations.SetMaintenanceModeCommand {
+	return operations.SetMaintenanceModeCommand{
+		Enabled:           r.Enabled,
+		Message:           r.Message,
+		RetryAfterSeconds: r.RetryAfterSeconds,
+	}
+}
+
+// MaintenanceResponse is the wire shape for the maintenance endpoints.
+type MaintenanceResponse struct {
+	// Enabled is the effective state: forced by configuration or set
+	// through this API.
+	Enabled bool `json:"enabled"`
+	// Forced reports FC_MAINTENANCE_MODE; while true, disabling through
+	// the API has no effect.
+	Forced            bool       `json:"forced"`
+	Message           *string    `json:"message,omitempty"`
+	RetryAfterSeconds int64      `json:"retryAfterSeconds"`
+	UpdatedBy         *string    `json:"updatedBy,omitempty"`
+	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
+	// InFlightWrites counts writes still being served on the instance
+	// that answered.
+	InFlightWrites int64 `json:"inFlightWrites"`
+	// Drained is set on enable: whether in-flight writes finished within
+	// the drain timeout.
+	Drained *bool `json:"drained,omitempty"`
+}
+
+func responseFrom(st maintenance.Status) MaintenanceResponse {
+	resp := MaintenanceResponse{
+		Enabled:           st.Enabled,
+		Forced:            st.Forced,
+		Message:           st.Setting.Message,
+		RetryAfterSeconds: int64(st.RetryAfter / time.Second),
+		UpdatedBy:         st.Setting.UpdatedBy,
+		InFlightWrites:    st.InFlightWrites,
+	}
+	if !st.Setting.UpdatedAt.IsZero() {
+		at := st.Setting.UpdatedAt
+		resp.UpdatedAt = &at
+	}
+	return resp
+}
@@ -0,0 +1,134 @@
+// Package maintenance is the platform's maintenance mode: while it is on,
+// the platform API refuses writes with 503 + Retry-After so the database
+// behind it can be upgraded or migrated, while reads, health checks and
+// the router carry on.
+//
+// The mode has two sources. The stored Setting (app_maintenance_mode) is
+// toggled through the admin API and goes through the UoW (see
+// operations.SetMaintenanceMode); every instance polls it, so a toggle
+// through one reaches the rest within PollInterval. FC_MAINTENANCE_MODE
+// forces the mode on from configuration — it survives the database being
+// unreachable and follows a config reload.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// Config holds the maintenance knobs (env-overridable).
+type Config struct {
+	// Forced turns the mode on regardless of the stored setting.
+	Forced bool
+	// RetryAfter is the Retry-After advertised when the setting doesn't
+	// carry its own.
+	RetryAfter time.Duration
+	// PollInterval is how often the stored setting is re-read.
+	PollInterval time.Duration
+	// DrainTimeout caps how long enabling the mode waits for in-flight
+	// writes on the instance handling the toggle.
+	DrainTimeout time.Duration
+}
+
+// ConfigFromEnv reads the FC_MAINTENANCE_* knobs.
+func ConfigFromEnv() Config {
+	retry := envutil.Int("FC_MAINTENANCE_RETRY_AFTER_SECS", 60)
+	if retry <= 0 {
+		retry = 60
+	}
+	return Config{
+		Forced:       envutil.Bool("FC_MAINTENANCE_MODE", false),
+		RetryAfter:   time.Duration(retry) * time.Second,
+		PollInterval: 5 * time.Second,
+		DrainTimeout: 30 * time.Second,
+	}
+}
+
+// Setting is the stored, API-toggled maintenance state. There is one.
+type Setting struct {
+	Enabled bool    `json:"enabled"`
+	Message *string `json:"message,omitempty"`
+	// RetryAfterSeconds overrides the configured Retry-After; nil keeps it.
+	RetryAfterSeconds *int32    `json:"retryAfterSeconds,omitempty"`
+	UpdatedBy         *string   `json:"updatedBy,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// settingID is the single row's key.
+const settingID = "maintenance"
+
+// IDStr satisfies usecase.HasID.
+func (Setting) IDStr() string { return settingID }
+
+// Set replaces the setting's state.
+func (s *Setting) Set(enabled bool, message *string, retryAfterSeconds *int32, by string) {
+	s.Enabled = enabled
+	s.Message = message
+	s.RetryAfterSeconds = retryAfterSeconds
+	s.UpdatedBy = &by
+	s.UpdatedAt = time.Now().UTC()
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = s.UpdatedAt
+	}
+}
+
+// Repository is the Postgres-backed store for the setting.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRepository wires a repository against an existing pgx pool.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Find loads the setting. A database that has never had one reports a
+// zero (disabled) Setting.
+func (r *Repository) Find(ctx context.Context) (*Setting, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT enabled, message, retry_after_seconds, updated_by, created_at, updated_at
+		   FROM app_maintenance_mode WHERE id = 1`)
+	if err != nil {
+		return nil, fmt.Errorf("find_maintenance: %w", err)
+	}
+	defer rows.Close()
+	var s Setting
+	if !rows.Next() {
+		return &s, rows.Err()
+	}
+	if err := rows.Scan(&s.Enabled, &s.Message, &s.RetryAfterSeconds, &s.UpdatedBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("find_maintenance: %w", err)
+	}
+	return &s, nil
+}
+
+// Persist implements usecasepgx.Persist[Setting].
+func (r *Repository) Persist(ctx context.Context, s *Setting, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx,
+		`INSERT INTO app_maintenance_mode (id, enabled, message, retry_after_seconds, updated_by, created_at, updated_at)
+		 VALUES (1, $1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (id) DO UPDATE
+		    SET enabled             = EXCLUDED.enabled,
+		        message             = EXCLUDED.message,
+		        retry_after_seconds = EXCLUDED.retry_after_seconds,
+		        updated_by          = EXCLUDED.updated_by,
+		        updated_at          = EXCLUDED.updated_at`,
+		s.Enabled, s.Message, s.RetryAfterSeconds, s.UpdatedBy, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("persist_maintenance: %w", err)
+	}
+	return nil
+}
+
+// Delete implements usecasepgx.Persist[Setting].
+func (r *Repository) Delete(ctx context.Context, _ *Setting, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx, `DELETE FROM app_maintenance_mode WHERE id = 1`)
+	return err
+}
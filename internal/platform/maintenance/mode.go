@@ -0,0 +1,246 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+)
+
+// AdminPath is the toggle endpoint. Always exempt, or the mode could
+// never be turned off again.
+const AdminPath = "/api/admin/platform/maintenance"
+
+// exemptPaths are writes that keep working during maintenance: signing
+// in (so an operator can reach the toggle) and the router's delivery
+// callback, so in-flight dispatches still complete.
+var exemptPaths = map[string]bool{
+	AdminPath:            true,
+	"/auth/login":        true,
+	"/auth/logout":       true,
+	"/auth/refresh":      true,
+	"/auth/check-domain": true,
+	"/auth/2fa/verify":   true,
+}
+
+var exemptPrefixes = []string{
+	"/auth/2fa/challenge/",
+	"/auth/webauthn/authenticate/",
+	"/auth/oidc/",
+	"/oauth/",
+	"/api/dispatch/",
+}
+
+// Exempt reports whether a write to path is served during maintenance.
+func Exempt(path string) bool {
+	if exemptPaths[path] {
+		return true
+	}
+	for _, p := range exemptPrefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// settingStore is what Mode polls. *Repository satisfies it; tests
+// substitute a fake.
+type settingStore interface {
+	Find(ctx context.Context) (*Setting, error)
+}
+
+// Mode is the per-instance view of the maintenance state plus the
+// middleware that enforces it. Construct with NewMode and run Run in its
+// own goroutine. Safe for concurrent use.
+//
+// The stored setting is polled, so on instances other than the one that
+// handled a toggle the mode takes effect within Config.PollInterval. A
+// failed poll keeps the last known setting: an unreachable database —
+// the usual reason for maintenance — must not flip the mode off.
+type Mode struct {
+	store settingStore
+
+	mu      sync.RWMutex
+	cfg     Config
+	setting Setting
+
+	// inFlight counts writes currently being served.
+	inFlight atomic.Int64
+}
+
+// Status is a point-in-time view of the mode.
+type Status struct {
+	// Enabled is the effective state: Forced or the stored setting.
+	Enabled bool
+	// Forced is FC_MAINTENANCE_MODE.
+	Forced         bool
+	Setting        Setting
+	RetryAfter     time.Duration
+	InFlightWrites int64
+}
+
+// NewMode wires a Mode against repo.
+func NewMode(cfg Config, repo *Repository) *Mode {
+	return newMode(cfg, repo)
+}
+
+func newMode(cfg Config, s settingStore) *Mode {
+	return &Mode{store: s, cfg: cfg}
+}
+
+// SetConfig swaps the configuration (config reload).
+func (m *Mode) SetConfig(cfg Config) {
+	m.mu.Lock()
+	old := m.cfg.Forced
+	m.cfg = cfg
+	m.mu.Unlock()
+	if old != cfg.Forced {
+		slog.Warn("maintenance mode forced by configuration changed", "forced", cfg.Forced)
+	}
+}
+
+// Config returns the current configuration.
+func (m *Mode) Config() Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
+// Apply installs s as the current setting without waiting for the next
+// poll (the instance that handled the toggle).
+func (m *Mode) Apply(s Setting) {
+	m.mu.Lock()
+	changed := m.setting.Enabled != s.Enabled
+	m.setting = s
+	m.mu.Unlock()
+	if changed {
+		slog.Warn("maintenance mode changed", "enabled", s.Enabled)
+	}
+}
+
+// Status reports the current state.
+func (m *Mode) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return Status{
+		Enabled:        m.cfg.Forced || m.setting.Enabled,
+		Forced:         m.cfg.Forced,
+		Setting:        m.setting,
+		RetryAfter:     m.retryAfter(),
+		InFlightWrites: m.inFlight.Load(),
+	}
+}
+
+// Enabled reports whether writes are currently refused.
+func (m *Mode) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg.Forced || m.setting.Enabled
+}
+
+// retryAfter is the advertised Retry-After. Caller holds mu.
+func (m *Mode) retryAfter() time.Duration {
+	if s := m.setting.RetryAfterSeconds; s != nil && *s > 0 {
+		return time.Duration(*s) * time.Second
+	}
+	return m.cfg.RetryAfter
+}
+
+// Refresh re-reads the stored setting. On error the last known setting
+// stays in force.
+func (m *Mode) Refresh(ctx context.Context) error {
+	s, err := m.store.Find(ctx)
+	if err != nil {
+		return err
+	}
+	m.Apply(*s)
+	return nil
+}
+
+// Run polls the stored setting until ctx is cancelled.
+func (m *Mode) Run(ctx context.Context) {
+	if err := m.Refresh(ctx); err != nil {
+		slog.Warn("maintenance: initial load failed", "err", err)
+	}
+	t := time.NewTicker(m.Config().PollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("maintenance: refresh failed; keeping the last known state", "err", err)
+			}
+		}
+	}
+}
+
+// Drain waits until no writes are in flight or ctx is done, and reports
+// whether it got there. Only writes on this instance are seen.
+func (m *Mode) Drain(ctx context.Context) bool {
+	t := time.NewTicker(50 * time.Millisecond)
+	defer t.Stop()
+	for m.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-t.C:
+		}
+	}
+	return true
+}
+
+// Middleware refuses writes with 503 + Retry-After while the mode is on.
+// Reads (GET/HEAD/OPTIONS) and the Exempt paths are always served.
+func (m *Mode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if safeMethod(r.Method) || Exempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Counted before the check so a Drain that starts after the mode
+		// flips on can't miss a write that has already been let through.
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+		if m.Enabled() {
+			st := m.Status()
+			msg := "The platform is in maintenance; writes are temporarily unavailable"
+			if st.Setting.Message != nil && *st.Setting.Message != "" {
+				msg = *st.Setting.Message
+			}
+			writeUnavailable(w, st.RetryAfter, msg)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// writeUnavailable writes the 503 envelope ({"error","message"}) with
+// Retry-After in whole seconds.
+func writeUnavailable(w http.ResponseWriter, retryAfter time.Duration, message string) {
+	secs := int64(retryAfter / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(httperror.Envelope{Code: "MAINTENANCE", Message: message})
+}
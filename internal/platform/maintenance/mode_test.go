@@ -0,0 +1,119 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	setting Setting
+	err     error
+}
+
+func (f *fakeStore) Find(context.Context) (*Setting, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	s := f.setting
+	return &s, nil
+}
+
+func testMode(s *fakeStore) *Mode {
+	return newMode(Config{RetryAfter: 60 * time.Second, PollInterval: time.Second, DrainTimeout: time.Second}, s)
+}
+
+func serve(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMiddleware_RefusesWritesWhileEnabled(t *testing.T) {
+	s := &fakeStore{}
+	m := testMode(s)
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+
+	assert.Equal(t, http.StatusNoContent, serve(h, http.MethodPost, "/api/events").Code, "off: writes pass")
+
+	msg := "Database upgrade until 14:00 UTC"
+	s.setting = Setting{Enabled: true, Message: &msg}
+	require.NoError(t, m.Refresh(context.Background()))
+
+	w := serve(h, http.MethodPost, "/api/events")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"MAINTENANCE","message":"Database upgrade until 14:00 UTC"}`, w.Body.String())
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		assert.Equal(t, http.StatusNoContent, serve(h, method, "/api/events").Code, "%s passes", method)
+	}
+	for _, path := range []string{AdminPath, "/auth/login", "/auth/2fa/challenge/email", "/oauth/token", "/api/dispatch/process"} {
+		assert.Equal(t, http.StatusNoContent, serve(h, http.MethodPost, path).Code, "%s is exempt", path)
+	}
+	for _, path := range []string{"/auth/change-password", "/api/subscriptions", "/ingest/stripe"} {
+		assert.Equal(t, http.StatusServiceUnavailable, serve(h, http.MethodPut, path).Code, "%s is refused", path)
+	}
+	assert.Zero(t, m.Status().InFlightWrites)
+}
+
+func TestMode_RetryAfterOverrideAndForced(t *testing.T) {
+	s := &fakeStore{}
+	m := testMode(s)
+	assert.False(t, m.Enabled())
+
+	m.SetConfig(Config{Forced: true, RetryAfter: 60 * time.Second})
+	st := m.Status()
+	assert.True(t, st.Enabled)
+	assert.True(t, st.Forced)
+
+	secs := int32(300)
+	s.setting = Setting{Enabled: false, RetryAfterSeconds: &secs}
+	require.NoError(t, m.Refresh(context.Background()))
+	assert.True(t, m.Enabled(), "forced wins over a disabled setting")
+	assert.Equal(t, 300*time.Second, m.Status().RetryAfter)
+}
+
+// A failed poll keeps the last known setting — an unreachable database
+// must not switch maintenance off.
+func TestMode_RefreshFailureKeepsState(t *testing.T) {
+	s := &fakeStore{setting: Setting{Enabled: true}}
+	m := testMode(s)
+	require.NoError(t, m.Refresh(context.Background()))
+
+	s.err = errors.New("connection refused")
+	assert.Error(t, m.Refresh(context.Background()))
+	assert.True(t, m.Enabled())
+}
+
+func TestMode_DrainWaitsForInFlightWrites(t *testing.T) {
+	m := testMode(&fakeStore{})
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	done := make(chan int)
+	go func() { done <- serve(h, http.MethodPost, "/api/events").Code }()
+	<-started
+
+	m.Apply(Setting{Enabled: true})
+	assert.Equal(t, int64(1), m.Status().InFlightWrites)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	assert.False(t, m.Drain(ctx), "times out while a write is in flight")
+	cancel()
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done, "the in-flight write completes")
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, m.Drain(ctx))
+}
@@ -0,0 +1,52 @@
+package operations
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+const (
+	MaintenanceEnabledType  = "platform:admin:maintenance:enabled"
+	MaintenanceDisabledType = "platform:admin:maintenance:disabled"
+	Source                  = "platform:admin"
+
+	subject = "platform.maintenance"
+	group   = "platform:maintenance"
+)
+
+func typeFor(enabled bool) string {
+	if enabled {
+		return MaintenanceEnabledType
+	}
+	return MaintenanceDisabledType
+}
+
+// MaintenanceChanged is emitted by [SetMaintenanceMode]; its type is
+// MaintenanceEnabledType or MaintenanceDisabledType.
+type MaintenanceChanged struct {
+	Metadata          usecase.EventMetadata
+	Enabled           bool
+	Message           *string
+	RetryAfterSeconds *int32
+}
+
+func (e MaintenanceChanged) EventID() string       { return e.Metadata.EventID }
+func (e MaintenanceChanged) EventType() string     { return typeFor(e.Enabled) }
+func (e MaintenanceChanged) SpecVersion() string   { return "1.0" }
+func (e MaintenanceChanged) Source() string        { return Source }
+func (e MaintenanceChanged) Subject() string       { return subject }
+func (e MaintenanceChanged) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e MaintenanceChanged) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e MaintenanceChanged) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e MaintenanceChanged) CausationID() string   { return e.Metadata.CausationID }
+func (e MaintenanceChanged) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e MaintenanceChanged) MessageGroup() string  { return group }
+func (e MaintenanceChanged) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Enabled           bool    `json:"enabled"`
+		Message           *string `json:"message"`
+		RetryAfterSeconds *int32  `json:"retryAfterSeconds"`
+	}{e.Enabled, e.Message, e.RetryAfterSeconds})
+}
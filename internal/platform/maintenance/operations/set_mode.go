@@ -0,0 +1,58 @@
+// Package operations holds the maintenance-mode use cases.
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// MaxRetryAfterSeconds caps the advertised Retry-After (one day).
+const MaxRetryAfterSeconds = 86400
+
+// SetMaintenanceModeCommand is the input DTO. A nil RetryAfterSeconds
+// falls back to FC_MAINTENANCE_RETRY_AFTER_SECS.
+type SetMaintenanceModeCommand struct {
+	Enabled           bool    `json:"enabled"`
+	Message           *string `json:"message"`
+	RetryAfterSeconds *int32  `json:"retryAfterSeconds"`
+}
+
+// SetMaintenanceMode replaces the stored maintenance setting and emits
+// [MaintenanceChanged].
+func SetMaintenanceMode(repo *maintenance.Repository) usecaseop.Operation[SetMaintenanceModeCommand, MaintenanceChanged] {
+	return usecaseop.Operation[SetMaintenanceModeCommand, MaintenanceChanged]{
+		Name: "SetMaintenanceMode",
+		Validate: func(_ context.Context, cmd SetMaintenanceModeCommand) error {
+			if r := cmd.RetryAfterSeconds; r != nil && (*r < 1 || *r > MaxRetryAfterSeconds) {
+				return usecase.Validation("INVALID_RETRY_AFTER", "retryAfterSeconds must be between 1 and 86400")
+			}
+			if cmd.Message != nil && len(*cmd.Message) > 500 {
+				return usecase.Validation("MESSAGE_TOO_LONG", "message must be at most 500 characters")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[SetMaintenanceModeCommand],
+		Execute: func(ctx context.Context, cmd SetMaintenanceModeCommand, ec usecase.ExecutionContext) (usecaseop.Plan[MaintenanceChanged], error) {
+			s, err := repo.Find(ctx)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_maintenance failed", err)
+			}
+			msg := cmd.Message
+			if msg != nil && strings.TrimSpace(*msg) == "" {
+				msg = nil
+			}
+			s.Set(cmd.Enabled, msg, cmd.RetryAfterSeconds, ec.PrincipalID)
+			event := MaintenanceChanged{
+				Metadata:          usecase.NewEventMetadata(ec, typeFor(s.Enabled), Source, subject),
+				Enabled:           s.Enabled,
+				Message:           s.Message,
+				RetryAfterSeconds: s.RetryAfterSeconds,
+			}
+			return usecaseop.Save(s, repo, event), nil
+		},
+	}
+}
@@ -0,0 +1,119 @@
+// Package api wires the per-client usage and quota endpoints via huma.
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// State bundles deps.
+type State struct {
+	Repo    *metering.Repository
+	Clients *client.Repository
+	// Meter supplies the limits config and has its cache dropped after a
+	// quota change so the new limit applies immediately on this instance.
+	Meter *metering.Meter
+	UoW   *usecasepgx.UnitOfWork
+}
+
+const tag = "clients"
+
+// Register mounts the metering endpoints under the client resource.
+// Anchor-only, like the rest of /api/clients.
+func Register(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Get(g, "getClientUsage", "/api/clients/{id}/usage", "Get a client's metered usage for a month", s.usage)
+	apiroute.Put(g, "setClientQuota", "/api/clients/{id}/quota", "Set a client's monthly quota overrides", http.StatusOK, s.setQuota)
+}
+
+type usageInput struct {
+	ID    string `path:"id"`
+	Month string `query:"month" doc:"YYYY-MM (UTC); defaults to the current month"`
+}
+
+// usage reports the month's totals against the effective limits plus the
+// per-day breakdown. Totals come from the stored daily rows, so they trail
+// live traffic by up to one flush interval.
+func (s *State) usage(ctx context.Context, in *usageInput) (*apicommon.Out[ClientUsageResponse], error) {
+	if err := auth.CanReadClients(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	month := metering.MonthStart(time.Now())
+	if in.Month != "" {
+		t, err := time.Parse("2006-01", in.Month)
+		if err != nil {
+			return nil, httperror.BadRequest("INVALID_MONTH", "month must be YYYY-MM")
+		}
+		month = t
+	}
+	c, err := s.Clients.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_client failed", err)
+	}
+	if c == nil {
+		return nil, httperror.NotFound("Client", in.ID)
+	}
+	days, err := s.Repo.Daily(ctx, c.ID, month)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "daily usage failed", err)
+	}
+	q, err := s.Repo.FindQuota(ctx, c.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_quota failed", err)
+	}
+
+	cfg := s.Meter.Config()
+	eventLimit, deliveryLimit := cfg.Limits(q)
+	var events, deliveries int64
+	daily := make([]DailyUsageResponse, 0, len(days))
+	for _, d := range days {
+		events += d.Events
+		deliveries += d.Deliveries
+		daily = append(daily, DailyUsageResponse{Date: d.Day.Format(time.DateOnly), Events: d.Events, Deliveries: d.Deliveries})
+	}
+	return &apicommon.Out[ClientUsageResponse]{Body: ClientUsageResponse{
+		ClientID:    c.ID,
+		Month:       month.Format("2006-01"),
+		Events:      UsageMeterResponse{Used: events, Limit: eventLimit, State: string(cfg.StateOf(events, eventLimit))},
+		Deliveries:  UsageMeterResponse{Used: deliveries, Limit: deliveryLimit, State: string(cfg.StateOf(deliveries, deliveryLimit))},
+		WarnPercent: cfg.WarnPercent,
+		Quota:       quotaFromEntity(q),
+		Daily:       daily,
+	}}, nil
+}
+
+type setQuotaInput struct {
+	ID   string `path:"id"`
+	Body SetQuotaRequest
+}
+
+func (s *State) setQuota(ctx context.Context, in *setQuotaInput) (*apicommon.Out[QuotaResponse], error) {
+	if err := auth.CanUpdateClients(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.SetQuota(s.Repo, s.Clients), in.Body.toCommand(in.ID), ec)
+	if err != nil {
+		return nil, err
+	}
+	s.Meter.Invalidate(event.ClientID)
+	return &apicommon.Out[QuotaResponse]{Body: QuotaResponse{
+		MonthlyEvents:     event.MonthlyEvents,
+		MonthlyDeliveries: event.MonthlyDeliveries,
+	}}, nil
+}
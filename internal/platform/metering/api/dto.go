@@ -0,0 +1,61 @@
+// dto.go contains the wire-format types for the metering API.
+package api
+
+import (
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering/operations"
+)
+
+// UsageMeterResponse is one metered kind's month-to-date position.
+type UsageMeterResponse struct {
+	Used int64 `json:"used"`
+	// Limit is the effective monthly limit; 0 = unlimited.
+	Limit int64  `json:"limit"`
+	State string `json:"state" enum:"OK,WARNING,EXCEEDED"`
+}
+
+// DailyUsageResponse is one UTC day's counts.
+type DailyUsageResponse struct {
+	Date       string `json:"date" doc:"YYYY-MM-DD (UTC)"`
+	Events     int64  `json:"events"`
+	Deliveries int64  `json:"deliveries"`
+}
+
+// QuotaResponse is the client's override; a null limit means the
+// platform default applies.
+type QuotaResponse struct {
+	MonthlyEvents     *int64 `json:"monthlyEvents"`
+	MonthlyDeliveries *int64 `json:"monthlyDeliveries"`
+}
+
+func quotaFromEntity(q *metering.Quota) QuotaResponse {
+	if q == nil {
+		return QuotaResponse{}
+	}
+	return QuotaResponse{MonthlyEvents: q.MonthlyEvents, MonthlyDeliveries: q.MonthlyDeliveries}
+}
+
+// ClientUsageResponse is the wire shape for GET /api/clients/{id}/usage.
+type ClientUsageResponse struct {
+	ClientID    string               `json:"clientId"`
+	Month       string               `json:"month" doc:"YYYY-MM (UTC)"`
+	Events      UsageMeterResponse   `json:"events"`
+	Deliveries  UsageMeterResponse   `json:"deliveries"`
+	WarnPercent int                  `json:"warnPercent"`
+	Quota       QuotaResponse        `json:"quota"`
+	Daily       []DailyUsageResponse `json:"daily"`
+}
+
+// SetQuotaRequest is the wire body for PUT /api/clients/{id}/quota.
+type SetQuotaRequest struct {
+	MonthlyEvents     *int64 `json:"monthlyEvents,omitempty" doc:"Monthly event limit; null = platform default, 0 = unlimited"`
+	MonthlyDeliveries *int64 `json:"monthlyDeliveries,omitempty" doc:"Monthly delivery limit; null = platform default, 0 = unlimited"`
+}
+
+func (r SetQuotaRequest) toCommand(clientID string) operations.SetQuotaCommand {
+	return operations.SetQuotaCommand{
+		ClientID:          clientID,
+		MonthlyEvents:     r.MonthlyEvents,
+		MonthlyDeliveries: r.MonthlyDeliveries,
+	}
+}
@@ -0,0 +1,63 @@
+package metering
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	eventsDesc = prometheus.NewDesc("fc_client_events_ingested_month",
+		"Events ingested by the client in the current UTC month.", []string{"client_id"}, nil)
+	deliveriesDesc = prometheus.NewDesc("fc_client_deliveries_month",
+		"Successful webhook deliveries for the client in the current UTC month.", []string{"client_id"}, nil)
+	limitDesc = prometheus.NewDesc("fc_client_quota_limit",
+		"The client's effective monthly limit (absent when unlimited).", []string{"client_id", "kind"}, nil)
+	ratioDesc = prometheus.NewDesc("fc_client_quota_usage_ratio",
+		"Month-to-date usage as a fraction of the monthly limit (absent when unlimited).", []string{"client_id", "kind"}, nil)
+)
+
+// Collector exports month-to-date usage per client as Prometheus gauges.
+// Every scrape reads a fresh snapshot from the stored daily rows, so the
+// series agree across instances (up to each one's unflushed counts).
+type Collector struct {
+	cfg  Config
+	repo *Repository
+	now  func() time.Time
+}
+
+// NewCollector wires a collector over repo.
+func NewCollector(cfg Config, repo *Repository) *Collector {
+	return &Collector{cfg: cfg, repo: repo, now: time.Now}
+}
+
+// Describe is a no-op (unchecked const-metric collector).
+func (c *Collector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits one set of gauges per client with traffic this month.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := c.repo.Summaries(ctx, MonthStart(c.now()))
+	if err != nil {
+		slog.Warn("metering: collect failed", "err", err)
+		return
+	}
+	for _, s := range rows {
+		ch <- prometheus.MustNewConstMetric(eventsDesc, prometheus.GaugeValue, float64(s.Events), s.ClientID)
+		ch <- prometheus.MustNewConstMetric(deliveriesDesc, prometheus.GaugeValue, float64(s.Deliveries), s.ClientID)
+		events, deliveries := c.cfg.Limits(s.Quota)
+		quota(ch, s.ClientID, KindEvents, s.Events, events)
+		quota(ch, s.ClientID, KindDeliveries, s.Deliveries, deliveries)
+	}
+}
+
+func quota(ch chan<- prometheus.Metric, clientID string, kind Kind, used, limit int64) {
+	if limit <= 0 {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(limitDesc, prometheus.GaugeValue, float64(limit), clientID, string(kind))
+	ch <- prometheus.MustNewConstMetric(ratioDesc, prometheus.GaugeValue, float64(used)/float64(limit), clientID, string(kind))
+}
@@ -0,0 +1,277 @@
+package metering
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// store is what the Meter reads and flushes into. *Repository satisfies
+// it; tests substitute an in-memory fake.
+type store interface {
+	AddUsage(ctx context.Context, rows []Usage) error
+	MonthTotals(ctx context.Context, clientID string, month time.Time) (Usage, error)
+	FindQuota(ctx context.Context, clientID string) (*Quota, error)
+}
+
+// Meter counts traffic off the request path and enforces quotas against
+// cached month-to-date totals. Construct with NewMeter and run Run in its
+// own goroutine. Safe for concurrent use.
+//
+// Enforcement is approximate by design: totals are this instance's own
+// counts on top of the stored ones as of the last cache load, so traffic
+// through other instances counts here within CacheTTL. A client can
+// overshoot its limit by roughly that window's worth of traffic.
+type Meter struct {
+	cfg   Config
+	store store
+
+	mu sync.Mutex
+	// pending is counted but not yet flushed, keyed by (client, day).
+	pending map[dayKey]*Usage
+	// months caches each client's month-to-date totals and limits.
+	months *lru.LRU[string, *month]
+	// warned records the month a (client, kind, state) crossing was last
+	// logged, so each threshold is logged once per month.
+	warned map[warnKey]time.Time
+	now    func() time.Time
+}
+
+type dayKey struct {
+	clientID string
+	day      time.Time
+}
+
+type warnKey struct {
+	clientID string
+	kind     Kind
+	state    State
+}
+
+// month is a client's cached position in the current month.
+type month struct {
+	start         time.Time
+	events        int64
+	deliveries    int64
+	eventLimit    int64
+	deliveryLimit int64
+}
+
+// NewMeter wires a Meter against repo.
+func NewMeter(cfg Config, repo *Repository) *Meter {
+	return newMeter(cfg, repo)
+}
+
+func newMeter(cfg Config, s store) *Meter {
+	size := cfg.CacheSize
+	if size <= 0 {
+		size = 10000
+	}
+	return &Meter{
+		cfg:     cfg,
+		store:   s,
+		pending: map[dayKey]*Usage{},
+		months:  lru.NewLRU[string, *month](size, nil, cfg.CacheTTL),
+		warned:  map[warnKey]time.Time{},
+		now:     time.Now,
+	}
+}
+
+// Config returns the meter's configuration.
+func (m *Meter) Config() Config { return m.cfg }
+
+// RecordEvents counts n events ingested for clientID.
+func (m *Meter) RecordEvents(clientID string, n int) { m.record(clientID, KindEvents, int64(n)) }
+
+// RecordDelivery counts one successful webhook delivery for clientID.
+func (m *Meter) RecordDelivery(clientID string) { m.record(clientID, KindDeliveries, 1) }
+
+// CheckEvents reports ErrQuotaExceeded when accepting n more events would
+// take clientID past its monthly limit. Fails open: a lookup error is
+// logged and the events are allowed — metering must not take ingest down.
+func (m *Meter) CheckEvents(ctx context.Context, clientID string, n int) error {
+	if !m.cfg.Enabled || clientID == "" {
+		return nil
+	}
+	c, err := m.load(ctx, clientID)
+	if err != nil {
+		slog.Warn("metering: quota lookup failed; allowing ingest", "client_id", clientID, "err", err)
+		return nil
+	}
+	if c.eventLimit > 0 && c.events+int64(n) > c.eventLimit {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// AllowDelivery reports whether clientID is still within its monthly
+// delivery limit. Fails open like CheckEvents.
+func (m *Meter) AllowDelivery(ctx context.Context, clientID string) bool {
+	if !m.cfg.Enabled || clientID == "" {
+		return true
+	}
+	c, err := m.load(ctx, clientID)
+	if err != nil {
+		slog.Warn("metering: quota lookup failed; allowing delivery", "client_id", clientID, "err", err)
+		return true
+	}
+	return c.deliveryLimit <= 0 || c.deliveries < c.deliveryLimit
+}
+
+// Invalidate drops clientID's cached totals and limits so the next check
+// reloads them. Called after a quota change.
+func (m *Meter) Invalidate(clientID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.months.Remove(clientID)
+}
+
+// Run flushes buffered counts every FlushInterval until ctx is cancelled,
+// then flushes once more.
+func (m *Meter) Run(ctx context.Context) {
+	if !m.cfg.Enabled {
+		return
+	}
+	t := time.NewTicker(m.cfg.FlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := m.Flush(fctx); err != nil {
+				slog.Warn("metering: final flush failed", "err", err)
+			}
+			cancel()
+			return
+		case <-t.C:
+			if err := m.Flush(ctx); err != nil {
+				slog.Warn("metering: flush failed", "err", err)
+			}
+		}
+	}
+}
+
+// Flush writes buffered counts. On failure they are kept and retried on
+// the next flush, so a database blip loses nothing.
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	if len(m.pending) == 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	batch := m.pending
+	m.pending = map[dayKey]*Usage{}
+	m.mu.Unlock()
+
+	rows := make([]Usage, 0, len(batch))
+	for _, u := range batch {
+		rows = append(rows, *u)
+	}
+	err := m.store.AddUsage(ctx, rows)
+	if err == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, u := range batch {
+		m.addPending(k, u.Events, u.Deliveries)
+	}
+	return err
+}
+
+func (m *Meter) record(clientID string, kind Kind, n int64) {
+	if !m.cfg.Enabled || clientID == "" || n <= 0 {
+		return
+	}
+	now := m.now()
+	var events, deliveries int64
+	if kind == KindEvents {
+		events = n
+	} else {
+		deliveries = n
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addPending(dayKey{clientID: clientID, day: DayStart(now)}, events, deliveries)
+	c, ok := m.months.Peek(clientID)
+	if !ok || !c.start.Equal(MonthStart(now)) {
+		return
+	}
+	c.events += events
+	c.deliveries += deliveries
+	if kind == KindEvents {
+		m.warn(clientID, kind, c.start, c.events, c.eventLimit)
+	} else {
+		m.warn(clientID, kind, c.start, c.deliveries, c.deliveryLimit)
+	}
+}
+
+// addPending must be called with mu held.
+func (m *Meter) addPending(k dayKey, events, deliveries int64) {
+	u, ok := m.pending[k]
+	if !ok {
+		u = &Usage{ClientID: k.clientID, Day: k.day}
+		m.pending[k] = u
+	}
+	u.Events += events
+	u.Deliveries += deliveries
+}
+
+// warn logs the first soft-threshold and hard-limit crossing per client,
+// kind and month. Must be called with mu held.
+func (m *Meter) warn(clientID string, kind Kind, monthStart time.Time, used, limit int64) {
+	state := m.cfg.StateOf(used, limit)
+	if state == StateOK {
+		return
+	}
+	k := warnKey{clientID: clientID, kind: kind, state: state}
+	if m.warned[k].Equal(monthStart) {
+		return
+	}
+	m.warned[k] = monthStart
+	if state == StateExceeded {
+		slog.Warn("metering: client reached monthly quota", "client_id", clientID, "kind", kind, "used", used, "limit", limit)
+		return
+	}
+	slog.Warn("metering: client nearing monthly quota", "client_id", clientID, "kind", kind, "used", used, "limit", limit, "warn_percent", m.cfg.WarnPercent)
+}
+
+// load returns a snapshot of clientID's current month, reading through
+// the cache.
+func (m *Meter) load(ctx context.Context, clientID string) (month, error) {
+	start := MonthStart(m.now())
+	m.mu.Lock()
+	if c, ok := m.months.Get(clientID); ok && c.start.Equal(start) {
+		snap := *c
+		m.mu.Unlock()
+		return snap, nil
+	}
+	m.mu.Unlock()
+
+	totals, err := m.store.MonthTotals(ctx, clientID, start)
+	if err != nil {
+		return month{}, err
+	}
+	q, err := m.store.FindQuota(ctx, clientID)
+	if err != nil {
+		return month{}, err
+	}
+	c := &month{start: start, events: totals.Events, deliveries: totals.Deliveries}
+	c.eventLimit, c.deliveryLimit = m.cfg.Limits(q)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Counts not yet flushed aren't in the stored totals.
+	for k, u := range m.pending {
+		if k.clientID == clientID && !k.day.Before(start) {
+			c.events += u.Events
+			c.deliveries += u.Deliveries
+		}
+	}
+	m.months.Add(clientID, c)
+	return *c, nil
+}
@@ -0,0 +1,118 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	rows   map[string]Usage // client → month totals
+	quotas map[string]*Quota
+	adds   [][]Usage
+	addErr error
+	err    error
+}
+
+func (f *fakeStore) AddUsage(_ context.Context, rows []Usage) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	f.adds = append(f.adds, rows)
+	for _, u := range rows {
+		t := f.rows[u.ClientID]
+		t.Events += u.Events
+		t.Deliveries += u.Deliveries
+		f.rows[u.ClientID] = t
+	}
+	return nil
+}
+
+func (f *fakeStore) MonthTotals(_ context.Context, clientID string, _ time.Time) (Usage, error) {
+	return f.rows[clientID], f.err
+}
+
+func (f *fakeStore) FindQuota(_ context.Context, clientID string) (*Quota, error) {
+	return f.quotas[clientID], f.err
+}
+
+func int64p(v int64) *int64 { return &v }
+
+func testMeter(s *fakeStore) *Meter {
+	m := newMeter(Config{Enabled: true, DefaultMonthlyEvents: 10, WarnPercent: 80, CacheTTL: time.Minute}, s)
+	m.now = func() time.Time { return time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC) }
+	return m
+}
+
+func TestConfig_LimitsAndState(t *testing.T) {
+	cfg := Config{DefaultMonthlyEvents: 100, DefaultMonthlyDeliveries: 50, WarnPercent: 80}
+
+	ev, del := cfg.Limits(nil)
+	assert.Equal(t, []int64{100, 50}, []int64{ev, del})
+	ev, del = cfg.Limits(&Quota{MonthlyEvents: int64p(0)})
+	assert.Equal(t, []int64{0, 50}, []int64{ev, del}, "0 overrides the default to unlimited")
+
+	assert.Equal(t, StateOK, cfg.StateOf(79, 100))
+	assert.Equal(t, StateWarning, cfg.StateOf(80, 100))
+	assert.Equal(t, StateExceeded, cfg.StateOf(100, 100))
+	assert.Equal(t, StateOK, cfg.StateOf(1e9, 0), "unlimited is never exceeded")
+}
+
+func TestMeter_EnforcesEventQuota(t *testing.T) {
+	ctx := context.Background()
+	s := &fakeStore{rows: map[string]Usage{"clt_1": {Events: 7}}, quotas: map[string]*Quota{}}
+	m := testMeter(s)
+
+	require.NoError(t, m.CheckEvents(ctx, "clt_1", 3))
+	assert.ErrorIs(t, m.CheckEvents(ctx, "clt_1", 4), ErrQuotaExceeded)
+
+	m.RecordEvents("clt_1", 3)
+	assert.ErrorIs(t, m.CheckEvents(ctx, "clt_1", 1), ErrQuotaExceeded, "local counts apply before a flush")
+	assert.NoError(t, m.CheckEvents(ctx, "", 1000), "platform events are unmetered")
+
+	s.quotas["clt_1"] = &Quota{MonthlyEvents: int64p(20)}
+	m.Invalidate("clt_1")
+	assert.NoError(t, m.CheckEvents(ctx, "clt_1", 1), "a raised quota applies after invalidation")
+}
+
+func TestMeter_DeliveriesUnlimitedByDefault(t *testing.T) {
+	ctx := context.Background()
+	s := &fakeStore{rows: map[string]Usage{"clt_1": {Deliveries: 1e6}}, quotas: map[string]*Quota{}}
+	m := testMeter(s)
+	assert.True(t, m.AllowDelivery(ctx, "clt_1"))
+
+	s.quotas["clt_1"] = &Quota{MonthlyDeliveries: int64p(1e6)}
+	m.Invalidate("clt_1")
+	assert.False(t, m.AllowDelivery(ctx, "clt_1"))
+}
+
+func TestMeter_FlushRetainsCountsOnError(t *testing.T) {
+	ctx := context.Background()
+	s := &fakeStore{rows: map[string]Usage{}, quotas: map[string]*Quota{}, addErr: errors.New("db down")}
+	m := testMeter(s)
+
+	m.RecordEvents("clt_1", 2)
+	m.RecordDelivery("clt_1")
+	require.Error(t, m.Flush(ctx))
+
+	m.RecordEvents("clt_1", 1)
+	s.addErr = nil
+	require.NoError(t, m.Flush(ctx))
+	require.Len(t, s.adds, 1)
+	require.Len(t, s.adds[0], 1, "one row per client and day")
+	assert.Equal(t, Usage{ClientID: "clt_1", Day: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), Events: 3, Deliveries: 1}, s.adds[0][0])
+
+	require.NoError(t, m.Flush(ctx))
+	assert.Len(t, s.adds, 1, "nothing pending, nothing written")
+}
+
+func TestMeter_LookupErrorFailsOpen(t *testing.T) {
+	s := &fakeStore{err: errors.New("db down")}
+	m := testMeter(s)
+	assert.NoError(t, m.CheckEvents(context.Background(), "clt_1", 100))
+	assert.True(t, m.AllowDelivery(context.Background(), "clt_1"))
+}
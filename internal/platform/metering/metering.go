@@ -0,0 +1,315 @@
+// Package metering counts billable per-client traffic — events ingested
+// and webhooks delivered — per UTC day, and enforces monthly quotas on it.
+//
+// The ingest handlers and the dispatch-processing callback hand counts to
+// an in-process Meter which batches them into msg_client_usage_daily.
+// Usage counters are not an aggregate: like apiactivity's samples they are
+// written directly, with no UoW commit or domain event. Quota definitions
+// are configuration and go through the UoW (see operations.SetQuota).
+//
+// A quota has two thresholds per kind: a soft warning at WarnPercent of
+// the limit (logged once per client per month and reported as WARNING),
+// and a hard block at the limit itself — ingest answers 429, deliveries
+// are deferred until the next month or a raised limit.
+package metering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// ErrQuotaExceeded is returned by Meter.CheckEvents when accepting the
+// events would take the client past its monthly limit.
+var ErrQuotaExceeded = errors.New("monthly quota exceeded")
+
+// Kind is a metered traffic class.
+type Kind string
+
+const (
+	KindEvents     Kind = "events"
+	KindDeliveries Kind = "deliveries"
+)
+
+// State is where a client's month-to-date usage sits against its limit.
+type State string
+
+const (
+	StateOK       State = "OK"
+	StateWarning  State = "WARNING"
+	StateExceeded State = "EXCEEDED"
+)
+
+// Config holds the metering knobs (all env-overridable).
+type Config struct {
+	// Enabled turns counting and enforcement on. When false every Meter
+	// method is a no-op that allows the traffic.
+	Enabled bool
+	// DefaultMonthlyEvents / DefaultMonthlyDeliveries apply to clients
+	// without an override. 0 = unlimited.
+	DefaultMonthlyEvents     int64
+	DefaultMonthlyDeliveries int64
+	// WarnPercent is the soft threshold, as a percentage of the limit.
+	WarnPercent int
+	// FlushInterval is how often buffered counts are written.
+	FlushInterval time.Duration
+	// CacheTTL bounds how stale a client's cached month-to-date totals
+	// may get, and so how long another instance's traffic takes to count
+	// toward enforcement here.
+	CacheTTL time.Duration
+	// CacheSize caps the number of clients with cached totals.
+	CacheSize int
+}
+
+// ConfigFromEnv builds a Config from FC_METERING_* env vars.
+func ConfigFromEnv() Config {
+	warn := envutil.Int("FC_METERING_WARN_PERCENT", 80)
+	if warn <= 0 || warn > 100 {
+		warn = 80
+	}
+	return Config{
+		Enabled:                  envutil.Bool("FC_METERING_ENABLED", true),
+		DefaultMonthlyEvents:     int64(envutil.Int("FC_METERING_DEFAULT_MONTHLY_EVENTS", 0)),
+		DefaultMonthlyDeliveries: int64(envutil.Int("FC_METERING_DEFAULT_MONTHLY_DELIVERIES", 0)),
+		WarnPercent:              warn,
+		FlushInterval:            5 * time.Second,
+		CacheTTL:                 time.Duration(envutil.Int("FC_METERING_CACHE_TTL_SECS", 30)) * time.Second,
+		CacheSize:                10000,
+	}
+}
+
+// Limits resolves a client's effective monthly limits: the override when
+// set, else the platform default. q may be nil. 0 = unlimited.
+func (c Config) Limits(q *Quota) (events, deliveries int64) {
+	events, deliveries = c.DefaultMonthlyEvents, c.DefaultMonthlyDeliveries
+	if q != nil && q.MonthlyEvents != nil {
+		events = *q.MonthlyEvents
+	}
+	if q != nil && q.MonthlyDeliveries != nil {
+		deliveries = *q.MonthlyDeliveries
+	}
+	return events, deliveries
+}
+
+// StateOf classifies used against limit.
+func (c Config) StateOf(used, limit int64) State {
+	switch {
+	case limit <= 0:
+		return StateOK
+	case used >= limit:
+		return StateExceeded
+	case used*100 >= limit*int64(c.WarnPercent):
+		return StateWarning
+	default:
+		return StateOK
+	}
+}
+
+// MonthStart truncates t to the first instant of its UTC month.
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// DayStart truncates t to the first instant of its UTC day.
+func DayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Usage is one client's counts for one UTC day (or, from MonthTotals and
+// Summaries, a month — Day is then the month start).
+type Usage struct {
+	ClientID   string
+	Day        time.Time
+	Events     int64
+	Deliveries int64
+}
+
+// Quota is a client's override of the default monthly limits. Aggregate
+// root, keyed by client id.
+type Quota struct {
+	ClientID string `json:"clientId"`
+	// MonthlyEvents / MonthlyDeliveries: nil = platform default,
+	// 0 = unlimited.
+	MonthlyEvents     *int64    `json:"monthlyEvents,omitempty"`
+	MonthlyDeliveries *int64    `json:"monthlyDeliveries,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (q Quota) IDStr() string { return q.ClientID }
+
+// NewQuota builds an empty override (defaults for both kinds).
+func NewQuota(clientID string) *Quota {
+	now := time.Now().UTC()
+	return &Quota{ClientID: clientID, CreatedAt: now, UpdatedAt: now}
+}
+
+// Set replaces both limits.
+func (q *Quota) Set(events, deliveries *int64) {
+	q.MonthlyEvents = events
+	q.MonthlyDeliveries = deliveries
+	q.UpdatedAt = time.Now().UTC()
+}
+
+// Repository reads/writes msg_client_usage_daily (direct writes, no UoW)
+// and msg_client_quotas (through the UoW via Persist/Delete).
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+// AddUsage adds each row's counts onto the stored day in one round-trip.
+func (r *Repository) AddUsage(ctx context.Context, rows []Usage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	clients := make([]string, len(rows))
+	days := make([]time.Time, len(rows))
+	events := make([]int64, len(rows))
+	deliveries := make([]int64, len(rows))
+	for i, u := range rows {
+		clients[i], days[i], events[i], deliveries[i] = u.ClientID, u.Day, u.Events, u.Deliveries
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO msg_client_usage_daily (client_id, day, events_ingested, deliveries)
+		 SELECT * FROM unnest($1::varchar[], $2::date[], $3::bigint[], $4::bigint[])
+		 ON CONFLICT (client_id, day) DO UPDATE
+		    SET events_ingested = msg_client_usage_daily.events_ingested + EXCLUDED.events_ingested,
+		        deliveries      = msg_client_usage_daily.deliveries + EXCLUDED.deliveries,
+		        updated_at      = NOW()`,
+		clients, days, events, deliveries)
+	if err != nil {
+		return fmt.Errorf("add_usage: %w", err)
+	}
+	return nil
+}
+
+// MonthTotals sums a client's stored counts for the month starting at month.
+func (r *Repository) MonthTotals(ctx context.Context, clientID string, month time.Time) (Usage, error) {
+	u := Usage{ClientID: clientID, Day: month}
+	err := r.pool.QueryRow(ctx,
+		`SELECT COALESCE(SUM(events_ingested), 0)::bigint, COALESCE(SUM(deliveries), 0)::bigint
+		   FROM msg_client_usage_daily
+		  WHERE client_id = $1 AND day >= $2 AND day < $3`,
+		clientID, month, month.AddDate(0, 1, 0)).Scan(&u.Events, &u.Deliveries)
+	if err != nil {
+		return u, fmt.Errorf("month_totals: %w", err)
+	}
+	return u, nil
+}
+
+// Daily returns a client's per-day rows for the month starting at month,
+// oldest first. Days without traffic have no row.
+func (r *Repository) Daily(ctx context.Context, clientID string, month time.Time) ([]Usage, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT day, events_ingested, deliveries
+		   FROM msg_client_usage_daily
+		  WHERE client_id = $1 AND day >= $2 AND day < $3
+		  ORDER BY day`,
+		clientID, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, fmt.Errorf("daily: %w", err)
+	}
+	defer rows.Close()
+	var out []Usage
+	for rows.Next() {
+		u := Usage{ClientID: clientID}
+		if err := rows.Scan(&u.Day, &u.Events, &u.Deliveries); err != nil {
+			return nil, fmt.Errorf("daily: %w", err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// Summary is a client's month-to-date totals with its quota override.
+type Summary struct {
+	Usage
+	Quota *Quota
+}
+
+// Summaries returns every client with traffic or an override in the
+// month starting at month.
+func (r *Repository) Summaries(ctx context.Context, month time.Time) ([]Summary, error) {
+	rows, err := r.pool.Query(ctx,
+		`WITH usage AS (
+		     SELECT client_id, SUM(events_ingested)::bigint AS events, SUM(deliveries)::bigint AS deliveries
+		       FROM msg_client_usage_daily
+		      WHERE day >= $1 AND day < $2
+		      GROUP BY client_id)
+		 SELECT COALESCE(u.client_id, q.client_id), COALESCE(u.events, 0), COALESCE(u.deliveries, 0),
+		        q.client_id IS NOT NULL, q.monthly_events, q.monthly_deliveries
+		   FROM usage u
+		   FULL JOIN msg_client_quotas q ON q.client_id = u.client_id`,
+		month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, fmt.Errorf("summaries: %w", err)
+	}
+	defer rows.Close()
+	var out []Summary
+	for rows.Next() {
+		s := Summary{Usage: Usage{Day: month}}
+		var hasQuota bool
+		var q Quota
+		if err := rows.Scan(&s.ClientID, &s.Events, &s.Deliveries,
+			&hasQuota, &q.MonthlyEvents, &q.MonthlyDeliveries); err != nil {
+			return nil, fmt.Errorf("summaries: %w", err)
+		}
+		if hasQuota {
+			q.ClientID = s.ClientID
+			s.Quota = &q
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// FindQuota loads a client's override. Returns (nil, nil) when it has none.
+func (r *Repository) FindQuota(ctx context.Context, clientID string) (*Quota, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT client_id, monthly_events, monthly_deliveries, created_at, updated_at
+		   FROM msg_client_quotas WHERE client_id = $1`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("find_quota: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var q Quota
+	if err := rows.Scan(&q.ClientID, &q.MonthlyEvents, &q.MonthlyDeliveries, &q.CreatedAt, &q.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("find_quota: %w", err)
+	}
+	return &q, nil
+}
+
+// Persist implements usecasepgx.Persist[Quota].
+func (r *Repository) Persist(ctx context.Context, q *Quota, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx,
+		`INSERT INTO msg_client_quotas (client_id, monthly_events, monthly_deliveries, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (client_id) DO UPDATE
+		    SET monthly_events     = EXCLUDED.monthly_events,
+		        monthly_deliveries = EXCLUDED.monthly_deliveries,
+		        updated_at         = EXCLUDED.updated_at`,
+		q.ClientID, q.MonthlyEvents, q.MonthlyDeliveries, q.CreatedAt, q.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("persist quota: %w", err)
+	}
+	return nil
+}
+
+// Delete implements usecasepgx.Persist[Quota].
+func (r *Repository) Delete(ctx context.Context, q *Quota, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx, `DELETE FROM msg_client_quotas WHERE client_id = $1`, q.ClientID)
+	return err
+}
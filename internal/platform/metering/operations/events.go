@@ -0,0 +1,42 @@
+package operations
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+const (
+	ClientQuotaUpdatedType = "platform:admin:client:quota-updated"
+	Source                 = "platform:admin"
+)
+
+func subjectFor(id string) string { return "platform.client." + id }
+func groupFor(id string) string   { return "platform:client:" + id }
+
+type ClientQuotaUpdated struct {
+	Metadata          usecase.EventMetadata
+	ClientID          string
+	MonthlyEvents     *int64
+	MonthlyDeliveries *int64
+}
+
+func (e ClientQuotaUpdated) EventID() string       { return e.Metadata.EventID }
+func (e ClientQuotaUpdated) EventType() string     { return ClientQuotaUpdatedType }
+func (e ClientQuotaUpdated) SpecVersion() string   { return "1.0" }
+func (e ClientQuotaUpdated) Source() string        { return Source }
+func (e ClientQuotaUpdated) Subject() string       { return subjectFor(e.ClientID) }
+func (e ClientQuotaUpdated) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ClientQuotaUpdated) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ClientQuotaUpdated) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ClientQuotaUpdated) CausationID() string   { return e.Metadata.CausationID }
+func (e ClientQuotaUpdated) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ClientQuotaUpdated) MessageGroup() string  { return groupFor(e.ClientID) }
+func (e ClientQuotaUpdated) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ClientID          string `json:"clientId"`
+		MonthlyEvents     *int64 `json:"monthlyEvents"`
+		MonthlyDeliveries *int64 `json:"monthlyDeliveries"`
+	}{e.ClientID, e.MonthlyEvents, e.MonthlyDeliveries})
+}
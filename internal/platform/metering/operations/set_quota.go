@@ -0,0 +1,66 @@
+// Package operations holds the metering use cases.
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// SetQuotaCommand is the input DTO. A nil limit falls back to the
+// platform default; 0 means unlimited.
+type SetQuotaCommand struct {
+	ClientID          string `json:"clientId"`
+	MonthlyEvents     *int64 `json:"monthlyEvents"`
+	MonthlyDeliveries *int64 `json:"monthlyDeliveries"`
+}
+
+// SetQuota replaces a client's monthly limit overrides and emits
+// [ClientQuotaUpdated].
+func SetQuota(repo *metering.Repository, clients *client.Repository) usecaseop.Operation[SetQuotaCommand, ClientQuotaUpdated] {
+	return usecaseop.Operation[SetQuotaCommand, ClientQuotaUpdated]{
+		Name: "SetClientQuota",
+		Validate: func(_ context.Context, cmd SetQuotaCommand) error {
+			if strings.TrimSpace(cmd.ClientID) == "" {
+				return usecase.Validation("CLIENT_ID_REQUIRED", "clientId is required")
+			}
+			if cmd.MonthlyEvents != nil && *cmd.MonthlyEvents < 0 {
+				return usecase.Validation("INVALID_LIMIT", "monthlyEvents must not be negative")
+			}
+			if cmd.MonthlyDeliveries != nil && *cmd.MonthlyDeliveries < 0 {
+				return usecase.Validation("INVALID_LIMIT", "monthlyDeliveries must not be negative")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[SetQuotaCommand],
+		Execute: func(ctx context.Context, cmd SetQuotaCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ClientQuotaUpdated], error) {
+			c, err := clients.FindByID(ctx, cmd.ClientID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_client failed", err)
+			}
+			if c == nil {
+				return nil, httperror.NotFound("Client", cmd.ClientID)
+			}
+			q, err := repo.FindQuota(ctx, c.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_quota failed", err)
+			}
+			if q == nil {
+				q = metering.NewQuota(c.ID)
+			}
+			q.Set(cmd.MonthlyEvents, cmd.MonthlyDeliveries)
+			event := ClientQuotaUpdated{
+				Metadata:          usecase.NewEventMetadata(ec, ClientQuotaUpdatedType, Source, subjectFor(c.ID)),
+				ClientID:          c.ID,
+				MonthlyEvents:     q.MonthlyEvents,
+				MonthlyDeliveries: q.MonthlyDeliveries,
+			}
+			return usecaseop.Save(q, repo, event), nil
+		},
+	}
+}
@@ -8,6 +8,7 @@ package notify
 
 import (
 	"context"
+	"html"
 	"log/slog"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/email"
@@ -132,6 +133,15 @@ func (n *Notifier) ResetApprovalNeeded(ctx context.Context, to, link string) {
 			"<p><a href=\""+link+"\">Review the request</a></p>")
 }
 
+// SubscriptionApprovalNeeded asks an approver to review a subscription
+// created in their organisation, which receives no events until approved.
+func (n *Notifier) SubscriptionApprovalNeeded(ctx context.Context, to, code, link string) {
+	n.send(ctx, to, "A subscription needs your approval",
+		"<p>The subscription <strong>"+html.EscapeString(code)+"</strong> was created in your "+
+			"organisation and won't receive events until an approver reviews it.</p>"+
+			"<p><a href=\""+link+"\">Review the subscription</a></p>")
+}
+
 func methodLabel(method string) string {
 	switch method {
 	case "TOTP":
@@ -235,11 +235,13 @@ func (s *State) enrollTTL() time.Duration {
 }
 
 // RegisterRoutes mounts the three unauthenticated endpoints. Mount OUTSIDE the
-// auth middleware (alongside /auth/login).
+// auth middleware (alongside /auth/login). POST /auth/reset-password is the
+// same confirm step under the shorter name SDKs and emailed clients use.
 func RegisterRoutes(r chi.Router, s *State) {
 	r.Post("/auth/password-reset/request", s.requestReset)
 	r.Get("/auth/password-reset/validate", s.validateToken)
 	r.Post("/auth/password-reset/confirm", s.confirmReset)
+	r.Post("/auth/reset-password", s.confirmReset)
 }
 
 type messageResponse struct {
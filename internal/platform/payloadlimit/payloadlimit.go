@@ -0,0 +1,308 @@
+// Package payloadlimit enforces the maximum payload size on event and
+// dispatch-job ingest, and offloads large event payloads to object
+// storage so the row — and every queue message fanned out from it —
+// carries a small reference instead of the body.
+//
+// With claim-check enabled, large dispatch-job payloads are offloaded the
+// same way. The queue's message pointer only ever carries the job id; the
+// dispatch-processing callback claims the referenced body at delivery time
+// and releases (deletes) a job's object once the job is terminal.
+//
+// Limits resolve most-specific first: a per-event-type limit, then a
+// per-client limit, then the global one. A payload over its limit is
+// rejected with 413 PAYLOAD_TOO_LARGE; the whole request body is still
+// bounded by the HTTP layer (1 MiB for huma routes) regardless.
+package payloadlimit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// RefKey is the single key of an offloaded event's data document:
+//
+//	{"$payloadRef": {"uri": "s3://…", "sizeBytes": 300000, "sha256": "…", "contentType": "application/json"}}
+const RefKey = "$payloadRef"
+
+// Policy is the size configuration. Zero limits are unlimited.
+type Policy struct {
+	// MaxBytes is the global payload ceiling.
+	MaxBytes int
+	// ByClient / ByEventType override MaxBytes for one client id or event
+	// type code. An event-type entry wins over a client entry.
+	ByClient    map[string]int
+	ByEventType map[string]int
+	// OffloadBytes is the size above which an event payload is written to
+	// object storage (when a Store is configured) and replaced by a
+	// reference. Zero never offloads.
+	OffloadBytes int
+	// OffloadDestination is s3://bucket[/prefix] or gs://bucket[/prefix];
+	// it names the store and prefixes each reference URI.
+	OffloadDestination string
+	// ClaimCheck also offloads dispatch-job payloads over OffloadBytes,
+	// deleting each object once its job completes or fails for good.
+	ClaimCheck bool
+}
+
+// PolicyFromEnv builds a Policy from FC_PAYLOAD_* env vars.
+func PolicyFromEnv() Policy {
+	return Policy{
+		MaxBytes:           envutil.Int("FC_PAYLOAD_MAX_BYTES", 1<<20),
+		ByClient:           limitsFromEnv("FC_PAYLOAD_MAX_BYTES_BY_CLIENT"),
+		ByEventType:        limitsFromEnv("FC_PAYLOAD_MAX_BYTES_BY_EVENT_TYPE"),
+		OffloadBytes:       envutil.Int("FC_PAYLOAD_OFFLOAD_BYTES", 256<<10),
+		OffloadDestination: strings.TrimRight(envutil.Or("FC_PAYLOAD_OFFLOAD_DESTINATION", ""), "/"),
+		ClaimCheck:         envutil.Bool("FC_PAYLOAD_CLAIM_CHECK", false),
+	}
+}
+
+// limitsFromEnv reads "key=bytes;key=bytes" from the named variable.
+// Malformed entries are logged and skipped rather than failing startup.
+func limitsFromEnv(name string) map[string]int {
+	out := map[string]int{}
+	for _, entry := range strings.Split(envutil.Or(name, ""), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if !ok || key == "" || err != nil || n < 0 {
+			slog.Warn("ignoring malformed payload limit entry", "var", name, "entry", entry)
+			continue
+		}
+		out[key] = n
+	}
+	return out
+}
+
+// Limit returns the effective ceiling for a payload and the scope it came
+// from ("eventType", "client" or "global"). Zero is unlimited.
+func (p Policy) Limit(clientID *string, eventType string) (int, string) {
+	if n, ok := p.ByEventType[eventType]; ok && eventType != "" {
+		return n, "eventType"
+	}
+	if clientID != nil {
+		if n, ok := p.ByClient[*clientID]; ok {
+			return n, "client"
+		}
+	}
+	return p.MaxBytes, "global"
+}
+
+// Check returns a *TooLargeError when a payload of size bytes exceeds its
+// limit, nil otherwise.
+func (p Policy) Check(clientID *string, eventType string, size int) *TooLargeError {
+	limit, scope := p.Limit(clientID, eventType)
+	if limit <= 0 || size <= limit {
+		return nil
+	}
+	return &TooLargeError{
+		Code:    "PAYLOAD_TOO_LARGE",
+		Message: fmt.Sprintf("payload is %d bytes; the %s limit is %d bytes", size, scope, limit),
+		Details: map[string]any{"sizeBytes": size, "limitBytes": limit, "scope": scope},
+	}
+}
+
+// TooLargeError is the 413 rejection. It implements huma.StatusError, so
+// huma handlers can return it as-is and it renders as the standard
+// {error, message, details} envelope; chi handlers use Write.
+type TooLargeError struct {
+	Code    string         `json:"error"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+func (e *TooLargeError) Error() string { return e.Message }
+
+// At marks the error with the offending batch item's index.
+func (e *TooLargeError) At(index int) *TooLargeError {
+	e.Message = fmt.Sprintf("items[%d]: %s", index, e.Message)
+	e.Details["index"] = index
+	return e
+}
+
+// GetStatus reports 413 Request Entity Too Large.
+func (e *TooLargeError) GetStatus() int { return http.StatusRequestEntityTooLarge }
+
+// Write renders the error on a plain net/http response.
+func (e *TooLargeError) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// Store is the object storage an Offloader writes to; export.ObjectStore
+// satisfies it.
+type Store interface {
+	Key(name string) string
+	Upload(ctx context.Context, key, contentType string, r io.Reader) (int64, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Ref is the body of an offloaded payload's reference document.
+type Ref struct {
+	URI         string `json:"uri"`
+	SizeBytes   int    `json:"sizeBytes"`
+	SHA256      string `json:"sha256"`
+	ContentType string `json:"contentType"`
+	// DeleteAfterDelivery marks a claim-checked dispatch-job payload, owned
+	// by one job. Event payloads are shared by every job fanned out from
+	// the event and are never deleted on delivery.
+	DeleteAfterDelivery bool `json:"deleteAfterDelivery,omitempty"`
+}
+
+// Offloader combines the policy with an optional store. A nil store (no
+// destination configured) only enforces limits.
+type Offloader struct {
+	policy Policy
+	store  Store
+}
+
+// New builds an Offloader. store may be nil.
+func New(p Policy, store Store) *Offloader { return &Offloader{policy: p, store: store} }
+
+// Check applies the policy. A nil Offloader allows everything.
+func (o *Offloader) Check(clientID *string, eventType string, size int) *TooLargeError {
+	if o == nil {
+		return nil
+	}
+	return o.policy.Check(clientID, eventType, size)
+}
+
+// OffloadEventData writes data to the store when it exceeds the offload
+// threshold and returns the reference document to store in its place;
+// otherwise data is returned unchanged. The object is keyed by event id
+// under events/<yyyy>/<mm>/<dd>/, so a retried ingest overwrites rather
+// than duplicates.
+func (o *Offloader) OffloadEventData(ctx context.Context, eventID string, at time.Time, data json.RawMessage) (json.RawMessage, error) {
+	if o == nil || o.store == nil || o.policy.OffloadBytes <= 0 || len(data) <= o.policy.OffloadBytes {
+		return data, nil
+	}
+	name := fmt.Sprintf("events/%s/%s.json", at.UTC().Format("2006/01/02"), eventID)
+	ref, err := o.upload(ctx, name, "application/json", data)
+	if err != nil {
+		return nil, fmt.Errorf("offload payload for event %s: %w", eventID, err)
+	}
+	return json.Marshal(map[string]Ref{RefKey: ref})
+}
+
+// OffloadJobPayload is the claim-check write for a dispatch job: with
+// ClaimCheck on and a store configured, a payload over the offload
+// threshold is written under dispatch-jobs/<yyyy>/<mm>/<dd>/<id> and the
+// returned reference document replaces it; otherwise payload is returned
+// unchanged.
+func (o *Offloader) OffloadJobPayload(ctx context.Context, jobID string, at time.Time, payload, contentType string) (string, error) {
+	if o == nil || !o.policy.ClaimCheck || o.store == nil || o.policy.OffloadBytes <= 0 || len(payload) <= o.policy.OffloadBytes {
+		return payload, nil
+	}
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	name := fmt.Sprintf("dispatch-jobs/%s/%s", at.UTC().Format("2006/01/02"), jobID)
+	ref, err := o.upload(ctx, name, contentType, []byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("claim-check payload for dispatch job %s: %w", jobID, err)
+	}
+	ref.DeleteAfterDelivery = true
+	doc, err := json.Marshal(map[string]Ref{RefKey: ref})
+	return string(doc), err
+}
+
+func (o *Offloader) upload(ctx context.Context, name, contentType string, body []byte) (Ref, error) {
+	if _, err := o.store.Upload(ctx, o.store.Key(name), contentType, bytes.NewReader(body)); err != nil {
+		return Ref{}, err
+	}
+	sum := sha256.Sum256(body)
+	return Ref{
+		URI:         o.policy.OffloadDestination + "/" + name,
+		SizeBytes:   len(body),
+		SHA256:      hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+	}, nil
+}
+
+// ParseRef returns the reference when payload is exactly a reference
+// document, nil for any other payload.
+func ParseRef(payload string) *Ref {
+	if !strings.Contains(payload, RefKey) {
+		return nil
+	}
+	var doc map[string]json.RawMessage
+	if json.Unmarshal([]byte(payload), &doc) != nil || len(doc) != 1 {
+		return nil
+	}
+	raw, ok := doc[RefKey]
+	if !ok {
+		return nil
+	}
+	var ref Ref
+	if json.Unmarshal(raw, &ref) != nil || ref.URI == "" {
+		return nil
+	}
+	return &ref
+}
+
+// Claim resolves an offloaded payload: when payload is a reference
+// document the referenced body is fetched, checked against its digest and
+// returned with the reference; any other payload is returned as-is with a
+// nil reference. Errors mean the body is (for now) unavailable.
+func (o *Offloader) Claim(ctx context.Context, payload string) (string, *Ref, error) {
+	ref := ParseRef(payload)
+	if ref == nil {
+		return payload, nil, nil
+	}
+	key, err := o.key(ref)
+	if err != nil {
+		return "", nil, err
+	}
+	body, err := o.store.Get(ctx, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch %s: %w", ref.URI, err)
+	}
+	if sum := sha256.Sum256(body); ref.SHA256 != "" && hex.EncodeToString(sum[:]) != ref.SHA256 {
+		return "", nil, fmt.Errorf("fetch %s: sha256 mismatch", ref.URI)
+	}
+	return string(body), ref, nil
+}
+
+// Release deletes a claimed object once its job is terminal. Only
+// claim-checked job payloads are deleted; a nil ref is a no-op.
+func (o *Offloader) Release(ctx context.Context, ref *Ref) error {
+	if ref == nil || !ref.DeleteAfterDelivery {
+		return nil
+	}
+	key, err := o.key(ref)
+	if err != nil {
+		return err
+	}
+	return o.store.Delete(ctx, key)
+}
+
+// key maps a reference URI back to its object key. Only URIs under the
+// configured destination resolve, so a crafted reference can't read or
+// delete arbitrary objects.
+func (o *Offloader) key(ref *Ref) (string, error) {
+	if o == nil || o.store == nil {
+		return "", fmt.Errorf("payload %s is offloaded but no offload destination is configured", ref.URI)
+	}
+	name, ok := strings.CutPrefix(ref.URI, o.policy.OffloadDestination+"/")
+	if !ok || name == "" || strings.Contains(name, "..") {
+		return "", fmt.Errorf("payload reference %s is outside the offload destination", ref.URI)
+	}
+	return o.store.Key(name), nil
+}
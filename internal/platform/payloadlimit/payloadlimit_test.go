@@ -0,0 +1,211 @@
+package payloadlimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimitMostSpecificWins(t *testing.T) {
+	p := Policy{
+		MaxBytes:    100,
+		ByClient:    map[string]int{"c1": 50},
+		ByEventType: map[string]int{"orders:sales:order:created": 500},
+	}
+	c1, c2 := "c1", "c2"
+	cases := []struct {
+		client    *string
+		eventType string
+		limit     int
+		scope     string
+	}{
+		{nil, "", 100, "global"},
+		{&c2, "other", 100, "global"},
+		{&c1, "other", 50, "client"},
+		{&c1, "orders:sales:order:created", 500, "eventType"},
+	}
+	for _, tc := range cases {
+		limit, scope := p.Limit(tc.client, tc.eventType)
+		if limit != tc.limit || scope != tc.scope {
+			t.Errorf("Limit(%v, %q) = %d/%s, want %d/%s", tc.client, tc.eventType, limit, scope, tc.limit, tc.scope)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := Policy{MaxBytes: 10}
+	if tl := p.Check(nil, "t", 10); tl != nil {
+		t.Fatalf("at the limit should pass: %v", tl)
+	}
+	tl := p.Check(nil, "t", 11)
+	if tl == nil || tl.Code != "PAYLOAD_TOO_LARGE" || tl.GetStatus() != http.StatusRequestEntityTooLarge {
+		t.Fatalf("over the limit: %+v", tl)
+	}
+	if (Policy{}).Check(nil, "t", 1<<30) != nil {
+		t.Error("zero limit is unlimited")
+	}
+	var o *Offloader
+	if o.Check(nil, "t", 1<<30) != nil {
+		t.Error("nil Offloader allows everything")
+	}
+
+	rec := httptest.NewRecorder()
+	p.Check(nil, "t", 11).At(3).Write(rec)
+	var body map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusRequestEntityTooLarge || body["error"] != "PAYLOAD_TOO_LARGE" ||
+		!strings.HasPrefix(body["message"].(string), "items[3]: ") {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	t.Setenv("FC_PAYLOAD_MAX_BYTES", "2048")
+	t.Setenv("FC_PAYLOAD_MAX_BYTES_BY_CLIENT", "clt_1=4096; bad; clt_2=-1")
+	t.Setenv("FC_PAYLOAD_MAX_BYTES_BY_EVENT_TYPE", "a:b:c:d=128")
+	p := PolicyFromEnv()
+	if p.MaxBytes != 2048 || len(p.ByClient) != 1 || p.ByClient["clt_1"] != 4096 || p.ByEventType["a:b:c:d"] != 128 {
+		t.Fatalf("unexpected policy: %+v", p)
+	}
+}
+
+type memStore struct {
+	key, contentType string
+	body             []byte
+	objects          map[string][]byte
+}
+
+func (m *memStore) Key(name string) string { return "pfx/" + name }
+
+func (m *memStore) Upload(_ context.Context, key, contentType string, r io.Reader) (int64, error) {
+	m.key, m.contentType = key, contentType
+	m.body, _ = io.ReadAll(r)
+	if m.objects == nil {
+		m.objects = map[string][]byte{}
+	}
+	m.objects[key] = m.body
+	return int64(len(m.body)), nil
+}
+
+func (m *memStore) Get(_ context.Context, key string) ([]byte, error) {
+	b, ok := m.objects[key]
+	if !ok {
+		return nil, errors.New("404 Not Found")
+	}
+	return b, nil
+}
+
+func (m *memStore) Delete(_ context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func TestOffloadEventData(t *testing.T) {
+	store := &memStore{}
+	o := New(Policy{OffloadBytes: 16, OffloadDestination: "s3://bucket/pfx"}, store)
+	at := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	small := json.RawMessage(`{"a":1}`)
+	got, err := o.OffloadEventData(context.Background(), "evt1", at, small)
+	if err != nil || string(got) != string(small) || store.key != "" {
+		t.Fatalf("small payload must stay inline: %s %v", got, err)
+	}
+
+	big := json.RawMessage(`{"items":["aaaaaaaaaaaaaaaaaaaa"]}`)
+	got, err = o.OffloadEventData(context.Background(), "evt2", at, big)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.key != "pfx/events/2026/03/04/evt2.json" || string(store.body) != string(big) {
+		t.Fatalf("unexpected upload %q %s", store.key, store.body)
+	}
+	var doc map[string]Ref
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatal(err)
+	}
+	ref := doc[RefKey]
+	if ref.URI != "s3://bucket/pfx/events/2026/03/04/evt2.json" || ref.SizeBytes != len(big) || len(ref.SHA256) != 64 {
+		t.Fatalf("unexpected ref %+v", ref)
+	}
+
+	if got, _ := New(Policy{OffloadBytes: 16}, nil).OffloadEventData(context.Background(), "e", at, big); string(got) != string(big) {
+		t.Error("no store means no offload")
+	}
+}
+
+func TestClaimCheckRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{}
+	at := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	big := `{"items":["aaaaaaaaaaaaaaaaaaaa"]}`
+
+	off := New(Policy{OffloadBytes: 16, OffloadDestination: "s3://bucket/pfx"}, store)
+	if got, _ := off.OffloadJobPayload(ctx, "job1", at, big, ""); got != big {
+		t.Fatal("claim-check off must keep the payload inline")
+	}
+
+	o := New(Policy{OffloadBytes: 16, OffloadDestination: "s3://bucket/pfx", ClaimCheck: true}, store)
+	doc, err := o.OffloadJobPayload(ctx, "job1", at, big, "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.key != "pfx/dispatch-jobs/2026/03/04/job1" {
+		t.Fatalf("unexpected key %q", store.key)
+	}
+	ref := ParseRef(doc)
+	if ref == nil || !ref.DeleteAfterDelivery || ref.URI != "s3://bucket/pfx/dispatch-jobs/2026/03/04/job1" {
+		t.Fatalf("unexpected ref %s", doc)
+	}
+
+	body, claimed, err := o.Claim(ctx, doc)
+	if err != nil || body != big || claimed == nil {
+		t.Fatalf("claim: %q %v %v", body, claimed, err)
+	}
+	if err := o.Release(ctx, claimed); err != nil || len(store.objects) != 0 {
+		t.Fatalf("release must delete the object: %v %v", err, store.objects)
+	}
+	if _, _, err := o.Claim(ctx, doc); err == nil {
+		t.Fatal("claiming a released payload must fail")
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{}
+	o := New(Policy{OffloadBytes: 4, OffloadDestination: "s3://bucket/pfx"}, store)
+
+	if body, ref, err := o.Claim(ctx, `{"a":1}`); body != `{"a":1}` || ref != nil || err != nil {
+		t.Fatal("an inline payload passes through")
+	}
+	if body, ref, _ := (*Offloader)(nil).Claim(ctx, "plain"); body != "plain" || ref != nil {
+		t.Fatal("a nil Offloader passes inline payloads through")
+	}
+
+	doc, _ := o.OffloadEventData(ctx, "evt1", time.Now(), json.RawMessage(`{"big":true}`))
+	_, ref, err := o.Claim(ctx, string(doc))
+	if err != nil || ref == nil || ref.DeleteAfterDelivery {
+		t.Fatalf("event ref: %v %v", ref, err)
+	}
+	_ = o.Release(ctx, ref)
+	if len(store.objects) != 1 {
+		t.Fatal("event payloads are shared and never released")
+	}
+
+	store.objects[store.key] = []byte(`{"big":false}`)
+	if _, _, err := o.Claim(ctx, string(doc)); err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Fatalf("tampered body: %v", err)
+	}
+	foreign := `{"$payloadRef":{"uri":"s3://other/secret","sizeBytes":1,"sha256":"","contentType":"application/json"}}`
+	if _, _, err := o.Claim(ctx, foreign); err == nil {
+		t.Fatal("a reference outside the destination must not resolve")
+	}
+	if _, _, err := New(Policy{}, nil).Claim(ctx, string(doc)); err == nil {
+		t.Fatal("a reference without a store must fail")
+	}
+}
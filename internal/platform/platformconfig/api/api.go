@@ -13,6 +13,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -129,7 +130,7 @@ func (s *State) setProperty(ctx context.Context, in *setPropertyInput) (*apicomm
 	}
 	// Authorization (anchor, or non-anchor with write access to the app) runs
 	// inside the use case's Authorize phase.
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.SetProperty(s.Repo), in.Body.toCommand(in.App, in.Section, in.Property), ec); err != nil {
 		return nil, err
 	}
@@ -145,36 +146,16 @@ func (s *State) setProperty(ctx context.Context, in *setPropertyInput) (*apicomm
 }
 
 func (s *State) deleteProperty(ctx context.Context, in *propertyInput) (*apicommon.Empty, error) {
-	ac := auth.FromContext(ctx)
-	if !ac.IsAnchor() {
-		ok, err := s.Repo.HasAccess(ctx, in.App, ac.Roles, true)
-		if err != nil {
-			return nil, usecase.Internal("REPO", "has_access failed", err)
-		}
-		if !ok {
-			return nil, httperror.Forbidden("No write access to platform config for " + in.App)
-		}
-	}
-	scope, clientID := scopeFor(in.ClientID)
-	c, err := s.Repo.FindByCoordinate(ctx, in.App, in.Section, in.Property, scope, clientID)
-	if err != nil {
-		return nil, usecase.Internal("REPO", "find_by_coordinate failed", err)
-	}
-	if c == nil {
-		return &apicommon.Empty{}, nil // idempotent
-	}
-	tx, err := s.UoW.Pool().Begin(ctx)
-	if err != nil {
-		return nil, usecase.Internal("TX", "begin failed", err)
-	}
-	defer tx.Rollback(ctx)
-	if err := s.Repo.Delete(ctx, c, usecasepgx.WrapTxForBootstrap(tx)); err != nil {
-		return nil, usecase.Internal("REPO", "delete failed", err)
+	cmd := operations.DeletePropertyCommand{ApplicationCode: in.App, Section: in.Section, Property: in.Property}
+	if in.ClientID != "" {
+		cid := in.ClientID
+		cmd.ClientID = &cid
 	}
-	if err := tx.Commit(ctx); err != nil {
-		return nil, usecase.Internal("TX", "commit failed", err)
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteProperty(s.Repo), cmd, ec); err != nil && !httperror.IsNotFound(err) {
+		return nil, err
 	}
-	return &apicommon.Empty{}, nil
+	return &apicommon.Empty{}, nil // idempotent
 }
 
 type listAccessInput struct {
@@ -204,7 +185,7 @@ func (s *State) grantAccess(ctx context.Context, in *grantAccessInput) (*apicomm
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.GrantAccess(s.Repo), in.Body.toCommand(in.App), ec)
 	if err != nil {
 		return nil, err
@@ -217,7 +198,7 @@ func (s *State) revokeAccess(ctx context.Context, in *apicommon.IDInput) (*apico
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.RevokeAccess(s.Repo), operations.RevokeAccessCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
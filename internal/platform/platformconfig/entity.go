@@ -59,6 +59,15 @@ type Config struct {
 // IDStr satisfies usecase.HasID.
 func (c Config) IDStr() string { return c.ID }
 
+// AuditSensitive satisfies audit.Sensitive: a SECRET value is redacted
+// from audit diffs (the change is still recorded).
+func (c Config) AuditSensitive() []string {
+	if c.ValueType == ValueSecret {
+		return []string{"value"}
+	}
+	return nil
+}
+
 // NewConfig constructs a Config.
 func NewConfig(app, section, property, value string) *Config {
 	now := time.Now().UTC()
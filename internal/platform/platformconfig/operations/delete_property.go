@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// DeletePropertyCommand is the input DTO.
+type DeletePropertyCommand struct {
+	ApplicationCode string  `json:"applicationCode"`
+	Section         string  `json:"section"`
+	Property        string  `json:"property"`
+	ClientID        *string `json:"clientId,omitempty"`
+}
+
+// DeleteProperty removes the (app, section, property, scope, client_id)
+// coordinate and emits [PropertyDeleted]. Authorization is SetProperty's
+// rule: any anchor, or a non-anchor with write access to the application.
+func DeleteProperty(repo *platformconfig.Repository) usecaseop.Operation[DeletePropertyCommand, PropertyDeleted] {
+	return usecaseop.Operation[DeletePropertyCommand, PropertyDeleted]{
+		Name: "DeleteProperty",
+		Validate: func(_ context.Context, cmd DeletePropertyCommand) error {
+			for name, v := range map[string]string{
+				"applicationCode": cmd.ApplicationCode, "section": cmd.Section, "property": cmd.Property,
+			} {
+				if strings.TrimSpace(v) == "" {
+					return usecase.Validation("FIELD_REQUIRED", name+" is required")
+				}
+			}
+			return nil
+		},
+		Authorize: func(ctx context.Context, cmd DeletePropertyCommand) error {
+			ac := auth.FromContext(ctx)
+			if ac.IsAnchor() {
+				return nil
+			}
+			ok, err := repo.HasAccess(ctx, cmd.ApplicationCode, ac.Roles, true)
+			if err != nil {
+				return usecase.Internal("REPO", "has_access failed", err)
+			}
+			if !ok {
+				return httperror.Forbidden("No write access to platform config for " + cmd.ApplicationCode)
+			}
+			return nil
+		},
+		Execute: func(ctx context.Context, cmd DeletePropertyCommand, ec usecase.ExecutionContext) (usecaseop.Plan[PropertyDeleted], error) {
+			scope := platformconfig.ScopeGlobal
+			if cmd.ClientID != nil {
+				scope = platformconfig.ScopeClient
+			}
+			c, err := repo.FindByCoordinate(ctx, cmd.ApplicationCode, cmd.Section, cmd.Property, scope, cmd.ClientID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_coordinate failed", err)
+			}
+			if c == nil {
+				return nil, httperror.NotFound("PlatformConfig", cmd.ApplicationCode+"/"+cmd.Section+"/"+cmd.Property)
+			}
+			event := PropertyDeleted{
+				Metadata:        usecase.NewEventMetadata(ec, PropertyDeletedType, Source, subjectFor(c.ID)),
+				ConfigID:        c.ID,
+				ApplicationCode: c.ApplicationCode,
+				Section:         c.Section,
+				Property:        c.Property,
+			}
+			return usecaseop.Delete(c, repo, event), nil
+		},
+	}
+}
@@ -8,10 +8,11 @@ import (
 )
 
 const (
-	PropertySetType   = "platform:admin:platform-config:property-set"
-	AccessGrantedType = "platform:admin:platform-config:access-granted"
-	AccessRevokedType = "platform:admin:platform-config:access-revoked"
-	Source            = "platform:admin"
+	PropertySetType     = "platform:admin:platform-config:property-set"
+	PropertyDeletedType = "platform:admin:platform-config:property-deleted"
+	AccessGrantedType   = "platform:admin:platform-config:access-granted"
+	AccessRevokedType   = "platform:admin:platform-config:access-revoked"
+	Source              = "platform:admin"
 )
 
 func subjectFor(id string) string { return "platform.platformconfig." + id }
@@ -45,6 +46,34 @@ func (e PropertySet) ToDataJSON() ([]byte, error) {
 	}{e.ConfigID, e.ApplicationCode, e.Section, e.Property})
 }
 
+type PropertyDeleted struct {
+	Metadata        usecase.EventMetadata
+	ConfigID        string
+	ApplicationCode string
+	Section         string
+	Property        string
+}
+
+func (e PropertyDeleted) EventID() string       { return e.Metadata.EventID }
+func (e PropertyDeleted) EventType() string     { return PropertyDeletedType }
+func (e PropertyDeleted) SpecVersion() string   { return "1.0" }
+func (e PropertyDeleted) Source() string        { return Source }
+func (e PropertyDeleted) Subject() string       { return subjectFor(e.ConfigID) }
+func (e PropertyDeleted) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e PropertyDeleted) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e PropertyDeleted) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e PropertyDeleted) CausationID() string   { return e.Metadata.CausationID }
+func (e PropertyDeleted) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e PropertyDeleted) MessageGroup() string  { return groupFor(e.ConfigID) }
+func (e PropertyDeleted) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ConfigID        string `json:"configId"`
+		ApplicationCode string `json:"applicationCode"`
+		Section         string `json:"section"`
+		Property        string `json:"property"`
+	}{e.ConfigID, e.ApplicationCode, e.Section, e.Property})
+}
+
 type AccessGranted struct {
 	Metadata        usecase.EventMetadata
 	AccessID        string
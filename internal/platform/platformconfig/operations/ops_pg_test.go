@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
@@ -131,6 +132,80 @@ func TestSetProperty_Validation(t *testing.T) {
 	}
 }
 
+// ── DeleteProperty ────────────────────────────────────────────────────────
+
+func TestDeleteProperty_HappyPath(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := platformconfig.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	seeded, err := runAuthorized(uow, operations.SetProperty(repo), operations.SetPropertyCommand{
+		ApplicationCode: "pcdel-happy", Section: "smtp", Property: "host", Value: "mail.example.com",
+	})
+	require.NoError(t, err)
+
+	ev, err := runAuthorized(uow, operations.DeleteProperty(repo), operations.DeletePropertyCommand{
+		ApplicationCode: "pcdel-happy", Section: "smtp", Property: "host",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, seeded.ConfigID, ev.ConfigID)
+
+	got, err := repo.FindByID(ctx, seeded.ConfigID)
+	require.NoError(t, err)
+	assert.Nil(t, got, "deleted property must be gone")
+
+	// The delete is audited with the removed row as its before-image.
+	logs := auditFor(t, seeded.ConfigID)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "DeletePropertyCommand", logs[0].Operation)
+	assert.Contains(t, logs[0].Changes, audit.Change{Path: "value", Before: "mail.example.com"})
+}
+
+func TestDeleteProperty_NotFound(t *testing.T) {
+	t.Parallel()
+	repo := platformconfig.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	_, err := runAuthorized(uow, operations.DeleteProperty(repo), operations.DeletePropertyCommand{
+		ApplicationCode: "pcdel-missing", Section: "smtp", Property: "host",
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindNotFound, "PlatformConfig_NOT_FOUND")
+}
+
+// TestSetProperty_AuditRedactsSecretValue proves a SECRET value never lands
+// in aud_logs.changes while the fact that it changed still does.
+func TestSetProperty_AuditRedactsSecretValue(t *testing.T) {
+	t.Parallel()
+	repo := platformconfig.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	secret := "SECRET"
+	cmd := operations.SetPropertyCommand{
+		ApplicationCode: "pcset-auditsecret", Section: "smtp", Property: "password", Value: "hunter2",
+		ValueType: &secret,
+	}
+	first, err := runAuthorized(uow, operations.SetProperty(repo), cmd)
+	require.NoError(t, err)
+	cmd.Value = "correct-horse"
+	_, err = runAuthorized(uow, operations.SetProperty(repo), cmd)
+	require.NoError(t, err)
+
+	logs := auditFor(t, first.ConfigID)
+	require.Len(t, logs, 2)
+	assert.Contains(t, logs[0].Changes, audit.Change{Path: "value", Before: audit.Redacted, After: audit.Redacted})
+	assert.Contains(t, logs[1].Changes, audit.Change{Path: "value", After: audit.Redacted})
+}
+
+// auditFor returns the audit rows for a config id, newest first.
+func auditFor(t *testing.T, configID string) []audit.Log {
+	t.Helper()
+	logs, err := audit.NewRepository(testpg.Pool(t)).FindWithFilters(context.Background(),
+		audit.FilterParams{EntityID: &configID, Limit: 10})
+	require.NoError(t, err)
+	return logs
+}
+
 // ── GrantAccess ───────────────────────────────────────────────────────────
 
 func TestGrantAccess_HappyPath(t *testing.T) {
@@ -17,6 +17,8 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
 	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/revocation"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
@@ -31,6 +33,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/jsontime"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/versioncache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -70,6 +73,21 @@ type State struct {
 	MFA *mfa.Service
 	// Audit (optional) records the admin 2FA reset to the audit trail.
 	Audit *audit.Repository
+	// Sessions (optional) backs POST /api/principals/{id}/revoke-sessions;
+	// RefreshTokens (optional) lets that endpoint also kill refresh tokens
+	// so a revoked session can't be re-minted.
+	Sessions      *revocation.Checker
+	RefreshTokens *grantstore.RefreshTokenRepository
+	// Backchannel (optional) sends OIDC back-channel logout tokens to the
+	// OAuth clients the principal signed in to when their sessions are
+	// revoked.
+	Backchannel LogoutNotifier
+}
+
+// LogoutNotifier is told when a principal's sessions end. Satisfied by
+// *backchannel.Notifier.
+type LogoutNotifier interface {
+	LoggedOut(ctx context.Context, principalID string)
 }
 
 // InviteEmailer mints a first-time set-password link for a new user. The
@@ -96,6 +114,7 @@ func Register(api huma.API, s *State) {
 	apiroute.Post(g, "resetPrincipalPassword", "/api/principals/{id}/reset-password", "Reset a user's password", http.StatusOK, s.resetPassword)
 	apiroute.Post(g, "sendPrincipalPasswordReset", "/api/principals/{id}/send-password-reset", "Send a password-reset email to a user", http.StatusOK, s.sendPasswordReset)
 	apiroute.Post(g, "resetPrincipalTwoFactor", "/api/principals/{id}/reset-2fa", "Clear a user's two-factor methods (forces re-enrollment)", http.StatusOK, s.resetTwoFactor)
+	apiroute.Post(g, "revokePrincipalSessions", "/api/principals/{id}/revoke-sessions", "Invalidate every session and token a principal currently holds", http.StatusOK, s.revokeSessions)
 	apiroute.Get(g, "checkPrincipalEmailDomain", "/api/principals/check-email-domain", "Resolve auth-method for an email's domain", s.checkEmailDomain)
 	apiroute.Delete(g, "deletePrincipal", "/api/principals/{id}", "Delete a principal", http.StatusNoContent, s.delete)
 	apiroute.Put(g, "assignPrincipalRoles", "/api/principals/{id}/roles", "Assign roles to a principal (replaces full set)", http.StatusOK, s.assignRoles)
@@ -108,6 +127,9 @@ func Register(api huma.API, s *State) {
 	apiroute.Get(g, "listPrincipalClientAccess", "/api/principals/{id}/client-access", "List client-access grants for a principal", s.listClientAccess)
 	apiroute.Post(g, "grantPrincipalClientAccess", "/api/principals/{id}/client-access", "Grant a client-access for a principal", http.StatusOK, s.grantClientAccess)
 	apiroute.Delete(g, "revokePrincipalClientAccess", "/api/principals/{id}/client-access/{clientId}", "Revoke a client-access grant", http.StatusNoContent, s.revokeClientAccess)
+	apiroute.Put(g, "setPrincipalClientAccessExpiry", "/api/principals/{id}/client-access/{clientId}/expiry", "Set or clear a client-access grant's expiry", http.StatusOK, s.setClientAccessExpiry)
+	apiroute.Post(g, "extendPrincipalClientAccess", "/api/principals/{id}/client-access/{clientId}/extend", "Push a client-access grant's expiry out by a number of days", http.StatusOK, s.extendClientAccess)
+	apiroute.Get(g, "listClientAccessGrants", "/api/clients/{id}/client-access", "List the client-access grants to a client", s.listClientGrants)
 	apiroute.Put(g, "setPrincipalClientAssociation", "/api/principals/{id}/client-association", "Change a principal's scope/client association (anchor-gated)", http.StatusOK, s.setClientAssociation)
 	apiroute.Get(g, "listDeveloperUsers", "/api/principals/developer-users", "List USER principals holding the developer role", s.listDeveloperUsers)
 	apiroute.Post(g, "setPrincipalDeveloperCredential", "/api/principals/{id}/developer-credential", "Create or rotate a principal's self-service developer API credential", http.StatusOK, s.setDeveloperCredential)
@@ -365,7 +387,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreatePrincipalRequ
 	if err := auth.RequireUserAdmin(ac, in.Body.ClientID); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateUser(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -399,7 +421,7 @@ func (s *State) bulkImport(ctx context.Context, in *apicommon.In[BulkImportReque
 		return nil, httperror.BadRequest("TOO_MANY", "Import is limited to 1000 users at a time")
 	}
 
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	out := BulkImportResponse{Results: make([]BulkImportResult, 0, len(in.Body.Users))}
 	seen := make(map[string]struct{}, len(in.Body.Users))
 
@@ -535,8 +557,8 @@ func errMessage(err error) string {
 // derives scope + client association from the email domain (anchor-domain check
 // + email-domain-mapping), then delegates to the shared CreateUser operation.
 // Returns the full principal (the SDK reads it back). The SDK's
-// enforcePasswordComplexity is accepted but not enforced (Go's create doesn't
-// apply a complexity policy). Magic-link-on-passwordless-create is intentionally
+// enforcePasswordComplexity selects the configured passwordpolicy (default) or
+// the relaxed floor, as on reset-password. Magic-link-on-passwordless-create is intentionally
 // not ported — the SDK always supplies a password and the reset emailer isn't
 // wired (matching Rust's unconfigured-emailer fallback).
 func (s *State) createUser(ctx context.Context, in *apicommon.In[CreateUserRequest]) (*apicommon.Out[PrincipalResponse], error) {
@@ -590,7 +612,7 @@ func (s *State) createUser(ctx context.Context, in *apicommon.In[CreateUserReque
 		return nil, err
 	}
 
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 
 	// Partner-merge: when a PARTNER user already exists for this email, grant
 	// access to the requested client rather than recreating (keeps events +
@@ -618,12 +640,13 @@ func (s *State) createUser(ctx context.Context, in *apicommon.In[CreateUserReque
 
 	name := in.Body.Name
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateUser(s.Repo), operations.CreateCommand{
-		Email:    email,
-		Name:     &name,
-		Scope:    scope,
-		ClientID: clientID,
-		Password: in.Body.Password,
-		IDPType:  &idpType,
+		Email:                     email,
+		Name:                      &name,
+		Scope:                     scope,
+		ClientID:                  clientID,
+		Password:                  in.Body.Password,
+		IDPType:                   &idpType,
+		EnforcePasswordComplexity: in.Body.EnforcePasswordComplexity,
 	}, ec)
 	if err != nil {
 		return nil, err
@@ -886,7 +909,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Out[Pri
 	if err := auth.CanWritePrincipals(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateUser(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -906,7 +929,7 @@ func (s *State) activate(ctx context.Context, in *apicommon.IDInput) (*apicommon
 	if err := auth.CanWritePrincipals(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ActivateUser(s.Repo), operations.ActivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -919,7 +942,7 @@ func (s *State) deactivate(ctx context.Context, in *apicommon.IDInput) (*apicomm
 	if err := auth.CanWritePrincipals(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeactivateUser(s.Repo), operations.DeactivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -939,7 +962,7 @@ func (s *State) resetPassword(ctx context.Context, in *resetPasswordInput) (*api
 	if err := s.requireScopeByID(ctx, ac, in.ID); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ResetPassword(s.Repo),
 		operations.ResetPasswordCommand{
 			ID:                        in.ID,
@@ -957,7 +980,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeletePrincipals(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteUser(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -1006,7 +1029,7 @@ func (s *State) assignRoles(ctx context.Context, in *assignRolesInput) (*apicomm
 	added := setDifference(desired, old)
 	removed := setDifference(old, desired)
 
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.AssignRoles(s.Repo, s.Roles),
 		operations.AssignRolesCommand{UserID: in.ID, Roles: effectiveRoles}, ec); err != nil {
 		return nil, err
@@ -1065,7 +1088,7 @@ func (s *State) assignApplicationAccess(ctx context.Context, in *assignAppAccess
 	added := len(setDifference(desired, old))
 	removed := len(setDifference(old, desired))
 
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.AssignApplicationAccess(s.Repo, s.Applications),
 		operations.AssignApplicationAccessCommand{
 			UserID:          in.ID,
@@ -1116,21 +1139,84 @@ func (s *State) grantClientAccess(ctx context.Context, in *grantClientAccessInpu
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.GrantClientAccess(s.Repo, s.Clients, s.GrantRepo),
-		operations.GrantClientAccessCommand{UserID: in.ID, ClientID: in.Body.ClientID}, ec); err != nil {
+		operations.GrantClientAccessCommand{UserID: in.ID, ClientID: in.Body.ClientID, ExpiresAt: in.Body.ExpiresAt}, ec); err != nil {
+		return nil, err
+	}
+	return s.grantOut(ctx, in.ID, in.Body.ClientID)
+}
+
+type clientAccessExpiryInput struct {
+	ID       string `path:"id"`
+	ClientID string `path:"clientId"`
+	Body     SetClientAccessExpiryRequest
+}
+
+func (s *State) setClientAccessExpiry(ctx context.Context, in *clientAccessExpiryInput) (*apicommon.Out[ClientAccessGrantResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	g, err := s.GrantRepo.FindByPrincipalAndClient(ctx, in.ID, in.Body.ClientID)
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.SetClientAccessExpiry(s.GrantRepo),
+		operations.SetClientAccessExpiryCommand{UserID: in.ID, ClientID: in.ClientID, ExpiresAt: in.Body.ExpiresAt}, ec); err != nil {
+		return nil, err
+	}
+	return s.grantOut(ctx, in.ID, in.ClientID)
+}
+
+type extendClientAccessInput struct {
+	ID       string `path:"id"`
+	ClientID string `path:"clientId"`
+	Body     ExtendClientAccessRequest
+}
+
+func (s *State) extendClientAccess(ctx context.Context, in *extendClientAccessInput) (*apicommon.Out[ClientAccessGrantResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.ExtendClientAccess(s.GrantRepo),
+		operations.ExtendClientAccessCommand{UserID: in.ID, ClientID: in.ClientID, By: time.Duration(in.Body.Days) * 24 * time.Hour}, ec); err != nil {
+		return nil, err
+	}
+	return s.grantOut(ctx, in.ID, in.ClientID)
+}
+
+// grantOut reloads a grant an op just wrote.
+func (s *State) grantOut(ctx context.Context, principalID, clientID string) (*apicommon.Out[ClientAccessGrantResponse], error) {
+	g, err := s.GrantRepo.FindByPrincipalAndClient(ctx, principalID, clientID)
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find grant failed", err)
 	}
 	if g == nil {
-		return nil, usecase.Internal("REPO", "grant not found after create", nil)
+		return nil, usecase.Internal("REPO", "grant not found after write", nil)
 	}
 	return &apicommon.Out[ClientAccessGrantResponse]{Body: clientAccessGrantFromEntity(g)}, nil
 }
 
+// listClientGrants lists who holds a grant to the client — the reverse of
+// listClientAccess. Lapsed grants the purger hasn't removed yet are
+// included, flagged expired.
+func (s *State) listClientGrants(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[ClientAccessGrantListResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	c, err := s.Clients.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_client failed", err)
+	}
+	if c == nil {
+		return nil, httperror.NotFound("Client", in.ID)
+	}
+	grants, err := s.GrantRepo.FindByClient(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "list grants failed", err)
+	}
+	out := apicommon.MapSlice(grants, clientAccessGrantFromEntity)
+	return &apicommon.Out[ClientAccessGrantListResponse]{Body: ClientAccessGrantListResponse{Grants: out}}, nil
+}
+
 type setClientAssociationInput struct {
 	ID   string `path:"id"`
 	Body ClientAssociationRequest
@@ -1146,7 +1232,7 @@ func (s *State) setClientAssociation(ctx context.Context, in *setClientAssociati
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	mode := ""
 	if in.Body.Mode != nil {
 		mode = strings.ToUpper(strings.TrimSpace(*in.Body.Mode))
@@ -1180,7 +1266,7 @@ func (s *State) revokeClientAccess(ctx context.Context, in *revokeClientAccessIn
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.RevokeClientAccess(s.Repo, s.GrantRepo),
 		operations.RevokeClientAccessCommand{UserID: in.ID, ClientID: in.ClientID}, ec); err != nil {
 		return nil, err
@@ -1218,7 +1304,7 @@ func (s *State) listDeveloperUsers(ctx context.Context, _ *apicommon.Empty) (*ap
 // their own id needs only the developer role; a caller acting on someone
 // else's id needs user-admin rights over that principal's client).
 func (s *State) setDeveloperCredential(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[SetDeveloperCredentialResponse], error) {
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, operations.SetDeveloperCredential(s.Repo),
 		operations.SetDeveloperCredentialCommand{PrincipalID: in.ID}, ec)
 	if err != nil {
@@ -1235,7 +1321,7 @@ func (s *State) setDeveloperCredential(ctx context.Context, in *apicommon.IDInpu
 // client_credentials secret without touching their role assignment. Same
 // self-or-admin shape as setDeveloperCredential.
 func (s *State) revokeDeveloperCredential(ctx context.Context, in *apicommon.IDInput) (*apicommon.Empty, error) {
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.RevokeDeveloperCredential(s.Repo),
 		operations.RevokeDeveloperCredentialCommand{PrincipalID: in.ID}, ec); err != nil {
 		return nil, err
@@ -1276,7 +1362,7 @@ func (s *State) sendPasswordReset(ctx context.Context, in *sendPasswordResetInpu
 	if in.Body != nil {
 		reset2FA = in.Body.Reset2FA
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if err := operations.SendPasswordReset(ctx, s.Repo, s.PasswordEmailer,
 		operations.SendPasswordResetCommand{ID: in.ID, Reset2FA: reset2FA}, ec); err != nil {
 		return nil, err
@@ -1328,6 +1414,54 @@ func (s *State) resetTwoFactor(ctx context.Context, in *apicommon.IDInput) (*api
 	return &apicommon.Out[apicommon.StatusChangeResponse]{Body: apicommon.StatusChangeResponse{Message: "Two-factor authentication reset"}}, nil
 }
 
+// revokeSessions invalidates every platform token the principal holds right
+// now (session cookies, access tokens, refresh tokens) — e.g. on offboarding
+// — and logs them out of the OAuth clients they signed in to. Tokens issued
+// afterwards are unaffected. Anchor or a client-administrator
+// of the principal's client.
+func (s *State) revokeSessions(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[apicommon.StatusChangeResponse], error) {
+	ac := auth.FromContext(ctx)
+	if s.Sessions == nil {
+		return nil, usecase.Internal("SESSIONS_NOT_CONFIGURED", "Session revocation not configured", nil)
+	}
+	p, err := s.Repo.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if p == nil {
+		return nil, httperror.NotFound("Principal", in.ID)
+	}
+	if err := auth.RequireUserAdmin(ac, p.ClientID); err != nil {
+		return nil, err
+	}
+	if err := blockNonClientTarget(ac, p); err != nil {
+		return nil, err
+	}
+	actor := ac.PrincipalID
+	if err := s.Sessions.RevokeAll(ctx, p.ID, &actor); err != nil {
+		return nil, usecase.Internal("REVOCATION", "revoke sessions failed", err)
+	}
+	if s.RefreshTokens != nil {
+		if _, err := s.RefreshTokens.RevokeAllForPrincipal(ctx, p.ID); err != nil {
+			return nil, usecase.Internal("REFRESH_TOKENS", "revoke refresh tokens failed", err)
+		}
+	}
+	if s.Backchannel != nil {
+		s.Backchannel.LoggedOut(ctx, p.ID)
+	}
+	if s.Audit != nil {
+		_ = s.Audit.Insert(ctx, &audit.Log{
+			ID:          tsid.Generate(tsid.AuditLog),
+			EntityType:  "PRINCIPAL",
+			EntityID:    p.ID,
+			Operation:   "SESSIONS_REVOKED_BY_ADMIN",
+			PrincipalID: &actor,
+			PerformedAt: time.Now().UTC(),
+		})
+	}
+	return &apicommon.Out[apicommon.StatusChangeResponse]{Body: apicommon.StatusChangeResponse{Message: "Sessions revoked"}}, nil
+}
+
 // ── check-email-domain (admin) ───────────────────────────────────────────
 
 type checkEmailDomainInput struct {
@@ -1526,7 +1660,7 @@ func (s *State) addRole(ctx context.Context, in *addRoleInput) (*apicommon.Out[P
 	roles := uniqueRoleNames(p.Roles)
 	if _, ok := roles[in.Body.Role]; !ok { // skip mutation when already present (idempotent)
 		desired := append(roleNamesFrom(p.Roles), in.Body.Role)
-		ec := auth.NewExecutionContext(ctx)
+		ec := reqctx.ExecutionContext(ctx)
 		if _, err := usecaseop.Run(ctx, s.UoW, operations.AssignRoles(s.Repo, s.Roles),
 			operations.AssignRolesCommand{UserID: in.ID, Roles: desired}, ec); err != nil {
 			return nil, err
@@ -1580,7 +1714,7 @@ func (s *State) removeRole(ctx context.Context, in *removeRoleInput) (*apicommon
 		desired = append(desired, r)
 	}
 	if found { // skip mutation when absent (idempotent)
-		ec := auth.NewExecutionContext(ctx)
+		ec := reqctx.ExecutionContext(ctx)
 		if _, err := usecaseop.Run(ctx, s.UoW, operations.AssignRoles(s.Repo, s.Roles),
 			operations.AssignRolesCommand{UserID: in.ID, Roles: desired}, ec); err != nil {
 			return nil, err
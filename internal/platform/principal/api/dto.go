@@ -3,6 +3,7 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/operations"
@@ -101,7 +102,20 @@ type AssignApplicationAccessRequest struct {
 // GrantClientAccessRequest is the wire body for
 // POST /api/principals/{id}/client-access.
 type GrantClientAccessRequest struct {
-	ClientID string `json:"clientId"`
+	ClientID  string     `json:"clientId"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" doc:"When the grant lapses (RFC 3339, in the future); omit for open-ended access"`
+}
+
+// SetClientAccessExpiryRequest is the wire body for
+// PUT /api/principals/{id}/client-access/{clientId}/expiry.
+type SetClientAccessExpiryRequest struct {
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" doc:"New expiry (RFC 3339, in the future); omit or null to make the grant open-ended"`
+}
+
+// ExtendClientAccessRequest is the wire body for
+// POST /api/principals/{id}/client-access/{clientId}/extend.
+type ExtendClientAccessRequest struct {
+	Days uint32 `json:"days" minimum:"1" maximum:"366" doc:"Days to push the grant's current expiry out by"`
 }
 
 // ClientAssociationRequest is the wire body for
@@ -234,23 +248,37 @@ type SetDeveloperCredentialResponse struct {
 
 // ClientAccessGrantResponse is the wire shape for a single client-access
 // grant. Matches the Rust platform + fcsdk client + SPA.
+// Expired marks a grant past its expiry that the purger hasn't removed
+// yet; it no longer counts towards the principal's clients.
 type ClientAccessGrantResponse struct {
-	ID        string           `json:"id"`
-	ClientID  string           `json:"clientId"`
-	GrantedAt httpcompat.Time  `json:"grantedAt"`
-	ExpiresAt *httpcompat.Time `json:"expiresAt,omitempty"`
+	ID          string           `json:"id"`
+	PrincipalID string           `json:"principalId"`
+	ClientID    string           `json:"clientId"`
+	GrantedBy   string           `json:"grantedBy"`
+	GrantedAt   httpcompat.Time  `json:"grantedAt"`
+	ExpiresAt   *httpcompat.Time `json:"expiresAt,omitempty"`
+	Expired     bool             `json:"expired"`
 }
 
 func clientAccessGrantFromEntity(g *principal.ClientAccessGrant) ClientAccessGrantResponse {
-	return ClientAccessGrantResponse{
-		ID:        g.ID,
-		ClientID:  g.ClientID,
-		GrantedAt: jsontime.New(g.GrantedAt),
+	out := ClientAccessGrantResponse{
+		ID:          g.ID,
+		PrincipalID: g.PrincipalID,
+		ClientID:    g.ClientID,
+		GrantedBy:   g.GrantedBy,
+		GrantedAt:   jsontime.New(g.GrantedAt),
+		Expired:     g.ExpiredAt(time.Now()),
+	}
+	if g.ExpiresAt != nil {
+		v := jsontime.New(*g.ExpiresAt)
+		out.ExpiresAt = &v
 	}
+	return out
 }
 
 // ClientAccessGrantListResponse is the wire shape for
-// GET /api/principals/{id}/client-access.
+// GET /api/principals/{id}/client-access and
+// GET /api/clients/{id}/client-access.
 type ClientAccessGrantListResponse struct {
 	Grants []ClientAccessGrantResponse `json:"grants"`
 }
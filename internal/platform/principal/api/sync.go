@@ -6,7 +6,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
-	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 )
 
@@ -61,7 +61,7 @@ func (s *State) syncUsers(ctx context.Context, in *apicommon.In[SyncUsersRequest
 		})
 	}
 
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, operations.SyncPrincipals(s.Repo),
 		operations.SyncPrincipalsCommand{Principals: inputs}, ec)
 	if err != nil {
@@ -17,19 +17,66 @@ import (
 // row that records a PARTNER user's access to a specific client. Each grant
 // is its own aggregate (UoW-managed) so the grant/revoke ops emit the
 // matching iam:user:client-access-* events.
+//
+// ExpiresAt, when set, bounds the grant: past it the grant no longer
+// counts towards the principal's clients, and the platform's purger
+// deletes it (ExpireClientAccess).
 type ClientAccessGrant struct {
-	ID          string    `json:"id"`
-	PrincipalID string    `json:"principalId"`
-	ClientID    string    `json:"clientId"`
-	GrantedBy   string    `json:"grantedBy"`
-	GrantedAt   time.Time `json:"grantedAt"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string     `json:"id"`
+	PrincipalID string     `json:"principalId"`
+	ClientID    string     `json:"clientId"`
+	GrantedBy   string     `json:"grantedBy"`
+	GrantedAt   time.Time  `json:"grantedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
 }
 
 // IDStr satisfies usecase.HasID.
 func (g ClientAccessGrant) IDStr() string { return g.ID }
 
+// ExpiredAt reports whether the grant has lapsed at now.
+func (g ClientAccessGrant) ExpiredAt(now time.Time) bool {
+	return g.ExpiresAt != nil && !g.ExpiresAt.After(now)
+}
+
+// ClientAccess is a principal's effective client access: every client
+// (AllClients) for anchors, else the home client plus the live grants.
+type ClientAccess struct {
+	AllClients bool                `json:"allClients"`
+	Clients    []ClientAccessEntry `json:"clients"`
+}
+
+// ClientAccessEntry is one accessible client. Source is "HOME" or
+// "GRANT"; ExpiresAt is set for an expiring grant.
+type ClientAccessEntry struct {
+	ClientID  string     `json:"clientId"`
+	Source    string     `json:"source"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// EffectiveClientAccess resolves p's client access at now from its grants,
+// skipping lapsed ones.
+func EffectiveClientAccess(p *Principal, grants []ClientAccessGrant, now time.Time) ClientAccess {
+	if p.Scope.IsAnchor() {
+		return ClientAccess{AllClients: true, Clients: []ClientAccessEntry{}}
+	}
+	out := ClientAccess{Clients: []ClientAccessEntry{}}
+	seen := map[string]bool{}
+	if p.ClientID != nil {
+		out.Clients = append(out.Clients, ClientAccessEntry{ClientID: *p.ClientID, Source: "HOME"})
+		seen[*p.ClientID] = true
+	}
+	for _, g := range grants {
+		if g.ExpiredAt(now) || seen[g.ClientID] {
+			continue
+		}
+		seen[g.ClientID] = true
+		out.Clients = append(out.Clients, ClientAccessEntry{ClientID: g.ClientID, Source: "GRANT", ExpiresAt: g.ExpiresAt})
+	}
+	return out
+}
+
 // NewClientAccessGrant constructs a new grant row.
 func NewClientAccessGrant(principalID, clientID, grantedBy string) *ClientAccessGrant {
 	now := time.Now().UTC()
@@ -55,7 +102,10 @@ func NewClientAccessGrantRepo(pool *pgxpool.Pool) *ClientAccessGrantRepo {
 }
 
 const grantSelect = `SELECT id, principal_id, client_id, granted_by, granted_at,
-	created_at, updated_at FROM iam_client_access_grants`
+	expires_at, created_at, updated_at FROM iam_client_access_grants`
+
+// grantActive is the predicate for grants that haven't lapsed.
+const grantActive = `(expires_at IS NULL OR expires_at > NOW())`
 
 // FindByPrincipalAndClient returns the existing grant (nil if none).
 func (r *ClientAccessGrantRepo) FindByPrincipalAndClient(ctx context.Context, principalID, clientID string) (*ClientAccessGrant, error) {
@@ -71,12 +121,29 @@ func (r *ClientAccessGrantRepo) FindByPrincipalAndClient(ctx context.Context, pr
 	return scanGrant(rows)
 }
 
-// FindByPrincipal lists all grants for a principal.
+// FindByPrincipal lists all grants for a principal, including lapsed
+// ones the purger hasn't removed yet.
 func (r *ClientAccessGrantRepo) FindByPrincipal(ctx context.Context, principalID string) ([]ClientAccessGrant, error) {
-	rows, err := r.pool.Query(ctx,
-		grantSelect+` WHERE principal_id = $1 ORDER BY granted_at`, principalID)
+	return r.query(ctx, grantSelect+` WHERE principal_id = $1 ORDER BY granted_at`, principalID)
+}
+
+// FindByClient lists all grants to a client, including lapsed ones the
+// purger hasn't removed yet.
+func (r *ClientAccessGrantRepo) FindByClient(ctx context.Context, clientID string) ([]ClientAccessGrant, error) {
+	return r.query(ctx, grantSelect+` WHERE client_id = $1 ORDER BY granted_at`, clientID)
+}
+
+// FindExpired returns up to limit grants that lapsed at or before now,
+// oldest expiry first.
+func (r *ClientAccessGrantRepo) FindExpired(ctx context.Context, now time.Time, limit int) ([]ClientAccessGrant, error) {
+	return r.query(ctx,
+		grantSelect+` WHERE expires_at IS NOT NULL AND expires_at <= $1 ORDER BY expires_at LIMIT $2`, now, limit)
+}
+
+func (r *ClientAccessGrantRepo) query(ctx context.Context, sql string, args ...any) ([]ClientAccessGrant, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("client_access_grant repo: %w", err)
 	}
 	defer rows.Close()
 	var out []ClientAccessGrant
@@ -95,12 +162,14 @@ func (r *ClientAccessGrantRepo) Persist(ctx context.Context, g *ClientAccessGran
 	now := time.Now().UTC()
 	_, err := tx.Inner().Exec(ctx,
 		`INSERT INTO iam_client_access_grants
-		     (id, principal_id, client_id, granted_by, granted_at, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		     (id, principal_id, client_id, granted_by, granted_at, expires_at, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		 ON CONFLICT (id) DO UPDATE SET
 		     granted_by = EXCLUDED.granted_by,
+		     granted_at = EXCLUDED.granted_at,
+		     expires_at = EXCLUDED.expires_at,
 		     updated_at = EXCLUDED.updated_at`,
-		g.ID, g.PrincipalID, g.ClientID, g.GrantedBy, g.GrantedAt, g.CreatedAt, now)
+		g.ID, g.PrincipalID, g.ClientID, g.GrantedBy, g.GrantedAt, g.ExpiresAt, g.CreatedAt, now)
 	return err
 }
 
@@ -110,10 +179,34 @@ func (r *ClientAccessGrantRepo) Delete(ctx context.Context, g *ClientAccessGrant
 	return err
 }
 
+// ErrGrantNotLapsed is returned by LapsedGrantDeleter when the grant is
+// gone or no longer past its expiry.
+var ErrGrantNotLapsed = errors.New("client access grant is not lapsed")
+
+// LapsedGrantDeleter adapts the repo so usecaseop.Delete only removes a
+// grant that is still past its expiry. Every node runs the purger; the
+// conditional DELETE makes the loser of a race (or a purge racing an
+// extension) roll back instead of recording a second expiry.
+type LapsedGrantDeleter struct{ *ClientAccessGrantRepo }
+
+// Delete removes the grant if it has lapsed, else returns
+// ErrGrantNotLapsed.
+func (d LapsedGrantDeleter) Delete(ctx context.Context, g *ClientAccessGrant, tx *usecasepgx.DbTx) error {
+	tag, err := tx.Inner().Exec(ctx,
+		`DELETE FROM iam_client_access_grants WHERE id = $1 AND expires_at <= NOW()`, g.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGrantNotLapsed
+	}
+	return nil
+}
+
 func scanGrant(rows pgx.Rows) (*ClientAccessGrant, error) {
 	var g ClientAccessGrant
 	if err := rows.Scan(&g.ID, &g.PrincipalID, &g.ClientID, &g.GrantedBy,
-		&g.GrantedAt, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		&g.GrantedAt, &g.ExpiresAt, &g.CreatedAt, &g.UpdatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
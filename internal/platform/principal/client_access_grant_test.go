@@ -0,0 +1,41 @@
+package principal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientAccessGrant_ExpiredAt(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	assert.False(t, ClientAccessGrant{}.ExpiredAt(now), "no expiry never lapses")
+	assert.False(t, ClientAccessGrant{ExpiresAt: &future}.ExpiredAt(now))
+	assert.True(t, ClientAccessGrant{ExpiresAt: &now}.ExpiredAt(now), "the expiry instant itself is lapsed")
+	assert.True(t, ClientAccessGrant{ExpiresAt: &past}.ExpiredAt(now))
+}
+
+func TestEffectiveClientAccess(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	home := "clt_home"
+	grants := []ClientAccessGrant{
+		{ClientID: "clt_open"},
+		{ClientID: "clt_expiring", ExpiresAt: &future},
+		{ClientID: "clt_lapsed", ExpiresAt: &past},
+		{ClientID: home},
+	}
+
+	got := EffectiveClientAccess(&Principal{Scope: ScopePartner, ClientID: &home}, grants, now)
+	assert.False(t, got.AllClients)
+	assert.Equal(t, []ClientAccessEntry{
+		{ClientID: home, Source: "HOME"},
+		{ClientID: "clt_open", Source: "GRANT"},
+		{ClientID: "clt_expiring", Source: "GRANT", ExpiresAt: &future},
+	}, got.Clients)
+
+	anchor := EffectiveClientAccess(&Principal{Scope: ScopeAnchor}, grants, now)
+	assert.True(t, anchor.AllClients)
+	assert.Empty(t, anchor.Clients)
+}
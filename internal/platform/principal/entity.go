@@ -91,6 +91,10 @@ type UserIdentity struct {
 	Provider      *string    `json:"provider,omitempty"`
 	PasswordHash  *string    `json:"passwordHash,omitempty"`
 	LastLoginAt   *time.Time `json:"lastLoginAt,omitempty"`
+	// PasswordChangedAt is when the user last set their password; drives
+	// passwordpolicy expiry. Nil for passwordless users and rows that
+	// predate tracking.
+	PasswordChangedAt *time.Time `json:"passwordChangedAt,omitempty"`
 	// DevClientSecretRef is the encrypted client_credentials secret for the
 	// self-service developer-token flow — distinct from PasswordHash, never
 	// used for interactive login. Nil = no developer credential set.
@@ -209,13 +213,16 @@ func (p *Principal) Deactivate() {
 	p.UpdatedAt = time.Now().UTC()
 }
 
-// SetPasswordHash updates the password hash on the user identity.
+// SetPasswordHash updates the password hash on the user identity and
+// restarts its expiry clock.
 func (p *Principal) SetPasswordHash(hash string) {
 	if p.UserIdentity == nil {
 		p.UserIdentity = NewUserIdentity("")
 	}
+	now := time.Now().UTC()
 	p.UserIdentity.PasswordHash = &hash
-	p.UpdatedAt = time.Now().UTC()
+	p.UserIdentity.PasswordChangedAt = &now
+	p.UpdatedAt = now
 }
 
 // SetDevClientSecretRef sets (or rotates) the encrypted developer
@@ -0,0 +1,178 @@
+package operations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// MaxClientAccessExtension caps a single ExtendClientAccess.
+const MaxClientAccessExtension = 366 * 24 * time.Hour
+
+type SetClientAccessExpiryCommand struct {
+	UserID   string `json:"userId"`
+	ClientID string `json:"clientId"`
+	// ExpiresAt is the new expiry; nil makes the grant open-ended.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// SetClientAccessExpiry sets or clears the expiry of a live client-access
+// grant and emits [ClientAccessExpiryChanged]. A lapsed grant can't be
+// revived this way — grant it again.
+//
+// Authorize is intentionally Public: the only caller is the admin endpoint,
+// gated by auth.RequireAnchor at the controller.
+func SetClientAccessExpiry(grants *principal.ClientAccessGrantRepo) usecaseop.Operation[SetClientAccessExpiryCommand, ClientAccessExpiryChanged] {
+	return usecaseop.Operation[SetClientAccessExpiryCommand, ClientAccessExpiryChanged]{
+		Name: "SetClientAccessExpiry",
+		Validate: func(_ context.Context, cmd SetClientAccessExpiryCommand) error {
+			if err := validateGrantKey(cmd.UserID, cmd.ClientID); err != nil {
+				return err
+			}
+			return validateExpiry(cmd.ExpiresAt)
+		},
+		Authorize: usecaseop.Public[SetClientAccessExpiryCommand],
+		Execute: func(ctx context.Context, cmd SetClientAccessExpiryCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ClientAccessExpiryChanged], error) {
+			grant, err := liveGrant(ctx, grants, cmd.UserID, cmd.ClientID)
+			if err != nil {
+				return nil, err
+			}
+			previous := grant.ExpiresAt
+			grant.ExpiresAt = cmd.ExpiresAt
+			return usecaseop.Save(grant, grants, expiryChanged(ec, grant, previous)), nil
+		},
+	}
+}
+
+type ExtendClientAccessCommand struct {
+	UserID   string        `json:"userId"`
+	ClientID string        `json:"clientId"`
+	By       time.Duration `json:"by"`
+}
+
+// ExtendClientAccess pushes a live, expiring grant's expiry out by By and
+// emits [ClientAccessExpiryChanged]. An open-ended grant has nothing to
+// extend (GRANT_NOT_EXPIRING).
+//
+// Authorize is intentionally Public: the only caller is the admin endpoint,
+// gated by auth.RequireAnchor at the controller.
+func ExtendClientAccess(grants *principal.ClientAccessGrantRepo) usecaseop.Operation[ExtendClientAccessCommand, ClientAccessExpiryChanged] {
+	return usecaseop.Operation[ExtendClientAccessCommand, ClientAccessExpiryChanged]{
+		Name: "ExtendClientAccess",
+		Validate: func(_ context.Context, cmd ExtendClientAccessCommand) error {
+			if err := validateGrantKey(cmd.UserID, cmd.ClientID); err != nil {
+				return err
+			}
+			if cmd.By <= 0 || cmd.By > MaxClientAccessExtension {
+				return usecase.Validation("INVALID_EXTENSION", "An extension must be positive and at most 366 days")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[ExtendClientAccessCommand],
+		Execute: func(ctx context.Context, cmd ExtendClientAccessCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ClientAccessExpiryChanged], error) {
+			grant, err := liveGrant(ctx, grants, cmd.UserID, cmd.ClientID)
+			if err != nil {
+				return nil, err
+			}
+			if grant.ExpiresAt == nil {
+				return nil, usecase.BusinessRule("GRANT_NOT_EXPIRING", "The grant has no expiry to extend")
+			}
+			previous := grant.ExpiresAt
+			extended := previous.Add(cmd.By)
+			grant.ExpiresAt = &extended
+			return usecaseop.Save(grant, grants, expiryChanged(ec, grant, previous)), nil
+		},
+	}
+}
+
+type ExpireClientAccessCommand struct {
+	UserID   string `json:"userId"`
+	ClientID string `json:"clientId"`
+}
+
+// ExpireClientAccess deletes a lapsed client-access grant and emits
+// [ClientAccessExpired], the audit record of access that ended without a
+// revoke. The platform's purger runs it for every grant past its expiry.
+// The delete goes through principal.LapsedGrantDeleter, so a grant
+// extended (or expired by another node) since it was read is left alone:
+// the op then fails wrapping principal.ErrGrantNotLapsed.
+//
+// Authorize is intentionally Public: the caller is the purger, acting as
+// the system.
+func ExpireClientAccess(grants *principal.ClientAccessGrantRepo) usecaseop.Operation[ExpireClientAccessCommand, ClientAccessExpired] {
+	return usecaseop.Operation[ExpireClientAccessCommand, ClientAccessExpired]{
+		Name: "ExpireClientAccess",
+		Validate: func(_ context.Context, cmd ExpireClientAccessCommand) error {
+			return validateGrantKey(cmd.UserID, cmd.ClientID)
+		},
+		Authorize: usecaseop.Public[ExpireClientAccessCommand],
+		Execute: func(ctx context.Context, cmd ExpireClientAccessCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ClientAccessExpired], error) {
+			grant, err := grants.FindByPrincipalAndClient(ctx, cmd.UserID, cmd.ClientID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_grant failed", err)
+			}
+			if grant == nil {
+				return nil, httperror.NotFound("Grant", cmd.UserID+":"+cmd.ClientID)
+			}
+			if !grant.ExpiredAt(time.Now()) {
+				return nil, usecase.BusinessRule("GRANT_NOT_EXPIRED", "The grant has not expired")
+			}
+			event := ClientAccessExpired{
+				Metadata:  usecase.NewEventMetadata(ec, ClientAccessExpiredType, Source, subjectFor(grant.PrincipalID)),
+				UserID:    grant.PrincipalID,
+				ClientID:  grant.ClientID,
+				ExpiredAt: *grant.ExpiresAt,
+			}
+			return usecaseop.Delete(grant, principal.LapsedGrantDeleter{ClientAccessGrantRepo: grants}, event), nil
+		},
+	}
+}
+
+func validateGrantKey(userID, clientID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return usecase.Validation("USER_ID_REQUIRED", "User ID is required")
+	}
+	if strings.TrimSpace(clientID) == "" {
+		return usecase.Validation("CLIENT_ID_REQUIRED", "Client ID is required")
+	}
+	return nil
+}
+
+// validateExpiry refuses an expiry that has already passed.
+func validateExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return usecase.Validation("EXPIRY_IN_PAST", "expiresAt must be in the future")
+	}
+	return nil
+}
+
+// liveGrant loads the (principal, client) grant, refusing a missing or
+// lapsed one.
+func liveGrant(ctx context.Context, grants *principal.ClientAccessGrantRepo, userID, clientID string) (*principal.ClientAccessGrant, error) {
+	grant, err := grants.FindByPrincipalAndClient(ctx, userID, clientID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_grant failed", err)
+	}
+	if grant == nil {
+		return nil, httperror.NotFound("Grant", userID+":"+clientID)
+	}
+	if grant.ExpiredAt(time.Now()) {
+		return nil, usecase.BusinessRule("GRANT_EXPIRED", "The grant has expired; grant access again instead")
+	}
+	return grant, nil
+}
+
+func expiryChanged(ec usecase.ExecutionContext, g *principal.ClientAccessGrant, previous *time.Time) ClientAccessExpiryChanged {
+	return ClientAccessExpiryChanged{
+		Metadata:          usecase.NewEventMetadata(ec, ClientAccessExpiryChangedType, Source, subjectFor(g.PrincipalID)),
+		UserID:            g.PrincipalID,
+		ClientID:          g.ClientID,
+		ExpiresAt:         g.ExpiresAt,
+		PreviousExpiresAt: previous,
+	}
+}
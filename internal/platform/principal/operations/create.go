@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordpolicy"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
@@ -21,6 +22,9 @@ type CreateCommand struct {
 	ClientID *string `json:"clientId,omitempty"`
 	Password *string `json:"password,omitempty"`
 	IDPType  *string `json:"idpType,omitempty"`
+	// EnforcePasswordComplexity behaves as on ResetPasswordCommand: nil or
+	// true applies the configured passwordpolicy, false only the relaxed floor.
+	EnforcePasswordComplexity *bool `json:"enforcePasswordComplexity,omitempty"`
 }
 
 // CreateUser creates a user principal and emits [UserCreated].
@@ -51,6 +55,15 @@ func CreateUser(repo *principal.Repository) usecaseop.Operation[CreateCommand, U
 			if (cmd.Scope == "CLIENT" || cmd.Scope == "PARTNER") && cmd.ClientID == nil {
 				return usecase.Validation("CLIENT_REQUIRED", "clientId is required for PARTNER or CLIENT scope")
 			}
+			// A password supplied for an OIDC user is discarded in Execute, so
+			// it isn't held to the local policy.
+			if cmd.Password != nil && *cmd.Password != "" && (cmd.IDPType == nil || *cmd.IDPType != "OIDC") {
+				policy := passwordpolicy.Current()
+				if cmd.EnforcePasswordComplexity != nil && !*cmd.EnforcePasswordComplexity {
+					policy = policy.Relaxed()
+				}
+				return policy.Check(*cmd.Password)
+			}
 			return nil
 		},
 		Authorize: usecaseop.Public[CreateCommand],
@@ -4,14 +4,13 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
@@ -150,23 +149,16 @@ func RevokeDeveloperCredential(repo *principal.Repository) usecaseop.Operation[R
 // generateDevClientSecret mirrors serviceaccount/operations.generateOAuthClientSecret
 // (duplicated rather than cross-imported — this package already follows that
 // convention for small resource-checks like blockNonClientTarget): 32 random
-// bytes, base64url plaintext, encrypted via the same encryption.Service OAuth
-// client secrets use, so /oauth/token's verifyClientSecret can decrypt+compare
-// either kind of secret through one shared helper.
+// bytes, base64url plaintext, hashed with argon2id like OAuth client
+// secrets, so /oauth/token's verifyClientSecret checks either kind of
+// secret through one shared helper.
 func generateDevClientSecret() (plaintext, ref string, err error) {
 	b := make([]byte, 32)
 	if _, err = rand.Read(b); err != nil {
 		return "", "", err
 	}
 	plaintext = base64.RawURLEncoding.EncodeToString(b)
-	enc, err := encryption.FromEnv()
-	if err != nil {
-		return "", "", err
-	}
-	if enc == nil {
-		return "", "", errors.New("FLOWCATALYST_APP_KEY not configured; cannot encrypt developer client secret")
-	}
-	ref, err = enc.Encrypt(plaintext)
+	ref, err = passwordhash.Hash(plaintext)
 	if err != nil {
 		return "", "", err
 	}
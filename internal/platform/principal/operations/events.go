@@ -18,6 +18,8 @@ const (
 	ApplicationAccessType          = "platform:iam:user:application-access-assigned"
 	ClientAccessGrantedType        = "platform:iam:user:client-access-granted"
 	ClientAccessRevokedType        = "platform:iam:user:client-access-revoked"
+	ClientAccessExpiryChangedType  = "platform:iam:user:client-access-expiry-changed"
+	ClientAccessExpiredType        = "platform:iam:user:client-access-expired"
 	PrincipalsSyncedType           = "platform:iam:principals:synced"
 	DeveloperCredentialSetType     = "platform:iam:user:developer-credential-set"
 	DeveloperCredentialRevokedType = "platform:iam:user:developer-credential-revoked"
@@ -32,6 +34,17 @@ func groupFor(id string) string   { return "platform:principal:" + id }
 // DomainEvent `PrincipalID()` method, which returns the actor (the
 // authenticated principal who performed the action) from Metadata.
 
+// NewUserCreatedEvent builds the created event with the canonical subject.
+// Exported so cross-aggregate orchestrations (e.g. client onboarding) can
+// emit it inside their own transaction.
+func NewUserCreatedEvent(ec usecase.ExecutionContext, userID, email string) UserCreated {
+	return UserCreated{
+		Metadata: usecase.NewEventMetadata(ec, UserCreatedType, Source, subjectFor(userID)),
+		UserID:   userID,
+		Email:    email,
+	}
+}
+
 type UserCreated struct {
 	Metadata usecase.EventMetadata
 	UserID   string
@@ -282,11 +295,12 @@ func (e ApplicationAccessAssigned) ToDataJSON() ([]byte, error) {
 }
 
 // ClientAccessGranted — emitted when a PARTNER user is granted access
-// to a specific client.
+// to a specific client. ExpiresAt is nil for an open-ended grant.
 type ClientAccessGranted struct {
-	Metadata usecase.EventMetadata
-	UserID   string
-	ClientID string
+	Metadata  usecase.EventMetadata
+	UserID    string
+	ClientID  string
+	ExpiresAt *time.Time
 }
 
 func (e ClientAccessGranted) EventID() string       { return e.Metadata.EventID }
@@ -302,9 +316,10 @@ func (e ClientAccessGranted) ExecutionID() string   { return e.Metadata.Executio
 func (e ClientAccessGranted) MessageGroup() string  { return groupFor(e.UserID) }
 func (e ClientAccessGranted) ToDataJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		PrincipalID string `json:"principalId"`
-		ClientID    string `json:"clientId"`
-	}{e.UserID, e.ClientID})
+		PrincipalID string     `json:"principalId"`
+		ClientID    string     `json:"clientId"`
+		ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	}{e.UserID, e.ClientID, e.ExpiresAt})
 }
 
 // ClientAccessRevoked — emitted when a PARTNER user loses access to a
@@ -333,6 +348,65 @@ func (e ClientAccessRevoked) ToDataJSON() ([]byte, error) {
 	}{e.UserID, e.ClientID})
 }
 
+// ClientAccessExpiryChanged — emitted when a client-access grant's expiry
+// is set, cleared (ExpiresAt nil) or extended.
+type ClientAccessExpiryChanged struct {
+	Metadata          usecase.EventMetadata
+	UserID            string
+	ClientID          string
+	ExpiresAt         *time.Time
+	PreviousExpiresAt *time.Time
+}
+
+func (e ClientAccessExpiryChanged) EventID() string       { return e.Metadata.EventID }
+func (e ClientAccessExpiryChanged) EventType() string     { return ClientAccessExpiryChangedType }
+func (e ClientAccessExpiryChanged) SpecVersion() string   { return "1.0" }
+func (e ClientAccessExpiryChanged) Source() string        { return Source }
+func (e ClientAccessExpiryChanged) Subject() string       { return subjectFor(e.UserID) }
+func (e ClientAccessExpiryChanged) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ClientAccessExpiryChanged) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ClientAccessExpiryChanged) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ClientAccessExpiryChanged) CausationID() string   { return e.Metadata.CausationID }
+func (e ClientAccessExpiryChanged) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ClientAccessExpiryChanged) MessageGroup() string  { return groupFor(e.UserID) }
+func (e ClientAccessExpiryChanged) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		PrincipalID       string     `json:"principalId"`
+		ClientID          string     `json:"clientId"`
+		ExpiresAt         *time.Time `json:"expiresAt"`
+		PreviousExpiresAt *time.Time `json:"previousExpiresAt"`
+	}{e.UserID, e.ClientID, e.ExpiresAt, e.PreviousExpiresAt})
+}
+
+// ClientAccessExpired — emitted when the purger removes a lapsed
+// client-access grant. The audit record of access ending without anyone
+// revoking it.
+type ClientAccessExpired struct {
+	Metadata  usecase.EventMetadata
+	UserID    string
+	ClientID  string
+	ExpiredAt time.Time
+}
+
+func (e ClientAccessExpired) EventID() string       { return e.Metadata.EventID }
+func (e ClientAccessExpired) EventType() string     { return ClientAccessExpiredType }
+func (e ClientAccessExpired) SpecVersion() string   { return "1.0" }
+func (e ClientAccessExpired) Source() string        { return Source }
+func (e ClientAccessExpired) Subject() string       { return subjectFor(e.UserID) }
+func (e ClientAccessExpired) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ClientAccessExpired) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ClientAccessExpired) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ClientAccessExpired) CausationID() string   { return e.Metadata.CausationID }
+func (e ClientAccessExpired) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ClientAccessExpired) MessageGroup() string  { return groupFor(e.UserID) }
+func (e ClientAccessExpired) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		PrincipalID string    `json:"principalId"`
+		ClientID    string    `json:"clientId"`
+		ExpiredAt   time.Time `json:"expiredAt"`
+	}{e.UserID, e.ClientID, e.ExpiredAt})
+}
+
 func defaultEmpty(xs []string) []string {
 	if xs == nil {
 		return []string{}
@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
@@ -14,6 +15,8 @@ import (
 type GrantClientAccessCommand struct {
 	UserID   string `json:"userId"`
 	ClientID string `json:"clientId"`
+	// ExpiresAt bounds the grant; nil grants open-ended access.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 // GrantClientAccess records a PARTNER user's access to a specific client and
@@ -24,7 +27,8 @@ type GrantClientAccessCommand struct {
 // (the dedicated endpoint with auth.RequireAnchor; createUser's partner-merge
 // with auth.RequireUserAdmin against the resolved client). The use case enforces
 // only the domain invariants (USER type, PARTNER scope, client exists, no
-// duplicate grant).
+// duplicate grant, expiry in the future). A grant that has lapsed but not
+// yet been purged is reinstated rather than refused as a duplicate.
 func GrantClientAccess(repo *principal.Repository, clients *client.Repository, grants *principal.ClientAccessGrantRepo) usecaseop.Operation[GrantClientAccessCommand, ClientAccessGranted] {
 	return usecaseop.Operation[GrantClientAccessCommand, ClientAccessGranted]{
 		Name: "GrantClientAccess",
@@ -35,7 +39,7 @@ func GrantClientAccess(repo *principal.Repository, clients *client.Repository, g
 			if strings.TrimSpace(cmd.ClientID) == "" {
 				return usecase.Validation("CLIENT_ID_REQUIRED", "Client ID is required")
 			}
-			return nil
+			return validateExpiry(cmd.ExpiresAt)
 		},
 		Authorize: usecaseop.Public[GrantClientAccessCommand],
 		Execute: func(ctx context.Context, cmd GrantClientAccessCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ClientAccessGranted], error) {
@@ -67,16 +71,20 @@ func GrantClientAccess(repo *principal.Repository, clients *client.Repository, g
 			if err != nil {
 				return nil, usecase.Internal("REPO", "find_existing_grant failed", err)
 			}
+			grant := principal.NewClientAccessGrant(cmd.UserID, cmd.ClientID, ec.PrincipalID)
 			if existing != nil {
-				return nil, usecase.BusinessRule("GRANT_EXISTS", "User already has access to this client")
+				if !existing.ExpiredAt(grant.GrantedAt) {
+					return nil, usecase.BusinessRule("GRANT_EXISTS", "User already has access to this client")
+				}
+				grant.ID, grant.CreatedAt = existing.ID, existing.CreatedAt
 			}
-
-			grant := principal.NewClientAccessGrant(cmd.UserID, cmd.ClientID, ec.PrincipalID)
+			grant.ExpiresAt = cmd.ExpiresAt
 
 			event := ClientAccessGranted{
-				Metadata: usecase.NewEventMetadata(ec, ClientAccessGrantedType, Source, subjectFor(p.ID)),
-				UserID:   p.ID,
-				ClientID: cmd.ClientID,
+				Metadata:  usecase.NewEventMetadata(ec, ClientAccessGrantedType, Source, subjectFor(p.ID)),
+				UserID:    p.ID,
+				ClientID:  cmd.ClientID,
+				ExpiresAt: cmd.ExpiresAt,
 			}
 			return usecaseop.Save(grant, grants, event), nil
 		},
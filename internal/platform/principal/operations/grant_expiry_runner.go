@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// grantExpiryBatch bounds how many lapsed grants one pass reads.
+const grantExpiryBatch = 100
+
+// GrantExpiryRunner removes lapsed client-access grants through
+// ExpireClientAccess, so each one leaves a client-access-expired event
+// and audit row. Lapsed grants already stop counting the moment they
+// pass their expiry; this only tidies and records them. Every instance
+// runs one — LapsedGrantDeleter makes the losers of a race back off.
+type GrantExpiryRunner struct {
+	grants   *principal.ClientAccessGrantRepo
+	uow      *usecasepgx.UnitOfWork
+	interval time.Duration
+}
+
+// NewGrantExpiryRunner wires a runner that sweeps once a minute.
+func NewGrantExpiryRunner(grants *principal.ClientAccessGrantRepo, uow *usecasepgx.UnitOfWork) *GrantExpiryRunner {
+	return &GrantExpiryRunner{grants: grants, uow: uow, interval: time.Minute}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (r *GrantExpiryRunner) Run(ctx context.Context) {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	slog.Info("client access expiry runner starting", "interval", r.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("client access expiry runner stopped")
+			return
+		case <-t.C:
+			if n, err := r.Sweep(ctx); err != nil {
+				slog.Warn("client access expiry sweep failed", "err", err)
+			} else if n > 0 {
+				slog.Info("client access grants expired", "count", n)
+			}
+		}
+	}
+}
+
+// Sweep expires every grant lapsed by now and returns how many this
+// instance removed. A grant another instance got to first, or that was
+// extended in between, is skipped; any other failure is logged and the
+// sweep moves on.
+func (r *GrantExpiryRunner) Sweep(ctx context.Context) (int, error) {
+	op := ExpireClientAccess(r.grants)
+	expired := 0
+	for ctx.Err() == nil {
+		batch, err := r.grants.FindExpired(ctx, time.Now(), grantExpiryBatch)
+		if err != nil {
+			return expired, err
+		}
+		progressed := false
+		for _, g := range batch {
+			ec := usecase.NewExecutionContext("system")
+			_, err := usecaseop.Run(ctx, r.uow, op, ExpireClientAccessCommand{UserID: g.PrincipalID, ClientID: g.ClientID}, ec)
+			switch {
+			case err == nil:
+				expired++
+				progressed = true
+			case expiryOvertaken(err):
+				progressed = true
+			default:
+				slog.Warn("client access expiry failed", "principal_id", g.PrincipalID, "client_id", g.ClientID, "err", err)
+			}
+		}
+		// A full batch may have more behind it; stop when one made no
+		// headway so a grant that keeps failing can't spin the loop.
+		if len(batch) < grantExpiryBatch || !progressed {
+			break
+		}
+	}
+	return expired, nil
+}
+
+// expiryOvertaken reports whether ExpireClientAccess failed only because
+// the grant was removed or extended since the sweep read it.
+func expiryOvertaken(err error) bool {
+	if errors.Is(err, principal.ErrGrantNotLapsed) || httperror.IsNotFound(err) {
+		return true
+	}
+	uc := usecase.AsError(err)
+	return uc != nil && uc.Code == "GRANT_NOT_EXPIRED"
+}
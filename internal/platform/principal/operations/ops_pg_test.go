@@ -5,6 +5,7 @@ package operations_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -714,6 +715,93 @@ func TestRevokeClientAccess_Errors(t *testing.T) {
 	}
 }
 
+// ── Client-access expiry ──────────────────────────────────────────────────
+
+func TestClientAccessExpiry_Lifecycle(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := principal.NewRepository(pool)
+	clients := client.NewRepository(pool)
+	grants := principal.NewClientAccessGrantRepo(pool)
+	uow := testpg.NewUoW(t)
+
+	clientID := mustCreateClient(t, uow, "Prn Grant Expiry", "prn-grant-expiry")
+	partner := mustCreateUser(t, repo, uow, "prn-grantexpiry@example.com", "PARTNER", &clientID)
+
+	// A grant can't start out lapsed.
+	_, err := runAuthorized(uow, operations.GrantClientAccess(repo, clients, grants),
+		operations.GrantClientAccessCommand{UserID: partner.UserID, ClientID: clientID, ExpiresAt: ptr(time.Now().Add(-time.Hour))})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "EXPIRY_IN_PAST")
+
+	expires := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Microsecond)
+	granted, err := runAuthorized(uow, operations.GrantClientAccess(repo, clients, grants),
+		operations.GrantClientAccessCommand{UserID: partner.UserID, ClientID: clientID, ExpiresAt: &expires})
+	require.NoError(t, err)
+	require.NotNil(t, granted.ExpiresAt)
+
+	// Extend pushes the current expiry out.
+	changed, err := runAuthorized(uow, operations.ExtendClientAccess(grants),
+		operations.ExtendClientAccessCommand{UserID: partner.UserID, ClientID: clientID, By: 48 * time.Hour})
+	require.NoError(t, err)
+	require.NotNil(t, changed.PreviousExpiresAt)
+	assert.True(t, changed.PreviousExpiresAt.Equal(expires))
+	assert.True(t, changed.ExpiresAt.Equal(expires.Add(48*time.Hour)))
+
+	// Clearing makes it open-ended, which then has nothing to extend.
+	_, err = runAuthorized(uow, operations.SetClientAccessExpiry(grants),
+		operations.SetClientAccessExpiryCommand{UserID: partner.UserID, ClientID: clientID})
+	require.NoError(t, err)
+	_, err = runAuthorized(uow, operations.ExtendClientAccess(grants),
+		operations.ExtendClientAccessCommand{UserID: partner.UserID, ClientID: clientID, By: time.Hour})
+	testpg.RequireUsecaseError(t, err, usecase.KindBusinessRule, "GRANT_NOT_EXPIRING")
+
+	// A live grant isn't expired by the op.
+	_, err = runAuthorized(uow, operations.ExpireClientAccess(grants),
+		operations.ExpireClientAccessCommand{UserID: partner.UserID, ClientID: clientID})
+	testpg.RequireUsecaseError(t, err, usecase.KindBusinessRule, "GRANT_NOT_EXPIRED")
+
+	// Once lapsed it stops counting at once…
+	_, err = pool.Exec(ctx, `UPDATE iam_client_access_grants SET expires_at = NOW() - INTERVAL '1 minute'
+		 WHERE principal_id = $1 AND client_id = $2`, partner.UserID, clientID)
+	require.NoError(t, err)
+	reloaded, err := repo.FindByID(ctx, partner.UserID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded)
+	assert.Empty(t, reloaded.AssignedClients, "a lapsed grant no longer hydrates")
+	_, err = runAuthorized(uow, operations.ExtendClientAccess(grants),
+		operations.ExtendClientAccessCommand{UserID: partner.UserID, ClientID: clientID, By: time.Hour})
+	testpg.RequireUsecaseError(t, err, usecase.KindBusinessRule, "GRANT_EXPIRED")
+
+	// …and the sweep removes it, leaving the expired event in the audit trail.
+	n, err := operations.NewGrantExpiryRunner(grants, uow).Sweep(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+	grant, err := grants.FindByPrincipalAndClient(ctx, partner.UserID, clientID)
+	require.NoError(t, err)
+	assert.Nil(t, grant)
+	var events int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT count(*) FROM msg_events WHERE type = $1 AND subject = $2`,
+		operations.ClientAccessExpiredType, "platform.principal."+partner.UserID).Scan(&events))
+	assert.Equal(t, 1, events)
+
+	// A lapsed-but-unswept grant is reinstated by granting again.
+	_, err = runAuthorized(uow, operations.GrantClientAccess(repo, clients, grants),
+		operations.GrantClientAccessCommand{UserID: partner.UserID, ClientID: clientID})
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE iam_client_access_grants SET expires_at = NOW() - INTERVAL '1 minute'
+		 WHERE principal_id = $1 AND client_id = $2`, partner.UserID, clientID)
+	require.NoError(t, err)
+	_, err = runAuthorized(uow, operations.GrantClientAccess(repo, clients, grants),
+		operations.GrantClientAccessCommand{UserID: partner.UserID, ClientID: clientID})
+	require.NoError(t, err)
+	grant, err = grants.FindByPrincipalAndClient(ctx, partner.UserID, clientID)
+	require.NoError(t, err)
+	require.NotNil(t, grant)
+	assert.Nil(t, grant.ExpiresAt)
+}
+
 // ── SetClientAssociation ──────────────────────────────────────────────────
 
 // The "*" wildcard promotes to ANCHOR regardless of mode (no mode needed) and
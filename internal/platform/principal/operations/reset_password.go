@@ -2,10 +2,10 @@ package operations
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordpolicy"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -15,10 +15,10 @@ import (
 type ResetPasswordCommand struct {
 	ID          string `json:"id"`
 	NewPassword string `json:"newPassword"`
-	// EnforcePasswordComplexity defaults to true when nil. When false the caller
-	// owns its own password policy, so we relax the minimum length (1:1 with
-	// Rust's relaxed() policy). Go has no upper/lower/digit/special complexity
-	// checks, so the only effect today is the minimum-length floor.
+	// EnforcePasswordComplexity defaults to true when nil, applying the
+	// configured passwordpolicy. When false the caller owns its own password
+	// policy, so only the relaxed 2-character floor applies (1:1 with Rust's
+	// relaxed() policy).
 	EnforcePasswordComplexity *bool `json:"enforcePasswordComplexity,omitempty"`
 }
 
@@ -38,18 +38,14 @@ func ResetPassword(repo *principal.Repository) usecaseop.Operation[ResetPassword
 			if strings.TrimSpace(cmd.ID) == "" {
 				return usecase.Validation("ID_REQUIRED", "id is required")
 			}
-			// Minimum length follows the complexity flag: the strict default requires 8
-			// (Rust PasswordPolicy::default min_length), an opt-out relaxes to 2 (Rust
-			// PasswordPolicy::relaxed). enforce defaults to true when the flag is absent.
-			minLen := 8
+			// The configured policy applies unless the caller opted out, which
+			// relaxes to a 2-character floor (Rust PasswordPolicy::relaxed).
+			// enforce defaults to true when the flag is absent.
+			policy := passwordpolicy.Current()
 			if cmd.EnforcePasswordComplexity != nil && !*cmd.EnforcePasswordComplexity {
-				minLen = 2
+				policy = policy.Relaxed()
 			}
-			if len(cmd.NewPassword) < minLen {
-				return usecase.Validation("PASSWORD_TOO_SHORT",
-					fmt.Sprintf("newPassword must be at least %d characters", minLen))
-			}
-			return nil
+			return policy.Check(cmd.NewPassword)
 		},
 		Authorize: usecaseop.Public[ResetPasswordCommand],
 		Execute: func(ctx context.Context, cmd ResetPasswordCommand, ec usecase.ExecutionContext) (usecaseop.Plan[UserPasswordReset], error) {
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/versioncache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
@@ -116,6 +117,38 @@ func (r *Repository) FindClientAdminEmails(ctx context.Context, clientID string)
 	return out, rows.Err()
 }
 
+// FindClientEmailsWithPermission returns the emails of active principals
+// whose home client is clientID and one of whose roles grants permission
+// (wildcards included) — used to notify subscription approvers.
+func (r *Repository) FindClientEmailsWithPermission(ctx context.Context, clientID, permission string) ([]string, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT DISTINCT p.email, rp.permission
+		   FROM iam_principals p
+		   JOIN iam_principal_roles pr ON pr.principal_id = p.id
+		   JOIN iam_roles r ON r.name = pr.role_name
+		   JOIN iam_role_permissions rp ON rp.role_id = r.id
+		  WHERE p.active = TRUE
+		    AND p.email IS NOT NULL
+		    AND p.client_id = $1`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("find client emails with permission: %w", err)
+	}
+	defer rows.Close()
+	var out []string
+	seen := map[string]bool{}
+	for rows.Next() {
+		var email, held string
+		if err := rows.Scan(&email, &held); err != nil {
+			return nil, err
+		}
+		if !seen[email] && auth.Grants([]string{held}, permission) {
+			seen[email] = true
+			out = append(out, email)
+		}
+	}
+	return out, rows.Err()
+}
+
 // FindByID loads a principal by id, with role assignments hydrated
 // from iam_principal_roles.
 func (r *Repository) FindByID(ctx context.Context, id string) (*Principal, error) {
@@ -182,9 +215,10 @@ func (r *Repository) hydrateClientAccess(ctx context.Context, p *Principal) erro
 	if r.pool == nil || p == nil {
 		return nil
 	}
-	// Granted clients (the access-grants junction).
+	// Granted clients (the access-grants junction), lapsed grants aside.
 	rows, err := r.pool.Query(ctx,
-		`SELECT client_id FROM iam_client_access_grants WHERE principal_id = $1 ORDER BY client_id`, p.ID)
+		`SELECT client_id FROM iam_client_access_grants
+		 WHERE principal_id = $1 AND `+grantActive+` ORDER BY client_id`, p.ID)
 	if err != nil {
 		return fmt.Errorf("principal client grants: %w", err)
 	}
@@ -422,7 +456,7 @@ func (r *Repository) hydrateClientGrantsAll(ctx context.Context, ps []Principal)
 	rows, err := r.pool.Query(ctx,
 		`SELECT principal_id, client_id
 		 FROM iam_client_access_grants
-		 WHERE principal_id = ANY($1)
+		 WHERE principal_id = ANY($1) AND `+grantActive+`
 		 ORDER BY client_id`, ids)
 	if err != nil {
 		return fmt.Errorf("principal client grants (bulk): %w", err)
@@ -447,7 +481,7 @@ func (r *Repository) Persist(ctx context.Context, p *Principal, tx *usecasepgx.D
 	var email, emailDomain, idpType, externalIdpID, passwordHash *string
 	var lastLoginAt *time.Time
 	var devClientSecretRef *string
-	var devClientSecretUpdatedAt *time.Time
+	var devClientSecretUpdatedAt, passwordChangedAt *time.Time
 
 	if p.UserIdentity != nil {
 		// Normalise on the way to the DB: every write to the email column goes
@@ -474,6 +508,7 @@ func (r *Repository) Persist(ctx context.Context, p *Principal, tx *usecasepgx.D
 		}
 		devClientSecretRef = p.UserIdentity.DevClientSecretRef
 		devClientSecretUpdatedAt = p.UserIdentity.DevClientSecretUpdatedAt
+		passwordChangedAt = p.UserIdentity.PasswordChangedAt
 	}
 	// USER without an explicit provider defaults to INTERNAL (matches Rust).
 	if idpType == nil && p.Type == TypeUser {
@@ -511,6 +546,7 @@ func (r *Repository) Persist(ctx context.Context, p *Principal, tx *usecasepgx.D
 		UpdatedAt:                now,
 		DevClientSecretRef:       devClientSecretRef,
 		DevClientSecretUpdatedAt: devClientSecretUpdatedAt,
+		PasswordChangedAt:        passwordChangedAt,
 	}); err != nil {
 		return err
 	}
@@ -601,14 +637,20 @@ func (cp ClientAssociationPersister) Persist(ctx context.Context, p *Principal,
 	now := time.Now().UTC()
 	for _, cid := range cp.GrantClientIDs {
 		// Idempotent: a grant for (principal, client) already present is left
-		// untouched, mirroring the prior FindByPrincipalAndClient skip. ON
-		// CONFLICT on the natural key keeps this safe even under a race.
+		// untouched, mirroring the prior FindByPrincipalAndClient skip, unless
+		// it has lapsed — then it's reinstated without an expiry. ON CONFLICT
+		// on the natural key keeps this safe even under a race.
 		grant := NewClientAccessGrant(p.ID, cid, cp.GrantedBy)
 		if _, err := q.Exec(ctx,
-			`INSERT INTO iam_client_access_grants
+			`INSERT INTO iam_client_access_grants AS g
 			     (id, principal_id, client_id, granted_by, granted_at, created_at, updated_at)
 			 VALUES ($1, $2, $3, $4, $5, $6, $7)
-			 ON CONFLICT (principal_id, client_id) DO NOTHING`,
+			 ON CONFLICT (principal_id, client_id) DO UPDATE SET
+			     granted_by = EXCLUDED.granted_by,
+			     granted_at = EXCLUDED.granted_at,
+			     expires_at = NULL,
+			     updated_at = EXCLUDED.updated_at
+			 WHERE g.expires_at <= NOW()`,
 			grant.ID, grant.PrincipalID, grant.ClientID, grant.GrantedBy, grant.GrantedAt, grant.CreatedAt, now); err != nil {
 			return fmt.Errorf("insert client access grant for client %q: %w", cid, err)
 		}
@@ -687,6 +729,21 @@ func (r *Repository) UpdatePasswordHash(ctx context.Context, principalID, hash s
 	return nil
 }
 
+// ChangePasswordHash sets a new password the user chose themselves (profile
+// change-password) and restarts its expiry clock. Unlike UpdatePasswordHash
+// this is a real credential change; callers own the post-change hygiene
+// (session / trusted-device revocation, notification).
+func (r *Repository) ChangePasswordHash(ctx context.Context, principalID, hash string) error {
+	now := time.Now().UTC()
+	if _, err := r.pool.Exec(ctx,
+		`UPDATE iam_principals SET password_hash = $1, password_changed_at = $2, updated_at = $2 WHERE id = $3`,
+		hash, now, principalID); err != nil {
+		return err
+	}
+	r.bumpVersion(ctx, principalID, now)
+	return nil
+}
+
 // LowercaseEmail normalises a principal's stored email (and the derived
 // email_domain) to lower-case in place, but only when it isn't already
 // normalised — an already-lower-case row triggers no write. Like
@@ -771,6 +828,7 @@ func rowToPrincipal(row dbq.IamPrincipal) *Principal {
 			LastLoginAt:              row.LastLoginAt,
 			DevClientSecretRef:       row.DevClientSecretRef,
 			DevClientSecretUpdatedAt: row.DevClientSecretUpdatedAt,
+			PasswordChangedAt:        row.PasswordChangedAt,
 		}
 	}
 	if row.ExternalIdpID != nil {
@@ -245,3 +245,30 @@ func (s *recordingStore) Bump(_ context.Context, principalID string, _ time.Time
 func (s *recordingStore) Get(context.Context, string) (time.Time, bool, error) {
 	return time.Time{}, false, nil
 }
+
+// TestPasswordChangedAt_TracksRealChangesOnly pins the expiry clock: a
+// user-chosen change restarts it, the transparent login rehash does not.
+func TestPasswordChangedAt_TracksRealChangesOnly(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := principal.NewRepository(pool)
+
+	const pid = "prn_pwchanged001"
+	old := time.Now().UTC().Add(-100 * 24 * time.Hour).Truncate(time.Microsecond)
+	_, err := pool.Exec(ctx,
+		`INSERT INTO iam_principals (id, type, scope, name, active, email, password_hash, password_changed_at)
+		 VALUES ($1, 'USER', 'ANCHOR', 'Pw User', TRUE, 'pw-changed@example.com', 'h0', $2)`, pid, old)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdatePasswordHash(ctx, pid, "h1"))
+	p, err := repo.FindByID(ctx, pid)
+	require.NoError(t, err)
+	require.NotNil(t, p.UserIdentity.PasswordChangedAt)
+	assert.True(t, p.UserIdentity.PasswordChangedAt.Equal(old), "rehash must not restart the expiry clock")
+
+	require.NoError(t, repo.ChangePasswordHash(ctx, pid, "h2"))
+	p, err = repo.FindByID(ctx, pid)
+	require.NoError(t, err)
+	assert.Equal(t, "h2", *p.UserIdentity.PasswordHash)
+	assert.WithinDuration(t, time.Now(), *p.UserIdentity.PasswordChangedAt, time.Minute)
+}
@@ -0,0 +1,83 @@
+// Package api wires the privacy purge endpoints via huma.
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// State bundles deps.
+type State struct {
+	Repo   *privacy.Repository
+	Config privacy.Config
+	UoW    *usecasepgx.UnitOfWork
+}
+
+const tag = "privacy"
+
+// listLimit caps GET /api/admin/platform/privacy/purge.
+const listLimit = 100
+
+// Register mounts the privacy endpoints. All of them are anchor-only: a
+// purge reaches across every tenant's data.
+func Register(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Post(g, "requestPrivacyPurge", "/api/admin/platform/privacy/purge", "Queue a purge of a data subject's events and dispatch jobs", http.StatusAccepted, s.create)
+	apiroute.Get(g, "listPrivacyPurges", "/api/admin/platform/privacy/purge", "List privacy purges", s.list)
+	apiroute.Get(g, "getPrivacyPurge", "/api/admin/platform/privacy/purge/{id}", "Get a privacy purge's status and report", s.get)
+}
+
+func (s *State) create(ctx context.Context, in *apicommon.In[PurgeRequest]) (*apicommon.Out[PurgeResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	event, err := usecaseop.Run(ctx, s.UoW, operations.RequestPurge(s.Repo, s.Config), in.Body.toCommand(), reqctx.ExecutionContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, event.PurgeID)
+}
+
+func (s *State) list(ctx context.Context, _ *apicommon.Empty) (*apicommon.Out[PurgeListResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.List(ctx, listLimit)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "list_privacy_purges failed", err)
+	}
+	return &apicommon.Out[PurgeListResponse]{Body: PurgeListResponse{
+		Items: apicommon.MapSlice(rows, func(p **privacy.Purge) PurgeResponse { return purgeFromEntity(*p) }),
+	}}, nil
+}
+
+func (s *State) get(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[PurgeResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return s.load(ctx, in.ID)
+}
+
+func (s *State) load(ctx context.Context, id string) (*apicommon.Out[PurgeResponse], error) {
+	p, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_privacy_purge failed", err)
+	}
+	if p == nil {
+		return nil, httperror.NotFound("PrivacyPurge", id)
+	}
+	return &apicommon.Out[PurgeResponse]{Body: purgeFromEntity(p)}, nil
+}
@@ -0,0 +1,81 @@
+// dto.go contains the wire-format types for the privacy purge API.
+package api
+
+import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy/operations"
+)
+
+// PurgeRequest is the wire body for POST /api/admin/platform/privacy/purge.
+type PurgeRequest struct {
+	SubjectKey   string            `json:"subjectKey" doc:"The data subject's identifier, matched exactly against the value at each event type's subject path"`
+	Mode         string            `json:"mode,omitempty" enum:"DELETE,ANONYMIZE" doc:"Defaults to DELETE"`
+	SubjectPaths map[string]string `json:"subjectPaths,omitempty" doc:"Event type → dotted JSON path into the event data (\"*\" = every other type); merged over FC_PRIVACY_SUBJECT_PATHS"`
+	EventTypes   []string          `json:"eventTypes,omitempty" doc:"Purge only these event types; empty = every type with a subject path"`
+	Reason       *string           `json:"reason,omitempty" doc:"Free text for the audit trail, e.g. the erasure request reference. Must not contain the subject key"`
+}
+
+func (r PurgeRequest) toCommand() operations.RequestPurgeCommand {
+	mode := r.Mode
+	if mode == "" {
+		mode = string(privacy.ModeDelete)
+	}
+	return operations.RequestPurgeCommand{
+		SubjectKey:   r.SubjectKey,
+		Mode:         mode,
+		SubjectPaths: r.SubjectPaths,
+		EventTypes:   r.EventTypes,
+		Reason:       r.Reason,
+	}
+}
+
+// PurgeReportResponse is the per-table count of rows a purge deleted or
+// anonymized.
+type PurgeReportResponse struct {
+	Events           int64 `json:"events"`
+	EventsRead       int64 `json:"eventsRead"`
+	DispatchJobs     int64 `json:"dispatchJobs"`
+	DispatchJobsRead int64 `json:"dispatchJobsRead"`
+	Attempts         int64 `json:"attempts"`
+}
+
+// PurgeResponse is the wire shape of a purge. The subject key itself is
+// never returned; subjectHash is its hex SHA-256.
+type PurgeResponse struct {
+	ID           string              `json:"id"`
+	Mode         string              `json:"mode" enum:"DELETE,ANONYMIZE"`
+	Status       string              `json:"status" enum:"PENDING,RUNNING,COMPLETED,FAILED"`
+	SubjectHash  string              `json:"subjectHash"`
+	SubjectPaths map[string]string   `json:"subjectPaths"`
+	Reason       *string             `json:"reason,omitempty"`
+	Report       PurgeReportResponse `json:"report"`
+	Error        *string             `json:"error,omitempty"`
+	RequestedBy  *string             `json:"requestedBy,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	StartedAt    *time.Time          `json:"startedAt,omitempty"`
+	CompletedAt  *time.Time          `json:"completedAt,omitempty"`
+}
+
+func purgeFromEntity(p *privacy.Purge) PurgeResponse {
+	return PurgeResponse{
+		ID:           p.ID,
+		Mode:         string(p.Mode),
+		Status:       string(p.Status),
+		SubjectHash:  p.SubjectHash,
+		SubjectPaths: p.SubjectPaths,
+		Reason:       p.Reason,
+		Report:       PurgeReportResponse(p.Report),
+		Error:        p.Error,
+		RequestedBy:  p.RequestedBy,
+		CreatedAt:    p.CreatedAt,
+		StartedAt:    p.StartedAt,
+		CompletedAt:  p.CompletedAt,
+	}
+}
+
+// PurgeListResponse wraps GET /api/admin/platform/privacy/purge.
+type PurgeListResponse struct {
+	Items []PurgeResponse `json:"items"`
+}
@@ -0,0 +1,219 @@
+// Package privacy erases a data subject's footprint from the messaging
+// tables for right-to-erasure (GDPR Art. 17) requests.
+//
+// POST /api/admin/platform/privacy/purge records a Purge through the UoW
+// (an anchor-only request with an audit trail). The Runner then claims it
+// on any instance and, in batches, finds every event whose payload holds
+// the subject key at the JSON path configured for its type, and deletes
+// or anonymizes those events, their read projections, the dispatch jobs
+// fanned out from them and those jobs' attempts. Progress transitions are
+// infrastructure-processing like the export runner: direct writes, no
+// domain event. On completion the runner writes a second audit row with
+// the per-table counts.
+//
+// The raw subject key never reaches the audit log or the event stream;
+// it sits on the purge row only until the purge finishes, after which
+// only its SHA-256 remains.
+package privacy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// Mode is what happens to matching rows.
+type Mode string
+
+const (
+	// ModeDelete removes the rows outright.
+	ModeDelete Mode = "DELETE"
+	// ModeAnonymize keeps the rows (counts, timings, delivery history)
+	// but clears every column that carries payload: event data and
+	// context data, dispatch job payload and metadata, and attempt
+	// response bodies.
+	ModeAnonymize Mode = "ANONYMIZE"
+)
+
+// ParseMode is the strict parser; ok is false for anything unknown.
+func ParseMode(s string) (Mode, bool) {
+	switch Mode(s) {
+	case ModeDelete, ModeAnonymize:
+		return Mode(s), true
+	}
+	return "", false
+}
+
+// Status is the purge lifecycle state.
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusRunning   Status = "RUNNING"
+	StatusCompleted Status = "COMPLETED"
+	StatusFailed    Status = "FAILED"
+)
+
+// AnyType is the SubjectPaths key whose path applies to every event type
+// without a path of its own.
+const AnyType = "*"
+
+// Report counts the rows a purge deleted or anonymized, per table.
+type Report struct {
+	Events           int64 `json:"events"`
+	EventsRead       int64 `json:"eventsRead"`
+	DispatchJobs     int64 `json:"dispatchJobs"`
+	DispatchJobsRead int64 `json:"dispatchJobsRead"`
+	Attempts         int64 `json:"attempts"`
+}
+
+// Add accumulates another batch's counts.
+func (r *Report) Add(o Report) {
+	r.Events += o.Events
+	r.EventsRead += o.EventsRead
+	r.DispatchJobs += o.DispatchJobs
+	r.DispatchJobsRead += o.DispatchJobsRead
+	r.Attempts += o.Attempts
+}
+
+// Purge is the aggregate root.
+type Purge struct {
+	ID   string `json:"id"`
+	Mode Mode   `json:"mode"`
+	// SubjectKey is the raw identifier; nil once the purge has finished.
+	SubjectKey  *string `json:"-"`
+	SubjectHash string  `json:"subjectHash"`
+	// SubjectPaths maps event type → dotted JSON path into the event data
+	// where the subject key is looked for.
+	SubjectPaths map[string]string `json:"subjectPaths"`
+	Reason       *string           `json:"reason,omitempty"`
+	Status       Status            `json:"status"`
+	Report       Report            `json:"report"`
+	Error        *string           `json:"error,omitempty"`
+	RequestedBy  *string           `json:"requestedBy,omitempty"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	StartedAt    *time.Time        `json:"startedAt,omitempty"`
+	CompletedAt  *time.Time        `json:"completedAt,omitempty"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (p Purge) IDStr() string { return p.ID }
+
+// New constructs a PENDING purge.
+func New(mode Mode, subjectKey string, paths map[string]string, reason, requestedBy *string) *Purge {
+	now := time.Now().UTC()
+	return &Purge{
+		ID:           tsid.Generate(tsid.PrivacyPurge),
+		Mode:         mode,
+		SubjectKey:   &subjectKey,
+		SubjectHash:  HashSubject(subjectKey),
+		SubjectPaths: paths,
+		Reason:       reason,
+		Status:       StatusPending,
+		RequestedBy:  requestedBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// HashSubject is the hex SHA-256 of a subject key — what is kept, and
+// can be searched for, once the raw key is gone.
+func HashSubject(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// SplitPath turns a dotted path ("customer.email", "lines.0.ref") into
+// the text[] a Postgres #>> lookup takes. ok is false for an empty path
+// or an empty segment.
+func SplitPath(path string) ([]string, bool) {
+	if path == "" {
+		return nil, false
+	}
+	parts := strings.Split(path, ".")
+	for _, p := range parts {
+		if p == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// Config holds the purge knobs (all env-overridable).
+type Config struct {
+	// SubjectPaths is the default event type → JSON path map; a request
+	// may override or extend it.
+	SubjectPaths map[string]string
+	// BatchSize is how many matching events one purge transaction takes.
+	BatchSize int
+	// PollInterval is how often an idle runner looks for queued purges.
+	PollInterval time.Duration
+	// StaleAfter is how long a RUNNING purge may go without finishing a
+	// batch before another instance picks it up.
+	StaleAfter time.Duration
+}
+
+// ConfigFromEnv builds a Config from FC_PRIVACY_* env vars.
+func ConfigFromEnv() Config {
+	return Config{
+		SubjectPaths: ParseSubjectPaths(envutil.Or("FC_PRIVACY_SUBJECT_PATHS", "")),
+		BatchSize:    envutil.Int("FC_PRIVACY_PURGE_BATCH_SIZE", 500),
+		PollInterval: 5 * time.Second,
+		StaleAfter:   5 * time.Minute,
+	}
+}
+
+// ParseSubjectPaths reads "type=path;type=path;*=path". Malformed entries
+// are logged and skipped rather than failing startup: a purge request
+// can always name its paths explicitly.
+func ParseSubjectPaths(s string) map[string]string {
+	out := map[string]string{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		typ, path, ok := strings.Cut(entry, "=")
+		typ, path = strings.TrimSpace(typ), strings.TrimSpace(path)
+		if _, valid := SplitPath(path); !ok || typ == "" || !valid {
+			slog.Warn("ignoring malformed FC_PRIVACY_SUBJECT_PATHS entry", "entry", entry)
+			continue
+		}
+		out[typ] = path
+	}
+	return out
+}
+
+// ResolvePaths merges a request's path overrides onto the configured
+// defaults. With eventTypes the result is narrowed to exactly those
+// types, each taking its own path or else the "*" default; a type with
+// neither is returned in missing.
+func ResolvePaths(defaults, overrides map[string]string, eventTypes []string) (paths map[string]string, missing []string) {
+	merged := make(map[string]string, len(defaults)+len(overrides))
+	for t, p := range defaults {
+		merged[t] = p
+	}
+	for t, p := range overrides {
+		merged[t] = p
+	}
+	if len(eventTypes) == 0 {
+		return merged, nil
+	}
+	paths = make(map[string]string, len(eventTypes))
+	for _, t := range eventTypes {
+		if p, ok := merged[t]; ok {
+			paths[t] = p
+		} else if p, ok := merged[AnyType]; ok {
+			paths[t] = p
+		} else {
+			missing = append(missing, t)
+		}
+	}
+	return paths, missing
+}
@@ -0,0 +1,58 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubjectPaths(t *testing.T) {
+	got := ParseSubjectPaths(" orders:order:placed = customer.email ; *=subject.id;bad;=x;y=;z=a..b")
+	assert.Equal(t, map[string]string{
+		"orders:order:placed": "customer.email",
+		"*":                   "subject.id",
+	}, got)
+	assert.Empty(t, ParseSubjectPaths(""))
+}
+
+func TestSplitPath(t *testing.T) {
+	segs, ok := SplitPath("lines.0.ref")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"lines", "0", "ref"}, segs)
+	for _, bad := range []string{"", ".", "a.", ".a", "a..b"} {
+		_, ok := SplitPath(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestResolvePaths(t *testing.T) {
+	defaults := map[string]string{"a": "x.id", "*": "subject"}
+
+	paths, missing := ResolvePaths(defaults, map[string]string{"a": "y.id", "b": "z"}, nil)
+	assert.Empty(t, missing)
+	assert.Equal(t, map[string]string{"a": "y.id", "b": "z", "*": "subject"}, paths, "overrides win, defaults kept")
+
+	paths, missing = ResolvePaths(defaults, nil, []string{"a", "c"})
+	assert.Empty(t, missing)
+	assert.Equal(t, map[string]string{"a": "x.id", "c": "subject"}, paths, "scoped types fall back to *")
+
+	_, missing = ResolvePaths(map[string]string{"a": "x.id"}, nil, []string{"a", "c"})
+	assert.Equal(t, []string{"c"}, missing)
+}
+
+func TestMatchClause(t *testing.T) {
+	args := []any{"key"}
+	sql := matchClause("data", map[string]string{"b": "p.q", "a": "r", "*": "s"}, &args)
+	assert.Equal(t,
+		"((type = $2 AND data #>> $3::text[] = $1) OR (type = $4 AND data #>> $5::text[] = $1)"+
+			" OR (type <> ALL($6::text[]) AND data #>> $7::text[] = $1))", sql)
+	assert.Equal(t, []any{"key", "a", []string{"r"}, "b", []string{"p", "q"}, []string{"a", "b"}, []string{"s"}}, args)
+
+	args = []any{"key"}
+	assert.Equal(t, "FALSE", matchClause("data", nil, &args))
+}
+
+func TestHashSubject(t *testing.T) {
+	assert.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", HashSubject("foo"))
+	assert.Len(t, New(ModeDelete, "foo", nil, nil, nil).SubjectHash, 64)
+}
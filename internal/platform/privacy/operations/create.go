@@ -0,0 +1,74 @@
+// Package operations holds the privacy purge use cases.
+package operations
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// RequestPurgeCommand is the input DTO. SubjectKey is excluded from JSON
+// so the audit row written from the command never holds it.
+type RequestPurgeCommand struct {
+	SubjectKey   string            `json:"-"`
+	Mode         string            `json:"mode"`
+	SubjectPaths map[string]string `json:"subjectPaths,omitempty"`
+	EventTypes   []string          `json:"eventTypes,omitempty"`
+	Reason       *string           `json:"reason,omitempty"`
+}
+
+// RequestPurge queues a PENDING purge and emits [PurgeRequested]. The
+// configured subject paths are merged with the command's and snapshotted
+// onto the purge, so a later config change doesn't alter it mid-run.
+func RequestPurge(repo *privacy.Repository, cfg privacy.Config) usecaseop.Operation[RequestPurgeCommand, PurgeRequested] {
+	return usecaseop.Operation[RequestPurgeCommand, PurgeRequested]{
+		Name: "RequestPrivacyPurge",
+		Validate: func(_ context.Context, cmd RequestPurgeCommand) error {
+			if strings.TrimSpace(cmd.SubjectKey) == "" {
+				return usecase.Validation("SUBJECT_KEY_REQUIRED", "subjectKey is required")
+			}
+			if _, ok := privacy.ParseMode(cmd.Mode); !ok {
+				return usecase.Validation("INVALID_MODE", "mode must be DELETE or ANONYMIZE")
+			}
+			for t, p := range cmd.SubjectPaths {
+				if _, ok := privacy.SplitPath(p); !ok || t == "" {
+					return usecase.Validation("INVALID_SUBJECT_PATH",
+						"subject path for '"+t+"' must be a dotted JSON path, e.g. customer.email")
+				}
+			}
+			paths, missing := privacy.ResolvePaths(cfg.SubjectPaths, cmd.SubjectPaths, cmd.EventTypes)
+			if len(missing) > 0 {
+				return usecase.Validation("SUBJECT_PATH_MISSING",
+					"no subject path configured for event types: "+strings.Join(missing, ", "))
+			}
+			if len(paths) == 0 {
+				return usecase.Validation("SUBJECT_PATHS_REQUIRED",
+					"no subject paths configured (FC_PRIVACY_SUBJECT_PATHS) or given in subjectPaths")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[RequestPurgeCommand],
+		Execute: func(_ context.Context, cmd RequestPurgeCommand, ec usecase.ExecutionContext) (usecaseop.Plan[PurgeRequested], error) {
+			mode, _ := privacy.ParseMode(cmd.Mode)
+			paths, _ := privacy.ResolvePaths(cfg.SubjectPaths, cmd.SubjectPaths, cmd.EventTypes)
+			var requestedBy *string
+			if ec.PrincipalID != "" {
+				requestedBy = &ec.PrincipalID
+			}
+			p := privacy.New(mode, strings.TrimSpace(cmd.SubjectKey), paths, cmd.Reason, requestedBy)
+			event := PurgeRequested{
+				Metadata:    usecase.NewEventMetadata(ec, PurgeRequestedType, Source, subjectFor(p.ID)),
+				PurgeID:     p.ID,
+				Mode:        string(p.Mode),
+				SubjectHash: p.SubjectHash,
+				EventTypes:  slices.Sorted(maps.Keys(p.SubjectPaths)),
+			}
+			return usecaseop.Save(p, repo, event), nil
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+const (
+	PurgeRequestedType = "platform:admin:privacy-purge:requested"
+	Source             = "platform:admin"
+)
+
+func subjectFor(id string) string { return "platform.privacypurge." + id }
+func groupFor(id string) string   { return "platform:privacypurge:" + id }
+
+// PurgeRequested carries the subject's hash, never the key itself: the
+// event stream is exactly what a purge has to keep clean.
+type PurgeRequested struct {
+	Metadata    usecase.EventMetadata
+	PurgeID     string
+	Mode        string
+	SubjectHash string
+	EventTypes  []string
+}
+
+func (e PurgeRequested) EventID() string       { return e.Metadata.EventID }
+func (e PurgeRequested) EventType() string     { return PurgeRequestedType }
+func (e PurgeRequested) SpecVersion() string   { return "1.0" }
+func (e PurgeRequested) Source() string        { return Source }
+func (e PurgeRequested) Subject() string       { return subjectFor(e.PurgeID) }
+func (e PurgeRequested) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e PurgeRequested) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e PurgeRequested) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e PurgeRequested) CausationID() string   { return e.Metadata.CausationID }
+func (e PurgeRequested) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e PurgeRequested) MessageGroup() string  { return groupFor(e.PurgeID) }
+func (e PurgeRequested) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		PurgeID     string   `json:"purgeId"`
+		Mode        string   `json:"mode"`
+		SubjectHash string   `json:"subjectHash"`
+		EventTypes  []string `json:"eventTypes"`
+	}{e.PurgeID, e.Mode, e.SubjectHash, e.EventTypes})
+}
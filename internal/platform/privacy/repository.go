@@ -0,0 +1,298 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// Repository reads/writes msg_privacy_purges and does the purging itself.
+// New purges go through the UoW via Persist; the runner's claim, batch
+// and finish writes are direct.
+type Repository struct{ pool *pgxpool.Pool }
+
+// NewRepository wires a repo.
+func NewRepository(pool *pgxpool.Pool) *Repository { return &Repository{pool: pool} }
+
+const selectCols = `id, mode, subject_key, subject_hash, subject_paths, reason, status, report,
+	error, requested_by, created_at, started_at, completed_at, updated_at`
+
+func scanPurge(row pgx.Row) (*Purge, error) {
+	var p Purge
+	var paths, report []byte
+	if err := row.Scan(&p.ID, &p.Mode, &p.SubjectKey, &p.SubjectHash, &paths, &p.Reason,
+		&p.Status, &report, &p.Error, &p.RequestedBy,
+		&p.CreatedAt, &p.StartedAt, &p.CompletedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(paths, &p.SubjectPaths); err != nil {
+		return nil, fmt.Errorf("decode subject_paths: %w", err)
+	}
+	if err := json.Unmarshal(report, &p.Report); err != nil {
+		return nil, fmt.Errorf("decode report: %w", err)
+	}
+	return &p, nil
+}
+
+// FindByID loads one purge. Returns (nil, nil) when not found.
+func (r *Repository) FindByID(ctx context.Context, id string) (*Purge, error) {
+	p, err := scanPurge(r.pool.QueryRow(ctx, `SELECT `+selectCols+` FROM msg_privacy_purges WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find_privacy_purge: %w", err)
+	}
+	return p, nil
+}
+
+// List returns purges newest first.
+func (r *Repository) List(ctx context.Context, limit int) ([]*Purge, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+selectCols+` FROM msg_privacy_purges ORDER BY created_at DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list_privacy_purges: %w", err)
+	}
+	defer rows.Close()
+	var out []*Purge
+	for rows.Next() {
+		p, err := scanPurge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list_privacy_purges: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// Persist implements usecasepgx.Persist[Purge].
+func (r *Repository) Persist(ctx context.Context, p *Purge, tx *usecasepgx.DbTx) error {
+	paths, err := json.Marshal(p.SubjectPaths)
+	if err != nil {
+		return fmt.Errorf("persist privacy purge: %w", err)
+	}
+	_, err = tx.Inner().Exec(ctx,
+		`INSERT INTO msg_privacy_purges
+		     (id, mode, subject_key, subject_hash, subject_paths, reason, status, requested_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 ON CONFLICT (id) DO UPDATE
+		    SET status     = EXCLUDED.status,
+		        updated_at = EXCLUDED.updated_at`,
+		p.ID, p.Mode, p.SubjectKey, p.SubjectHash, paths, p.Reason, p.Status, p.RequestedBy, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("persist privacy purge: %w", err)
+	}
+	return nil
+}
+
+// Delete implements usecasepgx.Persist[Purge].
+func (r *Repository) Delete(ctx context.Context, p *Purge, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx, `DELETE FROM msg_privacy_purges WHERE id = $1`, p.ID)
+	return err
+}
+
+// Claim moves the oldest PENDING purge — or a RUNNING one whose
+// heartbeat is older than staleAfter — to RUNNING and returns it. A
+// reclaimed purge keeps its report so far. Returns (nil, nil) when there
+// is nothing to do.
+func (r *Repository) Claim(ctx context.Context, staleAfter time.Duration) (*Purge, error) {
+	p, err := scanPurge(r.pool.QueryRow(ctx,
+		`UPDATE msg_privacy_purges
+		    SET status = 'RUNNING', started_at = COALESCE(started_at, NOW()),
+		        heartbeat_at = NOW(), updated_at = NOW()
+		  WHERE id = (
+		        SELECT id FROM msg_privacy_purges
+		         WHERE status = 'PENDING'
+		            OR (status = 'RUNNING' AND heartbeat_at < $1)
+		         ORDER BY created_at
+		         LIMIT 1
+		         FOR UPDATE SKIP LOCKED)
+		 RETURNING `+selectCols,
+		time.Now().Add(-staleAfter).UTC()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim_privacy_purge: %w", err)
+	}
+	return p, nil
+}
+
+// SaveProgress stores the running totals; it doubles as the heartbeat.
+func (r *Repository) SaveProgress(ctx context.Context, id string, report Report) error {
+	rep, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("progress_privacy_purge: %w", err)
+	}
+	_, err = r.pool.Exec(ctx,
+		`UPDATE msg_privacy_purges SET report = $2, heartbeat_at = NOW(), updated_at = NOW()
+		  WHERE id = $1 AND status = 'RUNNING'`, id, rep)
+	if err != nil {
+		return fmt.Errorf("progress_privacy_purge: %w", err)
+	}
+	return nil
+}
+
+// MarkCompleted records the final counts and drops the raw subject key.
+func (r *Repository) MarkCompleted(ctx context.Context, id string, report Report) error {
+	return r.finish(ctx, id, StatusCompleted, nil, report)
+}
+
+// MarkFailed records why a purge stopped, with the counts so far. The raw
+// subject key is dropped here too; a retry is a new request.
+func (r *Repository) MarkFailed(ctx context.Context, id, reason string, report Report) error {
+	return r.finish(ctx, id, StatusFailed, &reason, report)
+}
+
+func (r *Repository) finish(ctx context.Context, id string, status Status, reason *string, report Report) error {
+	rep, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("finish_privacy_purge: %w", err)
+	}
+	_, err = r.pool.Exec(ctx,
+		`UPDATE msg_privacy_purges
+		    SET status = $2, error = $3, report = $4, subject_key = NULL,
+		        completed_at = NOW(), updated_at = NOW()
+		  WHERE id = $1`,
+		id, status, reason, rep)
+	if err != nil {
+		return fmt.Errorf("finish_privacy_purge: %w", err)
+	}
+	return nil
+}
+
+// PurgeBatch deletes or anonymizes up to limit of the events matching
+// p's subject, together with their read projections, the dispatch jobs
+// fanned out from them and those jobs' attempts, in one transaction.
+// found is the number of events taken; zero means the purge is done.
+//
+// Events are found through msg_events_read (type-indexed) plus the
+// not-yet-projected tail of msg_events, and dispatch jobs likewise
+// through msg_dispatch_jobs_read's event_id index plus the unprojected
+// tail of msg_dispatch_jobs — the write tables carry no index for either
+// lookup. A purged row no longer matches (it is gone, or its data is
+// NULL), so a batch interrupted by a crash is simply redone.
+func (r *Repository) PurgeBatch(ctx context.Context, p *Purge, limit int) (report Report, found int, err error) {
+	if p.SubjectKey == nil {
+		return Report{}, 0, errors.New("subject key already cleared")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Report{}, 0, fmt.Errorf("purge batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := []any{*p.SubjectKey}
+	readMatch := matchClause("data::jsonb", p.SubjectPaths, &args)
+	writeMatch := matchClause("data", p.SubjectPaths, &args)
+	args = append(args, limit)
+	eventIDs, err := collectIDs(ctx, tx,
+		`SELECT id FROM msg_events_read WHERE `+readMatch+`
+		 UNION
+		 SELECT id FROM msg_events WHERE projected_at IS NULL AND `+writeMatch+`
+		 LIMIT $`+fmt.Sprint(len(args)),
+		args...)
+	if err != nil {
+		return Report{}, 0, fmt.Errorf("purge batch: find events: %w", err)
+	}
+	if len(eventIDs) == 0 {
+		return Report{}, 0, nil
+	}
+	jobIDs, err := collectIDs(ctx, tx,
+		`SELECT id FROM msg_dispatch_jobs_read WHERE event_id = ANY($1)
+		 UNION
+		 SELECT id FROM msg_dispatch_jobs WHERE projected_at IS NULL AND event_id = ANY($1)`,
+		eventIDs)
+	if err != nil {
+		return Report{}, 0, fmt.Errorf("purge batch: find dispatch jobs: %w", err)
+	}
+
+	steps := []purgeStep{
+		{&report.Attempts, `DELETE FROM msg_dispatch_job_attempts WHERE dispatch_job_id = ANY($1)`, jobIDs},
+		{&report.DispatchJobs, `DELETE FROM msg_dispatch_jobs WHERE id = ANY($1)`, jobIDs},
+		{&report.DispatchJobsRead, `DELETE FROM msg_dispatch_jobs_read WHERE id = ANY($1)`, jobIDs},
+		{&report.Events, `DELETE FROM msg_events WHERE id = ANY($1)`, eventIDs},
+		{&report.EventsRead, `DELETE FROM msg_events_read WHERE id = ANY($1)`, eventIDs},
+	}
+	if p.Mode == ModeAnonymize {
+		// msg_dispatch_jobs_read carries no payload columns; it is left alone.
+		steps = []purgeStep{
+			{&report.Attempts, `UPDATE msg_dispatch_job_attempts SET response_body = NULL
+			   WHERE dispatch_job_id = ANY($1) AND response_body IS NOT NULL`, jobIDs},
+			{&report.DispatchJobs, `UPDATE msg_dispatch_jobs SET payload = NULL, metadata = '[]'::jsonb WHERE id = ANY($1)`, jobIDs},
+			{&report.Events, `UPDATE msg_events SET data = NULL, context_data = NULL WHERE id = ANY($1)`, eventIDs},
+			{&report.EventsRead, `UPDATE msg_events_read SET data = NULL WHERE id = ANY($1)`, eventIDs},
+		}
+	}
+	for _, s := range steps {
+		if len(s.ids) == 0 {
+			continue
+		}
+		tag, err := tx.Exec(ctx, s.sql, s.ids)
+		if err != nil {
+			return Report{}, 0, fmt.Errorf("purge batch: %w", err)
+		}
+		*s.count = tag.RowsAffected()
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Report{}, 0, fmt.Errorf("purge batch: commit: %w", err)
+	}
+	return report, len(eventIDs), nil
+}
+
+// purgeStep is one statement of a batch; ids binds $1 and the affected
+// row count lands in count.
+type purgeStep struct {
+	count *int64
+	sql   string
+	ids   []string
+}
+
+// matchClause is the WHERE predicate selecting events whose data (the
+// dataExpr column, as jsonb) holds the subject key — args[0] — at their
+// type's path. Explicitly configured types are matched on their own
+// path; the "*" path covers every other type. Parameters are appended to
+// args.
+func matchClause(dataExpr string, paths map[string]string, args *[]any) string {
+	types := make([]string, 0, len(paths))
+	for t := range paths {
+		if t != AnyType {
+			types = append(types, t)
+		}
+	}
+	slices.Sort(types)
+	arg := func(v any) int {
+		*args = append(*args, v)
+		return len(*args)
+	}
+	var ors []string
+	for _, t := range types {
+		segs, _ := SplitPath(paths[t])
+		ors = append(ors, fmt.Sprintf("(type = $%d AND %s #>> $%d::text[] = $1)", arg(t), dataExpr, arg(segs)))
+	}
+	if p, ok := paths[AnyType]; ok {
+		segs, _ := SplitPath(p)
+		ors = append(ors, fmt.Sprintf("(type <> ALL($%d::text[]) AND %s #>> $%d::text[] = $1)", arg(types), dataExpr, arg(segs)))
+	}
+	if len(ors) == 0 {
+		return "FALSE"
+	}
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+func collectIDs(ctx context.Context, tx pgx.Tx, sql string, args ...any) ([]string, error) {
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
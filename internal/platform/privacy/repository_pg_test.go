@@ -0,0 +1,91 @@
+//go:build integration
+
+package privacy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+// TestPurgeBatch covers both modes end to end: the subject's events are
+// found on the write side (unprojected) and the read side, their dispatch
+// jobs and attempts go with them, another subject's rows are untouched,
+// and a second pass finds nothing left to do.
+func TestPurgeBatch(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := NewRepository(pool)
+	now := time.Now().UTC()
+
+	seed := func(prefix, typ, email string) {
+		t.Helper()
+		data := `{"customer":{"email":"` + email + `"}}`
+		_, err := pool.Exec(ctx,
+			`INSERT INTO msg_events (id, type, source, time, data, context_data, created_at)
+			 VALUES ($1, $2, 'test://privacy', $3, $4, '{"ip":"1.2.3.4"}', $3)`,
+			prefix+"evt1", typ, now, data)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx,
+			`INSERT INTO msg_events_read (id, type, source, time, data, created_at)
+			 VALUES ($1, $2, 'test://privacy', $3, $4, $3)`,
+			prefix+"evt2", typ, now, data)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx,
+			`INSERT INTO msg_dispatch_jobs (id, code, target_url, event_id, payload, created_at)
+			 VALUES ($1, $2, 'http://example.test', $3, $4, $5)`,
+			prefix+"dsj1", typ, prefix+"evt1", data, now)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx,
+			`INSERT INTO msg_dispatch_job_attempts (id, dispatch_job_id, attempt_number, response_body, created_at)
+			 VALUES ($1, $2, 1, 'echo', $3)`,
+			prefix+"att1", prefix+"dsj1", now)
+		require.NoError(t, err)
+	}
+	count := func(table, prefix string) (n int) {
+		t.Helper()
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT count(*) FROM `+table+` WHERE id LIKE $1`, prefix+"%").Scan(&n))
+		return n
+	}
+	paths := map[string]string{"privacy.test.order": "customer.email"}
+
+	seed("prvdel", "privacy.test.order", "gone@example.test")
+	seed("prvkep", "privacy.test.order", "kept@example.test")
+	p := New(ModeDelete, "gone@example.test", paths, nil, nil)
+	report, found, err := repo.PurgeBatch(ctx, p, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found)
+	assert.Equal(t, Report{Events: 1, EventsRead: 1, DispatchJobs: 1, Attempts: 1}, report)
+	for _, table := range []string{"msg_events", "msg_events_read", "msg_dispatch_jobs", "msg_dispatch_job_attempts"} {
+		assert.Zero(t, count(table, "prvdel"), table)
+		assert.Equal(t, 1, count(table, "prvkep"), table)
+	}
+	_, found, err = repo.PurgeBatch(ctx, p, 100)
+	require.NoError(t, err)
+	assert.Zero(t, found)
+
+	seed("prvano", "privacy.test.other", "anon@example.test")
+	p = New(ModeAnonymize, "anon@example.test", map[string]string{AnyType: "customer.email"}, nil, nil)
+	report, found, err = repo.PurgeBatch(ctx, p, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found)
+	assert.Equal(t, Report{Events: 1, EventsRead: 1, DispatchJobs: 1, Attempts: 1}, report)
+	assert.Equal(t, 1, count("msg_events", "prvano"), "anonymized rows are kept")
+	var cleared bool
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT e.data IS NULL AND e.context_data IS NULL AND j.payload IS NULL AND a.response_body IS NULL
+		   FROM msg_events e, msg_dispatch_jobs j, msg_dispatch_job_attempts a
+		  WHERE e.id = 'prvanoevt1' AND j.id = 'prvanodsj1' AND a.id = 'prvanoatt1'`).Scan(&cleared))
+	assert.True(t, cleared)
+	_, found, err = repo.PurgeBatch(ctx, p, 100)
+	require.NoError(t, err)
+	assert.Zero(t, found)
+}
@@ -0,0 +1,111 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// auditEntityType matches what the UoW sink derives from the
+// PurgeRequested subject, so request and completion rows line up.
+const auditEntityType = "Privacypurge"
+
+// Runner claims queued purges and works through them batch by batch, one
+// purge at a time per instance. Construct with NewRunner and run Run in
+// its own goroutine.
+type Runner struct {
+	cfg   Config
+	repo  *Repository
+	audit *audit.Repository
+}
+
+// NewRunner wires a runner.
+func NewRunner(cfg Config, repo *Repository, auditRepo *audit.Repository) *Runner {
+	return &Runner{cfg: cfg, repo: repo, audit: auditRepo}
+}
+
+// Run polls for work every PollInterval until ctx is cancelled, draining
+// the queue on each tick. A purge interrupted by shutdown stays RUNNING
+// and is resumed by whichever instance next sees its heartbeat go stale.
+func (r *Runner) Run(ctx context.Context) {
+	t := time.NewTicker(r.cfg.PollInterval)
+	defer t.Stop()
+	slog.Info("privacy purge runner starting", "interval", r.cfg.PollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("privacy purge runner stopped")
+			return
+		case <-t.C:
+			for ctx.Err() == nil {
+				ran, err := r.runOnce(ctx)
+				if err != nil {
+					slog.Warn("privacy purge runner error", "err", err)
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
+
+// runOnce claims and runs one purge. ran is false when the queue is empty.
+func (r *Runner) runOnce(ctx context.Context) (ran bool, err error) {
+	p, err := r.repo.Claim(ctx, r.cfg.StaleAfter)
+	if err != nil || p == nil {
+		return false, err
+	}
+	slog.Info("privacy purge started", "purge_id", p.ID, "mode", p.Mode, "subject_hash", p.SubjectHash)
+	report := p.Report
+	for {
+		batch, found, err := r.repo.PurgeBatch(ctx, p, r.cfg.BatchSize)
+		if ctx.Err() != nil {
+			return true, nil
+		}
+		if err != nil {
+			slog.Warn("privacy purge failed", "purge_id", p.ID, "err", err)
+			return true, r.repo.MarkFailed(ctx, p.ID, err.Error(), report)
+		}
+		if found == 0 {
+			break
+		}
+		report.Add(batch)
+		if err := r.repo.SaveProgress(ctx, p.ID, report); err != nil {
+			slog.Warn("privacy purge progress write failed", "purge_id", p.ID, "err", err)
+		}
+	}
+	if err := r.repo.MarkCompleted(ctx, p.ID, report); err != nil {
+		return true, err
+	}
+	slog.Info("privacy purge completed", "purge_id", p.ID,
+		"events", report.Events, "dispatch_jobs", report.DispatchJobs, "attempts", report.Attempts)
+	return true, r.writeAudit(ctx, p, report)
+}
+
+// writeAudit records the completion report against the purge, next to
+// the request row the UoW wrote. Like the request, it names the subject
+// only by hash.
+func (r *Runner) writeAudit(ctx context.Context, p *Purge, report Report) error {
+	op, err := json.Marshal(struct {
+		Mode        Mode   `json:"mode"`
+		SubjectHash string `json:"subjectHash"`
+		Report      Report `json:"report"`
+	}{p.Mode, p.SubjectHash, report})
+	if err != nil {
+		return err
+	}
+	return r.audit.Insert(ctx, &audit.Log{
+		ID:            tsid.Generate(tsid.AuditLog),
+		EntityType:    auditEntityType,
+		EntityID:      p.ID,
+		Operation:     "CompletePrivacyPurge",
+		OperationJSON: op,
+		PrincipalID:   p.RequestedBy,
+		PerformedAt:   time.Now().UTC(),
+	})
+}
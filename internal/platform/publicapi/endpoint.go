@@ -3,10 +3,12 @@
 //
 //	GET /api/public/platform     — feature flags shown on the login page
 //	GET /api/public/login-theme  — branded login-page theme (logo, colours, …)
+//	GET /api/meta/egress-ips     — addresses webhook deliveries come from
 //
-// Mirrors crates/fc-platform/src/shared/public_api.rs. Both endpoints
+// The first two mirror crates/fc-platform/src/shared/public_api.rs. All
 // are read-only and intentionally low-privilege — the SPA hits them
-// before the user signs in.
+// before the user signs in, and customers read the egress addresses into
+// their firewall allowlists.
 package publicapi
 
 import (
@@ -19,11 +21,13 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/branding"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
 )
 
 // Endpoint bundles the deps for the public API.
 type Endpoint struct {
 	configs *platformconfig.Repository
+	egress  *egress.Policy // optional; set via SetEgress
 }
 
 // New wires an Endpoint.
@@ -31,11 +35,17 @@ func New(configs *platformconfig.Repository) *Endpoint {
 	return &Endpoint{configs: configs}
 }
 
-// RegisterRoutes mounts /api/public/platform and /api/public/login-theme
-// on r. Callers MUST mount r outside any bearer-auth middleware.
+// SetEgress supplies the egress policy whose public addresses
+// /api/meta/egress-ips publishes. Unset: the list is empty.
+func (e *Endpoint) SetEgress(p *egress.Policy) { e.egress = p }
+
+// RegisterRoutes mounts /api/public/platform, /api/public/login-theme and
+// /api/meta/egress-ips on r. Callers MUST mount r outside any bearer-auth
+// middleware.
 func (e *Endpoint) RegisterRoutes(r chi.Router) {
 	r.Get("/api/public/platform", e.handlePlatform)
 	r.Get("/api/public/login-theme", e.handleLoginTheme)
+	r.Get("/api/meta/egress-ips", e.handleEgressIPs)
 	// SPA bootstrap path — same payload as /api/public/platform but at
 	// the path the embedded frontend's platformConfig store fetches.
 	// Mirrors Rust's `/api/config/platform` (platform_config_router).
@@ -107,6 +117,20 @@ func loadLoginTheme(ctx context.Context, configs *platformconfig.Repository) log
 	return out
 }
 
+// egressIPsResponse lists the IPs and CIDRs deliveries leave from. Empty
+// when the deployment hasn't published them (FC_EGRESS_PUBLIC_IPS).
+type egressIPsResponse struct {
+	IPs []string `json:"ips"`
+}
+
+func (e *Endpoint) handleEgressIPs(w http.ResponseWriter, _ *http.Request) {
+	ips := []string{}
+	if e.egress != nil {
+		ips = e.egress.PublicIPs()
+	}
+	writeJSON(w, http.StatusOK, egressIPsResponse{IPs: ips})
+}
+
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -14,6 +14,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -114,7 +115,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateRoleRequest])
 	if err := auth.CanWriteRoles(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateRole(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -137,7 +138,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateRole(s.Repo), in.Body.toCommand(r.ID), ec); err != nil {
 		return nil, err
 	}
@@ -154,7 +155,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteRole(s.Repo), operations.DeleteCommand{ID: r.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -263,7 +264,7 @@ func (s *State) grantPermission(ctx context.Context, in *rolePermissionGrantInpu
 	if err := auth.CanWriteRoles(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.GrantPermission(s.Repo), operations.GrantPermissionCommand{
 		RoleName: in.RoleName, Permission: in.Permission,
 	}, ec); err != nil {
@@ -291,7 +292,7 @@ func (s *State) grantPermissionByBody(ctx context.Context, in *rolePermissionGra
 	if err := auth.CanWriteRoles(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.GrantPermission(s.Repo), operations.GrantPermissionCommand{
 		RoleName: in.RoleName, Permission: in.Body.Permission,
 	}, ec); err != nil {
@@ -310,7 +311,7 @@ func (s *State) revokePermission(ctx context.Context, in *rolePermissionGrantInp
 	if err := auth.CanWriteRoles(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.RevokePermission(s.Repo), operations.RevokePermissionCommand{
 		RoleName: in.RoleName, Permission: in.Permission,
 	}, ec); err != nil {
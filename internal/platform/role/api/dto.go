@@ -106,18 +106,22 @@ type RolePermissionListResponse struct {
 
 // PermissionResponse is the wire shape for a catalog row.
 type PermissionResponse struct {
-	Permission  string  `json:"permission"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	Category    *string `json:"category,omitempty"`
+	Permission    string  `json:"permission"`
+	Name          string  `json:"name"`
+	Description   *string `json:"description,omitempty"`
+	Category      *string `json:"category,omitempty"`
+	ApplicationID *string `json:"applicationId,omitempty" doc:"Registering application, for SDK entries"`
+	Source        string  `json:"source" enum:"CODE,DATABASE,SDK" doc:"CODE: platform built-in; SDK: registered by an application; DATABASE: created in the dashboard"`
 }
 
 func permissionToResponse(p *role.Permission) PermissionResponse {
 	return PermissionResponse{
-		Permission:  p.Permission,
-		Name:        p.Name,
-		Description: p.Description,
-		Category:    p.Category,
+		Permission:    p.Permission,
+		Name:          p.Name,
+		Description:   p.Description,
+		Category:      p.Category,
+		ApplicationID: p.ApplicationID,
+		Source:        string(p.Source),
 	}
 }
 
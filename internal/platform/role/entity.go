@@ -30,12 +30,17 @@ func ParseSource(s string) Source {
 	}
 }
 
-// Permission is the per-permission catalog entry.
+// Permission is the per-permission catalog entry. Source is CODE for
+// the seeded platform built-ins, SDK for an application's registered
+// catalog and DATABASE for entries created from the dashboard;
+// ApplicationID is set on SDK entries.
 type Permission struct {
-	Permission  string  `json:"permission"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	Category    *string `json:"category,omitempty"`
+	Permission    string  `json:"permission"`
+	Name          string  `json:"name"`
+	Description   *string `json:"description,omitempty"`
+	Category      *string `json:"category,omitempty"`
+	ApplicationID *string `json:"applicationId,omitempty"`
+	Source        Source  `json:"source"`
 }
 
 // Role is the aggregate root. Mirrors AuthRole in Rust.
@@ -119,6 +124,54 @@ func (r *Role) RevokePermission(p string) {
 	r.UpdatedAt = time.Now().UTC()
 }
 
+// UnknownPermissions returns the entries of perms that catalog (the
+// permission codes in iam_permissions) doesn't cover, in order. Only
+// namespaces — first segments — with a catalog are checked: the platform,
+// whose built-ins are seeded at startup, and each application that has
+// registered its permissions. There an exact code must be in the catalog
+// and a wildcard pattern must match at least one entry. Permissions in a
+// namespace with no catalog pass, so applications that never registered
+// one keep composing roles freely.
+func UnknownPermissions(catalog, perms []string) []string {
+	codes := make(map[string]struct{}, len(catalog))
+	namespaces := make(map[string]struct{})
+	for _, c := range catalog {
+		codes[c] = struct{}{}
+		namespaces[namespaceOf(c)] = struct{}{}
+	}
+	var unknown []string
+	for _, p := range perms {
+		if _, ok := codes[p]; ok {
+			continue
+		}
+		ns := namespaceOf(p)
+		if _, ok := namespaces[ns]; !ok && ns != "*" {
+			continue
+		}
+		if ns == "*" && len(catalog) == 0 {
+			continue
+		}
+		if !strings.Contains(p, "*") || !matchesAny(p, catalog) {
+			unknown = append(unknown, p)
+		}
+	}
+	return unknown
+}
+
+func namespaceOf(p string) string {
+	ns, _, _ := strings.Cut(p, ":")
+	return ns
+}
+
+func matchesAny(pattern string, codes []string) bool {
+	for _, c := range codes {
+		if matchesWildcard(pattern, c) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchesWildcard checks 4-segment wildcard match: pattern segments
 // may be `*` to match any value.
 func matchesWildcard(pattern, value string) bool {
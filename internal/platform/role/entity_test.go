@@ -0,0 +1,38 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownPermissions(t *testing.T) {
+	catalog := []string{
+		"platform:iam:user:view",
+		"platform:iam:user:create",
+		"orders:sales:order:view",
+	}
+	cases := []struct {
+		name  string
+		perms []string
+		want  []string
+	}{
+		{"exact codes", []string{"platform:iam:user:view", "orders:sales:order:view"}, nil},
+		{"undeclared code in a cataloged namespace", []string{"platform:iam:user:purge", "orders:sales:order:ship"},
+			[]string{"platform:iam:user:purge", "orders:sales:order:ship"}},
+		{"wildcard matching an entry", []string{"platform:iam:*:*", "orders:*:order:view", "*:*:*:*"}, nil},
+		{"wildcard matching nothing", []string{"platform:messaging:*:*", "*:*:*:delete"},
+			[]string{"platform:messaging:*:*", "*:*:*:delete"}},
+		{"namespace without a catalog", []string{"billing:invoice:invoice:view", "billing:*:*:*"}, nil},
+		{"malformed in a cataloged namespace", []string{"platform:iam:user"}, []string{"platform:iam:user"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, UnknownPermissions(catalog, c.perms))
+		})
+	}
+}
+
+func TestUnknownPermissions_EmptyCatalogAcceptsEverything(t *testing.T) {
+	assert.Empty(t, UnknownPermissions(nil, []string{"platform:iam:user:view", "*:*:*:*"}))
+}
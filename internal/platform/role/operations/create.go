@@ -19,7 +19,8 @@ type CreateCommand struct {
 	ClientManaged   bool     `json:"clientManaged"`
 }
 
-// CreateRole creates a new role and emits RoleCreated.
+// CreateRole creates a new role and emits RoleCreated. Its permissions
+// must be covered by the permission catalog.
 func CreateRole(repo *role.Repository) usecaseop.Operation[CreateCommand, RoleCreated] {
 	return usecaseop.Operation[CreateCommand, RoleCreated]{
 		Name: "CreateRole",
@@ -48,6 +49,9 @@ func CreateRole(repo *role.Repository) usecaseop.Operation[CreateCommand, RoleCr
 			if existing != nil {
 				return nil, usecase.Conflict("ROLE_EXISTS", "Role '"+fullName+"' already exists")
 			}
+			if err := requireCataloged(ctx, repo, cmd.Permissions); err != nil {
+				return nil, err
+			}
 			r := role.New(cmd.ApplicationCode, cmd.RoleName, cmd.DisplayName)
 			r.Description = cmd.Description
 			r.ClientManaged = cmd.ClientManaged
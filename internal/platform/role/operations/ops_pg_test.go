@@ -630,3 +630,50 @@ func TestSyncPlatformRoles_CatalogueLifecycle(t *testing.T) {
 	require.NotNil(t, admin, "production catalogue rows must never be swept")
 	assert.Equal(t, role.SourceCode, admin.Source)
 }
+
+// ── Permission catalog ───────────────────────────────────────────────────
+
+func TestRoleComposition_ValidatedAgainstCatalog(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	repo := role.NewRepository(pool)
+	perms := role.NewPermissionRepo(pool)
+	uow := testpg.NewUoW(t)
+
+	// No catalog yet: the namespace composes freely.
+	mustCreate(t, repo, uow, "rolecat", "before", "Before", "rolecat:doc:anything:goes")
+
+	appID := "app_rolecat00001"
+	res, err := perms.Sync(ctx, role.SourceSDK, &appID, []role.Permission{
+		{Permission: "rolecat:doc:doc:view", Category: ptr("Documents")},
+		{Permission: "rolecat:doc:doc:edit"},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), res.Created)
+
+	mustCreate(t, repo, uow, "rolecat", "viewer", "Viewer", "rolecat:doc:doc:view", "rolecat:doc:*:edit")
+
+	_, err = runAuthorized(uow, operations.CreateRole(repo), operations.CreateCommand{
+		ApplicationCode: "rolecat", RoleName: "bad", DisplayName: "Bad",
+		Permissions: []string{"rolecat:doc:doc:view", "rolecat:doc:doc:delete"},
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "UNKNOWN_PERMISSION")
+
+	_, err = runAuthorized(uow, operations.GrantPermission(repo), operations.GrantPermissionCommand{
+		RoleName: "rolecat:viewer", Permission: "rolecat:admin:*:*",
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "UNKNOWN_PERMISSION")
+
+	// Re-registering without removeUnlisted keeps the rest; with it, prunes.
+	res, err = perms.Sync(ctx, role.SourceSDK, &appID, []role.Permission{{Permission: "rolecat:doc:doc:view"}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), res.Updated)
+	assert.Equal(t, uint32(1), res.Deleted)
+	got, err := perms.FindByApplicationID(ctx, appID)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, role.SourceSDK, got[0].Source)
+	assert.Equal(t, "rolecat:doc:doc", *got[0].Category,
+		"registration replaces the category; without one it falls back to the code's triple")
+}
@@ -21,7 +21,8 @@ type GrantPermissionCommand struct {
 }
 
 // GrantPermission adds Permission to the role's permission set and
-// emits [RolePermissionGranted].
+// emits [RolePermissionGranted]. The permission must be covered by the
+// permission catalog.
 func GrantPermission(repo *role.Repository) usecaseop.Operation[GrantPermissionCommand, RolePermissionGranted] {
 	return usecaseop.Operation[GrantPermissionCommand, RolePermissionGranted]{
 		Name: "GrantPermission",
@@ -46,6 +47,9 @@ func GrantPermission(repo *role.Repository) usecaseop.Operation[GrantPermissionC
 			if r == nil {
 				return nil, httperror.NotFound("Role", cmd.RoleName)
 			}
+			if err := requireCataloged(ctx, repo, []string{cmd.Permission}); err != nil {
+				return nil, err
+			}
 			r.GrantPermission(cmd.Permission)
 			event := RolePermissionGranted{
 				Metadata:   usecase.NewEventMetadata(ec, RolePermissionGrantedType, Source, subjectFor(r.ID)),
@@ -101,6 +105,20 @@ func RevokePermission(repo *role.Repository) usecaseop.Operation[RevokePermissio
 	}
 }
 
+// requireCataloged fails with UNKNOWN_PERMISSION when the permission
+// catalog doesn't cover one of perms (see role.UnknownPermissions).
+func requireCataloged(ctx context.Context, repo *role.Repository, perms []string) error {
+	unknown, err := repo.UnknownPermissions(ctx, perms)
+	if err != nil {
+		return usecase.Internal("REPO", "permission catalog lookup failed", err)
+	}
+	if len(unknown) > 0 {
+		return usecase.Validation("UNKNOWN_PERMISSION",
+			"Permissions not in the catalog: "+strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
 // RolePermissionGranted — emitted on grant.
 type RolePermissionGranted struct {
 	Metadata   usecase.EventMetadata
@@ -174,6 +174,9 @@ type SyncRolesCommand struct {
 //     REFUSES (business-rule error ROLE_HAS_ASSIGNMENTS) when a role still
 //     has principal assignments — the junction has no DB-level FK, so a
 //     silent drop would orphan user role assignments.
+//   - Every permission in the payload must be covered by the permission
+//     catalog (UNKNOWN_PERMISSION otherwise); an application that registers
+//     its own permissions does so before syncing roles that use them.
 //
 // Authorization: the controller does the coarse "may sync roles" check and
 // resolves the application; the use case enforces the per-resource rule — the
@@ -200,6 +203,13 @@ func SyncRoles(repo *role.Repository) usecaseop.Operation[SyncRolesCommand, Role
 			return nil
 		},
 		Execute: func(ctx context.Context, cmd SyncRolesCommand, ec usecase.ExecutionContext) (usecaseop.Plan[RolesSynced], error) {
+			var perms []string
+			for _, in := range cmd.Roles {
+				perms = append(perms, in.Permissions...)
+			}
+			if err := requireCataloged(ctx, repo, perms); err != nil {
+				return nil, err
+			}
 			existing, err := repo.FindByApplicationID(ctx, cmd.ApplicationID)
 			if err != nil {
 				return nil, usecase.Internal("REPO", "find_by_application failed", err)
@@ -319,6 +329,14 @@ func SyncRoles(repo *role.Repository) usecaseop.Operation[SyncRolesCommand, Role
 	}
 }
 
+// SyncedRoleName is the canonical name SyncRoles stores for an SDK role
+// declared as name under applicationCode: "{applicationCode}:{short}", with
+// the name lower-cased and a leading "{applicationCode}:" stripped so an
+// already-qualified name is not double-prefixed.
+func SyncedRoleName(applicationCode, name string) string {
+	return applicationCode + ":" + splitRoleName(strings.ToLower(name), applicationCode)
+}
+
 // displayNameOr returns *dn when non-nil, else the fallback. Mirrors Rust
 // `display_name.unwrap_or_else(|| name.clone())`.
 func displayNameOr(dn *string, fallback string) string {
@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
@@ -21,7 +22,8 @@ type UpdateCommand struct {
 }
 
 // UpdateRole mutates an existing role and emits RoleUpdated. Roles with
-// source=CODE are immutable.
+// source=CODE are immutable; added permissions must be covered by the
+// permission catalog.
 func UpdateRole(repo *role.Repository) usecaseop.Operation[UpdateCommand, RoleUpdated] {
 	return usecaseop.Operation[UpdateCommand, RoleUpdated]{
 		Name: "UpdateRole",
@@ -56,6 +58,17 @@ func UpdateRole(repo *role.Repository) usecaseop.Operation[UpdateCommand, RoleUp
 				r.Description = cmd.Description
 			}
 			if cmd.Permissions != nil {
+				// Only the additions are checked: a grant the catalog has since
+				// dropped doesn't block an unrelated edit.
+				var added []string
+				for _, p := range cmd.Permissions {
+					if !slices.Contains(r.Permissions, p) {
+						added = append(added, p)
+					}
+				}
+				if err := requireCataloged(ctx, repo, added); err != nil {
+					return nil, err
+				}
 				r.Permissions = cmd.Permissions
 			}
 			if cmd.ClientManaged != nil {
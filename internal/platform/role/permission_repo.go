@@ -18,11 +18,14 @@ import (
 // permission catalog half. The Permission rows live in iam_permissions;
 // individual roles still grant permissions via iam_role_permissions
 // (handled by Repository).
-type PermissionRepo struct{ q *dbq.Queries }
+type PermissionRepo struct {
+	pool *pgxpool.Pool
+	q    *dbq.Queries
+}
 
 // NewPermissionRepo wires the catalog repo.
 func NewPermissionRepo(pool *pgxpool.Pool) *PermissionRepo {
-	return &PermissionRepo{q: dbq.New(pool)}
+	return &PermissionRepo{pool: pool, q: dbq.New(pool)}
 }
 
 // FindAll returns every catalog row, ordered by code.
@@ -38,6 +41,20 @@ func (r *PermissionRepo) FindAll(ctx context.Context) ([]Permission, error) {
 	return out, nil
 }
 
+// FindByApplicationID returns an application's registered catalog,
+// ordered by code.
+func (r *PermissionRepo) FindByApplicationID(ctx context.Context, applicationID string) ([]Permission, error) {
+	rows, err := r.q.PermissionFindByApplicationID(ctx, &applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("permission_find_by_application_id: %w", err)
+	}
+	out := make([]Permission, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, permissionFromRow(row))
+	}
+	return out, nil
+}
+
 // FindByCode loads one catalog row or returns nil if absent.
 func (r *PermissionRepo) FindByCode(ctx context.Context, code string) (*Permission, error) {
 	row, err := r.q.PermissionFindByCode(ctx, code)
@@ -62,34 +79,102 @@ func (r *PermissionRepo) DeleteByCode(ctx context.Context, code string) error {
 // "application:context:aggregate:action"; subdomain carries the application
 // segment so the row round-trips through permissionFromRow's Category. Unlike
 // permissions that exist only as strings attached to roles, catalogue rows
-// persist independently of any role and survive SDK role re-syncs.
+// persist independently of any role and survive SDK role re-syncs. An empty
+// Source writes DATABASE; a CODE row is only overwritten by another CODE
+// write.
 func (r *PermissionRepo) Upsert(ctx context.Context, p Permission) error {
+	return upsertPermission(ctx, r.q, p)
+}
+
+// PermissionSyncResult counts what a Sync changed.
+type PermissionSyncResult struct {
+	Created uint32
+	Updated uint32
+	Deleted uint32
+	Codes   []string
+}
+
+// Sync upserts perms as one source's catalog — the platform built-ins
+// (CODE, applicationID nil) or one application's registration (SDK) —
+// in a single transaction. With removeUnlisted, that source's rows for
+// applicationID that perms doesn't list are deleted; other sources' rows
+// are never touched.
+func (r *PermissionRepo) Sync(ctx context.Context, source Source, applicationID *string, perms []Permission, removeUnlisted bool) (PermissionSyncResult, error) {
+	var res PermissionSyncResult
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		q := r.q.WithTx(tx)
+		for _, p := range perms {
+			_, err := q.PermissionFindByCode(ctx, p.Permission)
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				res.Created++
+			case err != nil:
+				return fmt.Errorf("permission_sync: %w", err)
+			default:
+				res.Updated++
+			}
+			p.Source = source
+			p.ApplicationID = applicationID
+			if err := upsertPermission(ctx, q, p); err != nil {
+				return err
+			}
+			res.Codes = append(res.Codes, p.Permission)
+		}
+		if !removeUnlisted {
+			return nil
+		}
+		n, err := q.PermissionDeleteUnlisted(ctx, dbq.PermissionDeleteUnlistedParams{
+			Source:        string(source),
+			ApplicationID: applicationID,
+			Codes:         append([]string{}, res.Codes...),
+		})
+		if err != nil {
+			return fmt.Errorf("permission_sync: %w", err)
+		}
+		res.Deleted = uint32(n)
+		return nil
+	})
+	return res, err
+}
+
+func upsertPermission(ctx context.Context, q *dbq.Queries, p Permission) error {
 	parts := strings.Split(p.Permission, ":")
 	if len(parts) != 4 {
 		return fmt.Errorf("permission upsert: malformed code %q (want application:context:aggregate:action)", p.Permission)
 	}
-	return r.q.PermissionUpsert(ctx, dbq.PermissionUpsertParams{
-		ID:          tsid.Generate(tsid.Permission),
-		Code:        p.Permission,
-		Subdomain:   parts[0],
-		Context:     parts[1],
-		Aggregate:   parts[2],
-		Action:      parts[3],
-		Description: p.Description,
+	source := p.Source
+	if source == "" {
+		source = SourceDatabase
+	}
+	return q.PermissionUpsert(ctx, dbq.PermissionUpsertParams{
+		ID:            tsid.Generate(tsid.Permission),
+		Code:          p.Permission,
+		Subdomain:     parts[0],
+		Context:       parts[1],
+		Aggregate:     parts[2],
+		Action:        parts[3],
+		Description:   p.Description,
+		ApplicationID: p.ApplicationID,
+		Category:      p.Category,
+		Source:        string(source),
 	})
 }
 
 func permissionFromRow(row dbq.IamPermission) Permission {
 	out := Permission{
-		Permission: row.Code,
-		Name:       row.Code, // catalog has no display name; Rust falls back to code
+		Permission:    row.Code,
+		Name:          row.Code, // catalog has no display name; Rust falls back to code
+		Description:   row.Description,
+		Category:      row.Category,
+		ApplicationID: row.ApplicationID,
+		Source:        ParseSource(row.Source),
 	}
-	if row.Description != nil {
-		out.Description = row.Description
+	if out.Category == nil {
+		// No registered category: construct one from the
+		// subdomain/context/aggregate triple, mirroring how Rust groups
+		// permissions in the filter UI.
+		cat := row.Subdomain + ":" + row.Context + ":" + row.Aggregate
+		out.Category = &cat
 	}
-	// Construct a Category from the subdomain/context/aggregate triple,
-	// mirroring how Rust groups permissions in the filter UI.
-	cat := row.Subdomain + ":" + row.Context + ":" + row.Aggregate
-	out.Category = &cat
 	return out
 }
@@ -129,6 +129,19 @@ func (r *Repository) ApplicationCodes(ctx context.Context) ([]string, error) {
 	return out, nil
 }
 
+// UnknownPermissions returns the entries of perms the permission catalog
+// doesn't cover; see [UnknownPermissions].
+func (r *Repository) UnknownPermissions(ctx context.Context, perms []string) ([]string, error) {
+	if len(perms) == 0 {
+		return nil, nil
+	}
+	catalog, err := r.q.PermissionCodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("permission_codes: %w", err)
+	}
+	return UnknownPermissions(catalog, perms), nil
+}
+
 // Persist implements usecasepgx.Persist[Role]. Replaces the role
 // permissions wholesale.
 func (r *Repository) Persist(ctx context.Context, role *Role, tx *usecasepgx.DbTx) error {
@@ -14,6 +14,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
@@ -76,12 +77,6 @@ func (s *State) list(ctx context.Context, in *listInput) (*apicommon.Out[apicomm
 		}
 	}
 	filters.Search = apicommon.OptStr(in.Search)
-	// Scope to accessible clients in SQL (anchor sees all → no scoping) so
-	// COUNT and LIMIT/OFFSET stay consistent across pages.
-	if !ac.IsAnchor() {
-		clients := ac.Clients
-		filters.AccessibleClientIDs = &clients
-	}
 	total, err := s.Repo.CountWithFilters(ctx, filters)
 	if err != nil {
 		return nil, usecase.Internal("REPO", "count_with_filters failed", err)
@@ -134,7 +129,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateScheduledJobR
 	// The client-scope check (client-scoped job → access that client; platform-
 	// scoped → anchor) lives in CreateScheduledJob's Authorize phase, against
 	// cmd.ClientID.
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateScheduledJob(s.Repo), in.Body.toCommand(), ec)
 	if err != nil {
 		return nil, err
@@ -158,7 +153,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanWriteScheduledJobs(ac); err != nil {
 		return nil, err
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateScheduledJob(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -170,7 +165,7 @@ func (s *State) pause(ctx context.Context, in *apicommon.IDInput) (*apicommon.Em
 	if err := auth.CanWriteScheduledJobs(ac); err != nil {
 		return nil, err
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.PauseScheduledJob(s.Repo), operations.PauseCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -182,7 +177,7 @@ func (s *State) resume(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanWriteScheduledJobs(ac); err != nil {
 		return nil, err
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ResumeScheduledJob(s.Repo), operations.ResumeCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -194,7 +189,7 @@ func (s *State) archive(ctx context.Context, in *apicommon.IDInput) (*apicommon.
 	if err := auth.CanWriteScheduledJobs(ac); err != nil {
 		return nil, err
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ArchiveScheduledJob(s.Repo), operations.ArchiveCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -215,7 +210,7 @@ func (s *State) fireNow(ctx context.Context, in *fireNowInput) (*apicommon.Out[F
 	if in.Body != nil {
 		correlationID = in.Body.CorrelationID
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.FireNow(s.Repo, s.Instances),
 		operations.FireNowCommand{ID: in.ID, CorrelationID: correlationID}, ec)
 	if err != nil {
@@ -233,7 +228,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteScheduledJobs(ac); err != nil {
 		return nil, err
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteScheduledJob(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
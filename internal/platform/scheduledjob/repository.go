@@ -11,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
@@ -73,7 +74,8 @@ func (r *Repository) FindByCode(ctx context.Context, code string, clientID *stri
 //   - Search: case-insensitive prefix match against code OR name.
 //   - AccessibleClientIDs: a non-nil pointer scopes results to
 //     platform-scoped jobs (client_id IS NULL) plus jobs whose client_id is
-//     in the set; a nil pointer means "no access scoping" (anchor/all-access).
+//     in the set; a nil pointer means the calling request's scope
+//     (reqctx.ClientScope — none for anchors and non-request callers).
 //   - Limit / Offset: applied only by List; Count ignores them.
 type ListFilters struct {
 	ClientID            *string
@@ -91,6 +93,7 @@ type ListFilters struct {
 // code, with optional pagination. Hand-rolled dynamic query (mirrors
 // the application repo pattern).
 func (r *Repository) FindWithFilters(ctx context.Context, f ListFilters) ([]ScheduledJob, error) {
+	f.AccessibleClientIDs = reqctx.ClientScope(ctx, f.AccessibleClientIDs)
 	q, args := buildJobQuery(`SELECT id, client_id, code, name, description, status, crons, timezone,
 		payload, concurrent, tracks_completion, timeout_seconds,
 		delivery_max_attempts, target_url, last_fired_at, created_at, updated_at,
@@ -113,6 +116,7 @@ func (r *Repository) FindWithFilters(ctx context.Context, f ListFilters) ([]Sche
 // CountWithFilters returns the total job count for the filters,
 // ignoring Limit/Offset so callers can render pagination totals.
 func (r *Repository) CountWithFilters(ctx context.Context, f ListFilters) (int64, error) {
+	f.AccessibleClientIDs = reqctx.ClientScope(ctx, f.AccessibleClientIDs)
 	q, args := buildJobQuery(`SELECT COUNT(*) FROM msg_scheduled_jobs`, f, false)
 	var count int64
 	if err := r.pool.QueryRow(ctx, q, args...).Scan(&count); err != nil {
@@ -5,8 +5,14 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
+	"strings"
 )
 
+// deliveryTokenDomain separates delivery-token MACs from the job-id
+// bearer tokens signed with the same secret, so one can't stand in for
+// the other.
+const deliveryTokenDomain = "delivery-ack:"
+
 // DispatchAuthService signs dispatch-job IDs with HMAC-SHA256 so the
 // router's callback to /api/dispatch/process can prove it really
 // originated from a job the scheduler queued. Same construction as
@@ -30,3 +36,28 @@ func (s *DispatchAuthService) Verify(jobID, token string) bool {
 	expected := s.Sign(jobID)
 	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
 }
+
+// DeliveryToken returns the token a receiver acknowledges a delivery of
+// jobID with: the job id and its domain-separated HMAC, dot-joined. The
+// same for every delivery of the job, so it doubles as the receiver's
+// de-duplication key.
+func (s *DispatchAuthService) DeliveryToken(jobID string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(deliveryTokenDomain + jobID))
+	return jobID + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseDeliveryToken returns the job id a delivery token was issued for,
+// checking its MAC in constant time. ok is false for a malformed or
+// forged token.
+func (s *DispatchAuthService) ParseDeliveryToken(token string) (jobID string, ok bool) {
+	i := strings.LastIndexByte(token, '.')
+	if i <= 0 {
+		return "", false
+	}
+	jobID = token[:i]
+	if subtle.ConstantTimeCompare([]byte(s.DeliveryToken(jobID)), []byte(token)) != 1 {
+		return "", false
+	}
+	return jobID, true
+}
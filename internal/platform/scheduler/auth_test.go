@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryToken_RoundTrip(t *testing.T) {
+	s := NewDispatchAuthService("secret")
+	token := s.DeliveryToken("0HZXEQ5Y8JY5Z")
+	assert.Equal(t, token, s.DeliveryToken("0HZXEQ5Y8JY5Z"), "stable across deliveries")
+
+	jobID, ok := s.ParseDeliveryToken(token)
+	assert.True(t, ok)
+	assert.Equal(t, "0HZXEQ5Y8JY5Z", jobID)
+}
+
+func TestParseDeliveryToken_RejectsForgeries(t *testing.T) {
+	s := NewDispatchAuthService("secret")
+	token := s.DeliveryToken("0HZXEQ5Y8JY5Z")
+
+	for name, forged := range map[string]string{
+		"empty":          "",
+		"no mac":         "0HZXEQ5Y8JY5Z",
+		"no job id":      token[len("0HZXEQ5Y8JY5Z"):],
+		"other job":      "0HZXEQ5Y8JY60" + token[len("0HZXEQ5Y8JY5Z"):],
+		"other secret":   NewDispatchAuthService("other").DeliveryToken("0HZXEQ5Y8JY5Z"),
+		"process bearer": "0HZXEQ5Y8JY5Z." + s.Sign("0HZXEQ5Y8JY5Z"),
+	} {
+		_, ok := s.ParseDeliveryToken(forged)
+		assert.False(t, ok, name)
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCatchUpOrder(t *testing.T) {
+	for in, want := range map[string]CatchUpOrder{
+		"": CatchUpOldestFirst, "oldest_first": CatchUpOldestFirst, " NEWEST_FIRST ": CatchUpNewestFirst,
+	} {
+		got, err := ParseCatchUpOrder(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseCatchUpOrder("random")
+	assert.Error(t, err)
+}
+
+// The publish budget cuts a poll's claim below the batch size, refills at
+// the configured rate, and is never larger than one poll interval's worth.
+func TestClaimLimit_FollowsPublishBudget(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxPublishRate = 20 // 20/s with a 1s poll: burst 20 of the 100 batch
+	p := NewPendingJobPoller(cfg, nil, nil, nil, nil)
+	now := time.Now()
+
+	assert.Equal(t, 20, p.claimLimit(now))
+	p.limiter.AllowN(now, 20)
+	assert.Equal(t, 0, p.claimLimit(now), "budget spent")
+	assert.Equal(t, 10, p.claimLimit(now.Add(500*time.Millisecond)), "half a second refills half")
+	assert.Equal(t, 20, p.claimLimit(now.Add(time.Minute)), "refill stops at the burst")
+
+	uncapped := NewPendingJobPoller(DefaultConfig(), nil, nil, nil, nil)
+	assert.Equal(t, 100, uncapped.claimLimit(now))
+
+	// Every poll above was held under the 100 batch size by the cap.
+	p.metrics.published.Add(20)
+	want := `
+# HELP fc_scheduler_max_publish_per_second Configured publish rate cap; 0 is uncapped.
+# TYPE fc_scheduler_max_publish_per_second gauge
+fc_scheduler_max_publish_per_second 20
+# HELP fc_scheduler_published_total Dispatch jobs handed to the queue; rate() of it is the publish rate.
+# TYPE fc_scheduler_published_total counter
+fc_scheduler_published_total 20
+# HELP fc_scheduler_throttled_polls_total Polls cut short by FC_SCHEDULER_MAX_PUBLISH_PER_SEC.
+# TYPE fc_scheduler_throttled_polls_total counter
+fc_scheduler_throttled_polls_total 4
+`
+	require.NoError(t, testutil.CollectAndCompare(p.metrics, strings.NewReader(want),
+		"fc_scheduler_max_publish_per_second", "fc_scheduler_published_total", "fc_scheduler_throttled_polls_total"))
+}
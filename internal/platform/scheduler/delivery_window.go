@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
+)
+
+// DeliveryWindowCache caches the delivery calendars of subscriptions that
+// have one. The poller defers claims whose calendar is closed to its next
+// opening instead of dispatching them.
+type DeliveryWindowCache struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+
+	mu          sync.RWMutex
+	windows     map[string]*deliverywindow.Schedule
+	lastRefresh time.Time
+}
+
+// NewDeliveryWindowCache wires the cache.
+func NewDeliveryWindowCache(pool *pgxpool.Pool, ttl time.Duration) *DeliveryWindowCache {
+	return &DeliveryWindowCache{
+		pool:        pool,
+		ttl:         ttl,
+		windows:     make(map[string]*deliverywindow.Schedule),
+		lastRefresh: time.Now().Add(-2 * ttl), // force initial refresh
+	}
+}
+
+// Windows returns the cached calendars by subscription ID, refreshing if
+// stale. The schedules are shared and must not be modified.
+func (c *DeliveryWindowCache) Windows(ctx context.Context) (map[string]*deliverywindow.Schedule, error) {
+	c.mu.RLock()
+	fresh := time.Since(c.lastRefresh) < c.ttl
+	out := c.windows
+	c.mu.RUnlock()
+	if fresh {
+		return out, nil
+	}
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.windows, nil
+}
+
+func (c *DeliveryWindowCache) refresh(ctx context.Context) error {
+	rows, err := c.pool.Query(ctx,
+		`SELECT id, delivery_window FROM msg_subscriptions WHERE delivery_window IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	windows := make(map[string]*deliverywindow.Schedule)
+	for rows.Next() {
+		var (
+			id  string
+			raw []byte
+		)
+		if err := rows.Scan(&id, &raw); err != nil {
+			return err
+		}
+		s, err := deliverywindow.Parse(raw)
+		if err != nil {
+			// The API validates on write; a bad row is delivered as if it
+			// had no calendar rather than held forever.
+			slog.Warn("ignoring invalid delivery window", "subscription_id", id, "err", err)
+			continue
+		}
+		if s != nil {
+			windows[id] = s
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.windows = windows
+	c.lastRefresh = time.Now()
+	c.mu.Unlock()
+	slog.Debug("delivery window cache refreshed", "subscriptions", len(windows))
+	return nil
+}
+
+// filterDeliveryWindows splits claims on their subscription's calendar at
+// now. Open claims are returned with windowClosesAt set when the window
+// has an end; closed ones are grouped by the instant their calendar next
+// opens, for the poller to reschedule. A closed claim whose next opening
+// can't be found is left out of both and stays PENDING.
+func filterDeliveryWindows(claims []dispatchClaim, windows map[string]*deliverywindow.Schedule, now time.Time) ([]dispatchClaim, map[time.Time][]string, int) {
+	if len(windows) == 0 {
+		return claims, nil, 0
+	}
+	kept := make([]dispatchClaim, 0, len(claims))
+	deferred := make(map[time.Time][]string)
+	for _, c := range claims {
+		s, ok := windows[c.subID]
+		if c.subID == "" || !ok {
+			kept = append(kept, c)
+			continue
+		}
+		if s.Open(now) {
+			if closes, ok := s.ClosesAt(now); ok {
+				c.windowClosesAt = &closes
+			}
+			kept = append(kept, c)
+			continue
+		}
+		if next, ok := s.NextOpen(now); ok {
+			next = next.UTC()
+			deferred[next] = append(deferred[next], c.id)
+		}
+	}
+	return kept, deferred, len(claims) - len(kept)
+}
@@ -3,6 +3,8 @@ package scheduler
 import (
 	"context"
 	"log/slog"
+	"net/url"
+	"strconv"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -28,6 +30,12 @@ type MessageGroupDispatcher struct {
 	publisher          queue.Publisher
 	authSvc            *DispatchAuthService
 	processingEndpoint string
+
+	// routes sends jobs of the named dispatch pools — or, keyed
+	// PriorityRoutePrefix+priority, of a priority — to their own queue
+	// instead of publisher, so a flooded pool can't hold urgent ones up
+	// behind it in a shared queue. Set via Scheduler.QueueRoutes.
+	routes map[string]queue.Publisher
 }
 
 // NewMessageGroupDispatcher wires the dispatcher.
@@ -48,47 +56,127 @@ func NewMessageGroupDispatcher(pool *pgxpool.Pool, publisher queue.Publisher, au
 // On a publish error the batch is reverted QUEUED→PENDING so the next poll
 // re-dispatches it. The `status = 'QUEUED'` guard leaves alone any job that
 // /api/dispatch/process has already advanced, and a re-published duplicate is
-// harmless (FIFO content-dedup + the endpoint's terminal-status check). A crash
+// harmless (FIFO dedup on the per-attempt id + the endpoint's terminal-status
+// check). A crash
 // between the caller's commit and this publish leaves rows QUEUED for stale
 // recovery — the same failure mode the recovery loop already covers.
-func (d *MessageGroupDispatcher) SubmitBatch(ctx context.Context, toks []DispatchJobToken) {
+//
+// With queue routes configured the batch is split per destination queue,
+// each part keeping the claim order; a subscription's jobs in a message
+// group never span queues because they share its dispatch pool and
+// priority.
+//
+// Returns how many jobs were published.
+func (d *MessageGroupDispatcher) SubmitBatch(ctx context.Context, toks []DispatchJobToken) int {
 	if len(toks) == 0 {
-		return
+		return 0
+	}
+	if len(d.routes) == 0 {
+		return d.publishBatch(ctx, d.publisher, toks)
+	}
+	var order []queue.Publisher
+	parts := make(map[queue.Publisher][]DispatchJobToken)
+	for _, tok := range toks {
+		pub := d.publisherFor(tok)
+		if _, ok := parts[pub]; !ok {
+			order = append(order, pub)
+		}
+		parts[pub] = append(parts[pub], tok)
+	}
+	published := 0
+	for _, pub := range order {
+		published += d.publishBatch(ctx, pub, parts[pub])
+	}
+	return published
+}
+
+// PriorityRoutePrefix marks a queue route keyed by priority rather than
+// dispatch pool, e.g. "priority:HIGH".
+const PriorityRoutePrefix = "priority:"
+
+// publisherFor picks the job's queue: its dispatch pool's route, else its
+// priority's route, else the default publisher.
+func (d *MessageGroupDispatcher) publisherFor(tok DispatchJobToken) queue.Publisher {
+	if pub, ok := d.routes[tok.PoolCode]; ok && tok.PoolCode != "" {
+		return pub
 	}
+	if pub, ok := d.routes[PriorityRoutePrefix+string(tok.Priority)]; ok {
+		return pub
+	}
+	return d.publisher
+}
+
+func (d *MessageGroupDispatcher) publishBatch(ctx context.Context, pub queue.Publisher, toks []DispatchJobToken) int {
 	msgs := make([]common.Message, len(toks))
 	for i, tok := range toks {
 		msgs[i] = d.buildMessage(tok)
 	}
-	if _, err := d.publisher.PublishBatch(ctx, msgs); err != nil {
+	if _, err := pub.PublishBatch(ctx, msgs); err != nil {
 		ids := make([]string, len(toks))
 		for i, tok := range toks {
 			ids[i] = tok.JobID
 		}
-		slog.Warn("batch publish failed; reverting QUEUED→PENDING", "count", len(ids), "err", err)
+		slog.Warn("batch publish failed; reverting QUEUED→PENDING", "queue", pub.Identifier(), "count", len(ids), "err", err)
 		if _, err := d.pool.Exec(ctx,
 			`UPDATE msg_dispatch_jobs SET status = 'PENDING', updated_at = NOW()
 			  WHERE id = ANY($1) AND status = 'QUEUED'`, ids); err != nil {
 			slog.Warn("batch revert failed", "err", err)
 		}
+		return 0
 	}
+	return len(toks)
 }
 
+// processingSlackSeconds covers the processing endpoint's own work around
+// the delivery (job load, transform, attempt write).
+const processingSlackSeconds = 30
+
 // buildMessage renders the queue message for a claimed job. mediation_target is
 // the platform processing endpoint (NOT the subscriber URL): the router POSTs
 // {messageId} there and that endpoint loads the job, delivers to
 // job.target_url, records the attempt, and advances status. The signed token
 // lets the endpoint verify the callback came from a job this scheduler queued.
+//
+// The deduplication id is per publish, not per job: a retry or a deferral
+// (a 429, an unacknowledged ack=false, an operator requeue) is re-published
+// well inside an SQS FIFO queue's 5-minute dedup window, and a job-id-only
+// key would have SQS silently drop it. Retries bump attempt_count; the
+// others keep it but move scheduled_for, so the id carries both. A
+// re-publish of the SAME claim (revert after a failed publish) changes
+// neither and keeps its id, so a batch that partly landed is not delivered
+// twice.
+//
+// The router's deadline for the callback is the job's own delivery timeout
+// plus processingSlack, so a slow subscriber the job allows for is not cut
+// off by the router's default.
 func (d *MessageGroupDispatcher) buildMessage(tok DispatchJobToken) common.Message {
 	authToken := d.authSvc.Sign(tok.JobID)
+	dedupID := tok.JobID + "-" + strconv.Itoa(int(tok.AttemptCount))
+	if tok.ScheduledFor != nil {
+		dedupID += "-" + strconv.FormatInt(tok.ScheduledFor.UnixMilli(), 10)
+	}
 	msg := common.Message{
 		ID:              tok.JobID,
 		MediationType:   common.MediationTypeHTTP,
 		MediationTarget: d.processingEndpoint,
 		AuthToken:       &authToken,
+		DeduplicationID: &dedupID,
+	}
+	if tok.TimeoutSeconds > 0 {
+		msg.TimeoutSeconds = uint32(tok.TimeoutSeconds) + processingSlackSeconds
 	}
 	if tok.MessageGroup != "" {
 		group := tok.MessageGroup // copy: don't alias the loop/param variable
 		msg.MessageGroupID = &group
 	}
+	msg.HighPriority = tok.Priority == common.PriorityHigh
+	msg.WindowClosesAt = tok.WindowClosesAt
+	msg.SubscriptionID = tok.SubscriptionID
+	if tok.Weight > 1 {
+		msg.SubscriptionWeight = uint32(tok.Weight)
+	}
+	if u, err := url.Parse(tok.TargetURL); err == nil {
+		msg.TargetHost = u.Host
+	}
 	return msg
 }
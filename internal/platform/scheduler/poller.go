@@ -1,13 +1,17 @@
 package scheduler
 
 import (
+	"cmp"
 	"context"
 	"log/slog"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/time/rate"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 )
@@ -17,8 +21,10 @@ import (
 const defaultMessageGroup = "default"
 
 // PausedConnectionCache caches the set of subscription IDs whose target
-// connections are PAUSED. The poller filters jobs whose subscription
-// matches; those jobs sit in PENDING until the connection is reactivated.
+// connections are PAUSED, or that wait on an approver (PENDING_APPROVAL,
+// e.g. after a change of endpoint). The poller filters jobs whose
+// subscription matches; those jobs sit in PENDING until the connection is
+// reactivated or the subscription approved.
 type PausedConnectionCache struct {
 	pool *pgxpool.Pool
 	ttl  time.Duration
@@ -65,8 +71,8 @@ func (c *PausedConnectionCache) PausedSubscriptionIDs(ctx context.Context) (map[
 func (c *PausedConnectionCache) refresh(ctx context.Context) error {
 	rows, err := c.pool.Query(ctx,
 		`SELECT s.id FROM msg_subscriptions s
-		   JOIN msg_connections c ON c.id = s.connection_id
-		  WHERE c.status = 'PAUSED'`)
+		   LEFT JOIN msg_connections c ON c.id = s.connection_id
+		  WHERE c.status = 'PAUSED' OR s.status = 'PENDING_APPROVAL'`)
 	if err != nil {
 		return err
 	}
@@ -98,17 +104,48 @@ type PendingJobPoller struct {
 	pool        *pgxpool.Pool
 	dispatcher  *MessageGroupDispatcher
 	pausedCache *PausedConnectionCache
+	windowCache *DeliveryWindowCache
 	// IsLeader gates claiming: when non-nil and false, the poller idles.
 	// The per-group FIFO dispatcher is in-process only, so within-group
 	// ordering requires a single active scheduler — concurrent SKIP-LOCKED
 	// claims across replicas would dispatch a group's jobs out of order.
 	// nil = always run (standby disabled). Set by Scheduler.Run.
 	IsLeader func() bool
+
+	// limiter enforces cfg.MaxPublishRate; nil when uncapped.
+	limiter *rate.Limiter
+	metrics *Metrics
+	// catchingUp is set when the last poll claimed all it was allowed to,
+	// i.e. more jobs were (probably) due; the next claim then follows
+	// cfg.CatchUp.
+	catchingUp bool
 }
 
 // NewPendingJobPoller wires the poller.
-func NewPendingJobPoller(cfg Config, pool *pgxpool.Pool, dispatcher *MessageGroupDispatcher, pausedCache *PausedConnectionCache) *PendingJobPoller {
-	return &PendingJobPoller{cfg: cfg, pool: pool, dispatcher: dispatcher, pausedCache: pausedCache}
+func NewPendingJobPoller(cfg Config, pool *pgxpool.Pool, dispatcher *MessageGroupDispatcher, pausedCache *PausedConnectionCache, windowCache *DeliveryWindowCache) *PendingJobPoller {
+	p := &PendingJobPoller{cfg: cfg, pool: pool, dispatcher: dispatcher, pausedCache: pausedCache, windowCache: windowCache,
+		metrics: &Metrics{maxRate: cfg.MaxPublishRate}}
+	if cfg.MaxPublishRate > 0 {
+		// Burst is one poll interval's worth, so the cap holds tick by tick
+		// rather than averaged over a longer window.
+		burst := int(math.Ceil(cfg.MaxPublishRate * cfg.PollInterval.Seconds()))
+		p.limiter = rate.NewLimiter(rate.Limit(cfg.MaxPublishRate), max(1, min(burst, cfg.BatchSize)))
+	}
+	return p
+}
+
+// claimLimit is how many jobs this poll may claim: the batch size, cut to
+// the publish budget when the rate is capped.
+func (p *PendingJobPoller) claimLimit(now time.Time) int {
+	limit := p.cfg.BatchSize
+	if p.limiter == nil {
+		return limit
+	}
+	if avail := int(p.limiter.TokensAt(now)); avail < limit {
+		p.metrics.throttled.Add(1)
+		limit = max(avail, 0)
+	}
+	return limit
 }
 
 // Run drives the poller until ctx is cancelled.
@@ -134,10 +171,29 @@ func (p *PendingJobPoller) Run(ctx context.Context) {
 
 // pollOnce claims a batch of jobs and submits them to the dispatcher.
 func (p *PendingJobPoller) pollOnce(ctx context.Context) error {
+	if p.cfg.SkipOlderThan > 0 {
+		n, err := expireStale(ctx, p.pool, p.cfg.SkipOlderThan)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			p.metrics.skipped.Add(uint64(n))
+			slog.Warn("expired stale dispatch jobs instead of dispatching them", "count", n, "older_than", p.cfg.SkipOlderThan)
+		}
+	}
+	now := time.Now()
+	limit := p.claimLimit(now)
+	if limit == 0 {
+		return nil // publish budget spent; the backlog waits in PENDING
+	}
 	paused, err := p.pausedCache.PausedSubscriptionIDs(ctx)
 	if err != nil {
 		return err
 	}
+	windows, err := p.windowCache.Windows(ctx)
+	if err != nil {
+		return err
+	}
 	tx, err := p.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -157,24 +213,32 @@ func (p *PendingJobPoller) pollOnce(ctx context.Context) error {
 	// message, so the poller is the single re-dispatch driver — no queue-NACK
 	// racing the poll. A NULL scheduled_for (every freshly-created job) is
 	// always eligible.
-	rows, err := tx.Query(ctx,
-		`SELECT id, subscription_id, message_group, mode, attempt_count, target_url
-		   FROM msg_dispatch_jobs
-		  WHERE status = 'PENDING'
-		    AND (scheduled_for IS NULL OR scheduled_for <= NOW())
-		  ORDER BY message_group ASC NULLS LAST, sequence ASC, created_at ASC
-		  LIMIT $1
-		  FOR UPDATE SKIP LOCKED`,
-		p.cfg.BatchSize)
+	//
+	// Priority sorts first so a backlog of bulk jobs can't fill the batch
+	// while HIGH ones wait. It never reorders a subscription's own jobs
+	// within a group: priority is per subscription, so they all share it.
+	//
+	// weight is read from the subscription rather than copied onto the
+	// job, so changing it applies to jobs already pending. Jobs without a
+	// subscription weigh 1.
+	//
+	// While catching up newest-first, the most recently due jobs are claimed
+	// first, and only the head of each message group is eligible so a
+	// group's jobs still go out in order.
+	claimSQL := claimOldestFirstSQL
+	if p.catchingUp && p.cfg.CatchUp == CatchUpNewestFirst {
+		claimSQL = claimNewestFirstSQL
+	}
+	rows, err := tx.Query(ctx, claimSQL, limit)
 	if err != nil {
 		return err
 	}
 	var claims []dispatchClaim
 	for rows.Next() {
 		var c dispatchClaim
-		var msgGroup *string
-		var subID *string
-		if err := rows.Scan(&c.id, &subID, &msgGroup, &c.mode, &c.attempt, &c.target); err != nil {
+		var msgGroup, subID, poolCode *string
+		var priority string
+		if err := rows.Scan(&c.id, &subID, &msgGroup, &c.mode, &c.attempt, &c.target, &c.timeout, &poolCode, &priority, &c.weight, &c.scheduledFor); err != nil {
 			rows.Close()
 			return err
 		}
@@ -184,9 +248,15 @@ func (p *PendingJobPoller) pollOnce(ctx context.Context) error {
 		if msgGroup != nil {
 			c.group = *msgGroup
 		}
+		if poolCode != nil {
+			c.poolCode = *poolCode
+		}
+		c.priority = common.ParsePriority(priority)
 		claims = append(claims, c)
 	}
 	rows.Close()
+	p.catchingUp = len(claims) == limit
+	p.metrics.catchingUp.Store(p.catchingUp)
 	if len(claims) == 0 {
 		return nil
 	}
@@ -203,7 +273,19 @@ func (p *PendingJobPoller) pollOnce(ctx context.Context) error {
 	// filter, then group, then the blocked-group hold-back, then the
 	// per-mode filter. Skipped claims are simply left PENDING — their row
 	// locks release at commit and the next poll retries them.
+	//
+	// Claims outside their subscription's delivery window are the
+	// exception: rather than being re-claimed every tick until it opens,
+	// they are rescheduled to the opening in this tx.
 	live, skippedPaused := filterPausedSubscriptions(claims, paused)
+	live, deferred, skippedWindow := filterDeliveryWindows(live, windows, now)
+	for next, ids := range deferred {
+		if _, err := tx.Exec(ctx,
+			`UPDATE msg_dispatch_jobs SET scheduled_for = $1, updated_at = NOW()
+			  WHERE id = ANY($2)`, next, ids); err != nil {
+			return err
+		}
+	}
 
 	byGroup := groupByMessageGroup(live)
 	candidates := make([]string, 0, len(byGroup))
@@ -232,12 +314,25 @@ func (p *PendingJobPoller) pollOnce(ctx context.Context) error {
 		for _, c := range filterByDispatchMode(jobs, blocked) {
 			queued = append(queued, c.id)
 			tokens = append(tokens, DispatchJobToken{
-				JobID:        c.id,
-				MessageGroup: c.group,
-				TargetURL:    c.target,
+				JobID:          c.id,
+				SubscriptionID: c.subID,
+				MessageGroup:   c.group,
+				TargetURL:      c.target,
+				AttemptCount:   c.attempt,
+				ScheduledFor:   c.scheduledFor,
+				TimeoutSeconds: c.timeout,
+				PoolCode:       c.poolCode,
+				Priority:       c.priority,
+				WindowClosesAt: c.windowClosesAt,
+				Weight:         c.weight,
 			})
 		}
 	}
+	// byGroup is a map, so restore priority order across groups before
+	// publishing. Stable: each group's jobs keep their claim order.
+	slices.SortStableFunc(tokens, func(a, b DispatchJobToken) int {
+		return cmp.Compare(a.Priority.Rank(), b.Priority.Rank())
+	})
 
 	if len(queued) > 0 {
 		if _, err := tx.Exec(ctx,
@@ -255,22 +350,60 @@ func (p *PendingJobPoller) pollOnce(ctx context.Context) error {
 	// order. A publish failure reverts QUEUED→PENDING for the next poll; a
 	// crash between commit and publish leaves rows QUEUED for stale recovery —
 	// the same failure mode the recovery loop already covers.
-	p.dispatcher.SubmitBatch(ctx, tokens)
+	published := p.dispatcher.SubmitBatch(ctx, tokens)
+	p.metrics.published.Add(uint64(published))
+	if p.limiter != nil {
+		// Spend the budget on what was queued, not on what was claimed:
+		// held-back claims publish nothing.
+		p.limiter.AllowN(now, len(tokens))
+	}
 
-	if len(queued) > 0 || skippedPaused > 0 || skippedBlocked > 0 {
+	if len(queued) > 0 || skippedPaused > 0 || skippedWindow > 0 || skippedBlocked > 0 {
 		slog.Debug("poll tick",
 			"queued", len(queued),
 			"skipped_paused", skippedPaused,
+			"skipped_window", skippedWindow,
 			"skipped_blocked", skippedBlocked)
 	}
 	return nil
 }
 
+// claimSelect is the poll query's column list and joins; the two claim
+// orders below share it.
+const claimSelect = `SELECT j.id, j.subscription_id, j.message_group, j.mode, j.attempt_count, j.target_url, j.timeout_seconds,
+		        (SELECT p.code FROM msg_dispatch_pools p WHERE p.id = j.dispatch_pool_id), j.priority,
+		        COALESCE(s.weight, 1), j.scheduled_for
+		   FROM msg_dispatch_jobs j
+		   LEFT JOIN msg_subscriptions s ON s.id = j.subscription_id
+		  WHERE j.status = 'PENDING'
+		    AND (j.scheduled_for IS NULL OR j.scheduled_for <= NOW())`
+
+const claimOldestFirstSQL = claimSelect + `
+		  ORDER BY CASE j.priority WHEN 'HIGH' THEN 0 WHEN 'LOW' THEN 2 ELSE 1 END,
+		           j.message_group ASC NULLS LAST, j.sequence ASC, j.created_at ASC
+		  LIMIT $1
+		  FOR UPDATE OF j SKIP LOCKED`
+
+const claimNewestFirstSQL = claimSelect + `
+		    AND (j.message_group IS NULL OR NOT EXISTS (
+		         SELECT 1 FROM msg_dispatch_jobs o
+		          WHERE o.message_group = j.message_group AND o.status = 'PENDING'
+		            AND (o.sequence, o.created_at) < (j.sequence, j.created_at)))
+		  ORDER BY CASE j.priority WHEN 'HIGH' THEN 0 WHEN 'LOW' THEN 2 ELSE 1 END,
+		           COALESCE(j.scheduled_for, j.created_at) DESC
+		  LIMIT $1
+		  FOR UPDATE OF j SKIP LOCKED`
+
 // dispatchClaim is one PENDING row claimed by the poll query. group and
 // subID are "" when the column is NULL.
 type dispatchClaim struct {
-	id, subID, group, mode, target string
-	attempt                        int32
+	id, subID, group, mode, target, poolCode string
+	attempt, timeout, weight                 int32
+	priority                                 common.Priority
+	scheduledFor                             *time.Time
+	// windowClosesAt is set by filterDeliveryWindows when the claim's
+	// delivery window has an end.
+	windowClosesAt *time.Time
 }
 
 // messageGroupKey maps a claim's message_group to its grouping key: jobs
@@ -373,7 +506,32 @@ func blockedGroups(ctx context.Context, tx pgx.Tx, groups []string) (map[string]
 // carries just enough to publish to the queue without re-reading the
 // job row.
 type DispatchJobToken struct {
-	JobID        string
-	MessageGroup string
-	TargetURL    string
+	JobID string
+	// SubscriptionID is "" for jobs without a subscription. With the
+	// TargetURL's host it keys the router's per-endpoint in-flight caps.
+	SubscriptionID string
+	MessageGroup   string
+	TargetURL      string
+	// AttemptCount is the job's attempt_count at claim time; with
+	// ScheduledFor it makes each re-dispatch of a job a distinct queue
+	// message (see buildMessage).
+	AttemptCount int32
+	// ScheduledFor is the job's scheduled_for at claim time; nil for a job
+	// that was never deferred.
+	ScheduledFor *time.Time
+	// TimeoutSeconds is the job's delivery timeout; buildMessage turns it
+	// into the message's router deadline. Zero = router default.
+	TimeoutSeconds int32
+	// PoolCode is the job's dispatch pool code ("" when it has none); it
+	// selects the queue when per-pool queue routes are configured.
+	PoolCode string
+	// Priority is inherited from the subscription. HIGH jobs are flagged
+	// HighPriority on the message; any priority may have its own queue.
+	Priority common.Priority
+	// WindowClosesAt is when the subscription's delivery window closes;
+	// buildMessage copies it to the message. nil = no window end.
+	WindowClosesAt *time.Time
+	// Weight is the subscription's dispatch weight; the router shares a
+	// pool's workers between subscriptions in proportion to it.
+	Weight int32
 }
@@ -13,16 +13,19 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 )
 
 func TestMain(m *testing.M) { testpg.RunMain(m) }
 
 // capturePublisher is a queue.Publisher that records published message
-// IDs. Submit dispatches asynchronously, so it must be race-safe.
+// IDs and deduplication IDs. Submit dispatches asynchronously, so it must
+// be race-safe.
 type capturePublisher struct {
-	mu  sync.Mutex
-	ids []string
+	mu     sync.Mutex
+	ids    []string
+	dedups []string
 }
 
 func (p *capturePublisher) Identifier() string { return "capture" }
@@ -31,6 +34,9 @@ func (p *capturePublisher) Publish(_ context.Context, m common.Message) (string,
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.ids = append(p.ids, m.ID)
+	if m.DeduplicationID != nil {
+		p.dedups = append(p.dedups, *m.DeduplicationID)
+	}
 	return m.ID, nil
 }
 
@@ -70,7 +76,7 @@ func TestPollOnce_BatchPublishFailureRevertsToPending(t *testing.T) {
 	seedJob(t, pool, id2, "PENDING", "grp_batchfail_it", "")
 
 	dispatcher := NewMessageGroupDispatcher(pool, failPublisher{}, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
-	poller := NewPendingJobPoller(DefaultConfig(), pool, dispatcher, NewPausedConnectionCache(pool, time.Minute))
+	poller := NewPendingJobPoller(DefaultConfig(), pool, dispatcher, NewPausedConnectionCache(pool, time.Minute), NewDeliveryWindowCache(pool, time.Minute))
 
 	require.NoError(t, poller.pollOnce(ctx))
 
@@ -83,7 +89,7 @@ func TestPollOnce_BatchPublishFailureRevertsToPending(t *testing.T) {
 func newTestPoller(pool *pgxpool.Pool) *PendingJobPoller {
 	dispatcher := NewMessageGroupDispatcher(pool, &capturePublisher{}, NewDispatchAuthService("test-secret"), "http://localhost:8080/api/dispatch/process")
 	cache := NewPausedConnectionCache(pool, time.Minute)
-	return NewPendingJobPoller(DefaultConfig(), pool, dispatcher, cache)
+	return NewPendingJobPoller(DefaultConfig(), pool, dispatcher, cache, NewDeliveryWindowCache(pool, time.Minute))
 }
 
 // seedJob inserts a msg_dispatch_jobs row. Empty group/subID seed NULL.
@@ -112,6 +118,36 @@ func jobStatus(t *testing.T, pool *pgxpool.Pool, id string) string {
 	return status
 }
 
+// TestPollOnce_DeferredJobRepublishesUnderANewDedupID: a job deferred
+// without spending an attempt (a 429, an ack=false) comes back under the
+// same attempt_count, so its next publish must still carry a new
+// deduplication id or an SQS FIFO queue drops it inside its 5-minute window.
+func TestPollOnce_DeferredJobRepublishesUnderANewDedupID(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	pub := &capturePublisher{}
+	dispatcher := NewMessageGroupDispatcher(pool, pub, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+	poller := NewPendingJobPoller(DefaultConfig(), pool, dispatcher, NewPausedConnectionCache(pool, time.Minute), NewDeliveryWindowCache(pool, time.Minute))
+
+	const id = "djdeferdedup1"
+	seedJob(t, pool, id, "PENDING", "grp_deferdedup_it", "")
+	require.NoError(t, poller.pollOnce(ctx))
+	require.Equal(t, "QUEUED", jobStatus(t, pool, id))
+
+	// Deferred to a moment that has already come due, so the next poll
+	// claims it again.
+	repo := dispatchjob.NewRepository(pool)
+	require.NoError(t, repo.Reschedule(ctx, id, time.Now().Add(-time.Second)))
+	require.NoError(t, poller.pollOnce(ctx))
+	require.Equal(t, "QUEUED", jobStatus(t, pool, id))
+
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	require.Equal(t, []string{id, id}, pub.ids)
+	require.Len(t, pub.dedups, 2)
+	require.NotEqual(t, pub.dedups[0], pub.dedups[1], "the deferred re-publish reused the first publish's dedup id")
+}
+
 // TestPollOnce_BlockedGroupHoldback pins the blocked-group filter: a
 // FAILED sibling holds the whole message group in PENDING (no QUEUED
 // flip), and resolving the failure releases the group on the next poll.
@@ -207,3 +243,31 @@ func TestPollOnce_PausedConnectionHoldsJob(t *testing.T) {
 	require.Equal(t, "QUEUED", jobStatus(t, pool, jobID),
 		"reactivated connection must release the job")
 }
+
+// TestPollOnce_SkipOlderThanExpiresStaleJobs: with SkipOlderThan set, a job
+// due longer ago than the cutoff goes to EXPIRED instead of the queue, and
+// a fresh one still dispatches.
+func TestPollOnce_SkipOlderThanExpiresStaleJobs(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+
+	const (
+		stale = "djskipstale01"
+		fresh = "djskipfresh01"
+	)
+	seedJob(t, pool, stale, "PENDING", "", "")
+	seedJob(t, pool, fresh, "PENDING", "", "")
+	_, err := pool.Exec(ctx, `UPDATE msg_dispatch_jobs SET created_at = NOW() - INTERVAL '2 hours' WHERE id = $1`, stale)
+	require.NoError(t, err)
+
+	pub := &capturePublisher{}
+	cfg := DefaultConfig()
+	cfg.SkipOlderThan = time.Hour
+	dispatcher := NewMessageGroupDispatcher(pool, pub, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+	poller := NewPendingJobPoller(cfg, pool, dispatcher, NewPausedConnectionCache(pool, time.Minute), NewDeliveryWindowCache(pool, time.Minute))
+	require.NoError(t, poller.pollOnce(ctx))
+
+	require.Equal(t, "EXPIRED", jobStatus(t, pool, stale))
+	require.Equal(t, uint64(1), poller.metrics.skipped.Load())
+	require.Eventually(t, func() bool { return jobStatus(t, pool, fresh) == "QUEUED" }, 5*time.Second, 20*time.Millisecond)
+}
@@ -1,9 +1,16 @@
 package scheduler
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
 )
 
 // These tests mirror the Rust poller's unit tests
@@ -150,3 +157,142 @@ func TestFilterPausedSubscriptions_NothingPaused(t *testing.T) {
 	assert.Equal(t, []string{"j1"}, claimIDs(kept))
 	assert.Zero(t, skipped)
 }
+
+func TestFilterDeliveryWindows(t *testing.T) {
+	// 2026-10-16 is a Friday; office hours are weekdays 08:00–18:00 UTC.
+	now := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	windows := map[string]*deliverywindow.Schedule{
+		"sub_office": {Windows: []deliverywindow.Window{{Days: []string{"MON", "TUE", "WED", "THU", "FRI"}, Start: "08:00", End: "18:00"}}},
+		"sub_late":   {Windows: []deliverywindow.Window{{Start: "18:00", End: "23:00"}}},
+	}
+	kept, deferred, skipped := filterDeliveryWindows([]dispatchClaim{
+		mkClaimWithSub("j1", "g", "sub_office"),
+		mkClaimWithSub("j2", "g", "sub_late"),
+		mkClaimWithSub("j3", "g", "sub_none"),
+		mkClaimWithSub("j4", "g", ""),
+		mkClaimWithSub("j5", "g", "sub_office"),
+	}, windows, now)
+
+	assert.Equal(t, []string{"j2", "j3", "j4"}, claimIDs(kept))
+	assert.Equal(t, 2, skipped)
+	monday := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, map[time.Time][]string{monday: {"j1", "j5"}}, deferred)
+	require.NotNil(t, kept[0].windowClosesAt)
+	assert.Equal(t, time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC), *kept[0].windowClosesAt)
+	assert.Nil(t, kept[1].windowClosesAt)
+}
+
+func TestBuildMessage_CarriesWindowClose(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+	closes := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, &closes, d.buildMessage(DispatchJobToken{JobID: "dsj_1", WindowClosesAt: &closes}).WindowClosesAt)
+	assert.Nil(t, d.buildMessage(DispatchJobToken{JobID: "dsj_2"}).WindowClosesAt)
+}
+
+func TestBuildMessage_CarriesReceiver(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+
+	msg := d.buildMessage(DispatchJobToken{JobID: "dsj_1", SubscriptionID: "sub_1", TargetURL: "https://hooks.example.com:8443/in"})
+	assert.Equal(t, "sub_1", msg.SubscriptionID)
+	assert.Equal(t, "hooks.example.com:8443", msg.TargetHost)
+	assert.Equal(t, "http://localhost/api/dispatch/process", msg.MediationTarget)
+}
+
+func TestBuildMessage_DeduplicationIDIsPerAttempt(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+
+	first := d.buildMessage(DispatchJobToken{JobID: "dsj_1", MessageGroup: "order-7"})
+	retry := d.buildMessage(DispatchJobToken{JobID: "dsj_1", MessageGroup: "order-7", AttemptCount: 1})
+
+	assert.Equal(t, "dsj_1-0", *first.DeduplicationID)
+	assert.Equal(t, "dsj_1-1", *retry.DeduplicationID, "a retry must not be dropped by FIFO dedup")
+	assert.Equal(t, "order-7", *retry.MessageGroupID)
+	assert.Equal(t, *first.AuthToken, *retry.AuthToken, "the callback token is per job")
+}
+
+func TestBuildMessage_DeferralGetsANewDeduplicationID(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+
+	deferredTo := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	first := d.buildMessage(DispatchJobToken{JobID: "dsj_1", AttemptCount: 1})
+	deferred := d.buildMessage(DispatchJobToken{JobID: "dsj_1", AttemptCount: 1, ScheduledFor: &deferredTo})
+	again := d.buildMessage(DispatchJobToken{JobID: "dsj_1", AttemptCount: 1, ScheduledFor: &deferredTo})
+
+	assert.NotEqual(t, *first.DeduplicationID, *deferred.DeduplicationID, "a deferral keeps attempt_count but must not be dropped by FIFO dedup")
+	assert.Equal(t, *deferred.DeduplicationID, *again.DeduplicationID, "re-publishing the same claim keeps its id")
+}
+
+func TestBuildMessage_TimeoutCoversJobTimeout(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+
+	msg := d.buildMessage(DispatchJobToken{JobID: "dsj_1", TimeoutSeconds: 600})
+	assert.Equal(t, uint32(600+processingSlackSeconds), msg.TimeoutSeconds)
+
+	msg = d.buildMessage(DispatchJobToken{JobID: "dsj_2"})
+	assert.Zero(t, msg.TimeoutSeconds, "no job timeout leaves the router default")
+}
+
+func TestBuildMessage_CarriesSubscriptionWeight(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+
+	assert.Zero(t, d.buildMessage(DispatchJobToken{JobID: "dsj_1", Weight: 1}).SubscriptionWeight, "the default weight stays off the wire")
+	assert.Equal(t, uint32(5), d.buildMessage(DispatchJobToken{JobID: "dsj_2", Weight: 5}).SubscriptionWeight)
+}
+
+// recordingPublisher captures published message ids.
+type recordingPublisher struct {
+	name string
+	ids  []string
+}
+
+func (p *recordingPublisher) Identifier() string { return p.name }
+func (p *recordingPublisher) Publish(_ context.Context, m common.Message) (string, error) {
+	p.ids = append(p.ids, m.ID)
+	return m.ID, nil
+}
+func (p *recordingPublisher) PublishBatch(ctx context.Context, msgs []common.Message) ([]string, error) {
+	for _, m := range msgs {
+		_, _ = p.Publish(ctx, m)
+	}
+	return p.ids, nil
+}
+
+func TestSubmitBatch_RoutesByDispatchPool(t *testing.T) {
+	shared := &recordingPublisher{name: "shared"}
+	urgent := &recordingPublisher{name: "urgent"}
+	d := NewMessageGroupDispatcher(nil, shared, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+	d.routes = map[string]queue.Publisher{"URGENT": urgent}
+
+	d.SubmitBatch(context.Background(), []DispatchJobToken{
+		{JobID: "j1", PoolCode: "BULK"},
+		{JobID: "j2", PoolCode: "URGENT"},
+		{JobID: "j3"},
+		{JobID: "j4", PoolCode: "URGENT"},
+	})
+	assert.Equal(t, []string{"j1", "j3"}, shared.ids)
+	assert.Equal(t, []string{"j2", "j4"}, urgent.ids, "claim order kept within a queue")
+}
+
+func TestSubmitBatch_RoutesByPriority(t *testing.T) {
+	shared := &recordingPublisher{name: "shared"}
+	urgent := &recordingPublisher{name: "urgent"}
+	high := &recordingPublisher{name: "high"}
+	d := NewMessageGroupDispatcher(nil, shared, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+	d.routes = map[string]queue.Publisher{"URGENT": urgent, PriorityRoutePrefix + "HIGH": high}
+
+	d.SubmitBatch(context.Background(), []DispatchJobToken{
+		{JobID: "j1", Priority: common.PriorityHigh},
+		{JobID: "j2", Priority: common.PriorityLow},
+		{JobID: "j3", PoolCode: "URGENT", Priority: common.PriorityHigh},
+	})
+	assert.Equal(t, []string{"j1"}, high.ids)
+	assert.Equal(t, []string{"j2"}, shared.ids)
+	assert.Equal(t, []string{"j3"}, urgent.ids, "a pool route wins over a priority route")
+}
+
+func TestBuildMessage_FlagsHighPriority(t *testing.T) {
+	d := NewMessageGroupDispatcher(nil, nil, NewDispatchAuthService("s"), "http://localhost/api/dispatch/process")
+	assert.True(t, d.buildMessage(DispatchJobToken{JobID: "j1", Priority: common.PriorityHigh}).HighPriority)
+	assert.False(t, d.buildMessage(DispatchJobToken{JobID: "j2", Priority: common.PriorityNormal}).HighPriority)
+}
@@ -51,6 +51,23 @@ type Config struct {
 	// delivery + status transitions. Empty is a misconfiguration — the
 	// dispatcher would publish messages the router can't route.
 	ProcessingEndpoint string
+
+	// MaxPublishRate caps the jobs published per second, so a backlog
+	// after downtime reaches receivers at a bounded rate instead of all at
+	// once. Jobs over the budget stay PENDING for a later poll. 0 = no cap.
+	MaxPublishRate float64
+
+	// CatchUp picks which due jobs are claimed first while the scheduler
+	// is behind (see CatchUpOrder).
+	CatchUp CatchUpOrder
+
+	// SkipOlderThan expires PENDING jobs that have been due for longer
+	// than this instead of dispatching them. 0 = never skip.
+	SkipOlderThan time.Duration
+
+	// BacklogProbeInterval is how often the backlog depth and age are
+	// measured for the metrics.
+	BacklogProbeInterval time.Duration
 }
 
 // DefaultConfig holds the Go dispatch-job scheduler defaults. These are
@@ -65,6 +82,9 @@ func DefaultConfig() Config {
 		PausedCacheTTL:    60 * time.Second,
 		StaleAfter:        5 * time.Minute,
 		StaleScanInterval: 60 * time.Second,
+		CatchUp:           CatchUpOldestFirst,
+
+		BacklogProbeInterval: 15 * time.Second,
 	}
 }
 
@@ -88,6 +108,10 @@ type Scheduler struct {
 	// only). nil = always run (standby disabled). Mirrors Rust's active_rx gate
 	// on spawn_scheduler.
 	IsLeader func() bool
+
+	// QueueRoutes maps dispatch pool codes to dedicated queues; jobs of
+	// other pools go to the default publisher. nil = one shared queue.
+	QueueRoutes map[string]queue.Publisher
 }
 
 // New wires the scheduler. publisher publishes to the queue (typically
@@ -97,7 +121,7 @@ func New(cfg Config, pool *pgxpool.Pool, publisher queue.Publisher, hmacSecret s
 	authSvc := NewDispatchAuthService(hmacSecret)
 	pausedCache := NewPausedConnectionCache(pool, cfg.PausedCacheTTL)
 	dispatcher := NewMessageGroupDispatcher(pool, publisher, authSvc, cfg.ProcessingEndpoint)
-	poller := NewPendingJobPoller(cfg, pool, dispatcher, pausedCache)
+	poller := NewPendingJobPoller(cfg, pool, dispatcher, pausedCache, NewDeliveryWindowCache(pool, cfg.PausedCacheTTL))
 	stale := NewStaleQueuedJobPoller(pool, cfg.StaleAfter, cfg.StaleScanInterval)
 	return &Scheduler{
 		cfg:         cfg,
@@ -117,19 +141,27 @@ func (s *Scheduler) Poller() *PendingJobPoller { return s.poller }
 // Dispatcher exposes the dispatcher.
 func (s *Scheduler) Dispatcher() *MessageGroupDispatcher { return s.dispatcher }
 
+// Metrics exposes the backlog and publish metrics for registration.
+func (s *Scheduler) Metrics() *Metrics { return s.poller.metrics }
+
 // AuthService exposes the dispatch-callback HMAC service.
 func (s *Scheduler) AuthService() *DispatchAuthService { return s.authService }
 
-// Run starts the poller + stale-recovery loops and blocks until ctx is
+// Run starts the poller, stale-recovery and backlog-probe loops and blocks until ctx is
 // cancelled. The dispatcher is event-driven via Submit calls from the
 // poller, so it doesn't need its own loop. fc-server uses this entry
 // point when FC_SCHEDULER_ENABLED=true.
 func (s *Scheduler) Run(ctx context.Context) {
 	s.poller.IsLeader = s.IsLeader
 	s.stale.IsLeader = s.IsLeader
+	s.dispatcher.routes = s.QueueRoutes
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() { defer wg.Done(); s.poller.Run(ctx) }()
 	go func() { defer wg.Done(); s.stale.Run(ctx) }()
+	if s.cfg.BacklogProbeInterval > 0 {
+		wg.Add(1)
+		go func() { defer wg.Done(); s.poller.runBacklogProbe(ctx, s.cfg.BacklogProbeInterval) }()
+	}
 	wg.Wait()
 }
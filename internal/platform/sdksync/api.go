@@ -17,6 +17,12 @@
 // existing, tested eventtype Sync use case). The remaining resources
 // (roles, subscriptions, dispatch-pools, principals, processes,
 // scheduled-jobs, openapi) follow the same shape.
+//
+// apply.go adds the anchor-only declarative counterpart, POST
+// /api/admin/platform/apply: one YAML/JSON bundle spanning applications,
+// diffed against current state and converged through the same use cases.
+// permissions.go serves each application's permission catalog under
+// /api/admin/platform/applications/{id}/permissions.
 package sdksync
 
 import (
@@ -45,6 +51,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
 	subscriptionops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -66,6 +73,7 @@ type State struct {
 	Principals    *principal.Repository
 	ScheduledJobs *scheduledjob.Repository
 	Specs         *openapispecs.Repository
+	Permissions   *role.PermissionRepo
 	UoW           *usecasepgx.UnitOfWork
 }
 
@@ -162,21 +170,12 @@ func (s *State) syncEventTypes(ctx context.Context, in *syncEventTypesInput) (*s
 		return nil, err
 	}
 
-	inputs := make([]eventtypeops.SyncEventTypeInput, 0, len(in.Body.EventTypes))
-	for _, et := range in.Body.EventTypes {
-		inputs = append(inputs, eventtypeops.SyncEventTypeInput{
-			Code:        et.Code,
-			Name:        et.Name,
-			Description: et.Description,
-		})
-	}
-
 	cmd := eventtypeops.SyncEventTypesCommand{
 		ApplicationCode: app.Code,
-		EventTypes:      inputs,
+		EventTypes:      eventTypeInputs(in.Body.EventTypes),
 		RemoveUnlisted:  in.RemoveUnlisted,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, eventtypeops.SyncEventTypes(s.EventTypes), cmd, ec)
 	if err != nil {
 		return nil, err
@@ -190,6 +189,18 @@ func (s *State) syncEventTypes(ctx context.Context, in *syncEventTypesInput) (*s
 	}}, nil
 }
 
+func eventTypeInputs(reqs []syncEventTypeInputRequest) []eventtypeops.SyncEventTypeInput {
+	inputs := make([]eventtypeops.SyncEventTypeInput, 0, len(reqs))
+	for _, et := range reqs {
+		inputs = append(inputs, eventtypeops.SyncEventTypeInput{
+			Code:        et.Code,
+			Name:        et.Name,
+			Description: et.Description,
+		})
+	}
+	return inputs
+}
+
 // ── Roles ─────────────────────────────────────────────────────────────────
 
 type syncRoleInputRequest struct {
@@ -220,24 +231,13 @@ func (s *State) syncRoles(ctx context.Context, in *syncRolesInput) (*syncResultO
 		return nil, err
 	}
 
-	inputs := make([]roleops.SyncRoleInput, 0, len(in.Body.Roles))
-	for _, r := range in.Body.Roles {
-		inputs = append(inputs, roleops.SyncRoleInput{
-			Name:          r.Name,
-			DisplayName:   r.DisplayName,
-			Description:   r.Description,
-			Permissions:   r.Permissions,
-			ClientManaged: r.ClientManaged,
-		})
-	}
-
 	cmd := roleops.SyncRolesCommand{
 		ApplicationCode: app.Code,
 		ApplicationID:   app.ID,
-		Roles:           inputs,
+		Roles:           roleInputs(in.Body.Roles),
 		RemoveUnlisted:  in.RemoveUnlisted,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, roleops.SyncRoles(s.Roles), cmd, ec)
 	if err != nil {
 		return nil, err
@@ -251,6 +251,20 @@ func (s *State) syncRoles(ctx context.Context, in *syncRolesInput) (*syncResultO
 	}}, nil
 }
 
+func roleInputs(reqs []syncRoleInputRequest) []roleops.SyncRoleInput {
+	inputs := make([]roleops.SyncRoleInput, 0, len(reqs))
+	for _, r := range reqs {
+		inputs = append(inputs, roleops.SyncRoleInput{
+			Name:          r.Name,
+			DisplayName:   r.DisplayName,
+			Description:   r.Description,
+			Permissions:   r.Permissions,
+			ClientManaged: r.ClientManaged,
+		})
+	}
+	return inputs
+}
+
 // ── Subscriptions ─────────────────────────────────────────────────────────
 
 type syncSubscriptionEventTypeRequest struct {
@@ -292,8 +306,29 @@ func (s *State) syncSubscriptions(ctx context.Context, in *syncSubscriptionsInpu
 		return nil, err
 	}
 
-	inputs := make([]subscriptionops.SyncSubscriptionInput, 0, len(in.Body.Subscriptions))
-	for _, sub := range in.Body.Subscriptions {
+	cmd := subscriptionops.SyncSubscriptionsCommand{
+		ApplicationID:   app.ID,
+		ApplicationCode: app.Code,
+		Subscriptions:   subscriptionInputs(in.Body.Subscriptions),
+		RemoveUnlisted:  in.RemoveUnlisted,
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	ev, err := usecaseop.Run(ctx, s.UoW, subscriptionops.SyncSubscriptions(s.Subscriptions, s.Connections, s.DispatchPools), cmd, ec)
+	if err != nil {
+		return nil, err
+	}
+	return &syncResultOutput{Body: SyncResultResponse{
+		ApplicationCode: ev.ApplicationCode,
+		Created:         ev.Created,
+		Updated:         ev.Updated,
+		Deleted:         ev.Deleted,
+		SyncedCodes:     ev.SyncedCodes,
+	}}, nil
+}
+
+func subscriptionInputs(reqs []syncSubscriptionInputRequest) []subscriptionops.SyncSubscriptionInput {
+	inputs := make([]subscriptionops.SyncSubscriptionInput, 0, len(reqs))
+	for _, sub := range reqs {
 		bindings := make([]subscriptionops.SyncEventTypeBindingInput, 0, len(sub.EventTypes))
 		for _, et := range sub.EventTypes {
 			bindings = append(bindings, subscriptionops.SyncEventTypeBindingInput{
@@ -315,25 +350,7 @@ func (s *State) syncSubscriptions(ctx context.Context, in *syncSubscriptionsInpu
 			DataOnly:         sub.DataOnly,
 		})
 	}
-
-	cmd := subscriptionops.SyncSubscriptionsCommand{
-		ApplicationID:   app.ID,
-		ApplicationCode: app.Code,
-		Subscriptions:   inputs,
-		RemoveUnlisted:  in.RemoveUnlisted,
-	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
-	ev, err := usecaseop.Run(ctx, s.UoW, subscriptionops.SyncSubscriptions(s.Subscriptions, s.Connections, s.DispatchPools), cmd, ec)
-	if err != nil {
-		return nil, err
-	}
-	return &syncResultOutput{Body: SyncResultResponse{
-		ApplicationCode: ev.ApplicationCode,
-		Created:         ev.Created,
-		Updated:         ev.Updated,
-		Deleted:         ev.Deleted,
-		SyncedCodes:     ev.SyncedCodes,
-	}}, nil
+	return inputs
 }
 
 // ── Principals ────────────────────────────────────────────────────────────
@@ -394,7 +411,7 @@ func (s *State) syncPrincipals(ctx context.Context, in *syncPrincipalsInput) (*s
 		Principals:      inputs,
 		RemoveUnlisted:  in.RemoveUnlisted,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, principalops.SyncPrincipals(s.Principals), cmd, ec)
 	if err != nil {
 		return nil, err
@@ -439,28 +456,13 @@ func (s *State) syncDispatchPools(ctx context.Context, in *syncDispatchPoolsInpu
 		return nil, err
 	}
 
-	inputs := make([]dispatchpoolops.SyncDispatchPoolInput, 0, len(in.Body.Pools))
-	for _, p := range in.Body.Pools {
-		concurrency := int32(10) // serde default_concurrency
-		if p.Concurrency != nil {
-			concurrency = *p.Concurrency
-		}
-		inputs = append(inputs, dispatchpoolops.SyncDispatchPoolInput{
-			Code:        p.Code,
-			Name:        p.Name,
-			Description: p.Description,
-			RateLimit:   p.RateLimit,
-			Concurrency: concurrency,
-		})
-	}
-
 	cmd := dispatchpoolops.SyncDispatchPoolsCommand{
 		ApplicationID:   app.ID,
 		ApplicationCode: app.Code,
-		Pools:           inputs,
+		Pools:           dispatchPoolInputs(in.Body.Pools),
 		RemoveUnlisted:  in.RemoveUnlisted,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, dispatchpoolops.SyncDispatchPools(s.DispatchPools), cmd, ec)
 	if err != nil {
 		return nil, err
@@ -474,6 +476,24 @@ func (s *State) syncDispatchPools(ctx context.Context, in *syncDispatchPoolsInpu
 	}}, nil
 }
 
+func dispatchPoolInputs(reqs []syncDispatchPoolInputRequest) []dispatchpoolops.SyncDispatchPoolInput {
+	inputs := make([]dispatchpoolops.SyncDispatchPoolInput, 0, len(reqs))
+	for _, p := range reqs {
+		concurrency := int32(10) // serde default_concurrency
+		if p.Concurrency != nil {
+			concurrency = *p.Concurrency
+		}
+		inputs = append(inputs, dispatchpoolops.SyncDispatchPoolInput{
+			Code:        p.Code,
+			Name:        p.Name,
+			Description: p.Description,
+			RateLimit:   p.RateLimit,
+			Concurrency: concurrency,
+		})
+	}
+	return inputs
+}
+
 // ── Processes ─────────────────────────────────────────────────────────────
 
 type syncProcessInputRequest struct {
@@ -553,7 +573,7 @@ func (s *State) runProcessSync(ctx context.Context, appCode string, processes []
 		Processes:       inputs,
 		RemoveUnlisted:  removeUnlisted,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, processops.SyncProcesses(s.Processes), cmd, ec)
 	if err != nil {
 		return nil, err
@@ -654,7 +674,7 @@ func (s *State) syncScheduledJobs(ctx context.Context, in *syncScheduledJobsInpu
 		Jobs:            jobs,
 		ArchiveUnlisted: in.Body.ArchiveUnlisted,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, scheduledjobops.SyncScheduledJobs(s.ScheduledJobs), cmd, ec)
 	if err != nil {
 		return nil, err
@@ -722,7 +742,7 @@ func (s *State) syncOpenapi(ctx context.Context, in *syncOpenapiInput) (*syncOpe
 		ApplicationCode: app.Code,
 		Spec:            in.Body.Spec,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	ev, err := usecaseop.Run(ctx, s.UoW, openapiops.SyncOpenApiSpec(s.Specs), cmd, ec)
 	if err != nil {
 		return nil, err
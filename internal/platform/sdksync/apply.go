@@ -0,0 +1,666 @@
+package sdksync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
+	dispatchpoolops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	eventtypeops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+	roleops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/role/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	subscriptionops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// ── Declarative apply ────────────────────────────────────────────────────
+//
+// POST /api/admin/platform/apply takes one bundle describing the desired
+// dispatch pools, event types, roles and subscriptions, diffs it against the
+// stored rows and converges them through the same Sync<Resource> use cases
+// the SDK endpoints run. A section omitted from the bundle is unmanaged;
+// a present section (even an empty list) is the complete desired set for
+// its scope when prune is on. Each kind applies in its own transaction, in
+// dependency order (pools, event types, roles, subscriptions); a failure
+// stops the apply, and re-running the same bundle converges the rest.
+
+// platformApplicationCode stamps the events of the global dispatch-pool
+// sync, which has no owning application.
+const platformApplicationCode = "platform"
+
+// Change kinds and actions reported by the apply endpoint.
+const (
+	KindDispatchPool = "DISPATCH_POOL"
+	KindEventType    = "EVENT_TYPE"
+	KindRole         = "ROLE"
+	KindSubscription = "SUBSCRIPTION"
+
+	ActionCreate  = "CREATE"
+	ActionUpdate  = "UPDATE"
+	ActionDelete  = "DELETE"
+	ActionArchive = "ARCHIVE"
+)
+
+// applyBundle is the declarative document. Sections are pointers so an
+// omitted section (unmanaged) is distinguishable from an empty one.
+type applyBundle struct {
+	DispatchPools *[]syncDispatchPoolInputRequest `json:"dispatchPools"`
+	Applications  []applyApplication              `json:"applications"`
+}
+
+type applyApplication struct {
+	Code          string                          `json:"code"`
+	EventTypes    *[]syncEventTypeInputRequest    `json:"eventTypes"`
+	Roles         *[]syncRoleInputRequest         `json:"roles"`
+	Subscriptions *[]syncSubscriptionInputRequest `json:"subscriptions"`
+}
+
+// ApplyChange is one row the bundle creates, updates or removes. Fields
+// names the attributes an UPDATE changes.
+type ApplyChange struct {
+	Kind        string   `json:"kind" enum:"DISPATCH_POOL,EVENT_TYPE,ROLE,SUBSCRIPTION"`
+	Application string   `json:"application,omitempty" doc:"Owning application code; empty for dispatch pools"`
+	Key         string   `json:"key" doc:"Code (role name for roles)"`
+	Action      string   `json:"action" enum:"CREATE,UPDATE,DELETE,ARCHIVE"`
+	Fields      []string `json:"fields,omitempty"`
+}
+
+// ApplyResponse reports the changes an apply made — or, on a dry run,
+// would make. An empty Changes list means the platform already matches.
+type ApplyResponse struct {
+	DryRun    bool          `json:"dryRun"`
+	Changes   []ApplyChange `json:"changes"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Deleted   int           `json:"deleted" doc:"Deleted or archived rows"`
+	Unchanged int           `json:"unchanged" doc:"Declared rows that already match"`
+}
+
+type applyInput struct {
+	DryRun      bool   `query:"dryRun" doc:"Compute and return the changes without applying them"`
+	Prune       bool   `query:"prune" doc:"Remove API/SDK-managed rows absent from a declared section (dispatch pools are archived)"`
+	ContentType string `header:"Content-Type"`
+	RawBody     []byte `contentType:"application/yaml" doc:"Bundle as YAML or JSON (Content-Type application/json)"`
+}
+
+type applyOutput struct {
+	Body ApplyResponse
+}
+
+// RegisterApply mounts the declarative apply endpoint. Anchor-only: a
+// bundle reaches global dispatch pools and any application.
+func RegisterApply(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Post(g, "applyPlatformConfig", "/api/admin/platform/apply", "Apply a declarative configuration bundle (event types, subscriptions, dispatch pools, roles)", http.StatusOK, s.apply)
+}
+
+// applyPlan is a validated bundle: the sync commands to run and the changes
+// they make. Commands are nil for kinds with nothing to change.
+type applyPlan struct {
+	changes       []ApplyChange
+	unchanged     int
+	pools         *dispatchpoolops.SyncDispatchPoolsCommand
+	eventTypes    []eventtypeops.SyncEventTypesCommand
+	roles         []roleops.SyncRolesCommand
+	subscriptions []subscriptionops.SyncSubscriptionsCommand
+}
+
+func (s *State) apply(ctx context.Context, in *applyInput) (*applyOutput, error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.RequireAnchor(ac); err != nil {
+		return nil, err
+	}
+	bundle, err := parseBundle(in.ContentType, in.RawBody)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.plan(ctx, ac, bundle, in.Prune)
+	if err != nil {
+		return nil, err
+	}
+
+	if !in.DryRun {
+		ec := reqctx.ExecutionContext(ctx)
+		if plan.pools != nil {
+			if _, err := usecaseop.Run(ctx, s.UoW, dispatchpoolops.SyncDispatchPools(s.DispatchPools), *plan.pools, ec); err != nil {
+				return nil, err
+			}
+		}
+		for _, cmd := range plan.eventTypes {
+			if _, err := usecaseop.Run(ctx, s.UoW, eventtypeops.SyncEventTypes(s.EventTypes), cmd, ec); err != nil {
+				return nil, err
+			}
+		}
+		for _, cmd := range plan.roles {
+			if _, err := usecaseop.Run(ctx, s.UoW, roleops.SyncRoles(s.Roles), cmd, ec); err != nil {
+				return nil, err
+			}
+		}
+		for _, cmd := range plan.subscriptions {
+			if _, err := usecaseop.Run(ctx, s.UoW, subscriptionops.SyncSubscriptions(s.Subscriptions, s.Connections, s.DispatchPools), cmd, ec); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res := ApplyResponse{DryRun: in.DryRun, Changes: plan.changes, Unchanged: plan.unchanged}
+	for _, c := range plan.changes {
+		switch c.Action {
+		case ActionCreate:
+			res.Created++
+		case ActionUpdate:
+			res.Updated++
+		default:
+			res.Deleted++
+		}
+	}
+	return &applyOutput{Body: res}, nil
+}
+
+// parseBundle decodes a YAML or JSON bundle. YAML is converted to JSON
+// first so both formats share one strict decoder: unknown keys are an
+// error rather than silently unmanaged.
+func parseBundle(contentType string, raw []byte) (*applyBundle, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, usecase.Validation("BUNDLE_REQUIRED", "A configuration bundle is required")
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		var doc any
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, usecase.Validation("INVALID_BUNDLE", "Bundle is not valid YAML: "+err.Error())
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, usecase.Validation("INVALID_BUNDLE", "Bundle keys must be strings: "+err.Error())
+		}
+		raw = converted
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var b applyBundle
+	if err := dec.Decode(&b); err != nil {
+		return nil, usecase.Validation("INVALID_BUNDLE", "Bundle does not match the expected shape: "+err.Error())
+	}
+	return &b, nil
+}
+
+// plan resolves the bundle's applications, validates every section with
+// its sync use case's own rules, and diffs it against the stored rows, so
+// a dry run fails exactly where the apply would.
+func (s *State) plan(ctx context.Context, ac *auth.AuthContext, b *applyBundle, prune bool) (*applyPlan, error) {
+	p := &applyPlan{changes: []ApplyChange{}}
+
+	if b.DispatchPools != nil {
+		// Pools are global: only a caller scoped to every application may
+		// declare (and, with prune, archive) them.
+		if !ac.AllApplications {
+			return nil, httperror.Forbidden("Dispatch pools are global; declaring them requires access to all applications")
+		}
+		cmd := dispatchpoolops.SyncDispatchPoolsCommand{
+			ApplicationCode: platformApplicationCode,
+			Pools:           dispatchPoolInputs(*b.DispatchPools),
+			RemoveUnlisted:  prune,
+		}
+		if err := validateCommand(ctx, dispatchpoolops.SyncDispatchPools(s.DispatchPools), cmd); err != nil {
+			return nil, err
+		}
+		if err := requireUniqueKeys(KindDispatchPool, cmd.Pools, func(in dispatchpoolops.SyncDispatchPoolInput) string { return in.Code }); err != nil {
+			return nil, err
+		}
+		existing, err := s.DispatchPools.FindWithFilters(ctx, nil, nil)
+		if err != nil {
+			return nil, usecase.Internal("REPO", "find_all(dispatch_pools) failed", err)
+		}
+		changes, unchanged := diffDispatchPools(existing, cmd.Pools, prune)
+		p.record(changes, unchanged)
+		if len(changes) > 0 {
+			p.pools = &cmd
+		}
+	}
+
+	apps := make([]*application.Application, len(b.Applications))
+	seen := make(map[string]struct{}, len(b.Applications))
+	for i, a := range b.Applications {
+		if _, dup := seen[a.Code]; dup {
+			return nil, usecase.Validation("DUPLICATE_APPLICATION", fmt.Sprintf("Application '%s' is listed twice", a.Code))
+		}
+		seen[a.Code] = struct{}{}
+		app, err := s.resolveApp(ctx, a.Code)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.requireAppAccess(ac, app); err != nil {
+			return nil, err
+		}
+		apps[i] = app
+	}
+
+	for i, a := range b.Applications {
+		if a.EventTypes == nil {
+			continue
+		}
+		if err := s.planEventTypes(ctx, p, apps[i], *a.EventTypes, prune); err != nil {
+			return nil, err
+		}
+	}
+	for i, a := range b.Applications {
+		if a.Roles == nil {
+			continue
+		}
+		if err := s.planRoles(ctx, p, apps[i], *a.Roles, prune); err != nil {
+			return nil, err
+		}
+	}
+	for i, a := range b.Applications {
+		if a.Subscriptions == nil {
+			continue
+		}
+		if err := s.planSubscriptions(ctx, p, apps[i], *a.Subscriptions, prune); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *applyPlan) record(changes []ApplyChange, unchanged int) {
+	p.changes = append(p.changes, changes...)
+	p.unchanged += unchanged
+}
+
+func (s *State) planEventTypes(ctx context.Context, p *applyPlan, app *application.Application, reqs []syncEventTypeInputRequest, prune bool) error {
+	cmd := eventtypeops.SyncEventTypesCommand{
+		ApplicationCode: app.Code,
+		EventTypes:      eventTypeInputs(reqs),
+		RemoveUnlisted:  prune,
+	}
+	if err := validateCommand(ctx, eventtypeops.SyncEventTypes(s.EventTypes), cmd); err != nil {
+		return err
+	}
+	if err := requireUniqueKeys(KindEventType, cmd.EventTypes, func(in eventtypeops.SyncEventTypeInput) string { return in.Code }); err != nil {
+		return err
+	}
+	existing, err := s.EventTypes.FindByApplication(ctx, app.Code)
+	if err != nil {
+		return usecase.Internal("REPO", "find_by_application failed", err)
+	}
+	changes, unchanged := diffEventTypes(app.Code, existing, cmd.EventTypes, prune)
+	// The sync rejects a malformed code only while executing; check the
+	// rows it would create now so a dry run reports it too.
+	creates := make(map[string]struct{})
+	for _, c := range changes {
+		if c.Action == ActionCreate {
+			creates[c.Key] = struct{}{}
+		}
+	}
+	for _, in := range cmd.EventTypes {
+		if _, ok := creates[in.Code]; !ok {
+			continue
+		}
+		if _, err := eventtype.New(in.Code, in.Name); err != nil {
+			return usecase.Validation("INVALID_CODE", fmt.Sprintf("%s (offending code: %q)", err.Error(), in.Code))
+		}
+	}
+	p.record(changes, unchanged)
+	if len(changes) > 0 {
+		p.eventTypes = append(p.eventTypes, cmd)
+	}
+	return nil
+}
+
+func (s *State) planRoles(ctx context.Context, p *applyPlan, app *application.Application, reqs []syncRoleInputRequest, prune bool) error {
+	cmd := roleops.SyncRolesCommand{
+		ApplicationCode: app.Code,
+		ApplicationID:   app.ID,
+		Roles:           roleInputs(reqs),
+		RemoveUnlisted:  prune,
+	}
+	if err := validateCommand(ctx, roleops.SyncRoles(s.Roles), cmd); err != nil {
+		return err
+	}
+	if err := requireUniqueKeys(KindRole, cmd.Roles, func(in roleops.SyncRoleInput) string { return roleops.SyncedRoleName(app.Code, in.Name) }); err != nil {
+		return err
+	}
+	existing, err := s.Roles.FindByApplicationID(ctx, app.ID)
+	if err != nil {
+		return usecase.Internal("REPO", "find_by_application failed", err)
+	}
+	changes, unchanged := diffRoles(app.Code, existing, cmd.Roles, prune)
+	// Mirror the sync's refusal to drop a role principals still hold.
+	for _, c := range changes {
+		if c.Action != ActionDelete {
+			continue
+		}
+		count, err := s.Roles.CountAssignments(ctx, c.Key)
+		if err != nil {
+			return usecase.Internal("REPO", "count_assignments failed for "+c.Key, err)
+		}
+		if count > 0 {
+			return usecase.BusinessRule("ROLE_HAS_ASSIGNMENTS",
+				fmt.Sprintf("Cannot remove role '%s' — %d principal(s) still hold it. "+
+					"Strip the assignments before applying.", c.Key, count))
+		}
+	}
+	p.record(changes, unchanged)
+	if len(changes) > 0 {
+		p.roles = append(p.roles, cmd)
+	}
+	return nil
+}
+
+func (s *State) planSubscriptions(ctx context.Context, p *applyPlan, app *application.Application, reqs []syncSubscriptionInputRequest, prune bool) error {
+	cmd := subscriptionops.SyncSubscriptionsCommand{
+		ApplicationID:   app.ID,
+		ApplicationCode: app.Code,
+		Subscriptions:   subscriptionInputs(reqs),
+		RemoveUnlisted:  prune,
+	}
+	if err := validateCommand(ctx, subscriptionops.SyncSubscriptions(s.Subscriptions, s.Connections, s.DispatchPools), cmd); err != nil {
+		return err
+	}
+	if err := requireUniqueKeys(KindSubscription, cmd.Subscriptions, func(in subscriptionops.SyncSubscriptionInput) string { return in.Code }); err != nil {
+		return err
+	}
+	for _, in := range cmd.Subscriptions {
+		if in.ConnectionID == nil {
+			continue
+		}
+		c, err := s.Connections.FindByID(ctx, *in.ConnectionID)
+		if err != nil {
+			return usecase.Internal("REPO", "find_by_id(connection) failed", err)
+		}
+		if c == nil {
+			return usecase.NotFound("CONNECTION_NOT_FOUND", "Connection '"+*in.ConnectionID+"' not found")
+		}
+	}
+	all, err := s.Subscriptions.FindByApplicationCode(ctx, app.Code)
+	if err != nil {
+		return usecase.Internal("REPO", "find_by_application_code failed", err)
+	}
+	// Same environment scoping as SyncSubscriptions.
+	existing := subscription.InEnvironment(all, auth.FromContext(ctx).EnvironmentFor(""))
+	changes, unchanged := diffSubscriptions(app.Code, existing, cmd.Subscriptions, prune)
+	p.record(changes, unchanged)
+	if len(changes) > 0 {
+		p.subscriptions = append(p.subscriptions, cmd)
+	}
+	return nil
+}
+
+// validateCommand runs an operation's Validate step on its own, so the
+// plan rejects a bundle with the same codes the apply would.
+func validateCommand[C any, E usecase.DomainEvent](ctx context.Context, op usecaseop.Operation[C, E], cmd C) error {
+	if op.Validate == nil {
+		return nil
+	}
+	return op.Validate(ctx, cmd)
+}
+
+// requireUniqueKeys rejects a section that declares the same key twice;
+// the sync would apply both entries to one row, last one winning.
+func requireUniqueKeys[T any](kind string, items []T, key func(T) string) error {
+	seen := make(map[string]struct{}, len(items))
+	for _, it := range items {
+		k := key(it)
+		if _, dup := seen[k]; dup {
+			return usecase.Validation("DUPLICATE_KEY", fmt.Sprintf("%s '%s' is declared twice", kind, k))
+		}
+		seen[k] = struct{}{}
+	}
+	return nil
+}
+
+// ── Diffs ────────────────────────────────────────────────────────────────
+//
+// Each diff mirrors what its Sync<Resource> use case writes: the fields it
+// overwrites, the rows it leaves alone (UI/CODE-authored ones) and the rows
+// RemoveUnlisted removes. Optional inputs the sync only applies when present
+// (role permissions, subscription retries/timeout/pool) only count when set.
+
+func diffDispatchPools(existing []dispatchpool.DispatchPool, desired []dispatchpoolops.SyncDispatchPoolInput, prune bool) ([]ApplyChange, int) {
+	byCode := make(map[string]*dispatchpool.DispatchPool, len(existing))
+	for i := range existing {
+		byCode[existing[i].Code] = &existing[i]
+	}
+	var (
+		changes   []ApplyChange
+		unchanged int
+		listed    = make(map[string]struct{}, len(desired))
+	)
+	for _, in := range desired {
+		listed[in.Code] = struct{}{}
+		cur, ok := byCode[in.Code]
+		if !ok {
+			changes = append(changes, ApplyChange{Kind: KindDispatchPool, Key: in.Code, Action: ActionCreate})
+			continue
+		}
+		var fields []string
+		fields = changed(fields, "name", cur.Name == in.Name)
+		fields = changed(fields, "description", sameString(cur.Description, in.Description))
+		fields = changed(fields, "rateLimit", samePtr(cur.RateLimit, in.RateLimit))
+		fields = changed(fields, "concurrency", cur.Concurrency == in.Concurrency)
+		if len(fields) == 0 {
+			unchanged++
+			continue
+		}
+		changes = append(changes, ApplyChange{Kind: KindDispatchPool, Key: in.Code, Action: ActionUpdate, Fields: fields})
+	}
+	if prune {
+		for _, cur := range existing {
+			if _, ok := listed[cur.Code]; ok || cur.Status == dispatchpool.StatusArchived {
+				continue
+			}
+			changes = append(changes, ApplyChange{Kind: KindDispatchPool, Key: cur.Code, Action: ActionArchive})
+		}
+	}
+	return changes, unchanged
+}
+
+func diffEventTypes(appCode string, existing []eventtype.EventType, desired []eventtypeops.SyncEventTypeInput, prune bool) ([]ApplyChange, int) {
+	byCode := make(map[string]*eventtype.EventType, len(existing))
+	for i := range existing {
+		byCode[existing[i].Code] = &existing[i]
+	}
+	var (
+		changes   []ApplyChange
+		unchanged int
+		listed    = make(map[string]struct{}, len(desired))
+	)
+	for _, in := range desired {
+		listed[in.Code] = struct{}{}
+		cur, ok := byCode[in.Code]
+		if !ok {
+			changes = append(changes, ApplyChange{Kind: KindEventType, Application: appCode, Key: in.Code, Action: ActionCreate})
+			continue
+		}
+		var fields []string
+		fields = changed(fields, "name", cur.Name == in.Name)
+		fields = changed(fields, "description", sameString(cur.Description, in.Description))
+		if len(fields) == 0 {
+			unchanged++
+			continue
+		}
+		changes = append(changes, ApplyChange{Kind: KindEventType, Application: appCode, Key: in.Code, Action: ActionUpdate, Fields: fields})
+	}
+	if prune {
+		for _, cur := range existing {
+			if _, ok := listed[cur.Code]; ok || cur.Source != eventtype.SourceAPI {
+				continue
+			}
+			changes = append(changes, ApplyChange{Kind: KindEventType, Application: appCode, Key: cur.Code, Action: ActionDelete})
+		}
+	}
+	return changes, unchanged
+}
+
+func diffRoles(appCode string, existing []role.Role, desired []roleops.SyncRoleInput, prune bool) ([]ApplyChange, int) {
+	byName := make(map[string]*role.Role, len(existing))
+	for i := range existing {
+		byName[existing[i].Name] = &existing[i]
+	}
+	var (
+		changes   []ApplyChange
+		unchanged int
+		listed    = make(map[string]struct{}, len(desired))
+	)
+	for _, in := range desired {
+		name := roleops.SyncedRoleName(appCode, in.Name)
+		listed[name] = struct{}{}
+		cur, ok := byName[name]
+		if !ok {
+			changes = append(changes, ApplyChange{Kind: KindRole, Application: appCode, Key: name, Action: ActionCreate})
+			continue
+		}
+		if cur.Source != role.SourceSDK {
+			continue // the sync never touches CODE/DATABASE roles
+		}
+		displayName := in.Name
+		if in.DisplayName != nil {
+			displayName = *in.DisplayName
+		}
+		var fields []string
+		fields = changed(fields, "displayName", cur.DisplayName == displayName)
+		fields = changed(fields, "description", sameString(cur.Description, in.Description))
+		if len(in.Permissions) > 0 {
+			fields = changed(fields, "permissions", slices.Equal(normalizedSet(cur.Permissions), normalizedSet(in.Permissions)))
+		}
+		fields = changed(fields, "clientManaged", cur.ClientManaged == in.ClientManaged)
+		if len(fields) == 0 {
+			unchanged++
+			continue
+		}
+		changes = append(changes, ApplyChange{Kind: KindRole, Application: appCode, Key: name, Action: ActionUpdate, Fields: fields})
+	}
+	if prune {
+		for _, cur := range existing {
+			if _, ok := listed[cur.Name]; ok || cur.Source != role.SourceSDK {
+				continue
+			}
+			changes = append(changes, ApplyChange{Kind: KindRole, Application: appCode, Key: cur.Name, Action: ActionDelete})
+		}
+	}
+	return changes, unchanged
+}
+
+func diffSubscriptions(appCode string, existing []subscription.Subscription, desired []subscriptionops.SyncSubscriptionInput, prune bool) ([]ApplyChange, int) {
+	byCode := make(map[string]*subscription.Subscription, len(existing))
+	for i := range existing {
+		byCode[existing[i].Code] = &existing[i]
+	}
+	syncManaged := func(sub *subscription.Subscription) bool {
+		return sub.Source == subscription.SourceAPI || sub.Source == subscription.SourceCode
+	}
+	var (
+		changes   []ApplyChange
+		unchanged int
+		listed    = make(map[string]struct{}, len(desired))
+	)
+	for _, in := range desired {
+		listed[in.Code] = struct{}{}
+		cur, ok := byCode[in.Code]
+		if !ok {
+			changes = append(changes, ApplyChange{Kind: KindSubscription, Application: appCode, Key: in.Code, Action: ActionCreate})
+			continue
+		}
+		if !syncManaged(cur) {
+			continue // the sync never touches UI-authored subscriptions
+		}
+		var fields []string
+		fields = changed(fields, "name", cur.Name == in.Name)
+		fields = changed(fields, "description", sameString(cur.Description, in.Description))
+		fields = changed(fields, "target", cur.Endpoint == in.Target)
+		fields = changed(fields, "connectionId", sameString(cur.ConnectionID, in.ConnectionID))
+		fields = changed(fields, "eventTypes", sameBindings(cur.EventTypes, in.EventTypes))
+		fields = changed(fields, "dataOnly", cur.DataOnly == in.DataOnly)
+		if in.MaxRetries != nil {
+			fields = changed(fields, "maxRetries", cur.MaxRetries == *in.MaxRetries)
+		}
+		if in.TimeoutSeconds != nil {
+			fields = changed(fields, "timeoutSeconds", cur.TimeoutSeconds == *in.TimeoutSeconds)
+		}
+		if in.DispatchPoolCode != nil && strings.TrimSpace(*in.DispatchPoolCode) != "" {
+			fields = changed(fields, "dispatchPoolCode", sameString(cur.DispatchPoolCode, in.DispatchPoolCode))
+		}
+		if len(fields) == 0 {
+			unchanged++
+			continue
+		}
+		changes = append(changes, ApplyChange{Kind: KindSubscription, Application: appCode, Key: in.Code, Action: ActionUpdate, Fields: fields})
+	}
+	if prune {
+		for i := range existing {
+			cur := &existing[i]
+			if _, ok := listed[cur.Code]; ok || !syncManaged(cur) {
+				continue
+			}
+			changes = append(changes, ApplyChange{Kind: KindSubscription, Application: appCode, Key: cur.Code, Action: ActionDelete})
+		}
+	}
+	return changes, unchanged
+}
+
+// changed appends field to fields unless same.
+func changed(fields []string, field string, same bool) []string {
+	if same {
+		return fields
+	}
+	return append(fields, field)
+}
+
+// sameString treats nil and "" alike: a bundle that omits an optional text
+// field matches a row that stored it empty.
+func sameString(a, b *string) bool {
+	var av, bv string
+	if a != nil {
+		av = *a
+	}
+	if b != nil {
+		bv = *b
+	}
+	return av == bv
+}
+
+func samePtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameBindings compares event-type bindings in order; the sync replaces
+// the whole list, so a reorder is a change.
+func sameBindings(cur []subscription.EventTypeBinding, in []subscriptionops.SyncEventTypeBindingInput) bool {
+	if len(cur) != len(in) {
+		return false
+	}
+	for i := range cur {
+		if cur[i].EventTypeCode != in[i].EventTypeCode || !sameString(cur[i].Filter, in[i].Filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizedSet sorts and de-duplicates, the form roles store permissions in.
+func normalizedSet(v []string) []string {
+	out := slices.Clone(v)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
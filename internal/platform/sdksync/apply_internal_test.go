@@ -0,0 +1,148 @@
+package sdksync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
+	dispatchpoolops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	eventtypeops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+	roleops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/role/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	subscriptionops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestParseBundleYAMLAndJSON(t *testing.T) {
+	yamlDoc := `
+dispatchPools:
+  - code: default
+    name: Default
+applications:
+  - code: orders
+    eventTypes:
+      - code: orders:fulfilment:order:created
+        name: Order created
+`
+	b, err := parseBundle("application/yaml", []byte(yamlDoc))
+	require.NoError(t, err)
+	require.NotNil(t, b.DispatchPools)
+	assert.Len(t, *b.DispatchPools, 1)
+	require.Len(t, b.Applications, 1)
+	require.NotNil(t, b.Applications[0].EventTypes)
+	assert.Nil(t, b.Applications[0].Roles, "an omitted section stays unmanaged")
+
+	// No Content-Type: YAML is a superset of JSON, so JSON parses too.
+	b, err = parseBundle("", []byte(`{"applications":[{"code":"orders","roles":[]}]}`))
+	require.NoError(t, err)
+	require.NotNil(t, b.Applications[0].Roles)
+	assert.Empty(t, *b.Applications[0].Roles)
+
+	_, err = parseBundle("application/json", []byte(`{"applications":[{"code":"orders","evenTypes":[]}]}`))
+	var ue *usecase.Error
+	require.True(t, errors.As(err, &ue))
+	assert.Equal(t, "INVALID_BUNDLE", ue.Code, "unknown keys must not be silently ignored")
+
+	_, err = parseBundle("application/yaml", nil)
+	require.True(t, errors.As(err, &ue))
+	assert.Equal(t, "BUNDLE_REQUIRED", ue.Code)
+}
+
+func TestDiffDispatchPools(t *testing.T) {
+	existing := []dispatchpool.DispatchPool{
+		{Code: "same", Name: "Same", Concurrency: 10, Status: dispatchpool.StatusActive},
+		{Code: "changed", Name: "Old", Concurrency: 10, Status: dispatchpool.StatusActive},
+		{Code: "stale", Name: "Stale", Concurrency: 10, Status: dispatchpool.StatusActive},
+		{Code: "gone", Name: "Gone", Concurrency: 10, Status: dispatchpool.StatusArchived},
+	}
+	desired := []dispatchpoolops.SyncDispatchPoolInput{
+		{Code: "same", Name: "Same", Concurrency: 10},
+		{Code: "changed", Name: "New", RateLimit: ptr(int32(60)), Concurrency: 10},
+		{Code: "fresh", Name: "Fresh", Concurrency: 5},
+	}
+
+	changes, unchanged := diffDispatchPools(existing, desired, false)
+	assert.Equal(t, 1, unchanged)
+	assert.Equal(t, []ApplyChange{
+		{Kind: KindDispatchPool, Key: "changed", Action: ActionUpdate, Fields: []string{"name", "rateLimit"}},
+		{Kind: KindDispatchPool, Key: "fresh", Action: ActionCreate},
+	}, changes)
+
+	changes, _ = diffDispatchPools(existing, desired, true)
+	assert.Contains(t, changes, ApplyChange{Kind: KindDispatchPool, Key: "stale", Action: ActionArchive})
+	assert.Len(t, changes, 3, "an already-archived pool is not archived again")
+}
+
+func TestDiffEventTypesPrunesOnlyAPIRows(t *testing.T) {
+	existing := []eventtype.EventType{
+		{Code: "orders:a:b:kept", Name: "Kept", Source: eventtype.SourceAPI},
+		{Code: "orders:a:b:api", Name: "API", Source: eventtype.SourceAPI},
+		{Code: "orders:a:b:ui", Name: "UI", Source: eventtype.SourceUI},
+	}
+	desired := []eventtypeops.SyncEventTypeInput{{Code: "orders:a:b:kept", Name: "Kept", Description: ptr("")}}
+
+	changes, unchanged := diffEventTypes("orders", existing, desired, true)
+	assert.Equal(t, 1, unchanged, "an empty description matches an unset one")
+	assert.Equal(t, []ApplyChange{
+		{Kind: KindEventType, Application: "orders", Key: "orders:a:b:api", Action: ActionDelete},
+	}, changes)
+}
+
+func TestDiffRolesMirrorsSyncRules(t *testing.T) {
+	existing := []role.Role{
+		{Name: "orders:viewer", DisplayName: "viewer", Permissions: []string{"a", "b"}, Source: role.SourceSDK},
+		{Name: "orders:admin", DisplayName: "Admin", Source: role.SourceCode},
+		{Name: "orders:old", DisplayName: "old", Source: role.SourceSDK},
+	}
+	desired := []roleops.SyncRoleInput{
+		// Qualified, upper-cased, permissions reordered: still the same role.
+		{Name: "orders:VIEWER", DisplayName: ptr("viewer"), Permissions: []string{"b", "a", "a"}},
+		// CODE-managed: the sync leaves it alone, so no change is reported.
+		{Name: "admin", DisplayName: ptr("Administrator")},
+		{Name: "editor"},
+	}
+
+	changes, unchanged := diffRoles("orders", existing, desired, true)
+	assert.Equal(t, 1, unchanged)
+	assert.Equal(t, []ApplyChange{
+		{Kind: KindRole, Application: "orders", Key: "orders:editor", Action: ActionCreate},
+		{Kind: KindRole, Application: "orders", Key: "orders:old", Action: ActionDelete},
+	}, changes)
+}
+
+func TestDiffSubscriptions(t *testing.T) {
+	existing := []subscription.Subscription{
+		{
+			Code: "hook", Name: "Hook", Endpoint: "https://a.example/hook", Source: subscription.SourceAPI,
+			EventTypes: []subscription.EventTypeBinding{{EventTypeCode: "orders:*:*:*"}},
+			MaxRetries: 3, TimeoutSeconds: 30,
+		},
+		{Code: "manual", Name: "Manual", Endpoint: "https://a.example/m", Source: subscription.SourceUI},
+	}
+	desired := []subscriptionops.SyncSubscriptionInput{{
+		Code: "hook", Name: "Hook", Target: "https://b.example/hook",
+		EventTypes: []subscriptionops.SyncEventTypeBindingInput{{EventTypeCode: "orders:*:*:*", Filter: ptr("data.total > 10")}},
+		MaxRetries: ptr(int32(3)),
+	}}
+
+	changes, unchanged := diffSubscriptions("orders", existing, desired, true)
+	assert.Zero(t, unchanged)
+	assert.Equal(t, []ApplyChange{
+		{Kind: KindSubscription, Application: "orders", Key: "hook", Action: ActionUpdate, Fields: []string{"target", "eventTypes"}},
+	}, changes, "UI-authored subscriptions are never pruned")
+}
+
+func TestRequireUniqueKeys(t *testing.T) {
+	pools := []dispatchpoolops.SyncDispatchPoolInput{{Code: "a"}, {Code: "a"}}
+	err := requireUniqueKeys(KindDispatchPool, pools, func(in dispatchpoolops.SyncDispatchPoolInput) string { return in.Code })
+	var ue *usecase.Error
+	require.True(t, errors.As(err, &ue))
+	assert.Equal(t, "DUPLICATE_KEY", ue.Code)
+}
@@ -0,0 +1,97 @@
+//go:build integration
+
+package sdksync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
+	appops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/application/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/connection"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// TestApply_DryRunThenApplyConverges drives the apply handler with a YAML
+// bundle: the dry run reports every change and writes nothing, the apply
+// makes them, and re-applying the same bundle reports no changes.
+func TestApply_DryRunThenApplyConverges(t *testing.T) {
+	ctx := context.Background()
+	pool := testpg.Pool(t)
+	uow := testpg.NewUoW(t)
+
+	appCode := "applyconv"
+	_, err := usecaseop.Run(ctx, uow, appops.CreateApplication(application.NewRepository(pool)),
+		appops.CreateCommand{Code: appCode, Name: "Apply Converge"}, testpg.TestEC())
+	require.NoError(t, err)
+
+	s := &State{
+		Apps:          application.NewRepository(pool),
+		EventTypes:    eventtype.NewRepository(pool),
+		Roles:         role.NewRepository(pool),
+		Subscriptions: subscription.NewRepository(pool),
+		Connections:   connection.NewRepository(pool),
+		DispatchPools: dispatchpool.NewRepository(pool),
+		UoW:           uow,
+	}
+	authCtx := auth.WithContext(ctx, &auth.AuthContext{
+		PrincipalID:     "p_apply_conv",
+		Scope:           auth.ScopeAnchor,
+		AllApplications: true,
+	})
+
+	bundle := []byte(`
+dispatchPools:
+  - code: applyconv_pool
+    name: Apply pool
+    concurrency: 4
+applications:
+  - code: applyconv
+    eventTypes:
+      - code: applyconv:orders:order:created
+        name: Order created
+    roles:
+      - name: viewer
+        permissions: [applyconv:orders:read]
+    subscriptions:
+      - code: applyconv-hook
+        name: Hook
+        target: https://hooks.example.com/orders
+        dispatchPoolCode: applyconv_pool
+        eventTypes:
+          - eventTypeCode: applyconv:orders:order:created
+`)
+	in := &applyInput{DryRun: true, ContentType: "application/yaml", RawBody: bundle}
+
+	out, err := s.apply(authCtx, in)
+	require.NoError(t, err)
+	assert.Equal(t, 4, out.Body.Created)
+	et, err := s.EventTypes.FindByApplication(ctx, appCode)
+	require.NoError(t, err)
+	assert.Empty(t, et, "a dry run writes nothing")
+
+	in.DryRun = false
+	out, err = s.apply(authCtx, in)
+	require.NoError(t, err)
+	assert.Equal(t, 4, out.Body.Created)
+
+	sub, err := s.Subscriptions.FindByApplicationCode(ctx, appCode)
+	require.NoError(t, err)
+	require.Len(t, sub, 1)
+	require.NotNil(t, sub[0].DispatchPoolCode, "the pool created earlier in the same apply resolves")
+	assert.Equal(t, "applyconv_pool", *sub[0].DispatchPoolCode)
+
+	out, err = s.apply(authCtx, in)
+	require.NoError(t, err)
+	assert.Empty(t, out.Body.Changes, "re-applying an applied bundle is a no-op")
+	assert.Equal(t, 4, out.Body.Unchanged)
+}
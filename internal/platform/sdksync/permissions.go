@@ -0,0 +1,181 @@
+package sdksync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/validate"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// ── Permission catalog registration ─────────────────────────────────────
+//
+// An application declares the permissions its roles may grant. Once it
+// has, role composition (create, update, grant, SDK role sync) refuses
+// permissions in its namespace that aren't declared — see
+// role.UnknownPermissions — so it registers its catalog before syncing
+// roles that use it. The platform's own catalog is seeded at startup and
+// can't be registered here.
+
+// RegisterPermissions mounts the per-application permission catalog
+// endpoints.
+func RegisterPermissions(api huma.API, s *State) {
+	g := apiroute.New(api, tag)
+	apiroute.Get(g, "listApplicationPermissions", "/api/admin/platform/applications/{id}/permissions", "List an application's registered permission catalog", s.listApplicationPermissions)
+	apiroute.Post(g, "registerApplicationPermissions", "/api/admin/platform/applications/{id}/permissions", "Register an application's permission catalog", http.StatusOK, s.registerApplicationPermissions)
+}
+
+type registerPermissionInputRequest struct {
+	Code        string  `json:"code" doc:"Full code (application:context:aggregate:action); the first segment is the application code"`
+	Description *string `json:"description,omitempty"`
+	Category    *string `json:"category,omitempty" doc:"Grouping label for the permission pickers"`
+}
+
+type registerPermissionsRequest struct {
+	Permissions []registerPermissionInputRequest `json:"permissions"`
+}
+
+type applicationPermissionsInput struct {
+	ID string `path:"id" doc:"Application id or code"`
+}
+
+type registerPermissionsInput struct {
+	ID             string `path:"id" doc:"Application id or code"`
+	RemoveUnlisted bool   `query:"removeUnlisted" doc:"Remove registered permissions not in the list"`
+	Body           registerPermissionsRequest
+}
+
+// ApplicationPermissionResponse is one registered catalog entry.
+type ApplicationPermissionResponse struct {
+	Code        string  `json:"code"`
+	Description *string `json:"description,omitempty"`
+	Category    *string `json:"category,omitempty"`
+	Source      string  `json:"source"`
+}
+
+// ApplicationPermissionListResponse is an application's registered catalog.
+type ApplicationPermissionListResponse struct {
+	ApplicationCode string                          `json:"applicationCode"`
+	Permissions     []ApplicationPermissionResponse `json:"permissions"`
+}
+
+type applicationPermissionsOutput struct {
+	Body ApplicationPermissionListResponse
+}
+
+func (s *State) listApplicationPermissions(ctx context.Context, in *applicationPermissionsInput) (*applicationPermissionsOutput, error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanReadPermissions(ac); err != nil {
+		return nil, err
+	}
+	app, err := s.resolveAppByID(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAppAccess(ac, app); err != nil {
+		return nil, err
+	}
+	rows, err := s.Permissions.FindByApplicationID(ctx, app.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_application failed", err)
+	}
+	out := ApplicationPermissionListResponse{ApplicationCode: app.Code, Permissions: make([]ApplicationPermissionResponse, 0, len(rows))}
+	for _, p := range rows {
+		out.Permissions = append(out.Permissions, ApplicationPermissionResponse{
+			Code:        p.Permission,
+			Description: p.Description,
+			Category:    p.Category,
+			Source:      string(p.Source),
+		})
+	}
+	return &applicationPermissionsOutput{Body: out}, nil
+}
+
+func (s *State) registerApplicationPermissions(ctx context.Context, in *registerPermissionsInput) (*syncResultOutput, error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanSyncPermissions(ac); err != nil {
+		return nil, err
+	}
+	app, err := s.resolveAppByID(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAppAccess(ac, app); err != nil {
+		return nil, err
+	}
+	perms, err := permissionInputs(app.Code, in.Body.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Permissions.Sync(ctx, role.SourceSDK, &app.ID, perms, in.RemoveUnlisted)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "permission sync failed", err)
+	}
+	return &syncResultOutput{Body: SyncResultResponse{
+		ApplicationCode: app.Code,
+		Created:         res.Created,
+		Updated:         res.Updated,
+		Deleted:         res.Deleted,
+		SyncedCodes:     res.Codes,
+	}}, nil
+}
+
+// resolveAppByID loads the application by id, falling back to its code.
+func (s *State) resolveAppByID(ctx context.Context, id string) (*application.Application, error) {
+	app, err := s.Apps.FindByID(ctx, id)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if app != nil {
+		return app, nil
+	}
+	return s.resolveApp(ctx, id)
+}
+
+// permissionInputs validates a registration payload for application
+// appCode: at least one permission, each a four-segment code in the
+// application's own namespace, none repeated. The platform's catalog is
+// code-defined and refused.
+func permissionInputs(appCode string, reqs []registerPermissionInputRequest) ([]role.Permission, error) {
+	if appCode == platformApplicationCode {
+		return nil, usecase.Validation("PLATFORM_PERMISSIONS_READ_ONLY", "The platform permission catalog is seeded from code")
+	}
+	if len(reqs) == 0 {
+		return nil, usecase.Validation("PERMISSIONS_REQUIRED", "At least one permission must be provided")
+	}
+	seen := make(map[string]struct{}, len(reqs))
+	out := make([]role.Permission, 0, len(reqs))
+	for _, r := range reqs {
+		code := strings.TrimSpace(r.Code)
+		parts := strings.Split(code, ":")
+		if len(parts) != 4 || parts[0] != appCode {
+			return nil, usecase.Validation("INVALID_CODE",
+				fmt.Sprintf("permission codes must be %s:context:aggregate:action (offending code: %q)", appCode, r.Code))
+		}
+		for _, seg := range parts[1:] {
+			if !validate.CodeUnderscorePattern.MatchString(seg) {
+				return nil, usecase.Validation("INVALID_CODE",
+					fmt.Sprintf("code segments must be lowercase alphanumerics, hyphens and underscores (offending code: %q)", r.Code))
+			}
+		}
+		if _, dup := seen[code]; dup {
+			return nil, usecase.Validation("DUPLICATE_CODE", fmt.Sprintf("permission %q is listed twice", code))
+		}
+		seen[code] = struct{}{}
+		out = append(out, role.Permission{
+			Permission:  code,
+			Name:        code,
+			Description: r.Description,
+			Category:    r.Category,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package sdksync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+func TestPermissionInputs(t *testing.T) {
+	desc, cat := "View orders", "Orders"
+	perms, err := permissionInputs("orders", []registerPermissionInputRequest{
+		{Code: " orders:sales:order:view ", Description: &desc, Category: &cat},
+		{Code: "orders:sales:order_line:edit"},
+	})
+	require.NoError(t, err)
+	require.Len(t, perms, 2)
+	assert.Equal(t, "orders:sales:order:view", perms[0].Permission)
+	assert.Equal(t, &desc, perms[0].Description)
+	assert.Equal(t, &cat, perms[0].Category)
+
+	cases := []struct {
+		name    string
+		appCode string
+		reqs    []registerPermissionInputRequest
+		code    string
+	}{
+		{"platform", "platform", []registerPermissionInputRequest{{Code: "platform:iam:user:view"}}, "PLATFORM_PERMISSIONS_READ_ONLY"},
+		{"empty", "orders", nil, "PERMISSIONS_REQUIRED"},
+		{"another namespace", "orders", []registerPermissionInputRequest{{Code: "billing:sales:order:view"}}, "INVALID_CODE"},
+		{"three segments", "orders", []registerPermissionInputRequest{{Code: "orders:order:view"}}, "INVALID_CODE"},
+		{"wildcard", "orders", []registerPermissionInputRequest{{Code: "orders:sales:order:*"}}, "INVALID_CODE"},
+		{"uppercase", "orders", []registerPermissionInputRequest{{Code: "orders:Sales:order:view"}}, "INVALID_CODE"},
+		{"duplicate", "orders", []registerPermissionInputRequest{{Code: "orders:sales:order:view"}, {Code: "orders:sales:order:view"}}, "DUPLICATE_CODE"},
+	}
+	for _, c := range cases {
+		_, err := permissionInputs(c.appCode, c.reqs)
+		var ue *usecase.Error
+		require.True(t, errors.As(err, &ue), c.name)
+		assert.Equal(t, c.code, ue.Code, c.name)
+	}
+}
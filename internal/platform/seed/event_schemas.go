@@ -43,11 +43,18 @@ func platformEventSchemas() map[string]json.RawMessage {
 		reqStrArray("removed"),
 	)
 	m["platform:iam:user:client-access-granted"] = obj(
-		reqStr("principalId"), reqStr("clientId"),
+		reqStr("principalId"), reqStr("clientId"), optStr("expiresAt"),
 	)
 	m["platform:iam:user:client-access-revoked"] = obj(
 		reqStr("principalId"), reqStr("clientId"),
 	)
+	m["platform:iam:user:client-access-expiry-changed"] = obj(
+		reqStr("principalId"), reqStr("clientId"),
+		optStr("expiresAt"), optStr("previousExpiresAt"),
+	)
+	m["platform:iam:user:client-access-expired"] = obj(
+		reqStr("principalId"), reqStr("clientId"), reqStr("expiredAt"),
+	)
 	// logged-in carries a richer payload — federatedClaims oneOf, etc.
 	m["platform:iam:user:logged-in"] = mustRaw(map[string]any{
 		"$schema": "http://json-schema.org/draft-07/schema#",
@@ -132,6 +139,9 @@ func platformEventSchemas() map[string]json.RawMessage {
 	m["platform:iam:client:note-added"] = obj(
 		reqStr("clientId"), reqStr("category"), reqStr("text"), reqStr("author"),
 	)
+	m["platform:iam:client:impersonated"] = obj(
+		reqStr("clientId"), reqStr("impersonationId"), reqStr("reason"), reqStr("expiresAt"),
+	)
 
 	// ── platform:iam:role ───────────────────────────────────────────────
 	m["platform:iam:role:created"] = obj(
@@ -213,6 +223,13 @@ func platformEventSchemas() map[string]json.RawMessage {
 	)
 	m["platform:admin:eventtype:archived"] = obj(reqStr("eventTypeId"), reqStr("code"))
 	m["platform:admin:eventtype:deleted"] = obj(reqStr("eventTypeId"), reqStr("code"))
+	m["platform:admin:eventtype:deprecated"] = obj(
+		reqStr("eventTypeId"), reqStr("code"), optStr("sunsetAt"), optStr("note"),
+	)
+	m["platform:admin:eventtype:reinstated"] = obj(reqStr("eventTypeId"), reqStr("code"))
+	m["platform:admin:eventtype:promoted"] = obj(reqStr("eventTypeId"), reqStr("code"))
+	m["platform:admin:maintenance:enabled"] = obj(reqBool("enabled"), optStr("message"), optU32("retryAfterSeconds"))
+	m["platform:admin:maintenance:disabled"] = obj(reqBool("enabled"), optStr("message"), optU32("retryAfterSeconds"))
 	m["platform:admin:eventtype:schema-added"] = obj(
 		reqStr("eventTypeId"), reqStr("version"), reqStr("mimeType"), reqStr("schemaType"),
 	)
@@ -225,6 +242,13 @@ func platformEventSchemas() map[string]json.RawMessage {
 		reqU32("created"), reqU32("updated"), reqU32("deleted"),
 		reqStrArray("syncedCodes"),
 	)
+	m["platform:admin:eventtype:schemas-imported"] = obj(
+		reqStr("eventTypeId"), reqStr("code"), reqStrArray("versions"),
+	)
+	m["platform:admin:eventtypes:imported"] = obj(
+		reqU32("created"), reqU32("updated"), reqU32("versionsAdded"),
+		reqStrArray("importedCodes"),
+	)
 
 	// ── platform:admin:connection ───────────────────────────────────────
 	m["platform:admin:connection:created"] = obj(
@@ -261,12 +285,17 @@ func platformEventSchemas() map[string]json.RawMessage {
 		reqStr("connectionId"), reqStrArray("eventTypes"), optStr("clientId"),
 	)
 	m["platform:admin:subscription:updated"] = obj(
-		reqStr("subscriptionId"), optStr("name"),
+		reqStr("subscriptionId"), optStr("name"), optStr("status"),
 		reqStrArray("eventTypesAdded"), reqStrArray("eventTypesRemoved"),
 	)
 	m["platform:admin:subscription:paused"] = obj(reqStr("subscriptionId"), reqStr("code"))
 	m["platform:admin:subscription:resumed"] = obj(reqStr("subscriptionId"), reqStr("code"))
 	m["platform:admin:subscription:deleted"] = obj(reqStr("subscriptionId"), reqStr("code"))
+	m["platform:admin:subscription:approved"] = obj(reqStr("subscriptionId"), reqStr("decisionId"), optStr("reason"))
+	m["platform:admin:subscription:rejected"] = obj(reqStr("subscriptionId"), reqStr("decisionId"), optStr("reason"))
+	m["platform:admin:subscription:promoted"] = obj(
+		reqStr("subscriptionId"), reqStr("sandboxId"), reqStr("code"), reqBool("created"),
+	)
 	m["platform:admin:subscription:synced"] = obj(
 		reqStr("applicationCode"),
 		reqU32("created"), reqU32("updated"), reqU32("deleted"),
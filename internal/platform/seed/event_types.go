@@ -50,6 +50,7 @@ func PlatformEventTypes() []PlatformEventTypeDef {
 		"created", "updated", "activated", "deactivated", "deleted",
 		"roles-assigned", "application-access-assigned",
 		"client-access-granted", "client-access-revoked",
+		"client-access-expiry-changed", "client-access-expired",
 		"logged-in", "password-reset-requested", "password-reset-completed")
 	push("platform:iam:principals:synced", "Principals Synced")
 
@@ -58,7 +59,8 @@ func PlatformEventTypes() []PlatformEventTypeDef {
 		"token-regenerated", "secret-regenerated")
 
 	group("platform:iam:client",
-		"created", "updated", "activated", "suspended", "deleted", "note-added")
+		"created", "updated", "activated", "suspended", "deleted", "note-added",
+		"impersonated")
 
 	group("platform:iam:role", "created", "updated", "deleted")
 	push("platform:iam:roles:synced", "Roles Synced")
@@ -79,9 +81,11 @@ func PlatformEventTypes() []PlatformEventTypeDef {
 	group("platform:admin:edm", "created", "updated", "deleted")
 
 	group("platform:admin:eventtype",
-		"created", "updated", "archived", "deleted",
-		"schema-added", "schema-finalised", "schema-deprecated")
+		"created", "updated", "archived", "deleted", "deprecated", "reinstated",
+		"schema-added", "schema-finalised", "schema-deprecated", "promoted")
+	push("platform:admin:eventtype:schemas-imported", "Event Type Schemas Imported")
 	push("platform:admin:eventtypes:synced", "Event Types Synced")
+	push("platform:admin:eventtypes:imported", "Event Types Imported")
 
 	group("platform:admin:connection", "created", "updated", "deleted")
 
@@ -90,7 +94,10 @@ func PlatformEventTypes() []PlatformEventTypeDef {
 	push("platform:admin:dispatch-pools:synced", "Dispatch Pools Synced")
 
 	group("platform:admin:subscription",
-		"created", "updated", "paused", "resumed", "deleted", "synced")
+		"created", "updated", "paused", "resumed", "deleted", "synced",
+		"approved", "rejected", "promoted")
+
+	group("platform:admin:maintenance", "enabled", "disabled")
 
 	return out
 }
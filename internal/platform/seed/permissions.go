@@ -1,5 +1,11 @@
 package seed
 
+import (
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+)
+
 // Permission identifiers — exact 1:1 port of
 // fc-platform/src/role/entity.rs::permissions::*. Keep the string values
 // byte-identical to what the Rust impl emits: existing rows in
@@ -20,6 +26,9 @@ const (
 	permAdminClientActivate   = "platform:admin:client:activate"
 	permAdminClientSuspend    = "platform:admin:client:suspend"
 	permAdminClientDeactivate = "platform:admin:client:deactivate"
+	// Impersonate mints a short-lived token acting as the client. No
+	// built-in role grants it; assign it to support staff explicitly.
+	permAdminClientImpersonate = "platform:admin:client:impersonate"
 
 	// Anchor domain
 	permAdminAnchorDomainRead   = "platform:admin:anchor-domain:view"
@@ -80,14 +89,26 @@ const (
 	permAdminSubscriptionDelete = "platform:messaging:subscription:delete"
 	permAdminSubscriptionManage = "platform:messaging:subscription:manage"
 	permAdminSubscriptionSync   = "platform:messaging:subscription:sync"
+	// permAdminSubscriptionEgressOverride lets a subscription target
+	// private ranges the egress policy refuses (shared/egress). Go-only.
+	permAdminSubscriptionEgressOverride = "platform:messaging:subscription:egress-override"
+	// permAdminSubscriptionApprove lets a principal approve or reject
+	// subscriptions pending approval. Go-only.
+	permAdminSubscriptionApprove = "platform:messaging:subscription:approve"
 
 	// Event
 	permAdminEventRead    = "platform:messaging:event:view"
 	permAdminEventViewRaw = "platform:messaging:event:view-raw"
+	// permAdminEventViewPII lifts the BFF redaction of event data and
+	// context values (shared/redact). Go-only.
+	permAdminEventViewPII = "platform:messaging:event:view-pii"
 
 	// Dispatch job
 	permAdminDispatchJobRead    = "platform:messaging:dispatch-job:view"
 	permAdminDispatchJobViewRaw = "platform:messaging:dispatch-job:view-raw"
+	// permAdminDispatchJobViewPII lifts the BFF redaction of payloads,
+	// metadata values, response bodies and target URLs. Go-only.
+	permAdminDispatchJobViewPII = "platform:messaging:dispatch-job:view-pii"
 
 	// Scheduled job
 	permAdminScheduledJobRead         = "platform:messaging:scheduled-job:view"
@@ -215,3 +236,89 @@ var permsApplicationService = []string{
 
 // Wildcard.
 const permAdminAll = "platform:*:*:*"
+
+// builtinPermissions lists every built-in permission above except the
+// permAdminAll wildcard, which is a pattern rather than a catalog entry.
+var builtinPermissions = append([]string{
+	permAdminClientRead, permAdminClientCreate, permAdminClientUpdate, permAdminClientDelete,
+	permAdminClientManage, permAdminClientActivate, permAdminClientSuspend,
+	permAdminClientDeactivate, permAdminClientImpersonate,
+	permAdminAnchorDomainRead, permAdminAnchorDomainCreate, permAdminAnchorDomainUpdate,
+	permAdminAnchorDomainDelete, permAdminAnchorDomainManage,
+	permAdminApplicationRead, permAdminApplicationCreate, permAdminApplicationUpdate,
+	permAdminApplicationDelete, permAdminApplicationManage, permAdminApplicationActivate,
+	permAdminApplicationDeactivate, permAdminApplicationEnableClient,
+	permAdminApplicationDisableClient,
+	permAdminEventTypeRead, permAdminEventTypeCreate, permAdminEventTypeUpdate,
+	permAdminEventTypeDelete, permAdminEventTypeManage, permAdminEventTypeArchive,
+	permAdminEventTypeManageSchema, permAdminEventTypeSync,
+	permAdminProcessRead, permAdminProcessCreate, permAdminProcessUpdate, permAdminProcessDelete,
+	permAdminProcessManage, permAdminProcessArchive, permAdminProcessSync,
+	permAdminDispatchPoolRead, permAdminDispatchPoolCreate, permAdminDispatchPoolUpdate,
+	permAdminDispatchPoolDelete, permAdminDispatchPoolManage, permAdminDispatchPoolSync,
+	permAdminConnectionRead, permAdminConnectionCreate, permAdminConnectionUpdate,
+	permAdminConnectionDelete, permAdminConnectionManage,
+	permAdminSubscriptionRead, permAdminSubscriptionCreate, permAdminSubscriptionUpdate,
+	permAdminSubscriptionDelete, permAdminSubscriptionManage, permAdminSubscriptionSync,
+	permAdminSubscriptionEgressOverride, permAdminSubscriptionApprove,
+	permAdminEventRead, permAdminEventViewRaw, permAdminEventViewPII,
+	permAdminDispatchJobRead, permAdminDispatchJobViewRaw, permAdminDispatchJobViewPII,
+	permAdminScheduledJobRead, permAdminScheduledJobCreate, permAdminScheduledJobUpdate,
+	permAdminScheduledJobDelete, permAdminScheduledJobPause, permAdminScheduledJobFire,
+	permAdminScheduledJobManage, permAdminScheduledJobSync, permAdminScheduledJobInstanceRead,
+	permAdminIdentityProviderRead, permAdminIdentityProviderCreate,
+	permAdminIdentityProviderUpdate, permAdminIdentityProviderDelete,
+	permAdminIdentityProviderManage,
+	permAdminEmailDomainMappingRead, permAdminEmailDomainMappingCreate,
+	permAdminEmailDomainMappingUpdate, permAdminEmailDomainMappingDelete,
+	permAdminEmailDomainMappingManage,
+	permAdminServiceAccountRead, permAdminServiceAccountCreate, permAdminServiceAccountUpdate,
+	permAdminServiceAccountDelete, permAdminServiceAccountManage,
+	permAdminCorsOriginRead, permAdminCorsOriginCreate, permAdminCorsOriginDelete,
+	permAdminCorsOriginManage,
+	permAdminLoginAttemptRead, permAdminAuditLogRead, permAdminAuditLogExport,
+	permAdminConfigRead, permAdminConfigUpdate,
+	permAdminBatchEventsWrite, permAdminBatchDispatchJobsWrite, permAdminBatchAuditLogsWrite,
+	permIAMUserRead, permIAMUserCreate, permIAMUserUpdate, permIAMUserDelete, permIAMUserManage,
+	permIAMUserActivate, permIAMUserDeactivate, permIAMUserAssignRoles, permIAMRoleRead,
+	permIAMRoleCreate, permIAMRoleUpdate, permIAMRoleDelete, permIAMRoleManage,
+	permIAMClientAccessGrant, permIAMClientAccessRevoke, permIAMClientAccessRead,
+	permIAMPermissionRead,
+	permAuthClientAuthConfigRead, permAuthClientAuthConfigCreate, permAuthClientAuthConfigUpdate,
+	permAuthClientAuthConfigDelete, permAuthClientAuthConfigManage, permAuthOAuthClientRead,
+	permAuthOAuthClientCreate, permAuthOAuthClientUpdate, permAuthOAuthClientDelete,
+	permAuthOAuthClientManage, permAuthOAuthClientRegenerateSecret,
+	permDeveloperApplicationOpenAPIView, permDeveloperApplicationOpenAPISync,
+	permDeveloperApplicationOpenAPIManage, permDeveloperAPICredentialManage,
+}, permsApplicationService...)
+
+// permissionCategories labels the built-ins' context segment.
+var permissionCategories = map[string]string{
+	"admin":               "Administration",
+	"iam":                 "Identity & access",
+	"messaging":           "Messaging",
+	"auth":                "Authentication",
+	"developer":           "Developer portal",
+	"application-service": "Application service",
+}
+
+// PlatformPermissions is the built-in permission catalog seeded into
+// iam_permissions (source CODE) at startup, which platform role
+// composition is validated against. Descriptions are derived from the
+// code: "platform:iam:user:assign-roles" → "User: assign roles".
+func PlatformPermissions() []role.Permission {
+	out := make([]role.Permission, 0, len(builtinPermissions))
+	for _, code := range builtinPermissions {
+		parts := strings.Split(code, ":")
+		aggregate := strings.ReplaceAll(parts[2], "-", " ")
+		desc := strings.ToUpper(aggregate[:1]) + aggregate[1:] + ": " + strings.ReplaceAll(parts[3], "-", " ")
+		out = append(out, role.Permission{
+			Permission:  code,
+			Name:        code,
+			Description: ptr(desc),
+			Category:    ptr(permissionCategories[parts[1]]),
+			Source:      role.SourceCode,
+		})
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package seed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
+)
+
+func TestPlatformPermissionsCatalogShape(t *testing.T) {
+	perms := PlatformPermissions()
+	seen := map[string]bool{}
+	for _, p := range perms {
+		if seen[p.Permission] {
+			t.Fatalf("duplicate permission: %s", p.Permission)
+		}
+		seen[p.Permission] = true
+		parts := strings.Split(p.Permission, ":")
+		if len(parts) != 4 || parts[0] != "platform" || strings.Contains(p.Permission, "*") {
+			t.Fatalf("%s: want a concrete platform:context:aggregate:action code", p.Permission)
+		}
+		if p.Category == nil || *p.Category == "" {
+			t.Errorf("%s: context %q has no category label", p.Permission, parts[1])
+		}
+		if p.Description == nil || *p.Description == "" {
+			t.Errorf("%s: empty description", p.Permission)
+		}
+		if p.Source != role.SourceCode {
+			t.Errorf("%s: source %s, want CODE", p.Permission, p.Source)
+		}
+	}
+	if got := *PlatformPermissions()[0].Description; got != "Client: view" {
+		t.Errorf("derived description = %q, want %q", got, "Client: view")
+	}
+}
+
+// The built-in roles are composed from the seeded catalog, so they must
+// pass the same validation an admin-composed role does.
+func TestPlatformRolesUseCatalogedPermissions(t *testing.T) {
+	var catalog []string
+	for _, p := range PlatformPermissions() {
+		catalog = append(catalog, p.Permission)
+	}
+	for _, r := range PlatformRoles() {
+		if unknown := role.UnknownPermissions(catalog, r.Permissions); len(unknown) > 0 {
+			t.Errorf("role %s grants permissions missing from the catalog: %v", r.Name, unknown)
+		}
+	}
+}
@@ -127,8 +127,8 @@ func PlatformRoles() []role.Role {
 				permAdminDispatchPoolUpdate, permAdminDispatchPoolDelete, permAdminDispatchPoolSync,
 				permAdminConnectionRead, permAdminConnectionCreate,
 				permAdminConnectionUpdate, permAdminConnectionDelete,
-				permAdminEventRead, permAdminEventViewRaw,
-				permAdminDispatchJobRead, permAdminDispatchJobViewRaw,
+				permAdminEventRead, permAdminEventViewRaw, permAdminEventViewPII,
+				permAdminDispatchJobRead, permAdminDispatchJobViewRaw, permAdminDispatchJobViewPII,
 				permAdminScheduledJobRead, permAdminScheduledJobCreate,
 				permAdminScheduledJobUpdate, permAdminScheduledJobDelete,
 				permAdminScheduledJobPause, permAdminScheduledJobFire, permAdminScheduledJobSync,
@@ -177,37 +177,3 @@ func PlatformRoles() []role.Role {
 }
 
 func ptr[T any](v T) *T { return &v }
-
-// Reference permission constants that don't land in any built-in role
-// today (CLIENT_MANAGE, EVENT_TYPE_MANAGE, etc.) so the unused-symbol
-// linter leaves them — they're still part of the catalog, referenced by
-// SDK consumers and future role definitions. The blank-identifier var is
-// itself exempt from the linter, so listing them here keeps the catalog
-// honest without forcing fake usage elsewhere.
-var _ = []string{
-	permAdminClientDelete, permAdminClientManage,
-	permAdminAnchorDomainManage,
-	permAdminApplicationManage, permAdminApplicationActivate, permAdminApplicationDeactivate,
-	permAdminEventTypeManage,
-	permAdminProcessManage,
-	permAdminDispatchPoolManage,
-	permAdminConnectionManage,
-	permAdminSubscriptionManage,
-	permAdminScheduledJobManage,
-	permAdminIdentityProviderRead, permAdminIdentityProviderCreate,
-	permAdminIdentityProviderUpdate, permAdminIdentityProviderDelete,
-	permAdminIdentityProviderManage,
-	permAdminEmailDomainMappingRead, permAdminEmailDomainMappingCreate,
-	permAdminEmailDomainMappingUpdate, permAdminEmailDomainMappingDelete,
-	permAdminEmailDomainMappingManage,
-	permAdminServiceAccountRead, permAdminServiceAccountCreate,
-	permAdminServiceAccountUpdate, permAdminServiceAccountDelete,
-	permAdminServiceAccountManage,
-	permAdminCorsOriginRead, permAdminCorsOriginCreate,
-	permAdminCorsOriginDelete, permAdminCorsOriginManage,
-	permAdminConfigRead, permAdminConfigUpdate,
-	permAdminBatchEventsWrite, permAdminBatchDispatchJobsWrite, permAdminBatchAuditLogsWrite,
-	permIAMUserManage, permIAMRoleManage, permIAMPermissionRead,
-	permAuthClientAuthConfigManage, permAuthOAuthClientManage,
-	permDeveloperApplicationOpenAPISync,
-}
@@ -17,6 +17,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
@@ -34,6 +35,9 @@ func (s *Seeder) Run(ctx context.Context) error {
 	if err := s.seedPlatformApplication(ctx); err != nil {
 		return fmt.Errorf("seed platform application: %w", err)
 	}
+	if err := s.seedPermissions(ctx); err != nil {
+		return fmt.Errorf("seed permissions: %w", err)
+	}
 	if err := s.seedRoles(ctx); err != nil {
 		return fmt.Errorf("seed roles: %w", err)
 	}
@@ -81,6 +85,21 @@ func (s *Seeder) seedPlatformApplication(ctx context.Context) error {
 	return nil
 }
 
+// seedPermissions converges the CODE rows of iam_permissions on
+// PlatformPermissions: upserts every built-in and drops the ones no
+// longer defined. Rows from applications and the dashboard are left
+// alone.
+func (s *Seeder) seedPermissions(ctx context.Context) error {
+	res, err := role.NewPermissionRepo(s.pool).Sync(ctx, role.SourceCode, nil, PlatformPermissions(), true)
+	if err != nil {
+		return err
+	}
+	if res.Created > 0 || res.Deleted > 0 {
+		slog.Info("seeded built-in permissions", "created", res.Created, "removed", res.Deleted)
+	}
+	return nil
+}
+
 // seedRoles upserts the 12 built-in roles. Mirrors Rust's
 // seed_builtin_roles: skip-if-name-exists (preserves any local edits to
 // permissions, matching Rust's behaviour exactly).
@@ -15,6 +15,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -119,7 +120,7 @@ func (s *State) create(ctx context.Context, in *apicommon.In[CreateServiceAccoun
 	if err := auth.CanWriteServiceAccounts(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	res, err := usecaseop.RunTx(ctx, s.UoW,
 		operations.CreateServiceAccountWithCredentials(s.Repo, s.Principals, s.OAuthClients),
 		in.Body.toCommand(), ec)
@@ -143,7 +144,7 @@ func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty,
 	if err := auth.CanWriteServiceAccounts(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateServiceAccount(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
 		return nil, err
 	}
@@ -154,7 +155,7 @@ func (s *State) deactivate(ctx context.Context, in *apicommon.IDInput) (*apicomm
 	if err := auth.CanWriteServiceAccounts(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeactivateServiceAccount(s.Repo), operations.DeactivateCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -165,7 +166,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteServiceAccounts(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteServiceAccount(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -216,7 +217,7 @@ func (s *State) assignRoles(ctx context.Context, in *assignRolesInput) (*apicomm
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	// The operation resolves the linked SERVICE principal, computes the
 	// added/removed diff, and writes iam_principal_roles in one transaction;
 	// the 404 for an unknown id is raised there too.
@@ -240,7 +241,7 @@ func (s *State) regenerateAuthToken(ctx context.Context, in *apicommon.IDInput)
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.RegenerateAuthToken(s.Repo),
 		operations.RegenerateAuthTokenCommand{ServiceAccountID: in.ID}, ec); err != nil {
 		return nil, err
@@ -256,7 +257,7 @@ func (s *State) regenerateSigningSecret(ctx context.Context, in *apicommon.IDInp
 	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.RegenerateSigningSecret(s.Repo),
 		operations.RegenerateSigningSecretCommand{ServiceAccountID: in.ID}, ec); err != nil {
 		return nil, err
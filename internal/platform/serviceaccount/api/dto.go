@@ -66,6 +66,7 @@ type CreateServiceAccountRequest struct {
 	ClientIDs          []string               `json:"clientIds,omitempty"`
 	ApplicationID      *string                `json:"applicationId,omitempty"`
 	WebhookCredentials *WebhookCredentialsDTO `json:"webhookCredentials,omitempty"`
+	Environment        string                 `json:"environment,omitempty" enum:"SANDBOX,PRODUCTION" doc:"Environment the account's API key acts in; default PRODUCTION. Fixed at creation."`
 }
 
 func (r CreateServiceAccountRequest) toCommand() operations.CreateCommand {
@@ -82,6 +83,7 @@ func (r CreateServiceAccountRequest) toCommand() operations.CreateCommand {
 		ClientIDs:          r.ClientIDs,
 		ApplicationID:      r.ApplicationID,
 		WebhookCredentials: creds,
+		Environment:        r.Environment,
 	}
 }
 
@@ -149,6 +151,7 @@ type ServiceAccountResponse struct {
 	ClientIDs     []string `json:"clientIds"`
 	Scope         *string  `json:"scope,omitempty"`
 	ApplicationID *string  `json:"applicationId,omitempty"`
+	Environment   string   `json:"environment"`
 	AuthType      string   `json:"authType"`
 	Roles         []string `json:"roles"`
 	// PrincipalID is the id of the linked SERVICE principal that actually owns
@@ -185,6 +188,7 @@ func fromEntity(sa *serviceaccount.ServiceAccount) ServiceAccountResponse {
 		ClientIDs:     clientIDs,
 		Scope:         sa.Scope,
 		ApplicationID: sa.ApplicationID,
+		Environment:   string(sa.Environment),
 		AuthType:      string(sa.WebhookCredentials.AuthType),
 		Roles:         roles,
 		LastUsedAt:    lastUsed,
@@ -9,6 +9,7 @@ package serviceaccount
 import (
 	"time"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
@@ -67,14 +68,17 @@ type RoleAssignment struct {
 
 // ServiceAccount is the aggregate root.
 type ServiceAccount struct {
-	ID                    string             `json:"id"`
-	Code                  string             `json:"code"`
-	Name                  string             `json:"name"`
-	Description           *string            `json:"description,omitempty"`
-	Active                bool               `json:"active"`
-	ClientIDs             []string           `json:"clientIds"`
-	Scope                 *string            `json:"scope,omitempty"`
-	ApplicationID         *string            `json:"applicationId,omitempty"`
+	ID            string   `json:"id"`
+	Code          string   `json:"code"`
+	Name          string   `json:"name"`
+	Description   *string  `json:"description,omitempty"`
+	Active        bool     `json:"active"`
+	ClientIDs     []string `json:"clientIds"`
+	Scope         *string  `json:"scope,omitempty"`
+	ApplicationID *string  `json:"applicationId,omitempty"`
+	// Environment is the SANDBOX or PRODUCTION space the account's API
+	// key acts in; its tokens are pinned to it. Fixed at creation.
+	Environment           common.Environment `json:"environment"`
 	WebhookCredentials    WebhookCredentials `json:"webhookCredentials"`
 	ServiceAccountTableID *string            `json:"-"`
 	Roles                 []RoleAssignment   `json:"roles"`
@@ -95,6 +99,7 @@ func New(code, name string) *ServiceAccount {
 		Name:               name,
 		Active:             true,
 		ClientIDs:          []string{},
+		Environment:        common.EnvironmentProduction,
 		WebhookCredentials: NoCredentials(),
 		Roles:              []RoleAssignment{},
 		CreatedAt:          now,
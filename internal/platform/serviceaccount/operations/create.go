@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/validate"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -19,6 +20,8 @@ type CreateCommand struct {
 	ClientIDs          []string                           `json:"clientIds,omitempty"`
 	ApplicationID      *string                            `json:"applicationId,omitempty"`
 	WebhookCredentials *serviceaccount.WebhookCredentials `json:"webhookCredentials,omitempty"`
+	// Environment is SANDBOX or PRODUCTION; empty means PRODUCTION.
+	Environment string `json:"environment,omitempty"`
 }
 
 // CreateServiceAccount validates cmd, enforces code uniqueness, persists
@@ -38,7 +41,7 @@ func CreateServiceAccount(repo *serviceaccount.Repository) usecaseop.Operation[C
 			if strings.TrimSpace(cmd.Name) == "" {
 				return usecase.Validation("NAME_REQUIRED", "name is required")
 			}
-			return nil
+			return validateEnvironment(cmd.Environment)
 		},
 		// The coarse "may write service accounts" permission is enforced at the
 		// controller; this admin-managed create has no per-client resource
@@ -60,6 +63,7 @@ func CreateServiceAccount(repo *serviceaccount.Repository) usecaseop.Operation[C
 			sa.Description = cmd.Description
 			sa.Scope = cmd.Scope
 			sa.ApplicationID = cmd.ApplicationID
+			sa.Environment = common.ParseEnvironment(cmd.Environment)
 			if cmd.ClientIDs != nil {
 				sa.ClientIDs = cmd.ClientIDs
 			}
@@ -77,3 +81,12 @@ func CreateServiceAccount(repo *serviceaccount.Repository) usecaseop.Operation[C
 		},
 	}
 }
+
+// validateEnvironment rejects anything but SANDBOX or PRODUCTION. Empty
+// (not supplied) is fine.
+func validateEnvironment(env string) error {
+	if env != "" && !common.Environment(env).Valid() {
+		return usecase.Validation("INVALID_ENVIRONMENT", "environment must be SANDBOX or PRODUCTION")
+	}
+	return nil
+}
@@ -4,16 +4,16 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
 	authops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/validate"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -67,7 +67,7 @@ func CreateServiceAccountWithCredentials(
 			if strings.TrimSpace(cmd.Name) == "" {
 				return usecase.Validation("NAME_REQUIRED", "name is required")
 			}
-			return nil
+			return validateEnvironment(cmd.Environment)
 		},
 		Authorize: usecaseop.Public[CreateCommand],
 		Execute: func(ctx context.Context, s *usecasepgx.TxScopedUnitOfWork, cmd CreateCommand, ec usecase.ExecutionContext) (CreateWithCredentialsResult, error) {
@@ -86,6 +86,7 @@ func CreateServiceAccountWithCredentials(
 			sa.Description = cmd.Description
 			sa.Scope = cmd.Scope
 			sa.ApplicationID = cmd.ApplicationID
+			sa.Environment = common.ParseEnvironment(cmd.Environment)
 			if cmd.ClientIDs != nil {
 				sa.ClientIDs = cmd.ClientIDs
 			}
@@ -159,22 +160,15 @@ func CreateServiceAccountWithCredentials(
 }
 
 // generateOAuthClientSecret returns a fresh URL-safe secret + its
-// encrypted reference (stored in client_secret_ref; verified at
-// /oauth/token by decrypt-and-compare — Rust parity).
+// argon2id hash (stored in client_secret_ref; verified at /oauth/token
+// with passwordhash.Verify).
 func generateOAuthClientSecret() (plaintext, ref string, err error) {
 	b := make([]byte, 32)
 	if _, err = rand.Read(b); err != nil {
 		return "", "", err
 	}
 	plaintext = base64.RawURLEncoding.EncodeToString(b)
-	enc, err := encryption.FromEnv()
-	if err != nil {
-		return "", "", err
-	}
-	if enc == nil {
-		return "", "", errors.New("FLOWCATALYST_APP_KEY not configured; cannot encrypt client secret")
-	}
-	ref, err = enc.Encrypt(plaintext)
+	ref, err = passwordhash.Hash(plaintext)
 	if err != nil {
 		return "", "", err
 	}
@@ -4,35 +4,23 @@ package operations_test
 
 import (
 	"context"
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	platformauth "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/passwordhash"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount/operations"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
-// TestMain seeds FLOWCATALYST_APP_KEY before the embedded-PG boot:
-// create-with-credentials encrypts the OAuth client secret via
-// encryption.FromEnv, which reads the env at call time. os.Setenv (not
-// t.Setenv) because every test here runs t.Parallel().
-func TestMain(m *testing.M) {
-	key, err := encryption.GenerateKey()
-	if err != nil {
-		panic(err)
-	}
-	_ = os.Setenv("FLOWCATALYST_APP_KEY", key)
-	testpg.RunMain(m)
-}
+func TestMain(m *testing.M) { testpg.RunMain(m) }
 
 // runOp drives op through the full use-case envelope (Validate → Authorize →
 // Execute → atomic commit) as an anchor principal — these operations are all
@@ -238,7 +226,7 @@ func TestCreateServiceAccountWithCredentials_HappyPath(t *testing.T) {
 	assert.Equal(t, []string{appID}, p.AccessibleApplicationIDs, "single app-access binding written")
 
 	// OAuth client row: CONFIDENTIAL, owned by the principal, with the
-	// client_credentials/refresh_token grants and an encrypted secret ref.
+	// client_credentials/refresh_token grants and a hashed secret ref.
 	oc, err := oauthRepo.FindByID(ctx, res.OAuthClientRowID)
 	require.NoError(t, err)
 	require.NotNil(t, oc)
@@ -251,18 +239,11 @@ func TestCreateServiceAccountWithCredentials_HappyPath(t *testing.T) {
 	assert.ElementsMatch(t, []string{"client_credentials", "refresh_token"}, oc.GrantTypes)
 	assert.Equal(t, []string{"openid"}, oc.Scopes)
 
-	// The stored ref decrypts back to the returned plaintext (the
-	// /oauth/token decrypt-and-compare contract). NOTE: this path stores the
-	// raw envelope WITHOUT the "encrypted:" prefix that auth/operations'
-	// generateSecret adds — pinned so a future unification flips it knowingly.
+	// The stored ref is an argon2id hash of the returned plaintext (what
+	// /oauth/token verifies against).
 	require.NotNil(t, oc.SecretRef)
-	assert.NotRegexp(t, `^encrypted:`, *oc.SecretRef)
-	enc, err := encryption.FromEnv()
-	require.NoError(t, err)
-	require.NotNil(t, enc)
-	plain, err := enc.Decrypt(*oc.SecretRef)
-	require.NoError(t, err)
-	assert.Equal(t, res.OAuthClientSecret, plain)
+	assert.True(t, passwordhash.IsHash(*oc.SecretRef))
+	assert.NoError(t, passwordhash.Verify(res.OAuthClientSecret, *oc.SecretRef))
 }
 
 func TestCreateServiceAccountWithCredentials_Errors(t *testing.T) {
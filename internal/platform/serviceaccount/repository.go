@@ -6,6 +6,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -47,6 +48,16 @@ func (r *Repository) FindByCode(ctx context.Context, code string) (*ServiceAccou
 	return rowToServiceAccount(*row), nil
 }
 
+// FindEnvironment returns the environment of a service account, for
+// pinning its tokens. PRODUCTION when the account doesn't exist.
+func (r *Repository) FindEnvironment(ctx context.Context, id string) (string, error) {
+	sa, err := r.FindByID(ctx, id)
+	if sa == nil || err != nil {
+		return string(common.EnvironmentProduction), err
+	}
+	return string(sa.Environment), nil
+}
+
 // FindAll returns every service account.
 func (r *Repository) FindAll(ctx context.Context) ([]ServiceAccount, error) {
 	rows, err := r.q.ServiceAccountFindAll(ctx)
@@ -85,6 +96,7 @@ func (r *Repository) Persist(ctx context.Context, sa *ServiceAccount, tx *usecas
 		LastUsedAt:                 sa.LastUsedAt,
 		CreatedAt:                  sa.CreatedAt,
 		UpdatedAt:                  time.Now().UTC(),
+		Environment:                string(sa.Environment),
 	})
 }
 
@@ -106,6 +118,7 @@ func rowToServiceAccount(row dbq.IamServiceAccount) *ServiceAccount {
 		CreatedAt:     row.CreatedAt,
 		UpdatedAt:     row.UpdatedAt,
 		ClientIDs:     append([]string{}, row.ClientIds...),
+		Environment:   common.ParseEnvironment(row.Environment),
 		Roles:         []RoleAssignment{},
 		WebhookCredentials: WebhookCredentials{
 			AuthType:         WebhookAuthType(stringDerefOrEmpty(row.WhAuthType)),
@@ -8,6 +8,7 @@
 //   - CanWrite<Resource>       for any of create/update/delete
 //   - RequireAnchor(ctx)       for anchor-only endpoints
 //   - IsAdmin(ctx)             anchor OR the super-admin wildcard
+//   - CanImpersonateClient(ctx) anchor AND the impersonate permission
 //
 // The check functions return a usecase.Error (Kind=Authorization) on
 // failure so handlers can httperror.Write(err) without branching.
@@ -25,6 +26,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 )
 
@@ -60,6 +62,12 @@ const (
 	permSubscriptionDelete = "platform:messaging:subscription:delete"
 	permSubscriptionSync   = "platform:messaging:subscription:sync"
 	permSubscriptionManage = "platform:messaging:subscription:manage"
+	// permSubscriptionEgressOverride lets a subscription target private
+	// ranges the egress policy otherwise refuses (shared/egress). Go-only.
+	permSubscriptionEgressOverride = "platform:messaging:subscription:egress-override"
+	// permSubscriptionApprove lets a principal approve or reject
+	// subscriptions pending approval, and create ones that skip it. Go-only.
+	permSubscriptionApprove = "platform:messaging:subscription:approve"
 	// DispatchPool (messaging)
 	permDispatchPoolView   = "platform:messaging:dispatch-pool:view"
 	permDispatchPoolCreate = "platform:messaging:dispatch-pool:create"
@@ -84,6 +92,8 @@ const (
 	permRoleUpdate = "platform:iam:role:update"
 	permRoleDelete = "platform:iam:role:delete"
 	permRoleManage = "platform:iam:role:manage"
+	// Permission catalog (iam)
+	permPermissionView = "platform:iam:permission:view"
 	// Application-service permissions. These are held by SDK service
 	// accounts so an application can self-register its own resources via
 	// the /api/applications/{appCode}/{resource}/sync endpoints. They sit
@@ -99,6 +109,8 @@ const (
 	permAppSvcSubscriptionUpdate = "platform:application-service:subscription:update"
 	permAppSvcSubscriptionDelete = "platform:application-service:subscription:delete"
 	permAppSvcScheduledJobSync   = "platform:application-service:scheduled-job:sync"
+	permAppSvcPermissionView     = "platform:application-service:permission:view"
+	permAppSvcPermissionSync     = "platform:application-service:permission:sync"
 	// Developer (application OpenAPI documents)
 	permAppOpenApiSync   = "platform:developer:application-openapi:sync"
 	permAppOpenApiManage = "platform:developer:application-openapi:manage"
@@ -124,6 +136,9 @@ const (
 	permScheduledJobFire   = "platform:messaging:scheduled-job:fire"
 	permScheduledJobSync   = "platform:messaging:scheduled-job:sync"
 	permScheduledJobManage = "platform:messaging:scheduled-job:manage"
+	// Client impersonation (support staff acting as a client). Never
+	// implied by anchor scope alone — see CanImpersonateClient.
+	permClientImpersonate = "platform:admin:client:impersonate"
 	// Super-admin wildcard.
 	permSuperAdmin = "platform:*:*:*"
 )
@@ -151,6 +166,16 @@ type AuthContext struct {
 	AllApplications bool
 	// Permissions is the flattened set of permission codes from all roles.
 	Permissions []string
+	// Impersonating is the client id an impersonation token was minted
+	// for; empty otherwise. PrincipalID stays the support principal who
+	// minted it, while Scope and Clients are pinned to that one client.
+	Impersonating string
+	// Environment pins the principal to one environment: a service
+	// account's tokens carry the SANDBOX or PRODUCTION environment its API
+	// key belongs to, and can't see or write the other. Empty (users, and
+	// tokens minted before environments existed) is unpinned: the request
+	// picks the environment, defaulting to PRODUCTION.
+	Environment common.Environment
 }
 
 // The boolean methods below are nil-receiver-safe and fail closed: an
@@ -164,8 +189,16 @@ func (a *AuthContext) IsAnchor() bool { return a != nil && a.Scope == ScopeAncho
 
 // IsSuperAdmin reports whether the principal holds the super-admin wildcard
 // permission (platform:*:*:*). Mirrors Rust has_permission(ADMIN_ALL) — used
-// by handlers (e.g. SDK openapi sync) that gate on the admin-all grant.
-func (a *AuthContext) IsSuperAdmin() bool { return a.HasPermission(permSuperAdmin) }
+// by handlers (e.g. SDK openapi sync) that gate on the admin-all grant. An
+// impersonation token is never super-admin: the wildcard still satisfies
+// permission checks, but not the platform-wide reach the grant implies.
+func (a *AuthContext) IsSuperAdmin() bool {
+	return !a.IsImpersonating() && a.HasPermission(permSuperAdmin)
+}
+
+// IsImpersonating reports whether the request carries an impersonation
+// token (see Impersonating).
+func (a *AuthContext) IsImpersonating() bool { return a != nil && a.Impersonating != "" }
 
 // CanAccessClient reports whether the principal has access to a specific tenant.
 func (a *AuthContext) CanAccessClient(clientID string) bool {
@@ -183,6 +216,26 @@ func (a *AuthContext) CanAccessClient(clientID string) bool {
 	return false
 }
 
+// CanAccessEnvironment reports whether the principal may act in env. An
+// unpinned principal may act in either.
+func (a *AuthContext) CanAccessEnvironment(env common.Environment) bool {
+	if a == nil {
+		return false
+	}
+	return a.Environment == "" || a.Environment == env
+}
+
+// EnvironmentFor resolves the environment a request acts in: the pinned
+// environment when there is one, else requested, else PRODUCTION. Pair it
+// with CheckRequestedEnvironment to reject a pinned principal asking for the
+// other environment rather than silently overriding it.
+func (a *AuthContext) EnvironmentFor(requested string) common.Environment {
+	if a != nil && a.Environment != "" {
+		return a.Environment
+	}
+	return common.ParseEnvironment(requested)
+}
+
 // IsApplicationScoped reports whether the principal is restricted to an explicit
 // set of applications (i.e. it does NOT hold all-applications access). An
 // application-scoped principal — e.g. an application service account — may only
@@ -278,20 +331,6 @@ func FromContext(ctx context.Context) *AuthContext {
 	return v
 }
 
-// NewExecutionContext builds a usecase.ExecutionContext seeded from the
-// principal on ctx. When the request is unauthenticated the principal id is
-// empty — the use case's Authorize phase rejects that before anything is
-// committed, so handlers can build the context without a nil check. This is
-// the single seam through which write handlers derive the execution context
-// for usecaseop.Run.
-func NewExecutionContext(ctx context.Context) usecase.ExecutionContext {
-	principalID := ""
-	if a := FromContext(ctx); a != nil {
-		principalID = a.PrincipalID
-	}
-	return usecase.NewExecutionContext(principalID)
-}
-
 // ── Check helpers ──────────────────────────────────────────────────────────
 
 // RequireAnchor errors if the principal is not anchor-scoped.
@@ -334,12 +373,32 @@ func IsAdmin(a *AuthContext) error {
 	if a == nil {
 		return usecase.Authorization("UNAUTHENTICATED", "authentication required")
 	}
-	if a.IsAnchor() || a.HasPermission(permSuperAdmin) {
+	if a.IsAnchor() || a.IsSuperAdmin() {
 		return nil
 	}
 	return usecase.Authorization("ADMIN_REQUIRED", "admin permission required")
 }
 
+// CanImpersonateClient authorizes minting an impersonation token. Unlike the
+// Can* helpers, anchor scope doesn't bypass the permission: the caller must
+// be anchor-scoped AND hold platform:admin:client:impersonate. An
+// impersonation token can't mint another.
+func CanImpersonateClient(a *AuthContext) error {
+	if a == nil {
+		return usecase.Authorization("UNAUTHENTICATED", "authentication required")
+	}
+	if a.IsImpersonating() {
+		return usecase.Authorization("IMPERSONATION_NESTED", "an impersonation token cannot impersonate")
+	}
+	if !a.IsAnchor() {
+		return usecase.Authorization("ANCHOR_REQUIRED", "anchor scope required")
+	}
+	if !a.HasPermission(permClientImpersonate) {
+		return usecase.Authorization("PERMISSION_REQUIRED", "permission required: "+permClientImpersonate)
+	}
+	return nil
+}
+
 // CanAccessScope reports whether the caller may access a resource owned by the
 // given client (nil clientID = platform-level → anchor/super-admin only). The
 // boolean form of CheckScopeAccess, for filtering list results.
@@ -389,6 +448,29 @@ func CheckScopeAccess(a *AuthContext, clientID *string) error {
 	return usecase.Authorization("SCOPE_FORBIDDEN", "anchor scope required for this resource")
 }
 
+// CheckEnvironmentAccess enforces environment isolation on top of
+// CheckScopeAccess: a principal pinned to one environment can't read or
+// write a resource in the other. env is the resource's environment.
+func CheckEnvironmentAccess(a *AuthContext, env common.Environment) error {
+	if a == nil {
+		return usecase.Authorization("UNAUTHENTICATED", "authentication required")
+	}
+	if a.CanAccessEnvironment(env) {
+		return nil
+	}
+	return usecase.Authorization("ENVIRONMENT_FORBIDDEN",
+		"credentials for "+string(a.Environment)+" can't access "+string(env)+" resources")
+}
+
+// CheckRequestedEnvironment is CheckEnvironmentAccess on an environment
+// named in a request body; an empty request defers to EnvironmentFor.
+func CheckRequestedEnvironment(a *AuthContext, requested string) error {
+	if requested == "" {
+		return nil
+	}
+	return CheckEnvironmentAccess(a, common.ParseEnvironment(requested))
+}
+
 // requirePermission is the generic helper.
 func requirePermission(a *AuthContext, perm string) error {
 	if a == nil {
@@ -480,6 +562,23 @@ func CanWriteSubscriptions(a *AuthContext) error {
 	return requireAny(a, permSubscriptionCreate, permSubscriptionUpdate, permSubscriptionDelete)
 }
 
+// CanOverrideEgress guards setting a subscription's allowPrivateTarget.
+// No built-in role grants it: anchor principals, super-admin and explicit
+// grants only.
+func CanOverrideEgress(a *AuthContext) error {
+	return requirePermission(a, permSubscriptionEgressOverride)
+}
+
+// CanApproveSubscriptions guards approving or rejecting a subscription
+// pending approval. No built-in role grants it.
+func CanApproveSubscriptions(a *AuthContext) error {
+	return requirePermission(a, permSubscriptionApprove)
+}
+
+// SubscriptionApprovePermission is the approve permission's code, for
+// finding the principals to notify of a pending subscription.
+const SubscriptionApprovePermission = permSubscriptionApprove
+
 // ── Dispatch pool permissions ────────────────────────────────────────────
 func CanReadDispatchPools(a *AuthContext) error { return requirePermission(a, permDispatchPoolView) }
 
@@ -536,6 +635,20 @@ func CanSyncRoles(a *AuthContext) error {
 		permAppSvcRoleCreate, permAppSvcRoleUpdate, permAppSvcRoleDelete)
 }
 
+// CanSyncPermissions guards POST
+// /api/admin/platform/applications/{id}/permissions: the iam role
+// manage/create/update tier, or the application-service permission:sync
+// an SDK service account holds. Per-application scope is enforced by the
+// handler.
+func CanSyncPermissions(a *AuthContext) error {
+	return requireAny(a, permRoleManage, permRoleCreate, permRoleUpdate, permAppSvcPermissionSync)
+}
+
+// CanReadPermissions guards reading an application's permission catalog.
+func CanReadPermissions(a *AuthContext) error {
+	return requireAny(a, permPermissionView, permRoleView, permAppSvcPermissionView)
+}
+
 // CanSyncSubscriptions guards POST /api/applications/{appCode}/subscriptions/sync.
 // Mirrors Rust can_sync_subscriptions: admin sync/manage plus the
 // application-service create/update/delete permissions an SDK service account
@@ -75,3 +75,38 @@ func TestNonAnchorWithoutPermissionDenied(t *testing.T) {
 	assert.Error(t, RequireAnchor(a), "non-anchor must fail RequireAnchor")
 	assert.Error(t, IsAdmin(a))
 }
+
+func TestCanImpersonateClientNeedsAnchorAndPermission(t *testing.T) {
+	assert.Error(t, CanImpersonateClient(nil))
+	assert.Error(t, CanImpersonateClient(&AuthContext{Scope: ScopeAnchor}),
+		"anchor scope alone must not bypass the impersonate permission")
+	assert.Error(t, CanImpersonateClient(&AuthContext{
+		Scope:       ScopeClient,
+		Permissions: []string{permClientImpersonate},
+	}), "the permission without anchor scope is not enough")
+	assert.NoError(t, CanImpersonateClient(&AuthContext{
+		Scope:       ScopeAnchor,
+		Permissions: []string{permClientImpersonate},
+	}))
+	assert.NoError(t, CanImpersonateClient(&AuthContext{
+		Scope:       ScopeAnchor,
+		Permissions: []string{permSuperAdmin},
+	}), "super-admin wildcard covers it")
+}
+
+func TestImpersonationIsConfinedToItsClient(t *testing.T) {
+	a := &AuthContext{
+		Scope:         ScopeClient,
+		Clients:       []string{"clt_1"},
+		Permissions:   []string{permSuperAdmin},
+		Impersonating: "clt_1",
+	}
+	assert.True(t, a.IsImpersonating())
+	assert.False(t, a.IsSuperAdmin(), "the wildcard must not confer platform-wide reach")
+	assert.Error(t, IsAdmin(a))
+	assert.NoError(t, CanReadEventTypes(a), "permission checks still pass")
+	assert.True(t, a.CanAccessClient("clt_1"))
+	assert.False(t, a.CanAccessClient("clt_2"))
+	assert.False(t, CanAccessScope(a, nil), "platform-scoped resources are out of reach")
+	assert.Error(t, CanImpersonateClient(a), "an impersonation token cannot mint another")
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+func TestEnvironmentAccess(t *testing.T) {
+	unpinned := &AuthContext{Scope: ScopeClient, Clients: []string{"clt_A"}}
+	sandbox := &AuthContext{Scope: ScopeClient, Clients: []string{"clt_A"}, Environment: common.EnvironmentSandbox}
+
+	cases := []struct {
+		name    string
+		ac      *AuthContext
+		env     common.Environment
+		wantErr bool
+	}{
+		{"unpinned reaches production", unpinned, common.EnvironmentProduction, false},
+		{"unpinned reaches sandbox", unpinned, common.EnvironmentSandbox, false},
+		{"sandbox key reaches sandbox", sandbox, common.EnvironmentSandbox, false},
+		{"sandbox key denied production", sandbox, common.EnvironmentProduction, true},
+		{"nil context denied", nil, common.EnvironmentProduction, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if gotErr := CheckEnvironmentAccess(tc.ac, tc.env) != nil; gotErr != tc.wantErr {
+				t.Fatalf("CheckEnvironmentAccess err=%v, wantErr=%v", gotErr, tc.wantErr)
+			}
+		})
+	}
+
+	if got := unpinned.EnvironmentFor(""); got != common.EnvironmentProduction {
+		t.Fatalf("unpinned default = %q, want PRODUCTION", got)
+	}
+	if got := unpinned.EnvironmentFor("SANDBOX"); got != common.EnvironmentSandbox {
+		t.Fatalf("unpinned requested sandbox = %q", got)
+	}
+	if got := sandbox.EnvironmentFor(""); got != common.EnvironmentSandbox {
+		t.Fatalf("sandbox key default = %q, want SANDBOX", got)
+	}
+	if CheckRequestedEnvironment(sandbox, "") != nil {
+		t.Fatal("empty request must defer to the pinned environment")
+	}
+	if CheckRequestedEnvironment(sandbox, "PRODUCTION") == nil {
+		t.Fatal("sandbox key asking for production must be refused")
+	}
+}
@@ -0,0 +1,195 @@
+package bff
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/analytics"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// analyticsViewPerm gates the analytics screens: they summarise dispatch
+// jobs, so whoever can list the jobs can see their aggregates.
+const analyticsViewPerm = "platform:messaging:dispatch-job:view"
+
+// AnalyticsState holds the deps for the /bff/analytics/* endpoints.
+type AnalyticsState struct {
+	Repo *analytics.Repository
+}
+
+// RegisterAnalytics mounts the delivery-analytics endpoints.
+//
+// Routes:
+//
+//	GET /bff/analytics/timeseries  — per-bucket events, jobs, success rate, latency + window totals
+//	GET /bff/analytics/breakdown   — the same job stats grouped by eventType, subscription or client
+//
+// Both take ?range=1h|6h|24h|7d|30d (default 24h) and the optional
+// clientId, eventType and subscriptionId filters. Non-anchor callers only
+// see their own tenants, like the dispatch-job list.
+func RegisterAnalytics(r chi.Router, s *AnalyticsState) {
+	r.Route("/bff/analytics", func(r chi.Router) {
+		r.Get("/timeseries", s.timeseries)
+		r.Get("/breakdown", s.breakdown)
+	})
+}
+
+// ── Wire DTOs ────────────────────────────────────────────────────────────
+
+type bffJobStats struct {
+	JobsDispatched int64    `json:"jobsDispatched"`
+	JobsSucceeded  int64    `json:"jobsSucceeded"`
+	JobsFailed     int64    `json:"jobsFailed"`
+	SuccessRate    *float64 `json:"successRate"`
+	P50LatencyMS   *float64 `json:"p50LatencyMs"`
+	P95LatencyMS   *float64 `json:"p95LatencyMs"`
+}
+
+func toBffJobStats(s analytics.JobStats) bffJobStats {
+	return bffJobStats{
+		JobsDispatched: s.Dispatched,
+		JobsSucceeded:  s.Succeeded,
+		JobsFailed:     s.Failed,
+		SuccessRate:    s.SuccessRate(),
+		P50LatencyMS:   s.P50Ms,
+		P95LatencyMS:   s.P95Ms,
+	}
+}
+
+type bffAnalyticsBucket struct {
+	Start          time.Time `json:"start"`
+	EventsIngested int64     `json:"eventsIngested"`
+	bffJobStats
+}
+
+type bffAnalyticsTotals struct {
+	EventsIngested int64 `json:"eventsIngested"`
+	bffJobStats
+}
+
+type bffAnalyticsTimeseries struct {
+	Range         string               `json:"range"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	BucketSeconds int64                `json:"bucketSeconds"`
+	Totals        bffAnalyticsTotals   `json:"totals"`
+	Buckets       []bffAnalyticsBucket `json:"buckets"`
+}
+
+type bffAnalyticsGroup struct {
+	Key *string `json:"key"`
+	bffJobStats
+}
+
+type bffAnalyticsBreakdown struct {
+	Range string              `json:"range"`
+	By    string              `json:"by"`
+	From  time.Time           `json:"from"`
+	To    time.Time           `json:"to"`
+	Items []bffAnalyticsGroup `json:"items"`
+}
+
+// ── Handlers ─────────────────────────────────────────────────────────────
+
+// GET /bff/analytics/timeseries?range=24h&clientId=&eventType=&subscriptionId=
+func (s *AnalyticsState) timeseries(w http.ResponseWriter, r *http.Request) {
+	rng, f, ok := analyticsQuery(w, r)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	buckets, err := s.Repo.Series(r.Context(), rng, f, now)
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "analytics series failed", err))
+		return
+	}
+	events, totals, err := s.Repo.Totals(r.Context(), rng, f, now)
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "analytics totals failed", err))
+		return
+	}
+	from, to := rng.Window(now)
+	resp := bffAnalyticsTimeseries{
+		Range:         rng.Name,
+		From:          from,
+		To:            to,
+		BucketSeconds: int64(rng.Bucket / time.Second),
+		Totals:        bffAnalyticsTotals{EventsIngested: events, bffJobStats: toBffJobStats(totals)},
+		Buckets:       make([]bffAnalyticsBucket, 0, len(buckets)),
+	}
+	for _, b := range buckets {
+		resp.Buckets = append(resp.Buckets, bffAnalyticsBucket{
+			Start:          b.Start,
+			EventsIngested: b.EventsIngested,
+			bffJobStats:    toBffJobStats(b.JobStats),
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GET /bff/analytics/breakdown?by=eventType&range=24h&limit=50
+func (s *AnalyticsState) breakdown(w http.ResponseWriter, r *http.Request) {
+	rng, f, ok := analyticsQuery(w, r)
+	if !ok {
+		return
+	}
+	by := analytics.Dimension(r.URL.Query().Get("by"))
+	if by == "" {
+		by = analytics.ByEventType
+	}
+	if !by.Valid() {
+		httperror.Write(w, httperror.BadRequest("VALIDATION", "by must be eventType, subscription or client"))
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	now := time.Now().UTC()
+	groups, err := s.Repo.Breakdown(r.Context(), rng, by, f, limit, now)
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "analytics breakdown failed", err))
+		return
+	}
+	from, to := rng.Window(now)
+	resp := bffAnalyticsBreakdown{Range: rng.Name, By: string(by), From: from, To: to,
+		Items: make([]bffAnalyticsGroup, 0, len(groups))}
+	for _, g := range groups {
+		resp.Items = append(resp.Items, bffAnalyticsGroup{Key: g.Key, bffJobStats: toBffJobStats(g.JobStats)})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// analyticsQuery authorizes the caller and reads the shared range and
+// filter params, writing the error response itself when it returns false.
+func analyticsQuery(w http.ResponseWriter, r *http.Request) (analytics.Range, analytics.Filter, bool) {
+	ac := auth.FromContext(r.Context())
+	if err := auth.CanWritePermission(ac, analyticsViewPerm); err != nil {
+		httperror.Write(w, err)
+		return analytics.Range{}, analytics.Filter{}, false
+	}
+	q := r.URL.Query()
+	name := q.Get("range")
+	if name == "" {
+		name = analytics.DefaultRange
+	}
+	rng, ok := analytics.Ranges[name]
+	if !ok {
+		httperror.Write(w, httperror.BadRequest("VALIDATION", "range must be 1h, 6h, 24h, 7d or 30d"))
+		return analytics.Range{}, analytics.Filter{}, false
+	}
+	opt := func(k string) *string {
+		if v := q.Get(k); v != "" {
+			return &v
+		}
+		return nil
+	}
+	f := analytics.Filter{
+		ClientID:       opt("clientId"),
+		EventType:      opt("eventType"),
+		SubscriptionID: opt("subscriptionId"),
+	}
+	return rng, f, true
+}
@@ -0,0 +1,166 @@
+package bff
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/apiactivity"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// APIActivityState holds the deps for the /bff/api-activity/* endpoints.
+// Read-only — rows are written by the apiactivity.Recorder middleware.
+type APIActivityState struct {
+	Repo *apiactivity.Repository
+}
+
+// RegisterAPIActivity mounts the admin API usage explorer.
+//
+// Routes:
+//
+//	GET /bff/api-activity/principals                  — per-principal rates + error ratios
+//	GET /bff/api-activity/principals/{id}/endpoints   — one principal's per-route breakdown
+//	GET /bff/api-activity/principals/{id}/calls       — one principal's recent sampled calls
+//
+// Every count is a sample-weighted estimate (see apiactivity). Admin only:
+// the log reveals every integration's call pattern.
+func RegisterAPIActivity(r chi.Router, s *APIActivityState) {
+	r.Route("/bff/api-activity", func(r chi.Router) {
+		r.Get("/principals", s.listPrincipals)
+		r.Get("/principals/{id}/endpoints", s.listEndpoints)
+		r.Get("/principals/{id}/calls", s.listCalls)
+	})
+}
+
+// ── Wire DTOs ────────────────────────────────────────────────────────────
+
+type bffAPIActivityPrincipal struct {
+	PrincipalID   string    `json:"principalId"`
+	PrincipalName *string   `json:"principalName,omitempty"`
+	PrincipalType *string   `json:"principalType,omitempty"`
+	Calls         int64     `json:"calls"`
+	Errors        int64     `json:"errors"`
+	ErrorRatio    float64   `json:"errorRatio"`
+	CallsPerHour  float64   `json:"callsPerHour"`
+	LastSeenAt    time.Time `json:"lastSeenAt"`
+}
+
+type bffAPIActivityEndpoint struct {
+	Method        string    `json:"method"`
+	Route         string    `json:"route"`
+	Calls         int64     `json:"calls"`
+	Errors        int64     `json:"errors"`
+	ErrorRatio    float64   `json:"errorRatio"`
+	CallsPerHour  float64   `json:"callsPerHour"`
+	AvgDurationMS float64   `json:"avgDurationMs"`
+	LastSeenAt    time.Time `json:"lastSeenAt"`
+}
+
+type bffAPIActivityWindowed[T any] struct {
+	WindowHours int `json:"windowHours"`
+	Items       []T `json:"items"`
+}
+
+// ── Handlers ─────────────────────────────────────────────────────────────
+
+// GET /bff/api-activity/principals?windowHours=24
+//
+// Principals with activity only before the window are still listed (calls=0,
+// lastSeenAt in the past) — that is the stale-integration signal.
+func (s *APIActivityState) listPrincipals(w http.ResponseWriter, r *http.Request) {
+	if err := auth.IsAdmin(auth.FromContext(r.Context())); err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	hours := activityWindowHours(r)
+	rows, err := s.Repo.SummarizeByPrincipal(r.Context(), time.Now().UTC().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "summarize api activity failed", err))
+		return
+	}
+	items := make([]bffAPIActivityPrincipal, 0, len(rows))
+	for _, p := range rows {
+		items = append(items, bffAPIActivityPrincipal{
+			PrincipalID:   p.PrincipalID,
+			PrincipalName: p.PrincipalName,
+			PrincipalType: p.PrincipalType,
+			Calls:         p.Calls,
+			Errors:        p.Errors,
+			ErrorRatio:    ratio(p.Errors, p.Calls),
+			CallsPerHour:  float64(p.Calls) / float64(hours),
+			LastSeenAt:    p.LastSeenAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, bffAPIActivityWindowed[bffAPIActivityPrincipal]{WindowHours: hours, Items: items})
+}
+
+// GET /bff/api-activity/principals/{id}/endpoints?windowHours=24
+func (s *APIActivityState) listEndpoints(w http.ResponseWriter, r *http.Request) {
+	if err := auth.IsAdmin(auth.FromContext(r.Context())); err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	hours := activityWindowHours(r)
+	rows, err := s.Repo.SummarizeEndpoints(r.Context(), chi.URLParam(r, "id"),
+		time.Now().UTC().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "summarize api activity endpoints failed", err))
+		return
+	}
+	items := make([]bffAPIActivityEndpoint, 0, len(rows))
+	for _, e := range rows {
+		items = append(items, bffAPIActivityEndpoint{
+			Method:        e.Method,
+			Route:         e.Route,
+			Calls:         e.Calls,
+			Errors:        e.Errors,
+			ErrorRatio:    ratio(e.Errors, e.Calls),
+			CallsPerHour:  float64(e.Calls) / float64(hours),
+			AvgDurationMS: e.AvgDurationMS,
+			LastSeenAt:    e.LastSeenAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, bffAPIActivityWindowed[bffAPIActivityEndpoint]{WindowHours: hours, Items: items})
+}
+
+// GET /bff/api-activity/principals/{id}/calls?errorsOnly=true&limit=100
+func (s *APIActivityState) listCalls(w http.ResponseWriter, r *http.Request) {
+	if err := auth.IsAdmin(auth.FromContext(r.Context())); err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	q := r.URL.Query()
+	limit := 100
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 && n <= 500 {
+		limit = n
+	}
+	calls, err := s.Repo.RecentCalls(r.Context(), chi.URLParam(r, "id"), q.Get("errorsOnly") == "true", limit)
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "recent api calls failed", err))
+		return
+	}
+	if calls == nil {
+		calls = []apiactivity.Call{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": calls})
+}
+
+// activityWindowHours reads ?windowHours= (1..720, default 24).
+func activityWindowHours(r *http.Request) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get("windowHours")); err == nil && n > 0 && n <= 720 {
+		return n
+	}
+	return 24
+}
+
+func ratio(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
@@ -14,6 +14,7 @@ import (
 	openapiops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/openapispecs/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -303,7 +304,7 @@ func (s *DeveloperState) syncPlatformOpenAPI(w http.ResponseWriter, r *http.Requ
 		ApplicationCode: app.Code,
 		Spec:            spec,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	ev, err := usecaseop.Run(r.Context(), s.UoW, openapiops.SyncOpenApiSpec(s.Specs), cmd, ec)
 	if err != nil {
 		httperror.Write(w, err)
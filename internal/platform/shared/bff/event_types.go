@@ -13,6 +13,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/seed"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -186,7 +187,7 @@ func (s *EventTypesState) create(w http.ResponseWriter, r *http.Request) {
 		ClientID:    body.ClientID,
 		Schema:      body.Schema,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	event, err := usecaseop.Run(r.Context(), s.UoW, operations.CreateEventType(s.Repo), cmd, ec)
 	if err != nil {
 		httperror.Write(w, err)
@@ -222,7 +223,7 @@ func (s *EventTypesState) update(w http.ResponseWriter, r *http.Request) {
 		cmd.Name = *body.Name
 	}
 	cmd.Description = body.Description
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.UpdateEventType(s.Repo), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -242,7 +243,7 @@ func (s *EventTypesState) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	cmd := operations.DeleteCommand{ID: chi.URLParam(r, "id")}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.DeleteEventType(s.Repo), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -274,7 +275,7 @@ func (s *EventTypesState) addSchema(w http.ResponseWriter, r *http.Request) {
 		Version:     body.Version,
 		Schema:      body.Schema,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.AddSchema(s.Repo), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -396,7 +397,7 @@ func (s *EventTypesState) archive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	cmd := operations.ArchiveCommand{ID: chi.URLParam(r, "id")}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.ArchiveEventType(s.Repo), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -424,7 +425,7 @@ func (s *EventTypesState) finaliseSchema(w http.ResponseWriter, r *http.Request)
 		EventTypeID: chi.URLParam(r, "id"),
 		Version:     chi.URLParam(r, "version"),
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.FinaliseEventTypeSchema(s.Repo), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -451,7 +452,7 @@ func (s *EventTypesState) deprecateSchema(w http.ResponseWriter, r *http.Request
 		EventTypeID: chi.URLParam(r, "id"),
 		Version:     chi.URLParam(r, "version"),
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.DeprecateEventTypeSchema(s.Repo), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -528,7 +529,7 @@ func (s *EventTypesState) syncPlatform(w http.ResponseWriter, r *http.Request) {
 		EventTypes:      inputs,
 		RemoveUnlisted:  true,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	ev, err := usecaseop.Run(r.Context(), s.UoW, operations.SyncEventTypes(s.Repo), cmd, ec)
 	if err != nil {
 		httperror.Write(w, err)
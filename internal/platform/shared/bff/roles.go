@@ -17,6 +17,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/seed"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -460,7 +461,7 @@ func (s *RolesState) create(w http.ResponseWriter, r *http.Request) {
 		Permissions:     body.Permissions,
 		ClientManaged:   body.ClientManaged,
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	event, err := usecaseop.Run(r.Context(), s.UoW, operations.CreateRole(s.Roles), cmd, ec)
 	if err != nil {
 		httperror.Write(w, err)
@@ -495,7 +496,7 @@ func (s *RolesState) update(w http.ResponseWriter, r *http.Request) {
 	if body.Permissions != nil {
 		cmd.Permissions = *body.Permissions
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.UpdateRole(s.Roles), cmd, ec); err != nil {
 		httperror.Write(w, err)
 		return
@@ -515,7 +516,7 @@ func (s *RolesState) syncPlatform(w http.ResponseWriter, r *http.Request) {
 		httperror.Write(w, err)
 		return
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	ev, err := usecaseop.Run(r.Context(), s.UoW, operations.SyncPlatformRoles(s.Roles, seed.PlatformRoles()), operations.SyncPlatformRolesCommand{}, ec)
 	if err != nil {
 		httperror.Write(w, err)
@@ -541,7 +542,7 @@ func (s *RolesState) delete(w http.ResponseWriter, r *http.Request) {
 		httperror.Write(w, err)
 		return
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(r.Context())
 	cmd := operations.DeleteCommand{ID: role.ID}
 	if _, err := usecaseop.Run(r.Context(), s.UoW, operations.DeleteRole(s.Roles), cmd, ec); err != nil {
 		httperror.Write(w, err)
@@ -608,10 +609,10 @@ func toBffRole(r role.Role) bffRoleResponse {
 // ── Permissions registry ─────────────────────────────────────────────────
 
 // builtinPermissions ports `get_builtin_permissions` from
-// bff_roles_api.rs. The catalog is static — the dashboard renders it
-// directly. Must stay in lockstep with `internal/platform/seed/permissions.go`
-// (the actual permission strings the platform recognises) until the
-// two are merged.
+// bff_roles_api.rs: the hand-worded descriptions for the common
+// platform permissions. The full built-in set is seeded into
+// iam_permissions (seed.PlatformPermissions), which permissionCatalog
+// merges in behind these, so a permission missing here still lists.
 func builtinPermissions() []bffPermissionResponse {
 	out := []bffPermissionResponse{}
 	// IAM
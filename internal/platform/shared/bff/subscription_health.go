@@ -0,0 +1,225 @@
+package bff
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/analytics"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/client"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// SubscriptionHealthState holds the deps for the /bff/subscriptions/*
+// health endpoints.
+type SubscriptionHealthState struct {
+	Repo    *analytics.Repository
+	Clients *client.Repository
+}
+
+// RegisterSubscriptionHealth mounts the subscription health page's
+// endpoints.
+//
+// Routes:
+//
+//	GET /bff/subscriptions/health                 — every visible subscription, worst first
+//	GET /bff/subscriptions/health/filter-options  — clients, statuses, healths, ranges
+//	GET /bff/subscriptions/{id}/health            — one subscription
+//
+// The list takes ?range=1h|6h|24h|7d|30d (default 24h) and the optional
+// clientId, status (ACTIVE/PAUSED) and health filters. Non-anchor
+// callers only see their own tenants' and platform-scoped
+// subscriptions, like the subscription list.
+func RegisterSubscriptionHealth(r chi.Router, s *SubscriptionHealthState) {
+	r.Route("/bff/subscriptions", func(r chi.Router) {
+		r.Get("/health", s.list)
+		r.Get("/health/filter-options", s.filterOptions)
+		r.Get("/{id}/health", s.get)
+	})
+}
+
+// ── Wire DTOs ────────────────────────────────────────────────────────────
+
+type bffSubscriptionHealth struct {
+	SubscriptionID   string  `json:"subscriptionId"`
+	Code             string  `json:"code"`
+	Name             string  `json:"name"`
+	ClientID         *string `json:"clientId"`
+	Status           string  `json:"status"`
+	DispatchPoolCode *string `json:"dispatchPoolCode"`
+	Health           string  `json:"health"`
+	bffJobStats
+	LastSuccessAt       *time.Time `json:"lastSuccessAt"`
+	LastAttemptAt       *time.Time `json:"lastAttemptAt"`
+	ConsecutiveFailures int64      `json:"consecutiveFailures"`
+	Backlog             int64      `json:"backlog"`
+	OldestBacklogAt     *time.Time `json:"oldestBacklogAt"`
+}
+
+func toBffSubscriptionHealth(h analytics.SubscriptionHealth) bffSubscriptionHealth {
+	return bffSubscriptionHealth{
+		SubscriptionID:      h.SubscriptionID,
+		Code:                h.Code,
+		Name:                h.Name,
+		ClientID:            h.ClientID,
+		Status:              h.Status,
+		DispatchPoolCode:    h.DispatchPoolCode,
+		Health:              string(h.Health),
+		bffJobStats:         toBffJobStats(h.JobStats),
+		LastSuccessAt:       h.LastSuccessAt,
+		LastAttemptAt:       h.LastAttemptAt,
+		ConsecutiveFailures: h.ConsecutiveFailures,
+		Backlog:             h.Backlog,
+		OldestBacklogAt:     h.OldestBacklogAt,
+	}
+}
+
+type bffSubscriptionHealthList struct {
+	Range  string                  `json:"range"`
+	From   time.Time               `json:"from"`
+	To     time.Time               `json:"to"`
+	Counts map[string]int          `json:"counts"` // per health, before the health filter
+	Items  []bffSubscriptionHealth `json:"items"`
+}
+
+type bffSubscriptionHealthFilterOptions struct {
+	Clients  []bffFilterOption `json:"clients"`
+	Statuses []bffFilterOption `json:"statuses"`
+	Healths  []bffFilterOption `json:"healths"`
+	Ranges   []string          `json:"ranges"`
+}
+
+// ── Handlers ─────────────────────────────────────────────────────────────
+
+// GET /bff/subscriptions/health?range=24h&clientId=&status=&health=
+func (s *SubscriptionHealthState) list(w http.ResponseWriter, r *http.Request) {
+	rng, f, ok := subscriptionHealthQuery(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	if v := q.Get("clientId"); v != "" {
+		f.ClientID = &v
+	}
+	if v := q.Get("status"); v != "" {
+		f.Status = &v
+	}
+	health := analytics.Health(q.Get("health"))
+	if health != "" && !health.Valid() {
+		httperror.Write(w, httperror.BadRequest("VALIDATION", "health must be FAILING, DEGRADED, HEALTHY, IDLE or PAUSED"))
+		return
+	}
+	now := time.Now().UTC()
+	rows, err := s.Repo.SubscriptionHealth(r.Context(), rng, f, now)
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "subscription health failed", err))
+		return
+	}
+	from, to := rng.Window(now)
+	resp := bffSubscriptionHealthList{Range: rng.Name, From: from, To: to,
+		Counts: make(map[string]int, len(analytics.Healths)),
+		Items:  make([]bffSubscriptionHealth, 0, len(rows))}
+	for _, h := range analytics.Healths {
+		resp.Counts[string(h)] = 0
+	}
+	for _, h := range rows {
+		resp.Counts[string(h.Health)]++
+		if health != "" && h.Health != health {
+			continue
+		}
+		resp.Items = append(resp.Items, toBffSubscriptionHealth(h))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GET /bff/subscriptions/{id}/health?range=24h
+func (s *SubscriptionHealthState) get(w http.ResponseWriter, r *http.Request) {
+	rng, f, ok := subscriptionHealthQuery(w, r)
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	f.SubscriptionID = &id
+	rows, err := s.Repo.SubscriptionHealth(r.Context(), rng, f, time.Now().UTC())
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "subscription health failed", err))
+		return
+	}
+	if len(rows) == 0 {
+		httperror.Write(w, httperror.NotFound("Subscription", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, toBffSubscriptionHealth(rows[0]))
+}
+
+// GET /bff/subscriptions/health/filter-options
+func (s *SubscriptionHealthState) filterOptions(w http.ResponseWriter, r *http.Request) {
+	ac := auth.FromContext(r.Context())
+	if err := auth.CanReadSubscriptions(ac); err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	rows, err := s.Clients.FindAll(r.Context())
+	if err != nil {
+		httperror.Write(w, usecase.Internal("REPO", "list clients failed", err))
+		return
+	}
+	clients := []bffFilterOption{}
+	for _, c := range rows {
+		if c.Status != client.StatusActive {
+			continue
+		}
+		if !ac.IsAnchor() && !ac.CanAccessClient(c.ID) {
+			continue
+		}
+		clients = append(clients, bffFilterOption{Value: c.ID, Label: c.Name})
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Label < clients[j].Label })
+
+	healths := make([]bffFilterOption, 0, len(analytics.Healths))
+	for _, h := range analytics.Healths {
+		healths = append(healths, bffFilterOption{Value: string(h), Label: healthLabels[h]})
+	}
+	writeJSON(w, http.StatusOK, bffSubscriptionHealthFilterOptions{
+		Clients: clients,
+		Statuses: []bffFilterOption{
+			{Value: "ACTIVE", Label: "Active"},
+			{Value: "PAUSED", Label: "Paused"},
+		},
+		Healths: healths,
+		Ranges:  []string{"1h", "6h", "24h", "7d", "30d"},
+	})
+}
+
+var healthLabels = map[analytics.Health]string{
+	analytics.HealthFailing:  "Failing",
+	analytics.HealthDegraded: "Degraded",
+	analytics.HealthHealthy:  "Healthy",
+	analytics.HealthIdle:     "Idle",
+	analytics.HealthPaused:   "Paused",
+}
+
+// subscriptionHealthQuery authorizes the caller and reads the range
+// (the repository scopes non-anchor callers to their clients). Writes the
+// error response itself when it returns false.
+func subscriptionHealthQuery(w http.ResponseWriter, r *http.Request) (analytics.Range, analytics.SubscriptionFilter, bool) {
+	ac := auth.FromContext(r.Context())
+	if err := auth.CanReadSubscriptions(ac); err != nil {
+		httperror.Write(w, err)
+		return analytics.Range{}, analytics.SubscriptionFilter{}, false
+	}
+	name := r.URL.Query().Get("range")
+	if name == "" {
+		name = analytics.DefaultRange
+	}
+	rng, ok := analytics.Ranges[name]
+	if !ok {
+		httperror.Write(w, httperror.BadRequest("VALIDATION", "range must be 1h, 6h, 24h, 7d or 30d"))
+		return analytics.Range{}, analytics.SubscriptionFilter{}, false
+	}
+	return rng, analytics.SubscriptionFilter{}, true
+}
@@ -0,0 +1,396 @@
+// Package egress decides which hosts the platform may call on a tenant's
+// behalf — webhook targets and their OAuth2 token endpoints — so that a
+// subscription can't point deliveries at internal services.
+//
+// By default private, loopback, link-local and other special-purpose
+// ranges are refused, and cloud metadata endpoints always are. A
+// deployment can widen that (FC_EGRESS_ALLOW_PRIVATE, FC_EGRESS_ALLOWLIST),
+// narrow it (FC_EGRESS_DENYLIST, FC_EGRESS_ALLOWLIST_ONLY), and a
+// subscription created by a holder of the egress-override permission may
+// reach private ranges regardless.
+//
+// URLs are checked when a subscription is saved and again when delivering:
+// the transport resolves the host itself, checks every address and dials
+// only the addresses it checked, so a DNS answer that changes between
+// validation and delivery (rebinding) gains nothing.
+//
+// The policy also decides how deliveries leave: through a deployment proxy
+// (FC_EGRESS_PROXY_URL) or a named one a subscription picks
+// (FC_EGRESS_PROXIES), and from a fixed set of local addresses
+// (FC_EGRESS_SOURCE_ADDRS), so customers can allowlist the addresses
+// published at GET /api/meta/egress-ips (FC_EGRESS_PUBLIC_IPS).
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// ErrBlocked is wrapped by every refusal.
+var ErrBlocked = errors.New("egress: destination not allowed")
+
+// Config is a deployment's egress policy.
+type Config struct {
+	// AllowPrivate permits private and other special-purpose ranges for
+	// every subscription. Metadata endpoints stay blocked.
+	AllowPrivate bool
+	// Allowlist entries are always permitted, even in private ranges.
+	// Entries are CIDRs, IPs, host names or *.domain wildcards.
+	Allowlist []string
+	// AllowlistOnly refuses every destination the allowlist doesn't match.
+	AllowlistOnly bool
+	// Denylist entries are always refused, override or not.
+	Denylist []string
+
+	// ProxyURL is the proxy every delivery goes through unless NoProxy
+	// matches its target. Empty: HTTPS_PROXY / HTTP_PROXY, as before.
+	ProxyURL string
+	// NoProxy targets go direct. Same syntax as Allowlist.
+	NoProxy []string
+	// Proxies are named proxies a subscription may select instead of
+	// ProxyURL, as name=url entries.
+	Proxies []string
+	// SourceAddrs are the local addresses deliveries are sent from: IPs,
+	// or interface names standing for their addresses. Empty: the OS
+	// picks.
+	SourceAddrs []string
+	// PublicIPs are the addresses targets see deliveries come from (IPs
+	// or CIDRs) — the NAT gateway or proxy addresses when those differ
+	// from SourceAddrs. Published for customers' firewall allowlists.
+	PublicIPs []string
+}
+
+// ConfigFromEnv reads FC_EGRESS_ALLOW_PRIVATE, FC_EGRESS_ALLOWLIST,
+// FC_EGRESS_ALLOWLIST_ONLY, FC_EGRESS_DENYLIST, FC_EGRESS_PROXY_URL,
+// FC_EGRESS_NO_PROXY, FC_EGRESS_PROXIES, FC_EGRESS_SOURCE_ADDRS and
+// FC_EGRESS_PUBLIC_IPS.
+func ConfigFromEnv() Config {
+	return Config{
+		AllowPrivate:  envutil.Bool("FC_EGRESS_ALLOW_PRIVATE", false),
+		Allowlist:     splitList(envutil.Or("FC_EGRESS_ALLOWLIST", "")),
+		AllowlistOnly: envutil.Bool("FC_EGRESS_ALLOWLIST_ONLY", false),
+		Denylist:      splitList(envutil.Or("FC_EGRESS_DENYLIST", "")),
+		ProxyURL:      strings.TrimSpace(envutil.Or("FC_EGRESS_PROXY_URL", "")),
+		NoProxy:       splitList(envutil.Or("FC_EGRESS_NO_PROXY", "")),
+		Proxies:       splitList(envutil.Or("FC_EGRESS_PROXIES", "")),
+		SourceAddrs:   splitList(envutil.Or("FC_EGRESS_SOURCE_ADDRS", "")),
+		PublicIPs:     splitList(envutil.Or("FC_EGRESS_PUBLIC_IPS", "")),
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Policy is a parsed Config. Safe for concurrent use.
+type Policy struct {
+	allowPrivate  bool
+	allowlistOnly bool
+	allow, deny   rules
+
+	// lookup resolves a host name; tests substitute a fixed answer.
+	lookup func(ctx context.Context, host string) ([]netip.Addr, error)
+	// proxies are the proxy addresses the transport sent requests through;
+	// dials to them aren't target dials and skip the checks.
+	proxies sync.Map
+
+	outbound
+	next atomic.Uint32 // source address rotation
+}
+
+// New parses cfg.
+func New(cfg Config) (*Policy, error) {
+	allow, err := parseRules(cfg.Allowlist)
+	if err != nil {
+		return nil, fmt.Errorf("egress allowlist: %w", err)
+	}
+	deny, err := parseRules(cfg.Denylist)
+	if err != nil {
+		return nil, fmt.Errorf("egress denylist: %w", err)
+	}
+	if cfg.AllowlistOnly && len(allow) == 0 {
+		return nil, errors.New("egress: allowlist-only mode needs a non-empty allowlist")
+	}
+	out, err := parseOutbound(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Policy{
+		allowPrivate:  cfg.AllowPrivate,
+		allowlistOnly: cfg.AllowlistOnly,
+		allow:         allow,
+		deny:          deny,
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+		outbound: out,
+	}, nil
+}
+
+// FromEnv returns the process-wide policy built from ConfigFromEnv. The
+// environment is read once; server startup calls it first so a bad list
+// fails the boot rather than the first subscription save.
+var FromEnv = sync.OnceValues(func() (*Policy, error) {
+	return New(ConfigFromEnv())
+})
+
+// overrideKey marks a context whose destination may be in a private range.
+type overrideKey struct{}
+
+// WithOverride marks ctx as carrying a request for a subscription allowed
+// to reach private ranges. The transport routes such requests through a
+// separate connection pool.
+func WithOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, overrideKey{}, true)
+}
+
+// Overridden reports whether ctx was marked by WithOverride.
+func Overridden(ctx context.Context) bool {
+	v, _ := ctx.Value(overrideKey{}).(bool)
+	return v
+}
+
+// proxyKey carries the named proxy a request should leave through.
+type proxyKey struct{}
+
+// WithProxy routes requests made with ctx through the named proxy from
+// FC_EGRESS_PROXIES instead of the deployment default.
+func WithProxy(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, proxyKey{}, name)
+}
+
+// ProxyName returns the proxy set by WithProxy, or "".
+func ProxyName(ctx context.Context) string {
+	v, _ := ctx.Value(proxyKey{}).(string)
+	return v
+}
+
+// Settings are one subscription's departures from the deployment policy.
+type Settings struct {
+	// AllowPrivate is the private-target override.
+	AllowPrivate bool
+	// Proxy names the FC_EGRESS_PROXIES entry to deliver through; "" for
+	// the deployment default.
+	Proxy string
+}
+
+// Apply marks ctx with s, for the transport and target auth to read.
+func (s Settings) Apply(ctx context.Context) context.Context {
+	if s.AllowPrivate {
+		ctx = WithOverride(ctx)
+	}
+	if s.Proxy != "" {
+		ctx = WithProxy(ctx, s.Proxy)
+	}
+	return ctx
+}
+
+// SettingsFrom reads back what Apply marked.
+func SettingsFrom(ctx context.Context) Settings {
+	return Settings{AllowPrivate: Overridden(ctx), Proxy: ProxyName(ctx)}
+}
+
+// CheckURL validates a target URL at save time: http(s) only, and every
+// address its host resolves to must be permitted. override is the
+// subscription's private-target override. A host that doesn't resolve yet
+// is accepted; delivery checks again.
+func (p *Policy) CheckURL(ctx context.Context, raw string, override bool) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q (want http or https)", ErrBlocked, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("invalid URL: no host")
+	}
+	if err := p.checkHost(host); err != nil {
+		return err
+	}
+	if p.allow.matchHost(host) {
+		return nil
+	}
+	addrs, err := p.resolve(ctx, host)
+	if err != nil {
+		if p.allowlistOnly {
+			return fmt.Errorf("%w: %s is not allowlisted", ErrBlocked, host)
+		}
+		return nil
+	}
+	for _, a := range addrs {
+		if err := p.checkAddr(host, a, override); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkHost applies the name rules, before any lookup.
+func (p *Policy) checkHost(host string) error {
+	if p.deny.matchHost(host) {
+		return fmt.Errorf("%w: %s is denylisted", ErrBlocked, host)
+	}
+	return nil
+}
+
+// checkAddr decides one resolved address of host.
+func (p *Policy) checkAddr(host string, a netip.Addr, override bool) error {
+	a = a.Unmap()
+	switch {
+	case p.deny.matchHost(host) || p.deny.matchAddr(a):
+		return fmt.Errorf("%w: %s (%s) is denylisted", ErrBlocked, host, a)
+	case p.allow.matchHost(host) || p.allow.matchAddr(a):
+		return nil
+	case p.allowlistOnly:
+		return fmt.Errorf("%w: %s (%s) is not allowlisted", ErrBlocked, host, a)
+	case isMetadata(a):
+		return fmt.Errorf("%w: %s (%s) is a cloud metadata endpoint", ErrBlocked, host, a)
+	case !p.allowPrivate && !override && isSpecial(a):
+		return fmt.Errorf("%w: %s (%s) is in a private or reserved range", ErrBlocked, host, a)
+	}
+	return nil
+}
+
+// resolve returns host's addresses; an IP literal is its own answer.
+func (p *Policy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if a, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return []netip.Addr{a}, nil
+	}
+	addrs, err := p.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("egress: %s has no addresses", host)
+	}
+	return addrs, nil
+}
+
+// metadataAddrs are instance-metadata services: AWS/GCP/Azure/OpenStack,
+// the AWS ECS task endpoint and its IPv6 variant, and Alibaba Cloud.
+var metadataAddrs = []netip.Addr{
+	netip.MustParseAddr("169.254.169.254"),
+	netip.MustParseAddr("169.254.170.2"),
+	netip.MustParseAddr("fd00:ec2::254"),
+	netip.MustParseAddr("100.100.100.200"),
+}
+
+func isMetadata(a netip.Addr) bool {
+	for _, m := range metadataAddrs {
+		if a == m {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedPrefixes are special-purpose ranges netip has no predicate for.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, broadcast
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+}
+
+// nat64 embeds an IPv4 address in its last 32 bits.
+var nat64 = netip.MustParsePrefix("64:ff9b::/96")
+
+// isSpecial reports loopback, private, link-local, multicast, unspecified
+// and reserved addresses — anything that isn't ordinary public unicast.
+func isSpecial(a netip.Addr) bool {
+	if nat64.Contains(a) {
+		b := a.As16()
+		return isSpecial(netip.AddrFrom4([4]byte(b[12:])))
+	}
+	if a.IsLoopback() || a.IsPrivate() || a.IsLinkLocalUnicast() || a.IsMulticast() ||
+		a.IsUnspecified() || a.IsInterfaceLocalMulticast() || a.IsLinkLocalMulticast() {
+		return true
+	}
+	for _, p := range reservedPrefixes {
+		if p.Contains(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// rules is a parsed allow- or denylist.
+type rules []rule
+
+type rule struct {
+	prefix netip.Prefix // valid for CIDR / IP entries
+	host   string       // exact host name, lower case
+	suffix string       // ".example.com" for *.example.com
+}
+
+func parseRules(entries []string) (rules, error) {
+	var out rules
+	for _, e := range entries {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch {
+		case e == "":
+			continue
+		case strings.Contains(e, "/"):
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", e, err)
+			}
+			out = append(out, rule{prefix: p.Masked()})
+		case strings.HasPrefix(e, "*."):
+			out = append(out, rule{suffix: e[1:]})
+		default:
+			if a, err := netip.ParseAddr(strings.Trim(e, "[]")); err == nil {
+				out = append(out, rule{prefix: netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen())})
+				continue
+			}
+			if strings.ContainsAny(e, " *:") {
+				return nil, fmt.Errorf("%q: want a CIDR, IP, host name or *.domain", e)
+			}
+			out = append(out, rule{host: strings.TrimSuffix(e, ".")})
+		}
+	}
+	return out, nil
+}
+
+func (rs rules) matchHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, r := range rs {
+		if (r.host != "" && r.host == host) || (r.suffix != "" && strings.HasSuffix(host, r.suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rs rules) matchAddr(a netip.Addr) bool {
+	for _, r := range rs {
+		if r.prefix.IsValid() && r.prefix.Contains(a) {
+			return true
+		}
+	}
+	return false
+}
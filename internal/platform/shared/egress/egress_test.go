@@ -0,0 +1,306 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustPolicy(t *testing.T, cfg Config) *Policy {
+	t.Helper()
+	p, err := New(cfg)
+	require.NoError(t, err)
+	return p
+}
+
+// withDNS answers lookups from a fixed table.
+func withDNS(p *Policy, table map[string][]string) *Policy {
+	p.lookup = func(_ context.Context, host string) ([]netip.Addr, error) {
+		ips, ok := table[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		var out []netip.Addr
+		for _, ip := range ips {
+			out = append(out, netip.MustParseAddr(ip))
+		}
+		return out, nil
+	}
+	return p
+}
+
+func TestIsSpecial(t *testing.T) {
+	special := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.1.1",
+		"100.64.0.1", "0.0.0.0", "198.18.0.1", "255.255.255.255", "224.0.0.1",
+		"::1", "::", "fe80::1", "fc00::1", "fd12::1", "ff02::1",
+		"::ffff:127.0.0.1", "64:ff9b::a00:1", "2001:db8::1",
+	}
+	for _, s := range special {
+		assert.True(t, isSpecial(netip.MustParseAddr(s).Unmap()), s)
+	}
+	public := []string{"8.8.8.8", "1.1.1.1", "2606:4700::1111", "64:ff9b::808:808"}
+	for _, s := range public {
+		assert.False(t, isSpecial(netip.MustParseAddr(s)), s)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	ctx := context.Background()
+	p := withDNS(mustPolicy(t, Config{}), map[string][]string{
+		"hooks.example.com": {"93.184.216.34"},
+		"internal.corp":     {"10.0.0.8"},
+		"mixed.example.com": {"93.184.216.34", "192.168.0.10"},
+	})
+
+	assert.NoError(t, p.CheckURL(ctx, "https://hooks.example.com/in", false))
+	assert.NoError(t, p.CheckURL(ctx, "https://unresolvable.example/in", false), "resolved again at delivery")
+
+	for _, raw := range []string{
+		"http://127.0.0.1:8080/", "http://internal.corp/", "http://[::1]/", "http://169.254.169.254/latest/meta-data",
+		"http://mixed.example.com/", "http://[::ffff:10.0.0.1]/",
+	} {
+		assert.ErrorIs(t, p.CheckURL(ctx, raw, false), ErrBlocked, raw)
+	}
+	assert.ErrorIs(t, p.CheckURL(ctx, "ftp://hooks.example.com/", false), ErrBlocked)
+
+	// The override opens private ranges but never metadata endpoints.
+	assert.NoError(t, p.CheckURL(ctx, "http://internal.corp/", true))
+	assert.ErrorIs(t, p.CheckURL(ctx, "http://169.254.169.254/", true), ErrBlocked)
+	assert.ErrorIs(t, p.CheckURL(ctx, "http://[fd00:ec2::254]/", true), ErrBlocked)
+}
+
+func TestCheckURL_Lists(t *testing.T) {
+	ctx := context.Background()
+	p := withDNS(mustPolicy(t, Config{
+		Allowlist: []string{"10.20.0.0/16", "*.svc.cluster.local", "169.254.169.254"},
+		Denylist:  []string{"evil.example.com", "93.184.0.0/16", "*.blocked.example"},
+	}), map[string][]string{
+		"hooks.example.com":       {"93.184.216.34"},
+		"api.svc.cluster.local":   {"10.96.0.5"},
+		"partner.blocked.example": {"1.2.3.4"},
+		"partner.example":         {"10.20.3.4"},
+	})
+
+	assert.NoError(t, p.CheckURL(ctx, "http://api.svc.cluster.local/", false), "allowlisted name")
+	assert.NoError(t, p.CheckURL(ctx, "http://partner.example/", false), "allowlisted range")
+	assert.NoError(t, p.CheckURL(ctx, "http://169.254.169.254/", false), "explicitly allowlisted metadata")
+	assert.ErrorIs(t, p.CheckURL(ctx, "http://hooks.example.com/", true), ErrBlocked, "denylisted range beats override")
+	assert.ErrorIs(t, p.CheckURL(ctx, "http://EVIL.example.com./", false), ErrBlocked)
+	assert.ErrorIs(t, p.CheckURL(ctx, "http://partner.blocked.example/", false), ErrBlocked)
+}
+
+func TestAllowlistOnly(t *testing.T) {
+	ctx := context.Background()
+	_, err := New(Config{AllowlistOnly: true})
+	require.Error(t, err)
+
+	p := withDNS(mustPolicy(t, Config{AllowlistOnly: true, Allowlist: []string{"*.partner.example"}}), map[string][]string{
+		"hooks.example.com": {"93.184.216.34"},
+	})
+	assert.NoError(t, p.CheckURL(ctx, "https://in.partner.example/", false))
+	assert.ErrorIs(t, p.CheckURL(ctx, "https://hooks.example.com/", false), ErrBlocked)
+	assert.ErrorIs(t, p.CheckURL(ctx, "https://unknown.example/", false), ErrBlocked)
+}
+
+func TestNew_RejectsBadEntries(t *testing.T) {
+	for _, e := range []string{"10.0.0.0/33", "bad host", "a*.example.com"} {
+		_, err := New(Config{Denylist: []string{e}})
+		assert.Error(t, err, e)
+	}
+}
+
+func TestTransport_PinsCheckedAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	port := u.Port()
+
+	get := func(ctx context.Context, p *Policy, host string) error {
+		tr := p.Transport(&http.Transport{})
+		defer tr.CloseIdleConnections()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+":"+port+"/", nil)
+		resp, err := (&http.Client{Transport: tr}).Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+	ctx := context.Background()
+
+	// A name that resolves to loopback at delivery is refused, whatever
+	// it resolved to when the subscription was saved.
+	p := withDNS(mustPolicy(t, Config{}), map[string][]string{"rebind.example": {"127.0.0.1"}})
+	err := get(ctx, p, "rebind.example")
+	require.Error(t, err)
+	assert.True(t, IsBlocked(err), err)
+	assert.True(t, IsBlocked(get(ctx, p, "127.0.0.1")))
+
+	// The override dials it, through its own pool.
+	assert.NoError(t, get(WithOverride(ctx), p, "rebind.example"))
+
+	// Only permitted addresses are dialled: the private one is skipped,
+	// the allowlisted loopback one connects.
+	p = withDNS(mustPolicy(t, Config{Allowlist: []string{"127.0.0.0/8"}}),
+		map[string][]string{"multi.example": {"10.255.255.1", "127.0.0.1"}})
+	assert.NoError(t, get(ctx, p, "multi.example"))
+}
+
+func TestTransport_ChecksTargetBehindProxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		proxied.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxySrv.Close()
+	proxyURL, _ := url.Parse(proxySrv.URL)
+
+	p := withDNS(mustPolicy(t, Config{}), map[string][]string{
+		"hooks.example.com": {"93.184.216.34"},
+		"internal.corp":     {"10.0.0.8"},
+	})
+	tr := p.Transport(&http.Transport{Proxy: http.ProxyURL(proxyURL)})
+	defer tr.CloseIdleConnections()
+	client := &http.Client{Transport: tr}
+
+	resp, err := client.Get("http://hooks.example.com/")
+	require.NoError(t, err, "the proxy itself is on loopback but is exempt")
+	resp.Body.Close()
+	assert.EqualValues(t, 1, proxied.Load())
+
+	_, err = client.Get("http://internal.corp/")
+	assert.True(t, IsBlocked(err), err)
+	assert.EqualValues(t, 1, proxied.Load())
+}
+
+func TestNew_RejectsBadOutbound(t *testing.T) {
+	for _, cfg := range []Config{
+		{ProxyURL: "ftp://proxy.example:21"},
+		{ProxyURL: "http://"},
+		{Proxies: []string{"eu"}},
+		{Proxies: []string{"eu=http://a:3128", "eu=http://b:3128"}},
+		{SourceAddrs: []string{"no-such-if0"}},
+		{PublicIPs: []string{"203.0.113.0/33"}},
+	} {
+		_, err := New(cfg)
+		assert.Error(t, err, "%+v", cfg)
+	}
+}
+
+func TestSelectProxy(t *testing.T) {
+	p := mustPolicy(t, Config{
+		ProxyURL: "http://proxy.default:3128",
+		NoProxy:  []string{"*.internal.example", "10.0.0.0/8"},
+		Proxies:  []string{"eu=socks5://proxy.eu:1080"},
+	})
+	sel := p.selectProxy(nil)
+	proxyFor := func(ctx context.Context, target string) string {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, target, nil)
+		u, err := sel(req)
+		require.NoError(t, err)
+		if u == nil {
+			return ""
+		}
+		return u.String()
+	}
+	ctx := context.Background()
+
+	assert.Equal(t, "http://proxy.default:3128", proxyFor(ctx, "https://hooks.example.com/"))
+	assert.Empty(t, proxyFor(ctx, "https://api.internal.example/"))
+	assert.Empty(t, proxyFor(ctx, "http://10.1.2.3/"))
+	assert.Equal(t, "socks5://proxy.eu:1080", proxyFor(WithProxy(ctx, "eu"), "https://api.internal.example/"),
+		"a subscription's named proxy wins over the no-proxy list")
+
+	req, _ := http.NewRequestWithContext(WithProxy(ctx, "us"), http.MethodPost, "https://hooks.example.com/", nil)
+	_, err := sel(req)
+	assert.True(t, IsBlocked(err))
+
+	assert.NoError(t, p.CheckProxy(""))
+	assert.NoError(t, p.CheckProxy("eu"))
+	assert.ErrorContains(t, p.CheckProxy("us"), "have eu")
+}
+
+func TestTransport_NamedProxy(t *testing.T) {
+	var viaDefault, viaEU atomic.Int32
+	proxy := func(n *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			n.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	def, eu := proxy(&viaDefault), proxy(&viaEU)
+	defer def.Close()
+	defer eu.Close()
+
+	p := withDNS(mustPolicy(t, Config{ProxyURL: def.URL, Proxies: []string{"eu=" + eu.URL}}),
+		map[string][]string{"hooks.example.com": {"93.184.216.34"}})
+	tr := p.Transport(&http.Transport{})
+	defer tr.CloseIdleConnections()
+	client := &http.Client{Transport: tr}
+
+	for _, ctx := range []context.Context{context.Background(), Settings{Proxy: "eu"}.Apply(context.Background())} {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://hooks.example.com/", nil)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.EqualValues(t, 1, viaDefault.Load())
+	assert.EqualValues(t, 1, viaEU.Load())
+}
+
+func TestTransport_BindsSourceAddress(t *testing.T) {
+	remote := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote <- r.RemoteAddr
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	get := func(p *Policy) error {
+		tr := p.Transport(&http.Transport{})
+		defer tr.CloseIdleConnections()
+		resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	// 127.0.0.2 is loopback too, so the bind succeeds without extra setup.
+	p := mustPolicy(t, Config{Allowlist: []string{"127.0.0.0/8"}, SourceAddrs: []string{"127.0.0.2"}})
+	require.NoError(t, get(p))
+	host, _, _ := net.SplitHostPort(<-remote)
+	assert.Equal(t, "127.0.0.2", host)
+
+	// No IPv4 source address: the IPv4 target isn't dialled at all.
+	p = mustPolicy(t, Config{Allowlist: []string{"127.0.0.0/8"}, SourceAddrs: []string{"::1"}})
+	assert.ErrorContains(t, get(p), "no IPv4 source address")
+}
+
+func TestPublicIPs(t *testing.T) {
+	p := mustPolicy(t, Config{PublicIPs: []string{"203.0.113.10", "198.51.100.0/28"}, SourceAddrs: []string{"10.0.0.5"}})
+	assert.Equal(t, []string{"203.0.113.10", "198.51.100.0/28"}, p.PublicIPs())
+
+	p = mustPolicy(t, Config{SourceAddrs: []string{"10.0.0.5", "93.184.216.34"}})
+	assert.Equal(t, []string{"93.184.216.34"}, p.PublicIPs(), "private source addresses are NATed; unknown")
+
+	p = mustPolicy(t, Config{SourceAddrs: []string{"93.184.216.34"}, ProxyURL: "http://proxy:3128"})
+	assert.Empty(t, p.PublicIPs(), "proxied: the proxy's address is what targets see")
+	assert.NotNil(t, p.PublicIPs())
+
+	p = mustPolicy(t, Config{SourceAddrs: []string{"lo"}})
+	assert.Contains(t, p.source, netip.MustParseAddr("127.0.0.1"), "interface names expand to their addresses")
+}
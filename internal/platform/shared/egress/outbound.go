@@ -0,0 +1,269 @@
+package egress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// outbound is the parsed routing half of Config: which proxy a delivery
+// goes through and which local address it leaves from.
+type outbound struct {
+	proxyURL *url.URL
+	noProxy  rules
+	named    map[string]*url.URL
+	source   []netip.Addr
+	public   []netip.Prefix
+}
+
+func parseOutbound(cfg Config) (outbound, error) {
+	var out outbound
+	if cfg.ProxyURL != "" {
+		u, err := parseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return out, fmt.Errorf("egress proxy URL: %w", err)
+		}
+		out.proxyURL = u
+	}
+	noProxy, err := parseRules(cfg.NoProxy)
+	if err != nil {
+		return out, fmt.Errorf("egress no-proxy list: %w", err)
+	}
+	out.noProxy = noProxy
+
+	for _, e := range cfg.Proxies {
+		name, raw, ok := strings.Cut(e, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return out, fmt.Errorf("egress proxies: %q: want name=url", e)
+		}
+		if _, dup := out.named[name]; dup {
+			return out, fmt.Errorf("egress proxies: %q is listed twice", name)
+		}
+		u, err := parseProxyURL(strings.TrimSpace(raw))
+		if err != nil {
+			return out, fmt.Errorf("egress proxies: %s: %w", name, err)
+		}
+		if out.named == nil {
+			out.named = make(map[string]*url.URL)
+		}
+		out.named[name] = u
+	}
+
+	for _, e := range cfg.SourceAddrs {
+		addrs, err := sourceAddrs(e)
+		if err != nil {
+			return out, fmt.Errorf("egress source addresses: %w", err)
+		}
+		out.source = append(out.source, addrs...)
+	}
+
+	for _, e := range cfg.PublicIPs {
+		p, err := parsePrefix(e)
+		if err != nil {
+			return out, fmt.Errorf("egress public IPs: %q: %w", e, err)
+		}
+		out.public = append(out.public, p)
+	}
+	return out, nil
+}
+
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%q: scheme must be http, https, socks5 or socks5h", raw)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("%q: no host", raw)
+	}
+	return u, nil
+}
+
+// sourceAddrs expands one SourceAddrs entry: an IP, or an interface's
+// routable addresses. Interfaces are read once, at startup.
+func sourceAddrs(e string) ([]netip.Addr, error) {
+	if a, err := netip.ParseAddr(e); err == nil {
+		return []netip.Addr{a.Unmap()}, nil
+	}
+	ifc, err := net.InterfaceByName(e)
+	if err != nil {
+		return nil, fmt.Errorf("%q: not an IP or interface: %w", e, err)
+	}
+	ifAddrs, err := ifc.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", e, err)
+	}
+	var out []netip.Addr
+	for _, ia := range ifAddrs {
+		ipn, ok := ia.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		a, ok := netip.AddrFromSlice(ipn.IP)
+		if !ok || a.Unmap().IsLinkLocalUnicast() {
+			continue // link-local addresses can't reach a target
+		}
+		out = append(out, a.Unmap())
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%q: interface has no usable address", e)
+	}
+	return out, nil
+}
+
+func parsePrefix(e string) (netip.Prefix, error) {
+	if strings.Contains(e, "/") {
+		p, err := netip.ParsePrefix(e)
+		return p.Masked(), err
+	}
+	a, err := netip.ParseAddr(e)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen()), nil
+}
+
+// CheckProxy validates a subscription's proxy choice: "" (the deployment
+// default) or a name from FC_EGRESS_PROXIES.
+func (p *Policy) CheckProxy(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := p.named[name]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(p.named))
+	for n := range p.named {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	if len(names) == 0 {
+		return fmt.Errorf("egress proxy %q is not configured; this deployment has no named proxies", name)
+	}
+	return fmt.Errorf("egress proxy %q is not configured (have %s)", name, strings.Join(names, ", "))
+}
+
+// PublicIPs lists the addresses targets see deliveries come from, as IPs
+// or CIDRs: FC_EGRESS_PUBLIC_IPS, else the public source addresses when
+// nothing is proxied. Empty when the deployment can't say.
+func (p *Policy) PublicIPs() []string {
+	out := []string{}
+	if len(p.public) > 0 {
+		for _, pf := range p.public {
+			if pf.IsSingleIP() {
+				out = append(out, pf.Addr().String())
+			} else {
+				out = append(out, pf.String())
+			}
+		}
+		return out
+	}
+	if p.proxyURL != nil || len(p.named) > 0 {
+		return out
+	}
+	for _, a := range p.source {
+		if !isSpecial(a) {
+			out = append(out, a.String())
+		}
+	}
+	return out
+}
+
+// routesProxies reports whether the policy chooses proxies itself rather
+// than leaving it to the base transport.
+func (p *Policy) routesProxies() bool { return p.proxyURL != nil || len(p.named) > 0 }
+
+// selectProxy picks the request's proxy: the subscription's named proxy,
+// else FC_EGRESS_PROXY_URL unless the target is on the no-proxy list,
+// else whatever the base transport would have used.
+func (p *Policy) selectProxy(fallback func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if name := ProxyName(req.Context()); name != "" {
+			u, ok := p.named[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: egress proxy %q is not configured", ErrBlocked, name)
+			}
+			return u, nil
+		}
+		if p.proxyURL != nil {
+			host := req.URL.Hostname()
+			if p.noProxy.matchHost(host) {
+				return nil, nil
+			}
+			if a, err := netip.ParseAddr(host); err == nil && p.noProxy.matchAddr(a.Unmap()) {
+				return nil, nil
+			}
+			return p.proxyURL, nil
+		}
+		if fallback != nil {
+			return fallback(req)
+		}
+		return nil, nil
+	}
+}
+
+// bind dials from the configured source addresses, rotating among those
+// of the destination's family. A destination no source address can
+// reach is skipped rather than dialled from an unlisted address.
+func (p *Policy) bind(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var lastErr error
+		for _, a := range addrs {
+			a = a.Unmap()
+			src, ok := p.sourceFor(a)
+			if !ok {
+				lastErr = fmt.Errorf("egress: no IPv%d source address to reach %s", family(a), host)
+				continue
+			}
+			bd := *d
+			bd.LocalAddr = &net.TCPAddr{IP: src.AsSlice()}
+			conn, err := bd.DialContext(ctx, network, net.JoinHostPort(a.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// sourceFor returns the next source address of a's family.
+func (p *Policy) sourceFor(a netip.Addr) (netip.Addr, bool) {
+	n := len(p.source)
+	if n == 0 {
+		return netip.Addr{}, false
+	}
+	start := int(p.next.Add(1) % uint32(n))
+	for i := range n {
+		s := p.source[(start+i)%n]
+		if s.Is4() == a.Is4() {
+			return s, true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+func family(a netip.Addr) int {
+	if a.Is4() {
+		return 4
+	}
+	return 6
+}
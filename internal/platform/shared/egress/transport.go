@@ -0,0 +1,141 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Transport returns a round tripper that enforces the policy on every
+// connection it opens and routes it through the configured proxy and
+// source addresses. base supplies the TLS, proxy and pooling settings
+// (nil: http.DefaultTransport); it is cloned, not modified. With source
+// addresses configured, base's dialer is replaced.
+//
+// Requests whose context carries WithOverride go through a second clone,
+// so a connection opened under the override is never reused for a
+// subscription without it.
+func (p *Policy) Transport(base *http.Transport) *Transport {
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	return &Transport{strict: p.guard(base, false), override: p.guard(base, true)}
+}
+
+// Transport is the round tripper Policy.Transport returns.
+type Transport struct {
+	strict, override *http.Transport
+}
+
+// RoundTrip sends req through the pool matching its override flag.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if Overridden(req.Context()) {
+		return t.override.RoundTrip(req)
+	}
+	return t.strict.RoundTrip(req)
+}
+
+// CloseIdleConnections closes both pools' idle connections.
+func (t *Transport) CloseIdleConnections() {
+	t.strict.CloseIdleConnections()
+	t.override.CloseIdleConnections()
+}
+
+// guard clones base with a checking dialer.
+func (p *Policy) guard(base *http.Transport, override bool) *http.Transport {
+	tr := base.Clone()
+	dial := tr.DialContext
+	if dial == nil || len(p.source) > 0 {
+		d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		dial = d.DialContext
+		if len(p.source) > 0 {
+			dial = p.bind(d)
+		}
+	}
+	tr.DialContext = p.dialer(dial, override)
+	tr.DialTLSContext = nil // would bypass DialContext
+	if p.routesProxies() {
+		tr.Proxy = p.selectProxy(tr.Proxy)
+	}
+	if proxy := tr.Proxy; proxy != nil {
+		tr.Proxy = p.proxy(proxy, override)
+	}
+	return tr
+}
+
+// dialer resolves the host itself, checks every address and dials only
+// the permitted ones, so the address connected to is the address checked.
+func (p *Policy) dialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), override bool) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := p.proxies.Load(addr); ok {
+			return dial(ctx, network, addr)
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkHost(host); err != nil {
+			return nil, err
+		}
+		addrs, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var blocked, dialErr error
+		for _, a := range addrs {
+			if err := p.checkAddr(host, a, override); err != nil {
+				blocked = err
+				continue
+			}
+			conn, err := dial(ctx, network, net.JoinHostPort(a.Unmap().String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		return nil, blocked
+	}
+}
+
+// proxy checks the target before handing the request to a proxy, which
+// resolves the name on its own and so can't be pinned; the proxy's address
+// is then exempt from the dial checks.
+func (p *Policy) proxy(next func(*http.Request) (*url.URL, error), override bool) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		u, err := next(req)
+		if err != nil || u == nil {
+			return u, err
+		}
+		if err := p.CheckURL(req.Context(), req.URL.String(), override); err != nil {
+			return nil, err
+		}
+		p.proxies.Store(canonicalAddr(u), struct{}{})
+		return u, nil
+	}
+}
+
+// canonicalAddr is the host:port the transport dials for proxy u.
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// IsBlocked reports whether err is a policy refusal rather than a network
+// failure.
+func IsBlocked(err error) bool { return errors.Is(err, ErrBlocked) }
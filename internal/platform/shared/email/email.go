@@ -1,4 +1,5 @@
-// Package email sends transactional emails (password reset, …) over SMTP.
+// Package email sends transactional emails (password reset, …) over SMTP
+// or Amazon SES.
 //
 // Port of the Rust shared/email_service.rs: configured from SMTP_* env vars
 // (FC_-prefixed names take precedence over the bare TS-style names). With
@@ -6,6 +7,7 @@
 // STARTTLS; SMTP_SECURE=true uses implicit TLS (:465). When SMTP_HOST isn't
 // set it returns a LogService that logs the message instead of sending, so the
 // platform still boots without a mailer (1:1 with Rust create_email_service).
+// FC_EMAIL_PROVIDER=ses switches to the SES v2 API instead; see SESService.
 package email
 
 import (
@@ -52,9 +54,13 @@ type SMTPService struct {
 	secure                               bool
 }
 
-// FromEnv builds the email service from the environment. Returns a LogService
-// (never nil) when SMTP_HOST isn't set — mirroring Rust create_email_service.
+// FromEnv builds the email service from the environment. FC_EMAIL_PROVIDER
+// selects "smtp" (default) or "ses". Returns a LogService (never nil) when the
+// selected provider isn't configured — mirroring Rust create_email_service.
 func FromEnv() Service {
+	if strings.EqualFold(envFirst("FC_EMAIL_PROVIDER"), "ses") {
+		return sesFromEnv()
+	}
 	host := envFirst("FC_SMTP_HOST", "SMTP_HOST")
 	if host == "" {
 		slog.Warn("SMTP not configured (no SMTP_HOST); password-reset and other emails will be logged only")
@@ -72,6 +78,24 @@ func FromEnv() Service {
 	return svc
 }
 
+// sesFromEnv builds the SES service. The sender falls back to SMTP_FROM so a
+// deployment switching providers keeps its verified From address.
+func sesFromEnv() Service {
+	region := envFirst("FC_SES_REGION", "AWS_REGION")
+	from := envFirst("FC_EMAIL_FROM", "FC_SMTP_FROM", "SMTP_FROM")
+	if region == "" || from == "" {
+		slog.Warn("SES selected but FC_SES_REGION/AWS_REGION or FC_EMAIL_FROM is missing; emails will be logged only")
+		return LogService{}
+	}
+	svc, err := NewSESService(context.Background(), region, from, envFirst("FC_SES_ENDPOINT"))
+	if err != nil {
+		slog.Error("SES email service unavailable; emails will be logged only", "err", err)
+		return LogService{}
+	}
+	slog.Info("SES email service configured", "region", region, "from", from)
+	return svc
+}
+
 // Send delivers m over SMTP.
 func (s *SMTPService) Send(_ context.Context, m Message) error {
 	raw := buildMIME(s.from, m.To, m.Subject, m.HTMLBody)
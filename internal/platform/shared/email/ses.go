@@ -0,0 +1,116 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// SESService sends through the Amazon SES v2 SendEmail API, signed with
+// SigV4 against the default AWS credential chain (env, profile, instance /
+// task role). It speaks the REST API directly so the platform doesn't pull
+// in the SES SDK module for a single call.
+type SESService struct {
+	endpoint, region, from string
+
+	creds  aws.CredentialsProvider
+	signer *v4.Signer
+	client *http.Client
+}
+
+// NewSESService resolves AWS credentials for region. endpoint overrides
+// https://email.<region>.amazonaws.com (LocalStack, tests).
+func NewSESService(ctx context.Context, region, from, endpoint string) (*SESService, error) {
+	if region == "" {
+		return nil, fmt.Errorf("ses: region is required")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("ses: aws config: %w", err)
+	}
+	return newSESService(region, from, endpoint, awsCfg.Credentials), nil
+}
+
+func newSESService(region, from, endpoint string, creds aws.CredentialsProvider) *SESService {
+	if endpoint == "" {
+		endpoint = "https://email." + region + ".amazonaws.com"
+	}
+	return &SESService{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		region:   region,
+		from:     from,
+		creds:    aws.NewCredentialsCache(creds),
+		signer:   v4.NewSigner(),
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// sesContent is the SendEmail "Data"/"Charset" pair.
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				HTML sesContent `json:"Html"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+// Send delivers m via SES. A non-2xx response is returned as an error
+// carrying SES's message (e.g. an unverified sender identity).
+func (s *SESService) Send(ctx context.Context, m Message) error {
+	var in sesSendEmailRequest
+	in.FromEmailAddress = s.from
+	in.Destination.ToAddresses = []string{m.To}
+	in.Content.Simple.Subject = sesContent{Data: m.Subject, Charset: "UTF-8"}
+	in.Content.Simple.Body.HTML = sesContent{Data: m.HTMLBody, Charset: "UTF-8"}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("ses: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ses: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	sum := sha256.Sum256(body)
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("ses: credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), "ses", s.region, time.Now()); err != nil {
+		return fmt.Errorf("ses: sign request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses: send to %s: %w", m.To, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("ses: send to %s: status %d: %s", m.To, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestSESServiceSend(t *testing.T) {
+	var got sesSendEmailRequest
+	var path, authz string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, authz = r.URL.Path, r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("bad body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"MessageId":"m-1"}`))
+	}))
+	defer srv.Close()
+
+	svc := newSESService("eu-west-1", "noreply@acme.com", srv.URL, credentials.NewStaticCredentialsProvider("AKID", "secret", ""))
+	err := svc.Send(context.Background(), Message{To: "a@b.com", Subject: "Reset", HTMLBody: "<p>hi</p>"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if path != "/v2/email/outbound-emails" {
+		t.Errorf("path = %q", path)
+	}
+	if !strings.Contains(authz, "Credential=AKID/") || !strings.Contains(authz, "/eu-west-1/ses/aws4_request") {
+		t.Errorf("request not SigV4-signed for ses: %q", authz)
+	}
+	if got.FromEmailAddress != "noreply@acme.com" || len(got.Destination.ToAddresses) != 1 || got.Destination.ToAddresses[0] != "a@b.com" {
+		t.Errorf("unexpected envelope: %+v", got)
+	}
+	if got.Content.Simple.Subject.Data != "Reset" || got.Content.Simple.Body.HTML.Data != "<p>hi</p>" {
+		t.Errorf("unexpected content: %+v", got.Content)
+	}
+}
+
+func TestSESServiceSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Email address is not verified."}`))
+	}))
+	defer srv.Close()
+
+	svc := newSESService("eu-west-1", "noreply@acme.com", srv.URL, credentials.NewStaticCredentialsProvider("AKID", "secret", ""))
+	err := svc.Send(context.Background(), Message{To: "a@b.com", Subject: "x", HTMLBody: "y"})
+	if err == nil || !strings.Contains(err.Error(), "not verified") {
+		t.Fatalf("expected SES error message to surface, got %v", err)
+	}
+}
+
+func TestFromEnvSESMissingConfigFallsBackToLog(t *testing.T) {
+	t.Setenv("FC_EMAIL_PROVIDER", "ses")
+	t.Setenv("FC_SES_REGION", "")
+	t.Setenv("AWS_REGION", "")
+	if _, ok := FromEnv().(LogService); !ok {
+		t.Fatalf("expected LogService when SES region is unset")
+	}
+}
@@ -18,9 +18,17 @@
 // FLOWCATALYST_APP_KEY_PREVIOUS optional fallback). Encrypt always uses
 // the current key. Decrypt tries current, then each previous key. Use
 // ReEncrypt + NeedsReEncryption to migrate.
+//
+// KMS envelope encryption: with FC_ENCRYPTION_KMS_KEY_ID set, new values
+// are sealed as v2 envelopes (see envelope.go) under a data key wrapped by
+// that AWS KMS key, and the app keys only decrypt older values. A value
+// Decrypt finds under an old key or format is re-encrypted and handed to
+// the SetRewriter hook, which writes it back (see Rewriter), so stored
+// secrets migrate as they are read.
 package encryption
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
@@ -28,6 +36,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	cryptorand "crypto/rand"
 )
@@ -35,10 +44,23 @@ import (
 // currentVersion is the format version byte for new encryptions.
 const currentVersion byte = 1
 
+// nonceSize is the AES-GCM standard nonce length.
+const nonceSize = 12
+
 // Service performs field-level encryption with optional key rotation.
 type Service struct {
+	// current is nil for a KMS-only service with no app key.
 	current  cipher.AEAD
 	previous []cipher.AEAD
+	// envelope seals new values under a KMS-wrapped data key; nil uses
+	// current.
+	envelope *envelope
+	// external resolves secret-manager references; nil leaves them
+	// undecryptable. Set via SetExternal.
+	external External
+	// rewrite receives values Decrypt re-encrypted under the current
+	// key. Set via SetRewriter.
+	rewrite func(stale, fresh string)
 }
 
 // New constructs a Service with a single key (no rotation). keyB64 is a
@@ -69,16 +91,44 @@ func WithPreviousKeys(currentKeyB64 string, previousKeysB64 []string) (*Service,
 	return &Service{current: current, previous: prev}, nil
 }
 
+// NewEnvelope constructs a Service that seals new values under data keys
+// from w. appKeysB64, the current app key first, decrypt values written
+// before KMS was enabled; empty entries are skipped.
+func NewEnvelope(w KeyWrapper, appKeysB64 ...string) (*Service, error) {
+	s := &Service{envelope: newEnvelope(w)}
+	for i, k := range appKeysB64 {
+		if k == "" {
+			continue
+		}
+		a, err := makeAEAD(k)
+		if err != nil {
+			return nil, fmt.Errorf("app key %d: %w", i, err)
+		}
+		if s.current == nil {
+			s.current = a
+		} else {
+			s.previous = append(s.previous, a)
+		}
+	}
+	return s, nil
+}
+
 // FromEnv reads FLOWCATALYST_APP_KEY (required) and
 // FLOWCATALYST_APP_KEY_PREVIOUS (optional). Returns nil, nil if the
 // current key is unset — callers should treat that as "encryption
-// disabled" and refuse to write encrypted fields.
+// disabled" and refuse to write encrypted fields. With
+// FC_ENCRYPTION_KMS_KEY_ID set the service uses KMS envelope encryption,
+// the app keys are optional, and the same Service is returned for as long
+// as the configuration is unchanged.
 func FromEnv() (*Service, error) {
 	current := os.Getenv("FLOWCATALYST_APP_KEY")
+	prev := strings.TrimSpace(os.Getenv("FLOWCATALYST_APP_KEY_PREVIOUS"))
+	if keyID := strings.TrimSpace(os.Getenv("FC_ENCRYPTION_KMS_KEY_ID")); keyID != "" {
+		return kmsFromEnv(keyID, current, prev)
+	}
 	if current == "" {
 		return nil, nil
 	}
-	prev := strings.TrimSpace(os.Getenv("FLOWCATALYST_APP_KEY_PREVIOUS"))
 	var prevKeys []string
 	if prev != "" {
 		prevKeys = []string{prev}
@@ -86,12 +136,54 @@ func FromEnv() (*Service, error) {
 	return WithPreviousKeys(current, prevKeys)
 }
 
+// kmsShared is the KMS service FromEnv last built. Many call sites build
+// a Service per operation; sharing one per configuration keeps them on one
+// data key instead of a KMS round-trip each.
+var kmsShared struct {
+	sync.Mutex
+	config string
+	svc    *Service
+}
+
+func kmsFromEnv(keyID, current, prev string) (*Service, error) {
+	config := keyID + "\x00" + current + "\x00" + prev
+	kmsShared.Lock()
+	defer kmsShared.Unlock()
+	if kmsShared.svc != nil && kmsShared.config == config {
+		return kmsShared.svc, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), externalTimeout)
+	defer cancel()
+	w, err := NewKMSWrapper(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := NewEnvelope(w, current, prev)
+	if err != nil {
+		return nil, err
+	}
+	kmsShared.config, kmsShared.svc = config, svc
+	return svc, nil
+}
+
+// SetRewriter has Decrypt pass every value it finds under an old key or
+// format, with its re-encryption, to fn so the stored copy can be
+// replaced. fn must not block. Set once at startup.
+func (s *Service) SetRewriter(fn func(stale, fresh string)) { s.rewrite = fn }
+
 // Encrypt returns the base64-encoded versioned envelope for plaintext.
 func (s *Service) Encrypt(plaintext string) (string, error) {
-	nonce := make([]byte, s.current.NonceSize())
+	nonce := make([]byte, nonceSize)
 	if _, err := cryptorand.Read(nonce); err != nil {
 		return "", fmt.Errorf("encryption: read nonce: %w", err)
 	}
+	if s.envelope != nil {
+		out, err := s.envelope.seal(plaintext, nonce)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(out), nil
+	}
 	ciphertext := s.current.Seal(nil, nonce, []byte(plaintext), nil)
 
 	out := make([]byte, 0, 1+len(nonce)+len(ciphertext))
@@ -103,48 +195,100 @@ func (s *Service) Encrypt(plaintext string) (string, error) {
 
 // Decrypt accepts both v1 versioned envelopes, v0 legacy (no version
 // byte), and TypeScript-style "encrypted:" prefixed values. It tries
-// the current key first then each previous key.
+// the current key first then each previous key. An external reference
+// ("vault://…", "aws-sm://…") is resolved through SetExternal's resolver.
 func (s *Service) Decrypt(encrypted string) (string, error) {
+	if s.external != nil && !strings.HasPrefix(encrypted, "encrypted:") && s.external.Handles(encrypted) {
+		return s.resolveExternal(encrypted)
+	}
+	pt, stale, err := s.decrypt(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if stale && s.rewrite != nil {
+		if fresh, err := s.Encrypt(pt); err == nil {
+			if strings.HasPrefix(encrypted, "encrypted:") {
+				fresh = "encrypted:" + fresh
+			}
+			s.rewrite(encrypted, fresh)
+		}
+	}
+	return pt, nil
+}
+
+// decrypt opens any stored format. stale reports that the value isn't in
+// the form Encrypt would produce now: an older key, or an older format.
+func (s *Service) decrypt(encrypted string) (pt string, stale bool, err error) {
 	raw := strings.TrimPrefix(encrypted, "encrypted:")
 	data, err := base64.StdEncoding.DecodeString(raw)
 	if err != nil {
-		return "", fmt.Errorf("encryption: invalid base64: %w", err)
+		return "", false, fmt.Errorf("encryption: invalid base64: %w", err)
 	}
 	if len(data) == 0 {
-		return "", errors.New("encryption: empty ciphertext")
+		return "", false, errors.New("encryption: empty ciphertext")
 	}
 
-	nonceSize := s.current.NonceSize()
+	// A legacy v0 value can start with the v2 byte too, so a v2 parse
+	// that doesn't open falls back to v0 before giving up.
+	var envErr error
+	if wrapped, nonce, ct, err := splitEnvelope(data, nonceSize); err == nil {
+		if s.envelope == nil {
+			envErr = errors.New("encryption: value is KMS-encrypted but FC_ENCRYPTION_KMS_KEY_ID is unset")
+		} else if pt, stale, err := s.envelope.open(wrapped, nonce, ct); err == nil {
+			return pt, stale, nil
+		} else {
+			envErr = err
+		}
+	}
 	if data[0] == currentVersion {
 		// v1: version(1) || nonce(12) || ciphertext
 		if len(data) < 1+nonceSize+1 {
-			return "", errors.New("encryption: ciphertext too short (v1)")
+			return "", false, errors.New("encryption: ciphertext too short (v1)")
 		}
-		return s.tryDecrypt(data[1:1+nonceSize], data[1+nonceSize:])
+		pt, byCurrent, err := s.tryDecrypt(data[1:1+nonceSize], data[1+nonceSize:])
+		return pt, err == nil && (s.envelope != nil || !byCurrent), err
 	}
 	// v0 legacy: nonce(12) || ciphertext
 	if len(data) < nonceSize+1 {
-		return "", errors.New("encryption: ciphertext too short (v0)")
+		return "", false, errors.New("encryption: ciphertext too short (v0)")
+	}
+	pt, _, err = s.tryDecrypt(data[:nonceSize], data[nonceSize:])
+	if err != nil && envErr != nil {
+		return "", false, envErr
+	}
+	return pt, err == nil, err
+}
+
+func (s *Service) resolveExternal(ref string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), externalTimeout)
+	defer cancel()
+	v, err := s.external.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("encryption: resolve secret reference: %w", err)
 	}
-	return s.tryDecrypt(data[:nonceSize], data[nonceSize:])
+	return v, nil
 }
 
-func (s *Service) tryDecrypt(nonce, ciphertext []byte) (string, error) {
-	if pt, err := s.current.Open(nil, nonce, ciphertext, nil); err == nil {
-		return string(pt), nil
+// tryDecrypt opens with the current app key, then each previous one.
+// byCurrent reports which.
+func (s *Service) tryDecrypt(nonce, ciphertext []byte) (pt string, byCurrent bool, err error) {
+	if s.current != nil {
+		if pt, err := s.current.Open(nil, nonce, ciphertext, nil); err == nil {
+			return string(pt), true, nil
+		}
 	}
 	for _, prev := range s.previous {
 		if pt, err := prev.Open(nil, nonce, ciphertext, nil); err == nil {
-			return string(pt), nil
+			return string(pt), false, nil
 		}
 	}
-	return "", errors.New("encryption: decryption failed with all available keys")
+	return "", false, errors.New("encryption: decryption failed with all available keys")
 }
 
 // ReEncrypt decrypts encrypted (with any available key) and re-encrypts
 // using the current key. Used by the rotation migration job.
 func (s *Service) ReEncrypt(encrypted string) (string, error) {
-	pt, err := s.Decrypt(encrypted)
+	pt, _, err := s.decrypt(encrypted)
 	if err != nil {
 		return "", err
 	}
@@ -152,18 +296,25 @@ func (s *Service) ReEncrypt(encrypted string) (string, error) {
 }
 
 // NeedsReEncryption returns true if encrypted was produced by an older
-// key or older format. False if the current key can decrypt it in v1
-// envelope, or if encrypted is malformed (no point in attempting).
+// key or older format: for a KMS service, anything but a v2 envelope
+// under the current KMS key. False if it is current, or if encrypted is
+// malformed (no point in attempting).
 func (s *Service) NeedsReEncryption(encrypted string) bool {
 	raw := strings.TrimPrefix(encrypted, "encrypted:")
 	data, err := base64.StdEncoding.DecodeString(raw)
 	if err != nil {
 		return false
 	}
+	if s.envelope != nil {
+		if _, _, _, err := splitEnvelope(data, nonceSize); err != nil {
+			return true
+		}
+		_, stale, err := s.decrypt(encrypted)
+		return err == nil && stale
+	}
 	if len(data) == 0 || data[0] != currentVersion {
 		return true
 	}
-	nonceSize := s.current.NonceSize()
 	if len(data) < 1+nonceSize+1 {
 		return true
 	}
@@ -186,6 +337,10 @@ func makeAEAD(keyB64 string) (cipher.AEAD, error) {
 	if err != nil {
 		return nil, fmt.Errorf("encryption: invalid base64 key: %w", err)
 	}
+	return aeadFromBytes(keyBytes)
+}
+
+func aeadFromBytes(keyBytes []byte) (cipher.AEAD, error) {
 	if len(keyBytes) != 32 {
 		return nil, fmt.Errorf("encryption: key must be 32 bytes, got %d", len(keyBytes))
 	}
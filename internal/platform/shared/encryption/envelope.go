@@ -0,0 +1,163 @@
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// envelopeVersion marks a KMS envelope:
+//
+//	version(1) || len(wrapped DEK)(2, big-endian) || wrapped DEK || nonce(12) || ciphertext+tag
+//
+// The data-encryption key (DEK) is a random AES-256 key wrapped by the
+// master key in the KMS; the master key never leaves it.
+const envelopeVersion byte = 2
+
+// dataKeyLifetime is how long one DEK encrypts new values before a fresh
+// one is requested. Old DEKs stay decryptable for as long as the master
+// key that wrapped them exists.
+const dataKeyLifetime = 24 * time.Hour
+
+// KeyWrapper generates and unwraps data keys under a master key held in a
+// KMS. *KMSWrapper implements it for AWS KMS.
+type KeyWrapper interface {
+	// GenerateDataKey returns a new 32-byte key, its wrapped form and
+	// the canonical ID of the master key that wrapped it.
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, keyID string, err error)
+	// Unwrap returns the plaintext of a wrapped key and the ID of the
+	// master key that wrapped it, which may be an older one.
+	Unwrap(ctx context.Context, wrapped []byte) (plaintext []byte, keyID string, err error)
+}
+
+// dataKey is one DEK, ready to use.
+type dataKey struct {
+	aead    cipher.AEAD
+	wrapped []byte
+	keyID   string
+	created time.Time
+}
+
+// envelope holds the KMS side of a Service: the DEK new values are sealed
+// with and the DEKs already unwrapped for reading.
+type envelope struct {
+	wrapper KeyWrapper
+	now     func() time.Time
+
+	mu        sync.Mutex
+	current   *dataKey
+	unwrapped map[string]*dataKey // by wrapped bytes
+}
+
+func newEnvelope(w KeyWrapper) *envelope {
+	return &envelope{wrapper: w, now: time.Now, unwrapped: make(map[string]*dataKey)}
+}
+
+// currentKey returns the DEK for new values, generating one when there is
+// none yet or it has aged out.
+func (e *envelope) currentKey() (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.current != nil && e.now().Sub(e.current.created) < dataKeyLifetime {
+		return e.current, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), externalTimeout)
+	defer cancel()
+	plain, wrapped, keyID, err := e.wrapper.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: generate data key: %w", err)
+	}
+	dk, err := newDataKey(plain, wrapped, keyID)
+	if err != nil {
+		return nil, err
+	}
+	dk.created = e.now()
+	e.current = dk
+	e.unwrapped[string(wrapped)] = dk
+	return dk, nil
+}
+
+// key returns the DEK for a wrapped key read from a value, asking the KMS
+// only the first time.
+func (e *envelope) key(wrapped []byte) (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if dk, ok := e.unwrapped[string(wrapped)]; ok {
+		return dk, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), externalTimeout)
+	defer cancel()
+	plain, keyID, err := e.wrapper.Unwrap(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: unwrap data key: %w", err)
+	}
+	dk, err := newDataKey(plain, wrapped, keyID)
+	if err != nil {
+		return nil, err
+	}
+	e.unwrapped[string(wrapped)] = dk
+	return dk, nil
+}
+
+func newDataKey(plain, wrapped []byte, keyID string) (*dataKey, error) {
+	aead, err := aeadFromBytes(plain)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: data key: %w", err)
+	}
+	return &dataKey{aead: aead, wrapped: append([]byte(nil), wrapped...), keyID: keyID}, nil
+}
+
+// seal encrypts plaintext into a v2 envelope.
+func (e *envelope) seal(plaintext string, nonce []byte) ([]byte, error) {
+	dk, err := e.currentKey()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 3+len(dk.wrapped)+len(nonce)+len(plaintext)+dk.aead.Overhead())
+	out = append(out, envelopeVersion)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(dk.wrapped)))
+	out = append(out, dk.wrapped...)
+	out = append(out, nonce...)
+	return dk.aead.Seal(out, nonce, []byte(plaintext), nil), nil
+}
+
+// errNotEnvelope means data doesn't parse as a v2 envelope, so it may be a
+// legacy v0 value whose nonce happens to start with 0x02.
+var errNotEnvelope = errors.New("encryption: not a v2 envelope")
+
+// splitEnvelope parses data (version byte included) into its wrapped DEK,
+// nonce and ciphertext.
+func splitEnvelope(data []byte, nonceSize int) (wrapped, nonce, ciphertext []byte, err error) {
+	if len(data) < 3 || data[0] != envelopeVersion {
+		return nil, nil, nil, errNotEnvelope
+	}
+	n := int(binary.BigEndian.Uint16(data[1:3]))
+	rest := data[3:]
+	if n == 0 || len(rest) < n+nonceSize+1 {
+		return nil, nil, nil, errNotEnvelope
+	}
+	return rest[:n], rest[n : n+nonceSize], rest[n+nonceSize:], nil
+}
+
+// open decrypts a v2 envelope. stale reports that the DEK was wrapped by a
+// master key other than the current one.
+func (e *envelope) open(wrapped, nonce, ciphertext []byte) (string, bool, error) {
+	dk, err := e.key(wrapped)
+	if err != nil {
+		return "", false, err
+	}
+	pt, err := dk.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", false, errors.New("encryption: decryption failed with the value's data key")
+	}
+	cur, err := e.currentKey()
+	if err != nil {
+		// Reading doesn't need the current key; just don't rewrite.
+		return string(pt), false, nil
+	}
+	return string(pt), dk.keyID != cur.keyID, nil
+}
@@ -0,0 +1,171 @@
+package encryption_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+)
+
+type fakeDataKey struct {
+	plain []byte
+	arn   string
+}
+
+// fakeKMS "wraps" a data key by remembering it under a random handle.
+type fakeKMS struct {
+	keyARN string
+
+	mu        sync.Mutex
+	keys      map[string]fakeDataKey
+	generated int
+	unwrapped int
+}
+
+func newFakeKMS(arn string) *fakeKMS {
+	return &fakeKMS{keyARN: arn, keys: map[string]fakeDataKey{}}
+}
+
+func (f *fakeKMS) GenerateDataKey(_ context.Context, _ *kms.GenerateDataKeyInput, _ ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error) {
+	plain, handle := make([]byte, 32), make([]byte, 48)
+	_, _ = rand.Read(plain)
+	_, _ = rand.Read(handle)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.generated++
+	f.keys[string(handle)] = fakeDataKey{plain, f.keyARN}
+	return &kms.GenerateDataKeyOutput{Plaintext: plain, CiphertextBlob: handle, KeyId: aws.String(f.keyARN)}, nil
+}
+
+func (f *fakeKMS) Decrypt(_ context.Context, in *kms.DecryptInput, _ ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unwrapped++
+	k := f.keys[string(in.CiphertextBlob)]
+	return &kms.DecryptOutput{Plaintext: k.plain, KeyId: aws.String(k.arn)}, nil
+}
+
+func newKMSService(t *testing.T, f *fakeKMS, appKeys ...string) *encryption.Service {
+	t.Helper()
+	svc, err := encryption.NewEnvelope(encryption.NewKMSWrapperWithClient(f, "alias/fc"), appKeys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	f := newFakeKMS("arn:aws:kms:eu-west-1:1:key/a")
+	svc := newKMSService(t, f)
+
+	ct1, err := svc.Encrypt("client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct2, _ := svc.Encrypt("another")
+	if raw, _ := base64.StdEncoding.DecodeString(ct1); raw[0] != 2 {
+		t.Fatalf("want a v2 envelope, got version %d", raw[0])
+	}
+	if f.generated != 1 {
+		t.Fatalf("data key must be reused, generated %d", f.generated)
+	}
+
+	// Another replica: unwraps the data key once, then reads from cache.
+	other := newKMSService(t, f)
+	for _, ct := range []string{ct1, ct1, "encrypted:" + ct1} {
+		got, err := other.Decrypt(ct)
+		if err != nil || got != "client-secret" {
+			t.Fatalf("Decrypt: got (%q,%v)", got, err)
+		}
+	}
+	if got, _ := other.Decrypt(ct2); got != "another" {
+		t.Fatalf("got %q", got)
+	}
+	if f.unwrapped != 1 {
+		t.Fatalf("want one unwrap, got %d", f.unwrapped)
+	}
+	if other.NeedsReEncryption(ct1) {
+		t.Fatal("current-key envelope must not need re-encryption")
+	}
+}
+
+func TestEnvelopeRewritesLegacyOnRead(t *testing.T) {
+	appKey, _ := encryption.GenerateKey()
+	legacy, _ := encryption.New(appKey)
+	old, _ := legacy.Encrypt("totp-seed")
+	old = "encrypted:" + old
+
+	svc := newKMSService(t, newFakeKMS("arn:aws:kms:eu-west-1:1:key/a"), appKey)
+	if !svc.NeedsReEncryption(old) {
+		t.Fatal("app-key value must need re-encryption once KMS is on")
+	}
+	var stale, fresh string
+	svc.SetRewriter(func(s, f string) { stale, fresh = s, f })
+
+	got, err := svc.Decrypt(old)
+	if err != nil || got != "totp-seed" {
+		t.Fatalf("Decrypt: got (%q,%v)", got, err)
+	}
+	if stale != old || !strings.HasPrefix(fresh, "encrypted:") {
+		t.Fatalf("rewriter got (%q,%q)", stale, fresh)
+	}
+	if got, _ := svc.Decrypt(fresh); got != "totp-seed" || svc.NeedsReEncryption(fresh) {
+		t.Fatalf("fresh value: got %q", got)
+	}
+}
+
+func TestEnvelopeRewritesOnKMSKeyChange(t *testing.T) {
+	f := newFakeKMS("arn:aws:kms:eu-west-1:1:key/a")
+	ct, _ := newKMSService(t, f).Encrypt("webhook-token")
+
+	f.keyARN = "arn:aws:kms:eu-west-1:1:key/b"
+	svc := newKMSService(t, f)
+	rewrites := 0
+	svc.SetRewriter(func(string, string) { rewrites++ })
+	if got, err := svc.Decrypt(ct); err != nil || got != "webhook-token" {
+		t.Fatalf("Decrypt: got (%q,%v)", got, err)
+	}
+	if rewrites != 1 || !svc.NeedsReEncryption(ct) {
+		t.Fatalf("value under the old KMS key must be rewritten (rewrites=%d)", rewrites)
+	}
+}
+
+func TestAppKeyRotationRewritesOnRead(t *testing.T) {
+	oldKey, _ := encryption.GenerateKey()
+	newKey, _ := encryption.GenerateKey()
+	oldSvc, _ := encryption.New(oldKey)
+	oldCT, _ := oldSvc.Encrypt("secret")
+
+	svc, _ := encryption.WithPreviousKeys(newKey, []string{oldKey})
+	var fresh string
+	svc.SetRewriter(func(_, f string) { fresh = f })
+	if _, err := svc.Decrypt(oldCT); err != nil {
+		t.Fatal(err)
+	}
+	if fresh == "" || svc.NeedsReEncryption(fresh) {
+		t.Fatalf("want a current-key rewrite, got %q", fresh)
+	}
+
+	fresh = ""
+	cur, _ := svc.Encrypt("secret")
+	_, _ = svc.Decrypt(cur)
+	if fresh != "" {
+		t.Fatal("current values must not be rewritten")
+	}
+}
+
+func TestEnvelopeNeedsKMSConfigured(t *testing.T) {
+	ct, _ := newKMSService(t, newFakeKMS("arn:aws:kms:eu-west-1:1:key/a")).Encrypt("x")
+	appKey, _ := encryption.GenerateKey()
+	svc, _ := encryption.New(appKey)
+	if _, err := svc.Decrypt(ct); err == nil || !strings.Contains(err.Error(), "FC_ENCRYPTION_KMS_KEY_ID") {
+		t.Fatalf("want a KMS-not-configured error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package encryption
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// rewriteTargets are the statements that replace one stored ciphertext
+// with its re-encryption, one per column holding encrypted values. $1 is
+// the stale value, $2 the fresh one; matching on the stale value makes
+// each a compare-and-swap, so a secret changed since it was read is left
+// alone. Ciphertexts carry a random nonce, so a value matches one row.
+var rewriteTargets = []string{
+	`UPDATE oauth_clients SET client_secret_ref = $2 WHERE client_secret_ref = $1`,
+	`UPDATE oauth_identity_providers SET oidc_client_secret_ref = $2 WHERE oidc_client_secret_ref = $1`,
+	`UPDATE tnt_client_auth_configs SET oidc_client_secret_ref = $2 WHERE oidc_client_secret_ref = $1`,
+	`UPDATE iam_service_accounts SET wh_auth_token_ref = $2 WHERE wh_auth_token_ref = $1`,
+	`UPDATE iam_service_accounts SET wh_signing_secret_ref = $2 WHERE wh_signing_secret_ref = $1`,
+	`UPDATE iam_principals SET dev_client_secret_ref = $2 WHERE dev_client_secret_ref = $1`,
+	`UPDATE iam_user_mfa_methods SET secret_encrypted = $2 WHERE secret_encrypted = $1`,
+	`UPDATE iam_jwt_signing_keys SET private_key_enc = $2 WHERE private_key_enc = $1`,
+	`UPDATE msg_subscription_target_tls SET client_key_enc = $2 WHERE client_key_enc = $1`,
+	`UPDATE msg_ingest_sources SET signing_secret = $2 WHERE signing_secret = $1`,
+	`UPDATE msg_subscription_target_auth
+	    SET config = jsonb_set(config, '{oauth2,clientSecret}', to_jsonb($2::text))
+	  WHERE config->'oauth2'->>'clientSecret' = $1`,
+}
+
+// rewriteQueueSize bounds the pending write-backs; past it Enqueue drops
+// and the value is rewritten on a later read.
+const rewriteQueueSize = 256
+
+// Rewriter writes values Decrypt re-encrypted back to Postgres, so a key
+// rotation (or the move to KMS) migrates stored secrets lazily as they are
+// read. Wire it with Service.SetRewriter(rw.Enqueue) and run Run.
+type Rewriter struct {
+	pool  *pgxpool.Pool
+	queue chan [2]string
+
+	mu   sync.Mutex
+	seen map[string]bool // stale values already queued
+}
+
+// NewRewriter builds a rewriter over pool.
+func NewRewriter(pool *pgxpool.Pool) *Rewriter {
+	return &Rewriter{pool: pool, queue: make(chan [2]string, rewriteQueueSize), seen: make(map[string]bool)}
+}
+
+// Enqueue schedules stale to be replaced by fresh. Each stale value is
+// queued once per process; it never blocks.
+func (w *Rewriter) Enqueue(stale, fresh string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.seen[stale] {
+		return
+	}
+	select {
+	case w.queue <- [2]string{stale, fresh}:
+		w.seen[stale] = true
+	default:
+	}
+}
+
+// Run applies queued rewrites until ctx is cancelled.
+func (w *Rewriter) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-w.queue:
+			n, err := w.rewrite(ctx, r[0], r[1])
+			if err != nil {
+				slog.Warn("re-encrypt stored secret failed", "err", err)
+				w.mu.Lock()
+				delete(w.seen, r[0]) // retry on a later read
+				w.mu.Unlock()
+				continue
+			}
+			if n > 0 {
+				slog.Debug("stored secret re-encrypted under the current key", "rows", n)
+			}
+		}
+	}
+}
+
+func (w *Rewriter) rewrite(ctx context.Context, stale, fresh string) (int64, error) {
+	var total int64
+	for _, q := range rewriteTargets {
+		tag, err := w.pool.Exec(ctx, q, stale, fresh)
+		if err != nil {
+			return total, err
+		}
+		if total += tag.RowsAffected(); total > 0 {
+			return total, nil
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package encryption_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+)
+
+func TestMain(m *testing.M) { testpg.RunMain(m) }
+
+func TestRewriterReplacesStoredValue(t *testing.T) {
+	pool := testpg.Pool(t)
+	ctx := context.Background()
+
+	oldKey, _ := encryption.GenerateKey()
+	newKey, _ := encryption.GenerateKey()
+	oldSvc, _ := encryption.New(oldKey)
+	stale, _ := oldSvc.Encrypt("pem")
+
+	if _, err := pool.Exec(ctx,
+		`INSERT INTO iam_jwt_signing_keys (kid, public_key_pem, private_key_enc, activates_at)
+		 VALUES ('rewrite-test', 'pub', $1, NOW())`, stale); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = pool.Exec(ctx, `DELETE FROM iam_jwt_signing_keys WHERE kid = 'rewrite-test'`) })
+
+	svc, _ := encryption.WithPreviousKeys(newKey, []string{oldKey})
+	rw := encryption.NewRewriter(pool)
+	svc.SetRewriter(rw.Enqueue)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go rw.Run(runCtx)
+
+	if _, err := svc.Decrypt(stale); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var stored string
+		if err := pool.QueryRow(ctx, `SELECT private_key_enc FROM iam_jwt_signing_keys WHERE kid = 'rewrite-test'`).Scan(&stored); err != nil {
+			t.Fatal(err)
+		}
+		if stored != stale {
+			if svc.NeedsReEncryption(stored) {
+				t.Fatal("stored value is still under the old key")
+			}
+			if got, _ := svc.Decrypt(stored); got != "pem" {
+				t.Fatalf("rewritten value decrypts to %q", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("value was not rewritten")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -1,8 +1,10 @@
 package encryption
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
 )
 
 // ErrNotConfigured is returned by EncryptSecretRef when a plaintext secret
@@ -14,6 +16,23 @@ var ErrNotConfigured = errors.New("encryption not configured: FLOWCATALYST_APP_K
 // verbatim and resolved at read time — never encrypted inline.
 var externalSecretSchemes = []string{"aws-sm://", "aws-ps://", "gcp-sm://", "vault://", "env://", "literal:"}
 
+// External resolves secret-manager references at read time;
+// *secrets.Service implements it.
+type External interface {
+	// Handles reports whether ref names a configured backend.
+	Handles(ref string) bool
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// externalTimeout bounds one external lookup from Decrypt, which has no
+// caller context (the resolver caches, so this is rarely on a hot path).
+const externalTimeout = 10 * time.Second
+
+// SetExternal lets Decrypt resolve the external references EncryptSecretRef
+// stores verbatim — an OIDC client secret or ingest signing secret kept in
+// Vault or AWS Secrets Manager. Set once at startup.
+func (s *Service) SetExternal(ext External) { s.external = ext }
+
 // EncryptSecretRef converts an incoming secret reference into its at-rest form.
 //
 // A plaintext value — optionally carrying the SecretRefInput "encrypt:"
@@ -1,6 +1,7 @@
 package encryption
 
 import (
+	"context"
 	"strings"
 	"testing"
 )
@@ -85,3 +86,37 @@ func TestEncryptSecretRef(t *testing.T) {
 		}
 	})
 }
+
+type fakeExternal map[string]string
+
+func (f fakeExternal) Handles(ref string) bool {
+	_, ok := f[ref]
+	return ok
+}
+
+func (f fakeExternal) Resolve(_ context.Context, ref string) (string, error) { return f[ref], nil }
+
+func TestDecryptResolvesExternalRefs(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc.SetExternal(fakeExternal{"vault://secret/oidc#client_secret": "from-vault"})
+
+	got, err := enc.Decrypt("vault://secret/oidc#client_secret")
+	if err != nil || got != "from-vault" {
+		t.Fatalf("want from-vault, got (%q,%v)", got, err)
+	}
+
+	stored, err := EncryptSecretRef(enc, ptr("inline"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := enc.Decrypt(*stored); err != nil || got != "inline" {
+		t.Fatalf("inline ciphertext: got (%q,%v)", got, err)
+	}
+}
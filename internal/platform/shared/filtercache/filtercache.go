@@ -0,0 +1,156 @@
+// Package filtercache holds the in-process caches in front of the
+// filter-options endpoints. Those run a handful of SELECT DISTINCTs over
+// the read tables on every dashboard load; the answer changes only when
+// the stream processor projects new rows, so each Cache keeps the last
+// answer until its TTL runs out or the processor reports a change.
+//
+// A change only marks the entry stale once it is MinAge old: during steady
+// ingest the projections report a batch every few hundred milliseconds,
+// and dropping the entry on each would leave it permanently cold.
+package filtercache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// Config holds the cache knobs (env-overridable).
+type Config struct {
+	// TTL is how long an answer is served without a change being
+	// reported. It bounds staleness when the stream processor runs in
+	// another process. 0 disables caching.
+	TTL time.Duration
+	// MinAge is how long an answer is served even after a change.
+	MinAge time.Duration
+}
+
+// ConfigFromEnv reads FC_FILTER_OPTIONS_CACHE_TTL_SECS.
+func ConfigFromEnv() Config {
+	ttl := envutil.Int("FC_FILTER_OPTIONS_CACHE_TTL_SECS", 60)
+	if ttl < 0 {
+		ttl = 0
+	}
+	return Config{TTL: time.Duration(ttl) * time.Second, MinAge: 5 * time.Second}
+}
+
+// Cache memoises one value. The zero of *Cache (nil) is usable and
+// caches nothing.
+type Cache[T any] struct {
+	name string
+	cfg  Config
+	now  func() time.Time
+
+	mu       sync.Mutex
+	value    T
+	loadedAt time.Time
+	valid    bool
+	changed  bool
+
+	hits, misses, invalidations atomic.Uint64
+}
+
+// New builds a cache named for its metrics label.
+func New[T any](name string, cfg Config) *Cache[T] {
+	return &Cache[T]{name: name, cfg: cfg, now: time.Now}
+}
+
+// Get returns the cached value, or calls load and caches its result. A
+// load error is returned with whatever load produced and nothing is
+// cached, so the next call retries. Concurrent misses share one load.
+func (c *Cache[T]) Get(ctx context.Context, load func(context.Context) (T, error)) (T, error) {
+	if c == nil || c.cfg.TTL <= 0 {
+		return load(ctx)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fresh() {
+		c.hits.Add(1)
+		return c.value, nil
+	}
+	c.misses.Add(1)
+	v, err := load(ctx)
+	if err != nil {
+		return v, err
+	}
+	c.value, c.loadedAt, c.valid, c.changed = v, c.now(), true, false
+	return v, nil
+}
+
+// fresh reports whether the held value may be served. c.mu is held.
+func (c *Cache[T]) fresh() bool {
+	if !c.valid {
+		return false
+	}
+	age := c.now().Sub(c.loadedAt)
+	if age >= c.cfg.TTL {
+		return false
+	}
+	return !c.changed || age < c.cfg.MinAge
+}
+
+// Invalidate records that the underlying data changed; the held value is
+// reloaded on the first Get once it is MinAge old.
+func (c *Cache[T]) Invalidate() {
+	if c == nil {
+		return
+	}
+	c.invalidations.Add(1)
+	c.mu.Lock()
+	c.changed = true
+	c.mu.Unlock()
+}
+
+// Name is the cache's metrics label.
+func (c *Cache[T]) Name() string { return c.name }
+
+// Stats is a cache's counters since start.
+type Stats struct {
+	Hits, Misses, Invalidations uint64
+}
+
+// Stats snapshots the counters.
+func (c *Cache[T]) Stats() Stats {
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Invalidations: c.invalidations.Load()}
+}
+
+// Source is what the Collector reads; every *Cache is one.
+type Source interface {
+	Name() string
+	Stats() Stats
+}
+
+var (
+	requestsDesc = prometheus.NewDesc("fc_filter_options_cache_requests_total",
+		"Filter-options cache lookups by result (hit or miss).", []string{"cache", "result"}, nil)
+	invalidationsDesc = prometheus.NewDesc("fc_filter_options_cache_invalidations_total",
+		"Change notifications from the stream processor.", []string{"cache"}, nil)
+)
+
+// Collector exposes the caches' counters as Prometheus metrics.
+type Collector struct {
+	sources []Source
+}
+
+// NewCollector builds a collector over the given caches.
+func NewCollector(sources ...Source) *Collector {
+	return &Collector{sources: sources}
+}
+
+// Describe is a no-op (unchecked const-metric collector).
+func (c *Collector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits each cache's hit, miss and invalidation counts.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.sources {
+		st := s.Stats()
+		ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.CounterValue, float64(st.Hits), s.Name(), "hit")
+		ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.CounterValue, float64(st.Misses), s.Name(), "miss")
+		ch <- prometheus.MustNewConstMetric(invalidationsDesc, prometheus.CounterValue, float64(st.Invalidations), s.Name())
+	}
+}
@@ -0,0 +1,100 @@
+package filtercache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestCache(cfg Config) (*Cache[int], *clock) {
+	clk := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := New[int]("events", cfg)
+	c.now = clk.now
+	return c, clk
+}
+
+func TestCache_TTLAndInvalidation(t *testing.T) {
+	c, clk := newTestCache(Config{TTL: time.Minute, MinAge: 5 * time.Second})
+	loads := 0
+	load := func(context.Context) (int, error) { loads++; return loads, nil }
+	get := func() int {
+		t.Helper()
+		v, err := c.Get(context.Background(), load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	if get() != 1 || get() != 1 {
+		t.Fatalf("second Get reloaded; loads = %d", loads)
+	}
+	// A change inside MinAge keeps serving the held value...
+	c.Invalidate()
+	if get() != 1 {
+		t.Fatal("Invalidate reloaded before MinAge")
+	}
+	// ...and reloads once it is old enough.
+	clk.advance(5 * time.Second)
+	if get() != 2 {
+		t.Fatal("changed entry past MinAge wasn't reloaded")
+	}
+	clk.advance(30 * time.Second)
+	if get() != 2 {
+		t.Fatal("unchanged entry reloaded before TTL")
+	}
+	clk.advance(30 * time.Second)
+	if get() != 3 {
+		t.Fatal("entry past TTL wasn't reloaded")
+	}
+
+	if st := c.Stats(); st.Hits != 3 || st.Misses != 3 || st.Invalidations != 1 {
+		t.Fatalf("Stats = %+v, want 3 hits, 3 misses, 1 invalidation", st)
+	}
+	want := `
+# HELP fc_filter_options_cache_requests_total Filter-options cache lookups by result (hit or miss).
+# TYPE fc_filter_options_cache_requests_total counter
+fc_filter_options_cache_requests_total{cache="events",result="hit"} 3
+fc_filter_options_cache_requests_total{cache="events",result="miss"} 3
+`
+	if err := testutil.CollectAndCompare(NewCollector(c), strings.NewReader(want), "fc_filter_options_cache_requests_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	c, _ := newTestCache(Config{TTL: time.Minute})
+	boom := errors.New("boom")
+	if _, err := c.Get(context.Background(), func(context.Context) (int, error) { return 7, boom }); !errors.Is(err, boom) {
+		t.Fatalf("Get = %v, want the load error", err)
+	}
+	v, err := c.Get(context.Background(), func(context.Context) (int, error) { return 8, nil })
+	if err != nil || v != 8 {
+		t.Fatalf("Get after a failed load = %d, %v; want a fresh load", v, err)
+	}
+}
+
+func TestCache_DisabledAndNil(t *testing.T) {
+	loads := 0
+	load := func(context.Context) (int, error) { loads++; return loads, nil }
+	off, _ := newTestCache(Config{})
+	var none *Cache[int]
+	for _, c := range []*Cache[int]{off, off, none, none} {
+		if _, err := c.Get(context.Background(), load); err != nil {
+			t.Fatal(err)
+		}
+	}
+	none.Invalidate()
+	if loads != 4 {
+		t.Fatalf("loads = %d, want every Get to load", loads)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/provider"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
@@ -145,6 +146,25 @@ func introspect(ctx context.Context, p *provider.Provider, token string, fromCoo
 		return nil, nil
 	}
 
+	// Impersonation tokens are authorized from their own claims on either
+	// transport: re-resolving the subject would hand back the support
+	// principal's full anchor access instead of the one client it acts as.
+	// Scope and Clients are pinned to the impersonated client regardless of
+	// what else the token says.
+	if c.Impersonating != "" {
+		return &auth.AuthContext{
+			PrincipalID:     c.Subject,
+			Scope:           auth.ScopeClient,
+			Email:           c.Email,
+			Clients:         []string{c.Impersonating},
+			Roles:           c.Roles,
+			Applications:    c.Applications,
+			AllApplications: c.AllApplications,
+			Permissions:     c.Permissions,
+			Impersonating:   c.Impersonating,
+		}, nil
+	}
+
 	// Session cookies (the SPA) carry only identity (subject). Resolve the
 	// mutable authorization data — scope, roles, clients, applications,
 	// permissions — FRESH from the DB on every request, so a role/permission/
@@ -189,9 +209,20 @@ func introspect(ctx context.Context, p *provider.Provider, token string, fromCoo
 		Applications:    c.Applications,
 		AllApplications: c.AllApplications,
 		Permissions:     perms,
+		Environment:     tokenEnvironment(c.Environment),
 	}, nil
 }
 
+// tokenEnvironment maps the "env" claim onto the environment the token is
+// pinned to. An absent claim leaves it unpinned; an unknown one pins to
+// production rather than to nothing.
+func tokenEnvironment(env string) common.Environment {
+	if env == "" {
+		return ""
+	}
+	return common.ParseEnvironment(env)
+}
+
 // stringSlice coerces a claim into []string — kept here for any future
 // adapter that needs it. Tokens we mint already arrive as []string.
 func stringSlice(v any) []string {
@@ -236,6 +267,7 @@ func writeInvalidTokenError(w http.ResponseWriter, err error) {
 //	X-FC-Test-Permissions:  comma-separated permission codes
 //	X-FC-Test-Roles:        comma-separated role names
 //	X-FC-Test-Email:        principal email
+//	X-FC-Test-Environment:  SANDBOX | PRODUCTION pins the principal (default unpinned)
 func buildTestAuthContext(r *http.Request) *auth.AuthContext {
 	scope := auth.Scope(r.Header.Get("X-FC-Test-Scope"))
 	if scope == "" {
@@ -260,6 +292,7 @@ func buildTestAuthContext(r *http.Request) *auth.AuthContext {
 		Applications:    apps,
 		AllApplications: allApps,
 		Permissions:     splitCSV(r.Header.Get("X-FC-Test-Permissions")),
+		Environment:     tokenEnvironment(r.Header.Get("X-FC-Test-Environment")),
 	}
 }
 
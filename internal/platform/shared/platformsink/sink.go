@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -31,8 +32,12 @@ type Sink struct{}
 // New constructs the platform sink.
 func New() *Sink { return &Sink{} }
 
-// Compile-time check that *Sink satisfies usecasepgx.Sink.
-var _ usecasepgx.Sink = (*Sink)(nil)
+// Compile-time checks that *Sink satisfies usecasepgx.Sink and records
+// aggregate changes.
+var (
+	_ usecasepgx.Sink          = (*Sink)(nil)
+	_ usecasepgx.ChangeAuditor = (*Sink)(nil)
+)
 
 // WriteEvent inserts the domain event into msg_events. The shape of
 // the row matches the Rust fc-platform PgUnitOfWork::persist_event.
@@ -92,11 +97,34 @@ func nullIfEmpty(s string) any {
 }
 
 // WriteAudit inserts an audit log row into aud_logs. Column set
-// matches the schema (migrations 006 + 009): id, entity_type, entity_id,
-// operation, operation_json, principal_id, application_id, client_id,
-// performed_at. Mirrors the Rust source's column ordering exactly so a
-// side-by-side parity diff stays clean.
-func (*Sink) WriteAudit(ctx context.Context, tx *usecasepgx.DbTx, event usecase.DomainEvent, command any) error {
+// matches the schema (migrations 006 + 009 + 053): id, entity_type,
+// entity_id, operation, operation_json, principal_id, application_id,
+// client_id, performed_at, changes. Mirrors the Rust source's column
+// ordering exactly so a side-by-side parity diff stays clean; changes is
+// Go-only and appended last.
+func (s *Sink) WriteAudit(ctx context.Context, tx *usecasepgx.DbTx, event usecase.DomainEvent, command any) error {
+	return s.writeAudit(ctx, tx, event, command, nil)
+}
+
+// WriteAuditChange is WriteAudit plus the before/after diff of the
+// aggregate written (audit.Diff, credentials redacted). An update that
+// changed nothing records an empty diff rather than NULL.
+func (s *Sink) WriteAuditChange(ctx context.Context, tx *usecasepgx.DbTx, event usecase.DomainEvent, command any, change usecasepgx.Change) error {
+	diff, err := audit.Diff(change.Before, change.After)
+	if err != nil {
+		return fmt.Errorf("audit diff: %w", err)
+	}
+	if diff == nil {
+		diff = []audit.Change{}
+	}
+	raw, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("marshal audit diff: %w", err)
+	}
+	return s.writeAudit(ctx, tx, event, command, raw)
+}
+
+func (*Sink) writeAudit(ctx context.Context, tx *usecasepgx.DbTx, event usecase.DomainEvent, command any, changes []byte) error {
 	cmdJSON, err := json.Marshal(command)
 	if err != nil {
 		return fmt.Errorf("marshal command: %w", err)
@@ -117,8 +145,8 @@ func (*Sink) WriteAudit(ctx context.Context, tx *usecasepgx.DbTx, event usecase.
 		`INSERT INTO aud_logs
 		     (id, entity_type, entity_id, operation,
 		      operation_json, principal_id, application_id,
-		      client_id, performed_at)
-		 VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7, $8, $9)`,
+		      client_id, performed_at, changes)
+		 VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7, $8, $9, $10::jsonb)`,
 		newAuditID(),
 		usecase.ExtractAggregateType(event.Subject()),
 		usecase.ExtractEntityID(event.Subject()),
@@ -128,6 +156,7 @@ func (*Sink) WriteAudit(ctx context.Context, tx *usecasepgx.DbTx, event usecase.
 		nil, // application_id
 		nil, // client_id
 		eventTime(event),
+		changes,
 	)
 	if err != nil {
 		slog.Error("aud_logs insert failed", "event_type", event.EventType(), "err", err)
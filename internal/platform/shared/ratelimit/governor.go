@@ -77,6 +77,18 @@ func OIDCBridgeGovernorFromEnv() GovernorConfig {
 // NewGovernor builds a Governor for the supplied quota. PerMinute or Burst
 // below 1 are clamped to 1 (matching Rust's max(1)).
 func NewGovernor(cfg GovernorConfig) *Governor {
+	limit, burst := cfg.quota()
+	return &Governor{
+		limit:         limit,
+		burst:         burst,
+		now:           time.Now,
+		pruneInterval: 5 * time.Minute,
+		idleTTL:       10 * time.Minute,
+		buckets:       make(map[string]*govEntry),
+	}
+}
+
+func (cfg GovernorConfig) quota() (rate.Limit, int) {
 	perMin := cfg.PerMinute
 	if perMin < 1 {
 		perMin = 1
@@ -85,13 +97,23 @@ func NewGovernor(cfg GovernorConfig) *Governor {
 	if burst < 1 {
 		burst = 1
 	}
-	return &Governor{
-		limit:         rate.Limit(float64(perMin) / 60.0),
-		burst:         int(burst),
-		now:           time.Now,
-		pruneInterval: 5 * time.Minute,
-		idleTTL:       10 * time.Minute,
-		buckets:       make(map[string]*govEntry),
+	return rate.Limit(float64(perMin) / 60.0), int(burst)
+}
+
+// SetQuota replaces the quota at runtime (config reload). Existing buckets
+// keep their tokens and move to the new rate and capacity from now on.
+func (g *Governor) SetQuota(cfg GovernorConfig) {
+	limit, burst := cfg.quota()
+	now := g.now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if limit == g.limit && burst == g.burst {
+		return
+	}
+	g.limit, g.burst = limit, burst
+	for _, e := range g.buckets {
+		e.lim.SetLimitAt(now, limit)
+		e.lim.SetBurstAt(now, burst)
 	}
 }
 
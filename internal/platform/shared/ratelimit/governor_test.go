@@ -147,3 +147,32 @@ func TestGovernorMiddlewareNoIPPassesThrough(t *testing.T) {
 		}
 	}
 }
+
+func TestGovernorSetQuota(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	g := newTestGovernor(60, 1, clk)
+
+	if ok, _ := g.Check("a"); !ok {
+		t.Fatal("first request should be allowed")
+	}
+	if ok, _ := g.Check("a"); ok {
+		t.Fatal("second request should be throttled at burst 1")
+	}
+
+	// Raising the quota applies to the existing bucket: burst 3 refills at
+	// 120/min (2/sec), so half a second buys one more token.
+	g.SetQuota(GovernorConfig{PerMinute: 120, Burst: 3})
+	clk.add(500 * time.Millisecond)
+	if ok, _ := g.Check("a"); !ok {
+		t.Error("request after the quota change should be allowed")
+	}
+	// New keys get the new burst.
+	for i := 0; i < 3; i++ {
+		if ok, _ := g.Check("b"); !ok {
+			t.Fatalf("burst request %d for a new key should be allowed", i+1)
+		}
+	}
+	if ok, _ := g.Check("b"); ok {
+		t.Error("4th request for b should be throttled")
+	}
+}
@@ -42,30 +42,53 @@ func (s *PostgresStore) CheckAndRecord(ctx context.Context, bucket Bucket, key s
 	}
 
 	if uint64(count) > uint64(policy.Limit) {
-		// Retry-after = time until the oldest in-window event ages out.
-		var oldest *time.Time
-		if err := s.pool.QueryRow(ctx,
-			`SELECT MIN(occurred_at) FROM iam_rate_limit_events
-			WHERE bucket = $1 AND key = $2 AND occurred_at > $3`,
-			string(bucket), key, windowStart).Scan(&oldest); err != nil {
-			return Decision{}, fmt.Errorf("rate-limit oldest: %w", err)
-		}
-		retryAfter := int64(policy.Window.Seconds())
-		if oldest != nil {
-			elapsed := int64(time.Since(*oldest).Seconds())
-			if elapsed < 0 {
-				elapsed = 0
-			}
-			retryAfter = int64(policy.Window.Seconds()) - elapsed
-		}
-		if retryAfter < 1 {
-			retryAfter = 1
-		}
-		return Decision{Allowed: false, RetryAfterSecs: clampU32(retryAfter)}, nil
+		return s.rejection(ctx, bucket, key, policy, windowStart)
 	}
 	return Decision{Allowed: true}, nil
 }
 
+// Check counts the window without inserting.
+func (s *PostgresStore) Check(ctx context.Context, bucket Bucket, key string, policy Policy) (Decision, error) {
+	windowStart := time.Now().UTC().Add(-policy.Window)
+
+	var count int64
+	err := s.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM iam_rate_limit_events
+		WHERE bucket = $1 AND key = $2 AND occurred_at > $3`,
+		string(bucket), key, windowStart).Scan(&count)
+	if err != nil {
+		return Decision{}, fmt.Errorf("rate-limit count: %w", err)
+	}
+	if uint64(count)+1 > uint64(policy.Limit) {
+		return s.rejection(ctx, bucket, key, policy, windowStart)
+	}
+	return Decision{Allowed: true}, nil
+}
+
+// rejection builds the over-limit decision; retry-after is the time until
+// the oldest in-window event ages out.
+func (s *PostgresStore) rejection(ctx context.Context, bucket Bucket, key string, policy Policy, windowStart time.Time) (Decision, error) {
+	var oldest *time.Time
+	if err := s.pool.QueryRow(ctx,
+		`SELECT MIN(occurred_at) FROM iam_rate_limit_events
+		WHERE bucket = $1 AND key = $2 AND occurred_at > $3`,
+		string(bucket), key, windowStart).Scan(&oldest); err != nil {
+		return Decision{}, fmt.Errorf("rate-limit oldest: %w", err)
+	}
+	retryAfter := int64(policy.Window.Seconds())
+	if oldest != nil {
+		elapsed := int64(time.Since(*oldest).Seconds())
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		retryAfter = int64(policy.Window.Seconds()) - elapsed
+	}
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return Decision{Allowed: false, RetryAfterSecs: clampU32(retryAfter)}, nil
+}
+
 // Prune deletes events older than olderThan. Returns the number removed.
 func (s *PostgresStore) Prune(ctx context.Context, olderThan time.Duration) (int64, error) {
 	cutoff := time.Now().UTC().Add(-olderThan)
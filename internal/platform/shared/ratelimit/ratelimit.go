@@ -19,6 +19,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -31,15 +32,18 @@ import (
 type Bucket string
 
 const (
-	BucketOAuthTokenIP         Bucket = "oauth_token_ip"
-	BucketOAuthTokenClient     Bucket = "oauth_token_client"
-	BucketOAuthAuthorizeIP     Bucket = "oauth_authorize_ip"
-	BucketOAuthAuthorizeClient Bucket = "oauth_authorize_client"
-	BucketOAuthIntrospectIP    Bucket = "oauth_introspect_ip"
-	BucketOAuthRevokeIP        Bucket = "oauth_revoke_ip"
-	BucketPasswordResetIP      Bucket = "password_reset_ip"
-	BucketPasswordResetEmail   Bucket = "password_reset_email"
-	BucketCheckDomainIP        Bucket = "check_domain_ip"
+	BucketOAuthTokenIP          Bucket = "oauth_token_ip"
+	BucketOAuthTokenClient      Bucket = "oauth_token_client"
+	BucketOAuthAuthorizeIP      Bucket = "oauth_authorize_ip"
+	BucketOAuthAuthorizeClient  Bucket = "oauth_authorize_client"
+	BucketOAuthIntrospectIP     Bucket = "oauth_introspect_ip"
+	BucketOAuthRevokeIP         Bucket = "oauth_revoke_ip"
+	BucketPasswordResetIP       Bucket = "password_reset_ip"
+	BucketPasswordResetEmail    Bucket = "password_reset_email"
+	BucketCheckDomainIP         Bucket = "check_domain_ip"
+	BucketDeviceVerifyIP        Bucket = "device_verify_ip"
+	BucketDeviceVerifyPrincipal Bucket = "device_verify_principal"
+	BucketDeviceUserCodeMiss    Bucket = "device_user_code_miss"
 )
 
 // Policy is "at most Limit events in any Window" for one (bucket, key).
@@ -51,26 +55,54 @@ type Policy struct {
 // Policies holds the per-bucket defaults, loaded once at startup. Defaults
 // are deliberately generous — this is the long-tail cluster-wide ceiling.
 type Policies struct {
-	OAuthTokenIP         Policy
-	OAuthTokenClient     Policy
-	OAuthAuthorizeIP     Policy
-	OAuthAuthorizeClient Policy
-	PasswordResetIP      Policy
-	PasswordResetEmail   Policy
+	OAuthTokenIP          Policy
+	OAuthTokenClient      Policy
+	OAuthAuthorizeIP      Policy
+	OAuthAuthorizeClient  Policy
+	PasswordResetIP       Policy
+	PasswordResetEmail    Policy
+	DeviceVerifyIP        Policy
+	DeviceVerifyPrincipal Policy
 }
 
 // PoliciesFromEnv reads the FC_RL_* knobs, matching the Rust defaults.
 func PoliciesFromEnv() Policies {
 	return Policies{
-		OAuthTokenIP:         Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_TOKEN_IP_PER_MIN", 600)},
-		OAuthTokenClient:     Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_TOKEN_CLIENT_PER_MIN", 300)},
-		OAuthAuthorizeIP:     Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_AUTHORIZE_IP_PER_MIN", 600)},
-		OAuthAuthorizeClient: Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_AUTHORIZE_CLIENT_PER_MIN", 300)},
-		PasswordResetIP:      Policy{time.Hour, envutil.Uint32("FC_RL_PASSWORD_RESET_IP_PER_HOUR", 20)},
-		PasswordResetEmail:   Policy{time.Hour, envutil.Uint32("FC_RL_PASSWORD_RESET_EMAIL_PER_HOUR", 5)},
+		OAuthTokenIP:          Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_TOKEN_IP_PER_MIN", 600)},
+		OAuthTokenClient:      Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_TOKEN_CLIENT_PER_MIN", 300)},
+		OAuthAuthorizeIP:      Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_AUTHORIZE_IP_PER_MIN", 600)},
+		OAuthAuthorizeClient:  Policy{time.Minute, envutil.Uint32("FC_RL_OAUTH_AUTHORIZE_CLIENT_PER_MIN", 300)},
+		PasswordResetIP:       Policy{time.Hour, envutil.Uint32("FC_RL_PASSWORD_RESET_IP_PER_HOUR", 20)},
+		PasswordResetEmail:    Policy{time.Hour, envutil.Uint32("FC_RL_PASSWORD_RESET_EMAIL_PER_HOUR", 5)},
+		DeviceVerifyIP:        Policy{time.Minute, envutil.Uint32("FC_RL_DEVICE_VERIFY_IP_PER_MIN", 60)},
+		DeviceVerifyPrincipal: Policy{time.Minute, envutil.Uint32("FC_RL_DEVICE_VERIFY_PRINCIPAL_PER_MIN", 20)},
 	}
 }
 
+// LivePolicies holds the current Policies for readers on the request path
+// while a config reload swaps them. The zero value is not usable; build one
+// with NewLivePolicies.
+type LivePolicies struct{ p atomic.Pointer[Policies] }
+
+// NewLivePolicies starts from p.
+func NewLivePolicies(p Policies) *LivePolicies {
+	l := &LivePolicies{}
+	l.Set(p)
+	return l
+}
+
+// Get returns the current policies; a nil *LivePolicies returns the zero
+// Policies (callers with no store configured never look at them).
+func (l *LivePolicies) Get() Policies {
+	if l == nil {
+		return Policies{}
+	}
+	return *l.p.Load()
+}
+
+// Set replaces the policies for every subsequent Get.
+func (l *LivePolicies) Set(p Policies) { l.p.Store(&p) }
+
 // MaxWindow is the longest window across all policies — used by the prune
 // task to know how far back to keep history.
 func (p Policies) MaxWindow() time.Duration {
@@ -79,6 +111,7 @@ func (p Policies) MaxWindow() time.Duration {
 		p.OAuthTokenIP.Window, p.OAuthTokenClient.Window,
 		p.OAuthAuthorizeIP.Window, p.OAuthAuthorizeClient.Window,
 		p.PasswordResetIP.Window, p.PasswordResetEmail.Window,
+		p.DeviceVerifyIP.Window, p.DeviceVerifyPrincipal.Window,
 	} {
 		if w > max {
 			max = w
@@ -99,6 +132,9 @@ type Decision struct {
 // a bypass race under burst load).
 type Store interface {
 	CheckAndRecord(ctx context.Context, bucket Bucket, key string, policy Policy) (Decision, error)
+	// Check reports what CheckAndRecord would decide for one more event,
+	// without recording it.
+	Check(ctx context.Context, bucket Bucket, key string, policy Policy) (Decision, error)
 	// Prune drops events older than the given age. Redis is a no-op
 	// (TTLs auto-expire); Postgres deletes old rows. Best-effort.
 	Prune(ctx context.Context, olderThan time.Duration) (int64, error)
@@ -112,6 +148,11 @@ func (NoopStore) CheckAndRecord(context.Context, Bucket, string, Policy) (Decisi
 	return Decision{Allowed: true}, nil
 }
 
+// Check always allows.
+func (NoopStore) Check(context.Context, Bucket, string, Policy) (Decision, error) {
+	return Decision{Allowed: true}, nil
+}
+
 // Prune is a no-op.
 func (NoopStore) Prune(context.Context, time.Duration) (int64, error) { return 0, nil }
 
@@ -170,6 +211,20 @@ func Enforce(ctx context.Context, store Store, bucket Bucket, key string, policy
 	return nil
 }
 
+// Exhausted reports whether one more event for (bucket, key) would be over
+// the limit, without recording it. Fails open (false) like Enforce.
+func Exhausted(ctx context.Context, store Store, bucket Bucket, key string, policy Policy) bool {
+	if store == nil {
+		return false
+	}
+	d, err := store.Check(ctx, bucket, key, policy)
+	if err != nil {
+		slog.Warn("distributed rate-limit backend error; failing open", "bucket", string(bucket), "err", err)
+		return false
+	}
+	return !d.Allowed
+}
+
 // WriteTooManyRequests emits a 429 with a Retry-After header and the
 // platform {error, message} envelope (matching Rust's enforce_distributed).
 func WriteTooManyRequests(w http.ResponseWriter, retryAfterSecs uint32, message string) {
@@ -185,6 +240,12 @@ func WriteTooManyRequests(w http.ResponseWriter, retryAfterSecs uint32, message
 // IPLimitMiddleware rejects requests whose source IP exhausts the bucket.
 // Requests with no resolvable IP pass through (that's the LB's job).
 func IPLimitMiddleware(store Store, bucket Bucket, policy Policy) func(http.Handler) http.Handler {
+	return IPLimitMiddlewareFunc(store, bucket, func() Policy { return policy })
+}
+
+// IPLimitMiddlewareFunc is IPLimitMiddleware with the policy looked up per
+// request, so a reloaded LivePolicies takes effect without re-mounting.
+func IPLimitMiddlewareFunc(store Store, bucket Bucket, policy func() Policy) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ip := ClientIP(r)
@@ -192,7 +253,7 @@ func IPLimitMiddleware(store Store, bucket Bucket, policy Policy) func(http.Hand
 				next.ServeHTTP(w, r)
 				return
 			}
-			if rej := Enforce(r.Context(), store, bucket, ip, policy); rej != nil {
+			if rej := Enforce(r.Context(), store, bucket, ip, policy()); rej != nil {
 				WriteTooManyRequests(w, rej.RetryAfterSecs, "rate limit exceeded for this IP")
 				return
 			}
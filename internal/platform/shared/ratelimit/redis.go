@@ -2,6 +2,7 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -80,6 +81,27 @@ func (s *RedisStore) CheckAndRecord(ctx context.Context, bucket Bucket, key stri
 	return Decision{Allowed: true}, nil
 }
 
+// Check reads the current window's counter without incrementing it.
+func (s *RedisStore) Check(ctx context.Context, bucket Bucket, key string, policy Policy) (Decision, error) {
+	windowSecs := int64(policy.Window.Seconds())
+	if windowSecs < 1 {
+		windowSecs = 1
+	}
+	nowSecs := time.Now().Unix()
+	count, err := s.client.Get(ctx, redisKey(bucket, key, nowSecs/windowSecs)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return Decision{}, fmt.Errorf("redis get: %w", err)
+	}
+	if uint64(count)+1 > uint64(policy.Limit) {
+		retryAfter := windowSecs - nowSecs%windowSecs
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		return Decision{Allowed: false, RetryAfterSecs: clampU32(retryAfter)}, nil
+	}
+	return Decision{Allowed: true}, nil
+}
+
 // Prune is a no-op for Redis (TTLs auto-expire fixed-window keys).
 func (s *RedisStore) Prune(context.Context, time.Duration) (int64, error) { return 0, nil }
 
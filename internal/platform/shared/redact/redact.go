@@ -0,0 +1,187 @@
+// Package redact masks customer data in the SPA-facing (/bff) event and
+// dispatch-job views, so support staff can triage delivery problems
+// without seeing payloads, credentials carried in headers, or receiver
+// URLs.
+//
+// Each collection has a default set of masked fields. A caller holding the
+// collection's view-pii permission sees everything; otherwise the fields
+// masked are the collection default, or — when one of the caller's roles
+// has a rule of its own — only the fields every such role masks (the most
+// permissive role wins). Rules come from FC_BFF_REDACTION_RULES.
+//
+// The /api mounts of the same handlers (SDK, bearer-auth) are not
+// redacted: service accounts read their own data back.
+package redact
+
+import (
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+)
+
+// Mask replaces a redacted value.
+const Mask = "[REDACTED]"
+
+// Collection names a redactable view.
+type Collection string
+
+const (
+	Events       Collection = "events"
+	DispatchJobs Collection = "dispatch-jobs"
+)
+
+// Field names, as they appear in the JSON responses.
+const (
+	FieldData         = "data"
+	FieldContextData  = "contextData"
+	FieldPayload      = "payload"
+	FieldMetadata     = "metadata"
+	FieldTargetURL    = "targetUrl"
+	FieldResponseBody = "responseBody"
+)
+
+// fieldsOf lists what each collection can redact; rules naming anything
+// else are rejected at parse time.
+var fieldsOf = map[Collection][]string{
+	Events:       {FieldData, FieldContextData},
+	DispatchJobs: {FieldPayload, FieldMetadata, FieldTargetURL, FieldResponseBody},
+}
+
+// unmaskPerm is the permission that lifts redaction for a collection.
+var unmaskPerm = map[Collection]string{
+	Events:       "platform:messaging:event:view-pii",
+	DispatchJobs: "platform:messaging:dispatch-job:view-pii",
+}
+
+// Fields is the set of fields to mask for one caller and collection.
+type Fields map[string]struct{}
+
+// Has reports whether field is masked.
+func (f Fields) Has(field string) bool {
+	_, ok := f[field]
+	return ok
+}
+
+// Policy holds the per-collection default and per-role rules.
+type Policy struct {
+	defaults map[Collection][]string
+	roles    map[Collection]map[string][]string
+}
+
+// Default masks every redactable field of every collection.
+func Default() *Policy {
+	p := &Policy{defaults: make(map[Collection][]string, len(fieldsOf)), roles: map[Collection]map[string][]string{}}
+	for c, f := range fieldsOf {
+		p.defaults[c] = f
+	}
+	return p
+}
+
+// PolicyFromEnv builds a Policy from FC_BFF_REDACTION_RULES.
+func PolicyFromEnv() *Policy {
+	return ParseRules(envutil.Or("FC_BFF_REDACTION_RULES", ""))
+}
+
+// ParseRules reads "collection[@role]=field,field;...". A rule without a
+// role replaces the collection default; an empty field list masks nothing.
+// Malformed entries are logged and skipped rather than failing startup;
+// anything not overridden keeps the mask-everything default.
+func ParseRules(s string) *Policy {
+	p := Default()
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, list, ok := strings.Cut(entry, "=")
+		coll, role, hasRole := strings.Cut(strings.TrimSpace(key), "@")
+		c := Collection(strings.TrimSpace(coll))
+		role = strings.TrimSpace(role)
+		fields, valid := parseFields(c, list)
+		if !ok || !valid || (hasRole && role == "") {
+			slog.Warn("ignoring malformed FC_BFF_REDACTION_RULES entry", "entry", entry)
+			continue
+		}
+		if !hasRole {
+			p.defaults[c] = fields
+			continue
+		}
+		if p.roles[c] == nil {
+			p.roles[c] = map[string][]string{}
+		}
+		p.roles[c][role] = fields
+	}
+	return p
+}
+
+func parseFields(c Collection, list string) ([]string, bool) {
+	known, ok := fieldsOf[c]
+	if !ok {
+		return nil, false
+	}
+	out := []string{}
+	for _, f := range strings.Split(list, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(known, f) {
+			return nil, false
+		}
+		out = append(out, f)
+	}
+	return out, true
+}
+
+// For returns the fields to mask for ac in collection c. A nil Policy
+// behaves like Default.
+func (p *Policy) For(ac *auth.AuthContext, c Collection) Fields {
+	if p == nil {
+		p = Default()
+	}
+	if ac.HasPermission(unmaskPerm[c]) {
+		return Fields{}
+	}
+	var matched [][]string
+	if ac != nil {
+		for _, r := range ac.Roles {
+			if f, ok := p.roles[c][r]; ok {
+				matched = append(matched, f)
+			}
+		}
+	}
+	if len(matched) == 0 {
+		matched = [][]string{p.defaults[c]}
+	}
+	out := Fields{}
+	for _, f := range matched[0] {
+		if slices.IndexFunc(matched[1:], func(m []string) bool { return !slices.Contains(m, f) }) < 0 {
+			out[f] = struct{}{}
+		}
+	}
+	return out
+}
+
+// String masks s when non-nil.
+func String(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	m := Mask
+	return &m
+}
+
+// URL keeps a target URL's scheme and host — enough to tell which receiver
+// a job went to — and masks the path and query, where webhook secrets and
+// customer identifiers tend to live.
+func URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return Mask
+	}
+	return u.Scheme + "://" + u.Host + "/" + Mask
+}
@@ -0,0 +1,71 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+)
+
+func names(f Fields) []string {
+	out := []string{}
+	for _, n := range fieldsOf[DispatchJobs] {
+		if f.Has(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func TestFor_DefaultMasksEverything(t *testing.T) {
+	ac := &auth.AuthContext{Permissions: []string{"platform:messaging:dispatch-job:view"}}
+	assert.Equal(t, fieldsOf[DispatchJobs], names(Default().For(ac, DispatchJobs)))
+
+	var nilPolicy *Policy
+	assert.Equal(t, fieldsOf[DispatchJobs], names(nilPolicy.For(ac, DispatchJobs)), "nil policy fails closed")
+	assert.Equal(t, fieldsOf[DispatchJobs], names(Default().For(nil, DispatchJobs)))
+}
+
+func TestFor_UnmaskPermission(t *testing.T) {
+	ac := &auth.AuthContext{Permissions: []string{"platform:messaging:dispatch-job:view-pii"}}
+	assert.Empty(t, Default().For(ac, DispatchJobs))
+	assert.True(t, Default().For(ac, Events).Has(FieldData), "the grant is per collection")
+
+	admin := &auth.AuthContext{Permissions: []string{"platform:*:*:*"}}
+	assert.Empty(t, Default().For(admin, Events), "wildcards match")
+}
+
+func TestParseRules_DefaultAndRoleOverrides(t *testing.T) {
+	p := ParseRules("dispatch-jobs=payload,targetUrl; dispatch-jobs@platform:support=payload ;" +
+		"dispatch-jobs@platform:l2=targetUrl,payload,metadata")
+
+	plain := &auth.AuthContext{Roles: []string{"platform:viewer"}}
+	assert.Equal(t, []string{FieldPayload, FieldTargetURL}, names(p.For(plain, DispatchJobs)))
+
+	support := &auth.AuthContext{Roles: []string{"platform:viewer", "platform:support"}}
+	assert.Equal(t, []string{FieldPayload}, names(p.For(support, DispatchJobs)))
+
+	both := &auth.AuthContext{Roles: []string{"platform:l2", "platform:support"}}
+	assert.Equal(t, []string{FieldPayload}, names(p.For(both, DispatchJobs)),
+		"a field stays masked only if every matching role masks it")
+
+	assert.True(t, p.For(plain, Events).Has(FieldContextData), "untouched collections keep the default")
+}
+
+func TestParseRules_EmptyListMasksNothing(t *testing.T) {
+	p := ParseRules("events@platform:support=")
+	assert.Empty(t, p.For(&auth.AuthContext{Roles: []string{"platform:support"}}, Events))
+}
+
+func TestParseRules_SkipsMalformed(t *testing.T) {
+	p := ParseRules("orders=payload;dispatch-jobs=password;dispatch-jobs@=payload;events;dispatch-jobs=payload")
+	assert.Equal(t, []string{FieldPayload}, names(p.For(nil, DispatchJobs)))
+	assert.Empty(t, p.roles)
+}
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "https://hooks.example.com/[REDACTED]", URL("https://hooks.example.com/t/abc123?sig=x"))
+	assert.Equal(t, "http://10.0.0.5:8080/[REDACTED]", URL("http://10.0.0.5:8080/"))
+	assert.Equal(t, Mask, URL("not a url"))
+}
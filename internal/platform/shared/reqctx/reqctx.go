@@ -0,0 +1,177 @@
+// Package reqctx is the typed per-request context: who is calling, which
+// tenant the request acts for, and the identifiers that tie its writes
+// and logs together. Middleware builds it once per request (after the
+// Authenticator); handlers, use cases and repositories read it from the
+// context instead of re-deriving it.
+//
+// Two seams matter:
+//   - ExecutionContext is how write handlers build the
+//     usecase.ExecutionContext for usecaseop.Run, so every event and
+//     audit row carries the principal and the inbound correlation ID.
+//   - ClientScope is how list repositories scope their queries to the
+//     caller's tenants, so a new list endpoint is tenant-filtered without
+//     the handler having to remember it.
+//
+// Code outside a request (runners, the stream processor) has no Request
+// on its context and so sees no scoping.
+package reqctx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// DefaultLocale is used when the request names none.
+const DefaultLocale = "en"
+
+// Request is what the platform knows about the request being served.
+type Request struct {
+	// Principal is the authenticated caller; nil when unauthenticated.
+	Principal *auth.AuthContext
+	// ClientID is the tenant the request acts for when it doesn't name
+	// one: the impersonated client, else a non-anchor principal's first
+	// client (Rust create_event's default). Empty for anchors.
+	ClientID string
+	// RequestID is the chi request ID (X-Request-Id).
+	RequestID string
+	// CorrelationID is the inbound X-Correlation-ID, or the one generated
+	// for this request.
+	CorrelationID string
+	// Locale is the primary Accept-Language tag, lower-cased.
+	Locale string
+	// unscoped lifts ClientScope for platform-internal reads made while
+	// serving the request (see Unscoped).
+	unscoped bool
+}
+
+type ctxKey struct{}
+
+// With attaches r to ctx.
+func With(ctx context.Context, r *Request) context.Context {
+	return context.WithValue(ctx, ctxKey{}, r)
+}
+
+// From returns the request on ctx. Without one (the middleware didn't
+// run — tests, non-HTTP callers) it is derived from whatever
+// auth.AuthContext and correlation ID ctx carries. Never nil.
+func From(ctx context.Context) *Request {
+	if r, ok := ctx.Value(ctxKey{}).(*Request); ok && r != nil {
+		return r
+	}
+	return newRequest(ctx, auth.FromContext(ctx))
+}
+
+func newRequest(ctx context.Context, ac *auth.AuthContext) *Request {
+	return &Request{
+		Principal:     ac,
+		ClientID:      defaultClient(ac),
+		RequestID:     chimw.GetReqID(ctx),
+		CorrelationID: logging.CorrelationID(ctx),
+		Locale:        DefaultLocale,
+	}
+}
+
+func defaultClient(ac *auth.AuthContext) string {
+	switch {
+	case ac == nil:
+		return ""
+	case ac.Impersonating != "":
+		return ac.Impersonating
+	case !ac.IsAnchor() && len(ac.Clients) > 0:
+		return ac.Clients[0]
+	}
+	return ""
+}
+
+// Middleware builds the Request. Mount it after the Authenticator and
+// CorrelationID middleware so it sees both.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := newRequest(r.Context(), auth.FromContext(r.Context()))
+		req.Locale = parseLocale(r.Header.Get("Accept-Language"))
+		next.ServeHTTP(w, r.WithContext(With(r.Context(), req)))
+	})
+}
+
+// parseLocale returns the first language tag of an Accept-Language
+// header ("de-CH, de;q=0.9" → "de-ch"); "*" or nothing → DefaultLocale.
+func parseLocale(header string) string {
+	tag, _, _ := strings.Cut(header, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || tag == "*" {
+		return DefaultLocale
+	}
+	return tag
+}
+
+// PrincipalID is the caller's principal ID, or "" when unauthenticated.
+func (r *Request) PrincipalID() string {
+	if r.Principal == nil {
+		return ""
+	}
+	return r.Principal.PrincipalID
+}
+
+// ExecutionContext builds the usecase.ExecutionContext for a use case run
+// on behalf of the request: the caller as principal, the request's
+// correlation ID when it has one. Unauthenticated requests get an empty
+// principal — the use case's Authorize phase rejects that before anything
+// is committed.
+func (r *Request) ExecutionContext() usecase.ExecutionContext {
+	if r.CorrelationID == "" {
+		return usecase.NewExecutionContext(r.PrincipalID())
+	}
+	return usecase.WithCorrelation(r.PrincipalID(), r.CorrelationID)
+}
+
+// ExecutionContext is From(ctx).ExecutionContext(). It is the seam through
+// which write handlers derive the execution context for usecaseop.Run.
+func ExecutionContext(ctx context.Context) usecase.ExecutionContext {
+	return From(ctx).ExecutionContext()
+}
+
+// ClientScope returns the tenant scope for a list query made on ctx, in the
+// repositories' AccessibleClientIDs form: nil means unscoped, otherwise
+// rows must be platform-scoped or belong to one of the listed clients.
+// An explicit scope (non-nil) wins. Anchors, Unscoped contexts and
+// contexts without a caller are unscoped; any other caller — including an
+// unauthenticated one — is scoped to its own clients (none, for the
+// unauthenticated).
+func ClientScope(ctx context.Context, explicit *[]string) *[]string {
+	if explicit != nil {
+		return explicit
+	}
+	r, ok := ctx.Value(ctxKey{}).(*Request)
+	if !ok || r == nil {
+		r = From(ctx)
+		if r.Principal == nil {
+			return nil
+		}
+	}
+	if r.unscoped || r.Principal.IsAnchor() {
+		return nil
+	}
+	clients := []string{}
+	if r.Principal != nil {
+		clients = append(clients, r.Principal.Clients...)
+	}
+	return &clients
+}
+
+// Unscoped returns a ctx whose repository reads are not tenant-scoped, for
+// platform-internal lookups made while serving a request (the request's
+// principal and IDs are kept). Use sparingly: a handler's own list queries
+// must stay scoped.
+func Unscoped(ctx context.Context) context.Context {
+	r := *From(ctx)
+	r.unscoped = true
+	return With(ctx, &r)
+}
@@ -0,0 +1,81 @@
+package reqctx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+)
+
+func TestMiddleware_BuildsRequest(t *testing.T) {
+	var got *Request
+	h := chimw.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := auth.WithContext(r.Context(), &auth.AuthContext{PrincipalID: "prn_1", Scope: auth.ScopePartner, Clients: []string{"clt_a", "clt_b"}})
+		ctx = logging.WithCorrelationID(ctx, "corr-1")
+		Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = From(r.Context())
+		})).ServeHTTP(w, r.WithContext(ctx))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("Accept-Language", "de-CH, de;q=0.9, en;q=0.8")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "prn_1", got.PrincipalID())
+	assert.Equal(t, "clt_a", got.ClientID, "a non-anchor acts for its first client")
+	assert.Equal(t, "corr-1", got.CorrelationID)
+	assert.NotEmpty(t, got.RequestID)
+	assert.Equal(t, "de-ch", got.Locale)
+
+	ec := got.ExecutionContext()
+	assert.Equal(t, "prn_1", ec.PrincipalID)
+	assert.Equal(t, "corr-1", ec.CorrelationID, "the inbound correlation ID reaches the use case")
+	assert.NotEqual(t, "corr-1", ec.ExecutionID)
+}
+
+func TestParseLocale(t *testing.T) {
+	for header, want := range map[string]string{
+		"":               DefaultLocale,
+		"*":              DefaultLocale,
+		"fr":             "fr",
+		"en-GB;q=0.9,fr": "en-gb",
+	} {
+		assert.Equal(t, want, parseLocale(header), "Accept-Language %q", header)
+	}
+}
+
+func TestFrom_WithoutMiddleware(t *testing.T) {
+	r := From(context.Background())
+	require.NotNil(t, r)
+	assert.Empty(t, r.PrincipalID())
+	assert.NotEmpty(t, r.ExecutionContext().CorrelationID, "a fresh correlation ID without an inbound one")
+
+	ctx := auth.WithContext(context.Background(), &auth.AuthContext{PrincipalID: "prn_1", Scope: auth.ScopeClient, Clients: []string{"clt_a"}, Impersonating: "clt_a"})
+	r = From(ctx)
+	assert.Equal(t, "prn_1", r.PrincipalID())
+	assert.Equal(t, "clt_a", r.ClientID)
+}
+
+func TestClientScope(t *testing.T) {
+	anchor := auth.WithContext(context.Background(), &auth.AuthContext{Scope: auth.ScopeAnchor})
+	client := auth.WithContext(context.Background(), &auth.AuthContext{Scope: auth.ScopeClient, Clients: []string{"clt_a"}})
+
+	assert.Nil(t, ClientScope(context.Background(), nil), "no caller: unscoped")
+	assert.Nil(t, ClientScope(anchor, nil), "anchors are unscoped")
+	assert.Equal(t, &[]string{"clt_a"}, ClientScope(client, nil))
+	assert.Nil(t, ClientScope(Unscoped(client), nil))
+
+	explicit := []string{"clt_b"}
+	assert.Equal(t, &explicit, ClientScope(anchor, &explicit), "an explicit scope wins")
+
+	// Through the middleware an unauthenticated request sees no tenant's rows.
+	anon := With(context.Background(), newRequest(context.Background(), nil))
+	assert.Equal(t, &[]string{}, ClientScope(anon, nil))
+}
@@ -9,8 +9,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -36,6 +38,7 @@ type CreateDispatchJobRequest struct {
 	ClientID           *string           `json:"clientId,omitempty"`
 	SubscriptionID     *string           `json:"subscriptionId,omitempty"`
 	Mode               string            `json:"mode,omitempty"`
+	Priority           string            `json:"priority,omitempty"`
 	DispatchPoolID     *string           `json:"dispatchPoolId,omitempty"`
 	MessageGroup       *string           `json:"messageGroup,omitempty"`
 	Sequence           *int32            `json:"sequence,omitempty"`
@@ -45,6 +48,17 @@ type CreateDispatchJobRequest struct {
 	IdempotencyKey     *string           `json:"idempotencyKey,omitempty"`
 	ExternalID         *string           `json:"externalId,omitempty"`
 	Metadata           map[string]string `json:"metadata,omitempty"`
+
+	// Delayed dispatch: an absolute scheduledAt (RFC 3339, or a local
+	// date-time read in timezone) or a relative delaySeconds. The
+	// scheduler leaves the job PENDING until then. Go-native extension.
+	ScheduledAt  *string `json:"scheduledAt,omitempty"`
+	Timezone     *string `json:"timezone,omitempty"`
+	DelaySeconds *uint32 `json:"delaySeconds,omitempty"`
+
+	// StatusCallbackURL is POSTed a signed status notification once the
+	// job is terminal, so the producer need not poll. Go-native extension.
+	StatusCallbackURL *string `json:"statusCallbackUrl,omitempty"`
 }
 
 // CreatedResponse is the wire body for POST /api/dispatch-jobs: {id},
@@ -92,6 +106,13 @@ func (s *DispatchJobsBatchState) createOne(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	sched := dispatchjob.Schedule{ScheduledAt: req.ScheduledAt, Timezone: req.Timezone, DelaySeconds: req.DelaySeconds}
+	scheduledFor, err := sched.Resolve(time.Now().UTC())
+	if err != nil {
+		httperror.Write(w, httperror.BadRequest("VALIDATION", err.Error()))
+		return
+	}
+
 	// Delegate through the batch item mapping so the singular create and a
 	// batch-of-1 persist identically, then layer on the fields only the
 	// singular contract carries (retryStrategy, idempotencyKey, metadata map).
@@ -113,6 +134,7 @@ func (s *DispatchJobsBatchState) createOne(w http.ResponseWriter, r *http.Reques
 		DispatchPoolID:     req.DispatchPoolID,
 		MessageGroup:       req.MessageGroup,
 		Mode:               req.Mode,
+		Priority:           req.Priority,
 		TimeoutSeconds:     req.TimeoutSeconds,
 		MaxRetries:         req.MaxRetries,
 	})
@@ -121,9 +143,31 @@ func (s *DispatchJobsBatchState) createOne(w http.ResponseWriter, r *http.Reques
 		// honored (the batch's value-typed field coerces 0 → default 99).
 		j.Sequence = *req.Sequence
 	}
+	// A sandbox key's jobs stay in the sandbox.
+	j.Environment = ac.EnvironmentFor("")
 	j.RetryStrategy = dispatchjob.ParseRetryStrategy(req.RetryStrategy)
 	j.IdempotencyKey = req.IdempotencyKey
 	j.Metadata = metadataFromMap(req.Metadata)
+	j.ScheduledFor = scheduledFor
+	if tl := s.checkPayload(&j); tl != nil {
+		tl.Write(w)
+		return
+	}
+	reg, err := s.callbackFor(&j, req.StatusCallbackURL)
+	if err != nil {
+		httperror.Write(w, httperror.BadRequest("VALIDATION", err.Error()))
+		return
+	}
+	if err := s.claimCheck(r.Context(), &j, time.Now()); err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	if reg != nil {
+		if err := s.registerCallbacks(r.Context(), []callback.Registration{*reg}); err != nil {
+			httperror.Write(w, err)
+			return
+		}
+	}
 
 	if err := s.Repo.InsertBatch(r.Context(), []dispatchjob.DispatchJob{j}); err != nil {
 		httperror.Write(w, usecase.Internal("REPO", "insert failed", err))
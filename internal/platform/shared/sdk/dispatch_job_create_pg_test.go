@@ -16,6 +16,7 @@ import (
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
 )
@@ -225,3 +226,96 @@ func TestCreateDispatchJob_TenantGuard(t *testing.T) {
 	assert.Contains(t, body, `"error":"FORBIDDEN"`)
 	assert.Contains(t, body, "No access to client")
 }
+
+// TestCreateDispatchJob_StatusCallbackURL pins that statusCallbackUrl is
+// registered (singular and batch) when callbacks are enabled, and
+// rejected when they are not.
+func TestCreateDispatchJob_StatusCallbackURL(t *testing.T) {
+	const job = `"code": "it:singular:dispatch:callback",
+		"targetUrl": "https://target.test/hook",
+		"payload": "{}",
+		"serviceAccountId": "sa_dj_cb"`
+
+	disabled, _ := newIngestServer(t, anchorAC())
+	resp, body := postJSON(t, disabled.URL+"/api/dispatch-jobs", `{`+job+`, "statusCallbackUrl": "https://producer.test/cb"}`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+	assert.Contains(t, body, "not enabled")
+
+	pool := testpg.Pool(t)
+	callbacks := callback.NewRepository(pool)
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(auth.WithContext(req.Context(), anchorAC())))
+		})
+	})
+	RegisterRoutes(r, &DispatchJobsBatchState{Repo: dispatchjob.NewRepository(pool), Callbacks: callbacks})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	resp, body = postJSON(t, srv.URL+"/api/dispatch-jobs", `{`+job+`, "statusCallbackUrl": "ftp://producer.test/cb"}`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+
+	resp, body = postJSON(t, srv.URL+"/api/dispatch-jobs", `{`+job+`, "statusCallbackUrl": "https://producer.test/cb"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	var created struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &created))
+	c, err := callbacks.FindByJobID(context.Background(), created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, "https://producer.test/cb", c.URL)
+	assert.Equal(t, callback.StateWaiting, c.State)
+
+	resp, body = postJSON(t, srv.URL+"/api/dispatch-jobs/batch", `{"items": [
+		{"id": "djcbbatch01", "kind": "EVENT", `+job+`, "statusCallbackUrl": "https://producer.test/batch"},
+		{"id": "djcbbatch02", "kind": "EVENT", `+job+`}
+	]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	c, err = callbacks.FindByJobID(context.Background(), "djcbbatch01")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, "https://producer.test/batch", c.URL)
+	c, err = callbacks.FindByJobID(context.Background(), "djcbbatch02")
+	require.NoError(t, err)
+	assert.Nil(t, c, "an item without a URL registers nothing")
+}
+
+// TestStreamDispatchJobs_PersistsValidLines pins the NDJSON endpoint's
+// insert path: valid lines persist, an invalid line in the
+// middle fails alone, and every line gets a result in order.
+func TestStreamDispatchJobs_PersistsValidLines(t *testing.T) {
+	srv, repo := newIngestServer(t, anchorAC())
+	const job = `"code": "it:stream:dispatch", "targetUrl": "https://target.test/hook", "payload": "{}"`
+	body := strings.Join([]string{
+		`{"id": "djstream0001", ` + job + `}`,
+		`{"id": "djstream0002", "code": ""}`,
+		`{"id": "djstream0003", ` + job + `}`,
+	}, "\n")
+	resp, err := http.Post(srv.URL+"/api/dispatch-jobs/stream", "application/x-ndjson", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	dec := json.NewDecoder(resp.Body)
+	var results []StreamResultLine
+	for range 3 {
+		var res StreamResultLine
+		require.NoError(t, dec.Decode(&res))
+		results = append(results, res)
+	}
+	var sum StreamSummary
+	require.NoError(t, dec.Decode(&sum))
+
+	assert.Equal(t, StreamResultLine{Line: 1, ID: "djstream0001", Status: "SUCCESS"}, results[0])
+	assert.Equal(t, "BAD_REQUEST", results[1].Status)
+	assert.Equal(t, StreamResultLine{Line: 3, ID: "djstream0003", Status: "SUCCESS"}, results[2])
+	assert.Equal(t, StreamSummary{Done: true, Lines: 3, Succeeded: 2, Failed: 1}, sum)
+
+	for _, id := range []string{"djstream0001", "djstream0003"} {
+		j, err := repo.FindByID(context.Background(), id)
+		require.NoError(t, err)
+		require.NotNil(t, j, id)
+	}
+}
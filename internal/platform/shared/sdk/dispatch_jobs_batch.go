@@ -10,13 +10,19 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/payloadlimit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
@@ -26,6 +32,15 @@ import (
 // DispatchJobsBatchState bundles deps.
 type DispatchJobsBatchState struct {
 	Repo *dispatchjob.Repository
+	// Payloads enforces the per-client / per-event-type payload size
+	// limits (413). Optional: nil applies no limit beyond the body decode.
+	Payloads *payloadlimit.Offloader
+	// Callbacks registers statusCallbackUrl. Optional: nil (no signing
+	// secret configured) rejects items that ask for a callback.
+	Callbacks *callback.Repository
+	// Stream bounds POST /api/dispatch-jobs/stream. Optional: nil runs
+	// streams with DefaultStreamConfig and no concurrency cap.
+	Stream *StreamGate
 }
 
 // BatchItem is one row in the inbound batch.
@@ -48,10 +63,24 @@ type BatchItem struct {
 	DispatchPoolID     *string                `json:"dispatchPoolId,omitempty"`
 	MessageGroup       *string                `json:"messageGroup,omitempty"`
 	Mode               string                 `json:"mode,omitempty"`
+	Priority           string                 `json:"priority,omitempty"`
 	Sequence           int32                  `json:"sequence,omitempty"`
 	TimeoutSeconds     uint32                 `json:"timeoutSeconds,omitempty"`
 	MaxRetries         uint32                 `json:"maxRetries,omitempty"`
 	Metadata           []dispatchjob.Metadata `json:"metadata,omitempty"`
+	// StatusCallbackURL is POSTed a signed status notification once the
+	// job is terminal. Go-native extension.
+	StatusCallbackURL *string `json:"statusCallbackUrl,omitempty"`
+
+	// Delayed dispatch — see dispatchjob.Schedule. Omitted → dispatch now.
+	ScheduledAt  *string `json:"scheduledAt,omitempty"`
+	Timezone     *string `json:"timezone,omitempty"`
+	DelaySeconds *uint32 `json:"delaySeconds,omitempty"`
+}
+
+// schedule returns the item's delayed-dispatch request.
+func (it BatchItem) schedule() dispatchjob.Schedule {
+	return dispatchjob.Schedule{ScheduledAt: it.ScheduledAt, Timezone: it.Timezone, DelaySeconds: it.DelaySeconds}
 }
 
 // BatchRequest is the inbound POST shape.
@@ -75,10 +104,12 @@ type BatchResponse struct {
 }
 
 // RegisterRoutes mounts the SDK dispatch-job ingest endpoints:
-// POST /api/dispatch-jobs (singular) and /api/dispatch-jobs/batch.
+// POST /api/dispatch-jobs (singular), /api/dispatch-jobs/batch and the
+// NDJSON /api/dispatch-jobs/stream.
 func RegisterRoutes(r chi.Router, s *DispatchJobsBatchState) {
 	r.Post("/api/dispatch-jobs", s.createOne)
 	r.Post("/api/dispatch-jobs/batch", s.batchIngest)
+	r.Post("/api/dispatch-jobs/stream", s.streamIngest)
 }
 
 // jobFromItem maps one inbound ingest item to a DispatchJob with the
@@ -104,6 +135,7 @@ func jobFromItem(it BatchItem) dispatchjob.DispatchJob {
 		DispatchPoolID:     it.DispatchPoolID,
 		MessageGroup:       it.MessageGroup,
 		Mode:               common.ParseDispatchMode(it.Mode),
+		Priority:           common.ParsePriority(it.Priority),
 		Sequence:           defaultI32(it.Sequence, 99),
 		TimeoutSeconds:     defaultU32(it.TimeoutSeconds, 30),
 		MaxRetries:         defaultU32(it.MaxRetries, 3),
@@ -144,18 +176,49 @@ func (s *DispatchJobsBatchState) batchIngest(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	now := time.Now().UTC()
 	jobs := make([]dispatchjob.DispatchJob, 0, len(body.Items))
-	for _, it := range body.Items {
+	var regs []callback.Registration
+	for i, it := range body.Items {
 		j := jobFromItem(it)
+		j.Environment = ac.EnvironmentFor("")
 		// Tenant guard: SDK service accounts can only ingest for clients
 		// they have access to.
 		if j.ClientID != nil && !ac.CanAccessClient(*j.ClientID) {
 			httperror.Write(w, httperror.Forbidden("No access to client: "+*j.ClientID))
 			return
 		}
+		if tl := s.checkPayload(&j); tl != nil {
+			tl.At(i).Write(w)
+			return
+		}
+		at, err := it.schedule().Resolve(now)
+		if err != nil {
+			httperror.Write(w, httperror.BadRequest("VALIDATION", fmt.Sprintf("items[%d]: %v", i, err)))
+			return
+		}
+		j.ScheduledFor = at
+		reg, err := s.callbackFor(&j, it.StatusCallbackURL)
+		if err != nil {
+			httperror.Write(w, httperror.BadRequest("VALIDATION", fmt.Sprintf("items[%d]: %v", i, err)))
+			return
+		}
+		if reg != nil {
+			regs = append(regs, *reg)
+		}
+		if err := s.claimCheck(r.Context(), &j, now); err != nil {
+			httperror.Write(w, err)
+			return
+		}
 		jobs = append(jobs, j)
 	}
 
+	// Callbacks first: one registered for a job whose insert then fails
+	// just never fires, while the reverse order could lose a callback.
+	if err := s.registerCallbacks(r.Context(), regs); err != nil {
+		httperror.Write(w, err)
+		return
+	}
 	if err := s.Repo.InsertBatch(r.Context(), jobs); err != nil {
 		httperror.Write(w, usecase.Internal("REPO", "insert batch failed", err))
 		return
@@ -171,6 +234,55 @@ func (s *DispatchJobsBatchState) batchIngest(w http.ResponseWriter, r *http.Requ
 	_ = json.NewEncoder(w).Encode(BatchResponse{Results: results})
 }
 
+// checkPayload applies the size policy to a job's payload, keyed by its
+// client and code (the event type for event-kind jobs).
+func (s *DispatchJobsBatchState) checkPayload(j *dispatchjob.DispatchJob) *payloadlimit.TooLargeError {
+	if j.Payload == nil {
+		return nil
+	}
+	return s.Payloads.Check(j.ClientID, j.Code, len(*j.Payload))
+}
+
+// callbackFor validates a job's statusCallbackUrl and returns its
+// registration; nil when the job asked for none.
+func (s *DispatchJobsBatchState) callbackFor(j *dispatchjob.DispatchJob, rawURL *string) (*callback.Registration, error) {
+	if rawURL == nil || *rawURL == "" {
+		return nil, nil
+	}
+	if s.Callbacks == nil {
+		return nil, errors.New("statusCallbackUrl is not enabled on this deployment")
+	}
+	if err := callback.ValidateURL(*rawURL); err != nil {
+		return nil, err
+	}
+	return &callback.Registration{JobID: j.ID, URL: *rawURL, ClientID: j.ClientID}, nil
+}
+
+func (s *DispatchJobsBatchState) registerCallbacks(ctx context.Context, regs []callback.Registration) error {
+	if len(regs) == 0 {
+		return nil
+	}
+	if err := s.Callbacks.Register(ctx, regs); err != nil {
+		return usecase.Internal("REPO", "register status callbacks failed", err)
+	}
+	return nil
+}
+
+// claimCheck swaps a large payload for an object-storage reference before
+// insert (when claim-check is enabled); the processing callback fetches it
+// back at delivery time.
+func (s *DispatchJobsBatchState) claimCheck(ctx context.Context, j *dispatchjob.DispatchJob, now time.Time) error {
+	if j.Payload == nil {
+		return nil
+	}
+	payload, err := s.Payloads.OffloadJobPayload(ctx, j.ID, now, *j.Payload, j.PayloadContentType)
+	if err != nil {
+		return usecase.Internal("PAYLOAD_OFFLOAD_FAILED", "could not store dispatch job payload", err)
+	}
+	j.Payload = &payload
+	return nil
+}
+
 func defaultIfEmpty(v, fallback string) string {
 	if v == "" {
 		return fallback
@@ -0,0 +1,305 @@
+// dispatch_jobs_stream.go hosts POST /api/dispatch-jobs/stream — bulk
+// dispatch-job ingest as NDJSON, for producers (nightly notification
+// runs and the like) whose job count is far past the batch endpoint's
+// 1000-item cap.
+//
+// The body is one BatchItem per line. The handler reads ChunkSize lines,
+// validates them, inserts the valid ones in one round-trip and streams a
+// result line per input line back before it reads the next chunk, so
+// memory stays bounded by one chunk however long the body is. That is also
+// the backpressure: the body is only read as fast as chunks are inserted,
+// and a client that stops reading results stalls its own upload. A
+// per-instance cap on concurrent streams turns away the rest with 429.
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+)
+
+// StreamConfig holds the streaming-ingest knobs (env-overridable).
+type StreamConfig struct {
+	// ChunkSize is how many lines are validated and inserted together.
+	ChunkSize int
+	// MaxLineBytes caps one line; a longer one ends the stream.
+	MaxLineBytes int
+	// MaxConcurrent caps the streams one instance runs at once.
+	MaxConcurrent int
+}
+
+// DefaultStreamConfig is the configuration with no env overrides.
+func DefaultStreamConfig() StreamConfig {
+	return StreamConfig{ChunkSize: 500, MaxLineBytes: 4 << 20, MaxConcurrent: 4}
+}
+
+// StreamConfigFromEnv reads the FC_DISPATCH_STREAM_* knobs. ChunkSize is
+// capped at the batch endpoint's 1000.
+func StreamConfigFromEnv() StreamConfig {
+	cfg := DefaultStreamConfig()
+	if v := envutil.Int("FC_DISPATCH_STREAM_CHUNK_SIZE", cfg.ChunkSize); v > 0 {
+		cfg.ChunkSize = min(v, 1000)
+	}
+	if v := envutil.Int("FC_DISPATCH_STREAM_MAX_LINE_BYTES", cfg.MaxLineBytes); v > 0 {
+		cfg.MaxLineBytes = v
+	}
+	if v := envutil.Int("FC_DISPATCH_STREAM_MAX_CONCURRENT", cfg.MaxConcurrent); v > 0 {
+		cfg.MaxConcurrent = v
+	}
+	return cfg
+}
+
+// StreamGate carries the config and the concurrent-stream slots. A nil
+// gate uses DefaultStreamConfig without a concurrency cap.
+type StreamGate struct {
+	cfg   StreamConfig
+	slots chan struct{}
+}
+
+// NewStreamGate builds a gate for cfg.
+func NewStreamGate(cfg StreamConfig) *StreamGate {
+	return &StreamGate{cfg: cfg, slots: make(chan struct{}, cfg.MaxConcurrent)}
+}
+
+func (g *StreamGate) config() StreamConfig {
+	if g == nil {
+		return DefaultStreamConfig()
+	}
+	return g.cfg
+}
+
+// acquire takes a slot without waiting; false when all are in use.
+func (g *StreamGate) acquire() bool {
+	if g == nil {
+		return true
+	}
+	select {
+	case g.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (g *StreamGate) release() {
+	if g != nil {
+		<-g.slots
+	}
+}
+
+// StreamResultLine is one per non-blank input line. Line is the 1-based
+// line number in the request body; Status uses the batch endpoint's
+// vocabulary (SUCCESS, BAD_REQUEST, FORBIDDEN, INTERNAL_ERROR).
+type StreamResultLine struct {
+	Line   int    `json:"line"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StreamSummary is the last line of every response. Error is set when the
+// stream ended early (unreadable body, over-long line, client gone); lines
+// after Lines were not processed.
+type StreamSummary struct {
+	Done      bool   `json:"done"`
+	Lines     int    `json:"lines"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// streamLine is one read line: its number and either its item or why it
+// didn't parse.
+type streamLine struct {
+	n    int
+	item BatchItem
+	err  error
+}
+
+// lineReader yields the body's non-blank lines in chunks.
+type lineReader struct {
+	sc      *bufio.Scanner
+	maxLine int
+	n       int
+}
+
+func newLineReader(r io.Reader, maxLine int) *lineReader {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, min(64<<10, maxLine)), maxLine)
+	return &lineReader{sc: sc, maxLine: maxLine}
+}
+
+// next reads up to size non-blank lines. It returns fewer only at the end
+// of the body or with the error that stopped it.
+func (lr *lineReader) next(size int) ([]streamLine, error) {
+	var out []streamLine
+	for len(out) < size && lr.sc.Scan() {
+		lr.n++
+		raw := bytes.TrimSpace(lr.sc.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		l := streamLine{n: lr.n}
+		if err := json.Unmarshal(raw, &l.item); err != nil {
+			l.err = fmt.Errorf("invalid JSON: %v", err)
+		}
+		out = append(out, l)
+	}
+	if err := lr.sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return out, fmt.Errorf("line %d is longer than %d bytes", lr.n+1, lr.maxLine)
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+// validateStreamItem is the per-line check: the fields a job can't be
+// delivered without, plus the batch endpoint's tenant guard.
+func validateStreamItem(ac *auth.AuthContext, it BatchItem) (status, msg string) {
+	switch {
+	case it.Code == "":
+		return "BAD_REQUEST", "code is required"
+	case it.TargetURL == "":
+		return "BAD_REQUEST", "targetUrl is required"
+	case it.ClientID != nil && !ac.CanAccessClient(*it.ClientID):
+		return "FORBIDDEN", "No access to client: " + *it.ClientID
+	}
+	return "", ""
+}
+
+func (s *DispatchJobsBatchState) streamIngest(w http.ResponseWriter, r *http.Request) {
+	ac := auth.FromContext(r.Context())
+	if err := auth.CanWritePermission(ac, "WRITE_DISPATCH_JOBS"); err != nil {
+		httperror.Write(w, err)
+		return
+	}
+	if !s.Stream.acquire() {
+		ratelimit.WriteTooManyRequests(w, 30, "too many concurrent dispatch-job streams on this instance")
+		return
+	}
+	defer s.Stream.release()
+	cfg := s.Stream.config()
+
+	// Results go out while the body is still being read; HTTP/1.x needs
+	// that asked for explicitly (HTTP/2 is always full duplex).
+	rc := http.NewResponseController(w)
+	_ = rc.EnableFullDuplex()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+
+	lr := newLineReader(r.Body, cfg.MaxLineBytes)
+	var sum StreamSummary
+	for {
+		lines, readErr := lr.next(cfg.ChunkSize)
+		for _, res := range s.ingestChunk(r.Context(), ac, lines) {
+			sum.Lines++
+			if res.Status == "SUCCESS" {
+				sum.Succeeded++
+			} else {
+				sum.Failed++
+			}
+			if err := enc.Encode(res); err != nil {
+				return // client gone
+			}
+		}
+		if readErr != nil {
+			sum.Error = readErr.Error()
+			break
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		if len(lines) < cfg.ChunkSize {
+			sum.Done = true
+			break
+		}
+	}
+	_ = enc.Encode(sum)
+	_ = rc.Flush()
+}
+
+// ingestChunk validates one chunk, inserts its valid lines in a single
+// batch and returns a result per line, in order. A failed insert fails
+// every line that reached it.
+func (s *DispatchJobsBatchState) ingestChunk(ctx context.Context, ac *auth.AuthContext, lines []streamLine) []StreamResultLine {
+	if len(lines) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	results := make([]StreamResultLine, len(lines))
+	jobs := make([]dispatchjob.DispatchJob, 0, len(lines))
+	pending := make([]int, 0, len(lines)) // results index per job
+	var regs []callback.Registration
+	for i, l := range lines {
+		res := &results[i]
+		res.Line = l.n
+		if l.err != nil {
+			res.Status, res.Error = "BAD_REQUEST", l.err.Error()
+			continue
+		}
+		if status, msg := validateStreamItem(ac, l.item); status != "" {
+			res.Status, res.Error = status, msg
+			continue
+		}
+		j := jobFromItem(l.item)
+		j.Environment = ac.EnvironmentFor("")
+		res.ID = j.ID
+		if tl := s.checkPayload(&j); tl != nil {
+			res.Status, res.Error = "BAD_REQUEST", tl.Message
+			continue
+		}
+		at, err := l.item.schedule().Resolve(now)
+		if err != nil {
+			res.Status, res.Error = "BAD_REQUEST", err.Error()
+			continue
+		}
+		j.ScheduledFor = at
+		reg, err := s.callbackFor(&j, l.item.StatusCallbackURL)
+		if err != nil {
+			res.Status, res.Error = "BAD_REQUEST", err.Error()
+			continue
+		}
+		if err := s.claimCheck(ctx, &j, now); err != nil {
+			res.Status, res.Error = "INTERNAL_ERROR", err.Error()
+			continue
+		}
+		if reg != nil {
+			regs = append(regs, *reg)
+		}
+		jobs = append(jobs, j)
+		pending = append(pending, i)
+	}
+
+	// Same order as the batch endpoint: callbacks before jobs.
+	err := s.registerCallbacks(ctx, regs)
+	if err == nil {
+		err = s.Repo.InsertBatch(ctx, jobs)
+	}
+	if err != nil {
+		slog.Warn("dispatch-job stream: chunk insert failed", "lines", len(pending), "err", err)
+	}
+	for _, i := range pending {
+		if err != nil {
+			results[i].Status, results[i].Error = "INTERNAL_ERROR", "insert failed"
+		} else {
+			results[i].Status = "SUCCESS"
+		}
+	}
+	return results
+}
@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+)
+
+// streamServer mounts RegisterRoutes with no repository: only lines that
+// fail validation (which never reach an insert) are exercised here; the
+// insert path is covered by the integration suite.
+func streamServer(ac *auth.AuthContext, gate *StreamGate) http.Handler {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(auth.WithContext(req.Context(), ac)))
+		})
+	})
+	RegisterRoutes(r, &DispatchJobsBatchState{Stream: gate})
+	return r
+}
+
+func clientAC() *auth.AuthContext {
+	return &auth.AuthContext{
+		PrincipalID: "p_stream_test",
+		Scope:       auth.ScopeClient,
+		Clients:     []string{"clt_a"},
+		Permissions: []string{"WRITE_DISPATCH_JOBS"},
+	}
+}
+
+func TestStreamIngest_PerLineResultsAndSummary(t *testing.T) {
+	body := strings.Join([]string{
+		`{not json`,
+		``,
+		`{"code":"c","targetUrl":""}`,
+		`{"code":"c","targetUrl":"https://t.test","clientId":"clt_b"}`,
+		`{"code":"c","targetUrl":"https://t.test","delaySeconds":99999999}`,
+	}, "\n")
+	cfg := DefaultStreamConfig()
+	cfg.ChunkSize = 2
+	rec := httptest.NewRecorder()
+	streamServer(clientAC(), NewStreamGate(cfg)).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodPost, "/api/dispatch-jobs/stream", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	sc := bufio.NewScanner(rec.Body)
+	var results []StreamResultLine
+	var sum StreamSummary
+	for sc.Scan() {
+		if strings.Contains(sc.Text(), `"done"`) {
+			if err := json.Unmarshal(sc.Bytes(), &sum); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		var res StreamResultLine
+		if err := json.Unmarshal(sc.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res)
+	}
+
+	want := []struct {
+		line   int
+		status string
+	}{{1, "BAD_REQUEST"}, {3, "BAD_REQUEST"}, {4, "FORBIDDEN"}, {5, "BAD_REQUEST"}}
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v, want %d lines", results, len(want))
+	}
+	for i, w := range want {
+		if results[i].Line != w.line || results[i].Status != w.status || results[i].Error == "" {
+			t.Errorf("result %d = %+v, want line %d %s with an error", i, results[i], w.line, w.status)
+		}
+	}
+	if !sum.Done || sum.Lines != 4 || sum.Failed != 4 || sum.Succeeded != 0 || sum.Error != "" {
+		t.Errorf("summary = %+v", sum)
+	}
+}
+
+func TestStreamIngest_OverlongLineEndsStream(t *testing.T) {
+	cfg := DefaultStreamConfig()
+	cfg.MaxLineBytes = 64
+	rec := httptest.NewRecorder()
+	body := `{"code":"c"}` + "\n" + `{"code":"` + strings.Repeat("x", 100) + `"}` + "\n" + `{"code":"c"}`
+	streamServer(clientAC(), NewStreamGate(cfg)).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodPost, "/api/dispatch-jobs/stream", strings.NewReader(body)))
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	var sum StreamSummary
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &sum); err != nil {
+		t.Fatal(err)
+	}
+	if sum.Done || sum.Lines != 1 || !strings.Contains(sum.Error, "line 2") {
+		t.Fatalf("summary = %+v, want the stream stopped at line 2", sum)
+	}
+}
+
+func TestStreamIngest_ConcurrencyCapAndPermission(t *testing.T) {
+	cfg := DefaultStreamConfig()
+	cfg.MaxConcurrent = 1
+	gate := NewStreamGate(cfg)
+	if !gate.acquire() {
+		t.Fatal("first acquire refused")
+	}
+	rec := httptest.NewRecorder()
+	streamServer(clientAC(), gate).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodPost, "/api/dispatch-jobs/stream", strings.NewReader("")))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("full gate: status %d, Retry-After %q; want 429 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	gate.release()
+
+	noPerm := clientAC()
+	noPerm.Permissions = nil
+	rec = httptest.NewRecorder()
+	streamServer(noPerm, gate).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodPost, "/api/dispatch-jobs/stream", strings.NewReader("")))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("no permission: status %d, want 403", rec.Code)
+	}
+}
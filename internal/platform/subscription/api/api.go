@@ -3,14 +3,21 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/notify"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apiroute"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -20,8 +27,16 @@ import (
 
 // State bundles the dependencies.
 type State struct {
-	Repo *subscription.Repository
-	UoW  *usecasepgx.UnitOfWork
+	Repo    *subscription.Repository
+	TLSRepo *subscription.TargetTLSRepository
+	UoW     *usecasepgx.UnitOfWork
+
+	// A subscription created PENDING_APPROVAL emails the client's
+	// approvers (found via Approvers) a link under ExternalBaseURL. All
+	// optional: without them nobody is notified.
+	Approvers       ApproverFinder
+	Notifier        *notify.Notifier
+	ExternalBaseURL string
 }
 
 const tag = "subscriptions"
@@ -31,16 +46,32 @@ func Register(api huma.API, s *State) {
 	g := apiroute.New(api, tag)
 	apiroute.Get(g, "listSubscriptions", "/api/subscriptions", "List subscriptions", s.list)
 	apiroute.Post(g, "createSubscription", "/api/subscriptions", "Create a subscription", http.StatusCreated, s.create)
+	apiroute.Post(g, "bulkSubscriptions", "/api/subscriptions/bulk", "Pause, resume or delete many subscriptions by id or filter", http.StatusOK, s.bulk)
+	apiroute.Post(g, "previewSubscriptionTransform", "/api/subscriptions/transform-preview", "Render a payload transform against a sample event", http.StatusOK, s.previewTransform)
 	apiroute.Get(g, "getSubscription", "/api/subscriptions/{id}", "Get a subscription by id", s.getByID)
 	apiroute.Put(g, "updateSubscription", "/api/subscriptions/{id}", "Update a subscription", http.StatusNoContent, s.update)
 	apiroute.Delete(g, "deleteSubscription", "/api/subscriptions/{id}", "Delete a subscription", http.StatusNoContent, s.delete)
 	apiroute.Post(g, "pauseSubscription", "/api/subscriptions/{id}/pause", "Pause a subscription", http.StatusNoContent, s.pause)
 	apiroute.Post(g, "resumeSubscription", "/api/subscriptions/{id}/resume", "Resume a subscription", http.StatusNoContent, s.resume)
+	apiroute.Post(g, "approveSubscription", "/api/subscriptions/{id}/approve", "Approve a subscription pending approval", http.StatusNoContent, s.approve)
+	apiroute.Post(g, "rejectSubscription", "/api/subscriptions/{id}/reject", "Reject a subscription pending approval", http.StatusNoContent, s.reject)
+	apiroute.Get(g, "listSubscriptionApprovals", "/api/subscriptions/{id}/approvals", "List a subscription's approval decisions", s.listApprovals)
+	apiroute.Get(g, "listExpiringSubscriptionTargetTLS", "/api/subscriptions/target-tls/expiring", "List subscriptions whose client certificate or CA bundle is expiring", s.expiringTargetTLS)
+	apiroute.Get(g, "getSubscriptionTargetTLS", "/api/subscriptions/{id}/target-tls", "Get a subscription's TLS certificate metadata", s.getTargetTLS)
+	apiroute.Put(g, "setSubscriptionTargetTLS", "/api/subscriptions/{id}/target-tls", "Upload a subscription's client certificate and/or CA bundle", http.StatusOK, s.setTargetTLS)
+	apiroute.Delete(g, "clearSubscriptionTargetTLS", "/api/subscriptions/{id}/target-tls", "Remove a subscription's TLS material", http.StatusNoContent, s.clearTargetTLS)
+
+	apiroute.Get(g, "listSubscriptionConfigSchemas", "/api/subscription-config-schemas", "List subscription config schemas", s.listSchemas)
+	apiroute.Put(g, "setSubscriptionConfigSchema", "/api/subscription-config-schemas", "Create or replace the config schema for a scope", http.StatusOK, s.setSchema)
+	apiroute.Get(g, "resolveSubscriptionConfigSchema", "/api/subscription-config-schemas/resolve", "Get the config schema governing an application's subscriptions", s.resolveSchema)
+	apiroute.Delete(g, "deleteSubscriptionConfigSchema", "/api/subscription-config-schemas/{id}", "Delete a subscription config schema", http.StatusNoContent, s.deleteSchema)
+	apiroute.Get(g, "subscriptionConfigSchemaConformance", "/api/subscription-config-schemas/{id}/conformance", "Report governed subscriptions whose config the schema would reject or rewrite", s.schemaConformance)
 }
 
 type listInput struct {
-	Status   string `query:"status"`
-	ClientID string `query:"clientId"`
+	Status      string `query:"status"`
+	ClientID    string `query:"clientId"`
+	Environment string `query:"environment" enum:"SANDBOX,PRODUCTION" doc:"Only subscriptions in this environment; a key pinned to an environment always sees just its own"`
 }
 
 func (s *State) list(ctx context.Context, in *listInput) (*apicommon.Out[SubscriptionListResponse], error) {
@@ -50,11 +81,14 @@ func (s *State) list(ctx context.Context, in *listInput) (*apicommon.Out[Subscri
 	}
 	status := apicommon.OptStr(in.Status)
 	clientID := apicommon.OptStr(in.ClientID)
-	rows, err := s.Repo.FindWithFilters(ctx, status, clientID)
+	rows, err := s.Repo.FindWithFilters(ctx, status, clientID, environmentFilter(ac, in.Environment))
 	if err != nil {
 		return nil, usecase.Internal("REPO", "find_with_filters failed", err)
 	}
 	visible := auth.FilterClientScoped(ac, rows, func(sub *subscription.Subscription) *string { return sub.ClientID })
+	if err := s.maskSecrets(ctx, visible); err != nil {
+		return nil, err
+	}
 	out := apicommon.MapSlice(visible, fromEntity)
 	return &apicommon.Out[SubscriptionListResponse]{Body: SubscriptionListResponse{Subscriptions: out, Total: len(out)}}, nil
 }
@@ -71,24 +105,40 @@ func (s *State) getByID(ctx context.Context, in *apicommon.IDInput) (*apicommon.
 	if sub == nil {
 		return nil, httperror.NotFound("Subscription", in.ID)
 	}
-	if sub.ClientID != nil && !ac.CanAccessClient(*sub.ClientID) {
+	if (sub.ClientID != nil && !ac.CanAccessClient(*sub.ClientID)) || !ac.CanAccessEnvironment(sub.Environment) {
 		return nil, httperror.Forbidden("No access to this subscription")
 	}
-	return &apicommon.Out[SubscriptionResponse]{Body: fromEntity(sub)}, nil
+	one := []subscription.Subscription{*sub}
+	if err := s.maskSecrets(ctx, one); err != nil {
+		return nil, err
+	}
+	return &apicommon.Out[SubscriptionResponse]{Body: fromEntity(&one[0])}, nil
 }
 
 func (s *State) create(ctx context.Context, in *apicommon.In[CreateSubscriptionRequest]) (*apicommon.Out[apicommon.CreatedResponse], error) {
 	// Coarse write permission at the controller; the use case enforces per-client
 	// resource access on the requested clientId (you may only bind a subscription
 	// to a client you can access; platform-wide requires anchor).
-	if err := auth.CanWriteSubscriptions(auth.FromContext(ctx)); err != nil {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWriteSubscriptions(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
-	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateSubscription(s.Repo), in.Body.toCommand(), ec)
+	ec := reqctx.ExecutionContext(ctx)
+	cmd := in.Body.toCommand()
+	if cmd.AllowPrivateTarget != nil && *cmd.AllowPrivateTarget {
+		if err := auth.CanOverrideEgress(ac); err != nil {
+			return nil, err
+		}
+	}
+	cmd.Approver = auth.CanApproveSubscriptions(ac) == nil
+	cmd.PlatformAWS = ac.IsAnchor()
+	event, err := usecaseop.Run(ctx, s.UoW, operations.CreateSubscription(s.Repo), cmd, ec)
 	if err != nil {
 		return nil, err
 	}
+	if event.Status == string(subscription.StatusPendingApproval) {
+		s.notifyApprovers(ctx, cmd.ClientID, event.SubscriptionID, event.Code)
+	}
 	return &apicommon.Out[apicommon.CreatedResponse]{Body: apicommon.CreatedResponse{ID: event.SubscriptionID}}, nil
 }
 
@@ -98,13 +148,24 @@ type updateInput struct {
 }
 
 func (s *State) update(ctx context.Context, in *updateInput) (*apicommon.Empty, error) {
-	if err := auth.CanWriteSubscriptions(auth.FromContext(ctx)); err != nil {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanWriteSubscriptions(ac); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
-	if _, err := usecaseop.Run(ctx, s.UoW, operations.UpdateSubscription(s.Repo), in.Body.toCommand(in.ID), ec); err != nil {
+	ec := reqctx.ExecutionContext(ctx)
+	cmd := in.Body.toCommand(in.ID)
+	// Whether the egress override may be turned on; the use case only asks
+	// when it would be.
+	cmd.MayOverrideEgress = auth.CanOverrideEgress(ac) == nil
+	cmd.PlatformAWS = ac.IsAnchor()
+	cmd.Approver = auth.CanApproveSubscriptions(ac) == nil
+	event, err := usecaseop.Run(ctx, s.UoW, operations.UpdateSubscription(s.Repo), cmd, ec)
+	if err != nil {
 		return nil, err
 	}
+	if event.SentForApproval {
+		s.notifyApprovers(ctx, event.ClientID, event.SubscriptionID, event.Code)
+	}
 	return &apicommon.Empty{}, nil
 }
 
@@ -112,7 +173,7 @@ func (s *State) delete(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanDeleteSubscriptions(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteSubscription(s.Repo), operations.DeleteCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -123,7 +184,7 @@ func (s *State) pause(ctx context.Context, in *apicommon.IDInput) (*apicommon.Em
 	if err := auth.CanWriteSubscriptions(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.PauseSubscription(s.Repo), operations.PauseCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
@@ -134,9 +195,161 @@ func (s *State) resume(ctx context.Context, in *apicommon.IDInput) (*apicommon.E
 	if err := auth.CanWriteSubscriptions(auth.FromContext(ctx)); err != nil {
 		return nil, err
 	}
-	ec := auth.NewExecutionContext(ctx)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.ResumeSubscription(s.Repo), operations.ResumeCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
 	}
 	return &apicommon.Empty{}, nil
 }
+
+// maskSecrets blanks secret custom-config values in place, per the schema
+// governing each subscription. Secrets are write-only on the wire.
+func (s *State) maskSecrets(ctx context.Context, subs []subscription.Subscription) error {
+	schemas, err := s.Repo.ConfigSchemas().FindAll(ctx)
+	if err != nil {
+		return usecase.Internal("REPO", "find config schemas failed", err)
+	}
+	for i := range subs {
+		schema := subscription.Governing(schemas, subs[i].ApplicationCode, common.MediationTypeHTTP)
+		subs[i].CustomConfig = schema.Mask(subs[i].CustomConfig)
+	}
+	return nil
+}
+
+// previewTransform dry-runs a payload transform: nothing is stored and no
+// delivery is made. Compile errors are 400s, like on save; render errors
+// come back in the body.
+func (s *State) previewTransform(ctx context.Context, in *apicommon.In[TransformPreviewRequest]) (*apicommon.Out[TransformPreviewResponse], error) {
+	if err := auth.CanReadSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	t := subscription.NewPayloadTransform(in.Body.Transform.Template, in.Body.Transform.ContentType)
+	if in.Body.Transform.Engine != "" {
+		t.Engine = payloadtransform.Engine(strings.ToUpper(in.Body.Transform.Engine))
+	}
+	p, err := t.Compile()
+	if err != nil {
+		return nil, usecase.Validation("INVALID_TRANSFORM", fmt.Sprintf("invalid transform: %v", err))
+	}
+	out := TransformPreviewResponse{ContentType: p.ContentType()}
+	body, err := p.Render(in.Body.Event.envelope())
+	if err != nil {
+		msg := err.Error()
+		out.Error = &msg
+	} else {
+		out.Body = string(body)
+	}
+	return &apicommon.Out[TransformPreviewResponse]{Body: out}, nil
+}
+
+// ── Config schemas ───────────────────────────────────────────────────────
+
+func (s *State) listSchemas(ctx context.Context, _ *struct{}) (*apicommon.Out[ConfigSchemaListResponse], error) {
+	if err := auth.CanReadSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.ConfigSchemas().FindAll(ctx)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find config schemas failed", err)
+	}
+	return &apicommon.Out[ConfigSchemaListResponse]{Body: ConfigSchemaListResponse{
+		Schemas: apicommon.MapSlice(rows, configSchemaFromEntity),
+	}}, nil
+}
+
+func (s *State) setSchema(ctx context.Context, in *apicommon.In[SetConfigSchemaRequest]) (*apicommon.Out[apicommon.CreatedResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	event, err := usecaseop.Run(ctx, s.UoW, operations.SetConfigSchema(s.Repo.ConfigSchemas()), in.Body.toCommand(), ec)
+	if err != nil {
+		return nil, err
+	}
+	return &apicommon.Out[apicommon.CreatedResponse]{Body: apicommon.CreatedResponse{ID: event.SchemaID}}, nil
+}
+
+type resolveSchemaInput struct {
+	ApplicationCode string `query:"applicationCode" doc:"Omit for UI-authored subscriptions"`
+}
+
+// resolveSchema returns the schema the subscription form should render.
+// 404 means no schema applies and config is free-form.
+func (s *State) resolveSchema(ctx context.Context, in *resolveSchemaInput) (*apicommon.Out[ConfigSchemaResponse], error) {
+	if err := auth.CanReadSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	c, err := s.Repo.ConfigSchemas().Resolve(ctx, apicommon.OptStr(in.ApplicationCode), common.MediationTypeHTTP)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "resolve config schema failed", err)
+	}
+	if c == nil {
+		return nil, httperror.NotFound("SubscriptionConfigSchema", in.ApplicationCode)
+	}
+	return &apicommon.Out[ConfigSchemaResponse]{Body: configSchemaFromEntity(c)}, nil
+}
+
+func (s *State) deleteSchema(ctx context.Context, in *apicommon.IDInput) (*apicommon.Empty, error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.DeleteConfigSchema(s.Repo.ConfigSchemas()), operations.DeleteConfigSchemaCommand{ID: in.ID}, ec); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
+
+// schemaConformance is the migration report for existing entries: every
+// subscription the schema governs whose stored config would fail, or be
+// rewritten, on its next save. Anchor only — it spans every tenant.
+func (s *State) schemaConformance(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[ConfigConformanceResponse], error) {
+	if err := auth.RequireAnchor(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	schemas, err := s.Repo.ConfigSchemas().FindAll(ctx)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find config schemas failed", err)
+	}
+	subs, err := s.Repo.FindAll(ctx)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_all failed", err)
+	}
+	out := ConfigConformanceResponse{SchemaID: in.ID, Items: []ConfigConformanceItem{}}
+	found := false
+	for i := range schemas {
+		found = found || schemas[i].ID == in.ID
+	}
+	if !found {
+		return nil, httperror.NotFound("SubscriptionConfigSchema", in.ID)
+	}
+	for i := range subs {
+		sub := &subs[i]
+		schema := subscription.Governing(schemas, sub.ApplicationCode, common.MediationTypeHTTP)
+		if schema == nil || schema.ID != in.ID {
+			continue
+		}
+		out.Governed++
+		normalised, err := schema.Apply(sub.CustomConfig)
+		switch {
+		case err != nil:
+			msg := err.Error()
+			out.Items = append(out.Items, ConfigConformanceItem{SubscriptionID: sub.ID, Code: sub.Code, Error: &msg})
+		case !slices.Equal(normalised, sub.CustomConfig):
+			out.Items = append(out.Items, ConfigConformanceItem{SubscriptionID: sub.ID, Code: sub.Code, Changes: true})
+		default:
+			out.Conforming++
+		}
+	}
+	return &apicommon.Out[ConfigConformanceResponse]{Body: out}, nil
+}
+
+// environmentFilter is the environment a listing narrows to: the pinned
+// one for an environment-pinned key, else the requested one (nil for all).
+func environmentFilter(ac *auth.AuthContext, requested string) *string {
+	if ac != nil && ac.Environment != "" {
+		env := string(ac.Environment)
+		return &env
+	}
+	return apicommon.OptStr(requested)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/jsontime"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// ApproverFinder resolves who may approve a client's subscriptions.
+type ApproverFinder interface {
+	FindClientEmailsWithPermission(ctx context.Context, clientID, permission string) ([]string, error)
+}
+
+type approveInput struct {
+	ID   string `path:"id"`
+	Body *SubscriptionDecisionRequest
+}
+
+func (s *State) approve(ctx context.Context, in *approveInput) (*apicommon.Empty, error) {
+	// Coarse approve permission here; the use case checks access to the
+	// subscription's client once it's loaded.
+	if err := auth.CanApproveSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	cmd := operations.DecideCommand{ID: in.ID}
+	if in.Body != nil {
+		cmd.Reason = in.Body.Reason
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.ApproveSubscription(s.Repo), cmd, ec); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
+
+type rejectInput struct {
+	ID   string `path:"id"`
+	Body SubscriptionDecisionRequest
+}
+
+func (s *State) reject(ctx context.Context, in *rejectInput) (*apicommon.Empty, error) {
+	if err := auth.CanApproveSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	ec := reqctx.ExecutionContext(ctx)
+	cmd := operations.DecideCommand{ID: in.ID, Reason: in.Body.Reason}
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.RejectSubscription(s.Repo), cmd, ec); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
+
+// listApprovals returns a subscription's approval decisions, newest first.
+func (s *State) listApprovals(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[SubscriptionApprovalListResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanReadSubscriptions(ac); err != nil {
+		return nil, err
+	}
+	sub, err := s.Repo.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if sub == nil {
+		return nil, httperror.NotFound("Subscription", in.ID)
+	}
+	if (sub.ClientID != nil && !ac.CanAccessClient(*sub.ClientID)) || !ac.CanAccessEnvironment(sub.Environment) {
+		return nil, httperror.Forbidden("No access to this subscription")
+	}
+	decisions, err := s.Repo.Approvals().FindBySubscription(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find approvals failed", err)
+	}
+	out := make([]SubscriptionApprovalResponse, 0, len(decisions))
+	for _, d := range decisions {
+		out = append(out, SubscriptionApprovalResponse{
+			ID:        d.ID,
+			Decision:  string(d.Decision),
+			DecidedBy: d.DecidedBy,
+			Reason:    d.Reason,
+			DecidedAt: jsontime.New(d.DecidedAt),
+		})
+	}
+	return &apicommon.Out[SubscriptionApprovalListResponse]{Body: SubscriptionApprovalListResponse{
+		Status:    string(sub.Status),
+		Approvals: out,
+		Total:     len(out),
+	}}, nil
+}
+
+// notifyApprovers emails the client's approvers about a subscription now
+// pending approval. Best-effort: a failed lookup is logged, not returned.
+func (s *State) notifyApprovers(ctx context.Context, clientID *string, id, code string) {
+	if s.Approvers == nil || s.Notifier == nil || clientID == nil {
+		return
+	}
+	approvers, err := s.Approvers.FindClientEmailsWithPermission(ctx, *clientID, auth.SubscriptionApprovePermission)
+	if err != nil {
+		slog.Warn("subscription approval: find approvers failed", "subscription_id", id, "err", err)
+		return
+	}
+	link := strings.TrimRight(s.ExternalBaseURL, "/") + "/subscriptions/" + id
+	for _, addr := range approvers {
+		s.Notifier.SubscriptionApprovalNeeded(ctx, addr, code, link)
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// maxBulkSubscriptions caps one bulk call. A filter matching more than
+// this is rejected rather than truncated, so the caller never gets a
+// half-applied outage response without noticing.
+const maxBulkSubscriptions = 500
+
+// bulk pauses, resumes or deletes many subscriptions at once — selected by
+// id or by filter (e.g. every subscription targeting a failing host). Each
+// subscription goes through the single-item operation in its own unit of
+// work, so every change emits its usual domain event and one failure
+// doesn't roll back the rest. DryRun returns the selection untouched.
+func (s *State) bulk(ctx context.Context, in *apicommon.In[BulkSubscriptionRequest]) (*apicommon.Out[BulkSubscriptionResponse], error) {
+	ac := auth.FromContext(ctx)
+	action := strings.ToUpper(strings.TrimSpace(in.Body.Action))
+	switch action {
+	case "DELETE":
+		if err := auth.CanDeleteSubscriptions(ac); err != nil {
+			return nil, err
+		}
+	case "PAUSE", "RESUME":
+		if err := auth.CanWriteSubscriptions(ac); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, httperror.BadRequest("INVALID_ACTION", "action must be PAUSE, RESUME or DELETE")
+	}
+
+	out := BulkSubscriptionResponse{Action: action, DryRun: in.Body.DryRun, Results: []BulkSubscriptionResult{}}
+	targets, err := s.bulkSelect(ctx, ac, in.Body, &out)
+	if err != nil {
+		return nil, err
+	}
+	out.Matched = len(targets)
+
+	ec := reqctx.ExecutionContext(ctx)
+	for i := range targets {
+		sub := &targets[i]
+		r := BulkSubscriptionResult{ID: sub.ID, Code: sub.Code, ClientID: sub.ClientID, Endpoint: sub.Endpoint}
+		switch {
+		case in.Body.DryRun:
+			r.Status = "matched"
+		case action == "PAUSE" && sub.Status == subscription.StatusPaused,
+			action == "RESUME" && sub.Status == subscription.StatusActive:
+			r.Status = "unchanged"
+			out.Unchanged++
+		default:
+			if err := s.applyBulk(ctx, action, sub.ID, ec); err != nil {
+				r.Status, r.Message = "error", bulkErrMessage(err)
+				out.Failed++
+			} else {
+				r.Status = "applied"
+				out.Applied++
+			}
+		}
+		out.Results = append(out.Results, r)
+	}
+	return &apicommon.Out[BulkSubscriptionResponse]{Body: out}, nil
+}
+
+// bulkSelect resolves the request to the subscriptions the caller may act
+// on. Explicit ids that are unknown or out of scope are reported as
+// per-item errors on out; filter matches outside the caller's scope are
+// silently excluded, as in the list endpoint.
+func (s *State) bulkSelect(ctx context.Context, ac *auth.AuthContext, req BulkSubscriptionRequest, out *BulkSubscriptionResponse) ([]subscription.Subscription, error) {
+	switch {
+	case len(req.IDs) > 0 && req.Filter != nil:
+		return nil, httperror.BadRequest("INVALID_SELECTION", "ids and filter are mutually exclusive")
+	case len(req.IDs) > 0:
+		if len(req.IDs) > maxBulkSubscriptions {
+			return nil, httperror.BadRequest("TOO_MANY", "Bulk operations are limited to 500 subscriptions at a time")
+		}
+		var subs []subscription.Subscription
+		seen := make(map[string]struct{}, len(req.IDs))
+		for _, id := range req.IDs {
+			id = strings.TrimSpace(id)
+			if _, dup := seen[id]; dup || id == "" {
+				continue
+			}
+			seen[id] = struct{}{}
+			sub, err := s.Repo.FindByID(ctx, id)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if sub == nil {
+				out.Failed++
+				out.Results = append(out.Results, BulkSubscriptionResult{ID: id, Status: "error", Message: "subscription not found"})
+				continue
+			}
+			err = auth.CheckScopeAccess(ac, sub.ClientID)
+			if err == nil {
+				err = auth.CheckEnvironmentAccess(ac, sub.Environment)
+			}
+			if err != nil {
+				out.Failed++
+				out.Results = append(out.Results, BulkSubscriptionResult{ID: id, Status: "error", Message: bulkErrMessage(err)})
+				continue
+			}
+			subs = append(subs, *sub)
+		}
+		return subs, nil
+	case req.Filter != nil:
+		f := req.Filter.toEntity()
+		if f.Empty() {
+			return nil, httperror.BadRequest("FILTER_REQUIRED", "filter needs at least one criterion")
+		}
+		// Status and client narrow in SQL; application and host in memory.
+		rows, err := s.Repo.FindWithFilters(ctx, f.Status, f.ClientID, environmentFilter(ac, ""))
+		if err != nil {
+			return nil, usecase.Internal("REPO", "find_with_filters failed", err)
+		}
+		var subs []subscription.Subscription
+		for i := range rows {
+			if f.Matches(&rows[i]) && auth.CanAccessScope(ac, rows[i].ClientID) {
+				subs = append(subs, rows[i])
+			}
+		}
+		if len(subs) > maxBulkSubscriptions {
+			return nil, httperror.BadRequest("TOO_MANY", "Filter matches more than 500 subscriptions; narrow it")
+		}
+		return subs, nil
+	default:
+		return nil, httperror.BadRequest("INVALID_SELECTION", "ids or filter is required")
+	}
+}
+
+func (s *State) applyBulk(ctx context.Context, action, id string, ec usecase.ExecutionContext) error {
+	var err error
+	switch action {
+	case "PAUSE":
+		_, err = usecaseop.Run(ctx, s.UoW, operations.PauseSubscription(s.Repo), operations.PauseCommand{ID: id}, ec)
+	case "RESUME":
+		_, err = usecaseop.Run(ctx, s.UoW, operations.ResumeSubscription(s.Repo), operations.ResumeCommand{ID: id}, ec)
+	case "DELETE":
+		_, err = usecaseop.Run(ctx, s.UoW, operations.DeleteSubscription(s.Repo), operations.DeleteCommand{ID: id}, ec)
+	}
+	return err
+}
+
+func bulkErrMessage(err error) string {
+	var ue *usecase.Error
+	if errors.As(err, &ue) {
+		return ue.Message
+	}
+	return err.Error()
+}
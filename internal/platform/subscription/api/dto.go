@@ -2,9 +2,15 @@
 package api
 
 import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/jsontime"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
 )
 
@@ -13,7 +19,7 @@ type EventTypeBindingDTO struct {
 	EventTypeID   *string `json:"eventTypeId,omitempty"`
 	EventTypeCode string  `json:"eventTypeCode"`
 	SpecVersion   *string `json:"specVersion,omitempty"`
-	Filter        *string `json:"filter,omitempty"`
+	Filter        *string `json:"filter,omitempty" doc:"Predicate over payload/event fields, e.g. payload.country == \"AU\" && payload.amount > 100"`
 }
 
 func (b EventTypeBindingDTO) toEntity() subscription.EventTypeBinding {
@@ -48,24 +54,260 @@ func configEntryFromEntity(c subscription.ConfigEntry) ConfigEntryDTO {
 	return ConfigEntryDTO{Key: c.Key, Value: c.Value}
 }
 
+// SigV4AuthDTO mirrors subscription.SigV4Auth.
+type SigV4AuthDTO struct {
+	Region     string  `json:"region" doc:"AWS region of the target, e.g. ap-southeast-2"`
+	Service    string  `json:"service" doc:"SigV4 signing service, e.g. execute-api or lambda"`
+	RoleARN    *string `json:"roleArn,omitempty" doc:"IAM role assumed for signing; omitted signs with the platform's own credentials (anchor only)"`
+	ExternalID *string `json:"externalId,omitempty" doc:"sts:ExternalId for the role; required unless the caller is anchor"`
+}
+
+// OAuth2AuthDTO mirrors subscription.OAuth2ClientCredentials.
+type OAuth2AuthDTO struct {
+	TokenURL     string   `json:"tokenUrl"`
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret,omitempty" doc:"Write-only; responses carry ******** and sending it back keeps the stored secret"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Audience     *string  `json:"audience,omitempty"`
+}
+
+// TargetAuthDTO mirrors subscription.TargetAuth.
+type TargetAuthDTO struct {
+	Type   string         `json:"type" doc:"AWS_SIGV4, OAUTH2_CLIENT_CREDENTIALS, or NONE (update only: removes target auth)"`
+	SigV4  *SigV4AuthDTO  `json:"sigv4,omitempty"`
+	OAuth2 *OAuth2AuthDTO `json:"oauth2,omitempty"`
+}
+
+func (a *TargetAuthDTO) toEntity() *subscription.TargetAuth {
+	if a == nil {
+		return nil
+	}
+	out := &subscription.TargetAuth{Type: subscription.TargetAuthType(strings.ToUpper(a.Type))}
+	if a.SigV4 != nil {
+		out.SigV4 = &subscription.SigV4Auth{
+			Region:     a.SigV4.Region,
+			Service:    a.SigV4.Service,
+			RoleARN:    a.SigV4.RoleARN,
+			ExternalID: a.SigV4.ExternalID,
+		}
+	}
+	if a.OAuth2 != nil {
+		out.OAuth2 = &subscription.OAuth2ClientCredentials{
+			TokenURL:     a.OAuth2.TokenURL,
+			ClientID:     a.OAuth2.ClientID,
+			ClientSecret: a.OAuth2.ClientSecret,
+			Scopes:       a.OAuth2.Scopes,
+			Audience:     a.OAuth2.Audience,
+		}
+	}
+	return out
+}
+
+// targetAuthFromEntity renders the masked form; the stored secret never
+// leaves the server.
+func targetAuthFromEntity(a *subscription.TargetAuth) *TargetAuthDTO {
+	a = a.Masked()
+	if a == nil {
+		return nil
+	}
+	out := &TargetAuthDTO{Type: string(a.Type)}
+	if a.SigV4 != nil {
+		out.SigV4 = &SigV4AuthDTO{
+			Region:     a.SigV4.Region,
+			Service:    a.SigV4.Service,
+			RoleARN:    a.SigV4.RoleARN,
+			ExternalID: a.SigV4.ExternalID,
+		}
+	}
+	if a.OAuth2 != nil {
+		out.OAuth2 = &OAuth2AuthDTO{
+			TokenURL:     a.OAuth2.TokenURL,
+			ClientID:     a.OAuth2.ClientID,
+			ClientSecret: a.OAuth2.ClientSecret,
+			Scopes:       a.OAuth2.Scopes,
+			Audience:     a.OAuth2.Audience,
+		}
+	}
+	return out
+}
+
+// PayloadTransformDTO mirrors subscription.PayloadTransform.
+type PayloadTransformDTO struct {
+	Engine      string `json:"engine,omitempty" doc:"Template language; GO_TEMPLATE (default)"`
+	Template    string `json:"template" doc:"Go text/template over the event envelope (id, type, source, subject, data, ...). On update an empty template removes the transform"`
+	ContentType string `json:"contentType,omitempty" doc:"Content-Type of the rendered body; defaults to application/json"`
+}
+
+func (t *PayloadTransformDTO) toEntity() *subscription.PayloadTransform {
+	if t == nil {
+		return nil
+	}
+	return &subscription.PayloadTransform{
+		Engine:      payloadtransform.Engine(t.Engine),
+		Template:    t.Template,
+		ContentType: t.ContentType,
+	}
+}
+
+func transformFromEntity(t *subscription.PayloadTransform) *PayloadTransformDTO {
+	if t == nil {
+		return nil
+	}
+	return &PayloadTransformDTO{Engine: string(t.Engine), Template: t.Template, ContentType: t.ContentType}
+}
+
+// DeliveryWindowDTO mirrors deliverywindow.Schedule.
+type DeliveryWindowDTO struct {
+	Timezone  string                `json:"timezone,omitempty" doc:"IANA timezone the windows are read in, e.g. Europe/Berlin; default UTC"`
+	Windows   []DeliveryPeriodDTO   `json:"windows,omitempty" doc:"Weekly periods delivery is allowed in; none = any time outside a blackout"`
+	Blackouts []DeliveryBlackoutDTO `json:"blackouts,omitempty" doc:"Absolute periods delivery is held. On update an empty windows and blackouts removes the delivery window"`
+}
+
+// DeliveryPeriodDTO mirrors deliverywindow.Window.
+type DeliveryPeriodDTO struct {
+	Days  []string `json:"days,omitempty" doc:"Weekdays (MON, TUE, WED, THU, FRI, SAT, SUN); empty = every day"`
+	Start string   `json:"start" doc:"HH:MM local time"`
+	End   string   `json:"end" doc:"HH:MM local time (24:00 = end of day); before start crosses midnight"`
+}
+
+// DeliveryBlackoutDTO mirrors deliverywindow.Blackout.
+type DeliveryBlackoutDTO struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason *string   `json:"reason,omitempty"`
+}
+
+// TapRequestDTO asks for a tap subscription.
+type TapRequestDTO struct {
+	SamplePercent    float64 `json:"samplePercent" doc:"Share of matching events the tap receives, 0.01-100"`
+	ExpiresInSeconds *int32  `json:"expiresInSeconds,omitempty" doc:"Seconds from now until the tap stops receiving events, up to 604800 (7 days); default 3600"`
+}
+
+func (t *TapRequestDTO) toCommand() *operations.TapCommand {
+	if t == nil {
+		return nil
+	}
+	return &operations.TapCommand{SamplePercent: t.SamplePercent, ExpiresInSeconds: t.ExpiresInSeconds}
+}
+
+// TapDTO mirrors subscription.Tap.
+type TapDTO struct {
+	SamplePercent float64         `json:"samplePercent"`
+	ExpiresAt     httpcompat.Time `json:"expiresAt"`
+	Expired       bool            `json:"expired" doc:"The tap is past its expiry and receives no more events"`
+}
+
+func tapFromEntity(t *subscription.Tap) *TapDTO {
+	if t == nil {
+		return nil
+	}
+	return &TapDTO{SamplePercent: t.SamplePercent, ExpiresAt: jsontime.New(t.ExpiresAt), Expired: t.Expired(time.Now())}
+}
+
+func (w *DeliveryWindowDTO) toEntity() *deliverywindow.Schedule {
+	if w == nil {
+		return nil
+	}
+	out := &deliverywindow.Schedule{Timezone: w.Timezone}
+	for _, p := range w.Windows {
+		out.Windows = append(out.Windows, deliverywindow.Window(p))
+	}
+	for _, b := range w.Blackouts {
+		out.Blackouts = append(out.Blackouts, deliverywindow.Blackout(b))
+	}
+	return out
+}
+
+func deliveryWindowFromEntity(w *deliverywindow.Schedule) *DeliveryWindowDTO {
+	if w == nil {
+		return nil
+	}
+	out := &DeliveryWindowDTO{Timezone: w.Timezone}
+	for _, p := range w.Windows {
+		out.Windows = append(out.Windows, DeliveryPeriodDTO(p))
+	}
+	for _, b := range w.Blackouts {
+		out.Blackouts = append(out.Blackouts, DeliveryBlackoutDTO(b))
+	}
+	return out
+}
+
+// SampleEventDTO is the event a transform preview renders.
+type SampleEventDTO struct {
+	Type          string          `json:"type" doc:"Event type code"`
+	Source        *string         `json:"source,omitempty"`
+	Subject       *string         `json:"subject,omitempty"`
+	CorrelationID *string         `json:"correlationId,omitempty"`
+	MessageGroup  *string         `json:"messageGroup,omitempty"`
+	ClientID      *string         `json:"clientId,omitempty"`
+	Data          json.RawMessage `json:"data,omitempty" doc:"Event payload data"`
+}
+
+// envelope mirrors the delivery envelope (dispatchjob/processing) so a
+// preview renders exactly what a real delivery would.
+func (e SampleEventDTO) envelope() map[string]any {
+	env := map[string]any{"id": "preview", "type": e.Type, "attemptNumber": 1}
+	for k, v := range map[string]*string{
+		"source": e.Source, "subject": e.Subject, "correlationId": e.CorrelationID,
+		"messageGroup": e.MessageGroup, "clientId": e.ClientID,
+	} {
+		if v != nil {
+			env[k] = *v
+		}
+	}
+	if len(e.Data) > 0 {
+		env["data"] = e.Data
+	}
+	return env
+}
+
+// TransformPreviewRequest is the wire body for
+// POST /api/subscriptions/transform-preview.
+type TransformPreviewRequest struct {
+	Transform PayloadTransformDTO `json:"transform"`
+	Event     SampleEventDTO      `json:"event"`
+}
+
+// TransformPreviewResponse carries the rendered body, or the render error.
+// A template that fails on this event is a 200 with Error set, so the form
+// can show it inline.
+type TransformPreviewResponse struct {
+	Body        string  `json:"body"`
+	ContentType string  `json:"contentType"`
+	Error       *string `json:"error,omitempty"`
+}
+
 // CreateSubscriptionRequest is the wire body for POST /api/subscriptions.
 type CreateSubscriptionRequest struct {
-	Code             string                `json:"code"`
-	Name             string                `json:"name"`
-	Endpoint         string                `json:"endpoint" doc:"http(s) URL delivery target"`
-	Description      *string               `json:"description,omitempty"`
-	ClientID         *string               `json:"clientId,omitempty"`
-	ConnectionID     *string               `json:"connectionId,omitempty"`
-	DispatchPoolID   *string               `json:"dispatchPoolId,omitempty"`
-	ServiceAccountID *string               `json:"serviceAccountId,omitempty"`
-	EventTypes       []EventTypeBindingDTO `json:"eventTypes,omitempty"`
-	CustomConfig     []ConfigEntryDTO      `json:"customConfig,omitempty"`
-	Mode             string                `json:"mode,omitempty" doc:"Dispatch mode (IMMEDIATE, NEXT_ON_ERROR, BLOCK_ON_ERROR)"`
-	TimeoutSeconds   *int32                `json:"timeoutSeconds,omitempty"`
-	MaxRetries       *int32                `json:"maxRetries,omitempty"`
-	DelaySeconds     *int32                `json:"delaySeconds,omitempty"`
-	MaxAgeSeconds    *int32                `json:"maxAgeSeconds,omitempty"`
-	DataOnly         *bool                 `json:"dataOnly,omitempty"`
+	Code               string                `json:"code"`
+	Name               string                `json:"name"`
+	Endpoint           string                `json:"endpoint" doc:"http(s) URL delivery target"`
+	Description        *string               `json:"description,omitempty"`
+	ClientID           *string               `json:"clientId,omitempty"`
+	ConnectionID       *string               `json:"connectionId,omitempty"`
+	DispatchPoolID     *string               `json:"dispatchPoolId,omitempty"`
+	ServiceAccountID   *string               `json:"serviceAccountId,omitempty"`
+	EventTypes         []EventTypeBindingDTO `json:"eventTypes,omitempty"`
+	CustomConfig       []ConfigEntryDTO      `json:"customConfig,omitempty"`
+	TargetAuth         *TargetAuthDTO        `json:"targetAuth,omitempty"`
+	Transform          *PayloadTransformDTO  `json:"transform,omitempty"`
+	Mode               string                `json:"mode,omitempty" doc:"Dispatch mode (IMMEDIATE, NEXT_ON_ERROR, BLOCK_ON_ERROR)"`
+	Priority           string                `json:"priority,omitempty" doc:"Delivery priority (HIGH, NORMAL, LOW); default NORMAL"`
+	Weight             *int32                `json:"weight,omitempty" doc:"Share of the dispatch pool's workers when other subscriptions are waiting too (1-100); default 1"`
+	AckTimeoutSeconds  *int32                `json:"ackTimeoutSeconds,omitempty" doc:"Require the receiver to acknowledge each delivery's token; one unacknowledged this many seconds (30-86400) is delivered again. Omit for 2xx-is-done delivery"`
+	DeliveryHeaders    []string              `json:"deliveryHeaders,omitempty" doc:"Standard X-FlowCatalyst-* headers each delivery carries (DELIVERY_ID, EVENT_ID, EVENT_TYPE, DELIVERY_ATTEMPT, FIRST_ATTEMPTED_AT, SUBSCRIPTION_ID, SIGNATURE, TRACE_CONTEXT); default all"`
+	TimeoutSeconds     *int32                `json:"timeoutSeconds,omitempty"`
+	MaxRetries         *int32                `json:"maxRetries,omitempty"`
+	HonorRetryAfter    *bool                 `json:"honorRetryAfter,omitempty" doc:"Wait out a receiver's Retry-After on 429/503 before retrying; default true"`
+	DeliveryFormat     *string               `json:"deliveryFormat,omitempty" doc:"Webhook body format (FLOWCATALYST, CLOUDEVENTS_BINARY, CLOUDEVENTS_STRUCTURED); default FLOWCATALYST"`
+	DeliveryWindow     *DeliveryWindowDTO    `json:"deliveryWindow,omitempty" doc:"When jobs may be delivered; outside it they wait for the next opening. Default: any time"`
+	AllowPrivateTarget *bool                 `json:"allowPrivateTarget,omitempty" doc:"Let the endpoint resolve to a private or reserved address the egress policy otherwise refuses. Requires the egress-override permission"`
+	EgressProxy        *string               `json:"egressProxy,omitempty" doc:"Deliver through this named deployment proxy (FC_EGRESS_PROXIES) instead of the default route"`
+	DelaySeconds       *int32                `json:"delaySeconds,omitempty"`
+	MaxAgeSeconds      *int32                `json:"maxAgeSeconds,omitempty"`
+	DataOnly           *bool                 `json:"dataOnly,omitempty"`
+	Environment        string                `json:"environment,omitempty" enum:"SANDBOX,PRODUCTION" doc:"Client environment the subscription lives in; only events ingested there reach it. Defaults to the caller's key environment, else PRODUCTION"`
+	Tap                *TapRequestDTO        `json:"tap,omitempty" doc:"Make this a debugging tap: it receives only a sample of matching events, its deliveries aren't metered, and it expires"`
 }
 
 func (r CreateSubscriptionRequest) toCommand() operations.CreateCommand {
@@ -81,22 +323,35 @@ func (r CreateSubscriptionRequest) toCommand() operations.CreateCommand {
 		}
 	}
 	return operations.CreateCommand{
-		Code:             r.Code,
-		Name:             r.Name,
-		Endpoint:         r.Endpoint,
-		Description:      r.Description,
-		ClientID:         r.ClientID,
-		ConnectionID:     r.ConnectionID,
-		DispatchPoolID:   r.DispatchPoolID,
-		ServiceAccountID: r.ServiceAccountID,
-		EventTypes:       events,
-		CustomConfig:     config,
-		Mode:             r.Mode,
-		TimeoutSeconds:   r.TimeoutSeconds,
-		MaxRetries:       r.MaxRetries,
-		DelaySeconds:     r.DelaySeconds,
-		MaxAgeSeconds:    r.MaxAgeSeconds,
-		DataOnly:         r.DataOnly,
+		Code:               r.Code,
+		Name:               r.Name,
+		Endpoint:           r.Endpoint,
+		Description:        r.Description,
+		ClientID:           r.ClientID,
+		ConnectionID:       r.ConnectionID,
+		DispatchPoolID:     r.DispatchPoolID,
+		ServiceAccountID:   r.ServiceAccountID,
+		EventTypes:         events,
+		CustomConfig:       config,
+		TargetAuth:         r.TargetAuth.toEntity(),
+		Transform:          r.Transform.toEntity(),
+		Mode:               r.Mode,
+		Priority:           r.Priority,
+		Weight:             r.Weight,
+		AckTimeoutSeconds:  r.AckTimeoutSeconds,
+		DeliveryHeaders:    r.DeliveryHeaders,
+		TimeoutSeconds:     r.TimeoutSeconds,
+		MaxRetries:         r.MaxRetries,
+		HonorRetryAfter:    r.HonorRetryAfter,
+		DeliveryFormat:     r.DeliveryFormat,
+		DeliveryWindow:     r.DeliveryWindow.toEntity(),
+		AllowPrivateTarget: r.AllowPrivateTarget,
+		EgressProxy:        r.EgressProxy,
+		DelaySeconds:       r.DelaySeconds,
+		MaxAgeSeconds:      r.MaxAgeSeconds,
+		DataOnly:           r.DataOnly,
+		Environment:        r.Environment,
+		Tap:                r.Tap.toCommand(),
 	}
 }
 
@@ -106,20 +361,32 @@ func (r CreateSubscriptionRequest) toCommand() operations.CreateCommand {
 // and dropped on input (the leniency net accepts them without persisting).
 // connectionId IS accepted + persisted, mirroring Rust's update use case.
 type UpdateSubscriptionRequest struct {
-	Name             *string               `json:"name,omitempty"`
-	Description      *string               `json:"description,omitempty"`
-	Endpoint         *string               `json:"endpoint,omitempty"`
-	ConnectionID     *string               `json:"connectionId,omitempty"`
-	EventTypes       []EventTypeBindingDTO `json:"eventTypes,omitempty"`
-	CustomConfig     []ConfigEntryDTO      `json:"customConfig,omitempty"`
-	Mode             *string               `json:"mode,omitempty"`
-	TimeoutSeconds   *int32                `json:"timeoutSeconds,omitempty"`
-	MaxRetries       *int32                `json:"maxRetries,omitempty"`
-	DelaySeconds     *int32                `json:"delaySeconds,omitempty"`
-	MaxAgeSeconds    *int32                `json:"maxAgeSeconds,omitempty"`
-	DispatchPoolID   *string               `json:"dispatchPoolId,omitempty"`
-	ServiceAccountID *string               `json:"serviceAccountId,omitempty"`
-	DataOnly         *bool                 `json:"dataOnly,omitempty"`
+	Name               *string               `json:"name,omitempty"`
+	Description        *string               `json:"description,omitempty"`
+	Endpoint           *string               `json:"endpoint,omitempty"`
+	ConnectionID       *string               `json:"connectionId,omitempty"`
+	EventTypes         []EventTypeBindingDTO `json:"eventTypes,omitempty"`
+	CustomConfig       []ConfigEntryDTO      `json:"customConfig,omitempty"`
+	TargetAuth         *TargetAuthDTO        `json:"targetAuth,omitempty"`
+	Transform          *PayloadTransformDTO  `json:"transform,omitempty"`
+	Mode               *string               `json:"mode,omitempty"`
+	Priority           *string               `json:"priority,omitempty" doc:"Delivery priority (HIGH, NORMAL, LOW)"`
+	Weight             *int32                `json:"weight,omitempty" doc:"Share of the dispatch pool's workers when other subscriptions are waiting too (1-100)"`
+	AckTimeoutSeconds  *int32                `json:"ackTimeoutSeconds,omitempty" doc:"Require the receiver to acknowledge each delivery's token, redelivering after this many seconds (30-86400); 0 turns acknowledgement off"`
+	DeliveryHeaders    []string              `json:"deliveryHeaders,omitempty" doc:"Standard X-FlowCatalyst-* headers each delivery carries; an empty list goes back to all of them"`
+	TimeoutSeconds     *int32                `json:"timeoutSeconds,omitempty"`
+	MaxRetries         *int32                `json:"maxRetries,omitempty"`
+	HonorRetryAfter    *bool                 `json:"honorRetryAfter,omitempty" doc:"Wait out a receiver's Retry-After on 429/503 before retrying"`
+	DeliveryFormat     *string               `json:"deliveryFormat,omitempty" doc:"Webhook body format (FLOWCATALYST, CLOUDEVENTS_BINARY, CLOUDEVENTS_STRUCTURED)"`
+	DeliveryWindow     *DeliveryWindowDTO    `json:"deliveryWindow,omitempty" doc:"When jobs may be delivered; omitted leaves it as is"`
+	AllowPrivateTarget *bool                 `json:"allowPrivateTarget,omitempty" doc:"Let the endpoint resolve to a private or reserved address. Turning it on requires the egress-override permission"`
+	EgressProxy        *string               `json:"egressProxy,omitempty" doc:"Named deployment proxy to deliver through; empty string returns to the default route"`
+	DelaySeconds       *int32                `json:"delaySeconds,omitempty"`
+	MaxAgeSeconds      *int32                `json:"maxAgeSeconds,omitempty"`
+	DispatchPoolID     *string               `json:"dispatchPoolId,omitempty"`
+	ServiceAccountID   *string               `json:"serviceAccountId,omitempty"`
+	DataOnly           *bool                 `json:"dataOnly,omitempty"`
+	Tap                *TapRequestDTO        `json:"tap,omitempty" doc:"Change a tap's sample and re-arm its expiry from now; only for subscriptions created as taps"`
 }
 
 func (r UpdateSubscriptionRequest) toCommand(id string) operations.UpdateCommand {
@@ -138,54 +405,79 @@ func (r UpdateSubscriptionRequest) toCommand(id string) operations.UpdateCommand
 		}
 	}
 	return operations.UpdateCommand{
-		ID:               id,
-		Name:             r.Name,
-		Description:      r.Description,
-		Endpoint:         r.Endpoint,
-		ConnectionID:     r.ConnectionID,
-		EventTypes:       events,
-		CustomConfig:     config,
-		Mode:             r.Mode,
-		TimeoutSeconds:   r.TimeoutSeconds,
-		MaxRetries:       r.MaxRetries,
-		DelaySeconds:     r.DelaySeconds,
-		MaxAgeSeconds:    r.MaxAgeSeconds,
-		DispatchPoolID:   r.DispatchPoolID,
-		ServiceAccountID: r.ServiceAccountID,
-		DataOnly:         r.DataOnly,
+		ID:                 id,
+		Name:               r.Name,
+		Description:        r.Description,
+		Endpoint:           r.Endpoint,
+		ConnectionID:       r.ConnectionID,
+		EventTypes:         events,
+		CustomConfig:       config,
+		TargetAuth:         r.TargetAuth.toEntity(),
+		Transform:          r.Transform.toEntity(),
+		Mode:               r.Mode,
+		Priority:           r.Priority,
+		Weight:             r.Weight,
+		AckTimeoutSeconds:  r.AckTimeoutSeconds,
+		DeliveryHeaders:    r.DeliveryHeaders,
+		TimeoutSeconds:     r.TimeoutSeconds,
+		MaxRetries:         r.MaxRetries,
+		HonorRetryAfter:    r.HonorRetryAfter,
+		DeliveryFormat:     r.DeliveryFormat,
+		DeliveryWindow:     r.DeliveryWindow.toEntity(),
+		AllowPrivateTarget: r.AllowPrivateTarget,
+		EgressProxy:        r.EgressProxy,
+		DelaySeconds:       r.DelaySeconds,
+		MaxAgeSeconds:      r.MaxAgeSeconds,
+		DispatchPoolID:     r.DispatchPoolID,
+		ServiceAccountID:   r.ServiceAccountID,
+		DataOnly:           r.DataOnly,
+		Tap:                r.Tap.toCommand(),
 	}
 }
 
 // SubscriptionResponse mirrors subscription.Subscription.
 type SubscriptionResponse struct {
-	ID               string                `json:"id"`
-	Code             string                `json:"code"`
-	ApplicationCode  *string               `json:"applicationCode,omitempty"`
-	Name             string                `json:"name"`
-	Description      *string               `json:"description,omitempty"`
-	ClientID         *string               `json:"clientId,omitempty"`
-	ClientIdentifier *string               `json:"clientIdentifier,omitempty"`
-	ClientScoped     bool                  `json:"clientScoped"`
-	EventTypes       []EventTypeBindingDTO `json:"eventTypes"`
-	ConnectionID     *string               `json:"connectionId,omitempty"`
-	Endpoint         string                `json:"endpoint"`
-	Queue            *string               `json:"queue,omitempty"`
-	CustomConfig     []ConfigEntryDTO      `json:"customConfig"`
-	Source           string                `json:"source"`
-	Status           string                `json:"status"`
-	MaxAgeSeconds    int32                 `json:"maxAgeSeconds"`
-	DispatchPoolID   *string               `json:"dispatchPoolId,omitempty"`
-	DispatchPoolCode *string               `json:"dispatchPoolCode,omitempty"`
-	DelaySeconds     int32                 `json:"delaySeconds"`
-	Sequence         int32                 `json:"sequence"`
-	Mode             string                `json:"mode"`
-	TimeoutSeconds   int32                 `json:"timeoutSeconds"`
-	MaxRetries       int32                 `json:"maxRetries"`
-	ServiceAccountID *string               `json:"serviceAccountId,omitempty"`
-	DataOnly         bool                  `json:"dataOnly"`
-	CreatedBy        *string               `json:"createdBy,omitempty"`
-	CreatedAt        httpcompat.Time       `json:"createdAt"`
-	UpdatedAt        httpcompat.Time       `json:"updatedAt"`
+	ID                 string                `json:"id"`
+	Code               string                `json:"code"`
+	ApplicationCode    *string               `json:"applicationCode,omitempty"`
+	Name               string                `json:"name"`
+	Description        *string               `json:"description,omitempty"`
+	ClientID           *string               `json:"clientId,omitempty"`
+	ClientIdentifier   *string               `json:"clientIdentifier,omitempty"`
+	ClientScoped       bool                  `json:"clientScoped"`
+	Environment        string                `json:"environment"`
+	EventTypes         []EventTypeBindingDTO `json:"eventTypes"`
+	ConnectionID       *string               `json:"connectionId,omitempty"`
+	Endpoint           string                `json:"endpoint"`
+	Queue              *string               `json:"queue,omitempty"`
+	CustomConfig       []ConfigEntryDTO      `json:"customConfig"`
+	TargetAuth         *TargetAuthDTO        `json:"targetAuth,omitempty"`
+	Transform          *PayloadTransformDTO  `json:"transform,omitempty"`
+	Source             string                `json:"source"`
+	Status             string                `json:"status"`
+	MaxAgeSeconds      int32                 `json:"maxAgeSeconds"`
+	DispatchPoolID     *string               `json:"dispatchPoolId,omitempty"`
+	DispatchPoolCode   *string               `json:"dispatchPoolCode,omitempty"`
+	DelaySeconds       int32                 `json:"delaySeconds"`
+	Sequence           int32                 `json:"sequence"`
+	Mode               string                `json:"mode"`
+	Priority           string                `json:"priority"`
+	Weight             int32                 `json:"weight"`
+	AckTimeoutSeconds  *int32                `json:"ackTimeoutSeconds,omitempty"`
+	DeliveryHeaders    []string              `json:"deliveryHeaders" doc:"Standard X-FlowCatalyst-* headers each delivery carries"`
+	TimeoutSeconds     int32                 `json:"timeoutSeconds"`
+	MaxRetries         int32                 `json:"maxRetries"`
+	HonorRetryAfter    bool                  `json:"honorRetryAfter"`
+	DeliveryFormat     string                `json:"deliveryFormat"`
+	DeliveryWindow     *DeliveryWindowDTO    `json:"deliveryWindow,omitempty"`
+	AllowPrivateTarget bool                  `json:"allowPrivateTarget"`
+	EgressProxy        *string               `json:"egressProxy,omitempty"`
+	Tap                *TapDTO               `json:"tap,omitempty"`
+	ServiceAccountID   *string               `json:"serviceAccountId,omitempty"`
+	DataOnly           bool                  `json:"dataOnly"`
+	CreatedBy          *string               `json:"createdBy,omitempty"`
+	CreatedAt          httpcompat.Time       `json:"createdAt"`
+	UpdatedAt          httpcompat.Time       `json:"updatedAt"`
 }
 
 func fromEntity(s *subscription.Subscription) SubscriptionResponse {
@@ -198,35 +490,61 @@ func fromEntity(s *subscription.Subscription) SubscriptionResponse {
 		config = append(config, configEntryFromEntity(c))
 	}
 	return SubscriptionResponse{
-		ID:               s.ID,
-		Code:             s.Code,
-		ApplicationCode:  s.ApplicationCode,
-		Name:             s.Name,
-		Description:      s.Description,
-		ClientID:         s.ClientID,
-		ClientIdentifier: s.ClientIdentifier,
-		ClientScoped:     s.ClientScoped,
-		EventTypes:       events,
-		ConnectionID:     s.ConnectionID,
-		Endpoint:         s.Endpoint,
-		Queue:            s.Queue,
-		CustomConfig:     config,
-		Source:           string(s.Source),
-		Status:           string(s.Status),
-		MaxAgeSeconds:    s.MaxAgeSeconds,
-		DispatchPoolID:   s.DispatchPoolID,
-		DispatchPoolCode: s.DispatchPoolCode,
-		DelaySeconds:     s.DelaySeconds,
-		Sequence:         s.Sequence,
-		Mode:             string(s.Mode),
-		TimeoutSeconds:   s.TimeoutSeconds,
-		MaxRetries:       s.MaxRetries,
-		ServiceAccountID: s.ServiceAccountID,
-		DataOnly:         s.DataOnly,
-		CreatedBy:        s.CreatedBy,
-		CreatedAt:        jsontime.New(s.CreatedAt),
-		UpdatedAt:        jsontime.New(s.UpdatedAt),
+		ID:                 s.ID,
+		Code:               s.Code,
+		ApplicationCode:    s.ApplicationCode,
+		Name:               s.Name,
+		Description:        s.Description,
+		ClientID:           s.ClientID,
+		ClientIdentifier:   s.ClientIdentifier,
+		ClientScoped:       s.ClientScoped,
+		Environment:        string(s.Environment),
+		EventTypes:         events,
+		ConnectionID:       s.ConnectionID,
+		Endpoint:           s.Endpoint,
+		Queue:              s.Queue,
+		CustomConfig:       config,
+		TargetAuth:         targetAuthFromEntity(s.TargetAuth),
+		Transform:          transformFromEntity(s.Transform),
+		Source:             string(s.Source),
+		Status:             string(s.Status),
+		MaxAgeSeconds:      s.MaxAgeSeconds,
+		DispatchPoolID:     s.DispatchPoolID,
+		DispatchPoolCode:   s.DispatchPoolCode,
+		DelaySeconds:       s.DelaySeconds,
+		Sequence:           s.Sequence,
+		Mode:               string(s.Mode),
+		Priority:           string(s.Priority),
+		Weight:             s.Weight,
+		AckTimeoutSeconds:  s.AckTimeoutSeconds,
+		DeliveryHeaders:    deliveryHeadersDTO(s.DeliveryHeaders),
+		TimeoutSeconds:     s.TimeoutSeconds,
+		MaxRetries:         s.MaxRetries,
+		HonorRetryAfter:    s.HonorRetryAfter,
+		DeliveryFormat:     string(s.DeliveryFormat),
+		DeliveryWindow:     deliveryWindowFromEntity(s.DeliveryWindow),
+		AllowPrivateTarget: s.AllowPrivateTarget,
+		EgressProxy:        s.EgressProxy,
+		Tap:                tapFromEntity(s.Tap),
+		ServiceAccountID:   s.ServiceAccountID,
+		DataOnly:           s.DataOnly,
+		CreatedBy:          s.CreatedBy,
+		CreatedAt:          jsontime.New(s.CreatedAt),
+		UpdatedAt:          jsontime.New(s.UpdatedAt),
+	}
+}
+
+// deliveryHeadersDTO lists the headers a subscription's deliveries
+// carry; an unset list means all of them.
+func deliveryHeadersDTO(hs []subscription.DeliveryHeader) []string {
+	if hs == nil {
+		hs = subscription.AllDeliveryHeaders
+	}
+	out := make([]string, 0, len(hs))
+	for _, h := range hs {
+		out = append(out, string(h))
 	}
+	return out
 }
 
 // SubscriptionListResponse is the wire shape for GET /api/subscriptions.
@@ -235,3 +553,244 @@ type SubscriptionListResponse struct {
 	Subscriptions []SubscriptionResponse `json:"subscriptions"`
 	Total         int                    `json:"total"`
 }
+
+// SubscriptionDecisionRequest is the body of POST
+// /api/subscriptions/{id}/approve (optional) and /reject (reason required).
+type SubscriptionDecisionRequest struct {
+	Reason *string `json:"reason,omitempty" maxLength:"2000" doc:"Why the subscription was approved or rejected; required to reject"`
+}
+
+// SubscriptionApprovalResponse is one recorded approval decision.
+type SubscriptionApprovalResponse struct {
+	ID        string          `json:"id"`
+	Decision  string          `json:"decision" enum:"APPROVED,REJECTED"`
+	DecidedBy string          `json:"decidedBy" doc:"Principal id of the approver"`
+	Reason    *string         `json:"reason,omitempty"`
+	DecidedAt httpcompat.Time `json:"decidedAt"`
+}
+
+// SubscriptionApprovalListResponse is the wire shape for GET
+// /api/subscriptions/{id}/approvals.
+type SubscriptionApprovalListResponse struct {
+	Status    string                         `json:"status" doc:"The subscription's current status"`
+	Approvals []SubscriptionApprovalResponse `json:"approvals"`
+	Total     int                            `json:"total"`
+}
+
+// ConfigFieldDTO mirrors subscription.ConfigField.
+type ConfigFieldDTO struct {
+	Key         string   `json:"key"`
+	Label       string   `json:"label,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type" enum:"STRING,INTEGER,BOOLEAN,URL,ENUM"`
+	Required    bool     `json:"required,omitempty"`
+	Secret      bool     `json:"secret,omitempty" doc:"Value is write-only; responses carry ******** instead"`
+	Default     *string  `json:"default,omitempty"`
+	Options     []string `json:"options,omitempty" doc:"Allowed values of an ENUM field"`
+	Pattern     string   `json:"pattern,omitempty" doc:"Regexp a STRING value must match"`
+	Aliases     []string `json:"aliases,omitempty" doc:"Legacy keys renamed to key on the subscription's next save"`
+}
+
+func (f ConfigFieldDTO) toEntity() subscription.ConfigField {
+	return subscription.ConfigField{
+		Key:         f.Key,
+		Label:       f.Label,
+		Description: f.Description,
+		Type:        subscription.ParseConfigFieldType(f.Type),
+		Required:    f.Required,
+		Secret:      f.Secret,
+		Default:     f.Default,
+		Options:     f.Options,
+		Pattern:     f.Pattern,
+		Aliases:     f.Aliases,
+	}
+}
+
+func configFieldFromEntity(f subscription.ConfigField) ConfigFieldDTO {
+	return ConfigFieldDTO{
+		Key:         f.Key,
+		Label:       f.Label,
+		Description: f.Description,
+		Type:        string(f.Type),
+		Required:    f.Required,
+		Secret:      f.Secret,
+		Default:     f.Default,
+		Options:     f.Options,
+		Pattern:     f.Pattern,
+		Aliases:     f.Aliases,
+	}
+}
+
+// SetConfigSchemaRequest is the wire body for PUT /api/subscription-config-schemas.
+type SetConfigSchemaRequest struct {
+	ApplicationCode *string          `json:"applicationCode,omitempty" doc:"Omit for the platform default schema"`
+	MediationType   string           `json:"mediationType,omitempty" doc:"Defaults to HTTP"`
+	Description     *string          `json:"description,omitempty"`
+	Fields          []ConfigFieldDTO `json:"fields"`
+}
+
+func (r SetConfigSchemaRequest) toCommand() operations.SetConfigSchemaCommand {
+	fields := make([]subscription.ConfigField, 0, len(r.Fields))
+	for _, f := range r.Fields {
+		fields = append(fields, f.toEntity())
+	}
+	return operations.SetConfigSchemaCommand{
+		ApplicationCode: r.ApplicationCode,
+		MediationType:   r.MediationType,
+		Description:     r.Description,
+		Fields:          fields,
+	}
+}
+
+// ConfigSchemaResponse is the wire shape of one config schema.
+type ConfigSchemaResponse struct {
+	ID              string           `json:"id"`
+	ApplicationCode *string          `json:"applicationCode,omitempty"`
+	MediationType   string           `json:"mediationType"`
+	Description     *string          `json:"description,omitempty"`
+	Fields          []ConfigFieldDTO `json:"fields"`
+	CreatedAt       httpcompat.Time  `json:"createdAt"`
+	UpdatedAt       httpcompat.Time  `json:"updatedAt"`
+}
+
+func configSchemaFromEntity(c *subscription.ConfigSchema) ConfigSchemaResponse {
+	fields := make([]ConfigFieldDTO, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		fields = append(fields, configFieldFromEntity(f))
+	}
+	return ConfigSchemaResponse{
+		ID:              c.ID,
+		ApplicationCode: c.ApplicationCode,
+		MediationType:   string(c.MediationType),
+		Description:     c.Description,
+		Fields:          fields,
+		CreatedAt:       jsontime.New(c.CreatedAt),
+		UpdatedAt:       jsontime.New(c.UpdatedAt),
+	}
+}
+
+// ConfigSchemaListResponse is the wire shape for GET /api/subscription-config-schemas.
+type ConfigSchemaListResponse struct {
+	Schemas []ConfigSchemaResponse `json:"schemas"`
+}
+
+// ConfigConformanceItem reports one governed subscription whose stored
+// config the schema would reject or rewrite on its next save.
+type ConfigConformanceItem struct {
+	SubscriptionID string `json:"subscriptionId"`
+	Code           string `json:"code"`
+	// Error is set when the stored config fails validation.
+	Error *string `json:"error,omitempty"`
+	// Changes is true when the config is valid but would be normalised
+	// (aliases renamed, defaults filled, booleans canonicalised).
+	Changes bool `json:"changes"`
+}
+
+// ConfigConformanceResponse is the wire shape for
+// GET /api/subscription-config-schemas/{id}/conformance.
+type ConfigConformanceResponse struct {
+	SchemaID   string                  `json:"schemaId"`
+	Governed   int                     `json:"governed"`
+	Conforming int                     `json:"conforming"`
+	Items      []ConfigConformanceItem `json:"items"`
+}
+
+// SetTargetTLSRequest is the wire body for PUT /api/subscriptions/{id}/target-tls.
+type SetTargetTLSRequest struct {
+	ClientCertificate string `json:"clientCertificate,omitempty" doc:"PEM client certificate (leaf first, then any intermediates) presented to targets that require mutual TLS"`
+	ClientKey         string `json:"clientKey,omitempty" doc:"PEM private key for clientCertificate. Encrypted at rest and never returned"`
+	CABundle          string `json:"caBundle,omitempty" doc:"PEM CA certificates trusted, alongside the system roots, for the target's server certificate"`
+}
+
+// TargetTLSResponse describes a subscription's TLS material. PEM and key
+// material are write-only; only certificate metadata is returned.
+type TargetTLSResponse struct {
+	SubscriptionID       string        `json:"subscriptionId"`
+	HasClientCertificate bool          `json:"hasClientCertificate"`
+	HasCABundle          bool          `json:"hasCaBundle"`
+	CertSubject          *string       `json:"certSubject,omitempty"`
+	CertNotAfter         *time.Time    `json:"certNotAfter,omitempty"`
+	CANotAfter           *time.Time    `json:"caNotAfter,omitempty" doc:"Earliest expiry in the CA bundle"`
+	ExpiresAt            *time.Time    `json:"expiresAt,omitempty"`
+	ExpiryState          string        `json:"expiryState" doc:"VALID, EXPIRING (within 30 days) or EXPIRED"`
+	UpdatedAt            jsontime.Time `json:"updatedAt"`
+}
+
+func targetTLSFromEntity(t *subscription.TargetTLS, now time.Time) TargetTLSResponse {
+	out := TargetTLSResponse{
+		SubscriptionID:       t.SubscriptionID,
+		HasClientCertificate: t.ClientCertPEM != nil,
+		HasCABundle:          t.CABundlePEM != nil,
+		CertSubject:          t.CertSubject,
+		CertNotAfter:         t.CertNotAfter,
+		CANotAfter:           t.CANotAfter,
+		ExpiresAt:            t.ExpiresAt(),
+		ExpiryState:          string(subscription.CertValid),
+		UpdatedAt:            jsontime.New(t.UpdatedAt),
+	}
+	if out.ExpiresAt != nil {
+		out.ExpiryState = string(subscription.ExpiryState(*out.ExpiresAt, now, subscription.CertExpiryWarning))
+	}
+	return out
+}
+
+// TargetTLSExpiryItem is one row of the certificate expiry report.
+type TargetTLSExpiryItem struct {
+	TargetTLSResponse
+	Code     string  `json:"code"`
+	ClientID *string `json:"clientId,omitempty"`
+}
+
+// TargetTLSExpiryResponse is the wire shape for
+// GET /api/subscriptions/target-tls/expiring.
+type TargetTLSExpiryResponse struct {
+	Items []TargetTLSExpiryItem `json:"items"`
+}
+
+// BulkSubscriptionRequest is the body of POST /api/subscriptions/bulk.
+// Exactly one of IDs or Filter selects the subscriptions.
+type BulkSubscriptionRequest struct {
+	Action string                  `json:"action" enum:"PAUSE,RESUME,DELETE"`
+	IDs    []string                `json:"ids,omitempty" doc:"Subscription ids; mutually exclusive with filter"`
+	Filter *BulkSubscriptionFilter `json:"filter,omitempty"`
+	DryRun bool                    `json:"dryRun,omitempty" doc:"List the affected subscriptions without changing them"`
+}
+
+// BulkSubscriptionFilter selects subscriptions by attribute. At least one
+// field is required; all set fields must match.
+type BulkSubscriptionFilter struct {
+	ClientID        *string `json:"clientId,omitempty"`
+	Status          *string `json:"status,omitempty" enum:"ACTIVE,PAUSED"`
+	ApplicationCode *string `json:"applicationCode,omitempty"`
+	TargetHost      *string `json:"targetHost,omitempty" doc:"Endpoint host, optionally with :port"`
+}
+
+func (f *BulkSubscriptionFilter) toEntity() subscription.BulkFilter {
+	return subscription.BulkFilter{
+		ClientID:        f.ClientID,
+		Status:          f.Status,
+		ApplicationCode: f.ApplicationCode,
+		TargetHost:      f.TargetHost,
+	}
+}
+
+// BulkSubscriptionResult is the per-subscription outcome.
+type BulkSubscriptionResult struct {
+	ID       string  `json:"id"`
+	Code     string  `json:"code,omitempty"`
+	ClientID *string `json:"clientId,omitempty"`
+	Endpoint string  `json:"endpoint,omitempty"`
+	Status   string  `json:"status" doc:"matched (dry run) | applied | unchanged | error"`
+	Message  string  `json:"message,omitempty"`
+}
+
+// BulkSubscriptionResponse summarises a bulk operation.
+type BulkSubscriptionResponse struct {
+	Action    string                   `json:"action"`
+	DryRun    bool                     `json:"dryRun"`
+	Matched   int                      `json:"matched"`
+	Applied   int                      `json:"applied"`
+	Unchanged int                      `json:"unchanged"`
+	Failed    int                      `json:"failed"`
+	Results   []BulkSubscriptionResult `json:"results"`
+}
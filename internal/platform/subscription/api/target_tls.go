@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/apicommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+type setTargetTLSInput struct {
+	ID   string `path:"id"`
+	Body SetTargetTLSRequest
+}
+
+// setTargetTLS uploads a subscription's client certificate and/or CA
+// bundle, replacing whatever was stored.
+func (s *State) setTargetTLS(ctx context.Context, in *setTargetTLSInput) (*apicommon.Out[TargetTLSResponse], error) {
+	if err := auth.CanWriteSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	cmd := operations.SetTargetTLSCommand{
+		SubscriptionID:    in.ID,
+		ClientCertificate: in.Body.ClientCertificate,
+		ClientKey:         in.Body.ClientKey,
+		CABundle:          in.Body.CABundle,
+	}
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.SetTargetTLS(s.Repo, s.TLSRepo), cmd, reqctx.ExecutionContext(ctx)); err != nil {
+		return nil, err
+	}
+	return s.getTargetTLS(ctx, &apicommon.IDInput{ID: in.ID})
+}
+
+// getTargetTLS reports a subscription's certificate metadata and expiry.
+func (s *State) getTargetTLS(ctx context.Context, in *apicommon.IDInput) (*apicommon.Out[TargetTLSResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanReadSubscriptions(ac); err != nil {
+		return nil, err
+	}
+	sub, err := s.Repo.FindByID(ctx, in.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if sub == nil {
+		return nil, httperror.NotFound("Subscription", in.ID)
+	}
+	if (sub.ClientID != nil && !ac.CanAccessClient(*sub.ClientID)) || !ac.CanAccessEnvironment(sub.Environment) {
+		return nil, httperror.Forbidden("No access to this subscription")
+	}
+	t, err := s.TLSRepo.FindTargetTLS(ctx, sub.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_target_tls failed", err)
+	}
+	if t == nil {
+		return nil, httperror.NotFound("SubscriptionTargetTLS", in.ID)
+	}
+	return &apicommon.Out[TargetTLSResponse]{Body: targetTLSFromEntity(t, time.Now())}, nil
+}
+
+func (s *State) clearTargetTLS(ctx context.Context, in *apicommon.IDInput) (*apicommon.Empty, error) {
+	if err := auth.CanWriteSubscriptions(auth.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+	cmd := operations.ClearTargetTLSCommand{SubscriptionID: in.ID}
+	if _, err := usecaseop.Run(ctx, s.UoW, operations.ClearTargetTLS(s.Repo, s.TLSRepo), cmd, reqctx.ExecutionContext(ctx)); err != nil {
+		return nil, err
+	}
+	return &apicommon.Empty{}, nil
+}
+
+type expiringTargetTLSInput struct {
+	WithinDays int `query:"withinDays" default:"30" minimum:"0" maximum:"365" doc:"Report certificates expiring within this many days; expired ones are always included"`
+}
+
+// expiringTargetTLS lists subscriptions whose client certificate or CA
+// bundle expires within the window, soonest first, narrowed to the
+// caller's clients.
+func (s *State) expiringTargetTLS(ctx context.Context, in *expiringTargetTLSInput) (*apicommon.Out[TargetTLSExpiryResponse], error) {
+	ac := auth.FromContext(ctx)
+	if err := auth.CanReadSubscriptions(ac); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rows, err := s.TLSRepo.FindExpiringBefore(ctx, now.AddDate(0, 0, in.WithinDays))
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_expiring_target_tls failed", err)
+	}
+	visible := auth.FilterClientScoped(ac, rows, func(e *subscription.TargetTLSExpiry) *string { return e.ClientID })
+	items := make([]TargetTLSExpiryItem, 0, len(visible))
+	for i := range visible {
+		items = append(items, TargetTLSExpiryItem{
+			TargetTLSResponse: targetTLSFromEntity(&visible[i].TargetTLS, now),
+			Code:              visible[i].Code,
+			ClientID:          visible[i].ClientID,
+		})
+	}
+	return &apicommon.Out[TargetTLSExpiryResponse]{Body: TargetTLSExpiryResponse{Items: items}}, nil
+}
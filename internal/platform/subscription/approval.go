@@ -0,0 +1,58 @@
+package subscription
+
+import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+)
+
+// The platform config property that puts a tenant under subscription
+// approval: CLIENT scope, value "true".
+const (
+	ApprovalConfigApp      = "platform"
+	ApprovalConfigSection  = "subscriptions"
+	ApprovalConfigProperty = "approval-required"
+)
+
+// Decision is an approver's verdict on a PENDING_APPROVAL subscription.
+type Decision string
+
+const (
+	DecisionApproved Decision = "APPROVED"
+	DecisionRejected Decision = "REJECTED"
+)
+
+// Status is the subscription status the decision moves to.
+func (d Decision) Status() Status {
+	if d == DecisionApproved {
+		return StatusActive
+	}
+	return StatusRejected
+}
+
+// ApprovalDecision is one recorded verdict (msg_subscription_approvals).
+// Decisions are append-only: they are the audit trail of who let a
+// subscription start receiving events, or turned it down, and why.
+type ApprovalDecision struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscriptionId"`
+	Decision       Decision  `json:"decision"`
+	DecidedBy      string    `json:"decidedBy"`
+	Reason         *string   `json:"reason,omitempty"`
+	DecidedAt      time.Time `json:"decidedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (d ApprovalDecision) IDStr() string { return d.ID }
+
+// NewApprovalDecision records decidedBy's verdict on subscriptionID now.
+func NewApprovalDecision(subscriptionID string, decision Decision, decidedBy string, reason *string) *ApprovalDecision {
+	return &ApprovalDecision{
+		ID:             tsid.Generate(tsid.SubscriptionApproval),
+		SubscriptionID: subscriptionID,
+		Decision:       decision,
+		DecidedBy:      decidedBy,
+		Reason:         reason,
+		DecidedAt:      time.Now().UTC(),
+	}
+}
@@ -0,0 +1,101 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// ErrNotPendingApproval is Persist's answer when the subscription left
+// PENDING_APPROVAL between load and decision (a concurrent decision or a
+// delete).
+var ErrNotPendingApproval = errors.New("subscription is no longer pending approval")
+
+// ApprovalRepository is the Postgres-backed repo for
+// msg_subscription_approvals. Persisting a decision also moves the
+// subscription out of PENDING_APPROVAL, in the same transaction.
+type ApprovalRepository struct{ pool *pgxpool.Pool }
+
+// Approvals returns the sibling approval repo on the same pool.
+func (r *Repository) Approvals() *ApprovalRepository { return &ApprovalRepository{pool: r.pool} }
+
+// ApprovalRequired reports whether subscriptions for clientID need
+// approval: the client has the CLIENT-scoped platform config
+// platform:subscriptions:approval-required set to "true". Platform-wide
+// subscriptions (nil clientID) never do.
+func (r *Repository) ApprovalRequired(ctx context.Context, clientID *string) (bool, error) {
+	if clientID == nil {
+		return false, nil
+	}
+	var value string
+	err := r.pool.QueryRow(ctx,
+		`SELECT value FROM app_platform_configs
+		  WHERE application_code = $1 AND section = $2 AND property = $3
+		    AND scope = 'CLIENT' AND client_id = $4`,
+		ApprovalConfigApp, ApprovalConfigSection, ApprovalConfigProperty, *clientID).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("subscription ApprovalRequired: %w", err)
+	}
+	return strings.EqualFold(strings.TrimSpace(value), "true"), nil
+}
+
+// Persist implements usecasepgx.Persist[ApprovalDecision]: it records the
+// decision and sets the subscription's status from it, guarded on the
+// subscription still being PENDING_APPROVAL.
+func (r *ApprovalRepository) Persist(ctx context.Context, d *ApprovalDecision, tx *usecasepgx.DbTx) error {
+	tag, err := tx.Inner().Exec(ctx,
+		`UPDATE msg_subscriptions SET status = $2, updated_at = $3
+		  WHERE id = $1 AND status = $4`,
+		d.SubscriptionID, string(d.Decision.Status()), d.DecidedAt, string(StatusPendingApproval))
+	if err != nil {
+		return fmt.Errorf("subscription approval persist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotPendingApproval
+	}
+	if _, err := tx.Inner().Exec(ctx,
+		`INSERT INTO msg_subscription_approvals (id, subscription_id, decision, decided_by, reason, decided_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		d.ID, d.SubscriptionID, string(d.Decision), d.DecidedBy, d.Reason, d.DecidedAt); err != nil {
+		return fmt.Errorf("subscription approval persist: %w", err)
+	}
+	return nil
+}
+
+// Delete is unsupported: decisions are the audit trail.
+func (r *ApprovalRepository) Delete(context.Context, *ApprovalDecision, *usecasepgx.DbTx) error {
+	return errors.New("subscription approval decisions are append-only")
+}
+
+// FindBySubscription lists a subscription's decisions, newest first.
+func (r *ApprovalRepository) FindBySubscription(ctx context.Context, subscriptionID string) ([]ApprovalDecision, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, subscription_id, decision, decided_by, reason, decided_at
+		   FROM msg_subscription_approvals
+		  WHERE subscription_id = $1
+		  ORDER BY decided_at DESC, id DESC`, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("subscription approvals: %w", err)
+	}
+	defer rows.Close()
+	out := []ApprovalDecision{}
+	for rows.Next() {
+		var d ApprovalDecision
+		var decision string
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &decision, &d.DecidedBy, &d.Reason, &d.DecidedAt); err != nil {
+			return nil, fmt.Errorf("subscription approvals: %w", err)
+		}
+		d.Decision = Decision(decision)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,32 @@
+package subscription_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+func TestParseStatus_ApprovalStates(t *testing.T) {
+	assert.Equal(t, subscription.StatusPendingApproval, subscription.ParseStatus("PENDING_APPROVAL"))
+	assert.Equal(t, subscription.StatusRejected, subscription.ParseStatus("REJECTED"))
+	assert.Equal(t, subscription.StatusPaused, subscription.ParseStatus("PAUSED"))
+	assert.Equal(t, subscription.StatusActive, subscription.ParseStatus("bogus"), "unknown reads as ACTIVE")
+
+	assert.True(t, subscription.StatusActive.Approved())
+	assert.True(t, subscription.StatusPaused.Approved())
+	assert.False(t, subscription.StatusPendingApproval.Approved())
+	assert.False(t, subscription.StatusRejected.Approved())
+}
+
+func TestDecisionStatus(t *testing.T) {
+	assert.Equal(t, subscription.StatusActive, subscription.DecisionApproved.Status())
+	assert.Equal(t, subscription.StatusRejected, subscription.DecisionRejected.Status())
+
+	reason := "ok"
+	d := subscription.NewApprovalDecision("sub_x", subscription.DecisionApproved, "prn_approver", &reason)
+	assert.Regexp(t, `^sap_`, d.ID)
+	assert.Equal(t, d.ID, d.IDStr())
+	assert.False(t, d.DecidedAt.IsZero())
+}
@@ -0,0 +1,54 @@
+package subscription
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// BulkFilter selects subscriptions for a bulk pause/resume/delete. Every
+// non-nil field must match. TargetHost compares against the host of the
+// subscription's endpoint, case-insensitively; it matches with or without
+// a port ("hooks.acme.com" matches "https://hooks.acme.com:8443/x", while
+// "hooks.acme.com:8443" only matches that port).
+type BulkFilter struct {
+	ClientID        *string
+	Status          *string
+	ApplicationCode *string
+	TargetHost      *string
+}
+
+// Empty reports whether no criteria are set. Bulk operations refuse an
+// empty filter so a missing field can't pause every subscription.
+func (f BulkFilter) Empty() bool {
+	return f.ClientID == nil && f.Status == nil && f.ApplicationCode == nil && f.TargetHost == nil
+}
+
+// Matches reports whether s satisfies every set criterion.
+func (f BulkFilter) Matches(s *Subscription) bool {
+	if f.ClientID != nil && (s.ClientID == nil || *s.ClientID != *f.ClientID) {
+		return false
+	}
+	if f.Status != nil && string(s.Status) != *f.Status {
+		return false
+	}
+	if f.ApplicationCode != nil && (s.ApplicationCode == nil || *s.ApplicationCode != *f.ApplicationCode) {
+		return false
+	}
+	if f.TargetHost != nil && !hostMatches(s.Endpoint, *f.TargetHost) {
+		return false
+	}
+	return true
+}
+
+func hostMatches(endpoint, want string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	want = strings.ToLower(strings.TrimSpace(want))
+	if _, _, err := net.SplitHostPort(want); err == nil {
+		return strings.EqualFold(u.Host, want)
+	}
+	return strings.EqualFold(u.Hostname(), want)
+}
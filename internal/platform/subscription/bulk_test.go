@@ -0,0 +1,37 @@
+package subscription_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+func TestBulkFilter_TargetHost(t *testing.T) {
+	s := subscription.New("orders", "Orders", "https://Hooks.Acme.com:8443/orders")
+
+	assert.True(t, subscription.BulkFilter{TargetHost: ptr("hooks.acme.com")}.Matches(s))
+	assert.True(t, subscription.BulkFilter{TargetHost: ptr("hooks.acme.com:8443")}.Matches(s))
+	assert.False(t, subscription.BulkFilter{TargetHost: ptr("hooks.acme.com:443")}.Matches(s))
+	assert.False(t, subscription.BulkFilter{TargetHost: ptr("acme.com")}.Matches(s), "no suffix matching")
+
+	s.Endpoint = "not a url"
+	assert.False(t, subscription.BulkFilter{TargetHost: ptr("hooks.acme.com")}.Matches(s))
+}
+
+func TestBulkFilter_AllCriteriaMustMatch(t *testing.T) {
+	s := subscription.New("orders", "Orders", "https://hooks.acme.com/orders")
+	s.ClientID = ptr("clt_1")
+	s.ApplicationCode = ptr("shop")
+
+	f := subscription.BulkFilter{ClientID: ptr("clt_1"), Status: ptr("ACTIVE"), ApplicationCode: ptr("shop"), TargetHost: ptr("hooks.acme.com")}
+	assert.True(t, f.Matches(s))
+
+	s.Pause()
+	assert.False(t, f.Matches(s))
+
+	assert.False(t, subscription.BulkFilter{ClientID: ptr("clt_2")}.Matches(s))
+	assert.True(t, subscription.BulkFilter{}.Empty())
+	assert.False(t, f.Empty())
+}
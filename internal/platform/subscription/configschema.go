@@ -0,0 +1,286 @@
+package subscription
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// ConfigFieldType is the value type of a managed custom-config field.
+type ConfigFieldType string
+
+const (
+	ConfigFieldString  ConfigFieldType = "STRING"
+	ConfigFieldInteger ConfigFieldType = "INTEGER"
+	ConfigFieldBoolean ConfigFieldType = "BOOLEAN"
+	ConfigFieldURL     ConfigFieldType = "URL"
+	ConfigFieldEnum    ConfigFieldType = "ENUM"
+)
+
+// ParseConfigFieldType is the lenient parser. Unknown → STRING.
+func ParseConfigFieldType(s string) ConfigFieldType {
+	switch ConfigFieldType(strings.ToUpper(s)) {
+	case ConfigFieldInteger:
+		return ConfigFieldInteger
+	case ConfigFieldBoolean:
+		return ConfigFieldBoolean
+	case ConfigFieldURL:
+		return ConfigFieldURL
+	case ConfigFieldEnum:
+		return ConfigFieldEnum
+	default:
+		return ConfigFieldString
+	}
+}
+
+// MaskedValue replaces secret values on the wire. Sending it back on an
+// update means "keep the stored value".
+const MaskedValue = "********"
+
+// ConfigField describes one key a subscription's CustomConfig may carry.
+type ConfigField struct {
+	Key         string          `json:"key"`
+	Label       string          `json:"label,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Type        ConfigFieldType `json:"type"`
+	Required    bool            `json:"required,omitempty"`
+	Secret      bool            `json:"secret,omitempty"`
+	Default     *string         `json:"default,omitempty"`
+	// Options lists the allowed values of an ENUM field.
+	Options []string `json:"options,omitempty"`
+	// Pattern is an optional regexp a STRING value must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Aliases are legacy keys renamed to Key on the next save, so entries
+	// written before the schema existed migrate instead of failing.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ConfigSchema is the managed shape of CustomConfig for one scope: an
+// application (or the platform default when ApplicationCode is nil) and a
+// mediation type. Stored in msg_subscription_config_schemas.
+type ConfigSchema struct {
+	ID              string               `json:"id"`
+	ApplicationCode *string              `json:"applicationCode,omitempty"`
+	MediationType   common.MediationType `json:"mediationType"`
+	Description     *string              `json:"description,omitempty"`
+	Fields          []ConfigField        `json:"fields"`
+	CreatedAt       time.Time            `json:"createdAt"`
+	UpdatedAt       time.Time            `json:"updatedAt"`
+}
+
+// IDStr satisfies usecase.HasID.
+func (c ConfigSchema) IDStr() string { return c.ID }
+
+// NewConfigSchema constructs a schema for the given scope.
+func NewConfigSchema(applicationCode *string, mediationType common.MediationType, fields []ConfigField) *ConfigSchema {
+	now := time.Now().UTC()
+	if mediationType == "" {
+		mediationType = common.MediationTypeHTTP
+	}
+	return &ConfigSchema{
+		ID:              tsid.Generate(tsid.SubscriptionConfigSchema),
+		ApplicationCode: applicationCode,
+		MediationType:   mediationType,
+		Fields:          fields,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Check validates the schema definition itself: unique non-empty keys
+// (aliases included), options for ENUM fields, compilable patterns and
+// defaults that satisfy their own field.
+func (c *ConfigSchema) Check() error {
+	seen := map[string]bool{}
+	for i, f := range c.Fields {
+		if strings.TrimSpace(f.Key) == "" {
+			return usecase.Validation("FIELD_KEY_REQUIRED", fmt.Sprintf("fields[%d].key is required", i))
+		}
+		if len(f.Key) > 100 {
+			return usecase.Validation("FIELD_KEY_TOO_LONG", fmt.Sprintf("field '%s': key exceeds 100 characters", f.Key))
+		}
+		for _, k := range append([]string{f.Key}, f.Aliases...) {
+			if seen[k] {
+				return usecase.Validation("DUPLICATE_FIELD_KEY", fmt.Sprintf("key '%s' is declared more than once", k))
+			}
+			seen[k] = true
+		}
+		if f.Type == ConfigFieldEnum && len(f.Options) == 0 {
+			return usecase.Validation("ENUM_OPTIONS_REQUIRED", fmt.Sprintf("field '%s': ENUM fields need options", f.Key))
+		}
+		if f.Pattern != "" {
+			if _, err := regexp.Compile(f.Pattern); err != nil {
+				return usecase.Validation("INVALID_FIELD_PATTERN", fmt.Sprintf("field '%s': %v", f.Key, err))
+			}
+		}
+		if f.Default != nil {
+			if err := f.check(*f.Default); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Apply validates entries against the schema and returns the normalised
+// set: aliased keys renamed, defaults filled for missing fields, booleans
+// canonicalised, and entries ordered as the schema declares them. Keys the
+// schema does not declare are rejected.
+func (c *ConfigSchema) Apply(entries []ConfigEntry) ([]ConfigEntry, error) {
+	byKey := map[string]*ConfigField{}
+	for i := range c.Fields {
+		f := &c.Fields[i]
+		byKey[f.Key] = f
+		for _, a := range f.Aliases {
+			byKey[a] = f
+		}
+	}
+
+	values := map[string]string{}
+	for _, e := range entries {
+		f, ok := byKey[e.Key]
+		if !ok {
+			return nil, usecase.Validation("UNKNOWN_CONFIG_KEY",
+				fmt.Sprintf("custom config key '%s' is not declared by the config schema", e.Key))
+		}
+		if _, dup := values[f.Key]; dup {
+			return nil, usecase.Validation("DUPLICATE_CONFIG_KEY",
+				fmt.Sprintf("custom config key '%s' is set more than once", f.Key))
+		}
+		values[f.Key] = e.Value
+	}
+
+	out := make([]ConfigEntry, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		v, ok := values[f.Key]
+		if !ok && f.Default != nil {
+			v, ok = *f.Default, true
+		}
+		if !ok {
+			if f.Required {
+				return nil, usecase.Validation("CONFIG_KEY_REQUIRED",
+					fmt.Sprintf("custom config key '%s' is required", f.Key))
+			}
+			continue
+		}
+		if err := f.check(v); err != nil {
+			return nil, err
+		}
+		if f.Type == ConfigFieldBoolean {
+			b, _ := strconv.ParseBool(v)
+			v = strconv.FormatBool(b)
+		}
+		out = append(out, ConfigEntry{Key: f.Key, Value: v})
+	}
+	return out, nil
+}
+
+// check validates a single value against the field's type and constraints.
+func (f ConfigField) check(v string) error {
+	invalid := func(why string) error {
+		return usecase.Validation("INVALID_CONFIG_VALUE", fmt.Sprintf("custom config '%s': %s", f.Key, why))
+	}
+	if f.Required && v == "" {
+		return invalid("value is required")
+	}
+	if len(v) > 1000 {
+		return invalid("value exceeds 1000 characters")
+	}
+	switch f.Type {
+	case ConfigFieldInteger:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return invalid("must be an integer")
+		}
+	case ConfigFieldBoolean:
+		if _, err := strconv.ParseBool(v); err != nil {
+			return invalid("must be true or false")
+		}
+	case ConfigFieldURL:
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid("must be a http(s) URL")
+		}
+	case ConfigFieldEnum:
+		if !slices.Contains(f.Options, v) {
+			return invalid("must be one of " + strings.Join(f.Options, ", "))
+		}
+	default:
+		if f.Pattern != "" {
+			// Pattern compiled in Check; a stored schema is always checked.
+			if re, err := regexp.Compile(f.Pattern); err == nil && !re.MatchString(v) {
+				return invalid("does not match " + f.Pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// IsSecret reports whether key holds a secret value.
+func (c *ConfigSchema) IsSecret(key string) bool {
+	for _, f := range c.Fields {
+		if f.Key == key {
+			return f.Secret
+		}
+	}
+	return false
+}
+
+// Mask returns a copy of entries with secret values replaced by MaskedValue.
+// A nil schema masks nothing.
+func (c *ConfigSchema) Mask(entries []ConfigEntry) []ConfigEntry {
+	out := make([]ConfigEntry, len(entries))
+	for i, e := range entries {
+		if c != nil && c.IsSecret(e.Key) && e.Value != "" {
+			e.Value = MaskedValue
+		}
+		out[i] = e
+	}
+	return out
+}
+
+// Unmask restores stored secret values for entries that came back from the
+// wire as MaskedValue, so a form round-trip does not overwrite secrets.
+func (c *ConfigSchema) Unmask(entries, stored []ConfigEntry) []ConfigEntry {
+	prev := make(map[string]string, len(stored))
+	for _, e := range stored {
+		prev[e.Key] = e.Value
+	}
+	out := make([]ConfigEntry, len(entries))
+	for i, e := range entries {
+		if e.Value == MaskedValue && c.IsSecret(e.Key) {
+			e.Value = prev[e.Key]
+		}
+		out[i] = e
+	}
+	return out
+}
+
+// Governing picks the schema for applicationCode and mediationType from an
+// already-loaded set, with the same precedence as
+// ConfigSchemaRepository.Resolve. Lets list endpoints resolve many
+// subscriptions with one query.
+func Governing(schemas []ConfigSchema, applicationCode *string, mediationType common.MediationType) *ConfigSchema {
+	var fallback *ConfigSchema
+	for i := range schemas {
+		c := &schemas[i]
+		if c.MediationType != mediationType {
+			continue
+		}
+		if c.ApplicationCode == nil {
+			fallback = c
+			continue
+		}
+		if applicationCode != nil && *c.ApplicationCode == *applicationCode {
+			return c
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,107 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// ConfigSchemaRepository is the Postgres-backed repo for
+// msg_subscription_config_schemas. Fields round-trip as a JSONB array.
+type ConfigSchemaRepository struct{ q *dbq.Queries }
+
+// NewConfigSchemaRepository wires a repo.
+func NewConfigSchemaRepository(pool *pgxpool.Pool) *ConfigSchemaRepository {
+	return &ConfigSchemaRepository{q: dbq.New(pool)}
+}
+
+// FindByID loads a schema by id.
+func (r *ConfigSchemaRepository) FindByID(ctx context.Context, id string) (*ConfigSchema, error) {
+	res, err := r.q.SubscriptionConfigSchemaFindByID(ctx, id)
+	row, err := repocommon.One(res, err, "subscription_config_schema repo")
+	if row == nil || err != nil {
+		return nil, err
+	}
+	return rowToConfigSchema(*row)
+}
+
+// FindAll returns every schema, platform defaults first.
+func (r *ConfigSchemaRepository) FindAll(ctx context.Context) ([]ConfigSchema, error) {
+	rows, err := r.q.SubscriptionConfigSchemaFindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ConfigSchema, 0, len(rows))
+	for _, row := range rows {
+		c, err := rowToConfigSchema(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+// Resolve returns the schema governing a subscription of applicationCode
+// (nil for UI-authored rows) and mediationType: the application-specific
+// schema if one exists, else the platform default. nil means no schema
+// applies and CustomConfig stays free-form.
+func (r *ConfigSchemaRepository) Resolve(ctx context.Context, applicationCode *string, mediationType common.MediationType) (*ConfigSchema, error) {
+	res, err := r.q.SubscriptionConfigSchemaResolve(ctx, dbq.SubscriptionConfigSchemaResolveParams{
+		MediationType:   string(mediationType),
+		ApplicationCode: applicationCode,
+	})
+	row, err := repocommon.One(res, err, "subscription_config_schema repo")
+	if row == nil || err != nil {
+		return nil, err
+	}
+	return rowToConfigSchema(*row)
+}
+
+// Persist implements usecasepgx.Persist[ConfigSchema].
+func (r *ConfigSchemaRepository) Persist(ctx context.Context, c *ConfigSchema, tx *usecasepgx.DbTx) error {
+	fields, err := json.Marshal(c.Fields)
+	if err != nil {
+		return fmt.Errorf("subscription_config_schema persist: %w", err)
+	}
+	return r.q.WithTx(tx.Inner()).SubscriptionConfigSchemaUpsert(ctx, dbq.SubscriptionConfigSchemaUpsertParams{
+		ID:              c.ID,
+		ApplicationCode: c.ApplicationCode,
+		MediationType:   string(c.MediationType),
+		Description:     c.Description,
+		Fields:          fields,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       time.Now().UTC(),
+	})
+}
+
+// Delete implements usecasepgx.Persist[ConfigSchema].Delete.
+func (r *ConfigSchemaRepository) Delete(ctx context.Context, c *ConfigSchema, tx *usecasepgx.DbTx) error {
+	return r.q.WithTx(tx.Inner()).SubscriptionConfigSchemaDelete(ctx, c.ID)
+}
+
+func rowToConfigSchema(row dbq.MsgSubscriptionConfigSchema) (*ConfigSchema, error) {
+	c := &ConfigSchema{
+		ID:              row.ID,
+		ApplicationCode: row.ApplicationCode,
+		MediationType:   common.MediationType(row.MediationType),
+		Description:     row.Description,
+		Fields:          []ConfigField{},
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+	if len(row.Fields) > 0 {
+		if err := json.Unmarshal(row.Fields, &c.Fields); err != nil {
+			return nil, fmt.Errorf("subscription_config_schema %s: decode fields: %w", row.ID, err)
+		}
+	}
+	return c, nil
+}
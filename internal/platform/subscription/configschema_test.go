@@ -0,0 +1,91 @@
+package subscription_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func testSchema() *subscription.ConfigSchema {
+	return subscription.NewConfigSchema(nil, common.MediationTypeHTTP, []subscription.ConfigField{
+		{Key: "region", Type: subscription.ConfigFieldEnum, Options: []string{"eu", "us"}, Required: true},
+		{Key: "batchSize", Type: subscription.ConfigFieldInteger, Default: ptr("50")},
+		{Key: "compress", Type: subscription.ConfigFieldBoolean},
+		{Key: "apiKey", Type: subscription.ConfigFieldString, Secret: true, Aliases: []string{"api_key"}},
+	})
+}
+
+func TestConfigSchemaApplyNormalises(t *testing.T) {
+	got, err := testSchema().Apply([]subscription.ConfigEntry{
+		{Key: "compress", Value: "1"},
+		{Key: "api_key", Value: "k-123"},
+		{Key: "region", Value: "eu"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []subscription.ConfigEntry{
+		{Key: "region", Value: "eu"},
+		{Key: "batchSize", Value: "50"},
+		{Key: "compress", Value: "true"},
+		{Key: "apiKey", Value: "k-123"},
+	}, got, "schema order, default filled, alias renamed, boolean canonical")
+}
+
+func TestConfigSchemaApplyRejects(t *testing.T) {
+	cases := map[string][]subscription.ConfigEntry{
+		"missing required": {},
+		"unknown key":      {{Key: "region", Value: "eu"}, {Key: "colour", Value: "red"}},
+		"bad enum":         {{Key: "region", Value: "apac"}},
+		"bad integer":      {{Key: "region", Value: "eu"}, {Key: "batchSize", Value: "lots"}},
+		"alias and key":    {{Key: "region", Value: "eu"}, {Key: "apiKey", Value: "a"}, {Key: "api_key", Value: "b"}},
+	}
+	for name, entries := range cases {
+		_, err := testSchema().Apply(entries)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestConfigSchemaCheck(t *testing.T) {
+	bad := map[string][]subscription.ConfigField{
+		"empty key":       {{Key: " ", Type: subscription.ConfigFieldString}},
+		"duplicate alias": {{Key: "a", Aliases: []string{"b"}}, {Key: "b"}},
+		"enum no options": {{Key: "a", Type: subscription.ConfigFieldEnum}},
+		"bad pattern":     {{Key: "a", Pattern: "("}},
+		"bad default":     {{Key: "a", Type: subscription.ConfigFieldURL, Default: ptr("ftp://x")}},
+	}
+	for name, fields := range bad {
+		c := subscription.ConfigSchema{Fields: fields}
+		assert.Error(t, c.Check(), name)
+	}
+	assert.NoError(t, testSchema().Check())
+}
+
+func TestConfigSchemaMaskRoundTrip(t *testing.T) {
+	c := testSchema()
+	stored := []subscription.ConfigEntry{{Key: "region", Value: "eu"}, {Key: "apiKey", Value: "k-123"}}
+
+	masked := c.Mask(stored)
+	assert.Equal(t, subscription.MaskedValue, masked[1].Value)
+	assert.Equal(t, "k-123", stored[1].Value, "Mask must not mutate its input")
+
+	assert.Equal(t, stored, c.Unmask(masked, stored))
+
+	var none *subscription.ConfigSchema
+	assert.Equal(t, stored, none.Mask(stored), "no schema, nothing secret")
+}
+
+func TestGoverningPrefersApplicationSchema(t *testing.T) {
+	platform := subscription.NewConfigSchema(nil, common.MediationTypeHTTP, nil)
+	orders := subscription.NewConfigSchema(ptr("orders"), common.MediationTypeHTTP, nil)
+	all := []subscription.ConfigSchema{*platform, *orders}
+
+	assert.Equal(t, orders.ID, subscription.Governing(all, ptr("orders"), common.MediationTypeHTTP).ID)
+	assert.Equal(t, platform.ID, subscription.Governing(all, ptr("billing"), common.MediationTypeHTTP).ID)
+	assert.Equal(t, platform.ID, subscription.Governing(all, nil, common.MediationTypeHTTP).ID)
+	assert.Nil(t, subscription.Governing(all[1:], nil, common.MediationTypeHTTP))
+}
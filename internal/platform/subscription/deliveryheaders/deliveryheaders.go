@@ -0,0 +1,193 @@
+// Package deliveryheaders stamps the standard X-FlowCatalyst-* headers on
+// a webhook delivery: delivery and event ids, event type, attempt number,
+// first attempt time, subscription id, an HMAC signature and the event's
+// trace context. A subscription may narrow the set
+// (subscription.Subscription.DeliveryHeaders). Signing secrets are cached
+// per service account for CacheTTL.
+package deliveryheaders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+const (
+	// CacheTTL bounds how stale a service account's signing secret may be
+	// at delivery time.
+	CacheTTL = time.Minute
+	// CacheSize bounds how many service accounts' secrets are cached.
+	CacheSize = 10000
+)
+
+// Header names.
+const (
+	DeliveryID       = "X-FlowCatalyst-Delivery-Id"
+	EventID          = "X-FlowCatalyst-Event-Id"
+	EventType        = "X-FlowCatalyst-Event-Type"
+	DeliveryAttempt  = "X-FlowCatalyst-Delivery-Attempt"
+	FirstAttemptedAt = "X-FlowCatalyst-First-Attempted-At"
+	SubscriptionID   = "X-FlowCatalyst-Subscription-Id"
+	Signature        = callback.SignatureHeader
+	Timestamp        = callback.TimestampHeader
+	Traceparent      = "traceparent"
+	Tracestate       = "tracestate"
+)
+
+// Store resolves a subscription's chosen headers, nil for all of them.
+// Satisfied by *deliverysettings.Cache.
+type Store interface {
+	DeliveryHeaders(ctx context.Context, subscriptionID string) ([]subscription.DeliveryHeader, error)
+}
+
+// Accounts loads the service account whose signing secret signs a
+// delivery. Satisfied by *serviceaccount.Repository.
+type Accounts interface {
+	FindByID(ctx context.Context, id string) (*serviceaccount.ServiceAccount, error)
+}
+
+// Delivery is what the headers describe: one attempt at one job.
+type Delivery struct {
+	ID               string // dispatch job id
+	EventID          string
+	EventType        string
+	SubscriptionID   string
+	ServiceAccountID string
+	Attempt          int32
+	FirstAttemptedAt time.Time
+	Traceparent      string
+	Tracestate       string
+}
+
+// Signing is the secret a delivery is signed with, and the header the
+// signature goes in (Signature when empty). A zero Signing doesn't sign.
+type Signing struct {
+	Secret string
+	Header string
+}
+
+// Set stamps the headers in hs (all of them when nil) on header. body is
+// the final request body; now is the signature timestamp. A header with
+// no value — no event id, no trace context, no signing secret — is left
+// off.
+func Set(header http.Header, hs []subscription.DeliveryHeader, d Delivery, sign Signing, body []byte, now time.Time) {
+	if hs == nil {
+		hs = subscription.AllDeliveryHeaders
+	}
+	set := func(k, v string) {
+		if v != "" {
+			header.Set(k, v)
+		}
+	}
+	for _, h := range hs {
+		switch h {
+		case subscription.HeaderDeliveryID:
+			set(DeliveryID, d.ID)
+		case subscription.HeaderEventID:
+			set(EventID, d.EventID)
+		case subscription.HeaderEventType:
+			set(EventType, d.EventType)
+		case subscription.HeaderDeliveryAttempt:
+			set(DeliveryAttempt, strconv.Itoa(int(d.Attempt)))
+		case subscription.HeaderFirstAttemptedAt:
+			if !d.FirstAttemptedAt.IsZero() {
+				set(FirstAttemptedAt, d.FirstAttemptedAt.UTC().Format(time.RFC3339Nano))
+			}
+		case subscription.HeaderSubscriptionID:
+			set(SubscriptionID, d.SubscriptionID)
+		case subscription.HeaderTraceContext:
+			set(Traceparent, d.Traceparent)
+			if d.Traceparent != "" {
+				set(Tracestate, d.Tracestate)
+			}
+		case subscription.HeaderSignature:
+			if sign.Secret == "" {
+				continue
+			}
+			name := sign.Header
+			if name == "" {
+				name = Signature
+			}
+			// Millisecond-precision ISO8601 UTC, as the router's webhook signature.
+			ts := now.UTC().Format("2006-01-02T15:04:05.000Z")
+			header.Set(Timestamp, ts)
+			header.Set(name, callback.Sign(sign.Secret, ts, body))
+		}
+	}
+}
+
+// Resolver loads each delivery's header set and signing secret. Safe for
+// concurrent use.
+type Resolver struct {
+	store    Store
+	accounts Accounts
+	now      func() time.Time
+
+	signings *lru.LRU[string, Signing]
+}
+
+// New wires a Resolver. accounts may be nil, in which case deliveries
+// are never signed.
+func New(store Store, accounts Accounts) *Resolver {
+	return &Resolver{
+		store:    store,
+		accounts: accounts,
+		now:      time.Now,
+		signings: lru.NewLRU[string, Signing](CacheSize, nil, CacheTTL),
+	}
+}
+
+// Apply stamps d's headers on header, per its subscription's choice.
+func (r *Resolver) Apply(ctx context.Context, header http.Header, d Delivery, body []byte) error {
+	var (
+		hs   []subscription.DeliveryHeader
+		sign Signing
+		err  error
+	)
+	if d.SubscriptionID != "" {
+		if hs, err = r.store.DeliveryHeaders(ctx, d.SubscriptionID); err != nil {
+			return err
+		}
+	}
+	if d.ServiceAccountID != "" && (hs == nil || slices.Contains(hs, subscription.HeaderSignature)) {
+		if sign, err = r.signing(ctx, d.ServiceAccountID); err != nil {
+			return err
+		}
+	}
+	Set(header, hs, d, sign, body, r.now())
+	return nil
+}
+
+func (r *Resolver) signing(ctx context.Context, serviceAccountID string) (Signing, error) {
+	if r.accounts == nil {
+		return Signing{}, nil
+	}
+	if s, ok := r.signings.Get(serviceAccountID); ok {
+		return s, nil
+	}
+
+	sa, err := r.accounts.FindByID(ctx, serviceAccountID)
+	if err != nil {
+		return Signing{}, fmt.Errorf("load signing secret: %w", err)
+	}
+	var s Signing
+	if sa != nil {
+		if c := sa.WebhookCredentials; c.SigningSecret != nil {
+			s.Secret = *c.SigningSecret
+			if c.SignatureHeader != nil {
+				s.Header = *c.SignatureHeader
+			}
+		}
+	}
+	r.signings.Add(serviceAccountID, s)
+	return s, nil
+}
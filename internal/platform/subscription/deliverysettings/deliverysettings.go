@@ -0,0 +1,149 @@
+// Package deliverysettings caches what dispatch reads from a subscription
+// on every delivery (subscription.DeliverySettings): its webhook format,
+// header set, delivery calendar, acknowledgement timeout, egress settings,
+// tap, Retry-After switch and compiled payload transform. One query loads them all; entries
+// live for CacheTTL, so a change reaches pending jobs within that window,
+// and at most CacheSize subscriptions are held.
+package deliverysettings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
+)
+
+const (
+	// CacheTTL bounds how stale a subscription's settings may be at
+	// delivery time.
+	CacheTTL = time.Minute
+	// CacheSize bounds how many subscriptions are cached; the least
+	// recently used are evicted first.
+	CacheSize = 10000
+)
+
+// Store loads a subscription's settings. Satisfied by
+// *subscription.Repository.
+type Store interface {
+	FindDeliverySettings(ctx context.Context, subscriptionID string) (subscription.DeliverySettings, error)
+}
+
+// Error is a lookup failure. Always temporary: the store was unreachable.
+type Error struct{ Err error }
+
+func (e *Error) Error() string   { return e.Err.Error() }
+func (e *Error) Unwrap() error   { return e.Err }
+func (e *Error) Temporary() bool { return true }
+
+// Cache is a bounded, per-entry-TTL cache of delivery settings. Safe for
+// concurrent use.
+type Cache struct {
+	store   Store
+	entries *lru.LRU[string, entry]
+}
+
+// entry holds the settings with their transform compiled once per load.
+type entry struct {
+	settings   subscription.DeliverySettings
+	program    *payloadtransform.Program
+	compileErr error
+}
+
+// New wires a Cache over store.
+func New(store Store) *Cache {
+	return newCache(store, CacheSize, CacheTTL)
+}
+
+func newCache(store Store, size int, ttl time.Duration) *Cache {
+	return &Cache{store: store, entries: lru.NewLRU[string, entry](size, nil, ttl)}
+}
+
+// Settings returns the subscription's delivery settings. A failed load
+// is not cached.
+func (c *Cache) Settings(ctx context.Context, subscriptionID string) (subscription.DeliverySettings, error) {
+	e, err := c.load(ctx, subscriptionID)
+	return e.settings, err
+}
+
+func (c *Cache) load(ctx context.Context, subscriptionID string) (entry, error) {
+	if e, ok := c.entries.Get(subscriptionID); ok {
+		return e, nil
+	}
+	s, err := c.store.FindDeliverySettings(ctx, subscriptionID)
+	if err != nil {
+		return entry{}, &Error{Err: fmt.Errorf("load delivery settings: %w", err)}
+	}
+	e := entry{settings: s}
+	if s.Transform != nil {
+		// Saved templates were compiled on write, so this only fails for
+		// rows edited outside the API.
+		if e.program, e.compileErr = s.Transform.Compile(); e.compileErr != nil {
+			e.compileErr = fmt.Errorf("compile transform: %w", e.compileErr)
+		}
+	}
+	c.entries.Add(subscriptionID, e)
+	return e, nil
+}
+
+// DeliveryFormat returns the subscription's webhook format.
+func (c *Cache) DeliveryFormat(ctx context.Context, subscriptionID string) (subscription.DeliveryFormat, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.Format, err
+}
+
+// DeliveryHeaders returns the standard headers the subscription sends;
+// nil for all of them.
+func (c *Cache) DeliveryHeaders(ctx context.Context, subscriptionID string) ([]subscription.DeliveryHeader, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.Headers, err
+}
+
+// DeliveryWindow returns the subscription's calendar; nil when it has
+// none.
+func (c *Cache) DeliveryWindow(ctx context.Context, subscriptionID string) (*deliverywindow.Schedule, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.Window, err
+}
+
+// AckTimeout returns how long a delivery for the subscription waits for
+// its acknowledgement; zero when the subscription doesn't require one.
+func (c *Cache) AckTimeout(ctx context.Context, subscriptionID string) (time.Duration, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.AckTimeout, err
+}
+
+// Egress returns the subscription's private-target override and proxy.
+func (c *Cache) Egress(ctx context.Context, subscriptionID string) (egress.Settings, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.Egress, err
+}
+
+// IsTap reports whether the subscription is a tap.
+func (c *Cache) IsTap(ctx context.Context, subscriptionID string) (bool, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.Tap != nil, err
+}
+
+// HonorRetryAfter reports whether deliveries wait out the receiver's
+// Retry-After.
+func (c *Cache) HonorRetryAfter(ctx context.Context, subscriptionID string) (bool, error) {
+	s, err := c.Settings(ctx, subscriptionID)
+	return s.HonorRetryAfter, err
+}
+
+// Program returns the subscription's compiled payload transform; nil when
+// it has none. A template that doesn't compile fails with an error that
+// isn't an *Error: retrying won't help.
+func (c *Cache) Program(ctx context.Context, subscriptionID string) (*payloadtransform.Program, error) {
+	e, err := c.load(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return e.program, e.compileErr
+}
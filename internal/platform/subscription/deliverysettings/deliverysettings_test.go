@@ -0,0 +1,119 @@
+package deliverysettings
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
+)
+
+type fakeStore struct {
+	calls    map[string]int
+	settings subscription.DeliverySettings
+	err      error
+}
+
+func (f *fakeStore) FindDeliverySettings(_ context.Context, id string) (subscription.DeliverySettings, error) {
+	f.calls[id]++
+	return f.settings, f.err
+}
+
+func newFakeStore(s subscription.DeliverySettings) *fakeStore {
+	return &fakeStore{calls: map[string]int{}, settings: s}
+}
+
+func TestCache_OneLoadServesEverySetting(t *testing.T) {
+	ctx := context.Background()
+	window := &deliverywindow.Schedule{Windows: []deliverywindow.Window{{Start: "08:00", End: "18:00"}}}
+	store := newFakeStore(subscription.DeliverySettings{
+		Format:     subscription.DeliveryCloudEventsBinary,
+		Headers:    []subscription.DeliveryHeader{subscription.HeaderDeliveryID},
+		Window:     window,
+		AckTimeout: 30 * time.Second,
+		Egress:     egress.Settings{AllowPrivate: true, Proxy: "eu"},
+		Tap:        subscription.NewTap(5, time.Hour),
+	})
+	c := New(store)
+
+	f, err := c.DeliveryFormat(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.Equal(t, subscription.DeliveryCloudEventsBinary, f)
+	hs, err := c.DeliveryHeaders(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.Equal(t, []subscription.DeliveryHeader{subscription.HeaderDeliveryID}, hs)
+	dw, err := c.DeliveryWindow(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.Same(t, window, dw)
+	ack, err := c.AckTimeout(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, ack)
+	eg, err := c.Egress(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.Equal(t, egress.Settings{AllowPrivate: true, Proxy: "eu"}, eg)
+	tap, err := c.IsTap(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.True(t, tap)
+	assert.Equal(t, 1, store.calls["sub_1"])
+
+	store.settings = subscription.DeliverySettings{}
+	tap, err = c.IsTap(ctx, "sub_2")
+	require.NoError(t, err)
+	assert.False(t, tap, "an ordinary subscription")
+	assert.Equal(t, 1, store.calls["sub_2"])
+}
+
+func TestCache_EntriesExpire(t *testing.T) {
+	store := newFakeStore(subscription.DeliverySettings{})
+	c := newCache(store, 10, 10*time.Millisecond)
+	_, err := c.Settings(context.Background(), "sub_1")
+	require.NoError(t, err)
+
+	store.settings.Egress.AllowPrivate = true
+	time.Sleep(20 * time.Millisecond)
+	eg, err := c.Egress(context.Background(), "sub_1")
+	require.NoError(t, err)
+	assert.True(t, eg.AllowPrivate, "a change is seen once the entry expires")
+	assert.Equal(t, 2, store.calls["sub_1"])
+}
+
+func TestCache_IsBounded(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore(subscription.DeliverySettings{})
+	c := newCache(store, 2, time.Minute)
+	for _, id := range []string{"sub_1", "sub_2", "sub_3"} {
+		_, err := c.Settings(ctx, id)
+		require.NoError(t, err)
+	}
+	_, err := c.Settings(ctx, "sub_3")
+	require.NoError(t, err)
+	_, err = c.Settings(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.calls["sub_3"])
+	assert.Equal(t, 2, store.calls["sub_1"], "the least recently used entry was evicted")
+}
+
+func TestCache_FailedLoadIsTemporaryAndNotCached(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore(subscription.DeliverySettings{Tap: subscription.NewTap(5, time.Hour)})
+	store.err = errors.New("db down")
+	c := New(store)
+
+	_, err := c.IsTap(ctx, "sub_1")
+	var se *Error
+	require.ErrorAs(t, err, &se)
+	assert.True(t, se.Temporary())
+	assert.ErrorContains(t, err, "db down")
+
+	store.err = nil
+	tap, err := c.IsTap(ctx, "sub_1")
+	require.NoError(t, err)
+	assert.True(t, tap)
+	assert.Equal(t, 2, store.calls["sub_1"])
+}
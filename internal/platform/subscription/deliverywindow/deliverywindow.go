@@ -0,0 +1,264 @@
+// Package deliverywindow is a subscription's delivery calendar: the weekly
+// windows its webhooks may be delivered in, evaluated in the receiver's
+// timezone, and dated blackouts (maintenance, change freezes) when they
+// may not. The scheduler holds a job outside the calendar until it next
+// opens; the router is told when the current window closes.
+package deliverywindow
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schedule is stored as JSON in msg_subscriptions.delivery_window. A nil
+// Schedule is always open.
+type Schedule struct {
+	// Timezone is the IANA zone windows are read in. Empty = UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Windows are the weekly periods delivery is allowed in. None = any
+	// time outside a blackout.
+	Windows []Window `json:"windows,omitempty"`
+	// Blackouts are absolute periods delivery is held, windows or not.
+	Blackouts []Blackout `json:"blackouts,omitempty"`
+}
+
+// Window is a daily period on some weekdays. End before Start crosses
+// midnight (22:00–06:00 on FRI runs into Saturday morning); End "24:00"
+// is the end of the day.
+type Window struct {
+	// Days are MON…SUN. Empty = every day.
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// Blackout is a half-open [Start, End) period.
+type Blackout struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason *string   `json:"reason,omitempty"`
+}
+
+var dayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// maxSteps bounds NextOpen's walk across window and blackout edges.
+const maxSteps = 512
+
+// Parse decodes and validates a stored schedule. Empty or JSON null is
+// nil.
+func Parse(raw []byte) (*Schedule, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s Schedule
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("decode delivery window: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Marshal encodes s for storage. Nil is SQL NULL.
+func Marshal(s *Schedule) ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Validate checks the timezone, the window times and days, and that each
+// blackout ends after it starts.
+func (s *Schedule) Validate() error {
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", s.Timezone)
+		}
+	}
+	for i, w := range s.Windows {
+		start, ok := parseClock(w.Start, false)
+		if !ok {
+			return fmt.Errorf("windows[%d].start must be HH:MM", i)
+		}
+		end, ok := parseClock(w.End, true)
+		if !ok {
+			return fmt.Errorf("windows[%d].end must be HH:MM (or 24:00)", i)
+		}
+		if start == end {
+			return fmt.Errorf("windows[%d] is empty: start equals end", i)
+		}
+		for _, d := range w.Days {
+			if !slices.Contains(dayNames, strings.ToUpper(d)) {
+				return fmt.Errorf("windows[%d].days: %q is not one of MON, TUE, WED, THU, FRI, SAT, SUN", i, d)
+			}
+		}
+	}
+	for i, b := range s.Blackouts {
+		if b.Start.IsZero() || !b.End.After(b.Start) {
+			return fmt.Errorf("blackouts[%d] must end after it starts", i)
+		}
+	}
+	return nil
+}
+
+// Open reports whether delivery is allowed at t.
+func (s *Schedule) Open(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	if _, in := s.blackoutAt(t); in {
+		return false
+	}
+	if len(s.Windows) == 0 {
+		return true
+	}
+	_, in := s.windowAt(t)
+	return in
+}
+
+// NextOpen is the earliest instant at or after t when delivery is
+// allowed. Blackouts are finite, so a valid schedule always reopens; ok
+// is false only when that is more than maxSteps edges away.
+func (s *Schedule) NextOpen(t time.Time) (time.Time, bool) {
+	cur := t
+	for range maxSteps {
+		if s.Open(cur) {
+			return cur, true
+		}
+		next, ok := s.nextEdge(cur)
+		if !ok {
+			return time.Time{}, false
+		}
+		cur = next
+	}
+	return time.Time{}, false
+}
+
+// ClosesAt is when the period open at t ends: the end of its window or
+// the start of the next blackout, whichever is first. ok is false when
+// s is open-ended from t (no windows and no later blackout).
+func (s *Schedule) ClosesAt(t time.Time) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	var (
+		closes time.Time
+		found  bool
+	)
+	if len(s.Windows) > 0 {
+		if end, in := s.windowAt(t); in {
+			closes, found = end, true
+		}
+	}
+	for _, b := range s.Blackouts {
+		if b.Start.After(t) && (!found || b.Start.Before(closes)) {
+			closes, found = b.Start, true
+		}
+	}
+	return closes.UTC(), found
+}
+
+func (s *Schedule) location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// blackoutAt returns the end of the blackout covering t, if any. With
+// overlapping blackouts it is the latest end among them.
+func (s *Schedule) blackoutAt(t time.Time) (time.Time, bool) {
+	var (
+		end time.Time
+		in  bool
+	)
+	for _, b := range s.Blackouts {
+		if !t.Before(b.Start) && t.Before(b.End) && (!in || b.End.After(end)) {
+			end, in = b.End, true
+		}
+	}
+	return end, in
+}
+
+// windowAt returns the end of the window occurrence covering t, if any.
+// The previous day is checked too, for windows that cross midnight.
+func (s *Schedule) windowAt(t time.Time) (time.Time, bool) {
+	loc := s.location()
+	lt := t.In(loc)
+	for back := 0; back <= 1; back++ {
+		day := lt.AddDate(0, 0, -back)
+		for _, w := range s.Windows {
+			start, end, ok := occurrence(w, day, loc)
+			if ok && !t.Before(start) && t.Before(end) {
+				return end, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextEdge is the next instant after a closed t at which Open may turn
+// true: the end of the blackout covering t, else the next window start.
+func (s *Schedule) nextEdge(t time.Time) (time.Time, bool) {
+	if end, in := s.blackoutAt(t); in {
+		return end, true
+	}
+	var (
+		next  time.Time
+		found bool
+	)
+	loc := s.location()
+	lt := t.In(loc)
+	for ahead := 0; ahead <= 7; ahead++ {
+		day := lt.AddDate(0, 0, ahead)
+		for _, w := range s.Windows {
+			if start, _, ok := occurrence(w, day, loc); ok && start.After(t) && (!found || start.Before(next)) {
+				next, found = start, true
+			}
+		}
+	}
+	return next, found
+}
+
+// occurrence is w's period starting on day's date, if w runs that
+// weekday.
+func occurrence(w Window, day time.Time, loc *time.Location) (start, end time.Time, ok bool) {
+	if len(w.Days) > 0 && !slices.ContainsFunc(w.Days, func(d string) bool {
+		return strings.EqualFold(d, dayNames[day.Weekday()])
+	}) {
+		return time.Time{}, time.Time{}, false
+	}
+	sm, ok1 := parseClock(w.Start, false)
+	em, ok2 := parseClock(w.End, true)
+	if !ok1 || !ok2 || sm == em {
+		return time.Time{}, time.Time{}, false
+	}
+	y, m, d := day.Date()
+	start = time.Date(y, m, d, sm/60, sm%60, 0, 0, loc)
+	if em < sm {
+		d++ // crosses midnight
+	}
+	end = time.Date(y, m, d, em/60, em%60, 0, 0, loc)
+	return start, end, true
+}
+
+// parseClock reads "HH:MM" as minutes after midnight. "24:00" is only
+// accepted as an end.
+func parseClock(v string, isEnd bool) (int, bool) {
+	if isEnd && v == "24:00" {
+		return 24 * 60, true
+	}
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
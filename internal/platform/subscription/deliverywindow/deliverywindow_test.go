@@ -0,0 +1,120 @@
+package deliverywindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(t *testing.T, loc, v string) time.Time {
+	t.Helper()
+	l, err := time.LoadLocation(loc)
+	require.NoError(t, err)
+	ts, err := time.ParseInLocation("2006-01-02 15:04", v, l)
+	require.NoError(t, err)
+	return ts
+}
+
+// officeHours is 08:00–18:00 Berlin time on weekdays. 2026-10-16 is a
+// Friday.
+var officeHours = &Schedule{
+	Timezone: "Europe/Berlin",
+	Windows:  []Window{{Days: []string{"MON", "TUE", "WED", "THU", "FRI"}, Start: "08:00", End: "18:00"}},
+}
+
+func TestOpen_WeeklyWindow(t *testing.T) {
+	assert.True(t, officeHours.Open(at(t, "Europe/Berlin", "2026-10-16 08:00")))
+	assert.True(t, officeHours.Open(at(t, "Europe/Berlin", "2026-10-16 17:59")))
+	assert.False(t, officeHours.Open(at(t, "Europe/Berlin", "2026-10-16 18:00")))
+	assert.False(t, officeHours.Open(at(t, "Europe/Berlin", "2026-10-17 12:00")), "Saturday")
+	// The zone is the receiver's, not UTC: 07:30 UTC is 09:30 in Berlin.
+	assert.True(t, officeHours.Open(at(t, "UTC", "2026-10-16 07:30")))
+
+	var none *Schedule
+	assert.True(t, none.Open(time.Now()))
+}
+
+func TestNextOpen(t *testing.T) {
+	// Friday evening → Monday 08:00.
+	next, ok := officeHours.NextOpen(at(t, "Europe/Berlin", "2026-10-16 19:00"))
+	require.True(t, ok)
+	assert.Equal(t, at(t, "Europe/Berlin", "2026-10-19 08:00").UTC(), next.UTC())
+
+	// Already open → unchanged.
+	now := at(t, "Europe/Berlin", "2026-10-16 09:00")
+	next, ok = officeHours.NextOpen(now)
+	require.True(t, ok)
+	assert.Equal(t, now, next)
+}
+
+func TestNextOpen_Blackout(t *testing.T) {
+	s := *officeHours
+	s.Blackouts = []Blackout{{
+		Start: at(t, "Europe/Berlin", "2026-10-19 00:00"),
+		End:   at(t, "Europe/Berlin", "2026-10-20 10:30"),
+	}}
+	// Monday is blacked out and Tuesday reopens mid-window.
+	next, ok := s.NextOpen(at(t, "Europe/Berlin", "2026-10-16 19:00"))
+	require.True(t, ok)
+	assert.Equal(t, at(t, "Europe/Berlin", "2026-10-20 10:30").UTC(), next.UTC())
+
+	// A year-long freeze with no windows reopens when it ends.
+	freeze := &Schedule{Blackouts: []Blackout{{
+		Start: at(t, "UTC", "2026-01-01 00:00"),
+		End:   at(t, "UTC", "2027-01-01 00:00"),
+	}}}
+	next, ok = freeze.NextOpen(at(t, "UTC", "2026-06-01 00:00"))
+	require.True(t, ok)
+	assert.Equal(t, at(t, "UTC", "2027-01-01 00:00"), next)
+}
+
+func TestOvernightWindow(t *testing.T) {
+	s := &Schedule{Windows: []Window{{Days: []string{"fri"}, Start: "22:00", End: "06:00"}}}
+	assert.True(t, s.Open(at(t, "UTC", "2026-10-16 23:00")))
+	assert.True(t, s.Open(at(t, "UTC", "2026-10-17 05:59")), "Saturday morning belongs to Friday's window")
+	assert.False(t, s.Open(at(t, "UTC", "2026-10-17 22:30")))
+
+	closes, ok := s.ClosesAt(at(t, "UTC", "2026-10-16 23:00"))
+	require.True(t, ok)
+	assert.Equal(t, at(t, "UTC", "2026-10-17 06:00"), closes)
+}
+
+func TestClosesAt(t *testing.T) {
+	closes, ok := officeHours.ClosesAt(at(t, "Europe/Berlin", "2026-10-16 09:00"))
+	require.True(t, ok)
+	assert.Equal(t, at(t, "Europe/Berlin", "2026-10-16 18:00").UTC(), closes)
+
+	// A blackout starting first closes the window early.
+	s := *officeHours
+	s.Blackouts = []Blackout{{Start: at(t, "Europe/Berlin", "2026-10-16 12:00"), End: at(t, "Europe/Berlin", "2026-10-16 13:00")}}
+	closes, ok = s.ClosesAt(at(t, "Europe/Berlin", "2026-10-16 09:00"))
+	require.True(t, ok)
+	assert.Equal(t, at(t, "Europe/Berlin", "2026-10-16 12:00").UTC(), closes)
+
+	// No windows and no later blackout: open-ended.
+	_, ok = (&Schedule{}).ClosesAt(time.Now())
+	assert.False(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	for name, s := range map[string]Schedule{
+		"bad zone":     {Timezone: "Mars/Olympus"},
+		"bad start":    {Windows: []Window{{Start: "8am", End: "18:00"}}},
+		"24:00 start":  {Windows: []Window{{Start: "24:00", End: "06:00"}}},
+		"empty window": {Windows: []Window{{Start: "08:00", End: "08:00"}}},
+		"bad day":      {Windows: []Window{{Days: []string{"FUNDAY"}, Start: "08:00", End: "18:00"}}},
+		"backwards":    {Blackouts: []Blackout{{Start: time.Now(), End: time.Now().Add(-time.Hour)}}},
+	} {
+		assert.Error(t, s.Validate(), name)
+	}
+	assert.NoError(t, (&Schedule{Windows: []Window{{Start: "00:00", End: "24:00"}}}).Validate())
+
+	s, err := Parse([]byte(`null`))
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+	s, err = Parse([]byte(`{"timezone":"Europe/Berlin","windows":[{"start":"08:00","end":"18:00"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", s.Timezone)
+}
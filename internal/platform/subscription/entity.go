@@ -3,10 +3,15 @@
 package subscription
 
 import (
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/eventfilter"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
@@ -16,16 +21,27 @@ type Status string
 const (
 	StatusActive Status = "ACTIVE"
 	StatusPaused Status = "PAUSED"
+	// StatusPendingApproval is a subscription created in a tenant that
+	// requires approval, waiting on an approver. It receives no dispatches.
+	StatusPendingApproval Status = "PENDING_APPROVAL"
+	// StatusRejected is a subscription an approver turned down. Terminal:
+	// it can only be deleted.
+	StatusRejected Status = "REJECTED"
 )
 
 // ParseStatus is the lenient parser. Unknown → ACTIVE.
 func ParseStatus(s string) Status {
-	if s == string(StatusPaused) {
-		return StatusPaused
+	switch Status(s) {
+	case StatusPaused, StatusPendingApproval, StatusRejected:
+		return Status(s)
 	}
 	return StatusActive
 }
 
+// Approved reports whether the status is past approval: ACTIVE or PAUSED.
+// Only approved subscriptions can be paused, resumed or receive dispatches.
+func (s Status) Approved() bool { return s == StatusActive || s == StatusPaused }
+
 // Source identifies where the subscription was authored.
 type Source string
 
@@ -47,8 +63,78 @@ func ParseSource(s string) Source {
 	}
 }
 
+// DeliveryFormat is how the dispatch-processing endpoint renders a webhook.
+type DeliveryFormat string
+
+const (
+	// DeliveryFlowCatalyst is the native body: the event envelope, or the
+	// raw payload for data-only subscriptions.
+	DeliveryFlowCatalyst DeliveryFormat = "FLOWCATALYST"
+	// DeliveryCloudEventsBinary sends the event data as the body and the
+	// CloudEvents attributes as ce-* headers.
+	DeliveryCloudEventsBinary DeliveryFormat = "CLOUDEVENTS_BINARY"
+	// DeliveryCloudEventsStructured sends the whole event as
+	// application/cloudevents+json.
+	DeliveryCloudEventsStructured DeliveryFormat = "CLOUDEVENTS_STRUCTURED"
+)
+
+// Valid reports whether f is a known format.
+func (f DeliveryFormat) Valid() bool {
+	switch f {
+	case DeliveryFlowCatalyst, DeliveryCloudEventsBinary, DeliveryCloudEventsStructured:
+		return true
+	}
+	return false
+}
+
+// ParseDeliveryFormat is the lenient parser. Unknown → FLOWCATALYST.
+func ParseDeliveryFormat(s string) DeliveryFormat {
+	if f := DeliveryFormat(s); f.Valid() {
+		return f
+	}
+	return DeliveryFlowCatalyst
+}
+
+// DeliveryHeader names one of the standard X-FlowCatalyst-* headers the
+// dispatch-processing endpoint can add to a delivery (see package
+// deliveryheaders for the header names and values).
+type DeliveryHeader string
+
+const (
+	HeaderDeliveryID       DeliveryHeader = "DELIVERY_ID"
+	HeaderEventID          DeliveryHeader = "EVENT_ID"
+	HeaderEventType        DeliveryHeader = "EVENT_TYPE"
+	HeaderDeliveryAttempt  DeliveryHeader = "DELIVERY_ATTEMPT"
+	HeaderFirstAttemptedAt DeliveryHeader = "FIRST_ATTEMPTED_AT"
+	HeaderSubscriptionID   DeliveryHeader = "SUBSCRIPTION_ID"
+	// HeaderSignature signs the body with the subscription's service
+	// account signing secret; skipped when the account has none.
+	HeaderSignature DeliveryHeader = "SIGNATURE"
+	// HeaderTraceContext forwards the job's W3C traceparent / tracestate
+	// metadata; skipped when the job carries none.
+	HeaderTraceContext DeliveryHeader = "TRACE_CONTEXT"
+)
+
+// AllDeliveryHeaders is every standard header, in the order they are
+// documented. A subscription that doesn't choose sends all of them.
+var AllDeliveryHeaders = []DeliveryHeader{
+	HeaderDeliveryID, HeaderEventID, HeaderEventType, HeaderDeliveryAttempt,
+	HeaderFirstAttemptedAt, HeaderSubscriptionID, HeaderSignature, HeaderTraceContext,
+}
+
+// Valid reports whether h is a known header.
+func (h DeliveryHeader) Valid() bool {
+	for _, k := range AllDeliveryHeaders {
+		if h == k {
+			return true
+		}
+	}
+	return false
+}
+
 // EventTypeBinding maps an event-type pattern (with wildcards) to this
-// subscription. Stored in msg_subscription_event_types.
+// subscription. Stored in msg_subscription_event_types. Filter is an
+// optional eventfilter expression narrowing the bound events by payload.
 type EventTypeBinding struct {
 	EventTypeID   *string `json:"eventTypeId,omitempty"`
 	EventTypeCode string  `json:"eventTypeCode"`
@@ -64,17 +150,16 @@ func NewEventTypeBinding(code string) EventTypeBinding {
 // Matches reports whether the binding's pattern matches the given event-type code.
 // Patterns are colon-separated; `*` matches any single segment.
 func (b EventTypeBinding) Matches(eventTypeCode string) bool {
-	patternParts := strings.Split(b.EventTypeCode, ":")
-	eventParts := strings.Split(eventTypeCode, ":")
-	if len(patternParts) != len(eventParts) {
-		return false
-	}
-	for i, p := range patternParts {
-		if p != "*" && p != eventParts[i] {
-			return false
-		}
+	return eventtype.CodeMatches(b.EventTypeCode, eventTypeCode)
+}
+
+// CompileFilter parses the binding's filter. A nil or blank filter returns
+// a nil program: every event of the bound type matches.
+func (b EventTypeBinding) CompileFilter() (*eventfilter.Program, error) {
+	if b.Filter == nil || strings.TrimSpace(*b.Filter) == "" {
+		return nil, nil
 	}
-	return true
+	return eventfilter.Compile(*b.Filter)
 }
 
 // ConfigEntry is a key/value pair stored in msg_subscription_custom_configs.
@@ -83,36 +168,124 @@ type ConfigEntry struct {
 	Value string `json:"value"`
 }
 
+// Subscription.Weight is the subscription's share of its dispatch pool's
+// workers while other subscriptions have messages waiting too: the router
+// serves them weighted round-robin, Weight messages per turn.
+const (
+	MinWeight     int32 = 1
+	MaxWeight     int32 = 100
+	DefaultWeight int32 = 1
+)
+
+// Subscription.AckTimeoutSeconds bounds: how long a delivery waits for the
+// receiver's acknowledgement before it is delivered again.
+const (
+	MinAckTimeoutSeconds int32 = 30
+	MaxAckTimeoutSeconds int32 = 86400
+)
+
+// Subscription.Tap bounds: the share of matching events a tap receives,
+// and how long it may run.
+const (
+	MinTapSamplePercent = 0.01
+	MaxTapSamplePercent = 100.0
+	DefaultTapLifetime  = time.Hour
+	MaxTapLifetime      = 7 * 24 * time.Hour
+)
+
+// Tap makes a subscription a debugging tap: fan-out hands it only
+// SamplePercent of its matching events, its deliveries aren't metered
+// against the client's quota, and it receives nothing after ExpiresAt.
+type Tap struct {
+	SamplePercent float64   `json:"samplePercent"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// NewTap builds a tap sampling percent of events for lifetime from now.
+func NewTap(percent float64, lifetime time.Duration) *Tap {
+	return &Tap{SamplePercent: percent, ExpiresAt: time.Now().UTC().Add(lifetime)}
+}
+
+// Expired reports whether the tap has stopped receiving events at now.
+func (t *Tap) Expired(now time.Time) bool { return !now.Before(t.ExpiresAt) }
+
+// DeliverySettings is what dispatch reads from a subscription on every
+// delivery. Loaded in one query by Repository.FindDeliverySettings and
+// cached by deliverysettings.Cache.
+type DeliverySettings struct {
+	Format DeliveryFormat
+	// Headers are the standard delivery headers sent; nil for all of them.
+	Headers []DeliveryHeader
+	// Window is the delivery calendar; nil is always open.
+	Window *deliverywindow.Schedule
+	// AckTimeout is how long a delivery waits for the receiver's
+	// acknowledgement; zero when it requires none.
+	AckTimeout time.Duration
+	Egress     egress.Settings
+	// Tap is nil for an ordinary subscription.
+	Tap *Tap
+	// Transform is nil when the default body is sent.
+	Transform *PayloadTransform
+	// HonorRetryAfter waits out a receiver's Retry-After on 429/503.
+	HonorRetryAfter bool
+}
+
 // Subscription is the aggregate root.
 type Subscription struct {
-	ID               string              `json:"id"`
-	Code             string              `json:"code"`
-	ApplicationCode  *string             `json:"applicationCode,omitempty"`
-	Name             string              `json:"name"`
-	Description      *string             `json:"description,omitempty"`
-	ClientID         *string             `json:"clientId,omitempty"`
-	ClientIdentifier *string             `json:"clientIdentifier,omitempty"`
-	ClientScoped     bool                `json:"clientScoped"`
-	EventTypes       []EventTypeBinding  `json:"eventTypes"`
-	ConnectionID     *string             `json:"connectionId,omitempty"`
-	Endpoint         string              `json:"endpoint"`
-	Queue            *string             `json:"queue,omitempty"`
-	CustomConfig     []ConfigEntry       `json:"customConfig"`
-	Source           Source              `json:"source"`
-	Status           Status              `json:"status"`
-	MaxAgeSeconds    int32               `json:"maxAgeSeconds"`
-	DispatchPoolID   *string             `json:"dispatchPoolId,omitempty"`
-	DispatchPoolCode *string             `json:"dispatchPoolCode,omitempty"`
-	DelaySeconds     int32               `json:"delaySeconds"`
-	Sequence         int32               `json:"sequence"`
-	Mode             common.DispatchMode `json:"mode"`
-	TimeoutSeconds   int32               `json:"timeoutSeconds"`
-	MaxRetries       int32               `json:"maxRetries"`
-	ServiceAccountID *string             `json:"serviceAccountId,omitempty"`
-	DataOnly         bool                `json:"dataOnly"`
-	CreatedBy        *string             `json:"createdBy,omitempty"`
-	CreatedAt        time.Time           `json:"createdAt"`
-	UpdatedAt        time.Time           `json:"updatedAt"`
+	ID               string                   `json:"id"`
+	Code             string                   `json:"code"`
+	ApplicationCode  *string                  `json:"applicationCode,omitempty"`
+	Name             string                   `json:"name"`
+	Description      *string                  `json:"description,omitempty"`
+	ClientID         *string                  `json:"clientId,omitempty"`
+	ClientIdentifier *string                  `json:"clientIdentifier,omitempty"`
+	ClientScoped     bool                     `json:"clientScoped"`
+	Environment      common.Environment       `json:"environment"`
+	EventTypes       []EventTypeBinding       `json:"eventTypes"`
+	ConnectionID     *string                  `json:"connectionId,omitempty"`
+	Endpoint         string                   `json:"endpoint"`
+	Queue            *string                  `json:"queue,omitempty"`
+	CustomConfig     []ConfigEntry            `json:"customConfig"`
+	TargetAuth       *TargetAuth              `json:"targetAuth,omitempty"`
+	Transform        *PayloadTransform        `json:"transform,omitempty"`
+	Source           Source                   `json:"source"`
+	Status           Status                   `json:"status"`
+	MaxAgeSeconds    int32                    `json:"maxAgeSeconds"`
+	DispatchPoolID   *string                  `json:"dispatchPoolId,omitempty"`
+	DispatchPoolCode *string                  `json:"dispatchPoolCode,omitempty"`
+	DelaySeconds     int32                    `json:"delaySeconds"`
+	Sequence         int32                    `json:"sequence"`
+	Mode             common.DispatchMode      `json:"mode"`
+	Priority         common.Priority          `json:"priority"`
+	Weight           int32                    `json:"weight"`
+	TimeoutSeconds   int32                    `json:"timeoutSeconds"`
+	MaxRetries       int32                    `json:"maxRetries"`
+	HonorRetryAfter  bool                     `json:"honorRetryAfter"`
+	DeliveryFormat   DeliveryFormat           `json:"deliveryFormat"`
+	DeliveryWindow   *deliverywindow.Schedule `json:"deliveryWindow,omitempty"`
+	// AckTimeoutSeconds turns on receiver acknowledgement: a 2xx doesn't
+	// complete a delivery until the receiver acks its delivery token, and
+	// one unacknowledged this long is delivered again. nil: a 2xx is done.
+	AckTimeoutSeconds *int32 `json:"ackTimeoutSeconds,omitempty"`
+	// DeliveryHeaders picks the standard headers deliveries carry. nil:
+	// all of them (AllDeliveryHeaders).
+	DeliveryHeaders []DeliveryHeader `json:"deliveryHeaders,omitempty"`
+	// AllowPrivateTarget exempts the endpoint from the egress block on
+	// private ranges (shared/egress). Set only with the egress-override
+	// permission.
+	AllowPrivateTarget bool `json:"allowPrivateTarget"`
+	// EgressProxy names the deployment proxy (FC_EGRESS_PROXIES) deliveries
+	// leave through; nil for the deployment default.
+	EgressProxy *string `json:"egressProxy,omitempty"`
+	// Tap, when set, makes this a sampled, expiring debugging tap rather
+	// than a full-volume subscription. Only set at creation; an update may
+	// change the sample or re-arm the expiry.
+	Tap              *Tap      `json:"tap,omitempty"`
+	ServiceAccountID *string   `json:"serviceAccountId,omitempty"`
+	DataOnly         bool      `json:"dataOnly"`
+	CreatedBy        *string   `json:"createdBy,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
 }
 
 // IDStr satisfies usecase.HasID.
@@ -122,24 +295,65 @@ func (s Subscription) IDStr() string { return s.ID }
 func New(code, name, endpoint string) *Subscription {
 	now := time.Now().UTC()
 	return &Subscription{
-		ID:             tsid.Generate(tsid.Subscription),
-		Code:           code,
-		Name:           name,
-		Endpoint:       endpoint,
-		EventTypes:     []EventTypeBinding{},
-		CustomConfig:   []ConfigEntry{},
-		Source:         SourceUI,
-		Status:         StatusActive,
-		MaxAgeSeconds:  86400,
-		DelaySeconds:   0,
-		Sequence:       99,
-		Mode:           common.DispatchImmediate,
-		TimeoutSeconds: 30,
-		MaxRetries:     3,
-		DataOnly:       true,
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		ID:              tsid.Generate(tsid.Subscription),
+		Code:            code,
+		Name:            name,
+		Endpoint:        endpoint,
+		EventTypes:      []EventTypeBinding{},
+		CustomConfig:    []ConfigEntry{},
+		Environment:     common.EnvironmentProduction,
+		Source:          SourceUI,
+		Status:          StatusActive,
+		MaxAgeSeconds:   86400,
+		DelaySeconds:    0,
+		Sequence:        99,
+		Mode:            common.DispatchImmediate,
+		Priority:        common.PriorityNormal,
+		Weight:          DefaultWeight,
+		TimeoutSeconds:  30,
+		MaxRetries:      3,
+		HonorRetryAfter: true,
+		DeliveryFormat:  DeliveryFlowCatalyst,
+		DataOnly:        true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// ProductionCopy is the production counterpart of a sandbox subscription:
+// its configuration under the same code, environment PRODUCTION. current
+// is the production subscription with that code, if any; the copy takes
+// its ID, status and creation stamp so promotion overwrites it in place. A
+// fresh copy is ACTIVE.
+func (s *Subscription) ProductionCopy(current *Subscription) *Subscription {
+	out := *s
+	out.EventTypes = slices.Clone(s.EventTypes)
+	out.CustomConfig = slices.Clone(s.CustomConfig)
+	out.DeliveryHeaders = slices.Clone(s.DeliveryHeaders)
+	out.Environment = common.EnvironmentProduction
+	out.UpdatedAt = time.Now().UTC()
+	if current != nil {
+		out.ID = current.ID
+		out.Status = current.Status
+		out.CreatedBy = current.CreatedBy
+		out.CreatedAt = current.CreatedAt
+	} else {
+		out.ID = tsid.Generate(tsid.Subscription)
+		out.Status = StatusActive
+		out.CreatedAt = out.UpdatedAt
+	}
+	return &out
+}
+
+// InEnvironment returns the subscriptions of subs that live in env.
+func InEnvironment(subs []Subscription, env common.Environment) []Subscription {
+	out := make([]Subscription, 0, len(subs))
+	for _, s := range subs {
+		if s.Environment == env {
+			out = append(out, s)
+		}
 	}
+	return out
 }
 
 // Pause flips status to PAUSED.
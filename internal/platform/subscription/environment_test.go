@@ -0,0 +1,42 @@
+package subscription_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+func TestProductionCopy(t *testing.T) {
+	sb := subscription.New("orders-hook", "Orders", "https://sandbox.example.com/hook")
+	sb.Environment = common.EnvironmentSandbox
+	sb.Status = subscription.StatusPaused
+	sb.EventTypes = []subscription.EventTypeBinding{subscription.NewEventTypeBinding("orders:sales:order:created")}
+
+	fresh := sb.ProductionCopy(nil)
+	assert.NotEqual(t, sb.ID, fresh.ID)
+	assert.Equal(t, common.EnvironmentProduction, fresh.Environment)
+	assert.Equal(t, subscription.StatusActive, fresh.Status, "a fresh copy starts ACTIVE")
+	assert.Equal(t, sb.Endpoint, fresh.Endpoint)
+	fresh.EventTypes[0].EventTypeCode = "changed"
+	assert.Equal(t, "orders:sales:order:created", sb.EventTypes[0].EventTypeCode, "bindings are copied, not shared")
+
+	current := subscription.New("orders-hook", "Orders (old)", "https://prod.example.com/hook")
+	current.Status = subscription.StatusPaused
+	over := sb.ProductionCopy(current)
+	assert.Equal(t, current.ID, over.ID)
+	assert.Equal(t, subscription.StatusPaused, over.Status, "promotion keeps production's status")
+	assert.Equal(t, sb.Endpoint, over.Endpoint)
+}
+
+func TestInEnvironment(t *testing.T) {
+	prod := *subscription.New("a", "A", "https://a.example.com")
+	sb := *subscription.New("b", "B", "https://b.example.com")
+	sb.Environment = common.EnvironmentSandbox
+
+	got := subscription.InEnvironment([]subscription.Subscription{prod, sb}, common.EnvironmentSandbox)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "b", got[0].Code)
+}
@@ -0,0 +1,122 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// maxDecisionReason caps the free-text reason recorded with a decision.
+const maxDecisionReason = 2000
+
+// DecideCommand is the input DTO for approving or rejecting.
+type DecideCommand struct {
+	ID     string  `json:"id"`
+	Reason *string `json:"reason,omitempty"`
+}
+
+// ApproveSubscription approves a PENDING_APPROVAL subscription, making it
+// ACTIVE, records the decision and emits [SubscriptionApproved].
+func ApproveSubscription(repo *subscription.Repository) usecaseop.Operation[DecideCommand, SubscriptionApproved] {
+	return usecaseop.Operation[DecideCommand, SubscriptionApproved]{
+		Name:      "ApproveSubscription",
+		Validate:  func(_ context.Context, cmd DecideCommand) error { return validateDecision(cmd, false) },
+		Authorize: usecaseop.Public[DecideCommand],
+		Execute: func(ctx context.Context, cmd DecideCommand, ec usecase.ExecutionContext) (usecaseop.Plan[SubscriptionApproved], error) {
+			d, err := decide(ctx, repo, cmd, subscription.DecisionApproved, ec)
+			if err != nil {
+				return nil, err
+			}
+			event := SubscriptionApproved{
+				Metadata:       usecase.NewEventMetadata(ec, SubscriptionApprovedType, Source, subjectFor(d.SubscriptionID)),
+				SubscriptionID: d.SubscriptionID,
+				DecisionID:     d.ID,
+				Reason:         d.Reason,
+			}
+			return usecaseop.Save(d, repo.Approvals(), event), nil
+		},
+	}
+}
+
+// RejectSubscription rejects a PENDING_APPROVAL subscription, making it
+// REJECTED, records the decision and emits [SubscriptionRejected]. A
+// reason is required so the creator learns what to change.
+func RejectSubscription(repo *subscription.Repository) usecaseop.Operation[DecideCommand, SubscriptionRejected] {
+	return usecaseop.Operation[DecideCommand, SubscriptionRejected]{
+		Name:      "RejectSubscription",
+		Validate:  func(_ context.Context, cmd DecideCommand) error { return validateDecision(cmd, true) },
+		Authorize: usecaseop.Public[DecideCommand],
+		Execute: func(ctx context.Context, cmd DecideCommand, ec usecase.ExecutionContext) (usecaseop.Plan[SubscriptionRejected], error) {
+			d, err := decide(ctx, repo, cmd, subscription.DecisionRejected, ec)
+			if err != nil {
+				return nil, err
+			}
+			event := SubscriptionRejected{
+				Metadata:       usecase.NewEventMetadata(ec, SubscriptionRejectedType, Source, subjectFor(d.SubscriptionID)),
+				SubscriptionID: d.SubscriptionID,
+				DecisionID:     d.ID,
+				Reason:         d.Reason,
+			}
+			return usecaseop.Save(d, repo.Approvals(), event), nil
+		},
+	}
+}
+
+func validateDecision(cmd DecideCommand, reasonRequired bool) error {
+	if strings.TrimSpace(cmd.ID) == "" {
+		return usecase.Validation("ID_REQUIRED", "id is required")
+	}
+	reason := ""
+	if cmd.Reason != nil {
+		reason = strings.TrimSpace(*cmd.Reason)
+	}
+	if reasonRequired && reason == "" {
+		return usecase.Validation("REASON_REQUIRED", "reason is required")
+	}
+	if len(reason) > maxDecisionReason {
+		return usecase.Validation("REASON_TOO_LONG", "reason must be at most 2000 characters")
+	}
+	return nil
+}
+
+// decide loads the subscription, checks it can be decided by this
+// principal (the approve permission is the controller's), and builds the decision. Persisting it moves the
+// subscription's status, guarded on it still pending.
+func decide(
+	ctx context.Context,
+	repo *subscription.Repository,
+	cmd DecideCommand,
+	decision subscription.Decision,
+	ec usecase.ExecutionContext,
+) (*subscription.ApprovalDecision, error) {
+	s, err := repo.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "find_by_id failed", err)
+	}
+	if s == nil {
+		return nil, httperror.NotFound("Subscription", cmd.ID)
+	}
+	if err := checkAccess(ctx, s); err != nil {
+		return nil, err
+	}
+	if s.Status != subscription.StatusPendingApproval {
+		return nil, usecase.Conflict("NOT_PENDING_APPROVAL",
+			"Subscription is "+string(s.Status)+", not pending approval")
+	}
+	// Four eyes: whoever created the subscription can't decide on it.
+	if s.CreatedBy != nil && *s.CreatedBy == ec.PrincipalID {
+		return nil, usecase.Authorization("SELF_APPROVAL",
+			"a subscription can't be approved or rejected by its creator")
+	}
+	var reason *string
+	if cmd.Reason != nil {
+		if r := strings.TrimSpace(*cmd.Reason); r != "" {
+			reason = &r
+		}
+	}
+	return subscription.NewApprovalDecision(s.ID, decision, ec.PrincipalID, reason), nil
+}
@@ -2,13 +2,18 @@ package operations
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/validate"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 )
@@ -17,30 +22,60 @@ var urlPattern = regexp.MustCompile(`^https?://.+`)
 
 // CreateCommand is the input DTO.
 type CreateCommand struct {
-	Code             string                          `json:"code"`
-	Name             string                          `json:"name"`
-	Endpoint         string                          `json:"endpoint"`
-	Description      *string                         `json:"description,omitempty"`
-	ClientID         *string                         `json:"clientId,omitempty"`
-	ConnectionID     *string                         `json:"connectionId,omitempty"`
-	DispatchPoolID   *string                         `json:"dispatchPoolId,omitempty"`
-	ServiceAccountID *string                         `json:"serviceAccountId,omitempty"`
-	EventTypes       []subscription.EventTypeBinding `json:"eventTypes,omitempty"`
-	CustomConfig     []subscription.ConfigEntry      `json:"customConfig,omitempty"`
-	Mode             string                          `json:"mode,omitempty"`
-	TimeoutSeconds   *int32                          `json:"timeoutSeconds,omitempty"`
-	MaxRetries       *int32                          `json:"maxRetries,omitempty"`
-	DelaySeconds     *int32                          `json:"delaySeconds,omitempty"`
-	MaxAgeSeconds    *int32                          `json:"maxAgeSeconds,omitempty"`
-	DataOnly         *bool                           `json:"dataOnly,omitempty"`
+	Code               string                          `json:"code"`
+	Name               string                          `json:"name"`
+	Endpoint           string                          `json:"endpoint"`
+	Description        *string                         `json:"description,omitempty"`
+	ClientID           *string                         `json:"clientId,omitempty"`
+	ConnectionID       *string                         `json:"connectionId,omitempty"`
+	DispatchPoolID     *string                         `json:"dispatchPoolId,omitempty"`
+	ServiceAccountID   *string                         `json:"serviceAccountId,omitempty"`
+	EventTypes         []subscription.EventTypeBinding `json:"eventTypes,omitempty"`
+	CustomConfig       []subscription.ConfigEntry      `json:"customConfig,omitempty"`
+	TargetAuth         *subscription.TargetAuth        `json:"targetAuth,omitempty"`
+	Transform          *subscription.PayloadTransform  `json:"transform,omitempty"`
+	Mode               string                          `json:"mode,omitempty"`
+	Priority           string                          `json:"priority,omitempty"`
+	Weight             *int32                          `json:"weight,omitempty"`
+	AckTimeoutSeconds  *int32                          `json:"ackTimeoutSeconds,omitempty"`
+	DeliveryHeaders    []string                        `json:"deliveryHeaders,omitempty"`
+	TimeoutSeconds     *int32                          `json:"timeoutSeconds,omitempty"`
+	MaxRetries         *int32                          `json:"maxRetries,omitempty"`
+	HonorRetryAfter    *bool                           `json:"honorRetryAfter,omitempty"`
+	DeliveryFormat     *string                         `json:"deliveryFormat,omitempty"`
+	DeliveryWindow     *deliverywindow.Schedule        `json:"deliveryWindow,omitempty"`
+	AllowPrivateTarget *bool                           `json:"allowPrivateTarget,omitempty"`
+	EgressProxy        *string                         `json:"egressProxy,omitempty"`
+	DelaySeconds       *int32                          `json:"delaySeconds,omitempty"`
+	MaxAgeSeconds      *int32                          `json:"maxAgeSeconds,omitempty"`
+	DataOnly           *bool                           `json:"dataOnly,omitempty"`
+	Environment        string                          `json:"environment,omitempty"`
+	Tap                *TapCommand                     `json:"tap,omitempty"`
+	// Approver says the creator holds the approve permission, resolved by
+	// the handler; their subscription needs no approval.
+	Approver bool `json:"-"`
+	// PlatformAWS says the creator is an anchor principal, resolved by the
+	// handler; only they may sign SigV4 with the platform's credentials.
+	PlatformAWS bool `json:"-"`
+}
+
+// TapCommand makes the subscription a debugging tap (see
+// subscription.Tap). ExpiresInSeconds defaults to
+// subscription.DefaultTapLifetime.
+type TapCommand struct {
+	SamplePercent    float64 `json:"samplePercent"`
+	ExpiresInSeconds *int32  `json:"expiresInSeconds,omitempty"`
 }
 
 // CreateSubscription validates cmd, enforces code uniqueness within the
-// client scope, persists the subscription, and emits [SubscriptionCreated].
+// client scope and environment, persists the subscription, and emits [SubscriptionCreated].
+// In a client that requires approval (see Repository.ApprovalRequired) a
+// creator who isn't an [CreateCommand.Approver] gets a PENDING_APPROVAL
+// subscription, which receives nothing until [ApproveSubscription].
 func CreateSubscription(repo *subscription.Repository) usecaseop.Operation[CreateCommand, SubscriptionCreated] {
 	return usecaseop.Operation[CreateCommand, SubscriptionCreated]{
 		Name: "CreateSubscription",
-		Validate: func(_ context.Context, cmd CreateCommand) error {
+		Validate: func(ctx context.Context, cmd CreateCommand) error {
 			code := strings.ToLower(strings.TrimSpace(cmd.Code))
 			if code == "" {
 				return usecase.Validation("CODE_REQUIRED", "code is required")
@@ -55,23 +90,68 @@ func CreateSubscription(repo *subscription.Repository) usecaseop.Operation[Creat
 			if !urlPattern.MatchString(cmd.Endpoint) {
 				return usecase.Validation("INVALID_ENDPOINT", "endpoint must be a http(s) URL")
 			}
+			if err := checkEgress(ctx, cmd.Endpoint, cmd.TargetAuth, isTrue(cmd.AllowPrivateTarget)); err != nil {
+				return err
+			}
+			if err := validateEgressProxy(egressProxy(cmd.EgressProxy)); err != nil {
+				return err
+			}
 			if len(cmd.EventTypes) == 0 {
 				return usecase.Validation("EVENT_TYPES_REQUIRED", "at least one event type binding is required")
 			}
-			return nil
+			if cmd.Priority != "" {
+				if err := validatePriority(cmd.Priority); err != nil {
+					return err
+				}
+			}
+			if err := validateWeight(cmd.Weight); err != nil {
+				return err
+			}
+			if err := validateAckTimeout(cmd.AckTimeoutSeconds); err != nil {
+				return err
+			}
+			if err := validateDeliveryHeaders(cmd.DeliveryHeaders); err != nil {
+				return err
+			}
+			if cmd.DeliveryFormat != nil {
+				if err := validateDeliveryFormat(*cmd.DeliveryFormat); err != nil {
+					return err
+				}
+			}
+			if err := validateDeliveryWindow(cmd.DeliveryWindow); err != nil {
+				return err
+			}
+			if err := validateTransform(cmd.Transform); err != nil {
+				return err
+			}
+			if err := validateTap(cmd.Tap); err != nil {
+				return err
+			}
+			if cmd.Environment != "" && !common.Environment(cmd.Environment).Valid() {
+				return usecase.Validation("INVALID_ENVIRONMENT", "environment must be SANDBOX or PRODUCTION")
+			}
+			return validateFilters(cmd.EventTypes)
 		},
 		// Resource-level authorization (the coarse "may write subscriptions"
 		// permission is enforced at the controller). A subscription bound to a
 		// client may only be created by a principal with access to that client;
 		// a platform-wide subscription (nil ClientID) requires anchor. This is
-		// exactly auth.CheckScopeAccess on the target client.
+		// exactly auth.CheckScopeAccess on the target client. A key pinned
+		// to one environment can't create in the other. Opting out of the
+		// egress block needs the egress-override permission, which is the
+		// controller's.
 		Authorize: func(ctx context.Context, cmd CreateCommand) error {
-			return auth.CheckScopeAccess(auth.FromContext(ctx), cmd.ClientID)
+			a := auth.FromContext(ctx)
+			if err := auth.CheckRequestedEnvironment(a, cmd.Environment); err != nil {
+				return err
+			}
+			return auth.CheckScopeAccess(a, cmd.ClientID)
 		},
 		Execute: func(ctx context.Context, cmd CreateCommand, ec usecase.ExecutionContext) (usecaseop.Plan[SubscriptionCreated], error) {
 			code := strings.ToLower(strings.TrimSpace(cmd.Code))
+			env := auth.FromContext(ctx).EnvironmentFor(cmd.Environment)
 
-			existing, err := repo.FindByCode(ctx, code, cmd.ClientID)
+			existing, err := repo.FindByCode(ctx, code, cmd.ClientID, env)
 			if err != nil {
 				return nil, usecase.Internal("REPO", "find_by_code failed", err)
 			}
@@ -81,7 +161,15 @@ func CreateSubscription(repo *subscription.Repository) usecaseop.Operation[Creat
 					"Subscription with code '"+code+"' already exists")
 			}
 
+			if err := checkBindingsCurrent(ctx, repo, cmd.EventTypes); err != nil {
+				return nil, err
+			}
+			if err := checkBindingsAvailable(ctx, repo, env, cmd.EventTypes); err != nil {
+				return nil, err
+			}
+
 			s := subscription.New(code, strings.TrimSpace(cmd.Name), cmd.Endpoint)
+			s.Environment = env
 			s.Description = cmd.Description
 			s.ClientID = cmd.ClientID
 			s.ConnectionID = cmd.ConnectionID
@@ -91,15 +179,41 @@ func CreateSubscription(repo *subscription.Repository) usecaseop.Operation[Creat
 			if cmd.CustomConfig != nil {
 				s.CustomConfig = cmd.CustomConfig
 			}
+			if s.CustomConfig, err = applyConfigSchema(ctx, repo, s, s.CustomConfig, nil); err != nil {
+				return nil, err
+			}
+			if cmd.TargetAuth != nil {
+				if s.TargetAuth, err = sealTargetAuth(cmd.TargetAuth, nil, cmd.PlatformAWS); err != nil {
+					return nil, err
+				}
+			}
+			s.Transform = normalizeTransform(cmd.Transform)
 			if cmd.Mode != "" {
 				s.Mode = common.ParseDispatchMode(cmd.Mode)
 			}
+			if cmd.Priority != "" {
+				s.Priority = common.Priority(cmd.Priority)
+			}
+			if cmd.Weight != nil {
+				s.Weight = *cmd.Weight
+			}
+			s.AckTimeoutSeconds = ackTimeout(cmd.AckTimeoutSeconds)
+			s.DeliveryHeaders = deliveryHeaders(cmd.DeliveryHeaders)
 			if cmd.TimeoutSeconds != nil {
 				s.TimeoutSeconds = *cmd.TimeoutSeconds
 			}
 			if cmd.MaxRetries != nil {
 				s.MaxRetries = *cmd.MaxRetries
 			}
+			if cmd.HonorRetryAfter != nil {
+				s.HonorRetryAfter = *cmd.HonorRetryAfter
+			}
+			if cmd.DeliveryFormat != nil {
+				s.DeliveryFormat = subscription.DeliveryFormat(*cmd.DeliveryFormat)
+			}
+			s.DeliveryWindow = normalizeDeliveryWindow(cmd.DeliveryWindow)
+			s.AllowPrivateTarget = isTrue(cmd.AllowPrivateTarget)
+			s.EgressProxy = egressProxy(cmd.EgressProxy)
 			if cmd.DelaySeconds != nil {
 				s.DelaySeconds = *cmd.DelaySeconds
 			}
@@ -109,15 +223,228 @@ func CreateSubscription(repo *subscription.Repository) usecaseop.Operation[Creat
 			if cmd.DataOnly != nil {
 				s.DataOnly = *cmd.DataOnly
 			}
+			s.Tap = newTap(cmd.Tap)
 			s.CreatedBy = &ec.PrincipalID
+			if !cmd.Approver {
+				required, err := repo.ApprovalRequired(ctx, s.ClientID)
+				if err != nil {
+					return nil, usecase.Internal("REPO", "approval_required failed", err)
+				}
+				if required {
+					s.Status = subscription.StatusPendingApproval
+				}
+			}
 
 			event := SubscriptionCreated{
 				Metadata:       usecase.NewEventMetadata(ec, SubscriptionCreatedType, Source, subjectFor(s.ID)),
 				SubscriptionID: s.ID,
 				Code:           s.Code,
 				Name:           s.Name,
+				Status:         string(s.Status),
 			}
 			return usecaseop.Save(s, repo, event), nil
 		},
 	}
 }
+
+// checkBindingsCurrent refuses bindings to a deprecated or archived event
+// type, or to a deprecated schema version of one: new consumers should
+// move with the producers, not onto what they are leaving.
+func checkBindingsCurrent(ctx context.Context, repo *subscription.Repository, bindings []subscription.EventTypeBinding) error {
+	if len(bindings) == 0 {
+		return nil
+	}
+	retired, err := repo.FindRetiredBindings(ctx, bindings)
+	if err != nil {
+		return usecase.Internal("REPO", "find_retired_bindings failed", err)
+	}
+	if len(retired) > 0 {
+		return usecase.Conflict("EVENT_TYPE_DEPRECATED",
+			"Cannot subscribe to deprecated event types: "+strings.Join(retired, ", "))
+	}
+	return nil
+}
+
+// applyConfigSchema validates entries against the config schema governing
+// s, if any, and returns the normalised set (see ConfigSchema.Apply).
+// stored is the previously persisted config: secrets sent back as
+// subscription.MaskedValue keep their stored value. Without a schema the
+// entries pass through untouched.
+func applyConfigSchema(
+	ctx context.Context,
+	repo *subscription.Repository,
+	s *subscription.Subscription,
+	entries, stored []subscription.ConfigEntry,
+) ([]subscription.ConfigEntry, error) {
+	// Every subscription mediates over HTTP today.
+	schema, err := repo.ConfigSchemas().Resolve(ctx, s.ApplicationCode, common.MediationTypeHTTP)
+	if err != nil {
+		return nil, usecase.Internal("REPO", "resolve config schema failed", err)
+	}
+	if schema == nil {
+		return entries, nil
+	}
+	return schema.Apply(schema.Unmask(entries, stored))
+}
+
+// validateTransform compiles the payload transform so a template error is
+// reported on save rather than on every delivery. A blank template is
+// valid: it means "no transform".
+func validateTransform(t *subscription.PayloadTransform) error {
+	t = normalizeTransform(t)
+	if t == nil {
+		return nil
+	}
+	if _, err := t.Compile(); err != nil {
+		return usecase.Validation("INVALID_TRANSFORM", fmt.Sprintf("invalid transform: %v", err))
+	}
+	return nil
+}
+
+// normalizeTransform fills defaults; nil for a nil or blank template.
+func normalizeTransform(t *subscription.PayloadTransform) *subscription.PayloadTransform {
+	if t == nil || strings.TrimSpace(t.Template) == "" {
+		return nil
+	}
+	out := subscription.NewPayloadTransform(t.Template, t.ContentType)
+	if t.Engine != "" {
+		out.Engine = payloadtransform.Engine(strings.ToUpper(string(t.Engine)))
+	}
+	return out
+}
+
+// validateFilters rejects bindings whose filter expression doesn't compile,
+// so fan-out never meets a broken filter on a freshly saved row.
+func validateFilters(bindings []subscription.EventTypeBinding) error {
+	for _, b := range bindings {
+		if _, err := b.CompileFilter(); err != nil {
+			return usecase.Validation("INVALID_FILTER",
+				fmt.Sprintf("event type '%s': invalid filter: %v", b.EventTypeCode, err))
+		}
+	}
+	return nil
+}
+
+// validatePriority rejects anything but HIGH, NORMAL or LOW. Unlike mode,
+// a typo here would silently demote a latency-sensitive subscription.
+func validatePriority(p string) error {
+	if !common.Priority(p).Valid() {
+		return usecase.Validation("INVALID_PRIORITY", "priority must be one of HIGH, NORMAL, LOW")
+	}
+	return nil
+}
+
+// validateWeight rejects a dispatch weight outside MinWeight..MaxWeight.
+// nil (not supplied) is fine.
+func validateWeight(w *int32) error {
+	if w != nil && (*w < subscription.MinWeight || *w > subscription.MaxWeight) {
+		return usecase.Validation("INVALID_WEIGHT",
+			fmt.Sprintf("weight must be between %d and %d", subscription.MinWeight, subscription.MaxWeight))
+	}
+	return nil
+}
+
+// validateAckTimeout rejects an acknowledgement timeout outside
+// MinAckTimeoutSeconds..MaxAckTimeoutSeconds. nil (not supplied) and 0
+// (acknowledgement off) are fine.
+func validateAckTimeout(secs *int32) error {
+	if secs != nil && *secs != 0 && (*secs < subscription.MinAckTimeoutSeconds || *secs > subscription.MaxAckTimeoutSeconds) {
+		return usecase.Validation("INVALID_ACK_TIMEOUT",
+			fmt.Sprintf("ackTimeoutSeconds must be 0 (off) or between %d and %d", subscription.MinAckTimeoutSeconds, subscription.MaxAckTimeoutSeconds))
+	}
+	return nil
+}
+
+// ackTimeout normalizes a requested acknowledgement timeout: 0 means off.
+func ackTimeout(secs *int32) *int32 {
+	if secs == nil || *secs == 0 {
+		return nil
+	}
+	v := *secs
+	return &v
+}
+
+// validateTap rejects a sample outside MinTapSamplePercent..
+// MaxTapSamplePercent or a lifetime beyond MaxTapLifetime. nil (an
+// ordinary subscription) is fine.
+func validateTap(t *TapCommand) error {
+	if t == nil {
+		return nil
+	}
+	if t.SamplePercent < subscription.MinTapSamplePercent || t.SamplePercent > subscription.MaxTapSamplePercent {
+		return usecase.Validation("INVALID_TAP_SAMPLE",
+			fmt.Sprintf("tap.samplePercent must be between %g and %g", subscription.MinTapSamplePercent, subscription.MaxTapSamplePercent))
+	}
+	maxSecs := int32(subscription.MaxTapLifetime / time.Second)
+	if t.ExpiresInSeconds != nil && (*t.ExpiresInSeconds <= 0 || *t.ExpiresInSeconds > maxSecs) {
+		return usecase.Validation("INVALID_TAP_EXPIRY",
+			fmt.Sprintf("tap.expiresInSeconds must be between 1 and %d", maxSecs))
+	}
+	return nil
+}
+
+// newTap arms a requested tap from now; nil for an ordinary subscription.
+func newTap(t *TapCommand) *subscription.Tap {
+	if t == nil {
+		return nil
+	}
+	lifetime := subscription.DefaultTapLifetime
+	if t.ExpiresInSeconds != nil {
+		lifetime = time.Duration(*t.ExpiresInSeconds) * time.Second
+	}
+	return subscription.NewTap(t.SamplePercent, lifetime)
+}
+
+// validateDeliveryHeaders rejects a name that isn't one of
+// subscription.AllDeliveryHeaders.
+func validateDeliveryHeaders(hs []string) error {
+	for _, h := range hs {
+		if !subscription.DeliveryHeader(h).Valid() {
+			return usecase.Validation("INVALID_DELIVERY_HEADER",
+				fmt.Sprintf("deliveryHeaders: unknown header %q; must be among DELIVERY_ID, EVENT_ID, EVENT_TYPE, DELIVERY_ATTEMPT, FIRST_ATTEMPTED_AT, SUBSCRIPTION_ID, SIGNATURE, TRACE_CONTEXT", h))
+		}
+	}
+	return nil
+}
+
+// deliveryHeaders normalizes a requested header set, dropping repeats.
+// Empty means the default: every standard header.
+func deliveryHeaders(hs []string) []subscription.DeliveryHeader {
+	if len(hs) == 0 {
+		return nil
+	}
+	out := make([]subscription.DeliveryHeader, 0, len(hs))
+	for _, h := range hs {
+		if !slices.Contains(out, subscription.DeliveryHeader(h)) {
+			out = append(out, subscription.DeliveryHeader(h))
+		}
+	}
+	return out
+}
+
+func validateDeliveryFormat(f string) error {
+	if !subscription.DeliveryFormat(f).Valid() {
+		return usecase.Validation("INVALID_DELIVERY_FORMAT",
+			"deliveryFormat must be one of FLOWCATALYST, CLOUDEVENTS_BINARY, CLOUDEVENTS_STRUCTURED")
+	}
+	return nil
+}
+
+func validateDeliveryWindow(w *deliverywindow.Schedule) error {
+	if w = normalizeDeliveryWindow(w); w == nil {
+		return nil
+	}
+	if err := w.Validate(); err != nil {
+		return usecase.Validation("INVALID_DELIVERY_WINDOW", "invalid deliveryWindow: "+err.Error())
+	}
+	return nil
+}
+
+// normalizeDeliveryWindow is nil for a nil window or one with neither
+// windows nor blackouts (always open).
+func normalizeDeliveryWindow(w *deliverywindow.Schedule) *deliverywindow.Schedule {
+	if w == nil || (len(w.Windows) == 0 && len(w.Blackouts) == 0) {
+		return nil
+	}
+	return w
+}
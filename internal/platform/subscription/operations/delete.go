@@ -4,7 +4,6 @@ import (
 	"context"
 	"strings"
 
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -38,7 +37,7 @@ func DeleteSubscription(repo *subscription.Repository) usecaseop.Operation[Delet
 				return nil, httperror.NotFound("Subscription", cmd.ID)
 			}
 			// Per-resource scope.
-			if err := auth.CheckScopeAccess(auth.FromContext(ctx), s.ClientID); err != nil {
+			if err := checkAccess(ctx, s); err != nil {
 				return nil, err
 			}
 			event := SubscriptionDeleted{
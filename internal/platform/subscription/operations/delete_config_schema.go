@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// DeleteConfigSchemaCommand is the input DTO.
+type DeleteConfigSchemaCommand struct {
+	ID string `json:"id"`
+}
+
+// DeleteConfigSchema removes a schema and emits [ConfigSchemaDeleted].
+// Stored subscription config is left as-is. Authorize is Public; the
+// controller gates it with auth.RequireAnchor.
+func DeleteConfigSchema(repo *subscription.ConfigSchemaRepository) usecaseop.Operation[DeleteConfigSchemaCommand, ConfigSchemaDeleted] {
+	return usecaseop.Operation[DeleteConfigSchemaCommand, ConfigSchemaDeleted]{
+		Name: "DeleteSubscriptionConfigSchema",
+		Validate: func(_ context.Context, cmd DeleteConfigSchemaCommand) error {
+			if strings.TrimSpace(cmd.ID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[DeleteConfigSchemaCommand],
+		Execute: func(ctx context.Context, cmd DeleteConfigSchemaCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ConfigSchemaDeleted], error) {
+			c, err := repo.FindByID(ctx, cmd.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if c == nil {
+				return nil, httperror.NotFound("SubscriptionConfigSchema", cmd.ID)
+			}
+			event := ConfigSchemaDeleted{
+				Metadata: usecase.NewEventMetadata(ec, ConfigSchemaDeletedType, Source, schemaSubjectFor(c.ID)),
+				SchemaID: c.ID,
+			}
+			return usecaseop.Delete(c, repo, event), nil
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// checkEgress refuses an endpoint, or an OAuth2 token URL, that the
+// deployment's egress policy blocks. allowPrivate is the subscription's
+// override. Delivery checks again against fresh DNS; this is the early,
+// user-facing answer.
+func checkEgress(ctx context.Context, endpoint string, ta *subscription.TargetAuth, allowPrivate bool) error {
+	p, err := egress.FromEnv()
+	if err != nil {
+		return usecase.Internal("EGRESS", "load egress policy failed", err)
+	}
+	if err := p.CheckURL(ctx, endpoint, allowPrivate); err != nil {
+		return usecase.Validation("ENDPOINT_NOT_ALLOWED", "endpoint: "+err.Error())
+	}
+	if ta != nil && ta.OAuth2 != nil {
+		if err := p.CheckURL(ctx, ta.OAuth2.TokenURL, allowPrivate); err != nil {
+			return usecase.Validation("ENDPOINT_NOT_ALLOWED", "targetAuth.oauth2.tokenUrl: "+err.Error())
+		}
+	}
+	return nil
+}
+
+// validateEgressProxy refuses a proxy name the deployment doesn't define
+// in FC_EGRESS_PROXIES. nil or "" (the deployment default) always passes.
+func validateEgressProxy(name *string) error {
+	if name == nil || *name == "" {
+		return nil
+	}
+	p, err := egress.FromEnv()
+	if err != nil {
+		return usecase.Internal("EGRESS", "load egress policy failed", err)
+	}
+	if err := p.CheckProxy(*name); err != nil {
+		return usecase.Validation("UNKNOWN_EGRESS_PROXY", "egressProxy: "+err.Error())
+	}
+	return nil
+}
+
+// egressProxy normalizes a requested proxy name: "" means the default.
+func egressProxy(name *string) *string {
+	if name == nil || strings.TrimSpace(*name) == "" {
+		return nil
+	}
+	n := strings.TrimSpace(*name)
+	return &n
+}
+
+func isTrue(b *bool) bool { return b != nil && *b }
@@ -0,0 +1,40 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// checkAccess is the per-resource rule for acting on an existing
+// subscription: the caller must reach its client, and a key pinned to one
+// environment can't touch the other's subscriptions.
+func checkAccess(ctx context.Context, s *subscription.Subscription) error {
+	a := auth.FromContext(ctx)
+	if err := auth.CheckScopeAccess(a, s.ClientID); err != nil {
+		return err
+	}
+	return auth.CheckEnvironmentAccess(a, s.Environment)
+}
+
+// checkBindingsAvailable refuses production bindings to event types that
+// are still SANDBOX: they have to be promoted first. Sandbox subscriptions
+// may bind to anything.
+func checkBindingsAvailable(ctx context.Context, repo *subscription.Repository, env common.Environment, bindings []subscription.EventTypeBinding) error {
+	if env == common.EnvironmentSandbox || len(bindings) == 0 {
+		return nil
+	}
+	sandbox, err := repo.FindSandboxEventTypes(ctx, bindings)
+	if err != nil {
+		return usecase.Internal("REPO", "find_sandbox_event_types failed", err)
+	}
+	if len(sandbox) > 0 {
+		return usecase.Conflict("EVENT_TYPE_NOT_PROMOTED",
+			"Event types are still in the sandbox; promote them first: "+strings.Join(sandbox, ", "))
+	}
+	return nil
+}
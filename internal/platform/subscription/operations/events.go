@@ -8,24 +8,36 @@ import (
 )
 
 const (
-	SubscriptionCreatedType = "platform:admin:subscription:created"
-	SubscriptionUpdatedType = "platform:admin:subscription:updated"
-	SubscriptionDeletedType = "platform:admin:subscription:deleted"
-	SubscriptionPausedType  = "platform:admin:subscription:paused"
-	SubscriptionResumedType = "platform:admin:subscription:resumed"
-	SubscriptionsSyncedType = "platform:admin:subscription:synced"
-	Source                  = "platform:admin"
+	SubscriptionCreatedType  = "platform:admin:subscription:created"
+	SubscriptionUpdatedType  = "platform:admin:subscription:updated"
+	SubscriptionDeletedType  = "platform:admin:subscription:deleted"
+	SubscriptionPausedType   = "platform:admin:subscription:paused"
+	SubscriptionResumedType  = "platform:admin:subscription:resumed"
+	SubscriptionApprovedType = "platform:admin:subscription:approved"
+	SubscriptionRejectedType = "platform:admin:subscription:rejected"
+	SubscriptionPromotedType = "platform:admin:subscription:promoted"
+	SubscriptionsSyncedType  = "platform:admin:subscription:synced"
+	TargetTLSSetType         = "platform:admin:subscription:target-tls-set"
+	TargetTLSClearedType     = "platform:admin:subscription:target-tls-cleared"
+	ConfigSchemaSetType      = "platform:admin:subscription-config-schema:set"
+	ConfigSchemaDeletedType  = "platform:admin:subscription-config-schema:deleted"
+	Source                   = "platform:admin"
 )
 
 func subjectFor(id string) string { return "platform.subscription." + id }
 func groupFor(id string) string   { return "platform:subscription:" + id }
 
-// SubscriptionCreated is emitted on create.
+func schemaSubjectFor(id string) string { return "platform.subscription-config-schema." + id }
+func schemaGroupFor(id string) string   { return "platform:subscription-config-schema:" + id }
+
+// SubscriptionCreated is emitted on create. Status is PENDING_APPROVAL
+// when the subscription waits on an approver.
 type SubscriptionCreated struct {
 	Metadata       usecase.EventMetadata
 	SubscriptionID string
 	Code           string
 	Name           string
+	Status         string
 }
 
 func (e SubscriptionCreated) EventID() string       { return e.Metadata.EventID }
@@ -44,14 +56,21 @@ func (e SubscriptionCreated) ToDataJSON() ([]byte, error) {
 		SubscriptionID string `json:"subscriptionId"`
 		Code           string `json:"code"`
 		Name           string `json:"name"`
-	}{e.SubscriptionID, e.Code, e.Name})
+		Status         string `json:"status"`
+	}{e.SubscriptionID, e.Code, e.Name, e.Status})
 }
 
-// SubscriptionUpdated emitted on update.
+// SubscriptionUpdated emitted on update. SentForApproval is set when the
+// change moved the subscription back to PENDING_APPROVAL; it and ClientID
+// are for the handler's approver notice, not the event data.
 type SubscriptionUpdated struct {
-	Metadata       usecase.EventMetadata
-	SubscriptionID string
-	Name           string
+	Metadata        usecase.EventMetadata
+	SubscriptionID  string
+	Code            string
+	Name            string
+	Status          string
+	SentForApproval bool
+	ClientID        *string
 }
 
 func (e SubscriptionUpdated) EventID() string       { return e.Metadata.EventID }
@@ -69,7 +88,8 @@ func (e SubscriptionUpdated) ToDataJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		SubscriptionID string `json:"subscriptionId"`
 		Name           string `json:"name"`
-	}{e.SubscriptionID, e.Name})
+		Status         string `json:"status"`
+	}{e.SubscriptionID, e.Name, e.Status})
 }
 
 // SubscriptionDeleted emitted on delete.
@@ -143,6 +163,106 @@ func (e SubscriptionResumed) ToDataJSON() ([]byte, error) {
 	}{e.SubscriptionID})
 }
 
+// SubscriptionApproved emitted when an approver lets a pending
+// subscription start receiving events.
+type SubscriptionApproved struct {
+	Metadata       usecase.EventMetadata
+	SubscriptionID string
+	DecisionID     string
+	Reason         *string
+}
+
+func (e SubscriptionApproved) EventID() string       { return e.Metadata.EventID }
+func (e SubscriptionApproved) EventType() string     { return SubscriptionApprovedType }
+func (e SubscriptionApproved) SpecVersion() string   { return "1.0" }
+func (e SubscriptionApproved) Source() string        { return Source }
+func (e SubscriptionApproved) Subject() string       { return subjectFor(e.SubscriptionID) }
+func (e SubscriptionApproved) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e SubscriptionApproved) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e SubscriptionApproved) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e SubscriptionApproved) CausationID() string   { return e.Metadata.CausationID }
+func (e SubscriptionApproved) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e SubscriptionApproved) MessageGroup() string  { return groupFor(e.SubscriptionID) }
+func (e SubscriptionApproved) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SubscriptionID string  `json:"subscriptionId"`
+		DecisionID     string  `json:"decisionId"`
+		Reason         *string `json:"reason,omitempty"`
+	}{e.SubscriptionID, e.DecisionID, e.Reason})
+}
+
+// SubscriptionRejected emitted when an approver turns a pending
+// subscription down.
+type SubscriptionRejected struct {
+	Metadata       usecase.EventMetadata
+	SubscriptionID string
+	DecisionID     string
+	Reason         *string
+}
+
+func (e SubscriptionRejected) EventID() string       { return e.Metadata.EventID }
+func (e SubscriptionRejected) EventType() string     { return SubscriptionRejectedType }
+func (e SubscriptionRejected) SpecVersion() string   { return "1.0" }
+func (e SubscriptionRejected) Source() string        { return Source }
+func (e SubscriptionRejected) Subject() string       { return subjectFor(e.SubscriptionID) }
+func (e SubscriptionRejected) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e SubscriptionRejected) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e SubscriptionRejected) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e SubscriptionRejected) CausationID() string   { return e.Metadata.CausationID }
+func (e SubscriptionRejected) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e SubscriptionRejected) MessageGroup() string  { return groupFor(e.SubscriptionID) }
+func (e SubscriptionRejected) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SubscriptionID string  `json:"subscriptionId"`
+		DecisionID     string  `json:"decisionId"`
+		Reason         *string `json:"reason,omitempty"`
+	}{e.SubscriptionID, e.DecisionID, e.Reason})
+}
+
+// SubscriptionPromoted emitted when a sandbox subscription is copied to
+// production. SubscriptionID is the production copy; Created is false when
+// an existing production subscription with the same code was overwritten.
+type SubscriptionPromoted struct {
+	Metadata       usecase.EventMetadata
+	SubscriptionID string
+	SandboxID      string
+	Code           string
+	Created        bool
+}
+
+// NewSubscriptionPromotedEvent builds the promoted event with the
+// canonical subject. Exported for the client environment promotion, which
+// writes subscriptions inside its own transaction.
+func NewSubscriptionPromotedEvent(ec usecase.ExecutionContext, subscriptionID, sandboxID, code string, created bool) SubscriptionPromoted {
+	return SubscriptionPromoted{
+		Metadata:       usecase.NewEventMetadata(ec, SubscriptionPromotedType, Source, subjectFor(subscriptionID)),
+		SubscriptionID: subscriptionID,
+		SandboxID:      sandboxID,
+		Code:           code,
+		Created:        created,
+	}
+}
+
+func (e SubscriptionPromoted) EventID() string       { return e.Metadata.EventID }
+func (e SubscriptionPromoted) EventType() string     { return SubscriptionPromotedType }
+func (e SubscriptionPromoted) SpecVersion() string   { return "1.0" }
+func (e SubscriptionPromoted) Source() string        { return Source }
+func (e SubscriptionPromoted) Subject() string       { return subjectFor(e.SubscriptionID) }
+func (e SubscriptionPromoted) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e SubscriptionPromoted) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e SubscriptionPromoted) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e SubscriptionPromoted) CausationID() string   { return e.Metadata.CausationID }
+func (e SubscriptionPromoted) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e SubscriptionPromoted) MessageGroup() string  { return groupFor(e.SubscriptionID) }
+func (e SubscriptionPromoted) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SubscriptionID string `json:"subscriptionId"`
+		SandboxID      string `json:"sandboxId"`
+		Code           string `json:"code"`
+		Created        bool   `json:"created"`
+	}{e.SubscriptionID, e.SandboxID, e.Code, e.Created})
+}
+
 // SubscriptionsSynced is the rollup emitted by the SDK app-scoped
 // subscription sync (SyncSubscriptions). Mirrors the Rust SubscriptionsSynced
 // event.
@@ -175,3 +295,111 @@ func (e SubscriptionsSynced) ToDataJSON() ([]byte, error) {
 		SyncedCodes     []string `json:"syncedCodes"`
 	}{e.ApplicationCode, e.Created, e.Updated, e.Deleted, e.SyncedCodes})
 }
+
+// ConfigSchemaSet is emitted when a subscription config schema is created
+// or replaced.
+type ConfigSchemaSet struct {
+	Metadata        usecase.EventMetadata
+	SchemaID        string
+	ApplicationCode *string
+	MediationType   string
+	FieldCount      int
+}
+
+func (e ConfigSchemaSet) EventID() string       { return e.Metadata.EventID }
+func (e ConfigSchemaSet) EventType() string     { return ConfigSchemaSetType }
+func (e ConfigSchemaSet) SpecVersion() string   { return "1.0" }
+func (e ConfigSchemaSet) Source() string        { return Source }
+func (e ConfigSchemaSet) Subject() string       { return schemaSubjectFor(e.SchemaID) }
+func (e ConfigSchemaSet) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ConfigSchemaSet) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ConfigSchemaSet) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ConfigSchemaSet) CausationID() string   { return e.Metadata.CausationID }
+func (e ConfigSchemaSet) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ConfigSchemaSet) MessageGroup() string  { return schemaGroupFor(e.SchemaID) }
+func (e ConfigSchemaSet) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SchemaID        string  `json:"schemaId"`
+		ApplicationCode *string `json:"applicationCode,omitempty"`
+		MediationType   string  `json:"mediationType"`
+		FieldCount      int     `json:"fieldCount"`
+	}{e.SchemaID, e.ApplicationCode, e.MediationType, e.FieldCount})
+}
+
+// ConfigSchemaDeleted is emitted when a subscription config schema is
+// removed. Subscriptions in its scope fall back to the platform default or
+// to free-form config.
+type ConfigSchemaDeleted struct {
+	Metadata usecase.EventMetadata
+	SchemaID string
+}
+
+func (e ConfigSchemaDeleted) EventID() string       { return e.Metadata.EventID }
+func (e ConfigSchemaDeleted) EventType() string     { return ConfigSchemaDeletedType }
+func (e ConfigSchemaDeleted) SpecVersion() string   { return "1.0" }
+func (e ConfigSchemaDeleted) Source() string        { return Source }
+func (e ConfigSchemaDeleted) Subject() string       { return schemaSubjectFor(e.SchemaID) }
+func (e ConfigSchemaDeleted) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e ConfigSchemaDeleted) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e ConfigSchemaDeleted) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e ConfigSchemaDeleted) CausationID() string   { return e.Metadata.CausationID }
+func (e ConfigSchemaDeleted) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e ConfigSchemaDeleted) MessageGroup() string  { return schemaGroupFor(e.SchemaID) }
+func (e ConfigSchemaDeleted) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SchemaID string `json:"schemaId"`
+	}{e.SchemaID})
+}
+
+// TargetTLSSet emitted when a subscription's TLS material is uploaded.
+// Carries the certificate metadata only — never PEM or key material.
+type TargetTLSSet struct {
+	Metadata       usecase.EventMetadata
+	SubscriptionID string
+	CertSubject    *string
+	CertNotAfter   *time.Time
+	CANotAfter     *time.Time
+}
+
+func (e TargetTLSSet) EventID() string       { return e.Metadata.EventID }
+func (e TargetTLSSet) EventType() string     { return TargetTLSSetType }
+func (e TargetTLSSet) SpecVersion() string   { return "1.0" }
+func (e TargetTLSSet) Source() string        { return Source }
+func (e TargetTLSSet) Subject() string       { return subjectFor(e.SubscriptionID) }
+func (e TargetTLSSet) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e TargetTLSSet) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e TargetTLSSet) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e TargetTLSSet) CausationID() string   { return e.Metadata.CausationID }
+func (e TargetTLSSet) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e TargetTLSSet) MessageGroup() string  { return groupFor(e.SubscriptionID) }
+func (e TargetTLSSet) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SubscriptionID string     `json:"subscriptionId"`
+		CertSubject    *string    `json:"certSubject,omitempty"`
+		CertNotAfter   *time.Time `json:"certNotAfter,omitempty"`
+		CANotAfter     *time.Time `json:"caNotAfter,omitempty"`
+	}{e.SubscriptionID, e.CertSubject, e.CertNotAfter, e.CANotAfter})
+}
+
+// TargetTLSCleared emitted when a subscription's TLS material is removed.
+type TargetTLSCleared struct {
+	Metadata       usecase.EventMetadata
+	SubscriptionID string
+}
+
+func (e TargetTLSCleared) EventID() string       { return e.Metadata.EventID }
+func (e TargetTLSCleared) EventType() string     { return TargetTLSClearedType }
+func (e TargetTLSCleared) SpecVersion() string   { return "1.0" }
+func (e TargetTLSCleared) Source() string        { return Source }
+func (e TargetTLSCleared) Subject() string       { return subjectFor(e.SubscriptionID) }
+func (e TargetTLSCleared) Time() time.Time       { return e.Metadata.OccurredAt }
+func (e TargetTLSCleared) PrincipalID() string   { return e.Metadata.PrincipalID }
+func (e TargetTLSCleared) CorrelationID() string { return e.Metadata.CorrelationID }
+func (e TargetTLSCleared) CausationID() string   { return e.Metadata.CausationID }
+func (e TargetTLSCleared) ExecutionID() string   { return e.Metadata.ExecutionID }
+func (e TargetTLSCleared) MessageGroup() string  { return groupFor(e.SubscriptionID) }
+func (e TargetTLSCleared) ToDataJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SubscriptionID string `json:"subscriptionId"`
+	}{e.SubscriptionID})
+}
@@ -4,20 +4,37 @@ package operations_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/connection"
 	connops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/connection/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
 	poolops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	etops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/testpg"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
@@ -191,6 +208,71 @@ func TestCreateSubscription_DuplicateCode_Conflict(t *testing.T) {
 	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "CODE_EXISTS")
 }
 
+// Deprecated and archived event types, and deprecated schema versions,
+// take no new subscribers; a subscription already bound keeps its binding
+// through later edits.
+func TestSubscription_RefusesDeprecatedEventTypes(t *testing.T) {
+	t.Parallel()
+	pool := testpg.Pool(t)
+	repo := subscription.NewRepository(pool)
+	etRepo := eventtype.NewRepository(pool)
+	uow := testpg.NewUoW(t)
+
+	created, err := runAuthorized(uow, etops.CreateEventType(etRepo),
+		etops.CreateCommand{Code: "subdepr:orders:order:created", Name: "Deprecated"})
+	require.NoError(t, err)
+	existing := mustCreateBound(t, repo, uow, "subdepr-existing", "subdepr:orders:order:created")
+	_, err = runAuthorized(uow, etops.DeprecateEventType(etRepo), etops.DeprecateCommand{ID: created.EventTypeID})
+	require.NoError(t, err)
+
+	_, err = runAuthorized(uow, operations.CreateSubscription(repo), operations.CreateCommand{
+		Code: "subdepr-new", Name: "New", Endpoint: "https://depr.example.test",
+		EventTypes: []subscription.EventTypeBinding{subscription.NewEventTypeBinding("subdepr:orders:order:created")},
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "EVENT_TYPE_DEPRECATED")
+
+	// A wildcard isn't pinned to the deprecated type.
+	mustCreateBound(t, repo, uow, "subdepr-wildcard", "subdepr:orders:order:*")
+
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID:   existing.SubscriptionID,
+		Name: ptr("Renamed"),
+		EventTypes: []subscription.EventTypeBinding{
+			subscription.NewEventTypeBinding("subdepr:orders:order:created"),
+			subscription.NewEventTypeBinding("subdepr:orders:order:shipped"),
+		},
+	})
+	require.NoError(t, err, "the existing binding is kept")
+
+	schema, err := runAuthorized(uow, etops.CreateEventType(etRepo), etops.CreateCommand{
+		Code: "subdepr:orders:order:placed", Name: "Placed", Schema: []byte(`{"type":"object"}`),
+	})
+	require.NoError(t, err)
+	_, err = runAuthorized(uow, etops.FinaliseEventTypeSchema(etRepo),
+		etops.FinaliseSchemaCommand{EventTypeID: schema.EventTypeID, Version: "1.0"})
+	require.NoError(t, err)
+	_, err = runAuthorized(uow, etops.DeprecateEventTypeSchema(etRepo),
+		etops.DeprecateSchemaCommand{EventTypeID: schema.EventTypeID, Version: "1.0"})
+	require.NoError(t, err)
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: existing.SubscriptionID,
+		EventTypes: []subscription.EventTypeBinding{
+			{EventTypeCode: "subdepr:orders:order:placed", SpecVersion: ptr("1.0")},
+		},
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "EVENT_TYPE_DEPRECATED")
+}
+
+func mustCreateBound(t *testing.T, repo *subscription.Repository, uow *usecasepgx.UnitOfWork, code, pattern string) operations.SubscriptionCreated {
+	t.Helper()
+	ev, err := runAuthorized(uow, operations.CreateSubscription(repo), operations.CreateCommand{
+		Code: code, Name: code, Endpoint: "https://depr.example.test/" + code,
+		EventTypes: []subscription.EventTypeBinding{subscription.NewEventTypeBinding(pattern)},
+	})
+	require.NoError(t, err)
+	return ev
+}
+
 // TestCreateSubscription_ResourceScope proves the use case's per-resource
 // authorization: the coarse "may write subscriptions" permission is the
 // controller's job, but the use case enforces that you can only bind a
@@ -239,6 +321,73 @@ func TestCreateSubscription_ResourceScope(t *testing.T) {
 	assert.Equal(t, "subscope-own", ev.Code)
 }
 
+// TestSubscription_EgressPolicy pins the save-time egress checks: a
+// private endpoint is refused unless the subscription carries the
+// override, only a principal with the egress-override permission may set
+// it, and a proxy must be one the deployment defines.
+func TestSubscription_EgressPolicy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	bindings := []subscription.EventTypeBinding{subscription.NewEventTypeBinding("subegress:a:b:c")}
+
+	_, err := runAuthorized(uow, operations.CreateSubscription(repo), operations.CreateCommand{
+		Code: "subegress-loopback", Name: "X", Endpoint: "http://127.0.0.1:8080/hook", EventTypes: bindings,
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "ENDPOINT_NOT_ALLOWED")
+
+	_, err = runAuthorized(uow, operations.CreateSubscription(repo), operations.CreateCommand{
+		Code: "subegress-metadata", Name: "X", Endpoint: "http://169.254.169.254/latest",
+		EventTypes: bindings, AllowPrivateTarget: ptr(true),
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "ENDPOINT_NOT_ALLOWED")
+
+	ev, err := runAuthorized(uow, operations.CreateSubscription(repo), operations.CreateCommand{
+		Code: "subegress-override", Name: "X", Endpoint: "http://10.1.2.3/hook",
+		EventTypes: bindings, AllowPrivateTarget: ptr(true),
+	})
+	require.NoError(t, err)
+	settings, err := repo.FindDeliverySettings(ctx, ev.SubscriptionID)
+	require.NoError(t, err)
+	assert.True(t, settings.Egress.AllowPrivate)
+	assert.Empty(t, settings.Egress.Proxy)
+
+	// Turning the override on needs the permission; re-sending it doesn't.
+	plain := mustCreate(t, repo, uow, "subegress-plain", "Plain")
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: plain.SubscriptionID, AllowPrivateTarget: ptr(true),
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindAuthorization, "EGRESS_OVERRIDE_REQUIRED")
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: ev.SubscriptionID, AllowPrivateTarget: ptr(true),
+	})
+	require.NoError(t, err)
+
+	// Clearing the override re-checks the stored endpoint.
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: ev.SubscriptionID, AllowPrivateTarget: ptr(false),
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "ENDPOINT_NOT_ALLOWED")
+
+	seeded := mustCreate(t, repo, uow, "subegress-update", "Update")
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: seeded.SubscriptionID, Endpoint: ptr("http://[::1]/hook"),
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "ENDPOINT_NOT_ALLOWED")
+
+	// Only proxies the deployment names (FC_EGRESS_PROXIES, none here) can
+	// be chosen; "" is the default route.
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: seeded.SubscriptionID, EgressProxy: ptr("eu"),
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "UNKNOWN_EGRESS_PROXY")
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: seeded.SubscriptionID, EgressProxy: ptr(""),
+	})
+	require.NoError(t, err)
+}
+
 // ── Update ────────────────────────────────────────────────────────────────
 
 func TestUpdateSubscription_HappyPath(t *testing.T) {
@@ -289,6 +438,231 @@ func TestUpdateSubscription_HappyPath(t *testing.T) {
 	assert.Equal(t, subscription.StatusActive, got.Status, "update must not touch status")
 }
 
+// Not parallel: sets FLOWCATALYST_APP_KEY for the secret encryption.
+func TestUpdateSubscription_TargetAuthRoundTrip(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	t.Setenv("FLOWCATALYST_APP_KEY", key)
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "subupd-targetauth", "Target auth")
+
+	oauth := func(secret string) *subscription.TargetAuth {
+		return &subscription.TargetAuth{Type: subscription.TargetAuthOAuth2, OAuth2: &subscription.OAuth2ClientCredentials{
+			TokenURL: "https://auth.partner.example/token", ClientID: "fc", ClientSecret: secret,
+		}}
+	}
+	update := func(ta *subscription.TargetAuth) *subscription.Subscription {
+		t.Helper()
+		_, err := runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+			ID: seeded.SubscriptionID, TargetAuth: ta,
+		})
+		require.NoError(t, err)
+		got, err := repo.FindByID(ctx, seeded.SubscriptionID)
+		require.NoError(t, err)
+		return got
+	}
+
+	got := update(oauth("s3cret"))
+	require.NotNil(t, got.TargetAuth)
+	stored := got.TargetAuth.OAuth2.ClientSecret
+	assert.NotEqual(t, "s3cret", stored, "client secret is encrypted at rest")
+	enc, err := encryption.New(key)
+	require.NoError(t, err)
+	plain, err := enc.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", plain)
+
+	got = update(oauth(subscription.MaskedValue))
+	assert.Equal(t, stored, got.TargetAuth.OAuth2.ClientSecret, "masked secret keeps the stored one")
+
+	moved := oauth(subscription.MaskedValue)
+	moved.OAuth2.TokenURL = "https://attacker.example/token"
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: seeded.SubscriptionID, TargetAuth: moved,
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "TARGET_AUTH_SECRET_REQUIRED")
+	renamed := oauth("")
+	renamed.OAuth2.ClientID = "other"
+	_, err = runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+		ID: seeded.SubscriptionID, TargetAuth: renamed,
+	})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "TARGET_AUTH_SECRET_REQUIRED")
+
+	got = update(&subscription.TargetAuth{Type: subscription.TargetAuthNone})
+	assert.Nil(t, got.TargetAuth, "NONE removes target auth")
+
+	ta, err := repo.FindTargetAuth(ctx, seeded.SubscriptionID)
+	require.NoError(t, err)
+	assert.Nil(t, ta)
+}
+
+func TestUpdateSubscription_SigV4PlatformCredentialsNeedAnchor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "subupd-sigv4", "SigV4")
+
+	role := "arn:aws:iam::123456789012:role/partner-delivery"
+	extID := "fc-tenant-1"
+	sigv4 := func(roleARN, externalID *string) *subscription.TargetAuth {
+		return &subscription.TargetAuth{Type: subscription.TargetAuthSigV4, SigV4: &subscription.SigV4Auth{
+			Region: "eu-west-1", Service: "execute-api", RoleARN: roleARN, ExternalID: externalID,
+		}}
+	}
+	update := func(cmd operations.UpdateCommand) error {
+		cmd.ID = seeded.SubscriptionID
+		_, err := runAuthorized(uow, operations.UpdateSubscription(repo), cmd)
+		return err
+	}
+
+	err := update(operations.UpdateCommand{TargetAuth: sigv4(nil, nil)})
+	testpg.RequireUsecaseError(t, err, usecase.KindAuthorization, "PLATFORM_AWS_CREDENTIALS")
+	err = update(operations.UpdateCommand{TargetAuth: sigv4(&role, nil)})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "INVALID_TARGET_AUTH")
+	require.NoError(t, update(operations.UpdateCommand{TargetAuth: sigv4(&role, &extID)}))
+
+	require.NoError(t, update(operations.UpdateCommand{TargetAuth: sigv4(nil, nil), PlatformAWS: true}))
+	endpoint := "https://elsewhere.example.test/hook"
+	err = update(operations.UpdateCommand{Endpoint: &endpoint})
+	testpg.RequireUsecaseError(t, err, usecase.KindAuthorization, "PLATFORM_AWS_CREDENTIALS")
+
+	got, err := repo.FindByID(ctx, seeded.SubscriptionID)
+	require.NoError(t, err)
+	require.NotNil(t, got.TargetAuth)
+	assert.Nil(t, got.TargetAuth.SigV4.RoleARN)
+	assert.NotEqual(t, endpoint, got.Endpoint)
+}
+
+func TestTargetTLS_SetExpiryClear(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	t.Setenv("FLOWCATALYST_APP_KEY", key)
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	tlsRepo := subscription.NewTargetTLSRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "subtls-roundtrip", "Target TLS")
+
+	certPEM, keyPEM := testCertificate(t, time.Now().Add(7*24*time.Hour))
+	ev, err := runAuthorized(uow, operations.SetTargetTLS(repo, tlsRepo), operations.SetTargetTLSCommand{
+		SubscriptionID: seeded.SubscriptionID, ClientCertificate: certPEM, ClientKey: keyPEM,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "CN=subtls", *ev.CertSubject)
+
+	got, err := tlsRepo.FindTargetTLS(ctx, seeded.SubscriptionID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.NotEqual(t, keyPEM, *got.ClientKey, "client key is encrypted at rest")
+	enc, err := encryption.New(key)
+	require.NoError(t, err)
+	plain, err := enc.Decrypt(*got.ClientKey)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(keyPEM), plain)
+
+	expiring, err := tlsRepo.FindExpiringBefore(ctx, time.Now().Add(30*24*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(expiring, func(e subscription.TargetTLSExpiry) bool {
+		return e.SubscriptionID == seeded.SubscriptionID && e.Code == "subtls-roundtrip"
+	}), "a certificate inside the window is reported")
+
+	_, err = runAuthorized(uow, operations.ClearTargetTLS(repo, tlsRepo), operations.ClearTargetTLSCommand{SubscriptionID: seeded.SubscriptionID})
+	require.NoError(t, err)
+	got, err = tlsRepo.FindTargetTLS(ctx, seeded.SubscriptionID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+// testCertificate returns a self-signed PEM certificate and key.
+func testCertificate(t *testing.T, notAfter time.Time) (string, string) {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "subtls"},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &k.PublicKey, k)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(k)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestUpdateSubscription_TransformRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "subupd-transform", "Transform")
+
+	update := func(tr *subscription.PayloadTransform) *subscription.Subscription {
+		t.Helper()
+		_, err := runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+			ID: seeded.SubscriptionID, Transform: tr,
+		})
+		require.NoError(t, err)
+		got, err := repo.FindByID(ctx, seeded.SubscriptionID)
+		require.NoError(t, err)
+		return got
+	}
+
+	got := update(&subscription.PayloadTransform{Template: `{"id": {{ json .data.id }}}`})
+	require.NotNil(t, got.Transform)
+	assert.Equal(t, payloadtransform.EngineGoTemplate, got.Transform.Engine)
+	assert.Equal(t, payloadtransform.DefaultContentType, got.Transform.ContentType)
+
+	got = update(nil)
+	assert.NotNil(t, got.Transform, "omitted transform is left unchanged")
+
+	got = update(&subscription.PayloadTransform{})
+	assert.Nil(t, got.Transform, "blank template removes the transform")
+
+	tr, err := repo.FindTransform(ctx, seeded.SubscriptionID)
+	require.NoError(t, err)
+	assert.Nil(t, tr)
+}
+
+func TestUpdateSubscription_DeliveryWindowRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+	seeded := mustCreate(t, repo, uow, "subupd-window", "Window")
+
+	update := func(dw *deliverywindow.Schedule) (*subscription.Subscription, error) {
+		t.Helper()
+		_, err := runAuthorized(uow, operations.UpdateSubscription(repo), operations.UpdateCommand{
+			ID: seeded.SubscriptionID, DeliveryWindow: dw,
+		})
+		if err != nil {
+			return nil, err
+		}
+		got, err := repo.FindByID(ctx, seeded.SubscriptionID)
+		require.NoError(t, err)
+		return got, nil
+	}
+
+	got, err := update(&deliverywindow.Schedule{
+		Timezone: "Europe/Berlin",
+		Windows:  []deliverywindow.Window{{Days: []string{"MON"}, Start: "08:00", End: "18:00"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, got.DeliveryWindow)
+	assert.Equal(t, "Europe/Berlin", got.DeliveryWindow.Timezone)
+
+	got, err = update(nil)
+	require.NoError(t, err)
+	assert.NotNil(t, got.DeliveryWindow, "omitted window is left unchanged")
+
+	_, err = update(&deliverywindow.Schedule{Timezone: "Mars/Olympus"})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "INVALID_DELIVERY_WINDOW")
+
+	got, err = update(&deliverywindow.Schedule{})
+	require.NoError(t, err)
+	assert.Nil(t, got.DeliveryWindow, "an empty calendar removes the window")
+}
+
 func TestUpdateSubscription_Errors(t *testing.T) {
 	t.Parallel()
 	repo := subscription.NewRepository(testpg.Pool(t))
@@ -303,6 +677,7 @@ func TestUpdateSubscription_Errors(t *testing.T) {
 		{"missing id", operations.UpdateCommand{Name: ptr("X")}, usecase.KindValidation, "ID_REQUIRED"},
 		{"blank name", operations.UpdateCommand{ID: "sub_doesnotexist1", Name: ptr(" ")}, usecase.KindValidation, "NAME_REQUIRED"},
 		{"bad endpoint", operations.UpdateCommand{ID: "sub_doesnotexist1", Endpoint: ptr("not-a-url")}, usecase.KindValidation, "INVALID_ENDPOINT"},
+		{"bad transform", operations.UpdateCommand{ID: "sub_doesnotexist1", Transform: &subscription.PayloadTransform{Template: "{{ .data"}}, usecase.KindValidation, "INVALID_TRANSFORM"},
 		{"unknown id", operations.UpdateCommand{ID: "sub_doesnotexist1", Name: ptr("X")}, usecase.KindNotFound, "Subscription_NOT_FOUND"},
 	}
 	for _, tc := range cases {
@@ -470,7 +845,7 @@ func TestSyncSubscriptions_UpsertRemoveAndPoolResolution(t *testing.T) {
 	assert.Equal(t, appCode, first.ApplicationCode)
 	assert.Equal(t, []string{"subsync-a", "subsync-b"}, first.SyncedCodes)
 
-	subA, err := subRepo.FindByCode(ctx, "subsync-a", nil)
+	subA, err := subRepo.FindByCode(ctx, "subsync-a", nil, common.EnvironmentProduction)
 	require.NoError(t, err)
 	require.NotNil(t, subA)
 	assert.Equal(t, subscription.SourceAPI, subA.Source, "synced rows are API-sourced")
@@ -484,7 +859,7 @@ func TestSyncSubscriptions_UpsertRemoveAndPoolResolution(t *testing.T) {
 	assert.Equal(t, "subsync-pool1", *subA.DispatchPoolCode)
 
 	// Pin: an unresolvable dispatchPoolCode is silently left unset — no error.
-	subB, err := subRepo.FindByCode(ctx, "subsync-b", nil)
+	subB, err := subRepo.FindByCode(ctx, "subsync-b", nil, common.EnvironmentProduction)
 	require.NoError(t, err)
 	require.NotNil(t, subB)
 	assert.Nil(t, subB.DispatchPoolID, "unresolvable pool code must leave the pool ref unset")
@@ -506,14 +881,14 @@ func TestSyncSubscriptions_UpsertRemoveAndPoolResolution(t *testing.T) {
 	assert.Equal(t, uint32(1), second.Updated)
 	assert.Equal(t, uint32(1), second.Deleted)
 
-	kept, err := subRepo.FindByCode(ctx, "subsync-a", nil)
+	kept, err := subRepo.FindByCode(ctx, "subsync-a", nil, common.EnvironmentProduction)
 	require.NoError(t, err)
 	require.NotNil(t, kept)
 	assert.Equal(t, "A renamed", kept.Name)
 	require.NotNil(t, kept.DispatchPoolCode, "omitted dispatchPoolCode must leave the existing pool link")
 	assert.Equal(t, "subsync-pool1", *kept.DispatchPoolCode)
 
-	goneB, err := subRepo.FindByCode(ctx, "subsync-b", nil)
+	goneB, err := subRepo.FindByCode(ctx, "subsync-b", nil, common.EnvironmentProduction)
 	require.NoError(t, err)
 	assert.Nil(t, goneB, "RemoveUnlisted must hard-delete unlisted API rows")
 
@@ -625,3 +1000,187 @@ func TestSyncSubscriptions_RequiresAppAccess(t *testing.T) {
 		}, testpg.TestEC())
 	testpg.RequireUsecaseError(t, err, usecase.KindAuthorization, "FORBIDDEN")
 }
+
+// ── Approval ──────────────────────────────────────────────────────────────
+
+// requireApproval puts clientID under subscription approval.
+func requireApproval(t *testing.T, clientID string) {
+	t.Helper()
+	_, err := testpg.Pool(t).Exec(context.Background(),
+		`INSERT INTO app_platform_configs (id, application_code, section, property, scope, client_id, value_type, value)
+		 VALUES ($1, $2, $3, $4, 'CLIENT', $5, 'PLAIN', 'true')`,
+		tsid.Generate(tsid.PlatformConfig), subscription.ApprovalConfigApp, subscription.ApprovalConfigSection,
+		subscription.ApprovalConfigProperty, clientID)
+	require.NoError(t, err)
+}
+
+// TestSubscriptionApproval_Workflow: in a regulated client a creator
+// without the approve permission gets a PENDING_APPROVAL subscription that
+// can't be paused or decided by its creator; another approver's decision
+// activates it and is recorded.
+func TestSubscriptionApproval_Workflow(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	client := "cli_subappr_reg1"
+	requireApproval(t, client)
+	bindings := []subscription.EventTypeBinding{subscription.NewEventTypeBinding("subappr:a:b:c")}
+	makerApprover := &auth.AuthContext{
+		PrincipalID: "prn_subapprmaker", Scope: auth.ScopeClient, Clients: []string{client},
+		Permissions: []string{"platform:messaging:subscription:create", "platform:messaging:subscription:approve"},
+	}
+	maker := &auth.AuthContext{
+		PrincipalID: "prn_subapprmaker", Scope: auth.ScopeClient, Clients: []string{client},
+		Permissions: []string{"platform:messaging:subscription:create"},
+	}
+	checker := &auth.AuthContext{
+		PrincipalID: "prn_subapprcheck", Scope: auth.ScopeClient, Clients: []string{client},
+		Permissions: []string{"platform:messaging:subscription:approve"},
+	}
+	makerEC := usecase.NewExecutionContext(maker.PrincipalID)
+	checkerEC := usecase.NewExecutionContext(checker.PrincipalID)
+
+	created, err := usecaseop.Run(testpg.WithAuth(ctx, maker), uow, operations.CreateSubscription(repo),
+		operations.CreateCommand{
+			Code: "subappr-pending", Name: "Pending", Endpoint: "https://x.example.test/hook",
+			ClientID: ptr(client), EventTypes: bindings,
+		}, makerEC)
+	require.NoError(t, err)
+	assert.Equal(t, string(subscription.StatusPendingApproval), created.Status)
+
+	_, err = runAuthorized(uow, operations.PauseSubscription(repo), operations.PauseCommand{ID: created.SubscriptionID})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "NOT_APPROVED")
+
+	// The maker can't approve their own subscription, even holding the permission.
+	_, err = usecaseop.Run(testpg.WithAuth(ctx, makerApprover), uow, operations.ApproveSubscription(repo),
+		operations.DecideCommand{ID: created.SubscriptionID}, makerEC)
+	testpg.RequireUsecaseError(t, err, usecase.KindAuthorization, "SELF_APPROVAL")
+	// Nor can someone outside the client.
+	outsider := &auth.AuthContext{
+		PrincipalID: "prn_subapproutsd", Scope: auth.ScopeClient, Clients: []string{"cli_subappr_other"},
+		Permissions: []string{"platform:messaging:subscription:approve"},
+	}
+	_, err = usecaseop.Run(testpg.WithAuth(ctx, outsider), uow, operations.ApproveSubscription(repo),
+		operations.DecideCommand{ID: created.SubscriptionID}, usecase.NewExecutionContext(outsider.PrincipalID))
+	require.Error(t, err)
+
+	approved, err := usecaseop.Run(testpg.WithAuth(ctx, checker), uow, operations.ApproveSubscription(repo),
+		operations.DecideCommand{ID: created.SubscriptionID, Reason: ptr("  reviewed target  ")}, checkerEC)
+	require.NoError(t, err)
+	got, err := repo.FindByID(ctx, created.SubscriptionID)
+	require.NoError(t, err)
+	assert.Equal(t, subscription.StatusActive, got.Status)
+
+	decisions, err := repo.Approvals().FindBySubscription(ctx, created.SubscriptionID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, approved.DecisionID, decisions[0].ID)
+	assert.Equal(t, subscription.DecisionApproved, decisions[0].Decision)
+	assert.Equal(t, checker.PrincipalID, decisions[0].DecidedBy)
+	require.NotNil(t, decisions[0].Reason)
+	assert.Equal(t, "reviewed target", *decisions[0].Reason)
+
+	_, err = usecaseop.Run(testpg.WithAuth(ctx, checker), uow, operations.RejectSubscription(repo),
+		operations.DecideCommand{ID: created.SubscriptionID, Reason: ptr("too late")}, checkerEC)
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "NOT_PENDING_APPROVAL")
+
+	// A creator who can approve skips the queue.
+	direct, err := usecaseop.Run(testpg.WithAuth(ctx, checker), uow, operations.CreateSubscription(repo),
+		operations.CreateCommand{
+			Code: "subappr-direct", Name: "Direct", Endpoint: "https://x.example.test/hook",
+			ClientID: ptr(client), EventTypes: bindings, Approver: true,
+		}, checkerEC)
+	require.NoError(t, err)
+	assert.Equal(t, string(subscription.StatusActive), direct.Status)
+}
+
+// TestUpdateSubscription_RetargetNeedsReapproval: in a regulated client,
+// a non-approver pointing an approved subscription somewhere else sends it
+// back to PENDING_APPROVAL; other edits, and an approver's, don't.
+func TestUpdateSubscription_RetargetNeedsReapproval(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	client := "cli_subappr_reg3"
+	requireApproval(t, client)
+	editor := &auth.AuthContext{
+		PrincipalID: "prn_subreapedit1", Scope: auth.ScopeClient, Clients: []string{client},
+		Permissions: []string{"platform:messaging:subscription:create", "platform:messaging:subscription:update"},
+	}
+	editorCtx, editorEC := testpg.WithAuth(ctx, editor), usecase.NewExecutionContext(editor.PrincipalID)
+	created, err := usecaseop.Run(editorCtx, uow, operations.CreateSubscription(repo),
+		operations.CreateCommand{
+			Code: "subappr-retarget", Name: "Retarget", Endpoint: "https://x.example.test/hook",
+			ClientID: ptr(client), EventTypes: []subscription.EventTypeBinding{subscription.NewEventTypeBinding("subappr:a:b:c")},
+			Approver: true,
+		}, editorEC)
+	require.NoError(t, err)
+	require.Equal(t, string(subscription.StatusActive), created.Status)
+	update := func(cmd operations.UpdateCommand) operations.SubscriptionUpdated {
+		t.Helper()
+		cmd.ID = created.SubscriptionID
+		ev, err := usecaseop.Run(editorCtx, uow, operations.UpdateSubscription(repo), cmd, editorEC)
+		require.NoError(t, err)
+		return ev
+	}
+	status := func() subscription.Status {
+		t.Helper()
+		got, err := repo.FindByID(ctx, created.SubscriptionID)
+		require.NoError(t, err)
+		return got.Status
+	}
+
+	ev := update(operations.UpdateCommand{Name: ptr("Renamed"), Endpoint: ptr("https://x.example.test/hook")})
+	assert.False(t, ev.SentForApproval, "re-sending the same endpoint isn't a change")
+	assert.Equal(t, subscription.StatusActive, status())
+
+	ev = update(operations.UpdateCommand{Endpoint: ptr("https://y.example.test/hook"), Approver: true})
+	assert.False(t, ev.SentForApproval, "an approver's change stands")
+	assert.Equal(t, subscription.StatusActive, status())
+
+	ev = update(operations.UpdateCommand{Transform: subscription.NewPayloadTransform(`{"id":{{ json .id }}}`, "")})
+	assert.True(t, ev.SentForApproval)
+	assert.Equal(t, string(subscription.StatusPendingApproval), ev.Status)
+	assert.Equal(t, subscription.StatusPendingApproval, status())
+
+	ev = update(operations.UpdateCommand{Endpoint: ptr("https://z.example.test/hook")})
+	assert.False(t, ev.SentForApproval, "already waiting on an approver")
+	assert.Equal(t, subscription.StatusPendingApproval, status())
+}
+
+func TestRejectSubscription(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := subscription.NewRepository(testpg.Pool(t))
+	uow := testpg.NewUoW(t)
+
+	client := "cli_subappr_reg2"
+	requireApproval(t, client)
+	maker := &auth.AuthContext{
+		PrincipalID: "prn_subrejmaker1", Scope: auth.ScopeClient, Clients: []string{client},
+		Permissions: []string{"platform:messaging:subscription:create"},
+	}
+	created, err := usecaseop.Run(testpg.WithAuth(ctx, maker), uow, operations.CreateSubscription(repo),
+		operations.CreateCommand{
+			Code: "subappr-reject", Name: "Reject", Endpoint: "https://x.example.test/hook",
+			ClientID: ptr(client), EventTypes: []subscription.EventTypeBinding{subscription.NewEventTypeBinding("subappr:a:b:c")},
+		}, usecase.NewExecutionContext(maker.PrincipalID))
+	require.NoError(t, err)
+
+	_, err = runAuthorized(uow, operations.RejectSubscription(repo), operations.DecideCommand{ID: created.SubscriptionID})
+	testpg.RequireUsecaseError(t, err, usecase.KindValidation, "REASON_REQUIRED")
+
+	_, err = runAuthorized(uow, operations.RejectSubscription(repo),
+		operations.DecideCommand{ID: created.SubscriptionID, Reason: ptr("target outside the approved list")})
+	require.NoError(t, err)
+	got, err := repo.FindByID(ctx, created.SubscriptionID)
+	require.NoError(t, err)
+	assert.Equal(t, subscription.StatusRejected, got.Status)
+
+	_, err = runAuthorized(uow, operations.ResumeSubscription(repo), operations.ResumeCommand{ID: created.SubscriptionID})
+	testpg.RequireUsecaseError(t, err, usecase.KindConflict, "NOT_APPROVED")
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"strings"
 
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -38,9 +37,13 @@ func ResumeSubscription(repo *subscription.Repository) usecaseop.Operation[Resum
 				return nil, httperror.NotFound("Subscription", cmd.ID)
 			}
 			// Per-resource scope.
-			if err := auth.CheckScopeAccess(auth.FromContext(ctx), s.ClientID); err != nil {
+			if err := checkAccess(ctx, s); err != nil {
 				return nil, err
 			}
+			if !s.Status.Approved() {
+				return nil, usecase.Conflict("NOT_APPROVED",
+					"Subscription is "+string(s.Status)+"; only an approved subscription can be resumed")
+			}
 			s.Resume()
 			event := SubscriptionResumed{
 				Metadata:       usecase.NewEventMetadata(ec, SubscriptionResumedType, Source, subjectFor(s.ID)),
@@ -0,0 +1,89 @@
+package operations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// SetConfigSchemaCommand creates or replaces the config schema for one
+// (applicationCode, mediationType) scope. A nil ApplicationCode targets the
+// platform default.
+type SetConfigSchemaCommand struct {
+	ApplicationCode *string                    `json:"applicationCode,omitempty"`
+	MediationType   string                     `json:"mediationType,omitempty"`
+	Description     *string                    `json:"description,omitempty"`
+	Fields          []subscription.ConfigField `json:"fields"`
+}
+
+func (c SetConfigSchemaCommand) mediationType() common.MediationType {
+	if c.MediationType == "" {
+		return common.MediationTypeHTTP
+	}
+	return common.MediationType(strings.ToUpper(c.MediationType))
+}
+
+// SetConfigSchema upserts a schema by scope and emits [ConfigSchemaSet].
+// Schemas govern every subscription in their scope across tenants, so have
+// no per-client dimension (Authorize: Public); the controller gates writes
+// with auth.RequireAnchor.
+//
+// Replacing a schema does not rewrite stored subscription config: existing
+// entries are re-validated (and aliases renamed) on their next save. Use
+// the conformance report to find rows the new schema would reject.
+func SetConfigSchema(repo *subscription.ConfigSchemaRepository) usecaseop.Operation[SetConfigSchemaCommand, ConfigSchemaSet] {
+	return usecaseop.Operation[SetConfigSchemaCommand, ConfigSchemaSet]{
+		Name: "SetSubscriptionConfigSchema",
+		Validate: func(_ context.Context, cmd SetConfigSchemaCommand) error {
+			if cmd.mediationType() != common.MediationTypeHTTP {
+				return usecase.Validation("INVALID_MEDIATION_TYPE", "mediationType must be HTTP")
+			}
+			if cmd.ApplicationCode != nil && strings.TrimSpace(*cmd.ApplicationCode) == "" {
+				return usecase.Validation("APPLICATION_CODE_REQUIRED", "applicationCode cannot be empty")
+			}
+			probe := subscription.ConfigSchema{Fields: cmd.Fields}
+			return probe.Check()
+		},
+		Authorize: usecaseop.Public[SetConfigSchemaCommand],
+		Execute: func(ctx context.Context, cmd SetConfigSchemaCommand, ec usecase.ExecutionContext) (usecaseop.Plan[ConfigSchemaSet], error) {
+			mt := cmd.mediationType()
+			existing, err := repo.Resolve(ctx, cmd.ApplicationCode, mt)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "resolve config schema failed", err)
+			}
+			// Resolve falls back to the platform default; only an exact scope
+			// match is the row to replace.
+			var c *subscription.ConfigSchema
+			if existing != nil && sameScope(existing.ApplicationCode, cmd.ApplicationCode) {
+				c = existing
+				c.Fields = cmd.Fields
+			} else {
+				c = subscription.NewConfigSchema(cmd.ApplicationCode, mt, cmd.Fields)
+			}
+			if c.Fields == nil {
+				c.Fields = []subscription.ConfigField{}
+			}
+			c.Description = cmd.Description
+
+			event := ConfigSchemaSet{
+				Metadata:        usecase.NewEventMetadata(ec, ConfigSchemaSetType, Source, schemaSubjectFor(c.ID)),
+				SchemaID:        c.ID,
+				ApplicationCode: c.ApplicationCode,
+				MediationType:   string(c.MediationType),
+				FieldCount:      len(c.Fields),
+			}
+			return usecaseop.Save(c, repo, event), nil
+		},
+	}
+}
+
+func sameScope(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
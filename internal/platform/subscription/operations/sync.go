@@ -65,6 +65,8 @@ type SyncSubscriptionsCommand struct {
 //     an unresolvable code is silently left unset (matches Rust).
 //   - maxRetries / timeoutSeconds are only overwritten when present.
 //   - RemoveUnlisted hard-deletes API/CODE rows absent from the payload.
+//   - The sync acts in the caller's environment (see auth.EnvironmentFor):
+//     a sandbox key syncs sandbox subscriptions and never sees production's.
 //
 // Authorization: the coarse "may sync subscriptions" permission and the app
 // resolution (code→id) are the controller's job; the use case enforces the
@@ -96,6 +98,12 @@ func SyncSubscriptions(
 				if len(in.EventTypes) == 0 {
 					return usecase.Validation("EVENT_TYPES_REQUIRED", "At least one event type is required")
 				}
+				for _, et := range in.EventTypes {
+					b := subscription.EventTypeBinding{EventTypeCode: et.EventTypeCode, Filter: et.Filter}
+					if err := validateFilters([]subscription.EventTypeBinding{b}); err != nil {
+						return err
+					}
+				}
 			}
 			return nil
 		},
@@ -120,10 +128,12 @@ func SyncSubscriptions(
 				}
 			}
 
-			existing, err := subRepo.FindByApplicationCode(ctx, cmd.ApplicationCode)
+			env := auth.FromContext(ctx).EnvironmentFor("")
+			all, err := subRepo.FindByApplicationCode(ctx, cmd.ApplicationCode)
 			if err != nil {
 				return nil, usecase.Internal("REPO", "find_by_application_code failed", err)
 			}
+			existing := subscription.InEnvironment(all, env)
 			existingByCode := make(map[string]*subscription.Subscription, len(existing))
 			for i := range existing {
 				existingByCode[existing[i].Code] = &existing[i]
@@ -154,6 +164,16 @@ func SyncSubscriptions(
 					if cur.Source != subscription.SourceAPI && cur.Source != subscription.SourceCode {
 						continue // never touch UI-authored rows
 					}
+					if err := checkEgress(ctx, in.Target, cur.TargetAuth, cur.AllowPrivateTarget); err != nil {
+						return nil, err
+					}
+					added := addedBindings(cur.EventTypes, bindings)
+					if err := checkBindingsCurrent(ctx, subRepo, added); err != nil {
+						return nil, err
+					}
+					if err := checkBindingsAvailable(ctx, subRepo, env, added); err != nil {
+						return nil, err
+					}
 					cur.Name = in.Name
 					cur.Description = in.Description
 					cur.Endpoint = in.Target
@@ -172,14 +192,26 @@ func SyncSubscriptions(
 						Event: SubscriptionUpdated{
 							Metadata:       usecase.NewEventMetadata(ec, SubscriptionUpdatedType, Source, subjectFor(cur.ID)),
 							SubscriptionID: cur.ID,
+							Code:           cur.Code,
 							Name:           cur.Name,
+							Status:         string(cur.Status),
 						},
 					})
 					updated++
 					continue
 				}
 
+				if err := checkEgress(ctx, in.Target, nil, false); err != nil {
+					return nil, err
+				}
+				if err := checkBindingsCurrent(ctx, subRepo, bindings); err != nil {
+					return nil, err
+				}
+				if err := checkBindingsAvailable(ctx, subRepo, env, bindings); err != nil {
+					return nil, err
+				}
 				sub := subscription.New(in.Code, in.Name, in.Target)
+				sub.Environment = env
 				sub.ConnectionID = in.ConnectionID
 				appCode := cmd.ApplicationCode
 				sub.ApplicationCode = &appCode
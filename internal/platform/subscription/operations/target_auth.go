@@ -0,0 +1,76 @@
+package operations
+
+import (
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// sealTargetAuth turns the wire form of a target auth into what is
+// persisted. NONE clears it. An OAuth2 secret sent as
+// subscription.MaskedValue (or left empty) keeps the stored ciphertext,
+// but only while the token URL and client id are unchanged: the secret
+// must not follow the subscription to another token endpoint. A new one
+// is encrypted with FLOWCATALYST_APP_KEY. SigV4 goes through
+// checkSigV4Use; platformAWS says the caller may sign with the
+// platform's own credentials.
+func sealTargetAuth(in, stored *subscription.TargetAuth, platformAWS bool) (*subscription.TargetAuth, error) {
+	if err := in.Check(); err != nil {
+		return nil, err
+	}
+	switch in.Type {
+	case subscription.TargetAuthNone:
+		return nil, nil
+	case subscription.TargetAuthSigV4:
+		if err := checkSigV4Use(in.SigV4, platformAWS); err != nil {
+			return nil, err
+		}
+		return in, nil
+	}
+	out := *in
+	o := *in.OAuth2
+	out.OAuth2 = &o
+	if o.ClientSecret == "" || o.ClientSecret == subscription.MaskedValue {
+		if stored == nil || stored.OAuth2 == nil || stored.OAuth2.ClientSecret == "" {
+			return nil, usecase.Validation("INVALID_TARGET_AUTH", "oauth2.clientSecret is required")
+		}
+		if stored.OAuth2.TokenURL != o.TokenURL || stored.OAuth2.ClientID != o.ClientID {
+			return nil, usecase.Validation("TARGET_AUTH_SECRET_REQUIRED",
+				"oauth2.clientSecret must be sent again when tokenUrl or clientId changes")
+		}
+		o.ClientSecret = stored.OAuth2.ClientSecret
+		return &out, nil
+	}
+	enc, err := encryption.FromEnv()
+	if err != nil {
+		return nil, usecase.Internal("ENCRYPTION", "load encryption key failed", err)
+	}
+	if enc == nil {
+		return nil, usecase.Validation("ENCRYPTION_UNAVAILABLE",
+			"FLOWCATALYST_APP_KEY is not configured; cannot store an OAuth2 client secret")
+	}
+	if o.ClientSecret, err = enc.Encrypt(strings.TrimSpace(o.ClientSecret)); err != nil {
+		return nil, usecase.Internal("ENCRYPTION", "encrypt client secret failed", err)
+	}
+	return &out, nil
+}
+
+// checkSigV4Use limits who may point SigV4 at a target. Without a role the
+// platform signs with its own AWS credentials, which only an anchor
+// principal may hand out; anyone else must name a role to assume, with
+// an external id so the role's trust policy can tell tenants apart.
+func checkSigV4Use(s *subscription.SigV4Auth, platformAWS bool) error {
+	if platformAWS {
+		return nil
+	}
+	if s.RoleARN == nil {
+		return usecase.Authorization("PLATFORM_AWS_CREDENTIALS",
+			"only anchor principals may sign with the platform's AWS credentials; set sigv4.roleArn and sigv4.externalId")
+	}
+	if s.ExternalID == nil || strings.TrimSpace(*s.ExternalID) == "" {
+		return usecase.Validation("INVALID_TARGET_AUTH", "sigv4.externalId is required to assume a role")
+	}
+	return nil
+}
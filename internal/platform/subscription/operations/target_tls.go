@@ -0,0 +1,125 @@
+package operations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+)
+
+// SetTargetTLSCommand uploads a subscription's TLS material. The PEM
+// fields replace whatever was stored: omit the CA bundle to trust the
+// system roots only, omit the certificate and key to drop mutual TLS.
+type SetTargetTLSCommand struct {
+	SubscriptionID    string `json:"subscriptionId"`
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	ClientKey         string `json:"clientKey,omitempty"`
+	CABundle          string `json:"caBundle,omitempty"`
+}
+
+// SetTargetTLS validates and stores a subscription's client certificate
+// and/or CA bundle and emits [TargetTLSSet]. The private key is
+// encrypted with FLOWCATALYST_APP_KEY before it is persisted.
+func SetTargetTLS(repo *subscription.Repository, tlsRepo *subscription.TargetTLSRepository) usecaseop.Operation[SetTargetTLSCommand, TargetTLSSet] {
+	return usecaseop.Operation[SetTargetTLSCommand, TargetTLSSet]{
+		Name: "SetSubscriptionTargetTLS",
+		Validate: func(_ context.Context, cmd SetTargetTLSCommand) error {
+			if strings.TrimSpace(cmd.SubscriptionID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			_, err := subscription.ParseTargetTLS(cmd.SubscriptionID, cmd.ClientCertificate, cmd.ClientKey, cmd.CABundle, time.Now())
+			return err
+		},
+		// Per-resource authz runs post-load in Execute; the coarse "may write
+		// subscriptions" permission is on the controller.
+		Authorize: usecaseop.Public[SetTargetTLSCommand],
+		Execute: func(ctx context.Context, cmd SetTargetTLSCommand, ec usecase.ExecutionContext) (usecaseop.Plan[TargetTLSSet], error) {
+			s, err := repo.FindByID(ctx, cmd.SubscriptionID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if s == nil {
+				return nil, httperror.NotFound("Subscription", cmd.SubscriptionID)
+			}
+			if err := checkAccess(ctx, s); err != nil {
+				return nil, err
+			}
+			t, err := subscription.ParseTargetTLS(s.ID, cmd.ClientCertificate, cmd.ClientKey, cmd.CABundle, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			if t.ClientKey != nil {
+				enc, err := encryption.FromEnv()
+				if err != nil {
+					return nil, usecase.Internal("ENCRYPTION", "load encryption key failed", err)
+				}
+				if enc == nil {
+					return nil, usecase.Validation("ENCRYPTION_UNAVAILABLE",
+						"FLOWCATALYST_APP_KEY is not configured; cannot store a client private key")
+				}
+				sealed, err := enc.Encrypt(*t.ClientKey)
+				if err != nil {
+					return nil, usecase.Internal("ENCRYPTION", "encrypt client key failed", err)
+				}
+				t.ClientKey = &sealed
+			}
+			event := TargetTLSSet{
+				Metadata:       usecase.NewEventMetadata(ec, TargetTLSSetType, Source, subjectFor(s.ID)),
+				SubscriptionID: s.ID,
+				CertSubject:    t.CertSubject,
+				CertNotAfter:   t.CertNotAfter,
+				CANotAfter:     t.CANotAfter,
+			}
+			return usecaseop.Save(t, tlsRepo, event), nil
+		},
+	}
+}
+
+// ClearTargetTLSCommand is the input DTO.
+type ClearTargetTLSCommand struct {
+	SubscriptionID string `json:"subscriptionId"`
+}
+
+// ClearTargetTLS removes a subscription's TLS material and emits
+// [TargetTLSCleared]. Deliveries fall back to the default TLS config.
+func ClearTargetTLS(repo *subscription.Repository, tlsRepo *subscription.TargetTLSRepository) usecaseop.Operation[ClearTargetTLSCommand, TargetTLSCleared] {
+	return usecaseop.Operation[ClearTargetTLSCommand, TargetTLSCleared]{
+		Name: "ClearSubscriptionTargetTLS",
+		Validate: func(_ context.Context, cmd ClearTargetTLSCommand) error {
+			if strings.TrimSpace(cmd.SubscriptionID) == "" {
+				return usecase.Validation("ID_REQUIRED", "id is required")
+			}
+			return nil
+		},
+		Authorize: usecaseop.Public[ClearTargetTLSCommand],
+		Execute: func(ctx context.Context, cmd ClearTargetTLSCommand, ec usecase.ExecutionContext) (usecaseop.Plan[TargetTLSCleared], error) {
+			s, err := repo.FindByID(ctx, cmd.SubscriptionID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_by_id failed", err)
+			}
+			if s == nil {
+				return nil, httperror.NotFound("Subscription", cmd.SubscriptionID)
+			}
+			if err := checkAccess(ctx, s); err != nil {
+				return nil, err
+			}
+			t, err := tlsRepo.FindTargetTLS(ctx, s.ID)
+			if err != nil {
+				return nil, usecase.Internal("REPO", "find_target_tls failed", err)
+			}
+			if t == nil {
+				return nil, httperror.NotFound("SubscriptionTargetTLS", s.ID)
+			}
+			event := TargetTLSCleared{
+				Metadata:       usecase.NewEventMetadata(ec, TargetTLSClearedType, Source, subjectFor(s.ID)),
+				SubscriptionID: s.ID,
+			}
+			return usecaseop.Delete(t, tlsRepo, event), nil
+		},
+	}
+}
@@ -2,36 +2,62 @@ package operations
 
 import (
 	"context"
+	"reflect"
 	"strings"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/auth"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
 )
 
 // UpdateCommand applies optional updates. Nil pointers mean "don't change".
 type UpdateCommand struct {
-	ID               string                          `json:"id"`
-	Name             *string                         `json:"name,omitempty"`
-	Description      *string                         `json:"description,omitempty"`
-	Endpoint         *string                         `json:"endpoint,omitempty"`
-	ConnectionID     *string                         `json:"connectionId,omitempty"`
-	EventTypes       []subscription.EventTypeBinding `json:"eventTypes,omitempty"`
-	CustomConfig     []subscription.ConfigEntry      `json:"customConfig,omitempty"`
-	Mode             *string                         `json:"mode,omitempty"`
-	TimeoutSeconds   *int32                          `json:"timeoutSeconds,omitempty"`
-	MaxRetries       *int32                          `json:"maxRetries,omitempty"`
-	DelaySeconds     *int32                          `json:"delaySeconds,omitempty"`
-	MaxAgeSeconds    *int32                          `json:"maxAgeSeconds,omitempty"`
-	DispatchPoolID   *string                         `json:"dispatchPoolId,omitempty"`
-	ServiceAccountID *string                         `json:"serviceAccountId,omitempty"`
-	DataOnly         *bool                           `json:"dataOnly,omitempty"`
+	ID                 string                          `json:"id"`
+	Name               *string                         `json:"name,omitempty"`
+	Description        *string                         `json:"description,omitempty"`
+	Endpoint           *string                         `json:"endpoint,omitempty"`
+	ConnectionID       *string                         `json:"connectionId,omitempty"`
+	EventTypes         []subscription.EventTypeBinding `json:"eventTypes,omitempty"`
+	CustomConfig       []subscription.ConfigEntry      `json:"customConfig,omitempty"`
+	TargetAuth         *subscription.TargetAuth        `json:"targetAuth,omitempty"`
+	Transform          *subscription.PayloadTransform  `json:"transform,omitempty"`
+	Mode               *string                         `json:"mode,omitempty"`
+	Priority           *string                         `json:"priority,omitempty"`
+	Weight             *int32                          `json:"weight,omitempty"`
+	AckTimeoutSeconds  *int32                          `json:"ackTimeoutSeconds,omitempty"`
+	DeliveryHeaders    []string                        `json:"deliveryHeaders,omitempty"`
+	TimeoutSeconds     *int32                          `json:"timeoutSeconds,omitempty"`
+	MaxRetries         *int32                          `json:"maxRetries,omitempty"`
+	HonorRetryAfter    *bool                           `json:"honorRetryAfter,omitempty"`
+	DeliveryFormat     *string                         `json:"deliveryFormat,omitempty"`
+	DeliveryWindow     *deliverywindow.Schedule        `json:"deliveryWindow,omitempty"`
+	AllowPrivateTarget *bool                           `json:"allowPrivateTarget,omitempty"`
+	EgressProxy        *string                         `json:"egressProxy,omitempty"`
+	DelaySeconds       *int32                          `json:"delaySeconds,omitempty"`
+	MaxAgeSeconds      *int32                          `json:"maxAgeSeconds,omitempty"`
+	DispatchPoolID     *string                         `json:"dispatchPoolId,omitempty"`
+	ServiceAccountID   *string                         `json:"serviceAccountId,omitempty"`
+	DataOnly           *bool                           `json:"dataOnly,omitempty"`
+	Tap                *TapCommand                     `json:"tap,omitempty"`
+	// MayOverrideEgress says the caller holds the egress-override
+	// permission, resolved by the handler.
+	MayOverrideEgress bool `json:"-"`
+	// PlatformAWS says the caller is an anchor principal, resolved by the
+	// handler; see [CreateCommand.PlatformAWS].
+	PlatformAWS bool `json:"-"`
+	// Approver says the caller holds the approve permission, resolved by
+	// the handler; see [CreateCommand.Approver].
+	Approver bool `json:"-"`
 }
 
 // UpdateSubscription mutates mutable fields and emits [SubscriptionUpdated].
+// In a client that requires approval, a caller who isn't an
+// [UpdateCommand.Approver] changing where or how an approved subscription
+// delivers (endpoint, target auth, transform) sends it back to
+// PENDING_APPROVAL; it receives nothing until approved again.
 func UpdateSubscription(repo *subscription.Repository) usecaseop.Operation[UpdateCommand, SubscriptionUpdated] {
 	return usecaseop.Operation[UpdateCommand, SubscriptionUpdated]{
 		Name: "UpdateSubscription",
@@ -45,7 +71,38 @@ func UpdateSubscription(repo *subscription.Repository) usecaseop.Operation[Updat
 			if cmd.Endpoint != nil && !urlPattern.MatchString(*cmd.Endpoint) {
 				return usecase.Validation("INVALID_ENDPOINT", "endpoint must be a http(s) URL")
 			}
-			return nil
+			if cmd.Priority != nil {
+				if err := validatePriority(*cmd.Priority); err != nil {
+					return err
+				}
+			}
+			if err := validateWeight(cmd.Weight); err != nil {
+				return err
+			}
+			if err := validateAckTimeout(cmd.AckTimeoutSeconds); err != nil {
+				return err
+			}
+			if err := validateDeliveryHeaders(cmd.DeliveryHeaders); err != nil {
+				return err
+			}
+			if cmd.DeliveryFormat != nil {
+				if err := validateDeliveryFormat(*cmd.DeliveryFormat); err != nil {
+					return err
+				}
+			}
+			if err := validateDeliveryWindow(cmd.DeliveryWindow); err != nil {
+				return err
+			}
+			if err := validateEgressProxy(egressProxy(cmd.EgressProxy)); err != nil {
+				return err
+			}
+			if err := validateTransform(cmd.Transform); err != nil {
+				return err
+			}
+			if err := validateTap(cmd.Tap); err != nil {
+				return err
+			}
+			return validateFilters(cmd.EventTypes)
 		},
 		// Per-resource authz needs the loaded row, so it runs post-load in
 		// Execute; the coarse "may write subscriptions" permission is on the
@@ -61,9 +118,17 @@ func UpdateSubscription(repo *subscription.Repository) usecaseop.Operation[Updat
 			}
 			// Per-resource scope: a non-anchor principal must not mutate another
 			// tenant's subscription by guessing its id.
-			if err := auth.CheckScopeAccess(auth.FromContext(ctx), s.ClientID); err != nil {
+			if err := checkAccess(ctx, s); err != nil {
 				return nil, err
 			}
+			// Only turning the egress override on needs the permission, so
+			// an editor re-sending the current value isn't refused.
+			if isTrue(cmd.AllowPrivateTarget) && !s.AllowPrivateTarget && !cmd.MayOverrideEgress {
+				return nil, usecase.Authorization("EGRESS_OVERRIDE_REQUIRED",
+					"turning on allowPrivateTarget needs the egress-override permission")
+			}
+
+			prevEndpoint, prevAuth, prevTransform := s.Endpoint, s.TargetAuth, s.Transform
 
 			if cmd.Name != nil {
 				s.Name = strings.TrimSpace(*cmd.Name)
@@ -81,20 +146,73 @@ func UpdateSubscription(repo *subscription.Repository) usecaseop.Operation[Updat
 				s.ConnectionID = cmd.ConnectionID
 			}
 			if cmd.EventTypes != nil {
+				// Existing bindings stay when their type is deprecated, so
+				// only the ones being added are checked.
+				added := addedBindings(s.EventTypes, cmd.EventTypes)
+				if err := checkBindingsCurrent(ctx, repo, added); err != nil {
+					return nil, err
+				}
+				if err := checkBindingsAvailable(ctx, repo, s.Environment, added); err != nil {
+					return nil, err
+				}
 				s.EventTypes = cmd.EventTypes
 			}
+			// Config is only re-validated when supplied: rows saved before a
+			// schema existed keep their legacy entries until next edited.
 			if cmd.CustomConfig != nil {
-				s.CustomConfig = cmd.CustomConfig
+				if s.CustomConfig, err = applyConfigSchema(ctx, repo, s, cmd.CustomConfig, s.CustomConfig); err != nil {
+					return nil, err
+				}
+			}
+			// Type NONE removes the target auth; omitted leaves it as is.
+			if cmd.TargetAuth != nil {
+				if s.TargetAuth, err = sealTargetAuth(cmd.TargetAuth, s.TargetAuth, cmd.PlatformAWS); err != nil {
+					return nil, err
+				}
+			} else if cmd.Endpoint != nil && s.TargetAuth != nil && s.TargetAuth.SigV4 != nil {
+				// Moving a stored SigV4 config to a new endpoint is held to
+				// the same rule as setting it.
+				if err := checkSigV4Use(s.TargetAuth.SigV4, cmd.PlatformAWS); err != nil {
+					return nil, err
+				}
+			}
+			// A blank template removes the transform; omitted leaves it as is.
+			if cmd.Transform != nil {
+				s.Transform = normalizeTransform(cmd.Transform)
 			}
 			if cmd.Mode != nil {
 				s.Mode = common.ParseDispatchMode(*cmd.Mode)
 			}
+			if cmd.Priority != nil {
+				s.Priority = common.Priority(*cmd.Priority)
+			}
+			if cmd.Weight != nil {
+				s.Weight = *cmd.Weight
+			}
+			if cmd.AckTimeoutSeconds != nil {
+				s.AckTimeoutSeconds = ackTimeout(cmd.AckTimeoutSeconds)
+			}
+			// An empty list goes back to every standard header; omitted
+			// leaves the choice as is.
+			if cmd.DeliveryHeaders != nil {
+				s.DeliveryHeaders = deliveryHeaders(cmd.DeliveryHeaders)
+			}
 			if cmd.TimeoutSeconds != nil {
 				s.TimeoutSeconds = *cmd.TimeoutSeconds
 			}
 			if cmd.MaxRetries != nil {
 				s.MaxRetries = *cmd.MaxRetries
 			}
+			if cmd.HonorRetryAfter != nil {
+				s.HonorRetryAfter = *cmd.HonorRetryAfter
+			}
+			if cmd.DeliveryFormat != nil {
+				s.DeliveryFormat = subscription.DeliveryFormat(*cmd.DeliveryFormat)
+			}
+			// An empty calendar removes the window; omitted leaves it as is.
+			if cmd.DeliveryWindow != nil {
+				s.DeliveryWindow = normalizeDeliveryWindow(cmd.DeliveryWindow)
+			}
 			if cmd.DelaySeconds != nil {
 				s.DelaySeconds = *cmd.DelaySeconds
 			}
@@ -110,13 +228,74 @@ func UpdateSubscription(repo *subscription.Repository) usecaseop.Operation[Updat
 			if cmd.DataOnly != nil {
 				s.DataOnly = *cmd.DataOnly
 			}
+			if cmd.AllowPrivateTarget != nil {
+				s.AllowPrivateTarget = *cmd.AllowPrivateTarget
+			}
+			if cmd.EgressProxy != nil {
+				s.EgressProxy = egressProxy(cmd.EgressProxy)
+			}
+			// A tap's sample can change and its expiry re-arm from now, but
+			// an ordinary subscription can't be turned into one.
+			if cmd.Tap != nil {
+				if s.Tap == nil {
+					return nil, usecase.Validation("NOT_A_TAP", "tap settings apply only to a subscription created as a tap")
+				}
+				s.Tap = newTap(cmd.Tap)
+			}
+			if cmd.Endpoint != nil || cmd.TargetAuth != nil || cmd.AllowPrivateTarget != nil {
+				if err := checkEgress(ctx, s.Endpoint, s.TargetAuth, s.AllowPrivateTarget); err != nil {
+					return nil, err
+				}
+			}
+			// A masked secret keeps the stored ciphertext, so re-sending
+			// the current target auth compares equal.
+			sentForApproval := false
+			retargeted := s.Endpoint != prevEndpoint ||
+				!reflect.DeepEqual(s.TargetAuth, prevAuth) ||
+				!reflect.DeepEqual(s.Transform, prevTransform)
+			if retargeted && !cmd.Approver && s.Status.Approved() {
+				required, err := repo.ApprovalRequired(ctx, s.ClientID)
+				if err != nil {
+					return nil, usecase.Internal("REPO", "approval_required failed", err)
+				}
+				if required {
+					s.Status = subscription.StatusPendingApproval
+					sentForApproval = true
+				}
+			}
 
 			event := SubscriptionUpdated{
-				Metadata:       usecase.NewEventMetadata(ec, SubscriptionUpdatedType, Source, subjectFor(s.ID)),
-				SubscriptionID: s.ID,
-				Name:           s.Name,
+				Metadata:        usecase.NewEventMetadata(ec, SubscriptionUpdatedType, Source, subjectFor(s.ID)),
+				SubscriptionID:  s.ID,
+				Code:            s.Code,
+				Name:            s.Name,
+				ClientID:        s.ClientID,
+				Status:          string(s.Status),
+				SentForApproval: sentForApproval,
 			}
 			return usecaseop.Save(s, repo, event), nil
 		},
 	}
 }
+
+// addedBindings returns the bindings in next whose (code, version) pair
+// isn't already in prev.
+func addedBindings(prev, next []subscription.EventTypeBinding) []subscription.EventTypeBinding {
+	key := func(b subscription.EventTypeBinding) string {
+		if b.SpecVersion == nil {
+			return b.EventTypeCode
+		}
+		return b.EventTypeCode + "@" + *b.SpecVersion
+	}
+	had := make(map[string]bool, len(prev))
+	for _, b := range prev {
+		had[key(b)] = true
+	}
+	var added []subscription.EventTypeBinding
+	for _, b := range next {
+		if !had[key(b)] {
+			added = append(added, b)
+		}
+	}
+	return added
+}
@@ -2,31 +2,45 @@ package subscription
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/repocommon"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverywindow"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/sqlc/dbq"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
 // Repository is the Postgres-backed repository. Tables: msg_subscriptions
-// + msg_subscription_event_types + msg_subscription_custom_configs.
-// EventTypeBinding.Filter is in-memory only — there's no column for it.
+// + msg_subscription_event_types + msg_subscription_custom_configs +
+// msg_subscription_target_auth + msg_subscription_transforms. The
+// target TLS material (msg_subscription_target_tls) has its own
+// TargetTLSRepository; Delete clears it with the subscription. Approval
+// decisions (msg_subscription_approvals) are written by Approvals().
 type Repository struct {
-	pool *pgxpool.Pool // retained for FindWithFilters
-	q    *dbq.Queries
+	pool    *pgxpool.Pool // retained for FindWithFilters
+	q       *dbq.Queries
+	schemas *ConfigSchemaRepository
 }
 
 // NewRepository wires a repo.
 func NewRepository(pool *pgxpool.Pool) *Repository {
-	return &Repository{pool: pool, q: dbq.New(pool)}
+	q := dbq.New(pool)
+	return &Repository{pool: pool, q: q, schemas: &ConfigSchemaRepository{q: q}}
 }
 
+// ConfigSchemas returns the sibling config-schema repo on the same pool.
+func (r *Repository) ConfigSchemas() *ConfigSchemaRepository { return r.schemas }
+
 // FindByID loads a subscription with hydrated junction tables.
 func (r *Repository) FindByID(ctx context.Context, id string) (*Subscription, error) {
 	res, err := r.q.SubscriptionFindByID(ctx, id)
@@ -37,18 +51,165 @@ func (r *Repository) FindByID(ctx context.Context, id string) (*Subscription, er
 	return r.hydrateOne(ctx, rowToSubscription(*row))
 }
 
-// FindByCode loads by (code, client_id).
-func (r *Repository) FindByCode(ctx context.Context, code string, clientID *string) (*Subscription, error) {
+// FindTargetAuth loads only the target auth for one subscription — the
+// dispatch delivery path needs nothing else. Nil when none is configured.
+func (r *Repository) FindTargetAuth(ctx context.Context, subscriptionID string) (*TargetAuth, error) {
+	res, err := r.q.SubscriptionTargetAuthFind(ctx, subscriptionID)
+	row, err := repocommon.One(res, err, "subscription repo")
+	if row == nil || err != nil {
+		return nil, err
+	}
+	return decodeTargetAuth(row.Config)
+}
+
+// FindTransform loads only the payload transform for one subscription.
+// Nil when none is configured.
+func (r *Repository) FindTransform(ctx context.Context, subscriptionID string) (*PayloadTransform, error) {
+	res, err := r.q.SubscriptionTransformFind(ctx, subscriptionID)
+	row, err := repocommon.One(res, err, "subscription repo")
+	if row == nil || err != nil {
+		return nil, err
+	}
+	return &PayloadTransform{
+		Engine:      payloadtransform.Engine(row.Engine),
+		Template:    row.Template,
+		ContentType: row.ContentType,
+	}, nil
+}
+
+// FindDeliverySettings loads what dispatch needs from one subscription
+// to deliver to it. The FLOWCATALYST format, honouring Retry-After, and
+// otherwise zero (all headers, no calendar, acknowledgement or transform,
+// default egress, not a tap) when it doesn't exist.
+func (r *Repository) FindDeliverySettings(ctx context.Context, subscriptionID string) (DeliverySettings, error) {
+	res, err := r.q.SubscriptionDeliverySettingsFind(ctx, subscriptionID)
+	row, err := repocommon.One(res, err, "subscription repo")
+	if row == nil || err != nil {
+		return DeliverySettings{Format: DeliveryFlowCatalyst, HonorRetryAfter: true}, err
+	}
+	window, err := deliverywindow.Parse(row.DeliveryWindow)
+	if err != nil {
+		return DeliverySettings{}, err
+	}
+	ds := DeliverySettings{
+		Format:          ParseDeliveryFormat(row.DeliveryFormat),
+		Headers:         parseDeliveryHeaders(row.DeliveryHeaders),
+		Window:          window,
+		Egress:          egress.Settings{AllowPrivate: row.AllowPrivateTarget},
+		Tap:             parseTap(row.TapSamplePercent, row.TapExpiresAt),
+		HonorRetryAfter: row.HonorRetryAfter,
+	}
+	if row.AckTimeoutSeconds != nil {
+		ds.AckTimeout = time.Duration(*row.AckTimeoutSeconds) * time.Second
+	}
+	if row.EgressProxy != nil {
+		ds.Egress.Proxy = *row.EgressProxy
+	}
+	if row.TransformTemplate != nil {
+		ds.Transform = &PayloadTransform{
+			Engine:      payloadtransform.Engine(*row.TransformEngine),
+			Template:    *row.TransformTemplate,
+			ContentType: *row.TransformContentType,
+		}
+	}
+	return ds, nil
+}
+
+// FindRetiredBindings returns the bindings that target a retired event
+// type: one that is DEPRECATED or ARCHIVED, or a schema version that is
+// DEPRECATED. Wildcard patterns and unregistered codes are never retired.
+// Entries read "code" or "code@version".
+func (r *Repository) FindRetiredBindings(ctx context.Context, bindings []EventTypeBinding) ([]string, error) {
+	var codes []string
+	for _, b := range bindings {
+		if !strings.Contains(b.EventTypeCode, "*") {
+			codes = append(codes, b.EventTypeCode)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT et.code, et.status, sv.version
+		   FROM msg_event_types et
+		   LEFT JOIN msg_event_type_spec_versions sv
+		          ON sv.event_type_id = et.id AND sv.status = 'DEPRECATED'
+		  WHERE et.code = ANY($1)`, codes)
+	if err != nil {
+		return nil, fmt.Errorf("subscription FindRetiredBindings: %w", err)
+	}
+	defer rows.Close()
+	retiredTypes := map[string]bool{}
+	retiredVersions := map[string]bool{}
+	for rows.Next() {
+		var code, status string
+		var version *string
+		if err := rows.Scan(&code, &status, &version); err != nil {
+			return nil, fmt.Errorf("subscription FindRetiredBindings: %w", err)
+		}
+		if status != "CURRENT" {
+			retiredTypes[code] = true
+		}
+		if version != nil {
+			retiredVersions[code+"@"+*version] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("subscription FindRetiredBindings: %w", err)
+	}
+	var retired []string
+	for _, b := range bindings {
+		switch {
+		case retiredTypes[b.EventTypeCode]:
+			retired = append(retired, b.EventTypeCode)
+		case b.SpecVersion != nil && retiredVersions[b.EventTypeCode+"@"+*b.SpecVersion]:
+			retired = append(retired, b.EventTypeCode+"@"+*b.SpecVersion)
+		}
+	}
+	return retired, nil
+}
+
+// FindSandboxEventTypes returns the codes among bindings that name a
+// SANDBOX event type. Wildcard patterns and unregistered codes are never
+// sandbox-only.
+func (r *Repository) FindSandboxEventTypes(ctx context.Context, bindings []EventTypeBinding) ([]string, error) {
+	var codes []string
+	for _, b := range bindings {
+		if !strings.Contains(b.EventTypeCode, "*") {
+			codes = append(codes, b.EventTypeCode)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT code FROM msg_event_types
+		  WHERE code = ANY($1) AND environment = 'SANDBOX'
+		  ORDER BY code`, codes)
+	if err != nil {
+		return nil, fmt.Errorf("subscription FindSandboxEventTypes: %w", err)
+	}
+	sandbox, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("subscription FindSandboxEventTypes: %w", err)
+	}
+	return sandbox, nil
+}
+
+// FindByCode loads by (code, client_id, environment).
+func (r *Repository) FindByCode(ctx context.Context, code string, clientID *string, env common.Environment) (*Subscription, error) {
 	var (
 		res dbq.MsgSubscription
 		err error
 	)
 	if clientID != nil {
 		res, err = r.q.SubscriptionFindByCodeClient(ctx, dbq.SubscriptionFindByCodeClientParams{
-			Code: code, ClientID: clientID,
+			Code: code, ClientID: clientID, Environment: string(env),
 		})
 	} else {
-		res, err = r.q.SubscriptionFindByCodeAnchor(ctx, code)
+		res, err = r.q.SubscriptionFindByCodeAnchor(ctx, dbq.SubscriptionFindByCodeAnchorParams{
+			Code: code, Environment: string(env),
+		})
 	}
 	row, err := repocommon.One(res, err, "subscription repo")
 	if row == nil || err != nil {
@@ -71,16 +232,19 @@ func (r *Repository) FindAll(ctx context.Context) ([]Subscription, error) {
 }
 
 // FindWithFilters returns subscriptions matching non-nil filters.
-func (r *Repository) FindWithFilters(ctx context.Context, status, clientID *string) ([]Subscription, error) {
+func (r *Repository) FindWithFilters(ctx context.Context, status, clientID, environment *string) ([]Subscription, error) {
 	var f repocommon.Filter
 	f.EqPtr("status", status)
 	f.EqPtr("client_id", clientID)
+	f.EqPtr("environment", environment)
 
 	q := `SELECT id, code, application_code, name, description, client_id,
 		client_identifier, client_scoped, target, queue, source, status,
 		max_age_seconds, dispatch_pool_id, dispatch_pool_code, delay_seconds, sequence,
 		mode, timeout_seconds, max_retries, service_account_id, data_only,
-		created_by, created_at, updated_at, connection_id FROM msg_subscriptions` + f.Where() + ` ORDER BY code`
+		created_by, created_at, updated_at, connection_id, priority, honor_retry_after, delivery_format, delivery_window,
+		allow_private_target, egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+		tap_sample_percent, tap_expires_at FROM msg_subscriptions` + f.Where() + ` ORDER BY code`
 
 	rows, err := r.pool.Query(ctx, q, f.Args()...)
 	if err != nil {
@@ -106,7 +270,9 @@ func (r *Repository) FindByApplicationCode(ctx context.Context, appCode string)
 		client_identifier, client_scoped, target, queue, source, status,
 		max_age_seconds, dispatch_pool_id, dispatch_pool_code, delay_seconds, sequence,
 		mode, timeout_seconds, max_retries, service_account_id, data_only,
-		created_by, created_at, updated_at, connection_id FROM msg_subscriptions
+		created_by, created_at, updated_at, connection_id, priority, honor_retry_after, delivery_format, delivery_window,
+		allow_private_target, egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+		tap_sample_percent, tap_expires_at FROM msg_subscriptions
 		WHERE application_code = $1 ORDER BY code`
 	rows, err := r.pool.Query(ctx, baseSelect, appCode)
 	if err != nil {
@@ -127,33 +293,56 @@ func (r *Repository) FindByApplicationCode(ctx context.Context, appCode string)
 // junction-table rows (event_types, custom_config) wholesale.
 func (r *Repository) Persist(ctx context.Context, s *Subscription, tx *usecasepgx.DbTx) error {
 	q := r.q.WithTx(tx.Inner())
+	window, err := deliverywindow.Marshal(s.DeliveryWindow)
+	if err != nil {
+		return fmt.Errorf("subscription persist: encode delivery window: %w", err)
+	}
+	var (
+		tapPercent   *float64
+		tapExpiresAt *time.Time
+	)
+	if s.Tap != nil {
+		tapPercent, tapExpiresAt = &s.Tap.SamplePercent, &s.Tap.ExpiresAt
+	}
 	if err := q.SubscriptionUpsert(ctx, dbq.SubscriptionUpsertParams{
-		ID:               s.ID,
-		Code:             s.Code,
-		ApplicationCode:  s.ApplicationCode,
-		Name:             s.Name,
-		Description:      s.Description,
-		ClientID:         s.ClientID,
-		ClientIdentifier: s.ClientIdentifier,
-		ClientScoped:     s.ClientScoped,
-		ConnectionID:     s.ConnectionID,
-		Target:           s.Endpoint,
-		Queue:            s.Queue,
-		Source:           string(s.Source),
-		Status:           string(s.Status),
-		MaxAgeSeconds:    s.MaxAgeSeconds,
-		DispatchPoolID:   s.DispatchPoolID,
-		DispatchPoolCode: s.DispatchPoolCode,
-		DelaySeconds:     s.DelaySeconds,
-		Sequence:         s.Sequence,
-		Mode:             string(s.Mode),
-		TimeoutSeconds:   s.TimeoutSeconds,
-		MaxRetries:       s.MaxRetries,
-		ServiceAccountID: s.ServiceAccountID,
-		DataOnly:         s.DataOnly,
-		CreatedBy:        s.CreatedBy,
-		CreatedAt:        s.CreatedAt,
-		UpdatedAt:        time.Now().UTC(),
+		ID:                 s.ID,
+		Code:               s.Code,
+		ApplicationCode:    s.ApplicationCode,
+		Name:               s.Name,
+		Description:        s.Description,
+		ClientID:           s.ClientID,
+		ClientIdentifier:   s.ClientIdentifier,
+		ClientScoped:       s.ClientScoped,
+		ConnectionID:       s.ConnectionID,
+		Target:             s.Endpoint,
+		Queue:              s.Queue,
+		Source:             string(s.Source),
+		Status:             string(s.Status),
+		MaxAgeSeconds:      s.MaxAgeSeconds,
+		DispatchPoolID:     s.DispatchPoolID,
+		DispatchPoolCode:   s.DispatchPoolCode,
+		DelaySeconds:       s.DelaySeconds,
+		Sequence:           s.Sequence,
+		Mode:               string(s.Mode),
+		Priority:           string(s.Priority),
+		HonorRetryAfter:    s.HonorRetryAfter,
+		DeliveryFormat:     string(s.DeliveryFormat),
+		DeliveryWindow:     window,
+		AllowPrivateTarget: s.AllowPrivateTarget,
+		EgressProxy:        s.EgressProxy,
+		Weight:             s.Weight,
+		AckTimeoutSeconds:  s.AckTimeoutSeconds,
+		DeliveryHeaders:    deliveryHeaderStrings(s.DeliveryHeaders),
+		Environment:        string(s.Environment),
+		TapSamplePercent:   tapPercent,
+		TapExpiresAt:       tapExpiresAt,
+		TimeoutSeconds:     s.TimeoutSeconds,
+		MaxRetries:         s.MaxRetries,
+		ServiceAccountID:   s.ServiceAccountID,
+		DataOnly:           s.DataOnly,
+		CreatedBy:          s.CreatedBy,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          time.Now().UTC(),
 	}); err != nil {
 		return fmt.Errorf("subscription persist: %w", err)
 	}
@@ -169,6 +358,7 @@ func (r *Repository) Persist(ctx context.Context, s *Subscription, tx *usecasepg
 			EventTypeID:    b.EventTypeID,
 			EventTypeCode:  b.EventTypeCode,
 			SpecVersion:    b.SpecVersion,
+			Filter:         b.Filter,
 		}); err != nil {
 			return err
 		}
@@ -182,7 +372,33 @@ func (r *Repository) Persist(ctx context.Context, s *Subscription, tx *usecasepg
 			return err
 		}
 	}
-	return nil
+	if err := r.persistTargetAuth(ctx, q, s); err != nil {
+		return err
+	}
+	if s.Transform == nil {
+		return q.SubscriptionTransformClear(ctx, s.ID)
+	}
+	return q.SubscriptionTransformUpsert(ctx, dbq.SubscriptionTransformUpsertParams{
+		SubscriptionID: s.ID,
+		Engine:         string(s.Transform.Engine),
+		Template:       s.Transform.Template,
+		ContentType:    s.Transform.ContentType,
+	})
+}
+
+func (r *Repository) persistTargetAuth(ctx context.Context, q *dbq.Queries, s *Subscription) error {
+	if s.TargetAuth == nil {
+		return q.SubscriptionTargetAuthClear(ctx, s.ID)
+	}
+	cfg, err := json.Marshal(s.TargetAuth)
+	if err != nil {
+		return fmt.Errorf("subscription target auth: %w", err)
+	}
+	return q.SubscriptionTargetAuthUpsert(ctx, dbq.SubscriptionTargetAuthUpsertParams{
+		SubscriptionID: s.ID,
+		AuthType:       string(s.TargetAuth.Type),
+		Config:         cfg,
+	})
 }
 
 // Delete removes the subscription.
@@ -190,6 +406,9 @@ func (r *Repository) Delete(ctx context.Context, s *Subscription, tx *usecasepgx
 	q := r.q.WithTx(tx.Inner())
 	_ = q.SubscriptionEventTypesClear(ctx, s.ID)
 	_ = q.SubscriptionConfigsClear(ctx, s.ID)
+	_ = q.SubscriptionTargetAuthClear(ctx, s.ID)
+	_ = q.SubscriptionTransformClear(ctx, s.ID)
+	_, _ = tx.Inner().Exec(ctx, `DELETE FROM msg_subscription_target_tls WHERE subscription_id = $1`, s.ID)
 	return q.SubscriptionDelete(ctx, s.ID)
 }
 
@@ -220,6 +439,14 @@ func (r *Repository) hydrateAll(ctx context.Context, subs []Subscription) ([]Sub
 	if err != nil {
 		return nil, err
 	}
+	authRows, err := r.q.SubscriptionTargetAuthForSubs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	transformRows, err := r.q.SubscriptionTransformsForSubs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
 
 	bindingsByID := make(map[string][]EventTypeBinding)
 	for _, b := range bindingRows {
@@ -227,6 +454,7 @@ func (r *Repository) hydrateAll(ctx context.Context, subs []Subscription) ([]Sub
 			EventTypeID:   b.EventTypeID,
 			EventTypeCode: b.EventTypeCode,
 			SpecVersion:   b.SpecVersion,
+			Filter:        b.Filter,
 		})
 	}
 	configsByID := make(map[string][]ConfigEntry)
@@ -235,7 +463,25 @@ func (r *Repository) hydrateAll(ctx context.Context, subs []Subscription) ([]Sub
 			Key: c.ConfigKey, Value: c.ConfigValue,
 		})
 	}
+	authByID := make(map[string]*TargetAuth, len(authRows))
+	for _, a := range authRows {
+		ta, err := decodeTargetAuth(a.Config)
+		if err != nil {
+			return nil, err
+		}
+		authByID[a.SubscriptionID] = ta
+	}
+	transformByID := make(map[string]*PayloadTransform, len(transformRows))
+	for _, t := range transformRows {
+		transformByID[t.SubscriptionID] = &PayloadTransform{
+			Engine:      payloadtransform.Engine(t.Engine),
+			Template:    t.Template,
+			ContentType: t.ContentType,
+		}
+	}
 	for i := range subs {
+		subs[i].TargetAuth = authByID[subs[i].ID]
+		subs[i].Transform = transformByID[subs[i].ID]
 		subs[i].EventTypes = bindingsByID[subs[i].ID]
 		subs[i].CustomConfig = configsByID[subs[i].ID]
 		if subs[i].EventTypes == nil {
@@ -248,35 +494,95 @@ func (r *Repository) hydrateAll(ctx context.Context, subs []Subscription) ([]Sub
 	return subs, nil
 }
 
+func decodeTargetAuth(raw json.RawMessage) (*TargetAuth, error) {
+	var ta TargetAuth
+	if err := json.Unmarshal(raw, &ta); err != nil {
+		return nil, fmt.Errorf("subscription target auth: %w", err)
+	}
+	return &ta, nil
+}
+
 func rowToSubscription(row dbq.MsgSubscription) *Subscription {
+	// A stored calendar that no longer parses (e.g. a timezone dropped from
+	// tzdata) reads as always open rather than failing the load.
+	window, err := deliverywindow.Parse(row.DeliveryWindow)
+	if err != nil {
+		slog.Warn("subscription: ignoring invalid delivery window", "subscription_id", row.ID, "err", err)
+	}
 	return &Subscription{
-		ID:               row.ID,
-		Code:             row.Code,
-		ApplicationCode:  row.ApplicationCode,
-		Name:             row.Name,
-		Description:      row.Description,
-		ClientID:         row.ClientID,
-		ClientIdentifier: row.ClientIdentifier,
-		ClientScoped:     row.ClientScoped,
-		ConnectionID:     row.ConnectionID,
-		Endpoint:         row.Target,
-		Queue:            row.Queue,
-		Source:           ParseSource(row.Source),
-		Status:           ParseStatus(row.Status),
-		MaxAgeSeconds:    row.MaxAgeSeconds,
-		DispatchPoolID:   row.DispatchPoolID,
-		DispatchPoolCode: row.DispatchPoolCode,
-		DelaySeconds:     row.DelaySeconds,
-		Sequence:         row.Sequence,
-		Mode:             common.ParseDispatchMode(row.Mode),
-		TimeoutSeconds:   row.TimeoutSeconds,
-		MaxRetries:       row.MaxRetries,
-		ServiceAccountID: row.ServiceAccountID,
-		DataOnly:         row.DataOnly,
-		CreatedBy:        row.CreatedBy,
-		CreatedAt:        row.CreatedAt,
-		UpdatedAt:        row.UpdatedAt,
-		EventTypes:       []EventTypeBinding{},
-		CustomConfig:     []ConfigEntry{},
+		ID:                 row.ID,
+		Code:               row.Code,
+		ApplicationCode:    row.ApplicationCode,
+		Name:               row.Name,
+		Description:        row.Description,
+		ClientID:           row.ClientID,
+		ClientIdentifier:   row.ClientIdentifier,
+		ClientScoped:       row.ClientScoped,
+		ConnectionID:       row.ConnectionID,
+		Endpoint:           row.Target,
+		Queue:              row.Queue,
+		Source:             ParseSource(row.Source),
+		Status:             ParseStatus(row.Status),
+		MaxAgeSeconds:      row.MaxAgeSeconds,
+		DispatchPoolID:     row.DispatchPoolID,
+		DispatchPoolCode:   row.DispatchPoolCode,
+		DelaySeconds:       row.DelaySeconds,
+		Sequence:           row.Sequence,
+		Mode:               common.ParseDispatchMode(row.Mode),
+		Priority:           common.ParsePriority(row.Priority),
+		HonorRetryAfter:    row.HonorRetryAfter,
+		DeliveryFormat:     ParseDeliveryFormat(row.DeliveryFormat),
+		DeliveryWindow:     window,
+		AllowPrivateTarget: row.AllowPrivateTarget,
+		EgressProxy:        row.EgressProxy,
+		Weight:             row.Weight,
+		AckTimeoutSeconds:  row.AckTimeoutSeconds,
+		DeliveryHeaders:    parseDeliveryHeaders(row.DeliveryHeaders),
+		Environment:        common.ParseEnvironment(row.Environment),
+		Tap:                parseTap(row.TapSamplePercent, row.TapExpiresAt),
+		TimeoutSeconds:     row.TimeoutSeconds,
+		MaxRetries:         row.MaxRetries,
+		ServiceAccountID:   row.ServiceAccountID,
+		DataOnly:           row.DataOnly,
+		CreatedBy:          row.CreatedBy,
+		CreatedAt:          row.CreatedAt,
+		UpdatedAt:          row.UpdatedAt,
+		EventTypes:         []EventTypeBinding{},
+		CustomConfig:       []ConfigEntry{},
+	}
+}
+
+// parseTap reads the tap columns; nil unless both are set.
+func parseTap(percent *float64, expiresAt *time.Time) *Tap {
+	if percent == nil || expiresAt == nil {
+		return nil
+	}
+	return &Tap{SamplePercent: *percent, ExpiresAt: *expiresAt}
+}
+
+// deliveryHeaderStrings stores nil (the default set) as NULL.
+func deliveryHeaderStrings(hs []DeliveryHeader) []string {
+	if len(hs) == 0 {
+		return nil
+	}
+	out := make([]string, len(hs))
+	for i, h := range hs {
+		out[i] = string(h)
+	}
+	return out
+}
+
+// parseDeliveryHeaders drops names this build doesn't know; a column
+// that was NULL reads as nil.
+func parseDeliveryHeaders(raw []string) []DeliveryHeader {
+	if raw == nil {
+		return nil
+	}
+	out := make([]DeliveryHeader, 0, len(raw))
+	for _, v := range raw {
+		if h := DeliveryHeader(v); h.Valid() {
+			out = append(out, h)
+		}
 	}
+	return out
 }
@@ -0,0 +1,128 @@
+package subscription
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// TargetAuthType selects how the platform authenticates to a
+// subscription's target when delivering a dispatch job.
+type TargetAuthType string
+
+const (
+	// TargetAuthNone is only meaningful on update: it removes the stored
+	// target auth. Persisted subscriptions carry a nil TargetAuth instead.
+	TargetAuthNone   TargetAuthType = "NONE"
+	TargetAuthSigV4  TargetAuthType = "AWS_SIGV4"
+	TargetAuthOAuth2 TargetAuthType = "OAUTH2_CLIENT_CREDENTIALS"
+)
+
+// ParseTargetAuthType is the lenient parser. Unknown → NONE.
+func ParseTargetAuthType(s string) TargetAuthType {
+	switch TargetAuthType(strings.ToUpper(s)) {
+	case TargetAuthSigV4:
+		return TargetAuthSigV4
+	case TargetAuthOAuth2:
+		return TargetAuthOAuth2
+	default:
+		return TargetAuthNone
+	}
+}
+
+// SigV4Auth signs deliveries with AWS Signature V4. With RoleARN set the
+// platform assumes that role (per subscription, credentials cached until
+// near expiry); otherwise the platform's own AWS credentials sign, which
+// only an anchor principal may configure. A tenant naming a role must
+// also send ExternalID.
+type SigV4Auth struct {
+	Region     string  `json:"region"`
+	Service    string  `json:"service"`
+	RoleARN    *string `json:"roleArn,omitempty"`
+	ExternalID *string `json:"externalId,omitempty"`
+}
+
+// OAuth2ClientCredentials fetches a bearer from the partner's token
+// endpoint with the client-credentials grant. ClientSecret is encrypted at
+// rest (shared/encryption) and masked on the wire.
+type OAuth2ClientCredentials struct {
+	TokenURL     string   `json:"tokenUrl"`
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Audience     *string  `json:"audience,omitempty"`
+}
+
+// TargetAuth is a subscription's outbound authentication. Exactly one of
+// SigV4 / OAuth2 is set, matching Type. Stored in
+// msg_subscription_target_auth.
+type TargetAuth struct {
+	Type   TargetAuthType           `json:"type"`
+	SigV4  *SigV4Auth               `json:"sigv4,omitempty"`
+	OAuth2 *OAuth2ClientCredentials `json:"oauth2,omitempty"`
+}
+
+var (
+	awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+	roleARNPattern   = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/.+$`)
+)
+
+// Check validates the configuration shape. The OAuth2 secret is checked
+// for presence only; sealing (encrypt / keep stored) happens in the
+// operation.
+func (a *TargetAuth) Check() error {
+	switch a.Type {
+	case TargetAuthNone:
+		return nil
+	case TargetAuthSigV4:
+		s := a.SigV4
+		if s == nil || a.OAuth2 != nil {
+			return usecase.Validation("INVALID_TARGET_AUTH", "AWS_SIGV4 target auth needs sigv4 settings only")
+		}
+		if !awsRegionPattern.MatchString(s.Region) {
+			return usecase.Validation("INVALID_TARGET_AUTH", fmt.Sprintf("sigv4.region '%s' is not an AWS region", s.Region))
+		}
+		if strings.TrimSpace(s.Service) == "" {
+			return usecase.Validation("INVALID_TARGET_AUTH", "sigv4.service is required (e.g. execute-api, lambda)")
+		}
+		if s.RoleARN != nil && !roleARNPattern.MatchString(*s.RoleARN) {
+			return usecase.Validation("INVALID_TARGET_AUTH", "sigv4.roleArn must be an IAM role ARN")
+		}
+		if s.ExternalID != nil && s.RoleARN == nil {
+			return usecase.Validation("INVALID_TARGET_AUTH", "sigv4.externalId requires sigv4.roleArn")
+		}
+		return nil
+	case TargetAuthOAuth2:
+		o := a.OAuth2
+		if o == nil || a.SigV4 != nil {
+			return usecase.Validation("INVALID_TARGET_AUTH", "OAUTH2_CLIENT_CREDENTIALS target auth needs oauth2 settings only")
+		}
+		if u, err := url.Parse(o.TokenURL); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			return usecase.Validation("INVALID_TARGET_AUTH", "oauth2.tokenUrl must be a http(s) URL")
+		}
+		if strings.TrimSpace(o.ClientID) == "" {
+			return usecase.Validation("INVALID_TARGET_AUTH", "oauth2.clientId is required")
+		}
+		return nil
+	default:
+		return usecase.Validation("INVALID_TARGET_AUTH", fmt.Sprintf("unknown target auth type '%s'", a.Type))
+	}
+}
+
+// Masked returns a copy safe to put on the wire: the OAuth2 secret is
+// replaced by MaskedValue. Nil-safe.
+func (a *TargetAuth) Masked() *TargetAuth {
+	if a == nil {
+		return nil
+	}
+	out := *a
+	if a.OAuth2 != nil {
+		o := *a.OAuth2
+		o.ClientSecret = MaskedValue
+		out.OAuth2 = &o
+	}
+	return &out
+}
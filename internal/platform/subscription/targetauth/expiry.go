@@ -0,0 +1,57 @@
+package targetauth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+var expiresInDesc = prometheus.NewDesc("fc_subscription_tls_expires_in_seconds",
+	"Seconds until a subscription's client certificate or CA bundle expires (negative once expired). "+
+		"Only subscriptions inside the 30-day warning window are reported.",
+	[]string{"subscription_id", "code"}, nil)
+
+// ExpiryStore lists expiring TLS material. Satisfied by
+// *subscription.TargetTLSRepository.
+type ExpiryStore interface {
+	FindExpiringBefore(ctx context.Context, cutoff time.Time) ([]subscription.TargetTLSExpiry, error)
+}
+
+// ExpiryCollector exports subscriptions whose TLS material is expiring
+// or expired as Prometheus gauges, so an alert can fire before
+// deliveries start failing the handshake.
+type ExpiryCollector struct {
+	store ExpiryStore
+	now   func() time.Time
+}
+
+// NewExpiryCollector wires a collector over store.
+func NewExpiryCollector(store ExpiryStore) *ExpiryCollector {
+	return &ExpiryCollector{store: store, now: time.Now}
+}
+
+// Describe is a no-op (unchecked const-metric collector).
+func (c *ExpiryCollector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits one gauge per subscription inside the warning window.
+func (c *ExpiryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	now := c.now()
+	rows, err := c.store.FindExpiringBefore(ctx, now.Add(subscription.CertExpiryWarning))
+	if err != nil {
+		slog.Warn("targetauth: expiry collect failed", "err", err)
+		return
+	}
+	for _, r := range rows {
+		at := r.ExpiresAt()
+		if at == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(expiresInDesc, prometheus.GaugeValue, at.Sub(now).Seconds(), r.SubscriptionID, r.Code)
+	}
+}
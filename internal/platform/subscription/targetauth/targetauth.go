@@ -0,0 +1,326 @@
+// Package targetauth applies a subscription's outbound authentication
+// (subscription.TargetAuth) to dispatch deliveries:
+//
+//   - AWS_SIGV4 signs the request with SigV4. A configured role is assumed
+//     through STS per subscription, credentials cached until near expiry.
+//   - OAUTH2_CLIENT_CREDENTIALS attaches a bearer fetched from the partner's
+//     token endpoint, cached until it expires or the target rejects it.
+//
+// Config is re-read from the store every CacheTTL; the signer / token
+// cache survives a reload as long as the config itself is unchanged. At
+// most deliverysettings.CacheSize subscriptions are held, least recently
+// used evicted first.
+package targetauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverysettings"
+)
+
+// CacheTTL bounds how stale a subscription's auth config may be at
+// delivery time.
+const CacheTTL = time.Minute
+
+// tokenTimeout caps a single token-endpoint round trip.
+const tokenTimeout = 30 * time.Second
+
+// Store loads a subscription's target auth. Satisfied by
+// *subscription.Repository.
+type Store interface {
+	FindTargetAuth(ctx context.Context, subscriptionID string) (*subscription.TargetAuth, error)
+}
+
+// Error is an auth failure raised before the request reached the target.
+// Temporary reports whether retrying may help (token endpoint / STS
+// unreachable or 5xx) as opposed to a configuration or credential problem.
+type Error struct {
+	Transient bool
+	Err       error
+}
+
+func (e *Error) Error() string   { return e.Err.Error() }
+func (e *Error) Unwrap() error   { return e.Err }
+func (e *Error) Temporary() bool { return e.Transient }
+
+func configErr(format string, args ...any) error {
+	return &Error{Err: fmt.Errorf(format, args...)}
+}
+
+// Authenticator resolves and applies target auth. Safe for concurrent use.
+type Authenticator struct {
+	store  Store
+	client *http.Client
+
+	awsOnce sync.Once
+	awsCfg  aws.Config
+	awsErr  error
+
+	// mu guards the entries' fields; the cache itself is safe for
+	// concurrent use.
+	mu      sync.Mutex
+	entries *lru.Cache[string, *entry]
+}
+
+type entry struct {
+	loadedAt    time.Time
+	fingerprint string
+	applier     applier // nil: no target auth configured
+}
+
+type applier interface {
+	apply(ctx context.Context, req *http.Request, body []byte) error
+	// reset drops cached credentials after the target rejected them.
+	reset()
+}
+
+// New wires an Authenticator over store.
+func New(store Store) *Authenticator {
+	entries, _ := lru.New[string, *entry](deliverysettings.CacheSize)
+	return &Authenticator{
+		store:   store,
+		client:  &http.Client{Timeout: tokenTimeout},
+		entries: entries,
+	}
+}
+
+// SetEgress applies the egress policy to OAuth2 token requests: a token
+// URL gets the same checks, route and per-subscription settings as
+// the delivery it authorizes. Set once at startup.
+func (a *Authenticator) SetEgress(p *egress.Policy) { a.client.Transport = p.Transport(nil) }
+
+// Authorize applies the subscription's target auth to req. body is the
+// exact request body (SigV4 signs its hash). applied is false when the
+// subscription has no target auth.
+func (a *Authenticator) Authorize(ctx context.Context, subscriptionID string, req *http.Request, body []byte) (applied bool, err error) {
+	ap, err := a.applierFor(ctx, subscriptionID)
+	if err != nil || ap == nil {
+		return false, err
+	}
+	return true, ap.apply(ctx, req, body)
+}
+
+// Rejected tells the authenticator the target answered 401/403, so the
+// next delivery fetches fresh credentials instead of replaying a token the
+// partner has revoked.
+func (a *Authenticator) Rejected(subscriptionID string) {
+	e, _ := a.entries.Get(subscriptionID)
+	if e != nil && e.applier != nil {
+		e.applier.reset()
+	}
+}
+
+func (a *Authenticator) applierFor(ctx context.Context, subscriptionID string) (applier, error) {
+	e, _ := a.entries.Get(subscriptionID)
+	if e != nil {
+		a.mu.Lock()
+		fresh := time.Since(e.loadedAt) < CacheTTL
+		a.mu.Unlock()
+		if fresh {
+			return e.applier, nil
+		}
+	}
+
+	ta, err := a.store.FindTargetAuth(ctx, subscriptionID)
+	if err != nil {
+		return nil, &Error{Transient: true, Err: fmt.Errorf("load target auth: %w", err)}
+	}
+	fp := ""
+	if ta != nil {
+		raw, _ := json.Marshal(ta)
+		fp = string(raw)
+	}
+	if e != nil && e.fingerprint == fp {
+		a.mu.Lock()
+		e.loadedAt = time.Now()
+		a.mu.Unlock()
+		return e.applier, nil
+	}
+
+	var ap applier
+	if ta != nil {
+		if ap, err = a.build(ctx, subscriptionID, ta); err != nil {
+			return nil, err
+		}
+	}
+	a.entries.Add(subscriptionID, &entry{loadedAt: time.Now(), fingerprint: fp, applier: ap})
+	return ap, nil
+}
+
+func (a *Authenticator) build(ctx context.Context, subscriptionID string, ta *subscription.TargetAuth) (applier, error) {
+	switch ta.Type {
+	case subscription.TargetAuthSigV4:
+		if ta.SigV4 == nil {
+			return nil, configErr("AWS_SIGV4 target auth has no sigv4 settings")
+		}
+		return a.newSigV4(ctx, subscriptionID, *ta.SigV4)
+	case subscription.TargetAuthOAuth2:
+		if ta.OAuth2 == nil {
+			return nil, configErr("OAUTH2_CLIENT_CREDENTIALS target auth has no oauth2 settings")
+		}
+		return a.newOAuth2(*ta.OAuth2)
+	default:
+		return nil, configErr("unsupported target auth type %q", ta.Type)
+	}
+}
+
+// ── AWS SigV4 ────────────────────────────────────────────────────────────
+
+type sigV4 struct {
+	region, service string
+	creds           *aws.CredentialsCache
+	signer          *v4.Signer
+}
+
+func (a *Authenticator) baseAWSConfig(ctx context.Context) (aws.Config, error) {
+	a.awsOnce.Do(func() {
+		a.awsCfg, a.awsErr = awsconfig.LoadDefaultConfig(ctx)
+	})
+	return a.awsCfg, a.awsErr
+}
+
+func (a *Authenticator) newSigV4(ctx context.Context, subscriptionID string, cfg subscription.SigV4Auth) (applier, error) {
+	base, err := a.baseAWSConfig(ctx)
+	if err != nil {
+		return nil, configErr("aws config: %v", err)
+	}
+	provider := base.Credentials
+	if cfg.RoleARN != nil {
+		client := sts.NewFromConfig(base, func(o *sts.Options) { o.Region = cfg.Region })
+		provider = stscreds.NewAssumeRoleProvider(client, *cfg.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "flowcatalyst-" + subscriptionID
+			o.ExternalID = cfg.ExternalID
+		})
+	}
+	if provider == nil {
+		return nil, configErr("no AWS credentials available for SigV4 signing")
+	}
+	return &sigV4{
+		region:  cfg.Region,
+		service: cfg.Service,
+		creds:   aws.NewCredentialsCache(provider),
+		signer:  v4.NewSigner(),
+	}, nil
+}
+
+func (s *sigV4) apply(ctx context.Context, req *http.Request, body []byte) error {
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return &Error{Transient: !isClientFault(err), Err: fmt.Errorf("aws credentials: %w", err)}
+	}
+	sum := sha256.Sum256(body)
+	if err := s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), s.service, s.region, time.Now()); err != nil {
+		return configErr("sigv4 sign: %v", err)
+	}
+	return nil
+}
+
+func (s *sigV4) reset() { s.creds.Invalidate() }
+
+// isClientFault reports an AWS API error the caller caused (AccessDenied
+// on AssumeRole, bad external id, ...): retrying won't fix it.
+func isClientFault(err error) bool {
+	var ae smithy.APIError
+	return errors.As(err, &ae) && ae.ErrorFault() == smithy.FaultClient
+}
+
+// ── OAuth2 client credentials ────────────────────────────────────────────
+
+type oauth2CC struct {
+	cfg    clientcredentials.Config
+	client *http.Client
+
+	mu     sync.Mutex
+	src    oauth2.TokenSource
+	egress egress.Settings // the egress settings src was built with
+}
+
+func (a *Authenticator) newOAuth2(cfg subscription.OAuth2ClientCredentials) (applier, error) {
+	enc, err := encryption.FromEnv()
+	if err != nil {
+		return nil, configErr("encryption key: %v", err)
+	}
+	if enc == nil {
+		return nil, configErr("FLOWCATALYST_APP_KEY not configured; cannot decrypt OAuth2 client secret")
+	}
+	secret, err := enc.Decrypt(cfg.ClientSecret)
+	if err != nil {
+		return nil, configErr("decrypt OAuth2 client secret: %v", err)
+	}
+	cc := clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: secret,
+		TokenURL:     cfg.TokenURL,
+		Scopes:       cfg.Scopes,
+	}
+	if cfg.Audience != nil {
+		cc.EndpointParams = url.Values{"audience": {*cfg.Audience}}
+	}
+	return &oauth2CC{cfg: cc, client: a.client}, nil
+}
+
+func (o *oauth2CC) source(s egress.Settings) oauth2.TokenSource {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.src == nil || o.egress != s {
+		// The context only carries the HTTP client and the egress
+		// settings; it must outlive any one delivery because the source
+		// refreshes lazily.
+		ctx := s.Apply(context.WithValue(context.Background(), oauth2.HTTPClient, o.client))
+		o.src = oauth2.ReuseTokenSource(nil, o.cfg.TokenSource(ctx))
+		o.egress = s
+	}
+	return o.src
+}
+
+func (o *oauth2CC) apply(ctx context.Context, req *http.Request, _ []byte) error {
+	tok, err := o.source(egress.SettingsFrom(ctx)).Token()
+	if err != nil {
+		return &Error{Transient: !isTokenRejection(err) && !egress.IsBlocked(err), Err: fmt.Errorf("oauth2 token: %w", err)}
+	}
+	tok.SetAuthHeader(req)
+	return nil
+}
+
+func (o *oauth2CC) reset() {
+	o.mu.Lock()
+	o.src = nil
+	o.mu.Unlock()
+}
+
+// isTokenRejection reports a 4xx from the token endpoint (invalid_client,
+// unauthorized_client, invalid_scope): the credentials are wrong, not the
+// endpoint unavailable.
+func isTokenRejection(err error) bool {
+	var re *oauth2.RetrieveError
+	if !errors.As(err, &re) {
+		return false
+	}
+	if re.Response != nil {
+		return re.Response.StatusCode >= 400 && re.Response.StatusCode < 500
+	}
+	return strings.TrimSpace(re.ErrorCode) != ""
+}
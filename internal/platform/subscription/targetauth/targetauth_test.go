@@ -0,0 +1,140 @@
+package targetauth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/targetauth"
+)
+
+type mapStore map[string]*subscription.TargetAuth
+
+func (m mapStore) FindTargetAuth(_ context.Context, id string) (*subscription.TargetAuth, error) {
+	return m[id], nil
+}
+
+func setAppKey(t *testing.T) *encryption.Service {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	t.Setenv("FLOWCATALYST_APP_KEY", key)
+	enc, err := encryption.New(key)
+	require.NoError(t, err)
+	return enc
+}
+
+func newRequest(t *testing.T) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "https://partner.example/hook", strings.NewReader("{}"))
+	require.NoError(t, err)
+	return req
+}
+
+func tokenServer(t *testing.T, status *int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		user, pass, _ := r.BasicAuth()
+		if *status != http.StatusOK || user != "partner-client" || pass != "s3cret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(max(*status, http.StatusUnauthorized))
+			fmt.Fprint(w, `{"error":"invalid_client"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"tok-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func oauthStore(t *testing.T, tokenURL string) mapStore {
+	secret, err := setAppKey(t).Encrypt("s3cret")
+	require.NoError(t, err)
+	return mapStore{"sub_1": {
+		Type: subscription.TargetAuthOAuth2,
+		OAuth2: &subscription.OAuth2ClientCredentials{
+			TokenURL: tokenURL, ClientID: "partner-client", ClientSecret: secret,
+		},
+	}}
+}
+
+func TestOAuth2TokenIsCachedUntilRejected(t *testing.T) {
+	status := http.StatusOK
+	srv, calls := tokenServer(t, &status)
+	a := targetauth.New(oauthStore(t, srv.URL))
+	ctx := context.Background()
+
+	for range 2 {
+		req := newRequest(t)
+		applied, err := a.Authorize(ctx, "sub_1", req, []byte("{}"))
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, "Bearer tok-1", req.Header.Get("Authorization"))
+	}
+	assert.EqualValues(t, 1, calls.Load(), "second delivery reuses the cached token")
+
+	a.Rejected("sub_1")
+	req := newRequest(t)
+	_, err := a.Authorize(ctx, "sub_1", req, []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer tok-2", req.Header.Get("Authorization"))
+}
+
+func TestOAuth2ClassifiesTokenFailures(t *testing.T) {
+	status := http.StatusUnauthorized
+	srv, _ := tokenServer(t, &status)
+	a := targetauth.New(oauthStore(t, srv.URL))
+
+	_, err := a.Authorize(context.Background(), "sub_1", newRequest(t), nil)
+	var ae *targetauth.Error
+	require.True(t, errors.As(err, &ae))
+	assert.False(t, ae.Temporary(), "invalid_client is a credential problem, not transient")
+
+	status = http.StatusServiceUnavailable
+	a.Rejected("sub_1")
+	_, err = a.Authorize(context.Background(), "sub_1", newRequest(t), nil)
+	require.True(t, errors.As(err, &ae))
+	assert.True(t, ae.Temporary(), "a 5xx token endpoint is worth retrying")
+}
+
+func TestSigV4SignsWithPlatformCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	a := targetauth.New(mapStore{"sub_1": {
+		Type:  subscription.TargetAuthSigV4,
+		SigV4: &subscription.SigV4Auth{Region: "ap-southeast-2", Service: "execute-api"},
+	}})
+
+	req := newRequest(t)
+	applied, err := a.Authorize(context.Background(), "sub_1", req, []byte("{}"))
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), req.Header.Get("Authorization"))
+	assert.Contains(t, req.Header.Get("Authorization"), "/ap-southeast-2/execute-api/aws4_request")
+	assert.NotEmpty(t, req.Header.Get("X-Amz-Date"))
+}
+
+func TestNoTargetAuthIsNotApplied(t *testing.T) {
+	a := targetauth.New(mapStore{})
+	req := newRequest(t)
+	applied, err := a.Authorize(context.Background(), "sub_x", req, nil)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
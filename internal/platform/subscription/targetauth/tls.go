@@ -0,0 +1,170 @@
+package targetauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverysettings"
+)
+
+// TLSStore loads a subscription's TLS material. Satisfied by
+// *subscription.TargetTLSRepository.
+type TLSStore interface {
+	FindTargetTLS(ctx context.Context, subscriptionID string) (*subscription.TargetTLS, error)
+}
+
+// Transports hands out a per-subscription HTTP transport carrying the
+// subscription's client certificate and CA bundle. Like Authenticator it
+// re-reads the store every CacheTTL and keeps the transport (and its
+// pooled connections) while the stored material is unchanged. It holds
+// as many subscriptions as Authenticator; an evicted transport's idle
+// connections are closed. Safe for concurrent use.
+type Transports struct {
+	store  TLSStore
+	egress *egress.Policy // optional; set via SetEgress
+
+	mu      sync.Mutex // guards the entries' fields
+	entries *lru.Cache[string, *tlsEntry]
+}
+
+type tlsEntry struct {
+	loadedAt  time.Time
+	updatedAt time.Time // zero: no TLS material configured
+	transport http.RoundTripper
+}
+
+// NewTransports wires Transports over store.
+func NewTransports(store TLSStore) *Transports {
+	entries, _ := lru.NewWithEvict(deliverysettings.CacheSize, func(_ string, e *tlsEntry) {
+		if c, ok := e.closer(); ok {
+			c.CloseIdleConnections()
+		}
+	})
+	return &Transports{store: store, entries: entries}
+}
+
+// SetEgress wraps every transport in the egress policy, so mTLS
+// deliveries are checked like the rest. Set once at startup.
+func (t *Transports) SetEgress(p *egress.Policy) { t.egress = p }
+
+// Transport returns the subscription's transport, or nil when it has no
+// TLS material and the caller's default transport applies.
+func (t *Transports) Transport(ctx context.Context, subscriptionID string) (http.RoundTripper, error) {
+	e, _ := t.entries.Get(subscriptionID)
+	if e != nil {
+		t.mu.Lock()
+		fresh := time.Since(e.loadedAt) < CacheTTL
+		t.mu.Unlock()
+		if fresh {
+			return e.transport, nil
+		}
+	}
+
+	st, err := t.store.FindTargetTLS(ctx, subscriptionID)
+	if err != nil {
+		return nil, &Error{Transient: true, Err: fmt.Errorf("load target tls: %w", err)}
+	}
+	var updatedAt time.Time
+	if st != nil {
+		updatedAt = st.UpdatedAt
+	}
+	if e != nil && e.updatedAt.Equal(updatedAt) {
+		t.mu.Lock()
+		e.loadedAt = time.Now()
+		t.mu.Unlock()
+		return e.transport, nil
+	}
+
+	var rt http.RoundTripper
+	if st != nil {
+		cfg, err := tlsConfig(st)
+		if err != nil {
+			return nil, err
+		}
+		warnExpiry(st)
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = cfg
+		rt = tr
+		if t.egress != nil {
+			rt = t.egress.Transport(tr)
+		}
+	}
+	// Replacing an entry doesn't run the eviction callback.
+	t.entries.Add(subscriptionID, &tlsEntry{loadedAt: time.Now(), updatedAt: updatedAt, transport: rt})
+	if c, ok := e.closer(); ok {
+		c.CloseIdleConnections()
+	}
+	return rt, nil
+}
+
+// closer is the replaced transport's idle-connection closer, if any.
+func (e *tlsEntry) closer() (interface{ CloseIdleConnections() }, bool) {
+	if e == nil || e.transport == nil {
+		return nil, false
+	}
+	c, ok := e.transport.(interface{ CloseIdleConnections() })
+	return c, ok
+}
+
+// tlsConfig decrypts the client key and assembles the client TLS config.
+// The CA bundle extends the system roots rather than replacing them.
+func tlsConfig(st *subscription.TargetTLS) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if st.ClientCertPEM != nil && st.ClientKey != nil {
+		enc, err := encryption.FromEnv()
+		if err != nil {
+			return nil, configErr("encryption key: %v", err)
+		}
+		if enc == nil {
+			return nil, configErr("FLOWCATALYST_APP_KEY not configured; cannot decrypt client key")
+		}
+		key, err := enc.Decrypt(*st.ClientKey)
+		if err != nil {
+			return nil, configErr("decrypt client key: %v", err)
+		}
+		cert, err := tls.X509KeyPair([]byte(*st.ClientCertPEM), []byte(key))
+		if err != nil {
+			return nil, configErr("client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if st.CABundlePEM != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(*st.CABundlePEM)) {
+			return nil, configErr("CA bundle has no PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// warnExpiry logs when a subscription's certificate material is expired
+// or close to it. Called when the transport is built — on first use and
+// after each upload — so the log stays quiet; ExpiryCollector carries the
+// ongoing signal.
+func warnExpiry(st *subscription.TargetTLS) {
+	at := st.ExpiresAt()
+	if at == nil {
+		return
+	}
+	switch subscription.ExpiryState(*at, time.Now(), subscription.CertExpiryWarning) {
+	case subscription.CertExpired:
+		slog.Warn("subscription TLS certificate has expired", "subscription_id", st.SubscriptionID, "expired_at", *at)
+	case subscription.CertExpiring:
+		slog.Warn("subscription TLS certificate expires soon", "subscription_id", st.SubscriptionID, "expires_at", *at)
+	}
+}
@@ -0,0 +1,120 @@
+package targetauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/targetauth"
+)
+
+type tlsStore map[string]*subscription.TargetTLS
+
+func (m tlsStore) FindTargetTLS(_ context.Context, id string) (*subscription.TargetTLS, error) {
+	return m[id], nil
+}
+
+// clientCert mints a client CA and a leaf it signed, returning the CA pool
+// for the server and the leaf's PEM certificate and key.
+func clientCert(t *testing.T) (*x509.CertPool, string, string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "partner client CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "flowcatalyst"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return pool,
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestTransports_MutualTLS(t *testing.T) {
+	enc := setAppKey(t)
+	clientCAs, certPEM, keyPEM := clientCert(t)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Client", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	st, err := subscription.ParseTargetTLS("sub_mtls", certPEM, keyPEM, serverCA, time.Now())
+	require.NoError(t, err)
+	sealed, err := enc.Encrypt(*st.ClientKey)
+	require.NoError(t, err)
+	st.ClientKey = &sealed
+
+	tr := targetauth.NewTransports(tlsStore{"sub_mtls": st})
+	rt, err := tr.Transport(context.Background(), "sub_mtls")
+	require.NoError(t, err)
+	require.NotNil(t, rt)
+	resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "flowcatalyst", resp.Header.Get("X-Client"))
+
+	again, err := tr.Transport(context.Background(), "sub_mtls")
+	require.NoError(t, err)
+	assert.Same(t, rt, again, "the transport and its connections are reused")
+
+	rt, err = tr.Transport(context.Background(), "sub_plain")
+	require.NoError(t, err)
+	assert.Nil(t, rt, "no TLS material keeps the default transport")
+}
+
+func TestTransports_UndecryptableKeyIsConfigError(t *testing.T) {
+	setAppKey(t)
+	_, certPEM, keyPEM := clientCert(t)
+	st, err := subscription.ParseTargetTLS("sub_bad", certPEM, keyPEM, "", time.Now())
+	require.NoError(t, err)
+	plaintext := keyPEM
+	st.ClientKey = &plaintext // never encrypted
+
+	_, err = targetauth.NewTransports(tlsStore{"sub_bad": st}).Transport(context.Background(), "sub_bad")
+	var ae *targetauth.Error
+	require.True(t, errors.As(err, &ae), "got %v", err)
+	assert.False(t, ae.Temporary())
+}
@@ -0,0 +1,48 @@
+package subscription_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+func TestTargetAuthCheck(t *testing.T) {
+	good := []subscription.TargetAuth{
+		{Type: subscription.TargetAuthNone},
+		{Type: subscription.TargetAuthSigV4, SigV4: &subscription.SigV4Auth{
+			Region: "eu-west-1", Service: "lambda", RoleARN: ptr("arn:aws:iam::123456789012:role/fc-delivery"), ExternalID: ptr("x"),
+		}},
+		{Type: subscription.TargetAuthOAuth2, OAuth2: &subscription.OAuth2ClientCredentials{
+			TokenURL: "https://auth.partner.example/oauth/token", ClientID: "fc",
+		}},
+	}
+	for _, a := range good {
+		assert.NoError(t, a.Check(), a.Type)
+	}
+
+	bad := map[string]subscription.TargetAuth{
+		"unknown type":      {Type: "BASIC"},
+		"sigv4 missing":     {Type: subscription.TargetAuthSigV4},
+		"bad region":        {Type: subscription.TargetAuthSigV4, SigV4: &subscription.SigV4Auth{Region: "sydney", Service: "lambda"}},
+		"bad role":          {Type: subscription.TargetAuthSigV4, SigV4: &subscription.SigV4Auth{Region: "eu-west-1", Service: "lambda", RoleARN: ptr("fc-delivery")}},
+		"external id alone": {Type: subscription.TargetAuthSigV4, SigV4: &subscription.SigV4Auth{Region: "eu-west-1", Service: "lambda", ExternalID: ptr("x")}},
+		"oauth2 bad url":    {Type: subscription.TargetAuthOAuth2, OAuth2: &subscription.OAuth2ClientCredentials{TokenURL: "partner", ClientID: "fc"}},
+		"both set": {Type: subscription.TargetAuthOAuth2,
+			OAuth2: &subscription.OAuth2ClientCredentials{TokenURL: "https://a.example/t", ClientID: "fc"},
+			SigV4:  &subscription.SigV4Auth{Region: "eu-west-1", Service: "lambda"}},
+	}
+	for name, a := range bad {
+		assert.Error(t, a.Check(), name)
+	}
+}
+
+func TestTargetAuthMasked(t *testing.T) {
+	a := &subscription.TargetAuth{Type: subscription.TargetAuthOAuth2, OAuth2: &subscription.OAuth2ClientCredentials{ClientSecret: "ciphertext"}}
+	assert.Equal(t, subscription.MaskedValue, a.Masked().OAuth2.ClientSecret)
+	assert.Equal(t, "ciphertext", a.OAuth2.ClientSecret, "Masked must not mutate its receiver")
+
+	var none *subscription.TargetAuth
+	assert.Nil(t, none.Masked())
+}
@@ -0,0 +1,148 @@
+package subscription
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+)
+
+// CertExpiryWarning is how far ahead of expiry a target certificate is
+// reported as EXPIRING.
+const CertExpiryWarning = 30 * 24 * time.Hour
+
+// CertExpiryState classifies a target certificate against the clock.
+type CertExpiryState string
+
+const (
+	CertValid    CertExpiryState = "VALID"
+	CertExpiring CertExpiryState = "EXPIRING"
+	CertExpired  CertExpiryState = "EXPIRED"
+)
+
+// TargetTLS is a subscription's TLS material for deliveries: a client
+// certificate presented to targets that require mutual TLS, and/or a CA
+// bundle trusted (alongside the system roots) for the target's server
+// certificate. ClientKey is the PEM private key encrypted with
+// FLOWCATALYST_APP_KEY (shared/encryption); it never leaves the platform.
+// Stored in msg_subscription_target_tls, one row per subscription.
+type TargetTLS struct {
+	SubscriptionID string
+	ClientCertPEM  *string
+	ClientKey      *string
+	CABundlePEM    *string
+	// Parsed at upload so expiry can be reported without the key.
+	CertSubject  *string
+	CertNotAfter *time.Time
+	CANotAfter   *time.Time // earliest expiry in the bundle
+	UpdatedAt    time.Time
+}
+
+// IDStr implements usecase.HasID.
+func (t TargetTLS) IDStr() string { return t.SubscriptionID }
+
+// ParseTargetTLS validates uploaded PEM material and returns the TLS
+// settings for subscriptionID with the key still in plaintext — the
+// operation encrypts it before persisting. certPEM and keyPEM go
+// together; at least one of the pair or caPEM is required.
+func ParseTargetTLS(subscriptionID, certPEM, keyPEM, caPEM string, now time.Time) (*TargetTLS, error) {
+	certPEM, keyPEM, caPEM = strings.TrimSpace(certPEM), strings.TrimSpace(keyPEM), strings.TrimSpace(caPEM)
+	if certPEM == "" && keyPEM == "" && caPEM == "" {
+		return nil, usecase.Validation("INVALID_TARGET_TLS", "a client certificate and key, a CA bundle, or both are required")
+	}
+	if (certPEM == "") != (keyPEM == "") {
+		return nil, usecase.Validation("INVALID_TARGET_TLS", "clientCertificate and clientKey must be uploaded together")
+	}
+	out := &TargetTLS{SubscriptionID: subscriptionID, UpdatedAt: now}
+	if certPEM != "" {
+		pair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, usecase.Validation("INVALID_TARGET_TLS", "client certificate: "+err.Error())
+		}
+		leaf, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return nil, usecase.Validation("INVALID_TARGET_TLS", "client certificate: "+err.Error())
+		}
+		if !leaf.NotAfter.After(now) {
+			return nil, usecase.Validation("TARGET_CERT_EXPIRED", "client certificate expired on "+leaf.NotAfter.UTC().Format(time.RFC3339))
+		}
+		subject, notAfter := leaf.Subject.String(), leaf.NotAfter.UTC()
+		out.ClientCertPEM, out.ClientKey = &certPEM, &keyPEM
+		out.CertSubject, out.CertNotAfter = &subject, &notAfter
+	}
+	if caPEM != "" {
+		certs, err := parseCertificates(caPEM)
+		if err != nil {
+			return nil, usecase.Validation("INVALID_TARGET_TLS", "CA bundle: "+err.Error())
+		}
+		earliest := certs[0].NotAfter
+		for _, c := range certs[1:] {
+			if c.NotAfter.Before(earliest) {
+				earliest = c.NotAfter
+			}
+		}
+		earliest = earliest.UTC()
+		out.CABundlePEM, out.CANotAfter = &caPEM, &earliest
+	}
+	return out, nil
+}
+
+// parseCertificates decodes every CERTIFICATE block in a PEM bundle.
+func parseCertificates(bundle string) ([]*x509.Certificate, error) {
+	var out []*x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		return nil, errNoCertificates
+	}
+	return out, nil
+}
+
+var errNoCertificates = errors.New("no PEM certificates found")
+
+// ExpiresAt is the earlier of the client certificate's and the CA
+// bundle's expiry. Nil-safe.
+func (t *TargetTLS) ExpiresAt() *time.Time {
+	if t == nil {
+		return nil
+	}
+	switch {
+	case t.CertNotAfter == nil:
+		return t.CANotAfter
+	case t.CANotAfter == nil || t.CertNotAfter.Before(*t.CANotAfter):
+		return t.CertNotAfter
+	default:
+		return t.CANotAfter
+	}
+}
+
+// ExpiryState classifies notAfter: EXPIRED once past, EXPIRING within
+// warnWithin of now, otherwise VALID.
+func ExpiryState(notAfter, now time.Time, warnWithin time.Duration) CertExpiryState {
+	switch {
+	case !notAfter.After(now):
+		return CertExpired
+	case notAfter.Sub(now) <= warnWithin:
+		return CertExpiring
+	default:
+		return CertValid
+	}
+}
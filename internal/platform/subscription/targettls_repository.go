@@ -0,0 +1,111 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// TargetTLSRepository is the Postgres-backed repo for
+// msg_subscription_target_tls.
+type TargetTLSRepository struct{ pool *pgxpool.Pool }
+
+// NewTargetTLSRepository wires a repo.
+func NewTargetTLSRepository(pool *pgxpool.Pool) *TargetTLSRepository {
+	return &TargetTLSRepository{pool: pool}
+}
+
+const targetTLSCols = `subscription_id, client_cert_pem, client_key_enc, ca_bundle_pem,
+	cert_subject, cert_not_after, ca_not_after, updated_at`
+
+func scanTargetTLS(row pgx.Row, extra ...any) (*TargetTLS, error) {
+	var t TargetTLS
+	dest := append([]any{&t.SubscriptionID, &t.ClientCertPEM, &t.ClientKey, &t.CABundlePEM,
+		&t.CertSubject, &t.CertNotAfter, &t.CANotAfter, &t.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindTargetTLS loads one subscription's TLS material. Nil when none is
+// configured.
+func (r *TargetTLSRepository) FindTargetTLS(ctx context.Context, subscriptionID string) (*TargetTLS, error) {
+	t, err := scanTargetTLS(r.pool.QueryRow(ctx,
+		`SELECT `+targetTLSCols+` FROM msg_subscription_target_tls WHERE subscription_id = $1`, subscriptionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("subscription_target_tls repo: %w", err)
+	}
+	return t, nil
+}
+
+// TargetTLSExpiry is one row of the expiry report: the TLS settings plus
+// the owning subscription's code and client for scoping.
+type TargetTLSExpiry struct {
+	TargetTLS
+	Code     string
+	ClientID *string
+}
+
+// FindExpiringBefore returns every subscription whose client certificate
+// or CA bundle expires before cutoff, soonest first.
+func (r *TargetTLSRepository) FindExpiringBefore(ctx context.Context, cutoff time.Time) ([]TargetTLSExpiry, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT t.subscription_id, t.client_cert_pem, t.client_key_enc, t.ca_bundle_pem,
+		        t.cert_subject, t.cert_not_after, t.ca_not_after, t.updated_at, s.code, s.client_id
+		   FROM msg_subscription_target_tls t
+		   JOIN msg_subscriptions s ON s.id = t.subscription_id
+		  WHERE LEAST(t.cert_not_after, t.ca_not_after) < $1
+		  ORDER BY LEAST(t.cert_not_after, t.ca_not_after)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("subscription_target_tls repo: %w", err)
+	}
+	defer rows.Close()
+	var out []TargetTLSExpiry
+	for rows.Next() {
+		var e TargetTLSExpiry
+		t, err := scanTargetTLS(rows, &e.Code, &e.ClientID)
+		if err != nil {
+			return nil, fmt.Errorf("subscription_target_tls repo: %w", err)
+		}
+		e.TargetTLS = *t
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Persist implements usecasepgx.Persist[TargetTLS].
+func (r *TargetTLSRepository) Persist(ctx context.Context, t *TargetTLS, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx,
+		`INSERT INTO msg_subscription_target_tls (`+targetTLSCols+`)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (subscription_id) DO UPDATE SET
+		     client_cert_pem = EXCLUDED.client_cert_pem,
+		     client_key_enc = EXCLUDED.client_key_enc,
+		     ca_bundle_pem = EXCLUDED.ca_bundle_pem,
+		     cert_subject = EXCLUDED.cert_subject,
+		     cert_not_after = EXCLUDED.cert_not_after,
+		     ca_not_after = EXCLUDED.ca_not_after,
+		     updated_at = EXCLUDED.updated_at`,
+		t.SubscriptionID, t.ClientCertPEM, t.ClientKey, t.CABundlePEM,
+		t.CertSubject, t.CertNotAfter, t.CANotAfter, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("subscription_target_tls persist: %w", err)
+	}
+	return nil
+}
+
+// Delete implements usecasepgx.Delete[TargetTLS].
+func (r *TargetTLSRepository) Delete(ctx context.Context, t *TargetTLS, tx *usecasepgx.DbTx) error {
+	_, err := tx.Inner().Exec(ctx, `DELETE FROM msg_subscription_target_tls WHERE subscription_id = $1`, t.SubscriptionID)
+	return err
+}
@@ -0,0 +1,78 @@
+package subscription_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+)
+
+// selfSigned returns a PEM certificate and key valid until notAfter.
+func selfSigned(t *testing.T, cn string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestParseTargetTLS(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM := selfSigned(t, "fc-client", now.Add(90*24*time.Hour))
+	caSoon, _ := selfSigned(t, "ca-soon", now.Add(10*24*time.Hour))
+	caLate, _ := selfSigned(t, "ca-late", now.Add(400*24*time.Hour))
+
+	got, err := subscription.ParseTargetTLS("sub_1", certPEM, keyPEM, caLate+caSoon, now)
+	require.NoError(t, err)
+	assert.Equal(t, "CN=fc-client", *got.CertSubject)
+	assert.WithinDuration(t, now.Add(90*24*time.Hour), *got.CertNotAfter, time.Second)
+	assert.WithinDuration(t, now.Add(10*24*time.Hour), *got.CANotAfter, time.Second, "the earliest CA expiry is tracked")
+	assert.Equal(t, got.CANotAfter, got.ExpiresAt())
+	assert.Equal(t, subscription.CertExpiring, subscription.ExpiryState(*got.ExpiresAt(), now, subscription.CertExpiryWarning))
+
+	caOnly, err := subscription.ParseTargetTLS("sub_1", "", "", caLate, now)
+	require.NoError(t, err)
+	assert.Nil(t, caOnly.ClientKey)
+	assert.Equal(t, subscription.CertValid, subscription.ExpiryState(*caOnly.ExpiresAt(), now, subscription.CertExpiryWarning))
+
+	_, otherKey := selfSigned(t, "other", now.Add(time.Hour))
+	expiredCert, expiredKey := selfSigned(t, "old", now.Add(-time.Hour))
+	bad := map[string][3]string{
+		"nothing":          {"", "", ""},
+		"cert without key": {certPEM, "", ""},
+		"mismatched key":   {certPEM, otherKey, ""},
+		"expired cert":     {expiredCert, expiredKey, ""},
+		"garbage CA":       {"", "", "not pem"},
+	}
+	for name, in := range bad {
+		_, err := subscription.ParseTargetTLS("sub_1", in[0], in[1], in[2], now)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestExpiryState(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, subscription.CertExpired, subscription.ExpiryState(now, now, time.Hour))
+	assert.Equal(t, subscription.CertExpiring, subscription.ExpiryState(now.Add(time.Minute), now, time.Hour))
+	assert.Equal(t, subscription.CertValid, subscription.ExpiryState(now.Add(2*time.Hour), now, time.Hour))
+}
@@ -0,0 +1,33 @@
+package subscription
+
+import (
+	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
+)
+
+// PayloadTransform maps the platform event envelope to a receiver-specific
+// body at delivery time. Stored in msg_subscription_transforms; see
+// internal/payloadtransform for the template language.
+type PayloadTransform struct {
+	Engine      payloadtransform.Engine `json:"engine"`
+	Template    string                  `json:"template"`
+	ContentType string                  `json:"contentType"`
+}
+
+// NewPayloadTransform fills the engine and content-type defaults.
+func NewPayloadTransform(template, contentType string) *PayloadTransform {
+	if strings.TrimSpace(contentType) == "" {
+		contentType = payloadtransform.DefaultContentType
+	}
+	return &PayloadTransform{
+		Engine:      payloadtransform.EngineGoTemplate,
+		Template:    template,
+		ContentType: strings.TrimSpace(contentType),
+	}
+}
+
+// Compile parses the template.
+func (t *PayloadTransform) Compile() (*payloadtransform.Program, error) {
+	return payloadtransform.Compile(t.Engine, t.Template, t.ContentType)
+}
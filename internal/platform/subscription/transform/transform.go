@@ -0,0 +1,61 @@
+// Package transform renders a subscription's payload transform
+// (subscription.PayloadTransform) at delivery time. Compiled programs come
+// from the delivery settings cache (deliverysettings.Cache), so a changed
+// template is picked up within its TTL.
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/payloadtransform"
+)
+
+// Programs resolves a subscription's compiled transform; nil when it has
+// none. A failure that is Temporary (the store was unreachable) is worth
+// retrying. Satisfied by *deliverysettings.Cache.
+type Programs interface {
+	Program(ctx context.Context, subscriptionID string) (*payloadtransform.Program, error)
+}
+
+// Error is a transform failure. Temporary reports whether retrying may help
+// (the store was unreachable) as opposed to a template that can't render
+// this event.
+type Error struct {
+	Transient bool
+	Err       error
+}
+
+func (e *Error) Error() string   { return e.Err.Error() }
+func (e *Error) Unwrap() error   { return e.Err }
+func (e *Error) Temporary() bool { return e.Transient }
+
+// Transformer applies transforms. Safe for concurrent use.
+type Transformer struct {
+	programs Programs
+}
+
+// New wires a Transformer over programs.
+func New(programs Programs) *Transformer {
+	return &Transformer{programs: programs}
+}
+
+// Transform renders envelope through the subscription's transform. applied
+// is false when the subscription has none, in which case the caller sends
+// its default body.
+func (t *Transformer) Transform(ctx context.Context, subscriptionID string, envelope map[string]any) (body []byte, contentType string, applied bool, err error) {
+	p, err := t.programs.Program(ctx, subscriptionID)
+	if err != nil {
+		var temp interface{ Temporary() bool }
+		return nil, "", false, &Error{Transient: errors.As(err, &temp) && temp.Temporary(), Err: err}
+	}
+	if p == nil {
+		return nil, "", false, nil
+	}
+	out, err := p.Render(envelope)
+	if err != nil {
+		return nil, "", false, &Error{Err: fmt.Errorf("render transform: %w", err)}
+	}
+	return out, p.ContentType(), true, nil
+}
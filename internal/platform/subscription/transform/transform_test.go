@@ -0,0 +1,72 @@
+package transform_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverysettings"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/transform"
+)
+
+type countingStore struct {
+	transforms map[string]*subscription.PayloadTransform
+	err        error
+	calls      atomic.Int32
+}
+
+func (s *countingStore) FindDeliverySettings(_ context.Context, id string) (subscription.DeliverySettings, error) {
+	s.calls.Add(1)
+	return subscription.DeliverySettings{Transform: s.transforms[id]}, s.err
+}
+
+func TestTransformRendersAndCaches(t *testing.T) {
+	store := &countingStore{transforms: map[string]*subscription.PayloadTransform{
+		"sub_1": subscription.NewPayloadTransform(`{"order":{{ json .data.id }},"kind":{{ json .type }}}`, ""),
+	}}
+	tr := transform.New(deliverysettings.New(store))
+	env := map[string]any{"type": "orders:order:created", "data": map[string]any{"id": "o-1"}}
+
+	for range 2 {
+		body, ct, applied, err := tr.Transform(context.Background(), "sub_1", env)
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, "application/json", ct)
+		assert.JSONEq(t, `{"order":"o-1","kind":"orders:order:created"}`, string(body))
+	}
+	assert.EqualValues(t, 1, store.calls.Load(), "second render uses the cached program")
+}
+
+func TestNoTransformIsNotApplied(t *testing.T) {
+	tr := transform.New(deliverysettings.New(&countingStore{}))
+	_, _, applied, err := tr.Transform(context.Background(), "sub_x", map[string]any{})
+	require.NoError(t, err)
+	assert.False(t, applied)
+}
+
+func TestTransformClassifiesFailures(t *testing.T) {
+	store := &countingStore{transforms: map[string]*subscription.PayloadTransform{
+		"sub_1": subscription.NewPayloadTransform(`{"total": {{ .data.amount }}`, ""),
+	}}
+	tr := transform.New(deliverysettings.New(store))
+
+	_, _, _, err := tr.Transform(context.Background(), "sub_1", map[string]any{"data": map[string]any{"amount": 5}})
+	var te *transform.Error
+	require.True(t, errors.As(err, &te))
+	assert.False(t, te.Temporary(), "invalid output is a template problem")
+
+	store.transforms["sub_2"] = &subscription.PayloadTransform{Template: `{{ .data.id `}
+	_, _, _, err = tr.Transform(context.Background(), "sub_2", map[string]any{})
+	require.True(t, errors.As(err, &te))
+	assert.False(t, te.Temporary(), "a template that doesn't compile won't on retry")
+
+	down := transform.New(deliverysettings.New(&countingStore{err: errors.New("connection refused")}))
+	_, _, _, err = down.Transform(context.Background(), "sub_1", map[string]any{})
+	require.True(t, errors.As(err, &te))
+	assert.True(t, te.Temporary(), "a store outage is worth retrying")
+}
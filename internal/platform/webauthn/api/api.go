@@ -33,6 +33,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httperror"
 	platformmw "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/middleware"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn/operations"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
@@ -190,7 +191,7 @@ func (s *State) registerComplete(ctx context.Context, in *registerCompleteInput)
 		return nil, httperror.BadRequest("ATTESTATION_INVALID", err.Error())
 	}
 
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	event, err := usecaseop.Run(ctx, s.UoW, operations.Register(s.Creds),
 		operations.RegisterCommand{StateID: in.Body.StateID, Response: *cred, Name: &name}, ec)
 	if err != nil {
@@ -336,10 +337,8 @@ func (s *State) authenticateComplete(ctx context.Context, in *authenticateComple
 		return nil, invalidCredentialsErr()
 	}
 
-	ec := usecase.NewExecutionContext(p.ID)
 	// Counter persistence failure is non-fatal; session still issued.
-	_, _ = usecaseop.Run(ctx, s.UoW, operations.Authenticate(s.Creds),
-		operations.AuthenticateCommand{StateID: in.Body.StateID, UpdatedCredential: *cred}, ec)
+	recordAssertion(ctx, s.UoW, s.Creds, p.ID, in.Body.StateID, cred)
 
 	var email *string
 	if p.UserIdentity != nil && p.UserIdentity.Email != "" {
@@ -442,7 +441,7 @@ func (s *State) deleteCredential(ctx context.Context, in *deleteCredInput) (*emp
 	if !found {
 		return nil, httperror.NotFound("Credential", in.ID)
 	}
-	ec := usecase.NewExecutionContext(ac.PrincipalID)
+	ec := reqctx.ExecutionContext(ctx)
 	if _, err := usecaseop.Run(ctx, s.UoW, operations.Revoke(s.Creds),
 		operations.RevokeCommand{ID: in.ID}, ec); err != nil {
 		return nil, err
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	gowebauthn "github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecase"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecaseop"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
+)
+
+// SecondFactor lets a registered passkey answer the password login's 2FA
+// challenge (it satisfies login.PasskeyFactor). The ceremony is the same
+// assertion authenticate/begin+complete runs, but the principal comes from
+// the pending MFA token instead of a typed email, and success completes the
+// password login rather than minting a session here.
+type SecondFactor struct {
+	Service *webauthn.Service
+	Creds   *webauthn.Repository
+	UoW     *usecasepgx.UnitOfWork
+}
+
+// HasPasskey reports whether the principal has at least one passkey.
+func (f *SecondFactor) HasPasskey(ctx context.Context, principalID string) (bool, error) {
+	rows, err := f.Service.Credentials().FindByPrincipal(ctx, principalID)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// BeginAssertion issues an assertion challenge over p's passkeys and returns
+// the ceremony state id with the browser options.
+func (f *SecondFactor) BeginAssertion(ctx context.Context, p *principal.Principal) (string, any, error) {
+	creds, err := f.Service.Credentials().LibraryCredentialsByPrincipal(ctx, p.ID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(creds) == 0 {
+		return "", nil, usecase.Validation("NO_PASSKEY", "no passkey is registered for this account")
+	}
+	user := &webauthn.PrincipalUser{PrincipalID: p.ID, DisplayName: p.Name, Credentials: creds}
+	options, sessionData, err := f.Service.WebAuthn().BeginLogin(user)
+	if err != nil {
+		return "", nil, err
+	}
+	stateID := newUUID()
+	if err := f.Service.Ceremonies().StoreAuthentication(ctx, stateID, &p.ID, sessionData); err != nil {
+		return "", nil, err
+	}
+	return stateID, options, nil
+}
+
+// FinishAssertion verifies the browser's assertion for the ceremony begun by
+// BeginAssertion. A missing/expired state, a ceremony begun for another
+// principal, or a bad signature all report false; only storage failures are
+// errors.
+func (f *SecondFactor) FinishAssertion(ctx context.Context, p *principal.Principal, stateID string, credential json.RawMessage) (bool, error) {
+	consumed, err := f.Service.Ceremonies().ConsumeAuthentication(ctx, stateID)
+	if err != nil {
+		return false, err
+	}
+	if consumed == nil || consumed.PrincipalID == nil || *consumed.PrincipalID != p.ID {
+		return false, nil
+	}
+	creds, err := f.Service.Credentials().LibraryCredentialsByPrincipal(ctx, p.ID)
+	if err != nil {
+		return false, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBody(io.NopCloser(bytes.NewReader(credential)))
+	if err != nil {
+		return false, nil
+	}
+	user := &webauthn.PrincipalUser{PrincipalID: p.ID, DisplayName: p.Name, Credentials: creds}
+	cred, err := f.Service.WebAuthn().ValidateLogin(user, consumed.Session, parsed)
+	if err != nil {
+		return false, nil
+	}
+	recordAssertion(ctx, f.UoW, f.Creds, p.ID, stateID, cred)
+	return true, nil
+}
+
+// recordAssertion persists the verified credential's sign counter and
+// last-used time. Best-effort: a failure here never fails the sign-in.
+func recordAssertion(ctx context.Context, uow *usecasepgx.UnitOfWork, creds *webauthn.Repository, principalID, stateID string, cred *gowebauthn.Credential) {
+	stored, err := creds.FindByCredentialID(ctx, cred.ID)
+	if err != nil || stored == nil {
+		return
+	}
+	ec := usecase.NewExecutionContext(principalID)
+	_, _ = usecaseop.Run(ctx, uow, operations.Authenticate(creds),
+		operations.AuthenticateCommand{StateID: stateID, UpdatedCredential: *cred, PersistedCredentialID: stored.ID}, ec)
+}
@@ -0,0 +1,168 @@
+package queue
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// Content encodings a publisher may apply to a message body. The encoding
+// travels beside the body (an SQS message attribute, a NATS header) so
+// consumers decompress transparently and uncompressed messages from older
+// publishers keep working.
+const (
+	EncodingGzip = "gzip"
+	EncodingZstd = "zstd"
+)
+
+// ContentEncodingAttr names the message attribute / header carrying the
+// body's content encoding. Absent means plain JSON.
+const ContentEncodingAttr = "contentEncoding"
+
+// maxDecodedBody caps a decompressed body: a message pointer is a few
+// hundred bytes, so anything near this is a compression bomb.
+const maxDecodedBody = 4 << 20
+
+// ErrUnsupportedVersion is returned (wrapped) by DecodeMessage for a
+// message pointer version this build cannot read — typically one written
+// by a newer producer mid-upgrade. Consumers leave such messages on the
+// broker for an upgraded consumer rather than dropping them as malformed.
+var ErrUnsupportedVersion = errors.New("queue: unsupported message version")
+
+// decoders maps each readable wire version to its decoder: the current
+// version and the one before it. A format change adds the new version,
+// drops the oldest, and upgrades the previous shape in its decoder.
+var decoders = map[uint32]func([]byte) (common.Message, error){
+	common.MessageVersionLegacy:  decodeLegacy,
+	common.MessageVersionCurrent: decodeCurrent,
+}
+
+// ValidEncoding reports whether enc is "" (no compression), gzip or zstd.
+func ValidEncoding(enc string) bool {
+	return enc == "" || enc == EncodingGzip || enc == EncodingZstd
+}
+
+var (
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdDec  *zstd.Decoder
+)
+
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder) {
+	zstdOnce.Do(func() {
+		// Neither constructor fails without options; both are safe for
+		// concurrent EncodeAll / DecodeAll.
+		zstdEnc, _ = zstd.NewWriter(nil)
+		zstdDec, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecodedBody))
+	})
+	return zstdEnc, zstdDec
+}
+
+// EncodeMessage marshals m to JSON and compresses it with enc ("" leaves
+// it as plain JSON). An unset Version is stamped as the current one.
+func EncodeMessage(m common.Message, enc string) ([]byte, error) {
+	if m.Version == 0 {
+		m.Version = common.MessageVersionCurrent
+	}
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	switch enc {
+	case "":
+		return body, nil
+	case EncodingGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case EncodingZstd:
+		e, _ := zstdCodec()
+		return e.EncodeAll(body, nil), nil
+	default:
+		return nil, fmt.Errorf("queue: unknown content encoding %q", enc)
+	}
+}
+
+// DecodeMessage reverses EncodeMessage, reading any version in decoders.
+// Bodies without a version field are Java-format pointers (version 1).
+func DecodeMessage(body []byte, enc string) (common.Message, error) {
+	var m common.Message
+	switch enc {
+	case "":
+	case EncodingGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return m, fmt.Errorf("gunzip: %w", err)
+		}
+		raw, err := io.ReadAll(io.LimitReader(zr, maxDecodedBody+1))
+		if err != nil {
+			return m, fmt.Errorf("gunzip: %w", err)
+		}
+		if len(raw) > maxDecodedBody {
+			return m, fmt.Errorf("gunzip: body exceeds %d bytes", maxDecodedBody)
+		}
+		body = raw
+	case EncodingZstd:
+		_, d := zstdCodec()
+		raw, err := d.DecodeAll(body, nil)
+		if err != nil {
+			return m, fmt.Errorf("zstd: %w", err)
+		}
+		body = raw
+	default:
+		return m, fmt.Errorf("queue: unknown content encoding %q", enc)
+	}
+	var head struct {
+		Version *uint32 `json:"version"`
+	}
+	if err := json.Unmarshal(body, &head); err != nil {
+		return m, fmt.Errorf("unmarshal: %w", err)
+	}
+	version := common.MessageVersionLegacy
+	if head.Version != nil {
+		version = *head.Version
+	}
+	decode, ok := decoders[version]
+	if !ok {
+		return m, fmt.Errorf("%w: %d (reads %d-%d)", ErrUnsupportedVersion,
+			version, common.MessageVersionLegacy, common.MessageVersionCurrent)
+	}
+	return decode(body)
+}
+
+// decodeLegacy reads a Java MessagePointer. Jackson writes absent
+// optionals as null, which unmarshals to the zero value; a pointer
+// without a mediationType predates non-HTTP mediation and is HTTP.
+func decodeLegacy(body []byte) (common.Message, error) {
+	var m common.Message
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, fmt.Errorf("unmarshal v1: %w", err)
+	}
+	m.Version = common.MessageVersionLegacy
+	if m.MediationType == "" {
+		m.MediationType = common.MediationTypeHTTP
+	}
+	return m, nil
+}
+
+func decodeCurrent(body []byte) (common.Message, error) {
+	var m common.Message
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, fmt.Errorf("unmarshal: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,90 @@
+package migrate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// Checkpoint is an append-only file of the messages a migration has
+// published, one key per line. Each record is synced before the source
+// ack, so after a crash the file names every message that may be on the
+// destination but still on the source. Safe for concurrent use.
+type Checkpoint struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]struct{}
+}
+
+// OpenCheckpoint loads path, creating it if missing, and appends to it.
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	done := make(map[string]struct{})
+	if f, err := os.Open(path); err == nil {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if k := strings.TrimSpace(sc.Text()); k != "" {
+				done[k] = struct{}{}
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read checkpoint: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("open checkpoint: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open checkpoint: %w", err)
+	}
+	return &Checkpoint{f: f, done: done}, nil
+}
+
+// Len is the number of messages recorded, this run and earlier ones.
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.done)
+}
+
+// Done reports whether m was published by this or an earlier run.
+func (c *Checkpoint) Done(m common.Message) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.done[checkpointKey(m)]
+	return ok
+}
+
+// Record notes m as published and syncs the file.
+func (c *Checkpoint) Record(m common.Message) error {
+	k := checkpointKey(m)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.f.WriteString(k + "\n"); err != nil {
+		return err
+	}
+	if err := c.f.Sync(); err != nil {
+		return err
+	}
+	c.done[k] = struct{}{}
+	return nil
+}
+
+// Close closes the file.
+func (c *Checkpoint) Close() error { return c.f.Close() }
+
+// checkpointKey identifies one publish of a message. A re-dispatch of the
+// same job reuses the ID with a fresh deduplication ID and must still be
+// moved.
+func checkpointKey(m common.Message) string {
+	if m.DeduplicationID != nil {
+		return m.ID + " " + *m.DeduplicationID
+	}
+	return m.ID
+}
@@ -0,0 +1,153 @@
+// Package migrate moves messages from one queue backend to another, for
+// switching brokers (e.g. SQS to NATS) without dropping in-flight
+// dispatches.
+//
+// Each message is republished whole — message group, deduplication ID,
+// pool code and the rest of common.Message travel in the body — and only
+// then acked on the source. Messages are moved one at a time in the order
+// the source hands them out, so a FIFO source's per-group order carries
+// over. A crash between publish and ack would republish the message on
+// the next run; the Checkpoint records what was published so a rerun acks
+// it instead.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// Options tune a migration run. The zero value moves everything at full
+// speed, without a checkpoint.
+type Options struct {
+	// BatchSize is the most messages requested per poll (default 10).
+	BatchSize uint32
+	// Rate caps publishes per second; 0 is unlimited.
+	Rate float64
+	// Limit stops after this many messages (moved or skipped); 0 drains.
+	Limit int
+	// IdlePolls is how many consecutive empty polls mean the source is
+	// drained (default 3).
+	IdlePolls int
+	// IdleWait is the pause after an empty poll (default 1s), for
+	// backends that return at once rather than long-poll.
+	IdleWait time.Duration
+	// Checkpoint, when set, skips messages an earlier run published.
+	Checkpoint *Checkpoint
+	// Progress is called after each message.
+	Progress func(Stats)
+}
+
+// Stats counts a run's messages.
+type Stats struct {
+	// Moved were published to the destination and acked on the source.
+	Moved int
+	// Skipped were already in the checkpoint and only acked.
+	Skipped int
+}
+
+// Run drains src into dst until the source is empty, Limit is reached or
+// ctx ends. A publish or ack failure stops the run: the failed message
+// and the rest of its batch are handed back to the source, so a rerun
+// picks up where this one stopped.
+func Run(ctx context.Context, src queue.Consumer, dst queue.Publisher, opts Options) (Stats, error) {
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 10
+	}
+	if opts.IdlePolls <= 0 {
+		opts.IdlePolls = 3
+	}
+	if opts.IdleWait <= 0 {
+		opts.IdleWait = time.Second
+	}
+	lim := rate.NewLimiter(rate.Inf, 1)
+	if opts.Rate > 0 {
+		lim = rate.NewLimiter(rate.Limit(opts.Rate), 1)
+	}
+
+	var st Stats
+	idle := 0
+	for {
+		n := opts.BatchSize
+		if opts.Limit > 0 {
+			left := opts.Limit - st.Moved - st.Skipped
+			if left <= 0 {
+				return st, nil
+			}
+			n = min(n, uint32(left))
+		}
+		msgs, err := src.Poll(ctx, n)
+		if err != nil {
+			if ctx.Err() != nil {
+				return st, ctx.Err()
+			}
+			return st, fmt.Errorf("poll %s: %w", src.Identifier(), err)
+		}
+		if len(msgs) == 0 {
+			idle++
+			if idle >= opts.IdlePolls {
+				return st, nil
+			}
+			select {
+			case <-ctx.Done():
+				return st, ctx.Err()
+			case <-time.After(opts.IdleWait):
+			}
+			continue
+		}
+		idle = 0
+
+		for i := range msgs {
+			if err := move(ctx, src, dst, lim, opts.Checkpoint, &msgs[i], &st); err != nil {
+				release(src, msgs[i:])
+				return st, err
+			}
+			if opts.Progress != nil {
+				opts.Progress(st)
+			}
+		}
+	}
+}
+
+func move(ctx context.Context, src queue.Consumer, dst queue.Publisher, lim *rate.Limiter, cp *Checkpoint, qm *common.QueuedMessage, st *Stats) error {
+	if cp != nil && cp.Done(qm.Message) {
+		if err := src.Ack(ctx, qm.ReceiptHandle); err != nil {
+			return fmt.Errorf("ack %s: %w", qm.Message.ID, err)
+		}
+		st.Skipped++
+		return nil
+	}
+	if err := lim.Wait(ctx); err != nil {
+		return err
+	}
+	if _, err := dst.Publish(ctx, qm.Message); err != nil {
+		return fmt.Errorf("publish %s to %s: %w", qm.Message.ID, dst.Identifier(), err)
+	}
+	if cp != nil {
+		if err := cp.Record(qm.Message); err != nil {
+			return fmt.Errorf("checkpoint %s: %w", qm.Message.ID, err)
+		}
+	}
+	if err := src.Ack(ctx, qm.ReceiptHandle); err != nil {
+		return fmt.Errorf("ack %s: %w", qm.Message.ID, err)
+	}
+	st.Moved++
+	return nil
+}
+
+// release makes unmoved messages visible on the source again, without
+// counting a failure against them. Best effort: the visibility timeout
+// returns them anyway.
+func release(src queue.Consumer, msgs []common.QueuedMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var now uint32
+	for _, qm := range msgs {
+		_ = src.Defer(ctx, qm.ReceiptHandle, &now)
+	}
+}
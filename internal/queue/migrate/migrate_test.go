@@ -0,0 +1,167 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// fakeSource hands out its messages in order; unacked ones come back on
+// the next poll, like a visibility timeout that has expired.
+type fakeSource struct {
+	queue.Consumer // unimplemented methods panic
+	msgs           []common.Message
+	acked          map[string]bool
+	deferred       int
+}
+
+func newSource(msgs ...common.Message) *fakeSource {
+	return &fakeSource{msgs: msgs, acked: map[string]bool{}}
+}
+
+func (s *fakeSource) Identifier() string { return "src" }
+
+func (s *fakeSource) Poll(_ context.Context, max uint32) ([]common.QueuedMessage, error) {
+	var out []common.QueuedMessage
+	for i, m := range s.msgs {
+		r := strconv.Itoa(i)
+		if s.acked[r] {
+			continue
+		}
+		out = append(out, common.QueuedMessage{Message: m, ReceiptHandle: r})
+		if len(out) == int(max) {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeSource) Ack(_ context.Context, receipt string) error {
+	s.acked[receipt] = true
+	return nil
+}
+
+func (s *fakeSource) Defer(context.Context, string, *uint32) error {
+	s.deferred++
+	return nil
+}
+
+type fakeDest struct {
+	queue.Publisher
+	got    []common.Message
+	failAt int // 1-based publish that fails; 0 never
+}
+
+func (d *fakeDest) Identifier() string { return "dst" }
+
+func (d *fakeDest) Publish(_ context.Context, m common.Message) (string, error) {
+	if d.failAt > 0 && len(d.got)+1 == d.failAt {
+		d.failAt = 0
+		return "", errors.New("broker down")
+	}
+	d.got = append(d.got, m)
+	return m.ID, nil
+}
+
+func msg(id, group string) common.Message {
+	m := common.Message{ID: id, PoolCode: "default", MediationTarget: "http://platform/api/dispatch/process"}
+	if group != "" {
+		m.MessageGroupID = &group
+	}
+	return m
+}
+
+func fast(o Options) Options {
+	o.IdlePolls, o.IdleWait = 1, time.Millisecond
+	return o
+}
+
+func ids(msgs []common.Message) []string {
+	var out []string
+	for _, m := range msgs {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+func TestRun_MovesInOrderWithMetadata(t *testing.T) {
+	dedup := "d-1"
+	first := msg("m1", "order-7")
+	first.DeduplicationID = &dedup
+	src := newSource(first, msg("m2", "order-7"), msg("m3", "order-9"))
+	dst := &fakeDest{}
+
+	st, err := Run(context.Background(), src, dst, fast(Options{BatchSize: 2}))
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Moved: 3}, st)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, ids(dst.got))
+	assert.Equal(t, first, dst.got[0], "group, dedup ID and pool travel unchanged")
+	assert.Len(t, src.acked, 3)
+}
+
+func TestRun_Limit(t *testing.T) {
+	src := newSource(msg("m1", ""), msg("m2", ""), msg("m3", ""))
+	dst := &fakeDest{}
+	st, err := Run(context.Background(), src, dst, fast(Options{Limit: 2}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, st.Moved)
+	assert.Equal(t, []string{"m1", "m2"}, ids(dst.got))
+}
+
+func TestRun_PublishFailureReleasesRestAndResumes(t *testing.T) {
+	src := newSource(msg("m1", "g"), msg("m2", "g"), msg("m3", "g"))
+	dst := &fakeDest{failAt: 2}
+
+	st, err := Run(context.Background(), src, dst, fast(Options{}))
+	require.ErrorContains(t, err, "broker down")
+	assert.Equal(t, 1, st.Moved)
+	assert.Equal(t, 2, src.deferred, "m2 and m3 go back to the source")
+
+	st, err = Run(context.Background(), src, dst, fast(Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, st.Moved)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, ids(dst.got), "order survives the restart")
+}
+
+func TestRun_CheckpointSkipsPublishedButUnacked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cp")
+	cp, err := OpenCheckpoint(path)
+	require.NoError(t, err)
+	// A crashed run published m1 but never acked it.
+	require.NoError(t, cp.Record(msg("m1", "")))
+	require.NoError(t, cp.Close())
+
+	cp, err = OpenCheckpoint(path)
+	require.NoError(t, err)
+	defer cp.Close()
+	assert.Equal(t, 1, cp.Len())
+
+	redispatch := msg("m1", "")
+	fresh := "d-2"
+	redispatch.DeduplicationID = &fresh
+	src := newSource(msg("m1", ""), msg("m2", ""), redispatch)
+	dst := &fakeDest{}
+	st, err := Run(context.Background(), src, dst, fast(Options{Checkpoint: cp}))
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Moved: 2, Skipped: 1}, st)
+	assert.Equal(t, []string{"m2", "m1"}, ids(dst.got), "a re-dispatch with a new dedup ID still moves")
+	assert.Len(t, src.acked, 3)
+	assert.Equal(t, 3, cp.Len())
+}
+
+func TestRun_RateLimit(t *testing.T) {
+	src := newSource(msg("m1", ""), msg("m2", ""), msg("m3", ""))
+	start := time.Now()
+	_, err := Run(context.Background(), src, &fakeDest{}, fast(Options{Rate: 20}))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "3 publishes at 20/s take ~100ms")
+}
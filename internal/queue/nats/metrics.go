@@ -0,0 +1,74 @@
+package nats
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	connectedDesc = prometheus.NewDesc("fc_nats_connections_connected",
+		"NATS connections currently connected, per queue.", []string{"queue"}, nil)
+	disconnectsDesc = prometheus.NewDesc("fc_nats_disconnects_total",
+		"Times a NATS connection dropped, per queue.", []string{"queue"}, nil)
+	reconnectsDesc = prometheus.NewDesc("fc_nats_reconnects_total",
+		"Times a dropped NATS connection was re-established, per queue.", []string{"queue"}, nil)
+	connectFailuresDesc = prometheus.NewDesc("fc_nats_connect_failures_total",
+		"Initial NATS connects that failed (auth, TLS or unreachable), per queue.", []string{"queue"}, nil)
+)
+
+// connStats tracks every connection opened for one queue identifier;
+// a queue with several connections shares one entry.
+type connStats struct {
+	connected       atomic.Int64
+	disconnects     atomic.Uint64
+	reconnects      atomic.Uint64
+	connectFailures atomic.Uint64
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = map[string]*connStats{}
+)
+
+// track returns the stats for identifier, creating them on first use.
+func track(identifier string) *connStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	s, ok := stats[identifier]
+	if !ok {
+		s = &connStats{}
+		stats[identifier] = s
+	}
+	return s
+}
+
+// Collector exports connection state and reconnect counts for every
+// NATS queue in the process. Register it once on the metrics registry.
+type Collector struct{}
+
+// Describe is a no-op (unchecked const-metric collector).
+func (Collector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits the gauge and counters per queue identifier.
+func (Collector) Collect(ch chan<- prometheus.Metric) {
+	statsMu.Lock()
+	ids := make([]string, 0, len(stats))
+	snapshot := make(map[string]*connStats, len(stats))
+	for id, s := range stats {
+		ids = append(ids, id)
+		snapshot[id] = s
+	}
+	statsMu.Unlock()
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		s := snapshot[id]
+		ch <- prometheus.MustNewConstMetric(connectedDesc, prometheus.GaugeValue, float64(s.connected.Load()), id)
+		ch <- prometheus.MustNewConstMetric(disconnectsDesc, prometheus.CounterValue, float64(s.disconnects.Load()), id)
+		ch <- prometheus.MustNewConstMetric(reconnectsDesc, prometheus.CounterValue, float64(s.reconnects.Load()), id)
+		ch <- prometheus.MustNewConstMetric(connectFailuresDesc, prometheus.CounterValue, float64(s.connectFailures.Load()), id)
+	}
+}
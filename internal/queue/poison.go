@@ -0,0 +1,29 @@
+package queue
+
+import "context"
+
+// PoisonMessage is a message a consumer received but could not decode.
+// Body is the payload as it came off the broker once transport framing
+// (SQS base64) is undone, but before decompression, so it can be decoded
+// again with ContentEncoding on replay.
+type PoisonMessage struct {
+	QueueIdentifier string
+	// Subject is the NATS subject the message arrived on; empty for
+	// backends without subjects.
+	Subject         string
+	BrokerMessageID string
+	Body            []byte
+	ContentEncoding string
+	Err             error
+}
+
+// PoisonHandler receives poison messages before the consumer drops them.
+// It runs on the poll loop, so it must not block for long.
+type PoisonHandler func(ctx context.Context, p PoisonMessage)
+
+// PoisonReporter is implemented by consumers that hand undecodable
+// messages to a PoisonHandler instead of dropping them silently. Set the
+// handler before the first Poll.
+type PoisonReporter interface {
+	SetPoisonHandler(h PoisonHandler)
+}
@@ -32,7 +32,6 @@ package postgres
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync/atomic"
 	"time"
@@ -168,9 +167,9 @@ UPDATE queue_messages t
 		if err := rows.Scan(&id, &payload); err != nil {
 			return nil, err
 		}
-		var m common.Message
-		if err := json.Unmarshal([]byte(payload), &m); err != nil {
-			return nil, fmt.Errorf("unmarshal message %s: %w", id, err)
+		m, err := queue.DecodeMessage([]byte(payload), "")
+		if err != nil {
+			return nil, fmt.Errorf("decode message %s: %w", id, err)
 		}
 		msgs = append(msgs, common.QueuedMessage{
 			Message:         m,
@@ -228,7 +227,7 @@ func (q *Queue) ExtendVisibility(ctx context.Context, receipt string, seconds ui
 // Publish writes a single message. Uses ON CONFLICT DO NOTHING so a
 // duplicate id is a no-op (matches Rust at-least-once publish semantics).
 func (q *Queue) Publish(ctx context.Context, m common.Message) (string, error) {
-	payload, err := json.Marshal(m)
+	payload, err := queue.EncodeMessage(m, "")
 	if err != nil {
 		return "", err
 	}
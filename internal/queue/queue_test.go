@@ -2,6 +2,8 @@ package queue_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,3 +51,77 @@ func TestSQSHTTPSURLRoutesToSQSBackend(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), `scheme "https"`)
 }
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	group := "order-7"
+	m := common.Message{ID: "dsj_1", MediationTarget: "http://platform/api/dispatch/process", MessageGroupID: &group}
+	for _, enc := range []string{"", queue.EncodingGzip, queue.EncodingZstd} {
+		body, err := queue.EncodeMessage(m, enc)
+		require.NoError(t, err, enc)
+		got, err := queue.DecodeMessage(body, enc)
+		require.NoError(t, err, enc)
+		want := m
+		want.Version = common.MessageVersionCurrent // stamped on encode
+		assert.Equal(t, want, got, enc)
+	}
+
+	_, err := queue.EncodeMessage(m, "brotli")
+	assert.Error(t, err)
+	assert.False(t, queue.ValidEncoding("brotli"))
+
+	// A body tagged with the wrong encoding is malformed, not silently misread.
+	plain, _ := queue.EncodeMessage(m, "")
+	_, err = queue.DecodeMessage(plain, queue.EncodingGzip)
+	assert.Error(t, err)
+}
+
+// The fixtures are message pointers as the Java producers (Jackson, no
+// version field, nulls for absent optionals) and the Go publishers write
+// them. Each must decode, so neither side of a rolling upgrade turns the
+// other's messages into poison.
+func TestDecodeMessage_ProducerFixtures(t *testing.T) {
+	read := func(name string) []byte {
+		b, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		return b
+	}
+
+	java, err := queue.DecodeMessage(read("java-message-pointer.json"), "")
+	require.NoError(t, err)
+	assert.Equal(t, common.MessageVersionLegacy, java.Version)
+	assert.Equal(t, "0HZXEQ5Y8JY5Z", java.ID)
+	assert.Equal(t, "POOL-HIGH", java.PoolCode)
+	require.NotNil(t, java.AuthToken)
+	assert.Nil(t, java.SigningSecret)
+	require.NotNil(t, java.MessageGroupID)
+	assert.Equal(t, "order-4711", *java.MessageGroupID)
+	assert.True(t, java.HighPriority)
+	assert.Equal(t, common.DispatchBlockOnError, java.DispatchMode)
+
+	minimal, err := queue.DecodeMessage(read("java-message-pointer-minimal.json"), "")
+	require.NoError(t, err)
+	assert.Equal(t, common.MediationTypeHTTP, minimal.MediationType, "v1 defaults to HTTP")
+	assert.Nil(t, minimal.MessageGroupID)
+
+	goV2, err := queue.DecodeMessage(read("go-message-pointer-v2.json"), "")
+	require.NoError(t, err)
+	assert.Equal(t, common.MessageVersionCurrent, goV2.Version)
+	assert.Equal(t, uint32(30), goV2.TimeoutSeconds)
+	assert.Equal(t, "hooks.example.com", goV2.TargetHost)
+	assert.Equal(t, uint32(3), goV2.SubscriptionWeight)
+
+	// Re-encoding a legacy pointer keeps its version, so a Go relay
+	// doesn't claim a shape it didn't produce.
+	body, err := queue.EncodeMessage(java, "")
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"version":1`)
+}
+
+func TestDecodeMessage_UnsupportedVersion(t *testing.T) {
+	_, err := queue.DecodeMessage([]byte(`{"version":3,"id":"dsj_1","mediationTarget":"http://x"}`), "")
+	require.ErrorIs(t, err, queue.ErrUnsupportedVersion)
+
+	_, err = queue.DecodeMessage([]byte(`{"version":"two"}`), "")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, queue.ErrUnsupportedVersion, "a mistyped field is malformed")
+}
@@ -0,0 +1,201 @@
+package sqs
+
+// Large-message support in the AWS SQS Extended Client format. A body
+// over ExtendedThreshold is written to S3 and the SQS message carries a
+// pointer instead:
+//
+//	["software.amazon.payloadoffloading.PayloadS3Pointer",{"s3BucketName":"b","s3Key":"k"}]
+//
+// with an ExtendedPayloadSize attribute holding the original body size.
+// The stored object is exactly the body that would otherwise have been
+// sent (compressed and base64-encoded when compression is on; the
+// contentEncoding attribute stays on the message), so Java / Python
+// extended clients and this consumer read each other's messages.
+//
+// Offloading is publish-side and opt-in (QueueConfig.LargePayloadBucket);
+// consumers always resolve pointers. Objects are not deleted on ack — a
+// redelivered duplicate would then find its payload gone — so the bucket
+// needs a lifecycle rule expiring them after the queue's retention period.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
+)
+
+// ExtendedThreshold is the message size (body plus attributes) above
+// which a body is offloaded — the extended clients' default, SQS's
+// classic 256 KiB limit.
+const ExtendedThreshold = 256 << 10
+
+// maxExtendedBody caps a fetched payload. Nothing this router consumes
+// comes near it; past it the message is treated as malformed.
+const maxExtendedBody = 16 << 20
+
+const (
+	extendedSizeAttr       = "ExtendedPayloadSize"
+	legacyExtendedSizeAttr = "SQSLargePayloadSize"
+	s3PointerClass         = "software.amazon.payloadoffloading.PayloadS3Pointer"
+)
+
+// s3Pointer is the second element of the pointer body.
+type s3Pointer struct {
+	Bucket string `json:"s3BucketName"`
+	Key    string `json:"s3Key"`
+}
+
+// payloadStore is where offloaded bodies live; S3 in production.
+type payloadStore interface {
+	put(ctx context.Context, bucket, key string, body []byte) error
+	// get returns errPayloadGone when the object doesn't exist.
+	get(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+var (
+	// errPayloadGone: the pointer's object no longer exists (expired by
+	// the lifecycle rule, or deleted by another consumer). Retrying
+	// won't bring it back.
+	errPayloadGone = errors.New("extended payload no longer in S3")
+	// errBadPointer: the message claims an extended payload but its body
+	// isn't a pointer.
+	errBadPointer = errors.New("malformed extended payload pointer")
+)
+
+// objectStores reaches S3 through export.ObjectStore, one per bucket a
+// pointer names, signed for the queue's region with the default AWS
+// credential chain.
+type objectStores struct {
+	region string
+
+	mu     sync.Mutex
+	stores map[string]*export.ObjectStore
+}
+
+func (s *objectStores) bucket(ctx context.Context, bucket string) (*export.ObjectStore, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.stores[bucket]; ok {
+		return st, nil
+	}
+	st, err := export.NewObjectStore(ctx, export.Config{Destination: "s3://" + bucket, Region: s.region})
+	if err != nil {
+		return nil, err
+	}
+	if s.stores == nil {
+		s.stores = make(map[string]*export.ObjectStore)
+	}
+	s.stores[bucket] = st
+	return st, nil
+}
+
+func (s *objectStores) put(ctx context.Context, bucket, key string, body []byte) error {
+	st, err := s.bucket(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := st.Upload(ctx, key, "", bytes.NewReader(body)); err != nil {
+		return fmt.Errorf("s3 put: %w", err)
+	}
+	return nil
+}
+
+func (s *objectStores) get(ctx context.Context, bucket, key string) ([]byte, error) {
+	st, err := s.bucket(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	body, err := st.Get(ctx, key)
+	if errors.Is(err, export.ErrNotFound) {
+		return nil, errPayloadGone
+	}
+	if err != nil {
+		return nil, fmt.Errorf("s3 get: %w", err)
+	}
+	if len(body) > maxExtendedBody {
+		return nil, fmt.Errorf("%w: payload over %d bytes", errBadPointer, maxExtendedBody)
+	}
+	return body, nil
+}
+
+// messageSize is what SQS counts against its limit: the body plus each
+// attribute's name, type and value.
+func messageSize(body string, attrs map[string]sqstypes.MessageAttributeValue) int {
+	n := len(body)
+	for name, a := range attrs {
+		n += len(name) + len(aws.ToString(a.DataType)) + len(aws.ToString(a.StringValue)) + len(a.BinaryValue)
+	}
+	return n
+}
+
+// offload moves body to S3 when the message would be over
+// ExtendedThreshold and offloading is configured, returning the pointer
+// body and attributes to send in its place. Otherwise both come back
+// unchanged.
+func (q *Queue) offload(ctx context.Context, body string, attrs map[string]sqstypes.MessageAttributeValue) (string, map[string]sqstypes.MessageAttributeValue, error) {
+	if q.largePayloadBucket == "" || messageSize(body, attrs) <= ExtendedThreshold {
+		return body, attrs, nil
+	}
+	ptr := s3Pointer{Bucket: q.largePayloadBucket, Key: uuid.NewString()}
+	if err := q.store.put(ctx, ptr.Bucket, ptr.Key, []byte(body)); err != nil {
+		return "", nil, fmt.Errorf("offload large message: %w", err)
+	}
+	raw, err := json.Marshal([]any{s3PointerClass, ptr})
+	if err != nil {
+		return "", nil, err
+	}
+	out := make(map[string]sqstypes.MessageAttributeValue, len(attrs)+1)
+	for k, v := range attrs {
+		out[k] = v
+	}
+	out[extendedSizeAttr] = sqstypes.MessageAttributeValue{
+		DataType:    aws.String("Number"),
+		StringValue: aws.String(strconv.Itoa(len(body))),
+	}
+	return string(raw), out, nil
+}
+
+// resolveExtended replaces a pointer body with the payload it points at.
+// Messages without the size attribute are left alone. errPayloadGone and
+// errBadPointer mean the message can never be read; any other error is
+// worth retrying.
+func (q *Queue) resolveExtended(ctx context.Context, sm *sqstypes.Message) error {
+	_, ext := sm.MessageAttributes[extendedSizeAttr]
+	_, legacy := sm.MessageAttributes[legacyExtendedSizeAttr]
+	if !ext && !legacy {
+		return nil
+	}
+	ptr, err := parseS3Pointer(aws.ToString(sm.Body))
+	if err != nil {
+		return err
+	}
+	body, err := q.store.get(ctx, ptr.Bucket, ptr.Key)
+	if err != nil {
+		return err
+	}
+	sm.Body = aws.String(string(body))
+	return nil
+}
+
+func parseS3Pointer(body string) (s3Pointer, error) {
+	var parts []json.RawMessage
+	if err := json.Unmarshal([]byte(body), &parts); err != nil || len(parts) != 2 {
+		return s3Pointer{}, errBadPointer
+	}
+	var class string
+	var ptr s3Pointer
+	if json.Unmarshal(parts[0], &class) != nil || class != s3PointerClass ||
+		json.Unmarshal(parts[1], &ptr) != nil || ptr.Bucket == "" || ptr.Key == "" {
+		return s3Pointer{}, errBadPointer
+	}
+	return ptr, nil
+}
@@ -0,0 +1,109 @@
+package sqs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// memStore is an in-memory payloadStore.
+type memStore map[string][]byte
+
+func (s memStore) put(_ context.Context, bucket, key string, body []byte) error {
+	s[bucket+"/"+key] = body
+	return nil
+}
+
+func (s memStore) get(_ context.Context, bucket, key string) ([]byte, error) {
+	b, ok := s[bucket+"/"+key]
+	if !ok {
+		return nil, errPayloadGone
+	}
+	return b, nil
+}
+
+func bigMessage() common.Message {
+	return common.Message{Version: common.MessageVersionCurrent, ID: "dsj_big", MediationTarget: "http://platform/" + strings.Repeat("x", ExtendedThreshold)}
+}
+
+func TestExtended_OffloadAndResolve(t *testing.T) {
+	ctx := context.Background()
+	for _, enc := range []string{"", queue.EncodingGzip} {
+		t.Run("encoding="+enc, func(t *testing.T) {
+			store := memStore{}
+			q := &Queue{store: store, largePayloadBucket: "fc-large", compression: enc}
+			m := bigMessage()
+			if enc != "" {
+				// Random, so it stays over the threshold compressed.
+				noise := make([]byte, ExtendedThreshold)
+				_, _ = rand.Read(noise)
+				m.MediationTarget = "http://platform/" + hex.EncodeToString(noise)
+			}
+			body, attrs, err := q.encode(ctx, m)
+			require.NoError(t, err)
+
+			require.Len(t, store, 1)
+			assert.True(t, strings.HasPrefix(body, `["software.amazon.payloadoffloading.PayloadS3Pointer",{"s3BucketName":"fc-large","s3Key":"`), body)
+			assert.Equal(t, "Number", *attrs[extendedSizeAttr].DataType)
+			if enc != "" {
+				assert.Equal(t, enc, *attrs[queue.ContentEncodingAttr].StringValue)
+			}
+
+			sm := sqstypes.Message{Body: &body, MessageAttributes: attrs, ReceiptHandle: aws.String("rh")}
+			require.NoError(t, q.resolveExtended(ctx, &sm))
+			got, _, _, err := q.parseMessage(sm)
+			require.NoError(t, err)
+			assert.Equal(t, m, got)
+		})
+	}
+}
+
+func TestExtended_SmallOrUnconfiguredStaysInline(t *testing.T) {
+	ctx := context.Background()
+	store := memStore{}
+	small := &Queue{store: store, largePayloadBucket: "fc-large"}
+	body, attrs, err := small.encode(ctx, common.Message{ID: "dsj_1"})
+	require.NoError(t, err)
+	assert.Contains(t, body, `"id":"dsj_1"`)
+	assert.Nil(t, attrs)
+
+	off := &Queue{store: store}
+	body, _, err = off.encode(ctx, bigMessage())
+	require.NoError(t, err)
+	assert.Contains(t, body, `"id":"dsj_big"`)
+	assert.Empty(t, store)
+}
+
+// A pointer from a Java extended client using the legacy attribute name
+// resolves too; a dangling or malformed pointer is permanent.
+func TestExtended_ResolveForeignAndBroken(t *testing.T) {
+	ctx := context.Background()
+	store := memStore{"b/k": []byte(`{"id":"dsj_java","mediationTarget":"http://t"}`)}
+	q := &Queue{store: store}
+	legacy := map[string]sqstypes.MessageAttributeValue{legacyExtendedSizeAttr: {DataType: aws.String("Number"), StringValue: aws.String("40")}}
+
+	sm := sqstypes.Message{Body: aws.String(`["software.amazon.payloadoffloading.PayloadS3Pointer",{"s3BucketName":"b","s3Key":"k"}]`), MessageAttributes: legacy}
+	require.NoError(t, q.resolveExtended(ctx, &sm))
+	assert.Contains(t, *sm.Body, "dsj_java")
+
+	gone := sqstypes.Message{Body: aws.String(`["software.amazon.payloadoffloading.PayloadS3Pointer",{"s3BucketName":"b","s3Key":"nope"}]`), MessageAttributes: legacy}
+	assert.True(t, errors.Is(q.resolveExtended(ctx, &gone), errPayloadGone))
+
+	bad := sqstypes.Message{Body: aws.String(`{"id":"x"}`), MessageAttributes: legacy}
+	assert.True(t, errors.Is(q.resolveExtended(ctx, &bad), errBadPointer))
+
+	plain := sqstypes.Message{Body: aws.String(`{"id":"x"}`)}
+	require.NoError(t, q.resolveExtended(ctx, &plain))
+	assert.Equal(t, `{"id":"x"}`, *plain.Body)
+}
@@ -7,13 +7,22 @@
 //     successfully (or unsuccessfully) DeleteMessage for a MessageId,
 //     subsequent redeliveries within PendingDeleteTTL are deleted
 //     immediately on poll instead of being routed to the mediator.
+//   - FIFO queues (URL / name ending ".fifo") always get a MessageGroupId
+//     and, unless the queue uses content-based dedup, a
+//     MessageDeduplicationId.
+//   - Optional body compression (QueueConfig.Compression): the compressed
+//     body is base64-encoded, since SQS bodies must be text, and tagged
+//     with a contentEncoding message attribute.
+//   - Optional large-message offload to S3 (QueueConfig.LargePayloadBucket)
+//     in the AWS SQS Extended Client format; see extended.go.
 package sqs
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log/slog"
 	neturl "net/url"
 	"strconv"
 	"strings"
@@ -82,10 +91,18 @@ func build(ctx context.Context, cfg common.QueueConfig) (*Queue, error) {
 	if vt == 0 {
 		vt = 30
 	}
+	if !queue.ValidEncoding(cfg.Compression) {
+		return nil, fmt.Errorf("sqs: unknown compression %q (gzip, zstd)", cfg.Compression)
+	}
 	q := &Queue{
 		client:             client,
+		store:              &objectStores{region: awsCfg.Region},
+		largePayloadBucket: cfg.LargePayloadBucket,
 		queueURL:           cfg.URI,
 		queueName:          queueName,
+		fifo:               isFIFO(cfg.URI, queueName),
+		contentBasedDedup:  cfg.ContentBasedDeduplication,
+		compression:        cfg.Compression,
 		visibilityTimeout:  int32(vt),
 		waitSeconds:        DefaultWaitSeconds,
 		pendingDelete:      make(map[string]time.Time),
@@ -110,6 +127,12 @@ func regionFromSQSURL(uri string) string {
 	return ""
 }
 
+// isFIFO reports an SQS FIFO queue: AWS requires the ".fifo" suffix on
+// every FIFO queue name, so the URL alone is authoritative.
+func isFIFO(uri, queueName string) bool {
+	return strings.HasSuffix(uri, ".fifo") || strings.HasSuffix(queueName, ".fifo")
+}
+
 func queueNameFromURL(url string) string {
 	parts := strings.Split(url, "/")
 	if len(parts) == 0 {
@@ -126,16 +149,24 @@ type receiptMapping struct {
 // Queue is the SQS-backed queue. Implements both Consumer and Publisher.
 type Queue struct {
 	client            *sqs.Client
+	store             payloadStore
 	queueURL          string
 	queueName         string
-	visibilityTimeout int32
-	waitSeconds       int32
+	fifo              bool
+	contentBasedDedup bool
+	compression       string
+	// largePayloadBucket receives bodies over ExtendedThreshold; "" sends
+	// them as-is (and SQS rejects them).
+	largePayloadBucket string
+	visibilityTimeout  int32
+	waitSeconds        int32
 
 	mu                 sync.Mutex
 	pendingDelete      map[string]time.Time
 	receiptToMessageID map[string]receiptMapping
 
 	running atomic.Bool
+	poison  atomic.Pointer[queue.PoisonHandler]
 
 	polled   atomic.Uint64
 	acked    atomic.Uint64
@@ -190,9 +221,30 @@ func (q *Queue) Poll(ctx context.Context, maxMessages uint32) ([]common.QueuedMe
 			}
 		}
 
+		if err := q.resolveExtended(ctx, &sm); err != nil {
+			if errors.Is(err, errPayloadGone) || errors.Is(err, errBadPointer) {
+				slog.Warn("sqs: dropping unreadable extended message", "queue", q.queueName, "message_id", aws.ToString(sm.MessageId), "err", err)
+				if sm.ReceiptHandle != nil {
+					_ = q.Ack(ctx, *sm.ReceiptHandle)
+				}
+			} else {
+				// Left invisible; SQS redelivers it after the visibility
+				// timeout.
+				slog.Warn("sqs: extended payload fetch failed", "queue", q.queueName, "message_id", aws.ToString(sm.MessageId), "err", err)
+			}
+			continue
+		}
 		msg, receipt, brokerID, perr := q.parseMessage(sm)
+		if errors.Is(perr, queue.ErrUnsupportedVersion) {
+			// Written by a newer producer: leave it invisible for an
+			// upgraded router (or the DLQ) instead of dropping it.
+			slog.Warn("sqs: unreadable message version", "queue", q.queueName, "message_id", aws.ToString(sm.MessageId), "err", perr)
+			continue
+		}
 		if perr != nil {
-			// Malformed — ACK it so it doesn't keep coming back.
+			// Malformed — quarantine it if a handler is set, then ACK it
+			// so it doesn't keep coming back.
+			q.reportPoison(ctx, sm, perr)
 			if sm.ReceiptHandle != nil {
 				_ = q.Ack(ctx, *sm.ReceiptHandle)
 			}
@@ -219,9 +271,18 @@ func (q *Queue) parseMessage(sm sqstypes.Message) (common.Message, string, strin
 	if sm.Body == nil {
 		return common.Message{}, "", "", errors.New("empty body")
 	}
-	var m common.Message
-	if err := json.Unmarshal([]byte(*sm.Body), &m); err != nil {
-		return common.Message{}, "", "", fmt.Errorf("unmarshal: %w", err)
+	body, enc := []byte(*sm.Body), ""
+	if a, ok := sm.MessageAttributes[queue.ContentEncodingAttr]; ok && a.StringValue != nil {
+		enc = *a.StringValue
+		raw, err := base64.StdEncoding.DecodeString(*sm.Body)
+		if err != nil {
+			return common.Message{}, "", "", fmt.Errorf("base64: %w", err)
+		}
+		body = raw
+	}
+	m, err := queue.DecodeMessage(body, enc)
+	if err != nil {
+		return common.Message{}, "", "", err
 	}
 	if sm.ReceiptHandle == nil {
 		return common.Message{}, "", "", errors.New("missing receipt handle")
@@ -233,6 +294,31 @@ func (q *Queue) parseMessage(sm sqstypes.Message) (common.Message, string, strin
 	return m, *sm.ReceiptHandle, brokerID, nil
 }
 
+// SetPoisonHandler implements queue.PoisonReporter.
+func (q *Queue) SetPoisonHandler(h queue.PoisonHandler) { q.poison.Store(&h) }
+
+// reportPoison hands an undecodable message to the poison handler. The
+// body is base64-decoded when it carries a content encoding and decodes
+// cleanly; otherwise it is kept as received.
+func (q *Queue) reportPoison(ctx context.Context, sm sqstypes.Message, err error) {
+	h := q.poison.Load()
+	if h == nil {
+		return
+	}
+	p := queue.PoisonMessage{
+		QueueIdentifier: q.queueName,
+		BrokerMessageID: aws.ToString(sm.MessageId),
+		Body:            []byte(aws.ToString(sm.Body)),
+		Err:             err,
+	}
+	if a, ok := sm.MessageAttributes[queue.ContentEncodingAttr]; ok && a.StringValue != nil {
+		if raw, derr := base64.StdEncoding.DecodeString(aws.ToString(sm.Body)); derr == nil {
+			p.Body, p.ContentEncoding = raw, *a.StringValue
+		}
+	}
+	(*h)(ctx, p)
+}
+
 // Ack deletes the message and records the MessageId in the pending-delete map.
 func (q *Queue) Ack(ctx context.Context, receipt string) error {
 	q.mu.Lock()
@@ -299,17 +385,16 @@ func (q *Queue) changeVisibility(ctx context.Context, receipt string, delaySecon
 
 // Publish sends a single message via SendMessage.
 func (q *Queue) Publish(ctx context.Context, m common.Message) (string, error) {
-	body, err := json.Marshal(m)
+	body, attrs, err := q.encode(ctx, m)
 	if err != nil {
 		return "", err
 	}
 	in := &sqs.SendMessageInput{
-		QueueUrl:    aws.String(q.queueURL),
-		MessageBody: aws.String(string(body)),
-	}
-	if m.MessageGroupID != nil {
-		in.MessageGroupId = aws.String(*m.MessageGroupID)
+		QueueUrl:          aws.String(q.queueURL),
+		MessageBody:       aws.String(body),
+		MessageAttributes: attrs,
 	}
+	in.MessageGroupId, in.MessageDeduplicationId = q.sendAttributes(m)
 	out, err := q.client.SendMessage(ctx, in)
 	if err != nil {
 		return "", fmt.Errorf("sqs SendMessage: %w", err)
@@ -320,6 +405,50 @@ func (q *Queue) Publish(ctx context.Context, m common.Message) (string, error) {
 	return *out.MessageId, nil
 }
 
+// encode renders m as the SQS message body plus, when compressed, the
+// contentEncoding attribute. An oversized body is offloaded to S3 when
+// the queue has a large-payload bucket.
+func (q *Queue) encode(ctx context.Context, m common.Message) (string, map[string]sqstypes.MessageAttributeValue, error) {
+	body, err := queue.EncodeMessage(m, q.compression)
+	if err != nil {
+		return "", nil, err
+	}
+	if q.compression == "" {
+		return q.offload(ctx, string(body), nil)
+	}
+	return q.offload(ctx, base64.StdEncoding.EncodeToString(body), map[string]sqstypes.MessageAttributeValue{
+		queue.ContentEncodingAttr: {DataType: aws.String("String"), StringValue: aws.String(q.compression)},
+	})
+}
+
+// sendAttributes picks MessageGroupId / MessageDeduplicationId for m.
+//
+// FIFO queues reject a send without a group, so an ungrouped message gets
+// its own id as the group: it is unordered relative to everything else,
+// which is what "no message group" means. The dedup id is the message's
+// DeduplicationID, falling back to its id, and is left unset when the
+// queue hashes the body instead (content-based dedup). Standard queues
+// keep the group (fair queues use it) and never get a dedup id, which SQS
+// rejects there.
+func (q *Queue) sendAttributes(m common.Message) (groupID, dedupID *string) {
+	if m.MessageGroupID != nil && *m.MessageGroupID != "" {
+		groupID = aws.String(*m.MessageGroupID)
+	}
+	if !q.fifo {
+		return groupID, nil
+	}
+	if groupID == nil {
+		groupID = aws.String(m.ID)
+	}
+	if q.contentBasedDedup {
+		return groupID, nil
+	}
+	if m.DeduplicationID != nil && *m.DeduplicationID != "" {
+		return groupID, aws.String(*m.DeduplicationID)
+	}
+	return groupID, aws.String(m.ID)
+}
+
 // PublishBatch sends in batches of 10 (SQS hard limit).
 func (q *Queue) PublishBatch(ctx context.Context, msgs []common.Message) ([]string, error) {
 	ids := make([]string, 0, len(msgs))
@@ -330,17 +459,16 @@ func (q *Queue) PublishBatch(ctx context.Context, msgs []common.Message) ([]stri
 		}
 		entries := make([]sqstypes.SendMessageBatchRequestEntry, 0, end-start)
 		for i := start; i < end; i++ {
-			body, err := json.Marshal(msgs[i])
+			body, attrs, err := q.encode(ctx, msgs[i])
 			if err != nil {
 				return ids, err
 			}
 			e := sqstypes.SendMessageBatchRequestEntry{
-				Id:          aws.String(strconv.Itoa(i)),
-				MessageBody: aws.String(string(body)),
-			}
-			if msgs[i].MessageGroupID != nil {
-				e.MessageGroupId = aws.String(*msgs[i].MessageGroupID)
+				Id:                aws.String(strconv.Itoa(i)),
+				MessageBody:       aws.String(body),
+				MessageAttributes: attrs,
 			}
+			e.MessageGroupId, e.MessageDeduplicationId = q.sendAttributes(msgs[i])
 			entries = append(entries, e)
 		}
 		out, err := q.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
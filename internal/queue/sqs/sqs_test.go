@@ -0,0 +1,68 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+func TestIsFIFO(t *testing.T) {
+	assert.True(t, isFIFO("https://sqs.ap-southeast-2.amazonaws.com/123456789012/dispatch.fifo", ""))
+	assert.True(t, isFIFO("", "dispatch.fifo"))
+	assert.False(t, isFIFO("https://sqs.ap-southeast-2.amazonaws.com/123456789012/dispatch", "dispatch"))
+}
+
+func TestSendAttributes(t *testing.T) {
+	grouped := common.Message{ID: "dsj_1", MessageGroupID: aws.String("order-7"), DeduplicationID: aws.String("dsj_1-2")}
+	ungrouped := common.Message{ID: "dsj_2"}
+
+	cases := []struct {
+		name               string
+		q                  *Queue
+		m                  common.Message
+		wantGroup, wantDup *string
+	}{
+		{"standard keeps group, never dedups", &Queue{}, grouped, aws.String("order-7"), nil},
+		{"standard ungrouped", &Queue{}, ungrouped, nil, nil},
+		{"fifo uses dedup id", &Queue{fifo: true}, grouped, aws.String("order-7"), aws.String("dsj_1-2")},
+		{"fifo ungrouped is its own group", &Queue{fifo: true}, ungrouped, aws.String("dsj_2"), aws.String("dsj_2")},
+		{"fifo content-based leaves dedup to SQS", &Queue{fifo: true, contentBasedDedup: true}, grouped, aws.String("order-7"), nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			group, dedup := tc.q.sendAttributes(tc.m)
+			assert.Equal(t, tc.wantGroup, group)
+			assert.Equal(t, tc.wantDup, dedup)
+		})
+	}
+}
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	m := common.Message{ID: "dsj_1", MediationTarget: "http://platform/api/dispatch/process", MessageGroupID: aws.String("order-7")}
+	for _, enc := range []string{"", queue.EncodingGzip, queue.EncodingZstd} {
+		t.Run("encoding="+enc, func(t *testing.T) {
+			q := &Queue{compression: enc}
+			body, attrs, err := q.encode(context.Background(), m)
+			require.NoError(t, err)
+			if enc == "" {
+				assert.Nil(t, attrs)
+				assert.Contains(t, body, `"id":"dsj_1"`)
+			} else {
+				assert.Equal(t, enc, *attrs[queue.ContentEncodingAttr].StringValue)
+			}
+
+			got, receipt, _, err := q.parseMessage(sqstypes.Message{Body: &body, MessageAttributes: attrs, ReceiptHandle: aws.String("rh")})
+			require.NoError(t, err)
+			assert.Equal(t, "rh", receipt)
+			assert.Equal(t, m.ID, got.ID)
+			assert.Equal(t, m.MessageGroupID, got.MessageGroupID)
+		})
+	}
+}
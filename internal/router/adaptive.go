@@ -0,0 +1,124 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+const (
+	// adaptiveInterval is how often the controller re-evaluates a pool's
+	// concurrency, and adaptiveMinSamples the deliveries a window needs
+	// before it is trusted.
+	adaptiveInterval   = 10 * time.Second
+	adaptiveMinSamples = 10
+	// adaptiveDecrease is the multiplicative step down on overload.
+	adaptiveDecrease = 0.75
+)
+
+// adaptiveController is AIMD concurrency control for one pool. Each
+// delivery outcome is observed; once per adaptiveInterval the window is
+// judged: too many overload outcomes, or a mean latency above the target,
+// shrinks concurrency by adaptiveDecrease; a window in which the pool ran
+// at its limit without either grows it by one. Bounded by the configured
+// min and max. Evaluation happens on the delivery path, so an idle pool
+// keeps its size.
+type adaptiveController struct {
+	cfg common.AdaptiveConcurrency
+
+	mu          sync.Mutex
+	windowStart time.Time
+	samples     int
+	overloads   int
+	latencySum  uint64
+	saturated   bool
+	// paused is set by a manual concurrency override (Manager.UpdatePool)
+	// and cleared when the synced config is reapplied.
+	paused    bool
+	increases uint64
+	decreases uint64
+}
+
+func newAdaptiveController(cfg common.AdaptiveConcurrency) *adaptiveController {
+	cfg = cfg.Normalized()
+	return &adaptiveController{cfg: cfg, windowStart: time.Now()}
+}
+
+// observe records one delivery. saturated reports that the pool was at
+// its concurrency limit when the delivery finished. It returns the new
+// concurrency when the window closed with a change, else 0.
+func (a *adaptiveController) observe(now time.Time, durationMs uint64, overload, saturated bool, current uint32) uint32 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.samples++
+	a.latencySum += durationMs
+	if overload {
+		a.overloads++
+	}
+	if saturated {
+		a.saturated = true
+	}
+	if now.Sub(a.windowStart) < adaptiveInterval {
+		return 0
+	}
+	next := a.decide(current)
+	a.windowStart, a.samples, a.overloads, a.latencySum, a.saturated = now, 0, 0, 0, false
+	if next == current {
+		return 0
+	}
+	return next
+}
+
+// decide judges the closed window. Caller holds a.mu.
+func (a *adaptiveController) decide(current uint32) uint32 {
+	if a.paused || a.samples < adaptiveMinSamples {
+		return current
+	}
+	errRate := float64(a.overloads) / float64(a.samples)
+	slow := a.cfg.TargetLatencyMs > 0 && a.latencySum/uint64(a.samples) > uint64(a.cfg.TargetLatencyMs)
+	next := current
+	switch {
+	case errRate > a.cfg.MaxErrorRate || slow:
+		next = uint32(float64(current) * adaptiveDecrease)
+	case a.saturated:
+		next = current + 1
+	}
+	next = max(a.cfg.MinConcurrency, min(a.cfg.MaxConcurrency, next))
+	switch {
+	case next > current:
+		a.increases++
+	case next < current:
+		a.decreases++
+	}
+	return next
+}
+
+func (a *adaptiveController) setPaused(paused bool) {
+	a.mu.Lock()
+	a.paused = paused
+	a.mu.Unlock()
+}
+
+func (a *adaptiveController) status() *AdaptiveStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return &AdaptiveStatus{
+		MinConcurrency: a.cfg.MinConcurrency,
+		MaxConcurrency: a.cfg.MaxConcurrency,
+		Paused:         a.paused,
+		Increases:      a.increases,
+		Decreases:      a.decreases,
+	}
+}
+
+// isOverload reports whether a mediation outcome says the receiver is
+// struggling: 5xx/timeouts, connection failures and 429s. 4xx and an
+// open breaker (no delivery attempted) don't count.
+func isOverload(r common.MediationResult) bool {
+	switch r {
+	case common.MediationErrorProcess, common.MediationErrorConnection, common.MediationRateLimited:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// window feeds n deliveries, of which overloads are overload outcomes,
+// and closes the window with the last one.
+func window(a *adaptiveController, start time.Time, n, overloads int, latencyMs uint64, saturated bool, cur uint32) uint32 {
+	var next uint32
+	for i := range n {
+		at := start.Add(time.Duration(i) * time.Millisecond)
+		if i == n-1 {
+			at = start.Add(adaptiveInterval)
+		}
+		next = a.observe(at, latencyMs, i < overloads, saturated, cur)
+	}
+	return next
+}
+
+func TestAdaptive_AIMD(t *testing.T) {
+	a := newAdaptiveController(common.AdaptiveConcurrency{MinConcurrency: 2, MaxConcurrency: 10, TargetLatencyMs: 500})
+	start := a.windowStart
+
+	assert.Equal(t, uint32(9), window(a, start, 20, 0, 50, true, 8), "saturated and healthy: grow by one")
+	start = start.Add(adaptiveInterval)
+	assert.Zero(t, window(a, start, 20, 0, 50, true, 10), "already at max")
+	start = start.Add(adaptiveInterval)
+	assert.Zero(t, window(a, start, 20, 0, 50, false, 8), "not saturated: hold")
+	start = start.Add(adaptiveInterval)
+	assert.Equal(t, uint32(6), window(a, start, 20, 5, 50, true, 8), "25% overload: shrink by a quarter")
+	start = start.Add(adaptiveInterval)
+	assert.Equal(t, uint32(6), window(a, start, 20, 0, 900, true, 8), "over target latency: shrink")
+	start = start.Add(adaptiveInterval)
+	assert.Zero(t, window(a, start, 20, 20, 50, true, 2), "never below min")
+	start = start.Add(adaptiveInterval)
+	assert.Zero(t, window(a, start, 5, 5, 50, true, 8), "too few samples to judge")
+
+	st := a.status()
+	assert.Equal(t, uint64(1), st.Increases)
+	assert.Equal(t, uint64(2), st.Decreases)
+}
+
+func TestAdaptive_PausedHolds(t *testing.T) {
+	a := newAdaptiveController(common.AdaptiveConcurrency{MinConcurrency: 1, MaxConcurrency: 10})
+	a.setPaused(true)
+	assert.Zero(t, window(a, a.windowStart, 20, 20, 50, true, 8))
+	assert.True(t, a.status().Paused)
+}
+
+func TestAdaptive_ManualOverridePausesUntilSync(t *testing.T) {
+	m := NewManager(&cascadeMediator{}, nil)
+	cfg := common.RouterConfig{ProcessingPools: []common.PoolConfig{{
+		Code: "A", Concurrency: 50,
+		AdaptiveConcurrency: &common.AdaptiveConcurrency{MinConcurrency: 2, MaxConcurrency: 20},
+	}}}
+	require.NoError(t, m.Reconfigure(context.Background(), cfg))
+	stats := m.Pool("A").Stats()
+	assert.Equal(t, uint32(20), stats.Concurrency, "the start is clamped into the bounds")
+	require.NotNil(t, stats.Adaptive)
+	assert.False(t, stats.Adaptive.Paused)
+
+	require.True(t, m.UpdatePool("A", 4, nil, false))
+	assert.True(t, m.Pool("A").Stats().Adaptive.Paused)
+
+	// The sync resumes adaptation from where the operator left it.
+	require.NoError(t, m.Reconfigure(context.Background(), cfg))
+	stats = m.Pool("A").Stats()
+	assert.False(t, stats.Adaptive.Paused)
+	assert.Equal(t, uint32(4), stats.Concurrency)
+
+	// Dropping the adaptive block returns the pool to fixed concurrency.
+	cfg.ProcessingPools[0].AdaptiveConcurrency = nil
+	require.NoError(t, m.Reconfigure(context.Background(), cfg))
+	stats = m.Pool("A").Stats()
+	assert.Nil(t, stats.Adaptive)
+	assert.Equal(t, uint32(50), stats.Concurrency)
+}
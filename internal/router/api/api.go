@@ -8,13 +8,15 @@
 // Mount pattern (cmd/fc-router):
 //
 //	r := chi.NewRouter()
-//	api := humachi.New(r, huma.DefaultConfig("FlowCatalyst Router API", "dev"))
+//	api := humachi.New(r, routerapi.HumaConfig())
 //	routerapi.Register(api, routerapi.FromServer(srv))
 //	routerapi.MountDashboard(r) // HTML — not a huma operation
+//	r.Method(http.MethodGet, "/swagger", swaggerui.Handler(...))
 package api
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -23,6 +25,7 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/standby"
 )
 
 // Version is the FlowCatalyst release reported by /monitoring. Override
@@ -80,6 +83,24 @@ type PoolUpdater interface {
 	UpdatePool(code string, concurrency uint32, rateLimitPerMinute *uint32, setRateLimit bool) bool
 }
 
+// PoolQuarantiner lifts a pool's auto-quarantine. Optional — when nil
+// POST /pools/{poolCode}/unquarantine returns 503.
+type PoolQuarantiner interface {
+	Unquarantine(code string) (found, lifted bool)
+}
+
+// InFlightCanceller aborts or unbuffers an in-flight message. Optional —
+// when nil DELETE /monitoring/in-flight-messages/{messageId} returns 503.
+type InFlightCanceller interface {
+	CancelInFlight(ctx context.Context, messageID string, action router.CancelAction) router.CancelResult
+}
+
+// PoolOverrideProvider reports runtime pool overrides not yet reconciled
+// with the synced config. Optional.
+type PoolOverrideProvider interface {
+	PoolOverrides() map[string]router.PoolOverride
+}
+
 // PublisherProvider returns the publisher bound to a pool's queue.
 // Used by POST /messages.
 type PublisherProvider interface {
@@ -106,6 +127,13 @@ type TrafficStatusProvider interface {
 	Status() router.TrafficStatus
 }
 
+// ShardStatusProvider exposes this router's shard ownership in sharded
+// mode. Optional — when nil /monitoring/shard-status reports
+// `enabled: false`.
+type ShardStatusProvider interface {
+	Status() router.ShardStatus
+}
+
 // StreamHealth is the projection-level snapshot consumed by the stream
 // health endpoints. Kept package-local so api callers don't need to
 // import internal/stream — fc-server adapts its stream.HealthService
@@ -118,6 +146,10 @@ type StreamHealth struct {
 	BatchSequence  uint64
 	ErrorCount     uint64
 	LastPollTimeMs int64
+	// Backlog and LagSeconds are the projection's last lag probe; nil
+	// when not measured (the fan-out, or before the first probe).
+	Backlog    *int64
+	LagSeconds *float64
 }
 
 // StreamHealthAggregate is the aggregated snapshot.
@@ -137,10 +169,65 @@ type StreamHealthProvider interface {
 	IsReady() bool
 }
 
+// StreamRebuildResult is what a rebuild queued.
+type StreamRebuildResult struct {
+	Projection string
+	Requeued   int64
+}
+
+// ErrProjectionNotRebuildable is wrapped by StreamRebuilder for names it
+// rejects (unknown, or a projection whose replay has side effects); the
+// handler maps it to 400.
+var ErrProjectionNotRebuildable = errors.New("projection not rebuildable")
+
+// StreamRebuilder resets a projection so it replays from its source
+// table. Optional — when nil POST /stream/rebuild returns 503.
+type StreamRebuilder interface {
+	Rebuild(ctx context.Context, projection string) (StreamRebuildResult, error)
+}
+
+// FailoverController reports and flips the multi-region handover record.
+// Optional — when nil the /admin/failover endpoints return 503.
+// Satisfied by *standby.Failover.
+type FailoverController interface {
+	Region() string
+	Status(ctx context.Context) (standby.RegionState, error)
+	Promote(ctx context.Context, by, reason string) (standby.RegionState, error)
+}
+
+// PoisonInspector lists, replays and discards quarantined messages.
+// Optional — when nil the /monitoring/poison-messages endpoints return
+// 503 and no poison counter is exported.
+type PoisonInspector interface {
+	Counts() map[string]uint64
+	List(ctx context.Context, q router.PoisonQuery) ([]router.PoisonMessage, error)
+	Get(ctx context.Context, id string) (router.PoisonMessage, error)
+	Delete(ctx context.Context, id string) error
+	Replay(ctx context.Context, id string, body []byte) (string, error)
+}
+
+// CaptureInspector lists captured mediation traffic and replays it
+// against a staging target. Optional — when nil (capture off) the
+// /monitoring/captures endpoints return 503. Satisfied by
+// *router.TrafficCapture.
+type CaptureInspector interface {
+	Rate() float64
+	Dropped() uint64
+	List(ctx context.Context, q router.CaptureQuery) ([]router.CapturedExchange, error)
+	Get(ctx context.Context, id string) (router.CapturedExchange, error)
+	Replay(ctx context.Context, id string, t router.ReplayTarget) (router.ReplayResult, error)
+}
+
 // ─────────────────────────────────────────────────────────────────────
 // State — bundles every dependency the handlers need.
 // ─────────────────────────────────────────────────────────────────────
 
+// OverloadStatsProvider exposes the mediator's in-flight ceiling and
+// retry budget. Optional.
+type OverloadStatsProvider interface {
+	OverloadStats() router.OverloadStats
+}
+
 // State is the dependency bundle passed to Register. Every field except
 // Warnings/Health is optional; handlers gracefully degrade when a
 // provider is nil (return 503 or an empty payload, matching Rust).
@@ -152,13 +239,22 @@ type State struct {
 	Breakers     BreakerSnapshotProvider
 	InFlight     InFlightSnapshotProvider
 	Mediating    MediatingProvider
+	Canceller    InFlightCanceller
 	BrokerStats  BrokerStatsProvider
 	PoolUpdater  PoolUpdater
+	Overrides    PoolOverrideProvider
+	Quarantiner  PoolQuarantiner
 	Publisher    PublisherProvider
 	Leader       LeaderInfo
 	Reloader     ConfigReloader
 	Traffic      TrafficStatusProvider
 	StreamHealth StreamHealthProvider
+	Rebuilder    StreamRebuilder
+	Failover     FailoverController
+	Shards       ShardStatusProvider
+	Poison       PoisonInspector
+	Capture      CaptureInspector
+	Overload     OverloadStatsProvider
 
 	// Mocks is the counter set for /api/test/*. Created automatically by
 	// FromServer; tests can substitute their own.
@@ -167,7 +263,7 @@ type State struct {
 
 // FromServer builds a fully-populated State from a *router.Server.
 func FromServer(s *router.Server) *State {
-	return &State{
+	st := &State{
 		Warnings:    s.Warnings,
 		Health:      s.Health,
 		PoolStats:   managerPoolStatsAdapter{m: s.Manager},
@@ -175,14 +271,30 @@ func FromServer(s *router.Server) *State {
 		Breakers:    breakerSnapshotAdapter{breakers: s.Breakers},
 		InFlight:    inFlightAdapter{tracker: s.Tracker},
 		Mediating:   managerMediatingAdapter{m: s.Manager},
+		Canceller:   managerMediatingAdapter{m: s.Manager},
 		BrokerStats: brokerStatsAdapter{cache: s.BrokerStats},
 		PoolUpdater: poolUpdaterAdapter{m: s.Manager},
+		Overrides:   poolUpdaterAdapter{m: s.Manager},
+		Quarantiner: poolUpdaterAdapter{m: s.Manager},
 		Publisher:   publisherAdapter{m: s.Manager},
 		Leader:      leaderAdapter{s: s},
 		Reloader:    reloaderAdapter{s: s},
 		Traffic:     trafficAdapter{traffic: s.Traffic},
 		Mocks:       NewMockState(),
 	}
+	if s.Shards != nil {
+		st.Shards = s.Shards
+	}
+	if s.Poison != nil {
+		st.Poison = poisonAdapter{pq: s.Poison, m: s.Manager}
+	}
+	if s.Capture != nil {
+		st.Capture = s.Capture
+	}
+	if hm, ok := s.Mediator.(*router.HTTPMediator); ok {
+		st.Overload = hm
+	}
+	return st
 }
 
 type trafficAdapter struct{ traffic *router.TrafficStrategy }
@@ -194,6 +306,17 @@ func (a trafficAdapter) Status() router.TrafficStatus {
 	return a.traffic.Status()
 }
 
+// HumaConfig is the huma config the router API is served with. Shared
+// with tools/dump-spec so api/router-openapi.lock.json matches the live
+// <prefix>/openapi.json.
+func HumaConfig() huma.Config {
+	cfg := huma.DefaultConfig("FlowCatalyst Router API", Version)
+	// Drop huma's $schema link injection (Rust never emits it), matching
+	// the platform API config.
+	cfg.SchemasPath = ""
+	return cfg
+}
+
 // Register mounts every router endpoint on the supplied huma API.
 // Call MountDashboard separately on the underlying chi router to serve
 // the embedded HTML.
@@ -206,9 +329,13 @@ func Register(api huma.API, s *State) {
 	registerDashboardReads(api, s)
 	registerWarnings(api, s)
 	registerMutations(api, s)
+	registerPools(api, s)
 	registerMessages(api, s)
 	registerMocks(api, s)
 	registerMisc(api, s)
+	registerFailover(api, s)
+	registerPoison(api, s)
+	registerCapture(api, s)
 }
 
 // MountDashboard registers the embedded HTML dashboard on the chi
@@ -241,6 +368,13 @@ func (a managerMediatingAdapter) MediatingSnapshot() []router.MediatingEntry {
 	return a.m.MediatingSnapshot()
 }
 
+func (a managerMediatingAdapter) CancelInFlight(ctx context.Context, id string, action router.CancelAction) router.CancelResult {
+	if a.m == nil {
+		return router.CancelNotFound
+	}
+	return a.m.CancelInFlight(ctx, id, action)
+}
+
 type breakersAdapter struct{ breakers *router.BreakerRegistry }
 
 func (a breakersAdapter) OpenCount() int {
@@ -320,6 +454,20 @@ func (a poolUpdaterAdapter) UpdatePool(code string, concurrency uint32, rate *ui
 	return a.m.UpdatePool(code, concurrency, rate, setRate)
 }
 
+func (a poolUpdaterAdapter) Unquarantine(code string) (bool, bool) {
+	if a.m == nil {
+		return false, false
+	}
+	return a.m.Unquarantine(code)
+}
+
+func (a poolUpdaterAdapter) PoolOverrides() map[string]router.PoolOverride {
+	if a.m == nil {
+		return nil
+	}
+	return a.m.PoolOverrides()
+}
+
 type publisherAdapter struct{ m *router.Manager }
 
 func (a publisherAdapter) Publisher(ctx context.Context, code string) (queue.Publisher, error) {
@@ -329,6 +477,29 @@ func (a publisherAdapter) Publisher(ctx context.Context, code string) (queue.Pub
 	return a.m.Publisher(ctx, code)
 }
 
+// poisonAdapter replays through the Manager's publishers, so a message
+// goes back to the queue config it was consumed from.
+type poisonAdapter struct {
+	pq *router.PoisonQuarantine
+	m  *router.Manager
+}
+
+func (a poisonAdapter) Counts() map[string]uint64 { return a.pq.Counts() }
+
+func (a poisonAdapter) List(ctx context.Context, q router.PoisonQuery) ([]router.PoisonMessage, error) {
+	return a.pq.List(ctx, q)
+}
+
+func (a poisonAdapter) Get(ctx context.Context, id string) (router.PoisonMessage, error) {
+	return a.pq.Get(ctx, id)
+}
+
+func (a poisonAdapter) Delete(ctx context.Context, id string) error { return a.pq.Delete(ctx, id) }
+
+func (a poisonAdapter) Replay(ctx context.Context, id string, body []byte) (string, error) {
+	return a.pq.Replay(ctx, id, body, a.m)
+}
+
 type reloaderAdapter struct{ s *router.Server }
 
 func (a reloaderAdapter) Reload(ctx context.Context) error {
@@ -61,6 +61,8 @@ type stubPoolUpdater struct {
 	lastRate    *uint32
 	lastSetRate bool
 	ok          bool
+	overrides   map[string]router.PoolOverride
+	quarantined map[string]bool
 }
 
 func (s *stubPoolUpdater) UpdatePool(code string, concurrency uint32, rate *uint32, setRate bool) bool {
@@ -71,6 +73,31 @@ func (s *stubPoolUpdater) UpdatePool(code string, concurrency uint32, rate *uint
 	return s.ok
 }
 
+func (s *stubPoolUpdater) PoolOverrides() map[string]router.PoolOverride { return s.overrides }
+
+func (s *stubPoolUpdater) Unquarantine(code string) (bool, bool) {
+	if code != "demo" {
+		return false, false
+	}
+	was := s.quarantined[code]
+	delete(s.quarantined, code)
+	return true, was
+}
+
+// stubCanceller finds only msg-1 (the stub in-flight entry) and records the
+// action it was cancelled with.
+type stubCanceller struct {
+	lastAction router.CancelAction
+}
+
+func (s *stubCanceller) CancelInFlight(_ context.Context, id string, action router.CancelAction) router.CancelResult {
+	if id != "msg-1" {
+		return router.CancelNotFound
+	}
+	s.lastAction = action
+	return router.CancelAborted
+}
+
 type stubPublisher struct {
 	identifier string
 	lastMsg    common.Message
@@ -167,6 +194,9 @@ func setupAPI(t *testing.T) (humatest.TestAPI, *chi.Mux, *stubBreakerSnapshotPro
 		InFlight:    inflight,
 		BrokerStats: bstats,
 		PoolUpdater: updater,
+		Overrides:   updater,
+		Quarantiner: updater,
+		Canceller:   &stubCanceller{},
 		Publisher:   stubPublisherProvider{pub: pub},
 		Leader:      stubLeader{leader: true, standby: false, instanceID: "test"},
 		Mocks:       routerapi.NewMockState(),
@@ -415,6 +445,85 @@ func TestPoolUpdate_NotFound(t *testing.T) {
 	}
 }
 
+func TestRouterPools_ListWithOverride(t *testing.T) {
+	api, _, _, _, updater, _ := setupAPI(t)
+	conc := uint32(4)
+	updater.overrides = map[string]router.PoolOverride{
+		"demo": {Concurrency: &conc, AppliedAt: time.Now()},
+	}
+	resp := api.Get("/pools")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", resp.Code, resp.Body.String())
+	}
+	var pools []routerapi.RouterPool
+	decodeBody(t, resp.Body.Bytes(), &pools)
+	if len(pools) != 1 || pools[0].PoolCode != "demo" || pools[0].ActiveWorkers != 3 {
+		t.Fatalf("pools=%+v", pools)
+	}
+	if o := pools[0].Override; o == nil || o.Concurrency == nil || *o.Concurrency != 4 || o.RateLimitSet {
+		t.Errorf("override=%+v", pools[0].Override)
+	}
+
+	if resp := api.Get("/pools/missing"); resp.Code != http.StatusNotFound {
+		t.Errorf("missing pool: status=%d want 404", resp.Code)
+	}
+}
+
+func TestCancelInFlightMessage(t *testing.T) {
+	api, _, _, _, _, _ := setupAPI(t)
+	resp := api.Delete("/monitoring/in-flight-messages/msg-1?action=dead-letter")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", resp.Code, resp.Body.String())
+	}
+	var out routerapi.InFlightCancelResponse
+	decodeBody(t, resp.Body.Bytes(), &out)
+	if out.MessageID != "msg-1" || out.Result != "ABORTED" || out.Action != "DEAD_LETTER" {
+		t.Errorf("response=%+v", out)
+	}
+
+	if resp := api.Delete("/monitoring/in-flight-messages/gone"); resp.Code != http.StatusNotFound {
+		t.Errorf("unknown message: status=%d want 404", resp.Code)
+	}
+	if resp := api.Delete("/monitoring/in-flight-messages/msg-1?action=drop"); resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("bad action: status=%d want 422", resp.Code)
+	}
+}
+
+func TestRouterPools_ConfigClearsRateLimit(t *testing.T) {
+	api, _, _, _, updater, _ := setupAPI(t)
+	resp := api.Post("/pools/demo/config", map[string]any{"clear_rate_limit": true})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d body=%s", resp.Code, resp.Body.String())
+	}
+	if updater.lastCode != "demo" || !updater.lastSetRate || updater.lastRate != nil || updater.lastConc != 0 {
+		t.Errorf("updater=%+v", updater)
+	}
+
+	resp = api.Post("/pools/demo/config", map[string]any{"clear_rate_limit": true, "rate_limit_per_minute": 10})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("conflicting body: status=%d want 422", resp.Code)
+	}
+}
+
+func TestRouterPools_Unquarantine(t *testing.T) {
+	api, _, _, _, updater, _ := setupAPI(t)
+	updater.quarantined = map[string]bool{"demo": true}
+	var out routerapi.PoolUnquarantineResponse
+	resp := api.Post("/pools/demo/unquarantine")
+	decodeBody(t, resp.Body.Bytes(), &out)
+	if resp.Code != http.StatusOK || !out.WasQuarantined || out.PoolCode != "demo" {
+		t.Fatalf("status %d body=%s", resp.Code, resp.Body.String())
+	}
+	resp = api.Post("/pools/demo/unquarantine")
+	decodeBody(t, resp.Body.Bytes(), &out)
+	if out.WasQuarantined {
+		t.Error("a dispatching pool reports was_quarantined=false")
+	}
+	if resp := api.Post("/pools/missing/unquarantine"); resp.Code != http.StatusNotFound {
+		t.Errorf("missing pool: status=%d want 404", resp.Code)
+	}
+}
+
 func TestBrokerStatsRefresh(t *testing.T) {
 	api, _, _, bstats, _, _ := setupAPI(t)
 	resp := api.Post("/monitoring/broker-stats/refresh")
@@ -595,6 +704,44 @@ func TestWarnings_AckLifecycle(t *testing.T) {
 	_ = api // setup helper not used in this sub-test
 }
 
+func TestWarnings_ResolveAndHistory(t *testing.T) {
+	ws := router.NewWarningService(router.WarningServiceConfig{})
+	hs := router.NewHealthService(router.DefaultHealthServiceConfig(), ws)
+	slo := ws.Add(router.WarningCategorySLO, router.WarningCritical, "slow", "x")
+	ws.Add(router.WarningCategoryStall, router.WarningWarning, "stuck", "x")
+
+	_, api := humatest.New(t)
+	routerapi.Register(api, &routerapi.State{
+		Warnings: ws, Health: hs, Mocks: routerapi.NewMockState(),
+	})
+
+	if resp := api.Post("/monitoring/warnings/" + slo + "/ack"); resp.Code != http.StatusOK {
+		t.Fatalf("ack: status %d body=%s", resp.Code, resp.Body.String())
+	}
+	resp := api.Get("/monitoring/warnings/history?category=slo&acknowledged=true")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("history: status %d body=%s", resp.Code, resp.Body.String())
+	}
+	var hist []routerapi.WireWarning
+	decodeBody(t, resp.Body.Bytes(), &hist)
+	if len(hist) != 1 || hist[0].ID != slo {
+		t.Fatalf("history: got %+v", hist)
+	}
+
+	resp = api.Post("/monitoring/warnings/" + slo + "/resolve")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("resolve: status %d body=%s", resp.Code, resp.Body.String())
+	}
+	var body routerapi.ResolvedResponse
+	decodeBody(t, resp.Body.Bytes(), &body)
+	if !body.Resolved || ws.Count() != 1 {
+		t.Fatalf("resolve: body %+v, %d warnings left", body, ws.Count())
+	}
+	if resp := api.Post("/monitoring/warnings/" + slo + "/resolve"); resp.Code != http.StatusNotFound {
+		t.Fatalf("re-resolve: status %d want 404", resp.Code)
+	}
+}
+
 // ── Dashboard HTML ───────────────────────────────────────────────────────
 
 func TestDashboardHTML_ServesEmbedded(t *testing.T) {
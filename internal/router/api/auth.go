@@ -36,6 +36,7 @@ var publicPaths = map[string]struct{}{
 	"/openapi-3.0.yaml": {},
 	"/openapi-3.1.json": {},
 	"/openapi-3.1.yaml": {},
+	"/swagger":          {},
 }
 
 // publicPrefixes covers paths where any subroute is public — the docs
@@ -103,6 +103,7 @@ func TestIsPublicPath(t *testing.T) {
 		"/openapi.json":        true,
 		"/docs":                true,
 		"/docs/swagger-ui.css": true,
+		"/swagger":             true,
 		"/messages":            false,
 		"/monitoring/pools":    false,
 		"/docsy":               false, // not a prefix match
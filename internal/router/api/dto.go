@@ -22,12 +22,17 @@ type ProbeResponse struct {
 	Status string `json:"status"`
 }
 
-// SimpleHealthResponse is the legacy /health summary.
+// SimpleHealthResponse is the /health (and /q/health) summary. Status is
+// HEALTHY, WARNING, DEGRADED or DOWN — the worst of the HealthReport
+// verdict and the per-component checks. Details carries the same shape
+// as /monitoring/health so the dashboard modal can render either.
 type SimpleHealthResponse struct {
-	Status           string `json:"status"`
-	Version          string `json:"version"`
-	ActiveWarnings   uint32 `json:"active_warnings"`
-	CriticalWarnings uint32 `json:"critical_warnings"`
+	Status           string                   `json:"status"`
+	Version          string                   `json:"version"`
+	ActiveWarnings   uint32                   `json:"active_warnings"`
+	CriticalWarnings uint32                   `json:"critical_warnings"`
+	Details          *DashboardHealthDetails  `json:"details,omitempty"`
+	Components       []router.ComponentHealth `json:"components,omitempty"`
 }
 
 // ── Monitoring overview ──────────────────────────────────────────────────
@@ -81,9 +86,35 @@ type WirePoolStats struct {
 	MessageGroupCount  uint32                      `json:"message_group_count"`
 	RateLimitPerMinute *uint32                     `json:"rate_limit_per_minute,omitempty"`
 	IsRateLimited      bool                        `json:"is_rate_limited"`
+	Adaptive           *WireAdaptiveStatus         `json:"adaptive,omitempty"`
+	Quarantine         *WireQuarantineStatus       `json:"quarantine,omitempty"`
 	Metrics            *common.EnhancedPoolMetrics `json:"metrics,omitempty"`
 }
 
+// WireAdaptiveStatus is an adaptive pool's bounds and resize counts.
+// paused = held by a runtime concurrency override until the next sync.
+type WireAdaptiveStatus struct {
+	MinConcurrency uint32 `json:"min_concurrency"`
+	MaxConcurrency uint32 `json:"max_concurrency"`
+	Paused         bool   `json:"paused"`
+	Increases      uint64 `json:"increases"`
+	Decreases      uint64 `json:"decreases"`
+}
+
+// WireQuarantineStatus is a pool's auto-quarantine config and state;
+// since / until / reason are set while it is quarantined.
+type WireQuarantineStatus struct {
+	Quarantined     bool       `json:"quarantined"`
+	Since           *time.Time `json:"since,omitempty"`
+	Until           *time.Time `json:"until,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	Trips           uint64     `json:"trips"`
+	FailureRate     float64    `json:"failure_rate"`
+	MinDeliveries   uint32     `json:"min_deliveries"`
+	WindowSeconds   uint32     `json:"window_seconds"`
+	CooldownSeconds uint32     `json:"cooldown_seconds"`
+}
+
 func fromPoolStats(s []router.PoolStats) []WirePoolStats {
 	out := make([]WirePoolStats, len(s))
 	for i, p := range s {
@@ -98,10 +129,56 @@ func fromPoolStats(s []router.PoolStats) []WirePoolStats {
 			IsRateLimited:      p.IsRateLimited,
 			Metrics:            p.Metrics,
 		}
+		if a := p.Adaptive; a != nil {
+			out[i].Adaptive = &WireAdaptiveStatus{
+				MinConcurrency: a.MinConcurrency,
+				MaxConcurrency: a.MaxConcurrency,
+				Paused:         a.Paused,
+				Increases:      a.Increases,
+				Decreases:      a.Decreases,
+			}
+		}
+		if q := p.Quarantine; q != nil {
+			out[i].Quarantine = &WireQuarantineStatus{
+				Quarantined:     q.Quarantined,
+				Since:           q.Since,
+				Until:           q.Until,
+				Reason:          q.Reason,
+				Trips:           q.Trips,
+				FailureRate:     q.FailureRate,
+				MinDeliveries:   q.MinDeliveries,
+				WindowSeconds:   q.WindowSeconds,
+				CooldownSeconds: q.CooldownSeconds,
+			}
+		}
 	}
 	return out
 }
 
+// PoolUnquarantineResponse is the body for POST
+// /pools/{poolCode}/unquarantine. was_quarantined is false when the pool
+// was already dispatching.
+type PoolUnquarantineResponse struct {
+	PoolCode       string `json:"pool_code"`
+	WasQuarantined bool   `json:"was_quarantined"`
+}
+
+// RouterPool is one pool on GET /pools: its live stats plus the
+// runtime override, if any, that the next config sync will reconcile.
+type RouterPool struct {
+	WirePoolStats
+	Override *WirePoolOverride `json:"override,omitempty"`
+}
+
+// WirePoolOverride is a runtime tuning change pending reconciliation with
+// the synced config. Nil fields were left unchanged.
+type WirePoolOverride struct {
+	Concurrency        *uint32   `json:"concurrency,omitempty"`
+	RateLimitSet       bool      `json:"rate_limit_set"`
+	RateLimitPerMinute *uint32   `json:"rate_limit_per_minute,omitempty"`
+	AppliedAt          time.Time `json:"applied_at"`
+}
+
 // ── Dashboard health (/monitoring/health) ────────────────────────────────
 
 // DashboardHealthResponse mirrors Rust DashboardHealthResponse. Top
@@ -124,6 +201,9 @@ type DashboardHealthDetails struct {
 	CriticalWarnings    uint32  `json:"criticalWarnings"`
 	CircuitBreakersOpen uint32  `json:"circuitBreakersOpen"`
 	DegradationReason   *string `json:"degradationReason,omitempty"`
+	// Components is the per-check breakdown (latency, failure streak,
+	// UP/DEGRADED/DOWN) behind the aggregate status.
+	Components []router.ComponentHealth `json:"components,omitempty"`
 }
 
 // ── Dashboard pool / queue / circuit-breaker / in-flight stats ───────────
@@ -220,6 +300,16 @@ type InFlightCheckResponse struct {
 	QueueID    string `json:"queueId,omitempty"`
 }
 
+// InFlightCancelResponse is the body for DELETE
+// /monitoring/in-flight-messages/{messageId}. Result is ABORTED (the
+// delivery was cut off; the action applies as the worker unwinds) or
+// REMOVED (taken out of its group buffer before dispatch).
+type InFlightCancelResponse struct {
+	MessageID string `json:"messageId"`
+	Result    string `json:"result"`
+	Action    string `json:"action"`
+}
+
 // InFlightCheckBatchRequest is the body for the batch in-flight check.
 type InFlightCheckBatchRequest struct {
 	MessageIDs []string `json:"messageIds"`
@@ -267,6 +357,7 @@ type WireWarning struct {
 	CreatedAt      time.Time  `json:"created_at"`
 	Acknowledged   bool       `json:"acknowledged"`
 	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
 }
 
 func fromWarning(w router.Warning) WireWarning {
@@ -279,6 +370,7 @@ func fromWarning(w router.Warning) WireWarning {
 		CreatedAt:      w.CreatedAt,
 		Acknowledged:   w.Acknowledged,
 		AcknowledgedAt: w.AcknowledgedAt,
+		ResolvedAt:     w.ResolvedAt,
 	}
 }
 
@@ -295,6 +387,11 @@ type CountResponse struct {
 	Cleared uint64 `json:"cleared"`
 }
 
+// ResolvedResponse is the body for single-warning resolution.
+type ResolvedResponse struct {
+	Resolved bool `json:"resolved"`
+}
+
 // AcknowledgedResponse is the body for single-warning acknowledgement.
 type AcknowledgedResponse struct {
 	Acknowledged bool `json:"acknowledged"`
@@ -307,11 +404,14 @@ type AcknowledgedCountResponse struct {
 
 // ── Mutations: PUT pool, broker refresh, breaker reset ───────────────────
 
-// PoolConfigUpdateRequest is the body for PUT /monitoring/pools/{poolCode}.
-// Both fields optional; omitting a field leaves the knob unchanged.
+// PoolConfigUpdateRequest is the body for PUT /monitoring/pools/{poolCode}
+// and POST /pools/{poolCode}/config. Every field optional; omitting
+// a field leaves the knob unchanged. ClearRateLimit removes the pool's
+// rate limit.
 type PoolConfigUpdateRequest struct {
-	Concurrency        *uint32 `json:"concurrency,omitempty"`
+	Concurrency        *uint32 `json:"concurrency,omitempty" doc:"New worker limit. On an adaptive pool this also pauses adaptation until the next config sync"`
 	RateLimitPerMinute *uint32 `json:"rate_limit_per_minute,omitempty"`
+	ClearRateLimit     bool    `json:"clear_rate_limit,omitempty"`
 }
 
 // PoolConfigUpdateResponse describes the applied update.
@@ -441,6 +541,39 @@ type TrafficStatusResponse struct {
 	LastError     string `json:"lastError,omitempty"`
 }
 
+// ShardStatusResponse is the body for /monitoring/shard-status. Owned
+// are the shards whose ordered groups this instance consumes; Releasing
+// were handed to another member and are draining here.
+type ShardStatusResponse struct {
+	Enabled    bool     `json:"enabled"`
+	InstanceID string   `json:"instanceId,omitempty"`
+	Shards     int      `json:"shards"`
+	Members    []string `json:"members"`
+	Owned      []int    `json:"owned"`
+	Releasing  []int    `json:"releasing"`
+}
+
+// FailoverStatusResponse is the body for /admin/failover and its promote.
+// Serving is whether this instance's region may lead right now — false
+// for the passive region and, after a promotion, until effectiveAt.
+type FailoverStatusResponse struct {
+	Region       string `json:"region"`
+	ActiveRegion string `json:"activeRegion"`
+	Previous     string `json:"previousRegion,omitempty"`
+	Epoch        int64  `json:"epoch"`
+	Serving      bool   `json:"serving"`
+	EffectiveAt  string `json:"effectiveAt,omitempty"`
+	PromotedAt   string `json:"promotedAt,omitempty"`
+	PromotedBy   string `json:"promotedBy,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// FailoverPromoteRequest is the body for POST /admin/failover/promote.
+type FailoverPromoteRequest struct {
+	Reason      string `json:"reason,omitempty" doc:"Why the region is being promoted; recorded on the handover record"`
+	RequestedBy string `json:"requestedBy,omitempty" doc:"Operator name recorded on the handover record (default \"api\")"`
+}
+
 // StreamHealthResponse is the body for /monitoring/stream-health. When
 // no StreamHealthProvider is wired the response is `enabled: false`
 // and `status: NOT_CONFIGURED`; otherwise the live aggregate from the
@@ -457,16 +590,132 @@ type StreamHealthResponse struct {
 
 // StreamProjectionHealth is one row in StreamHealthResponse.Streams.
 type StreamProjectionHealth struct {
-	Name           string `json:"name"`
-	Status         string `json:"status"`
-	Running        bool   `json:"running"`
-	Healthy        bool   `json:"healthy"`
-	BatchSequence  uint64 `json:"batchSequence"`
-	ErrorCount     uint64 `json:"errorCount"`
-	LastPollTimeMs int64  `json:"lastPollTimeMs"`
+	Name           string   `json:"name"`
+	Status         string   `json:"status"`
+	Running        bool     `json:"running"`
+	Healthy        bool     `json:"healthy"`
+	BatchSequence  uint64   `json:"batchSequence"`
+	ErrorCount     uint64   `json:"errorCount"`
+	LastPollTimeMs int64    `json:"lastPollTimeMs"`
+	Backlog        *int64   `json:"backlog,omitempty" doc:"Source rows still to project, capped at 10000"`
+	LagSeconds     *float64 `json:"lagSeconds,omitempty" doc:"Age of the oldest source row still to project"`
+}
+
+// StreamRebuildResponse is the body for POST /stream/rebuild. Requeued
+// is the number of source rows the projector will replay.
+type StreamRebuildResponse struct {
+	Projection string `json:"projection"`
+	Requeued   int64  `json:"requeued"`
 }
 
 // StreamProbeResponse is the body for /monitoring/stream-health/{live,ready}.
 type StreamProbeResponse struct {
 	Status string `json:"status"`
 }
+
+// PoisonMessagesResponse is the body for GET /monitoring/poison-messages,
+// most recently seen first.
+type PoisonMessagesResponse struct {
+	Messages []PoisonMessageSummary `json:"messages"`
+	Total    int                    `json:"total"`
+}
+
+// PoisonMessageSummary is one quarantined message without its body.
+// Count is how often the same body arrived on the queue; Size is the
+// stored sample's length.
+type PoisonMessageSummary struct {
+	ID              string `json:"id"`
+	Queue           string `json:"queue"`
+	QueueIdentifier string `json:"queueIdentifier"`
+	Subject         string `json:"subject,omitempty"`
+	BrokerMessageID string `json:"brokerMessageId,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	Error           string `json:"error"`
+	Size            int    `json:"size"`
+	Truncated       bool   `json:"truncated"`
+	Count           uint64 `json:"count"`
+	FirstSeenAt     string `json:"firstSeenAt"`
+	LastSeenAt      string `json:"lastSeenAt"`
+}
+
+// PoisonMessageResponse is the body for GET /monitoring/poison-messages/{id}.
+// Body is the raw payload as received (still base64 when contentEncoding
+// is set).
+type PoisonMessageResponse struct {
+	PoisonMessageSummary
+	Body string `json:"body"`
+}
+
+// PoisonDeletedResponse is the body for DELETE /monitoring/poison-messages/{id}.
+type PoisonDeletedResponse struct {
+	Deleted bool `json:"deleted"`
+}
+
+// PoisonReplayRequest is the optional body for
+// POST /monitoring/poison-messages/{id}/replay.
+type PoisonReplayRequest struct {
+	Body string `json:"body,omitempty" doc:"Corrected message pointer JSON to publish instead of the stored body"`
+}
+
+// PoisonReplayResponse is the body for a successful replay. The message
+// has left quarantine.
+type PoisonReplayResponse struct {
+	ID        string `json:"id"`
+	PublishID string `json:"publishId"`
+}
+
+// CapturesResponse is the body for GET /monitoring/captures, newest
+// first. Dropped counts captures lost to a full write queue since start.
+type CapturesResponse struct {
+	Captures   []CaptureSummary `json:"captures"`
+	Total      int              `json:"total"`
+	SampleRate float64          `json:"sampleRate"`
+	Dropped    uint64           `json:"dropped"`
+}
+
+// CaptureSummary is one captured exchange without its headers and
+// bodies. ResponseStatus is 0 when no response arrived (see error).
+type CaptureSummary struct {
+	ID                string `json:"id"`
+	MessageID         string `json:"messageId"`
+	PoolCode          string `json:"poolCode,omitempty"`
+	SubscriptionID    string `json:"subscriptionId,omitempty"`
+	Method            string `json:"method"`
+	Target            string `json:"target"`
+	ResponseStatus    int    `json:"responseStatus"`
+	Outcome           string `json:"outcome"`
+	Error             string `json:"error,omitempty"`
+	RequestTruncated  bool   `json:"requestTruncated"`
+	ResponseTruncated bool   `json:"responseTruncated"`
+	DurationMs        int64  `json:"durationMs"`
+	CapturedAt        string `json:"capturedAt"`
+}
+
+// CaptureResponse is the body for GET /monitoring/captures/{id}.
+// Secret headers and JSON fields read "[REDACTED]".
+type CaptureResponse struct {
+	CaptureSummary
+	RequestHeaders  map[string]string `json:"requestHeaders"`
+	RequestBody     string            `json:"requestBody"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	ResponseBody    string            `json:"responseBody,omitempty"`
+}
+
+// CaptureReplayRequest is the body for
+// POST /monitoring/captures/{id}/replay.
+type CaptureReplayRequest struct {
+	Target        string `json:"target" doc:"Staging URL to send the captured request to; the original receiver is refused"`
+	SigningSecret string `json:"signingSecret,omitempty" doc:"Re-sign the request with this secret (the captured signature was redacted)"`
+	AuthToken     string `json:"authToken,omitempty" doc:"Send this bearer token (the captured one was redacted)"`
+}
+
+// CaptureReplayResponse is the staging target's answer to a replay.
+type CaptureReplayResponse struct {
+	ID         string            `json:"id"`
+	Target     string            `json:"target"`
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+	Truncated  bool              `json:"truncated"`
+	DurationMs int64             `json:"durationMs"`
+}
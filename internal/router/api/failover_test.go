@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	routerapi "github.com/flowcatalyst/flowcatalyst-go/internal/router/api"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/standby"
+)
+
+type stubFailover struct {
+	region string
+	state  standby.RegionState
+	err    error
+}
+
+func (f *stubFailover) Region() string { return f.region }
+
+func (f *stubFailover) Status(context.Context) (standby.RegionState, error) { return f.state, nil }
+
+func (f *stubFailover) Promote(_ context.Context, by, reason string) (standby.RegionState, error) {
+	if f.err != nil {
+		return standby.RegionState{}, f.err
+	}
+	f.state = standby.RegionState{
+		Active: f.region, Previous: f.state.Active, Epoch: f.state.Epoch + 1,
+		EffectiveAt: time.Now().Add(30 * time.Second), PromotedAt: time.Now(), PromotedBy: by, Reason: reason,
+	}
+	return f.state, nil
+}
+
+func TestFailoverPromote(t *testing.T) {
+	fo := &stubFailover{region: "us-east-1", state: standby.RegionState{Active: "eu-west-1", Epoch: 1}}
+	_, api := humatest.New(t)
+	routerapi.Register(api, &routerapi.State{Failover: fo})
+
+	resp := api.Get("/admin/failover")
+	var st routerapi.FailoverStatusResponse
+	decodeBody(t, resp.Body.Bytes(), &st)
+	if resp.Code != http.StatusOK || st.ActiveRegion != "eu-west-1" || st.Serving {
+		t.Fatalf("status %d %+v", resp.Code, st)
+	}
+
+	resp = api.Post("/admin/failover/promote", map[string]any{"reason": "eu outage", "requestedBy": "oncall"})
+	decodeBody(t, resp.Body.Bytes(), &st)
+	if resp.Code != http.StatusOK || st.ActiveRegion != "us-east-1" || st.Previous != "eu-west-1" ||
+		st.Epoch != 2 || st.PromotedBy != "oncall" || st.EffectiveAt == "" {
+		t.Fatalf("promote %d %+v", resp.Code, st)
+	}
+	if st.Serving {
+		t.Error("the promoted region serves only after the handover window")
+	}
+
+	fo.err = standby.ErrPromoteConflict
+	if resp := api.Post("/admin/failover/promote", map[string]any{}); resp.Code != http.StatusConflict {
+		t.Errorf("conflict: got %d", resp.Code)
+	}
+}
+
+func TestFailoverNotConfigured(t *testing.T) {
+	_, api := humatest.New(t)
+	routerapi.Register(api, &routerapi.State{})
+	if resp := api.Post("/admin/failover/promote", map[string]any{}); resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d", resp.Code)
+	}
+}
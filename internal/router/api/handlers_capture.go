@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+)
+
+func registerCapture(api huma.API, s *State) {
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringCaptures", Method: http.MethodGet, Path: "/monitoring/captures",
+		Summary: "Sampled outbound mediation requests and their responses, newest first", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.listCaptures)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringCapture", Method: http.MethodGet, Path: "/monitoring/captures/{id}",
+		Summary: "One captured exchange, with its headers and bodies", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.getCapture)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringReplayCapture", Method: http.MethodPost, Path: "/monitoring/captures/{id}/replay",
+		Summary: "Resend a captured request to a staging target", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.replayCapture)
+}
+
+type listCapturesInput struct {
+	MessageID string `query:"messageId" doc:"Only captures of this message"`
+	Limit     int    `query:"limit" doc:"Maximum entries returned (default 100)"`
+}
+
+type listCapturesOutput struct {
+	Body CapturesResponse
+}
+
+func (s *State) listCaptures(ctx context.Context, in *listCapturesInput) (*listCapturesOutput, error) {
+	if s.Capture == nil {
+		return nil, notConfigured("traffic capture")
+	}
+	exs, err := s.Capture.List(ctx, router.CaptureQuery{MessageID: in.MessageID, Limit: in.Limit})
+	if err != nil {
+		return nil, huma.Error503ServiceUnavailable("list captures: " + err.Error())
+	}
+	out := CapturesResponse{
+		Captures:   make([]CaptureSummary, 0, len(exs)),
+		SampleRate: s.Capture.Rate(),
+		Dropped:    s.Capture.Dropped(),
+	}
+	for _, ex := range exs {
+		out.Captures = append(out.Captures, captureSummary(ex))
+	}
+	out.Total = len(out.Captures)
+	return &listCapturesOutput{Body: out}, nil
+}
+
+type captureIDInput struct {
+	ID string `path:"id"`
+}
+
+type captureOutput struct {
+	Body CaptureResponse
+}
+
+func (s *State) getCapture(ctx context.Context, in *captureIDInput) (*captureOutput, error) {
+	if s.Capture == nil {
+		return nil, notConfigured("traffic capture")
+	}
+	ex, err := s.Capture.Get(ctx, in.ID)
+	if err != nil {
+		return nil, captureError(err, in.ID)
+	}
+	return &captureOutput{Body: CaptureResponse{
+		CaptureSummary:  captureSummary(ex),
+		RequestHeaders:  ex.RequestHeaders,
+		RequestBody:     string(ex.RequestBody),
+		ResponseHeaders: ex.ResponseHeaders,
+		ResponseBody:    string(ex.ResponseBody),
+	}}, nil
+}
+
+type replayCaptureInput struct {
+	ID   string `path:"id"`
+	Body CaptureReplayRequest
+}
+
+type replayCaptureOutput struct {
+	Body CaptureReplayResponse
+}
+
+func (s *State) replayCapture(ctx context.Context, in *replayCaptureInput) (*replayCaptureOutput, error) {
+	if s.Capture == nil {
+		return nil, notConfigured("traffic capture")
+	}
+	res, err := s.Capture.Replay(ctx, in.ID, router.ReplayTarget{
+		URL:           in.Body.Target,
+		SigningSecret: in.Body.SigningSecret,
+		AuthToken:     in.Body.AuthToken,
+	})
+	if err != nil {
+		return nil, captureError(err, in.ID)
+	}
+	return &replayCaptureOutput{Body: CaptureReplayResponse{
+		ID:         in.ID,
+		Target:     in.Body.Target,
+		Status:     res.Status,
+		Headers:    res.Headers,
+		Body:       string(res.Body),
+		Truncated:  res.Truncated,
+		DurationMs: res.DurationMs,
+	}}, nil
+}
+
+// captureError maps capture errors: unknown ID → 404, an unusable
+// replay target → 422, anything else (store, or the target unreachable)
+// → 503.
+func captureError(err error, id string) error {
+	switch {
+	case errors.Is(err, router.ErrCaptureNotFound):
+		return huma.Error404NotFound("capture not found: " + id)
+	case errors.Is(err, router.ErrCaptureReplayTarget):
+		return huma.Error422UnprocessableEntity(err.Error())
+	default:
+		return huma.Error503ServiceUnavailable(err.Error())
+	}
+}
+
+func captureSummary(ex router.CapturedExchange) CaptureSummary {
+	return CaptureSummary{
+		ID:                ex.ID,
+		MessageID:         ex.MessageID,
+		PoolCode:          ex.PoolCode,
+		SubscriptionID:    ex.SubscriptionID,
+		Method:            ex.Method,
+		Target:            ex.Target,
+		ResponseStatus:    ex.ResponseStatus,
+		Outcome:           ex.Outcome,
+		Error:             ex.Error,
+		RequestTruncated:  ex.RequestTruncated,
+		ResponseTruncated: ex.ResponseTruncated,
+		DurationMs:        ex.DurationMs,
+		CapturedAt:        ex.CapturedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/standby"
+)
+
+const tagFailover = "failover"
+
+func registerFailover(api huma.API, s *State) {
+	huma.Register(api, huma.Operation{
+		OperationID: "failoverStatus", Method: http.MethodGet, Path: "/admin/failover",
+		Summary: "Multi-region active/passive status", Tags: []string{tagFailover}, DefaultStatus: http.StatusOK,
+	}, s.failoverStatus)
+	huma.Register(api, huma.Operation{
+		OperationID: "failoverPromote", Method: http.MethodPost, Path: "/admin/failover/promote",
+		Summary: "Promote this instance's region to active", Tags: []string{tagFailover}, DefaultStatus: http.StatusOK,
+	}, s.failoverPromote)
+}
+
+type failoverOutput struct {
+	Body FailoverStatusResponse
+}
+
+func (s *State) failoverStatus(ctx context.Context, _ *emptyInput) (*failoverOutput, error) {
+	if s.Failover == nil {
+		return nil, notConfigured("region failover")
+	}
+	st, err := s.Failover.Status(ctx)
+	if err != nil {
+		return nil, huma.Error503ServiceUnavailable("read region handover: " + err.Error())
+	}
+	return &failoverOutput{Body: failoverResponse(s.Failover.Region(), st)}, nil
+}
+
+type failoverPromoteInput struct {
+	Body FailoverPromoteRequest
+}
+
+func (s *State) failoverPromote(ctx context.Context, in *failoverPromoteInput) (*failoverOutput, error) {
+	if s.Failover == nil {
+		return nil, notConfigured("region failover")
+	}
+	by := in.Body.RequestedBy
+	if by == "" {
+		by = "api"
+	}
+	st, err := s.Failover.Promote(ctx, by, in.Body.Reason)
+	if errors.Is(err, standby.ErrPromoteConflict) {
+		return nil, huma.Error409Conflict("another promotion is in progress; re-read /admin/failover")
+	}
+	if err != nil {
+		return nil, huma.Error503ServiceUnavailable("promote region: " + err.Error())
+	}
+	slog.Warn("region promoted to active", "region", st.Active, "previous", st.Previous,
+		"epoch", st.Epoch, "effective_at", st.EffectiveAt, "by", by, "reason", in.Body.Reason)
+	return &failoverOutput{Body: failoverResponse(s.Failover.Region(), st)}, nil
+}
+
+func failoverResponse(region string, st standby.RegionState) FailoverStatusResponse {
+	out := FailoverStatusResponse{
+		Region:       region,
+		ActiveRegion: st.Active,
+		Previous:     st.Previous,
+		Epoch:        st.Epoch,
+		Serving:      st.Serving(region, time.Now()),
+		PromotedBy:   st.PromotedBy,
+		Reason:       st.Reason,
+	}
+	if !st.EffectiveAt.IsZero() {
+		out.EffectiveAt = st.EffectiveAt.UTC().Format(time.RFC3339)
+	}
+	if !st.PromotedAt.IsZero() {
+		out.PromotedAt = st.PromotedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sort"
 	"strings"
@@ -46,11 +47,14 @@ type healthOutput struct {
 
 func (s *State) health(_ context.Context, _ *emptyInput) (*healthOutput, error) {
 	report := s.Health.HealthReport(s.poolStatsSnap())
+	components := s.runComponentChecks(report)
 	return &healthOutput{Body: SimpleHealthResponse{
-		Status:           statusString(report.Status),
+		Status:           overallStatus(report.Status, components),
 		Version:          Version,
 		ActiveWarnings:   report.ActiveWarnings,
 		CriticalWarnings: report.CriticalWarnings,
+		Details:          s.healthDetails(report, components),
+		Components:       components,
 	}}, nil
 }
 
@@ -121,31 +125,13 @@ type dashboardHealthOutput struct {
 }
 
 func (s *State) dashboardHealth(_ context.Context, _ *emptyInput) (*dashboardHealthOutput, error) {
-	stats := s.poolStatsSnap()
-	report := s.Health.HealthReport(stats)
-	var degradationReason *string
-	if len(report.Issues) > 0 {
-		joined := strings.Join(report.Issues, "; ")
-		degradationReason = &joined
-	}
-	breakersOpen := uint32(0)
-	if s.OpenCount != nil {
-		breakersOpen = uint32(s.OpenCount.OpenCount())
-	}
+	report := s.Health.HealthReport(s.poolStatsSnap())
+	components := s.runComponentChecks(report)
 	return &dashboardHealthOutput{Body: DashboardHealthResponse{
-		Status:       statusString(report.Status),
+		Status:       overallStatus(report.Status, components),
 		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
 		UptimeMillis: time.Since(startTime).Milliseconds(),
-		Details: &DashboardHealthDetails{
-			TotalQueues:         report.ConsumersHealthy + report.ConsumersUnhealthy,
-			HealthyQueues:       report.ConsumersHealthy,
-			TotalPools:          report.PoolsHealthy + report.PoolsUnhealthy,
-			HealthyPools:        report.PoolsHealthy,
-			ActiveWarnings:      report.ActiveWarnings,
-			CriticalWarnings:    report.CriticalWarnings,
-			CircuitBreakersOpen: breakersOpen,
-			DegradationReason:   degradationReason,
-		},
+		Details:      s.healthDetails(report, components),
 	}}, nil
 }
 
@@ -196,6 +182,71 @@ func (s *State) poolStatsSnap() []router.PoolStats {
 	return s.PoolStats.PoolStats()
 }
 
+// runComponentChecks runs the report-derived checks plus the stream
+// processor check when a provider is wired.
+func (s *State) runComponentChecks(report router.HealthReport) []router.ComponentHealth {
+	checks := router.ReportChecks(report)
+	if s.StreamHealth != nil {
+		checks = append(checks, router.ComponentCheck{Name: "stream", Check: s.streamCheck})
+	}
+	return s.Health.RunChecks(checks)
+}
+
+// streamCheck is DOWN when the processor isn't running at all and
+// DEGRADED when it runs but some projections are unhealthy or lagging.
+func (s *State) streamCheck() (router.ComponentStatus, string) {
+	if !s.StreamHealth.IsLive() {
+		return router.ComponentDown, "stream processor not running"
+	}
+	agg := s.StreamHealth.Aggregate()
+	if agg.UnhealthyStreams > 0 || !s.StreamHealth.IsReady() {
+		return router.ComponentDegraded, fmt.Sprintf("%d of %d projections unhealthy", agg.UnhealthyStreams, agg.TotalStreams)
+	}
+	return router.ComponentUp, ""
+}
+
+func (s *State) healthDetails(report router.HealthReport, components []router.ComponentHealth) *DashboardHealthDetails {
+	issues := report.Issues
+	for _, c := range components {
+		if c.Name == "stream" && c.Status != router.ComponentUp {
+			issues = append(issues, "Stream: "+c.Detail)
+		}
+	}
+	var degradationReason *string
+	if len(issues) > 0 {
+		joined := strings.Join(issues, "; ")
+		degradationReason = &joined
+	}
+	breakersOpen := uint32(0)
+	if s.OpenCount != nil {
+		breakersOpen = uint32(s.OpenCount.OpenCount())
+	}
+	return &DashboardHealthDetails{
+		TotalQueues:         report.ConsumersHealthy + report.ConsumersUnhealthy,
+		HealthyQueues:       report.ConsumersHealthy,
+		TotalPools:          report.PoolsHealthy + report.PoolsUnhealthy,
+		HealthyPools:        report.PoolsHealthy,
+		ActiveWarnings:      report.ActiveWarnings,
+		CriticalWarnings:    report.CriticalWarnings,
+		CircuitBreakersOpen: breakersOpen,
+		DegradationReason:   degradationReason,
+		Components:          components,
+	}
+}
+
+// overallStatus folds the component verdicts into the report status:
+// any DOWN component reports DOWN, any DEGRADED one lifts a
+// HEALTHY/WARNING report to DEGRADED.
+func overallStatus(status router.HealthStatus, components []router.ComponentHealth) string {
+	switch router.AggregateComponentStatus(components) {
+	case router.ComponentDown:
+		return "DOWN"
+	case router.ComponentDegraded:
+		return "DEGRADED"
+	}
+	return statusString(status)
+}
+
 func statusString(s router.HealthStatus) string {
 	switch s {
 	case router.HealthHealthy:
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -39,6 +40,10 @@ func registerMisc(api huma.API, s *State) {
 		OperationID: "trafficStatus", Method: http.MethodGet, Path: "/monitoring/traffic-status",
 		Summary: "Traffic management status", Tags: []string{tagStandby}, DefaultStatus: http.StatusOK,
 	}, s.trafficStatus)
+	huma.Register(api, huma.Operation{
+		OperationID: "shardStatus", Method: http.MethodGet, Path: "/monitoring/shard-status",
+		Summary: "Sharded consumption status", Tags: []string{tagStandby}, DefaultStatus: http.StatusOK,
+	}, s.shardStatus)
 
 	// Stream health. When the router is co-tenanted with the stream
 	// processor (fc-server) a StreamHealthProvider is wired into State
@@ -56,6 +61,10 @@ func registerMisc(api huma.API, s *State) {
 		OperationID: "streamReadiness", Method: http.MethodGet, Path: "/monitoring/stream-health/ready",
 		Summary: "Stream processor readiness", Tags: []string{tagStream}, DefaultStatus: http.StatusOK,
 	}, s.streamReadiness)
+	huma.Register(api, huma.Operation{
+		OperationID: "streamRebuild", Method: http.MethodPost, Path: "/stream/rebuild",
+		Summary: "Rebuild a projection from its source table", Tags: []string{tagStream}, DefaultStatus: http.StatusAccepted,
+	}, s.streamRebuild)
 }
 
 type streamHealthOutput struct {
@@ -255,3 +264,61 @@ func (s *State) trafficStatus(_ context.Context, _ *emptyInput) (*trafficStatusO
 	}
 	return &trafficStatusOutput{Body: resp}, nil
 }
+
+type shardStatusOutput struct {
+	Body ShardStatusResponse
+}
+
+func (s *State) shardStatus(_ context.Context, _ *emptyInput) (*shardStatusOutput, error) {
+	if s.Shards == nil {
+		return &shardStatusOutput{Body: ShardStatusResponse{Owned: []int{}, Releasing: []int{}, Members: []string{}}}, nil
+	}
+	st := s.Shards.Status()
+	resp := ShardStatusResponse{
+		Enabled:    true,
+		InstanceID: st.InstanceID,
+		Shards:     st.Shards,
+		Members:    st.Members,
+		Owned:      st.Owned,
+		Releasing:  st.Releasing,
+	}
+	if resp.Members == nil {
+		resp.Members = []string{}
+	}
+	if resp.Owned == nil {
+		resp.Owned = []int{}
+	}
+	if resp.Releasing == nil {
+		resp.Releasing = []int{}
+	}
+	return &shardStatusOutput{Body: resp}, nil
+}
+
+type streamRebuildInput struct {
+	Projection string `query:"projection" required:"true" doc:"Projector name, e.g. event_projection or dispatch_job_projection"`
+}
+
+type streamRebuildOutput struct {
+	Body StreamRebuildResponse
+}
+
+// streamRebuild clears the projection's read table and re-queues every
+// source row. The replay itself runs on the stream processor; 202 means
+// the reset is committed, not that the read model is complete.
+func (s *State) streamRebuild(ctx context.Context, in *streamRebuildInput) (*streamRebuildOutput, error) {
+	if s.Rebuilder == nil {
+		return nil, notConfigured("stream rebuild")
+	}
+	res, err := s.Rebuilder.Rebuild(ctx, in.Projection)
+	if errors.Is(err, ErrProjectionNotRebuildable) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err != nil {
+		return nil, huma.Error500InternalServerError("rebuild: " + err.Error())
+	}
+	slog.Warn("stream projection rebuild requested", "projection", res.Projection, "requeued", res.Requeued)
+	return &streamRebuildOutput{Body: StreamRebuildResponse{
+		Projection: res.Projection,
+		Requeued:   res.Requeued,
+	}}, nil
+}
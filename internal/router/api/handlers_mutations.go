@@ -6,6 +6,8 @@ import (
 	"net/http"
 
 	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
 )
 
 func registerMutations(api huma.API, s *State) {
@@ -29,6 +31,10 @@ func registerMutations(api huma.API, s *State) {
 		OperationID: "monitoringAcknowledgeWarning", Method: http.MethodPost, Path: "/monitoring/warnings/{id}/acknowledge",
 		Summary: "Acknowledge a warning (dashboard alias)", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
 	}, s.acknowledgeWarning)
+	huma.Register(api, huma.Operation{
+		OperationID: "cancelInFlightMessage", Method: http.MethodDelete, Path: "/monitoring/in-flight-messages/{messageId}",
+		Summary: "Abort an in-flight mediation and nack or dead-letter the message", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.cancelInFlight)
 }
 
 type updatePoolConfigInput struct {
@@ -44,11 +50,14 @@ func (s *State) updatePoolConfig(_ context.Context, in *updatePoolConfigInput) (
 	if s.PoolUpdater == nil {
 		return nil, notConfigured("pool updater")
 	}
+	if in.Body.ClearRateLimit && in.Body.RateLimitPerMinute != nil {
+		return nil, huma.Error422UnprocessableEntity("rate_limit_per_minute and clear_rate_limit are mutually exclusive")
+	}
 	var concurrency uint32
 	if in.Body.Concurrency != nil {
 		concurrency = *in.Body.Concurrency
 	}
-	setRate := in.Body.RateLimitPerMinute != nil
+	setRate := in.Body.RateLimitPerMinute != nil || in.Body.ClearRateLimit
 	if !s.PoolUpdater.UpdatePool(in.PoolCode, concurrency, in.Body.RateLimitPerMinute, setRate) {
 		return nil, huma.Error404NotFound("pool not found or update rejected: " + in.PoolCode)
 	}
@@ -112,3 +121,35 @@ func (s *State) resetAllBreakers(_ context.Context, _ *emptyInput) (*resetAllBre
 	n := s.Breakers.ResetAll()
 	return &resetAllBreakersOutput{Body: BreakerResetAllResponse{Reset: uint64(n)}}, nil
 }
+
+type cancelInFlightInput struct {
+	MessageID string `path:"messageId"`
+	Action    string `query:"action" enum:"nack,dead-letter," doc:"nack (default) releases the message for redelivery; dead-letter ACKs it off the broker and records a warning"`
+}
+
+type cancelInFlightOutput struct {
+	Body InFlightCancelResponse
+}
+
+// cancelInFlight frees a worker held by a hung receiver: the mediation's
+// request is aborted and the message leaves the pipeline per ?action.
+func (s *State) cancelInFlight(ctx context.Context, in *cancelInFlightInput) (*cancelInFlightOutput, error) {
+	if s.Canceller == nil {
+		return nil, notConfigured("in-flight cancellation")
+	}
+	action, err := router.ParseCancelAction(in.Action)
+	if err != nil {
+		return nil, huma.Error422UnprocessableEntity(err.Error())
+	}
+	res := s.Canceller.CancelInFlight(ctx, in.MessageID, action)
+	if res == router.CancelNotFound {
+		return nil, huma.Error404NotFound("message not mediating or buffered: " + in.MessageID)
+	}
+	slog.Info("in-flight message cancel requested via API",
+		"message_id", in.MessageID, "action", action, "result", res)
+	return &cancelInFlightOutput{Body: InFlightCancelResponse{
+		MessageID: in.MessageID,
+		Result:    string(res),
+		Action:    string(action),
+	}}, nil
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+)
+
+func registerPoison(api huma.API, s *State) {
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringPoisonMessages", Method: http.MethodGet, Path: "/monitoring/poison-messages",
+		Summary: "Quarantined messages a consumer could not decode", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.listPoisonMessages)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringPoisonMessage", Method: http.MethodGet, Path: "/monitoring/poison-messages/{id}",
+		Summary: "One quarantined message, with its body sample", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.getPoisonMessage)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringDeletePoisonMessage", Method: http.MethodDelete, Path: "/monitoring/poison-messages/{id}",
+		Summary: "Discard a quarantined message", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.deletePoisonMessage)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringReplayPoisonMessage", Method: http.MethodPost, Path: "/monitoring/poison-messages/{id}/replay",
+		Summary: "Republish a quarantined message to its queue, optionally with a corrected body", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.replayPoisonMessage)
+}
+
+type listPoisonInput struct {
+	Queue string `query:"queue" doc:"Queue config name to filter by"`
+	Limit int    `query:"limit" doc:"Maximum entries returned (default 200)"`
+}
+
+type listPoisonOutput struct {
+	Body PoisonMessagesResponse
+}
+
+func (s *State) listPoisonMessages(ctx context.Context, in *listPoisonInput) (*listPoisonOutput, error) {
+	if s.Poison == nil {
+		return nil, notConfigured("poison quarantine")
+	}
+	msgs, err := s.Poison.List(ctx, router.PoisonQuery{QueueName: in.Queue, Limit: in.Limit})
+	if err != nil {
+		return nil, huma.Error503ServiceUnavailable("list poison messages: " + err.Error())
+	}
+	out := PoisonMessagesResponse{Messages: make([]PoisonMessageSummary, 0, len(msgs))}
+	for _, p := range msgs {
+		out.Messages = append(out.Messages, poisonSummary(p))
+	}
+	out.Total = len(out.Messages)
+	return &listPoisonOutput{Body: out}, nil
+}
+
+type poisonIDInput struct {
+	ID string `path:"id"`
+}
+
+type poisonMessageOutput struct {
+	Body PoisonMessageResponse
+}
+
+func (s *State) getPoisonMessage(ctx context.Context, in *poisonIDInput) (*poisonMessageOutput, error) {
+	if s.Poison == nil {
+		return nil, notConfigured("poison quarantine")
+	}
+	p, err := s.Poison.Get(ctx, in.ID)
+	if err != nil {
+		return nil, poisonError(err, in.ID)
+	}
+	return &poisonMessageOutput{Body: PoisonMessageResponse{
+		PoisonMessageSummary: poisonSummary(p),
+		Body:                 string(p.Body),
+	}}, nil
+}
+
+type poisonDeleteOutput struct {
+	Body PoisonDeletedResponse
+}
+
+func (s *State) deletePoisonMessage(ctx context.Context, in *poisonIDInput) (*poisonDeleteOutput, error) {
+	if s.Poison == nil {
+		return nil, notConfigured("poison quarantine")
+	}
+	if err := s.Poison.Delete(ctx, in.ID); err != nil {
+		return nil, poisonError(err, in.ID)
+	}
+	return &poisonDeleteOutput{Body: PoisonDeletedResponse{Deleted: true}}, nil
+}
+
+type replayPoisonInput struct {
+	ID   string               `path:"id"`
+	Body *PoisonReplayRequest `required:"false"`
+}
+
+type replayPoisonOutput struct {
+	Body PoisonReplayResponse
+}
+
+func (s *State) replayPoisonMessage(ctx context.Context, in *replayPoisonInput) (*replayPoisonOutput, error) {
+	if s.Poison == nil {
+		return nil, notConfigured("poison quarantine")
+	}
+	var body []byte
+	if in.Body != nil && in.Body.Body != "" {
+		body = []byte(in.Body.Body)
+	}
+	pubID, err := s.Poison.Replay(ctx, in.ID, body)
+	if err != nil {
+		return nil, poisonError(err, in.ID)
+	}
+	return &replayPoisonOutput{Body: PoisonReplayResponse{ID: in.ID, PublishID: pubID}}, nil
+}
+
+// poisonError maps quarantine errors: unknown ID → 404, a body that still
+// doesn't decode → 422, anything else (store or broker) → 503.
+func poisonError(err error, id string) error {
+	switch {
+	case errors.Is(err, router.ErrPoisonNotFound):
+		return huma.Error404NotFound("poison message not found: " + id)
+	case errors.Is(err, router.ErrPoisonNotReplayable):
+		return huma.Error422UnprocessableEntity(err.Error())
+	default:
+		return huma.Error503ServiceUnavailable(err.Error())
+	}
+}
+
+func poisonSummary(p router.PoisonMessage) PoisonMessageSummary {
+	return PoisonMessageSummary{
+		ID:              p.ID,
+		Queue:           p.QueueName,
+		QueueIdentifier: p.QueueIdentifier,
+		Subject:         p.Subject,
+		BrokerMessageID: p.BrokerMessageID,
+		ContentEncoding: p.ContentEncoding,
+		Error:           p.Error,
+		Size:            len(p.Body),
+		Truncated:       p.Truncated,
+		Count:           p.Count,
+		FirstSeenAt:     p.FirstSeenAt.UTC().Format(time.RFC3339),
+		LastSeenAt:      p.LastSeenAt.UTC().Format(time.RFC3339),
+	}
+}
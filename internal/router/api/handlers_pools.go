@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+)
+
+const tagPools = "pools"
+
+// registerPools is the router admin surface (/router/pools/... under
+// fc-server's default FC_ROUTER_HTTP_PREFIX): live pool inspection and
+// runtime tuning. Tuning lasts until the next config sync (5m by default,
+// or POST /config/reload), which puts every pool back on its synced
+// config; until then the pool reports the override.
+func registerPools(api huma.API, s *State) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listRouterPools", Method: http.MethodGet, Path: "/pools",
+		Summary: "List pools with live stats and runtime overrides", Tags: []string{tagPools}, DefaultStatus: http.StatusOK,
+	}, s.listRouterPools)
+	huma.Register(api, huma.Operation{
+		OperationID: "getRouterPool", Method: http.MethodGet, Path: "/pools/{poolCode}",
+		Summary: "Get one pool's live stats and runtime override", Tags: []string{tagPools}, DefaultStatus: http.StatusOK,
+	}, s.getRouterPool)
+	huma.Register(api, huma.Operation{
+		OperationID: "updateRouterPoolConfig", Method: http.MethodPost, Path: "/pools/{poolCode}/config",
+		Summary: "Adjust a pool's concurrency / rate limit until the next config sync", Tags: []string{tagPools}, DefaultStatus: http.StatusOK,
+	}, s.updatePoolConfig)
+	huma.Register(api, huma.Operation{
+		OperationID: "unquarantineRouterPool", Method: http.MethodPost, Path: "/pools/{poolCode}/unquarantine",
+		Summary: "Lift a pool's auto-quarantine and resume dispatch", Tags: []string{tagPools}, DefaultStatus: http.StatusOK,
+	}, s.unquarantinePool)
+}
+
+type routerPoolsOutput struct {
+	Body []RouterPool
+}
+
+func (s *State) listRouterPools(_ context.Context, _ *emptyInput) (*routerPoolsOutput, error) {
+	if s.PoolStats == nil {
+		return nil, notConfigured("pool stats")
+	}
+	stats := s.PoolStats.PoolStats()
+	sort.Slice(stats, func(i, j int) bool { return stats[i].PoolCode < stats[j].PoolCode })
+	return &routerPoolsOutput{Body: s.routerPools(stats)}, nil
+}
+
+type routerPoolInput struct {
+	PoolCode string `path:"poolCode"`
+}
+
+type routerPoolOutput struct {
+	Body RouterPool
+}
+
+func (s *State) getRouterPool(_ context.Context, in *routerPoolInput) (*routerPoolOutput, error) {
+	if s.PoolStats == nil {
+		return nil, notConfigured("pool stats")
+	}
+	for _, p := range s.PoolStats.PoolStats() {
+		if p.PoolCode == in.PoolCode {
+			return &routerPoolOutput{Body: s.routerPools([]router.PoolStats{p})[0]}, nil
+		}
+	}
+	return nil, huma.Error404NotFound("pool not found: " + in.PoolCode)
+}
+
+func (s *State) routerPools(stats []router.PoolStats) []RouterPool {
+	var overrides map[string]router.PoolOverride
+	if s.Overrides != nil {
+		overrides = s.Overrides.PoolOverrides()
+	}
+	wire := fromPoolStats(stats)
+	out := make([]RouterPool, len(wire))
+	for i, w := range wire {
+		out[i] = RouterPool{WirePoolStats: w}
+		if o, ok := overrides[w.PoolCode]; ok {
+			out[i].Override = &WirePoolOverride{
+				Concurrency:        o.Concurrency,
+				RateLimitSet:       o.RateLimitSet,
+				RateLimitPerMinute: o.RateLimitPerMinute,
+				AppliedAt:          o.AppliedAt,
+			}
+		}
+	}
+	return out
+}
+
+type unquarantineOutput struct {
+	Body PoolUnquarantineResponse
+}
+
+func (s *State) unquarantinePool(_ context.Context, in *routerPoolInput) (*unquarantineOutput, error) {
+	if s.Quarantiner == nil {
+		return nil, notConfigured("pool quarantine")
+	}
+	found, lifted := s.Quarantiner.Unquarantine(in.PoolCode)
+	if !found {
+		return nil, huma.Error404NotFound("pool not found: " + in.PoolCode)
+	}
+	if lifted {
+		slog.Info("pool unquarantined via API", "pool", in.PoolCode)
+	}
+	return &unquarantineOutput{Body: PoolUnquarantineResponse{PoolCode: in.PoolCode, WasQuarantined: lifted}}, nil
+}
@@ -47,6 +47,22 @@ func registerWarnings(api huma.API, s *State) {
 		OperationID: "clearOldWarnings", Method: http.MethodDelete, Path: "/warnings/old",
 		Summary: "Purge warnings older than ?hours", Tags: []string{tagWarnings}, DefaultStatus: http.StatusOK,
 	}, s.clearOldWarnings)
+	huma.Register(api, huma.Operation{
+		OperationID: "resolveWarning", Method: http.MethodPost, Path: "/warnings/{id}/resolve",
+		Summary: "Resolve a warning (drops it from the live set; kept in history)", Tags: []string{tagWarnings}, DefaultStatus: http.StatusOK,
+	}, s.resolveWarning)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringAckWarning", Method: http.MethodPost, Path: "/monitoring/warnings/{id}/ack",
+		Summary: "Acknowledge a warning (short alias)", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.acknowledgeWarning)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringResolveWarning", Method: http.MethodPost, Path: "/monitoring/warnings/{id}/resolve",
+		Summary: "Resolve a warning (dashboard alias)", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.resolveWarning)
+	huma.Register(api, huma.Operation{
+		OperationID: "monitoringWarningHistory", Method: http.MethodGet, Path: "/monitoring/warnings/history",
+		Summary: "Warning history, filtered by category / severity / time", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
+	}, s.warningHistory)
 	huma.Register(api, huma.Operation{
 		OperationID: "monitoringUnacknowledged", Method: http.MethodGet, Path: "/monitoring/warnings/unacknowledged",
 		Summary: "Unacknowledged warnings (dashboard alias)", Tags: []string{tagMonitoring}, DefaultStatus: http.StatusOK,
@@ -137,6 +153,61 @@ func (s *State) acknowledgeWarning(_ context.Context, in *acknowledgeInput) (*ac
 	return nil, huma.Error404NotFound("Warning not found: " + in.ID)
 }
 
+type resolveOutput struct {
+	Body ResolvedResponse
+}
+
+func (s *State) resolveWarning(_ context.Context, in *acknowledgeInput) (*resolveOutput, error) {
+	if s.Warnings.Resolve(in.ID) {
+		return &resolveOutput{Body: ResolvedResponse{Resolved: true}}, nil
+	}
+	return nil, huma.Error404NotFound("Warning not found: " + in.ID)
+}
+
+type warningHistoryInput struct {
+	Category     string    `query:"category"`
+	Severity     string    `query:"severity"`
+	Since        time.Time `query:"since" doc:"RFC 3339; inclusive"`
+	Until        time.Time `query:"until" doc:"RFC 3339; exclusive"`
+	Acknowledged string    `query:"acknowledged" enum:"true,false," doc:"Empty = either"`
+	Resolved     string    `query:"resolved" enum:"true,false," doc:"Empty = either"`
+	Limit        int       `query:"limit" minimum:"0" maximum:"5000" doc:"0 = 500"`
+}
+
+// warningHistory serves the persisted history when a warning store is
+// configured, else the in-memory set.
+func (s *State) warningHistory(ctx context.Context, in *warningHistoryInput) (*warningsListOutput, error) {
+	q := router.WarningQuery{
+		Category:     router.WarningCategory(strings.ToUpper(in.Category)),
+		Severity:     router.WarningSeverity(strings.ToUpper(in.Severity)),
+		Since:        in.Since,
+		Until:        in.Until,
+		Acknowledged: optionalBool(in.Acknowledged),
+		Resolved:     optionalBool(in.Resolved),
+		Limit:        in.Limit,
+	}
+	if q.Severity == "WARN" {
+		q.Severity = router.WarningWarning
+	}
+	ws, err := s.Warnings.History(ctx, q)
+	if err != nil {
+		return nil, huma.Error503ServiceUnavailable("warning history unavailable", err)
+	}
+	return &warningsListOutput{Body: fromWarnings(ws)}, nil
+}
+
+func optionalBool(v string) *bool {
+	switch v {
+	case "true":
+		b := true
+		return &b
+	case "false":
+		b := false
+		return &b
+	}
+	return nil
+}
+
 type acknowledgeAllOutput struct {
 	Body AcknowledgedCountResponse
 }
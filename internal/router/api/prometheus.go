@@ -22,6 +22,9 @@ import (
 //   - fc_messages_processed_total{success}                              (counter)
 //   - fc_rate_limit_exceeded_total                                      (counter)
 //   - fc_mediation_duration_seconds                                     (histogram)
+//   - fc_pool_message_group_max_depth (gauge) — Go-only; group skew
+//   - fc_pool_concurrency, fc_pool_adaptive_paused (gauges) and
+//     fc_pool_concurrency_adjustments_total{direction} (counter) — Go-only
 //
 // Global:
 //   - fc_in_pipeline_messages                                          (gauge)
@@ -30,11 +33,19 @@ import (
 //   - fc_queue_pending_messages, fc_queue_in_flight_messages           (gauges)
 //   - fc_consumer_messages_received_total{consumer}                    (counter)
 //   - fc_queue_messages_total{queue,outcome=acked|nacked|deferred}     (counter)
+//   - fc_poison_messages_total{queue}                                  (counter) — Go-only;
+//     undecodable messages quarantined (see /monitoring/poison-messages)
 //
 // Circuit breaker (label: target):
 //   - fc_circuit_breaker_open                                          (gauge)
 //   - fc_circuit_breaker_calls_total{outcome=success|failure}          (counter)
 //
+// Mediator overload guard — Go-only:
+//   - fc_mediator_in_flight_requests, fc_mediator_waiting_requests,
+//     fc_mediator_max_in_flight, fc_mediator_retry_share               (gauges)
+//   - fc_mediator_overload_total{action=queued|shed}                   (counter)
+//   - fc_mediator_retry_budget_exhausted_total                         (counter)
+//
 // Note (Rust parity gap, dashboards only): Rust additionally emits
 // fc_messages_submitted_total, fc_messages_rejected_total{reason},
 // fc_consumer_polls_total / fc_consumer_errors_total{type}, the `result`
@@ -62,8 +73,10 @@ func (c *routerCollector) Describe(_ chan<- *prometheus.Desc) {}
 func (c *routerCollector) Collect(ch chan<- prometheus.Metric) {
 	c.collectPools(ch)
 	c.collectQueues(ch)
+	c.collectPoison(ch)
 	c.collectBreakers(ch)
 	c.collectInFlight(ch)
+	c.collectOverload(ch)
 }
 
 func (c *routerCollector) collectPools(ch chan<- prometheus.Metric) {
@@ -82,6 +95,34 @@ func (c *routerCollector) collectPools(ch chan<- prometheus.Metric) {
 		gauge(ch, "fc_pool_message_groups",
 			"Distinct message groups currently holding buffered work.",
 			float64(s.MessageGroupCount), poolLabel, lv)
+		gauge(ch, "fc_pool_message_group_max_depth",
+			"Messages buffered in the pool's deepest message group.",
+			float64(s.LargestGroupDepth), poolLabel, lv)
+		gauge(ch, "fc_pool_overflow",
+			"Messages parked for a full pool, awaiting re-offer.",
+			float64(s.Overflow), poolLabel, lv)
+		gauge(ch, "fc_pool_concurrency",
+			"Worker limit per pool; moves on its own for adaptive pools.",
+			float64(s.Concurrency), poolLabel, lv)
+		if a := s.Adaptive; a != nil {
+			counter(ch, "fc_pool_concurrency_adjustments_total",
+				"Adaptive concurrency resizes, by direction.",
+				float64(a.Increases), []string{"pool", "direction"}, []string{s.PoolCode, "up"})
+			counter(ch, "fc_pool_concurrency_adjustments_total",
+				"Adaptive concurrency resizes, by direction.",
+				float64(a.Decreases), []string{"pool", "direction"}, []string{s.PoolCode, "down"})
+			gauge(ch, "fc_pool_adaptive_paused",
+				"1 while a manual concurrency override holds an adaptive pool.",
+				boolFloat(a.Paused), poolLabel, lv)
+		}
+		if q := s.Quarantine; q != nil {
+			gauge(ch, "fc_pool_quarantined",
+				"1 while a pool is auto-quarantined after repeated delivery failures.",
+				boolFloat(q.Quarantined), poolLabel, lv)
+			counter(ch, "fc_pool_quarantines_total",
+				"Times a pool has been auto-quarantined.",
+				float64(q.Trips), poolLabel, lv)
+		}
 
 		if s.Metrics != nil {
 			m := s.Metrics
@@ -94,6 +135,15 @@ func (c *routerCollector) collectPools(ch chan<- prometheus.Metric) {
 			counter(ch, "fc_rate_limit_exceeded_total",
 				"Cumulative rate-limit events.",
 				float64(m.TotalRateLimited), poolLabel, lv)
+			counter(ch, "fc_receiver_pushback_total",
+				"Cumulative 429 / 503 responses from receivers, by status.",
+				float64(m.TotalPushback429), []string{"pool", "status"}, []string{s.PoolCode, "429"})
+			counter(ch, "fc_receiver_pushback_total",
+				"Cumulative 429 / 503 responses from receivers, by status.",
+				float64(m.TotalPushback503), []string{"pool", "status"}, []string{s.PoolCode, "503"})
+			counter(ch, "fc_pool_rejected_total",
+				"Messages NACKed back to the broker because the pool was full.",
+				float64(m.TotalRejected), poolLabel, lv)
 		}
 
 		// fc_mediation_duration_seconds — cumulative histogram.
@@ -116,6 +166,11 @@ func (c *routerCollector) collectQueues(ch chan<- prometheus.Metric) {
 	if c.state.BrokerStats == nil {
 		return
 	}
+	if age := c.state.BrokerStats.AgeSeconds(); age >= 0 {
+		gauge(ch, "fc_queue_stats_age_seconds",
+			"Seconds since queue depth was last fetched from the broker.",
+			float64(age), nil, nil)
+	}
 	for _, m := range c.state.BrokerStats.GetWindowed(0) {
 		q := normaliseQueueID(m.QueueIdentifier)
 		gauge(ch, "fc_queue_pending_messages",
@@ -140,6 +195,17 @@ func (c *routerCollector) collectQueues(ch chan<- prometheus.Metric) {
 	}
 }
 
+func (c *routerCollector) collectPoison(ch chan<- prometheus.Metric) {
+	if c.state.Poison == nil {
+		return
+	}
+	for q, n := range c.state.Poison.Counts() {
+		counter(ch, "fc_poison_messages_total",
+			"Undecodable messages quarantined, per queue config.",
+			float64(n), []string{"queue"}, []string{q})
+	}
+}
+
 func (c *routerCollector) collectBreakers(ch chan<- prometheus.Metric) {
 	if c.state.Breakers == nil {
 		return
@@ -170,6 +236,34 @@ func (c *routerCollector) collectInFlight(ch chan<- prometheus.Metric) {
 		float64(count), nil, nil)
 }
 
+func (c *routerCollector) collectOverload(ch chan<- prometheus.Metric) {
+	if c.state.Overload == nil {
+		return
+	}
+	st := c.state.Overload.OverloadStats()
+	gauge(ch, "fc_mediator_in_flight_requests",
+		"Delivery requests the mediator is making, across all pools.",
+		float64(st.InFlight), nil, nil)
+	gauge(ch, "fc_mediator_waiting_requests",
+		"Deliveries queued for an in-flight slot at the ceiling.",
+		float64(st.Waiting), nil, nil)
+	gauge(ch, "fc_mediator_max_in_flight",
+		"The mediator's global in-flight ceiling; 0 when unlimited.",
+		float64(st.MaxInFlight), nil, nil)
+	gauge(ch, "fc_mediator_retry_share",
+		"Share of the last ten seconds' delivery requests that were retries.",
+		st.RetryShare, nil, nil)
+	counter(ch, "fc_mediator_overload_total",
+		"Deliveries that found the in-flight ceiling reached, by what they did.",
+		float64(st.Queued), []string{"action"}, []string{"queued"})
+	counter(ch, "fc_mediator_overload_total",
+		"Deliveries that found the in-flight ceiling reached, by what they did.",
+		float64(st.Shed), []string{"action"}, []string{"shed"})
+	counter(ch, "fc_mediator_retry_budget_exhausted_total",
+		"Retries the mediator refused because the retry budget was spent.",
+		float64(st.RetryBudgetExhausted), nil, nil)
+}
+
 // gauge emits a single typed gauge metric.
 func gauge(ch chan<- prometheus.Metric, name, help string, value float64, labels, labelValues []string) {
 	desc := prometheus.NewDesc(name, help, labels, nil)
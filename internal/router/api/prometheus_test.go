@@ -20,7 +20,9 @@ func TestPrometheusHandler_EmitsExpectedMetrics(t *testing.T) {
 		QueueSize:          5,
 		QueueCapacity:      200,
 		MessageGroupCount:  2,
+		LargestGroupDepth:  4,
 		RateLimitPerMinute: &rl,
+		Adaptive:           &router.AdaptiveStatus{MinConcurrency: 2, MaxConcurrency: 20, Increases: 4, Decreases: 1},
 		Metrics: &common.EnhancedPoolMetrics{
 			TotalSuccess: 100, TotalFailure: 2, TotalRateLimited: 1,
 			SuccessRate: 100.0 / 102.0,
@@ -50,6 +52,10 @@ func TestPrometheusHandler_EmitsExpectedMetrics(t *testing.T) {
 		`fc_pool_active_workers{pool="demo"} 3`,
 		`fc_pool_queue_size{pool="demo"} 5`,
 		`fc_pool_message_groups{pool="demo"} 2`,
+		`fc_pool_message_group_max_depth{pool="demo"} 4`,
+		`fc_pool_concurrency{pool="demo"} 10`,
+		`fc_pool_concurrency_adjustments_total{direction="up",pool="demo"} 4`,
+		`fc_pool_adaptive_paused{pool="demo"} 0`,
 		`fc_messages_processed_total{pool="demo",success="true"} 100`,
 		`fc_messages_processed_total{pool="demo",success="false"} 2`,
 		`fc_rate_limit_exceeded_total{pool="demo"} 1`,
@@ -94,3 +100,37 @@ func TestPrometheusHandler_EmitsBreakerAndInFlight(t *testing.T) {
 		}
 	}
 }
+
+type stubOverloadProvider struct{ st router.OverloadStats }
+
+func (s stubOverloadProvider) OverloadStats() router.OverloadStats { return s.st }
+
+func TestPrometheusHandler_EmitsOverload(t *testing.T) {
+	state := &routerapi.State{
+		Overload: stubOverloadProvider{st: router.OverloadStats{
+			MaxInFlight: 100, InFlight: 40, Waiting: 2, Queued: 9, Shed: 3,
+			RetryBudgetExhausted: 11, RetryShare: 0.25,
+		}},
+		Mocks: routerapi.NewMockState(),
+	}
+
+	h := routerapi.PrometheusHandler(state)
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	body := rec.Body.String()
+
+	for _, s := range []string{
+		`fc_mediator_in_flight_requests 40`,
+		`fc_mediator_waiting_requests 2`,
+		`fc_mediator_max_in_flight 100`,
+		`fc_mediator_retry_share 0.25`,
+		`fc_mediator_overload_total{action="queued"} 9`,
+		`fc_mediator_overload_total{action="shed"} 3`,
+		`fc_mediator_retry_budget_exhausted_total 11`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("missing %q in:\n%s", s, body)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package api_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -123,3 +126,107 @@ func TestStreamHealth_LiveProviderNotReady(t *testing.T) {
 		t.Errorf("ready status=%d want 503", ready.Code)
 	}
 }
+
+func TestHealth_DegradedByLaggingStream(t *testing.T) {
+	ws := router.NewWarningService(router.WarningServiceConfig{})
+	hs := router.NewHealthService(router.DefaultHealthServiceConfig(), ws)
+
+	provider := stubStreamHealthProvider{
+		agg: routerapi.StreamHealthAggregate{
+			TotalStreams:     2,
+			HealthyStreams:   1,
+			UnhealthyStreams: 1,
+		},
+		live:  true,
+		ready: false,
+	}
+
+	_, api := humatest.New(t)
+	routerapi.Register(api, &routerapi.State{
+		Warnings: ws, Health: hs, StreamHealth: provider, Mocks: routerapi.NewMockState(),
+	})
+
+	resp := api.Get("/q/health")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d", resp.Code)
+	}
+	var body routerapi.SimpleHealthResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Status != "DEGRADED" {
+		t.Errorf("Status=%q want DEGRADED", body.Status)
+	}
+	if body.Details == nil || body.Details.DegradationReason == nil {
+		t.Fatalf("missing details.degradationReason: %+v", body.Details)
+	}
+	var stream *router.ComponentHealth
+	for i := range body.Components {
+		if body.Components[i].Name == "stream" {
+			stream = &body.Components[i]
+		}
+	}
+	if stream == nil || stream.Status != router.ComponentDegraded || stream.ConsecutiveFailures != 1 {
+		t.Errorf("stream component=%+v", stream)
+	}
+
+	// A stopped processor is DOWN, not DEGRADED.
+	provider.live = false
+	_, api = humatest.New(t)
+	routerapi.Register(api, &routerapi.State{
+		Warnings: ws, Health: hs, StreamHealth: provider, Mocks: routerapi.NewMockState(),
+	})
+	resp = api.Get("/q/health")
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Status != "DOWN" {
+		t.Errorf("Status=%q want DOWN", body.Status)
+	}
+}
+
+// stubRebuilder implements routerapi.StreamRebuilder.
+type stubRebuilder struct{ err error }
+
+func (s stubRebuilder) Rebuild(_ context.Context, projection string) (routerapi.StreamRebuildResult, error) {
+	if s.err != nil {
+		return routerapi.StreamRebuildResult{}, s.err
+	}
+	return routerapi.StreamRebuildResult{Projection: projection, Requeued: 7}, nil
+}
+
+func TestStreamRebuild(t *testing.T) {
+	ws := router.NewWarningService(router.WarningServiceConfig{})
+	hs := router.NewHealthService(router.DefaultHealthServiceConfig(), ws)
+	cases := []struct {
+		name     string
+		rebuild  routerapi.StreamRebuilder
+		wantCode int
+	}{
+		{"not configured", nil, http.StatusServiceUnavailable},
+		{"rejected projection", stubRebuilder{err: fmt.Errorf("%w: event_fan_out", routerapi.ErrProjectionNotRebuildable)}, http.StatusBadRequest},
+		{"failure", stubRebuilder{err: errors.New("db down")}, http.StatusInternalServerError},
+		{"accepted", stubRebuilder{}, http.StatusAccepted},
+	}
+	for _, tc := range cases {
+		_, api := humatest.New(t)
+		routerapi.Register(api, &routerapi.State{
+			Warnings: ws, Health: hs, Rebuilder: tc.rebuild, Mocks: routerapi.NewMockState(),
+		})
+		resp := api.Post("/stream/rebuild?projection=event_projection")
+		if resp.Code != tc.wantCode {
+			t.Errorf("%s: status=%d want %d body=%s", tc.name, resp.Code, tc.wantCode, resp.Body.String())
+			continue
+		}
+		if tc.wantCode != http.StatusAccepted {
+			continue
+		}
+		var body routerapi.StreamRebuildResponse
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body.Projection != "event_projection" || body.Requeued != 7 {
+			t.Errorf("body=%+v", body)
+		}
+	}
+}
@@ -14,9 +14,15 @@ import (
 // `api::CachedBrokerStats`.
 const counterHistoryWindow = 30 * time.Minute
 
-// brokerRefreshInterval is the cadence for fresh SQS attribute fetches.
-// Mirrors the 60s ticker in `api::spawn_broker_stats_refresh`.
-const brokerRefreshInterval = 60 * time.Second
+// defaultBrokerRefreshInterval is the cadence for fresh broker attribute
+// fetches (SQS queue attributes, JetStream consumer info) when
+// ServerConfig.BrokerStatsInterval is unset. Mirrors the 60s ticker in
+// `api::spawn_broker_stats_refresh`.
+const defaultBrokerRefreshInterval = 60 * time.Second
+
+// brokerFetchTimeout bounds one Refresh so a hung broker call can't
+// stall the refresh goroutine (or an on-demand ?refresh=true request).
+const brokerFetchTimeout = 15 * time.Second
 
 // queueAttr is the pair of expensive broker attributes that the cache
 // refreshes on a slow cadence. Counter fields (polled / acked / nacked
@@ -79,19 +85,31 @@ func NewCachedBrokerStats(source MetricsSource) *CachedBrokerStats {
 
 // Refresh fetches fresh broker attributes and appends a counter snapshot
 // to the rolling history. Trims history entries older than the window.
+//
+// A queue whose fetch failed keeps its last known attributes rather than
+// dropping to zero; entries for queues no longer consumed are pruned.
 func (c *CachedBrokerStats) Refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, brokerFetchTimeout)
+	defer cancel()
 	fresh := c.source.QueueMetrics(ctx)
+	live := c.source.QueueCounters()
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.attrs = make(map[string]queueAttr, len(fresh))
+	attrs := make(map[string]queueAttr, len(live))
+	for _, m := range live {
+		if a, ok := c.attrs[m.QueueIdentifier]; ok {
+			attrs[m.QueueIdentifier] = a
+		}
+	}
 	for _, m := range fresh {
-		c.attrs[m.QueueIdentifier] = queueAttr{
+		attrs[m.QueueIdentifier] = queueAttr{
 			pendingMessages:  m.PendingMessages,
 			inFlightMessages: m.InFlightMessages,
 		}
 	}
+	c.attrs = attrs
 	c.lastUpdated = time.Now()
 
 	c.snapshotCountersLocked(fresh)
@@ -195,12 +213,15 @@ func saturatingSub(a, b uint64) uint64 {
 }
 
 // SpawnBrokerStatsRefresh kicks off a background goroutine that performs
-// an initial refresh and then re-fetches every brokerRefreshInterval
-// until ctx is cancelled.
-func SpawnBrokerStatsRefresh(ctx context.Context, c *CachedBrokerStats) {
+// an initial refresh and then re-fetches every interval (zero falls back
+// to 60s) until ctx is cancelled.
+func SpawnBrokerStatsRefresh(ctx context.Context, c *CachedBrokerStats, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultBrokerRefreshInterval
+	}
 	go func() {
 		c.Refresh(ctx)
-		t := time.NewTicker(brokerRefreshInterval)
+		t := time.NewTicker(interval)
 		defer t.Stop()
 		for {
 			select {
@@ -211,5 +232,5 @@ func SpawnBrokerStatsRefresh(ctx context.Context, c *CachedBrokerStats) {
 			}
 		}
 	}()
-	slog.Debug("broker stats refresh task spawned", "interval", brokerRefreshInterval)
+	slog.Debug("broker stats refresh task spawned", "interval", interval)
 }
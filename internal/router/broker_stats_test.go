@@ -126,3 +126,42 @@ func TestSaturatingSub(t *testing.T) {
 		t.Errorf("saturatingSub(3,10)=%d, want 0", got)
 	}
 }
+
+func TestCachedBrokerStats_KeepsLastKnownAttrsOnFetchFailure(t *testing.T) {
+	fetchOK := true
+	queues := []string{"q1", "q2"}
+	src := &fakeMetricsSource{
+		metrics: func() []queue.Metrics {
+			if !fetchOK {
+				return nil // every broker fetch failed
+			}
+			return []queue.Metrics{{QueueIdentifier: "q1", PendingMessages: 42, InFlightMessages: 3}}
+		},
+		counters: func() []queue.Metrics {
+			out := make([]queue.Metrics, 0, len(queues))
+			for _, q := range queues {
+				out = append(out, queue.Metrics{QueueIdentifier: q})
+			}
+			return out
+		},
+	}
+	c := NewCachedBrokerStats(src)
+	c.Refresh(context.Background())
+
+	fetchOK = false
+	c.Refresh(context.Background())
+	out := c.GetWindowed(0)
+	if len(out) != 2 || out[0].PendingMessages != 42 || out[0].InFlightMessages != 3 {
+		t.Fatalf("q1 should keep last known attrs after a failed fetch, got %+v", out)
+	}
+
+	// Once q1 is no longer consumed its cached attrs are pruned.
+	queues = []string{"q2"}
+	c.Refresh(context.Background())
+	c.mu.RLock()
+	_, kept := c.attrs["q1"]
+	c.mu.RUnlock()
+	if kept {
+		t.Fatal("attrs for an unconsumed queue should be pruned on refresh")
+	}
+}
@@ -0,0 +1,134 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// CancelAction is what happens to an in-flight message an operator cancels.
+type CancelAction string
+
+const (
+	// CancelNack releases the message to its source queue for redelivery.
+	CancelNack CancelAction = "NACK"
+	// CancelDeadLetter takes the message out of circulation: it is ACKed
+	// off the broker (the router has no dead-letter queue of its own) and
+	// a warning records it. The dispatch job's own state on the platform
+	// is untouched.
+	CancelDeadLetter CancelAction = "DEAD_LETTER"
+)
+
+// ParseCancelAction accepts nack / dead-letter (any case, '-' or '_').
+func ParseCancelAction(s string) (CancelAction, error) {
+	switch s {
+	case "", "nack", "NACK":
+		return CancelNack, nil
+	case "dead-letter", "dead_letter", "DEAD_LETTER", "DEAD-LETTER":
+		return CancelDeadLetter, nil
+	}
+	return "", fmt.Errorf("unknown cancel action %q: want nack or dead-letter", s)
+}
+
+// CancelResult reports what CancelInFlight found.
+type CancelResult string
+
+const (
+	// CancelNotFound: the message isn't in any worker or group buffer of
+	// this router (already finished, never routed here, or waiting out an
+	// IMMEDIATE-mode retry backoff).
+	CancelNotFound CancelResult = "NOT_FOUND"
+	// CancelAborted: the delivery was in progress; its request was
+	// aborted and the action is applied as the worker unwinds.
+	CancelAborted CancelResult = "ABORTED"
+	// CancelRemoved: the message was buffered behind its group and was
+	// removed before dispatch; the action has been applied.
+	CancelRemoved CancelResult = "REMOVED"
+)
+
+// errOperatorCancel is the cancellation cause of an operator-aborted
+// mediation; processOne recognises it to apply the requested action
+// instead of retrying.
+var errOperatorCancel = errors.New("mediation cancelled by operator")
+
+// mediationHandle is a running mediation's abort switch.
+type mediationHandle struct {
+	cancel context.CancelCauseFunc
+	action CancelAction // set by Cancel before cancel is called; guarded by mediatingMu
+}
+
+// Cancel aborts or unbuffers message id in this pool and applies action.
+func (p *Pool) Cancel(ctx context.Context, id string, action CancelAction) CancelResult {
+	p.mediatingMu.Lock()
+	if h, ok := p.handles[id]; ok {
+		h.action = action
+		p.mediatingMu.Unlock()
+		h.cancel(errOperatorCancel)
+		return CancelAborted
+	}
+	p.mediatingMu.Unlock()
+
+	if qm, ok := p.unbuffer(id); ok {
+		p.applyCancel(ctx, qm, action)
+		return CancelRemoved
+	}
+	return CancelNotFound
+}
+
+// unbuffer removes message id from its group buffer, if it is waiting
+// there.
+func (p *Pool) unbuffer(id string) (common.QueuedMessage, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, gq := range p.groupQs {
+		for i, m := range gq.msgs {
+			if m.Message.ID == id {
+				gq.msgs = append(gq.msgs[:i:i], gq.msgs[i+1:]...)
+				p.queueSize.Add(^uint32(0))
+				return m, true
+			}
+		}
+	}
+	return common.QueuedMessage{}, false
+}
+
+// applyCancel takes a cancelled message out of the pipeline per action.
+func (p *Pool) applyCancel(ctx context.Context, qm common.QueuedMessage, action CancelAction) {
+	// The request context may be the one that was just cancelled; the
+	// broker call must still go out.
+	ctx = context.WithoutCancel(ctx)
+	switch action {
+	case CancelDeadLetter:
+		p.ackTracked(ctx, qm)
+		if ws := p.warnings.Load(); ws != nil {
+			ws.Add(WarningCategoryRouting, WarningWarning,
+				fmt.Sprintf("message %s dead-lettered by operator (pool %s, target %s)",
+					qm.Message.ID, p.cfg.Code, qm.Message.MediationTarget),
+				"Pool:"+p.cfg.Code)
+		}
+	default:
+		p.nackMsg(ctx, qm, nil, "cancelled by operator")
+	}
+	slog.Info("in-flight message cancelled by operator",
+		"message_id", qm.Message.ID, "pool", p.cfg.Code, "action", action)
+}
+
+// CancelInFlight finds message id in whichever pool holds it and cancels
+// it. Backs DELETE /monitoring/in-flight-messages/{messageId}.
+func (m *Manager) CancelInFlight(ctx context.Context, id string, action CancelAction) CancelResult {
+	m.mu.Lock()
+	pools := make([]*Pool, 0, len(m.pools))
+	for _, p := range m.pools {
+		pools = append(pools, p)
+	}
+	m.mu.Unlock()
+	for _, p := range pools {
+		if r := p.Cancel(ctx, id, action); r != CancelNotFound {
+			return r
+		}
+	}
+	return CancelNotFound
+}
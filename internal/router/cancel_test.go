@@ -0,0 +1,83 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// hangingMediator blocks every delivery until its context is cancelled —
+// a destination that never answers.
+type hangingMediator struct{}
+
+func (hangingMediator) Mediate(ctx context.Context, _ *common.Message) common.MediationOutcome {
+	<-ctx.Done()
+	return common.MediationOutcome{Result: common.MediationErrorConnection, ErrorMessage: ctx.Err().Error()}
+}
+
+func waitMediating(t *testing.T, p *Pool, id string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		for _, e := range p.MediatingSnapshot() {
+			if e.MessageID == id {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 5*time.Millisecond, "%s never started mediating", id)
+}
+
+// TestPoolCancelAbortsAndUnbuffers covers both halves of an operator
+// cancel: m2, buffered behind m1 in its group, is removed and NACKed
+// without ever being mediated; m1, hung mid-delivery, is aborted and
+// dead-lettered (ACKed off the broker, with a warning).
+func TestPoolCancelAbortsAndUnbuffers(t *testing.T) {
+	group := "g"
+	cons := &cascadeConsumer{wantTotal: 2, done: make(chan struct{})}
+	pool := newCascadePool(hangingMediator{}, func(string) queue.Consumer { return cons })
+	ws := NewWarningService(WarningServiceConfig{})
+	pool.SetWarnings(ws)
+
+	submitBatch(context.Background(), pool, []common.QueuedMessage{mkOrdered("m1", &group), mkOrdered("m2", &group)})
+	waitMediating(t, pool, "m1")
+
+	assert.Equal(t, CancelNotFound, pool.Cancel(context.Background(), "nope", CancelNack))
+	assert.Equal(t, CancelRemoved, pool.Cancel(context.Background(), "m2", CancelNack))
+	assert.Equal(t, CancelAborted, pool.Cancel(context.Background(), "m1", CancelDeadLetter))
+
+	select {
+	case <-cons.done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for both cancels to reach the broker")
+	}
+	cons.mu.Lock()
+	assert.Equal(t, []string{"m2"}, cons.nacked)
+	assert.Equal(t, []string{"m1"}, cons.acked)
+	cons.mu.Unlock()
+
+	require.Eventually(t, func() bool { return len(pool.MediatingSnapshot()) == 0 },
+		time.Second, 5*time.Millisecond, "aborted message should leave the mediating set")
+	assert.Equal(t, uint32(0), pool.queueSize.Load())
+	warnings := ws.All()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarningCategoryRouting, warnings[0].Category)
+	assert.Contains(t, warnings[0].Message, "m1")
+}
+
+func TestParseCancelAction(t *testing.T) {
+	for in, want := range map[string]CancelAction{
+		"": CancelNack, "nack": CancelNack, "dead-letter": CancelDeadLetter, "DEAD_LETTER": CancelDeadLetter,
+	} {
+		got, err := ParseCancelAction(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseCancelAction("drop")
+	assert.Error(t, err)
+}
@@ -0,0 +1,480 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// CaptureBodyLimit caps each captured request and response body. A
+// mediation request is a small pointer; receivers' responses can be
+// anything, and past this only the head is kept.
+const CaptureBodyLimit = 16 << 10
+
+// DefaultCaptureQueryLimit bounds a capture listing without an explicit
+// limit.
+const DefaultCaptureQueryLimit = 100
+
+// maxMemoryCaptures bounds MemoryCaptureStore; the oldest are dropped
+// first, like the Mongo capped collection.
+const maxMemoryCaptures = 1000
+
+// captureQueueSize bounds captures waiting to be written. Deliveries
+// never wait on the store: a capture that finds the queue full is
+// dropped and counted.
+const captureQueueSize = 256
+
+// captureReplayTimeout bounds one replay request.
+const captureReplayTimeout = 30 * time.Second
+
+// Redacted replaces a secret in a captured header or body.
+const Redacted = "[REDACTED]"
+
+// ErrCaptureNotFound is returned for an unknown capture ID.
+var ErrCaptureNotFound = errors.New("captured exchange not found")
+
+// ErrCaptureReplayTarget is wrapped by Replay when the replay target is
+// unusable — not an absolute http(s) URL, or the receiver the exchange
+// was captured from — or the captured request body was truncated.
+var ErrCaptureReplayTarget = errors.New("invalid replay target")
+
+// CapturedExchange is one sampled mediation request and what came back.
+// Secrets are redacted before it is stored (see redactHeaders,
+// redactBody); bodies are cut at CaptureBodyLimit.
+type CapturedExchange struct {
+	ID              string
+	MessageID       string
+	PoolCode        string
+	SubscriptionID  string
+	Method          string
+	Target          string
+	RequestHeaders  map[string]string
+	RequestBody     []byte
+	ResponseStatus  int // 0 when no response arrived
+	ResponseHeaders map[string]string
+	ResponseBody    []byte
+	// RequestTruncated/ResponseTruncated: the body was cut at
+	// CaptureBodyLimit.
+	RequestTruncated  bool
+	ResponseTruncated bool
+	// Error is the transport error when no response arrived.
+	Error string
+	// Outcome is the mediation result the response was classified as
+	// (see outcomeName).
+	Outcome    string
+	DurationMs int64
+	CapturedAt time.Time
+}
+
+// CaptureQuery filters a capture listing. Zero fields don't filter.
+type CaptureQuery struct {
+	MessageID string
+	// Limit caps the result; 0 means DefaultCaptureQueryLimit.
+	Limit int
+}
+
+func (q CaptureQuery) limit() int {
+	if q.Limit <= 0 {
+		return DefaultCaptureQueryLimit
+	}
+	return q.Limit
+}
+
+// CaptureStore keeps captured exchanges, bounded: old captures make way
+// for new ones.
+type CaptureStore interface {
+	Record(ctx context.Context, ex CapturedExchange) error
+	// Find returns the captures matching q, newest first.
+	Find(ctx context.Context, q CaptureQuery) ([]CapturedExchange, error)
+	// Get returns one capture, or ErrCaptureNotFound.
+	Get(ctx context.Context, id string) (CapturedExchange, error)
+	Close() error
+}
+
+// TrafficCapture samples the mediator's outbound requests into a
+// CaptureStore, so a receiver's claim about what it was sent can be
+// checked against what went over the wire, and replays a capture
+// against a staging target.
+type TrafficCapture struct {
+	store CaptureStore
+	rate  float64
+	// sample draws from [0, 1); swapped in tests.
+	sample func() float64
+
+	// mu guards queue against a send after Close: Capture holds it for
+	// reading, Close for writing.
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan CapturedExchange
+	done    chan struct{}
+	dropped atomic.Uint64
+	client  *http.Client
+}
+
+// NewTrafficCapture captures a share rate (0 < rate <= 1) of requests
+// into store and starts the writer.
+func NewTrafficCapture(store CaptureStore, rate float64) *TrafficCapture {
+	tc := &TrafficCapture{
+		store:  store,
+		rate:   rate,
+		sample: rand.Float64,
+		queue:  make(chan CapturedExchange, captureQueueSize),
+		done:   make(chan struct{}),
+		client: &http.Client{Timeout: captureReplayTimeout},
+	}
+	go tc.write()
+	return tc
+}
+
+// Sampled reports whether the next request is captured.
+func (tc *TrafficCapture) Sampled() bool { return tc.sample() < tc.rate }
+
+// Rate is the sampled share of requests.
+func (tc *TrafficCapture) Rate() float64 { return tc.rate }
+
+// Dropped counts captures lost to a full write queue since start.
+func (tc *TrafficCapture) Dropped() uint64 { return tc.dropped.Load() }
+
+// Capture queues ex for storage without blocking. After Close it drops
+// ex: a delivery that outlived the drain may still finish.
+func (tc *TrafficCapture) Capture(ex CapturedExchange) {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	if tc.closed {
+		return
+	}
+	select {
+	case tc.queue <- ex:
+	default:
+		tc.dropped.Add(1)
+	}
+}
+
+func (tc *TrafficCapture) write() {
+	defer close(tc.done)
+	for ex := range tc.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := tc.store.Record(ctx, ex); err != nil {
+			slog.Warn("capture: store failed; exchange dropped", "message_id", ex.MessageID, "err", err)
+		}
+		cancel()
+	}
+}
+
+// Close flushes queued captures and closes the store.
+func (tc *TrafficCapture) Close() error {
+	tc.mu.Lock()
+	if tc.closed {
+		tc.mu.Unlock()
+		return nil
+	}
+	tc.closed = true
+	close(tc.queue)
+	tc.mu.Unlock()
+	<-tc.done
+	return tc.store.Close()
+}
+
+// List returns captures matching q.
+func (tc *TrafficCapture) List(ctx context.Context, q CaptureQuery) ([]CapturedExchange, error) {
+	return tc.store.Find(ctx, q)
+}
+
+// Get returns one capture.
+func (tc *TrafficCapture) Get(ctx context.Context, id string) (CapturedExchange, error) {
+	return tc.store.Get(ctx, id)
+}
+
+// ReplayTarget is where a capture is resent and the credentials to send
+// it with. The captured signature and token were redacted, so a replay
+// is re-signed with SigningSecret (fresh timestamp) and authorized with
+// AuthToken when they are set.
+type ReplayTarget struct {
+	URL           string
+	SigningSecret string
+	AuthToken     string
+}
+
+// ReplayResult is the staging target's answer to a replay.
+type ReplayResult struct {
+	Status     int
+	Headers    map[string]string
+	Body       []byte
+	Truncated  bool
+	DurationMs int64
+}
+
+// Replay resends capture id's request to t.URL: the stored body (with
+// any redacted fields still redacted) and the headers that weren't.
+// The receiver the exchange was captured from is refused: replays are
+// for staging.
+func (tc *TrafficCapture) Replay(ctx context.Context, id string, t ReplayTarget) (ReplayResult, error) {
+	ex, err := tc.store.Get(ctx, id)
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ReplayResult{}, fmt.Errorf("%w: %q is not an absolute http(s) URL", ErrCaptureReplayTarget, t.URL)
+	}
+	if sameOrigin(u, ex.Target) {
+		return ReplayResult{}, fmt.Errorf("%w: %s is the receiver the exchange was captured from", ErrCaptureReplayTarget, u.Host)
+	}
+	if ex.RequestTruncated {
+		return ReplayResult{}, fmt.Errorf("%w: captured request body was truncated", ErrCaptureReplayTarget)
+	}
+
+	method := ex.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(ex.RequestBody))
+	if err != nil {
+		return ReplayResult{}, fmt.Errorf("%w: %v", ErrCaptureReplayTarget, err)
+	}
+	for k, v := range ex.RequestHeaders {
+		if v != Redacted {
+			req.Header.Set(k, v)
+		}
+	}
+	if t.SigningSecret != "" {
+		sig, ts := signWebhook(ex.RequestBody, t.SigningSecret)
+		req.Header.Set(SignatureHeader, sig)
+		req.Header.Set(TimestampHeader, ts)
+	}
+	if t.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.AuthToken)
+	}
+
+	start := time.Now()
+	resp, err := tc.client.Do(req)
+	if err != nil {
+		return ReplayResult{}, fmt.Errorf("replay to %s: %w", u.Host, err)
+	}
+	defer resp.Body.Close()
+	body, truncated := readCaptureBody(resp.Body)
+	slog.Info("capture: exchange replayed", "id", id, "message_id", ex.MessageID, "target", u.Host, "status", resp.StatusCode)
+	return ReplayResult{
+		Status:     resp.StatusCode,
+		Headers:    redactHeaders(resp.Header),
+		Body:       body,
+		Truncated:  truncated,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// sameOrigin reports whether u and the captured target share scheme and
+// host.
+func sameOrigin(u *url.URL, target string) bool {
+	t, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, t.Scheme) && strings.EqualFold(u.Host, t.Host)
+}
+
+// captureID is a random capture ID.
+func captureID() string {
+	var b [12]byte
+	_, _ = crand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// readCaptureBody reads up to CaptureBodyLimit bytes of r.
+func readCaptureBody(r io.Reader) ([]byte, bool) {
+	b, _ := io.ReadAll(io.LimitReader(r, CaptureBodyLimit+1))
+	if len(b) > CaptureBodyLimit {
+		return b[:CaptureBodyLimit], true
+	}
+	return b, false
+}
+
+// secretHeaders are always redacted, whatever their name suggests.
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", SignatureHeader}
+
+// secretMarkers mark a header or JSON field name as holding a secret.
+var secretMarkers = []string{"secret", "token", "password", "passwd", "apikey", "api-key", "api_key", "signature", "credential"}
+
+func isSecretName(name string) bool {
+	if slices.ContainsFunc(secretHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+		return true
+	}
+	n := strings.ToLower(name)
+	return slices.ContainsFunc(secretMarkers, func(m string) bool { return strings.Contains(n, m) })
+}
+
+// redactHeaders flattens h, one value per name, with secrets replaced
+// by Redacted.
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, vs := range h {
+		if isSecretName(k) {
+			out[k] = Redacted
+		} else {
+			out[k] = strings.Join(vs, ", ")
+		}
+	}
+	return out
+}
+
+// redactBody replaces the values of secret-named fields in a JSON body.
+// A body that isn't JSON is kept as is: the mediator only sends JSON,
+// and a receiver's non-JSON answer has no fields to go by.
+func redactBody(b []byte) []byte {
+	var v any
+	if len(b) == 0 || json.Unmarshal(b, &v) != nil {
+		return b
+	}
+	if !redactValue(v) {
+		return b
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return out
+}
+
+// redactValue redacts v in place, reporting whether anything changed.
+func redactValue(v any) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]any:
+		for k, x := range t {
+			if isSecretName(k) {
+				t[k] = Redacted
+				changed = true
+			} else if redactValue(x) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, x := range t {
+			if redactValue(x) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// outcomeName names a mediation result for a capture.
+func outcomeName(r common.MediationResult) string {
+	switch r {
+	case common.MediationSuccess:
+		return "SUCCESS"
+	case common.MediationErrorConfig:
+		return "ERROR_CONFIG"
+	case common.MediationErrorProcess:
+		return "ERROR_PROCESS"
+	case common.MediationErrorConnection:
+		return "ERROR_CONNECTION"
+	case common.MediationRateLimited:
+		return "RATE_LIMITED"
+	case common.MediationCircuitOpen:
+		return "CIRCUIT_OPEN"
+	case common.MediationShed:
+		return "SHED"
+	}
+	return "UNKNOWN"
+}
+
+// newCapture starts a capture of req, about to be sent for msg.
+func newCapture(msg *common.Message, req *http.Request, payload []byte) *CapturedExchange {
+	body, truncated := payload, false
+	if len(body) > CaptureBodyLimit {
+		body, truncated = body[:CaptureBodyLimit], true
+	}
+	return &CapturedExchange{
+		ID:               captureID(),
+		MessageID:        msg.ID,
+		PoolCode:         msg.PoolCode,
+		SubscriptionID:   msg.SubscriptionID,
+		Method:           req.Method,
+		Target:           msg.MediationTarget,
+		RequestHeaders:   redactHeaders(req.Header),
+		RequestBody:      redactBody(slices.Clone(body)),
+		RequestTruncated: truncated,
+		CapturedAt:       time.Now().UTC(),
+	}
+}
+
+// recordResponse copies resp's status, headers and the head of its body
+// into ex, leaving resp.Body readable from the start.
+func (ex *CapturedExchange) recordResponse(resp *http.Response) {
+	head, truncated := readCaptureBody(resp.Body)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	ex.ResponseStatus = resp.StatusCode
+	ex.ResponseHeaders = redactHeaders(resp.Header)
+	ex.ResponseBody = redactBody(slices.Clone(head))
+	ex.ResponseTruncated = truncated
+}
+
+// MemoryCaptureStore is the in-process CaptureStore used when no Mongo
+// store is configured: the last maxMemoryCaptures exchanges, lost on
+// restart.
+type MemoryCaptureStore struct {
+	mu      sync.Mutex
+	entries []CapturedExchange // oldest first
+}
+
+// NewMemoryCaptureStore builds an empty store.
+func NewMemoryCaptureStore() *MemoryCaptureStore { return &MemoryCaptureStore{} }
+
+// Record implements CaptureStore.
+func (st *MemoryCaptureStore) Record(_ context.Context, ex CapturedExchange) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if len(st.entries) >= maxMemoryCaptures {
+		st.entries = slices.Delete(st.entries, 0, len(st.entries)-maxMemoryCaptures+1)
+	}
+	st.entries = append(st.entries, ex)
+	return nil
+}
+
+// Find implements CaptureStore.
+func (st *MemoryCaptureStore) Find(_ context.Context, q CaptureQuery) ([]CapturedExchange, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	out := []CapturedExchange{}
+	for i := len(st.entries) - 1; i >= 0 && len(out) < q.limit(); i-- {
+		if q.MessageID == "" || st.entries[i].MessageID == q.MessageID {
+			out = append(out, st.entries[i])
+		}
+	}
+	return out, nil
+}
+
+// Get implements CaptureStore.
+func (st *MemoryCaptureStore) Get(_ context.Context, id string) (CapturedExchange, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for _, e := range st.entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return CapturedExchange{}, ErrCaptureNotFound
+}
+
+// Close implements CaptureStore.
+func (st *MemoryCaptureStore) Close() error { return nil }
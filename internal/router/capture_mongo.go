@@ -0,0 +1,179 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const captureCollection = "router_captures"
+
+// DefaultCaptureCollectionSize is the capped collection's size when none
+// is given.
+const DefaultCaptureCollectionSize = 256 << 20
+
+// MongoCaptureStore keeps captured exchanges in router_captures, a
+// capped collection: once it reaches its size the oldest captures are
+// overwritten, so it needs no retention job. The size is fixed when the
+// collection is created; changing it means dropping the collection.
+type MongoCaptureStore struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+}
+
+type captureDoc struct {
+	ID                string            `bson:"_id"`
+	MessageID         string            `bson:"message_id"`
+	PoolCode          string            `bson:"pool_code,omitempty"`
+	SubscriptionID    string            `bson:"subscription_id,omitempty"`
+	Method            string            `bson:"method"`
+	Target            string            `bson:"target"`
+	RequestHeaders    map[string]string `bson:"request_headers"`
+	RequestBody       []byte            `bson:"request_body"`
+	ResponseStatus    int               `bson:"response_status"`
+	ResponseHeaders   map[string]string `bson:"response_headers,omitempty"`
+	ResponseBody      []byte            `bson:"response_body,omitempty"`
+	RequestTruncated  bool              `bson:"request_truncated"`
+	ResponseTruncated bool              `bson:"response_truncated"`
+	Error             string            `bson:"error,omitempty"`
+	Outcome           string            `bson:"outcome"`
+	DurationMs        int64             `bson:"duration_ms"`
+	CapturedAt        time.Time         `bson:"captured_at"`
+}
+
+// NewMongoCaptureStore connects to uri, targets database dbName and
+// creates the capped collection of sizeBytes (<= 0 means
+// DefaultCaptureCollectionSize) unless it exists.
+func NewMongoCaptureStore(ctx context.Context, uri, dbName string, sizeBytes int64, mc mongoconn.Config) (*MongoCaptureStore, error) {
+	if uri == "" {
+		return nil, errors.New("mongo capture store requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	if sizeBytes <= 0 {
+		sizeBytes = DefaultCaptureCollectionSize
+	}
+	client, err := mongoconn.Connect(ctx, "router-capture", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	db := client.Database(dbName)
+	if err := db.CreateCollection(ctx, captureCollection,
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(sizeBytes)); err != nil {
+		var cmdErr mongo.CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Code != 48 { // NamespaceExists
+			_ = client.Disconnect(ctx)
+			return nil, fmt.Errorf("create capture collection: %w", err)
+		}
+	}
+	st := &MongoCaptureStore{client: client, coll: db.Collection(captureCollection)}
+	if _, err := st.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "message_id", Value: 1}},
+	}); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("create capture indexes: %w", err)
+	}
+	return st, nil
+}
+
+// Record implements CaptureStore.
+func (st *MongoCaptureStore) Record(ctx context.Context, ex CapturedExchange) error {
+	_, err := st.coll.InsertOne(ctx, captureDoc{
+		ID:                ex.ID,
+		MessageID:         ex.MessageID,
+		PoolCode:          ex.PoolCode,
+		SubscriptionID:    ex.SubscriptionID,
+		Method:            ex.Method,
+		Target:            ex.Target,
+		RequestHeaders:    ex.RequestHeaders,
+		RequestBody:       ex.RequestBody,
+		ResponseStatus:    ex.ResponseStatus,
+		ResponseHeaders:   ex.ResponseHeaders,
+		ResponseBody:      ex.ResponseBody,
+		RequestTruncated:  ex.RequestTruncated,
+		ResponseTruncated: ex.ResponseTruncated,
+		Error:             ex.Error,
+		Outcome:           ex.Outcome,
+		DurationMs:        ex.DurationMs,
+		CapturedAt:        ex.CapturedAt,
+	})
+	return err
+}
+
+// Find implements CaptureStore. A capped collection keeps insertion
+// order, so newest first is a reverse natural-order scan.
+func (st *MongoCaptureStore) Find(ctx context.Context, q CaptureQuery) ([]CapturedExchange, error) {
+	filter := bson.M{}
+	if q.MessageID != "" {
+		filter["message_id"] = q.MessageID
+	}
+	opts := options.Find().SetLimit(int64(q.limit()))
+	if q.MessageID == "" {
+		opts.SetSort(bson.D{{Key: "$natural", Value: -1}})
+	} else {
+		opts.SetSort(bson.D{{Key: "captured_at", Value: -1}})
+	}
+	cur, err := st.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []captureDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	out := make([]CapturedExchange, len(docs))
+	for i, d := range docs {
+		out[i] = d.exchange()
+	}
+	return out, nil
+}
+
+// Get implements CaptureStore.
+func (st *MongoCaptureStore) Get(ctx context.Context, id string) (CapturedExchange, error) {
+	var d captureDoc
+	err := st.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&d)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return CapturedExchange{}, ErrCaptureNotFound
+	}
+	if err != nil {
+		return CapturedExchange{}, err
+	}
+	return d.exchange(), nil
+}
+
+// Close implements CaptureStore.
+func (st *MongoCaptureStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return st.client.Disconnect(ctx)
+}
+
+func (d captureDoc) exchange() CapturedExchange {
+	return CapturedExchange{
+		ID:                d.ID,
+		MessageID:         d.MessageID,
+		PoolCode:          d.PoolCode,
+		SubscriptionID:    d.SubscriptionID,
+		Method:            d.Method,
+		Target:            d.Target,
+		RequestHeaders:    d.RequestHeaders,
+		RequestBody:       d.RequestBody,
+		ResponseStatus:    d.ResponseStatus,
+		ResponseHeaders:   d.ResponseHeaders,
+		ResponseBody:      d.ResponseBody,
+		RequestTruncated:  d.RequestTruncated,
+		ResponseTruncated: d.ResponseTruncated,
+		Error:             d.Error,
+		Outcome:           d.Outcome,
+		DurationMs:        d.DurationMs,
+		CapturedAt:        d.CapturedAt.UTC(),
+	}
+}
@@ -0,0 +1,158 @@
+package router
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+func TestRedactHeadersAndBody(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer abc")
+	h.Set(SignatureHeader, "deadbeef")
+	h.Set("X-Api-Key", "k")
+	h.Set("Content-Type", "application/json")
+	got := redactHeaders(h)
+	assert.Equal(t, Redacted, got["Authorization"])
+	assert.Equal(t, Redacted, got[http.CanonicalHeaderKey(SignatureHeader)])
+	assert.Equal(t, Redacted, got["X-Api-Key"])
+	assert.Equal(t, "application/json", got["Content-Type"])
+
+	body := redactBody([]byte(`{"id":"o1","clientSecret":"s","nested":[{"accessToken":"t","n":1}]}`))
+	assert.JSONEq(t, `{"id":"o1","clientSecret":"[REDACTED]","nested":[{"accessToken":"[REDACTED]","n":1}]}`, string(body))
+
+	plain := []byte(`{"messageId":"m1"}`)
+	assert.Equal(t, plain, redactBody(plain), "nothing to redact keeps the bytes")
+	assert.Equal(t, []byte("token=abc"), redactBody([]byte("token=abc")), "non-JSON is kept")
+}
+
+func TestMemoryCaptureStore_CapsAndReturnsNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryCaptureStore()
+	for i := range maxMemoryCaptures + 5 {
+		require.NoError(t, st.Record(ctx, CapturedExchange{ID: fmt.Sprintf("c%d", i), MessageID: fmt.Sprintf("m%d", i%2)}))
+	}
+
+	all, err := st.Find(ctx, CaptureQuery{Limit: maxMemoryCaptures * 2})
+	require.NoError(t, err)
+	require.Len(t, all, maxMemoryCaptures)
+	assert.Equal(t, fmt.Sprintf("c%d", maxMemoryCaptures+4), all[0].ID)
+	assert.Equal(t, "c5", all[len(all)-1].ID, "oldest entries are evicted")
+
+	odd, err := st.Find(ctx, CaptureQuery{MessageID: "m1", Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, odd, 3)
+	for _, ex := range odd {
+		assert.Equal(t, "m1", ex.MessageID)
+	}
+
+	_, err = st.Get(ctx, "c0")
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+}
+
+// TestMediatorCapturesExchange: a sampled delivery is stored with its
+// secrets redacted, and the receiver still reads the full response the
+// capture teed off.
+func TestMediatorCapturesExchange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=1")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{"ok":true,"token":"t"}`)
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCaptureStore()
+	tc := NewTrafficCapture(store, 1)
+	med := NewHTTPMediator(DevMediatorConfig(), NewBreakerRegistry(DefaultBreakerConfig()))
+	med.SetCapture(tc)
+
+	token, secret := "abc", "s3cret"
+	out := med.Mediate(context.Background(), &common.Message{
+		ID: "m1", PoolCode: "P", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL,
+		AuthToken: &token, SigningSecret: &secret,
+	})
+	require.Equal(t, common.MediationSuccess, out.Result)
+	require.NoError(t, tc.Close(), "close flushes the queue")
+
+	exs, err := store.Find(context.Background(), CaptureQuery{MessageID: "m1"})
+	require.NoError(t, err)
+	require.Len(t, exs, 1)
+	ex := exs[0]
+	assert.Equal(t, "SUCCESS", ex.Outcome)
+	assert.Equal(t, "P", ex.PoolCode)
+	assert.Equal(t, http.MethodPost, ex.Method)
+	assert.Equal(t, `{"messageId":"m1"}`, string(ex.RequestBody))
+	assert.Equal(t, Redacted, ex.RequestHeaders["Authorization"])
+	assert.Equal(t, Redacted, ex.RequestHeaders[http.CanonicalHeaderKey(SignatureHeader)])
+	assert.Equal(t, http.StatusOK, ex.ResponseStatus)
+	assert.Equal(t, Redacted, ex.ResponseHeaders["Set-Cookie"])
+	assert.JSONEq(t, `{"ok":true,"token":"[REDACTED]"}`, string(ex.ResponseBody))
+}
+
+func TestTrafficCapture_SamplingAndClose(t *testing.T) {
+	tc := NewTrafficCapture(NewMemoryCaptureStore(), 0.25)
+	tc.sample = func() float64 { return 0.2 }
+	assert.True(t, tc.Sampled())
+	tc.sample = func() float64 { return 0.3 }
+	assert.False(t, tc.Sampled())
+
+	require.NoError(t, tc.Close())
+	tc.Capture(CapturedExchange{ID: "late"}) // must not panic
+	require.NoError(t, tc.Close(), "second close is a no-op")
+}
+
+func TestTrafficCapture_ReplayToStaging(t *testing.T) {
+	ctx := context.Background()
+	var gotSig, gotTs, gotAuth, gotBody, gotType string
+	staging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(SignatureHeader)
+		gotTs = r.Header.Get(TimestampHeader)
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer staging.Close()
+
+	store := NewMemoryCaptureStore()
+	require.NoError(t, store.Record(ctx, CapturedExchange{
+		ID: "c1", MessageID: "m1", Method: http.MethodPost, Target: "https://receiver.example.com/hook",
+		RequestHeaders: map[string]string{"Content-Type": "application/json", "Authorization": Redacted, SignatureHeader: Redacted},
+		RequestBody:    []byte(`{"messageId":"m1"}`),
+	}))
+	require.NoError(t, store.Record(ctx, CapturedExchange{ID: "c2", Target: "https://receiver.example.com/hook", RequestTruncated: true}))
+	tc := NewTrafficCapture(store, 1)
+	defer tc.Close()
+
+	res, err := tc.Replay(ctx, "c1", ReplayTarget{URL: staging.URL, SigningSecret: "staging", AuthToken: "st"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, res.Status)
+	assert.Equal(t, `{"messageId":"m1"}`, gotBody)
+	assert.Equal(t, "application/json", gotType)
+	assert.Equal(t, "Bearer st", gotAuth)
+	mac := hmac.New(sha256.New, []byte("staging"))
+	mac.Write([]byte(gotTs))
+	mac.Write([]byte(gotBody))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), gotSig, "re-signed with the staging secret")
+
+	_, err = tc.Replay(ctx, "c1", ReplayTarget{URL: "https://RECEIVER.example.com/other"})
+	assert.ErrorIs(t, err, ErrCaptureReplayTarget, "the original receiver is refused")
+	_, err = tc.Replay(ctx, "c1", ReplayTarget{URL: "ftp://staging"})
+	assert.ErrorIs(t, err, ErrCaptureReplayTarget)
+	_, err = tc.Replay(ctx, "c2", ReplayTarget{URL: staging.URL})
+	assert.ErrorIs(t, err, ErrCaptureReplayTarget, "a truncated body can't be replayed")
+	_, err = tc.Replay(ctx, "missing", ReplayTarget{URL: staging.URL})
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+}
@@ -193,7 +193,11 @@ func mergeConfigs(sources []sourceConfig) common.RouterConfig {
 func conflictingPool(existing []common.PoolConfig, p common.PoolConfig) bool {
 	for _, e := range existing {
 		if e.Code == p.Code {
-			return e.Concurrency != p.Concurrency || !u32PtrEqual(e.RateLimitPerMinute, p.RateLimitPerMinute)
+			return e.Concurrency != p.Concurrency || !u32PtrEqual(e.RateLimitPerMinute, p.RateLimitPerMinute) ||
+				!u32PtrEqual(e.MaxInFlightPerHost, p.MaxInFlightPerHost) ||
+				!u32PtrEqual(e.MaxInFlightPerSubscription, p.MaxInFlightPerSubscription) ||
+				!e.AdaptiveConcurrency.Equal(p.AdaptiveConcurrency) ||
+				!e.Quarantine.Equal(p.Quarantine)
 		}
 	}
 	return false
@@ -202,7 +206,8 @@ func conflictingPool(existing []common.PoolConfig, p common.PoolConfig) bool {
 func conflictingQueue(existing []common.QueueConfig, q common.QueueConfig) bool {
 	for _, e := range existing {
 		if e.URI == q.URI {
-			return e.Name != q.Name || e.Connections != q.Connections || e.VisibilityTimeout != q.VisibilityTimeout
+			return e.Name != q.Name || e.Connections != q.Connections || e.VisibilityTimeout != q.VisibilityTimeout ||
+				e.Weight != q.Weight
 		}
 	}
 	return false
@@ -215,13 +220,61 @@ func u32PtrEqual(a, b *uint32) bool {
 	return *a == *b
 }
 
+// Invalidate forgets the last fetched config so the next Fetch returns
+// it even if unchanged.
+func (cs *ConfigSource) Invalidate() {
+	cs.mu.Lock()
+	cs.last = nil
+	cs.mu.Unlock()
+}
+
 // Watch polls cs every interval and applies the result to manager.
 // Blocks until ctx is cancelled.
 func Watch(ctx context.Context, cs *ConfigSource, manager *Manager, interval time.Duration) {
-	tick := time.NewTicker(interval)
+	watch(ctx, cs, manager, newLiveInterval(interval))
+}
+
+// liveInterval is a poll interval that can change while watchers run:
+// get hands out the current value with a channel that closes on the next
+// set, so every watcher (one per leadership term) picks the change up.
+type liveInterval struct {
+	mu      sync.Mutex
+	d       time.Duration
+	changed chan struct{}
+}
+
+func newLiveInterval(d time.Duration) *liveInterval {
+	return &liveInterval{d: d, changed: make(chan struct{})}
+}
+
+func (l *liveInterval) get() (time.Duration, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.d, l.changed
+}
+
+func (l *liveInterval) set(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d == l.d {
+		return
+	}
+	l.d = d
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+func watch(ctx context.Context, cs *ConfigSource, manager *Manager, interval *liveInterval) {
+	d, changed := interval.get()
+	tick := time.NewTicker(d)
 	defer tick.Stop()
 
 	apply := func() {
+		if manager.HasPoolOverrides() {
+			// Runtime tuning lasts until the next sync; force it through
+			// even when the source hasn't changed.
+			cs.Invalidate()
+		}
 		cfg, err := cs.Fetch(ctx)
 		if errors.Is(err, ErrUnchanged) {
 			return
@@ -242,6 +295,9 @@ func Watch(ctx context.Context, cs *ConfigSource, manager *Manager, interval tim
 			return
 		case <-tick.C:
 			apply()
+		case <-changed:
+			d, changed = interval.get()
+			tick.Reset(d)
 		}
 	}
 }
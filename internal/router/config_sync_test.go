@@ -2,6 +2,7 @@ package router
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -57,3 +58,30 @@ func TestNewConfigSourceParsesCommaSeparated(t *testing.T) {
 	assert.Equal(t, []string{"http://a/cfg", "http://b/cfg", "http://c/cfg"}, cs.URLs)
 	assert.Equal(t, 12, cs.MaxAttempts)
 }
+
+// TestLiveIntervalWakesEveryWatcher covers a poll-interval reload reaching
+// watchers started before it.
+func TestLiveIntervalWakesEveryWatcher(t *testing.T) {
+	l := newLiveInterval(time.Minute)
+	d1, c1 := l.get()
+	_, c2 := l.get()
+	assert.Equal(t, time.Minute, d1)
+
+	l.set(time.Minute)
+	select {
+	case <-c1:
+		t.Fatal("setting the same interval must not wake watchers")
+	default:
+	}
+
+	l.set(10 * time.Second)
+	for _, c := range []<-chan struct{}{c1, c2} {
+		select {
+		case <-c:
+		default:
+			t.Fatal("watcher not woken")
+		}
+	}
+	d, _ := l.get()
+	assert.Equal(t, 10*time.Second, d)
+}
@@ -0,0 +1,156 @@
+package router
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// keyLimiter caps in-flight messages per key (a target host or a
+// subscription). Waiters for a key are served in arrival order. A zero
+// limit or an empty key never blocks.
+//
+// The pool takes its key slots BEFORE a worker slot, so messages held by
+// their endpoint's cap wait without occupying the pool: a slow endpoint
+// can tie up at most its cap of the pool's workers, and at most that many
+// of its messages are ever queued on the worker semaphore, so the
+// endpoints sharing a pool interleave there rather than one backlog
+// filling it.
+type keyLimiter struct {
+	mu    sync.Mutex
+	limit int
+	keys  map[string]*keySlots
+}
+
+type keySlots struct {
+	inUse   int
+	waiters []chan struct{} // closed when granted a slot
+}
+
+func newKeyLimiter(limit *uint32) *keyLimiter {
+	l := &keyLimiter{keys: make(map[string]*keySlots)}
+	l.setLimit(limit)
+	return l
+}
+
+// setLimit changes the cap. Raising it admits waiters up to the new cap;
+// lowering it lets in-flight messages finish. nil or 0 = unlimited.
+func (l *keyLimiter) setLimit(limit *uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = 0
+	if limit != nil {
+		l.limit = int(*limit)
+	}
+	for key, ks := range l.keys {
+		for len(ks.waiters) > 0 && (l.limit == 0 || ks.inUse < l.limit) {
+			l.grant(ks)
+		}
+		if ks.inUse == 0 {
+			delete(l.keys, key)
+		}
+	}
+}
+
+// acquire takes a slot for key. Returns false when ctx is done first.
+func (l *keyLimiter) acquire(ctx context.Context, key string) bool {
+	if key == "" {
+		return true
+	}
+	l.mu.Lock()
+	ks := l.keys[key]
+	if ks == nil {
+		ks = &keySlots{}
+		l.keys[key] = ks
+	}
+	if l.limit == 0 || ks.inUse < l.limit {
+		ks.inUse++
+		l.mu.Unlock()
+		return true
+	}
+	granted := make(chan struct{})
+	ks.waiters = append(ks.waiters, granted)
+	l.mu.Unlock()
+
+	select {
+	case <-granted:
+		return true
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-granted:
+		// Granted while we were giving up: hand the slot on.
+		l.releaseLocked(key)
+	default:
+		if ks := l.keys[key]; ks != nil {
+			for i, w := range ks.waiters {
+				if w == granted {
+					ks.waiters = append(ks.waiters[:i], ks.waiters[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return false
+}
+
+// release returns key's slot, passing it to the next waiter if any.
+func (l *keyLimiter) release(key string) {
+	if key == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked(key)
+}
+
+func (l *keyLimiter) releaseLocked(key string) {
+	ks := l.keys[key]
+	if ks == nil {
+		return
+	}
+	ks.inUse--
+	for len(ks.waiters) > 0 && (l.limit == 0 || ks.inUse < l.limit) {
+		l.grant(ks)
+	}
+	if ks.inUse <= 0 && len(ks.waiters) == 0 {
+		delete(l.keys, key)
+	}
+}
+
+// grant admits ks's longest waiter. Caller holds l.mu.
+func (l *keyLimiter) grant(ks *keySlots) {
+	w := ks.waiters[0]
+	ks.waiters = ks.waiters[1:]
+	ks.inUse++
+	close(w)
+}
+
+// inFlight is the number of slots held for key.
+func (l *keyLimiter) inFlight(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ks := l.keys[key]; ks != nil {
+		return ks.inUse
+	}
+	return 0
+}
+
+// targetHostKey is the host a message is delivered to: the scheduler's
+// TargetHost when the mediation target is the platform callback, else
+// the mediation target's own host. "" when neither parses.
+func targetHostKey(m *common.Message) string {
+	if m.TargetHost != "" {
+		return strings.ToLower(m.TargetHost)
+	}
+	u, err := url.Parse(m.MediationTarget)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,134 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+func u32(v uint32) *uint32 { return &v }
+
+func TestKeyLimiter_CapsPerKeyInArrivalOrder(t *testing.T) {
+	l := newKeyLimiter(u32(1))
+	ctx := context.Background()
+	require.True(t, l.acquire(ctx, "a"))
+	require.True(t, l.acquire(ctx, "b"), "keys are capped independently")
+
+	order := make(chan int, 2)
+	for i := 1; i <= 2; i++ {
+		go func() {
+			l.acquire(ctx, "a")
+			order <- i
+		}()
+		// Let each waiter queue before the next starts.
+		require.Eventually(t, func() bool {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			return len(l.keys["a"].waiters) == i
+		}, time.Second, time.Millisecond)
+	}
+
+	l.release("a")
+	assert.Equal(t, 1, <-order)
+	l.release("a")
+	assert.Equal(t, 2, <-order)
+	assert.Equal(t, 1, l.inFlight("a"))
+	l.release("a")
+	assert.Zero(t, l.inFlight("a"))
+	assert.True(t, l.acquire(ctx, ""), "no key never blocks")
+}
+
+func TestKeyLimiter_CancelledWaiterGivesUp(t *testing.T) {
+	l := newKeyLimiter(u32(1))
+	require.True(t, l.acquire(context.Background(), "a"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, l.acquire(ctx, "a"))
+
+	l.release("a")
+	assert.Zero(t, l.inFlight("a"), "the cancelled waiter must not be granted the slot")
+}
+
+func TestKeyLimiter_RaisingLimitAdmitsWaiters(t *testing.T) {
+	l := newKeyLimiter(u32(1))
+	ctx := context.Background()
+	require.True(t, l.acquire(ctx, "a"))
+	done := make(chan struct{})
+	go func() {
+		l.acquire(ctx, "a")
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.keys["a"].waiters) == 1
+	}, time.Second, time.Millisecond)
+
+	l.setLimit(nil)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("removing the cap must admit the waiter")
+	}
+	assert.Equal(t, 2, l.inFlight("a"))
+}
+
+func TestTargetHostKey(t *testing.T) {
+	assert.Equal(t, "api.example.com", targetHostKey(&common.Message{MediationTarget: "https://API.example.com/hook"}))
+	assert.Equal(t, "receiver.test:8443", targetHostKey(&common.Message{
+		MediationTarget: "http://platform/api/dispatch/process", TargetHost: "receiver.test:8443",
+	}))
+}
+
+// blockingMediator holds every delivery to slowHost until release is
+// closed, and counts the rest.
+type blockingMediator struct {
+	slowHost string
+	release  chan struct{}
+	fast     atomic.Int32
+}
+
+func (m *blockingMediator) Mediate(ctx context.Context, msg *common.Message) common.MediationOutcome {
+	if msg.TargetHost == m.slowHost {
+		<-m.release
+	} else {
+		m.fast.Add(1)
+	}
+	return common.Success()
+}
+
+func TestPool_SlowEndpointDoesNotStarveOthers(t *testing.T) {
+	med := &blockingMediator{slowHost: "slow.test", release: make(chan struct{})}
+	c := &grConsumer{id: "q1"}
+	p := NewPool(common.PoolConfig{Code: "TEST", Concurrency: 4, MaxInFlightPerHost: u32(2)},
+		med, NewInFlightTracker(), func(string) queue.Consumer { return c })
+	ctx := context.Background()
+
+	send := func(id, sub, host string) {
+		m := grMsg(id, "http://platform/api/dispatch/process")
+		m.Message.SubscriptionID = sub
+		m.Message.TargetHost = host
+		p.submit(ctx, m)
+	}
+	// A backlog for the slow endpoint arrives first…
+	for i := range 10 {
+		send(fmt.Sprintf("slow_%d", i), "sub_slow", "slow.test")
+	}
+	// …and the other subscription still gets through on the free workers.
+	for i := range 5 {
+		send(fmt.Sprintf("fast_%d", i), "sub_fast", "fast.test")
+	}
+	grWaitFor(t, func() bool { return med.fast.Load() == 5 }, 2*time.Second)
+	assert.Equal(t, 2, p.hostLimit.inFlight("slow.test"), "the slow host holds only its cap")
+
+	close(med.release)
+	grWaitFor(t, func() bool { return c.acks.Load() == 15 }, 2*time.Second)
+}
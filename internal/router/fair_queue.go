@@ -0,0 +1,148 @@
+package router
+
+import (
+	"context"
+	"sync"
+)
+
+// fairQueue shares a pool's worker slots between subscriptions. Waiters
+// on the semaphore are served in arrival order, so without it a
+// subscription that dumps a backlog into a pool holds every slot until
+// the backlog drains while the pool's other subscriptions wait behind
+// it.
+//
+// Only one normal-priority message at a time — the turn holder — waits
+// on the semaphore. The rest wait here, in a FIFO per subscription, and
+// the turn passes between subscriptions weighted round-robin: up to
+// Message.SubscriptionWeight messages from one, then on to the next. A
+// subscription's own messages keep their order, so message groups (one
+// message in flight per group) are untouched. A subscription's round
+// ends when it has used its weight or has nobody waiting when the turn
+// reaches it; until then it keeps its place and count, so a subscription
+// whose next message only arrives once the last was granted (one
+// message group) still gets its weight. HIGH messages bypass it; see
+// workerLimiter.
+type fairQueue struct {
+	mu   sync.Mutex
+	held bool // a turn holder is waiting on (or taking) a slot
+	subs map[string]*fairSub
+	// ring lists the subscriptions with waiters or a round in progress,
+	// in serving order; next indexes the one being served.
+	ring []string
+	next int
+}
+
+type fairSub struct {
+	weight  int
+	served  int             // turns granted in the current round
+	waiters []chan struct{} // closed when granted the turn
+}
+
+func newFairQueue() *fairQueue {
+	return &fairQueue{subs: make(map[string]*fairSub)}
+}
+
+// acquire waits for the turn to take a worker slot for subscription sub,
+// served weight messages per round. The caller must call pass once it
+// holds the slot or has given up on it. Returns false, not holding the
+// turn, when ctx is done first.
+func (q *fairQueue) acquire(ctx context.Context, sub string, weight uint32) bool {
+	q.mu.Lock()
+	if !q.held && len(q.ring) == 0 {
+		q.held = true
+		q.mu.Unlock()
+		return true
+	}
+	fs := q.subs[sub]
+	if fs == nil {
+		fs = &fairSub{}
+		q.subs[sub] = fs
+		q.ring = append(q.ring, sub)
+	}
+	fs.weight = max(1, int(weight))
+	granted := make(chan struct{})
+	fs.waiters = append(fs.waiters, granted)
+	q.mu.Unlock()
+
+	select {
+	case <-granted:
+		return true
+	case <-ctx.Done():
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	select {
+	case <-granted:
+		// Granted while we were giving up: hand the turn on.
+		q.passLocked()
+	default:
+		q.removeLocked(sub, granted)
+	}
+	return false
+}
+
+// pass hands the turn to the next waiter, if any.
+func (q *fairQueue) pass() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.passLocked()
+}
+
+func (q *fairQueue) passLocked() {
+	for len(q.ring) > 0 {
+		if q.next >= len(q.ring) {
+			q.next = 0
+		}
+		key := q.ring[q.next]
+		fs := q.subs[key]
+		if fs.served >= fs.weight || len(fs.waiters) == 0 {
+			// Its round is over.
+			fs.served = 0
+			if len(fs.waiters) == 0 {
+				// Out of the ring until it has waiters again; next now
+				// indexes the following subscription.
+				q.dropLocked(key)
+			} else {
+				q.next++
+			}
+			continue
+		}
+		w := fs.waiters[0]
+		fs.waiters = fs.waiters[1:]
+		fs.served++
+		close(w)
+		return
+	}
+	q.held = false
+}
+
+// removeLocked withdraws a waiter that gave up.
+func (q *fairQueue) removeLocked(sub string, w chan struct{}) {
+	fs := q.subs[sub]
+	if fs == nil {
+		return
+	}
+	for i, x := range fs.waiters {
+		if x == w {
+			fs.waiters = append(fs.waiters[:i], fs.waiters[i+1:]...)
+			break
+		}
+	}
+	if len(fs.waiters) == 0 && fs.served == 0 {
+		// Mid-round, it stays for passLocked to close the round.
+		q.dropLocked(sub)
+	}
+}
+
+func (q *fairQueue) dropLocked(sub string) {
+	delete(q.subs, sub)
+	for i, k := range q.ring {
+		if k == sub {
+			q.ring = append(q.ring[:i], q.ring[i+1:]...)
+			if i < q.next {
+				q.next--
+			}
+			return
+		}
+	}
+}
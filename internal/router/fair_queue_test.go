@@ -0,0 +1,156 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+func TestFairQueue_WeightedRoundRobin(t *testing.T) {
+	q := newFairQueue()
+	ctx := context.Background()
+	require.True(t, q.acquire(ctx, "holder", 1), "an idle queue grants the turn at once")
+
+	order := make(chan string, 6)
+	enqueue := func(id, sub string, weight uint32, queued int) {
+		go func() {
+			q.acquire(ctx, sub, weight)
+			order <- id
+		}()
+		require.Eventually(t, func() bool {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			return q.subs[sub] != nil && len(q.subs[sub].waiters) == queued
+		}, time.Second, time.Millisecond)
+	}
+	enqueue("a1", "A", 2, 1)
+	enqueue("a2", "A", 2, 2)
+	enqueue("b1", "B", 1, 1)
+	enqueue("a3", "A", 2, 3)
+	enqueue("a4", "A", 2, 4)
+	enqueue("b2", "B", 1, 2)
+
+	var got []string
+	for range 6 {
+		q.pass()
+		got = append(got, <-order)
+	}
+	assert.Equal(t, []string{"a1", "a2", "b1", "a3", "a4", "b2"}, got)
+
+	q.pass()
+	assert.False(t, q.held, "the last pass with nobody waiting frees the turn")
+}
+
+// TestFairQueue_WeightHoldsWithOneWaiterAtATime pins the round state of a
+// subscription whose next message only queues once the last was granted
+// (a single message group): it must still get its weight per round, not
+// lose its count every time it runs out of waiters.
+func TestFairQueue_WeightHoldsWithOneWaiterAtATime(t *testing.T) {
+	q := newFairQueue()
+	ctx := context.Background()
+	require.True(t, q.acquire(ctx, "holder", 1))
+
+	order := make(chan string, 1)
+	weights := map[string]uint32{"A": 3, "B": 1}
+	next := map[string]int{}
+	enqueue := func(sub string) {
+		next[sub]++
+		id := fmt.Sprintf("%s%d", sub, next[sub])
+		go func() {
+			q.acquire(ctx, sub, weights[sub])
+			order <- id
+		}()
+		require.Eventually(t, func() bool {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			return q.subs[sub] != nil && len(q.subs[sub].waiters) == 1
+		}, time.Second, time.Millisecond)
+	}
+	enqueue("A")
+	enqueue("B")
+
+	var got []string
+	for range 8 {
+		q.pass()
+		id := <-order
+		got = append(got, id)
+		enqueue(id[:1]) // the group's next message
+	}
+	assert.Equal(t, []string{"A1", "A2", "A3", "B1", "A4", "A5", "A6", "B2"}, got)
+}
+
+func TestFairQueue_CancelledWaiterGivesUp(t *testing.T) {
+	q := newFairQueue()
+	require.True(t, q.acquire(context.Background(), "A", 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, q.acquire(ctx, "B", 1))
+	assert.Empty(t, q.ring, "the cancelled waiter leaves the ring")
+
+	q.pass()
+	assert.False(t, q.held, "the turn must not be handed to the cancelled waiter")
+	assert.True(t, q.acquire(context.Background(), "B", 1))
+}
+
+// recordingMediator holds every delivery until release is closed and
+// records the order they were made in.
+type recordingMediator struct {
+	release chan struct{}
+	mu      sync.Mutex
+	order   []string
+}
+
+func (m *recordingMediator) Mediate(_ context.Context, msg *common.Message) common.MediationOutcome {
+	m.mu.Lock()
+	m.order = append(m.order, msg.SubscriptionID)
+	m.mu.Unlock()
+	<-m.release
+	return common.Success()
+}
+
+func TestPool_BacklogDoesNotStarveOtherSubscriptions(t *testing.T) {
+	med := &recordingMediator{release: make(chan struct{})}
+	c := &grConsumer{id: "q1"}
+	p := NewPool(common.PoolConfig{Code: "TEST", Concurrency: 1},
+		med, NewInFlightTracker(), func(string) queue.Consumer { return c })
+	ctx := context.Background()
+
+	send := func(id, sub string) {
+		m := grMsg(id, "http://platform/api/dispatch/process")
+		m.Message.SubscriptionID = sub
+		p.submit(ctx, m)
+	}
+	queued := func(sub string) int {
+		p.fair.mu.Lock()
+		defer p.fair.mu.Unlock()
+		if fs := p.fair.subs[sub]; fs != nil {
+			return len(fs.waiters)
+		}
+		return 0
+	}
+	// A chatty subscription's backlog arrives first: one in flight, one
+	// holding the turn, the rest queued behind them…
+	for i := range 20 {
+		send(fmt.Sprintf("chatty_%d", i), "sub_chatty")
+	}
+	grWaitFor(t, func() bool { return queued("sub_chatty") == 18 }, 2*time.Second)
+	// …and a quiet subscription's message is served on the next round
+	// rather than after the whole backlog.
+	send("quiet_0", "sub_quiet")
+	grWaitFor(t, func() bool { return queued("sub_quiet") == 1 }, 2*time.Second)
+
+	close(med.release)
+	grWaitFor(t, func() bool { return c.acks.Load() == 21 }, 2*time.Second)
+	med.mu.Lock()
+	defer med.mu.Unlock()
+	assert.Less(t, slices.Index(med.order, "sub_quiet"), 4)
+}
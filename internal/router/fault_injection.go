@@ -0,0 +1,226 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// FaultKind is a failure the mediator's fault injector can simulate.
+type FaultKind string
+
+const (
+	// FaultTimeout holds the request until its deadline expires.
+	FaultTimeout FaultKind = "timeout"
+	// FaultServerError answers 500, 502, 503 or 504 without calling the
+	// target.
+	FaultServerError FaultKind = "5xx"
+	// FaultSlow delays the request by the rule's SlowDelay, then delivers
+	// it.
+	FaultSlow FaultKind = "slow"
+	// FaultReset fails the request with a connection reset.
+	FaultReset FaultKind = "reset"
+)
+
+// DefaultFaultSlowDelay is the delay of a "slow" fault that names none.
+const DefaultFaultSlowDelay = 5 * time.Second
+
+// FaultRule injects faults into a share of the deliveries to one target
+// host. Dev mode only: BuildMediatorConfig refuses rules otherwise.
+type FaultRule struct {
+	// Host is the target's host[:port], lowercased; "*" covers every
+	// target without a rule of its own.
+	Host string
+	// Rate is the share of attempts that fail, in (0, 1].
+	Rate float64
+	// Kinds are the faults to pick from, uniformly, per failed attempt.
+	Kinds     []FaultKind
+	SlowDelay time.Duration
+}
+
+// ParseFaultRules parses a ";"-separated list of HOST:PCT%:KIND[,KIND...]
+// rules, e.g. "billing.local:8080:20%:5xx,reset;*:5%:slow=2s". A slow
+// fault takes its delay after "=" (default DefaultFaultSlowDelay).
+func ParseFaultRules(spec string) ([]FaultRule, error) {
+	var out []FaultRule
+	seen := map[string]bool{}
+	for entry := range strings.SplitSeq(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rest, kinds, ok := cutLast(entry, ":")
+		host, pct, ok2 := cutLast(rest, ":")
+		host = strings.ToLower(strings.TrimSpace(host))
+		pct, ok3 := strings.CutSuffix(strings.TrimSpace(pct), "%")
+		if !ok || !ok2 || !ok3 || host == "" {
+			return nil, fmt.Errorf("fault rule %q: want HOST:PCT%%:KIND[,KIND...]", entry)
+		}
+		rate, err := strconv.ParseFloat(pct, 64)
+		if err != nil || rate <= 0 || rate > 100 {
+			return nil, fmt.Errorf("fault rule %q: percentage must be in (0, 100]", entry)
+		}
+		rule := FaultRule{Host: host, Rate: rate / 100, SlowDelay: DefaultFaultSlowDelay}
+		for k := range strings.SplitSeq(kinds, ",") {
+			name, arg, hasArg := strings.Cut(strings.TrimSpace(k), "=")
+			kind := FaultKind(strings.ToLower(name))
+			switch kind {
+			case FaultTimeout, FaultServerError, FaultReset:
+				if hasArg {
+					return nil, fmt.Errorf("fault rule %q: %s takes no argument", entry, kind)
+				}
+			case FaultSlow:
+				if hasArg {
+					d, err := time.ParseDuration(arg)
+					if err != nil || d <= 0 {
+						return nil, fmt.Errorf("fault rule %q: slow delay must be a positive duration", entry)
+					}
+					rule.SlowDelay = d
+				}
+			default:
+				return nil, fmt.Errorf("fault rule %q: unknown fault %q (want timeout, 5xx, slow or reset)", entry, name)
+			}
+			rule.Kinds = append(rule.Kinds, kind)
+		}
+		if seen[host] {
+			return nil, fmt.Errorf("fault rule for %q defined twice", host)
+		}
+		seen[host] = true
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// faultTargetKey carries the delivery's target host to the fault
+// transport, so scheduler-dispatched messages (whose request goes to the
+// platform callback) match on their receiver rather than the platform.
+type faultTargetKey struct{}
+
+func withFaultTarget(ctx context.Context, host string) context.Context {
+	if host == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, faultTargetKey{}, host)
+}
+
+// faultTransport wraps a slot's transport and fails a share of its
+// requests as configured. The failures surface through the real client,
+// so the mediator classifies, retries and feeds the breaker exactly as it
+// would for a misbehaving receiver.
+type faultTransport struct {
+	next     http.RoundTripper
+	rules    map[string]FaultRule
+	fallback *FaultRule
+	// roll returns a value in [0, 1); pick one in [0, n). Swapped in tests.
+	roll func() float64
+	pick func(n int) int
+}
+
+func newFaultTransport(next http.RoundTripper, rules []FaultRule) *faultTransport {
+	t := &faultTransport{next: next, rules: make(map[string]FaultRule), roll: rand.Float64, pick: rand.IntN}
+	for _, r := range rules {
+		if r.Host == "*" {
+			t.fallback = &r
+			continue
+		}
+		t.rules[r.Host] = r
+	}
+	return t
+}
+
+func (t *faultTransport) rule(req *http.Request) (FaultRule, bool) {
+	host, _ := req.Context().Value(faultTargetKey{}).(string)
+	if host == "" {
+		host = strings.ToLower(req.URL.Host)
+	}
+	if r, ok := t.rules[host]; ok {
+		return r, true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		if r, ok := t.rules[h]; ok {
+			return r, true
+		}
+	}
+	if t.fallback != nil {
+		return *t.fallback, true
+	}
+	return FaultRule{}, false
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule, ok := t.rule(req)
+	if !ok || len(rule.Kinds) == 0 || t.roll() >= rule.Rate {
+		return t.next.RoundTrip(req)
+	}
+	kind := rule.Kinds[t.pick(len(rule.Kinds))]
+	slog.Debug("injecting delivery fault", "url", req.URL.String(), "fault", kind)
+
+	ctx := req.Context()
+	switch kind {
+	case FaultSlow:
+		timer := time.NewTimer(rule.SlowDelay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return t.next.RoundTrip(req)
+		case <-ctx.Done():
+			closeBody(req)
+			return nil, ctx.Err()
+		}
+	case FaultTimeout:
+		closeBody(req)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case FaultReset:
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	default: // FaultServerError
+		closeBody(req)
+		status := []int{
+			http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		}[t.pick(4)]
+		body := "injected fault"
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/plain"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+}
+
+// CloseIdleConnections forwards to the wrapped transport so the host
+// pool can still release an evicted slot's connections.
+func (t *faultTransport) CloseIdleConnections() {
+	if c, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,162 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+)
+
+func TestParseFaultRules(t *testing.T) {
+	rules, err := router.ParseFaultRules(" Billing.local:8080:20%:5xx,reset ; *:5%:slow=2s,timeout;")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "billing.local:8080", rules[0].Host)
+	assert.InDelta(t, 0.2, rules[0].Rate, 1e-9)
+	assert.Equal(t, []router.FaultKind{router.FaultServerError, router.FaultReset}, rules[0].Kinds)
+	assert.Equal(t, router.DefaultFaultSlowDelay, rules[0].SlowDelay)
+	assert.Equal(t, "*", rules[1].Host)
+	assert.Equal(t, []router.FaultKind{router.FaultSlow, router.FaultTimeout}, rules[1].Kinds)
+	assert.Equal(t, 2*time.Second, rules[1].SlowDelay)
+
+	none, err := router.ParseFaultRules("")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	for _, bad := range []string{
+		"host:20%",               // no kinds
+		"host:20:5xx",            // no %
+		"host:0%:5xx",            // rate out of range
+		"host:150%:5xx",          // rate out of range
+		"host:20%:explode",       // unknown kind
+		"host:20%:slow=soon",     // bad delay
+		"host:20%:reset=1s",      // argument on a plain kind
+		"a:5%:5xx;A:10%:timeout", // duplicate host
+	} {
+		_, err := router.ParseFaultRules(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestBuildMediatorConfig_FaultsDevOnly(t *testing.T) {
+	rules, err := router.ParseFaultRules("*:50%:5xx")
+	require.NoError(t, err)
+
+	_, err = router.BuildMediatorConfig(false, router.MediatorOverrides{Faults: rules})
+	require.Error(t, err, "fault injection must be refused outside dev mode")
+
+	cfg, err := router.BuildMediatorConfig(true, router.MediatorOverrides{Faults: rules})
+	require.NoError(t, err)
+	assert.Equal(t, rules, cfg.Faults)
+}
+
+// faultMediator builds a single-attempt dev mediator with spec applied,
+// plus a target that counts the requests that reach it.
+func faultMediator(t *testing.T, spec string) (*router.HTTPMediator, *httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	rules, err := router.ParseFaultRules(spec)
+	require.NoError(t, err)
+	cfg, err := router.BuildMediatorConfig(true, router.MediatorOverrides{Timeout: 200 * time.Millisecond, Faults: rules})
+	require.NoError(t, err)
+	cfg.MaxRetries = 1
+	m := router.NewHTTPMediator(cfg, router.NewBreakerRegistry(router.DefaultBreakerConfig()))
+	t.Cleanup(m.Close)
+	return m, srv, &hits
+}
+
+func faultMsg(target string) *common.Message {
+	return &common.Message{ID: "msg_FAULT", MediationType: common.MediationTypeHTTP, MediationTarget: target}
+}
+
+func TestFaultInjection_Kinds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("5xx", func(t *testing.T) {
+		m, srv, hits := faultMediator(t, "*:100%:5xx")
+		out := m.Mediate(ctx, faultMsg(srv.URL))
+		assert.Equal(t, common.MediationErrorProcess, out.Result)
+		assert.GreaterOrEqual(t, out.StatusCode, 500)
+		assert.Zero(t, hits.Load(), "a 5xx fault must not reach the target")
+	})
+	t.Run("reset", func(t *testing.T) {
+		m, srv, hits := faultMediator(t, "*:100%:reset")
+		out := m.Mediate(ctx, faultMsg(srv.URL))
+		assert.Equal(t, common.MediationErrorConnection, out.Result)
+		assert.Contains(t, out.ErrorMessage, "connection reset")
+		assert.Zero(t, hits.Load())
+	})
+	t.Run("timeout", func(t *testing.T) {
+		m, srv, hits := faultMediator(t, "*:100%:timeout")
+		start := time.Now()
+		out := m.Mediate(ctx, faultMsg(srv.URL))
+		assert.Equal(t, common.MediationErrorConnection, out.Result)
+		assert.Equal(t, "Request timeout", out.ErrorMessage)
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "held until the request deadline")
+		assert.Zero(t, hits.Load())
+	})
+	t.Run("slow", func(t *testing.T) {
+		m, srv, hits := faultMediator(t, "*:100%:slow=50ms")
+		start := time.Now()
+		out := m.Mediate(ctx, faultMsg(srv.URL))
+		assert.Equal(t, common.MediationSuccess, out.Result)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.EqualValues(t, 1, hits.Load(), "a slow fault still delivers")
+	})
+}
+
+func TestFaultInjection_PerTarget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A rule for another host leaves this target alone.
+	m, srv, hits := faultMediator(t, "elsewhere.example:100%:reset")
+	assert.Equal(t, common.MediationSuccess, m.Mediate(ctx, faultMsg(srv.URL)).Result)
+	assert.EqualValues(t, 1, hits.Load())
+
+	// A rule naming the target's host (with or without the port) applies.
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	m, srv, _ = faultMediator(t, u.Hostname()+":100%:reset")
+	assert.Equal(t, common.MediationErrorConnection, m.Mediate(ctx, faultMsg(srv.URL)).Result)
+
+	// A scheduler-dispatched message matches on its receiver's host, not
+	// the callback it is posted to.
+	m, srv, hits = faultMediator(t, "receiver.example:100%:5xx")
+	msg := faultMsg(srv.URL)
+	msg.TargetHost = "receiver.example"
+	assert.Equal(t, common.MediationErrorProcess, m.Mediate(ctx, msg).Result)
+	assert.Zero(t, hits.Load())
+}
+
+func TestFaultInjection_TripsBreaker(t *testing.T) {
+	m, srv, hits := faultMediator(t, "*:100%:5xx")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var last common.MediationOutcome
+	for range 50 {
+		last = m.Mediate(ctx, faultMsg(srv.URL))
+		if last.Result == common.MediationCircuitOpen {
+			break
+		}
+	}
+	assert.Equal(t, common.MediationCircuitOpen, last.Result, "injected failures must open the breaker")
+	assert.Zero(t, hits.Load())
+}
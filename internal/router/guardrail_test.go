@@ -113,7 +113,8 @@ func grWaitFor(t *testing.T, cond func() bool, timeout time.Duration) {
 func TestGuardrail_ResolutionOnSuccess(t *testing.T) {
 	c := &grConsumer{id: "q1"}
 	p := grPool(&grMediator{outcome: common.Success()}, c)
-	res, _ := p.processOne(context.Background(), grMsg("evt_ok", "http://t/ok"))
+	m := grMsg("evt_ok", "http://t/ok")
+	res, _ := p.processOne(context.Background(), &m)
 	if res != processDone || c.acks.Load() != 1 || c.nacks.Load() != 0 || c.defers.Load() != 0 {
 		t.Fatalf("success must ACK exactly once and report processDone; got res=%d acks=%d nacks=%d defers=%d",
 			res, c.acks.Load(), c.nacks.Load(), c.defers.Load())
@@ -123,7 +124,8 @@ func TestGuardrail_ResolutionOnSuccess(t *testing.T) {
 func TestGuardrail_RetryOnProcessError(t *testing.T) {
 	c := &grConsumer{id: "q1"}
 	p := grPool(&grMediator{outcome: common.ErrorProcess(30, "5xx")}, c)
-	res, delay := p.processOne(context.Background(), grMsg("evt_5xx", "http://t/5xx"))
+	m := grMsg("evt_5xx", "http://t/5xx")
+	res, delay := p.processOne(context.Background(), &m)
 	if res != processRetry {
 		t.Fatalf("process error must retry in-pipeline; got res=%d", res)
 	}
@@ -140,7 +142,8 @@ func TestGuardrail_RetryOnCircuitOpen(t *testing.T) {
 	// the pool must retry in-pipeline (no broker action) after the reset delay.
 	c := &grConsumer{id: "q1"}
 	p := grPool(&grMediator{outcome: common.CircuitOpen(5)}, c)
-	res, delay := p.processOne(context.Background(), grMsg("evt_cb", "http://t/cb"))
+	m := grMsg("evt_cb", "http://t/cb")
+	res, delay := p.processOne(context.Background(), &m)
 	if res != processRetry || c.total() != 0 {
 		t.Fatalf("circuit-open must retry in-pipeline with no broker action; got res=%d terminal=%d",
 			res, c.total())
@@ -156,7 +159,8 @@ func TestGuardrail_RetryOnPanic(t *testing.T) {
 	// internally and returns processRetry with no broker action.
 	c := &grConsumer{id: "q1"}
 	p := grPool(&grMediator{panicMsg: "boom"}, c)
-	res, _ := p.processOne(context.Background(), grMsg("evt_panic", "http://t/panic"))
+	m := grMsg("evt_panic", "http://t/panic")
+	res, _ := p.processOne(context.Background(), &m)
 	if res != processRetry {
 		t.Fatalf("a panic mid-mediation must be recovered and retried in-pipeline; got res=%d", res)
 	}
@@ -165,11 +169,51 @@ func TestGuardrail_RetryOnPanic(t *testing.T) {
 	}
 }
 
+func TestGuardrail_WindowClosedAcksWithoutMediating(t *testing.T) {
+	// A message whose delivery window has closed is released to the
+	// scheduler: ACKed, never mediated.
+	c := &grConsumer{id: "q1"}
+	med := &grMediator{outcome: common.Success()}
+	p := grPool(med, c)
+	m := grMsg("evt_closed", "http://t/closed")
+	closed := time.Now().Add(-time.Minute)
+	m.Message.WindowClosesAt = &closed
+	res, _ := p.processOne(context.Background(), &m)
+	if res != processDone || c.acks.Load() != 1 || med.called.Load() {
+		t.Fatalf("closed window must ACK without mediating; got res=%d acks=%d mediated=%v",
+			res, c.acks.Load(), med.called.Load())
+	}
+}
+
+func TestGuardrail_RetryPastWindowCloseAcks(t *testing.T) {
+	// The 30s retry floor lands after the window closes: don't hold the
+	// message for it.
+	c := &grConsumer{id: "q1"}
+	p := grPool(&grMediator{outcome: common.ErrorProcess(30, "5xx")}, c)
+	m := grMsg("evt_late", "http://t/late")
+	closes := time.Now().Add(10 * time.Second)
+	m.Message.WindowClosesAt = &closes
+	res, _ := p.processOne(context.Background(), &m)
+	if res != processDone || c.acks.Load() != 1 {
+		t.Fatalf("a retry past the window close must ACK; got res=%d acks=%d", res, c.acks.Load())
+	}
+
+	// A retry inside the window is held as usual.
+	c = &grConsumer{id: "q1"}
+	p = grPool(&grMediator{outcome: common.ErrorProcess(30, "5xx")}, c)
+	m = grMsg("evt_early", "http://t/early")
+	closes = time.Now().Add(time.Hour)
+	m.Message.WindowClosesAt = &closes
+	if res, _ := p.processOne(context.Background(), &m); res != processRetry || c.total() != 0 {
+		t.Fatalf("a retry inside the window must stay in-pipeline; got res=%d terminal=%d", res, c.total())
+	}
+}
+
 // --- Pool (marquee): the data-race surface under contention ---
 
 // Hammer submit() from many goroutines across both dispatch paths (IMMEDIATE
 // goroutine-per-message + ordered per-group drainers) and overlapping groups.
-// Exercises groupQs (p.mu), the swappable semaphore and the atomic counters
+// Exercises groupQs (p.mu), the resizable worker limit and the atomic counters
 // concurrently. Under -race, any future edit that drops a lock fails here (or
 // panics on concurrent map write). All messages succeed here, so the invariant
 // is: every submitted message is ACKed exactly once (no loss, no double-ack).
@@ -64,6 +64,9 @@ type HealthService struct {
 	poolCounters     map[string]*rollingCounter
 	consumerLastPoll map[string]time.Time
 	consumerRunning  map[string]bool
+	// checkFailures is the consecutive non-UP streak per component
+	// check name; see RunChecks.
+	checkFailures map[string]uint32
 }
 
 // NewHealthService builds a service. Pass nil warningService to use a
@@ -82,6 +85,7 @@ func NewHealthService(cfg HealthServiceConfig, ws *WarningService) *HealthServic
 		poolCounters:     make(map[string]*rollingCounter),
 		consumerLastPoll: make(map[string]time.Time),
 		consumerRunning:  make(map[string]bool),
+		checkFailures:    make(map[string]uint32),
 	}
 }
 
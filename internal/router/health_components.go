@@ -0,0 +1,119 @@
+package router
+
+import (
+	"fmt"
+	"time"
+)
+
+// ComponentStatus is the per-check verdict on /health. DEGRADED means
+// the component still serves traffic but below par (e.g. a lagging
+// stream projection); DOWN means it has stopped doing its job.
+type ComponentStatus string
+
+const (
+	ComponentUp       ComponentStatus = "UP"
+	ComponentDegraded ComponentStatus = "DEGRADED"
+	ComponentDown     ComponentStatus = "DOWN"
+)
+
+// severity orders statuses so the aggregate can pick the worst.
+func (c ComponentStatus) severity() int {
+	switch c {
+	case ComponentDown:
+		return 2
+	case ComponentDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ComponentCheck is one named health probe. Check returns the verdict
+// plus an optional human-readable detail shown in the dashboard modal.
+type ComponentCheck struct {
+	Name  string
+	Check func() (ComponentStatus, string)
+}
+
+// ComponentHealth is the result of running one ComponentCheck.
+// ConsecutiveFailures counts back-to-back non-UP results for the same
+// check name and resets on the first UP.
+type ComponentHealth struct {
+	Name                string          `json:"name"`
+	Status              ComponentStatus `json:"status"`
+	LatencyMs           float64         `json:"latencyMs"`
+	ConsecutiveFailures uint32          `json:"consecutiveFailures"`
+	Detail              string          `json:"detail,omitempty"`
+}
+
+// RunChecks executes the checks in order, timing each and updating the
+// per-name failure streaks kept on the service.
+func (s *HealthService) RunChecks(checks []ComponentCheck) []ComponentHealth {
+	out := make([]ComponentHealth, 0, len(checks))
+	for _, c := range checks {
+		start := time.Now()
+		status, detail := c.Check()
+		latency := time.Since(start)
+
+		s.mu.Lock()
+		if status == ComponentUp {
+			delete(s.checkFailures, c.Name)
+		} else {
+			s.checkFailures[c.Name]++
+		}
+		failures := s.checkFailures[c.Name]
+		s.mu.Unlock()
+
+		out = append(out, ComponentHealth{
+			Name:                c.Name,
+			Status:              status,
+			LatencyMs:           float64(latency.Microseconds()) / 1000,
+			ConsecutiveFailures: failures,
+			Detail:              detail,
+		})
+	}
+	return out
+}
+
+// AggregateComponentStatus returns the worst status across components,
+// or UP for an empty slice.
+func AggregateComponentStatus(components []ComponentHealth) ComponentStatus {
+	worst := ComponentUp
+	for _, c := range components {
+		if c.Status.severity() > worst.severity() {
+			worst = c.Status
+		}
+	}
+	return worst
+}
+
+// ReportChecks derives the built-in component checks (pools, consumers,
+// warnings) from a HealthReport. Any component with every member
+// unhealthy is DOWN; a partial failure is DEGRADED.
+func ReportChecks(report HealthReport) []ComponentCheck {
+	return []ComponentCheck{
+		{Name: "pools", Check: func() (ComponentStatus, string) {
+			return ratioStatus(report.PoolsHealthy, report.PoolsUnhealthy, "pools")
+		}},
+		{Name: "consumers", Check: func() (ComponentStatus, string) {
+			return ratioStatus(report.ConsumersHealthy, report.ConsumersUnhealthy, "consumers")
+		}},
+		{Name: "warnings", Check: func() (ComponentStatus, string) {
+			if report.CriticalWarnings > 0 {
+				return ComponentDegraded, fmt.Sprintf("%d critical warnings", report.CriticalWarnings)
+			}
+			return ComponentUp, ""
+		}},
+	}
+}
+
+func ratioStatus(healthy, unhealthy uint32, noun string) (ComponentStatus, string) {
+	switch {
+	case unhealthy == 0:
+		return ComponentUp, ""
+	case healthy == 0:
+		return ComponentDown, fmt.Sprintf("all %d %s unhealthy", unhealthy, noun)
+	default:
+		return ComponentDegraded, fmt.Sprintf("%d of %d %s unhealthy", unhealthy, healthy+unhealthy, noun)
+	}
+}
@@ -125,3 +125,36 @@ func TestHealthService_RemoveStaleEntries(t *testing.T) {
 		t.Fatal("RemoveStaleEntries: c2 should be gone")
 	}
 }
+
+func TestHealthService_RunChecks_TracksFailureStreak(t *testing.T) {
+	s := NewHealthService(DefaultHealthServiceConfig(), nil)
+	status := ComponentDegraded
+	checks := []ComponentCheck{{Name: "stream", Check: func() (ComponentStatus, string) { return status, "lagging" }}}
+
+	s.RunChecks(checks)
+	got := s.RunChecks(checks)
+	if got[0].ConsecutiveFailures != 2 || got[0].Detail != "lagging" {
+		t.Fatalf("after 2 failures: got %+v", got[0])
+	}
+	if AggregateComponentStatus(got) != ComponentDegraded {
+		t.Fatalf("aggregate: got %v want DEGRADED", AggregateComponentStatus(got))
+	}
+
+	status = ComponentUp
+	got = s.RunChecks(checks)
+	if got[0].ConsecutiveFailures != 0 {
+		t.Fatalf("streak not reset on UP: got %d", got[0].ConsecutiveFailures)
+	}
+}
+
+func TestReportChecks_DownVsDegraded(t *testing.T) {
+	s := NewHealthService(DefaultHealthServiceConfig(), nil)
+	got := s.RunChecks(ReportChecks(HealthReport{PoolsHealthy: 1, PoolsUnhealthy: 1}))
+	if got[0].Status != ComponentDegraded {
+		t.Fatalf("partial pool failure: got %v want DEGRADED", got[0].Status)
+	}
+	got = s.RunChecks(ReportChecks(HealthReport{PoolsUnhealthy: 2}))
+	if got[0].Status != ComponentDown {
+		t.Fatalf("all pools unhealthy: got %v want DOWN", got[0].Status)
+	}
+}
@@ -1,6 +1,10 @@
 package router
 
-import "github.com/flowcatalyst/flowcatalyst-go/internal/common"
+import (
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
 
 // Shared types referenced by HealthService + the /monitoring/* HTTP
 // surface. Mirrors `fc_common::{HealthStatus, HealthReport,
@@ -38,6 +42,30 @@ type ConsumerHealth struct {
 	IsRunning           bool   `json:"isRunning"`
 }
 
+// AdaptiveStatus is an adaptive pool's bounds and how often it has
+// resized. Paused = held by a manual concurrency override.
+type AdaptiveStatus struct {
+	MinConcurrency uint32 `json:"minConcurrency"`
+	MaxConcurrency uint32 `json:"maxConcurrency"`
+	Paused         bool   `json:"paused"`
+	Increases      uint64 `json:"increases"`
+	Decreases      uint64 `json:"decreases"`
+}
+
+// QuarantineStatus is a pool's auto-quarantine config and state. Since,
+// Until and Reason are set only while the pool is quarantined.
+type QuarantineStatus struct {
+	Quarantined     bool       `json:"quarantined"`
+	Since           *time.Time `json:"since,omitempty"`
+	Until           *time.Time `json:"until,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	Trips           uint64     `json:"trips"`
+	FailureRate     float64    `json:"failureRate"`
+	MinDeliveries   uint32     `json:"minDeliveries"`
+	WindowSeconds   uint32     `json:"windowSeconds"`
+	CooldownSeconds uint32     `json:"cooldownSeconds"`
+}
+
 // PoolStats is the per-pool snapshot returned by /monitoring/pools.
 type PoolStats struct {
 	PoolCode           string                      `json:"poolCode"`
@@ -46,8 +74,12 @@ type PoolStats struct {
 	QueueSize          uint32                      `json:"queueSize"`
 	QueueCapacity      uint32                      `json:"queueCapacity"`
 	MessageGroupCount  uint32                      `json:"messageGroupCount"`
+	LargestGroupDepth  uint32                      `json:"largestGroupDepth"`
+	Overflow           uint32                      `json:"overflow"` // parked while full, awaiting re-offer
 	RateLimitPerMinute *uint32                     `json:"rateLimitPerMinute,omitempty"`
 	IsRateLimited      bool                        `json:"isRateLimited"`
+	Adaptive           *AdaptiveStatus             `json:"adaptive,omitempty"`
+	Quarantine         *QuarantineStatus           `json:"quarantine,omitempty"`
 	Metrics            *common.EnhancedPoolMetrics `json:"metrics,omitempty"`
 	// Histogram is the cumulative mediation-latency histogram, emitted by the
 	// Prometheus collector as fc_mediation_duration_seconds. Not serialized to
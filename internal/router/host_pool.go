@@ -324,9 +324,7 @@ func (p *HostConnectionPool) Sweep() {
 	p.mu.Unlock()
 
 	for _, s := range evicted {
-		if t, ok := s.client.Transport.(*http.Transport); ok {
-			t.CloseIdleConnections()
-		}
+		s.client.CloseIdleConnections()
 	}
 	if removed := before - remaining; removed > 0 {
 		slog.Info("shrank per-host HTTP/2 connection pool",
@@ -494,9 +492,7 @@ func (r *HostPoolRegistry) Close() {
 		slots := append([]*ClientSlot(nil), p.slots...)
 		p.mu.RUnlock()
 		for _, s := range slots {
-			if t, ok := s.client.Transport.(*http.Transport); ok {
-				t.CloseIdleConnections()
-			}
+			s.client.CloseIdleConnections()
 		}
 	}
 }
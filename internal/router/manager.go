@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"sort"
 	"strconv"
 	"sync"
@@ -44,7 +45,9 @@ const consumerRestartCriticalAfter = 10
 type Manager struct {
 	mediator Mediator
 	tracker  *InFlightTracker
-	warnings atomic.Pointer[WarningService] // optional; set via SetWarnings. nil → no-op.
+	warnings atomic.Pointer[WarningService]   // optional; set via SetWarnings. nil → no-op.
+	shards   atomic.Pointer[ShardCoordinator] // optional; set via SetShards. nil → every group is ours.
+	poison   atomic.Pointer[PoisonQuarantine] // optional; set via SetPoison. nil → consumers drop poison.
 
 	mu        sync.Mutex
 	pools     map[string]*Pool              // pool code → passive pool
@@ -62,6 +65,28 @@ type Manager struct {
 
 	pubMu      sync.Mutex
 	publishers map[string]queue.Publisher // queue name → publisher (lazy)
+
+	// share weights polls between queues while the pools are saturated.
+	share *pollShare
+
+	// overflow parks messages for a full pool while others have room.
+	overflow overflow
+
+	// overrides records runtime pool tuning (UpdatePool) by pool code,
+	// guarded by mu. The next Reconfigure reapplies the synced config and
+	// clears them.
+	overrides map[string]PoolOverride
+}
+
+// PoolOverride is a runtime change to a pool's tuning that hasn't been
+// reconciled with the synced config yet. Nil fields were left unchanged.
+type PoolOverride struct {
+	Concurrency *uint32
+	// RateLimitSet reports that the rate limit was changed; a nil
+	// RateLimitPerMinute with RateLimitSet disabled it.
+	RateLimitSet       bool
+	RateLimitPerMinute *uint32
+	AppliedAt          time.Time
 }
 
 type runningConsumer struct {
@@ -72,6 +97,9 @@ type runningConsumer struct {
 	// loop wedged inside consumer.Poll leaves it stale, which the
 	// consumer-restart watchdog (RestartStalledConsumers) detects.
 	lastPoll atomic.Int64
+	// pass is this consumer's stride-scheduling position; guarded by
+	// Manager.share's mutex.
+	pass float64
 }
 
 // NewManager builds a manager. The mediator (which now owns the per-endpoint
@@ -86,6 +114,8 @@ func NewManager(mediator Mediator, tracker *InFlightTracker) *Manager {
 		queues:          make(map[string]common.QueueConfig),
 		publishers:      make(map[string]queue.Publisher),
 		restartAttempts: make(map[string]int),
+		share:           newPollShare(),
+		overrides:       make(map[string]PoolOverride),
 	}
 }
 
@@ -93,6 +123,27 @@ func NewManager(mediator Mediator, tracker *InFlightTracker) *Manager {
 // /warnings and into health. Opt-in; set once at startup before Start.
 func (m *Manager) SetWarnings(ws *WarningService) { m.warnings.Store(ws) }
 
+// SetShards restricts ordered consumption to the message groups sc owns
+// (sharded mode). Opt-in; set once at startup before Start.
+func (m *Manager) SetShards(sc *ShardCoordinator) { m.shards.Store(sc) }
+
+// SetPoison hands undecodable messages from every consumer that supports
+// it (queue.PoisonReporter) to pq. Opt-in; set once at startup before Start.
+func (m *Manager) SetPoison(pq *PoisonQuarantine) { m.poison.Store(pq) }
+
+// bindPoison points a new consumer's poison handler at the quarantine,
+// tagged with the queue's config name so a replay finds its publisher.
+func (m *Manager) bindPoison(c queue.Consumer, queueName string) {
+	pq := m.poison.Load()
+	pr, ok := c.(queue.PoisonReporter)
+	if pq == nil || !ok {
+		return
+	}
+	pr.SetPoisonHandler(func(ctx context.Context, p queue.PoisonMessage) {
+		pq.Handle(ctx, queueName, p)
+	})
+}
+
 // resolveConsumer maps a message's origin queue to its consumer so a pool can
 // ack/nack on the right queue. Returns nil if the queue was deregistered.
 func (m *Manager) resolveConsumer(queueID string) queue.Consumer {
@@ -130,11 +181,16 @@ func (m *Manager) Consumers() []queue.Consumer {
 
 // PoolStats returns one snapshot per running pool (map iteration order).
 func (m *Manager) PoolStats() []PoolStats {
+	// Read before taking mu: the overflow lock is held while submitting,
+	// which can resolve consumers under mu.
+	parked := m.overflow.counts()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	out := make([]PoolStats, 0, len(m.pools))
 	for _, p := range m.pools {
-		out = append(out, p.Stats())
+		st := p.Stats()
+		st.Overflow = parked[p.Identifier()]
+		out = append(out, st)
 	}
 	return out
 }
@@ -167,6 +223,15 @@ func (m *Manager) PoolCodes() []string {
 	return out
 }
 
+// PoolMetrics returns the metrics collector of the named pool, or nil if
+// no such pool is running.
+func (m *Manager) PoolMetrics(code string) *PoolMetricsCollector {
+	if p := m.Pool(code); p != nil {
+		return p.Metrics()
+	}
+	return nil
+}
+
 // Pool returns the running pool with the given code, or nil if absent.
 func (m *Manager) Pool(code string) *Pool {
 	m.mu.Lock()
@@ -256,6 +321,9 @@ func (m *Manager) queueForPublish(key string) (common.QueueConfig, bool) {
 // UpdatePool applies a runtime config update to an existing pool. See the
 // PUT /monitoring/pools/{poolCode} handler. Concurrency==0 leaves it
 // unchanged; setRateLimit toggles whether rateLimitPerMinute is applied.
+//
+// The change is recorded as a PoolOverride and lasts until the next config
+// sync, which puts the pool back on its synced config.
 func (m *Manager) UpdatePool(code string, concurrency uint32, rateLimitPerMinute *uint32, setRateLimit bool) bool {
 	pool := m.Pool(code)
 	if pool == nil {
@@ -265,31 +333,88 @@ func (m *Manager) UpdatePool(code string, concurrency uint32, rateLimitPerMinute
 		if !pool.UpdateConcurrency(concurrency) {
 			return false
 		}
+		pool.PauseAdaptive()
 	}
 	if setRateLimit {
 		pool.UpdateRateLimit(rateLimitPerMinute)
 	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o := m.overrides[code]
+	if concurrency != 0 {
+		o.Concurrency = &concurrency
+	}
+	if setRateLimit {
+		o.RateLimitSet = true
+		o.RateLimitPerMinute = rateLimitPerMinute
+	}
+	o.AppliedAt = time.Now().UTC()
+	m.overrides[code] = o
 	return true
 }
 
+// Unquarantine lifts the named pool's auto-quarantine. found is false
+// when no such pool runs; lifted reports whether it was quarantined.
+func (m *Manager) Unquarantine(code string) (found, lifted bool) {
+	m.mu.Lock()
+	p, ok := m.pools[code]
+	m.mu.Unlock()
+	if !ok {
+		return false, false
+	}
+	return true, p.Unquarantine()
+}
+
+// PoolOverrides returns the runtime overrides not yet reconciled with the
+// synced config, by pool code.
+func (m *Manager) PoolOverrides() map[string]PoolOverride {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.overrides)
+}
+
+// HasPoolOverrides reports whether any pool runs on a runtime override.
+// The config watcher then reapplies the synced config even if it hasn't
+// changed.
+func (m *Manager) HasPoolOverrides() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.overrides) > 0
+}
+
 // route handles one poll batch from a consumer (1:1 with Rust route_batch).
 // It assigns the batch id, registers each message with the in-flight tracker
 // (claiming pipeline ownership BEFORE buffering/dispatch, so ordered-group
 // buffering windows dedupe too), drops broker redeliveries and ACK-drops
 // external-requeue duplicates, then routes each surviving message to the pool
-// named by its pool_code (DEFAULT-POOL fallback) and submits it. ack/nack of
-// the eventual outcome is the pool's job, against the message's source
-// consumer.
+// named by its pool_code (DEFAULT-POOL fallback) and submits it. A message
+// for a full pool is parked in the overflow buffer rather than NACKed, so
+// one saturated pool doesn't bounce its share of every batch off the broker
+// while the others have room. ack/nack of the eventual outcome is the
+// pool's job, against the message's source consumer.
 func (m *Manager) route(ctx context.Context, msgs []common.QueuedMessage, source queue.Consumer) {
 	if len(msgs) == 0 {
 		return
 	}
 	batchID := strconv.FormatUint(m.batchCounter.Add(1), 10)
+	m.overflow.reoffer()
+	maxWait := overflowMaxWait(m.visibilityTimeout(source))
 
 	for i := range msgs {
 		msg := msgs[i]
 		msg.BatchID = batchID
 
+		if !m.ownsGroup(msg.Message) {
+			// Sharded mode: another router owns this ordered group. Hand the
+			// message straight back so the owner's next poll picks it up;
+			// nothing was tracked yet.
+			if err := source.Nack(ctx, msg.ReceiptHandle, ptrU32(0)); err != nil {
+				slog.Warn("nack (foreign shard) failed", "message_id", msg.Message.ID, "err", err)
+			}
+			continue
+		}
+
 		if m.tracker != nil {
 			im := common.NewInFlightMessage(&msg.Message, msg.BrokerMessageID, msg.QueueIdentifier, msg.BatchID, msg.ReceiptHandle)
 			switch m.tracker.Register(im) {
@@ -348,8 +473,35 @@ func (m *Manager) route(ctx context.Context, msgs []common.QueuedMessage, source
 			}
 			continue
 		}
-		pool.submit(ctx, msg)
+		m.overflow.offer(ctx, pool, msg, maxWait)
+	}
+}
+
+// visibilityTimeout returns the configured visibility timeout of the queue
+// source polls, in seconds; 0 when unset or source isn't one of ours.
+func (m *Manager) visibilityTimeout(source queue.Consumer) uint32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, rc := range m.consumers {
+		if rc.consumer == source {
+			return rc.queueCfg.VisibilityTimeout
+		}
 	}
+	return 0
+}
+
+// ownsGroup reports whether this router may process msg. Only ordered
+// messages are sharded; anything may take an unordered one.
+func (m *Manager) ownsGroup(msg common.Message) bool {
+	sc := m.shards.Load()
+	if sc == nil || !msg.DispatchMode.RequiresOrdering() {
+		return true
+	}
+	group := ""
+	if msg.MessageGroupID != nil {
+		group = *msg.MessageGroupID
+	}
+	return sc.Owns(group)
 }
 
 // poolByCode resolves a pool by code with the DEFAULT-POOL fallback, without
@@ -393,15 +545,18 @@ func (m *Manager) poolForMessage(msg common.QueuedMessage) *Pool {
 // runConsumer is the per-consumer poll loop (1:1 with Rust
 // spawn_consumer_poll_task). It pauses when all pools are at capacity to
 // avoid a hot poll-defer loop, polls up to 10, routes the batch, and paces
-// itself by batch fullness.
+// itself by batch fullness. Queues that waited out a capacity pause
+// resume in proportion to their weight.
 func (m *Manager) runConsumer(ctx context.Context, rc *runningConsumer) {
 	defer m.wg.Done()
+	defer m.share.leave(rc)
 	const maxPoll = 10
 	wasFull := false
 	for {
 		if ctx.Err() != nil {
 			return
 		}
+		m.overflow.reoffer()
 		// Backpressure: if every pool is full, wait rather than poll. Surface the
 		// transition into full as a PoolCapacity warning (once per full period,
 		// not every tick, to avoid flooding /warnings).
@@ -413,6 +568,7 @@ func (m *Manager) runConsumer(ctx context.Context, rc *runningConsumer) {
 						fmt.Sprintf("all pools at capacity; pausing %s", rc.consumer.Identifier()), "router")
 				}
 			}
+			m.share.contend(rc)
 			select {
 			case <-ctx.Done():
 				return
@@ -421,6 +577,16 @@ func (m *Manager) runConsumer(ctx context.Context, rc *runningConsumer) {
 			continue
 		}
 		wasFull = false
+		// Capacity is back, but if other queues were also waiting for it
+		// the turn goes by weight (see pollShare).
+		if !m.share.take(rc) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+			continue
+		}
 
 		msgs, err := rc.consumer.Poll(ctx, maxPoll)
 		if err != nil {
@@ -472,8 +638,9 @@ func (m *Manager) runConsumer(ctx context.Context, rc *runningConsumer) {
 	}
 }
 
-// hasPoolCapacity reports whether at least one pool has room in its
-// pre-dispatch buffer. With no pools, returns false (nothing to route to).
+// hasPoolCapacity reports whether at least one pool that isn't
+// quarantined has room in its pre-dispatch buffer. With no pools, returns
+// false (nothing to route to).
 func (m *Manager) hasPoolCapacity() bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -481,11 +648,10 @@ func (m *Manager) hasPoolCapacity() bool {
 		return false
 	}
 	for _, p := range m.pools {
-		capacity := p.Concurrency() * queueCapacityMultiplier
-		if capacity < minQueueCapacity {
-			capacity = minQueueCapacity
+		if p.Quarantined() {
+			continue
 		}
-		if p.QueueSize() < capacity {
+		if p.hasRoom() {
 			return true
 		}
 	}
@@ -511,6 +677,12 @@ func (m *Manager) Reconfigure(ctx context.Context, cfg common.RouterConfig) erro
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// Every pool is set from cfg below, so runtime overrides end here.
+	for code := range m.overrides {
+		slog.Info("manager: pool override reconciled with synced config", "code", code)
+	}
+	clear(m.overrides)
+
 	// Pools: stop removed, update existing, start new. (Pools are passive —
 	// stopping just flips the flag so in-flight submits NACK.)
 	for code, p := range m.pools {
@@ -527,12 +699,22 @@ func (m *Manager) Reconfigure(ctx context.Context, cfg common.RouterConfig) erro
 				rate = *pc.RateLimitPerMinute
 			}
 			p.SetRateLimit(rate)
+			p.SetEndpointLimits(pc.MaxInFlightPerHost, pc.MaxInFlightPerSubscription)
+			p.SetQuarantine(pc.Quarantine)
+			if pc.AdaptiveConcurrency != nil {
+				// The controller owns the size; only its bounds are synced.
+				p.SetAdaptive(pc.AdaptiveConcurrency)
+				continue
+			}
+			p.SetAdaptive(nil)
 			if pc.Concurrency != 0 {
 				p.UpdateConcurrency(pc.Concurrency)
 			}
 			continue
 		}
-		m.pools[code] = NewPool(pc, m.mediator, m.tracker, m.resolveConsumer)
+		p := NewPool(pc, m.mediator, m.tracker, m.resolveConsumer)
+		p.SetWarnings(m.warnings.Load())
+		m.pools[code] = p
 	}
 
 	// Consumers: stop removed/changed, start new. A queue config change
@@ -554,6 +736,7 @@ func (m *Manager) Reconfigure(ctx context.Context, cfg common.RouterConfig) erro
 		if err != nil {
 			return fmt.Errorf("build consumer for queue %s: %w", name, err)
 		}
+		m.bindPoison(consumer, name)
 		cctx, cancel := context.WithCancel(ctx)
 		rc := &runningConsumer{consumer: consumer, cancel: cancel, queueCfg: qc}
 		rc.lastPoll.Store(time.Now().UnixNano())
@@ -585,7 +768,9 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 	m.consumers = make(map[string]*runningConsumer)
 	m.pools = make(map[string]*Pool)
 	m.queues = make(map[string]common.QueueConfig)
+	clear(m.overrides)
 	m.mu.Unlock()
+	m.overflow.drop()
 
 	done := make(chan struct{})
 	go func() { m.wg.Wait(); close(done) }()
@@ -673,6 +858,7 @@ func (m *Manager) RestartStalledConsumers(ctx context.Context, threshold time.Du
 			slog.Error("failed to rebuild stalled consumer", "queue", c.name, "err", err)
 			continue
 		}
+		m.bindPoison(consumer, c.name)
 		cctx, cancel := context.WithCancel(ctx)
 		rc := &runningConsumer{consumer: consumer, cancel: cancel, queueCfg: c.qc}
 		rc.lastPoll.Store(time.Now().UnixNano())
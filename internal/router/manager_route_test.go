@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -241,8 +242,7 @@ func TestManagerRouteRedeliveryResumesParkedGroup(t *testing.T) {
 	m, _, pool := newRouteHarness(med, cons)
 
 	// Occupy the only concurrency slot so the drainer parks on the acquire.
-	sem := pool.loadSem()
-	sem <- struct{}{}
+	pool.workers.acquire(context.Background(), false)
 
 	ctx1, cancel1 := context.WithCancel(context.Background())
 	m.route(ctx1, []common.QueuedMessage{mkGrouped("m1", "b1", "rh-m1")}, cons)
@@ -251,7 +251,7 @@ func TestManagerRouteRedeliveryResumesParkedGroup(t *testing.T) {
 	cancel1() // the consumer restart
 	require.Eventually(t, func() bool { return groupIdleWithBuffered(pool, "g", 1) },
 		time.Second, 5*time.Millisecond, "cancelled drainer must park the group resumable")
-	<-sem
+	pool.workers.release()
 
 	// The broker redelivers m1 (same broker id) under the restarted consumer.
 	m.route(context.Background(), []common.QueuedMessage{mkGrouped("m1", "b1", "rh-m1-redelivered")}, cons)
@@ -320,3 +320,128 @@ func TestPoolStopFlushesBufferedTrackerEntries(t *testing.T) {
 	im := common.NewInFlightMessage(&common.Message{ID: "m2"}, "b2", "q", "", "rh-m2-again")
 	assert.Equal(t, RegisterNew, tr.Register(im), "flushed m2 must be re-registrable on redelivery")
 }
+
+// TestManagerPoolOverrideReconciledOnReconfigure verifies runtime tuning is
+// recorded as an override and that the next Reconfigure puts the pool back
+// on its synced config.
+func TestManagerPoolOverrideReconciledOnReconfigure(t *testing.T) {
+	med := &cascadeMediator{}
+	m := NewManager(med, nil)
+	rate := uint32(60)
+	cfg := common.RouterConfig{ProcessingPools: []common.PoolConfig{
+		{Code: "A", Concurrency: 5, RateLimitPerMinute: &rate},
+	}}
+	require.NoError(t, m.Reconfigure(context.Background(), cfg))
+	assert.False(t, m.HasPoolOverrides())
+
+	assert.False(t, m.UpdatePool("NOPE", 3, nil, false), "unknown pool")
+	require.True(t, m.UpdatePool("A", 12, nil, true))
+	o, ok := m.PoolOverrides()["A"]
+	require.True(t, ok)
+	require.NotNil(t, o.Concurrency)
+	assert.Equal(t, uint32(12), *o.Concurrency)
+	assert.True(t, o.RateLimitSet)
+	assert.Nil(t, o.RateLimitPerMinute)
+	assert.Equal(t, uint32(12), m.Pool("A").Stats().Concurrency)
+	assert.Nil(t, m.Pool("A").RateLimitPerMinute())
+
+	require.NoError(t, m.Reconfigure(context.Background(), cfg))
+	assert.False(t, m.HasPoolOverrides())
+	stats := m.Pool("A").Stats()
+	assert.Equal(t, uint32(5), stats.Concurrency)
+	require.NotNil(t, stats.RateLimitPerMinute)
+	assert.Equal(t, uint32(60), *stats.RateLimitPerMinute)
+}
+
+// TestManagerRouteParksFullPoolWhileOthersProceed pins the overflow: a
+// batch split between a full pool and one with room submits the latter's
+// messages at once, parks the former's instead of NACKing them, and hands
+// them over in order once the pool drains.
+func TestManagerRouteParksFullPoolWhileOthersProceed(t *testing.T) {
+	cons := &cascadeConsumer{wantTotal: 3, done: make(chan struct{})}
+	med := &cascadeMediator{}
+	m, _, full := newRouteHarness(med, cons)
+	other := NewPool(common.PoolConfig{Code: "B", Concurrency: 1}, med, m.tracker, m.resolveConsumer)
+	m.pools["B"] = other
+	full.queueSize.Store(full.queueCapacity())
+
+	forB := mkGrouped("b1", "bb1", "rh-b1")
+	forB.Message.PoolCode = "B"
+	m.route(context.Background(), []common.QueuedMessage{
+		mkGrouped("a1", "ba1", "rh-a1"), forB, mkGrouped("a2", "ba2", "rh-a2"),
+	}, cons)
+
+	require.Eventually(t, func() bool {
+		cons.mu.Lock()
+		defer cons.mu.Unlock()
+		return len(cons.acked) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	cons.mu.Lock()
+	assert.Empty(t, cons.nacked, "the full pool's messages are parked, not NACKed")
+	cons.mu.Unlock()
+	assert.Equal(t, uint32(2), m.overflow.counts()[defaultPoolCode])
+
+	full.queueSize.Store(0)
+	m.overflow.reoffer()
+	select {
+	case <-cons.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the parked messages")
+	}
+	med.mu.Lock()
+	assert.Equal(t, []string{"b1", "a1", "a2"}, med.seen)
+	med.mu.Unlock()
+	assert.Empty(t, m.overflow.counts())
+	assert.Zero(t, full.Metrics().Snapshot().TotalRejected)
+}
+
+// TestManagerRouteRejectsOnceOverflowFull verifies a full buffer falls back
+// to NACKing, counted against the pool that caused it.
+func TestManagerRouteRejectsOnceOverflowFull(t *testing.T) {
+	cons := &cascadeConsumer{wantTotal: 1, done: make(chan struct{})}
+	m, tr, full := newRouteHarness(&cascadeMediator{}, cons)
+	full.queueSize.Store(full.queueCapacity())
+
+	batch := make([]common.QueuedMessage, overflowCapacity+1)
+	for i := range batch {
+		id := strconv.Itoa(i)
+		batch[i] = mkGrouped("m"+id, "b"+id, "rh-"+id)
+	}
+	m.route(context.Background(), batch, cons)
+
+	cons.mu.Lock()
+	assert.Equal(t, []string{"rh-" + strconv.Itoa(overflowCapacity)}, cons.nacked)
+	cons.mu.Unlock()
+	assert.Equal(t, uint64(1), full.Metrics().Snapshot().TotalRejected)
+	assert.Equal(t, uint32(overflowCapacity), m.PoolStats()[0].Overflow)
+
+	m.overflow.drop()
+	assert.Equal(t, 0, tr.Count(), "dropping the buffer releases its tracker entries")
+}
+
+// TestManagerRouteParksForHalfTheVisibilityTimeout pins the overflow wait
+// to the source queue's visibility timeout: a message parked from a 4s
+// queue is NACKed after 2s rather than sitting out a fixed wait the broker
+// may already have redelivered it behind.
+func TestManagerRouteParksForHalfTheVisibilityTimeout(t *testing.T) {
+	assert.Equal(t, 15*time.Second, overflowMaxWait(0))
+	assert.Equal(t, time.Minute, overflowMaxWait(120))
+
+	cons := &cascadeConsumer{wantTotal: 1, done: make(chan struct{})}
+	m, _, full := newRouteHarness(&cascadeMediator{}, cons)
+	m.consumers["q"] = &runningConsumer{consumer: cons, queueCfg: common.QueueConfig{Name: "q", VisibilityTimeout: 4}}
+	full.queueSize.Store(full.queueCapacity())
+
+	m.route(context.Background(), []common.QueuedMessage{mkGrouped("m1", "b1", "rh-m1")}, cons)
+	m.overflow.mu.Lock()
+	require.Len(t, m.overflow.entries, 1)
+	expires := m.overflow.entries[0].expires
+	m.overflow.entries[0].expires = time.Now() // as if 2s had passed
+	m.overflow.mu.Unlock()
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), expires, time.Second)
+
+	m.overflow.reoffer()
+	cons.mu.Lock()
+	assert.Equal(t, []string{"rh-m1"}, cons.nacked)
+	cons.mu.Unlock()
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -17,7 +18,8 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
-	"strconv"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/http2"
@@ -53,12 +55,41 @@ type MediatorConfig struct {
 	HTTPVersion         HTTPVersion
 	MaxRetries          int
 	RetryDelays         []time.Duration
+	// MaxRetryAfter caps the delay a receiver can ask for with Retry-After
+	// on a 429/503. Zero means DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
 	// HostPoolSizing tunes the per-host HTTP/2 connection pool (slot
 	// grow/shrink). Mirrors crates/fc-router/src/http_pool.rs sizing.
 	// Zero-value Sizing means "use the default for the negotiated HTTP
 	// version" — DefaultHostPoolSizing for HTTP/2, HTTP1HostPoolSizing
 	// for HTTP/1.1.
 	HostPoolSizing HostPoolSizing
+	// MaxIdleConnsPerHost / IdleConnTimeout tune each slot's keep-alive
+	// pool. Zero means 10 / 90s (the reqwest defaults the Rust mediator
+	// runs with).
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// TLS is the client TLS config for HTTPS targets — extra trusted CAs
+	// and a client certificate for receivers that require mutual TLS.
+	// nil = system roots, no client certificate. See MediatorTLS.
+	TLS *tls.Config
+	// Faults makes the mediator fail a share of deliveries on purpose, to
+	// exercise retry, the circuit breaker and the failure barrier. Dev
+	// mode only; empty in production. See FaultRule.
+	Faults []FaultRule
+	// MaxInFlight caps concurrent delivery requests across every pool and
+	// host; OverloadPolicy says whether a delivery at the ceiling queues
+	// for a slot or is shed. 0 means no ceiling.
+	MaxInFlight    int
+	OverloadPolicy OverloadPolicy
+	// RetryBudget is the largest share (0-1] of requests over the last
+	// ten seconds that may be retries, counting the mediator's own
+	// retries and the pool's re-dispatches; 1 turns it off. A retry over
+	// budget isn't made: the failure goes back to the pool, or a
+	// re-dispatch is shed. RetryBudgetMinPerSecond retries are allowed
+	// regardless, so light traffic can still retry a blip.
+	RetryBudget             float64
+	RetryBudgetMinPerSecond int
 }
 
 // DefaultMediatorConfig matches the Rust production defaults (15min timeout, HTTP/2).
@@ -70,7 +101,12 @@ func DefaultMediatorConfig() MediatorConfig {
 		HTTPVersion:         HTTPVersion2,
 		MaxRetries:          3,
 		RetryDelays:         []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second},
+		MaxRetryAfter:       DefaultMaxRetryAfter,
 		HostPoolSizing:      DefaultHostPoolSizing(),
+
+		OverloadPolicy:          OverloadQueue,
+		RetryBudget:             DefaultRetryBudget,
+		RetryBudgetMinPerSecond: DefaultRetryBudgetMinPerSecond,
 	}
 }
 
@@ -84,6 +120,126 @@ func DevMediatorConfig() MediatorConfig {
 	return c
 }
 
+// MediatorOverrides are operator overrides applied on top of the
+// Default/Dev mediator config. Zero fields keep the default.
+type MediatorOverrides struct {
+	Timeout             time.Duration
+	ConnectTimeout      time.Duration
+	MaxIdleConnsPerHost int
+	MaxRetryAfter       time.Duration
+	// HTTPVersion forces HTTP/1.1 or HTTP/2; nil keeps the mode default
+	// (HTTP/2 in production, HTTP/1.1 in dev).
+	HTTPVersion *HTTPVersion
+	TLS         MediatorTLS
+	// Faults enables fault injection (see MediatorConfig.Faults). Rejected
+	// outside dev mode.
+	Faults []FaultRule
+	// MaxInFlight, OverloadPolicy and the retry budget: see
+	// MediatorConfig. An empty policy keeps QUEUE.
+	MaxInFlight             int
+	OverloadPolicy          OverloadPolicy
+	RetryBudget             float64
+	RetryBudgetMinPerSecond int
+}
+
+// MediatorTLS names the PEM files for the mediator's outbound TLS.
+type MediatorTLS struct {
+	// CAFile holds extra CA certificates trusted alongside the system
+	// roots (private PKI in front of a receiver).
+	CAFile string
+	// CertFile / KeyFile are the client certificate and key presented to
+	// receivers that require mutual TLS. Both or neither.
+	CertFile string
+	KeyFile  string
+}
+
+// Config loads the files into a *tls.Config. Returns (nil, nil) when
+// nothing is configured.
+func (t MediatorTLS) Config() (*tls.Config, error) {
+	if t.CAFile == "" && t.CertFile == "" && t.KeyFile == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("mediator CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("mediator CA file %s: no PEM certificates found", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return nil, errors.New("mediator client certificate needs both a cert file and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("mediator client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// BuildMediatorConfig starts from the Default (or, in dev mode, Dev)
+// config and applies o.
+func BuildMediatorConfig(devMode bool, o MediatorOverrides) (MediatorConfig, error) {
+	cfg := DefaultMediatorConfig()
+	if devMode {
+		cfg = DevMediatorConfig()
+	}
+	if o.Timeout > 0 {
+		cfg.Timeout = o.Timeout
+	}
+	if o.ConnectTimeout > 0 {
+		cfg.ConnectTimeout = o.ConnectTimeout
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		cfg.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.MaxRetryAfter > 0 {
+		cfg.MaxRetryAfter = o.MaxRetryAfter
+	}
+	if o.HTTPVersion != nil && *o.HTTPVersion != cfg.HTTPVersion {
+		cfg.HTTPVersion = *o.HTTPVersion
+		// Re-derive the slot sizing for the new version.
+		cfg.HostPoolSizing = HostPoolSizing{}
+	}
+	tlsCfg, err := o.TLS.Config()
+	if err != nil {
+		return MediatorConfig{}, err
+	}
+	cfg.TLS = tlsCfg
+	if len(o.Faults) > 0 {
+		if !devMode {
+			return MediatorConfig{}, errors.New("mediator fault injection is only allowed in dev mode")
+		}
+		cfg.Faults = o.Faults
+	}
+	if o.MaxInFlight > 0 {
+		cfg.MaxInFlight = o.MaxInFlight
+	}
+	if o.OverloadPolicy != "" {
+		cfg.OverloadPolicy = o.OverloadPolicy
+	}
+	if o.RetryBudget < 0 || o.RetryBudget > 1 {
+		return MediatorConfig{}, fmt.Errorf("mediator retry budget %v: want a share between 0 and 1", o.RetryBudget)
+	}
+	if o.RetryBudget > 0 {
+		cfg.RetryBudget = o.RetryBudget
+	}
+	if o.RetryBudgetMinPerSecond > 0 {
+		cfg.RetryBudgetMinPerSecond = o.RetryBudgetMinPerSecond
+	}
+	return cfg, nil
+}
+
 // HTTPMediator delivers via net/http with a per-host HTTP/2 connection
 // pool (HostPoolRegistry). Each origin gets one or more *http.Client
 // slots, each backed by its own *http.Transport so the slots' h2
@@ -93,6 +249,13 @@ type HTTPMediator struct {
 	cfg      MediatorConfig
 	breakers *BreakerRegistry
 	warnings *WarningService // optional; set via SetWarnings. nil → no-op.
+	capture  *TrafficCapture // optional; set via SetCapture. nil → no capture.
+	overload *overloadGuard
+
+	// timeout and maxRetryAfter start as cfg.Timeout / cfg.MaxRetryAfter
+	// and change with SetTimeouts (config reload); nanoseconds.
+	timeout       atomic.Int64
+	maxRetryAfter atomic.Int64
 }
 
 // NewHTTPMediator wires an HTTP mediator with the supplied config.
@@ -102,7 +265,7 @@ type HTTPMediator struct {
 //   - MaxIdleConnsPerHost = 10           ↔ pool_max_idle_per_host(10)
 //   - IdleConnTimeout = 90s              ↔ reqwest default
 //   - DialContext.Timeout = ConnectTimeout ↔ connect_timeout(...)
-//   - Client.Timeout = Timeout           ↔ timeout(...)
+//   - request deadline = Timeout         ↔ timeout(...)
 //
 // HTTP/2 specifics:
 //   - http2.Transport.StrictMaxConcurrentStreams=true: honour ALB's
@@ -113,8 +276,10 @@ type HTTPMediator struct {
 //     watermark, raising the effective concurrent-stream cap.
 //
 // `ResponseHeaderTimeout` is intentionally NOT set: it would shadow
-// Client.Timeout for the response-header phase only and obscure which
-// timeout is actually enforced. Single source of truth: Client.Timeout.
+// the request deadline for the response-header phase only and obscure
+// which timeout is actually enforced. Single source of truth: the
+// per-request context deadline set in mediateOnce — Timeout, or the
+// message's own TimeoutSeconds when it carries one.
 func NewHTTPMediator(cfg MediatorConfig, breakers *BreakerRegistry) *HTTPMediator {
 	sizing := cfg.HostPoolSizing
 	if sizing.MaxSlotsPerHost == 0 {
@@ -125,10 +290,24 @@ func NewHTTPMediator(cfg MediatorConfig, breakers *BreakerRegistry) *HTTPMediato
 		}
 		cfg.HostPoolSizing = sizing
 	}
+	if len(cfg.Faults) > 0 {
+		slog.Warn("mediator fault injection enabled: deliveries will fail on purpose", "rules", len(cfg.Faults))
+	}
 	builder := newClientBuilder(cfg)
 	pools := NewHostPoolRegistry(sizing, builder)
 	pools.StartSweep()
-	return &HTTPMediator{pools: pools, cfg: cfg, breakers: breakers}
+	m := &HTTPMediator{pools: pools, cfg: cfg, breakers: breakers, overload: newOverloadGuard(cfg)}
+	m.SetTimeouts(cfg.Timeout, cfg.MaxRetryAfter)
+	return m
+}
+
+// SetTimeouts changes the request deadline and the Retry-After cap for
+// deliveries that start after the call; in-flight requests keep the
+// deadline they started with. A zero maxRetryAfter means
+// DefaultMaxRetryAfter.
+func (m *HTTPMediator) SetTimeouts(timeout, maxRetryAfter time.Duration) {
+	m.timeout.Store(int64(timeout))
+	m.maxRetryAfter.Store(int64(maxRetryAfter))
 }
 
 // Close stops the host-pool sweep goroutine. Safe to call multiple
@@ -140,6 +319,9 @@ func (m *HTTPMediator) Close() {
 	}
 }
 
+// OverloadStats snapshots the in-flight ceiling and retry budget.
+func (m *HTTPMediator) OverloadStats() OverloadStats { return m.overload.stats(time.Now()) }
+
 // HostPools is exposed for tests/metrics. Production code should not
 // poke at the registry directly.
 func (m *HTTPMediator) HostPools() *HostPoolRegistry { return m.pools }
@@ -160,6 +342,10 @@ func (m *HTTPMediator) warnConfig(severity WarningSeverity, message string, msg
 	}
 }
 
+// SetCapture samples outbound requests and their responses into tc. Set
+// once at startup, before serving.
+func (m *HTTPMediator) SetCapture(tc *TrafficCapture) { m.capture = tc }
+
 // newClientBuilder returns a ClientBuilder that mints a fresh
 // *http.Client with its own *http.Transport per call. Each Transport
 // owns its own connection pool, so two slots backed by separate
@@ -172,10 +358,19 @@ func newClientBuilder(cfg MediatorConfig) ClientBuilder {
 		}
 		transport := &http.Transport{
 			DialContext:         dialer.DialContext,
-			MaxIdleConnsPerHost: 10,
-			IdleConnTimeout:     90 * time.Second,
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+			IdleConnTimeout:     cfg.IdleConnTimeout,
 			TLSHandshakeTimeout: cfg.TLSHandshakeTimeout,
 		}
+		if transport.MaxIdleConnsPerHost == 0 {
+			transport.MaxIdleConnsPerHost = 10
+		}
+		if transport.IdleConnTimeout == 0 {
+			transport.IdleConnTimeout = 90 * time.Second
+		}
+		if cfg.TLS != nil {
+			transport.TLSClientConfig = cfg.TLS.Clone()
+		}
 		if cfg.HTTPVersion == HTTPVersion1 {
 			transport.ForceAttemptHTTP2 = false
 			transport.TLSNextProto = map[string]func(authority string, c *tls.Conn) http.RoundTripper{}
@@ -185,7 +380,10 @@ func newClientBuilder(cfg MediatorConfig) ClientBuilder {
 				h2.StrictMaxConcurrentStreams = true
 			}
 		}
-		return &http.Client{Transport: transport, Timeout: cfg.Timeout}
+		if len(cfg.Faults) > 0 {
+			return &http.Client{Transport: newFaultTransport(transport, cfg.Faults)}
+		}
+		return &http.Client{Transport: transport}
 	}
 }
 
@@ -220,12 +418,18 @@ func signWebhook(payload []byte, signingSecret string) (sigHex, ts string) {
 // recording here (rather than per-outcome in the pool) removes the class of bug
 // where a single switch arm forgets to record. An open breaker short-circuits:
 // no HTTP is attempted and a circuit-open outcome is returned for the pool to DEFER.
+// So is a re-dispatch the retry budget has no room for, as a shed outcome.
 func (m *HTTPMediator) Mediate(ctx context.Context, msg *common.Message) common.MediationOutcome {
 	cb := m.breakers.Get(msg.MediationTarget)
 	if err := cb.Allow(); err != nil {
 		return common.CircuitOpen(int(cb.ResetTimeout().Seconds()))
 	}
 	outcome := m.deliverWithRetry(ctx, msg)
+	if ctx.Err() != nil {
+		// Aborted by the caller (shutdown, operator cancel): says nothing
+		// about the endpoint's health.
+		return outcome
+	}
 	switch outcome.Result {
 	case common.MediationSuccess, common.MediationErrorConfig:
 		// 4xx is reachable → record a SUCCESS: a config error must not trip the
@@ -233,10 +437,10 @@ func (m *HTTPMediator) Mediate(ctx context.Context, msg *common.Message) common.
 		cb.RecordSuccess()
 	case common.MediationErrorProcess, common.MediationErrorConnection:
 		cb.RecordFailure()
-	case common.MediationRateLimited, common.MediationCircuitOpen:
+	case common.MediationRateLimited, common.MediationCircuitOpen, common.MediationShed:
 		// 429: destination healthy, just throttling — neither success nor failure.
 		// CircuitOpen is returned before delivery, so it never reaches here; listed
-		// for switch exhaustiveness.
+		// for switch exhaustiveness. Shed made no call: says nothing either.
 	}
 	return outcome
 }
@@ -248,13 +452,23 @@ func (m *HTTPMediator) deliverWithRetry(ctx context.Context, msg *common.Message
 	// the max TOTAL attempts (default 3), and a delay is taken only between
 	// attempts (after attempt 1 and 2 for the default), never after the last.
 	attempts := 0
+	retry := isRetryAttempt(ctx)
 	for {
-		last = m.mediateOnce(ctx, msg)
+		out := m.mediateOnce(ctx, msg, retry)
+		if out.Result == common.MediationShed && attempts > 0 {
+			// An in-process retry found no slot or no budget: hand the
+			// failure back for the pool to retry in-pipeline, later,
+			// rather than pile on now.
+			return last
+		}
+		last = out
 
 		// Don't retry on success, config errors, or rate-limit responses.
 		// For 429 the queue applies Retry-After delay rather than busy-waiting here.
+		// A shed attempt made no call; the pool defers it.
 		switch last.Result {
-		case common.MediationSuccess, common.MediationErrorConfig, common.MediationRateLimited:
+		case common.MediationSuccess, common.MediationErrorConfig, common.MediationRateLimited,
+			common.MediationShed:
 			return last
 		default:
 			// ErrorProcess / ErrorConnection are retryable; fall through to backoff.
@@ -264,6 +478,7 @@ func (m *HTTPMediator) deliverWithRetry(ctx context.Context, msg *common.Message
 		if attempts >= m.cfg.MaxRetries {
 			return last
 		}
+		retry = true
 
 		// Backoff according to configured retry_delays (index = attempts-1).
 		delay := 3 * time.Second
@@ -278,7 +493,19 @@ func (m *HTTPMediator) deliverWithRetry(ctx context.Context, msg *common.Message
 	}
 }
 
-func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) common.MediationOutcome {
+// requestTimeout is the deadline for one delivery attempt: the message's
+// own TimeoutSeconds when set (it knows how long its target may take),
+// otherwise the configured Timeout. Zero means no deadline.
+func (m *HTTPMediator) requestTimeout(msg *common.Message) time.Duration {
+	if msg.TimeoutSeconds > 0 {
+		return time.Duration(msg.TimeoutSeconds) * time.Second
+	}
+	return time.Duration(m.timeout.Load())
+}
+
+// mediateOnce makes one delivery attempt; retry says it repeats a request
+// the receiver already failed, for the retry budget.
+func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message, retry bool) (out common.MediationOutcome) {
 	if msg.MediationType != common.MediationTypeHTTP {
 		return common.ErrorConfig(0, fmt.Sprintf("Unsupported mediation type: %s", msg.MediationType))
 	}
@@ -288,6 +515,27 @@ func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) com
 		return common.ErrorConfig(0, fmt.Sprintf("payload marshal: %v", err))
 	}
 
+	// Taken before the request deadline starts, so time queued for a slot
+	// isn't charged to the receiver.
+	if !m.overload.acquire(ctx) {
+		return common.Shed(shedRetryDelay, "Mediator in-flight ceiling reached")
+	}
+	defer m.overload.release()
+	// Counted only once a slot is held, so a request shed at the ceiling
+	// isn't in the budget's window.
+	if !m.overload.admit(retry, time.Now()) {
+		return common.Shed(shedRetryDelay, "Retry budget exhausted")
+	}
+
+	if d := m.requestTimeout(msg); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	if len(m.cfg.Faults) > 0 {
+		ctx = withFaultTarget(ctx, targetHostKey(msg))
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.MediationTarget, bytes.NewReader(payload))
 	if err != nil {
 		return common.ErrorConnection(fmt.Sprintf("build request: %v", err))
@@ -311,8 +559,22 @@ func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) com
 	guard := m.pools.Acquire(host)
 	defer guard.Release()
 
+	var capture *CapturedExchange
+	if m.capture != nil && m.capture.Sampled() {
+		capture = newCapture(msg, req, payload)
+		start := time.Now()
+		defer func() {
+			capture.Outcome = outcomeName(out.Result)
+			capture.DurationMs = time.Since(start).Milliseconds()
+			m.capture.Capture(*capture)
+		}()
+	}
+
 	resp, err := guard.Client().Do(req)
 	if err != nil {
+		if capture != nil {
+			capture.Error = err.Error()
+		}
 		// Connection-level failures (DNS, refused, timeout) MUST log: unlike
 		// HTTP-status failures below they produce no response, and a silently
 		// unreachable target otherwise leaves no log evidence at all while
@@ -326,6 +588,9 @@ func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) com
 		return common.ErrorConnection(fmt.Sprintf("Request failed: %v", err))
 	}
 	defer resp.Body.Close()
+	if capture != nil {
+		capture.recordResponse(resp)
+	}
 
 	status := resp.StatusCode
 	switch {
@@ -359,13 +624,11 @@ func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) com
 		return common.ErrorConfig(status, "HTTP 404: Not found")
 
 	case status == 429:
-		retryAfter := 30
-		if v := resp.Header.Get("Retry-After"); v != "" {
-			if n, err := strconv.Atoi(v); err == nil {
-				retryAfter = n
-			}
+		retryAfter, ok := m.retryAfter(resp)
+		if !ok {
+			retryAfter = defaultRetryAfterSeconds
 		}
-		slog.Warn("rate limited by target", "message_id", msg.ID, "retry_after", retryAfter)
+		slog.Warn("rate limited by target", "message_id", msg.ID, "retry_after", retryAfter, "honoured", ok)
 		return common.RateLimited(retryAfter)
 
 	case status == 501:
@@ -377,6 +640,17 @@ func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) com
 		return common.ErrorConfig(status, fmt.Sprintf("HTTP %d: Client error", status))
 
 	case status >= 500:
+		// A 503 that says when to come back is pushback, not a failure:
+		// wait as asked, without the in-process retries or a breaker hit.
+		if status == http.StatusServiceUnavailable {
+			if retryAfter, ok := m.retryAfter(resp); ok {
+				slog.Warn("target unavailable", "message_id", msg.ID, "target", msg.MediationTarget, "retry_after", retryAfter)
+				out := common.RateLimited(retryAfter)
+				out.StatusCode = status
+				out.ErrorMessage = "HTTP 503: Service Unavailable"
+				return out
+			}
+		}
 		slog.Warn("server error from target", "message_id", msg.ID, "status", status, "target", msg.MediationTarget)
 		out := common.ErrorProcess(30, fmt.Sprintf("HTTP %d: Server error", status))
 		out.StatusCode = status
@@ -387,3 +661,26 @@ func (m *HTTPMediator) mediateOnce(ctx context.Context, msg *common.Message) com
 		return common.ErrorProcess(30, fmt.Sprintf("HTTP %d: Unexpected status", status))
 	}
 }
+
+// DefaultMaxRetryAfter bounds a receiver's Retry-After; it matches the
+// pool's own retry backoff ceiling.
+const DefaultMaxRetryAfter = 5 * time.Minute
+
+// defaultRetryAfterSeconds is the 429 delay when the receiver's
+// Retry-After is missing or unusable.
+const defaultRetryAfterSeconds = 30
+
+// retryAfter reads the response's Retry-After in whole seconds, clamped
+// to MaxRetryAfter. ok is false when the header is absent or malformed.
+func (m *HTTPMediator) retryAfter(resp *http.Response) (int, bool) {
+	d, ok := common.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+	limit := time.Duration(m.maxRetryAfter.Load())
+	if limit <= 0 {
+		limit = DefaultMaxRetryAfter
+	}
+	d = min(d, limit)
+	return int((d + time.Second - 1) / time.Second), true
+}
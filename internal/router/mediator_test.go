@@ -103,6 +103,85 @@ func TestMediatorRateLimitedReadsRetryAfter(t *testing.T) {
 	assert.Equal(t, 120, out.DelaySeconds)
 }
 
+// TestMediatorRetryAfterBounds covers the clamp to MaxRetryAfter, a 503
+// with Retry-After taking the pushback path (one attempt, no breaker
+// failure), the HTTP-date form, and a 503 without one.
+func TestMediatorRetryAfterBounds(t *testing.T) {
+	var status, attempts int
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if header != "" {
+			w.Header().Set("Retry-After", header)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	cfg := router.DevMediatorConfig()
+	cfg.MaxRetryAfter = time.Minute
+	cfg.RetryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	breakers := router.NewBreakerRegistry(router.DefaultBreakerConfig())
+	med := router.NewHTTPMediator(cfg, breakers)
+	mediate := func() common.MediationOutcome {
+		attempts = 0
+		return med.Mediate(context.Background(), &common.Message{
+			ID: "m", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL,
+		})
+	}
+
+	status, header = http.StatusTooManyRequests, "3600"
+	out := mediate()
+	assert.Equal(t, common.MediationRateLimited, out.Result)
+	assert.Equal(t, 60, out.DelaySeconds, "clamped to MaxRetryAfter")
+
+	header = "soon"
+	out = mediate()
+	assert.Equal(t, 30, out.DelaySeconds, "unusable header: router default")
+
+	status, header = http.StatusServiceUnavailable, time.Now().Add(20*time.Second).UTC().Format(http.TimeFormat)
+	out = mediate()
+	assert.Equal(t, common.MediationRateLimited, out.Result)
+	assert.Equal(t, 503, out.StatusCode)
+	assert.InDelta(t, 20, out.DelaySeconds, 2)
+	assert.Equal(t, 1, attempts, "honoured 503 is not retried in-process")
+	assert.Zero(t, breakers.Get(srv.URL).Stats().Failures, "honoured 503 is not a breaker failure")
+
+	header = ""
+	out = mediate()
+	assert.Equal(t, common.MediationErrorProcess, out.Result, "a 503 without Retry-After is an ordinary server error")
+	assert.Equal(t, 3, attempts)
+}
+
+// TestMediatorSetTimeouts covers a config reload: the new Retry-After cap
+// and request deadline apply to the next delivery.
+func TestMediatorSetTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cfg := router.DevMediatorConfig()
+	cfg.RetryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	med := router.NewHTTPMediator(cfg, router.NewBreakerRegistry(router.DefaultBreakerConfig()))
+	mediate := func(path string) common.MediationOutcome {
+		return med.Mediate(context.Background(), &common.Message{
+			ID: "m", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL + path,
+		})
+	}
+
+	assert.Equal(t, 300, mediate("/").DelaySeconds, "DefaultMaxRetryAfter")
+	med.SetTimeouts(50*time.Millisecond, 2*time.Minute)
+	assert.Equal(t, 120, mediate("/").DelaySeconds)
+	assert.NotEqual(t, common.MediationSuccess, mediate("/slow").Result, "new deadline applies")
+}
+
 func TestMediatorServerErrorRetries(t *testing.T) {
 	attempts := 0
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
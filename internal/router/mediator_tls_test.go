@@ -0,0 +1,165 @@
+package router_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+)
+
+// writePEM writes one PEM block to dir/name and returns the path.
+func writePEM(t *testing.T, dir, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+	return path
+}
+
+// newClientCert mints a self-signed client CA and a leaf signed by it,
+// returning the CA pool (for the server) and the leaf's cert/key files.
+func newClientCert(t *testing.T, dir string) (*x509.CertPool, string, string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test client CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "fc-router"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return pool, writePEM(t, dir, "client.pem", "CERTIFICATE", leafDER), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func TestMediatorMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCAs, certFile, keyFile := newClientCert(t, dir)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+	caFile := writePEM(t, dir, "server-ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+
+	mediate := func(o router.MediatorOverrides) common.MediationOutcome {
+		t.Helper()
+		cfg, err := router.BuildMediatorConfig(true, o)
+		require.NoError(t, err)
+		cfg.MaxRetries = 0
+		return router.NewHTTPMediator(cfg, router.NewBreakerRegistry(router.DefaultBreakerConfig())).Mediate(
+			context.Background(),
+			&common.Message{ID: "m", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL},
+		)
+	}
+
+	out := mediate(router.MediatorOverrides{TLS: router.MediatorTLS{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}})
+	assert.Equal(t, common.MediationSuccess, out.Result, "%+v", out)
+
+	out = mediate(router.MediatorOverrides{TLS: router.MediatorTLS{CAFile: caFile}})
+	assert.NotEqual(t, common.MediationSuccess, out.Result, "the server requires a client certificate")
+
+	out = mediate(router.MediatorOverrides{})
+	assert.NotEqual(t, common.MediationSuccess, out.Result, "the server's CA is not a system root")
+}
+
+func TestMediatorTLS_Config(t *testing.T) {
+	cfg, err := router.MediatorTLS{}.Config()
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "nothing configured keeps the transport default")
+
+	dir := t.TempDir()
+	_, certFile, keyFile := newClientCert(t, dir)
+
+	_, err = router.MediatorTLS{CertFile: certFile}.Config()
+	assert.Error(t, err, "a cert without its key")
+
+	bad := filepath.Join(dir, "bad.pem")
+	require.NoError(t, os.WriteFile(bad, []byte("not pem"), 0o600))
+	_, err = router.MediatorTLS{CAFile: bad}.Config()
+	assert.Error(t, err)
+
+	cfg, err = router.MediatorTLS{CertFile: certFile, KeyFile: keyFile}.Config()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Certificates, 1)
+	assert.Nil(t, cfg.RootCAs, "no CA file keeps the system roots")
+}
+
+func TestBuildMediatorConfig_Overrides(t *testing.T) {
+	h2 := router.HTTPVersion2
+	cfg, err := router.BuildMediatorConfig(true, router.MediatorOverrides{
+		Timeout:             2 * time.Minute,
+		MaxIdleConnsPerHost: 64,
+		HTTPVersion:         &h2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.Timeout)
+	assert.Equal(t, router.DevMediatorConfig().ConnectTimeout, cfg.ConnectTimeout, "zero keeps the mode default")
+	assert.Equal(t, 64, cfg.MaxIdleConnsPerHost)
+	assert.Equal(t, router.HTTPVersion2, cfg.HTTPVersion)
+}
+
+// TestMediatorMessageTimeoutOverridesDefault checks that a message's own
+// TimeoutSeconds, not the configured Timeout, bounds the request.
+func TestMediatorMessageTimeoutOverridesDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := router.DevMediatorConfig()
+	cfg.MaxRetries = 0
+	m := router.NewHTTPMediator(cfg, router.NewBreakerRegistry(router.DefaultBreakerConfig()))
+
+	start := time.Now()
+	out := m.Mediate(context.Background(), &common.Message{
+		ID: "m", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL, TimeoutSeconds: 1,
+	})
+	assert.NotEqual(t, common.MediationSuccess, out.Result)
+	assert.Less(t, time.Since(start), 3*time.Second, "the 30s dev default applied instead of the message's 1s")
+}
@@ -49,6 +49,14 @@ type PoolMetricsCollector struct {
 	totalFailure     atomic.Uint64
 	totalRateLimited atomic.Uint64
 
+	// Receiver pushback: 429 / 503 responses, whether or not a
+	// Retry-After was honoured.
+	totalPushback429 atomic.Uint64
+	totalPushback503 atomic.Uint64
+
+	// Messages turned back to the broker because the pool was full.
+	totalRejected atomic.Uint64
+
 	// Cumulative mediation-latency histogram, emitted as the Prometheus
 	// fc_mediation_duration_seconds histogram. Monotonic across the process
 	// lifetime — distinct from the sliding `samples` window used for the
@@ -138,11 +146,29 @@ func (c *PoolMetricsCollector) RecordRateLimited() {
 	c.rateLimitedEvents = append(c.rateLimitedEvents, now)
 }
 
+// RecordPushback counts a receiver pushing back with 429 or 503; other
+// statuses are ignored.
+func (c *PoolMetricsCollector) RecordPushback(status int) {
+	switch status {
+	case 429:
+		c.totalPushback429.Add(1)
+	case 503:
+		c.totalPushback503.Add(1)
+	}
+}
+
+// RecordRejected counts a message NACKed because the pool's pre-dispatch
+// buffer was full. Not a delivery attempt, so no sample.
+func (c *PoolMetricsCollector) RecordRejected() { c.totalRejected.Add(1) }
+
 // Reset clears every counter and sample. Test helper.
 func (c *PoolMetricsCollector) Reset() {
 	c.totalSuccess.Store(0)
 	c.totalFailure.Store(0)
 	c.totalRateLimited.Store(0)
+	c.totalPushback429.Store(0)
+	c.totalPushback503.Store(0)
+	c.totalRejected.Store(0)
 	c.durationCount.Store(0)
 	c.durationSumMs.Store(0)
 	for i := range c.durationBuckets {
@@ -204,6 +230,22 @@ func (c *PoolMetricsCollector) addSample(durationMs uint64, success bool) {
 	}
 }
 
+// LatencyCompliance counts the delivery attempts in the trailing window and
+// how many of them succeeded within threshold. Failed attempts count against
+// compliance: a message that wasn't delivered wasn't delivered in time.
+func (c *PoolMetricsCollector) LatencyCompliance(window, threshold time.Duration) (within, total uint64) {
+	limit := uint64(threshold.Milliseconds())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range filterSamples(c.samples, time.Now().Add(-window)) {
+		total++
+		if s.success && s.durationMs <= limit {
+			within++
+		}
+	}
+	return within, total
+}
+
 // Snapshot returns the dashboard-shaped metrics. Safe to call at any
 // time; copies are taken under the lock.
 func (c *PoolMetricsCollector) Snapshot() common.EnhancedPoolMetrics {
@@ -243,6 +285,9 @@ func (c *PoolMetricsCollector) Snapshot() common.EnhancedPoolMetrics {
 		TotalSuccess:     totalSuccess,
 		TotalFailure:     totalFailure,
 		TotalRateLimited: totalRateLimited,
+		TotalPushback429: c.totalPushback429.Load(),
+		TotalPushback503: c.totalPushback503.Load(),
+		TotalRejected:    c.totalRejected.Load(),
 		SuccessRate:      successRate,
 		ProcessingTime:   processingTimeFromSamples(samples),
 		Last5Min:         last5,
@@ -145,6 +145,21 @@ func TestPoolMetricsCollector_RateLimitedWindowed(t *testing.T) {
 	}
 }
 
+func TestPoolMetricsCollector_Pushback(t *testing.T) {
+	c := NewPoolMetricsCollector()
+	for _, status := range []int{429, 429, 503, 500, 0} {
+		c.RecordPushback(status)
+	}
+
+	m := c.Snapshot()
+	if m.TotalPushback429 != 2 || m.TotalPushback503 != 1 {
+		t.Fatalf("pushback 429=%d 503=%d, want 2 / 1", m.TotalPushback429, m.TotalPushback503)
+	}
+	if m.TotalRateLimited != 0 {
+		t.Fatalf("pushback must not count as rate limiting, got %d", m.TotalRateLimited)
+	}
+}
+
 func TestPoolMetricsCollector_WindowEviction(t *testing.T) {
 	// Use a tiny short window so we can verify samples drop out without
 	// relying on real time-of-day.
@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,6 +29,9 @@ const (
 	WarningCategoryPoolCapacity   WarningCategory = "POOL_CAPACITY"
 	WarningCategoryQueueHealth    WarningCategory = "QUEUE_HEALTH"
 	WarningCategoryConsumerHealth WarningCategory = "CONSUMER_HEALTH"
+	WarningCategorySLO            WarningCategory = "SLO"
+	WarningCategoryQuarantine     WarningCategory = "QUARANTINE"
+	WarningCategoryPoisonMessage  WarningCategory = "POISON_MESSAGE"
 )
 
 // WarningSeverity mirrors the Rust enum.
@@ -51,6 +56,10 @@ type Warning struct {
 	CreatedAt      time.Time       `json:"createdAt"`
 	Acknowledged   bool            `json:"acknowledged"`
 	AcknowledgedAt *time.Time      `json:"acknowledgedAt,omitempty"`
+	// ResolvedAt is set when an operator marks the underlying problem
+	// fixed. Resolved warnings leave the live set but stay in the
+	// WarningStore history.
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
 }
 
 // NewWarning constructs a Warning with a freshly-minted UUID and the
@@ -78,6 +87,8 @@ type Notifier struct {
 	batchSize   int
 	interval    time.Duration
 	minSeverity WarningSeverity // "" = deliver all; else drop warnings below it
+	slack       bool            // post Slack incoming-webhook bodies instead of {"warnings": [...]}
+	mail        AlertMailFunc   // send each batch as one email instead of posting to webhookURL
 	client      *http.Client
 
 	mu    sync.Mutex
@@ -113,9 +124,32 @@ func NewNotifier(webhookURL string, batchSize int, interval time.Duration) *Noti
 	}
 }
 
+// NewSlackNotifier builds a notifier that posts to a Slack incoming
+// webhook: one {"text": ...} message per batch, a line per warning.
+func NewSlackNotifier(webhookURL string, batchSize int, interval time.Duration) *Notifier {
+	n := NewNotifier(webhookURL, batchSize, interval)
+	n.slack = true
+	return n
+}
+
+// AlertMailFunc sends one alert email. The router stays free of any mailer
+// dependency; the caller (internal/server) adapts its email service to this.
+type AlertMailFunc func(ctx context.Context, subject, htmlBody string) error
+
+// NewMailNotifier builds a notifier that emails each batch through send: one
+// message per batch, a line per warning.
+func NewMailNotifier(send AlertMailFunc, batchSize int, interval time.Duration) *Notifier {
+	n := NewNotifier("", batchSize, interval)
+	n.mail = send
+	return n
+}
+
+// enabled reports whether the notifier has somewhere to deliver to.
+func (n *Notifier) enabled() bool { return n.webhookURL != "" || n.mail != nil }
+
 // Run starts the flush loop. Returns when ctx is cancelled or Stop is called.
 func (n *Notifier) Run(ctx context.Context) {
-	if n.webhookURL == "" {
+	if !n.enabled() {
 		return // noop
 	}
 	tick := time.NewTicker(n.interval)
@@ -175,7 +209,7 @@ func (n *Notifier) Stop() {
 
 func (n *Notifier) flush(ctx context.Context) {
 	n.mu.Lock()
-	if len(n.queue) == 0 || n.webhookURL == "" {
+	if len(n.queue) == 0 || !n.enabled() {
 		n.mu.Unlock()
 		return
 	}
@@ -183,7 +217,19 @@ func (n *Notifier) flush(ctx context.Context) {
 	n.queue = nil
 	n.mu.Unlock()
 
-	body, err := json.Marshal(map[string]any{"warnings": batch})
+	if n.mail != nil {
+		subject, body := mailPayload(batch)
+		if err := n.mail(ctx, subject, body); err != nil {
+			slog.Warn("notifier: email failed", "err", err, "batch_size", len(batch))
+		}
+		return
+	}
+
+	var payload any = map[string]any{"warnings": batch}
+	if n.slack {
+		payload = slackPayload(batch)
+	}
+	body, err := json.Marshal(payload)
 	if err != nil {
 		slog.Warn("notifier: marshal failed", "err", err)
 		return
@@ -205,6 +251,36 @@ func (n *Notifier) flush(ctx context.Context) {
 	}
 }
 
+// slackPayload renders a batch as a Slack incoming-webhook message.
+func slackPayload(batch []Warning) map[string]string {
+	var b strings.Builder
+	for i, w := range batch {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "*%s* %s: %s (from %s)", w.Severity, w.Category, w.Message, w.Source)
+	}
+	return map[string]string{"text": b.String()}
+}
+
+// mailPayload renders a batch as an email subject and HTML body. The subject
+// names the first warning so a single-incident mail reads at a glance.
+func mailPayload(batch []Warning) (string, string) {
+	subject := fmt.Sprintf("[FlowCatalyst %s] %s: %s", batch[0].Severity, batch[0].Category, batch[0].Message)
+	if len(batch) > 1 {
+		subject = fmt.Sprintf("[FlowCatalyst %s] %d warnings", batch[0].Severity, len(batch))
+	}
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, w := range batch {
+		fmt.Fprintf(&b, "<li><strong>%s</strong> %s: %s <em>(from %s, %s)</em></li>",
+			html.EscapeString(string(w.Severity)), html.EscapeString(string(w.Category)),
+			html.EscapeString(w.Message), html.EscapeString(w.Source), w.CreatedAt.Format(time.RFC3339))
+	}
+	b.WriteString("</ul>")
+	return subject, b.String()
+}
+
 // String formats a warning for diagnostic logs.
 func (w Warning) String() string {
 	return fmt.Sprintf("[%s/%s] %s (from %s)", w.Category, w.Severity, w.Message, w.Source)
@@ -0,0 +1,140 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// overflowCapacity bounds the messages parked across all pools.
+const overflowCapacity = 100
+
+// defaultVisibilityTimeout is what the SQS and Postgres consumers use when
+// a queue config leaves the visibility timeout unset, in seconds.
+const defaultVisibilityTimeout = 30
+
+// overflowMaxWait is how long a message from a queue with the given
+// visibility timeout (seconds; 0 = the default) may stay parked: half of
+// it, so a parked message is settled well before the broker redelivers it
+// behind our back.
+func overflowMaxWait(visibilityTimeout uint32) time.Duration {
+	if visibilityTimeout == 0 {
+		visibilityTimeout = defaultVisibilityTimeout
+	}
+	return time.Duration(visibilityTimeout) * time.Second / 2
+}
+
+// overflow parks messages whose pool was full when their batch was
+// routed. Without it a saturated pool NACKs its share of every batch while
+// the other pools have room — the consumer keeps polling, since some pool
+// has capacity, and the broker redelivers the same messages over and over.
+// Parked messages are re-offered to their pool, oldest first, as it drains.
+// A pool with anything parked takes new messages through the buffer too,
+// so group order holds. Once the buffer is full, or a message has waited
+// its overflowMaxWait, the pool NACKs as before.
+type overflow struct {
+	mu      sync.Mutex
+	entries []overflowEntry
+}
+
+type overflowEntry struct {
+	ctx  context.Context // the source consumer's poll context
+	pool *Pool
+	msg  common.QueuedMessage
+	// expires is when the entry has waited its overflowMaxWait.
+	expires time.Time
+}
+
+// offer submits msg to pool, parking it for up to maxWait if the pool is
+// full.
+func (o *overflow) offer(ctx context.Context, pool *Pool, msg common.QueuedMessage, maxWait time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.holds(pool) && decidesNow(pool) {
+		pool.submit(ctx, msg)
+		return
+	}
+	if len(o.entries) >= overflowCapacity {
+		pool.reject(ctx, msg)
+		return
+	}
+	o.entries = append(o.entries, overflowEntry{ctx: ctx, pool: pool, msg: msg, expires: time.Now().Add(maxWait)})
+}
+
+// reoffer hands parked messages to pools that have drained, in arrival
+// order per pool, and NACKs those that have waited too long.
+func (o *overflow) reoffer() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.entries) == 0 {
+		return
+	}
+	now := time.Now()
+	blocked := make(map[*Pool]bool)
+	kept := o.entries[:0]
+	for _, e := range o.entries {
+		switch {
+		case e.ctx.Err() != nil:
+			// The consumer was stopped or restarted; nothing can ack for
+			// this receipt handle now. Release the tracker entry and let
+			// the visibility timeout redeliver it.
+			forget(e.pool, e.msg)
+		case blocked[e.pool]:
+			kept = append(kept, e)
+		case decidesNow(e.pool):
+			e.pool.submit(e.ctx, e.msg)
+		case !now.Before(e.expires):
+			e.pool.reject(e.ctx, e.msg)
+		default:
+			blocked[e.pool] = true
+			kept = append(kept, e)
+		}
+	}
+	clear(o.entries[len(kept):])
+	o.entries = kept
+}
+
+// drop empties the buffer on shutdown, releasing tracker entries; the
+// broker redelivers once the visibility timeout lapses.
+func (o *overflow) drop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, e := range o.entries {
+		forget(e.pool, e.msg)
+	}
+	o.entries = nil
+}
+
+// counts returns the parked messages per pool code.
+func (o *overflow) counts() map[string]uint32 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make(map[string]uint32)
+	for _, e := range o.entries {
+		out[e.pool.Identifier()]++
+	}
+	return out
+}
+
+func (o *overflow) holds(pool *Pool) bool {
+	for _, e := range o.entries {
+		if e.pool == pool {
+			return true
+		}
+	}
+	return false
+}
+
+// decidesNow reports whether submit settles a message for pool right away
+// — it has room, or is stopped or quarantined and will NACK it regardless.
+func decidesNow(pool *Pool) bool {
+	return pool.hasRoom() || pool.stopped.Load() || pool.Quarantined()
+}
+
+func forget(pool *Pool, msg common.QueuedMessage) {
+	if pool.tracker != nil {
+		pool.tracker.Remove(msg.Message.ID, msg.BrokerMessageID)
+	}
+}
@@ -0,0 +1,221 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverloadPolicy is what a delivery does when the mediator's global
+// in-flight ceiling (MediatorConfig.MaxInFlight) is reached.
+type OverloadPolicy string
+
+const (
+	// OverloadQueue waits for a free slot for as long as the caller's
+	// context allows. The default.
+	OverloadQueue OverloadPolicy = "QUEUE"
+	// OverloadShed doesn't wait: the delivery is shed (common.Shed) and the
+	// pool retries it in-pipeline after shedRetryDelay.
+	OverloadShed OverloadPolicy = "SHED"
+)
+
+// ParseOverloadPolicy parses QUEUE or SHED, case-insensitively. Empty is
+// OverloadQueue.
+func ParseOverloadPolicy(s string) (OverloadPolicy, error) {
+	switch p := OverloadPolicy(strings.ToUpper(strings.TrimSpace(s))); p {
+	case "":
+		return OverloadQueue, nil
+	case OverloadQueue, OverloadShed:
+		return p, nil
+	}
+	return "", fmt.Errorf("overload policy %q: want QUEUE or SHED", s)
+}
+
+const (
+	// DefaultRetryBudget lets retries be at most a fifth of the mediator's
+	// outbound requests.
+	DefaultRetryBudget = 0.2
+	// DefaultRetryBudgetMinPerSecond retries are always allowed, so a
+	// trickle of traffic can still retry a blip.
+	DefaultRetryBudgetMinPerSecond = 10
+
+	// retryBudgetWindow is how many one-second buckets the retry share is
+	// measured over.
+	retryBudgetWindow = 10
+	// shedRetryDelay is how long (seconds) the pool waits before trying a
+	// shed delivery again.
+	shedRetryDelay = 5
+)
+
+// overloadGuard bounds how hard the mediator can push on receivers: a
+// global in-flight ceiling across every pool and host, and a retry budget
+// so an outage isn't amplified by every message retrying at once. Safe
+// for concurrent use.
+type overloadGuard struct {
+	policy OverloadPolicy
+	slots  chan struct{} // nil = no ceiling
+	limit  int
+
+	// budget is the largest share of requests that may be retries; >= 1
+	// disables it. minRetries are allowed per window regardless.
+	budget     float64
+	minRetries int
+
+	mu      sync.Mutex
+	buckets [retryBudgetWindow]budgetBucket
+
+	inFlight        atomic.Int64
+	waiting         atomic.Int64
+	queued          atomic.Uint64
+	shed            atomic.Uint64
+	budgetExhausted atomic.Uint64
+}
+
+// budgetBucket counts one second of outbound requests.
+type budgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+func newOverloadGuard(cfg MediatorConfig) *overloadGuard {
+	g := &overloadGuard{
+		policy:     cfg.OverloadPolicy,
+		limit:      cfg.MaxInFlight,
+		budget:     cfg.RetryBudget,
+		minRetries: cfg.RetryBudgetMinPerSecond * retryBudgetWindow,
+	}
+	if g.policy == "" {
+		g.policy = OverloadQueue
+	}
+	if g.budget <= 0 {
+		g.budget = 1
+	}
+	if cfg.MaxInFlight > 0 {
+		g.slots = make(chan struct{}, cfg.MaxInFlight)
+	}
+	return g
+}
+
+// acquire takes an in-flight slot; release gives it back. false means
+// there was no slot: the ceiling is reached under OverloadShed, or ctx
+// ended while queued.
+func (g *overloadGuard) acquire(ctx context.Context) bool {
+	if g.slots != nil {
+		select {
+		case g.slots <- struct{}{}:
+		default:
+			if g.policy == OverloadShed {
+				g.shed.Add(1)
+				return false
+			}
+			g.queued.Add(1)
+			g.waiting.Add(1)
+			select {
+			case g.slots <- struct{}{}:
+				g.waiting.Add(-1)
+			case <-ctx.Done():
+				g.waiting.Add(-1)
+				return false
+			}
+		}
+	}
+	g.inFlight.Add(1)
+	return true
+}
+
+func (g *overloadGuard) release() {
+	g.inFlight.Add(-1)
+	if g.slots != nil {
+		<-g.slots
+	}
+}
+
+// admit counts one outbound request against the retry budget. A first
+// attempt is always admitted; a retry only while the window's retries
+// are under the floor or within the budget's share of its requests.
+func (g *overloadGuard) admit(retry bool, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if retry && g.budget < 1 {
+		requests, retries := g.windowLocked(now)
+		if retries >= g.minRetries && float64(retries+1) > g.budget*float64(requests+1) {
+			g.budgetExhausted.Add(1)
+			return false
+		}
+	}
+	b := &g.buckets[now.Unix()%retryBudgetWindow]
+	if b.second != now.Unix() {
+		*b = budgetBucket{second: now.Unix()}
+	}
+	b.requests++
+	if retry {
+		b.retries++
+	}
+	return true
+}
+
+func (g *overloadGuard) windowLocked(now time.Time) (requests, retries int) {
+	cutoff := now.Unix() - retryBudgetWindow
+	for _, b := range g.buckets {
+		if b.second > cutoff {
+			requests += b.requests
+			retries += b.retries
+		}
+	}
+	return requests, retries
+}
+
+// OverloadStats is a snapshot of the mediator's in-flight ceiling and
+// retry budget.
+type OverloadStats struct {
+	// MaxInFlight is the ceiling; 0 means none.
+	MaxInFlight int
+	Policy      OverloadPolicy
+	// InFlight are requests being made; Waiting are queued for a slot.
+	InFlight int
+	Waiting  int
+	// Queued and Shed count the deliveries that found the ceiling reached
+	// and waited for a slot or were turned away.
+	Queued uint64
+	Shed   uint64
+	// RetryBudgetExhausted counts retries refused by the budget.
+	RetryBudgetExhausted uint64
+	// RetryShare is the share of the window's requests that were retries.
+	RetryShare float64
+}
+
+func (g *overloadGuard) stats(now time.Time) OverloadStats {
+	g.mu.Lock()
+	requests, retries := g.windowLocked(now)
+	g.mu.Unlock()
+	st := OverloadStats{
+		MaxInFlight:          g.limit,
+		Policy:               g.policy,
+		InFlight:             int(g.inFlight.Load()),
+		Waiting:              int(g.waiting.Load()),
+		Queued:               g.queued.Load(),
+		Shed:                 g.shed.Load(),
+		RetryBudgetExhausted: g.budgetExhausted.Load(),
+	}
+	if requests > 0 {
+		st.RetryShare = float64(retries) / float64(requests)
+	}
+	return st
+}
+
+type retryAttemptKey struct{}
+
+// withRetryAttempt marks ctx as the re-dispatch of a message the receiver
+// has already failed, so the mediator counts it against the retry budget.
+func withRetryAttempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryAttemptKey{}, true)
+}
+
+func isRetryAttempt(ctx context.Context) bool {
+	v, _ := ctx.Value(retryAttemptKey{}).(bool)
+	return v
+}
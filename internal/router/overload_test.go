@@ -0,0 +1,187 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+func TestParseOverloadPolicy(t *testing.T) {
+	for in, want := range map[string]OverloadPolicy{"": OverloadQueue, "queue": OverloadQueue, " SHED ": OverloadShed} {
+		got, err := ParseOverloadPolicy(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseOverloadPolicy("DROP")
+	assert.Error(t, err)
+}
+
+func TestOverloadGuard_CeilingShedsOrQueues(t *testing.T) {
+	shed := newOverloadGuard(MediatorConfig{MaxInFlight: 1, OverloadPolicy: OverloadShed})
+	require.True(t, shed.acquire(context.Background()))
+	assert.False(t, shed.acquire(context.Background()), "SHED must not wait for a slot")
+	shed.release()
+	assert.True(t, shed.acquire(context.Background()))
+	assert.Equal(t, uint64(1), shed.stats(time.Now()).Shed)
+
+	queue := newOverloadGuard(MediatorConfig{MaxInFlight: 1})
+	require.True(t, queue.acquire(context.Background()))
+	got := make(chan bool)
+	go func() { got <- queue.acquire(context.Background()) }()
+	require.Eventually(t, func() bool { return queue.stats(time.Now()).Waiting == 1 }, time.Second, time.Millisecond)
+	queue.release()
+	assert.True(t, <-got, "a queued delivery takes the freed slot")
+	st := queue.stats(time.Now())
+	assert.Equal(t, uint64(1), st.Queued)
+	assert.Equal(t, 1, st.InFlight)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, queue.acquire(ctx), "a queued delivery gives up with its context")
+	assert.Zero(t, queue.stats(time.Now()).Waiting)
+}
+
+func TestOverloadGuard_RetryBudget(t *testing.T) {
+	g := newOverloadGuard(MediatorConfig{RetryBudget: 0.2, RetryBudgetMinPerSecond: 1})
+	now := time.Unix(1_000_000, 0)
+	for range 40 {
+		require.True(t, g.admit(false, now))
+	}
+	// The floor (1/s over the 10s window) covers the first ten retries;
+	// beyond it, retries may be a fifth of all requests: 10 of 50.
+	for i := range 10 {
+		require.True(t, g.admit(true, now), "retry %d", i)
+	}
+	assert.False(t, g.admit(true, now), "over budget")
+	assert.Equal(t, uint64(1), g.stats(now).RetryBudgetExhausted)
+	assert.InDelta(t, 0.2, g.stats(now).RetryShare, 1e-9)
+
+	later := now.Add(retryBudgetWindow * time.Second)
+	assert.True(t, g.admit(true, later), "the window slides")
+	assert.Equal(t, 1.0, g.stats(later).RetryShare)
+
+	off := newOverloadGuard(MediatorConfig{RetryBudget: 1})
+	for range 50 {
+		require.True(t, off.admit(true, now))
+	}
+}
+
+func TestHTTPMediator_RetryBudgetStopsInProcessRetries(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cfg := DevMediatorConfig()
+	cfg.RetryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	cfg.RetryBudget = 0.2
+	cfg.RetryBudgetMinPerSecond = 0
+	m := NewHTTPMediator(cfg, NewBreakerRegistry(DefaultBreakerConfig()))
+	defer m.Close()
+	msg := &common.Message{ID: "msg_1", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL}
+
+	out := m.Mediate(context.Background(), msg)
+	assert.Equal(t, common.MediationErrorProcess, out.Result, "the failure goes back to the pool")
+	assert.Equal(t, int32(1), hits.Load(), "one request in one is already a full retry budget")
+	assert.Equal(t, uint64(1), m.OverloadStats().RetryBudgetExhausted)
+
+	out = m.Mediate(withRetryAttempt(context.Background()), msg)
+	assert.Equal(t, common.MediationShed, out.Result, "a re-dispatch over budget is shed")
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+// retryRecorder answers with outcomes in turn and records whether each
+// call was marked a retry.
+type retryRecorder struct {
+	outcomes []common.MediationOutcome
+	retries  []bool
+}
+
+func (r *retryRecorder) Mediate(ctx context.Context, _ *common.Message) common.MediationOutcome {
+	r.retries = append(r.retries, isRetryAttempt(ctx))
+	return r.outcomes[len(r.retries)-1]
+}
+
+func TestPool_OnlyReceiverFailuresAreRetries(t *testing.T) {
+	med := &retryRecorder{outcomes: []common.MediationOutcome{
+		common.CircuitOpen(0),
+		common.Shed(0, "full"),
+		common.RateLimited(0),
+		common.ErrorProcess(0, "5xx"),
+		common.Success(),
+	}}
+	p := grPool(med, &grConsumer{id: "q1"})
+	m := grMsg("evt_1", "http://t/1")
+	for range med.outcomes {
+		if res, _ := p.processOne(context.Background(), &m); res == processRetry {
+			m.Attempts++
+		}
+	}
+	assert.Equal(t, []bool{false, false, false, false, true}, med.retries,
+		"only the re-dispatch after the receiver failed is charged to the budget")
+	assert.Equal(t, uint(1), m.Failures)
+}
+
+func TestHTTPMediator_ShedsAtInFlightCeiling(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := DevMediatorConfig()
+	cfg.MaxInFlight = 1
+	cfg.OverloadPolicy = OverloadShed
+	m := NewHTTPMediator(cfg, NewBreakerRegistry(DefaultBreakerConfig()))
+	defer m.Close()
+	msg := &common.Message{ID: "msg_1", MediationType: common.MediationTypeHTTP, MediationTarget: srv.URL}
+
+	first := make(chan common.MediationOutcome)
+	go func() { first <- m.Mediate(context.Background(), msg) }()
+	require.Eventually(t, func() bool { return m.OverloadStats().InFlight == 1 }, time.Second, time.Millisecond)
+
+	out := m.Mediate(withRetryAttempt(context.Background()), msg)
+	assert.Equal(t, common.MediationShed, out.Result)
+	assert.Equal(t, shedRetryDelay, out.DelaySeconds)
+	assert.Equal(t, CircuitClosed, m.breakers.Get(srv.URL).State(), "shedding is not a receiver failure")
+	m.overload.mu.Lock()
+	requests, retries := m.overload.windowLocked(time.Now())
+	m.overload.mu.Unlock()
+	assert.Equal(t, 1, requests, "a shed request isn't counted in the budget's window")
+	assert.Zero(t, retries)
+
+	close(release)
+	assert.Equal(t, common.MediationSuccess, (<-first).Result)
+	assert.Zero(t, m.OverloadStats().InFlight)
+}
+
+func TestBuildMediatorConfig_OverloadOverrides(t *testing.T) {
+	cfg, err := BuildMediatorConfig(false, MediatorOverrides{})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxInFlight)
+	assert.Equal(t, OverloadQueue, cfg.OverloadPolicy)
+	assert.Equal(t, DefaultRetryBudget, cfg.RetryBudget)
+
+	cfg, err = BuildMediatorConfig(false, MediatorOverrides{
+		MaxInFlight: 500, OverloadPolicy: OverloadShed, RetryBudget: 0.1, RetryBudgetMinPerSecond: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 500, cfg.MaxInFlight)
+	assert.Equal(t, OverloadShed, cfg.OverloadPolicy)
+	assert.Equal(t, 0.1, cfg.RetryBudget)
+	assert.Equal(t, 2, cfg.RetryBudgetMinPerSecond)
+
+	_, err = BuildMediatorConfig(false, MediatorOverrides{RetryBudget: 1.5})
+	assert.Error(t, err)
+}
@@ -0,0 +1,246 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// PipelineStore persists the in-flight tracker so a restarted router can
+// reconcile what its previous incarnation held instead of leaving it all
+// to broker redelivery. Entries are scoped by instance ID, which must be
+// stable across restarts of the same router.
+type PipelineStore interface {
+	// Load returns instanceID's saved entries.
+	Load(ctx context.Context, instanceID string) ([]PipelineEntry, error)
+	// Apply upserts put and deletes the entries with the message IDs in
+	// del, for instanceID.
+	Apply(ctx context.Context, instanceID string, put []PipelineEntry, del []string) error
+	Close() error
+}
+
+// PipelineEntry is one persisted in-flight message.
+type PipelineEntry struct {
+	common.InFlightMessage
+	// Mediating reports that the message was inside a worker, possibly
+	// mid-delivery, when it was saved.
+	Mediating bool
+}
+
+// PipelineConfig tunes a PipelinePersister.
+type PipelineConfig struct {
+	// InstanceID scopes this router's entries; required.
+	InstanceID string
+	// Interval is how often the tracker is written behind (default 5s).
+	Interval time.Duration
+	// OrphanAfter is how long a recovered message waits for a consumer on
+	// its queue before it is reported orphaned (default 2m).
+	OrphanAfter time.Duration
+	// Extend is the visibility extension for messages recovered
+	// mid-delivery — the longest the receiver may still be working on one
+	// (the mediator timeout).
+	Extend time.Duration
+}
+
+// PipelinePersister writes the in-flight tracker behind to a
+// PipelineStore and, on start, reconciles the entries a previous run left:
+//   - a message that was only buffered or waiting on a retry is NACKed
+//     with no delay, so it comes back now rather than after its
+//     visibility timeout;
+//   - a message that was mid-delivery has its visibility extended by
+//     Extend, so a redelivery doesn't race a receiver still working on it;
+//   - a message already redelivered and tracked again needs nothing;
+//   - a message whose queue has no consumer after OrphanAfter is reported
+//     as orphaned (a ROUTING warning); its broker returns it eventually.
+//
+// Persistence is best effort and never blocks delivery: a failed write is
+// logged and retried on the next tick.
+type PipelinePersister struct {
+	cfg       PipelineConfig
+	store     PipelineStore
+	tracker   *InFlightTracker
+	consumers func(queueID string) queue.Consumer
+	mediating func() []MediatingEntry
+	warnings  *WarningService
+	now       func() time.Time
+
+	// Touched only by the Run goroutine and, after it returns, Flush.
+	saved     map[string]PipelineEntry
+	recovered []PipelineEntry
+	started   time.Time
+}
+
+// NewPipelinePersister wires a persister over m's tracker and consumers.
+// warnings may be nil.
+func NewPipelinePersister(cfg PipelineConfig, store PipelineStore, tracker *InFlightTracker, m *Manager, warnings *WarningService) *PipelinePersister {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.OrphanAfter <= 0 {
+		cfg.OrphanAfter = 2 * time.Minute
+	}
+	return &PipelinePersister{
+		cfg:       cfg,
+		store:     store,
+		tracker:   tracker,
+		consumers: m.resolveConsumer,
+		mediating: m.MediatingSnapshot,
+		warnings:  warnings,
+		now:       time.Now,
+		saved:     make(map[string]PipelineEntry),
+	}
+}
+
+// Recover loads what the previous run left for reconciliation. Call once,
+// before Run.
+func (p *PipelinePersister) Recover(ctx context.Context) error {
+	p.started = p.now()
+	entries, err := p.store.Load(ctx, p.cfg.InstanceID)
+	if err != nil {
+		return fmt.Errorf("load pipeline: %w", err)
+	}
+	for _, e := range entries {
+		p.saved[e.MessageID] = e
+	}
+	p.recovered = entries
+	if len(entries) > 0 {
+		slog.Info("pipeline: recovering in-flight messages from the previous run",
+			"instance", p.cfg.InstanceID, "count", len(entries))
+	}
+	return nil
+}
+
+// Run reconciles recovered entries and writes the tracker behind every
+// Interval until ctx ends. Call Flush after the drain for a final write.
+func (p *PipelinePersister) Run(ctx context.Context) {
+	tick := time.NewTicker(p.cfg.Interval)
+	defer tick.Stop()
+	for {
+		p.reconcile(ctx)
+		if err := p.Flush(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("pipeline: write failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// Flush writes the changes since the last write: new and changed entries,
+// and deletions for messages that left the pipeline.
+func (p *PipelinePersister) Flush(ctx context.Context) error {
+	cur := p.current()
+	var put []PipelineEntry
+	var del []string
+	for id, e := range cur {
+		if prev, ok := p.saved[id]; !ok || changed(prev, e) {
+			put = append(put, e)
+		}
+	}
+	for id := range p.saved {
+		if _, ok := cur[id]; !ok {
+			del = append(del, id)
+		}
+	}
+	if len(put) == 0 && len(del) == 0 {
+		return nil
+	}
+	if err := p.store.Apply(ctx, p.cfg.InstanceID, put, del); err != nil {
+		return err
+	}
+	p.saved = cur
+	return nil
+}
+
+// current is what should be persisted: the tracker, plus recovered
+// entries not yet reconciled.
+func (p *PipelinePersister) current() map[string]PipelineEntry {
+	inWorker := make(map[string]bool)
+	for _, e := range p.mediating() {
+		inWorker[e.MessageID] = true
+	}
+	out := make(map[string]PipelineEntry)
+	for _, im := range p.tracker.Snapshot() {
+		out[im.MessageID] = PipelineEntry{InFlightMessage: im, Mediating: inWorker[im.MessageID]}
+	}
+	for _, e := range p.recovered {
+		if _, ok := out[e.MessageID]; !ok {
+			out[e.MessageID] = e
+		}
+	}
+	return out
+}
+
+func changed(a, b PipelineEntry) bool {
+	return a.ReceiptHandle != b.ReceiptHandle || a.Attempts != b.Attempts ||
+		a.Mediating != b.Mediating || a.BrokerMessageID != b.BrokerMessageID
+}
+
+// reconcile settles each recovered entry whose queue's consumer is up.
+func (p *PipelinePersister) reconcile(ctx context.Context) {
+	if len(p.recovered) == 0 {
+		return
+	}
+	var pending, orphaned []PipelineEntry
+	var released, extended, redelivered int
+	for _, e := range p.recovered {
+		if _, ok := p.tracker.CurrentReceipt(e.MessageID, e.BrokerMessageID); ok {
+			redelivered++
+			continue
+		}
+		c := p.consumers(e.QueueIdentifier)
+		if c == nil {
+			if p.now().Sub(p.started) < p.cfg.OrphanAfter {
+				pending = append(pending, e)
+			} else {
+				orphaned = append(orphaned, e)
+			}
+			continue
+		}
+		var err error
+		if e.Mediating {
+			err = c.ExtendVisibility(ctx, e.ReceiptHandle, uint32(p.cfg.Extend.Seconds()))
+			extended++
+		} else {
+			var now uint32
+			err = c.Nack(ctx, e.ReceiptHandle, &now)
+			released++
+		}
+		if err != nil {
+			// Usually a stale receipt: the broker already redelivered it.
+			slog.Debug("pipeline: recovered message not settled", "message_id", e.MessageID, "queue", e.QueueIdentifier, "err", err)
+		}
+	}
+	p.recovered = pending
+	if released+extended+redelivered > 0 {
+		slog.Info("pipeline: reconciled recovered messages",
+			"released", released, "extended", extended, "redelivered", redelivered, "waiting", len(pending))
+	}
+	if len(orphaned) > 0 {
+		p.reportOrphans(orphaned)
+	}
+}
+
+func (p *PipelinePersister) reportOrphans(orphaned []PipelineEntry) {
+	var queues []string
+	for _, e := range orphaned {
+		slog.Warn("pipeline: orphaned in-flight message", "message_id", e.MessageID,
+			"queue", e.QueueIdentifier, "pool", e.PoolCode, "started_at", e.StartedAt)
+		if !slices.Contains(queues, e.QueueIdentifier) {
+			queues = append(queues, e.QueueIdentifier)
+		}
+	}
+	if p.warnings != nil {
+		p.warnings.Add(WarningCategoryRouting, WarningWarning,
+			fmt.Sprintf("%d in-flight messages from before the restart have no consumer on their queue (%s); the broker redelivers them once their visibility timeout lapses",
+				len(orphaned), strings.Join(queues, ", ")), "pipeline")
+	}
+}
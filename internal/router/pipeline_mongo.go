@@ -0,0 +1,135 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const pipelineCollection = "router_pipeline"
+
+// MongoPipelineStore keeps in-flight entries in router_pipeline, one
+// document per instance and message.
+type MongoPipelineStore struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+}
+
+type pipelineDoc struct {
+	ID              string    `bson:"_id"`
+	Instance        string    `bson:"instance"`
+	MessageID       string    `bson:"message_id"`
+	BrokerMessageID string    `bson:"broker_message_id,omitempty"`
+	PoolCode        string    `bson:"pool_code"`
+	Queue           string    `bson:"queue"`
+	MessageGroupID  string    `bson:"message_group_id,omitempty"`
+	BatchID         string    `bson:"batch_id,omitempty"`
+	ReceiptHandle   string    `bson:"receipt_handle"`
+	Attempts        uint      `bson:"attempts"`
+	Mediating       bool      `bson:"mediating"`
+	StartedAt       time.Time `bson:"started_at"`
+	LastSeenAt      time.Time `bson:"last_seen_at"`
+}
+
+// NewMongoPipelineStore connects to uri, targets database dbName and
+// ensures the instance index.
+func NewMongoPipelineStore(ctx context.Context, uri, dbName string, mc mongoconn.Config) (*MongoPipelineStore, error) {
+	if uri == "" {
+		return nil, errors.New("mongo pipeline store requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	client, err := mongoconn.Connect(ctx, "router-pipeline", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	st := &MongoPipelineStore{client: client, coll: client.Database(dbName).Collection(pipelineCollection)}
+	if _, err := st.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "instance", Value: 1}},
+	}); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("pipeline instance index: %w", err)
+	}
+	return st, nil
+}
+
+// Load implements PipelineStore.
+func (st *MongoPipelineStore) Load(ctx context.Context, instanceID string) ([]PipelineEntry, error) {
+	cur, err := st.coll.Find(ctx, bson.M{"instance": instanceID})
+	if err != nil {
+		return nil, err
+	}
+	var docs []pipelineDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	out := make([]PipelineEntry, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, PipelineEntry{
+			InFlightMessage: common.InFlightMessage{
+				MessageID:       d.MessageID,
+				BrokerMessageID: d.BrokerMessageID,
+				PoolCode:        d.PoolCode,
+				QueueIdentifier: d.Queue,
+				StartedAt:       d.StartedAt,
+				LastSeenAt:      d.LastSeenAt,
+				MessageGroupID:  d.MessageGroupID,
+				BatchID:         d.BatchID,
+				ReceiptHandle:   d.ReceiptHandle,
+				Attempts:        d.Attempts,
+			},
+			Mediating: d.Mediating,
+		})
+	}
+	return out, nil
+}
+
+// Apply implements PipelineStore.
+func (st *MongoPipelineStore) Apply(ctx context.Context, instanceID string, put []PipelineEntry, del []string) error {
+	models := make([]mongo.WriteModel, 0, len(put)+len(del))
+	for _, e := range put {
+		d := pipelineDoc{
+			ID:              pipelineDocID(instanceID, e.MessageID),
+			Instance:        instanceID,
+			MessageID:       e.MessageID,
+			BrokerMessageID: e.BrokerMessageID,
+			PoolCode:        e.PoolCode,
+			Queue:           e.QueueIdentifier,
+			MessageGroupID:  e.MessageGroupID,
+			BatchID:         e.BatchID,
+			ReceiptHandle:   e.ReceiptHandle,
+			Attempts:        e.Attempts,
+			Mediating:       e.Mediating,
+			StartedAt:       e.StartedAt,
+			LastSeenAt:      e.LastSeenAt,
+		}
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": d.ID}).SetReplacement(d).SetUpsert(true))
+	}
+	for _, id := range del {
+		models = append(models, mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": pipelineDocID(instanceID, id)}))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := st.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Close implements PipelineStore.
+func (st *MongoPipelineStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return st.client.Disconnect(ctx)
+}
+
+func pipelineDocID(instanceID, messageID string) string { return instanceID + "/" + messageID }
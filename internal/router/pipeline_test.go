@@ -0,0 +1,151 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+type memPipelineStore struct {
+	mu      sync.Mutex
+	entries map[string]map[string]PipelineEntry // instance → message ID → entry
+	applies int
+}
+
+func newMemPipelineStore() *memPipelineStore {
+	return &memPipelineStore{entries: make(map[string]map[string]PipelineEntry)}
+}
+
+func (s *memPipelineStore) Load(_ context.Context, instanceID string) ([]PipelineEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []PipelineEntry
+	for _, e := range s.entries[instanceID] {
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (s *memPipelineStore) Apply(_ context.Context, instanceID string, put []PipelineEntry, del []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applies++
+	m := s.entries[instanceID]
+	if m == nil {
+		m = make(map[string]PipelineEntry)
+		s.entries[instanceID] = m
+	}
+	for _, e := range put {
+		m[e.MessageID] = e
+	}
+	for _, id := range del {
+		delete(m, id)
+	}
+	return nil
+}
+
+func (s *memPipelineStore) Close() error { return nil }
+
+func (s *memPipelineStore) ids(instanceID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for id := range s.entries[instanceID] {
+		out = append(out, id)
+	}
+	return out
+}
+
+// recoveryConsumer records the settle calls a recovery makes.
+type recoveryConsumer struct {
+	queue.Consumer
+	nacked   []string
+	extended map[string]uint32
+}
+
+func (c *recoveryConsumer) Nack(_ context.Context, rh string, delay *uint32) error {
+	if delay == nil || *delay != 0 {
+		panic("recovery must release without delay")
+	}
+	c.nacked = append(c.nacked, rh)
+	return nil
+}
+
+func (c *recoveryConsumer) ExtendVisibility(_ context.Context, rh string, secs uint32) error {
+	c.extended[rh] = secs
+	return nil
+}
+
+func inFlight(id, queueID, receipt string) *common.InFlightMessage {
+	return common.NewInFlightMessage(&common.Message{ID: id, PoolCode: defaultPoolCode}, "b-"+id, queueID, "1", receipt)
+}
+
+func TestPipelinePersisterWritesTrackerBehind(t *testing.T) {
+	store := newMemPipelineStore()
+	tr := NewInFlightTracker()
+	p := NewPipelinePersister(PipelineConfig{InstanceID: "r1"}, store, tr, NewManager(&cascadeMediator{}, tr), nil)
+	ctx := context.Background()
+	require.NoError(t, p.Recover(ctx))
+
+	tr.Register(inFlight("m1", "q", "rh-1"))
+	tr.Register(inFlight("m2", "q", "rh-2"))
+	require.NoError(t, p.Flush(ctx))
+	assert.ElementsMatch(t, []string{"m1", "m2"}, store.ids("r1"))
+
+	require.NoError(t, p.Flush(ctx))
+	assert.Equal(t, 1, store.applies, "an unchanged tracker writes nothing")
+
+	tr.Remove("m1", "b-m1")
+	tr.Register(inFlight("m2", "q", "rh-2b")) // redelivery swaps the receipt
+	require.NoError(t, p.Flush(ctx))
+	assert.Equal(t, []string{"m2"}, store.ids("r1"))
+	assert.Equal(t, "rh-2b", store.entries["r1"]["m2"].ReceiptHandle)
+}
+
+func TestPipelinePersisterReconcilesPreviousRun(t *testing.T) {
+	store := newMemPipelineStore()
+	prev := func(id, queueID string, mediating bool) PipelineEntry {
+		return PipelineEntry{InFlightMessage: *inFlight(id, queueID, "rh-"+id), Mediating: mediating}
+	}
+	require.NoError(t, store.Apply(context.Background(), "r1", []PipelineEntry{
+		prev("buffered", "q", false),
+		prev("delivering", "q", true),
+		prev("back", "q", false),
+		prev("lost", "gone", false),
+	}, nil))
+	require.NoError(t, store.Apply(context.Background(), "r2", []PipelineEntry{prev("other", "q", false)}, nil))
+
+	tr := NewInFlightTracker()
+	m := NewManager(&cascadeMediator{}, tr)
+	cons := &recoveryConsumer{extended: make(map[string]uint32)}
+	m.consumers["q"] = &runningConsumer{consumer: cons}
+	ws := NewWarningService(DefaultWarningServiceConfig())
+	p := NewPipelinePersister(PipelineConfig{InstanceID: "r1", Extend: 15 * time.Minute}, store, tr, m, ws)
+	clock := time.Now()
+	p.now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	require.NoError(t, p.Recover(ctx))
+	tr.Register(inFlight("back", "q", "rh-new")) // already redelivered and tracked
+
+	p.reconcile(ctx)
+	assert.Equal(t, []string{"rh-buffered"}, cons.nacked)
+	assert.Equal(t, map[string]uint32{"rh-delivering": 900}, cons.extended)
+	require.NoError(t, p.Flush(ctx))
+	assert.ElementsMatch(t, []string{"back", "lost"}, store.ids("r1"), "the unreconciled entry stays persisted")
+	assert.Empty(t, ws.ByCategory(WarningCategoryRouting))
+
+	clock = clock.Add(3 * time.Minute)
+	p.reconcile(ctx)
+	require.Len(t, ws.ByCategory(WarningCategoryRouting), 1, "no consumer for its queue → orphaned")
+	require.NoError(t, p.Flush(ctx))
+	assert.Equal(t, []string{"back"}, store.ids("r1"))
+	assert.Equal(t, []string{"other"}, store.ids("r2"), "other instances are untouched")
+}
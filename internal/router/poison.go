@@ -0,0 +1,307 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// PoisonSampleLimit caps the body kept for a poison message. A message
+// pointer is a few hundred bytes; a body past this is kept truncated,
+// which is enough to see what went wrong but can't be replayed.
+const PoisonSampleLimit = 64 << 10
+
+// DefaultPoisonQueryLimit bounds a poison listing without an explicit limit.
+const DefaultPoisonQueryLimit = 200
+
+// maxMemoryPoison bounds MemoryPoisonStore; the least recently seen
+// entries are evicted first.
+const maxMemoryPoison = 1000
+
+// ErrPoisonNotFound is returned for an unknown poison message ID.
+var ErrPoisonNotFound = errors.New("poison message not found")
+
+// ErrPoisonNotReplayable is wrapped by Replay when the stored body still
+// doesn't decode (or was truncated), so republishing it would only
+// quarantine it again.
+var ErrPoisonNotReplayable = errors.New("poison message not replayable")
+
+// PoisonMessage is a quarantined message that a consumer could not
+// decode. Repeats of the same body on the same queue collapse into one
+// entry: ID is a hash of both, and Count / LastSeenAt track the repeats.
+type PoisonMessage struct {
+	ID              string
+	QueueName       string // the router's queue config name
+	QueueIdentifier string // the consumer's identifier (SQS name, stream/consumer)
+	Subject         string
+	BrokerMessageID string // from the most recent sighting
+	Body            []byte
+	Truncated       bool
+	ContentEncoding string
+	Error           string
+	Count           uint64
+	FirstSeenAt     time.Time
+	LastSeenAt      time.Time
+}
+
+// PoisonQuery filters a poison listing. Zero fields don't filter.
+type PoisonQuery struct {
+	QueueName string
+	// Limit caps the result; 0 means DefaultPoisonQueryLimit.
+	Limit int
+}
+
+func (q PoisonQuery) limit() int {
+	if q.Limit <= 0 {
+		return DefaultPoisonQueryLimit
+	}
+	return q.Limit
+}
+
+// PoisonStore keeps quarantined messages.
+type PoisonStore interface {
+	// Record upserts p by ID: a new entry is stored as given, a repeat
+	// adds p.Count and takes p's LastSeenAt, BrokerMessageID and Error.
+	// Reports whether the entry is new.
+	Record(ctx context.Context, p PoisonMessage) (bool, error)
+	// Find returns the entries matching q, most recently seen first.
+	Find(ctx context.Context, q PoisonQuery) ([]PoisonMessage, error)
+	// Get returns one entry, or ErrPoisonNotFound.
+	Get(ctx context.Context, id string) (PoisonMessage, error)
+	// Delete removes one entry, or returns ErrPoisonNotFound.
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+// PoisonQuarantine receives poison messages from the consumers, stores
+// them, counts them per queue and raises a warning for each new one.
+type PoisonQuarantine struct {
+	store    PoisonStore
+	warnings *WarningService
+	now      func() time.Time
+
+	mu     sync.Mutex
+	counts map[string]uint64 // queue name → poison messages received
+}
+
+// NewPoisonQuarantine builds a quarantine over store. warnings may be nil.
+func NewPoisonQuarantine(store PoisonStore, warnings *WarningService) *PoisonQuarantine {
+	return &PoisonQuarantine{
+		store:    store,
+		warnings: warnings,
+		now:      func() time.Time { return time.Now().UTC() },
+		counts:   make(map[string]uint64),
+	}
+}
+
+// poisonID is the dedup key: the same body on the same queue is one entry.
+func poisonID(queueName string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(queueName))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// Handle quarantines p, received on the queue configured as queueName.
+// It is the consumers' queue.PoisonHandler (bound per queue by the
+// Manager). A store failure is logged; the message is still counted.
+func (pq *PoisonQuarantine) Handle(ctx context.Context, queueName string, p queue.PoisonMessage) {
+	pq.mu.Lock()
+	pq.counts[queueName]++
+	pq.mu.Unlock()
+
+	now := pq.now()
+	rec := PoisonMessage{
+		ID:              poisonID(queueName, p.Body),
+		QueueName:       queueName,
+		QueueIdentifier: p.QueueIdentifier,
+		Subject:         p.Subject,
+		BrokerMessageID: p.BrokerMessageID,
+		Body:            p.Body,
+		ContentEncoding: p.ContentEncoding,
+		Count:           1,
+		FirstSeenAt:     now,
+		LastSeenAt:      now,
+	}
+	if p.Err != nil {
+		rec.Error = p.Err.Error()
+	}
+	if len(rec.Body) > PoisonSampleLimit {
+		rec.Body, rec.Truncated = rec.Body[:PoisonSampleLimit], true
+	}
+
+	sctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	isNew, err := pq.store.Record(sctx, rec)
+	if err != nil {
+		slog.Error("poison: quarantine failed; message dropped", "queue", queueName,
+			"broker_message_id", p.BrokerMessageID, "decode_err", rec.Error, "err", err)
+		return
+	}
+	slog.Warn("poison: message quarantined", "queue", queueName, "id", rec.ID,
+		"broker_message_id", p.BrokerMessageID, "new", isNew, "err", rec.Error)
+	if isNew && pq.warnings != nil {
+		pq.warnings.Add(WarningCategoryPoisonMessage, WarningError,
+			fmt.Sprintf("Undecodable message on queue %s quarantined as %s: %s", queueName, rec.ID, rec.Error),
+			"router")
+	}
+}
+
+// Counts returns the poison messages received per queue name since start.
+func (pq *PoisonQuarantine) Counts() map[string]uint64 {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	out := make(map[string]uint64, len(pq.counts))
+	for k, v := range pq.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// List returns quarantined messages matching q.
+func (pq *PoisonQuarantine) List(ctx context.Context, q PoisonQuery) ([]PoisonMessage, error) {
+	return pq.store.Find(ctx, q)
+}
+
+// Get returns one quarantined message.
+func (pq *PoisonQuarantine) Get(ctx context.Context, id string) (PoisonMessage, error) {
+	return pq.store.Get(ctx, id)
+}
+
+// Delete discards a quarantined message.
+func (pq *PoisonQuarantine) Delete(ctx context.Context, id string) error {
+	return pq.store.Delete(ctx, id)
+}
+
+// PublisherSource resolves the publisher for a queue config name.
+// Satisfied by *Manager.
+type PublisherSource interface {
+	Publisher(ctx context.Context, key string) (queue.Publisher, error)
+}
+
+// Replay republishes a quarantined message to its source queue and
+// removes it from quarantine. body, when non-nil, replaces the stored
+// payload — an operator's corrected JSON — and is read as plain JSON.
+// Returns the publish ID.
+func (pq *PoisonQuarantine) Replay(ctx context.Context, id string, body []byte, pubs PublisherSource) (string, error) {
+	p, err := pq.store.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	enc := p.ContentEncoding
+	if body == nil {
+		if p.Truncated {
+			return "", fmt.Errorf("%w: stored body was truncated at %d bytes", ErrPoisonNotReplayable, PoisonSampleLimit)
+		}
+		body = p.Body
+	} else {
+		enc = ""
+	}
+	m, err := queue.DecodeMessage(body, enc)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPoisonNotReplayable, err)
+	}
+	pub, err := pubs.Publisher(ctx, p.QueueName)
+	if err != nil {
+		return "", err
+	}
+	pubID, err := pub.Publish(ctx, m)
+	if err != nil {
+		return "", fmt.Errorf("republish to %s: %w", p.QueueName, err)
+	}
+	if err := pq.store.Delete(ctx, id); err != nil && !errors.Is(err, ErrPoisonNotFound) {
+		slog.Warn("poison: replayed but not removed from quarantine", "id", id, "err", err)
+	}
+	slog.Info("poison: message replayed", "id", id, "queue", p.QueueName, "message_id", m.ID, "publish_id", pubID)
+	return pubID, nil
+}
+
+// MemoryPoisonStore is the in-process PoisonStore used when no Mongo
+// store is configured. Entries don't survive a restart.
+type MemoryPoisonStore struct {
+	mu      sync.Mutex
+	entries map[string]PoisonMessage
+}
+
+// NewMemoryPoisonStore builds an empty store.
+func NewMemoryPoisonStore() *MemoryPoisonStore {
+	return &MemoryPoisonStore{entries: make(map[string]PoisonMessage)}
+}
+
+// Record implements PoisonStore.
+func (st *MemoryPoisonStore) Record(_ context.Context, p PoisonMessage) (bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if cur, ok := st.entries[p.ID]; ok {
+		cur.Count += p.Count
+		cur.LastSeenAt = p.LastSeenAt
+		cur.BrokerMessageID = p.BrokerMessageID
+		cur.Error = p.Error
+		st.entries[p.ID] = cur
+		return false, nil
+	}
+	if len(st.entries) >= maxMemoryPoison {
+		oldest := ""
+		for id, e := range st.entries {
+			if oldest == "" || e.LastSeenAt.Before(st.entries[oldest].LastSeenAt) {
+				oldest = id
+			}
+		}
+		delete(st.entries, oldest)
+	}
+	p.Body = slices.Clone(p.Body)
+	st.entries[p.ID] = p
+	return true, nil
+}
+
+// Find implements PoisonStore.
+func (st *MemoryPoisonStore) Find(_ context.Context, q PoisonQuery) ([]PoisonMessage, error) {
+	st.mu.Lock()
+	out := make([]PoisonMessage, 0, len(st.entries))
+	for _, e := range st.entries {
+		if q.QueueName == "" || e.QueueName == q.QueueName {
+			out = append(out, e)
+		}
+	}
+	st.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].LastSeenAt.After(out[j].LastSeenAt) })
+	if len(out) > q.limit() {
+		out = out[:q.limit()]
+	}
+	return out, nil
+}
+
+// Get implements PoisonStore.
+func (st *MemoryPoisonStore) Get(_ context.Context, id string) (PoisonMessage, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	e, ok := st.entries[id]
+	if !ok {
+		return PoisonMessage{}, ErrPoisonNotFound
+	}
+	return e, nil
+}
+
+// Delete implements PoisonStore.
+func (st *MemoryPoisonStore) Delete(_ context.Context, id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.entries[id]; !ok {
+		return ErrPoisonNotFound
+	}
+	delete(st.entries, id)
+	return nil
+}
+
+// Close implements PoisonStore.
+func (st *MemoryPoisonStore) Close() error { return nil }
@@ -0,0 +1,188 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const poisonCollection = "router_poison_messages"
+
+// MongoPoisonStore keeps quarantined messages in router_poison_messages,
+// one document per dedup ID. Retention is a TTL index on last_seen_at,
+// so an entry expires once it stops recurring; changing the retention
+// rebuilds the index on the next start.
+type MongoPoisonStore struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+}
+
+type poisonDoc struct {
+	ID              string    `bson:"_id"`
+	QueueName       string    `bson:"queue_name"`
+	QueueIdentifier string    `bson:"queue_identifier"`
+	Subject         string    `bson:"subject,omitempty"`
+	BrokerMessageID string    `bson:"broker_message_id"`
+	Body            []byte    `bson:"body"`
+	Truncated       bool      `bson:"truncated"`
+	ContentEncoding string    `bson:"content_encoding,omitempty"`
+	Error           string    `bson:"error"`
+	Count           int64     `bson:"count"`
+	FirstSeenAt     time.Time `bson:"first_seen_at"`
+	LastSeenAt      time.Time `bson:"last_seen_at"`
+}
+
+// NewMongoPoisonStore connects to uri, targets database dbName and
+// ensures the collection's indexes. retention <= 0 keeps entries until
+// they are replayed or deleted.
+func NewMongoPoisonStore(ctx context.Context, uri, dbName string, retention time.Duration, mc mongoconn.Config) (*MongoPoisonStore, error) {
+	if uri == "" {
+		return nil, errors.New("mongo poison store requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	client, err := mongoconn.Connect(ctx, "router-poison", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	st := &MongoPoisonStore{client: client, coll: client.Database(dbName).Collection(poisonCollection)}
+	if err := st.ensureIndexes(ctx, retention); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+	return st, nil
+}
+
+func (st *MongoPoisonStore) ensureIndexes(ctx context.Context, retention time.Duration) error {
+	const ttlName = "last_seen_at_ttl"
+	// Same dance as the warning store: Mongo won't change an existing
+	// TTL index's expireAfterSeconds in place.
+	if _, err := st.coll.Indexes().DropOne(ctx, ttlName); err != nil {
+		var cmdErr mongo.CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Code != 27 { // IndexNotFound
+			return fmt.Errorf("drop poison ttl index: %w", err)
+		}
+	}
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "queue_name", Value: 1}, {Key: "last_seen_at", Value: -1}}},
+	}
+	if retention > 0 {
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{{Key: "last_seen_at", Value: 1}},
+			Options: options.Index().SetName(ttlName).
+				SetExpireAfterSeconds(int32(retention.Seconds())),
+		})
+	} else {
+		models = append(models, mongo.IndexModel{Keys: bson.D{{Key: "last_seen_at", Value: -1}}})
+	}
+	if _, err := st.coll.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("create poison indexes: %w", err)
+	}
+	return nil
+}
+
+// Record implements PoisonStore. One upsert: the insert-only fields go
+// in $setOnInsert, so a repeat only bumps the count and last sighting.
+func (st *MongoPoisonStore) Record(ctx context.Context, p PoisonMessage) (bool, error) {
+	res, err := st.coll.UpdateOne(ctx, bson.M{"_id": p.ID}, bson.M{
+		"$setOnInsert": bson.M{
+			"queue_name":       p.QueueName,
+			"queue_identifier": p.QueueIdentifier,
+			"subject":          p.Subject,
+			"body":             p.Body,
+			"truncated":        p.Truncated,
+			"content_encoding": p.ContentEncoding,
+			"first_seen_at":    p.FirstSeenAt,
+		},
+		"$set": bson.M{
+			"broker_message_id": p.BrokerMessageID,
+			"error":             p.Error,
+			"last_seen_at":      p.LastSeenAt,
+		},
+		"$inc": bson.M{"count": int64(p.Count)},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+// Find implements PoisonStore.
+func (st *MongoPoisonStore) Find(ctx context.Context, q PoisonQuery) ([]PoisonMessage, error) {
+	filter := bson.M{}
+	if q.QueueName != "" {
+		filter["queue_name"] = q.QueueName
+	}
+	cur, err := st.coll.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "last_seen_at", Value: -1}}).
+		SetLimit(int64(q.limit())))
+	if err != nil {
+		return nil, err
+	}
+	var docs []poisonDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	out := make([]PoisonMessage, len(docs))
+	for i, d := range docs {
+		out[i] = d.message()
+	}
+	return out, nil
+}
+
+// Get implements PoisonStore.
+func (st *MongoPoisonStore) Get(ctx context.Context, id string) (PoisonMessage, error) {
+	var d poisonDoc
+	err := st.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&d)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return PoisonMessage{}, ErrPoisonNotFound
+	}
+	if err != nil {
+		return PoisonMessage{}, err
+	}
+	return d.message(), nil
+}
+
+// Delete implements PoisonStore.
+func (st *MongoPoisonStore) Delete(ctx context.Context, id string) error {
+	res, err := st.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrPoisonNotFound
+	}
+	return nil
+}
+
+// Close implements PoisonStore.
+func (st *MongoPoisonStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return st.client.Disconnect(ctx)
+}
+
+func (d poisonDoc) message() PoisonMessage {
+	return PoisonMessage{
+		ID:              d.ID,
+		QueueName:       d.QueueName,
+		QueueIdentifier: d.QueueIdentifier,
+		Subject:         d.Subject,
+		BrokerMessageID: d.BrokerMessageID,
+		Body:            d.Body,
+		Truncated:       d.Truncated,
+		ContentEncoding: d.ContentEncoding,
+		Error:           d.Error,
+		Count:           uint64(d.Count),
+		FirstSeenAt:     d.FirstSeenAt.UTC(),
+		LastSeenAt:      d.LastSeenAt.UTC(),
+	}
+}
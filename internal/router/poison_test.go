@@ -0,0 +1,129 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+)
+
+// recordingPublisher captures what a replay publishes.
+type recordingPublisher struct {
+	published []common.Message
+}
+
+func (p *recordingPublisher) Identifier() string { return "q" }
+
+func (p *recordingPublisher) Publish(_ context.Context, m common.Message) (string, error) {
+	p.published = append(p.published, m)
+	return fmt.Sprintf("pub-%d", len(p.published)), nil
+}
+
+func (p *recordingPublisher) PublishBatch(ctx context.Context, msgs []common.Message) ([]string, error) {
+	ids := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		id, _ := p.Publish(ctx, m)
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+type publisherMap map[string]queue.Publisher
+
+func (m publisherMap) Publisher(_ context.Context, key string) (queue.Publisher, error) {
+	if p, ok := m[key]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("no queue %q", key)
+}
+
+func poison(body string) queue.PoisonMessage {
+	return queue.PoisonMessage{QueueIdentifier: "orders-sqs", BrokerMessageID: "b1", Body: []byte(body), Err: errors.New("bad json")}
+}
+
+func TestPoisonQuarantine_DedupsPerQueueAndWarnsOnce(t *testing.T) {
+	ctx := context.Background()
+	ws := NewWarningService(WarningServiceConfig{})
+	pq := NewPoisonQuarantine(NewMemoryPoisonStore(), ws)
+
+	pq.Handle(ctx, "orders", poison("{not json"))
+	pq.Handle(ctx, "orders", poison("{not json"))
+	pq.Handle(ctx, "billing", poison("{not json"))
+
+	assert.Equal(t, map[string]uint64{"orders": 2, "billing": 1}, pq.Counts())
+	assert.Equal(t, 2, ws.Count(), "one warning per new entry, not per repeat")
+
+	orders, err := pq.List(ctx, PoisonQuery{QueueName: "orders"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, uint64(2), orders[0].Count)
+	assert.Equal(t, "bad json", orders[0].Error)
+	assert.Equal(t, "orders-sqs", orders[0].QueueIdentifier)
+
+	all, err := pq.List(ctx, PoisonQuery{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.NotEqual(t, all[0].ID, all[1].ID, "same body on two queues is two entries")
+}
+
+func TestPoisonQuarantine_TruncatedBodyIsNotReplayable(t *testing.T) {
+	ctx := context.Background()
+	pq := NewPoisonQuarantine(NewMemoryPoisonStore(), nil)
+	pq.Handle(ctx, "orders", poison(string(bytes.Repeat([]byte("x"), PoisonSampleLimit+10))))
+
+	list, err := pq.List(ctx, PoisonQuery{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.True(t, list[0].Truncated)
+	assert.Len(t, list[0].Body, PoisonSampleLimit)
+
+	_, err = pq.Replay(ctx, list[0].ID, nil, publisherMap{})
+	assert.ErrorIs(t, err, ErrPoisonNotReplayable)
+}
+
+func TestPoisonQuarantine_Replay(t *testing.T) {
+	ctx := context.Background()
+	pq := NewPoisonQuarantine(NewMemoryPoisonStore(), nil)
+	pub := &recordingPublisher{}
+	pubs := publisherMap{"orders": pub}
+
+	pq.Handle(ctx, "orders", poison(`{"id":"m1","poolCode":`))
+	list, _ := pq.List(ctx, PoisonQuery{})
+	require.Len(t, list, 1)
+	id := list[0].ID
+
+	_, err := pq.Replay(ctx, id, nil, pubs)
+	require.ErrorIs(t, err, ErrPoisonNotReplayable, "the stored body still doesn't decode")
+	_, err = pq.Get(ctx, id)
+	require.NoError(t, err, "a failed replay leaves the entry quarantined")
+
+	pubID, err := pq.Replay(ctx, id, []byte(`{"id":"m1","poolCode":"P","mediationTarget":"http://x"}`), pubs)
+	require.NoError(t, err)
+	assert.Equal(t, "pub-1", pubID)
+	require.Len(t, pub.published, 1)
+	assert.Equal(t, "m1", pub.published[0].ID)
+
+	_, err = pq.Get(ctx, id)
+	assert.ErrorIs(t, err, ErrPoisonNotFound, "a replayed message leaves quarantine")
+	_, err = pq.Replay(ctx, id, nil, pubs)
+	assert.ErrorIs(t, err, ErrPoisonNotFound)
+}
+
+func TestMemoryPoisonStore_EvictsLeastRecentlySeen(t *testing.T) {
+	ctx := context.Background()
+	pq := NewPoisonQuarantine(NewMemoryPoisonStore(), nil)
+	for i := 0; i <= maxMemoryPoison; i++ {
+		pq.Handle(ctx, "orders", poison(fmt.Sprintf("bad-%d", i)))
+	}
+	list, err := pq.List(ctx, PoisonQuery{Limit: 2 * maxMemoryPoison})
+	require.NoError(t, err)
+	assert.Len(t, list, maxMemoryPoison)
+	assert.Equal(t, uint64(maxMemoryPoison+1), pq.Counts()["orders"])
+}
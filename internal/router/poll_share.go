@@ -0,0 +1,81 @@
+package router
+
+import "sync"
+
+// pollShare apportions polls between queues by QueueConfig.Weight while
+// every pool is saturated. Without it, whichever consumer happens to
+// recheck first after a slot frees claims it, so a flooded low-priority
+// queue takes capacity from an urgent one in proportion to its backlog.
+//
+// Stride scheduling: each consumer that hit backpressure contends with a
+// pass value; only the lowest pass may poll, and polling advances it by
+// 1/weight. A weight-3 queue therefore gets three polls for every one of
+// a weight-1 queue. Consumers that are not contending (pools had room on
+// their last check) are never gated.
+type pollShare struct {
+	mu      sync.Mutex
+	waiting map[*runningConsumer]struct{}
+	// vtime is the pass of the most recent turn granted.
+	vtime float64
+}
+
+func newPollShare() *pollShare {
+	return &pollShare{waiting: make(map[*runningConsumer]struct{})}
+}
+
+// contend registers rc as waiting for pool capacity. A consumer joining
+// starts no lower than the last granted turn, so time spent polling
+// freely (or a fresh consumer's zero pass) isn't banked as extra turns.
+func (s *pollShare) contend(rc *runningConsumer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.waiting[rc]; ok {
+		return
+	}
+	rc.pass = max(rc.pass, s.vtime)
+	s.waiting[rc] = struct{}{}
+}
+
+// take reports whether rc may poll now. A contending consumer may poll
+// only when it holds the lowest pass; taking the turn ends its contention.
+func (s *pollShare) take(rc *runningConsumer) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.waiting[rc]; !ok {
+		return true
+	}
+	if rc.pass > s.minPassLocked() {
+		return false
+	}
+	delete(s.waiting, rc)
+	s.vtime = rc.pass
+	rc.pass += 1 / float64(queueWeight(rc.queueCfg.Weight))
+	return true
+}
+
+// leave drops rc from the contention; called when its poll loop exits so
+// a departed consumer can't hold the lowest pass forever.
+func (s *pollShare) leave(rc *runningConsumer) {
+	s.mu.Lock()
+	delete(s.waiting, rc)
+	s.mu.Unlock()
+}
+
+func (s *pollShare) minPassLocked() float64 {
+	first := true
+	var lowest float64
+	for rc := range s.waiting {
+		if first || rc.pass < lowest {
+			lowest, first = rc.pass, false
+		}
+	}
+	return lowest
+}
+
+// queueWeight treats an unset weight as 1.
+func queueWeight(w uint32) uint32 {
+	if w == 0 {
+		return 1
+	}
+	return w
+}
@@ -0,0 +1,52 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// Two saturated queues contend every round; turns split by weight.
+func TestPollShare_SplitsTurnsByWeight(t *testing.T) {
+	s := newPollShare()
+	urgent := &runningConsumer{queueCfg: common.QueueConfig{Name: "urgent", Weight: 3}}
+	bulk := &runningConsumer{queueCfg: common.QueueConfig{Name: "bulk"}} // weight 0 → 1
+	turns := map[*runningConsumer]int{}
+	for range 400 {
+		s.contend(urgent)
+		s.contend(bulk)
+		for _, rc := range []*runningConsumer{bulk, urgent} {
+			if s.take(rc) {
+				turns[rc]++
+			}
+		}
+	}
+	if got := float64(turns[urgent]) / float64(turns[bulk]); got < 2.8 || got > 3.2 {
+		t.Fatalf("urgent:bulk turns = %d:%d (%.2f), want ~3:1", turns[urgent], turns[bulk], got)
+	}
+}
+
+func TestPollShare_NonContenderNeverGated(t *testing.T) {
+	s := newPollShare()
+	a := &runningConsumer{}
+	b := &runningConsumer{}
+	s.contend(a)
+	if !s.take(b) {
+		t.Fatal("consumer that never hit backpressure was gated")
+	}
+}
+
+func TestPollShare_LeaveReleasesTurn(t *testing.T) {
+	s := newPollShare()
+	a := &runningConsumer{}
+	b := &runningConsumer{pass: 5}
+	s.contend(a)
+	s.contend(b)
+	if s.take(b) {
+		t.Fatal("higher pass took the turn")
+	}
+	s.leave(a)
+	if !s.take(b) {
+		t.Fatal("turn not released after the lowest consumer left")
+	}
+}
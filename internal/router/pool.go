@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 	"sync/atomic"
@@ -15,6 +16,9 @@ import (
 //   - configured concurrency (semaphore-style worker cap),
 //   - configured rate limit (per-pool token bucket),
 //   - per-endpoint circuit breakers,
+//   - optional in-flight caps per target host and per subscription,
+//   - optional adaptive concurrency (AIMD within configured bounds),
+//   - weighted round-robin between subscriptions competing for workers,
 //   - FIFO ordering within message groups (when DispatchMode requires it).
 //
 // A Pool does NOT own a queue or poll. The Manager polls every queue and
@@ -34,16 +38,23 @@ type Pool struct {
 	// between routing and processing; the action is skipped (logged).
 	resolveConsumer func(queueID string) queue.Consumer
 
-	// sem is the pool-wide concurrency semaphore. Buffered chan: a send
-	// is an acquire (blocks when full); the matching receive is a
-	// release. UpdateConcurrency swaps the chan atomically; workers
-	// snapshot the chan locally before acquire so the matching release
-	// goes back to the same chan they acquired from. During a resize,
-	// in-flight workers continue against the old chan (effective
-	// concurrency = old_in_flight + new_cap) and the old chan is GC'd
-	// once those workers finish.
-	sem         atomic.Value // chan struct{}
-	concurrency atomic.Uint32
+	// workers is the pool-wide concurrency limit; UpdateConcurrency and
+	// adaptive concurrency resize it in place. HighPriority messages
+	// acquire it ahead of the rest.
+	workers *workerLimiter
+	// fair shares workers between subscriptions, weighted round-robin.
+	fair *fairQueue
+	// hostLimit and subLimit cap in-flight messages per target host and
+	// per subscription (PoolConfig.MaxInFlightPerHost/PerSubscription).
+	// Taken before workers — see keyLimiter.
+	hostLimit *keyLimiter
+	subLimit  *keyLimiter
+	// adaptive resizes workers from delivery outcomes; nil = fixed
+	// concurrency (PoolConfig.AdaptiveConcurrency unset).
+	adaptive atomic.Pointer[adaptiveController]
+	// quarantine pauses dispatch after repeated delivery failures; nil =
+	// never (PoolConfig.Quarantine unset).
+	quarantine atomic.Pointer[quarantineController]
 
 	mu      sync.Mutex
 	groupQs map[string]*groupQueue // ordered FIFO queues per message-group
@@ -60,6 +71,12 @@ type Pool struct {
 	// time: FIFO within a group, one worker per message in IMMEDIATE mode).
 	mediatingMu sync.Mutex
 	mediating   map[string]MediatingEntry
+	// handles holds each mediating message's abort switch (Cancel), keyed
+	// like mediating; guarded by mediatingMu.
+	handles map[string]*mediationHandle
+
+	// warnings is optional; set via SetWarnings. nil → no-op.
+	warnings atomic.Pointer[WarningService]
 
 	stopped atomic.Bool
 }
@@ -80,8 +97,9 @@ type MediatingEntry struct {
 // groupQueue is the per-message-group buffer: a single strict FIFO. A message
 // group is an ordering contract, so there is deliberately NO priority lane —
 // letting a "high priority" message jump ahead of an earlier one in the same
-// group would defeat in-order delivery. (Message.HighPriority is a queue-level
-// concern, not an intra-group one, and does not reorder here.) On a retryable
+// group would defeat in-order delivery. (Message.HighPriority only gives a
+// group's head message first claim on a free worker — see workerLimiter — and
+// does not reorder here.) On a retryable
 // failure the drainer re-inserts the message at the FRONT (enqueueFront) so the
 // failed message is the next one attempted — never overtaken by a later one.
 type groupQueue struct {
@@ -129,17 +147,26 @@ func NewPool(cfg common.PoolConfig, mediator Mediator, tracker *InFlightTracker,
 		resolveConsumer: resolveConsumer,
 		groupQs:         make(map[string]*groupQueue),
 		mediating:       make(map[string]MediatingEntry),
+		handles:         make(map[string]*mediationHandle),
+		fair:            newFairQueue(),
+		hostLimit:       newKeyLimiter(cfg.MaxInFlightPerHost),
+		subLimit:        newKeyLimiter(cfg.MaxInFlightPerSubscription),
+	}
+	if cfg.AdaptiveConcurrency != nil {
+		a := newAdaptiveController(*cfg.AdaptiveConcurrency)
+		concurrency = max(a.cfg.MinConcurrency, min(a.cfg.MaxConcurrency, concurrency))
+		p.adaptive.Store(a)
+	}
+	if cfg.Quarantine != nil {
+		p.quarantine.Store(newQuarantineController(*cfg.Quarantine))
 	}
-	p.sem.Store(make(chan struct{}, concurrency))
-	p.concurrency.Store(concurrency)
+	p.workers = newWorkerLimiter(concurrency)
 	return p
 }
 
-// loadSem returns the current concurrency channel. Callers should
-// snapshot it locally before an acquire so that the matching release
-// receives from the same channel even if UpdateConcurrency swaps it
-// mid-flight.
-func (p *Pool) loadSem() chan struct{} { return p.sem.Load().(chan struct{}) }
+// SetWarnings wires a WarningService for operator-action warnings (see
+// Cancel). Opt-in; the Manager sets it as it creates pools.
+func (p *Pool) SetWarnings(ws *WarningService) { p.warnings.Store(ws) }
 
 // consumerFor resolves the source consumer for a message via its origin
 // queue (QueueIdentifier); nil when that queue was deregistered between
@@ -219,25 +246,117 @@ func (p *Pool) UpdateRateLimit(perMinute *uint32) {
 	p.limiter.SetRate(v)
 }
 
-// UpdateConcurrency swaps the semaphore to a new capacity. Returns false
-// on n==0 (invalid). Existing in-flight workers continue to release into
-// the old channel, which is GC'd once empty; new work uses the new
-// channel. Effective concurrency during the transition is bounded by
-// old_in_flight + new_cap; steady state is new_cap.
+// UpdateConcurrency changes the worker limit. Returns false on n==0
+// (invalid). Lowering it lets in-flight workers finish; no new work starts
+// until fewer than n are busy.
 func (p *Pool) UpdateConcurrency(n uint32) bool {
 	if n == 0 {
 		return false
 	}
-	old := p.concurrency.Load()
-	if n == old {
-		return true
+	if old, changed := p.resize(n); changed {
+		slog.Info("pool concurrency updated", "pool", p.cfg.Code, "from", old, "to", n)
+	}
+	return true
+}
+
+// resize sets the worker limit to n. Returns the previous limit and
+// whether it changed.
+func (p *Pool) resize(n uint32) (uint32, bool) {
+	old := p.workers.setLimit(n)
+	return old, old != n
+}
+
+// SetAdaptive turns adaptive concurrency on (or retunes it) within cfg's
+// bounds, or off with nil, and resumes it if a manual override paused
+// it. The current concurrency is clamped into the new bounds.
+func (p *Pool) SetAdaptive(cfg *common.AdaptiveConcurrency) {
+	if cfg == nil {
+		p.adaptive.Store(nil)
+		return
+	}
+	a := p.adaptive.Load()
+	if a == nil {
+		a = newAdaptiveController(*cfg)
+		p.adaptive.Store(a)
+	} else {
+		a.mu.Lock()
+		a.cfg = cfg.Normalized()
+		a.paused = false
+		a.mu.Unlock()
+	}
+	cur := p.workers.capacity()
+	p.UpdateConcurrency(max(a.cfg.MinConcurrency, min(a.cfg.MaxConcurrency, cur)))
+}
+
+// PauseAdaptive holds an adaptive pool at its current concurrency — an
+// operator has set it by hand — until SetAdaptive is next called.
+func (p *Pool) PauseAdaptive() {
+	if a := p.adaptive.Load(); a != nil {
+		a.setPaused(true)
+	}
+}
+
+// observeAdaptive feeds one delivery outcome to the adaptive controller
+// and applies any resize it decides on.
+func (p *Pool) observeAdaptive(result common.MediationResult, durationMs uint64) {
+	a := p.adaptive.Load()
+	if a == nil {
+		return
+	}
+	cur := p.workers.capacity()
+	// This worker still counts as active, so a full pool reads as cur.
+	saturated := p.activeWorkers.Load() >= cur || p.queueSize.Load() > 0
+	if next := a.observe(time.Now(), durationMs, isOverload(result), saturated, cur); next != 0 {
+		if old, changed := p.resize(next); changed {
+			slog.Debug("pool concurrency adapted", "pool", p.cfg.Code, "from", old, "to", next)
+		}
+	}
+}
+
+// SetEndpointLimits hot-swaps the per-host and per-subscription in-flight
+// caps. nil or 0 = unlimited.
+func (p *Pool) SetEndpointLimits(perHost, perSubscription *uint32) {
+	p.hostLimit.setLimit(perHost)
+	p.subLimit.setLimit(perSubscription)
+}
+
+// acquireWorker takes m's subscription and host slots, then — once its
+// subscription's turn comes round (see fairQueue; HIGH messages skip the
+// queue) — a worker slot. Returns false, holding nothing, when ctx is
+// done first. Slots are always taken in this order, so two messages
+// can't each hold one the other needs.
+func (p *Pool) acquireWorker(ctx context.Context, m *common.Message) bool {
+	sub, host := m.SubscriptionID, targetHostKey(m)
+	if !p.subLimit.acquire(ctx, sub) {
+		return false
+	}
+	if !p.hostLimit.acquire(ctx, host) {
+		p.subLimit.release(sub)
+		return false
+	}
+	if !m.HighPriority {
+		if !p.fair.acquire(ctx, sub, m.SubscriptionWeight) {
+			p.hostLimit.release(host)
+			p.subLimit.release(sub)
+			return false
+		}
+		defer p.fair.pass()
+	}
+	if !p.workers.acquire(ctx, m.HighPriority) {
+		p.hostLimit.release(host)
+		p.subLimit.release(sub)
+		return false
 	}
-	p.sem.Store(make(chan struct{}, n))
-	p.concurrency.Store(n)
-	slog.Info("pool concurrency updated", "pool", p.cfg.Code, "from", old, "to", n)
 	return true
 }
 
+// releaseWorker returns the slots acquireWorker took.
+func (p *Pool) releaseWorker(m *common.Message) {
+	p.workers.release()
+	p.hostLimit.release(targetHostKey(m))
+	p.subLimit.release(m.SubscriptionID)
+}
+
 // Metrics exposes the pool's metric collector. The HTTP API hits this
 // when building EnhancedPoolMetrics for /monitoring/pool-stats.
 func (p *Pool) Metrics() *PoolMetricsCollector { return p.metrics }
@@ -258,12 +377,12 @@ func (p *Pool) submit(ctx context.Context, m common.QueuedMessage) {
 	}
 	// Capacity backpressure: NACK (delay 10) when the pre-dispatch buffer is
 	// already at capacity = max(concurrency*20, 50).
-	capacity := p.concurrency.Load() * queueCapacityMultiplier
-	if capacity < minQueueCapacity {
-		capacity = minQueueCapacity
+	if !p.hasRoom() {
+		p.reject(ctx, m)
+		return
 	}
-	if p.queueSize.Load() >= capacity {
-		p.nackMsg(ctx, m, ptrU32(10), "pool at capacity")
+	if rem := p.quarantineRemaining(); rem > 0 {
+		p.nackQuarantined(ctx, m, rem)
 		return
 	}
 
@@ -289,14 +408,12 @@ func (p *Pool) submit(ctx context.Context, m common.QueuedMessage) {
 }
 
 // runImmediate dispatches a single IMMEDIATE-mode message concurrently:
-// acquire a pool semaphore slot, then process it. IMMEDIATE messages have no
+// acquire its endpoint slots and a pool worker slot, then process it. IMMEDIATE messages have no
 // group buffer, so a retryable failure re-dispatches the same message after the
 // backoff (one chained goroutine per failing message — sequential, not a leak),
 // keeping it in-pipeline rather than releasing it to the broker.
 func (p *Pool) runImmediate(ctx context.Context, m common.QueuedMessage) {
-	sem := p.loadSem()
-	select {
-	case <-ctx.Done():
+	if !p.acquireWorker(ctx, &m.Message) {
 		// Shutdown before we could start. nackMsg releases the route-time
 		// tracker entry so the broker's redelivery (NACK is a no-op on SQS;
 		// the message reappears after the visibility timeout) re-enters the
@@ -304,12 +421,11 @@ func (p *Pool) runImmediate(ctx context.Context, m common.QueuedMessage) {
 		p.queueSize.Add(^uint32(0))
 		p.nackMsg(ctx, m, ptrU32(10), "shutdown before dispatch")
 		return
-	case sem <- struct{}{}:
 	}
 	p.queueSize.Add(^uint32(0)) // now active, not queued
 	result, retryAfter := func() (processResult, time.Duration) {
-		defer func() { <-sem }() // release on every exit path (acquired above)
-		return p.processOne(ctx, m)
+		defer p.releaseWorker(&m.Message) // release on every exit path (acquired above)
+		return p.processOne(ctx, &m)
 	}()
 	if result != processRetry {
 		return
@@ -367,9 +483,10 @@ func (p *Pool) Stop() {
 	}
 }
 
-// trackMediating records a message as actively inside a worker. Called at the
-// top of processOne, paired with untrackMediating on exit.
-func (p *Pool) trackMediating(qm common.QueuedMessage) {
+// trackMediating records a message as actively inside a worker, with the
+// cancel func that aborts its mediation. Called at the top of processOne,
+// paired with untrackMediating on exit.
+func (p *Pool) trackMediating(qm common.QueuedMessage, cancel context.CancelCauseFunc) {
 	group := ""
 	if qm.Message.MessageGroupID != nil {
 		group = *qm.Message.MessageGroupID
@@ -384,15 +501,31 @@ func (p *Pool) trackMediating(qm common.QueuedMessage) {
 		Attempts:   qm.Attempts,
 		MediatedAt: time.Now(),
 	}
+	p.handles[qm.Message.ID] = &mediationHandle{cancel: cancel}
 	p.mediatingMu.Unlock()
 }
 
 func (p *Pool) untrackMediating(messageID string) {
 	p.mediatingMu.Lock()
 	delete(p.mediating, messageID)
+	delete(p.handles, messageID)
 	p.mediatingMu.Unlock()
 }
 
+// operatorCancelled reports whether mctx was cancelled by Cancel, and the
+// action requested.
+func (p *Pool) operatorCancelled(mctx context.Context, messageID string) (CancelAction, bool) {
+	if !errors.Is(context.Cause(mctx), errOperatorCancel) {
+		return "", false
+	}
+	p.mediatingMu.Lock()
+	defer p.mediatingMu.Unlock()
+	if h, ok := p.handles[messageID]; ok {
+		return h.action, true
+	}
+	return CancelNack, true
+}
+
 // MediatingSnapshot returns the messages currently inside this pool's workers.
 // Never reaped, so a long-running delivery stays listed for its full duration —
 // the reliable answer to "which records are mediating right now" (its length
@@ -419,7 +552,7 @@ func (p *Pool) ActiveWorkers() uint32 { return p.activeWorkers.Load() }
 func (p *Pool) QueueSize() uint32 { return p.queueSize.Load() }
 
 // Concurrency returns the current concurrency cap.
-func (p *Pool) Concurrency() uint32 { return p.concurrency.Load() }
+func (p *Pool) Concurrency() uint32 { return p.workers.capacity() }
 
 // RateLimitPerMinute returns the current rate-limit (or nil if disabled).
 // Mirrors the way Rust's PoolStats reports the field.
@@ -442,36 +575,79 @@ func (p *Pool) MessageGroupCount() uint32 {
 	return uint32(len(p.groupQs))
 }
 
+// LargestGroupDepth returns the buffered depth of the pool's deepest
+// message group. Each group drains through a single worker, so when one
+// group holds most of QueueSize the pool's other workers can't help with
+// it: that skew, not the pool's concurrency, bounds how fast it clears.
+func (p *Pool) LargestGroupDepth() uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var deepest int
+	for _, gq := range p.groupQs {
+		deepest = max(deepest, len(gq.msgs))
+	}
+	return uint32(deepest)
+}
+
 // Stats returns the dashboard-shaped snapshot of this pool.
 func (p *Pool) Stats() PoolStats {
-	concurrency := p.concurrency.Load()
-	capacity := concurrency * queueCapacityMultiplier
-	if capacity < minQueueCapacity {
-		capacity = minQueueCapacity
-	}
 	m := p.metrics.Snapshot()
 	return PoolStats{
 		PoolCode:           p.cfg.Code,
-		Concurrency:        concurrency,
+		Concurrency:        p.workers.capacity(),
 		ActiveWorkers:      p.activeWorkers.Load(),
 		QueueSize:          p.queueSize.Load(),
-		QueueCapacity:      capacity,
+		QueueCapacity:      p.queueCapacity(),
 		MessageGroupCount:  p.MessageGroupCount(),
+		LargestGroupDepth:  p.LargestGroupDepth(),
 		RateLimitPerMinute: p.RateLimitPerMinute(),
 		IsRateLimited:      p.IsRateLimited(),
+		Adaptive:           p.adaptiveStatus(),
+		Quarantine:         p.quarantineStatus(),
 		Metrics:            &m,
 		Histogram:          p.metrics.HistogramSnapshot(),
 	}
 }
 
+func (p *Pool) adaptiveStatus() *AdaptiveStatus {
+	if a := p.adaptive.Load(); a != nil {
+		return a.status()
+	}
+	return nil
+}
+
+func (p *Pool) quarantineStatus() *QuarantineStatus {
+	if q := p.quarantine.Load(); q != nil {
+		return q.status(time.Now())
+	}
+	return nil
+}
+
 // queueCapacityMultiplier and minQueueCapacity mirror the Java/Rust
-// derivation: capacity = max(concurrency * 20, 50). Used by Stats() so
-// the dashboard's "queue capacity" matches the reference implementations.
+// derivation: capacity = max(concurrency * 20, 50). Shared by submit's
+// backpressure and Stats(), so the dashboard's "queue capacity" is the
+// limit actually enforced.
 const (
 	queueCapacityMultiplier uint32 = 20
 	minQueueCapacity        uint32 = 50
 )
 
+// queueCapacity is the pre-dispatch buffer limit, max(concurrency*20, 50).
+func (p *Pool) queueCapacity() uint32 {
+	return max(p.workers.capacity()*queueCapacityMultiplier, minQueueCapacity)
+}
+
+// hasRoom reports whether the pre-dispatch buffer is below capacity.
+func (p *Pool) hasRoom() bool { return p.queueSize.Load() < p.queueCapacity() }
+
+// reject turns a message away because the pool is full: NACK (delay 10)
+// and count it against the pool, so broker churn can be traced to the
+// pool causing it.
+func (p *Pool) reject(ctx context.Context, m common.QueuedMessage) {
+	p.metrics.RecordRejected()
+	p.nackMsg(ctx, m, ptrU32(10), "pool at capacity")
+}
+
 // enqueue appends a newly-arrived message to the BACK of its group's FIFO.
 // Returns false without buffering when the pool has stopped — checked under
 // p.mu so it can't race Stop's buffer flush and strand a message (with a live
@@ -518,7 +694,7 @@ func (p *Pool) enqueueFront(group string, m common.QueuedMessage) bool {
 // none is running. Only ordered-mode messages (NEXT_ON_ERROR /
 // BLOCK_ON_ERROR) reach here — IMMEDIATE-mode messages dispatch
 // concurrently via runImmediate. The drainer processes one message per
-// group at a time to preserve FIFO order, bounded across groups by `sem`.
+// group at a time to preserve FIFO order, bounded across groups by the worker limit.
 func (p *Pool) tryDrainGroup(ctx context.Context, group string) {
 	p.mu.Lock()
 	gq := p.groupQs[group]
@@ -534,11 +710,11 @@ func (p *Pool) tryDrainGroup(ctx context.Context, group string) {
 
 // drainGroup is the per-message-group worker goroutine spawned by
 // tryDrainGroup. Drains one message at a time from gq.msgs (preserving
-// FIFO order within the group), gated by the pool-wide `sem` semaphore.
+// FIFO order within the group), gated by the pool-wide worker limit.
 //
 // Exit conditions:
 //   - the group buffer is empty (the groupQs entry is removed).
-//   - ctx is cancelled while waiting for a semaphore slot or sitting out a
+//   - ctx is cancelled while waiting for a worker slot or sitting out a
 //     retry backoff (the in-hand message is re-fronted and the working flag
 //     cleared so a replacement drainer resumes the group — spawned by the
 //     next submit or by Manager.route's redelivery-dedup kick).
@@ -546,7 +722,7 @@ func (p *Pool) tryDrainGroup(ctx context.Context, group string) {
 //     the broker via nackMsg instead of parked.
 //
 // Note: ctx cancellation between processOne calls does NOT stop the loop
-// — only the semaphore-acquire and backoff selects are ctx-aware. This is
+// — only the worker-acquire and backoff selects are ctx-aware. This is
 // intentional; a cancellation mid-process is handled inside processOne /
 // mediator.
 //
@@ -574,14 +750,11 @@ func (p *Pool) drainGroup(ctx context.Context, group string) {
 		// consistent with what's actually buffered in groupQs.
 		p.queueSize.Add(^uint32(0)) // atomic decrement
 
-		// Acquire a concurrency slot. Snapshot the channel locally so a
-		// resize between acquire and release doesn't cross channels.
-		// Wakeup conditions:
-		//   <-ctx.Done()         — consumer stopping; park the message and exit.
-		//   sem <- struct{}{}    — slot acquired; proceed.
-		sem := p.loadSem()
-		select {
-		case <-ctx.Done():
+		// Acquire the endpoint slots and a concurrency slot (HIGH messages
+		// first, see workerLimiter; subscriptions in turn, see fairQueue).
+		// Fails only when ctx is done: the consumer is stopping; park the
+		// message and exit.
+		if !p.acquireWorker(ctx, &msg.Message) {
 			// Re-front the popped message (preserving FIFO — dropping just the
 			// head while later messages stay buffered would reorder the group)
 			// and clear working so the group resumes under a fresh drainer —
@@ -595,21 +768,20 @@ func (p *Pool) drainGroup(ctx context.Context, group string) {
 			}
 			p.clearWorking(group)
 			return
-		case sem <- struct{}{}:
 		}
 
 		// Release the slot per iteration even if processOne panics past its own
-		// recover — a bare deferred <-sem would accumulate across the loop, so
+		// recover — a bare deferred release would accumulate across the loop, so
 		// scope it to a closure.
 		result, retryAfter := func() (processResult, time.Duration) {
-			defer func() { <-sem }()
-			return p.processOne(ctx, msg)
+			defer p.releaseWorker(&msg.Message)
+			return p.processOne(ctx, &msg)
 		}()
 
 		if result == processRetry {
 			// Preserve FIFO: re-insert the failed message at the FRONT of its
 			// group so it is the next one attempted, then wait out the backoff
-			// before the next attempt (holding no semaphore slot). The single
+			// before the next attempt (holding no worker slot). The single
 			// drainer + front re-insert blocks the whole group on this message
 			// until it succeeds — the intended ordered-delivery (head-of-line)
 			// semantic. The in-flight tracker entry is kept across the retry.
@@ -697,10 +869,14 @@ func retryDelay(attempts uint, outcomeDelaySec int) time.Duration {
 // broker on failure — a retryable outcome keeps the in-flight entry and returns
 // processRetry so the caller retries in-pipeline (preserving order for grouped
 // messages). Only a terminal 2xx/4xx ACKs and clears the entry.
-func (p *Pool) processOne(ctx context.Context, qm common.QueuedMessage) (result processResult, retryAfter time.Duration) {
+func (p *Pool) processOne(ctx context.Context, qm *common.QueuedMessage) (result processResult, retryAfter time.Duration) {
 	p.activeWorkers.Add(1)
 	defer p.activeWorkers.Add(^uint32(0)) // atomic decrement
-	p.trackMediating(qm)
+	// mctx scopes this attempt so an operator can abort it (Cancel) without
+	// touching the consumer context the rest of the pipeline runs on.
+	mctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	p.trackMediating(*qm, cancel)
 	defer p.untrackMediating(qm.Message.ID)
 
 	// Panic isolation: a panic mid-mediation must not crash the process (an
@@ -735,7 +911,7 @@ func (p *Pool) processOne(ctx context.Context, qm common.QueuedMessage) (result
 			// it redeliver forever — and leave the owner's entry alone.
 			slog.Info("external requeue duplicate (process-time backstop); ACKing copy",
 				"message_id", qm.Message.ID, "queue", qm.QueueIdentifier)
-			if c := p.consumerFor(qm); c != nil {
+			if c := p.consumerFor(*qm); c != nil {
 				if err := c.Ack(ctx, qm.ReceiptHandle); err != nil {
 					slog.Warn("ack (requeue duplicate) failed", "message_id", qm.Message.ID, "err", err)
 				}
@@ -744,12 +920,36 @@ func (p *Pool) processOne(ctx context.Context, qm common.QueuedMessage) (result
 		}
 	}
 
+	// The subscription's delivery window closed while the message waited in
+	// the queue or the buffer: don't deliver. ACK it — stale recovery
+	// returns the job to PENDING and the scheduler holds it until the
+	// window reopens.
+	if windowClosed(&qm.Message, time.Now()) {
+		slog.Info("delivery window closed; releasing to scheduler",
+			"message_id", qm.Message.ID, "closed_at", *qm.Message.WindowClosesAt)
+		p.ackTracked(ctx, *qm)
+		return processDone, 0
+	}
+
+	// Quarantined: don't attempt the receiver. Wait in-pipeline (ordered
+	// groups keep their place) and re-check.
+	if rem := p.quarantineRemaining(); rem > 0 {
+		if p.tracker != nil {
+			p.tracker.MarkRetrying(qm.Message.ID, qm.BrokerMessageID)
+		}
+		return processRetry, min(rem, quarantineRecheck)
+	}
+
 	// Rate limit (per-pool token bucket). Record a rate-limited event when the
 	// limiter actually held us back (current tokens exhausted).
 	if p.limiter.IsLimited() {
 		p.metrics.RecordRateLimited()
 	}
-	if err := p.limiter.Wait(ctx); err != nil {
+	if err := p.limiter.Wait(mctx); err != nil {
+		if action, ok := p.operatorCancelled(mctx, qm.Message.ID); ok {
+			p.applyCancel(ctx, *qm, action)
+			return processDone, 0
+		}
 		// Context cancelled mid-wait — keep the entry and retry in-pipeline.
 		if p.tracker != nil {
 			p.tracker.MarkRetrying(qm.Message.ID, qm.BrokerMessageID)
@@ -757,14 +957,38 @@ func (p *Pool) processOne(ctx context.Context, qm common.QueuedMessage) (result
 		return processRetry, retryDelay(qm.Attempts, 5)
 	}
 
+	if qm.Failures > 0 {
+		// A re-dispatch after the receiver failed it: the mediator counts it
+		// against its retry budget. One held back before reaching the
+		// receiver (shed, circuit open, 429) isn't a retry of a request.
+		mctx = withRetryAttempt(mctx)
+	}
 	start := time.Now()
-	outcome := p.mediator.Mediate(ctx, &qm.Message)
+	outcome := p.mediator.Mediate(mctx, &qm.Message)
+	if isDeliveryFailure(outcome.Result) {
+		qm.Failures++
+	}
 	durationMs := uint64(time.Since(start).Milliseconds())
+	p.metrics.RecordPushback(outcome.StatusCode)
+	if outcome.Result != common.MediationCircuitOpen && outcome.Result != common.MediationShed {
+		p.observeAdaptive(outcome.Result, durationMs)
+	}
+	p.observeQuarantine(outcome.Result)
+
+	// An aborted delivery that nonetheless completed (the response beat the
+	// cancel) resolves normally; only what would have been retried takes
+	// the operator's action.
+	if outcome.Result != common.MediationSuccess && outcome.Result != common.MediationErrorConfig {
+		if action, ok := p.operatorCancelled(mctx, qm.Message.ID); ok {
+			p.applyCancel(ctx, *qm, action)
+			return processDone, 0
+		}
+	}
 
 	switch outcome.Result {
 	case common.MediationSuccess:
 		p.metrics.RecordSuccess(durationMs)
-		p.ackTracked(ctx, qm)
+		p.ackTracked(ctx, *qm)
 		return processDone, 0
 
 	case common.MediationErrorConfig:
@@ -772,40 +996,60 @@ func (p *Pool) processOne(ctx context.Context, qm common.QueuedMessage) (result
 		// 4xx — ACK to avoid an infinite client-error retry loop. Do NOT trip
 		// the breaker. Counted against total_failure (a non-success terminal).
 		p.metrics.RecordFailure(durationMs)
-		p.ackTracked(ctx, qm)
+		p.ackTracked(ctx, *qm)
 		return processDone, 0
 
 	case common.MediationErrorProcess:
 		// Transient (5xx/timeout): retry in-pipeline. Don't penalise the
 		// all-time failure counter.
 		p.metrics.RecordTransient(durationMs)
-		return p.retry(qm, outcome.DelaySeconds)
+		return p.retry(ctx, *qm, outcome.DelaySeconds)
 
 	case common.MediationErrorConnection:
 		p.metrics.RecordFailure(durationMs)
-		return p.retry(qm, outcome.DelaySeconds)
+		return p.retry(ctx, *qm, outcome.DelaySeconds)
 
 	case common.MediationRateLimited:
 		// 429 — retry in-pipeline honouring Retry-After; NOT a breaker failure.
 		p.metrics.RecordRateLimited()
-		return p.retry(qm, outcome.DelaySeconds)
+		return p.retry(ctx, *qm, outcome.DelaySeconds)
 
 	case common.MediationCircuitOpen:
 		// Breaker open (decided by the mediator): no delivery was attempted.
 		// Retry in-pipeline once the breaker reset timeout (carried in the
 		// outcome) elapses.
-		return p.retry(qm, outcome.DelaySeconds)
+		return p.retry(ctx, *qm, outcome.DelaySeconds)
+
+	case common.MediationShed:
+		// Turned away by the mediator's in-flight ceiling or retry budget:
+		// no delivery was attempted. Retry in-pipeline after the delay.
+		return p.retry(ctx, *qm, outcome.DelaySeconds)
 	}
 	return processDone, 0
 }
 
 // retry marks the in-flight entry as retrying (so the stall detector / reaper
 // skip it) and returns the processRetry verdict with the computed backoff.
-func (p *Pool) retry(qm common.QueuedMessage, outcomeDelaySec int) (processResult, time.Duration) {
+// A retry that would land after the message's delivery window closes is
+// not held for: the message is ACKed and left to the scheduler, as in
+// processOne.
+func (p *Pool) retry(ctx context.Context, qm common.QueuedMessage, outcomeDelaySec int) (processResult, time.Duration) {
+	delay := retryDelay(qm.Attempts, outcomeDelaySec)
+	if windowClosed(&qm.Message, time.Now().Add(delay)) {
+		slog.Info("delivery window closes before retry; releasing to scheduler",
+			"message_id", qm.Message.ID, "closed_at", *qm.Message.WindowClosesAt)
+		p.ackTracked(ctx, qm)
+		return processDone, 0
+	}
 	if p.tracker != nil {
 		p.tracker.MarkRetrying(qm.Message.ID, qm.BrokerMessageID)
 	}
-	return processRetry, retryDelay(qm.Attempts, outcomeDelaySec)
+	return processRetry, delay
+}
+
+// windowClosed reports whether m's delivery window has closed by t.
+func windowClosed(m *common.Message, t time.Time) bool {
+	return m.WindowClosesAt != nil && !t.Before(*m.WindowClosesAt)
 }
 
 func ptrU32(v uint32) *uint32 { return &v }
@@ -237,8 +237,7 @@ func TestPoolOrderedGroupRecoversAfterCancelDuringSemWait(t *testing.T) {
 	pool := newCascadePool(med, func(string) queue.Consumer { return cons })
 
 	// Occupy the single concurrency slot so the drainer parks on the acquire.
-	sem := pool.loadSem()
-	sem <- struct{}{}
+	pool.workers.acquire(context.Background(), false)
 
 	ctx1, cancel1 := context.WithCancel(context.Background())
 	pool.submit(ctx1, mkOrdered("m1", &group))
@@ -254,7 +253,7 @@ func TestPoolOrderedGroupRecoversAfterCancelDuringSemWait(t *testing.T) {
 		"cancelled drainer must leave the group idle with m1 re-fronted")
 
 	// Free the slot, then submit from the "restarted consumer".
-	<-sem
+	pool.workers.release()
 	pool.submit(context.Background(), mkOrdered("m2", &group))
 
 	select {
@@ -1,11 +1,16 @@
 package router
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
 )
 
 // A message group is a strict FIFO: messages drain in arrival order regardless
@@ -54,3 +59,68 @@ func TestPoolEnqueueAppendsToBackEnqueueFrontPrepends(t *testing.T) {
 	}
 	assert.Equal(t, []string{"retry", "m1", "m2"}, got, "enqueue → back, enqueueFront → head")
 }
+
+// groupTrackingMediator records, per message group, the order deliveries
+// started in and the most that ever ran at once.
+type groupTrackingMediator struct {
+	mu      sync.Mutex
+	running map[string]int
+	peak    map[string]int
+	order   map[string][]string
+}
+
+func (m *groupTrackingMediator) Mediate(_ context.Context, msg *common.Message) common.MediationOutcome {
+	g := *msg.MessageGroupID
+	m.mu.Lock()
+	m.running[g]++
+	m.peak[g] = max(m.peak[g], m.running[g])
+	m.order[g] = append(m.order[g], msg.ID)
+	m.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	m.mu.Lock()
+	m.running[g]--
+	m.mu.Unlock()
+	return common.Success()
+}
+
+// A pool with spare workers still runs a group's messages one at a time,
+// in arrival order, while other groups use the rest of the workers.
+func TestPool_GroupNeverRunsConcurrentlyOrOutOfOrder(t *testing.T) {
+	med := &groupTrackingMediator{running: map[string]int{}, peak: map[string]int{}, order: map[string][]string{}}
+	c := &grConsumer{id: "q1"}
+	p := NewPool(common.PoolConfig{Code: "TEST", Concurrency: 8},
+		med, NewInFlightTracker(), func(string) queue.Consumer { return c })
+	ctx := context.Background()
+
+	groups := []string{"g1", "g2", "g3"}
+	want := map[string][]string{}
+	for i := range 10 {
+		for _, g := range groups {
+			id := fmt.Sprintf("%s_%d", g, i)
+			m := grMsg(id, "http://receiver.test/hook")
+			m.Message.DispatchMode = common.DispatchBlockOnError
+			m.Message.MessageGroupID = &g
+			p.submit(ctx, m)
+			want[g] = append(want[g], id)
+		}
+	}
+	grWaitFor(t, func() bool { return c.acks.Load() == 30 }, 5*time.Second)
+
+	med.mu.Lock()
+	defer med.mu.Unlock()
+	for _, g := range groups {
+		assert.Equal(t, 1, med.peak[g], "group %s ran concurrently", g)
+		assert.Equal(t, want[g], med.order[g], "group %s ran out of order", g)
+	}
+}
+
+func TestPool_LargestGroupDepth(t *testing.T) {
+	p := &Pool{groupQs: map[string]*groupQueue{}}
+	assert.Zero(t, p.LargestGroupDepth())
+	for i := range 3 {
+		p.enqueue("hot", common.QueuedMessage{Message: common.Message{ID: fmt.Sprintf("h%d", i)}})
+	}
+	p.enqueue("cold", common.QueuedMessage{Message: common.Message{ID: "c0"}})
+	assert.Equal(t, uint32(3), p.LargestGroupDepth())
+	assert.Equal(t, uint32(2), p.MessageGroupCount())
+}
@@ -0,0 +1,180 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// quarantineRecheck caps how long a message already in the pipeline
+// waits before re-checking a quarantined pool, so a manual unquarantine
+// takes effect promptly.
+const quarantineRecheck = 15 * time.Second
+
+// quarantineController pauses a pool whose deliveries keep failing. Each
+// delivery outcome is observed into a tumbling window; as soon as the
+// window holds MinDeliveries with a failure share of at least
+// FailureRate the pool is quarantined for CooldownSeconds and the window
+// restarts. A quarantined pool dispatches nothing: new messages go back
+// to the broker, and buffered or retrying ones wait, so retries aren't
+// burned against a receiver that is down. Only delivery failures count —
+// 5xx, timeouts and connection errors; 4xx (reachable), 429 (alive, just
+// busy) and an open breaker (nothing attempted) don't.
+type quarantineController struct {
+	cfg common.PoolQuarantine
+
+	mu          sync.Mutex
+	windowStart time.Time
+	samples     uint32
+	failures    uint32
+	since       time.Time
+	until       time.Time
+	reason      string
+	trips       uint64
+}
+
+func newQuarantineController(cfg common.PoolQuarantine) *quarantineController {
+	return &quarantineController{cfg: cfg.Normalized(), windowStart: time.Now()}
+}
+
+// observe records one delivery and reports whether it tripped the
+// quarantine.
+func (q *quarantineController) observe(now time.Time, failure bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if now.Before(q.until) {
+		// Stragglers that were already mediating when the pool tripped.
+		return false
+	}
+	if now.Sub(q.windowStart) >= time.Duration(q.cfg.WindowSeconds)*time.Second {
+		q.windowStart, q.samples, q.failures = now, 0, 0
+	}
+	q.samples++
+	if failure {
+		q.failures++
+	}
+	if q.samples < q.cfg.MinDeliveries || float64(q.failures)/float64(q.samples) < q.cfg.FailureRate {
+		return false
+	}
+	q.reason = fmt.Sprintf("%d of %d deliveries failed within %ds", q.failures, q.samples, q.cfg.WindowSeconds)
+	q.since = now
+	q.until = now.Add(time.Duration(q.cfg.CooldownSeconds) * time.Second)
+	q.trips++
+	q.windowStart, q.samples, q.failures = now, 0, 0
+	return true
+}
+
+// remaining is how much of the cool-down is left at now (0 = not
+// quarantined).
+func (q *quarantineController) remaining(now time.Time) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if now.Before(q.until) {
+		return q.until.Sub(now)
+	}
+	return 0
+}
+
+// lift ends a quarantine early and starts a fresh window. Reports whether
+// the pool was quarantined.
+func (q *quarantineController) lift(now time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	was := now.Before(q.until)
+	q.until = time.Time{}
+	q.windowStart, q.samples, q.failures = now, 0, 0
+	return was
+}
+
+func (q *quarantineController) status(now time.Time) *QuarantineStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	st := &QuarantineStatus{
+		FailureRate:     q.cfg.FailureRate,
+		MinDeliveries:   q.cfg.MinDeliveries,
+		WindowSeconds:   q.cfg.WindowSeconds,
+		CooldownSeconds: q.cfg.CooldownSeconds,
+		Trips:           q.trips,
+	}
+	if now.Before(q.until) {
+		st.Quarantined = true
+		since, until := q.since, q.until
+		st.Since, st.Until, st.Reason = &since, &until, q.reason
+	}
+	return st
+}
+
+// isDeliveryFailure reports whether an outcome counts against the
+// receiver for quarantine.
+func isDeliveryFailure(r common.MediationResult) bool {
+	return r == common.MediationErrorProcess || r == common.MediationErrorConnection
+}
+
+// SetQuarantine turns auto-quarantine on (or retunes it), or off with
+// nil — which also lifts an active quarantine.
+func (p *Pool) SetQuarantine(cfg *common.PoolQuarantine) {
+	if cfg == nil {
+		p.quarantine.Store(nil)
+		return
+	}
+	if q := p.quarantine.Load(); q != nil {
+		q.mu.Lock()
+		q.cfg = cfg.Normalized()
+		q.mu.Unlock()
+		return
+	}
+	p.quarantine.Store(newQuarantineController(*cfg))
+}
+
+// Unquarantine lifts an active quarantine immediately. Reports whether
+// the pool was quarantined.
+func (p *Pool) Unquarantine() bool {
+	q := p.quarantine.Load()
+	if q == nil || !q.lift(time.Now()) {
+		return false
+	}
+	slog.Info("pool quarantine lifted", "pool", p.cfg.Code)
+	return true
+}
+
+// quarantineRemaining is the cool-down left on the pool (0 = dispatching).
+func (p *Pool) quarantineRemaining() time.Duration {
+	if q := p.quarantine.Load(); q != nil {
+		return q.remaining(time.Now())
+	}
+	return 0
+}
+
+// Quarantined reports whether the pool is currently paused.
+func (p *Pool) Quarantined() bool { return p.quarantineRemaining() > 0 }
+
+// observeQuarantine feeds one delivery outcome to the quarantine
+// controller and raises the warning when it trips.
+func (p *Pool) observeQuarantine(result common.MediationResult) {
+	q := p.quarantine.Load()
+	if q == nil || result == common.MediationCircuitOpen || result == common.MediationRateLimited ||
+		result == common.MediationShed {
+		return
+	}
+	if !q.observe(time.Now(), isDeliveryFailure(result)) {
+		return
+	}
+	st := q.status(time.Now())
+	slog.Warn("pool quarantined after repeated delivery failures",
+		"pool", p.cfg.Code, "reason", st.Reason, "cooldown_seconds", st.CooldownSeconds)
+	if w := p.warnings.Load(); w != nil {
+		w.Add(WarningCategoryQuarantine, WarningError,
+			fmt.Sprintf("pool %s quarantined for %ds: %s", p.cfg.Code, st.CooldownSeconds, st.Reason), "Pool:"+p.cfg.Code)
+	}
+}
+
+// nackQuarantined returns a message to the broker for redelivery once
+// the cool-down is over.
+func (p *Pool) nackQuarantined(ctx context.Context, m common.QueuedMessage, remaining time.Duration) {
+	delay := uint32(remaining.Round(time.Second) / time.Second)
+	p.nackMsg(ctx, m, &delay, "pool quarantined")
+}
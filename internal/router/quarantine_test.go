@@ -0,0 +1,76 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+func TestQuarantine_TripsOnFailureRate(t *testing.T) {
+	q := newQuarantineController(common.PoolQuarantine{FailureRate: 0.5, MinDeliveries: 4, WindowSeconds: 60, CooldownSeconds: 120})
+	now := q.windowStart
+
+	assert.False(t, q.observe(now, true))
+	assert.False(t, q.observe(now, true))
+	assert.False(t, q.observe(now, true), "too few deliveries to judge")
+	assert.True(t, q.observe(now, false), "3 of 4 failed")
+	assert.Equal(t, 120*time.Second, q.remaining(now))
+	assert.False(t, q.observe(now.Add(time.Second), true), "stragglers during the cool-down don't re-trip")
+
+	after := now.Add(121 * time.Second)
+	assert.Zero(t, q.remaining(after), "the cool-down ends on its own")
+	for range 4 {
+		assert.False(t, q.observe(after, false), "a healthy window doesn't trip")
+	}
+
+	// A window that closes below the minimum starts over.
+	later := after.Add(time.Minute)
+	for range 3 {
+		q.observe(later, true)
+	}
+	assert.False(t, q.observe(later.Add(time.Minute), true), "failures from a closed window don't carry over")
+
+	st := q.status(now)
+	assert.True(t, st.Quarantined)
+	assert.Equal(t, uint64(1), st.Trips)
+	assert.Equal(t, "3 of 4 deliveries failed within 60s", st.Reason)
+}
+
+func TestQuarantine_PoolPausesAndUnquarantines(t *testing.T) {
+	c := &grConsumer{id: "q1"}
+	med := &grMediator{outcome: common.MediationOutcome{Result: common.MediationErrorConnection}}
+	p := grPool(med, c)
+	ws := NewWarningService(DefaultWarningServiceConfig())
+	p.SetWarnings(ws)
+	p.SetQuarantine(&common.PoolQuarantine{MinDeliveries: 2, CooldownSeconds: 60})
+
+	ctx := context.Background()
+	for _, id := range []string{"m1", "m2"} {
+		m := grMsg(id, "http://t/down")
+		res, _ := p.processOne(ctx, &m)
+		require.Equal(t, processRetry, res)
+	}
+	require.True(t, p.Quarantined(), "two connection failures trip the pool")
+	assert.Len(t, ws.ByCategory(WarningCategoryQuarantine), 1)
+	assert.True(t, p.Stats().Quarantine.Quarantined)
+
+	med.called.Store(false)
+	m := grMsg("m3", "http://t/down")
+	res, wait := p.processOne(ctx, &m)
+	assert.Equal(t, processRetry, res)
+	assert.Equal(t, quarantineRecheck, wait, "buffered work re-checks the quarantine")
+	assert.False(t, med.called.Load(), "a quarantined pool doesn't call the receiver")
+
+	p.submit(ctx, grMsg("m4", "http://t/down"))
+	assert.Equal(t, int64(1), c.nacks.Load(), "new messages go back to the broker")
+	assert.Equal(t, uint32(60), *c.lastNackDelay.Load(), "redelivered once the cool-down is over")
+
+	assert.True(t, p.Unquarantine())
+	assert.False(t, p.Quarantined())
+	assert.False(t, p.Unquarantine(), "nothing left to lift")
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"time"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/standby"
 )
 
@@ -18,6 +20,11 @@ type ServerConfig struct {
 	// DevMode swaps in the dev mediator (relaxed TLS, longer timeouts).
 	DevMode bool
 
+	// Mediator overrides the mediator defaults DevMode selects: request
+	// and connect timeouts, keep-alive pool size, HTTP version, and
+	// outbound TLS (custom CAs, mTLS client certificate).
+	Mediator MediatorOverrides
+
 	// ConfigURL is the FLOWCATALYST_CONFIG_URL the router polls for
 	// pool definitions. Empty disables config sync — no pools will run.
 	ConfigURL string
@@ -29,6 +36,60 @@ type ServerConfig struct {
 	// NotifyWebhookURL receives stall + backlog warnings. Empty → log-only.
 	NotifyWebhookURL string
 
+	// AlertWebhookURL and AlertSlackWebhookURL receive CRITICAL warnings
+	// only, flushed immediately: a generic JSON webhook ({"warnings":
+	// [...]}) and a Slack incoming webhook. Empty disables each.
+	AlertWebhookURL      string
+	AlertSlackWebhookURL string
+	// AlertMail, when set, also emails CRITICAL warnings (one message per
+	// batch). Nil disables email alerts.
+	AlertMail AlertMailFunc
+
+	// WarningStoreMongoURI enables the persistent warning history
+	// (MongoWarningStore) in WarningStoreMongoDB. WarningRetention is how
+	// long history is kept; zero falls back to 30 days.
+	WarningStoreMongoURI string
+	WarningStoreMongoDB  string
+	WarningRetention     time.Duration
+
+	// PoisonStoreMongoURI keeps quarantined poison messages (messages a
+	// consumer couldn't decode) in MongoPoisonStore in PoisonStoreMongoDB
+	// instead of memory. PoisonRetention expires entries that stop
+	// recurring; zero falls back to 14 days.
+	PoisonStoreMongoURI string
+	PoisonStoreMongoDB  string
+	PoisonRetention     time.Duration
+
+	// CaptureSampleRate records that share (0..1] of the mediator's
+	// outbound requests and their responses, secrets redacted, for
+	// /monitoring/captures. Zero disables capture. CaptureMongoURI keeps
+	// them in MongoCaptureStore — a capped collection of CaptureSizeBytes
+	// (zero: DefaultCaptureCollectionSize) in CaptureMongoDB — instead of
+	// memory.
+	CaptureSampleRate float64
+	CaptureMongoURI   string
+	CaptureMongoDB    string
+	CaptureSizeBytes  int64
+
+	// PipelineMongoURI enables pipeline persistence (MongoPipelineStore in
+	// PipelineMongoDB): the in-flight tracker is written behind so a
+	// restarted router reconciles what it held (see PipelinePersister).
+	// PipelineInstanceID scopes the entries and must survive restarts;
+	// empty falls back to the hostname.
+	PipelineMongoURI   string
+	PipelineMongoDB    string
+	PipelineInstanceID string
+
+	// SLOs are the per-pool delivery-latency objectives the SLO monitor
+	// evaluates. Empty disables the monitor.
+	SLOs []SLO
+
+	// BrokerStatsInterval is how often queue depth is fetched from the
+	// broker (SQS ApproximateNumberOfMessages / NotVisible, JetStream
+	// NumPending / NumAckPending) for /monitoring/queue-stats and the
+	// fc_queue_* gauges. Zero falls back to 60s.
+	BrokerStatsInterval time.Duration
+
 	// DrainTimeout is the upper bound for graceful drain on shutdown.
 	// Zero falls back to 60s.
 	DrainTimeout time.Duration
@@ -46,11 +107,37 @@ type ServerConfig struct {
 	// each 5m tick.
 	BreakerIdleMaxAge time.Duration
 
-	// Standby (Redis leader election). When enabled the pool config
+	// Standby (leader election). When enabled the pool config
 	// watcher only runs while this instance holds the lock.
+	// StandbyBackend is "redis" (default) or "mongo".
 	StandbyEnabled  bool
+	StandbyBackend  string
 	StandbyRedisURL string
+	StandbyMongoURI string
+	StandbyMongoDB  string
 	StandbyLockKey  string
+	// Region gates the election on the multi-region handover record
+	// (standby.MongoRegionStore on StandbyMongoURI): a router outside the
+	// active region stays warm but never starts its pools.
+	// InitialActiveRegion seeds the record on first use.
+	Region              string
+	InitialActiveRegion string
+
+	// Sharding replaces leader election for the router: every instance
+	// consumes, and ordered message groups are partitioned between them
+	// through the Mongo shard store (see ShardCoordinator). Standby is
+	// ignored while it is on. Shards is the partition count (same on
+	// every instance); ShardLeaseTTL bounds how long a dead instance's
+	// shards stay unowned. Zero values fall back to 64 and 30s.
+	ShardingEnabled bool
+	Shards          int
+	ShardMongoURI   string
+	ShardMongoDB    string
+	ShardLeaseTTL   time.Duration
+
+	// Mongo holds the pool and timeout options for every Mongo client
+	// above (warning history, shard store, Mongo-backed election).
+	Mongo mongoconn.Config
 
 	// Traffic management. When enabled, this instance is
 	// registered/deregistered with the ALB target group as it
@@ -77,13 +164,30 @@ type Server struct {
 	BrokerStats  *CachedBrokerStats
 	ConfigSource *ConfigSource
 	Traffic      *TrafficStrategy
-
-	election *standby.Election
+	// Shards is the shard coordinator in sharded mode; nil otherwise.
+	Shards *ShardCoordinator
+	// Poison quarantines messages the consumers couldn't decode.
+	Poison *PoisonQuarantine
+	// Capture samples outbound mediation traffic; nil when
+	// CaptureSampleRate is zero.
+	Capture *TrafficCapture
+	// Alerts are the CRITICAL-only sinks from AlertWebhookURL,
+	// AlertSlackWebhookURL and AlertMail; started and stopped with the
+	// Notifier.
+	Alerts []*Notifier
+
+	election     *standby.Election
+	warningStore WarningStore
+	poisonStore  PoisonStore
+	pipeline     *PipelinePersister
+	pipeStore    PipelineStore
+	pollInterval *liveInterval
 }
 
 // NewServer assembles the long-lived components. Nothing starts running
-// until Run is called. Returns an error only when standby is enabled
-// but the Redis client cannot be constructed.
+// until Run is called. Returns an error when the mediator's TLS files
+// cannot be loaded, or when standby is enabled but the leader-election
+// backend cannot be constructed.
 func NewServer(cfg ServerConfig) (*Server, error) {
 	if cfg.DrainTimeout == 0 {
 		cfg.DrainTimeout = 60 * time.Second
@@ -97,14 +201,29 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	if cfg.BreakerIdleMaxAge == 0 {
 		cfg.BreakerIdleMaxAge = time.Hour
 	}
+	if cfg.WarningRetention == 0 {
+		cfg.WarningRetention = 30 * 24 * time.Hour
+	}
+	if cfg.PoisonRetention == 0 {
+		cfg.PoisonRetention = 14 * 24 * time.Hour
+	}
+	if cfg.PipelineMongoURI != "" && cfg.PipelineInstanceID == "" {
+		cfg.PipelineInstanceID, _ = os.Hostname()
+	}
 
+	mcfg, err := BuildMediatorConfig(cfg.DevMode, cfg.Mediator)
+	if err != nil {
+		return nil, fmt.Errorf("mediator config: %w", err)
+	}
 	breakers := NewBreakerRegistry(DefaultBreakerConfig())
 	s := &Server{
 		Cfg:      cfg,
 		Notifier: NewNotifier(cfg.NotifyWebhookURL, 20, 10*time.Second),
-		Mediator: pickMediator(cfg.DevMode, breakers),
+		Mediator: NewHTTPMediator(mcfg, breakers),
 		Breakers: breakers,
 		Tracker:  NewInFlightTracker(),
+
+		pollInterval: newLiveInterval(cfg.ConfigPollInterval),
 	}
 	s.Manager = NewManager(s.Mediator, s.Tracker)
 	s.BrokerStats = NewCachedBrokerStats(s.Manager)
@@ -118,6 +237,63 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	// Notifier so the webhook path stays consistent.
 	s.Warnings = NewWarningService(DefaultWarningServiceConfig())
 	s.Warnings.SetNotifier(s.Notifier)
+	if cfg.AlertWebhookURL != "" {
+		s.Alerts = append(s.Alerts, NewNotifier(cfg.AlertWebhookURL, 20, 10*time.Second))
+	}
+	if cfg.AlertSlackWebhookURL != "" {
+		s.Alerts = append(s.Alerts, NewSlackNotifier(cfg.AlertSlackWebhookURL, 20, 10*time.Second))
+	}
+	if cfg.AlertMail != nil {
+		s.Alerts = append(s.Alerts, NewMailNotifier(cfg.AlertMail, 20, 10*time.Second))
+	}
+	for _, a := range s.Alerts {
+		a.SetMinSeverity(WarningCritical)
+		s.Warnings.AddNotifier(a)
+	}
+	if cfg.WarningStoreMongoURI != "" {
+		// History is an audit aid, not a delivery dependency: an
+		// unreachable store leaves the router running on memory alone.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		store, err := NewMongoWarningStore(ctx, cfg.WarningStoreMongoURI, cfg.WarningStoreMongoDB, cfg.WarningRetention, cfg.Mongo)
+		cancel()
+		if err != nil {
+			slog.Error("warning store unavailable; warnings are kept in memory only", "err", err)
+		} else {
+			s.Warnings.SetStore(store)
+			s.warningStore = store
+		}
+	}
+	s.poisonStore = NewMemoryPoisonStore()
+	if cfg.PoisonStoreMongoURI != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		store, err := NewMongoPoisonStore(ctx, cfg.PoisonStoreMongoURI, cfg.PoisonStoreMongoDB, cfg.PoisonRetention, cfg.Mongo)
+		cancel()
+		if err != nil {
+			slog.Error("poison store unavailable; poison messages are kept in memory only", "err", err)
+		} else {
+			s.poisonStore = store
+		}
+	}
+	s.Poison = NewPoisonQuarantine(s.poisonStore, s.Warnings)
+	s.Manager.SetPoison(s.Poison)
+	if cfg.CaptureSampleRate > 0 {
+		var store CaptureStore = NewMemoryCaptureStore()
+		if cfg.CaptureMongoURI != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			ms, err := NewMongoCaptureStore(ctx, cfg.CaptureMongoURI, cfg.CaptureMongoDB, cfg.CaptureSizeBytes, cfg.Mongo)
+			cancel()
+			if err != nil {
+				slog.Error("capture store unavailable; captures are kept in memory only", "err", err)
+			} else {
+				store = ms
+			}
+		}
+		s.Capture = NewTrafficCapture(store, min(cfg.CaptureSampleRate, 1))
+		if hm, ok := s.Mediator.(*HTTPMediator); ok {
+			hm.SetCapture(s.Capture)
+		}
+		slog.Info("mediator traffic capture enabled", "sample_rate", s.Capture.Rate())
+	}
 	// Surface mediator config-error warnings (400/401/403/404, 501→Critical) on
 	// /warnings and into health. Opt-in setter avoids a constructor dependency.
 	if hm, ok := s.Mediator.(*HTTPMediator); ok {
@@ -132,8 +308,47 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	s.Lifecycle.SetConsumerRestarter(s.Manager)
 	s.Lifecycle.SetPoolStatsProvider(s.Manager)
 
-	if cfg.StandbyEnabled {
+	if cfg.PipelineMongoURI != "" {
+		// Like the warning history, persistence is a recovery aid: without
+		// it a restart falls back to broker redelivery.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		store, err := NewMongoPipelineStore(ctx, cfg.PipelineMongoURI, cfg.PipelineMongoDB, cfg.Mongo)
+		cancel()
+		switch {
+		case err != nil:
+			slog.Error("pipeline store unavailable; in-flight state is kept in memory only", "err", err)
+		case cfg.PipelineInstanceID == "":
+			_ = store.Close()
+			slog.Error("pipeline persistence needs an instance ID; in-flight state is kept in memory only")
+		default:
+			s.pipeStore = store
+			s.pipeline = NewPipelinePersister(PipelineConfig{
+				InstanceID: cfg.PipelineInstanceID,
+				Extend:     mcfg.Timeout,
+			}, store, s.Tracker, s.Manager, s.Warnings)
+		}
+	}
+
+	if cfg.ShardingEnabled {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		store, err := NewMongoShardStore(ctx, cfg.ShardMongoURI, cfg.ShardMongoDB, cfg.Mongo)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("router sharding: %w", err)
+		}
+		s.Shards = NewShardCoordinator(ShardConfig{Shards: cfg.Shards, LeaseTTL: cfg.ShardLeaseTTL}, store, s.Tracker)
+		s.Manager.SetShards(s.Shards)
+		if cfg.StandbyEnabled {
+			slog.Info("router sharding enabled; leader election not used for the router")
+		}
+	} else if cfg.StandbyEnabled {
 		ecfg := common.NewLeaderElectionConfig(cfg.StandbyRedisURL)
+		ecfg.Backend = cfg.StandbyBackend
+		ecfg.MongoURI = cfg.StandbyMongoURI
+		ecfg.MongoDatabase = cfg.StandbyMongoDB
+		ecfg.Mongo = cfg.Mongo
+		ecfg.Region = cfg.Region
+		ecfg.InitialActiveRegion = cfg.InitialActiveRegion
 		if cfg.StandbyLockKey != "" {
 			ecfg.LockKey = cfg.StandbyLockKey
 		}
@@ -162,6 +377,9 @@ func (s *Server) Reload(ctx context.Context) error {
 	if s.ConfigSource == nil {
 		return errors.New("router has no config source configured")
 	}
+	if s.Manager.HasPoolOverrides() {
+		s.ConfigSource.Invalidate()
+	}
 	cfg, err := s.ConfigSource.Fetch(ctx)
 	if err != nil {
 		if errors.Is(err, ErrUnchanged) {
@@ -181,16 +399,77 @@ func (s *Server) IsLeader() bool {
 	return s.election.IsLeader()
 }
 
+// Tunables are the settings a running Server can change without a
+// restart. Zero fields fall back to the defaults NewServer would use.
+type Tunables struct {
+	// MediatorTimeout is the per-delivery request deadline.
+	MediatorTimeout time.Duration
+	// MaxRetryAfter caps a receiver's Retry-After.
+	MaxRetryAfter time.Duration
+	// ConfigPollInterval is how often ConfigURL is re-fetched.
+	ConfigPollInterval time.Duration
+}
+
+// Retune applies t to the running server: new deliveries use the new
+// mediator timeouts, and the config watcher switches to the new poll
+// interval at once.
+func (s *Server) Retune(t Tunables) {
+	if t.MediatorTimeout == 0 {
+		t.MediatorTimeout = DefaultMediatorConfig().Timeout
+		if s.Cfg.DevMode {
+			t.MediatorTimeout = DevMediatorConfig().Timeout
+		}
+	}
+	if t.ConfigPollInterval == 0 {
+		t.ConfigPollInterval = 300 * time.Second
+	}
+	if hm, ok := s.Mediator.(*HTTPMediator); ok {
+		hm.SetTimeouts(t.MediatorTimeout, t.MaxRetryAfter)
+	}
+	s.pollInterval.set(t.ConfigPollInterval)
+}
+
 // Run starts every subsystem and blocks until ctx is cancelled. On
 // cancellation it performs a graceful drain (up to DrainTimeout) and
 // then a full Manager + Notifier + Election shutdown.
 func (s *Server) Run(ctx context.Context) error {
+	if n, err := s.Warnings.Restore(ctx); err != nil {
+		slog.Warn("warning store: restore failed", "err", err)
+	} else if n > 0 {
+		slog.Info("warning store: restored unresolved warnings", "count", n)
+	}
+	persistDone := make(chan struct{})
+	go func() {
+		defer close(persistDone)
+		s.Warnings.RunPersist(ctx)
+	}()
+	pipelineDone := make(chan struct{})
+	if s.pipeline != nil {
+		if err := s.pipeline.Recover(ctx); err != nil {
+			slog.Warn("pipeline: recovery failed; relying on broker redelivery", "err", err)
+		}
+		go func() {
+			defer close(pipelineDone)
+			s.pipeline.Run(ctx)
+		}()
+	} else {
+		close(pipelineDone)
+	}
 	go s.Notifier.Run(ctx)
+	for _, a := range s.Alerts {
+		go a.Run(ctx)
+	}
+	if len(s.Cfg.SLOs) > 0 {
+		go NewSLOMonitor(DefaultSLOMonitorConfig(), s.Cfg.SLOs, s.Manager, s.Warnings).Run(ctx)
+	}
 	go NewStallDetector(DefaultStallConfig(), s.Tracker, s.Notifier, s.Manager.NackInFlight).Watch(ctx)
 	go NewQueueHealthMonitor(DefaultQueueHealthConfig(), s.Notifier).Watch(ctx, s.Manager.Consumers)
 	go s.reapInFlight(ctx)
-	SpawnBrokerStatsRefresh(ctx, s.BrokerStats)
+	SpawnBrokerStatsRefresh(ctx, s.BrokerStats, s.Cfg.BrokerStatsInterval)
 	s.Lifecycle.Start(ctx)
+	if s.Shards != nil {
+		go s.Shards.Run(ctx)
+	}
 
 	startPools := func(c context.Context) {
 		if s.ConfigSource == nil {
@@ -207,7 +486,7 @@ func (s *Server) Run(ctx context.Context) error {
 			slog.Warn("router config URL not set; no pools will start")
 			return
 		}
-		go Watch(c, s.ConfigSource, s.Manager, s.Cfg.ConfigPollInterval)
+		go watch(c, s.ConfigSource, s.Manager, s.pollInterval)
 	}
 
 	if s.election != nil {
@@ -238,6 +517,13 @@ func (s *Server) Run(ctx context.Context) error {
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
+	<-pipelineDone
+	if s.pipeline != nil {
+		// Whatever the drain left behind is what the next start reconciles.
+		if err := s.pipeline.Flush(shutdownCtx); err != nil {
+			slog.Warn("pipeline: final write failed", "err", err)
+		}
+	}
 	// Deregister early in shutdown so the ALB stops routing new traffic
 	// before the drain finishes.
 	if err := s.Traffic.Deregister(shutdownCtx); err != nil {
@@ -254,7 +540,36 @@ func (s *Server) Run(ctx context.Context) error {
 			slog.Warn("router standby stop error", "err", err)
 		}
 	}
+	if s.Shards != nil {
+		// Drained: hand the shards over now rather than at lease expiry.
+		s.Shards.Release(shutdownCtx)
+		if err := s.Shards.Close(); err != nil {
+			slog.Warn("router shard store close error", "err", err)
+		}
+	}
 	s.Notifier.Stop()
+	for _, a := range s.Alerts {
+		a.Stop()
+	}
+	<-persistDone
+	if s.warningStore != nil {
+		if err := s.warningStore.Close(); err != nil {
+			slog.Warn("warning store close error", "err", err)
+		}
+	}
+	if err := s.poisonStore.Close(); err != nil {
+		slog.Warn("poison store close error", "err", err)
+	}
+	if s.Capture != nil {
+		if err := s.Capture.Close(); err != nil {
+			slog.Warn("capture store close error", "err", err)
+		}
+	}
+	if s.pipeStore != nil {
+		if err := s.pipeStore.Close(); err != nil {
+			slog.Warn("pipeline store close error", "err", err)
+		}
+	}
 
 	slog.Info("router stopped")
 	return nil
@@ -293,13 +608,6 @@ func (s *Server) reapInFlight(ctx context.Context) {
 	}
 }
 
-func pickMediator(devMode bool, breakers *BreakerRegistry) Mediator {
-	if devMode {
-		return NewHTTPMediator(DevMediatorConfig(), breakers)
-	}
-	return NewHTTPMediator(DefaultMediatorConfig(), breakers)
-}
-
 // gateOnLeadership starts the pool config watcher only when this
 // instance is the leader. On loss of leadership it cancels the
 // per-leadership context so pools wind down. Also drives the traffic
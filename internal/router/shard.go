@@ -0,0 +1,282 @@
+package router
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sharded consumption. Under leader election one router consumes
+// everything; in sharded mode every instance consumes, and ordered message
+// groups are split between them. A group hashes to one of a fixed number
+// of shards, each shard is held under a lease by one instance, and a
+// router hands ordered messages of groups it doesn't own straight back to
+// the broker for the owner to pick up. Unordered messages go to whoever
+// polls them.
+//
+// Instances heartbeat into a membership list and agree on the desired
+// owner of each shard by rendezvous hashing over the live members, so a
+// join or leave moves only ~1/N of the shards. Handover is cooperative:
+// the old owner stops admitting the shard, finishes what it has in flight
+// for it, and only then releases the lease the new owner is waiting to
+// take. Per-group ordering therefore survives rebalances.
+
+// ShardStore coordinates membership and shard leases between routers.
+type ShardStore interface {
+	// Heartbeat records instanceID as alive for ttl and returns every
+	// live member, including instanceID.
+	Heartbeat(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error)
+	// Acquire takes or renews shard's lease for ttl. It reports false
+	// while another instance holds an unexpired lease.
+	Acquire(ctx context.Context, shard int, instanceID string, ttl time.Duration) (bool, error)
+	// Release drops instanceID's lease on shard, if it holds it.
+	Release(ctx context.Context, shard int, instanceID string) error
+	Close() error
+}
+
+// ShardConfig tunes a ShardCoordinator.
+type ShardConfig struct {
+	// Shards is the number of partitions groups hash into; every router
+	// must use the same value. Zero falls back to 64.
+	Shards int
+	// InstanceID identifies this router; empty generates one.
+	InstanceID string
+	// LeaseTTL is how long membership and shard leases live without a
+	// heartbeat. Heartbeats run every LeaseTTL/3. Zero falls back to 30s.
+	LeaseTTL time.Duration
+}
+
+// ShardStatus is a point-in-time view of this router's shards.
+type ShardStatus struct {
+	InstanceID string
+	Shards     int
+	Members    []string
+	// Owned are the shards this router currently admits.
+	Owned []int
+	// Releasing are shards handed to another member that still have
+	// messages in flight here.
+	Releasing []int
+}
+
+// ShardOf maps a message group to its shard.
+func ShardOf(group string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(group))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// shardOwner picks shard's desired owner among members: the member with
+// the highest hash of (member, shard).
+func shardOwner(members []string, shard int) string {
+	var best string
+	var bestScore uint64
+	for _, m := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(m))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(strconv.Itoa(shard)))
+		if s := mix64(h.Sum64()); best == "" || s > bestScore {
+			best, bestScore = m, s
+		}
+	}
+	return best
+}
+
+// mix64 is the splitmix64 finalizer. FNV alone barely spreads inputs
+// that differ in one early byte (instance IDs often do), which skews
+// rendezvous scores.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// shardView is the admitted set, valid until the leases could lapse.
+type shardView struct {
+	owned map[int]bool
+	until time.Time
+}
+
+// ShardCoordinator keeps this router's shard leases and answers whether a
+// message group belongs here.
+type ShardCoordinator struct {
+	cfg     ShardConfig
+	store   ShardStore
+	tracker *InFlightTracker
+	now     func() time.Time
+
+	view atomic.Pointer[shardView]
+
+	// mu guards the lease bookkeeping; only the Run loop writes it.
+	mu        sync.Mutex
+	held      map[int]bool
+	releasing map[int]bool
+	members   []string
+}
+
+// NewShardCoordinator builds a coordinator on store. tracker supplies the
+// in-flight messages a shard must drain before its lease is released; nil
+// releases immediately.
+func NewShardCoordinator(cfg ShardConfig, store ShardStore, tracker *InFlightTracker) *ShardCoordinator {
+	if cfg.Shards <= 0 {
+		cfg.Shards = 64
+	}
+	if cfg.InstanceID == "" {
+		cfg.InstanceID = uuid.NewString()
+	}
+	if cfg.LeaseTTL <= 0 {
+		cfg.LeaseTTL = 30 * time.Second
+	}
+	return &ShardCoordinator{
+		cfg:       cfg,
+		store:     store,
+		tracker:   tracker,
+		now:       time.Now,
+		held:      make(map[int]bool),
+		releasing: make(map[int]bool),
+	}
+}
+
+// Owns reports whether this router admits messages of group. It fails
+// closed: before the first successful heartbeat, and once heartbeats have
+// failed for half a lease, nothing is owned.
+func (c *ShardCoordinator) Owns(group string) bool {
+	v := c.view.Load()
+	if v == nil || c.now().After(v.until) {
+		return false
+	}
+	return v.owned[ShardOf(group, c.cfg.Shards)]
+}
+
+// Status returns the current shard view.
+func (c *ShardCoordinator) Status() ShardStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := ShardStatus{
+		InstanceID: c.cfg.InstanceID,
+		Shards:     c.cfg.Shards,
+		Members:    append([]string(nil), c.members...),
+	}
+	v := c.view.Load()
+	live := v != nil && !c.now().After(v.until)
+	for s := range c.held {
+		if c.releasing[s] {
+			st.Releasing = append(st.Releasing, s)
+		} else if live {
+			st.Owned = append(st.Owned, s)
+		}
+	}
+	sort.Ints(st.Owned)
+	sort.Ints(st.Releasing)
+	return st
+}
+
+// Run heartbeats and rebalances until ctx is cancelled, after which
+// nothing is admitted. The leases stay held until Release so in-flight
+// work can drain first.
+func (c *ShardCoordinator) Run(ctx context.Context) {
+	tick := time.NewTicker(c.cfg.LeaseTTL / 3)
+	defer tick.Stop()
+	c.rebalance(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			c.view.Store(nil)
+			return
+		case <-tick.C:
+			c.rebalance(ctx)
+		}
+	}
+}
+
+// rebalance is one heartbeat: renew what this router should keep, start
+// handing over what it shouldn't, and claim what it should own but
+// doesn't yet.
+func (c *ShardCoordinator) rebalance(ctx context.Context) {
+	start := c.now()
+	members, err := c.store.Heartbeat(ctx, c.cfg.InstanceID, c.cfg.LeaseTTL)
+	if err != nil {
+		// Keep the current view: it lapses on its own before the leases
+		// do, so a store outage stops ordered consumption rather than
+		// risk two owners.
+		slog.Warn("router shard heartbeat failed", "instance", c.cfg.InstanceID, "err", err)
+		return
+	}
+	sort.Strings(members)
+	busy := c.busyShards()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.members = members
+	owned := make(map[int]bool)
+	for s := 0; s < c.cfg.Shards; s++ {
+		want := shardOwner(members, s) == c.cfg.InstanceID
+		switch {
+		case c.held[s] && !want && !busy[s]:
+			if err := c.store.Release(ctx, s, c.cfg.InstanceID); err != nil {
+				slog.Warn("router shard release failed", "shard", s, "err", err)
+			}
+			delete(c.held, s)
+			delete(c.releasing, s)
+		case c.held[s] || want:
+			ok, err := c.store.Acquire(ctx, s, c.cfg.InstanceID, c.cfg.LeaseTTL)
+			if err != nil {
+				slog.Warn("router shard lease failed", "shard", s, "err", err)
+			}
+			if !ok {
+				delete(c.held, s)
+				delete(c.releasing, s)
+				continue
+			}
+			c.held[s] = true
+			if want {
+				delete(c.releasing, s)
+				owned[s] = true
+			} else {
+				// Handed to another member but still draining here: keep
+				// the lease so the new owner waits, admit nothing new.
+				c.releasing[s] = true
+			}
+		}
+	}
+	c.view.Store(&shardView{owned: owned, until: start.Add(c.cfg.LeaseTTL / 2)})
+}
+
+// busyShards are the shards with messages in flight here.
+func (c *ShardCoordinator) busyShards() map[int]bool {
+	busy := make(map[int]bool)
+	if c.tracker == nil {
+		return busy
+	}
+	for _, im := range c.tracker.Snapshot() {
+		busy[ShardOf(im.MessageGroupID, c.cfg.Shards)] = true
+	}
+	return busy
+}
+
+// Release gives up every lease so the remaining members take over
+// without waiting for expiry. Call it after the pools have drained.
+func (c *ShardCoordinator) Release(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s := range c.held {
+		if err := c.store.Release(ctx, s, c.cfg.InstanceID); err != nil {
+			slog.Warn("router shard release failed", "shard", s, "err", err)
+		}
+	}
+	c.held = make(map[int]bool)
+	c.releasing = make(map[int]bool)
+}
+
+// Close releases the store.
+func (c *ShardCoordinator) Close() error { return c.store.Close() }
@@ -0,0 +1,125 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const (
+	shardMemberCollection = "router_shard_members"
+	shardLeaseCollection  = "router_shard_leases"
+)
+
+// MongoShardStore keeps router membership in router_shard_members (one
+// document per instance, expired by a TTL index) and shard leases in
+// router_shard_leases (one document per shard). A lease is taken with a
+// conditional upsert: it matches only when free, expired or already ours,
+// so a lost race surfaces as a duplicate-key error on _id.
+type MongoShardStore struct {
+	client  *mongo.Client
+	members *mongo.Collection
+	leases  *mongo.Collection
+	now     func() time.Time
+}
+
+type shardMemberDoc struct {
+	ID        string    `bson:"_id"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// NewMongoShardStore connects to uri, targets database dbName and ensures
+// the membership TTL index.
+func NewMongoShardStore(ctx context.Context, uri, dbName string, mc mongoconn.Config) (*MongoShardStore, error) {
+	if uri == "" {
+		return nil, errors.New("mongo shard store requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	client, err := mongoconn.Connect(ctx, "router-shards", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	db := client.Database(dbName)
+	majority := options.Collection().SetWriteConcern(writeconcern.Majority())
+	st := &MongoShardStore{
+		client:  client,
+		members: db.Collection(shardMemberCollection, majority),
+		leases:  db.Collection(shardLeaseCollection, majority),
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+	if _, err := st.members.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("shard member ttl index: %w", err)
+	}
+	return st, nil
+}
+
+// Heartbeat implements ShardStore.
+func (st *MongoShardStore) Heartbeat(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error) {
+	now := st.now()
+	if _, err := st.members.UpdateOne(ctx,
+		bson.M{"_id": instanceID},
+		bson.M{"$set": bson.M{"expires_at": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		return nil, err
+	}
+	// The TTL monitor only sweeps once a minute; filter expired members
+	// here so a dead router stops counting after one lease.
+	cur, err := st.members.Find(ctx, bson.M{"expires_at": bson.M{"$gt": now}})
+	if err != nil {
+		return nil, err
+	}
+	var docs []shardMemberDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, d.ID)
+	}
+	return out, nil
+}
+
+// Acquire implements ShardStore.
+func (st *MongoShardStore) Acquire(ctx context.Context, shard int, instanceID string, ttl time.Duration) (bool, error) {
+	now := st.now()
+	_, err := st.leases.UpdateOne(ctx,
+		bson.M{"_id": shard, "$or": bson.A{
+			bson.M{"owner": instanceID},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		}},
+		bson.M{"$set": bson.M{"owner": instanceID, "expires_at": now.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Release implements ShardStore.
+func (st *MongoShardStore) Release(ctx context.Context, shard int, instanceID string) error {
+	_, err := st.leases.DeleteOne(ctx, bson.M{"_id": shard, "owner": instanceID})
+	return err
+}
+
+// Close implements ShardStore.
+func (st *MongoShardStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return st.client.Disconnect(ctx)
+}
@@ -0,0 +1,202 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// memShardStore is an in-process ShardStore on a shared test clock.
+type memShardStore struct {
+	mu      sync.Mutex
+	now     *time.Time
+	members map[string]time.Time
+	leases  map[int]shardLease
+	fail    bool
+}
+
+type shardLease struct {
+	owner   string
+	expires time.Time
+}
+
+func newMemShardStore(now *time.Time) *memShardStore {
+	return &memShardStore{now: now, members: map[string]time.Time{}, leases: map[int]shardLease{}}
+}
+
+func (m *memShardStore) Heartbeat(_ context.Context, id string, ttl time.Duration) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return nil, errors.New("store down")
+	}
+	m.members[id] = m.now.Add(ttl)
+	var out []string
+	for id, exp := range m.members {
+		if exp.After(*m.now) {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func (m *memShardStore) Acquire(_ context.Context, shard int, id string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.leases[shard]; ok && l.owner != id && l.expires.After(*m.now) {
+		return false, nil
+	}
+	m.leases[shard] = shardLease{owner: id, expires: m.now.Add(ttl)}
+	return true, nil
+}
+
+func (m *memShardStore) Release(_ context.Context, shard int, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leases[shard].owner == id {
+		delete(m.leases, shard)
+	}
+	return nil
+}
+
+func (m *memShardStore) Close() error { return nil }
+
+func newTestShards(id string, store *memShardStore, clock *time.Time, tracker *InFlightTracker) *ShardCoordinator {
+	c := NewShardCoordinator(ShardConfig{Shards: 16, InstanceID: id, LeaseTTL: 30 * time.Second}, store, tracker)
+	c.now = func() time.Time { return *clock }
+	return c
+}
+
+func TestShardOwnerStableOnJoin(t *testing.T) {
+	before := []string{"a", "b", "c"}
+	after := []string{"a", "b", "c", "d"}
+	counts := map[string]int{}
+	for s := 0; s < 256; s++ {
+		was, is := shardOwner(before, s), shardOwner(after, s)
+		counts[was]++
+		if was != is && is != "d" {
+			t.Fatalf("shard %d moved %s→%s; a join may only move shards to the new member", s, was, is)
+		}
+	}
+	for _, m := range before {
+		if counts[m] < 40 {
+			t.Errorf("member %s owns only %d/256 shards", m, counts[m])
+		}
+	}
+}
+
+func TestShardHandoverWaitsForInFlight(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(1_700_000_000, 0)
+	store := newMemShardStore(&clock)
+	trA := NewInFlightTracker()
+	a := newTestShards("a", store, &clock, trA)
+	b := newTestShards("b", store, &clock, nil)
+
+	groups := make([]string, 200)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("order-%d", i)
+	}
+	exclusive := func(step string) {
+		t.Helper()
+		for _, g := range groups {
+			if a.Owns(g) && b.Owns(g) {
+				t.Fatalf("%s: group %s owned by both routers", step, g)
+			}
+		}
+	}
+
+	a.rebalance(ctx)
+	if len(a.Status().Owned) != 16 {
+		t.Fatalf("a lone router owns every shard, got %v", a.Status().Owned)
+	}
+
+	// A group moving to b has a message in flight on a.
+	var moving string
+	for _, g := range groups {
+		if shardOwner([]string{"a", "b"}, ShardOf(g, 16)) == "b" {
+			moving = g
+			break
+		}
+	}
+	trA.Register(common.NewInFlightMessage(&common.Message{ID: "m1", MessageGroupID: &moving}, "", "q", "1", "rh"))
+
+	b.rebalance(ctx)
+	exclusive("b joined")
+	if len(b.Status().Owned) != 0 {
+		t.Fatal("b must wait for a to release")
+	}
+
+	a.rebalance(ctx)
+	exclusive("a rebalanced")
+	if a.Owns(moving) || len(a.Status().Releasing) != 1 {
+		t.Fatalf("a must stop admitting the moving shard but keep its lease: %+v", a.Status())
+	}
+
+	b.rebalance(ctx)
+	exclusive("b claimed")
+	if b.Owns(moving) || len(b.Status().Owned) == 0 {
+		t.Fatalf("b takes every free shard but not the draining one: %+v", b.Status())
+	}
+
+	trA.Remove("m1", "")
+	a.rebalance(ctx)
+	b.rebalance(ctx)
+	exclusive("drained")
+	if !b.Owns(moving) || len(a.Status().Owned)+len(b.Status().Owned) != 16 {
+		t.Fatalf("after the drain b owns the moved shard: a=%+v b=%+v", a.Status(), b.Status())
+	}
+
+	a.Release(ctx)
+	clock = clock.Add(31 * time.Second) // a's membership lapses
+	b.rebalance(ctx)
+	if len(b.Status().Owned) != 16 {
+		t.Fatalf("b takes over a released router's shards, got %v", b.Status().Owned)
+	}
+}
+
+func TestShardOwnershipFailsClosed(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	store := newMemShardStore(&clock)
+	c := newTestShards("a", store, &clock, nil)
+	if c.Owns("g") {
+		t.Fatal("nothing is owned before the first heartbeat")
+	}
+	c.rebalance(context.Background())
+	if !c.Owns("g") {
+		t.Fatal("a lone router owns every group")
+	}
+	store.fail = true
+	clock = clock.Add(10 * time.Second)
+	c.rebalance(context.Background())
+	if !c.Owns("g") {
+		t.Fatal("one failed heartbeat keeps the view")
+	}
+	clock = clock.Add(6 * time.Second)
+	if c.Owns("g") {
+		t.Fatal("ownership lapses half a lease after the last heartbeat")
+	}
+}
+
+func TestManagerRouteNacksForeignShard(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	m := NewManager(&cascadeMediator{}, NewInFlightTracker())
+	m.SetShards(newTestShards("a", newMemShardStore(&clock), &clock, nil)) // no heartbeat yet: owns nothing
+
+	cons := &grConsumer{id: "q"}
+	m.route(context.Background(), []common.QueuedMessage{mkGrouped("m1", "b1", "rh1")}, cons)
+	if cons.nacks.Load() != 1 || *cons.lastNackDelay.Load() != 0 {
+		t.Fatalf("a foreign ordered message is nacked for immediate redelivery, nacks=%d", cons.nacks.Load())
+	}
+	if m.tracker.Count() != 0 {
+		t.Fatal("a foreign message must not be tracked")
+	}
+	if !m.ownsGroup(common.Message{ID: "m2", DispatchMode: common.DispatchImmediate}) {
+		t.Fatal("unordered messages are never sharded")
+	}
+}
@@ -0,0 +1,225 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SLO is a delivery-latency objective for a pool: at least Target of the
+// delivery attempts in the evaluation window succeed within Threshold.
+type SLO struct {
+	// Pool is the pool code, or "*" for every pool without its own SLO.
+	Pool      string
+	Target    float64 // fraction, e.g. 0.95
+	Threshold time.Duration
+}
+
+func (o SLO) String() string {
+	return fmt.Sprintf("%s:%s%%<%s", o.Pool, strconv.FormatFloat(o.Target*100, 'f', -1, 64), o.Threshold)
+}
+
+// ParseSLOs parses FC_ROUTER_SLOS: ';'-separated POOL:PCT%<DURATION
+// entries, e.g. "DEFAULT-POOL:95%<60s;*:99%<5m".
+func ParseSLOs(spec string) ([]SLO, error) {
+	var out []SLO
+	seen := map[string]bool{}
+	for entry := range strings.SplitSeq(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pool, objective, ok := strings.Cut(entry, ":")
+		pct, threshold, ok2 := strings.Cut(objective, "%<")
+		pool = strings.TrimSpace(pool)
+		if !ok || !ok2 || pool == "" {
+			return nil, fmt.Errorf("SLO %q: want POOL:PCT%%<DURATION", entry)
+		}
+		target, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || target <= 0 || target > 100 {
+			return nil, fmt.Errorf("SLO %q: percentage must be in (0, 100]", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(threshold))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("SLO %q: threshold must be a positive duration", entry)
+		}
+		if seen[pool] {
+			return nil, fmt.Errorf("SLO for pool %q defined twice", pool)
+		}
+		seen[pool] = true
+		out = append(out, SLO{Pool: pool, Target: target / 100, Threshold: d})
+	}
+	return out, nil
+}
+
+// SLOMonitorConfig tunes SLO evaluation.
+type SLOMonitorConfig struct {
+	// Interval between evaluations.
+	Interval time.Duration
+	// Window is the trailing period each evaluation looks at. Capped by
+	// the metrics collector's long window (30m).
+	Window time.Duration
+	// MinSamples: fewer attempts than this in the window is not enough
+	// traffic to judge; the SLO's state is left unchanged.
+	MinSamples uint64
+	// EscalateAfter is the number of consecutive breached evaluations
+	// that raises the warning a severity level: WARNING, then ERROR after
+	// EscalateAfter, then CRITICAL after twice that.
+	EscalateAfter int
+}
+
+// DefaultSLOMonitorConfig evaluates every 30s over 5m and escalates
+// every 5 minutes of continuous breach.
+func DefaultSLOMonitorConfig() SLOMonitorConfig {
+	return SLOMonitorConfig{
+		Interval:      30 * time.Second,
+		Window:        5 * time.Minute,
+		MinSamples:    20,
+		EscalateAfter: 10,
+	}
+}
+
+// sloMetricsSource yields per-pool metrics. Satisfied by *Manager.
+type sloMetricsSource interface {
+	PoolCodes() []string
+	PoolMetrics(code string) *PoolMetricsCollector
+}
+
+// SLOMonitor evaluates the configured SLOs against the pools' windowed
+// delivery metrics and raises SLO warnings. A breach raises a WARNING;
+// while it persists the warning is re-raised at ERROR and then CRITICAL
+// (which the alert sinks deliver). When the pool recovers its SLO
+// warnings are acknowledged.
+type SLOMonitor struct {
+	cfg      SLOMonitorConfig
+	slos     []SLO
+	pools    sloMetricsSource
+	warnings *WarningService
+
+	mu    sync.Mutex
+	state map[string]*sloState // by pool code
+}
+
+type sloState struct {
+	breaches int
+	raised   WarningSeverity // "" while within objective
+}
+
+// NewSLOMonitor wires a monitor. Zero config fields take the defaults.
+func NewSLOMonitor(cfg SLOMonitorConfig, slos []SLO, pools sloMetricsSource, warnings *WarningService) *SLOMonitor {
+	def := DefaultSLOMonitorConfig()
+	if cfg.Interval <= 0 {
+		cfg.Interval = def.Interval
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = def.Window
+	}
+	if cfg.MinSamples == 0 {
+		cfg.MinSamples = def.MinSamples
+	}
+	if cfg.EscalateAfter <= 0 {
+		cfg.EscalateAfter = def.EscalateAfter
+	}
+	return &SLOMonitor{
+		cfg:      cfg,
+		slos:     slos,
+		pools:    pools,
+		warnings: warnings,
+		state:    make(map[string]*sloState),
+	}
+}
+
+// Run evaluates on a ticker until ctx is cancelled.
+func (m *SLOMonitor) Run(ctx context.Context) {
+	slog.Info("SLO monitor started", "slos", len(m.slos), "interval", m.cfg.Interval, "window", m.cfg.Window)
+	t := time.NewTicker(m.cfg.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			m.Evaluate()
+		}
+	}
+}
+
+// Evaluate checks every running pool against its SLO once.
+func (m *SLOMonitor) Evaluate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, code := range m.pools.PoolCodes() {
+		slo, ok := m.sloFor(code)
+		if !ok {
+			continue
+		}
+		metrics := m.pools.PoolMetrics(code)
+		if metrics == nil {
+			continue
+		}
+		within, total := metrics.LatencyCompliance(m.cfg.Window, slo.Threshold)
+		if total < m.cfg.MinSamples {
+			continue
+		}
+		st := m.state[code]
+		if st == nil {
+			st = &sloState{}
+			m.state[code] = st
+		}
+		compliance := float64(within) / float64(total)
+		if compliance >= slo.Target {
+			if st.raised != "" {
+				source := sloSource(code)
+				n := m.warnings.AcknowledgeMatching(func(w Warning) bool { return w.Source == source })
+				slog.Info("pool back within SLO", "pool", code, "slo", slo.String(),
+					"compliance", compliance, "acknowledged", n)
+			}
+			*st = sloState{}
+			continue
+		}
+		st.breaches++
+		severity := m.severityFor(st.breaches)
+		if severity == st.raised {
+			continue
+		}
+		st.raised = severity
+		m.warnings.Add(WarningCategorySLO, severity,
+			fmt.Sprintf("pool %s is breaching its SLO (%s): %.1f%% of %d deliveries in the last %s were on time",
+				code, slo, compliance*100, total, m.cfg.Window),
+			sloSource(code))
+	}
+}
+
+// sloFor picks the pool's own SLO, else the "*" one.
+func (m *SLOMonitor) sloFor(code string) (SLO, bool) {
+	var fallback *SLO
+	for i := range m.slos {
+		switch m.slos[i].Pool {
+		case code:
+			return m.slos[i], true
+		case "*":
+			fallback = &m.slos[i]
+		}
+	}
+	if fallback == nil {
+		return SLO{}, false
+	}
+	return *fallback, true
+}
+
+func (m *SLOMonitor) severityFor(breaches int) WarningSeverity {
+	switch {
+	case breaches > 2*m.cfg.EscalateAfter:
+		return WarningCritical
+	case breaches > m.cfg.EscalateAfter:
+		return WarningError
+	default:
+		return WarningWarning
+	}
+}
+
+func sloSource(pool string) string { return "SLOMonitor:" + pool }
@@ -0,0 +1,156 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSLOs(t *testing.T) {
+	got, err := ParseSLOs(" DEFAULT-POOL:95%<60s ; *:99.5%<5m ;")
+	if err != nil {
+		t.Fatalf("ParseSLOs: %v", err)
+	}
+	want := []SLO{
+		{Pool: "DEFAULT-POOL", Target: 0.95, Threshold: time.Minute},
+		{Pool: "*", Target: 0.995, Threshold: 5 * time.Minute},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d]: got %+v want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"P:95<60s", "P:0%<60s", "P:101%<1s", "P:95%<soon", ":95%<1s", "P:95%<1s;P:90%<1s"} {
+		if _, err := ParseSLOs(bad); err == nil {
+			t.Errorf("ParseSLOs(%q): expected error", bad)
+		}
+	}
+}
+
+type fakeSLOPools map[string]*PoolMetricsCollector
+
+func (f fakeSLOPools) PoolCodes() []string {
+	codes := make([]string, 0, len(f))
+	for c := range f {
+		codes = append(codes, c)
+	}
+	return codes
+}
+
+func (f fakeSLOPools) PoolMetrics(code string) *PoolMetricsCollector { return f[code] }
+
+func TestSLOMonitor_EscalatesAndRecovers(t *testing.T) {
+	slow := NewPoolMetricsCollector()
+	for range 10 {
+		slow.RecordSuccess(5000) // 5s, over the 1s threshold
+	}
+	pools := fakeSLOPools{"P": slow}
+	warnings := NewWarningService(WarningServiceConfig{})
+	m := NewSLOMonitor(SLOMonitorConfig{MinSamples: 5, EscalateAfter: 2},
+		[]SLO{{Pool: "*", Target: 0.9, Threshold: time.Second}}, pools, warnings)
+
+	// Breaches 1-2 raise one WARNING, 3-4 an ERROR, 5+ a CRITICAL.
+	wantCounts := []int{1, 1, 2, 2, 3, 3}
+	for i, want := range wantCounts {
+		m.Evaluate()
+		if got := warnings.Count(); got != want {
+			t.Fatalf("evaluation %d: %d warnings, want %d", i+1, got, want)
+		}
+	}
+	if got := warnings.CriticalCount(); got != 1 {
+		t.Fatalf("CriticalCount: got %d want 1", got)
+	}
+	for _, w := range warnings.All() {
+		if w.Category != WarningCategorySLO || w.Source != "SLOMonitor:P" {
+			t.Errorf("unexpected warning %v", w)
+		}
+	}
+
+	// Recovery: the window is now dominated by fast deliveries.
+	for range 200 {
+		slow.RecordSuccess(10)
+	}
+	m.Evaluate()
+	if got := warnings.UnacknowledgedCount(); got != 0 {
+		t.Fatalf("UnacknowledgedCount after recovery: got %d want 0", got)
+	}
+}
+
+func TestSLOMonitor_SkipsLowTrafficAndPrefersPoolSLO(t *testing.T) {
+	quiet := NewPoolMetricsCollector()
+	quiet.RecordFailure(10)
+	busy := NewPoolMetricsCollector()
+	for range 10 {
+		busy.RecordSuccess(2000) // 2s: breaches "*" but not the BUSY SLO
+	}
+	warnings := NewWarningService(WarningServiceConfig{})
+	m := NewSLOMonitor(SLOMonitorConfig{MinSamples: 5},
+		[]SLO{
+			{Pool: "*", Target: 0.9, Threshold: time.Second},
+			{Pool: "BUSY", Target: 0.9, Threshold: 5 * time.Second},
+		},
+		fakeSLOPools{"QUIET": quiet, "BUSY": busy}, warnings)
+	m.Evaluate()
+	if got := warnings.Count(); got != 0 {
+		t.Fatalf("got %d warnings, want 0: %+v", got, warnings.All())
+	}
+}
+
+func TestSlackNotifier_PostsText(t *testing.T) {
+	got := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		_ = json.Unmarshal(body, &m)
+		got <- m
+	}))
+	defer srv.Close()
+
+	n := NewSlackNotifier(srv.URL, 20, time.Hour)
+	n.SetMinSeverity(WarningCritical)
+	n.Add(NewWarning(WarningCategorySLO, WarningError, "dropped", "t"))
+	n.Add(NewWarning(WarningCategorySLO, WarningCritical, "pool P is breaching", "t"))
+
+	select {
+	case m := <-got:
+		text, _ := m["text"].(string)
+		if text != "*CRITICAL* SLO: pool P is breaching (from t)" {
+			t.Fatalf("text: got %q", text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no post received")
+	}
+}
+
+func TestMailNotifier_EmailsCritical(t *testing.T) {
+	type mail struct{ subject, body string }
+	got := make(chan mail, 1)
+	n := NewMailNotifier(func(_ context.Context, subject, body string) error {
+		got <- mail{subject, body}
+		return nil
+	}, 20, time.Hour)
+	n.SetMinSeverity(WarningCritical)
+	n.Add(NewWarning(WarningCategorySLO, WarningError, "dropped", "t"))
+	n.Add(NewWarning(WarningCategorySLO, WarningCritical, "pool <P> is breaching", "t"))
+
+	select {
+	case m := <-got:
+		if m.subject != "[FlowCatalyst CRITICAL] SLO: pool <P> is breaching" {
+			t.Fatalf("subject: got %q", m.subject)
+		}
+		if !strings.Contains(m.body, "pool &lt;P&gt; is breaching") || strings.Contains(m.body, "dropped") {
+			t.Fatalf("body: got %q", m.body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no email sent")
+	}
+}
@@ -33,7 +33,8 @@ func DefaultWarningServiceConfig() WarningServiceConfig {
 }
 
 // WarningService is the in-memory warning store. Mirrors
-// `crates/fc-router/src/warning.rs::WarningService`.
+// `crates/fc-router/src/warning.rs::WarningService`. An optional
+// WarningStore (SetStore) keeps the history across restarts.
 //
 // The store is bounded (MaxWarnings) and self-cleaning (cleanup()
 // auto-acks aged warnings + drops very old ones). If a Notifier is
@@ -50,6 +51,12 @@ type WarningService struct {
 
 	notifyMu sync.RWMutex
 	notifier *Notifier
+	sinks    []*Notifier // alert sinks added with AddNotifier
+
+	// store, when set, receives every add/ack/resolve via persistCh;
+	// see warning_store.go.
+	store     WarningStore
+	persistCh chan Warning
 }
 
 // NewWarningService builds a service. Pass a zero-value Config to use defaults.
@@ -81,8 +88,16 @@ func (s *WarningService) SetNotifier(n *Notifier) {
 	s.notifier = n
 }
 
+// AddNotifier attaches an additional Notifier (an alert sink) alongside
+// the one set by SetNotifier. Each applies its own MinSeverity filter.
+func (s *WarningService) AddNotifier(n *Notifier) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.sinks = append(s.sinks, n)
+}
+
 // Add records a new warning and returns its id. Forwards to the
-// attached notifier (if any). Evicts the oldest 10% if the store is at
+// attached notifier and alert sinks (if any). Evicts the oldest 10% if the store is at
 // capacity.
 func (s *WarningService) Add(category WarningCategory, severity WarningSeverity, message, source string) string {
 	w := NewWarning(category, severity, message, source)
@@ -95,11 +110,15 @@ func (s *WarningService) Add(category WarningCategory, severity WarningSeverity,
 	s.mu.Unlock()
 
 	s.notifyMu.RLock()
-	n := s.notifier
+	n, sinks := s.notifier, s.sinks
 	s.notifyMu.RUnlock()
 	if n != nil {
 		n.Add(w)
 	}
+	for _, sink := range sinks {
+		sink.Add(w)
+	}
+	s.persist(w)
 	return w.ID
 }
 
@@ -182,6 +201,28 @@ func (s *WarningService) Acknowledge(id string) bool {
 	w.Acknowledged = true
 	w.AcknowledgedAt = &now
 	s.warnings[id] = w
+	s.persist(w)
+	return true
+}
+
+// Resolve marks a warning resolved (acknowledging it if needed) and drops
+// it from the live set; it remains in the store's history. Returns false
+// if no warning has that id.
+func (s *WarningService) Resolve(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.warnings[id]
+	if !ok {
+		return false
+	}
+	now := time.Now().UTC()
+	if !w.Acknowledged {
+		w.Acknowledged = true
+		w.AcknowledgedAt = &now
+	}
+	w.ResolvedAt = &now
+	delete(s.warnings, id)
+	s.persist(w)
 	return true
 }
 
@@ -197,6 +238,7 @@ func (s *WarningService) AcknowledgeMatching(predicate func(Warning) bool) int {
 			w.Acknowledged = true
 			w.AcknowledgedAt = &now
 			s.warnings[id] = w
+			s.persist(w)
 			count++
 		}
 	}
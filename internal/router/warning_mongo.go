@@ -0,0 +1,163 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const warningCollection = "router_warnings"
+
+// MongoWarningStore keeps warnings in router_warnings, one document per
+// warning keyed by its ID. Retention is a TTL index on created_at, so
+// Mongo expires history itself; changing the retention rebuilds the
+// index on the next start.
+type MongoWarningStore struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+}
+
+type warningDoc struct {
+	ID             string     `bson:"_id"`
+	Category       string     `bson:"category"`
+	Severity       string     `bson:"severity"`
+	Message        string     `bson:"message"`
+	Source         string     `bson:"source"`
+	CreatedAt      time.Time  `bson:"created_at"`
+	Acknowledged   bool       `bson:"acknowledged"`
+	AcknowledgedAt *time.Time `bson:"acknowledged_at,omitempty"`
+	ResolvedAt     *time.Time `bson:"resolved_at,omitempty"`
+}
+
+// NewMongoWarningStore connects to uri, targets database dbName and
+// ensures the collection's indexes. retention <= 0 keeps history forever.
+func NewMongoWarningStore(ctx context.Context, uri, dbName string, retention time.Duration, mc mongoconn.Config) (*MongoWarningStore, error) {
+	if uri == "" {
+		return nil, errors.New("mongo warning store requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	client, err := mongoconn.Connect(ctx, "router-warnings", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	st := &MongoWarningStore{client: client, coll: client.Database(dbName).Collection(warningCollection)}
+	if err := st.ensureIndexes(ctx, retention); err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+	return st, nil
+}
+
+func (st *MongoWarningStore) ensureIndexes(ctx context.Context, retention time.Duration) error {
+	const ttlName = "created_at_ttl"
+	// Drop the TTL index first: Mongo rejects re-creating it with a
+	// different expireAfterSeconds. A missing index is fine.
+	if _, err := st.coll.Indexes().DropOne(ctx, ttlName); err != nil {
+		var cmdErr mongo.CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Code != 27 { // IndexNotFound
+			return fmt.Errorf("drop warning ttl index: %w", err)
+		}
+	}
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "severity", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	if retention > 0 {
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName(ttlName).
+				SetExpireAfterSeconds(int32(retention.Seconds())),
+		})
+	} else {
+		models = append(models, mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}})
+	}
+	if _, err := st.coll.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("create warning indexes: %w", err)
+	}
+	return nil
+}
+
+// Save implements WarningStore.
+func (st *MongoWarningStore) Save(ctx context.Context, w Warning) error {
+	d := warningDoc{
+		ID:             w.ID,
+		Category:       string(w.Category),
+		Severity:       string(w.Severity),
+		Message:        w.Message,
+		Source:         w.Source,
+		CreatedAt:      w.CreatedAt,
+		Acknowledged:   w.Acknowledged,
+		AcknowledgedAt: w.AcknowledgedAt,
+		ResolvedAt:     w.ResolvedAt,
+	}
+	_, err := st.coll.ReplaceOne(ctx, bson.M{"_id": w.ID}, d, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Find implements WarningStore.
+func (st *MongoWarningStore) Find(ctx context.Context, q WarningQuery) ([]Warning, error) {
+	filter := bson.M{}
+	if q.Category != "" {
+		filter["category"] = string(q.Category)
+	}
+	if q.Severity != "" {
+		filter["severity"] = string(q.Severity)
+	}
+	created := bson.M{}
+	if !q.Since.IsZero() {
+		created["$gte"] = q.Since
+	}
+	if !q.Until.IsZero() {
+		created["$lt"] = q.Until
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+	if q.Acknowledged != nil {
+		filter["acknowledged"] = *q.Acknowledged
+	}
+	if q.Resolved != nil {
+		filter["resolved_at"] = bson.M{"$exists": *q.Resolved}
+	}
+	cur, err := st.coll.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(q.limit())))
+	if err != nil {
+		return nil, err
+	}
+	var docs []warningDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	out := make([]Warning, len(docs))
+	for i, d := range docs {
+		out[i] = Warning{
+			ID:             d.ID,
+			Category:       WarningCategory(d.Category),
+			Severity:       WarningSeverity(d.Severity),
+			Message:        d.Message,
+			Source:         d.Source,
+			CreatedAt:      d.CreatedAt.UTC(),
+			Acknowledged:   d.Acknowledged,
+			AcknowledgedAt: d.AcknowledgedAt,
+			ResolvedAt:     d.ResolvedAt,
+		}
+	}
+	return out, nil
+}
+
+// Close implements WarningStore.
+func (st *MongoWarningStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return st.client.Disconnect(ctx)
+}
@@ -0,0 +1,180 @@
+package router
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WarningStore persists warnings beyond the in-memory working set so the
+// operational history survives restarts and can be audited. The
+// WarningService stays the source of truth for live reads (health,
+// dashboard); the store is written behind it and queried for history.
+type WarningStore interface {
+	// Save upserts w by ID.
+	Save(ctx context.Context, w Warning) error
+	// Find returns the stored warnings matching q, newest first.
+	Find(ctx context.Context, q WarningQuery) ([]Warning, error)
+	Close() error
+}
+
+// WarningQuery filters warning history. Zero fields don't filter.
+type WarningQuery struct {
+	Category     WarningCategory
+	Severity     WarningSeverity
+	Since        time.Time // CreatedAt >= Since
+	Until        time.Time // CreatedAt < Until
+	Acknowledged *bool
+	Resolved     *bool
+	// Limit caps the result; 0 means DefaultWarningQueryLimit.
+	Limit int
+}
+
+// DefaultWarningQueryLimit bounds a history query without an explicit limit.
+const DefaultWarningQueryLimit = 500
+
+// Matches reports whether w passes the query's filters (Limit aside).
+func (q WarningQuery) Matches(w Warning) bool {
+	if q.Category != "" && !strings.EqualFold(string(w.Category), string(q.Category)) {
+		return false
+	}
+	if q.Severity != "" && !strings.EqualFold(string(w.Severity), string(q.Severity)) {
+		return false
+	}
+	if !q.Since.IsZero() && w.CreatedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !w.CreatedAt.Before(q.Until) {
+		return false
+	}
+	if q.Acknowledged != nil && w.Acknowledged != *q.Acknowledged {
+		return false
+	}
+	if q.Resolved != nil && (w.ResolvedAt != nil) != *q.Resolved {
+		return false
+	}
+	return true
+}
+
+func (q WarningQuery) limit() int {
+	if q.Limit <= 0 {
+		return DefaultWarningQueryLimit
+	}
+	return q.Limit
+}
+
+// persistQueueSize bounds the write-behind queue. Warnings raised while
+// it is full are still kept in memory; only their history is lost.
+const persistQueueSize = 1024
+
+// SetStore attaches a WarningStore. Adds, acknowledgements and
+// resolutions are queued for it and written by RunPersist.
+func (s *WarningService) SetStore(store WarningStore) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.store = store
+	if s.persistCh == nil {
+		s.persistCh = make(chan Warning, persistQueueSize)
+	}
+}
+
+// persist queues w for the store, if one is attached. Never blocks.
+func (s *WarningService) persist(w Warning) {
+	s.notifyMu.RLock()
+	ch := s.persistCh
+	s.notifyMu.RUnlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- w:
+	default:
+		slog.Warn("warning store: write queue full, warning not persisted", "id", w.ID)
+	}
+}
+
+// RunPersist writes queued warnings to the store until ctx is cancelled,
+// then flushes what is still queued. No-op without a store.
+func (s *WarningService) RunPersist(ctx context.Context) {
+	s.notifyMu.RLock()
+	store, ch := s.store, s.persistCh
+	s.notifyMu.RUnlock()
+	if store == nil {
+		return
+	}
+	save := func(ctx context.Context, w Warning) {
+		wctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := store.Save(wctx, w); err != nil {
+			slog.Warn("warning store: save failed", "id", w.ID, "err", err)
+		}
+	}
+	for {
+		select {
+		case w := <-ch:
+			save(ctx, w)
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			for {
+				select {
+				case w := <-ch:
+					save(flushCtx, w)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Restore loads the unresolved warnings younger than MaxWarningAge from
+// the store back into memory, so a restart doesn't drop what operators
+// haven't dealt with yet. Returns the number loaded.
+func (s *WarningService) Restore(ctx context.Context) (int, error) {
+	s.notifyMu.RLock()
+	store := s.store
+	s.notifyMu.RUnlock()
+	if store == nil {
+		return 0, nil
+	}
+	unresolved := false
+	ws, err := store.Find(ctx, WarningQuery{
+		Since:    time.Now().Add(-s.cfg.MaxWarningAge),
+		Resolved: &unresolved,
+		Limit:    s.cfg.MaxWarnings,
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, w := range ws {
+		s.warnings[w.ID] = w
+	}
+	return len(ws), nil
+}
+
+// History returns warnings matching q, newest first: from the store when
+// one is attached, else from memory.
+func (s *WarningService) History(ctx context.Context, q WarningQuery) ([]Warning, error) {
+	s.notifyMu.RLock()
+	store := s.store
+	s.notifyMu.RUnlock()
+	if store != nil {
+		return store.Find(ctx, q)
+	}
+	var out []Warning
+	for _, w := range s.All() {
+		if q.Matches(w) {
+			out = append(out, w)
+		}
+	}
+	slices.SortFunc(out, func(a, b Warning) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	if len(out) > q.limit() {
+		out = out[:q.limit()]
+	}
+	return out, nil
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 )
@@ -80,3 +82,104 @@ func TestWarningService_ActiveFiltersOnAcknowledgedAndAge(t *testing.T) {
 		t.Fatalf("Active: got %+v want only 'b'", active)
 	}
 }
+
+type memWarningStore struct {
+	mu    sync.Mutex
+	saved map[string]Warning
+}
+
+func (m *memWarningStore) Save(_ context.Context, w Warning) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved[w.ID] = w
+	return nil
+}
+
+func (m *memWarningStore) Find(_ context.Context, q WarningQuery) ([]Warning, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Warning
+	for _, w := range m.saved {
+		if q.Matches(w) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (m *memWarningStore) Close() error { return nil }
+
+func (m *memWarningStore) get(id string) (Warning, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.saved[id]
+	return w, ok
+}
+
+func TestWarningService_PersistsAndRestores(t *testing.T) {
+	store := &memWarningStore{saved: map[string]Warning{}}
+	s := NewWarningService(WarningServiceConfig{})
+	s.SetStore(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); s.RunPersist(ctx) }()
+
+	acked := s.Add(WarningCategorySLO, WarningError, "slow", "t")
+	resolved := s.Add(WarningCategoryStall, WarningWarning, "stuck", "t")
+	open := s.Add(WarningCategoryRouting, WarningWarning, "unknown pool", "t")
+	s.Acknowledge(acked)
+	if !s.Resolve(resolved) {
+		t.Fatal("Resolve: returned false for existing id")
+	}
+	if s.Resolve("does-not-exist") {
+		t.Fatal("Resolve: returned true for missing id")
+	}
+	if got := s.Count(); got != 2 {
+		t.Fatalf("Count after resolve: got %d want 2", got)
+	}
+	cancel()
+	<-done // RunPersist flushes the queue on exit
+
+	if w, _ := store.get(acked); !w.Acknowledged {
+		t.Errorf("acknowledgement not persisted: %+v", w)
+	}
+	if w, _ := store.get(resolved); w.ResolvedAt == nil || !w.Acknowledged {
+		t.Errorf("resolution not persisted: %+v", w)
+	}
+
+	// A fresh service (restart) gets the unresolved ones back.
+	restarted := NewWarningService(WarningServiceConfig{})
+	restarted.SetStore(store)
+	n, err := restarted.Restore(context.Background())
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if n != 2 || restarted.UnacknowledgedCount() != 1 {
+		t.Fatalf("Restore: loaded %d (%d unacked), want 2 (1 unacked)", n, restarted.UnacknowledgedCount())
+	}
+	if all := restarted.Unacknowledged(); all[0].ID != open {
+		t.Errorf("unexpected unacknowledged warning %+v", all[0])
+	}
+
+	yes := true
+	hist, err := restarted.History(context.Background(), WarningQuery{Resolved: &yes})
+	if err != nil || len(hist) != 1 || hist[0].ID != resolved {
+		t.Fatalf("History(resolved): got %+v, %v", hist, err)
+	}
+}
+
+func TestWarningService_HistoryWithoutStore(t *testing.T) {
+	s := NewWarningService(WarningServiceConfig{})
+	s.Add(WarningCategorySLO, WarningError, "a", "t")
+	s.Add(WarningCategorySLO, WarningCritical, "b", "t")
+	s.Add(WarningCategoryStall, WarningError, "c", "t")
+
+	hist, err := s.History(context.Background(), WarningQuery{Category: WarningCategorySLO, Severity: WarningError})
+	if err != nil || len(hist) != 1 || hist[0].Message != "a" {
+		t.Fatalf("History: got %+v, %v", hist, err)
+	}
+	hist, _ = s.History(context.Background(), WarningQuery{Since: time.Now().Add(time.Minute)})
+	if len(hist) != 0 {
+		t.Fatalf("History(since future): got %d want 0", len(hist))
+	}
+}
@@ -0,0 +1,133 @@
+package router
+
+import (
+	"context"
+	"sync"
+)
+
+// workerLimiter counts a pool's busy workers against a limit that can
+// change at any time (UpdateConcurrency, adaptive concurrency). There is
+// one counter: every release decrements the counter its acquire
+// incremented, so after a resize the pool converges on the new limit as
+// soon as the work above it finishes, however often it is resized
+// meanwhile. Raising the limit admits waiters at once; lowering it admits
+// none until enough slots are released.
+//
+// HIGH-priority messages (Message.HighPriority) get first claim: a free
+// slot goes to the longest-waiting HIGH message before any other, so a
+// webhook flagged urgent doesn't queue behind every bulk message already
+// waiting. It only orders slot acquisition between messages that are
+// already dispatchable; it never reorders within a message group.
+type workerLimiter struct {
+	mu    sync.Mutex
+	limit int
+	inUse int
+	// high and normal are the waiters, oldest first; each channel is
+	// closed when granted a slot.
+	high, normal []chan struct{}
+}
+
+func newWorkerLimiter(limit uint32) *workerLimiter {
+	return &workerLimiter{limit: int(limit)}
+}
+
+// acquire takes a slot. Returns false, holding nothing, when ctx is done
+// first.
+func (l *workerLimiter) acquire(ctx context.Context, high bool) bool {
+	l.mu.Lock()
+	// A normal message may not take a free slot ahead of a waiting HIGH
+	// one; a HIGH message only queues behind other HIGH ones.
+	if l.inUse < l.limit && len(l.high) == 0 && (high || len(l.normal) == 0) {
+		l.inUse++
+		l.mu.Unlock()
+		return true
+	}
+	granted := make(chan struct{})
+	if high {
+		l.high = append(l.high, granted)
+	} else {
+		l.normal = append(l.normal, granted)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-granted:
+		return true
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-granted:
+		// Granted while we were giving up: hand the slot on.
+		l.inUse--
+		l.grantLocked()
+	default:
+		if high {
+			l.high = removeWaiter(l.high, granted)
+		} else {
+			l.normal = removeWaiter(l.normal, granted)
+		}
+	}
+	return false
+}
+
+// release returns a slot, passing it to the next waiter if the limit
+// allows.
+func (l *workerLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inUse--
+	l.grantLocked()
+}
+
+// setLimit changes the limit and returns the previous one.
+func (l *workerLimiter) setLimit(n uint32) uint32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	old := l.limit
+	l.limit = int(n)
+	l.grantLocked()
+	return uint32(old)
+}
+
+// capacity is the current limit.
+func (l *workerLimiter) capacity() uint32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return uint32(l.limit)
+}
+
+// inFlight is the number of slots held.
+func (l *workerLimiter) inFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inUse
+}
+
+// grantLocked admits waiters, HIGH first, while the limit allows. Caller
+// holds l.mu.
+func (l *workerLimiter) grantLocked() {
+	for l.inUse < l.limit {
+		var w chan struct{}
+		switch {
+		case len(l.high) > 0:
+			w, l.high = l.high[0], l.high[1:]
+		case len(l.normal) > 0:
+			w, l.normal = l.normal[0], l.normal[1:]
+		default:
+			return
+		}
+		l.inUse++
+		close(w)
+	}
+}
+
+func removeWaiter(ws []chan struct{}, w chan struct{}) []chan struct{} {
+	for i, x := range ws {
+		if x == w {
+			return append(ws[:i], ws[i+1:]...)
+		}
+	}
+	return ws
+}
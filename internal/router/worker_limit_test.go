@@ -0,0 +1,86 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerLimiter_HighJumpsWaitingNormal(t *testing.T) {
+	l := newWorkerLimiter(1)
+	assert.True(t, l.acquire(context.Background(), false)) // pool saturated
+
+	order := make(chan string, 2)
+	go func() {
+		if l.acquire(context.Background(), false) {
+			order <- "normal"
+		}
+	}()
+	time.Sleep(20 * time.Millisecond) // normal is queued first
+	go func() {
+		if l.acquire(context.Background(), true) {
+			order <- "high"
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	l.release() // free one slot
+	assert.Equal(t, "high", <-order)
+	l.release()
+	assert.Equal(t, "normal", <-order)
+}
+
+func TestWorkerLimiter_CancelledWaiterGivesUp(t *testing.T) {
+	l := newWorkerLimiter(1)
+	assert.True(t, l.acquire(context.Background(), false))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, l.acquire(ctx, true))
+	assert.False(t, l.acquire(ctx, false))
+
+	// The cancelled waiters must not hold or block the slot.
+	l.release()
+	assert.Equal(t, 0, l.inFlight())
+	assert.True(t, l.acquire(context.Background(), false))
+}
+
+// TestWorkerLimiter_ResizeDownThenReleaseDoesNotOverAdmit pins that a
+// release after a resize comes off the same count the acquire went on:
+// with 4 busy and the limit cut to 2, nothing starts until two finish,
+// and no more than 2 run after that.
+func TestWorkerLimiter_ResizeDownThenReleaseDoesNotOverAdmit(t *testing.T) {
+	l := newWorkerLimiter(4)
+	for range 4 {
+		assert.True(t, l.acquire(context.Background(), false))
+	}
+	assert.Equal(t, uint32(4), l.setLimit(2))
+
+	admitted := make(chan struct{}, 3)
+	for range 3 {
+		go func() {
+			if l.acquire(context.Background(), false) {
+				admitted <- struct{}{}
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	l.release()
+	l.release()
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, admitted, "no slot frees while 2 of the old 4 still run")
+
+	l.release()
+	<-admitted
+	l.release()
+	<-admitted
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, admitted)
+	assert.Equal(t, 2, l.inFlight())
+
+	l.setLimit(3) // raising the limit admits a waiter at once
+	<-admitted
+	assert.Equal(t, 3, l.inFlight())
+}
@@ -0,0 +1,148 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// SecretsManagerAPI is the slice of the Secrets Manager client the
+// provider uses; tests substitute a fake.
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, in *secretsmanager.GetSecretValueInput, opts ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager.
+//
+//	ref: aws-sm://name-or-arn
+//	ref: aws-sm://name-or-arn#field   (one key of a JSON secret)
+//
+// A secret is regional; when the reference is a full ARN the client for
+// the ARN's own region is used, so AWS_REGION doesn't have to match.
+// Credentials come from the default AWS chain (env, instance profile,
+// ECS task role, ...).
+type AWSSecretsManagerProvider struct {
+	newClient func(ctx context.Context, region string) (SecretsManagerAPI, error)
+
+	mu      sync.Mutex
+	clients map[string]SecretsManagerAPI // by region; "" = SDK default
+}
+
+// NewAWSSecretsManagerProvider builds the provider. Clients are created
+// on first use, so registering it costs nothing when no aws-sm://
+// reference is configured.
+func NewAWSSecretsManagerProvider() *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{
+		newClient: func(ctx context.Context, region string) (SecretsManagerAPI, error) {
+			var opts []func(*awsconfig.LoadOptions) error
+			if region != "" {
+				opts = append(opts, awsconfig.WithRegion(region))
+			}
+			cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("load AWS config: %w", err)
+			}
+			return secretsmanager.NewFromConfig(cfg), nil
+		},
+		clients: make(map[string]SecretsManagerAPI),
+	}
+}
+
+// NewAWSSecretsManagerProviderWithClient uses client for every region.
+func NewAWSSecretsManagerProviderWithClient(client SecretsManagerAPI) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{
+		newClient: func(context.Context, string) (SecretsManagerAPI, error) { return client, nil },
+		clients:   make(map[string]SecretsManagerAPI),
+	}
+}
+
+// Name returns the scheme.
+func (*AWSSecretsManagerProvider) Name() string { return "aws-sm" }
+
+// Get reads the secret's string value, or one field of it when the key
+// carries a #field suffix.
+func (p *AWSSecretsManagerProvider) Get(ctx context.Context, key string) (string, error) {
+	id, field, _ := strings.Cut(key, "#")
+	client, err := p.client(ctx, regionFromARN(id))
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &id})
+	if err != nil {
+		var nf *smtypes.ResourceNotFoundException
+		if errors.As(err, &nf) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("aws-sm get %s: %w", id, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("aws-sm secret %s has no string value", id)
+	}
+	if field == "" {
+		return *out.SecretString, nil
+	}
+	return jsonField(*out.SecretString, field, "aws-sm secret "+id)
+}
+
+func (p *AWSSecretsManagerProvider) client(ctx context.Context, region string) (SecretsManagerAPI, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[region]; ok {
+		return c, nil
+	}
+	c, err := p.newClient(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[region] = c
+	return c, nil
+}
+
+// Set is unsupported: secrets are managed in AWS, not written by the
+// platform.
+func (*AWSSecretsManagerProvider) Set(_ context.Context, _, _ string) error {
+	return errors.New("aws-sm provider is read-only")
+}
+
+// Delete is unsupported.
+func (*AWSSecretsManagerProvider) Delete(_ context.Context, key string) error {
+	return fmt.Errorf("aws-sm provider is read-only (cannot delete %q)", key)
+}
+
+// regionFromARN extracts REGION from arn:partition:service:REGION:...;
+// "" for a bare secret name.
+func regionFromARN(s string) string {
+	parts := strings.Split(s, ":")
+	if len(parts) < 4 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
+
+// jsonField returns one string (or number/bool, as text) field of a JSON
+// object secret.
+func jsonField(raw, field, what string) (string, error) {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+		return "", fmt.Errorf("%s is not a JSON object, so #%s can't be selected", what, field)
+	}
+	v, ok := obj[field]
+	if !ok || v == nil {
+		return "", fmt.Errorf("%s has no field %q: %w", what, field, ErrNotFound)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
 )
 
 // EnvProvider reads secrets from environment variables.
@@ -37,3 +40,28 @@ func (*EnvProvider) Set(_ context.Context, _, _ string) error {
 func (*EnvProvider) Delete(_ context.Context, key string) error {
 	return fmt.Errorf("env provider is read-only (cannot delete %q)", key)
 }
+
+// ServiceFromEnv builds the process-wide service: env:// and aws-sm://
+// references always resolve, vault:// ones when VAULT_ADDR is set.
+// Values are cached for FC_SECRETS_CACHE_TTL_SECS (default 300; 0
+// disables the cache).
+func ServiceFromEnv() (*Service, error) {
+	s := NewService("env")
+	s.Register(NewEnvProvider())
+	s.Register(NewAWSSecretsManagerProvider())
+	if vc := VaultConfigFromEnv(); vc.Addr != "" {
+		v, err := NewVaultProvider(vc)
+		if err != nil {
+			return nil, err
+		}
+		s.Register(v)
+	}
+	s.SetCacheTTL(time.Duration(envutil.Int("FC_SECRETS_CACHE_TTL_SECS", 300)) * time.Second)
+	return s, nil
+}
+
+// RefreshIntervalFromEnv is how often rotation callbacks are checked:
+// FC_SECRETS_REFRESH_INTERVAL_SECS (default 300; 0 disables).
+func RefreshIntervalFromEnv() time.Duration {
+	return time.Duration(envutil.Int("FC_SECRETS_REFRESH_INTERVAL_SECS", 300)) * time.Second
+}
@@ -3,17 +3,21 @@
 // Backends register themselves via Register. The SecretService routes
 // references (e.g. "aws-sm://my-secret") to the right backend.
 //
-// Mirrors the Rust fc-secrets crate. Phase 1 ships the in-process
-// backends (env, encrypted file). AWS Secrets Manager, AWS SSM, and
-// Vault land in Phase 1.5+ when the team picks the AWS SDK version.
+// Mirrors the Rust fc-secrets crate: the in-process backends (env,
+// encrypted file) plus AWS Secrets Manager and HashiCorp Vault (KV).
+// Resolved values are cached for a TTL, and callers that hold a secret
+// for the life of the process (the JWT signing key) register a rotation
+// callback that Refresh fires when the backend value changes.
 package secrets
 
 import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Provider is a single secrets backend.
@@ -40,12 +44,36 @@ type Service struct {
 	mu        sync.RWMutex
 	providers map[string]Provider
 	defaultP  string
+
+	ttl     time.Duration
+	now     func() time.Time
+	cacheMu sync.Mutex
+	cache   map[string]cachedSecret
+	watched map[string]*watchedSecret
+}
+
+type cachedSecret struct {
+	value   string
+	fetched time.Time
+}
+
+// watchedSecret is a reference with rotation callbacks and the value they
+// were last given.
+type watchedSecret struct {
+	current string
+	fns     []func(string)
 }
 
 // NewService constructs a service. defaultProvider is the name used
 // when a reference has no scheme.
 func NewService(defaultProvider string) *Service {
-	return &Service{providers: make(map[string]Provider), defaultP: defaultProvider}
+	return &Service{
+		providers: make(map[string]Provider),
+		defaultP:  defaultProvider,
+		now:       time.Now,
+		cache:     make(map[string]cachedSecret),
+		watched:   make(map[string]*watchedSecret),
+	}
 }
 
 // Register adds a provider keyed by its name. Names match the scheme
@@ -56,6 +84,25 @@ func (s *Service) Register(p Provider) {
 	s.providers[p.Name()] = p
 }
 
+// SetCacheTTL caches resolved values for ttl, so hot paths (an OIDC
+// login, an inbound webhook) don't call the backend on every request.
+// Zero, the default, disables the cache. Set once at startup.
+func (s *Service) SetCacheTTL(ttl time.Duration) { s.ttl = ttl }
+
+// Handles reports whether ref is a reference to a registered provider, as
+// opposed to a plain value that merely contains "://" (a connection
+// string, say).
+func (s *Service) Handles(ref string) bool {
+	scheme, _, isRef := parseRef(ref)
+	if !isRef {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.providers[scheme]
+	return ok
+}
+
 // Resolve takes a reference and returns the plaintext secret. Only
 // system processes should call this; admin/validation paths should use
 // Validate instead.
@@ -65,6 +112,27 @@ func (s *Service) Resolve(ctx context.Context, ref string) (string, error) {
 		// Literal or bare value; `key` is the unwrapped payload.
 		return key, nil
 	}
+	if s.ttl > 0 {
+		s.cacheMu.Lock()
+		c, ok := s.cache[ref]
+		s.cacheMu.Unlock()
+		if ok && s.now().Sub(c.fetched) < s.ttl {
+			return c.value, nil
+		}
+	}
+	v, err := s.fetch(ctx, scheme, key)
+	if err != nil {
+		return "", err
+	}
+	if s.ttl > 0 {
+		s.cacheMu.Lock()
+		s.cache[ref] = cachedSecret{value: v, fetched: s.now()}
+		s.cacheMu.Unlock()
+	}
+	return v, nil
+}
+
+func (s *Service) fetch(ctx context.Context, scheme, key string) (string, error) {
 	s.mu.RLock()
 	p, ok := s.providers[scheme]
 	s.mu.RUnlock()
@@ -74,6 +142,70 @@ func (s *Service) Resolve(ctx context.Context, ref string) (string, error) {
 	return p.Get(ctx, key)
 }
 
+// OnRotate registers fn to be called with the new value whenever Refresh
+// sees ref change from current (the value the caller is using now).
+func (s *Service) OnRotate(ref, current string, fn func(value string)) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	w, ok := s.watched[ref]
+	if !ok {
+		w = &watchedSecret{current: current}
+		s.watched[ref] = w
+	}
+	w.fns = append(w.fns, fn)
+}
+
+// Refresh re-reads every reference with a rotation callback straight from
+// its backend and fires the callbacks for those that changed. A failed
+// read is logged and leaves the cached value in place.
+func (s *Service) Refresh(ctx context.Context) {
+	s.cacheMu.Lock()
+	refs := make([]string, 0, len(s.watched))
+	for ref := range s.watched {
+		refs = append(refs, ref)
+	}
+	s.cacheMu.Unlock()
+
+	for _, ref := range refs {
+		scheme, key, _ := parseRef(ref)
+		v, err := s.fetch(ctx, scheme, key)
+		if err != nil {
+			slog.Warn("secret refresh failed; keeping the current value", "scheme", scheme, "err", err)
+			continue
+		}
+		s.cacheMu.Lock()
+		w := s.watched[ref]
+		changed := w.current != v
+		w.current = v
+		fns := append([]func(string){}, w.fns...)
+		if s.ttl > 0 {
+			s.cache[ref] = cachedSecret{value: v, fetched: s.now()}
+		}
+		s.cacheMu.Unlock()
+		if !changed {
+			continue
+		}
+		slog.Info("secret rotated", "scheme", scheme)
+		for _, fn := range fns {
+			fn(v)
+		}
+	}
+}
+
+// Run calls Refresh every interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.Refresh(ctx)
+		}
+	}
+}
+
 // Validate checks that a reference is resolvable without revealing
 // plaintext. Used in admin paths where the caller has permission to
 // validate but not to read.
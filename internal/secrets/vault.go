@@ -0,0 +1,165 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+)
+
+// VaultConfig configures the Vault provider. Token authentication only:
+// either a static token or a file a Vault Agent keeps renewed.
+type VaultConfig struct {
+	// Addr is the server URL (https://vault.example.com:8200).
+	Addr string
+	// Token is a static token. TokenFile, when set, is read on every
+	// request instead, so an agent-rotated token is always current.
+	Token     string
+	TokenFile string
+	// Namespace is the Vault Enterprise namespace; empty for none.
+	Namespace string
+	// KVVersion is the secrets-engine version, 1 or 2 (default).
+	KVVersion int
+	// Timeout bounds one request; zero means 10s.
+	Timeout time.Duration
+}
+
+// VaultConfigFromEnv reads the standard VAULT_ADDR, VAULT_TOKEN and
+// VAULT_NAMESPACE, plus FC_VAULT_TOKEN_FILE and FC_VAULT_KV_VERSION.
+func VaultConfigFromEnv() VaultConfig {
+	return VaultConfig{
+		Addr:      envutil.Or("VAULT_ADDR", ""),
+		Token:     envutil.Or("VAULT_TOKEN", ""),
+		TokenFile: envutil.Or("FC_VAULT_TOKEN_FILE", ""),
+		Namespace: envutil.Or("VAULT_NAMESPACE", ""),
+		KVVersion: envutil.Int("FC_VAULT_KV_VERSION", 2),
+	}
+}
+
+// VaultProvider reads secrets from a HashiCorp Vault KV engine over its
+// HTTP API.
+//
+//	ref: vault://mount/path/to/secret#field
+//
+// The first path segment is the engine mount; field defaults to "value".
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider validates cfg and builds the provider.
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("vault: address is required")
+	}
+	if cfg.Token == "" && cfg.TokenFile == "" {
+		return nil, errors.New("vault: a token or token file is required")
+	}
+	if cfg.KVVersion == 0 {
+		cfg.KVVersion = 2
+	}
+	if cfg.KVVersion != 1 && cfg.KVVersion != 2 {
+		return nil, fmt.Errorf("vault: KV version %d: want 1 or 2", cfg.KVVersion)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	cfg.Addr = strings.TrimRight(cfg.Addr, "/")
+	return &VaultProvider{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// Name returns the scheme.
+func (*VaultProvider) Name() string { return "vault" }
+
+// Get reads one field of a KV secret.
+func (p *VaultProvider) Get(ctx context.Context, key string) (string, error) {
+	path, field, _ := strings.Cut(key, "#")
+	if field == "" {
+		field = "value"
+	}
+	mount, rest, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || rest == "" {
+		return "", fmt.Errorf("vault reference %q: want mount/path#field", key)
+	}
+	endpoint := p.cfg.Addr + "/v1/" + url.PathEscape(mount) + "/"
+	if p.cfg.KVVersion == 2 {
+		endpoint += "data/"
+	}
+	endpoint += rest
+
+	token, err := p.token()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault get %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("vault get %s: %w", path, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("vault get %s: HTTP %d", path, resp.StatusCode)
+	}
+
+	var out struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("vault get %s: decode: %w", path, err)
+	}
+	data := out.Data
+	if p.cfg.KVVersion == 2 {
+		var v2 struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return "", fmt.Errorf("vault get %s: decode: %w", path, err)
+		}
+		data = v2.Data
+	}
+	return jsonField(string(data), field, "vault secret "+path)
+}
+
+func (p *VaultProvider) token() (string, error) {
+	if p.cfg.TokenFile == "" {
+		return p.cfg.Token, nil
+	}
+	b, err := os.ReadFile(p.cfg.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("vault token file: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Set is unsupported: secrets are managed in Vault, not written by the
+// platform.
+func (*VaultProvider) Set(_ context.Context, _, _ string) error {
+	return errors.New("vault provider is read-only")
+}
+
+// Delete is unsupported.
+func (*VaultProvider) Delete(_ context.Context, key string) error {
+	return fmt.Errorf("vault provider is read-only (cannot delete %q)", key)
+}
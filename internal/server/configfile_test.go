@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "fc.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(body), 0o600))
+	return p
+}
+
+func TestReadConfigFile(t *testing.T) {
+	f, err := ReadConfigFile(writeConfig(t, `
+FC_API_PORT: 8081
+FC_ROUTER_ENABLED: true
+FC_WEBAUTHN_ORIGINS:
+  - https://a.example.com
+  - https://b.example.com
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"FC_API_PORT":         "8081",
+		"FC_ROUTER_ENABLED":   "true",
+		"FC_WEBAUTHN_ORIGINS": "https://a.example.com,https://b.example.com",
+	}, f.Values)
+}
+
+func TestReadConfigFile_ReportsEveryProblem(t *testing.T) {
+	_, err := ReadConfigFile(writeConfig(t, `
+FC_API_PROT: 8081
+FC_ROUTER_ENABLED:
+FC_MONGO_MAX_POOL_SIZE:
+  nested: 1
+FC_API_PORT: 1
+FC_API_PORT: 2
+`))
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, `line 2: unknown key "FC_API_PROT" (did you mean FC_API_PORT?)`)
+	assert.Contains(t, msg, "line 3: FC_ROUTER_ENABLED has no value")
+	assert.Contains(t, msg, "FC_MONGO_MAX_POOL_SIZE must be a value or a list of values")
+	assert.Contains(t, msg, "line 7: FC_API_PORT is set twice")
+
+	_, err = ReadConfigFile(writeConfig(t, "- FC_API_PORT\n"))
+	assert.ErrorContains(t, err, "top level must be a map")
+}
+
+func TestConfigFile_EnvWins(t *testing.T) {
+	t.Setenv("FC_API_PORT", "9000")
+	t.Setenv("FC_METRICS_PORT", "")
+	f, err := ReadConfigFile(writeConfig(t, "FC_API_PORT: 8081\nFC_METRICS_PORT: 9191\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Apply())
+
+	cfg := LoadEnv()
+	assert.Equal(t, 9000, cfg.APIPort)
+	assert.Equal(t, 9191, cfg.MetricsPort)
+	assert.False(t, f.applied["FC_API_PORT"])
+	assert.True(t, f.applied["FC_METRICS_PORT"])
+}
+
+func TestConfigFile_Reload(t *testing.T) {
+	t.Setenv("FC_API_PORT", "9000")
+	t.Setenv("FC_METRICS_PORT", "")
+	t.Setenv("FC_LOG_LEVEL", "")
+	path := writeConfig(t, "FC_API_PORT: 8081\nFC_METRICS_PORT: 9191\nFC_LOG_LEVEL: debug\n")
+	f, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Apply())
+
+	require.NoError(t, os.WriteFile(path, []byte("FC_API_PORT: 8082\nFC_METRICS_PORT: 9292\n"), 0o600))
+	require.NoError(t, f.Reload())
+	assert.Equal(t, "9000", os.Getenv("FC_API_PORT"), "env still wins")
+	assert.Equal(t, "9292", os.Getenv("FC_METRICS_PORT"), "file value updated")
+	assert.Empty(t, os.Getenv("FC_LOG_LEVEL"), "dropped from the file")
+
+	require.NoError(t, os.WriteFile(path, []byte("FC_METRICS_PROT: 1\n"), 0o600))
+	require.Error(t, f.Reload())
+	assert.Equal(t, "9292", os.Getenv("FC_METRICS_PORT"), "a bad file changes nothing")
+}
+
+func TestEnvCfgValidate(t *testing.T) {
+	cfg := EnvCfg{APIPort: 8080, MetricsPort: 9090}
+	require.NoError(t, cfg.Validate())
+
+	cfg.StandbyEnabled, cfg.StandbyBackend, cfg.Region = true, "mongo", "eu"
+	cfg.OutboxEnabled, cfg.OutboxBackend = true, "mongo"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FC_STANDBY_MONGO_URI is required when FC_STANDBY_BACKEND=mongo")
+	assert.Contains(t, err.Error(), "FC_STANDBY_MONGO_URI is required when FC_REGION is set")
+	assert.Contains(t, err.Error(), "FC_OUTBOX_MONGO_URI is required")
+}
+
+func TestPrintConfig_Redacts(t *testing.T) {
+	t.Setenv("FC_DATABASE_URL", "postgresql://fc:hunter2@db:5432/fc")
+	t.Setenv("FC_SMTP_PASSWORD", "hunter2")
+	t.Setenv("DB_SECRET_ARN", "arn:aws:secretsmanager:eu-west-1:1:secret:db")
+	cfg := LoadEnv()
+
+	var buf bytes.Buffer
+	require.NoError(t, PrintConfig(&buf, cfg, nil))
+	out := buf.String()
+	assert.NotContains(t, out, "hunter2")
+	assert.Contains(t, out, "postgresql://fc:<redacted>@db:5432/fc")
+	assert.Contains(t, out, "arn:aws:secretsmanager:eu-west-1:1:secret:db")
+
+	var parsed map[string]map[string]any
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &parsed), "output is valid YAML")
+	assert.Equal(t, "<redacted>", parsed["variables"]["FC_SMTP_PASSWORD"])
+	assert.Equal(t, 8080, parsed["resolved"]["APIPort"])
+}
+
+// TestConfigVars_MatchDocs keeps configVars in step with the reference:
+// every variable (and alias) in sections 1–11 of the doc, except the
+// fc-dev- and SDK-only rows, must be accepted in a config file, and
+// nothing else.
+func TestConfigVars_MatchDocs(t *testing.T) {
+	fh, err := os.Open("../../docs/environment-variables.md")
+	require.NoError(t, err)
+	defer fh.Close()
+
+	name := regexp.MustCompile("`([A-Z][A-Z0-9_]+)`")
+	documented := map[string]bool{}
+	sc := bufio.NewScanner(fh)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "## 12.") {
+			break
+		}
+		cols := strings.Split(line, "|")
+		if !strings.HasPrefix(line, "| `") || len(cols) < 5 {
+			continue
+		}
+		if readIn := strings.TrimSpace(cols[4]); strings.HasPrefix(readIn, "`cmd/fc-dev") || strings.HasPrefix(readIn, "`pkg/") {
+			continue
+		}
+		for _, m := range name.FindAllStringSubmatch(cols[1]+cols[3], -1) {
+			documented[m[1]] = true
+		}
+	}
+	require.NoError(t, sc.Err())
+	delete(documented, "FC_CONFIG_FILE") // names the file; can't be set inside it
+
+	for v := range documented {
+		assert.True(t, configVars[v], "%s is documented but not in configVars", v)
+	}
+	for v := range configVars {
+		assert.True(t, documented[v], "%s is in configVars but not documented", v)
+	}
+}
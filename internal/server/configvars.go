@@ -0,0 +1,128 @@
+package server
+
+import "strings"
+
+// configVars is every variable fc-server reads, grouped like
+// docs/environment-variables.md (sections 1–11). A config file may only
+// set these; TestConfigVars_MatchDocs keeps the two lists in step.
+var configVars = func() map[string]bool {
+	m := map[string]bool{}
+	for _, group := range []string{
+		// Core server & subsystem toggles
+		`FC_API_PORT PORT FC_METRICS_PORT FC_PLATFORM_ENABLED PLATFORM_ENABLED FC_ROUTER_ENABLED
+			MESSAGE_ROUTER_ENABLED FC_SCHEDULER_ENABLED DISPATCH_SCHEDULER_ENABLED
+			FC_SCHEDULED_JOB_ENABLED SCHEDULED_JOB_SCHEDULER_ENABLED FC_STREAM_PROCESSOR_ENABLED
+			STREAM_PROCESSOR_ENABLED FC_OUTBOX_ENABLED OUTBOX_PROCESSOR_ENABLED FC_MCP_ENABLED
+			FC_DEFAULT_BROKER`,
+		// Database & AWS Secrets Manager
+		`FC_DATABASE_URL DATABASE_URL DB_HOST DB_PORT DB_NAME DB_USERNAME DB_PASSWORD DB_SECRET_ARN
+			DB_SECRET_PROVIDER DB_SECRET_REFRESH_INTERVAL_MS`,
+		// Auth & JWT
+		`FC_JWT_ISSUER FC_EXTERNAL_BASE_URL EXTERNAL_BASE_URL FC_JWT_SIGNING_KEY_PATH
+			FLOWCATALYST_JWT_PRIVATE_KEY FC_JWT_SIGNING_KEY_PEM FC_JWT_SIGNING_KEY_SECRET_ARN FC_JWT_SIGNING_KEY_REF
+			FLOWCATALYST_JWT_PREVIOUS_PUBLIC_KEY FC_JWT_KEY_ROTATION_DAYS
+			FC_JWT_KEY_PUBLISH_AHEAD_SECS FC_JWT_KEY_OVERLAP_SECS FC_JWT_KEY_REFRESH_SECS
+			FC_SESSION_REVOCATION_CACHE_SIZE FC_SESSION_REVOCATION_CACHE_TTL_SECS
+			FC_PRINCIPAL_VERSION_CACHE_SIZE FC_PRINCIPAL_VERSION_CACHE_TTL_SECS AUTH_MODE
+			FC_ROUTER_AUTH_USER AUTH_BASIC_USERNAME FC_ROUTER_AUTH_PASS AUTH_BASIC_PASSWORD
+			FC_AUTH_ALLOW_TEST_HEADERS`,
+		// Encryption & secrets
+		`FLOWCATALYST_APP_KEY FLOWCATALYST_APP_KEY_PREVIOUS FC_ENCRYPTION_KMS_KEY_ID
+			FC_ENCRYPTION_REWRITE_ON_READ FC_SECRETS_CACHE_TTL_SECS
+			FC_SECRETS_REFRESH_INTERVAL_SECS VAULT_ADDR VAULT_TOKEN FC_VAULT_TOKEN_FILE VAULT_NAMESPACE
+			FC_VAULT_KV_VERSION`,
+		// Rate limiting
+		`FC_RL_OAUTH_TOKEN_IP_PER_MIN FC_RL_OAUTH_TOKEN_CLIENT_PER_MIN
+			FC_RL_OAUTH_AUTHORIZE_IP_PER_MIN FC_RL_OAUTH_AUTHORIZE_CLIENT_PER_MIN
+			FC_RL_PASSWORD_RESET_IP_PER_HOUR FC_RL_PASSWORD_RESET_EMAIL_PER_HOUR
+			FC_RL_DEVICE_VERIFY_IP_PER_MIN FC_RL_DEVICE_VERIFY_PRINCIPAL_PER_MIN
+			FC_OAUTH_TOKEN_IP_RATE_PER_MIN FC_OAUTH_TOKEN_IP_BURST FC_OAUTH_TOKEN_CLIENT_RATE_PER_MIN
+			FC_OAUTH_TOKEN_CLIENT_BURST FC_OIDC_RATE_PER_MIN FC_OIDC_BURST FC_RATE_LIMIT_DISABLE
+			FC_REDIS_URL`,
+		// Login backoff and password policy
+		`FC_LOGIN_BACKOFF_FREE_ATTEMPTS FC_LOGIN_BACKOFF_BASE_SECS FC_LOGIN_BACKOFF_MAX_SECS
+			FC_LOGIN_GLOBAL_WINDOW_SECS FC_LOGIN_GLOBAL_CEILING FC_LOGIN_GLOBAL_LOCK_SECS
+			FC_LOGIN_IP_WINDOW_SECS FC_LOGIN_IP_CEILING FC_LOGIN_IP_LOCK_SECS FC_PASSWORD_MIN_LENGTH
+			FC_PASSWORD_REQUIRE_UPPERCASE FC_PASSWORD_REQUIRE_LOWERCASE FC_PASSWORD_REQUIRE_DIGIT
+			FC_PASSWORD_REQUIRE_SPECIAL FC_PASSWORD_MAX_AGE_DAYS FC_API_ACTIVITY_ENABLED
+			FC_API_ACTIVITY_SAMPLE_PERCENT FC_API_ACTIVITY_RETENTION_DAYS FC_API_ACTIVITY_BUFFER
+			FC_METERING_ENABLED FC_METERING_DEFAULT_MONTHLY_EVENTS
+			FC_METERING_DEFAULT_MONTHLY_DELIVERIES FC_METERING_WARN_PERCENT FC_METERING_CACHE_TTL_SECS
+			FC_MAINTENANCE_MODE FC_MAINTENANCE_RETRY_AFTER_SECS FC_FILTER_OPTIONS_CACHE_TTL_SECS
+			FC_EVENT_DEDUP_WINDOW_SECS
+			FC_PAYLOAD_MAX_BYTES FC_PAYLOAD_MAX_BYTES_BY_CLIENT FC_PAYLOAD_MAX_BYTES_BY_EVENT_TYPE
+			FC_PAYLOAD_OFFLOAD_DESTINATION FC_PAYLOAD_OFFLOAD_BYTES FC_PAYLOAD_CLAIM_CHECK
+			FC_PAYLOAD_OFFLOAD_ENDPOINT FC_PAYLOAD_OFFLOAD_REGION FC_PAYLOAD_OFFLOAD_ACCESS_KEY_ID
+			FC_PAYLOAD_OFFLOAD_SECRET_ACCESS_KEY FC_EXPORT_DESTINATION FC_EXPORT_ENDPOINT
+			FC_EXPORT_REGION FC_EXPORT_ACCESS_KEY_ID FC_EXPORT_SECRET_ACCESS_KEY
+			FC_EXPORT_URL_TTL_SECS FC_PRIVACY_SUBJECT_PATHS FC_PRIVACY_PURGE_BATCH_SIZE
+			FC_INGEST_POLL_INTERVAL_MS FC_INGEST_MAX_ATTEMPTS FC_DISPATCH_CALLBACK_SIGNING_SECRET
+			FC_DISPATCH_CALLBACK_MAX_ATTEMPTS FC_DISPATCH_CALLBACK_RATE_PER_HOST
+			FC_DISPATCH_CALLBACK_POLL_INTERVAL_MS FC_BFF_REDACTION_RULES FC_DISPATCH_STREAM_CHUNK_SIZE
+			FC_DISPATCH_STREAM_MAX_LINE_BYTES FC_DISPATCH_STREAM_MAX_CONCURRENT
+			FC_SCHEMA_REGISTRY_KIND FC_SCHEMA_REGISTRY_URL FC_SCHEMA_REGISTRY_USERNAME
+			FC_SCHEMA_REGISTRY_PASSWORD FC_SCHEMA_REGISTRY_GROUP FC_SCHEMA_REGISTRY_SYNC_INTERVAL_SECS
+			FC_SCHEMA_REGISTRY_MAX_ATTEMPTS`,
+		// Email / SMTP
+		`FC_SMTP_HOST SMTP_HOST FC_SMTP_PORT SMTP_PORT FC_SMTP_USERNAME SMTP_USERNAME
+			FC_SMTP_PASSWORD SMTP_PASSWORD FC_SMTP_FROM SMTP_FROM FC_SMTP_SECURE SMTP_SECURE
+			FC_EMAIL_PROVIDER FC_SES_REGION AWS_REGION FC_EMAIL_FROM FC_SES_ENDPOINT`,
+		// WebAuthn (passkeys)
+		`FC_WEBAUTHN_RP_ID FC_WEBAUTHN_ORIGINS FC_WEBAUTHN_RP_ORIGIN`,
+		// Subsystems
+		`FC_ROUTER_HTTP_PREFIX FC_DRAIN_TIMEOUT_SECONDS FLOWCATALYST_DEV_MODE
+			FC_ROUTER_TIMEOUT_SECONDS FC_ROUTER_CONNECT_TIMEOUT_SECONDS
+			FC_ROUTER_MAX_IDLE_CONNS_PER_HOST FC_ROUTER_MAX_RETRY_AFTER_SECONDS FC_ROUTER_HTTP_VERSION
+			FC_ROUTER_TLS_CA_FILE FC_ROUTER_TLS_CLIENT_CERT_FILE FC_ROUTER_TLS_CLIENT_KEY_FILE
+			FC_ROUTER_FAULTS FC_ROUTER_MAX_IN_FLIGHT FC_ROUTER_OVERLOAD_POLICY FC_ROUTER_RETRY_BUDGET
+			FC_ROUTER_RETRY_BUDGET_MIN_PER_SECOND FC_OUTBOX_PLATFORM_URL FC_OUTBOX_API_URL FC_API_BASE_URL FLOWCATALYST_URL
+			FC_OUTBOX_PLATFORM_AUTH_TOKEN FC_OUTBOX_TOKEN FC_API_TOKEN FC_OUTBOX_BATCH_SIZE
+			FC_OUTBOX_MAX_IN_FLIGHT FC_OUTBOX_POLL_INTERVAL_MS FC_OUTBOX_MAX_CONCURRENT_GROUPS
+			FC_MAX_CONCURRENT_GROUPS FC_OUTBOX_BLOCK_ON_ERROR FC_OUTBOX_ADMIN_PORT FC_OUTBOX_BACKEND
+			FC_OUTBOX_DB_TYPE FC_OUTBOX_MONGO_URI FC_OUTBOX_DB_URL FC_OUTBOX_MONGO_DB FC_OUTBOX_SOURCES
+			FC_OUTBOX_QUEUE_URL
+			FC_STREAM_EVENTS_ENABLED FC_STREAM_DISPATCH_JOBS_ENABLED FC_STREAM_FAN_OUT_ENABLED
+			FC_STREAM_PARTITION_MANAGER_ENABLED FC_STREAM_PARTITIONS_ENABLED FC_STREAM_BATCH_SIZE
+			FC_STREAM_EVENTS_BATCH_SIZE FC_STREAM_DISPATCH_JOBS_BATCH_SIZE
+			FC_STREAM_FAN_OUT_BATCH_SIZE FC_STREAM_FAN_OUT_SUBS_REFRESH_SECS
+			FC_STREAM_PARTITION_MONTHS_FORWARD FC_STREAM_PARTITION_RETENTION_DAYS
+			FC_STREAM_PARTITION_TICK_HOURS FC_STREAM_PROJECTIONS FC_STREAM_DISABLED_PROJECTIONS
+			FC_SCHEDULER_QUEUE_URL
+			FC_SCHEDULER_QUEUE_CONTENT_BASED_DEDUP FC_SCHEDULER_QUEUE_COMPRESSION
+			FC_SCHEDULER_QUEUE_LARGE_PAYLOAD_BUCKET
+			FC_SCHEDULER_QUEUE_ROUTES FC_SCHEDULER_MAX_PUBLISH_PER_SEC FC_SCHEDULER_CATCH_UP
+			FC_SCHEDULER_SKIP_OLDER_THAN_SECS FC_DISPATCH_PROCESSING_ENDPOINT FC_DISPATCH_RETRY_BASE_SECONDS
+			FC_DISPATCH_RETRY_MAX_SECONDS FC_EGRESS_ALLOW_PRIVATE FC_EGRESS_ALLOWLIST
+			FC_EGRESS_ALLOWLIST_ONLY FC_EGRESS_DENYLIST FC_EGRESS_PROXY_URL FC_EGRESS_NO_PROXY
+			FC_EGRESS_PROXIES FC_EGRESS_SOURCE_ADDRS FC_EGRESS_PUBLIC_IPS FC_SCHEDULED_JOB_POLL_SECONDS
+			FC_SCHEDULED_JOB_DISPATCH_SECONDS FC_SCHEDULED_JOB_DISPATCH_BATCH
+			FC_SCHEDULED_JOB_HTTP_TIMEOUT_SECONDS FC_STANDBY_ENABLED STANDBY_ENABLED
+			FC_STANDBY_BACKEND FC_STANDBY_REDIS_URL REDIS_URL FC_STANDBY_MONGO_URI FC_STANDBY_MONGO_DB
+			FC_STANDBY_LOCK_KEY FC_REGION FC_FAILOVER_INITIAL_REGION FC_FAILOVER_HANDOVER_SECONDS
+			FC_MONGO_MAX_POOL_SIZE FC_MONGO_MIN_POOL_SIZE FC_MONGO_MAX_CONN_IDLE_MS
+			FC_MONGO_CONNECT_TIMEOUT_MS FC_MONGO_SOCKET_TIMEOUT_MS
+			FC_MONGO_SERVER_SELECTION_TIMEOUT_MS FC_MONGO_READ_PREFERENCE FC_MONGO_WRITE_CONCERN
+			FC_MONGO_COMPRESSORS FC_MCP_PORT FC_MCP_BIND FC_MCP_PLATFORM_URL FLOWCATALYST_CLIENT_ID
+			FLOWCATALYST_CLIENT_SECRET`,
+		// Bootstrap & seeding
+		`FLOWCATALYST_BOOTSTRAP_ADMIN_EMAIL FLOWCATALYST_BOOTSTRAP_ADMIN_PASSWORD
+			FLOWCATALYST_BOOTSTRAP_ADMIN_NAME`,
+		// Logging, router config & misc
+		`FC_LOG_LEVEL FLOWCATALYST_CONFIG_URL FC_NOTIFY_WEBHOOK_URL
+			FC_ROUTER_QUEUE_STATS_INTERVAL_SECONDS FC_ROUTER_CONFIG_SYNC_SECONDS FC_ROUTER_SLOS
+			FC_ALERT_WEBHOOK_URL FC_ALERT_SLACK_WEBHOOK_URL FC_ALERT_EMAIL_TO FC_ROUTER_WARNINGS_MONGO_URI
+			FC_ROUTER_WARNINGS_MONGO_DB FC_ROUTER_WARNING_RETENTION_DAYS FC_ROUTER_POISON_MONGO_URI
+			FC_ROUTER_POISON_MONGO_DB FC_ROUTER_POISON_RETENTION_DAYS FC_ROUTER_CAPTURE_SAMPLE_PERCENT
+			FC_ROUTER_CAPTURE_MONGO_URI FC_ROUTER_CAPTURE_MONGO_DB FC_ROUTER_CAPTURE_MAX_MB
+			FC_ROUTER_PIPELINE_MONGO_URI
+			FC_ROUTER_PIPELINE_MONGO_DB FC_ROUTER_PIPELINE_INSTANCE_ID FC_ROUTER_SHARDING_ENABLED
+			FC_ROUTER_SHARDS FC_ROUTER_SHARD_MONGO_URI FC_ROUTER_SHARD_MONGO_DB
+			FC_ROUTER_SHARD_LEASE_SECONDS FC_ALB_ENABLED FC_ALB_TARGET_GROUP_ARN FC_ALB_TARGET_ID
+			FC_ALB_INSTANCE_IP FC_ALB_TARGET_PORT FC_ALB_REGION FC_ALB_DEREGISTRATION_DELAY_SECONDS`,
+	} {
+		for _, v := range strings.Fields(group) {
+			m[v] = true
+		}
+	}
+	return m
+}()
@@ -1,11 +1,15 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/secrets"
 )
 
 // EnvCfg captures every env-driven knob fc-server reads. Mirrors the
@@ -45,6 +49,45 @@ type EnvCfg struct {
 	// webhook delivery + status transitions (POST /api/dispatch/process).
 	// Empty → derived from the local API listener at load time.
 	DispatchProcessingEndpoint string
+	// DispatchRetryBaseSec / DispatchRetryMaxSec shape the backoff the
+	// processing callback writes to scheduled_for after a failed attempt:
+	// base·2^(attempt-1) capped at max (exponential jobs), base for fixed
+	// jobs, ±20% jitter. Defaults 5s / 120s.
+	DispatchRetryBaseSec int
+	DispatchRetryMaxSec  int
+
+	// SchedulerQueueURL is the queue the scheduler publishes dispatch jobs
+	// to — in production the SQS queue URL the router consumes. A ".fifo"
+	// URL gets per-message-group ordering and dedup. Empty falls back to the
+	// built-in Postgres broker when DefaultBroker=postgres.
+	SchedulerQueueURL string
+	// SchedulerQueueContentBasedDedup must match the FIFO queue's
+	// ContentBasedDeduplication attribute: when true the scheduler leaves
+	// MessageDeduplicationId to SQS instead of sending its own.
+	SchedulerQueueContentBasedDedup bool
+	// SchedulerQueueRoutes sends the jobs of specific dispatch pools to
+	// their own queues: "POOL=queue-url;POOL2=queue-url". Pools not listed
+	// use SchedulerQueueURL. Each routed queue needs a matching entry in
+	// the router's queue config.
+	SchedulerQueueRoutes string
+	// SchedulerQueueCompression compresses published message bodies
+	// ("gzip" or "zstd") on the scheduler's SQS / NATS queues. Routers
+	// decompress whatever a message is tagged with, so roll routers out
+	// first and then turn this on.
+	SchedulerQueueCompression string
+	// SchedulerQueueLargePayloadBucket is the S3 bucket the scheduler's
+	// SQS publishers offload messages over 256 KiB to (AWS Extended
+	// Client format). Empty sends every message inline.
+	SchedulerQueueLargePayloadBucket string
+	// SchedulerMaxPublishPerSec caps how many dispatch jobs the scheduler
+	// publishes per second; 0 is uncapped.
+	SchedulerMaxPublishPerSec int
+	// SchedulerCatchUp is the claim order while the scheduler is behind:
+	// OLDEST_FIRST (default) or NEWEST_FIRST.
+	SchedulerCatchUp string
+	// SchedulerSkipOlderThanSec expires PENDING jobs due for longer than
+	// this instead of dispatching them; 0 never does.
+	SchedulerSkipOlderThanSec int
 
 	// MCPPort is the listener for the MCP subsystem. Default 8090.
 	MCPPort int
@@ -61,6 +104,12 @@ type EnvCfg struct {
 	StreamFanOutEnabled       bool
 	StreamPartitionsEnabled   bool
 	StreamBatchSize           int
+	// StreamProjections registers extra projections (FC_STREAM_PROJECTIONS,
+	// a JSON array; see stream.ParseProjections).
+	StreamProjections string
+	// StreamDisabledProjections names registered projections not to run
+	// (FC_STREAM_DISABLED_PROJECTIONS, comma-separated).
+	StreamDisabledProjections string
 	// Fan-out subscription cache TTL in seconds (Rust
 	// FC_STREAM_FAN_OUT_SUBS_REFRESH_SECS; 0 = use the 5s default).
 	StreamFanOutSubsRefreshSecs int
@@ -90,6 +139,13 @@ type EnvCfg struct {
 	OutboxBackend  string
 	OutboxMongoURI string
 	OutboxMongoDB  string
+	// OutboxSources lists extra tenant outbox databases polled beside the
+	// default one (FC_OUTBOX_SOURCES, a JSON array; see outbox.ParseSources).
+	OutboxSources string
+	// OutboxQueueURL switches on direct queue mode: dispatch jobs are
+	// published as message pointers to this queue (the router's) instead
+	// of posted to the platform. Empty = every item goes over HTTP.
+	OutboxQueueURL string
 
 	// Router — used when FC_ROUTER_ENABLED=true. Mirrors the env vars
 	// the standalone cmd/fc-router binary reads.
@@ -97,6 +153,79 @@ type EnvCfg struct {
 	RouterDevMode          bool
 	RouterNotifyWebhookURL string
 	RouterDrainTimeoutSec  int
+	// RouterQueueStatsIntervalSec is the broker queue-depth refresh
+	// cadence (router.ServerConfig.BrokerStatsInterval).
+	RouterQueueStatsIntervalSec int
+	// RouterConfigSyncSec is how often FLOWCATALYST_CONFIG_URL is
+	// re-fetched (router.ServerConfig.ConfigPollInterval); 0 = 300.
+	RouterConfigSyncSec int
+
+	// Router delivery-latency SLOs (FC_ROUTER_SLOS, parsed by
+	// router.ParseSLOs) and the CRITICAL-only alert sinks.
+	RouterSLOs                 string
+	RouterAlertWebhookURL      string
+	RouterAlertSlackWebhookURL string
+	// RouterAlertEmailTo is the comma-separated FC_ALERT_EMAIL_TO list.
+	RouterAlertEmailTo string
+
+	// Router warning history (router.MongoWarningStore). Empty URI keeps
+	// warnings in memory only.
+	RouterWarningsMongoURI     string
+	RouterWarningsMongoDB      string
+	RouterWarningRetentionDays int
+
+	// Router poison-message quarantine (router.MongoPoisonStore). Empty
+	// URI keeps quarantined messages in memory only.
+	RouterPoisonMongoURI      string
+	RouterPoisonMongoDB       string
+	RouterPoisonRetentionDays int
+
+	// Router traffic capture (router.TrafficCapture): the percentage of
+	// outbound mediation requests recorded, 0 = off. Empty URI keeps
+	// captures in memory only; RouterCaptureMaxMB sizes the capped
+	// collection.
+	RouterCaptureSamplePercent float64
+	RouterCaptureMongoURI      string
+	RouterCaptureMongoDB       string
+	RouterCaptureMaxMB         int
+
+	// Router pipeline persistence (router.PipelinePersister). Empty URI
+	// leaves in-flight state to broker redelivery across restarts.
+	RouterPipelineMongoURI   string
+	RouterPipelineMongoDB    string
+	RouterPipelineInstanceID string
+
+	// Sharded router consumption (router.ShardCoordinator): every router
+	// consumes and ordered message groups are partitioned between them,
+	// coordinated through MongoDB. Replaces leader election for the router.
+	RouterShardingEnabled  bool
+	RouterShards           int
+	RouterShardMongoURI    string
+	RouterShardMongoDB     string
+	RouterShardLeaseTTLSec int
+
+	// Router mediator overrides. Zero / empty keeps the DevMode default.
+	// RouterHTTPVersion is "1" or "2"; the TLS files add a private CA and
+	// an mTLS client certificate for the outbound delivery calls.
+	RouterTimeoutSec          int
+	RouterConnectTimeoutSec   int
+	RouterMaxIdleConnsPerHost int
+	RouterMaxRetryAfterSec    int
+	RouterHTTPVersion         string
+	RouterTLSCAFile           string
+	RouterTLSClientCertFile   string
+	RouterTLSClientKeyFile    string
+	// RouterFaults is the mediator fault-injection spec
+	// (router.ParseFaultRules). Dev mode only.
+	RouterFaults string
+	// RouterMaxInFlight caps the mediator's concurrent delivery requests;
+	// RouterOverloadPolicy (QUEUE or SHED) is what a delivery at the cap
+	// does. RouterRetryBudget is the largest share of requests that may
+	// be retries.
+	RouterMaxInFlight             int
+	RouterOverloadPolicy          string
+	RouterRetryBudget             float64
+	RouterRetryBudgetMinPerSecond int
 
 	// ALB self-registration (router). When ALBEnabled, the router registers
 	// this instance's IP with the target group on leader-gain (or non-standby
@@ -108,13 +237,31 @@ type EnvCfg struct {
 	ALBRegion         string
 	ALBDeregDelaySec  int
 
-	// Standby / HA.
+	// Standby / HA. StandbyBackend selects the lease store ("redis" or
+	// "mongo"); the URL for the other backend is ignored.
 	StandbyEnabled  bool
+	StandbyBackend  string
 	StandbyRedisURL string
+	StandbyMongoURI string
+	StandbyMongoDB  string
 	StandbyLockKey  string
-
-	// JWT signing.
+	// Region puts every election of this instance behind the multi-region
+	// handover record (FC_STANDBY_MONGO_URI): only the active region leads.
+	// FailoverInitialRegion seeds the record; FailoverHandoverSec is the
+	// wait between a promotion and the new region serving.
+	Region                string
+	FailoverInitialRegion string
+	FailoverHandoverSec   int
+
+	// Mongo is the client pool/timeout config shared by every MongoDB
+	// connection (FC_MONGO_*).
+	Mongo mongoconn.Config
+
+	// JWT signing. JWTSigningKeyRef is a secret reference (vault://…,
+	// aws-sm://…) to the PEM; it takes precedence over every other source
+	// and is followed when the secret rotates.
 	JWTSigningKeyPath string
+	JWTSigningKeyRef  string
 	// JWTPreviousPublicKey is the validation-only previous public key for
 	// zero-downtime key rotation (FLOWCATALYST_JWT_PREVIOUS_PUBLIC_KEY),
 	// PEM-normalized; empty when unset or not a real PEM — it's optional,
@@ -164,6 +311,8 @@ func LoadEnv() EnvCfg {
 		// FC_STREAM_PARTITIONS_ENABLED stays as a back-compat alias.
 		StreamPartitionsEnabled:      envBoolAlias("FC_STREAM_PARTITION_MANAGER_ENABLED", "FC_STREAM_PARTITIONS_ENABLED", true),
 		StreamBatchSize:              envInt("FC_STREAM_BATCH_SIZE", 0),
+		StreamProjections:            os.Getenv("FC_STREAM_PROJECTIONS"),
+		StreamDisabledProjections:    os.Getenv("FC_STREAM_DISABLED_PROJECTIONS"),
 		StreamFanOutSubsRefreshSecs:  envInt("FC_STREAM_FAN_OUT_SUBS_REFRESH_SECS", 0),
 		StreamPartitionMonthsForward: envInt("FC_STREAM_PARTITION_MONTHS_FORWARD", 0),
 		StreamPartitionRetentionDays: envInt("FC_STREAM_PARTITION_RETENTION_DAYS", 0),
@@ -187,12 +336,58 @@ func LoadEnv() EnvCfg {
 		OutboxBackend:  envFirst("FC_OUTBOX_BACKEND", "FC_OUTBOX_DB_TYPE", "postgres"),
 		OutboxMongoURI: envFirst("FC_OUTBOX_MONGO_URI", "FC_OUTBOX_DB_URL", "", ""),
 		OutboxMongoDB:  envOr("FC_OUTBOX_MONGO_DB", "flowcatalyst"),
+		OutboxSources:  os.Getenv("FC_OUTBOX_SOURCES"),
+		OutboxQueueURL: os.Getenv("FC_OUTBOX_QUEUE_URL"),
 
 		RouterConfigURL:        os.Getenv("FLOWCATALYST_CONFIG_URL"),
 		RouterDevMode:          envBool("FLOWCATALYST_DEV_MODE", false),
 		RouterNotifyWebhookURL: os.Getenv("FC_NOTIFY_WEBHOOK_URL"),
 		RouterDrainTimeoutSec:  envInt("FC_DRAIN_TIMEOUT_SECONDS", 60),
 
+		RouterQueueStatsIntervalSec: envInt("FC_ROUTER_QUEUE_STATS_INTERVAL_SECONDS", 60),
+		RouterConfigSyncSec:         envInt("FC_ROUTER_CONFIG_SYNC_SECONDS", 0),
+
+		RouterSLOs:                 os.Getenv("FC_ROUTER_SLOS"),
+		RouterAlertWebhookURL:      os.Getenv("FC_ALERT_WEBHOOK_URL"),
+		RouterAlertSlackWebhookURL: os.Getenv("FC_ALERT_SLACK_WEBHOOK_URL"),
+		RouterAlertEmailTo:         os.Getenv("FC_ALERT_EMAIL_TO"),
+		RouterWarningsMongoURI:     os.Getenv("FC_ROUTER_WARNINGS_MONGO_URI"),
+		RouterWarningsMongoDB:      envOr("FC_ROUTER_WARNINGS_MONGO_DB", "flowcatalyst"),
+		RouterWarningRetentionDays: envInt("FC_ROUTER_WARNING_RETENTION_DAYS", 30),
+		RouterPoisonMongoURI:       os.Getenv("FC_ROUTER_POISON_MONGO_URI"),
+		RouterPoisonMongoDB:        envOr("FC_ROUTER_POISON_MONGO_DB", "flowcatalyst"),
+		RouterPoisonRetentionDays:  envInt("FC_ROUTER_POISON_RETENTION_DAYS", 14),
+
+		RouterCaptureSamplePercent: envFloat("FC_ROUTER_CAPTURE_SAMPLE_PERCENT", 0),
+		RouterCaptureMongoURI:      os.Getenv("FC_ROUTER_CAPTURE_MONGO_URI"),
+		RouterCaptureMongoDB:       envOr("FC_ROUTER_CAPTURE_MONGO_DB", "flowcatalyst"),
+		RouterCaptureMaxMB:         envInt("FC_ROUTER_CAPTURE_MAX_MB", 256),
+
+		RouterPipelineMongoURI:   os.Getenv("FC_ROUTER_PIPELINE_MONGO_URI"),
+		RouterPipelineMongoDB:    envOr("FC_ROUTER_PIPELINE_MONGO_DB", "flowcatalyst"),
+		RouterPipelineInstanceID: os.Getenv("FC_ROUTER_PIPELINE_INSTANCE_ID"),
+
+		RouterShardingEnabled:  envBool("FC_ROUTER_SHARDING_ENABLED", false),
+		RouterShards:           envInt("FC_ROUTER_SHARDS", 64),
+		RouterShardMongoURI:    envFirst("FC_ROUTER_SHARD_MONGO_URI", "FC_STANDBY_MONGO_URI", "", ""),
+		RouterShardMongoDB:     envOr("FC_ROUTER_SHARD_MONGO_DB", "flowcatalyst"),
+		RouterShardLeaseTTLSec: envInt("FC_ROUTER_SHARD_LEASE_SECONDS", 30),
+
+		RouterTimeoutSec:          envInt("FC_ROUTER_TIMEOUT_SECONDS", 0),
+		RouterConnectTimeoutSec:   envInt("FC_ROUTER_CONNECT_TIMEOUT_SECONDS", 0),
+		RouterMaxIdleConnsPerHost: envInt("FC_ROUTER_MAX_IDLE_CONNS_PER_HOST", 0),
+		RouterMaxRetryAfterSec:    envInt("FC_ROUTER_MAX_RETRY_AFTER_SECONDS", 0),
+		RouterHTTPVersion:         os.Getenv("FC_ROUTER_HTTP_VERSION"),
+		RouterTLSCAFile:           os.Getenv("FC_ROUTER_TLS_CA_FILE"),
+		RouterTLSClientCertFile:   os.Getenv("FC_ROUTER_TLS_CLIENT_CERT_FILE"),
+		RouterTLSClientKeyFile:    os.Getenv("FC_ROUTER_TLS_CLIENT_KEY_FILE"),
+		RouterFaults:              os.Getenv("FC_ROUTER_FAULTS"),
+
+		RouterMaxInFlight:             envInt("FC_ROUTER_MAX_IN_FLIGHT", 0),
+		RouterOverloadPolicy:          os.Getenv("FC_ROUTER_OVERLOAD_POLICY"),
+		RouterRetryBudget:             envFloat("FC_ROUTER_RETRY_BUDGET", 0),
+		RouterRetryBudgetMinPerSecond: envInt("FC_ROUTER_RETRY_BUDGET_MIN_PER_SECOND", 0),
+
 		ALBEnabled:        envBool("FC_ALB_ENABLED", false),
 		ALBTargetGroupARN: os.Getenv("FC_ALB_TARGET_GROUP_ARN"),
 		ALBInstanceIP:     envFirst("FC_ALB_TARGET_ID", "FC_ALB_INSTANCE_IP", "", ""),
@@ -201,10 +396,20 @@ func LoadEnv() EnvCfg {
 		ALBDeregDelaySec:  envInt("FC_ALB_DEREGISTRATION_DELAY_SECONDS", 0),
 
 		StandbyEnabled:  envBoolAlias("FC_STANDBY_ENABLED", "STANDBY_ENABLED", false),
+		StandbyBackend:  strings.ToLower(envOr("FC_STANDBY_BACKEND", "redis")),
 		StandbyRedisURL: envFirst("FC_STANDBY_REDIS_URL", "REDIS_URL", "", "redis://127.0.0.1:6379"),
+		StandbyMongoURI: envOr("FC_STANDBY_MONGO_URI", ""),
+		StandbyMongoDB:  envOr("FC_STANDBY_MONGO_DB", "flowcatalyst"),
 		StandbyLockKey:  envOr("FC_STANDBY_LOCK_KEY", "fc:server:leader"),
 
+		Region:                envOr("FC_REGION", ""),
+		FailoverInitialRegion: envOr("FC_FAILOVER_INITIAL_REGION", ""),
+		FailoverHandoverSec:   envInt("FC_FAILOVER_HANDOVER_SECONDS", 30),
+
+		Mongo: mongoconn.ConfigFromEnv(),
+
 		JWTSigningKeyPath:    os.Getenv("FC_JWT_SIGNING_KEY_PATH"),
+		JWTSigningKeyRef:     os.Getenv("FC_JWT_SIGNING_KEY_REF"),
 		JWTPreviousPublicKey: normalizedPreviousPublicKey(),
 		AuthAllowTestHeaders: envBool("FC_AUTH_ALLOW_TEST_HEADERS", false),
 
@@ -213,6 +418,17 @@ func LoadEnv() EnvCfg {
 		MCPClientSecret: os.Getenv("FLOWCATALYST_CLIENT_SECRET"),
 
 		DispatchProcessingEndpoint: envOr("FC_DISPATCH_PROCESSING_ENDPOINT", ""),
+		DispatchRetryBaseSec:       envInt("FC_DISPATCH_RETRY_BASE_SECONDS", 5),
+		DispatchRetryMaxSec:        envInt("FC_DISPATCH_RETRY_MAX_SECONDS", 120),
+
+		SchedulerQueueURL:                envOr("FC_SCHEDULER_QUEUE_URL", ""),
+		SchedulerQueueContentBasedDedup:  envBool("FC_SCHEDULER_QUEUE_CONTENT_BASED_DEDUP", false),
+		SchedulerQueueRoutes:             envOr("FC_SCHEDULER_QUEUE_ROUTES", ""),
+		SchedulerQueueCompression:        envOr("FC_SCHEDULER_QUEUE_COMPRESSION", ""),
+		SchedulerQueueLargePayloadBucket: envOr("FC_SCHEDULER_QUEUE_LARGE_PAYLOAD_BUCKET", ""),
+		SchedulerMaxPublishPerSec:        envInt("FC_SCHEDULER_MAX_PUBLISH_PER_SEC", 0),
+		SchedulerCatchUp:                 envOr("FC_SCHEDULER_CATCH_UP", ""),
+		SchedulerSkipOlderThanSec:        envInt("FC_SCHEDULER_SKIP_OLDER_THAN_SECS", 0),
 	}
 	// Default the dispatch callback to the local API listener: the router
 	// consumes a queued job and POSTs {messageId} here for delivery.
@@ -312,6 +528,15 @@ func envInt(key string, def int) int {
 	return def
 }
 
+func envFloat(key string, def float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
 func envIntAlias(key, alias string, def int) int {
 	if v := os.Getenv(key); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
@@ -344,3 +569,28 @@ func envBoolAlias(key, alias string, def bool) bool {
 	}
 	return envBool(alias, def)
 }
+
+// ResolveSecrets replaces Mongo URIs given as secret references
+// (vault://..., aws-sm://...) with the values they point at. Plain URIs
+// pass through untouched.
+func (c *EnvCfg) ResolveSecrets(ctx context.Context, sec *secrets.Service) error {
+	for name, field := range map[string]*string{
+		"FC_STANDBY_MONGO_URI":         &c.StandbyMongoURI,
+		"FC_OUTBOX_MONGO_URI":          &c.OutboxMongoURI,
+		"FC_ROUTER_WARNINGS_MONGO_URI": &c.RouterWarningsMongoURI,
+		"FC_ROUTER_SHARD_MONGO_URI":    &c.RouterShardMongoURI,
+		"FC_ROUTER_PIPELINE_MONGO_URI": &c.RouterPipelineMongoURI,
+		"FC_ROUTER_POISON_MONGO_URI":   &c.RouterPoisonMongoURI,
+		"FC_ROUTER_CAPTURE_MONGO_URI":  &c.RouterCaptureMongoURI,
+	} {
+		if *field == "" || !sec.Handles(*field) {
+			continue
+		}
+		v, err := sec.Resolve(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*field = v
+	}
+	return nil
+}
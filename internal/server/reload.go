@@ -0,0 +1,121 @@
+package server
+
+import (
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+)
+
+// reloadableVars are the variables a reload applies to the running
+// process. Everything else is read once while wiring; a change to one of
+// those is logged as needing a restart and otherwise ignored.
+var reloadableVars = map[string]bool{
+	"FC_LOG_LEVEL": true,
+
+	"FC_ROUTER_TIMEOUT_SECONDS":         true,
+	"FC_ROUTER_MAX_RETRY_AFTER_SECONDS": true,
+	"FC_ROUTER_CONFIG_SYNC_SECONDS":     true,
+
+	"FC_RL_OAUTH_TOKEN_IP_PER_MIN":          true,
+	"FC_RL_OAUTH_TOKEN_CLIENT_PER_MIN":      true,
+	"FC_RL_OAUTH_AUTHORIZE_IP_PER_MIN":      true,
+	"FC_RL_OAUTH_AUTHORIZE_CLIENT_PER_MIN":  true,
+	"FC_RL_PASSWORD_RESET_IP_PER_HOUR":      true,
+	"FC_RL_PASSWORD_RESET_EMAIL_PER_HOUR":   true,
+	"FC_RL_DEVICE_VERIFY_IP_PER_MIN":        true,
+	"FC_RL_DEVICE_VERIFY_PRINCIPAL_PER_MIN": true,
+	"FC_OAUTH_TOKEN_IP_RATE_PER_MIN":        true,
+	"FC_OAUTH_TOKEN_IP_BURST":               true,
+	"FC_OAUTH_TOKEN_CLIENT_RATE_PER_MIN":    true,
+	"FC_OAUTH_TOKEN_CLIENT_BURST":           true,
+	"FC_OIDC_RATE_PER_MIN":                  true,
+	"FC_OIDC_BURST":                         true,
+
+	"FC_MAINTENANCE_MODE":             true,
+	"FC_MAINTENANCE_RETRY_AFTER_SECS": true,
+}
+
+// Reloader re-applies the reloadable settings when asked (SIGHUP in
+// fc-server). Subsystems register a hook while they are wired; Reload
+// re-reads the environment and hands every hook the new EnvCfg. Hooks
+// that read their own variables (the rate-limit packages) simply re-read
+// them.
+type Reloader struct {
+	mu    sync.Mutex
+	vars  map[string]string
+	hooks []func(EnvCfg)
+}
+
+func newReloader() *Reloader {
+	return &Reloader{vars: configEnv()}
+}
+
+// OnReload registers fn to run on every successful reload.
+func (r *Reloader) OnReload(fn func(EnvCfg)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, fn)
+}
+
+// Reload re-reads the environment and applies it. A configuration that
+// fails Validate is rejected whole and the running settings stay as they
+// were. Returns the variables that changed.
+func (r *Reloader) Reload() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg := LoadEnv()
+	if err := cfg.Validate(); err != nil {
+		slog.Error("config reload rejected; keeping the running settings", "err", err)
+		return nil
+	}
+	vars := configEnv()
+	var changed, restart []string
+	for k := range configVars {
+		if vars[k] == r.vars[k] {
+			continue
+		}
+		changed = append(changed, k)
+		if !reloadableVars[k] {
+			restart = append(restart, k)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(restart)
+	r.vars = vars
+
+	logging.SetLevel(logging.ParseLevel(os.Getenv("FC_LOG_LEVEL")))
+	for _, fn := range r.hooks {
+		fn(cfg)
+	}
+	slog.Info("config reloaded", "changed", changed, "log_level", logging.Level().String())
+	if len(restart) > 0 {
+		slog.Warn("config reload: these settings only take effect after a restart", "vars", restart)
+	}
+	return changed
+}
+
+// configEnv snapshots the known variables.
+func configEnv() map[string]string {
+	m := make(map[string]string, len(configVars))
+	for k := range configVars {
+		if v := os.Getenv(k); v != "" {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// routerTunables maps the reloadable router settings.
+func routerTunables(cfg EnvCfg) router.Tunables {
+	return router.Tunables{
+		MediatorTimeout:    time.Duration(cfg.RouterTimeoutSec) * time.Second,
+		MaxRetryAfter:      time.Duration(cfg.RouterMaxRetryAfterSec) * time.Second,
+		ConfigPollInterval: time.Duration(cfg.RouterConfigSyncSec) * time.Second,
+	}
+}
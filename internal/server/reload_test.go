@@ -0,0 +1,37 @@
+package server
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/logging"
+)
+
+func TestReloader(t *testing.T) {
+	t.Cleanup(func() { logging.SetLevel(slog.LevelInfo) })
+	t.Setenv("FC_LOG_LEVEL", "info")
+	t.Setenv("FC_ROUTER_TIMEOUT_SECONDS", "")
+	t.Setenv("FC_API_PORT", "")
+	r := newReloader()
+	var got []EnvCfg
+	r.OnReload(func(c EnvCfg) { got = append(got, c) })
+
+	t.Setenv("FC_LOG_LEVEL", "debug")
+	t.Setenv("FC_ROUTER_TIMEOUT_SECONDS", "45")
+	t.Setenv("FC_API_PORT", "8081")
+	assert.Equal(t, []string{"FC_API_PORT", "FC_LOG_LEVEL", "FC_ROUTER_TIMEOUT_SECONDS"}, r.Reload())
+	assert.Equal(t, slog.LevelDebug, logging.Level())
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, 45*time.Second, routerTunables(got[0]).MediatorTimeout)
+	}
+
+	// An invalid configuration is rejected whole.
+	t.Setenv("FC_LOG_LEVEL", "error")
+	t.Setenv("FC_API_PORT", "70000")
+	assert.Nil(t, r.Reload())
+	assert.Equal(t, slog.LevelDebug, logging.Level())
+	assert.Len(t, got, 1)
+}
@@ -11,26 +11,35 @@ import (
 	"sync"
 	"time"
 
-	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/email"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
+	natsqueue "github.com/flowcatalyst/flowcatalyst-go/internal/queue/nats"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
 	routerapi "github.com/flowcatalyst/flowcatalyst-go/internal/router/api"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/secrets"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/standby"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/stream"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/swaggerui"
 )
 
 // RunOptions lets the caller (fc-server / fc-dev) extend the unified
 // HTTP server without forking it. ExtraAPIRoutes runs after platform +
 // router are mounted; Fallback runs as the NotFound handler (used by
-// fc-dev to mount the embedded Vue SPA).
+// fc-dev to mount the embedded Vue SPA). Each receive on Reload re-reads
+// the environment and applies the reloadable settings (see Reloader);
+// nil disables reloading.
 type RunOptions struct {
 	ExtraAPIRoutes func(r chi.Router)
 	Fallback       http.Handler
+	Reload         <-chan struct{}
 }
 
 // Run is the single orchestrator that fc-server and fc-dev both call.
@@ -58,6 +67,25 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 	// the router's StreamHealthProvider reports zero streams gracefully.
 	streamHealth := stream.NewHealthService()
 
+	// External secret references (vault://, aws-sm://) in the Mongo URIs
+	// are resolved before anything dials; the same service resolves
+	// references stored in the database and follows rotations.
+	sec, err := secrets.ServiceFromEnv()
+	if err != nil {
+		return fmt.Errorf("secrets: %w", err)
+	}
+	if err := cfg.ResolveSecrets(ctx, sec); err != nil {
+		return err
+	}
+	if every := secrets.RefreshIntervalFromEnv(); every > 0 {
+		go sec.Run(ctx, every)
+	}
+	// Configured projections join the built-ins before anything looks
+	// them up — the stream processor, and the rebuild endpoint.
+	if err := stream.RegisterConfigured(cfg.StreamProjections); err != nil {
+		return fmt.Errorf("FC_STREAM_PROJECTIONS: %w", err)
+	}
+
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
@@ -67,8 +95,15 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 	var routerSrv *router.Server
 	var routerErr error
 
+	// Platform-level Prometheus series, served on the metrics port.
+	// Router series stay under the router prefix on the API port.
+	platformMetrics := prometheus.NewRegistry()
+	platformMetrics.MustRegister(mongoconn.Collector{}, natsqueue.Collector{}, streamHealth)
+
+	reload := newReloader()
+
 	if cfg.PlatformEnabled {
-		if err := WirePlatform(r, pool, cfg); err != nil {
+		if err := WirePlatform(ctx, r, pool, cfg, platformMetrics, reload, sec); err != nil {
 			return fmt.Errorf("platform wiring: %w", err)
 		}
 		slog.Info("platform API wired")
@@ -83,8 +118,9 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 		if prefix == "" {
 			prefix = "/router"
 		}
-		MountRouterHTTP(r, prefix, routerSrv, streamHealth, cfg)
+		MountRouterHTTP(r, prefix, routerSrv, pool, streamHealth, cfg)
 		slog.Info("router HTTP mounted", "prefix", prefix)
+		reload.OnReload(func(c EnvCfg) { routerSrv.Retune(routerTunables(c)) })
 	}
 
 	if opts.ExtraAPIRoutes != nil {
@@ -114,7 +150,7 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 	}
 	if cfg.SchedulerEnabled {
 		wg.Add(1)
-		go func() { defer wg.Done(); StartScheduler(ctx, pool, cfg) }()
+		go func() { defer wg.Done(); StartScheduler(ctx, pool, cfg, platformMetrics) }()
 		slog.Info("scheduler started")
 	}
 	if cfg.ScheduledJobEnabled {
@@ -132,7 +168,7 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 	}
 	if cfg.OutboxEnabled {
 		wg.Add(1)
-		go func() { defer wg.Done(); StartOutboxProcessor(ctx, pool, cfg) }()
+		go func() { defer wg.Done(); StartOutboxProcessor(ctx, pool, cfg, platformMetrics, sec) }()
 		slog.Info("outbox processor started")
 	}
 	if cfg.RouterEnabled {
@@ -150,6 +186,18 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 		go func() { defer wg.Done(); StartMCP(ctx, cfg) }()
 		slog.Info("mcp started")
 	}
+	if opts.Reload != nil {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-opts.Reload:
+					reload.Reload()
+				}
+			}
+		}()
+	}
 
 	// ── Listeners ─────────────────────────────────────────────────────────
 	apiSrv := &http.Server{
@@ -159,7 +207,7 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 	}
 	metricsSrv := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.MetricsPort),
-		Handler:           metricsRouter(cfg),
+		Handler:           metricsRouter(cfg, platformMetrics),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
@@ -203,28 +251,49 @@ func Run(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, opts RunOptions) e
 // MountRouterHTTP nests the router API + dashboard + Prometheus under
 // the supplied prefix. Authentication is BasicAuth (env-driven). The
 // router engine itself must be started separately — this only wires
-// the HTTP surface that reads its state.
-func MountRouterHTTP(r chi.Router, prefix string, srv *router.Server, streamHealth *stream.HealthService, cfg EnvCfg) {
+// the HTTP surface that reads its state. pool, when non-nil, backs the
+// stream projection rebuild endpoint.
+func MountRouterHTTP(r chi.Router, prefix string, srv *router.Server, pool *pgxpool.Pool, streamHealth *stream.HealthService, cfg EnvCfg) {
 	state := routerapi.FromServer(srv)
 	if streamHealth != nil {
 		state.StreamHealth = streamHealthBridge{svc: streamHealth}
 	}
+	if pool != nil {
+		state.Rebuilder = streamRebuildBridge{pool: pool}
+	}
+	if fo := newFailover(cfg); fo != nil {
+		state.Failover = fo
+	}
 	r.Route(prefix, func(sub chi.Router) {
 		// BasicAuth on the router prefix. Disabled when no creds set.
 		sub.Use(routerapi.BasicAuthMiddleware(resolveRouterAuth()))
-		humaCfg := huma.DefaultConfig("FlowCatalyst Router API", routerapi.Version)
 		// Nest the spec under the prefix so external tooling can grab
-		// the OpenAPI doc at <prefix>/openapi.json.
-		// Drop huma's $schema link injection (Rust never emits it), matching
-		// the platform API config in wire.go.
-		humaCfg.SchemasPath = ""
-		api := humachi.New(sub, humaCfg)
+		// the OpenAPI doc at <prefix>/openapi.json, browsable at
+		// <prefix>/swagger.
+		api := humachi.New(sub, routerapi.HumaConfig())
 		routerapi.Register(api, state)
 		routerapi.MountDashboard(sub)
+		sub.Method(http.MethodGet, "/swagger", swaggerui.Handler("FlowCatalyst Router API", prefix+"/openapi.json"))
 		sub.Mount("/metrics", routerapi.PrometheusHandler(state))
 	})
 }
 
+// newFailover builds the promote-API handle when this instance runs
+// region-gated elections, or nil (the endpoints then answer 503). The
+// handover record is shared with the elections, so it lives in the standby
+// MongoDB whichever lease backend is configured.
+func newFailover(cfg EnvCfg) *standby.Failover {
+	if !cfg.StandbyEnabled || cfg.Region == "" {
+		return nil
+	}
+	store, err := standby.NewMongoRegionStore(cfg.StandbyMongoURI, cfg.StandbyMongoDB, cfg.FailoverInitialRegion, cfg.Mongo)
+	if err != nil {
+		slog.Warn("region failover API disabled", "region", cfg.Region, "error", err)
+		return nil
+	}
+	return standby.NewFailover(store, cfg.Region, time.Duration(cfg.FailoverHandoverSec)*time.Second)
+}
+
 // resolveRouterAuth reads the router HTTP BasicAuth config, accepting the Rust
 // AUTH_BASIC_USERNAME / AUTH_BASIC_PASSWORD names as aliases for
 // FC_ROUTER_AUTH_USER / FC_ROUTER_AUTH_PASS. AUTH_MODE=NONE (case-insensitive)
@@ -259,6 +328,8 @@ func (b streamHealthBridge) Aggregate() routerapi.StreamHealthAggregate {
 			BatchSequence:  s.BatchSequence,
 			ErrorCount:     s.ErrorCount,
 			LastPollTimeMs: s.LastPollTimeMs,
+			Backlog:        s.Backlog,
+			LagSeconds:     s.LagSeconds,
 		})
 	}
 	return routerapi.StreamHealthAggregate{
@@ -273,18 +344,74 @@ func (b streamHealthBridge) Aggregate() routerapi.StreamHealthAggregate {
 func (b streamHealthBridge) IsLive() bool  { return b.svc.IsLive() }
 func (b streamHealthBridge) IsReady() bool { return b.svc.IsReady() }
 
+// streamRebuildBridge exposes stream.Rebuild as a
+// routerapi.StreamRebuilder, translating rejected names into the api's
+// sentinel so they surface as 400.
+type streamRebuildBridge struct{ pool *pgxpool.Pool }
+
+func (b streamRebuildBridge) Rebuild(ctx context.Context, projection string) (routerapi.StreamRebuildResult, error) {
+	res, err := stream.Rebuild(ctx, b.pool, projection)
+	if errors.Is(err, stream.ErrUnknownProjection) || errors.Is(err, stream.ErrNotRebuildable) {
+		return routerapi.StreamRebuildResult{}, fmt.Errorf("%w: %v (rebuildable: %s)",
+			routerapi.ErrProjectionNotRebuildable, err, strings.Join(stream.RebuildableProjections(), ", "))
+	}
+	if err != nil {
+		return routerapi.StreamRebuildResult{}, err
+	}
+	return routerapi.StreamRebuildResult{Projection: res.Projection, Requeued: res.Requeued}, nil
+}
+
 // newRouterServer wraps router.NewServer with the env-driven router
 // config. When cfg.RouterConfigURL is empty we honour cfg.DefaultBroker
 // to synthesize an in-process Postgres pool config so fc-dev "just works".
 func newRouterServer(cfg EnvCfg, pool *pgxpool.Pool) (*router.Server, error) {
+	mediator, err := routerMediatorOverrides(cfg)
+	if err != nil {
+		return nil, err
+	}
+	slos, err := router.ParseSLOs(cfg.RouterSLOs)
+	if err != nil {
+		return nil, fmt.Errorf("FC_ROUTER_SLOS: %w", err)
+	}
 	rcfg := router.ServerConfig{
-		DevMode:          cfg.RouterDevMode,
-		ConfigURL:        cfg.RouterConfigURL,
-		NotifyWebhookURL: cfg.RouterNotifyWebhookURL,
-		DrainTimeout:     time.Duration(cfg.RouterDrainTimeoutSec) * time.Second,
-		StandbyEnabled:   cfg.StandbyEnabled,
-		StandbyRedisURL:  cfg.StandbyRedisURL,
-		StandbyLockKey:   cfg.StandbyLockKey,
+		DevMode:              cfg.RouterDevMode,
+		Mediator:             mediator,
+		ConfigURL:            cfg.RouterConfigURL,
+		ConfigPollInterval:   time.Duration(cfg.RouterConfigSyncSec) * time.Second,
+		NotifyWebhookURL:     cfg.RouterNotifyWebhookURL,
+		AlertWebhookURL:      cfg.RouterAlertWebhookURL,
+		AlertSlackWebhookURL: cfg.RouterAlertSlackWebhookURL,
+		AlertMail:            routerAlertMail(cfg),
+		SLOs:                 slos,
+		WarningStoreMongoURI: cfg.RouterWarningsMongoURI,
+		WarningStoreMongoDB:  cfg.RouterWarningsMongoDB,
+		WarningRetention:     time.Duration(cfg.RouterWarningRetentionDays) * 24 * time.Hour,
+		PoisonStoreMongoURI:  cfg.RouterPoisonMongoURI,
+		PoisonStoreMongoDB:   cfg.RouterPoisonMongoDB,
+		PoisonRetention:      time.Duration(cfg.RouterPoisonRetentionDays) * 24 * time.Hour,
+		CaptureSampleRate:    cfg.RouterCaptureSamplePercent / 100,
+		CaptureMongoURI:      cfg.RouterCaptureMongoURI,
+		CaptureMongoDB:       cfg.RouterCaptureMongoDB,
+		CaptureSizeBytes:     int64(cfg.RouterCaptureMaxMB) << 20,
+		BrokerStatsInterval:  time.Duration(cfg.RouterQueueStatsIntervalSec) * time.Second,
+		DrainTimeout:         time.Duration(cfg.RouterDrainTimeoutSec) * time.Second,
+		StandbyEnabled:       cfg.StandbyEnabled,
+		StandbyBackend:       cfg.StandbyBackend,
+		StandbyRedisURL:      cfg.StandbyRedisURL,
+		StandbyMongoURI:      cfg.StandbyMongoURI,
+		StandbyMongoDB:       cfg.StandbyMongoDB,
+		StandbyLockKey:       cfg.StandbyLockKey,
+		Region:               cfg.Region,
+		InitialActiveRegion:  cfg.FailoverInitialRegion,
+		PipelineMongoURI:     cfg.RouterPipelineMongoURI,
+		PipelineMongoDB:      cfg.RouterPipelineMongoDB,
+		PipelineInstanceID:   cfg.RouterPipelineInstanceID,
+		ShardingEnabled:      cfg.RouterShardingEnabled,
+		Shards:               cfg.RouterShards,
+		ShardMongoURI:        cfg.RouterShardMongoURI,
+		ShardMongoDB:         cfg.RouterShardMongoDB,
+		ShardLeaseTTL:        time.Duration(cfg.RouterShardLeaseTTLSec) * time.Second,
+		Mongo:                cfg.Mongo,
 		// ALB self-registration: register on leader-gain / non-standby start,
 		// deregister on leader-loss / drain. No-op unless FC_ALB_ENABLED + the
 		// target group ARN + instance IP are set.
@@ -335,6 +462,71 @@ func newRouterServer(cfg EnvCfg, pool *pgxpool.Pool) (*router.Server, error) {
 	return srv, nil
 }
 
+// routerAlertMail adapts the env-configured mailer to router.AlertMailFunc,
+// sending each alert to every FC_ALERT_EMAIL_TO address. Nil when unset.
+func routerAlertMail(cfg EnvCfg) router.AlertMailFunc {
+	var to []string
+	for _, addr := range strings.Split(cfg.RouterAlertEmailTo, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	svc := email.FromEnv()
+	return func(ctx context.Context, subject, htmlBody string) error {
+		var errs []error
+		for _, addr := range to {
+			if err := svc.Send(ctx, email.Message{To: addr, Subject: subject, HTMLBody: htmlBody}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// routerMediatorOverrides maps the FC_ROUTER_* mediator env vars onto
+// router.MediatorOverrides.
+func routerMediatorOverrides(cfg EnvCfg) (router.MediatorOverrides, error) {
+	o := router.MediatorOverrides{
+		Timeout:             time.Duration(cfg.RouterTimeoutSec) * time.Second,
+		ConnectTimeout:      time.Duration(cfg.RouterConnectTimeoutSec) * time.Second,
+		MaxIdleConnsPerHost: cfg.RouterMaxIdleConnsPerHost,
+		MaxRetryAfter:       time.Duration(cfg.RouterMaxRetryAfterSec) * time.Second,
+		MaxInFlight:         cfg.RouterMaxInFlight,
+		RetryBudget:         cfg.RouterRetryBudget,
+		TLS: router.MediatorTLS{
+			CAFile:   cfg.RouterTLSCAFile,
+			CertFile: cfg.RouterTLSClientCertFile,
+			KeyFile:  cfg.RouterTLSClientKeyFile,
+		},
+	}
+	switch cfg.RouterHTTPVersion {
+	case "":
+	case "1", "1.1":
+		v := router.HTTPVersion1
+		o.HTTPVersion = &v
+	case "2":
+		v := router.HTTPVersion2
+		o.HTTPVersion = &v
+	default:
+		return o, fmt.Errorf("FC_ROUTER_HTTP_VERSION=%q: want 1 or 2", cfg.RouterHTTPVersion)
+	}
+	o.RetryBudgetMinPerSecond = cfg.RouterRetryBudgetMinPerSecond
+	policy, err := router.ParseOverloadPolicy(cfg.RouterOverloadPolicy)
+	if err != nil {
+		return o, fmt.Errorf("FC_ROUTER_OVERLOAD_POLICY: %w", err)
+	}
+	o.OverloadPolicy = policy
+	faults, err := router.ParseFaultRules(cfg.RouterFaults)
+	if err != nil {
+		return o, fmt.Errorf("FC_ROUTER_FAULTS: %w", err)
+	}
+	o.Faults = faults
+	return o, nil
+}
+
 // initQueueSchema bootstraps the backend's tables when the underlying
 // queue.Consumer also implements queue.Embedded (the in-process backends
 // — Postgres, SQLite — do). External backends like SQS no-op cleanly
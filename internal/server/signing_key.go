@@ -1,29 +1,40 @@
 package server
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/signingkey"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/secrets"
 )
 
 // LoadSigningKeyOrEphemeral returns the PEM-encoded RSA private key for
 // JWT signing. Resolution order:
 //
 //  1. cfg.JWTSigningKeyPath — read from disk if set.
-//  2. Inline PEM from env: FLOWCATALYST_JWT_PRIVATE_KEY (the name the Rust
+//  2. FC_JWT_SIGNING_KEY_SECRET_ARN — an AWS Secrets Manager secret whose
+//     string value is the PEM (same mangling tolerance as the env vars), so
+//     the key never has to sit in the task definition or on disk.
+//  3. Inline PEM from env: FLOWCATALYST_JWT_PRIVATE_KEY (the name the Rust
 //     platform + the deploy IaC use — load-bearing for drop-in token parity)
 //     or FC_JWT_SIGNING_KEY_PEM (the Go-native alias).
-//  3. Otherwise, generate an ephemeral 2048-bit RSA key and log a warning.
+//  4. Otherwise, generate an ephemeral 2048-bit RSA key and log a warning.
 //     Ephemeral keys are fine for dev / first-boot smoke tests but lose every
 //     token's signature on restart AND differ per instance (so a multi-replica
-//     service rejects each other's tokens). Production must supply (1) or (2).
+//     service rejects each other's tokens). Production must supply (1)–(3).
 func LoadSigningKeyOrEphemeral(path string) []byte {
 	if path != "" {
 		b, err := os.ReadFile(path)
@@ -32,6 +43,13 @@ func LoadSigningKeyOrEphemeral(path string) []byte {
 		}
 		slog.Warn("FC_JWT_SIGNING_KEY_PATH unreadable, falling back", "err", err)
 	}
+	if arn := os.Getenv("FC_JWT_SIGNING_KEY_SECRET_ARN"); arn != "" {
+		pemStr, err := fetchSigningKeySecret(arn)
+		if err == nil {
+			return []byte(pemStr)
+		}
+		slog.Warn("FC_JWT_SIGNING_KEY_SECRET_ARN unreadable, falling back", "err", err)
+	}
 	// FLOWCATALYST_JWT_PRIVATE_KEY first: it's the key the Rust system signs
 	// with, so reading it keeps Go RS256 tokens validating against the same
 	// keypair (and stops the silent ephemeral-key fallback that mints tokens
@@ -45,6 +63,82 @@ func LoadSigningKeyOrEphemeral(path string) []byte {
 	return generateRSAPEM()
 }
 
+// fetchSigningKeySecret reads the signing-key PEM from Secrets Manager.
+func fetchSigningKeySecret(arn string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	sm, err := newSMClient(ctx, arn)
+	if err != nil {
+		return "", err
+	}
+	out, err := sm.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &arn})
+	if err != nil {
+		return "", fmt.Errorf("get secret %s: %w", arn, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", arn)
+	}
+	pemStr := NormalizePEM(*out.SecretString)
+	if !strings.Contains(pemStr, "-----BEGIN") {
+		return "", fmt.Errorf("secret %s is not a PEM key", arn)
+	}
+	return pemStr, nil
+}
+
+// signingKeyFromRef resolves FC_JWT_SIGNING_KEY_REF through the secrets
+// service. Unlike the other sources there is no fallback: a configured
+// reference that can't be read stops startup.
+func signingKeyFromRef(sec *secrets.Service, ref string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	v, err := sec.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	pemStr := NormalizePEM(v)
+	if !strings.Contains(pemStr, "-----BEGIN") {
+		return nil, errors.New("secret is not a PEM key")
+	}
+	return []byte(pemStr), nil
+}
+
+// followSigningKeyRef swaps the ring's signer when the referenced secret
+// rotates. The outgoing key keeps verifying, so tokens it signed stay
+// valid until they expire.
+func followSigningKeyRef(sec *secrets.Service, ref string, current []byte, ring *signingkey.Ring) {
+	sec.OnRotate(ref, string(current), func(v string) {
+		priv, err := signingkey.ParsePrivateKeyPEM([]byte(NormalizePEM(v)))
+		if err != nil {
+			slog.Error("rotated JWT signing key is not usable; keeping the current key", "err", err)
+			return
+		}
+		next := signingkey.NewKey(priv)
+		ring.Replace(next, ring.Keys())
+		slog.Info("JWT signing key rotated from the secret store", "kid", next.ID)
+	})
+}
+
+// buildKeyRing assembles the JWT key ring from the configured signing key
+// plus the validation-only previous public key(s) — one or more
+// concatenated PEM blocks, so several retired keys can keep verifying.
+func buildKeyRing(signingKeyPEM []byte, previousPEM string) (*signingkey.Ring, error) {
+	priv, err := signingkey.ParsePrivateKeyPEM(signingKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("signing key: %w", err)
+	}
+	var previous []signingkey.Key
+	if previousPEM != "" {
+		pubs, err := signingkey.ParsePublicKeysPEM([]byte(previousPEM))
+		if err != nil {
+			return nil, fmt.Errorf("previous public key: %w", err)
+		}
+		for _, pub := range pubs {
+			previous = append(previous, signingkey.NewPublicKey(pub))
+		}
+	}
+	return signingkey.NewRing(signingkey.NewKey(priv), previous...), nil
+}
+
 // NormalizePEM repairs the common ways a PEM key gets mangled when carried in
 // an environment variable (AWS SSM / Secrets Manager → ECS task def):
 //
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"strings"
 	"testing"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/signingkey"
 )
 
 func TestNormalizePEM(t *testing.T) {
@@ -47,3 +50,37 @@ func TestNormalizePEM(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildKeyRing(t *testing.T) {
+	signing := generateRSAPEM()
+	prevA, prevB := generateRSAPEM(), generateRSAPEM()
+	pubPEM := func(privPEM []byte) string {
+		k, err := signingkey.ParsePrivateKeyPEM(privPEM)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+
+	ring, err := buildKeyRing(signing, pubPEM(prevA)+pubPEM(prevB))
+	if err != nil {
+		t.Fatalf("buildKeyRing: %v", err)
+	}
+	if ring.Signer().Private == nil {
+		t.Fatal("signer has no private key")
+	}
+	if got := len(ring.Keys()); got != 3 {
+		t.Errorf("ring holds %d keys, want signer + 2 previous", got)
+	}
+
+	if _, err := buildKeyRing(signing, ""); err != nil {
+		t.Errorf("no previous key is fine: %v", err)
+	}
+	if _, err := buildKeyRing([]byte("garbage"), ""); err == nil {
+		t.Error("an unparseable signing key must fail")
+	}
+}
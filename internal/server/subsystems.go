@@ -16,6 +16,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/mcp"
@@ -27,9 +28,11 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/scheduledjob"
 	sjscheduler "github.com/flowcatalyst/flowcatalyst-go/internal/platform/scheduledjob/scheduler"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/scheduler"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/database"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/queue"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/router"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/secrets"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/standby"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/stream"
 
@@ -55,7 +58,7 @@ import (
 // Fail-closed: the dispatch-auth HMAC secret is derived from
 // FLOWCATALYST_APP_KEY; without it the scheduler refuses to start rather
 // than signing with a known literal.
-func StartScheduler(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) {
+func StartScheduler(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, metrics prometheus.Registerer) {
 	secret, err := dispatchAuthSecret()
 	if err != nil {
 		slog.Error("scheduler disabled: cannot derive dispatch-auth secret; set FLOWCATALYST_APP_KEY", "err", err)
@@ -69,16 +72,38 @@ func StartScheduler(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) {
 	if c, ok := pub.(interface{ Stop() }); ok {
 		defer c.Stop()
 	}
+	routes, err := schedulerQueueRoutes(ctx, cfg)
+	if err != nil {
+		slog.Error("scheduler disabled: cannot build queue routes", "err", err)
+		return
+	}
+	for _, r := range routes {
+		if c, ok := r.(interface{ Stop() }); ok {
+			defer c.Stop()
+		}
+	}
 	scfg := scheduler.DefaultConfig()
 	scfg.ProcessingEndpoint = cfg.DispatchProcessingEndpoint
+	scfg.MaxPublishRate = float64(cfg.SchedulerMaxPublishPerSec)
+	scfg.SkipOlderThan = time.Duration(cfg.SchedulerSkipOlderThanSec) * time.Second
+	// Validated at startup (EnvCfg.Validate).
+	scfg.CatchUp, _ = scheduler.ParseCatchUpOrder(cfg.SchedulerCatchUp)
 	s := scheduler.New(scfg, pool, pub, secret)
+	if metrics != nil {
+		if err := metrics.Register(s.Metrics()); err != nil {
+			slog.Warn("scheduler metrics not registered", "err", err)
+		}
+	}
 	s.IsLeader = newLeaderGate(ctx, cfg, "scheduler")
+	s.QueueRoutes = routes
 	s.Run(ctx)
 	slog.Info("scheduler stopped")
 }
 
 // schedulerPublisher builds the queue.Publisher the dispatcher uses to hand
-// claimed dispatch jobs to the router. In dev / single-tenant mode
+// claimed dispatch jobs to the router. FC_SCHEDULER_QUEUE_URL wins when set:
+// in production that is the router's SQS queue (standard or FIFO). In dev /
+// single-tenant mode
 // (DefaultBroker=postgres) it targets the SAME built-in Postgres broker queue
 // the router consumes from — reusing defaultPostgresRouterConfig so the
 // publish queue and the router's consume queue can never drift — so dispatch
@@ -92,6 +117,24 @@ func StartScheduler(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) {
 // env knobs are not yet wired. Claimed jobs then drain into the void and are
 // recovered by stale recovery, so make that impossible to miss in the logs.
 func schedulerPublisher(ctx context.Context, cfg EnvCfg) (queue.Publisher, error) {
+	if cfg.SchedulerQueueURL != "" {
+		qc := common.QueueConfig{
+			URI:                       cfg.SchedulerQueueURL,
+			ContentBasedDeduplication: cfg.SchedulerQueueContentBasedDedup,
+			Compression:               cfg.SchedulerQueueCompression,
+			LargePayloadBucket:        cfg.SchedulerQueueLargePayloadBucket,
+		}
+		pub, err := queue.NewPublisher(ctx, qc)
+		if err != nil {
+			return nil, fmt.Errorf("dispatch publisher for %q: %w", cfg.SchedulerQueueURL, err)
+		}
+		slog.Info("scheduler: dispatch jobs published to queue",
+			"queue", pub.Identifier(),
+			"fifo", strings.HasSuffix(cfg.SchedulerQueueURL, ".fifo"),
+			"content_based_dedup", cfg.SchedulerQueueContentBasedDedup,
+			"compression", cfg.SchedulerQueueCompression)
+		return pub, nil
+	}
 	if cfg.DefaultBroker == "postgres" && cfg.DatabaseURL != "" {
 		// Single source of truth for the dev queue: whatever the router
 		// consumes is what the scheduler publishes to.
@@ -111,6 +154,57 @@ func schedulerPublisher(ctx context.Context, cfg EnvCfg) (queue.Publisher, error
 	return NoopPublisher{}, nil
 }
 
+// schedulerQueueRoutes builds a publisher per FC_SCHEDULER_QUEUE_ROUTES
+// entry ("POOL=queue-url" or "priority:HIGH=queue-url", ';'-separated —
+// queue URIs may carry commas). nil when unset.
+func schedulerQueueRoutes(ctx context.Context, cfg EnvCfg) (map[string]queue.Publisher, error) {
+	spec := strings.TrimSpace(cfg.SchedulerQueueRoutes)
+	if spec == "" {
+		return nil, nil
+	}
+	if cfg.SchedulerQueueURL == "" {
+		return nil, errors.New("FC_SCHEDULER_QUEUE_ROUTES requires FC_SCHEDULER_QUEUE_URL for unrouted pools")
+	}
+	routes := make(map[string]queue.Publisher)
+	fail := func(err error) (map[string]queue.Publisher, error) {
+		for _, r := range routes {
+			if c, ok := r.(interface{ Stop() }); ok {
+				c.Stop()
+			}
+		}
+		return nil, err
+	}
+	for entry := range strings.SplitSeq(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, uri, ok := strings.Cut(entry, "=")
+		code, uri = strings.TrimSpace(code), strings.TrimSpace(uri)
+		if !ok || code == "" || uri == "" {
+			return fail(fmt.Errorf("FC_SCHEDULER_QUEUE_ROUTES: entry %q is not POOL=queue-url or priority:LEVEL=queue-url", entry))
+		}
+		if p, ok := strings.CutPrefix(code, scheduler.PriorityRoutePrefix); ok && !common.Priority(p).Valid() {
+			return fail(fmt.Errorf("FC_SCHEDULER_QUEUE_ROUTES: unknown priority %q (HIGH, NORMAL, LOW)", p))
+		}
+		if _, dup := routes[code]; dup {
+			return fail(fmt.Errorf("FC_SCHEDULER_QUEUE_ROUTES: %q routed twice", code))
+		}
+		pub, err := queue.NewPublisher(ctx, common.QueueConfig{
+			URI:                       uri,
+			ContentBasedDeduplication: cfg.SchedulerQueueContentBasedDedup,
+			Compression:               cfg.SchedulerQueueCompression,
+			LargePayloadBucket:        cfg.SchedulerQueueLargePayloadBucket,
+		})
+		if err != nil {
+			return fail(fmt.Errorf("dispatch publisher for route %q: %w", code, err))
+		}
+		routes[code] = pub
+		slog.Info("scheduler: dispatch jobs routed to their own queue", "route", code, "queue", pub.Identifier())
+	}
+	return routes, nil
+}
+
 // dispatchAuthSecret derives the HMAC key for dispatch-job auth tokens from
 // FLOWCATALYST_APP_KEY via HKDF-SHA256 with a purpose-bound info string.
 // Deriving (rather than reusing the raw key) keeps the field-encryption key
@@ -145,7 +239,8 @@ func StartScheduledJobScheduler(ctx context.Context, pool *pgxpool.Pool, cfg Env
 
 // newLeaderGate returns an IsLeader predicate for a leader-only background
 // subsystem. When standby is disabled it always returns true (single
-// instance). When enabled it runs a dedicated Redis election on a
+// instance). When enabled it runs a dedicated election (on the
+// FC_STANDBY_BACKEND lease store) on a
 // subsystem-suffixed lock key, so it elects independently of the router's own
 // election (sharing the router's exact key with a different instance id would
 // starve this gate). The election is stopped when ctx is cancelled.
@@ -155,6 +250,12 @@ func newLeaderGate(ctx context.Context, cfg EnvCfg, subsystem string) func() boo
 	}
 	ecfg := common.NewLeaderElectionConfig(cfg.StandbyRedisURL)
 	ecfg.Enabled = true
+	ecfg.Backend = cfg.StandbyBackend
+	ecfg.MongoURI = cfg.StandbyMongoURI
+	ecfg.MongoDatabase = cfg.StandbyMongoDB
+	ecfg.Mongo = cfg.Mongo
+	ecfg.Region = cfg.Region
+	ecfg.InitialActiveRegion = cfg.FailoverInitialRegion
 	ecfg.LockKey = cfg.StandbyLockKey + ":" + subsystem
 	// Election failures fail CLOSED (never leader): standby is enabled, so
 	// other replicas exist, and an un-gated fallback would let every replica
@@ -216,6 +317,7 @@ func StartStreamProcessorWithHealth(ctx context.Context, pool *pgxpool.Pool, cfg
 
 	registerProjector := func(name string, p *stream.Projector) *stream.Projector {
 		p.IsLeader = streamLeader
+		p.Changed = func() { stream.Changes.Notify(name) }
 		if healths != nil {
 			h := stream.NewHealth(name)
 			p.Health = h
@@ -240,15 +342,32 @@ func StartStreamProcessorWithHealth(ctx context.Context, pool *pgxpool.Pool, cfg
 		return c
 	}
 
-	if cfg.StreamEventsEnabled {
-		p := registerProjector("event_projection",
-			stream.NewEventProjection(pool).Projector(projCfg("FC_STREAM_EVENTS_BATCH_SIZE", 100)))
-		launch("event_projection", p.Run)
+	// Every registered projection runs unless disabled: by its definition
+	// ("enabled": false in FC_STREAM_PROJECTIONS), by name in
+	// FC_STREAM_DISABLED_PROJECTIONS, or — for the built-ins — by their
+	// own sub-toggle. The built-ins keep their batch-size env vars.
+	disabled := map[string]bool{
+		"event_projection":        !cfg.StreamEventsEnabled,
+		"dispatch_job_projection": !cfg.StreamDispatchJobsEnabled,
 	}
-	if cfg.StreamDispatchJobsEnabled {
-		p := registerProjector("dispatch_job_projection",
-			stream.NewDispatchJobProjection(pool).Projector(projCfg("FC_STREAM_DISPATCH_JOBS_BATCH_SIZE", 100)))
-		launch("dispatch_job_projection", p.Run)
+	for name := range strings.SplitSeq(cfg.StreamDisabledProjections, ",") {
+		disabled[strings.TrimSpace(name)] = true
+	}
+	batchEnv := map[string]string{
+		"event_projection":        "FC_STREAM_EVENTS_BATCH_SIZE",
+		"dispatch_job_projection": "FC_STREAM_DISPATCH_JOBS_BATCH_SIZE",
+	}
+	for _, def := range stream.Projections.Definitions() {
+		if def.Disabled || disabled[def.Name] {
+			slog.Info("projection disabled", "name", def.Name)
+			continue
+		}
+		if err := def.EnsureIndexes(ctx, pool); err != nil {
+			slog.Error("projection not started", "name", def.Name, "err", err)
+			continue
+		}
+		p := registerProjector(def.Name, def.Projector(pool, projCfg(batchEnv[def.Name], 100)))
+		launch(def.Name, p.Run)
 	}
 	if cfg.StreamFanOutEnabled {
 		// FC_STREAM_FAN_OUT_SUBS_REFRESH_SECS tunes the subscription cache TTL
@@ -287,27 +406,23 @@ func StartStreamProcessorWithHealth(ctx context.Context, pool *pgxpool.Pool, cfg
 
 // StartOutboxProcessor runs the consumer-app SDK outbox poller. The backend
 // is selected by FC_OUTBOX_BACKEND: "postgres" (default) reuses the shared
-// pool; "mongo" dials FC_OUTBOX_MONGO_URI. Blocks until ctx is cancelled.
+// pool; "mongo" dials FC_OUTBOX_MONGO_URI. That outbox is the "default"
+// source; each FC_OUTBOX_SOURCES entry adds a tenant Postgres database polled
+// alongside it on its own pool and poll interval. With FC_OUTBOX_QUEUE_URL
+// set, dispatch jobs skip the platform and are published straight to that
+// queue (outbox.QueueDispatcher). Blocks until ctx is cancelled.
 //
 // The processor is leader-gated (newLeaderGate): when standby is enabled only
 // the leader polls — the Mongo backend has no atomic claim, so a single
 // active poller avoids double-claims. Mirrors the Rust outbox leadership gate.
-func StartOutboxProcessor(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) {
+func StartOutboxProcessor(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg, metrics prometheus.Registerer, sec *secrets.Service) {
 	if cfg.OutboxPlatformURL == "" {
 		slog.Error("outbox processor enabled but FC_OUTBOX_PLATFORM_URL / FC_OUTBOX_API_URL not set; skipping")
 		return
 	}
-
-	repo, closeRepo, err := buildOutboxRepo(ctx, pool, cfg)
+	specs, err := outbox.ParseSources(cfg.OutboxSources)
 	if err != nil {
-		slog.Error("outbox backend init failed", "backend", cfg.OutboxBackend, "err", err)
-		return
-	}
-	if closeRepo != nil {
-		defer closeRepo()
-	}
-	if err := repo.InitSchema(ctx); err != nil {
-		slog.Error("outbox init schema failed", "err", err)
+		slog.Error("outbox sources invalid", "err", fmt.Errorf("FC_OUTBOX_SOURCES: %w", err))
 		return
 	}
 
@@ -327,15 +442,49 @@ func StartOutboxProcessor(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) {
 		pcfg.MaxConcurrentGroups = cfg.OutboxMaxConcurrentGroups
 	}
 	pcfg.BlockOnError = cfg.OutboxBlockOnError
+	if cfg.OutboxQueueURL != "" {
+		pub, err := queue.NewPublisher(ctx, common.QueueConfig{URI: cfg.OutboxQueueURL})
+		if err != nil {
+			slog.Error("outbox queue publisher init failed", "err", fmt.Errorf("FC_OUTBOX_QUEUE_URL: %w", err))
+			return
+		}
+		if c, ok := pub.(interface{ Stop() }); ok {
+			defer c.Stop()
+		}
+		pcfg.Publisher = pub
+		slog.Info("outbox: dispatch jobs published directly to queue", "queue", pub.Identifier())
+	}
+
+	sources := outbox.NewSources(pcfg, outboxSourceConnector(sec))
+	sources.IsLeader = newLeaderGate(ctx, cfg, "outbox")
 
-	p := outbox.NewProcessor(pcfg, repo)
-	p.IsLeader = newLeaderGate(ctx, cfg, "outbox")
+	repo, closeRepo, err := buildOutboxRepo(ctx, pool, cfg)
+	if err != nil {
+		slog.Error("outbox backend init failed", "backend", cfg.OutboxBackend, "err", err)
+		return
+	}
+	if err := sources.Attach(ctx, outbox.SourceSpec{Name: outbox.DefaultSource}, repo, closeRepo); err != nil {
+		slog.Error("outbox init schema failed", "err", err)
+		return
+	}
+	// A tenant database that is down at startup is logged and skipped, not
+	// fatal: the others still poll, and it can be re-added over the admin API.
+	for _, spec := range specs {
+		if err := sources.Add(ctx, spec); err != nil {
+			slog.Error("outbox source not started", "source", spec.Name, "err", err)
+		}
+	}
+	if metrics != nil {
+		if err := metrics.Register(sources); err != nil {
+			slog.Warn("outbox source metrics not registered", "err", err)
+		}
+	}
 
-	// Operational state-machine admin API (pause/resume/unblock/skip groups),
-	// localhost-only, when FC_OUTBOX_ADMIN_PORT is set.
+	// Operational admin API (pause/resume/unblock/skip groups, add/remove
+	// sources), localhost-only, when FC_OUTBOX_ADMIN_PORT is set.
 	if cfg.OutboxAdminPort > 0 {
 		addr := fmt.Sprintf("127.0.0.1:%d", cfg.OutboxAdminPort)
-		adminSrv := &http.Server{Addr: addr, Handler: p.AdminHandler(), ReadHeaderTimeout: 5 * time.Second}
+		adminSrv := &http.Server{Addr: addr, Handler: sources.AdminHandler(), ReadHeaderTimeout: 5 * time.Second}
 		go func() {
 			slog.Info("outbox admin API listening", "addr", addr)
 			if err := adminSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -350,11 +499,32 @@ func StartOutboxProcessor(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) {
 		}()
 	}
 
-	slog.Info("outbox processor started", "platform_url", cfg.OutboxPlatformURL, "backend", cfg.OutboxBackend)
-	p.Run(ctx)
+	slog.Info("outbox processor started", "platform_url", cfg.OutboxPlatformURL, "backend", cfg.OutboxBackend, "sources", len(specs)+1)
+	sources.Run(ctx)
 	slog.Info("outbox processor stopped")
 }
 
+// outboxSourceConnector opens a tenant outbox database on its own small
+// pool. A URL given as a secret reference (vault://..., aws-sm://...) is
+// resolved first, so each tenant's credentials can live in the store.
+func outboxSourceConnector(sec *secrets.Service) outbox.Connector {
+	return func(ctx context.Context, spec outbox.SourceSpec) (outbox.Repository, func(), error) {
+		url := spec.URL
+		if sec != nil && sec.Handles(url) {
+			v, err := sec.Resolve(ctx, url)
+			if err != nil {
+				return nil, nil, err
+			}
+			url = v
+		}
+		pool, err := database.NewPool(ctx, database.Config{URL: url, MaxConnections: 4})
+		if err != nil {
+			return nil, nil, err
+		}
+		return outboxpg.New(pool), pool.Close, nil
+	}
+}
+
 // buildOutboxRepo selects the outbox backend. Returns an optional cleanup
 // func (non-nil for Mongo, which owns a client connection).
 func buildOutboxRepo(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) (outbox.Repository, func(), error) {
@@ -363,7 +533,7 @@ func buildOutboxRepo(ctx context.Context, pool *pgxpool.Pool, cfg EnvCfg) (outbo
 		if cfg.OutboxMongoURI == "" {
 			return nil, nil, fmt.Errorf("FC_OUTBOX_BACKEND=mongo requires FC_OUTBOX_MONGO_URI")
 		}
-		repo, err := outboxmongo.Connect(ctx, cfg.OutboxMongoURI, cfg.OutboxMongoDB)
+		repo, err := outboxmongo.Connect(ctx, cfg.OutboxMongoURI, cfg.OutboxMongoDB, cfg.Mongo)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -449,14 +619,55 @@ func StartMCP(ctx context.Context, cfg EnvCfg) {
 // constructed per-pool inside the router. The signature keeps pool in
 // case a future co-tenanted Postgres queue backend wants to share it.
 func StartRouter(ctx context.Context, _ *pgxpool.Pool, cfg EnvCfg) {
+	mediator, err := routerMediatorOverrides(cfg)
+	if err != nil {
+		slog.Error("router init failed", "err", err)
+		return
+	}
+	slos, err := router.ParseSLOs(cfg.RouterSLOs)
+	if err != nil {
+		slog.Error("router init failed", "err", fmt.Errorf("FC_ROUTER_SLOS: %w", err))
+		return
+	}
 	rcfg := router.ServerConfig{
-		DevMode:          cfg.RouterDevMode,
-		ConfigURL:        cfg.RouterConfigURL,
-		NotifyWebhookURL: cfg.RouterNotifyWebhookURL,
-		DrainTimeout:     time.Duration(cfg.RouterDrainTimeoutSec) * time.Second,
-		StandbyEnabled:   cfg.StandbyEnabled,
-		StandbyRedisURL:  cfg.StandbyRedisURL,
-		StandbyLockKey:   cfg.StandbyLockKey,
+		DevMode:              cfg.RouterDevMode,
+		Mediator:             mediator,
+		ConfigURL:            cfg.RouterConfigURL,
+		ConfigPollInterval:   time.Duration(cfg.RouterConfigSyncSec) * time.Second,
+		NotifyWebhookURL:     cfg.RouterNotifyWebhookURL,
+		AlertWebhookURL:      cfg.RouterAlertWebhookURL,
+		AlertSlackWebhookURL: cfg.RouterAlertSlackWebhookURL,
+		AlertMail:            routerAlertMail(cfg),
+		SLOs:                 slos,
+		WarningStoreMongoURI: cfg.RouterWarningsMongoURI,
+		WarningStoreMongoDB:  cfg.RouterWarningsMongoDB,
+		WarningRetention:     time.Duration(cfg.RouterWarningRetentionDays) * 24 * time.Hour,
+		PoisonStoreMongoURI:  cfg.RouterPoisonMongoURI,
+		PoisonStoreMongoDB:   cfg.RouterPoisonMongoDB,
+		PoisonRetention:      time.Duration(cfg.RouterPoisonRetentionDays) * 24 * time.Hour,
+		CaptureSampleRate:    cfg.RouterCaptureSamplePercent / 100,
+		CaptureMongoURI:      cfg.RouterCaptureMongoURI,
+		CaptureMongoDB:       cfg.RouterCaptureMongoDB,
+		CaptureSizeBytes:     int64(cfg.RouterCaptureMaxMB) << 20,
+		BrokerStatsInterval:  time.Duration(cfg.RouterQueueStatsIntervalSec) * time.Second,
+		DrainTimeout:         time.Duration(cfg.RouterDrainTimeoutSec) * time.Second,
+		StandbyEnabled:       cfg.StandbyEnabled,
+		StandbyBackend:       cfg.StandbyBackend,
+		StandbyRedisURL:      cfg.StandbyRedisURL,
+		StandbyMongoURI:      cfg.StandbyMongoURI,
+		StandbyMongoDB:       cfg.StandbyMongoDB,
+		StandbyLockKey:       cfg.StandbyLockKey,
+		Region:               cfg.Region,
+		InitialActiveRegion:  cfg.FailoverInitialRegion,
+		PipelineMongoURI:     cfg.RouterPipelineMongoURI,
+		PipelineMongoDB:      cfg.RouterPipelineMongoDB,
+		PipelineInstanceID:   cfg.RouterPipelineInstanceID,
+		ShardingEnabled:      cfg.RouterShardingEnabled,
+		Shards:               cfg.RouterShards,
+		ShardMongoURI:        cfg.RouterShardMongoURI,
+		ShardMongoDB:         cfg.RouterShardMongoDB,
+		ShardLeaseTTL:        time.Duration(cfg.RouterShardLeaseTTLSec) * time.Second,
+		Mongo:                cfg.Mongo,
 	}
 	srv, err := router.NewServer(rcfg)
 	if err != nil {
@@ -1,11 +1,29 @@
 package server
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/schemaregistry"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
+	principalops "github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/operations"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/filtercache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
 	platformsink "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/platformsink"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/targetauth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/secrets"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/stream"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
@@ -27,7 +45,18 @@ import (
 //	                   repo, build the use cases, build the api.State,
 //	                   register it on the huma API.
 //	wire_spec.go     — registerSpecRoutes: unauthenticated OpenAPI/Swagger
-func WirePlatform(r chi.Router, pool *pgxpool.Pool, cfg EnvCfg) error {
+//
+// ctx bounds the request-path helpers that need a background loop (the
+// API activity recorder's flush/prune ticker, the stored-secret
+// re-encryption writer, the JWT key rotator, the
+// usage meter's and event-type usage tracker's flushes, the maintenance-mode
+// poll, the export and privacy purge runners, the
+// client-access grant expiry runner); they stop
+// when it is cancelled. Platform-level Prometheus collectors are
+// registered on metrics, which the metrics port serves; the rate limits
+// and maintenance mode follow a config reload through reload. sec resolves secret references
+// held in the database and the JWT signing key reference.
+func WirePlatform(ctx context.Context, r chi.Router, pool *pgxpool.Pool, cfg EnvCfg, metrics prometheus.Registerer, reload *Reloader, sec *secrets.Service) error {
 	// Wire the huma error transformer so handler-returned *usecase.Error
 	// values flow out as the canonical {code, message, details} envelope.
 	httpcompat.Init()
@@ -36,13 +65,78 @@ func WirePlatform(r chi.Router, pool *pgxpool.Pool, cfg EnvCfg) error {
 	uow := usecasepgx.New(pool, sink)
 
 	repos := buildRepos(pool)
-	svcs, err := buildServices(cfg, pool, repos)
+	svcs, err := buildServices(cfg, pool, uow, repos, sec)
 	if err != nil {
 		return err
 	}
 
-	registerPublicRoutes(r, cfg, pool, uow, repos, svcs)
-	humaAPI := registerPlatformAPI(r, cfg, pool, uow, repos, svcs)
+	go svcs.apiActivity.Run(ctx)
+	if svcs.encRewriter != nil {
+		go svcs.encRewriter.Run(ctx)
+	}
+	if svcs.keyRotator != nil {
+		go svcs.keyRotator.Run(ctx)
+	}
+	go svcs.meter.Run(ctx)
+	go svcs.eventTypeUsage.Run(ctx)
+	go svcs.eventDedup.Run(ctx)
+	go svcs.maintenance.Run(ctx)
+	// Only reaches the caches when the stream processor runs in this
+	// process; otherwise their TTL bounds staleness.
+	stream.Changes.Subscribe("event_projection", svcs.eventFilterOptions.Invalidate)
+	stream.Changes.Subscribe("dispatch_job_projection", svcs.jobFilterOptions.Invalidate)
+	if err := metrics.Register(filtercache.NewCollector(svcs.eventFilterOptions, svcs.jobFilterOptions)); err != nil {
+		return fmt.Errorf("register filter-options cache collector: %w", err)
+	}
+	if err := metrics.Register(svcs.eventDedup); err != nil {
+		return fmt.Errorf("register event dedup collector: %w", err)
+	}
+	if err := metrics.Register(metering.NewCollector(svcs.meter.Config(), repos.meteringRepo)); err != nil {
+		return fmt.Errorf("register metering collector: %w", err)
+	}
+	if err := metrics.Register(targetauth.NewExpiryCollector(repos.subscriptionTLSRepo)); err != nil {
+		return fmt.Errorf("register subscription TLS expiry collector: %w", err)
+	}
+	if svcs.exportStore != nil {
+		go export.NewRunner(svcs.exportCfg, repos.exportRepo, svcs.exportStore).Run(ctx)
+	}
+	go privacy.NewRunner(svcs.privacyCfg, repos.privacyRepo, repos.auditRepo).Run(ctx)
+	go ingestion.NewRunner(ingestion.ConfigFromEnv(), repos.ingestRepo, repos.eventRepo, svcs.meter).Run(ctx)
+	go principalops.NewGrantExpiryRunner(repos.principalGrantRepo, uow).Run(ctx)
+	if svcs.callbackCfg.Enabled() {
+		cr := callback.NewRunner(svcs.callbackCfg, repos.callbackRepo, repos.dispatchJobRepo)
+		// The policy parsed at startup (EnvCfg.Validate), so this can't fail
+		// in a deployment that booted.
+		if pol, err := egress.FromEnv(); err == nil {
+			cr.SetEgress(pol)
+		}
+		go cr.Run(ctx)
+	}
+	if svcs.schemaRegistryCfg.Enabled() {
+		sr, err := schemaregistry.NewRunner(svcs.schemaRegistryCfg, repos.schemaRegistryRepo)
+		if err != nil {
+			return fmt.Errorf("schema registry sync: %w", err)
+		}
+		if err := metrics.Register(sr); err != nil {
+			return fmt.Errorf("register schema registry collector: %w", err)
+		}
+		go sr.Run(ctx)
+	}
+
+	// Maintenance mode wraps both the public and the authenticated routes:
+	// writes are refused with 503 while it is on (see maintenance.Exempt
+	// for the sign-in and router paths that keep working). Health and the
+	// spec routes stay outside.
+	var humaAPI huma.API
+	r.Group(func(r chi.Router) {
+		r.Use(svcs.maintenance.Middleware)
+		registerPublicRoutes(r, cfg, pool, uow, repos, svcs)
+		humaAPI = registerPlatformAPI(r, cfg, pool, uow, repos, svcs)
+	})
 	registerSpecRoutes(r, humaAPI)
+	reload.OnReload(func(EnvCfg) {
+		svcs.reloadRateLimits()
+		svcs.maintenance.SetConfig(maintenance.ConfigFromEnv())
+	})
 	return nil
 }
@@ -2,16 +2,24 @@ package server
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
 	dispatchprocessing "github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/processing"
+	ingestionapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion/api"
 	passwordresetapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/passwordreset/api"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/publicapi"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/scheduler"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliveryheaders"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/deliverysettings"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/targetauth"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/transform"
 	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
@@ -27,9 +35,14 @@ func registerPublicRoutes(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *use
 	svcs.loginEP.RegisterPublicRoutes(r)
 
 	// Public read-only endpoints the SPA hits before sign-in
-	// (login-theme branding, platform feature flags). Mounted outside
-	// the auth middleware for the same reason as the login surface.
-	publicapi.New(repos.platformConfigRepo).RegisterRoutes(r)
+	// (login-theme branding, platform feature flags), plus the published
+	// delivery egress addresses. Mounted outside the auth middleware for
+	// the same reason as the login surface.
+	pub := publicapi.New(repos.platformConfigRepo)
+	if pol, err := egress.FromEnv(); err == nil {
+		pub.SetEgress(pol)
+	}
+	pub.RegisterRoutes(r)
 
 	// Unauthenticated password-reset flow (request/validate/confirm). Public
 	// like /auth/login. Email is delivered via the SMTP_* env (SendGrid in
@@ -60,16 +73,76 @@ func registerPublicRoutes(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *use
 	// /oauth/authorize is mounted OUTSIDE the auth middleware: an absent or
 	// expired session must redirect to login (not 401), and the handler
 	// validates the session cookie itself. Wrapped in the per-IP throttle.
-	svcs.oauthTokenEP.RegisterAuthorizeRoutes(r.With(ratelimit.IPLimitMiddleware(svcs.rlStore, ratelimit.BucketOAuthAuthorizeIP, svcs.rlPolicies.OAuthAuthorizeIP)))
+	authorizeIPLimit := ratelimit.IPLimitMiddlewareFunc(svcs.rlStore, ratelimit.BucketOAuthAuthorizeIP,
+		func() ratelimit.Policy { return svcs.rlPolicies.Get().OAuthAuthorizeIP })
+	svcs.oauthTokenEP.RegisterAuthorizeRoutes(r.With(authorizeIPLimit))
+	// The device-flow verification page (/oauth/device) has the same
+	// redirect-to-login posture, and shares the authorize per-IP bucket so
+	// user codes can't be guessed faster than authorize can be probed.
+	svcs.oauthTokenEP.RegisterDeviceVerificationRoutes(r.With(authorizeIPLimit))
+	// The consent endpoints likewise resolve the session themselves (a
+	// third-party authorization lands there mid-flow).
+	svcs.oauthTokenEP.RegisterConsentRoutes(r.With(authorizeIPLimit))
+	// RP-initiated logout: the browser arrives from the relying party with
+	// a session that may already have expired.
+	svcs.oauthTokenEP.RegisterLogoutRoutes(r.With(authorizeIPLimit))
 
 	// POST /api/dispatch/process — the message router's delivery callback.
 	// MUST be outside the bearer middleware: the router authenticates with the
 	// scheduler's HMAC job token (verified inside the handler), not a platform
-	// JWT. Skipped only when the dispatch-auth secret can't be derived (no
+	// JWT. Receivers acknowledge deliveries at POST
+	// /api/dispatch/deliveries/{token}/ack, mounted with it for the same
+	// reason. Skipped only when the dispatch-auth secret can't be derived (no
 	// FLOWCATALYST_APP_KEY) — same fail-closed condition as StartScheduler.
 	if secret, err := dispatchAuthSecret(); err == nil {
-		dispatchprocessing.New(repos.dispatchJobRepo, scheduler.NewDispatchAuthService(secret)).Mount(r)
+		dispatchAuth := scheduler.NewDispatchAuthService(secret)
+		h := dispatchprocessing.New(repos.dispatchJobRepo, dispatchAuth)
+		targetAuth := targetauth.New(repos.subscriptionRepo)
+		targetTLS := targetauth.NewTransports(repos.subscriptionTLSRepo)
+		settings := deliverysettings.New(repos.subscriptionRepo)
+		// The policy parsed at startup (EnvCfg.Validate), so this can't fail
+		// in a deployment that booted.
+		if pol, err := egress.FromEnv(); err == nil {
+			h.SetEgress(pol, settings)
+			targetAuth.SetEgress(pol)
+			targetTLS.SetEgress(pol)
+		} else {
+			slog.Error("egress policy invalid; deliveries are not egress-checked", "err", err)
+		}
+		h.SetTargetAuth(targetAuth)
+		h.SetTargetTLS(targetTLS)
+		h.SetTransformer(transform.New(settings))
+		h.SetDeliveryFormats(settings)
+		h.SetDeliveryHeaders(deliveryheaders.New(settings, repos.serviceAccountRepo))
+		h.SetDeliveryWindows(settings)
+		h.SetAcks(settings, dispatchAuth)
+		h.SetMeter(svcs.meter)
+		h.SetTaps(settings)
+		h.SetRetryAfter(settings, time.Duration(cfg.RouterMaxRetryAfterSec)*time.Second)
+		h.SetClaimCheck(svcs.payloads)
+		if svcs.callbackCfg.Enabled() {
+			h.SetCallbacks(repos.callbackRepo)
+		}
+		backoff := dispatchjob.DefaultBackoffPolicy()
+		if cfg.DispatchRetryBaseSec > 0 {
+			backoff.Base = time.Duration(cfg.DispatchRetryBaseSec) * time.Second
+		}
+		if cfg.DispatchRetryMaxSec > 0 {
+			backoff.Max = time.Duration(cfg.DispatchRetryMaxSec) * time.Second
+		}
+		h.SetRetryBackoff(backoff)
+		h.Mount(r)
 	} else {
 		slog.Warn("dispatch-processing callback not mounted: cannot derive dispatch-auth secret", "err", err)
 	}
+
+	// POST /ingest/{sourceCode} — the inbound webhook gateway. Outside the
+	// bearer middleware: providers authenticate with the request signature,
+	// checked against the source's encrypted signing secret. Without
+	// FLOWCATALYST_APP_KEY no source can hold a secret, so nothing mounts.
+	if svcs.encSvc != nil {
+		ingestionapi.NewIngestHandler(repos.ingestRepo, svcs.encSvc, svcs.meter).Mount(r)
+	} else {
+		slog.Warn("inbound webhook gateway not mounted: no encryption key")
+	}
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/apiactivity"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/application"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth"
@@ -10,15 +11,22 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/connection"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/cors"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchpool"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/schemaregistry"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/loginattempt"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/passwordreset"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/process"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/resetapproval"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
@@ -43,6 +51,7 @@ type repoSet struct {
 	corsRepo                    *cors.Repository
 	connectionRepo              *connection.Repository
 	subscriptionRepo            *subscription.Repository
+	subscriptionTLSRepo         *subscription.TargetTLSRepository
 	dispatchPoolRepo            *dispatchpool.Repository
 	dispatchJobRepo             *dispatchjob.Repository
 	eventTypeRepo               *eventtype.Repository
@@ -58,6 +67,14 @@ type repoSet struct {
 	webauthnCeremonyRepo        *webauthn.CeremonyRepository
 	resetTokenRepo              *passwordreset.Repository
 	resetApprovalRepo           *resetapproval.Repository
+	apiActivityRepo             *apiactivity.Repository
+	meteringRepo                *metering.Repository
+	maintenanceRepo             *maintenance.Repository
+	exportRepo                  *export.Repository
+	privacyRepo                 *privacy.Repository
+	ingestRepo                  *ingestion.Repository
+	callbackRepo                *callback.Repository
+	schemaRegistryRepo          *schemaregistry.Repository
 }
 
 func buildRepos(pool *pgxpool.Pool) *repoSet {
@@ -73,6 +90,7 @@ func buildRepos(pool *pgxpool.Pool) *repoSet {
 		corsRepo:                    cors.NewRepository(pool),
 		connectionRepo:              connection.NewRepository(pool),
 		subscriptionRepo:            subscription.NewRepository(pool),
+		subscriptionTLSRepo:         subscription.NewTargetTLSRepository(pool),
 		dispatchPoolRepo:            dispatchpool.NewRepository(pool),
 		dispatchJobRepo:             dispatchjob.NewRepository(pool),
 		eventTypeRepo:               eventtype.NewRepository(pool),
@@ -88,5 +106,13 @@ func buildRepos(pool *pgxpool.Pool) *repoSet {
 		webauthnCeremonyRepo:        webauthn.NewCeremonyRepository(pool),
 		resetTokenRepo:              passwordreset.NewRepository(pool),
 		resetApprovalRepo:           resetapproval.NewRepository(pool),
+		apiActivityRepo:             apiactivity.NewRepository(pool),
+		meteringRepo:                metering.NewRepository(pool),
+		maintenanceRepo:             maintenance.NewRepository(pool),
+		exportRepo:                  export.NewRepository(pool),
+		privacyRepo:                 privacy.NewRepository(pool),
+		ingestRepo:                  ingestion.NewRepository(pool),
+		callbackRepo:                callback.NewRepository(pool),
+		schemaRegistryRepo:          schemaregistry.NewRepository(pool),
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/analytics"
 	applicationapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/application/api"
 	auditapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit/api"
 	authapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/api"
@@ -24,12 +25,17 @@ import (
 	emaildomainapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping/api"
 	eventapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/event/api"
 	eventtypeapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/api"
+	exportapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/export/api"
 	identityproviderapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider/api"
+	ingestionapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion/api"
 	loginattemptapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/loginattempt/api"
+	maintenanceapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance/api"
+	meteringapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering/api"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/openapispecs"
 	passwordresetapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/passwordreset/api"
 	platformconfigapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig/api"
 	principalapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/api"
+	privacyapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy/api"
 	processapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/process/api"
 	resetapprovalapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/resetapproval/api"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/role"
@@ -39,11 +45,11 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/sdksync"
 	serviceaccountapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/serviceaccount/api"
 	bff "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/bff"
-	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
 	meapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/me"
 	platformmw "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/middleware"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/reqctx"
 	sdkapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/sdk"
 	subscriptionapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/api"
 	webauthnapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn/api"
@@ -51,9 +57,9 @@ import (
 )
 
 // registerPlatformAPI wires the authenticated platform surface: a chi
-// Group carrying the CorrelationID + Authenticator middleware, the huma
-// API every aggregate registers against, and the chi-mounted BFF/SDK/me
-// endpoints. Returns the huma API so the unauthenticated spec/docs
+// Group carrying the CorrelationID + Authenticator + reqctx middleware,
+// the huma API every aggregate registers against, and the chi-mounted
+// BFF/SDK/me endpoints. Returns the huma API so the unauthenticated spec/docs
 // handlers (mounted on the parent router by registerSpecRoutes) can
 // serve the generated OpenAPI document.
 //
@@ -81,6 +87,16 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 			Provider:         svcs.authProvider,
 			AllowTestHeaders: cfg.AuthAllowTestHeaders,
 		}))
+		// The typed request context (principal, client, request and
+		// correlation IDs, locale) read by handlers, use cases and the
+		// list repositories' tenant scoping. After Authenticator.
+		r.Use(reqctx.Middleware)
+		// Sampled per-principal call log (after Authenticator so the
+		// principal is known; records the chi route pattern only).
+		r.Use(svcs.apiActivity.Middleware)
+		// CloudEvents on POST /api/events are rewritten to the native
+		// request shape before huma decodes the body.
+		r.Use(eventapi.CloudEventsIngest)
 		// /auth/me — needs the AuthContext, so mounted INSIDE the auth
 		// group. /auth/check-domain + /auth/login + /auth/logout are
 		// public (see registerPublicRoutes).
@@ -105,10 +121,44 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 
 		// ── api.State + RegisterRoutes per subdomain ───────────────────
 		clientapi.Register(humaAPI, &clientapi.State{
-			Repo:          repos.clientRepo,
-			Applications:  repos.applicationRepo,
-			ClientConfigs: repos.applicationClientConfigRepo,
-			UoW:           uow,
+			Repo:            repos.clientRepo,
+			Applications:    repos.applicationRepo,
+			ClientConfigs:   repos.applicationClientConfigRepo,
+			DispatchPools:   repos.dispatchPoolRepo,
+			Principals:      repos.principalRepo,
+			ServiceAccounts: repos.serviceAccountRepo,
+			OAuthClients:    repos.authRepo.OAuthClients,
+			Subscriptions:   repos.subscriptionRepo,
+			EventTypes:      repos.eventTypeRepo,
+			InviteEmailer:   principalResetEmailer,
+			Impersonation:   svcs.authProvider,
+			UoW:             uow,
+		})
+		meteringapi.Register(humaAPI, &meteringapi.State{
+			Repo:    repos.meteringRepo,
+			Clients: repos.clientRepo,
+			Meter:   svcs.meter,
+			UoW:     uow,
+		})
+		maintenanceapi.Register(humaAPI, &maintenanceapi.State{
+			Repo: repos.maintenanceRepo,
+			Mode: svcs.maintenance,
+			UoW:  uow,
+		})
+		exportapi.Register(humaAPI, &exportapi.State{
+			Repo:   repos.exportRepo,
+			Config: svcs.exportCfg,
+			Store:  svcs.exportStore,
+			UoW:    uow,
+		})
+		privacyapi.Register(humaAPI, &privacyapi.State{
+			Repo:   repos.privacyRepo,
+			Config: svcs.privacyCfg,
+			UoW:    uow,
+		})
+		ingestionapi.Register(humaAPI, &ingestionapi.State{
+			Repo: repos.ingestRepo,
+			UoW:  uow,
 		})
 
 		roleapi.Register(humaAPI, &roleapi.State{
@@ -144,6 +194,9 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 			Notifier:          svcs.notifier,
 			MFA:               svcs.mfaSvc,
 			Audit:             repos.auditRepo,
+			Sessions:          svcs.sessionRevocations,
+			RefreshTokens:     svcs.oauthTokenEP.RefreshTokens,
+			Backchannel:       svcs.backchannel,
 			UoW:               uow,
 		})
 
@@ -173,7 +226,8 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 		// registerPublicRoutes, outside this auth group.
 		svcs.oauthTokenEP.RegisterTokenRoutes(r.With(
 			ratelimit.GovernorMiddleware(svcs.oauthTokenIPGov, "rate limit exceeded for this IP"),
-			ratelimit.IPLimitMiddleware(svcs.rlStore, ratelimit.BucketOAuthTokenIP, svcs.rlPolicies.OAuthTokenIP),
+			ratelimit.IPLimitMiddlewareFunc(svcs.rlStore, ratelimit.BucketOAuthTokenIP,
+				func() ratelimit.Policy { return svcs.rlPolicies.Get().OAuthTokenIP }),
 		))
 		svcs.oauthTokenEP.RegisterIntrospectRoutes(r)
 		svcs.oauthTokenEP.RegisterRevokeRoutes(r)
@@ -192,8 +246,7 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 		// Field-level encryption (FLOWCATALYST_APP_KEY) — nil-safe; the
 		// bridge will surface a clear error if a confidential OIDC config
 		// needs a secret and the key isn't set.
-		appEnc, _ := encryption.FromEnv()
-		bridgeClient := bridge.NewBridge(repos.edmRepo, repos.idpRepo, appEnc)
+		bridgeClient := bridge.NewBridge(repos.edmRepo, repos.idpRepo, svcs.encSvc)
 		loginStateRepo := bridge.NewLoginStateRepo(pool)
 		bridgeLoginEP := bridge.NewLoginEndpoint(bridgeClient, loginStateRepo, repos.principalRepo, repos.edmRepo,
 			repos.roleRepo, repos.authRepo.IdpRoleMappings, uow, repos.authRepo.OAuthClients)
@@ -228,9 +281,8 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 		// Per-IP rate limit on the public OIDC bridge routes (login start +
 		// callback + session/end) — blunts authorization-code probing / DoS
 		// without impeding a real interactive login.
-		oidcGov := ratelimit.NewGovernor(ratelimit.OIDCBridgeGovernorFromEnv())
 		r.Group(func(g chi.Router) {
-			g.Use(ratelimit.GovernorMiddleware(oidcGov, "Too many authentication requests"))
+			g.Use(ratelimit.GovernorMiddleware(svcs.oidcGov, "Too many authentication requests"))
 			bridgeLoginEP.RegisterRoutes(g)
 		})
 
@@ -245,23 +297,29 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 		})
 
 		subscriptionapi.Register(humaAPI, &subscriptionapi.State{
-			Repo: repos.subscriptionRepo,
-			UoW:  uow,
+			Repo:            repos.subscriptionRepo,
+			TLSRepo:         repos.subscriptionTLSRepo,
+			UoW:             uow,
+			Approvers:       repos.principalRepo,
+			Notifier:        svcs.notifier,
+			ExternalBaseURL: cfg.JWTIssuer,
 		})
 
 		dispatchpoolapi.Register(humaAPI, &dispatchpoolapi.State{
-			Repo: repos.dispatchPoolRepo,
-			UoW:  uow,
+			Repo:      repos.dispatchPoolRepo,
+			UoW:       uow,
+			Analytics: analytics.NewRepository(pool),
 		})
 
 		eventtypeapi.Register(humaAPI, &eventtypeapi.State{
-			Repo: repos.eventTypeRepo,
-			UoW:  uow,
+			Repo:  repos.eventTypeRepo,
+			UoW:   uow,
+			Usage: svcs.eventTypeUsage,
 		})
 
 		// SDK self-registration ("sync") endpoints, scoped under
 		// /api/applications/{appCode}. Mirrors the Rust sdk_sync_router.
-		sdksync.Register(humaAPI, &sdksync.State{
+		sdkSyncState := &sdksync.State{
 			Apps:          repos.applicationRepo,
 			EventTypes:    repos.eventTypeRepo,
 			Roles:         repos.roleRepo,
@@ -272,12 +330,18 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 			Principals:    repos.principalRepo,
 			ScheduledJobs: repos.scheduledJobRepo,
 			Specs:         openapispecs.NewRepository(pool),
+			Permissions:   role.NewPermissionRepo(pool),
 			UoW:           uow,
-		})
+		}
+		sdksync.Register(humaAPI, sdkSyncState)
+		sdksync.RegisterPermissions(humaAPI, sdkSyncState)
+		// Declarative apply (anchor-only) converges a whole bundle through
+		// the same sync use cases.
+		sdksync.RegisterApply(humaAPI, sdkSyncState)
 
-		eventapi.Register(humaAPI, &eventapi.State{Repo: repos.eventRepo, Clients: repos.clientRepo})
+		eventapi.Register(humaAPI, &eventapi.State{Repo: repos.eventRepo, Clients: repos.clientRepo, Meter: svcs.meter, Redaction: svcs.redaction, Payloads: svcs.payloads, EventTypes: svcs.eventTypeUsage, FilterOptions: svcs.eventFilterOptions, Dedup: svcs.eventDedup})
 		auditapi.Register(humaAPI, &auditapi.State{Repo: repos.auditRepo})
-		dispatchjobapi.Register(humaAPI, &dispatchjobapi.State{Repo: repos.dispatchJobRepo})
+		dispatchjobapi.Register(humaAPI, &dispatchjobapi.State{Repo: repos.dispatchJobRepo, Redaction: svcs.redaction, FilterOptions: svcs.jobFilterOptions})
 
 		identityproviderapi.Register(humaAPI, &identityproviderapi.State{
 			Repo: repos.idpRepo,
@@ -346,6 +410,10 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 			Clients:      repos.clientRepo,
 			Applications: repos.applicationRepo,
 		})
+		bff.RegisterAPIActivity(r, &bff.APIActivityState{Repo: repos.apiActivityRepo})
+		analyticsRepo := analytics.NewRepository(pool)
+		bff.RegisterAnalytics(r, &bff.AnalyticsState{Repo: analyticsRepo})
+		bff.RegisterSubscriptionHealth(r, &bff.SubscriptionHealthState{Repo: analyticsRepo, Clients: repos.clientRepo})
 		bff.RegisterDeveloper(r, &bff.DeveloperState{
 			Applications: repos.applicationRepo,
 			Specs:        openapispecs.NewRepository(pool),
@@ -365,7 +433,11 @@ func registerPlatformAPI(r chi.Router, cfg EnvCfg, pool *pgxpool.Pool, uow *usec
 			Grants:     repos.principalGrantRepo,
 			Auth:       svcs.authSvc,
 		})
-		sdkapi.RegisterRoutes(r, &sdkapi.DispatchJobsBatchState{Repo: repos.dispatchJobRepo})
+		sdkState := &sdkapi.DispatchJobsBatchState{Repo: repos.dispatchJobRepo, Payloads: svcs.payloads, Stream: sdkapi.NewStreamGate(sdkapi.StreamConfigFromEnv())}
+		if svcs.callbackCfg.Enabled() {
+			sdkState.Callbacks = repos.callbackRepo
+		}
+		sdkapi.RegisterRoutes(r, sdkState)
 		sdkapi.RegisterAuditRoutes(r, &sdkapi.AuditBatchState{Repo: repos.auditRepo, Apps: repos.applicationRepo, Clients: repos.clientRepo})
 	})
 
@@ -3,27 +3,49 @@ package server
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/envutil"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/apiactivity"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/authservice"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/backchannel"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/grantstore"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/login"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/loginbackoff"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/mfatoken"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/oauthapi"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/provider"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/revocation"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/signingkey"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/twofa"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/branding"
+	dispatchjobapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/api"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/dispatchjob/callback"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/event"
+	eventapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/event/api"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/schemaregistry"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/export"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/mfa"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/notify"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/payloadlimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/egress"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/email"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/encryption"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/filtercache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/ratelimit"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/redact"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/versioncache"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn"
+	webauthnapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn/api"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/secrets"
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/usecasepgx"
 )
 
 // serviceSet bundles the shared services WirePlatform threads through the
@@ -33,10 +55,12 @@ type serviceSet struct {
 	authProvider        *provider.Provider
 	authSvc             *authservice.AuthService
 	encSvc              *encryption.Service
+	encRewriter         *encryption.Rewriter
 	rlStore             ratelimit.Store
-	rlPolicies          ratelimit.Policies
+	rlPolicies          *ratelimit.LivePolicies
 	oauthTokenIPGov     *ratelimit.Governor
 	oauthTokenClientGov *ratelimit.Governor
+	oidcGov             *ratelimit.Governor
 	oauthTokenEP        *oauthapi.State
 	webauthnService     *webauthn.Service
 	emailSvc            email.Service
@@ -47,9 +71,26 @@ type serviceSet struct {
 	twofaPolicy         twofa.Policy
 	loginEP             *login.Endpoint
 	principalVersions   *versioncache.Reader
+	apiActivity         *apiactivity.Recorder
+	sessionRevocations  *revocation.Checker
+	backchannel         *backchannel.Notifier
+	keyRotator          *signingkey.Rotator
+	meter               *metering.Meter
+	maintenance         *maintenance.Mode
+	eventFilterOptions  *filtercache.Cache[eventapi.EventFilterOptionsResponse]
+	jobFilterOptions    *filtercache.Cache[dispatchjobapi.DispatchJobFilterOptionsResponse]
+	eventTypeUsage      *eventtype.UsageTracker
+	eventDedup          *event.Dedup
+	exportCfg           export.Config
+	exportStore         *export.ObjectStore
+	privacyCfg          privacy.Config
+	callbackCfg         callback.Config
+	schemaRegistryCfg   schemaregistry.Config
+	redaction           *redact.Policy
+	payloads            *payloadlimit.Offloader
 }
 
-func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet, error) {
+func buildServices(cfg EnvCfg, pool *pgxpool.Pool, uow *usecasepgx.UnitOfWork, repos *repoSet, sec *secrets.Service) (*serviceSet, error) {
 	svcs := &serviceSet{}
 
 	// ── Auth provider (claims projection + session JWTs) ───────────────
@@ -58,6 +99,12 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 	// boot without filesystem deps. See fc-dev for the persistent-key
 	// path used by local development.
 	signingKey := LoadSigningKeyOrEphemeral(cfg.JWTSigningKeyPath)
+	if cfg.JWTSigningKeyRef != "" {
+		var err error
+		if signingKey, err = signingKeyFromRef(sec, cfg.JWTSigningKeyRef); err != nil {
+			return nil, fmt.Errorf("FC_JWT_SIGNING_KEY_REF: %w", err)
+		}
+	}
 	authProvider, err := provider.NewProvider(provider.Config{
 		Issuer: cfg.JWTIssuer,
 		// Must match authservice's access-token `aud` below so bearers it
@@ -71,6 +118,17 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 	}
 	svcs.authProvider = authProvider
 
+	// Session revocation list: admin revoke-sessions and logout write it,
+	// ValidateSessionToken (bearer middleware + /oauth/authorize) reads it.
+	// Cached per principal for a few seconds, so a revocation takes at most
+	// one TTL to reach every instance.
+	svcs.sessionRevocations = revocation.NewChecker(
+		revocation.NewRepository(pool),
+		envutil.Int("FC_SESSION_REVOCATION_CACHE_SIZE", 10_000),
+		time.Duration(envutil.Int("FC_SESSION_REVOCATION_CACHE_TTL_SECS", 5))*time.Second,
+	)
+	authProvider.SetRevocations(svcs.sessionRevocations)
+
 	// ── Hand-rolled OAuth token service (/oauth/token) ────────────────
 	// authservice signs/validates with the same RSA key the auth provider
 	// loaded, so the JWKS + session-cookie paths line up. encSvc verifies
@@ -93,17 +151,56 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 	if err != nil {
 		return nil, fmt.Errorf("encryption init: %w", err)
 	}
+	if svcs.encSvc != nil {
+		// Stored secrets may be references into Vault / Secrets Manager
+		// (OIDC client secrets, ingest signing secrets); Decrypt resolves
+		// them through the shared, caching secrets service.
+		svcs.encSvc.SetExternal(sec)
+		// Values read under an old app key or format (or from before
+		// FC_ENCRYPTION_KMS_KEY_ID) are written back re-encrypted.
+		if envutil.Bool("FC_ENCRYPTION_REWRITE_ON_READ", true) {
+			svcs.encRewriter = encryption.NewRewriter(pool)
+			svcs.encSvc.SetRewriter(svcs.encRewriter.Enqueue)
+		}
+	}
+
+	// ── JWT key ring ───────────────────────────────────────────────────
+	// Session cookies and OAuth tokens sign with the ring's current key
+	// (kid-stamped) and verify against every key it holds; the JWKS
+	// publishes them all. With FC_JWT_KEY_ROTATION_DAYS set, the rotator
+	// generates keys into iam_jwt_signing_keys (encrypted with the app key)
+	// and every instance converges on the same signer; the configured key
+	// keeps verifying through the overlap window.
+	keyRing, err := buildKeyRing(signingKey, cfg.JWTPreviousPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("jwt key ring: %w", err)
+	}
+	authProvider.SetKeyRing(keyRing)
+	svcs.authSvc.SetKeyRing(keyRing)
+	if rotCfg := signingkey.ConfigFromEnv(); rotCfg.Enabled() {
+		if svcs.encSvc == nil {
+			slog.Warn("JWT key rotation disabled: FLOWCATALYST_APP_KEY is required to store rotated keys")
+		} else {
+			svcs.keyRotator = signingkey.NewRotator(signingkey.NewRepository(pool, svcs.encSvc), keyRing, rotCfg)
+			if err := svcs.keyRotator.Sync(context.Background()); err != nil {
+				return nil, fmt.Errorf("jwt key rotation: %w", err)
+			}
+		}
+	} else if cfg.JWTSigningKeyRef != "" {
+		followSigningKeyRef(sec, cfg.JWTSigningKeyRef, signingKey, keyRing)
+	}
 	// Distributed rate-limit store: Redis when FC_REDIS_URL is reachable,
 	// else Postgres, else Noop (FC_RATE_LIMIT_DISABLE=1). Throttles
 	// /oauth/{token,authorize} per-client_id (+ per-IP via middleware).
 	svcs.rlStore = ratelimit.Build(context.Background(), pool)
-	svcs.rlPolicies = ratelimit.PoliciesFromEnv()
+	svcs.rlPolicies = ratelimit.NewLivePolicies(ratelimit.PoliciesFromEnv())
 	// In-memory per-instance governors layered in front of the distributed
 	// store on /oauth/token (defence-in-depth; 1:1 with Rust's
 	// rate_limit_middleware.rs). They shed a local flood before the network
 	// round-trip; the distributed store remains the cluster-wide ceiling.
 	svcs.oauthTokenIPGov = ratelimit.NewGovernor(ratelimit.OAuthTokenIPGovernorFromEnv())
 	svcs.oauthTokenClientGov = ratelimit.NewGovernor(ratelimit.OAuthTokenClientGovernorFromEnv())
+	svcs.oidcGov = ratelimit.NewGovernor(ratelimit.OIDCBridgeGovernorFromEnv())
 
 	// Principal version cache: backs GET /api/principals/{id}/version, which
 	// SDKs (e.g. the Laravel SDK's opt-in revocation check) poll to catch a
@@ -118,6 +215,15 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 		time.Duration(envutil.Int("FC_PRINCIPAL_VERSION_CACHE_TTL_SECS", 30))*time.Second,
 		repos.principalRepo.LookupVersion,
 	)
+	// Back-channel logout: /oauth/token records which clients a user signs
+	// in to; console logout, /oauth/logout and admin revoke-sessions send
+	// those clients a logout token. Delivery obeys the webhook egress
+	// policy (the URI is admin-supplied).
+	svcs.backchannel = backchannel.New(repos.authRepo.OAuthClients,
+		grantstore.NewClientLoginRepository(pool), svcs.authSvc)
+	if pol, err := egress.FromEnv(); err == nil {
+		svcs.backchannel.SetEgress(pol)
+	}
 	svcs.oauthTokenEP = &oauthapi.State{
 		OAuthClients:      repos.authRepo.OAuthClients,
 		Principals:        repos.principalRepo,
@@ -125,12 +231,20 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 		AuthCodes:         grantstore.NewAuthorizationCodeRepository(pool),
 		RefreshTokens:     grantstore.NewRefreshTokenRepository(pool),
 		PendingAuth:       grantstore.NewPendingAuthRepository(pool),
+		DeviceCodes:       grantstore.NewDeviceCodeRepository(pool),
 		Encryption:        svcs.encSvc,
 		BaseURL:           cfg.JWTIssuer,
 		LoginAttempts:     repos.loginAttemptRepo,
 		RateLimit:         svcs.rlStore,
 		RateLimitPolicies: svcs.rlPolicies,
 		ClientGovernor:    svcs.oauthTokenClientGov,
+		Revocations:       svcs.sessionRevocations,
+		Audit:             repos.auditRepo,
+		Consents:          grantstore.NewConsentRepository(pool),
+		PendingConsents:   grantstore.NewPendingConsentRepository(pool),
+		Scopes:            repos.authRepo.OAuthScopes,
+		Backchannel:       svcs.backchannel,
+		CookieSecure:      !cfg.AuthAllowTestHeaders,
 		// /oauth/authorize treats an invalid/absent session as
 		// redirect-to-login, so it validates the session cookie itself
 		// (it's mounted outside the rejecting auth middleware).
@@ -141,12 +255,29 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 			}
 			return c.Subject, c.IssuedAt, true
 		},
+		// /oauth/logout ends the presented session server-side, like
+		// /auth/logout.
+		EndSession: func(ctx context.Context, token string) (string, bool) {
+			c, err := authProvider.ValidateSessionToken(ctx, token)
+			if err != nil || c == nil {
+				return "", false
+			}
+			if c.ID != "" {
+				if err := svcs.sessionRevocations.RevokeToken(ctx, c.Subject, c.ID, c.ExpiresAt, nil); err != nil {
+					slog.Warn("session revocation on oauth logout failed", "principal", c.Subject, "err", err)
+				}
+			}
+			return c.Subject, true
+		},
 		// Flatten roles → permission ceiling for the granted "scope" claim and
 		// requested-scope narrowing on /oauth/token.
 		FlattenPermissions: authProvider.FlattenPermissions,
 		// Narrows a minted ID token's "roles" claim to an app-scoped OAuth
 		// client's own application(s).
 		FilterRolesForApplications: authProvider.FilterRolesForApplications,
+		// Pins service account tokens to the account's sandbox or
+		// production environment.
+		ServiceAccountEnvironment: repos.serviceAccountRepo.FindEnvironment,
 	}
 
 	// ── Webauthn service ───────────────────────────────────────────────
@@ -202,13 +333,106 @@ func buildServices(cfg EnvCfg, pool *pgxpool.Pool, repos *repoSet) (*serviceSet,
 		// signer so a token issued via either path rotates identically.
 		RefreshTokens: svcs.oauthTokenEP.RefreshTokens,
 		Auth:          svcs.authSvc,
-		// 2FA: challenge/enroll endpoints. (A passkey does not exempt the
-		// password path, so no webauthn dependency here.)
+		// 2FA: challenge/enroll endpoints. A registered passkey can answer
+		// the challenge as the WEBAUTHN method.
 		MFA:       svcs.mfaSvc,
 		MFATokens: svcs.mfaTokens,
-		Notifier:  svcs.notifier,
-		Audit:     repos.auditRepo,
+		Passkeys: &webauthnapi.SecondFactor{
+			Service: svcs.webauthnService,
+			Creds:   repos.webauthnCredRepo,
+			UoW:     uow,
+		},
+		Notifier: svcs.notifier,
+		Audit:    repos.auditRepo,
+		// Logout revokes the presented session token server-side, not
+		// just the cookie.
+		Sessions:    svcs.sessionRevocations,
+		Grants:      repos.principalGrantRepo,
+		Backchannel: svcs.backchannel,
 	})
 
+	// Sampled per-principal API call log behind the admin usage explorer.
+	// Mounted as middleware inside the auth group; its flush loop is
+	// started by WirePlatform.
+	svcs.apiActivity = apiactivity.NewRecorder(apiactivity.ConfigFromEnv(), repos.apiActivityRepo)
+
+	// Per-client usage metering + monthly quotas. Fed by event ingest and
+	// the dispatch-processing callback; its flush loop is started by
+	// WirePlatform.
+	svcs.meter = metering.NewMeter(metering.ConfigFromEnv(), repos.meteringRepo)
+
+	// Platform maintenance mode: the write gate around the routes and its
+	// poll loop, both started by WirePlatform.
+	svcs.maintenance = maintenance.NewMode(maintenance.ConfigFromEnv(), repos.maintenanceRepo)
+
+	// Filter-options caches for the events / dispatch-jobs dashboards. The
+	// stream processor's change feed and the metrics collector are hooked
+	// up by WirePlatform.
+	fcCfg := filtercache.ConfigFromEnv()
+	svcs.eventFilterOptions = filtercache.New[eventapi.EventFilterOptionsResponse]("events", fcCfg)
+	svcs.jobFilterOptions = filtercache.New[dispatchjobapi.DispatchJobFilterOptionsResponse]("dispatch_jobs", fcCfg)
+
+	// Event-type producer tracking + deprecation warnings on ingest. Its
+	// flush loop is started by WirePlatform.
+	svcs.eventTypeUsage = eventtype.NewUsageTracker(repos.eventTypeRepo)
+
+	// Ingest deduplication by source event ID. Its purge loop and metrics
+	// collector are started by WirePlatform.
+	svcs.eventDedup = event.NewDedup(event.DedupConfigFromEnv(), pool)
+
+	// Bulk exports to S3/GCS. Without a destination the API rejects new
+	// exports and no runner starts (WirePlatform).
+	svcs.exportCfg = export.ConfigFromEnv()
+	if svcs.exportCfg.Enabled() {
+		svcs.exportStore, err = export.NewObjectStore(context.Background(), svcs.exportCfg)
+		if err != nil {
+			return nil, fmt.Errorf("export destination: %w", err)
+		}
+	}
+
+	// Dispatch-job status callbacks. Without a signing secret the SDK
+	// create endpoints reject statusCallbackUrl and no runner starts
+	// (WirePlatform).
+	svcs.callbackCfg = callback.ConfigFromEnv()
+
+	// Sync of finalised schemas to an external registry. Without a registry
+	// URL and kind no runner starts (WirePlatform).
+	svcs.schemaRegistryCfg = schemaregistry.ConfigFromEnv()
+
+	// Event / dispatch-job payload size limits. With an offload destination,
+	// event data (and, with claim-check on, dispatch-job payloads) over the
+	// offload threshold goes to object storage and the row keeps a
+	// reference, resolved by the dispatch-processing callback.
+	payloadPolicy := payloadlimit.PolicyFromEnv()
+	var payloadStore payloadlimit.Store
+	if payloadPolicy.OffloadDestination != "" {
+		payloadStore, err = export.NewObjectStore(context.Background(), export.Config{
+			Destination:     payloadPolicy.OffloadDestination,
+			Endpoint:        envOr("FC_PAYLOAD_OFFLOAD_ENDPOINT", ""),
+			Region:          envOr("FC_PAYLOAD_OFFLOAD_REGION", envOr("AWS_REGION", "us-east-1")),
+			AccessKeyID:     envOr("FC_PAYLOAD_OFFLOAD_ACCESS_KEY_ID", ""),
+			SecretAccessKey: envOr("FC_PAYLOAD_OFFLOAD_SECRET_ACCESS_KEY", ""),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("payload offload destination: %w", err)
+		}
+	}
+	svcs.payloads = payloadlimit.New(payloadPolicy, payloadStore)
+
+	// Right-to-erasure purges; the runner always starts (WirePlatform).
+	svcs.privacyCfg = privacy.ConfigFromEnv()
+
+	// Field masking on the /bff event + dispatch-job views.
+	svcs.redaction = redact.PolicyFromEnv()
+
 	return svcs, nil
 }
+
+// reloadRateLimits re-reads the FC_RL_* policies and the governor quotas
+// (config reload).
+func (svcs *serviceSet) reloadRateLimits() {
+	svcs.rlPolicies.Set(ratelimit.PoliciesFromEnv())
+	svcs.oauthTokenIPGov.SetQuota(ratelimit.OAuthTokenIPGovernorFromEnv())
+	svcs.oauthTokenClientGov.SetQuota(ratelimit.OAuthTokenClientGovernorFromEnv())
+	svcs.oidcGov.SetQuota(ratelimit.OIDCBridgeGovernorFromEnv())
+}
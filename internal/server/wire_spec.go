@@ -5,6 +5,8 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/swaggerui"
 )
 
 // registerSpecRoutes mounts the spec + Swagger UI on the PARENT router
@@ -34,7 +36,8 @@ func registerSpecRoutes(r chi.Router, humaAPI huma.API) {
 
 	// Rust serves the spec at /q/openapi and Swagger UI at /swagger-ui;
 	// alias both for drop-in tooling parity. /api/openapi.json is kept for
-	// the existing make/Hey-API codegen tooling.
+	// the existing make/Hey-API codegen tooling, and /swagger matches the
+	// router and outbox admin surfaces.
 	r.Get("/q/openapi", func(w http.ResponseWriter, _ *http.Request) {
 		spec, err := humaAPI.OpenAPI().MarshalJSON()
 		if err != nil {
@@ -44,8 +47,7 @@ func registerSpecRoutes(r chi.Router, humaAPI huma.API) {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write(spec)
 	})
-	r.Get("/swagger-ui", func(w http.ResponseWriter, _ *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		_, _ = w.Write([]byte(swaggerUIHTML))
-	})
+	ui := swaggerui.Handler("FlowCatalyst API", "/q/openapi")
+	r.Method(http.MethodGet, "/swagger-ui", ui)
+	r.Method(http.MethodGet, "/swagger", ui)
 }
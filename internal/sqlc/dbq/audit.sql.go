@@ -15,7 +15,7 @@ const auditFindByID = `-- name: AuditFindByID :one
 
 SELECT a.id, a.entity_type, a.entity_id, a.operation, a.operation_json,
        a.principal_id, p.name AS principal_name,
-       a.application_id, a.client_id, a.performed_at
+       a.application_id, a.client_id, a.performed_at, a.changes
 FROM aud_logs a
 LEFT JOIN iam_principals p ON p.id = a.principal_id
 WHERE a.id = $1
@@ -32,6 +32,7 @@ type AuditFindByIDRow struct {
 	ApplicationID *string         `db:"application_id"`
 	ClientID      *string         `db:"client_id"`
 	PerformedAt   time.Time       `db:"performed_at"`
+	Changes       json.RawMessage `db:"changes"`
 }
 
 // Queries for aud_logs (read-only — writes happen in platformsink).
@@ -49,6 +50,7 @@ func (q *Queries) AuditFindByID(ctx context.Context, id string) (AuditFindByIDRo
 		&i.ApplicationID,
 		&i.ClientID,
 		&i.PerformedAt,
+		&i.Changes,
 	)
 	return i, err
 }
@@ -56,7 +58,7 @@ func (q *Queries) AuditFindByID(ctx context.Context, id string) (AuditFindByIDRo
 const auditFindWithFilters = `-- name: AuditFindWithFilters :many
 SELECT a.id, a.entity_type, a.entity_id, a.operation, a.operation_json,
        a.principal_id, p.name AS principal_name,
-       a.application_id, a.client_id, a.performed_at
+       a.application_id, a.client_id, a.performed_at, a.changes
 FROM aud_logs a
 LEFT JOIN iam_principals p ON p.id = a.principal_id
 WHERE ($1::text IS NULL OR a.entity_type = $1::text)
@@ -91,6 +93,7 @@ type AuditFindWithFiltersRow struct {
 	ApplicationID *string         `db:"application_id"`
 	ClientID      *string         `db:"client_id"`
 	PerformedAt   time.Time       `db:"performed_at"`
+	Changes       json.RawMessage `db:"changes"`
 }
 
 // All filters are optional via the IS-NULL-OR pattern. Limit + offset
@@ -124,6 +127,7 @@ func (q *Queries) AuditFindWithFilters(ctx context.Context, arg AuditFindWithFil
 			&i.ApplicationID,
 			&i.ClientID,
 			&i.PerformedAt,
+			&i.Changes,
 		); err != nil {
 			return nil, err
 		}
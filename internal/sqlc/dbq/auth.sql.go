@@ -435,7 +435,8 @@ func (q *Queries) OAuthClientDelete(ctx context.Context, id string) error {
 const oAuthClientFindAll = `-- name: OAuthClientFindAll :many
 SELECT id, client_id, client_name, client_type, client_secret_ref,
        default_scopes, pkce_required, service_account_principal_id,
-       active, created_at, updated_at
+       active, created_at, updated_at, token_endpoint_auth_method, jwks,
+       first_party, backchannel_logout_uri
 FROM oauth_clients
 ORDER BY client_name
 `
@@ -461,6 +462,10 @@ func (q *Queries) OAuthClientFindAll(ctx context.Context) ([]OauthClient, error)
 			&i.Active,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TokenEndpointAuthMethod,
+			&i.Jwks,
+			&i.FirstParty,
+			&i.BackchannelLogoutUri,
 		); err != nil {
 			return nil, err
 		}
@@ -475,7 +480,8 @@ func (q *Queries) OAuthClientFindAll(ctx context.Context) ([]OauthClient, error)
 const oAuthClientFindByClientID = `-- name: OAuthClientFindByClientID :one
 SELECT id, client_id, client_name, client_type, client_secret_ref,
        default_scopes, pkce_required, service_account_principal_id,
-       active, created_at, updated_at
+       active, created_at, updated_at, token_endpoint_auth_method, jwks,
+       first_party, backchannel_logout_uri
 FROM oauth_clients
 WHERE client_id = $1
 `
@@ -495,6 +501,10 @@ func (q *Queries) OAuthClientFindByClientID(ctx context.Context, clientID string
 		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.FirstParty,
+		&i.BackchannelLogoutUri,
 	)
 	return i, err
 }
@@ -504,7 +514,8 @@ const oAuthClientFindByID = `-- name: OAuthClientFindByID :one
 
 SELECT id, client_id, client_name, client_type, client_secret_ref,
        default_scopes, pkce_required, service_account_principal_id,
-       active, created_at, updated_at
+       active, created_at, updated_at, token_endpoint_auth_method, jwks,
+       first_party, backchannel_logout_uri
 FROM oauth_clients
 WHERE id = $1
 `
@@ -515,9 +526,9 @@ WHERE id = $1
 // The schema also has 3 more junctions (post_logout_redirect_uris,
 // allowed_origins, application_ids) that the Go entity doesn't carry
 // yet — they're a follow-up alongside the entity extension.
-// The Go entity stores Argon2-hashed secrets in client_secret_ref
-// (Rust uses it as a secrets-manager reference; Go uses it as the
-// hash). See HANDOFF.md §4 for the planned PHC-salt fix.
+// The Go entity stores argon2id PHC hashes in client_secret_ref
+// (older rows hold an encrypted secret; Rust uses it as a
+// secrets-manager reference).
 func (q *Queries) OAuthClientFindByID(ctx context.Context, id string) (OauthClient, error) {
 	row := q.db.QueryRow(ctx, oAuthClientFindByID, id)
 	var i OauthClient
@@ -533,6 +544,10 @@ func (q *Queries) OAuthClientFindByID(ctx context.Context, id string) (OauthClie
 		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.FirstParty,
+		&i.BackchannelLogoutUri,
 	)
 	return i, err
 }
@@ -691,8 +706,9 @@ const oAuthClientUpsert = `-- name: OAuthClientUpsert :exec
 INSERT INTO oauth_clients
     (id, client_id, client_name, client_type, client_secret_ref,
      default_scopes, pkce_required, service_account_principal_id,
-     active, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+     active, created_at, updated_at, token_endpoint_auth_method, jwks,
+     first_party, backchannel_logout_uri)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 ON CONFLICT (id) DO UPDATE SET
     client_id = EXCLUDED.client_id,
     client_name = EXCLUDED.client_name,
@@ -702,7 +718,11 @@ ON CONFLICT (id) DO UPDATE SET
     pkce_required = EXCLUDED.pkce_required,
     service_account_principal_id = EXCLUDED.service_account_principal_id,
     active = EXCLUDED.active,
-    updated_at = EXCLUDED.updated_at
+    updated_at = EXCLUDED.updated_at,
+    token_endpoint_auth_method = EXCLUDED.token_endpoint_auth_method,
+    jwks = EXCLUDED.jwks,
+    first_party = EXCLUDED.first_party,
+    backchannel_logout_uri = EXCLUDED.backchannel_logout_uri
 `
 
 type OAuthClientUpsertParams struct {
@@ -717,6 +737,10 @@ type OAuthClientUpsertParams struct {
 	Active                    bool      `db:"active"`
 	CreatedAt                 time.Time `db:"created_at"`
 	UpdatedAt                 time.Time `db:"updated_at"`
+	TokenEndpointAuthMethod   *string   `db:"token_endpoint_auth_method"`
+	Jwks                      *string   `db:"jwks"`
+	FirstParty                bool      `db:"first_party"`
+	BackchannelLogoutUri      *string   `db:"backchannel_logout_uri"`
 }
 
 func (q *Queries) OAuthClientUpsert(ctx context.Context, arg OAuthClientUpsertParams) error {
@@ -732,6 +756,119 @@ func (q *Queries) OAuthClientUpsert(ctx context.Context, arg OAuthClientUpsertPa
 		arg.Active,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.TokenEndpointAuthMethod,
+		arg.Jwks,
+		arg.FirstParty,
+		arg.BackchannelLogoutUri,
+	)
+	return err
+}
+
+const oAuthScopeDelete = `-- name: OAuthScopeDelete :exec
+DELETE FROM oauth_scopes WHERE id = $1
+`
+
+func (q *Queries) OAuthScopeDelete(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, oAuthScopeDelete, id)
+	return err
+}
+
+const oAuthScopeFindAll = `-- name: OAuthScopeFindAll :many
+SELECT id, name, description, created_at, updated_at
+FROM oauth_scopes
+ORDER BY name
+`
+
+func (q *Queries) OAuthScopeFindAll(ctx context.Context) ([]OauthScope, error) {
+	rows, err := q.db.Query(ctx, oAuthScopeFindAll)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OauthScope{}
+	for rows.Next() {
+		var i OauthScope
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const oAuthScopeFindByID = `-- name: OAuthScopeFindByID :one
+
+SELECT id, name, description, created_at, updated_at
+FROM oauth_scopes
+WHERE id = $1
+`
+
+// ── OAuthScope (oauth_scopes) ────────────────────────────────────────
+// Consent-screen text for scopes; Go-only (migration 073).
+func (q *Queries) OAuthScopeFindByID(ctx context.Context, id string) (OauthScope, error) {
+	row := q.db.QueryRow(ctx, oAuthScopeFindByID, id)
+	var i OauthScope
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const oAuthScopeFindByName = `-- name: OAuthScopeFindByName :one
+SELECT id, name, description, created_at, updated_at
+FROM oauth_scopes
+WHERE name = $1
+`
+
+func (q *Queries) OAuthScopeFindByName(ctx context.Context, name string) (OauthScope, error) {
+	row := q.db.QueryRow(ctx, oAuthScopeFindByName, name)
+	var i OauthScope
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const oAuthScopeUpsert = `-- name: OAuthScopeUpsert :exec
+INSERT INTO oauth_scopes (id, name, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET
+    description = EXCLUDED.description,
+    updated_at = EXCLUDED.updated_at
+`
+
+type OAuthScopeUpsertParams struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (q *Queries) OAuthScopeUpsert(ctx context.Context, arg OAuthScopeUpsertParams) error {
+	_, err := q.db.Exec(ctx, oAuthScopeUpsert,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
 	)
 	return err
 }
@@ -114,7 +114,8 @@ SELECT id, external_id, source, kind, code, subject, event_id,
        timeout_seconds, schema_id, status, max_retries, retry_strategy,
        scheduled_for, expires_at, attempt_count, last_attempt_at,
        completed_at, duration_millis, last_error, idempotency_key,
-       created_at, updated_at
+       created_at, updated_at, priority, ack_deadline, acked_at,
+       first_attempt_at, environment
 FROM msg_dispatch_jobs
 WHERE id = $1
 `
@@ -156,6 +157,11 @@ type DispatchJobFindByIDRow struct {
 	IdempotencyKey     *string         `db:"idempotency_key"`
 	CreatedAt          time.Time       `db:"created_at"`
 	UpdatedAt          time.Time       `db:"updated_at"`
+	Priority           string          `db:"priority"`
+	AckDeadline        *time.Time      `db:"ack_deadline"`
+	AckedAt            *time.Time      `db:"acked_at"`
+	FirstAttemptAt     *time.Time      `db:"first_attempt_at"`
+	Environment        string          `db:"environment"`
 }
 
 // Queries for msg_dispatch_jobs + msg_dispatch_job_attempts. The
@@ -210,6 +216,11 @@ func (q *Queries) DispatchJobFindByID(ctx context.Context, id string) (DispatchJ
 		&i.IdempotencyKey,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Priority,
+		&i.AckDeadline,
+		&i.AckedAt,
+		&i.FirstAttemptAt,
+		&i.Environment,
 	)
 	return i, err
 }
@@ -221,10 +232,11 @@ INSERT INTO msg_dispatch_jobs
      service_account_id, client_id, subscription_id, mode, dispatch_pool_id,
      message_group, sequence, timeout_seconds, schema_id, status, max_retries,
      retry_strategy, scheduled_for, expires_at, attempt_count, last_attempt_at,
-     completed_at, duration_millis, last_error, idempotency_key, created_at, updated_at)
+     completed_at, duration_millis, last_error, idempotency_key, created_at, updated_at,
+     priority, environment)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
         $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26,
-        $27, $28, $29, $30, $31, $32, $33, $34, $35, $36)
+        $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38)
 `
 
 type DispatchJobInsertParams struct {
@@ -264,6 +276,8 @@ type DispatchJobInsertParams struct {
 	IdempotencyKey     *string         `db:"idempotency_key"`
 	CreatedAt          time.Time       `db:"created_at"`
 	UpdatedAt          time.Time       `db:"updated_at"`
+	Priority           string          `db:"priority"`
+	Environment        string          `db:"environment"`
 }
 
 func (q *Queries) DispatchJobInsert(ctx context.Context, arg DispatchJobInsertParams) error {
@@ -304,6 +318,8 @@ func (q *Queries) DispatchJobInsert(ctx context.Context, arg DispatchJobInsertPa
 		arg.IdempotencyKey,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Priority,
+		arg.Environment,
 	)
 	return err
 }
@@ -314,7 +330,7 @@ UPDATE msg_dispatch_jobs
        completed_at = $2,
        duration_millis = $3,
        updated_at = $2
- WHERE id = $1
+ WHERE id = $1 AND acked_at IS NULL
 `
 
 type DispatchJobMarkCompletedParams struct {
@@ -336,7 +352,7 @@ UPDATE msg_dispatch_jobs
        duration_millis = $3,
        last_error = $4,
        updated_at = $2
- WHERE id = $1
+ WHERE id = $1 AND acked_at IS NULL
 `
 
 type DispatchJobMarkFailedParams struct {
@@ -361,8 +377,9 @@ const dispatchJobMarkInProgress = `-- name: DispatchJobMarkInProgress :exec
 UPDATE msg_dispatch_jobs
    SET status = 'PROCESSING',
        last_attempt_at = $2,
+       first_attempt_at = COALESCE(first_attempt_at, $2),
        updated_at = $2
- WHERE id = $1
+ WHERE id = $1 AND acked_at IS NULL
 `
 
 type DispatchJobMarkInProgressParams struct {
@@ -370,8 +387,10 @@ type DispatchJobMarkInProgressParams struct {
 	LastAttemptAt *time.Time `db:"last_attempt_at"`
 }
 
-// Status → PROCESSING. Stamps last_attempt_at. Called by the router
-// immediately before the first delivery attempt.
+// Status → PROCESSING. Stamps last_attempt_at, and first_attempt_at on
+// the first attempt. Called by the router
+// immediately before the first delivery attempt. This and the other
+// delivery-path transitions skip a job its receiver has acknowledged.
 func (q *Queries) DispatchJobMarkInProgress(ctx context.Context, arg DispatchJobMarkInProgressParams) error {
 	_, err := q.db.Exec(ctx, dispatchJobMarkInProgress, arg.ID, arg.LastAttemptAt)
 	return err
@@ -385,7 +404,7 @@ UPDATE msg_dispatch_jobs
        last_attempt_at = NOW(),
        status = 'PENDING',
        updated_at = NOW()
- WHERE id = $1
+ WHERE id = $1 AND acked_at IS NULL
 `
 
 type DispatchJobScheduleRetryParams struct {
@@ -22,7 +22,8 @@ func (q *Queries) EventTypeDelete(ctx context.Context, id string) error {
 
 const eventTypeFindByApplication = `-- name: EventTypeFindByApplication :many
 SELECT id, code, name, description, status, source, client_scoped,
-       application, subdomain, aggregate, created_at, updated_at, created_by
+       application, subdomain, aggregate, created_at, updated_at, created_by,
+       deprecated_at, sunset_at, deprecation_note, environment
 FROM msg_event_types
 WHERE application = $1
 ORDER BY code
@@ -51,6 +52,10 @@ func (q *Queries) EventTypeFindByApplication(ctx context.Context, application st
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.CreatedBy,
+			&i.DeprecatedAt,
+			&i.SunsetAt,
+			&i.DeprecationNote,
+			&i.Environment,
 		); err != nil {
 			return nil, err
 		}
@@ -64,7 +69,8 @@ func (q *Queries) EventTypeFindByApplication(ctx context.Context, application st
 
 const eventTypeFindByCode = `-- name: EventTypeFindByCode :one
 SELECT id, code, name, description, status, source, client_scoped,
-       application, subdomain, aggregate, created_at, updated_at, created_by
+       application, subdomain, aggregate, created_at, updated_at, created_by,
+       deprecated_at, sunset_at, deprecation_note, environment
 FROM msg_event_types
 WHERE code = $1
 `
@@ -86,6 +92,10 @@ func (q *Queries) EventTypeFindByCode(ctx context.Context, code string) (MsgEven
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.CreatedBy,
+		&i.DeprecatedAt,
+		&i.SunsetAt,
+		&i.DeprecationNote,
+		&i.Environment,
 	)
 	return i, err
 }
@@ -93,7 +103,8 @@ func (q *Queries) EventTypeFindByCode(ctx context.Context, code string) (MsgEven
 const eventTypeFindByID = `-- name: EventTypeFindByID :one
 
 SELECT id, code, name, description, status, source, client_scoped,
-       application, subdomain, aggregate, created_at, updated_at, created_by
+       application, subdomain, aggregate, created_at, updated_at, created_by,
+       deprecated_at, sunset_at, deprecation_note, environment
 FROM msg_event_types
 WHERE id = $1
 `
@@ -118,6 +129,10 @@ func (q *Queries) EventTypeFindByID(ctx context.Context, id string) (MsgEventTyp
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.CreatedBy,
+		&i.DeprecatedAt,
+		&i.SunsetAt,
+		&i.DeprecationNote,
+		&i.Environment,
 	)
 	return i, err
 }
@@ -125,31 +140,39 @@ func (q *Queries) EventTypeFindByID(ctx context.Context, id string) (MsgEventTyp
 const eventTypeUpsertByCode = `-- name: EventTypeUpsertByCode :exec
 INSERT INTO msg_event_types
     (id, code, name, description, status, source, client_scoped,
-     application, subdomain, aggregate, created_by, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+     application, subdomain, aggregate, created_by, created_at, updated_at,
+     deprecated_at, sunset_at, deprecation_note, environment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 ON CONFLICT (code) DO UPDATE SET
     name = EXCLUDED.name,
     description = EXCLUDED.description,
     status = EXCLUDED.status,
     source = EXCLUDED.source,
     client_scoped = EXCLUDED.client_scoped,
-    updated_at = EXCLUDED.updated_at
+    updated_at = EXCLUDED.updated_at,
+    deprecated_at = EXCLUDED.deprecated_at,
+    sunset_at = EXCLUDED.sunset_at,
+    deprecation_note = EXCLUDED.deprecation_note
 `
 
 type EventTypeUpsertByCodeParams struct {
-	ID           string    `db:"id"`
-	Code         string    `db:"code"`
-	Name         string    `db:"name"`
-	Description  *string   `db:"description"`
-	Status       string    `db:"status"`
-	Source       string    `db:"source"`
-	ClientScoped bool      `db:"client_scoped"`
-	Application  string    `db:"application"`
-	Subdomain    string    `db:"subdomain"`
-	Aggregate    string    `db:"aggregate"`
-	CreatedBy    *string   `db:"created_by"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID              string     `db:"id"`
+	Code            string     `db:"code"`
+	Name            string     `db:"name"`
+	Description     *string    `db:"description"`
+	Status          string     `db:"status"`
+	Source          string     `db:"source"`
+	ClientScoped    bool       `db:"client_scoped"`
+	Application     string     `db:"application"`
+	Subdomain       string     `db:"subdomain"`
+	Aggregate       string     `db:"aggregate"`
+	CreatedBy       *string    `db:"created_by"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+	DeprecatedAt    *time.Time `db:"deprecated_at"`
+	SunsetAt        *time.Time `db:"sunset_at"`
+	DeprecationNote *string    `db:"deprecation_note"`
+	Environment     string     `db:"environment"`
 }
 
 func (q *Queries) EventTypeUpsertByCode(ctx context.Context, arg EventTypeUpsertByCodeParams) error {
@@ -167,6 +190,10 @@ func (q *Queries) EventTypeUpsertByCode(ctx context.Context, arg EventTypeUpsert
 		arg.CreatedBy,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.DeprecatedAt,
+		arg.SunsetAt,
+		arg.DeprecationNote,
+		arg.Environment,
 	)
 	return err
 }
@@ -174,8 +201,9 @@ func (q *Queries) EventTypeUpsertByCode(ctx context.Context, arg EventTypeUpsert
 const eventTypeUpsertByID = `-- name: EventTypeUpsertByID :exec
 INSERT INTO msg_event_types
     (id, code, name, description, status, source, client_scoped,
-     application, subdomain, aggregate, created_by, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+     application, subdomain, aggregate, created_by, created_at, updated_at,
+     deprecated_at, sunset_at, deprecation_note, environment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 ON CONFLICT (id) DO UPDATE SET
     code = EXCLUDED.code,
     name = EXCLUDED.name,
@@ -186,23 +214,31 @@ ON CONFLICT (id) DO UPDATE SET
     application = EXCLUDED.application,
     subdomain = EXCLUDED.subdomain,
     aggregate = EXCLUDED.aggregate,
-    updated_at = EXCLUDED.updated_at
+    updated_at = EXCLUDED.updated_at,
+    deprecated_at = EXCLUDED.deprecated_at,
+    sunset_at = EXCLUDED.sunset_at,
+    deprecation_note = EXCLUDED.deprecation_note,
+    environment = EXCLUDED.environment
 `
 
 type EventTypeUpsertByIDParams struct {
-	ID           string    `db:"id"`
-	Code         string    `db:"code"`
-	Name         string    `db:"name"`
-	Description  *string   `db:"description"`
-	Status       string    `db:"status"`
-	Source       string    `db:"source"`
-	ClientScoped bool      `db:"client_scoped"`
-	Application  string    `db:"application"`
-	Subdomain    string    `db:"subdomain"`
-	Aggregate    string    `db:"aggregate"`
-	CreatedBy    *string   `db:"created_by"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID              string     `db:"id"`
+	Code            string     `db:"code"`
+	Name            string     `db:"name"`
+	Description     *string    `db:"description"`
+	Status          string     `db:"status"`
+	Source          string     `db:"source"`
+	ClientScoped    bool       `db:"client_scoped"`
+	Application     string     `db:"application"`
+	Subdomain       string     `db:"subdomain"`
+	Aggregate       string     `db:"aggregate"`
+	CreatedBy       *string    `db:"created_by"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+	DeprecatedAt    *time.Time `db:"deprecated_at"`
+	SunsetAt        *time.Time `db:"sunset_at"`
+	DeprecationNote *string    `db:"deprecation_note"`
+	Environment     string     `db:"environment"`
 }
 
 func (q *Queries) EventTypeUpsertByID(ctx context.Context, arg EventTypeUpsertByIDParams) error {
@@ -220,6 +256,10 @@ func (q *Queries) EventTypeUpsertByID(ctx context.Context, arg EventTypeUpsertBy
 		arg.CreatedBy,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.DeprecatedAt,
+		arg.SunsetAt,
+		arg.DeprecationNote,
+		arg.Environment,
 	)
 	return err
 }
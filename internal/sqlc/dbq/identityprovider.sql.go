@@ -79,7 +79,7 @@ func (q *Queries) IdentityProviderDomainsForIDPs(ctx context.Context, idpIds []s
 const identityProviderFindAll = `-- name: IdentityProviderFindAll :many
 SELECT id, code, name, type, oidc_issuer_url, oidc_client_id,
        oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-       created_at, updated_at
+       created_at, updated_at, idp_type
 FROM oauth_identity_providers
 ORDER BY code
 `
@@ -105,6 +105,7 @@ func (q *Queries) IdentityProviderFindAll(ctx context.Context) ([]OauthIdentityP
 			&i.OidcIssuerPattern,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.IdpType,
 		); err != nil {
 			return nil, err
 		}
@@ -119,7 +120,7 @@ func (q *Queries) IdentityProviderFindAll(ctx context.Context) ([]OauthIdentityP
 const identityProviderFindByCode = `-- name: IdentityProviderFindByCode :one
 SELECT id, code, name, type, oidc_issuer_url, oidc_client_id,
        oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-       created_at, updated_at
+       created_at, updated_at, idp_type
 FROM oauth_identity_providers
 WHERE code = $1
 `
@@ -139,6 +140,7 @@ func (q *Queries) IdentityProviderFindByCode(ctx context.Context, code string) (
 		&i.OidcIssuerPattern,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IdpType,
 	)
 	return i, err
 }
@@ -147,7 +149,7 @@ const identityProviderFindByID = `-- name: IdentityProviderFindByID :one
 
 SELECT id, code, name, type, oidc_issuer_url, oidc_client_id,
        oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-       created_at, updated_at
+       created_at, updated_at, idp_type
 FROM oauth_identity_providers
 WHERE id = $1
 `
@@ -171,6 +173,7 @@ func (q *Queries) IdentityProviderFindByID(ctx context.Context, id string) (Oaut
 		&i.OidcIssuerPattern,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IdpType,
 	)
 	return i, err
 }
@@ -179,8 +182,8 @@ const identityProviderUpsert = `-- name: IdentityProviderUpsert :exec
 INSERT INTO oauth_identity_providers
     (id, code, name, type, oidc_issuer_url, oidc_client_id,
      oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-     created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+     created_at, updated_at, idp_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 ON CONFLICT (id) DO UPDATE SET
     code = EXCLUDED.code,
     name = EXCLUDED.name,
@@ -189,6 +192,7 @@ ON CONFLICT (id) DO UPDATE SET
     oidc_client_secret_ref = EXCLUDED.oidc_client_secret_ref,
     oidc_multi_tenant = EXCLUDED.oidc_multi_tenant,
     oidc_issuer_pattern = EXCLUDED.oidc_issuer_pattern,
+    idp_type = EXCLUDED.idp_type,
     updated_at = EXCLUDED.updated_at
 `
 
@@ -204,6 +208,7 @@ type IdentityProviderUpsertParams struct {
 	OidcIssuerPattern   *string   `db:"oidc_issuer_pattern"`
 	CreatedAt           time.Time `db:"created_at"`
 	UpdatedAt           time.Time `db:"updated_at"`
+	IdpType             string    `db:"idp_type"`
 }
 
 func (q *Queries) IdentityProviderUpsert(ctx context.Context, arg IdentityProviderUpsertParams) error {
@@ -219,6 +224,7 @@ func (q *Queries) IdentityProviderUpsert(ctx context.Context, arg IdentityProvid
 		arg.OidcIssuerPattern,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.IdpType,
 	)
 	return err
 }
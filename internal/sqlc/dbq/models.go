@@ -83,6 +83,18 @@ type AudLog struct {
 	PerformedAt   time.Time       `db:"performed_at"`
 	ApplicationID *string         `db:"application_id"`
 	ClientID      *string         `db:"client_id"`
+	Changes       json.RawMessage `db:"changes"`
+}
+
+type IamApiActivity struct {
+	ID           int64     `db:"id"`
+	PrincipalID  string    `db:"principal_id"`
+	Method       string    `db:"method"`
+	Route        string    `db:"route"`
+	Status       int16     `db:"status"`
+	DurationMs   int32     `db:"duration_ms"`
+	SampleWeight int32     `db:"sample_weight"`
+	OccurredAt   time.Time `db:"occurred_at"`
 }
 
 type IamAuthorizationCode struct {
@@ -174,15 +186,18 @@ type IamPasswordResetToken struct {
 }
 
 type IamPermission struct {
-	ID          string    `db:"id"`
-	Code        string    `db:"code"`
-	Subdomain   string    `db:"subdomain"`
-	Context     string    `db:"context"`
-	Aggregate   string    `db:"aggregate"`
-	Action      string    `db:"action"`
-	Description *string   `db:"description"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID            string    `db:"id"`
+	Code          string    `db:"code"`
+	Subdomain     string    `db:"subdomain"`
+	Context       string    `db:"context"`
+	Aggregate     string    `db:"aggregate"`
+	Action        string    `db:"action"`
+	Description   *string   `db:"description"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+	ApplicationID *string   `db:"application_id"`
+	Category      *string   `db:"category"`
+	Source        string    `db:"source"`
 }
 
 type IamPrincipal struct {
@@ -205,6 +220,7 @@ type IamPrincipal struct {
 	AllApplications          bool       `db:"all_applications"`
 	DevClientSecretRef       *string    `db:"dev_client_secret_ref"`
 	DevClientSecretUpdatedAt *time.Time `db:"dev_client_secret_updated_at"`
+	PasswordChangedAt        *time.Time `db:"password_changed_at"`
 }
 
 type IamPrincipalApplicationAccess struct {
@@ -294,6 +310,7 @@ type IamServiceAccount struct {
 	UpdatedAt                  time.Time  `db:"updated_at"`
 	Scope                      *string    `db:"scope"`
 	ClientIds                  []string   `db:"client_ids"`
+	Environment                string     `db:"environment"`
 }
 
 type IamUserMfaMethod struct {
@@ -367,6 +384,10 @@ type MsgDispatchJob struct {
 	UpdatedAt          time.Time       `db:"updated_at"`
 	ProjectedAt        *time.Time      `db:"projected_at"`
 	QueuedAt           *time.Time      `db:"queued_at"`
+	Priority           string          `db:"priority"`
+	AckDeadline        *time.Time      `db:"ack_deadline"`
+	AckedAt            *time.Time      `db:"acked_at"`
+	FirstAttemptAt     *time.Time      `db:"first_attempt_at"`
 }
 
 type MsgDispatchJobAttempt struct {
@@ -480,19 +501,23 @@ type MsgEventProjectionFeed struct {
 }
 
 type MsgEventType struct {
-	ID           string    `db:"id"`
-	Code         string    `db:"code"`
-	Name         string    `db:"name"`
-	Description  *string   `db:"description"`
-	Status       string    `db:"status"`
-	Source       string    `db:"source"`
-	ClientScoped bool      `db:"client_scoped"`
-	Application  string    `db:"application"`
-	Subdomain    string    `db:"subdomain"`
-	Aggregate    string    `db:"aggregate"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
-	CreatedBy    *string   `db:"created_by"`
+	ID              string     `db:"id"`
+	Code            string     `db:"code"`
+	Name            string     `db:"name"`
+	Description     *string    `db:"description"`
+	Status          string     `db:"status"`
+	Source          string     `db:"source"`
+	ClientScoped    bool       `db:"client_scoped"`
+	Application     string     `db:"application"`
+	Subdomain       string     `db:"subdomain"`
+	Aggregate       string     `db:"aggregate"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+	CreatedBy       *string    `db:"created_by"`
+	DeprecatedAt    *time.Time `db:"deprecated_at"`
+	SunsetAt        *time.Time `db:"sunset_at"`
+	DeprecationNote *string    `db:"deprecation_note"`
+	Environment     string     `db:"environment"`
 }
 
 type MsgEventTypeSpecVersion struct {
@@ -599,32 +624,54 @@ type MsgScheduledJobInstanceLog struct {
 }
 
 type MsgSubscription struct {
-	ID               string    `db:"id"`
-	Code             string    `db:"code"`
-	ApplicationCode  *string   `db:"application_code"`
-	Name             string    `db:"name"`
-	Description      *string   `db:"description"`
-	ClientID         *string   `db:"client_id"`
-	ClientIdentifier *string   `db:"client_identifier"`
-	ClientScoped     bool      `db:"client_scoped"`
-	Target           string    `db:"target"`
-	Queue            *string   `db:"queue"`
-	Source           string    `db:"source"`
-	Status           string    `db:"status"`
-	MaxAgeSeconds    int32     `db:"max_age_seconds"`
-	DispatchPoolID   *string   `db:"dispatch_pool_id"`
-	DispatchPoolCode *string   `db:"dispatch_pool_code"`
-	DelaySeconds     int32     `db:"delay_seconds"`
-	Sequence         int32     `db:"sequence"`
-	Mode             string    `db:"mode"`
-	TimeoutSeconds   int32     `db:"timeout_seconds"`
-	MaxRetries       int32     `db:"max_retries"`
-	ServiceAccountID *string   `db:"service_account_id"`
-	DataOnly         bool      `db:"data_only"`
-	CreatedAt        time.Time `db:"created_at"`
-	UpdatedAt        time.Time `db:"updated_at"`
-	ConnectionID     *string   `db:"connection_id"`
-	CreatedBy        *string   `db:"created_by"`
+	ID                 string          `db:"id"`
+	Code               string          `db:"code"`
+	ApplicationCode    *string         `db:"application_code"`
+	Name               string          `db:"name"`
+	Description        *string         `db:"description"`
+	ClientID           *string         `db:"client_id"`
+	ClientIdentifier   *string         `db:"client_identifier"`
+	ClientScoped       bool            `db:"client_scoped"`
+	Target             string          `db:"target"`
+	Queue              *string         `db:"queue"`
+	Source             string          `db:"source"`
+	Status             string          `db:"status"`
+	MaxAgeSeconds      int32           `db:"max_age_seconds"`
+	DispatchPoolID     *string         `db:"dispatch_pool_id"`
+	DispatchPoolCode   *string         `db:"dispatch_pool_code"`
+	DelaySeconds       int32           `db:"delay_seconds"`
+	Sequence           int32           `db:"sequence"`
+	Mode               string          `db:"mode"`
+	TimeoutSeconds     int32           `db:"timeout_seconds"`
+	MaxRetries         int32           `db:"max_retries"`
+	ServiceAccountID   *string         `db:"service_account_id"`
+	DataOnly           bool            `db:"data_only"`
+	CreatedAt          time.Time       `db:"created_at"`
+	UpdatedAt          time.Time       `db:"updated_at"`
+	ConnectionID       *string         `db:"connection_id"`
+	CreatedBy          *string         `db:"created_by"`
+	Priority           string          `db:"priority"`
+	HonorRetryAfter    bool            `db:"honor_retry_after"`
+	DeliveryFormat     string          `db:"delivery_format"`
+	DeliveryWindow     json.RawMessage `db:"delivery_window"`
+	AllowPrivateTarget bool            `db:"allow_private_target"`
+	EgressProxy        *string         `db:"egress_proxy"`
+	Weight             int32           `db:"weight"`
+	AckTimeoutSeconds  *int32          `db:"ack_timeout_seconds"`
+	DeliveryHeaders    []string        `db:"delivery_headers"`
+	Environment        string          `db:"environment"`
+	TapSamplePercent   *float64        `db:"tap_sample_percent"`
+	TapExpiresAt       *time.Time      `db:"tap_expires_at"`
+}
+
+type MsgSubscriptionConfigSchema struct {
+	ID              string          `db:"id"`
+	ApplicationCode *string         `db:"application_code"`
+	MediationType   string          `db:"mediation_type"`
+	Description     *string         `db:"description"`
+	Fields          json.RawMessage `db:"fields"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
 }
 
 type MsgSubscriptionCustomConfig struct {
@@ -640,6 +687,22 @@ type MsgSubscriptionEventType struct {
 	EventTypeID    *string `db:"event_type_id"`
 	EventTypeCode  string  `db:"event_type_code"`
 	SpecVersion    *string `db:"spec_version"`
+	Filter         *string `db:"filter"`
+}
+
+type MsgSubscriptionTargetAuth struct {
+	SubscriptionID string          `db:"subscription_id"`
+	AuthType       string          `db:"auth_type"`
+	Config         json.RawMessage `db:"config"`
+	UpdatedAt      time.Time       `db:"updated_at"`
+}
+
+type MsgSubscriptionTransform struct {
+	SubscriptionID string    `db:"subscription_id"`
+	Engine         string    `db:"engine"`
+	Template       string    `db:"template"`
+	ContentType    string    `db:"content_type"`
+	UpdatedAt      time.Time `db:"updated_at"`
 }
 
 type OauthClient struct {
@@ -654,6 +717,10 @@ type OauthClient struct {
 	Active                    bool      `db:"active"`
 	CreatedAt                 time.Time `db:"created_at"`
 	UpdatedAt                 time.Time `db:"updated_at"`
+	TokenEndpointAuthMethod   *string   `db:"token_endpoint_auth_method"`
+	Jwks                      *string   `db:"jwks"`
+	FirstParty                bool      `db:"first_party"`
+	BackchannelLogoutUri      *string   `db:"backchannel_logout_uri"`
 }
 
 type OauthClientAllowedOrigin struct {
@@ -681,6 +748,14 @@ type OauthClientRedirectUri struct {
 	RedirectUri   string `db:"redirect_uri"`
 }
 
+type OauthConsent struct {
+	PrincipalID string    `db:"principal_id"`
+	ClientID    string    `db:"client_id"`
+	Scopes      []string  `db:"scopes"`
+	GrantedAt   time.Time `db:"granted_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
 type OauthIdentityProvider struct {
 	ID                  string    `db:"id"`
 	Code                string    `db:"code"`
@@ -693,6 +768,7 @@ type OauthIdentityProvider struct {
 	OidcIssuerPattern   *string   `db:"oidc_issuer_pattern"`
 	CreatedAt           time.Time `db:"created_at"`
 	UpdatedAt           time.Time `db:"updated_at"`
+	IdpType             string    `db:"idp_type"`
 }
 
 type OauthIdentityProviderAllowedDomain struct {
@@ -742,6 +818,14 @@ type OauthOidcPayload struct {
 	CreatedAt  time.Time       `db:"created_at"`
 }
 
+type OauthScope struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
 type TntAnchorDomain struct {
 	ID        string    `db:"id"`
 	Domain    string    `db:"domain"`
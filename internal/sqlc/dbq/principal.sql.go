@@ -45,7 +45,7 @@ const principalFindAll = `-- name: PrincipalFindAll :many
 SELECT id, type, scope, client_id, application_id, name, active,
        email, email_domain, idp_type, external_idp_id, password_hash,
        last_login_at, service_account_id, created_at, updated_at, all_applications,
-       dev_client_secret_ref, dev_client_secret_updated_at
+       dev_client_secret_ref, dev_client_secret_updated_at, password_changed_at
 FROM iam_principals
 ORDER BY created_at DESC
 `
@@ -79,6 +79,7 @@ func (q *Queries) PrincipalFindAll(ctx context.Context) ([]IamPrincipal, error)
 			&i.AllApplications,
 			&i.DevClientSecretRef,
 			&i.DevClientSecretUpdatedAt,
+			&i.PasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -94,7 +95,7 @@ const principalFindByEmail = `-- name: PrincipalFindByEmail :one
 SELECT id, type, scope, client_id, application_id, name, active,
        email, email_domain, idp_type, external_idp_id, password_hash,
        last_login_at, service_account_id, created_at, updated_at, all_applications,
-       dev_client_secret_ref, dev_client_secret_updated_at
+       dev_client_secret_ref, dev_client_secret_updated_at, password_changed_at
 FROM iam_principals
 WHERE type = 'USER' AND LOWER(email) = $1
 `
@@ -126,6 +127,7 @@ func (q *Queries) PrincipalFindByEmail(ctx context.Context, email *string) (IamP
 		&i.AllApplications,
 		&i.DevClientSecretRef,
 		&i.DevClientSecretUpdatedAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
@@ -135,7 +137,7 @@ const principalFindByID = `-- name: PrincipalFindByID :one
 SELECT id, type, scope, client_id, application_id, name, active,
        email, email_domain, idp_type, external_idp_id, password_hash,
        last_login_at, service_account_id, created_at, updated_at, all_applications,
-       dev_client_secret_ref, dev_client_secret_updated_at
+       dev_client_secret_ref, dev_client_secret_updated_at, password_changed_at
 FROM iam_principals
 WHERE id = $1
 `
@@ -144,11 +146,11 @@ WHERE id = $1
 //
 // The schema stores user-identity fields as flat columns (email, email_domain,
 // idp_type, external_idp_id, password_hash, last_login_at, dev_client_secret_ref,
-// dev_client_secret_updated_at) rather than the JSONB blobs the Go entity
-// carries. Mapping happens in repository.go. Column order in every
+// dev_client_secret_updated_at, password_changed_at) rather than the JSONB
+// blobs the Go entity carries. Mapping happens in repository.go. Column order in every
 // SELECT/INSERT list must match the table's physical column order
-// (dev_client_secret_ref/dev_client_secret_updated_at last — appended by
-// migration 039's ALTER TABLE) so sqlc maps rows onto the shared
+// (dev_client_secret_ref/dev_client_secret_updated_at, then password_changed_at
+// last — appended by migrations 039 and 059) so sqlc maps rows onto the shared
 // IamPrincipal model instead of generating a bespoke per-query Row type.
 func (q *Queries) PrincipalFindByID(ctx context.Context, id string) (IamPrincipal, error) {
 	row := q.db.QueryRow(ctx, principalFindByID, id)
@@ -173,6 +175,7 @@ func (q *Queries) PrincipalFindByID(ctx context.Context, id string) (IamPrincipa
 		&i.AllApplications,
 		&i.DevClientSecretRef,
 		&i.DevClientSecretUpdatedAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
@@ -181,7 +184,7 @@ const principalFindByRole = `-- name: PrincipalFindByRole :many
 SELECT p.id, p.type, p.scope, p.client_id, p.application_id, p.name, p.active,
        p.email, p.email_domain, p.idp_type, p.external_idp_id, p.password_hash,
        p.last_login_at, p.service_account_id, p.created_at, p.updated_at, p.all_applications,
-       p.dev_client_secret_ref, p.dev_client_secret_updated_at
+       p.dev_client_secret_ref, p.dev_client_secret_updated_at, p.password_changed_at
 FROM iam_principals p
 JOIN iam_principal_roles pr ON pr.principal_id = p.id
 WHERE pr.role_name = $1
@@ -219,6 +222,7 @@ func (q *Queries) PrincipalFindByRole(ctx context.Context, roleName string) ([]I
 			&i.AllApplications,
 			&i.DevClientSecretRef,
 			&i.DevClientSecretUpdatedAt,
+			&i.PasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -234,7 +238,7 @@ const principalFindByServiceAccount = `-- name: PrincipalFindByServiceAccount :o
 SELECT id, type, scope, client_id, application_id, name, active,
        email, email_domain, idp_type, external_idp_id, password_hash,
        last_login_at, service_account_id, created_at, updated_at, all_applications,
-       dev_client_secret_ref, dev_client_secret_updated_at
+       dev_client_secret_ref, dev_client_secret_updated_at, password_changed_at
 FROM iam_principals
 WHERE type = 'SERVICE' AND service_account_id = $1
 `
@@ -262,6 +266,7 @@ func (q *Queries) PrincipalFindByServiceAccount(ctx context.Context, serviceAcco
 		&i.AllApplications,
 		&i.DevClientSecretRef,
 		&i.DevClientSecretUpdatedAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
@@ -271,8 +276,8 @@ INSERT INTO iam_principals
     (id, type, scope, client_id, application_id, name, active,
      email, email_domain, idp_type, external_idp_id, password_hash,
      last_login_at, service_account_id, all_applications, created_at, updated_at,
-     dev_client_secret_ref, dev_client_secret_updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+     dev_client_secret_ref, dev_client_secret_updated_at, password_changed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 ON CONFLICT (id) DO UPDATE SET
     type = EXCLUDED.type,
     scope = EXCLUDED.scope,
@@ -290,7 +295,8 @@ ON CONFLICT (id) DO UPDATE SET
     all_applications = EXCLUDED.all_applications,
     updated_at = EXCLUDED.updated_at,
     dev_client_secret_ref = EXCLUDED.dev_client_secret_ref,
-    dev_client_secret_updated_at = EXCLUDED.dev_client_secret_updated_at
+    dev_client_secret_updated_at = EXCLUDED.dev_client_secret_updated_at,
+    password_changed_at = EXCLUDED.password_changed_at
 `
 
 type PrincipalUpsertParams struct {
@@ -313,6 +319,7 @@ type PrincipalUpsertParams struct {
 	UpdatedAt                time.Time  `db:"updated_at"`
 	DevClientSecretRef       *string    `db:"dev_client_secret_ref"`
 	DevClientSecretUpdatedAt *time.Time `db:"dev_client_secret_updated_at"`
+	PasswordChangedAt        *time.Time `db:"password_changed_at"`
 }
 
 func (q *Queries) PrincipalUpsert(ctx context.Context, arg PrincipalUpsertParams) error {
@@ -336,6 +343,7 @@ func (q *Queries) PrincipalUpsert(ctx context.Context, arg PrincipalUpsertParams
 		arg.UpdatedAt,
 		arg.DevClientSecretRef,
 		arg.DevClientSecretUpdatedAt,
+		arg.PasswordChangedAt,
 	)
 	return err
 }
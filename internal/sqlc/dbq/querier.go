@@ -159,9 +159,9 @@ type Querier interface {
 	// The schema also has 3 more junctions (post_logout_redirect_uris,
 	// allowed_origins, application_ids) that the Go entity doesn't carry
 	// yet — they're a follow-up alongside the entity extension.
-	// The Go entity stores Argon2-hashed secrets in client_secret_ref
-	// (Rust uses it as a secrets-manager reference; Go uses it as the
-	// hash). See HANDOFF.md §4 for the planned PHC-salt fix.
+	// The Go entity stores argon2id PHC hashes in client_secret_ref
+	// (older rows hold an encrypted secret; Rust uses it as a
+	// secrets-manager reference).
 	OAuthClientFindByID(ctx context.Context, id string) (OauthClient, error)
 	OAuthClientGrantTypeInsert(ctx context.Context, arg OAuthClientGrantTypeInsertParams) error
 	OAuthClientGrantTypesClear(ctx context.Context, oauthClientID string) error
@@ -182,6 +182,13 @@ type Querier interface {
 	OAuthPayloadInsert(ctx context.Context, arg OAuthPayloadInsertParams) error
 	OAuthPayloadMarkConsumed(ctx context.Context, id string) error
 	OAuthPayloadPurgeExpired(ctx context.Context) (int64, error)
+	OAuthScopeDelete(ctx context.Context, id string) error
+	OAuthScopeFindAll(ctx context.Context) ([]OauthScope, error)
+	// ── OAuthScope (oauth_scopes) ────────────────────────────────────────
+	// Consent-screen text for scopes; Go-only (migration 073).
+	OAuthScopeFindByID(ctx context.Context, id string) (OauthScope, error)
+	OAuthScopeFindByName(ctx context.Context, name string) (OauthScope, error)
+	OAuthScopeUpsert(ctx context.Context, arg OAuthScopeUpsertParams) error
 	PermissionDeleteByCode(ctx context.Context, code string) error
 	PermissionFindAll(ctx context.Context) ([]IamPermission, error)
 	PermissionFindByCode(ctx context.Context, code string) (IamPermission, error)
@@ -217,11 +224,11 @@ type Querier interface {
 	//
 	// The schema stores user-identity fields as flat columns (email, email_domain,
 	// idp_type, external_idp_id, password_hash, last_login_at, dev_client_secret_ref,
-	// dev_client_secret_updated_at) rather than the JSONB blobs the Go entity
-	// carries. Mapping happens in repository.go. Column order in every
+	// dev_client_secret_updated_at, password_changed_at) rather than the JSONB
+	// blobs the Go entity carries. Mapping happens in repository.go. Column order in every
 	// SELECT/INSERT list must match the table's physical column order
-	// (dev_client_secret_ref/dev_client_secret_updated_at last — appended by
-	// migration 039's ALTER TABLE) so sqlc maps rows onto the shared
+	// (dev_client_secret_ref/dev_client_secret_updated_at, then password_changed_at
+	// last — appended by migrations 039 and 059) so sqlc maps rows onto the shared
 	// IamPrincipal model instead of generating a bespoke per-query Row type.
 	PrincipalFindByID(ctx context.Context, id string) (IamPrincipal, error)
 	// Backs the Developer Users admin page (generalises the previous
@@ -282,26 +289,41 @@ type Querier interface {
 	SpecVersionsClear(ctx context.Context, eventTypeID string) error
 	SpecVersionsForEventTypes(ctx context.Context, eventTypeIds []string) ([]MsgEventTypeSpecVersion, error)
 	SubscriptionConfigInsert(ctx context.Context, arg SubscriptionConfigInsertParams) error
+	SubscriptionConfigSchemaDelete(ctx context.Context, id string) error
+	SubscriptionConfigSchemaFindAll(ctx context.Context) ([]MsgSubscriptionConfigSchema, error)
+	SubscriptionConfigSchemaFindByID(ctx context.Context, id string) (MsgSubscriptionConfigSchema, error)
+	SubscriptionConfigSchemaResolve(ctx context.Context, arg SubscriptionConfigSchemaResolveParams) (MsgSubscriptionConfigSchema, error)
+	SubscriptionConfigSchemaUpsert(ctx context.Context, arg SubscriptionConfigSchemaUpsertParams) error
 	SubscriptionConfigsClear(ctx context.Context, subscriptionID string) error
 	SubscriptionConfigsForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionConfigsForSubsRow, error)
 	SubscriptionDelete(ctx context.Context, id string) error
+	SubscriptionDeliverySettingsFind(ctx context.Context, id string) (SubscriptionDeliverySettingsFindRow, error)
 	SubscriptionEventTypeInsert(ctx context.Context, arg SubscriptionEventTypeInsertParams) error
 	SubscriptionEventTypesClear(ctx context.Context, subscriptionID string) error
 	SubscriptionEventTypesForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionEventTypesForSubsRow, error)
 	SubscriptionFindAll(ctx context.Context) ([]MsgSubscription, error)
-	SubscriptionFindByCodeAnchor(ctx context.Context, code string) (MsgSubscription, error)
+	SubscriptionFindByCodeAnchor(ctx context.Context, arg SubscriptionFindByCodeAnchorParams) (MsgSubscription, error)
 	SubscriptionFindByCodeClient(ctx context.Context, arg SubscriptionFindByCodeClientParams) (MsgSubscription, error)
 	// Queries for msg_subscriptions + msg_subscription_event_types +
-	// msg_subscription_custom_configs.
+	// msg_subscription_custom_configs + msg_subscription_target_auth +
+	// msg_subscription_transforms.
 	//
 	// Schema columns differ from the previous Go port in several places:
 	//   - target (not endpoint)
-	//   - msg_subscription_event_types has no filter column
+	//   - msg_subscription_event_types had no filter column (added in migration 042)
 	//   - msg_subscription_custom_configs uses config_key/config_value (not key/value)
 	// All of these were silent runtime bugs in the pre-sqlc repo.
 	// created_by was added Go-side in migration 035 (Rust never had it; its
 	// rows read back NULL).
 	SubscriptionFindByID(ctx context.Context, id string) (MsgSubscription, error)
+	SubscriptionTargetAuthClear(ctx context.Context, subscriptionID string) error
+	SubscriptionTargetAuthFind(ctx context.Context, subscriptionID string) (MsgSubscriptionTargetAuth, error)
+	SubscriptionTargetAuthForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionTargetAuthForSubsRow, error)
+	SubscriptionTargetAuthUpsert(ctx context.Context, arg SubscriptionTargetAuthUpsertParams) error
+	SubscriptionTransformClear(ctx context.Context, subscriptionID string) error
+	SubscriptionTransformFind(ctx context.Context, subscriptionID string) (MsgSubscriptionTransform, error)
+	SubscriptionTransformUpsert(ctx context.Context, arg SubscriptionTransformUpsertParams) error
+	SubscriptionTransformsForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionTransformsForSubsRow, error)
 	SubscriptionUpsert(ctx context.Context, arg SubscriptionUpsertParams) error
 	WebauthnCeremonyConsume(ctx context.Context, id string) (json.RawMessage, error)
 	WebauthnCeremonyPurgeExpired(ctx context.Context, arg WebauthnCeremonyPurgeExpiredParams) (int64, error)
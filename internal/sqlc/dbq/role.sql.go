@@ -10,6 +10,30 @@ import (
 	"time"
 )
 
+const permissionCodes = `-- name: PermissionCodes :many
+SELECT code FROM iam_permissions ORDER BY code
+`
+
+func (q *Queries) PermissionCodes(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, permissionCodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		items = append(items, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const permissionDeleteByCode = `-- name: PermissionDeleteByCode :exec
 DELETE FROM iam_permissions WHERE code = $1
 `
@@ -19,8 +43,31 @@ func (q *Queries) PermissionDeleteByCode(ctx context.Context, code string) error
 	return err
 }
 
+const permissionDeleteUnlisted = `-- name: PermissionDeleteUnlisted :execrows
+DELETE FROM iam_permissions
+WHERE source = $1
+  AND application_id IS NOT DISTINCT FROM $2::varchar
+  AND code <> ALL($3::text[])
+`
+
+type PermissionDeleteUnlistedParams struct {
+	Source        string   `db:"source"`
+	ApplicationID *string  `db:"application_id"`
+	Codes         []string `db:"codes"`
+}
+
+// Prunes one source's rows (one application's, for SDK) that aren't in codes.
+func (q *Queries) PermissionDeleteUnlisted(ctx context.Context, arg PermissionDeleteUnlistedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, permissionDeleteUnlisted, arg.Source, arg.ApplicationID, arg.Codes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const permissionFindAll = `-- name: PermissionFindAll :many
-SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at
+SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at,
+       application_id, category, source
 FROM iam_permissions
 ORDER BY code
 `
@@ -44,6 +91,50 @@ func (q *Queries) PermissionFindAll(ctx context.Context) ([]IamPermission, error
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ApplicationID,
+			&i.Category,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const permissionFindByApplicationID = `-- name: PermissionFindByApplicationID :many
+SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at,
+       application_id, category, source
+FROM iam_permissions
+WHERE application_id = $1
+ORDER BY code
+`
+
+func (q *Queries) PermissionFindByApplicationID(ctx context.Context, applicationID *string) ([]IamPermission, error) {
+	rows, err := q.db.Query(ctx, permissionFindByApplicationID, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []IamPermission{}
+	for rows.Next() {
+		var i IamPermission
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Subdomain,
+			&i.Context,
+			&i.Aggregate,
+			&i.Action,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ApplicationID,
+			&i.Category,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -56,7 +147,8 @@ func (q *Queries) PermissionFindAll(ctx context.Context) ([]IamPermission, error
 }
 
 const permissionFindByCode = `-- name: PermissionFindByCode :one
-SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at
+SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at,
+       application_id, category, source
 FROM iam_permissions
 WHERE code = $1
 `
@@ -74,32 +166,46 @@ func (q *Queries) PermissionFindByCode(ctx context.Context, code string) (IamPer
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ApplicationID,
+		&i.Category,
+		&i.Source,
 	)
 	return i, err
 }
 
 const permissionUpsert = `-- name: PermissionUpsert :exec
-INSERT INTO iam_permissions (id, code, subdomain, context, aggregate, action, description)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO iam_permissions (id, code, subdomain, context, aggregate, action, description,
+                             application_id, category, source)
+VALUES ($1, $2, $3, $4, $5, $6, $7,
+        $8, $9, $10)
 ON CONFLICT (code) DO UPDATE SET
-    subdomain   = EXCLUDED.subdomain,
-    context     = EXCLUDED.context,
-    aggregate   = EXCLUDED.aggregate,
-    action      = EXCLUDED.action,
-    description = EXCLUDED.description,
-    updated_at  = NOW()
+    subdomain      = EXCLUDED.subdomain,
+    context        = EXCLUDED.context,
+    aggregate      = EXCLUDED.aggregate,
+    action         = EXCLUDED.action,
+    description    = EXCLUDED.description,
+    application_id = EXCLUDED.application_id,
+    category       = EXCLUDED.category,
+    source         = EXCLUDED.source,
+    updated_at     = NOW()
+WHERE iam_permissions.source <> 'CODE' OR EXCLUDED.source = 'CODE'
 `
 
 type PermissionUpsertParams struct {
-	ID          string  `db:"id"`
-	Code        string  `db:"code"`
-	Subdomain   string  `db:"subdomain"`
-	Context     string  `db:"context"`
-	Aggregate   string  `db:"aggregate"`
-	Action      string  `db:"action"`
-	Description *string `db:"description"`
+	ID            string  `db:"id"`
+	Code          string  `db:"code"`
+	Subdomain     string  `db:"subdomain"`
+	Context       string  `db:"context"`
+	Aggregate     string  `db:"aggregate"`
+	Action        string  `db:"action"`
+	Description   *string `db:"description"`
+	ApplicationID *string `db:"application_id"`
+	Category      *string `db:"category"`
+	Source        string  `db:"source"`
 }
 
+// CODE rows (the seeded platform catalog) are only rewritten by another
+// CODE upsert, so a dashboard or SDK write can't take one over.
 func (q *Queries) PermissionUpsert(ctx context.Context, arg PermissionUpsertParams) error {
 	_, err := q.db.Exec(ctx, permissionUpsert,
 		arg.ID,
@@ -109,6 +215,9 @@ func (q *Queries) PermissionUpsert(ctx context.Context, arg PermissionUpsertPara
 		arg.Aggregate,
 		arg.Action,
 		arg.Description,
+		arg.ApplicationID,
+		arg.Category,
+		arg.Source,
 	)
 	return err
 }
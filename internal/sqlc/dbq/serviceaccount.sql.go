@@ -24,7 +24,7 @@ SELECT id, code, name, description, application_id, active,
        wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
        wh_signing_algorithm, wh_credentials_created_at,
        wh_credentials_regenerated_at, last_used_at, created_at, updated_at,
-       scope, client_ids
+       scope, client_ids, environment
 FROM iam_service_accounts
 ORDER BY code
 `
@@ -56,6 +56,7 @@ func (q *Queries) ServiceAccountFindAll(ctx context.Context) ([]IamServiceAccoun
 			&i.UpdatedAt,
 			&i.Scope,
 			&i.ClientIds,
+			&i.Environment,
 		); err != nil {
 			return nil, err
 		}
@@ -72,7 +73,7 @@ SELECT id, code, name, description, application_id, active,
        wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
        wh_signing_algorithm, wh_credentials_created_at,
        wh_credentials_regenerated_at, last_used_at, created_at, updated_at,
-       scope, client_ids
+       scope, client_ids, environment
 FROM iam_service_accounts
 WHERE code = $1
 `
@@ -98,6 +99,7 @@ func (q *Queries) ServiceAccountFindByCode(ctx context.Context, code string) (Ia
 		&i.UpdatedAt,
 		&i.Scope,
 		&i.ClientIds,
+		&i.Environment,
 	)
 	return i, err
 }
@@ -108,7 +110,7 @@ SELECT id, code, name, description, application_id, active,
        wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
        wh_signing_algorithm, wh_credentials_created_at,
        wh_credentials_regenerated_at, last_used_at, created_at, updated_at,
-       scope, client_ids
+       scope, client_ids, environment
 FROM iam_service_accounts
 WHERE id = $1
 `
@@ -138,6 +140,7 @@ func (q *Queries) ServiceAccountFindByID(ctx context.Context, id string) (IamSer
 		&i.UpdatedAt,
 		&i.Scope,
 		&i.ClientIds,
+		&i.Environment,
 	)
 	return i, err
 }
@@ -147,8 +150,8 @@ INSERT INTO iam_service_accounts
     (id, code, name, description, application_id, scope, client_ids, active,
      wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
      wh_signing_algorithm, wh_credentials_created_at,
-     wh_credentials_regenerated_at, last_used_at, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+     wh_credentials_regenerated_at, last_used_at, created_at, updated_at, environment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 ON CONFLICT (id) DO UPDATE SET
     name = EXCLUDED.name,
     description = EXCLUDED.description,
@@ -184,6 +187,7 @@ type ServiceAccountUpsertParams struct {
 	LastUsedAt                 *time.Time `db:"last_used_at"`
 	CreatedAt                  time.Time  `db:"created_at"`
 	UpdatedAt                  time.Time  `db:"updated_at"`
+	Environment                string     `db:"environment"`
 }
 
 func (q *Queries) ServiceAccountUpsert(ctx context.Context, arg ServiceAccountUpsertParams) error {
@@ -205,6 +209,7 @@ func (q *Queries) ServiceAccountUpsert(ctx context.Context, arg ServiceAccountUp
 		arg.LastUsedAt,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Environment,
 	)
 	return err
 }
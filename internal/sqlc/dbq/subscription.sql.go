@@ -7,6 +7,7 @@ package dbq
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 )
 
@@ -27,6 +28,132 @@ func (q *Queries) SubscriptionConfigInsert(ctx context.Context, arg Subscription
 	return err
 }
 
+const subscriptionConfigSchemaDelete = `-- name: SubscriptionConfigSchemaDelete :exec
+DELETE FROM msg_subscription_config_schemas WHERE id = $1
+`
+
+func (q *Queries) SubscriptionConfigSchemaDelete(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, subscriptionConfigSchemaDelete, id)
+	return err
+}
+
+const subscriptionConfigSchemaFindAll = `-- name: SubscriptionConfigSchemaFindAll :many
+SELECT id, application_code, mediation_type, description, fields, created_at, updated_at
+FROM msg_subscription_config_schemas
+ORDER BY application_code NULLS FIRST, mediation_type
+`
+
+func (q *Queries) SubscriptionConfigSchemaFindAll(ctx context.Context) ([]MsgSubscriptionConfigSchema, error) {
+	rows, err := q.db.Query(ctx, subscriptionConfigSchemaFindAll)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MsgSubscriptionConfigSchema{}
+	for rows.Next() {
+		var i MsgSubscriptionConfigSchema
+		if err := rows.Scan(
+			&i.ID,
+			&i.ApplicationCode,
+			&i.MediationType,
+			&i.Description,
+			&i.Fields,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const subscriptionConfigSchemaFindByID = `-- name: SubscriptionConfigSchemaFindByID :one
+SELECT id, application_code, mediation_type, description, fields, created_at, updated_at
+FROM msg_subscription_config_schemas
+WHERE id = $1
+`
+
+func (q *Queries) SubscriptionConfigSchemaFindByID(ctx context.Context, id string) (MsgSubscriptionConfigSchema, error) {
+	row := q.db.QueryRow(ctx, subscriptionConfigSchemaFindByID, id)
+	var i MsgSubscriptionConfigSchema
+	err := row.Scan(
+		&i.ID,
+		&i.ApplicationCode,
+		&i.MediationType,
+		&i.Description,
+		&i.Fields,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const subscriptionConfigSchemaResolve = `-- name: SubscriptionConfigSchemaResolve :one
+SELECT id, application_code, mediation_type, description, fields, created_at, updated_at
+FROM msg_subscription_config_schemas
+WHERE mediation_type = $1
+  AND (application_code = $2::varchar OR application_code IS NULL)
+ORDER BY application_code NULLS LAST
+LIMIT 1
+`
+
+type SubscriptionConfigSchemaResolveParams struct {
+	MediationType   string  `db:"mediation_type"`
+	ApplicationCode *string `db:"application_code"`
+}
+
+func (q *Queries) SubscriptionConfigSchemaResolve(ctx context.Context, arg SubscriptionConfigSchemaResolveParams) (MsgSubscriptionConfigSchema, error) {
+	row := q.db.QueryRow(ctx, subscriptionConfigSchemaResolve, arg.MediationType, arg.ApplicationCode)
+	var i MsgSubscriptionConfigSchema
+	err := row.Scan(
+		&i.ID,
+		&i.ApplicationCode,
+		&i.MediationType,
+		&i.Description,
+		&i.Fields,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const subscriptionConfigSchemaUpsert = `-- name: SubscriptionConfigSchemaUpsert :exec
+INSERT INTO msg_subscription_config_schemas
+    (id, application_code, mediation_type, description, fields, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE SET
+    description = EXCLUDED.description,
+    fields = EXCLUDED.fields,
+    updated_at = EXCLUDED.updated_at
+`
+
+type SubscriptionConfigSchemaUpsertParams struct {
+	ID              string          `db:"id"`
+	ApplicationCode *string         `db:"application_code"`
+	MediationType   string          `db:"mediation_type"`
+	Description     *string         `db:"description"`
+	Fields          json.RawMessage `db:"fields"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
+}
+
+func (q *Queries) SubscriptionConfigSchemaUpsert(ctx context.Context, arg SubscriptionConfigSchemaUpsertParams) error {
+	_, err := q.db.Exec(ctx, subscriptionConfigSchemaUpsert,
+		arg.ID,
+		arg.ApplicationCode,
+		arg.MediationType,
+		arg.Description,
+		arg.Fields,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const subscriptionConfigsClear = `-- name: SubscriptionConfigsClear :exec
 DELETE FROM msg_subscription_custom_configs WHERE subscription_id = $1
 `
@@ -77,10 +204,55 @@ func (q *Queries) SubscriptionDelete(ctx context.Context, id string) error {
 	return err
 }
 
+const subscriptionDeliverySettingsFind = `-- name: SubscriptionDeliverySettingsFind :one
+SELECT s.delivery_format, s.delivery_headers, s.delivery_window, s.ack_timeout_seconds,
+       s.allow_private_target, s.egress_proxy, s.tap_sample_percent, s.tap_expires_at,
+       s.honor_retry_after, t.engine AS transform_engine, t.template AS transform_template,
+       t.content_type AS transform_content_type
+FROM msg_subscriptions s
+LEFT JOIN msg_subscription_transforms t ON t.subscription_id = s.id
+WHERE s.id = $1
+`
+
+type SubscriptionDeliverySettingsFindRow struct {
+	DeliveryFormat       string          `db:"delivery_format"`
+	DeliveryHeaders      []string        `db:"delivery_headers"`
+	DeliveryWindow       json.RawMessage `db:"delivery_window"`
+	AckTimeoutSeconds    *int32          `db:"ack_timeout_seconds"`
+	AllowPrivateTarget   bool            `db:"allow_private_target"`
+	EgressProxy          *string         `db:"egress_proxy"`
+	TapSamplePercent     *float64        `db:"tap_sample_percent"`
+	TapExpiresAt         *time.Time      `db:"tap_expires_at"`
+	HonorRetryAfter      bool            `db:"honor_retry_after"`
+	TransformEngine      *string         `db:"transform_engine"`
+	TransformTemplate    *string         `db:"transform_template"`
+	TransformContentType *string         `db:"transform_content_type"`
+}
+
+func (q *Queries) SubscriptionDeliverySettingsFind(ctx context.Context, id string) (SubscriptionDeliverySettingsFindRow, error) {
+	row := q.db.QueryRow(ctx, subscriptionDeliverySettingsFind, id)
+	var i SubscriptionDeliverySettingsFindRow
+	err := row.Scan(
+		&i.DeliveryFormat,
+		&i.DeliveryHeaders,
+		&i.DeliveryWindow,
+		&i.AckTimeoutSeconds,
+		&i.AllowPrivateTarget,
+		&i.EgressProxy,
+		&i.TapSamplePercent,
+		&i.TapExpiresAt,
+		&i.HonorRetryAfter,
+		&i.TransformEngine,
+		&i.TransformTemplate,
+		&i.TransformContentType,
+	)
+	return i, err
+}
+
 const subscriptionEventTypeInsert = `-- name: SubscriptionEventTypeInsert :exec
 INSERT INTO msg_subscription_event_types
-    (subscription_id, event_type_id, event_type_code, spec_version)
-VALUES ($1, $2, $3, $4)
+    (subscription_id, event_type_id, event_type_code, spec_version, filter)
+VALUES ($1, $2, $3, $4, $5)
 `
 
 type SubscriptionEventTypeInsertParams struct {
@@ -88,6 +260,7 @@ type SubscriptionEventTypeInsertParams struct {
 	EventTypeID    *string `db:"event_type_id"`
 	EventTypeCode  string  `db:"event_type_code"`
 	SpecVersion    *string `db:"spec_version"`
+	Filter         *string `db:"filter"`
 }
 
 func (q *Queries) SubscriptionEventTypeInsert(ctx context.Context, arg SubscriptionEventTypeInsertParams) error {
@@ -96,6 +269,7 @@ func (q *Queries) SubscriptionEventTypeInsert(ctx context.Context, arg Subscript
 		arg.EventTypeID,
 		arg.EventTypeCode,
 		arg.SpecVersion,
+		arg.Filter,
 	)
 	return err
 }
@@ -110,7 +284,7 @@ func (q *Queries) SubscriptionEventTypesClear(ctx context.Context, subscriptionI
 }
 
 const subscriptionEventTypesForSubs = `-- name: SubscriptionEventTypesForSubs :many
-SELECT subscription_id, event_type_id, event_type_code, spec_version
+SELECT subscription_id, event_type_id, event_type_code, spec_version, filter
 FROM msg_subscription_event_types
 WHERE subscription_id = ANY($1::text[])
 `
@@ -120,6 +294,7 @@ type SubscriptionEventTypesForSubsRow struct {
 	EventTypeID    *string `db:"event_type_id"`
 	EventTypeCode  string  `db:"event_type_code"`
 	SpecVersion    *string `db:"spec_version"`
+	Filter         *string `db:"filter"`
 }
 
 func (q *Queries) SubscriptionEventTypesForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionEventTypesForSubsRow, error) {
@@ -136,6 +311,7 @@ func (q *Queries) SubscriptionEventTypesForSubs(ctx context.Context, subscriptio
 			&i.EventTypeID,
 			&i.EventTypeCode,
 			&i.SpecVersion,
+			&i.Filter,
 		); err != nil {
 			return nil, err
 		}
@@ -152,7 +328,10 @@ SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
 ORDER BY code
 `
@@ -193,6 +372,18 @@ func (q *Queries) SubscriptionFindAll(ctx context.Context) ([]MsgSubscription, e
 			&i.UpdatedAt,
 			&i.ConnectionID,
 			&i.CreatedBy,
+			&i.Priority,
+			&i.HonorRetryAfter,
+			&i.DeliveryFormat,
+			&i.DeliveryWindow,
+			&i.AllowPrivateTarget,
+			&i.EgressProxy,
+			&i.Weight,
+			&i.AckTimeoutSeconds,
+			&i.DeliveryHeaders,
+			&i.Environment,
+			&i.TapSamplePercent,
+			&i.TapExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -209,13 +400,21 @@ SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
-WHERE code = $1 AND client_id IS NULL
+WHERE code = $1 AND client_id IS NULL AND environment = $2
 `
 
-func (q *Queries) SubscriptionFindByCodeAnchor(ctx context.Context, code string) (MsgSubscription, error) {
-	row := q.db.QueryRow(ctx, subscriptionFindByCodeAnchor, code)
+type SubscriptionFindByCodeAnchorParams struct {
+	Code        string `db:"code"`
+	Environment string `db:"environment"`
+}
+
+func (q *Queries) SubscriptionFindByCodeAnchor(ctx context.Context, arg SubscriptionFindByCodeAnchorParams) (MsgSubscription, error) {
+	row := q.db.QueryRow(ctx, subscriptionFindByCodeAnchor, arg.Code, arg.Environment)
 	var i MsgSubscription
 	err := row.Scan(
 		&i.ID,
@@ -244,6 +443,19 @@ func (q *Queries) SubscriptionFindByCodeAnchor(ctx context.Context, code string)
 		&i.UpdatedAt,
 		&i.ConnectionID,
 		&i.CreatedBy,
+		&i.Priority,
+		&i.HonorRetryAfter,
+		&i.DeliveryFormat,
+		&i.DeliveryWindow,
+		&i.AckTimeoutSeconds,
+		&i.AllowPrivateTarget,
+		&i.EgressProxy,
+		&i.Weight,
+		&i.AckTimeoutSeconds,
+		&i.DeliveryHeaders,
+		&i.Environment,
+		&i.TapSamplePercent,
+		&i.TapExpiresAt,
 	)
 	return i, err
 }
@@ -253,18 +465,22 @@ SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
-WHERE code = $1 AND client_id = $2
+WHERE code = $1 AND client_id = $2 AND environment = $3
 `
 
 type SubscriptionFindByCodeClientParams struct {
-	Code     string  `db:"code"`
-	ClientID *string `db:"client_id"`
+	Code        string  `db:"code"`
+	ClientID    *string `db:"client_id"`
+	Environment string  `db:"environment"`
 }
 
 func (q *Queries) SubscriptionFindByCodeClient(ctx context.Context, arg SubscriptionFindByCodeClientParams) (MsgSubscription, error) {
-	row := q.db.QueryRow(ctx, subscriptionFindByCodeClient, arg.Code, arg.ClientID)
+	row := q.db.QueryRow(ctx, subscriptionFindByCodeClient, arg.Code, arg.ClientID, arg.Environment)
 	var i MsgSubscription
 	err := row.Scan(
 		&i.ID,
@@ -293,6 +509,19 @@ func (q *Queries) SubscriptionFindByCodeClient(ctx context.Context, arg Subscrip
 		&i.UpdatedAt,
 		&i.ConnectionID,
 		&i.CreatedBy,
+		&i.Priority,
+		&i.HonorRetryAfter,
+		&i.DeliveryFormat,
+		&i.DeliveryWindow,
+		&i.AckTimeoutSeconds,
+		&i.AllowPrivateTarget,
+		&i.EgressProxy,
+		&i.Weight,
+		&i.AckTimeoutSeconds,
+		&i.DeliveryHeaders,
+		&i.Environment,
+		&i.TapSamplePercent,
+		&i.TapExpiresAt,
 	)
 	return i, err
 }
@@ -303,17 +532,21 @@ SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
 WHERE id = $1
 `
 
 // Queries for msg_subscriptions + msg_subscription_event_types +
-// msg_subscription_custom_configs.
+// msg_subscription_custom_configs + msg_subscription_target_auth +
+// msg_subscription_transforms.
 //
 // Schema columns differ from the previous Go port in several places:
 //   - target (not endpoint)
-//   - msg_subscription_event_types has no filter column
+//   - msg_subscription_event_types had no filter column (added in migration 042)
 //   - msg_subscription_custom_configs uses config_key/config_value (not key/value)
 //
 // All of these were silent runtime bugs in the pre-sqlc repo.
@@ -349,18 +582,205 @@ func (q *Queries) SubscriptionFindByID(ctx context.Context, id string) (MsgSubsc
 		&i.UpdatedAt,
 		&i.ConnectionID,
 		&i.CreatedBy,
+		&i.Priority,
+		&i.HonorRetryAfter,
+		&i.DeliveryFormat,
+		&i.DeliveryWindow,
+		&i.AckTimeoutSeconds,
+		&i.AllowPrivateTarget,
+		&i.EgressProxy,
+		&i.Weight,
+		&i.AckTimeoutSeconds,
+		&i.DeliveryHeaders,
+		&i.Environment,
+		&i.TapSamplePercent,
+		&i.TapExpiresAt,
+	)
+	return i, err
+}
+
+const subscriptionTargetAuthClear = `-- name: SubscriptionTargetAuthClear :exec
+DELETE FROM msg_subscription_target_auth WHERE subscription_id = $1
+`
+
+func (q *Queries) SubscriptionTargetAuthClear(ctx context.Context, subscriptionID string) error {
+	_, err := q.db.Exec(ctx, subscriptionTargetAuthClear, subscriptionID)
+	return err
+}
+
+const subscriptionTargetAuthFind = `-- name: SubscriptionTargetAuthFind :one
+SELECT subscription_id, auth_type, config, updated_at
+FROM msg_subscription_target_auth
+WHERE subscription_id = $1
+`
+
+func (q *Queries) SubscriptionTargetAuthFind(ctx context.Context, subscriptionID string) (MsgSubscriptionTargetAuth, error) {
+	row := q.db.QueryRow(ctx, subscriptionTargetAuthFind, subscriptionID)
+	var i MsgSubscriptionTargetAuth
+	err := row.Scan(
+		&i.SubscriptionID,
+		&i.AuthType,
+		&i.Config,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const subscriptionTargetAuthForSubs = `-- name: SubscriptionTargetAuthForSubs :many
+SELECT subscription_id, auth_type, config
+FROM msg_subscription_target_auth
+WHERE subscription_id = ANY($1::text[])
+`
+
+type SubscriptionTargetAuthForSubsRow struct {
+	SubscriptionID string          `db:"subscription_id"`
+	AuthType       string          `db:"auth_type"`
+	Config         json.RawMessage `db:"config"`
+}
+
+func (q *Queries) SubscriptionTargetAuthForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionTargetAuthForSubsRow, error) {
+	rows, err := q.db.Query(ctx, subscriptionTargetAuthForSubs, subscriptionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SubscriptionTargetAuthForSubsRow{}
+	for rows.Next() {
+		var i SubscriptionTargetAuthForSubsRow
+		if err := rows.Scan(&i.SubscriptionID, &i.AuthType, &i.Config); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const subscriptionTargetAuthUpsert = `-- name: SubscriptionTargetAuthUpsert :exec
+INSERT INTO msg_subscription_target_auth (subscription_id, auth_type, config, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (subscription_id) DO UPDATE SET
+    auth_type = EXCLUDED.auth_type,
+    config = EXCLUDED.config,
+    updated_at = NOW()
+`
+
+type SubscriptionTargetAuthUpsertParams struct {
+	SubscriptionID string          `db:"subscription_id"`
+	AuthType       string          `db:"auth_type"`
+	Config         json.RawMessage `db:"config"`
+}
+
+func (q *Queries) SubscriptionTargetAuthUpsert(ctx context.Context, arg SubscriptionTargetAuthUpsertParams) error {
+	_, err := q.db.Exec(ctx, subscriptionTargetAuthUpsert, arg.SubscriptionID, arg.AuthType, arg.Config)
+	return err
+}
+
+const subscriptionTransformClear = `-- name: SubscriptionTransformClear :exec
+DELETE FROM msg_subscription_transforms WHERE subscription_id = $1
+`
+
+func (q *Queries) SubscriptionTransformClear(ctx context.Context, subscriptionID string) error {
+	_, err := q.db.Exec(ctx, subscriptionTransformClear, subscriptionID)
+	return err
+}
+
+const subscriptionTransformFind = `-- name: SubscriptionTransformFind :one
+SELECT subscription_id, engine, template, content_type, updated_at
+FROM msg_subscription_transforms
+WHERE subscription_id = $1
+`
+
+func (q *Queries) SubscriptionTransformFind(ctx context.Context, subscriptionID string) (MsgSubscriptionTransform, error) {
+	row := q.db.QueryRow(ctx, subscriptionTransformFind, subscriptionID)
+	var i MsgSubscriptionTransform
+	err := row.Scan(
+		&i.SubscriptionID,
+		&i.Engine,
+		&i.Template,
+		&i.ContentType,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const subscriptionTransformUpsert = `-- name: SubscriptionTransformUpsert :exec
+INSERT INTO msg_subscription_transforms (subscription_id, engine, template, content_type, updated_at)
+VALUES ($1, $2, $3, $4, NOW())
+ON CONFLICT (subscription_id) DO UPDATE SET
+    engine = EXCLUDED.engine,
+    template = EXCLUDED.template,
+    content_type = EXCLUDED.content_type,
+    updated_at = NOW()
+`
+
+type SubscriptionTransformUpsertParams struct {
+	SubscriptionID string `db:"subscription_id"`
+	Engine         string `db:"engine"`
+	Template       string `db:"template"`
+	ContentType    string `db:"content_type"`
+}
+
+func (q *Queries) SubscriptionTransformUpsert(ctx context.Context, arg SubscriptionTransformUpsertParams) error {
+	_, err := q.db.Exec(ctx, subscriptionTransformUpsert,
+		arg.SubscriptionID,
+		arg.Engine,
+		arg.Template,
+		arg.ContentType,
+	)
+	return err
+}
+
+const subscriptionTransformsForSubs = `-- name: SubscriptionTransformsForSubs :many
+SELECT subscription_id, engine, template, content_type
+FROM msg_subscription_transforms
+WHERE subscription_id = ANY($1::text[])
+`
+
+type SubscriptionTransformsForSubsRow struct {
+	SubscriptionID string `db:"subscription_id"`
+	Engine         string `db:"engine"`
+	Template       string `db:"template"`
+	ContentType    string `db:"content_type"`
+}
+
+func (q *Queries) SubscriptionTransformsForSubs(ctx context.Context, subscriptionIds []string) ([]SubscriptionTransformsForSubsRow, error) {
+	rows, err := q.db.Query(ctx, subscriptionTransformsForSubs, subscriptionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SubscriptionTransformsForSubsRow{}
+	for rows.Next() {
+		var i SubscriptionTransformsForSubsRow
+		if err := rows.Scan(
+			&i.SubscriptionID,
+			&i.Engine,
+			&i.Template,
+			&i.ContentType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const subscriptionUpsert = `-- name: SubscriptionUpsert :exec
 INSERT INTO msg_subscriptions
     (id, code, application_code, name, description, client_id, client_identifier,
      client_scoped, connection_id, target, queue, source, status, max_age_seconds,
      dispatch_pool_id, dispatch_pool_code, delay_seconds, sequence, mode,
      timeout_seconds, max_retries, service_account_id, data_only,
-     created_by, created_at, updated_at)
-VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26)
+     created_by, created_at, updated_at, priority, honor_retry_after, delivery_format, delivery_window,
+     allow_private_target, egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+     tap_sample_percent, tap_expires_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38)
 ON CONFLICT (id) DO UPDATE SET
     name = EXCLUDED.name,
     description = EXCLUDED.description,
@@ -376,36 +796,59 @@ ON CONFLICT (id) DO UPDATE SET
     max_retries = EXCLUDED.max_retries,
     service_account_id = EXCLUDED.service_account_id,
     data_only = EXCLUDED.data_only,
+    priority = EXCLUDED.priority,
+    honor_retry_after = EXCLUDED.honor_retry_after,
+    delivery_format = EXCLUDED.delivery_format,
+    delivery_window = EXCLUDED.delivery_window,
+    allow_private_target = EXCLUDED.allow_private_target,
+    egress_proxy = EXCLUDED.egress_proxy,
+    weight = EXCLUDED.weight,
+    ack_timeout_seconds = EXCLUDED.ack_timeout_seconds,
+    delivery_headers = EXCLUDED.delivery_headers,
+    tap_sample_percent = EXCLUDED.tap_sample_percent,
+    tap_expires_at = EXCLUDED.tap_expires_at,
     updated_at = EXCLUDED.updated_at
 `
 
 type SubscriptionUpsertParams struct {
-	ID               string    `db:"id"`
-	Code             string    `db:"code"`
-	ApplicationCode  *string   `db:"application_code"`
-	Name             string    `db:"name"`
-	Description      *string   `db:"description"`
-	ClientID         *string   `db:"client_id"`
-	ClientIdentifier *string   `db:"client_identifier"`
-	ClientScoped     bool      `db:"client_scoped"`
-	ConnectionID     *string   `db:"connection_id"`
-	Target           string    `db:"target"`
-	Queue            *string   `db:"queue"`
-	Source           string    `db:"source"`
-	Status           string    `db:"status"`
-	MaxAgeSeconds    int32     `db:"max_age_seconds"`
-	DispatchPoolID   *string   `db:"dispatch_pool_id"`
-	DispatchPoolCode *string   `db:"dispatch_pool_code"`
-	DelaySeconds     int32     `db:"delay_seconds"`
-	Sequence         int32     `db:"sequence"`
-	Mode             string    `db:"mode"`
-	TimeoutSeconds   int32     `db:"timeout_seconds"`
-	MaxRetries       int32     `db:"max_retries"`
-	ServiceAccountID *string   `db:"service_account_id"`
-	DataOnly         bool      `db:"data_only"`
-	CreatedBy        *string   `db:"created_by"`
-	CreatedAt        time.Time `db:"created_at"`
-	UpdatedAt        time.Time `db:"updated_at"`
+	ID                 string          `db:"id"`
+	Code               string          `db:"code"`
+	ApplicationCode    *string         `db:"application_code"`
+	Name               string          `db:"name"`
+	Description        *string         `db:"description"`
+	ClientID           *string         `db:"client_id"`
+	ClientIdentifier   *string         `db:"client_identifier"`
+	ClientScoped       bool            `db:"client_scoped"`
+	ConnectionID       *string         `db:"connection_id"`
+	Target             string          `db:"target"`
+	Queue              *string         `db:"queue"`
+	Source             string          `db:"source"`
+	Status             string          `db:"status"`
+	MaxAgeSeconds      int32           `db:"max_age_seconds"`
+	DispatchPoolID     *string         `db:"dispatch_pool_id"`
+	DispatchPoolCode   *string         `db:"dispatch_pool_code"`
+	DelaySeconds       int32           `db:"delay_seconds"`
+	Sequence           int32           `db:"sequence"`
+	Mode               string          `db:"mode"`
+	TimeoutSeconds     int32           `db:"timeout_seconds"`
+	MaxRetries         int32           `db:"max_retries"`
+	ServiceAccountID   *string         `db:"service_account_id"`
+	DataOnly           bool            `db:"data_only"`
+	CreatedBy          *string         `db:"created_by"`
+	CreatedAt          time.Time       `db:"created_at"`
+	UpdatedAt          time.Time       `db:"updated_at"`
+	Priority           string          `db:"priority"`
+	HonorRetryAfter    bool            `db:"honor_retry_after"`
+	DeliveryFormat     string          `db:"delivery_format"`
+	DeliveryWindow     json.RawMessage `db:"delivery_window"`
+	AllowPrivateTarget bool            `db:"allow_private_target"`
+	EgressProxy        *string         `db:"egress_proxy"`
+	Weight             int32           `db:"weight"`
+	AckTimeoutSeconds  *int32          `db:"ack_timeout_seconds"`
+	DeliveryHeaders    []string        `db:"delivery_headers"`
+	Environment        string          `db:"environment"`
+	TapSamplePercent   *float64        `db:"tap_sample_percent"`
+	TapExpiresAt       *time.Time      `db:"tap_expires_at"`
 }
 
 func (q *Queries) SubscriptionUpsert(ctx context.Context, arg SubscriptionUpsertParams) error {
@@ -436,6 +879,18 @@ func (q *Queries) SubscriptionUpsert(ctx context.Context, arg SubscriptionUpsert
 		arg.CreatedBy,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Priority,
+		arg.HonorRetryAfter,
+		arg.DeliveryFormat,
+		arg.DeliveryWindow,
+		arg.AllowPrivateTarget,
+		arg.EgressProxy,
+		arg.Weight,
+		arg.AckTimeoutSeconds,
+		arg.DeliveryHeaders,
+		arg.Environment,
+		arg.TapSamplePercent,
+		arg.TapExpiresAt,
 	)
 	return err
 }
@@ -3,7 +3,7 @@
 -- name: AuditFindByID :one
 SELECT a.id, a.entity_type, a.entity_id, a.operation, a.operation_json,
        a.principal_id, p.name AS principal_name,
-       a.application_id, a.client_id, a.performed_at
+       a.application_id, a.client_id, a.performed_at, a.changes
 FROM aud_logs a
 LEFT JOIN iam_principals p ON p.id = a.principal_id
 WHERE a.id = $1;
@@ -13,7 +13,7 @@ WHERE a.id = $1;
 -- are always bound. Ordered by most recent first.
 SELECT a.id, a.entity_type, a.entity_id, a.operation, a.operation_json,
        a.principal_id, p.name AS principal_name,
-       a.application_id, a.client_id, a.performed_at
+       a.application_id, a.client_id, a.performed_at, a.changes
 FROM aud_logs a
 LEFT JOIN iam_principals p ON p.id = a.principal_id
 WHERE (sqlc.narg('entity_type')::text IS NULL OR a.entity_type = sqlc.narg('entity_type')::text)
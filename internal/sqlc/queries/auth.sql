@@ -5,28 +5,31 @@
 -- The schema also has 3 more junctions (post_logout_redirect_uris,
 -- allowed_origins, application_ids) that the Go entity doesn't carry
 -- yet — they're a follow-up alongside the entity extension.
--- The Go entity stores Argon2-hashed secrets in client_secret_ref
--- (Rust uses it as a secrets-manager reference; Go uses it as the
--- hash). See HANDOFF.md §4 for the planned PHC-salt fix.
+-- The Go entity stores argon2id PHC hashes in client_secret_ref
+-- (older rows hold an encrypted secret; Rust uses it as a
+-- secrets-manager reference).
 
 -- name: OAuthClientFindByID :one
 SELECT id, client_id, client_name, client_type, client_secret_ref,
        default_scopes, pkce_required, service_account_principal_id,
-       active, created_at, updated_at
+       active, created_at, updated_at, token_endpoint_auth_method, jwks,
+       first_party, backchannel_logout_uri
 FROM oauth_clients
 WHERE id = $1;
 
 -- name: OAuthClientFindByClientID :one
 SELECT id, client_id, client_name, client_type, client_secret_ref,
        default_scopes, pkce_required, service_account_principal_id,
-       active, created_at, updated_at
+       active, created_at, updated_at, token_endpoint_auth_method, jwks,
+       first_party, backchannel_logout_uri
 FROM oauth_clients
 WHERE client_id = $1;
 
 -- name: OAuthClientFindAll :many
 SELECT id, client_id, client_name, client_type, client_secret_ref,
        default_scopes, pkce_required, service_account_principal_id,
-       active, created_at, updated_at
+       active, created_at, updated_at, token_endpoint_auth_method, jwks,
+       first_party, backchannel_logout_uri
 FROM oauth_clients
 ORDER BY client_name;
 
@@ -34,8 +37,9 @@ ORDER BY client_name;
 INSERT INTO oauth_clients
     (id, client_id, client_name, client_type, client_secret_ref,
      default_scopes, pkce_required, service_account_principal_id,
-     active, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+     active, created_at, updated_at, token_endpoint_auth_method, jwks,
+     first_party, backchannel_logout_uri)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 ON CONFLICT (id) DO UPDATE SET
     client_id = EXCLUDED.client_id,
     client_name = EXCLUDED.client_name,
@@ -45,7 +49,11 @@ ON CONFLICT (id) DO UPDATE SET
     pkce_required = EXCLUDED.pkce_required,
     service_account_principal_id = EXCLUDED.service_account_principal_id,
     active = EXCLUDED.active,
-    updated_at = EXCLUDED.updated_at;
+    updated_at = EXCLUDED.updated_at,
+    token_endpoint_auth_method = EXCLUDED.token_endpoint_auth_method,
+    jwks = EXCLUDED.jwks,
+    first_party = EXCLUDED.first_party,
+    backchannel_logout_uri = EXCLUDED.backchannel_logout_uri;
 
 -- name: OAuthClientDelete :exec
 DELETE FROM oauth_clients WHERE id = $1;
@@ -192,3 +200,31 @@ ON CONFLICT (id) DO UPDATE SET
 
 -- name: IdpRoleMappingDelete :exec
 DELETE FROM oauth_idp_role_mappings WHERE id = $1;
+
+-- ── OAuthScope (oauth_scopes) ────────────────────────────────────────
+-- Consent-screen text for scopes; Go-only (migration 073).
+
+-- name: OAuthScopeFindByID :one
+SELECT id, name, description, created_at, updated_at
+FROM oauth_scopes
+WHERE id = $1;
+
+-- name: OAuthScopeFindByName :one
+SELECT id, name, description, created_at, updated_at
+FROM oauth_scopes
+WHERE name = $1;
+
+-- name: OAuthScopeFindAll :many
+SELECT id, name, description, created_at, updated_at
+FROM oauth_scopes
+ORDER BY name;
+
+-- name: OAuthScopeUpsert :exec
+INSERT INTO oauth_scopes (id, name, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET
+    description = EXCLUDED.description,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: OAuthScopeDelete :exec
+DELETE FROM oauth_scopes WHERE id = $1;
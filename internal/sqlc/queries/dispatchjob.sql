@@ -19,7 +19,8 @@ SELECT id, external_id, source, kind, code, subject, event_id,
        timeout_seconds, schema_id, status, max_retries, retry_strategy,
        scheduled_for, expires_at, attempt_count, last_attempt_at,
        completed_at, duration_millis, last_error, idempotency_key,
-       created_at, updated_at
+       created_at, updated_at, priority, ack_deadline, acked_at,
+       first_attempt_at, environment
 FROM msg_dispatch_jobs
 WHERE id = $1;
 
@@ -30,19 +31,23 @@ INSERT INTO msg_dispatch_jobs
      service_account_id, client_id, subscription_id, mode, dispatch_pool_id,
      message_group, sequence, timeout_seconds, schema_id, status, max_retries,
      retry_strategy, scheduled_for, expires_at, attempt_count, last_attempt_at,
-     completed_at, duration_millis, last_error, idempotency_key, created_at, updated_at)
+     completed_at, duration_millis, last_error, idempotency_key, created_at, updated_at,
+     priority, environment)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
         $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26,
-        $27, $28, $29, $30, $31, $32, $33, $34, $35, $36);
+        $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38);
 
 -- name: DispatchJobMarkInProgress :exec
--- Status → PROCESSING. Stamps last_attempt_at. Called by the router
--- immediately before the first delivery attempt.
+-- Status → PROCESSING. Stamps last_attempt_at, and first_attempt_at on
+-- the first attempt. Called by the router
+-- immediately before the first delivery attempt. This and the other
+-- delivery-path transitions skip a job its receiver has acknowledged.
 UPDATE msg_dispatch_jobs
    SET status = 'PROCESSING',
        last_attempt_at = $2,
+       first_attempt_at = COALESCE(first_attempt_at, $2),
        updated_at = $2
- WHERE id = $1;
+ WHERE id = $1 AND acked_at IS NULL;
 
 -- name: DispatchJobMarkCompleted :exec
 -- Status → COMPLETED. Stamps completed_at + duration_millis.
@@ -51,7 +56,7 @@ UPDATE msg_dispatch_jobs
        completed_at = $2,
        duration_millis = $3,
        updated_at = $2
- WHERE id = $1;
+ WHERE id = $1 AND acked_at IS NULL;
 
 -- name: DispatchJobMarkFailed :exec
 -- Terminal failure. Stamps last_error + completed_at + duration_millis.
@@ -61,7 +66,7 @@ UPDATE msg_dispatch_jobs
        duration_millis = $3,
        last_error = $4,
        updated_at = $2
- WHERE id = $1;
+ WHERE id = $1 AND acked_at IS NULL;
 
 -- name: DispatchJobScheduleRetry :exec
 -- Bumps attempt_count + stamps scheduled_for so the next poll picks
@@ -73,7 +78,7 @@ UPDATE msg_dispatch_jobs
        last_attempt_at = NOW(),
        status = 'PENDING',
        updated_at = NOW()
- WHERE id = $1;
+ WHERE id = $1 AND acked_at IS NULL;
 
 -- name: DispatchJobAttemptInsert :exec
 -- One row per delivery attempt. The schema column `status` stores the
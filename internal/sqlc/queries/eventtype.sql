@@ -4,19 +4,22 @@
 
 -- name: EventTypeFindByID :one
 SELECT id, code, name, description, status, source, client_scoped,
-       application, subdomain, aggregate, created_at, updated_at, created_by
+       application, subdomain, aggregate, created_at, updated_at, created_by,
+       deprecated_at, sunset_at, deprecation_note, environment
 FROM msg_event_types
 WHERE id = $1;
 
 -- name: EventTypeFindByCode :one
 SELECT id, code, name, description, status, source, client_scoped,
-       application, subdomain, aggregate, created_at, updated_at, created_by
+       application, subdomain, aggregate, created_at, updated_at, created_by,
+       deprecated_at, sunset_at, deprecation_note, environment
 FROM msg_event_types
 WHERE code = $1;
 
 -- name: EventTypeFindByApplication :many
 SELECT id, code, name, description, status, source, client_scoped,
-       application, subdomain, aggregate, created_at, updated_at, created_by
+       application, subdomain, aggregate, created_at, updated_at, created_by,
+       deprecated_at, sunset_at, deprecation_note, environment
 FROM msg_event_types
 WHERE application = $1
 ORDER BY code;
@@ -24,8 +27,9 @@ ORDER BY code;
 -- name: EventTypeUpsertByID :exec
 INSERT INTO msg_event_types
     (id, code, name, description, status, source, client_scoped,
-     application, subdomain, aggregate, created_by, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+     application, subdomain, aggregate, created_by, created_at, updated_at,
+     deprecated_at, sunset_at, deprecation_note, environment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 ON CONFLICT (id) DO UPDATE SET
     code = EXCLUDED.code,
     name = EXCLUDED.name,
@@ -36,20 +40,28 @@ ON CONFLICT (id) DO UPDATE SET
     application = EXCLUDED.application,
     subdomain = EXCLUDED.subdomain,
     aggregate = EXCLUDED.aggregate,
-    updated_at = EXCLUDED.updated_at;
+    updated_at = EXCLUDED.updated_at,
+    deprecated_at = EXCLUDED.deprecated_at,
+    sunset_at = EXCLUDED.sunset_at,
+    deprecation_note = EXCLUDED.deprecation_note,
+    environment = EXCLUDED.environment;
 
 -- name: EventTypeUpsertByCode :exec
 INSERT INTO msg_event_types
     (id, code, name, description, status, source, client_scoped,
-     application, subdomain, aggregate, created_by, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+     application, subdomain, aggregate, created_by, created_at, updated_at,
+     deprecated_at, sunset_at, deprecation_note, environment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 ON CONFLICT (code) DO UPDATE SET
     name = EXCLUDED.name,
     description = EXCLUDED.description,
     status = EXCLUDED.status,
     source = EXCLUDED.source,
     client_scoped = EXCLUDED.client_scoped,
-    updated_at = EXCLUDED.updated_at;
+    updated_at = EXCLUDED.updated_at,
+    deprecated_at = EXCLUDED.deprecated_at,
+    sunset_at = EXCLUDED.sunset_at,
+    deprecation_note = EXCLUDED.deprecation_note;
 
 -- name: EventTypeDelete :exec
 DELETE FROM msg_event_types WHERE id = $1;
@@ -6,21 +6,21 @@
 -- name: IdentityProviderFindByID :one
 SELECT id, code, name, type, oidc_issuer_url, oidc_client_id,
        oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-       created_at, updated_at
+       created_at, updated_at, idp_type
 FROM oauth_identity_providers
 WHERE id = $1;
 
 -- name: IdentityProviderFindByCode :one
 SELECT id, code, name, type, oidc_issuer_url, oidc_client_id,
        oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-       created_at, updated_at
+       created_at, updated_at, idp_type
 FROM oauth_identity_providers
 WHERE code = $1;
 
 -- name: IdentityProviderFindAll :many
 SELECT id, code, name, type, oidc_issuer_url, oidc_client_id,
        oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-       created_at, updated_at
+       created_at, updated_at, idp_type
 FROM oauth_identity_providers
 ORDER BY code;
 
@@ -28,8 +28,8 @@ ORDER BY code;
 INSERT INTO oauth_identity_providers
     (id, code, name, type, oidc_issuer_url, oidc_client_id,
      oidc_client_secret_ref, oidc_multi_tenant, oidc_issuer_pattern,
-     created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+     created_at, updated_at, idp_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 ON CONFLICT (id) DO UPDATE SET
     code = EXCLUDED.code,
     name = EXCLUDED.name,
@@ -38,6 +38,7 @@ ON CONFLICT (id) DO UPDATE SET
     oidc_client_secret_ref = EXCLUDED.oidc_client_secret_ref,
     oidc_multi_tenant = EXCLUDED.oidc_multi_tenant,
     oidc_issuer_pattern = EXCLUDED.oidc_issuer_pattern,
+    idp_type = EXCLUDED.idp_type,
     updated_at = EXCLUDED.updated_at;
 
 -- name: IdentityProviderDelete :exec
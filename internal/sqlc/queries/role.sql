@@ -85,25 +85,52 @@ ORDER BY name;
 SELECT DISTINCT application_code FROM iam_roles ORDER BY application_code;
 
 -- name: PermissionFindAll :many
-SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at
+SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at,
+       application_id, category, source
 FROM iam_permissions
 ORDER BY code;
 
 -- name: PermissionFindByCode :one
-SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at
+SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at,
+       application_id, category, source
 FROM iam_permissions
 WHERE code = $1;
 
 -- name: PermissionUpsert :exec
-INSERT INTO iam_permissions (id, code, subdomain, context, aggregate, action, description)
-VALUES (@id, @code, @subdomain, @context, @aggregate, @action, @description)
+-- CODE rows (the seeded platform catalog) are only rewritten by another
+-- CODE upsert, so a dashboard or SDK write can't take one over.
+INSERT INTO iam_permissions (id, code, subdomain, context, aggregate, action, description,
+                             application_id, category, source)
+VALUES (@id, @code, @subdomain, @context, @aggregate, @action, @description,
+        @application_id, @category, @source)
 ON CONFLICT (code) DO UPDATE SET
-    subdomain   = EXCLUDED.subdomain,
-    context     = EXCLUDED.context,
-    aggregate   = EXCLUDED.aggregate,
-    action      = EXCLUDED.action,
-    description = EXCLUDED.description,
-    updated_at  = NOW();
+    subdomain      = EXCLUDED.subdomain,
+    context        = EXCLUDED.context,
+    aggregate      = EXCLUDED.aggregate,
+    action         = EXCLUDED.action,
+    description    = EXCLUDED.description,
+    application_id = EXCLUDED.application_id,
+    category       = EXCLUDED.category,
+    source         = EXCLUDED.source,
+    updated_at     = NOW()
+WHERE iam_permissions.source <> 'CODE' OR EXCLUDED.source = 'CODE';
+
+-- name: PermissionFindByApplicationID :many
+SELECT id, code, subdomain, context, aggregate, action, description, created_at, updated_at,
+       application_id, category, source
+FROM iam_permissions
+WHERE application_id = $1
+ORDER BY code;
+
+-- name: PermissionCodes :many
+SELECT code FROM iam_permissions ORDER BY code;
+
+-- name: PermissionDeleteUnlisted :execrows
+-- Prunes one source's rows (one application's, for SDK) that aren't in codes.
+DELETE FROM iam_permissions
+WHERE source = @source
+  AND application_id IS NOT DISTINCT FROM sqlc.narg(application_id)::varchar
+  AND code <> ALL(@codes::text[]);
 
 -- name: PermissionDeleteByCode :exec
 DELETE FROM iam_permissions WHERE code = $1;
@@ -8,7 +8,7 @@ SELECT id, code, name, description, application_id, active,
        wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
        wh_signing_algorithm, wh_credentials_created_at,
        wh_credentials_regenerated_at, last_used_at, created_at, updated_at,
-       scope, client_ids
+       scope, client_ids, environment
 FROM iam_service_accounts
 WHERE id = $1;
 
@@ -17,7 +17,7 @@ SELECT id, code, name, description, application_id, active,
        wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
        wh_signing_algorithm, wh_credentials_created_at,
        wh_credentials_regenerated_at, last_used_at, created_at, updated_at,
-       scope, client_ids
+       scope, client_ids, environment
 FROM iam_service_accounts
 WHERE code = $1;
 
@@ -26,7 +26,7 @@ SELECT id, code, name, description, application_id, active,
        wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
        wh_signing_algorithm, wh_credentials_created_at,
        wh_credentials_regenerated_at, last_used_at, created_at, updated_at,
-       scope, client_ids
+       scope, client_ids, environment
 FROM iam_service_accounts
 ORDER BY code;
 
@@ -35,8 +35,8 @@ INSERT INTO iam_service_accounts
     (id, code, name, description, application_id, scope, client_ids, active,
      wh_auth_type, wh_auth_token_ref, wh_signing_secret_ref,
      wh_signing_algorithm, wh_credentials_created_at,
-     wh_credentials_regenerated_at, last_used_at, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+     wh_credentials_regenerated_at, last_used_at, created_at, updated_at, environment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 ON CONFLICT (id) DO UPDATE SET
     name = EXCLUDED.name,
     description = EXCLUDED.description,
@@ -1,9 +1,10 @@
 -- Queries for msg_subscriptions + msg_subscription_event_types +
--- msg_subscription_custom_configs.
+-- msg_subscription_custom_configs + msg_subscription_target_auth +
+-- msg_subscription_transforms.
 --
 -- Schema columns differ from the previous Go port in several places:
 --   - target (not endpoint)
---   - msg_subscription_event_types has no filter column
+--   - msg_subscription_event_types had no filter column (added in migration 042)
 --   - msg_subscription_custom_configs uses config_key/config_value (not key/value)
 -- All of these were silent runtime bugs in the pre-sqlc repo.
 -- created_by was added Go-side in migration 035 (Rust never had it; its
@@ -14,7 +15,10 @@ SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
 WHERE id = $1;
 
@@ -23,25 +27,34 @@ SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
-WHERE code = $1 AND client_id = $2;
+WHERE code = $1 AND client_id = $2 AND environment = $3;
 
 -- name: SubscriptionFindByCodeAnchor :one
 SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
-WHERE code = $1 AND client_id IS NULL;
+WHERE code = $1 AND client_id IS NULL AND environment = $2;
 
 -- name: SubscriptionFindAll :many
 SELECT id, code, application_code, name, description, client_id,
        client_identifier, client_scoped, target, queue,
        source, status, max_age_seconds, dispatch_pool_id, dispatch_pool_code,
        delay_seconds, sequence, mode, timeout_seconds, max_retries,
-       service_account_id, data_only, created_at, updated_at, connection_id, created_by
+       service_account_id, data_only, created_at, updated_at, connection_id, created_by,
+       priority, honor_retry_after, delivery_format, delivery_window, allow_private_target,
+       egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+       tap_sample_percent, tap_expires_at
 FROM msg_subscriptions
 ORDER BY code;
 
@@ -51,8 +64,10 @@ INSERT INTO msg_subscriptions
      client_scoped, connection_id, target, queue, source, status, max_age_seconds,
      dispatch_pool_id, dispatch_pool_code, delay_seconds, sequence, mode,
      timeout_seconds, max_retries, service_account_id, data_only,
-     created_by, created_at, updated_at)
-VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26)
+     created_by, created_at, updated_at, priority, honor_retry_after, delivery_format, delivery_window,
+     allow_private_target, egress_proxy, weight, ack_timeout_seconds, delivery_headers, environment,
+     tap_sample_percent, tap_expires_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38)
 ON CONFLICT (id) DO UPDATE SET
     name = EXCLUDED.name,
     description = EXCLUDED.description,
@@ -68,6 +83,17 @@ ON CONFLICT (id) DO UPDATE SET
     max_retries = EXCLUDED.max_retries,
     service_account_id = EXCLUDED.service_account_id,
     data_only = EXCLUDED.data_only,
+    priority = EXCLUDED.priority,
+    honor_retry_after = EXCLUDED.honor_retry_after,
+    delivery_format = EXCLUDED.delivery_format,
+    delivery_window = EXCLUDED.delivery_window,
+    allow_private_target = EXCLUDED.allow_private_target,
+    egress_proxy = EXCLUDED.egress_proxy,
+    weight = EXCLUDED.weight,
+    ack_timeout_seconds = EXCLUDED.ack_timeout_seconds,
+    delivery_headers = EXCLUDED.delivery_headers,
+    tap_sample_percent = EXCLUDED.tap_sample_percent,
+    tap_expires_at = EXCLUDED.tap_expires_at,
     updated_at = EXCLUDED.updated_at;
 
 -- name: SubscriptionDelete :exec
@@ -78,11 +104,11 @@ DELETE FROM msg_subscription_event_types WHERE subscription_id = $1;
 
 -- name: SubscriptionEventTypeInsert :exec
 INSERT INTO msg_subscription_event_types
-    (subscription_id, event_type_id, event_type_code, spec_version)
-VALUES (@subscription_id, @event_type_id, @event_type_code, @spec_version);
+    (subscription_id, event_type_id, event_type_code, spec_version, filter)
+VALUES (@subscription_id, @event_type_id, @event_type_code, @spec_version, @filter);
 
 -- name: SubscriptionEventTypesForSubs :many
-SELECT subscription_id, event_type_id, event_type_code, spec_version
+SELECT subscription_id, event_type_id, event_type_code, spec_version, filter
 FROM msg_subscription_event_types
 WHERE subscription_id = ANY(@subscription_ids::text[]);
 
@@ -98,3 +124,85 @@ VALUES (@subscription_id, @config_key, @config_value);
 SELECT subscription_id, config_key, config_value
 FROM msg_subscription_custom_configs
 WHERE subscription_id = ANY(@subscription_ids::text[]);
+
+-- name: SubscriptionTargetAuthClear :exec
+DELETE FROM msg_subscription_target_auth WHERE subscription_id = $1;
+
+-- name: SubscriptionTargetAuthUpsert :exec
+INSERT INTO msg_subscription_target_auth (subscription_id, auth_type, config, updated_at)
+VALUES (@subscription_id, @auth_type, @config, NOW())
+ON CONFLICT (subscription_id) DO UPDATE SET
+    auth_type = EXCLUDED.auth_type,
+    config = EXCLUDED.config,
+    updated_at = NOW();
+
+-- name: SubscriptionTargetAuthForSubs :many
+SELECT subscription_id, auth_type, config
+FROM msg_subscription_target_auth
+WHERE subscription_id = ANY(@subscription_ids::text[]);
+
+-- name: SubscriptionTargetAuthFind :one
+SELECT subscription_id, auth_type, config, updated_at
+FROM msg_subscription_target_auth
+WHERE subscription_id = $1;
+
+-- name: SubscriptionTransformClear :exec
+DELETE FROM msg_subscription_transforms WHERE subscription_id = $1;
+
+-- name: SubscriptionTransformUpsert :exec
+INSERT INTO msg_subscription_transforms (subscription_id, engine, template, content_type, updated_at)
+VALUES (@subscription_id, @engine, @template, @content_type, NOW())
+ON CONFLICT (subscription_id) DO UPDATE SET
+    engine = EXCLUDED.engine,
+    template = EXCLUDED.template,
+    content_type = EXCLUDED.content_type,
+    updated_at = NOW();
+
+-- name: SubscriptionTransformsForSubs :many
+SELECT subscription_id, engine, template, content_type
+FROM msg_subscription_transforms
+WHERE subscription_id = ANY(@subscription_ids::text[]);
+
+-- name: SubscriptionTransformFind :one
+SELECT subscription_id, engine, template, content_type, updated_at
+FROM msg_subscription_transforms
+WHERE subscription_id = $1;
+
+-- name: SubscriptionDeliverySettingsFind :one
+SELECT s.delivery_format, s.delivery_headers, s.delivery_window, s.ack_timeout_seconds,
+       s.allow_private_target, s.egress_proxy, s.tap_sample_percent, s.tap_expires_at,
+       s.honor_retry_after, t.engine AS transform_engine, t.template AS transform_template,
+       t.content_type AS transform_content_type
+FROM msg_subscriptions s
+LEFT JOIN msg_subscription_transforms t ON t.subscription_id = s.id
+WHERE s.id = $1;
+
+-- name: SubscriptionConfigSchemaFindByID :one
+SELECT id, application_code, mediation_type, description, fields, created_at, updated_at
+FROM msg_subscription_config_schemas
+WHERE id = $1;
+
+-- name: SubscriptionConfigSchemaFindAll :many
+SELECT id, application_code, mediation_type, description, fields, created_at, updated_at
+FROM msg_subscription_config_schemas
+ORDER BY application_code NULLS FIRST, mediation_type;
+
+-- name: SubscriptionConfigSchemaResolve :one
+SELECT id, application_code, mediation_type, description, fields, created_at, updated_at
+FROM msg_subscription_config_schemas
+WHERE mediation_type = @mediation_type
+  AND (application_code = sqlc.narg('application_code')::varchar OR application_code IS NULL)
+ORDER BY application_code NULLS LAST
+LIMIT 1;
+
+-- name: SubscriptionConfigSchemaUpsert :exec
+INSERT INTO msg_subscription_config_schemas
+    (id, application_code, mediation_type, description, fields, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE SET
+    description = EXCLUDED.description,
+    fields = EXCLUDED.fields,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: SubscriptionConfigSchemaDelete :exec
+DELETE FROM msg_subscription_config_schemas WHERE id = $1;
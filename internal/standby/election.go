@@ -1,9 +1,21 @@
-// Package standby implements Redis-backed leader election. Mirrors the
-// Rust fc-standby crate.
+// Package standby implements leader election over a pluggable lease
+// store. Mirrors the Rust fc-standby crate.
 //
-// One process acquires the lock via SET NX EX; while it holds the
-// lock, it periodically refreshes the TTL. If the leader crashes or
-// is partitioned, the lock expires and another instance acquires it.
+// One process acquires the lease; while it holds it, it periodically
+// renews the TTL. If the leader crashes or is partitioned, the lease
+// expires and another instance acquires it. Two backends ship:
+//
+//   - redis: SET NX PX on the lock key (see redis.go)
+//   - mongo: a TTL lease document in leader_leases (see mongo.go)
+//
+// Every fresh acquisition bumps a monotonically increasing fencing token,
+// reported on LeadershipChange. Writes are not checked against it; the
+// election uses it to tell a lease that lapsed and was re-won between
+// heartbeats from a renewal.
+//
+// With a Region configured the lease is additionally gated on the
+// multi-region handover record (see region.go), so only the active
+// region's instances ever lead.
 //
 // Consumers query IsLeader() (atomic, lock-free) or subscribe to a
 // channel of LeadershipChange events.
@@ -11,29 +23,46 @@ package standby
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/redis/go-redis/v9"
-
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
 )
 
 // LeadershipChange is emitted on transitions.
 type LeadershipChange struct {
 	IsLeader bool
-	At       time.Time
+	// Token is the fencing token of the lease just gained (0 on loss).
+	Token int64
+	At    time.Time
+}
+
+// Backend is a lease store. Implementations must make Acquire atomic:
+// exactly one instanceID may hold key at a time.
+type Backend interface {
+	// Acquire takes the lease when free/expired, or renews it when
+	// instanceID already holds it. Returns held=false when another
+	// instance owns it. token is the fencing token of the held lease —
+	// unchanged across renewals, strictly greater than every previous
+	// token on a fresh acquisition.
+	Acquire(ctx context.Context, key, instanceID string, ttl time.Duration) (held bool, token int64, err error)
+	// Release drops the lease if instanceID holds it.
+	Release(ctx context.Context, key, instanceID string) error
+	// Ping verifies the store is reachable.
+	Ping(ctx context.Context) error
+	// Close releases client resources.
+	Close() error
 }
 
 // Election is a single instance of the leader-election state machine.
 type Election struct {
-	cfg    common.LeaderElectionConfig
-	client *redis.Client
+	cfg     common.LeaderElectionConfig
+	backend Backend
 
 	isLeader atomic.Bool
+	token    atomic.Int64
 	stopOnce sync.Once
 	stopCh   chan struct{}
 	doneCh   chan struct{}
@@ -42,19 +71,44 @@ type Election struct {
 	subs   []chan LeadershipChange
 }
 
-// New constructs an Election. The caller is responsible for calling
-// Start to spawn the heartbeat goroutine and Stop on shutdown.
+// New constructs an Election on the backend cfg.Backend names ("redis"
+// when empty). The caller is responsible for calling Start to spawn the
+// heartbeat goroutine and Stop on shutdown.
 func New(cfg common.LeaderElectionConfig) (*Election, error) {
-	opts, err := redis.ParseURL(cfg.RedisURL)
+	var (
+		b   Backend
+		err error
+	)
+	switch cfg.Backend {
+	case "", "redis":
+		b, err = NewRedisBackend(cfg.RedisURL)
+	case "mongo", "mongodb":
+		b, err = NewMongoBackend(cfg.MongoURI, cfg.MongoDatabase, cfg.Mongo)
+	default:
+		return nil, fmt.Errorf("unknown leader election backend %q (want redis|mongo)", cfg.Backend)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("parse redis url: %w", err)
+		return nil, err
+	}
+	if cfg.Region != "" {
+		store, err := NewMongoRegionStore(cfg.MongoURI, cfg.MongoDatabase, cfg.InitialActiveRegion, cfg.Mongo)
+		if err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("region gate: %w", err)
+		}
+		b = NewRegionGatedBackend(b, store, cfg.Region)
 	}
+	return NewWithBackend(cfg, b), nil
+}
+
+// NewWithBackend constructs an Election on a caller-supplied backend.
+func NewWithBackend(cfg common.LeaderElectionConfig, b Backend) *Election {
 	return &Election{
-		cfg:    cfg,
-		client: redis.NewClient(opts),
-		stopCh: make(chan struct{}),
-		doneCh: make(chan struct{}),
-	}, nil
+		cfg:     cfg,
+		backend: b,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
 }
 
 // IsLeader reports whether this instance currently holds the lock.
@@ -76,12 +130,12 @@ func (e *Election) Subscribe() <-chan LeadershipChange {
 func (e *Election) Start(ctx context.Context) error {
 	if !e.cfg.Enabled {
 		// Disabled: assume leader (single-instance mode).
-		e.setLeader(true)
+		e.setLeader(true, 0)
 		close(e.doneCh)
 		return nil
 	}
-	if err := e.client.Ping(ctx).Err(); err != nil {
-		return fmt.Errorf("redis ping: %w", err)
+	if err := e.backend.Ping(ctx); err != nil {
+		return fmt.Errorf("leader election backend ping: %w", err)
 	}
 	go e.loop(ctx)
 	return nil
@@ -96,10 +150,11 @@ func (e *Election) Stop(ctx context.Context) error {
 	case <-ctx.Done():
 		return ctx.Err()
 	}
-	if e.IsLeader() {
-		_ = e.releaseUnsafe(ctx)
+	if e.IsLeader() && e.cfg.Enabled {
+		_ = e.backend.Release(ctx, e.cfg.LockKey, e.cfg.InstanceID)
+		e.setLeader(false, 0)
 	}
-	return e.client.Close()
+	return e.backend.Close()
 }
 
 func (e *Election) loop(ctx context.Context) {
@@ -116,60 +171,43 @@ func (e *Election) loop(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
+			// shutdown
 			return
 		case <-e.stopCh:
+			// Stop called
 			return
 		case <-ticker.C:
+			// heartbeat: acquire or renew
 			e.tryAcquire(ctx)
 		}
 	}
 }
 
-// tryAcquire attempts to take or refresh the lock. The acquisition
-// strategy is uniform: SET key id EX ttl NX (acquire) or, if we
-// already hold it, SET key id EX ttl XX (refresh). We use a Lua
-// script for safe extend-if-mine.
+// tryAcquire takes or renews the lease. Any backend error demotes: a
+// leader that cannot prove it still holds the lease must stop acting.
 func (e *Election) tryAcquire(ctx context.Context) {
 	ttl := time.Duration(e.cfg.LockTTLSeconds) * time.Second
 	if ttl <= 0 {
 		ttl = 30 * time.Second
 	}
-	// Try acquire (NX). On success: we are the leader.
-	ok, err := e.client.SetNX(ctx, e.cfg.LockKey, e.cfg.InstanceID, ttl).Result()
+	held, token, err := e.backend.Acquire(ctx, e.cfg.LockKey, e.cfg.InstanceID, ttl)
 	if err != nil {
 		// Network blip; demote to safe.
-		e.setLeader(false)
-		return
-	}
-	if ok {
-		e.setLeader(true)
-		return
-	}
-	// SetNX failed — somebody owns the lock. Try refresh-if-mine.
-	res, err := refreshIfMine.Run(ctx, e.client,
-		[]string{e.cfg.LockKey}, e.cfg.InstanceID, int(ttl.Seconds())).Int()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		e.setLeader(false)
+		e.setLeader(false, 0)
 		return
 	}
-	e.setLeader(res == 1)
+	e.setLeader(held, token)
 }
 
-func (e *Election) releaseUnsafe(ctx context.Context) error {
-	_, err := releaseIfMine.Run(ctx, e.client,
-		[]string{e.cfg.LockKey}, e.cfg.InstanceID).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil
-	}
-	return err
-}
-
-func (e *Election) setLeader(now bool) {
+func (e *Election) setLeader(now bool, token int64) {
+	prevToken := e.token.Swap(token)
 	prev := e.isLeader.Swap(now)
-	if prev == now {
+	// A token change while leader means the lease lapsed and was re-won
+	// between heartbeats — report it as a fresh gain so subscribers re-fence.
+	if prev == now && (!now || prevToken == token) {
 		return
 	}
-	change := LeadershipChange{IsLeader: now, At: time.Now()}
+	change := LeadershipChange{IsLeader: now, Token: token, At: time.Now()}
 	e.subsMu.RLock()
 	for _, ch := range e.subs {
 		select {
@@ -180,17 +218,3 @@ func (e *Election) setLeader(now bool) {
 	}
 	e.subsMu.RUnlock()
 }
-
-var refreshIfMine = redis.NewScript(`
-if redis.call("GET", KEYS[1]) == ARGV[1] then
-  return redis.call("EXPIRE", KEYS[1], ARGV[2])
-end
-return 0
-`)
-
-var releaseIfMine = redis.NewScript(`
-if redis.call("GET", KEYS[1]) == ARGV[1] then
-  return redis.call("DEL", KEYS[1])
-end
-return 0
-`)
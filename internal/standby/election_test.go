@@ -0,0 +1,132 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+// memBackend is an in-process lease store with the same semantics the
+// Redis and Mongo backends implement.
+type memBackend struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	token   int64
+	now     time.Time
+	fail    bool
+}
+
+func (m *memBackend) Acquire(_ context.Context, _, id string, ttl time.Duration) (bool, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return false, 0, errors.New("backend down")
+	}
+	switch {
+	case m.holder == id && m.now.Before(m.expires):
+		m.expires = m.now.Add(ttl)
+		return true, m.token, nil
+	case m.holder == "" || !m.now.Before(m.expires):
+		m.holder, m.expires = id, m.now.Add(ttl)
+		m.token++
+		return true, m.token, nil
+	default:
+		return false, 0, nil
+	}
+}
+
+func (m *memBackend) Release(_ context.Context, _, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder == id {
+		m.holder = ""
+	}
+	return nil
+}
+
+func (m *memBackend) Ping(context.Context) error { return nil }
+func (m *memBackend) Close() error               { return nil }
+
+// tokenOf is e's fencing token while it leads, 0 otherwise.
+func tokenOf(e *Election) int64 {
+	if !e.IsLeader() {
+		return 0
+	}
+	return e.token.Load()
+}
+
+func testConfig(id string) common.LeaderElectionConfig {
+	cfg := common.NewLeaderElectionConfig("")
+	cfg.InstanceID = id
+	return cfg
+}
+
+func TestOnlyOneInstanceLeads(t *testing.T) {
+	b := &memBackend{now: time.Unix(1_700_000_000, 0)}
+	a := NewWithBackend(testConfig("a"), b)
+	c := NewWithBackend(testConfig("c"), b)
+
+	a.tryAcquire(context.Background())
+	c.tryAcquire(context.Background())
+
+	if !a.IsLeader() || c.IsLeader() {
+		t.Fatalf("leaders: a=%v c=%v, want only a", a.IsLeader(), c.IsLeader())
+	}
+	if tokenOf(a) != 1 || tokenOf(c) != 0 {
+		t.Errorf("tokens: a=%d c=%d, want 1 and 0", tokenOf(a), tokenOf(c))
+	}
+}
+
+func TestFencingTokenIncreasesOnTakeover(t *testing.T) {
+	b := &memBackend{now: time.Unix(1_700_000_000, 0)}
+	a := NewWithBackend(testConfig("a"), b)
+	c := NewWithBackend(testConfig("c"), b)
+
+	a.tryAcquire(context.Background())
+	a.tryAcquire(context.Background()) // renewal keeps the token
+	if tokenOf(a) != 1 {
+		t.Fatalf("renewed token = %d, want 1", tokenOf(a))
+	}
+
+	b.now = b.now.Add(time.Minute) // a's lease lapses
+	c.tryAcquire(context.Background())
+	if !c.IsLeader() || tokenOf(c) != 2 {
+		t.Fatalf("takeover: leader=%v token=%d, want true/2", c.IsLeader(), tokenOf(c))
+	}
+	a.tryAcquire(context.Background())
+	if a.IsLeader() {
+		t.Error("deposed instance must not still report leader")
+	}
+}
+
+func TestBackendErrorDemotes(t *testing.T) {
+	b := &memBackend{now: time.Unix(1_700_000_000, 0)}
+	a := NewWithBackend(testConfig("a"), b)
+	sub := a.Subscribe()
+
+	a.tryAcquire(context.Background())
+	if ch := <-sub; !ch.IsLeader || ch.Token != 1 {
+		t.Fatalf("gain event = %+v", ch)
+	}
+	b.fail = true
+	a.tryAcquire(context.Background())
+	if a.IsLeader() {
+		t.Fatal("backend error should demote")
+	}
+	if ch := <-sub; ch.IsLeader {
+		t.Fatalf("loss event = %+v", ch)
+	}
+}
+
+func TestNewRejectsUnknownBackend(t *testing.T) {
+	cfg := testConfig("a")
+	cfg.Backend = "etcd"
+	if _, err := New(cfg); err == nil {
+		t.Fatal("expected an error for an unknown backend")
+	}
+}
@@ -0,0 +1,118 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const leaseCollection = "leader_leases"
+
+// MongoBackend holds the lease as one document per lock key in
+// leader_leases:
+//
+//	{ _id: <key>, holder: <instanceID>, token: <int64>, expires_at: <date> }
+//
+// Acquisition is a conditional upsert (filter: expired), renewal a
+// conditional update (filter: holder == me); the unique _id makes a lost
+// race surface as a duplicate-key error. Expiry is checked against
+// expires_at in every filter rather than left to a TTL index: the
+// document is never deleted, so the token survives renewals, releases
+// and lapses and strictly increases on every takeover. There is one
+// document per lock key, so the collection stays tiny.
+type MongoBackend struct {
+	client *mongo.Client
+	coll   *mongo.Collection
+	// now is the lease clock (UTC).
+	now func() time.Time
+}
+
+type leaseDoc struct {
+	Key       string    `bson:"_id"`
+	Holder    string    `bson:"holder"`
+	Token     int64     `bson:"token"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// NewMongoBackend connects to uri with mc and targets database dbName.
+func NewMongoBackend(uri, dbName string, mc mongoconn.Config) (*MongoBackend, error) {
+	if uri == "" {
+		return nil, errors.New("mongo leader election requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	client, err := mongoconn.Connect(context.Background(), "standby-lease", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	return &MongoBackend{
+		client: client,
+		coll:   client.Database(dbName).Collection(leaseCollection),
+		now:    func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// Acquire implements Backend.
+func (b *MongoBackend) Acquire(ctx context.Context, key, instanceID string, ttl time.Duration) (bool, int64, error) {
+	now := b.now()
+	expires := now.Add(ttl)
+
+	// Renew if we already hold it (and it hasn't lapsed).
+	var renewed leaseDoc
+	err := b.coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": key, "holder": instanceID, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"expires_at": expires}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&renewed)
+	if err == nil {
+		return true, renewed.Token, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return false, 0, err
+	}
+
+	// Take over a missing or expired lease. A live lease held by someone
+	// else fails the filter, and the upsert's insert then collides on _id.
+	var taken leaseDoc
+	err = b.coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": key, "expires_at": bson.M{"$lte": now}},
+		bson.M{
+			"$set": bson.M{"holder": instanceID, "expires_at": expires},
+			"$inc": bson.M{"token": int64(1)},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&taken)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+	return true, taken.Token, nil
+}
+
+// Release implements Backend. Expires the lease in place rather than
+// deleting it so the fencing token keeps counting up for the next holder.
+func (b *MongoBackend) Release(ctx context.Context, key, instanceID string) error {
+	_, err := b.coll.UpdateOne(ctx,
+		bson.M{"_id": key, "holder": instanceID},
+		bson.M{"$set": bson.M{"expires_at": b.now()}})
+	return err
+}
+
+// Ping implements Backend.
+func (b *MongoBackend) Ping(ctx context.Context) error { return b.client.Ping(ctx, nil) }
+
+// Close implements Backend.
+func (b *MongoBackend) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return b.client.Disconnect(ctx)
+}
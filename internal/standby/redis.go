@@ -0,0 +1,84 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend holds the lease as `SET key instanceID NX PX ttl`. The
+// fencing token is a persistent INCR counter at `{key}:fence` — the hash
+// tag pins it to the lock key's cluster slot so the acquire script stays
+// single-slot on Redis Cluster.
+type RedisBackend struct {
+	client *redis.Client
+}
+
+// NewRedisBackend dials nothing — go-redis connects lazily; Ping in
+// Election.Start surfaces an unreachable server.
+func NewRedisBackend(url string) (*RedisBackend, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	return &RedisBackend{client: redis.NewClient(opts)}, nil
+}
+
+func fenceKey(key string) string { return "{" + key + "}:fence" }
+
+// Acquire implements Backend. A single script run decides renew vs take
+// vs lose so a concurrent holder can never interleave between the check
+// and the write.
+func (b *RedisBackend) Acquire(ctx context.Context, key, instanceID string, ttl time.Duration) (bool, int64, error) {
+	token, err := acquireOrRenew.Run(ctx, b.client,
+		[]string{key, fenceKey(key)}, instanceID, ttl.Milliseconds()).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, 0, err
+	}
+	return token > 0, token, nil
+}
+
+// Release implements Backend.
+func (b *RedisBackend) Release(ctx context.Context, key, instanceID string) error {
+	_, err := releaseIfMine.Run(ctx, b.client, []string{key}, instanceID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// Ping implements Backend.
+func (b *RedisBackend) Ping(ctx context.Context) error { return b.client.Ping(ctx).Err() }
+
+// Close implements Backend.
+func (b *RedisBackend) Close() error { return b.client.Close() }
+
+// acquireOrRenew returns the fencing token when the caller holds the lease
+// after the call, 0 otherwise. Renewal keeps the current token; a fresh
+// SET NX bumps it.
+var acquireOrRenew = redis.NewScript(`
+local cur = redis.call("GET", KEYS[1])
+if cur == ARGV[1] then
+  redis.call("PEXPIRE", KEYS[1], ARGV[2])
+  local t = redis.call("GET", KEYS[2])
+  if t then return tonumber(t) end
+  return redis.call("INCR", KEYS[2])
+end
+if cur then
+  return 0
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+  return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+var releaseIfMine = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
@@ -0,0 +1,146 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Multi-region active/passive. Every region runs the full stack against
+// the same (globally replicated) databases, but only the active region may
+// lead: a region-gated election in a passive region stays connected and
+// warm yet never takes its lease, so its router pools, scheduler and
+// stream processor idle.
+//
+// The active region lives in a single handover record (see
+// MongoRegionStore). Promotion flips it and bumps the epoch, but the new
+// region only starts serving at EffectiveAt — one handover window later —
+// so the old region's leaders, which re-read the record on every
+// heartbeat, have stepped down first. Fencing tokens of a gated election
+// carry the epoch in their high bits, so a token minted after a failover
+// is greater than every token from before it whatever the regional lease
+// stores say.
+
+// ErrPromoteConflict is returned when another promotion changed the
+// handover record between read and write.
+var ErrPromoteConflict = errors.New("standby: concurrent region promotion")
+
+// epochShift leaves 2^40 lease-store tokens per epoch.
+const epochShift = 40
+
+// RegionState is the handover record.
+type RegionState struct {
+	// Active is the region allowed to lead ("" = none yet).
+	Active string
+	// Previous is the region Active took over from.
+	Previous string
+	// Epoch increases on every promotion.
+	Epoch int64
+	// EffectiveAt is when Active starts serving.
+	EffectiveAt time.Time
+	PromotedAt  time.Time
+	PromotedBy  string
+	Reason      string
+}
+
+// Serving reports whether region may lead at now.
+func (s RegionState) Serving(region string, now time.Time) bool {
+	return region != "" && s.Active == region && !now.Before(s.EffectiveAt)
+}
+
+// RegionStore persists the handover record.
+type RegionStore interface {
+	// State returns the record, seeding it with the store's initial
+	// active region when none exists yet.
+	State(ctx context.Context) (RegionState, error)
+	// Promote makes region active from now+handover. Promoting the
+	// already-active region is a no-op that returns the current record.
+	Promote(ctx context.Context, region, by, reason string, handover time.Duration) (RegionState, error)
+	Close() error
+}
+
+// regionGated admits lease acquisition only while the store names this
+// instance's region as serving.
+type regionGated struct {
+	inner  Backend
+	store  RegionStore
+	region string
+	now    func() time.Time
+}
+
+// NewRegionGatedBackend wraps inner so that Acquire only succeeds in
+// region while it is the serving region. A passive region releases any
+// lease it still holds; an unreadable handover record is an error, which
+// demotes the caller.
+func NewRegionGatedBackend(inner Backend, store RegionStore, region string) Backend {
+	return &regionGated{inner: inner, store: store, region: region, now: time.Now}
+}
+
+// Acquire implements Backend.
+func (b *regionGated) Acquire(ctx context.Context, key, instanceID string, ttl time.Duration) (bool, int64, error) {
+	st, err := b.store.State(ctx)
+	if err != nil {
+		return false, 0, fmt.Errorf("read region handover: %w", err)
+	}
+	if !st.Serving(b.region, b.now()) {
+		_ = b.inner.Release(ctx, key, instanceID)
+		return false, 0, nil
+	}
+	held, token, err := b.inner.Acquire(ctx, key, instanceID, ttl)
+	if err != nil || !held {
+		return held, 0, err
+	}
+	return true, st.Epoch<<epochShift | token, nil
+}
+
+// Release implements Backend.
+func (b *regionGated) Release(ctx context.Context, key, instanceID string) error {
+	return b.inner.Release(ctx, key, instanceID)
+}
+
+// Ping implements Backend. The handover record must be readable too.
+func (b *regionGated) Ping(ctx context.Context) error {
+	if err := b.inner.Ping(ctx); err != nil {
+		return err
+	}
+	_, err := b.store.State(ctx)
+	return err
+}
+
+// Close implements Backend.
+func (b *regionGated) Close() error {
+	return errors.Join(b.inner.Close(), b.store.Close())
+}
+
+// Failover is the operator handle behind the promote API: it reports the
+// handover record and promotes this instance's region.
+type Failover struct {
+	store    RegionStore
+	region   string
+	handover time.Duration
+}
+
+// NewFailover builds the handle for region. handover is the delay
+// between a promotion and the new region serving; it must cover the old
+// region's heartbeat interval (30s when zero).
+func NewFailover(store RegionStore, region string, handover time.Duration) *Failover {
+	if handover <= 0 {
+		handover = 30 * time.Second
+	}
+	return &Failover{store: store, region: region, handover: handover}
+}
+
+// Region is this instance's region.
+func (f *Failover) Region() string { return f.region }
+
+// Status returns the current handover record.
+func (f *Failover) Status(ctx context.Context) (RegionState, error) { return f.store.State(ctx) }
+
+// Promote makes this instance's region the active one.
+func (f *Failover) Promote(ctx context.Context, by, reason string) (RegionState, error) {
+	return f.store.Promote(ctx, f.region, by, reason, f.handover)
+}
+
+// Close releases the store.
+func (f *Failover) Close() error { return f.store.Close() }
@@ -0,0 +1,145 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/mongoconn"
+)
+
+const (
+	regionCollection = "region_failover"
+	regionDocID      = "active"
+)
+
+// MongoRegionStore keeps the handover record as one document in
+// region_failover:
+//
+//	{ _id: "active", active: "eu-west-1", previous: "eu-central-1", epoch: 3,
+//	  effective_at: <date>, promoted_at: <date>, promoted_by: "ops", reason: "…" }
+//
+// The collection must live in a cluster replicated to every region. Reads
+// and writes use majority concern against the primary, so a region never
+// acts on a record another region hasn't durably seen. Promotion is a
+// compare-and-set on epoch; a lost race surfaces as ErrPromoteConflict.
+type MongoRegionStore struct {
+	client  *mongo.Client
+	coll    *mongo.Collection
+	initial string
+	now     func() time.Time
+}
+
+type regionDoc struct {
+	ID          string    `bson:"_id"`
+	Active      string    `bson:"active"`
+	Previous    string    `bson:"previous,omitempty"`
+	Epoch       int64     `bson:"epoch"`
+	EffectiveAt time.Time `bson:"effective_at"`
+	PromotedAt  time.Time `bson:"promoted_at,omitempty"`
+	PromotedBy  string    `bson:"promoted_by,omitempty"`
+	Reason      string    `bson:"reason,omitempty"`
+}
+
+func (d regionDoc) state() RegionState {
+	return RegionState{
+		Active: d.Active, Previous: d.Previous, Epoch: d.Epoch, EffectiveAt: d.EffectiveAt,
+		PromotedAt: d.PromotedAt, PromotedBy: d.PromotedBy, Reason: d.Reason,
+	}
+}
+
+// NewMongoRegionStore connects to uri and targets database dbName.
+// initial seeds the record on first read (the region that is active
+// before anyone has promoted); empty leaves every region passive until
+// the first promotion.
+func NewMongoRegionStore(uri, dbName, initial string, mc mongoconn.Config) (*MongoRegionStore, error) {
+	if uri == "" {
+		return nil, errors.New("region failover requires a MongoDB URI")
+	}
+	if dbName == "" {
+		dbName = "flowcatalyst"
+	}
+	client, err := mongoconn.Connect(context.Background(), "standby-region", uri, mc)
+	if err != nil {
+		return nil, err
+	}
+	coll := client.Database(dbName).Collection(regionCollection, options.Collection().
+		SetReadConcern(readconcern.Majority()).
+		SetWriteConcern(writeconcern.Majority()).
+		SetReadPreference(readpref.Primary()))
+	return &MongoRegionStore{
+		client:  client,
+		coll:    coll,
+		initial: initial,
+		now:     func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// State implements RegionStore.
+func (s *MongoRegionStore) State(ctx context.Context) (RegionState, error) {
+	var doc regionDoc
+	if s.initial == "" {
+		err := s.coll.FindOne(ctx, bson.M{"_id": regionDocID}).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return RegionState{}, nil
+		}
+		return doc.state(), err
+	}
+	err := s.coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": regionDocID},
+		bson.M{"$setOnInsert": bson.M{"active": s.initial, "epoch": int64(1), "effective_at": time.Unix(0, 0).UTC()}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	if mongo.IsDuplicateKeyError(err) {
+		// Lost the seeding race; the winner's document is there now.
+		err = s.coll.FindOne(ctx, bson.M{"_id": regionDocID}).Decode(&doc)
+	}
+	return doc.state(), err
+}
+
+// Promote implements RegionStore.
+func (s *MongoRegionStore) Promote(ctx context.Context, region, by, reason string, handover time.Duration) (RegionState, error) {
+	cur, err := s.State(ctx)
+	if err != nil {
+		return RegionState{}, err
+	}
+	if cur.Active == region {
+		return cur, nil
+	}
+	now := s.now()
+	var doc regionDoc
+	err = s.coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": regionDocID, "epoch": cur.Epoch},
+		bson.M{"$set": bson.M{
+			"active":       region,
+			"previous":     cur.Active,
+			"epoch":        cur.Epoch + 1,
+			"effective_at": now.Add(handover),
+			"promoted_at":  now,
+			"promoted_by":  by,
+			"reason":       reason,
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return RegionState{}, ErrPromoteConflict
+	}
+	if err != nil {
+		return RegionState{}, err
+	}
+	return doc.state(), nil
+}
+
+// Close implements RegionStore.
+func (s *MongoRegionStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.client.Disconnect(ctx)
+}
@@ -0,0 +1,125 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memRegionStore is an in-process handover record.
+type memRegionStore struct {
+	mu    sync.Mutex
+	state RegionState
+	now   func() time.Time
+	fail  bool
+}
+
+func (m *memRegionStore) State(context.Context) (RegionState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return RegionState{}, errors.New("store down")
+	}
+	return m.state, nil
+}
+
+func (m *memRegionStore) Promote(_ context.Context, region, by, reason string, handover time.Duration) (RegionState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.state.Active == region {
+		return m.state, nil
+	}
+	now := m.now()
+	m.state = RegionState{
+		Active: region, Previous: m.state.Active, Epoch: m.state.Epoch + 1,
+		EffectiveAt: now.Add(handover), PromotedAt: now, PromotedBy: by, Reason: reason,
+	}
+	return m.state, nil
+}
+
+func (m *memRegionStore) Close() error { return nil }
+
+// regionalElection builds an election in region whose lease store is its
+// own (as with per-region Redis) and whose clock is the lease clock.
+func regionalElection(id, region string, store *memRegionStore, clock *time.Time) (*Election, *memBackend) {
+	lease := &memBackend{now: *clock}
+	g := NewRegionGatedBackend(lease, store, region).(*regionGated)
+	g.now = func() time.Time { return *clock }
+	return NewWithBackend(testConfig(id), g), lease
+}
+
+func TestRegionFailover(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(1_700_000_000, 0)
+	store := &memRegionStore{state: RegionState{Active: "eu", Epoch: 1}, now: func() time.Time { return clock }}
+
+	primary, _ := regionalElection("eu-1", "eu", store, &clock)
+	secondary, _ := regionalElection("us-1", "us", store, &clock)
+
+	primary.tryAcquire(ctx)
+	secondary.tryAcquire(ctx)
+	if !primary.IsLeader() || secondary.IsLeader() {
+		t.Fatalf("before failover: eu=%v us=%v, want only eu", primary.IsLeader(), secondary.IsLeader())
+	}
+	before := tokenOf(primary)
+
+	fo := NewFailover(store, "us", 30*time.Second)
+	st, err := fo.Promote(ctx, "ops", "eu outage")
+	if err != nil || st.Active != "us" || st.Previous != "eu" || st.Epoch != 2 {
+		t.Fatalf("promote = %+v, %v", st, err)
+	}
+
+	// The old region steps down on its next heartbeat; the new one waits
+	// out the handover window, so nobody leads in between.
+	primary.tryAcquire(ctx)
+	secondary.tryAcquire(ctx)
+	if primary.IsLeader() || secondary.IsLeader() {
+		t.Fatalf("during handover: eu=%v us=%v, want neither", primary.IsLeader(), secondary.IsLeader())
+	}
+
+	clock = clock.Add(30 * time.Second)
+	secondary.tryAcquire(ctx)
+	primary.tryAcquire(ctx)
+	if !secondary.IsLeader() || primary.IsLeader() {
+		t.Fatalf("after handover: eu=%v us=%v, want only us", primary.IsLeader(), secondary.IsLeader())
+	}
+	if tokenOf(secondary) <= before {
+		t.Errorf("token after failover %d must exceed %d", tokenOf(secondary), before)
+	}
+
+	if again, _ := fo.Promote(ctx, "ops", "retry"); again.Epoch != 2 {
+		t.Errorf("re-promoting the active region must be a no-op, got epoch %d", again.Epoch)
+	}
+}
+
+func TestRegionStoreErrorDemotes(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	store := &memRegionStore{state: RegionState{Active: "eu", Epoch: 1}, now: func() time.Time { return clock }}
+	e, lease := regionalElection("eu-1", "eu", store, &clock)
+
+	e.tryAcquire(context.Background())
+	if !e.IsLeader() {
+		t.Fatal("active region should lead")
+	}
+	store.fail = true
+	e.tryAcquire(context.Background())
+	if e.IsLeader() {
+		t.Fatal("an unreadable handover record must demote")
+	}
+	if lease.holder != "eu-1" {
+		t.Error("a store error alone should not release the lease")
+	}
+}
+
+func TestServing(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	st := RegionState{Active: "eu", EffectiveAt: now}
+	if !st.Serving("eu", now) || st.Serving("us", now) || st.Serving("eu", now.Add(-time.Second)) {
+		t.Fatal("unexpected Serving result")
+	}
+	if (RegionState{}).Serving("", now) {
+		t.Fatal("no region is serving before the first promotion")
+	}
+}
@@ -0,0 +1,38 @@
+package stream
+
+import "sync"
+
+// ChangeFeed tells in-process listeners that a projection wrote rows, so
+// caches over its read table can drop what they hold. Delivery is
+// best-effort and only reaches the process running the projector — a
+// listener in another process still needs its own expiry.
+type ChangeFeed struct {
+	mu   sync.RWMutex
+	subs map[string][]func()
+}
+
+// Changes is the process-wide feed the stream processor publishes to.
+var Changes = NewChangeFeed()
+
+// NewChangeFeed builds an empty feed.
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{subs: map[string][]func(){}}
+}
+
+// Subscribe calls fn whenever the named projection reports a non-empty
+// batch. fn runs on the projector's goroutine, so it must be cheap.
+func (f *ChangeFeed) Subscribe(projection string, fn func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subs[projection] = append(f.subs[projection], fn)
+}
+
+// Notify runs the named projection's subscribers.
+func (f *ChangeFeed) Notify(projection string) {
+	f.mu.RLock()
+	subs := f.subs[projection]
+	f.mu.RUnlock()
+	for _, fn := range subs {
+		fn()
+	}
+}
@@ -73,7 +73,7 @@ func (p *DispatchJobProjection) step(ctx context.Context, batchSize int) (int, e
 		     status, max_retries, retry_strategy, scheduled_for, expires_at,
 		     attempt_count, last_attempt_at, completed_at, duration_millis, last_error,
 		     idempotency_key, is_completed, is_terminal,
-		     application, subdomain, aggregate,
+		     application, subdomain, aggregate, environment,
 		     created_at, updated_at, projected_at)
 		 SELECT j.id, j.external_id, j.source, j.kind, j.code, j.subject,
 		        j.event_id, j.correlation_id, j.target_url, j.protocol,
@@ -94,6 +94,7 @@ func (p *DispatchJobProjection) step(ctx context.Context, batchSize int) (int, e
 		        split_part(j.code, ':', 1),
 		        NULLIF(split_part(j.code, ':', 2), ''),
 		        NULLIF(split_part(j.code, ':', 3), ''),
+		        j.environment,
 		        j.created_at, j.updated_at, NOW()
 		   FROM msg_dispatch_jobs j
 		  WHERE j.id = ANY($1)
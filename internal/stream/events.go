@@ -72,7 +72,7 @@ func (p *EventProjection) step(ctx context.Context, batchSize int) (int, error)
 		`INSERT INTO msg_events_read
 		     (id, spec_version, type, source, subject, time, data,
 		      correlation_id, causation_id, deduplication_id, message_group,
-		      client_id, application, subdomain, aggregate, created_at, projected_at)
+		      client_id, application, subdomain, aggregate, created_at, projected_at, environment)
 		 SELECT e.id, e.spec_version, e.type, e.source, e.subject, e.time, e.data::text,
 		        e.correlation_id, e.causation_id, e.deduplication_id, e.message_group,
 		        e.client_id,
@@ -84,7 +84,8 @@ func (p *EventProjection) step(ctx context.Context, batchSize int) (int, error)
 		        -- source events and age out with them. Was defaulting to the
 		        -- projection time (NOW()). Mirrors the Rust event projection.
 		        e.created_at,
-		        NOW()
+		        NOW(),
+		        e.environment
 		   FROM msg_events e
 		  WHERE e.id = ANY($1)
 		 ON CONFLICT (id, created_at) DO NOTHING`, ids); err != nil {
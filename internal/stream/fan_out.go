@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+	"github.com/flowcatalyst/flowcatalyst-go/internal/eventfilter"
 	"github.com/flowcatalyst/flowcatalyst-go/internal/tsid"
 )
 
@@ -138,6 +141,7 @@ type claimedEvent struct {
 	CorrelationID *string
 	MessageGroup  *string
 	ClientID      *string
+	Environment   common.Environment
 	CreatedAt     time.Time
 }
 
@@ -158,7 +162,7 @@ func claimUnfannedEvents(ctx context.Context, tx pgx.Tx, batchSize int) ([]claim
 		   FROM batch b
 		  WHERE e.id = b.id AND e.created_at = b.created_at
 		 RETURNING e.id, e.type, e.source, e.subject, e.data,
-		           e.correlation_id, e.message_group, e.client_id, e.created_at`,
+		           e.correlation_id, e.message_group, e.client_id, e.environment, e.created_at`,
 		batchSize)
 	if err != nil {
 		return nil, err
@@ -168,10 +172,12 @@ func claimUnfannedEvents(ctx context.Context, tx pgx.Tx, batchSize int) ([]claim
 	for rows.Next() {
 		var e claimedEvent
 		var data []byte
+		var env string
 		if err := rows.Scan(&e.ID, &e.EventType, &e.Source, &e.Subject, &data,
-			&e.CorrelationID, &e.MessageGroup, &e.ClientID, &e.CreatedAt); err != nil {
+			&e.CorrelationID, &e.MessageGroup, &e.ClientID, &env, &e.CreatedAt); err != nil {
 			return nil, err
 		}
+		e.Environment = common.ParseEnvironment(env)
 		if len(data) > 0 {
 			e.Data = data
 		}
@@ -185,28 +191,101 @@ func claimUnfannedEvents(ctx context.Context, tx pgx.Tx, batchSize int) ([]claim
 // cachedSubscription is the minimal field set fanout needs. Loaded by
 // `loadActiveSubscriptions` and refreshed every SubscriptionTTL.
 type cachedSubscription struct {
-	ID                string
-	ClientID          *string
-	Target            string
-	Mode              common.DispatchMode
-	DataOnly          bool
-	DispatchPoolID    *string
-	ServiceAccountID  *string
-	MaxRetries        int32
-	TimeoutSeconds    int32
-	Sequence          int32
-	EventTypePatterns []string
+	ID               string
+	ClientID         *string
+	Environment      common.Environment
+	Target           string
+	Mode             common.DispatchMode
+	Priority         common.Priority
+	DataOnly         bool
+	DispatchPoolID   *string
+	ServiceAccountID *string
+	MaxRetries       int32
+	TimeoutSeconds   int32
+	Sequence         int32
+	Bindings         []cachedBinding
+	// TapPercent and TapExpiresAt are set for a tap subscription, which
+	// gets only a sample of its matching events and nothing once expired.
+	TapPercent   *float64
+	TapExpiresAt *time.Time
 }
 
-func (s *cachedSubscription) matchesEventType(code string) bool {
-	for _, p := range s.EventTypePatterns {
-		if patternMatches(p, code) {
+// cachedBinding is one event-type pattern plus its optional payload
+// filter. A filter that failed to compile leaves Broken set so the
+// binding never matches — fail closed rather than deliver everything.
+type cachedBinding struct {
+	Pattern string
+	Filter  *eventfilter.Program
+	Broken  bool
+}
+
+// matches reports whether any binding accepts the event. The event's
+// payload is decoded lazily, and only once, the first time a filter
+// needs it.
+func (s *cachedSubscription) matches(e *claimedEvent, in **eventfilter.Input) bool {
+	for _, b := range s.Bindings {
+		if b.Broken || !patternMatches(b.Pattern, e.EventType) {
+			continue
+		}
+		if b.Filter == nil {
+			return true
+		}
+		if *in == nil {
+			decoded, err := eventfilter.NewInput(eventfilter.Event{
+				Type:         e.EventType,
+				Source:       e.Source,
+				Subject:      e.Subject,
+				ClientID:     e.ClientID,
+				MessageGroup: e.MessageGroup,
+				Payload:      e.Data,
+			})
+			if err != nil {
+				slog.Warn("fanout: event payload is not valid JSON; filters skipped",
+					"event_id", e.ID, "err", err)
+				continue
+			}
+			*in = decoded
+		}
+		ok, err := b.Filter.Match(*in)
+		if err != nil {
+			slog.Debug("fanout: filter evaluation failed",
+				"subscription_id", s.ID, "event_id", e.ID, "err", err)
+			continue
+		}
+		if ok {
 			return true
 		}
 	}
 	return false
 }
 
+// matchesEnvironment keeps sandbox events away from production
+// subscriptions and vice versa. A zero environment on either side (rows
+// built before environments existed) reads as production.
+func (s *cachedSubscription) matchesEnvironment(env common.Environment) bool {
+	return common.ParseEnvironment(string(s.Environment)) == common.ParseEnvironment(string(env))
+}
+
+// samples reports whether a tap subscription takes this event: it must
+// not have expired, and the event must fall in its sample. The sample is
+// a hash of event and subscription, not a coin toss, so an event that is
+// fanned out again after a rolled-back claim is sampled the same way.
+// Ordinary subscriptions take everything.
+func (s *cachedSubscription) samples(eventID string, now time.Time) bool {
+	if s.TapPercent == nil {
+		return true
+	}
+	if s.TapExpiresAt != nil && !now.Before(*s.TapExpiresAt) {
+		return false
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(eventID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(s.ID))
+	// Basis points: 0.01% is the finest sample a tap can ask for.
+	return float64(h.Sum64()%10000) < *s.TapPercent*100
+}
+
 func (s *cachedSubscription) matchesClient(eventClient *string) bool {
 	if s.ClientID == nil {
 		return true
@@ -255,12 +334,14 @@ func (f *FanOut) subscriptions(ctx context.Context) ([]cachedSubscription, error
 
 func loadActiveSubscriptions(ctx context.Context, pool *pgxpool.Pool) ([]cachedSubscription, error) {
 	rows, err := pool.Query(ctx,
-		`SELECT s.id, s.client_id, s.target, s.mode, s.data_only,
+		`SELECT s.id, s.client_id, s.environment, s.target, s.mode, s.priority, s.data_only,
 		        s.dispatch_pool_id, s.service_account_id, s.max_retries,
-		        s.timeout_seconds, s.sequence, e.event_type_code
+		        s.timeout_seconds, s.sequence, s.tap_sample_percent, s.tap_expires_at,
+		        e.event_type_code, e.filter
 		   FROM msg_subscriptions s
 		   LEFT JOIN msg_subscription_event_types e ON e.subscription_id = s.id
 		  WHERE s.status = 'ACTIVE'
+		    AND (s.tap_expires_at IS NULL OR s.tap_expires_at > NOW())
 		  ORDER BY s.id`)
 	if err != nil {
 		return nil, err
@@ -270,14 +351,17 @@ func loadActiveSubscriptions(ctx context.Context, pool *pgxpool.Pool) ([]cachedS
 	var order []string
 	for rows.Next() {
 		var (
-			id, target, mode                       string
+			id, env, target, mode, priority        string
 			clientID, dispatchPoolID, saID, etCode *string
+			filter                                 *string
 			dataOnly                               bool
 			maxRetries, timeoutSeconds, sequence   int32
+			tapPercent                             *float64
+			tapExpiresAt                           *time.Time
 		)
-		if err := rows.Scan(&id, &clientID, &target, &mode, &dataOnly,
+		if err := rows.Scan(&id, &clientID, &env, &target, &mode, &priority, &dataOnly,
 			&dispatchPoolID, &saID, &maxRetries, &timeoutSeconds,
-			&sequence, &etCode); err != nil {
+			&sequence, &tapPercent, &tapExpiresAt, &etCode, &filter); err != nil {
 			return nil, err
 		}
 		entry, ok := byID[id]
@@ -285,20 +369,24 @@ func loadActiveSubscriptions(ctx context.Context, pool *pgxpool.Pool) ([]cachedS
 			entry = &cachedSubscription{
 				ID:               id,
 				ClientID:         clientID,
+				Environment:      common.ParseEnvironment(env),
 				Target:           target,
 				Mode:             common.ParseDispatchMode(mode),
+				Priority:         common.ParsePriority(priority),
 				DataOnly:         dataOnly,
 				DispatchPoolID:   dispatchPoolID,
 				ServiceAccountID: saID,
 				MaxRetries:       maxRetries,
 				TimeoutSeconds:   timeoutSeconds,
 				Sequence:         sequence,
+				TapPercent:       tapPercent,
+				TapExpiresAt:     tapExpiresAt,
 			}
 			byID[id] = entry
 			order = append(order, id)
 		}
 		if etCode != nil {
-			entry.EventTypePatterns = append(entry.EventTypePatterns, *etCode)
+			entry.Bindings = append(entry.Bindings, compileBinding(id, *etCode, filter))
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -311,6 +399,24 @@ func loadActiveSubscriptions(ctx context.Context, pool *pgxpool.Pool) ([]cachedS
 	return out, nil
 }
 
+func compileBinding(subscriptionID, pattern string, filter *string) cachedBinding {
+	b := cachedBinding{Pattern: pattern}
+	if filter == nil || strings.TrimSpace(*filter) == "" {
+		return b
+	}
+	prog, err := eventfilter.Compile(*filter)
+	if err != nil {
+		// Validated on write, so this means the row was edited by hand or
+		// the grammar tightened since.
+		slog.Warn("fanout: subscription filter does not compile; binding disabled",
+			"subscription_id", subscriptionID, "event_type", pattern, "err", err)
+		b.Broken = true
+		return b
+	}
+	b.Filter = prog
+	return b
+}
+
 // ── Dispatch job assembly + insert ───────────────────────────────────────
 
 // newJob is the subset of msg_dispatch_jobs columns fanout sets. Other
@@ -328,8 +434,10 @@ type newJob struct {
 	DataOnly       bool
 	ServiceAcctID  *string
 	ClientID       *string
+	Environment    string
 	SubscriptionID string
 	Mode           string
+	Priority       string
 	DispatchPoolID *string
 	MessageGroup   *string
 	Sequence       int32
@@ -342,13 +450,15 @@ type newJob struct {
 
 func buildJobs(events []claimedEvent, subs []cachedSubscription) []newJob {
 	var jobs []newJob
+	now := time.Now()
 	for _, e := range events {
+		var in *eventfilter.Input
 		for i := range subs {
 			s := &subs[i]
-			if !s.matchesEventType(e.EventType) {
+			if !s.matchesClient(e.ClientID) || !s.matchesEnvironment(e.Environment) {
 				continue
 			}
-			if !s.matchesClient(e.ClientID) {
+			if !s.samples(e.ID, now) || !s.matches(&e, &in) {
 				continue
 			}
 			payload := "null"
@@ -370,8 +480,10 @@ func buildJobs(events []claimedEvent, subs []cachedSubscription) []newJob {
 				DataOnly:       s.DataOnly,
 				ServiceAcctID:  s.ServiceAccountID,
 				ClientID:       e.ClientID,
+				Environment:    string(common.ParseEnvironment(string(s.Environment))),
 				SubscriptionID: s.ID,
 				Mode:           dispatchModeStr(s.Mode),
+				Priority:       string(s.Priority),
 				DispatchPoolID: s.DispatchPoolID,
 				MessageGroup:   e.MessageGroup,
 				Sequence:       s.Sequence,
@@ -413,16 +525,16 @@ func insertJobsInTx(ctx context.Context, tx pgx.Tx, jobs []newJob) error {
 			    target_url, protocol, payload, data_only, service_account_id,
 			    client_id, subscription_id, mode, dispatch_pool_id, message_group,
 			    sequence, timeout_seconds, status, max_retries, idempotency_key,
-			    created_at, updated_at)
+			    created_at, updated_at, priority, environment)
 			 VALUES ($1, $2, $3, $4, $5, $6, $7, 'HTTP_WEBHOOK', $8, $9,
 			         $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
-			         $21, $21)
+			         $21, $21, $22, $23)
 			 ON CONFLICT (id, created_at) DO NOTHING`,
 			j.ID, j.Code, j.Source, j.Subject, j.EventID, j.CorrelationID,
 			j.TargetURL, j.Payload, j.DataOnly, j.ServiceAcctID,
 			j.ClientID, j.SubscriptionID, j.Mode, j.DispatchPoolID,
 			j.MessageGroup, j.Sequence, j.TimeoutSeconds, j.Status,
-			j.MaxRetries, j.IdempotencyKey, j.CreatedAt)
+			j.MaxRetries, j.IdempotencyKey, j.CreatedAt, j.Priority, j.Environment)
 	}
 	br := tx.SendBatch(ctx, batch)
 	defer br.Close()
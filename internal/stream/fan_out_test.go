@@ -0,0 +1,147 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flowcatalyst/flowcatalyst-go/internal/common"
+)
+
+func filtered(t *testing.T, pattern, filter string) cachedBinding {
+	t.Helper()
+	b := compileBinding("sub", pattern, &filter)
+	if b.Broken {
+		t.Fatalf("filter %q did not compile", filter)
+	}
+	return b
+}
+
+func TestBuildJobs_EvaluatesFilters(t *testing.T) {
+	events := []claimedEvent{
+		{ID: "e1", EventType: "orders:sales:order:created", Data: json.RawMessage(`{"country":"AU","amount":250}`)},
+		{ID: "e2", EventType: "orders:sales:order:created", Data: json.RawMessage(`{"country":"AU","amount":50}`)},
+		{ID: "e3", EventType: "orders:sales:order:created", Data: json.RawMessage(`{"country":"NZ","amount":500}`)},
+	}
+	subs := []cachedSubscription{
+		{ID: "all", Bindings: []cachedBinding{{Pattern: "orders:sales:order:*"}}},
+		{ID: "big-au", Bindings: []cachedBinding{
+			filtered(t, "orders:sales:order:created", `payload.country == "AU" && payload.amount > 100`),
+		}},
+		{ID: "either", Bindings: []cachedBinding{
+			filtered(t, "orders:sales:order:created", `payload.country == "NZ"`),
+			filtered(t, "orders:*:order:created", `payload.amount < 100`),
+		}},
+	}
+
+	got := map[string][]string{}
+	for _, j := range buildJobs(events, subs) {
+		got[j.SubscriptionID] = append(got[j.SubscriptionID], j.EventID)
+	}
+	want := map[string][]string{
+		"all":    {"e1", "e2", "e3"},
+		"big-au": {"e1"},
+		"either": {"e2", "e3"},
+	}
+	for sub, ids := range want {
+		if len(got[sub]) != len(ids) {
+			t.Errorf("%s: got %v, want %v", sub, got[sub], ids)
+			continue
+		}
+		for i := range ids {
+			if got[sub][i] != ids[i] {
+				t.Errorf("%s: got %v, want %v", sub, got[sub], ids)
+				break
+			}
+		}
+	}
+}
+
+func TestBuildJobs_FilterFailsClosed(t *testing.T) {
+	bad := "payload.country =="
+	events := []claimedEvent{
+		{ID: "e1", EventType: "a:b:c:d", Data: json.RawMessage(`{"country":"AU"}`)},
+	}
+	subs := []cachedSubscription{
+		{ID: "broken", Bindings: []cachedBinding{compileBinding("broken", "a:b:c:d", &bad)}},
+		{ID: "non-bool", Bindings: []cachedBinding{filtered(t, "a:b:c:d", `payload.country`)}},
+	}
+	if jobs := buildJobs(events, subs); len(jobs) != 0 {
+		t.Fatalf("got %d jobs, want none", len(jobs))
+	}
+}
+
+func TestBuildJobs_CopiesSubscriptionPriority(t *testing.T) {
+	events := []claimedEvent{{ID: "e1", EventType: "a:b:c:d"}}
+	subs := []cachedSubscription{
+		{ID: "urgent", Priority: "HIGH", Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+		{ID: "bulk", Priority: "LOW", Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+	}
+	got := map[string]string{}
+	for _, j := range buildJobs(events, subs) {
+		got[j.SubscriptionID] = j.Priority
+	}
+	if got["urgent"] != "HIGH" || got["bulk"] != "LOW" {
+		t.Errorf("priorities = %v, want urgent=HIGH bulk=LOW", got)
+	}
+}
+
+func TestBuildJobs_StampsSubscriptionEnvironment(t *testing.T) {
+	events := []claimedEvent{
+		{ID: "e1", EventType: "a:b:c:d", Environment: common.EnvironmentSandbox},
+		{ID: "e2", EventType: "a:b:c:d"},
+	}
+	subs := []cachedSubscription{
+		{ID: "sandbox", Environment: common.EnvironmentSandbox, Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+		{ID: "legacy", Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+	}
+	got := map[string]string{}
+	for _, j := range buildJobs(events, subs) {
+		got[j.SubscriptionID+"/"+j.EventID] = j.Environment
+	}
+	want := map[string]string{"sandbox/e1": "SANDBOX", "legacy/e2": "PRODUCTION"}
+	if len(got) != len(want) || got["sandbox/e1"] != want["sandbox/e1"] || got["legacy/e2"] != want["legacy/e2"] {
+		t.Errorf("job environments = %v, want %v", got, want)
+	}
+}
+
+func TestBuildJobs_SamplesTaps(t *testing.T) {
+	var events []claimedEvent
+	for i := range 2000 {
+		events = append(events, claimedEvent{ID: fmt.Sprintf("e%d", i), EventType: "a:b:c:d"})
+	}
+	pct := func(v float64) *float64 { return &v }
+	later := time.Now().Add(time.Hour)
+	earlier := time.Now().Add(-time.Minute)
+	subs := []cachedSubscription{
+		{ID: "full", Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+		{ID: "tap", TapPercent: pct(10), TapExpiresAt: &later, Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+		{ID: "expired", TapPercent: pct(100), TapExpiresAt: &earlier, Bindings: []cachedBinding{{Pattern: "a:b:c:d"}}},
+	}
+
+	sampled := map[string]bool{}
+	got := map[string]int{}
+	for _, j := range buildJobs(events, subs) {
+		got[j.SubscriptionID]++
+		if j.SubscriptionID == "tap" {
+			sampled[j.EventID] = true
+		}
+	}
+	if got["full"] != len(events) {
+		t.Errorf("full subscription got %d jobs, want %d", got["full"], len(events))
+	}
+	if got["tap"] < 120 || got["tap"] > 280 {
+		t.Errorf("10%% tap got %d of %d events", got["tap"], len(events))
+	}
+	if got["expired"] != 0 {
+		t.Errorf("expired tap got %d jobs, want none", got["expired"])
+	}
+
+	// Fanning the same events out again must pick the same sample.
+	for _, j := range buildJobs(events, subs) {
+		if j.SubscriptionID == "tap" && !sampled[j.EventID] {
+			t.Fatalf("event %s sampled on the second pass only", j.EventID)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // StreamStatus is the coarse running-state enum.
@@ -25,6 +27,11 @@ type Health struct {
 	errorCount     atomic.Uint64
 	// lastPollMs is set on every successful AddProcessed call.
 	lastPollMs atomic.Int64
+	// backlog and lagMs are the last Lag probe; lagMeasured is false until
+	// one succeeds.
+	backlog     atomic.Int64
+	lagMs       atomic.Int64
+	lagMeasured atomic.Bool
 }
 
 // NewHealth builds a stopped health tracker with the supplied name.
@@ -52,6 +59,13 @@ func (h *Health) AddProcessed(n uint64) {
 // RecordError bumps the error counter. Called when Step returns an error.
 func (h *Health) RecordError() { h.errorCount.Add(1) }
 
+// SetLag records a Lag probe. Called from Projector.Run.
+func (h *Health) SetLag(l Lag) {
+	h.backlog.Store(l.Backlog)
+	h.lagMs.Store(l.Oldest.Milliseconds())
+	h.lagMeasured.Store(true)
+}
+
 // IsHealthy is currently equivalent to IsRunning — a projection that's
 // up is healthy. Matches Rust's `is_healthy = is_running`.
 func (h *Health) IsHealthy() bool { return h.IsRunning() }
@@ -65,6 +79,11 @@ type Snapshot struct {
 	BatchSequence  uint64       `json:"batchSequence"`
 	ErrorCount     uint64       `json:"errorCount"`
 	LastPollTimeMs int64        `json:"lastPollTimeMs"`
+	// Backlog and LagSeconds are the last lag probe: source rows still to
+	// project (capped at LagBacklogCap) and the oldest one's age. nil
+	// until measured, and always for the fan-out.
+	Backlog    *int64   `json:"backlog,omitempty"`
+	LagSeconds *float64 `json:"lagSeconds,omitempty"`
 }
 
 // Status returns the snapshot for this projection.
@@ -74,7 +93,7 @@ func (h *Health) Status() Snapshot {
 	if running {
 		status = StatusRunning
 	}
-	return Snapshot{
+	snap := Snapshot{
 		Name:           h.name,
 		Status:         status,
 		Running:        running,
@@ -83,6 +102,12 @@ func (h *Health) Status() Snapshot {
 		ErrorCount:     h.errorCount.Load(),
 		LastPollTimeMs: h.lastPollMs.Load(),
 	}
+	if h.lagMeasured.Load() {
+		backlog := h.backlog.Load()
+		lag := float64(h.lagMs.Load()) / 1000
+		snap.Backlog, snap.LagSeconds = &backlog, &lag
+	}
+	return snap
 }
 
 // HealthService aggregates per-projection Health trackers. Used by the
@@ -178,3 +203,37 @@ func (s *HealthService) Count() int {
 	defer s.mu.RUnlock()
 	return len(s.healths)
 }
+
+var (
+	projectionRunningDesc = prometheus.NewDesc("fc_stream_projection_running",
+		"1 while the projection's loop is running, else 0.", []string{"projection"}, nil)
+	projectionRowsDesc = prometheus.NewDesc("fc_stream_projection_rows_total",
+		"Rows the projection has processed since start.", []string{"projection"}, nil)
+	projectionErrorsDesc = prometheus.NewDesc("fc_stream_projection_errors_total",
+		"Failed projection steps since start.", []string{"projection"}, nil)
+	projectionBacklogDesc = prometheus.NewDesc("fc_stream_projection_backlog",
+		"Source rows still to project at the last lag probe (capped at 10000).", []string{"projection"}, nil)
+	projectionLagDesc = prometheus.NewDesc("fc_stream_projection_lag_seconds",
+		"Age of the oldest source row still to project at the last lag probe.", []string{"projection"}, nil)
+)
+
+// Describe is a no-op (unchecked const-metric collector).
+func (s *HealthService) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect emits one set of series per registered projection; the lag
+// series only once a projection has been probed.
+func (s *HealthService) Collect(ch chan<- prometheus.Metric) {
+	for _, snap := range s.Aggregate().Streams {
+		running := 0.0
+		if snap.Running {
+			running = 1
+		}
+		ch <- prometheus.MustNewConstMetric(projectionRunningDesc, prometheus.GaugeValue, running, snap.Name)
+		ch <- prometheus.MustNewConstMetric(projectionRowsDesc, prometheus.CounterValue, float64(snap.BatchSequence), snap.Name)
+		ch <- prometheus.MustNewConstMetric(projectionErrorsDesc, prometheus.CounterValue, float64(snap.ErrorCount), snap.Name)
+		if snap.Backlog != nil {
+			ch <- prometheus.MustNewConstMetric(projectionBacklogDesc, prometheus.GaugeValue, float64(*snap.Backlog), snap.Name)
+			ch <- prometheus.MustNewConstMetric(projectionLagDesc, prometheus.GaugeValue, *snap.LagSeconds, snap.Name)
+		}
+	}
+}
@@ -27,6 +27,8 @@ type ProjectorConfig struct {
 	IdleSleep time.Duration
 	// ErrorSleep is the back-off after a Step error.
 	ErrorSleep time.Duration
+	// LagInterval is how often the projector's Lag probe runs; 0 never.
+	LagInterval time.Duration
 }
 
 // DefaultProjectorConfig holds the per-projection defaults. BatchSize 100
@@ -41,6 +43,7 @@ func DefaultProjectorConfig() ProjectorConfig {
 		PollInterval: 100 * time.Millisecond,
 		IdleSleep:    1 * time.Second,
 		ErrorSleep:   5 * time.Second,
+		LagInterval:  15 * time.Second,
 	}
 }
 
@@ -67,6 +70,13 @@ type Projector struct {
 	// Mirrors Rust's whole-stream-processor leadership gate (active_rx). nil
 	// = always run (single-node / standby disabled).
 	IsLeader func() bool
+	// Lag, when set alongside Health, is probed every Cfg.LagInterval and
+	// recorded with Health.SetLag. Registered projections set it; the
+	// fan-out doesn't.
+	Lag func(ctx context.Context) (Lag, error)
+	// Changed, when set, is called after every non-empty Step — the
+	// processor points it at Changes so read-side caches can invalidate.
+	Changed func()
 }
 
 // Run drives the projector until ctx is cancelled.
@@ -80,6 +90,7 @@ func (p *Projector) Run(ctx context.Context) {
 		p.Health.SetRunning(true)
 		defer p.Health.SetRunning(false)
 	}
+	var lastLag time.Time
 	for {
 		select {
 		case <-ctx.Done():
@@ -88,6 +99,15 @@ func (p *Projector) Run(ctx context.Context) {
 		default:
 		}
 
+		if p.Lag != nil && p.Health != nil && p.Cfg.LagInterval > 0 && time.Since(lastLag) >= p.Cfg.LagInterval {
+			lastLag = time.Now()
+			if lag, err := p.Lag(ctx); err != nil {
+				slog.Warn("projector lag probe failed", "name", p.Name, "err", err)
+			} else {
+				p.Health.SetLag(lag)
+			}
+		}
+
 		if p.IsLeader != nil && !p.IsLeader() {
 			sleep(ctx, p.Cfg.IdleSleep) // only the leader claims
 			continue
@@ -99,8 +119,13 @@ func (p *Projector) Run(ctx context.Context) {
 			if p.Health != nil {
 				p.Health.RecordError()
 			}
-		} else if n > 0 && p.Health != nil {
-			p.Health.AddProcessed(uint64(n))
+		} else if n > 0 {
+			if p.Health != nil {
+				p.Health.AddProcessed(uint64(n))
+			}
+			if p.Changed != nil {
+				p.Changed()
+			}
 		}
 		sleep(ctx, nextSleep(p.Cfg, n, err))
 	}
@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Projections keep their progress on the source rows: `projected_at` is
+// stamped in the same transaction that writes the read model, so a
+// restarted processor resumes exactly where it stopped — there is no
+// separate cursor (change-stream resume token) to persist. Rebuild is
+// the backfill path for when the read model itself is wrong.
+
+// ErrUnknownProjection is returned for a projection name that doesn't
+// exist.
+var ErrUnknownProjection = errors.New("unknown projection")
+
+// ErrNotRebuildable is returned for projections whose replay has side
+// effects. The fan-out creates dispatch jobs; replaying it would deliver
+// every event again.
+var ErrNotRebuildable = errors.New("projection cannot be rebuilt")
+
+// RebuildableProjections lists the projector names Rebuild accepts: every
+// registered projection.
+func RebuildableProjections() []string {
+	defs := Projections.Definitions()
+	names := make([]string, 0, len(defs))
+	for _, d := range defs {
+		names = append(names, d.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func lookupProjection(name string) (Definition, error) {
+	if d, ok := Projections.Lookup(name); ok {
+		return d, nil
+	}
+	if name == "event_fan_out" {
+		return Definition{}, fmt.Errorf("%w: %s", ErrNotRebuildable, name)
+	}
+	return Definition{}, fmt.Errorf("%w: %q", ErrUnknownProjection, name)
+}
+
+// RebuildResult reports what Rebuild queued.
+type RebuildResult struct {
+	Projection string
+	// Requeued is the number of source rows marked unprojected.
+	Requeued int64
+}
+
+// Rebuild empties a projection's read table and clears `projected_at` on
+// every source row, in one transaction, so the running projector replays
+// the whole source table into a fresh read model. The read table is
+// empty (not stale) until the projector catches up; progress is visible
+// as the projector's batch count on /monitoring/stream-health.
+func Rebuild(ctx context.Context, pool *pgxpool.Pool, projection string) (RebuildResult, error) {
+	t, err := lookupProjection(projection)
+	if err != nil {
+		return RebuildResult{}, err
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return RebuildResult{}, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `TRUNCATE `+t.Target); err != nil {
+		return RebuildResult{}, fmt.Errorf("truncate %s: %w", t.Target, err)
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE `+t.Source+` SET projected_at = NULL WHERE projected_at IS NOT NULL`)
+	if err != nil {
+		return RebuildResult{}, fmt.Errorf("reset %s: %w", t.Source, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return RebuildResult{}, fmt.Errorf("commit: %w", err)
+	}
+	return RebuildResult{Projection: projection, Requeued: tag.RowsAffected()}, nil
+}
+
+// Pending counts the source rows a projection has yet to (re)project.
+func Pending(ctx context.Context, pool *pgxpool.Pool, projection string) (int64, error) {
+	t, err := lookupProjection(projection)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if err := pool.QueryRow(ctx,
+		`SELECT count(*) FROM `+t.Source+` WHERE `+t.pending()).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count pending %s: %w", t.Source, err)
+	}
+	return n, nil
+}
+
+// Drain runs the projection's step in-process until nothing is pending,
+// calling progress after every batch with the running total. It claims
+// with SKIP LOCKED like the projector, so it is safe alongside a running
+// stream processor — the two simply share the backlog.
+func Drain(ctx context.Context, pool *pgxpool.Pool, projection string, batchSize int, progress func(projected int64)) (int64, error) {
+	t, err := lookupProjection(projection)
+	if err != nil {
+		return 0, err
+	}
+	step := t.step(pool)
+	var total int64
+	for {
+		n, err := step(ctx, batchSize)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+		total += int64(n)
+		if progress != nil {
+			progress(total)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Name checks run before the pool is touched, so a nil pool is fine here.
+func TestRebuild_RejectsUnknownAndFanOut(t *testing.T) {
+	if _, err := Rebuild(context.Background(), nil, "event_fan_out"); !errors.Is(err, ErrNotRebuildable) {
+		t.Errorf("fan-out: err = %v, want ErrNotRebuildable", err)
+	}
+	if _, err := Rebuild(context.Background(), nil, "nope"); !errors.Is(err, ErrUnknownProjection) {
+		t.Errorf("unknown: err = %v, want ErrUnknownProjection", err)
+	}
+}
+
+func TestRebuildableProjections_MatchProjectorNames(t *testing.T) {
+	names := map[string]bool{
+		NewEventProjection(nil).Projector(DefaultProjectorConfig()).Name:       true,
+		NewDispatchJobProjection(nil).Projector(DefaultProjectorConfig()).Name: true,
+	}
+	got := RebuildableProjections()
+	if len(got) != len(names) {
+		t.Fatalf("RebuildableProjections() = %v, want %d names", got, len(names))
+	}
+	for _, n := range got {
+		if !names[n] {
+			t.Errorf("%q is not a projector name", n)
+		}
+	}
+}
+
+func TestBucketReport_State(t *testing.T) {
+	cases := []struct {
+		name string
+		b    BucketReport
+		want BucketState
+	}{
+		{"match", BucketReport{SourceRows: 3, ReadRows: 3, SourceChecksum: "a", ReadChecksum: "a"}, BucketOK},
+		{"missing rows, pending", BucketReport{SourceRows: 3, ReadRows: 1, Pending: 2, SourceChecksum: "a", ReadChecksum: "b"}, BucketLagging},
+		{"missing rows, none pending", BucketReport{SourceRows: 3, ReadRows: 1, SourceChecksum: "a", ReadChecksum: "b"}, BucketDrift},
+		{"same count, content differs", BucketReport{SourceRows: 3, ReadRows: 3, SourceChecksum: "a", ReadChecksum: "b"}, BucketDrift},
+		{"read-only bucket", BucketReport{ReadRows: 2, ReadChecksum: "b"}, BucketDrift},
+	}
+	for _, tc := range cases {
+		if got := tc.b.State(); got != tc.want {
+			t.Errorf("%s: State() = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestVerify_RejectsBadOptions(t *testing.T) {
+	now := time.Now()
+	if _, err := Verify(context.Background(), nil, "event_projection", VerifyOptions{From: now.Add(-time.Hour), To: now, Bucket: "minute"}); err == nil {
+		t.Error("bucket=minute accepted")
+	}
+	if _, err := Verify(context.Background(), nil, "event_projection", VerifyOptions{From: now, To: now, Bucket: "day"}); err == nil {
+		t.Error("empty range accepted")
+	}
+	if _, err := Verify(context.Background(), nil, "event_fan_out", VerifyOptions{From: now.Add(-time.Hour), To: now, Bucket: "day"}); !errors.Is(err, ErrNotRebuildable) {
+		t.Errorf("fan-out: err = %v", err)
+	}
+}
@@ -0,0 +1,363 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// A projection copies rows from a write table (Source) into a read table
+// (Target). Progress lives on the source rows: a row matching Pending is
+// still to project, and projecting it stamps `projected_at` in the same
+// transaction that writes the read row. Anything shaped that way can be
+// registered — from code with Register, or from FC_STREAM_PROJECTIONS via
+// ParseProjections — and the stream processor, Rebuild, Verify and Drain
+// pick it up by name. The fan-out isn't one: it creates dispatch jobs
+// rather than a read model, and stays wired by hand.
+
+// StepFunc claims and projects one batch, returning the rows projected.
+type StepFunc func(ctx context.Context, batchSize int) (int, error)
+
+// Index is an index the projection needs on its Target table.
+type Index struct {
+	// Name defaults to idx_<target>_<columns>.
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// Definition is one registered projection.
+type Definition struct {
+	Name   string
+	Source string
+	Target string
+	// Pending is the SQL predicate on Source matching rows still to
+	// project. "" means `projected_at IS NULL`.
+	Pending string
+	// Digest is the per-row expression Verify checksums on both sides —
+	// the columns copied verbatim. "" leaves the projection unverifiable.
+	Digest string
+	// Indexes are created on Target (IF NOT EXISTS) before the projection
+	// first runs.
+	Indexes []Index
+	// Transform writes the read rows for the claimed source ids inside the
+	// claim transaction; the registry claims the rows (oldest created_at
+	// first, SKIP LOCKED) and stamps projected_at around it.
+	Transform func(ctx context.Context, tx pgx.Tx, ids []string) error
+	// Step, when set, replaces the claim/Transform/stamp step entirely.
+	// The built-in projections keep their Rust-parity SQL this way.
+	Step func(pool *pgxpool.Pool) StepFunc
+	// BatchSize overrides the processor's batch size; 0 keeps it.
+	BatchSize int
+	// Disabled keeps the projection registered (rebuildable, verifiable)
+	// but not run by the stream processor.
+	Disabled bool
+}
+
+func (d Definition) pending() string {
+	if d.Pending == "" {
+		return "projected_at IS NULL"
+	}
+	return d.Pending
+}
+
+// identRe matches a plain or schema-qualified SQL identifier; registered
+// names are spliced into SQL, so nothing else is accepted.
+var identRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+func (d Definition) validate() error {
+	if !identRe.MatchString(d.Name) || strings.Contains(d.Name, ".") {
+		return fmt.Errorf("projection name %q: want lowercase letters, digits and '_'", d.Name)
+	}
+	if !identRe.MatchString(d.Source) || !identRe.MatchString(d.Target) {
+		return fmt.Errorf("projection %s: source and target must be table names", d.Name)
+	}
+	if d.Source == d.Target {
+		return fmt.Errorf("projection %s: source and target are the same table", d.Name)
+	}
+	if d.Step == nil && d.Transform == nil {
+		return fmt.Errorf("projection %s: needs a transform", d.Name)
+	}
+	if d.BatchSize < 0 {
+		return fmt.Errorf("projection %s: batch size must not be negative", d.Name)
+	}
+	for _, ix := range d.Indexes {
+		if len(ix.Columns) == 0 {
+			return fmt.Errorf("projection %s: index without columns", d.Name)
+		}
+		if ix.Name != "" && !identRe.MatchString(ix.Name) {
+			return fmt.Errorf("projection %s: index name %q", d.Name, ix.Name)
+		}
+		for _, c := range ix.Columns {
+			if !identRe.MatchString(c) || strings.Contains(c, ".") {
+				return fmt.Errorf("projection %s: index column %q", d.Name, c)
+			}
+		}
+	}
+	return nil
+}
+
+// step returns the projection's claim+process step over pool.
+func (d Definition) step(pool *pgxpool.Pool) StepFunc {
+	if d.Step != nil {
+		return d.Step(pool)
+	}
+	return func(ctx context.Context, batchSize int) (int, error) {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("begin: %w", err)
+		}
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		rows, err := tx.Query(ctx,
+			`SELECT id FROM `+d.Source+` WHERE `+d.pending()+`
+			 ORDER BY created_at LIMIT $1 FOR UPDATE SKIP LOCKED`, batchSize)
+		if err != nil {
+			return 0, fmt.Errorf("claim: %w", err)
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return 0, fmt.Errorf("claim: %w", err)
+		}
+		if len(ids) == 0 {
+			return 0, nil
+		}
+		if err := d.Transform(ctx, tx, ids); err != nil {
+			return 0, fmt.Errorf("transform: %w", err)
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE `+d.Source+` SET projected_at = NOW() WHERE id = ANY($1)`, ids); err != nil {
+			return 0, fmt.Errorf("update projected_at: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("commit: %w", err)
+		}
+		return len(ids), nil
+	}
+}
+
+// EnsureIndexes creates the projection's Target indexes that don't exist.
+func (d Definition) EnsureIndexes(ctx context.Context, pool *pgxpool.Pool) error {
+	for _, ix := range d.Indexes {
+		name := ix.Name
+		if name == "" {
+			name = "idx_" + strings.ReplaceAll(d.Target, ".", "_") + "_" + strings.Join(ix.Columns, "_")
+		}
+		unique := ""
+		if ix.Unique {
+			unique = "UNIQUE "
+		}
+		if _, err := pool.Exec(ctx, `CREATE `+unique+`INDEX IF NOT EXISTS `+name+
+			` ON `+d.Target+` (`+strings.Join(ix.Columns, ", ")+`)`); err != nil {
+			return fmt.Errorf("projection %s: index %s: %w", d.Name, name, err)
+		}
+	}
+	return nil
+}
+
+// LagBacklogCap bounds the backlog count a lag probe reads, so a
+// projection far behind doesn't turn every probe into a table scan.
+const LagBacklogCap = 10000
+
+// Lag is how far a projection is behind its source.
+type Lag struct {
+	// Backlog is the rows still to project, at most LagBacklogCap.
+	Backlog int64
+	// Oldest is the age of the oldest row still to project; 0 when
+	// caught up.
+	Oldest time.Duration
+}
+
+// Lag probes the projection's backlog with the claim's own predicate.
+func (d Definition) Lag(ctx context.Context, pool *pgxpool.Pool) (Lag, error) {
+	var (
+		lag    Lag
+		oldest *time.Time
+	)
+	err := pool.QueryRow(ctx,
+		`SELECT (SELECT count(*) FROM (SELECT 1 FROM `+d.Source+` WHERE `+d.pending()+` LIMIT $1) b),
+		        (SELECT created_at FROM `+d.Source+` WHERE `+d.pending()+` ORDER BY created_at LIMIT 1)`,
+		LagBacklogCap).Scan(&lag.Backlog, &oldest)
+	if err != nil {
+		return Lag{}, fmt.Errorf("projection %s: lag: %w", d.Name, err)
+	}
+	if oldest != nil {
+		lag.Oldest = max(time.Since(*oldest), 0)
+	}
+	return lag, nil
+}
+
+// Projector returns a Projector running the definition over pool, with the
+// definition's batch size (if any) applied to cfg and its lag probed.
+func (d Definition) Projector(pool *pgxpool.Pool, cfg ProjectorConfig) *Projector {
+	if d.BatchSize > 0 {
+		cfg.BatchSize = d.BatchSize
+	}
+	cfg.Enabled = cfg.Enabled && !d.Disabled
+	return &Projector{
+		Name: d.Name,
+		Pool: pool,
+		Cfg:  cfg,
+		Step: d.step(pool),
+		Lag:  func(ctx context.Context) (Lag, error) { return d.Lag(ctx, pool) },
+	}
+}
+
+// Registry holds projection definitions in registration order.
+type Registry struct {
+	mu   sync.RWMutex
+	defs []Definition
+}
+
+// ErrDuplicateProjection is returned when a name is registered twice.
+var ErrDuplicateProjection = errors.New("projection already registered")
+
+// NewRegistry builds an empty registry.
+func NewRegistry() *Registry { return &Registry{} }
+
+// Register adds a definition.
+func (r *Registry) Register(d Definition) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, have := range r.defs {
+		if have.Name == d.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicateProjection, d.Name)
+		}
+	}
+	r.defs = append(r.defs, d)
+	return nil
+}
+
+// Lookup returns the named definition.
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, d := range r.defs {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Definitions returns every definition in registration order.
+func (r *Registry) Definitions() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Definition(nil), r.defs...)
+}
+
+// Projections is the process-wide registry: the built-ins plus whatever
+// Register adds.
+var Projections = NewRegistry()
+
+// Register adds a definition to Projections.
+func Register(d Definition) error { return Projections.Register(d) }
+
+func init() {
+	for _, d := range []Definition{
+		{
+			Name:   "event_projection",
+			Source: "msg_events",
+			Target: "msg_events_read",
+			Digest: "concat_ws('|', id, type, source, subject, client_id, message_group)",
+			Step:   func(pool *pgxpool.Pool) StepFunc { return NewEventProjection(pool).step },
+		},
+		{
+			Name:    "dispatch_job_projection",
+			Source:  "msg_dispatch_jobs",
+			Target:  "msg_dispatch_jobs_read",
+			Pending: "projected_at IS NULL OR updated_at > projected_at",
+			Digest:  "concat_ws('|', id, code, status, attempt_count, completed_at, updated_at)",
+			Step:    func(pool *pgxpool.Pool) StepFunc { return NewDispatchJobProjection(pool).step },
+		},
+	} {
+		if err := Register(d); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// projectionConfig is one FC_STREAM_PROJECTIONS entry.
+type projectionConfig struct {
+	Name    string  `json:"name"`
+	Source  string  `json:"source"`
+	Target  string  `json:"target"`
+	Pending string  `json:"pending"`
+	Digest  string  `json:"digest"`
+	SQL     string  `json:"transform"`
+	Indexes []Index `json:"indexes"`
+	Batch   int     `json:"batchSize"`
+	Enabled *bool   `json:"enabled"`
+}
+
+// ParseProjections reads FC_STREAM_PROJECTIONS: a JSON array of
+// projections whose transform is one SQL statement writing the read rows
+// for the claimed source ids, bound as $1 (text[]), e.g.
+//
+//	[{"name":"order_projection","source":"app_orders","target":"app_orders_read",
+//	  "transform":"INSERT INTO app_orders_read (id, total, created_at) SELECT id, total, created_at FROM app_orders WHERE id = ANY($1) ON CONFLICT (id) DO UPDATE SET total = EXCLUDED.total",
+//	  "indexes":[{"columns":["created_at"]}]}]
+//
+// The source table needs id, created_at and projected_at columns. Empty
+// means none.
+func ParseProjections(spec string) ([]Definition, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var cfgs []projectionConfig
+	if err := json.Unmarshal([]byte(spec), &cfgs); err != nil {
+		return nil, fmt.Errorf("want a JSON array of projections: %w", err)
+	}
+	out := make([]Definition, 0, len(cfgs))
+	for _, c := range cfgs {
+		if strings.TrimSpace(c.SQL) == "" {
+			return nil, fmt.Errorf("projection %q: transform is required", c.Name)
+		}
+		sql := c.SQL
+		d := Definition{
+			Name:    c.Name,
+			Source:  c.Source,
+			Target:  c.Target,
+			Pending: c.Pending,
+			Digest:  c.Digest,
+			Indexes: c.Indexes,
+			Transform: func(ctx context.Context, tx pgx.Tx, ids []string) error {
+				_, err := tx.Exec(ctx, sql, ids)
+				return err
+			},
+			BatchSize: c.Batch,
+			Disabled:  c.Enabled != nil && !*c.Enabled,
+		}
+		if err := d.validate(); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// RegisterConfigured registers the FC_STREAM_PROJECTIONS projections.
+func RegisterConfigured(spec string) error {
+	defs, err := ParseProjections(spec)
+	if err != nil {
+		return err
+	}
+	for _, d := range defs {
+		if err := Register(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
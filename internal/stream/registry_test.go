@@ -0,0 +1,142 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseProjections(t *testing.T) {
+	got, err := ParseProjections(`[{"name":"order_projection","source":"app_orders","target":"app_orders_read",
+		"transform":"INSERT INTO app_orders_read SELECT id FROM app_orders WHERE id = ANY($1)",
+		"indexes":[{"columns":["created_at"]}],"batchSize":200,"enabled":false}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ParseProjections = %+v, want one", got)
+	}
+	d := got[0]
+	if d.Name != "order_projection" || d.Source != "app_orders" || d.Target != "app_orders_read" ||
+		d.BatchSize != 200 || !d.Disabled || d.Transform == nil || d.pending() != "projected_at IS NULL" {
+		t.Errorf("definition = %+v", d)
+	}
+	if got, err := ParseProjections(" "); err != nil || got != nil {
+		t.Fatalf("empty spec = %v, %v; want none", got, err)
+	}
+
+	for name, spec := range map[string]string{
+		"not json":     `order_projection=app_orders`,
+		"no transform": `[{"name":"p","source":"a","target":"b"}]`,
+		"bad name":     `[{"name":"Order Projection","source":"a","target":"b","transform":"SELECT 1"}]`,
+		"bad table":    `[{"name":"p","source":"a; DROP TABLE b","target":"b","transform":"SELECT 1"}]`,
+		"same table":   `[{"name":"p","source":"a","target":"a","transform":"SELECT 1"}]`,
+		"bad column":   `[{"name":"p","source":"a","target":"b","transform":"SELECT 1","indexes":[{"columns":["id desc"]}]}]`,
+		"no columns":   `[{"name":"p","source":"a","target":"b","transform":"SELECT 1","indexes":[{}]}]`,
+		"negative":     `[{"name":"p","source":"a","target":"b","transform":"SELECT 1","batchSize":-1}]`,
+	} {
+		if _, err := ParseProjections(spec); err == nil {
+			t.Errorf("%s: ParseProjections(%s) accepted", name, spec)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	noop := func(context.Context, pgx.Tx, []string) error { return nil }
+	for _, name := range []string{"b_projection", "a_projection"} {
+		if err := r.Register(Definition{Name: name, Source: "src", Target: "dst", Transform: noop}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Register(Definition{Name: "a_projection", Source: "src", Target: "dst", Transform: noop}); !errors.Is(err, ErrDuplicateProjection) {
+		t.Fatalf("duplicate Register = %v, want ErrDuplicateProjection", err)
+	}
+	if err := r.Register(Definition{Name: "c_projection", Source: "src", Target: "dst"}); err == nil {
+		t.Fatal("Register accepted a definition without a transform")
+	}
+	defs := r.Definitions()
+	if len(defs) != 2 || defs[0].Name != "b_projection" || defs[1].Name != "a_projection" {
+		t.Fatalf("Definitions = %+v, want registration order", defs)
+	}
+	if _, ok := r.Lookup("a_projection"); !ok {
+		t.Fatal("Lookup missed a registered projection")
+	}
+	if _, ok := r.Lookup("nope"); ok {
+		t.Fatal("Lookup found an unregistered projection")
+	}
+}
+
+// The built-ins are registered with their Rust-parity steps, so the
+// processor, Rebuild and Verify all see them.
+func TestBuiltinProjectionsRegistered(t *testing.T) {
+	for _, name := range []string{"event_projection", "dispatch_job_projection"} {
+		d, ok := Projections.Lookup(name)
+		if !ok {
+			t.Fatalf("%s not registered", name)
+		}
+		if d.Step == nil || d.Digest == "" {
+			t.Errorf("%s = %+v, want a step and a digest", name, d)
+		}
+	}
+}
+
+// A projector with a Lag probe records it on its Health, which surfaces
+// on the snapshot and as metrics.
+func TestProjectorRecordsLag(t *testing.T) {
+	h := NewHealth("order_projection")
+	cfg := DefaultProjectorConfig()
+	cfg.IdleSleep = time.Millisecond
+	cfg.LagInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Projector{
+		Name:   "order_projection",
+		Cfg:    cfg,
+		Step:   func(context.Context, int) (int, error) { return 0, nil },
+		Health: h,
+		Lag: func(context.Context) (Lag, error) {
+			cancel()
+			return Lag{Backlog: 42, Oldest: 90 * time.Second}, nil
+		},
+	}
+	p.Run(ctx)
+
+	snap := h.Status()
+	if snap.Backlog == nil || *snap.Backlog != 42 || snap.LagSeconds == nil || *snap.LagSeconds != 90 {
+		t.Fatalf("snapshot = %+v, want backlog 42, lag 90s", snap)
+	}
+
+	svc := NewHealthService()
+	svc.Register(h)
+	svc.Register(NewHealth("event_fan_out"))
+	want := `
+# HELP fc_stream_projection_backlog Source rows still to project at the last lag probe (capped at 10000).
+# TYPE fc_stream_projection_backlog gauge
+fc_stream_projection_backlog{projection="order_projection"} 42
+# HELP fc_stream_projection_lag_seconds Age of the oldest source row still to project at the last lag probe.
+# TYPE fc_stream_projection_lag_seconds gauge
+fc_stream_projection_lag_seconds{projection="order_projection"} 90
+`
+	if err := testutil.CollectAndCompare(svc, strings.NewReader(want),
+		"fc_stream_projection_backlog", "fc_stream_projection_lag_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify_RejectsProjectionWithoutDigest(t *testing.T) {
+	defer func(old *Registry) { Projections = old }(Projections)
+	Projections = NewRegistry()
+	noop := func(context.Context, pgx.Tx, []string) error { return nil }
+	if err := Register(Definition{Name: "undigested_projection", Source: "src", Target: "dst", Transform: noop}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if _, err := Verify(context.Background(), nil, "undigested_projection", VerifyOptions{From: now.Add(-time.Hour), To: now, Bucket: "day"}); err == nil {
+		t.Fatal("Verify accepted a projection without a digest")
+	}
+}
@@ -0,0 +1,34 @@
+// Package swaggerui serves a minimal Swagger UI page for an OpenAPI spec.
+// Every binary's HTTP surface — the platform API, the router's monitoring
+// API, the outbox admin API — mounts it at /swagger next to its spec, so
+// operators can browse an API and tooling can find the spec it points at.
+package swaggerui
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var page = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8"/>
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist/swagger-ui.css"/>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist/swagger-ui-bundle.js" crossorigin></script>
+<script>window.onload=function(){window.ui=SwaggerUIBundle({url:{{.SpecURL}},dom_id:'#swagger-ui'});};</script>
+</body>
+</html>`))
+
+// Handler serves the Swagger UI page titled title, loading the spec from
+// specURL (absolute path or URL — the page is served from wherever it is
+// mounted, so a relative one would resolve against that).
+func Handler(title, specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = page.Execute(w, struct{ Title, SpecURL string }{title, specURL})
+	})
+}
@@ -0,0 +1,20 @@
+package swaggerui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerPointsAtSpec(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler("Router API", "/router/openapi.json").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/router/swagger", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "<title>Router API</title>")
+	assert.Contains(t, body, `url:"/router/openapi.json"`, "the spec URL is a JS string literal")
+}
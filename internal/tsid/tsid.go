@@ -70,6 +70,26 @@ const (
 	MfaEmailPin
 	MfaTrustedDevice
 	ResetApprovalRequest
+	// SubscriptionConfigSchema backs the Go-only managed custom-config
+	// schemas (migration 041).
+	SubscriptionConfigSchema
+	// Export backs the Go-only bulk export requests (migration 048).
+	Export
+	// PrivacyPurge backs the Go-only right-to-erasure purges (migration 052).
+	PrivacyPurge
+	// IngestSource and IngestDelivery back the Go-only inbound webhook
+	// gateway (migration 056).
+	IngestSource
+	IngestDelivery
+	// DispatchJobSavedFilter backs the Go-only saved dispatch-job search
+	// filters (migration 060).
+	DispatchJobSavedFilter
+	// OAuthScope backs the Go-only consent-screen scope definitions
+	// (migration 073).
+	OAuthScope
+	// SubscriptionApproval backs the Go-only subscription approval
+	// decisions (migration 076).
+	SubscriptionApproval
 )
 
 // Prefix returns the 3-character prefix for this entity type. Mirrors
@@ -162,6 +182,22 @@ func (e EntityType) Prefix() string {
 		return "mtd"
 	case ResetApprovalRequest:
 		return "rar"
+	case SubscriptionConfigSchema:
+		return "scs"
+	case Export:
+		return "exp"
+	case PrivacyPurge:
+		return "ppg"
+	case IngestSource:
+		return "isr"
+	case IngestDelivery:
+		return "idv"
+	case DispatchJobSavedFilter:
+		return "dsf"
+	case OAuthScope:
+		return "osc"
+	case SubscriptionApproval:
+		return "sap"
 	default:
 		return "unk"
 	}
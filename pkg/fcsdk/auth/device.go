@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeviceGrantType is the RFC 8628 grant_type for /oauth/token.
+const DeviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthorizationResponse is the body of /oauth/device_authorization
+// (RFC 8628 §3.2). Show the user VerificationURI and UserCode (or
+// VerificationURIComplete), then poll with PollDeviceToken.
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval,omitempty"`
+}
+
+// StartDeviceAuthorization begins the device authorization grant for a
+// client without a browser (CLIs, headless tools). RedirectURI is unused.
+func (c *OAuthClient) StartDeviceAuthorization(ctx context.Context) (*DeviceAuthorizationResponse, error) {
+	form := url.Values{}
+	form.Set("client_id", c.cfg.ClientID)
+	form.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	if c.cfg.ClientSecret != "" {
+		form.Set("client_secret", c.cfg.ClientSecret)
+	}
+	resp, err := c.postForm(ctx, c.cfg.IssuerURL+"/oauth/device_authorization", form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newErr(KindTokenExchange, fmt.Sprintf("device authorization failed (%d): %s", resp.StatusCode, body))
+	}
+	var out DeviceAuthorizationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, newErr(KindTokenExchange, "parse device authorization response: "+err.Error())
+	}
+	return &out, nil
+}
+
+// PollDeviceToken polls /oauth/token until the user approves or denies
+// the device authorization, it expires, or ctx is done. It waits the
+// server's interval between polls and backs off by 5s on slow_down
+// (RFC 8628 §3.5).
+func (c *OAuthClient) PollDeviceToken(ctx context.Context, da *DeviceAuthorizationResponse) (*TokenResponse, error) {
+	interval := time.Duration(da.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	form := url.Values{}
+	form.Set("grant_type", DeviceGrantType)
+	form.Set("device_code", da.DeviceCode)
+	form.Set("client_id", c.cfg.ClientID)
+	if c.cfg.ClientSecret != "" {
+		form.Set("client_secret", c.cfg.ClientSecret)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		resp, err := c.postForm(ctx, c.cfg.IssuerURL+"/oauth/token", form)
+		if err != nil {
+			return nil, err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			var out TokenResponse
+			if err := json.Unmarshal(body, &out); err != nil {
+				return nil, newErr(KindTokenExchange, "parse token response: "+err.Error())
+			}
+			return &out, nil
+		}
+		var oerr struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &oerr)
+		switch oerr.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, newErr(KindTokenExchange, "device authorization was denied")
+		case "expired_token":
+			return nil, newErr(KindTokenExchange, "device code expired before it was approved")
+		default:
+			return nil, newErr(KindTokenExchange, fmt.Sprintf("token exchange failed (%d): %s", resp.StatusCode, body))
+		}
+	}
+}
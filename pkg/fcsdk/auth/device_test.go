@@ -0,0 +1,67 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flowcatalyst/flowcatalyst-go/pkg/fcsdk/auth"
+)
+
+// The client starts a device authorization, then keeps polling through
+// authorization_pending until the token is issued.
+func TestDeviceFlowPollsUntilApproved(t *testing.T) {
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/device_authorization":
+			assert.Equal(t, "fcctl", r.PostForm.Get("client_id"))
+			assert.Equal(t, "openid offline_access", r.PostForm.Get("scope"))
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"BCDF-GHJK","verification_uri":"https://fc/oauth/device","expires_in":600,"interval":1}`))
+		case "/oauth/token":
+			assert.Equal(t, auth.DeviceGrantType, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "dc", r.PostForm.Get("device_code"))
+			if polls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"at","token_type":"Bearer","expires_in":3600,"refresh_token":"rt"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := auth.NewOAuthClient(auth.OAuthConfig{IssuerURL: srv.URL, ClientID: "fcctl", Scopes: []string{"openid", "offline_access"}})
+	da, err := c.StartDeviceAuthorization(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "BCDF-GHJK", da.UserCode)
+
+	tok, err := c.PollDeviceToken(context.Background(), da)
+	require.NoError(t, err)
+	assert.Equal(t, "at", tok.AccessToken)
+	assert.Equal(t, "rt", tok.RefreshToken)
+	assert.Equal(t, int32(2), polls.Load())
+}
+
+func TestDeviceFlowDenied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"access_denied"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := auth.NewOAuthClient(auth.OAuthConfig{IssuerURL: srv.URL, ClientID: "fcctl"})
+	_, err := c.PollDeviceToken(context.Background(), &auth.DeviceAuthorizationResponse{DeviceCode: "dc", Interval: 1})
+	require.ErrorIs(t, err, auth.ErrTokenExchange)
+	assert.Contains(t, err.Error(), "denied")
+}
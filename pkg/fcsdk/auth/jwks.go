@@ -90,6 +90,46 @@ func (v *TokenValidator) Validate(ctx context.Context, token string) (*AuthConte
 	return NewAuthContext(claims, token), nil
 }
 
+// BackchannelLogoutEvent is the events member of a Back-Channel Logout
+// token.
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// ValidateLogoutToken verifies an OIDC Back-Channel Logout token posted
+// to your backchannel_logout_uri (as the logout_token form field) and
+// returns the principal id whose sessions you should end. clientID is
+// your OAuth client_id — the token's audience, which differs from the
+// access-token Audience this validator was built with.
+func (v *TokenValidator) ValidateLogoutToken(ctx context.Context, token, clientID string) (string, error) {
+	if err := v.ensureRegistered(ctx); err != nil {
+		return "", err
+	}
+	set, err := v.cache.Get(ctx, v.jwksURL)
+	if err != nil {
+		return "", newErr(KindDiscovery, "fetch JWKS: "+err.Error())
+	}
+	tok, err := jwt.ParseString(
+		token,
+		jwt.WithKeySet(set, jws.WithRequireKid(false)),
+		jwt.WithIssuer(v.cfg.IssuerURL),
+		jwt.WithAudience(clientID),
+		jwt.WithAcceptableSkew(v.cfg.ClockSkew),
+	)
+	if err != nil {
+		return "", mapJWXError(err)
+	}
+	events, _ := tok.PrivateClaims()["events"].(map[string]any)
+	if _, ok := events[BackchannelLogoutEvent]; !ok {
+		return "", newErr(KindInvalidToken, "not a logout token: missing back-channel logout event")
+	}
+	if _, ok := tok.PrivateClaims()["nonce"]; ok {
+		return "", newErr(KindInvalidToken, "logout token must not carry a nonce")
+	}
+	if tok.Subject() == "" {
+		return "", newErr(KindInvalidToken, "logout token has no sub")
+	}
+	return tok.Subject(), nil
+}
+
 // ValidateBearer strips the "Bearer " prefix and calls Validate.
 func (v *TokenValidator) ValidateBearer(ctx context.Context, authHeader string) (*AuthContext, error) {
 	token, err := stripBearer(authHeader)
@@ -194,3 +194,25 @@ func TestTokenValidatorSurfacesDiscoveryFailure(t *testing.T) {
 	_, err := v.Validate(context.Background(), "any.token.here")
 	require.Error(t, err)
 }
+
+func TestValidateLogoutToken(t *testing.T) {
+	f := newOIDCFixture(t)
+	v := auth.NewTokenValidator(auth.TokenValidatorConfig{IssuerURL: f.issuerURL})
+	events := map[string]any{"events": map[string]any{auth.BackchannelLogoutEvent: map[string]any{}}}
+	exp := time.Now().Add(2 * time.Minute)
+
+	sub, err := v.ValidateLogoutToken(context.Background(), f.mint(t, f.issuerURL, "my-app", "prn_42", exp, events), "my-app")
+	require.NoError(t, err)
+	assert.Equal(t, "prn_42", sub)
+
+	// An access or ID token is not a logout token.
+	_, err = v.ValidateLogoutToken(context.Background(), f.mint(t, f.issuerURL, "my-app", "prn_42", exp, nil), "my-app")
+	require.Error(t, err)
+	// Addressed to another client.
+	_, err = v.ValidateLogoutToken(context.Background(), f.mint(t, f.issuerURL, "other-app", "prn_42", exp, events), "my-app")
+	require.Error(t, err)
+	// A nonce means an ID token dressed up as a logout token.
+	withNonce := map[string]any{"events": events["events"], "nonce": "n1"}
+	_, err = v.ValidateLogoutToken(context.Background(), f.mint(t, f.issuerURL, "my-app", "prn_42", exp, withNonce), "my-app")
+	require.Error(t, err)
+}
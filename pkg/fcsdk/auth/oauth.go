@@ -170,13 +170,20 @@ func (c *OAuthClient) IntrospectToken(ctx context.Context, token string) (*Intro
 	return &out, nil
 }
 
-// UserInfoResponse is the body of /oauth/userinfo. Extra carries any
-// custom claims the platform adds.
+// RolesClaim is the namespaced userinfo claim carrying the principal's
+// role names.
+const RolesClaim = "https://flowcatalyst.io/roles"
+
+// UserInfoResponse is the body of /oauth/userinfo. UpdatedAt is epoch
+// seconds; Roles is read from RolesClaim. Extra carries any other custom
+// claims the platform adds.
 type UserInfoResponse struct {
 	Sub           string         `json:"sub"`
 	Name          string         `json:"name,omitempty"`
 	Email         string         `json:"email,omitempty"`
 	EmailVerified *bool          `json:"email_verified,omitempty"`
+	UpdatedAt     int64          `json:"updated_at,omitempty"`
+	Roles         []string       `json:"-"`
 	Extra         map[string]any `json:"-"`
 }
 
@@ -206,6 +213,12 @@ func (u *UserInfoResponse) UnmarshalJSON(b []byte) error {
 	if err := get("email_verified", &u.EmailVerified); err != nil {
 		return err
 	}
+	if err := get("updated_at", &u.UpdatedAt); err != nil {
+		return err
+	}
+	if err := get(RolesClaim, &u.Roles); err != nil {
+		return err
+	}
 	if len(raw) > 0 {
 		u.Extra = make(map[string]any, len(raw))
 		for k, v := range raw {
@@ -251,7 +264,7 @@ func (c *OAuthClient) UserInfo(ctx context.Context, accessToken string) (*UserIn
 // Initiated Logout 1.0 §2). Omitting the hint causes the OP to refuse
 // the redirect.
 func (c *OAuthClient) LogoutURL(postLogoutRedirectURI, idTokenHint, state string) string {
-	base := c.cfg.IssuerURL + "/auth/oidc/session/end"
+	base := c.cfg.IssuerURL + "/oauth/logout"
 	q := url.Values{}
 	if postLogoutRedirectURI != "" {
 		q.Set("post_logout_redirect_uri", postLogoutRedirectURI)
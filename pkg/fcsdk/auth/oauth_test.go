@@ -86,6 +86,7 @@ func newOAuthMockServer(t *testing.T) (*httptest.Server, *struct {
 		assert.Equal(t, "Bearer at_abc", r.Header.Get("Authorization"))
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"sub": "prn_42", "email": "u@example.com", "email_verified": true,
+			"updated_at": 1700000000, auth.RolesClaim: []string{"orders:admin"},
 			"custom": "value", "org_id": 99,
 		})
 	})
@@ -159,6 +160,9 @@ func TestOAuthUserInfoExposesExtraClaims(t *testing.T) {
 	assert.Equal(t, "u@example.com", info.Email)
 	require.NotNil(t, info.EmailVerified)
 	assert.True(t, *info.EmailVerified)
+	assert.EqualValues(t, 1700000000, info.UpdatedAt)
+	assert.Equal(t, []string{"orders:admin"}, info.Roles)
+	assert.NotContains(t, info.Extra, auth.RolesClaim)
 	assert.Equal(t, "value", info.Extra["custom"])
 	assert.EqualValues(t, 99, info.Extra["org_id"])
 }
@@ -168,14 +172,14 @@ func TestOAuthLogoutURL(t *testing.T) {
 		IssuerURL: "https://auth.example.com/", ClientID: "app",
 	})
 	// Bare URL — no query string.
-	assert.Equal(t, "https://auth.example.com/auth/oidc/session/end",
+	assert.Equal(t, "https://auth.example.com/oauth/logout",
 		c.LogoutURL("", "", ""))
 
 	// All params present.
 	urlStr := c.LogoutURL("https://app.example.com", "eyJ.hint.sig", "s1")
 	u, err := url.Parse(urlStr)
 	require.NoError(t, err)
-	assert.Equal(t, "/auth/oidc/session/end", u.Path)
+	assert.Equal(t, "/oauth/logout", u.Path)
 	q := u.Query()
 	assert.Equal(t, "https://app.example.com", q.Get("post_logout_redirect_uri"))
 	assert.Equal(t, "eyJ.hint.sig", q.Get("id_token_hint"))
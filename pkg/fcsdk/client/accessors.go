@@ -19,6 +19,11 @@ func (c *FlowCatalystClient) DispatchPools() *DispatchPoolsResource {
 	return &DispatchPoolsResource{c: c}
 }
 
+// DispatchJobs returns the dispatch-jobs accessor — /api/dispatch-jobs/*.
+func (c *FlowCatalystClient) DispatchJobs() *DispatchJobsResource {
+	return &DispatchJobsResource{c: c}
+}
+
 // Applications returns the applications resource accessor — /api/applications/*.
 func (c *FlowCatalystClient) Applications() *ApplicationsResource {
 	return &ApplicationsResource{c: c}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"strconv"
+)
+
+// DispatchJobFilters — query parameters for GET /api/dispatch-jobs.
+// Since/Until are RFC 3339 and bound the job's creation time.
+type DispatchJobFilters struct {
+	Status         string
+	ClientID       string
+	DispatchPoolID string
+	SubscriptionID string
+	Code           string
+	Since          string
+	Until          string
+	// Limit caps rows (server default 50, max 1000).
+	Limit  int
+	Offset int
+}
+
+// ─── Response DTOs ───────────────────────────────────────────────────
+
+// DispatchJobResponse is a dispatch job's read-projection view.
+type DispatchJobResponse struct {
+	ID               string `json:"id"`
+	EventID          string `json:"eventId,omitempty"`
+	SubscriptionID   string `json:"subscriptionId,omitempty"`
+	ClientID         string `json:"clientId,omitempty"`
+	ClientIdentifier string `json:"clientIdentifier,omitempty"`
+	Code             string `json:"code"`
+	Subject          string `json:"subject,omitempty"`
+	Status           string `json:"status"`
+	Kind             string `json:"kind"`
+	TargetURL        string `json:"targetUrl"`
+	Mode             string `json:"mode"`
+	CorrelationID    string `json:"correlationId,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+	UpdatedAt        string `json:"updatedAt"`
+	CompletedAt      string `json:"completedAt,omitempty"`
+	LastAttemptAt    string `json:"lastAttemptAt,omitempty"`
+	AttemptCount     int32  `json:"attemptCount"`
+}
+
+// RequeueDispatchJobsRequest — body for POST /api/dispatch-jobs/requeue.
+type RequeueDispatchJobsRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// RequeueDispatchJobsResponse — number of jobs reset to PENDING.
+type RequeueDispatchJobsResponse struct {
+	Requeued int64 `json:"requeued"`
+}
+
+// ─── Resource ────────────────────────────────────────────────────────
+
+// DispatchJobsResource — /api/dispatch-jobs/*.
+type DispatchJobsResource struct {
+	c *FlowCatalystClient
+}
+
+// List — GET /api/dispatch-jobs. Newest first.
+func (r *DispatchJobsResource) List(ctx context.Context, filters *DispatchJobFilters) ([]DispatchJobResponse, error) {
+	q := ""
+	if filters != nil {
+		qb := NewQuery().
+			String("status", filters.Status).
+			String("clientId", filters.ClientID).
+			String("dispatchPoolId", filters.DispatchPoolID).
+			String("subscriptionId", filters.SubscriptionID).
+			String("code", filters.Code).
+			String("since", filters.Since).
+			String("until", filters.Until)
+		if filters.Limit > 0 {
+			qb.String("limit", strconv.Itoa(filters.Limit))
+		}
+		if filters.Offset > 0 {
+			qb.String("offset", strconv.Itoa(filters.Offset))
+		}
+		q = qb.Encode()
+	}
+	var out []DispatchJobResponse
+	if err := r.c.Get(ctx, "/api/dispatch-jobs"+q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get — GET /api/dispatch-jobs/{id}.
+func (r *DispatchJobsResource) Get(ctx context.Context, id string) (*DispatchJobResponse, error) {
+	var out DispatchJobResponse
+	if err := r.c.Get(ctx, "/api/dispatch-jobs/"+id, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Requeue — POST /api/dispatch-jobs/requeue. Resets the given jobs to
+// PENDING for re-dispatch; ids outside the caller's client scope are
+// skipped, so Requeued may be less than len(ids).
+func (r *DispatchJobsResource) Requeue(ctx context.Context, ids []string) (*RequeueDispatchJobsResponse, error) {
+	var out RequeueDispatchJobsResponse
+	if err := r.c.Post(ctx, "/api/dispatch-jobs/requeue", &RequeueDispatchJobsRequest{IDs: ids}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -345,3 +345,48 @@ func TestRolesListForApplication(t *testing.T) {
 	require.Len(t, r.Roles, 1)
 	assert.Equal(t, "orders:admin", r.Roles[0].Name)
 }
+
+func TestDispatchJobsListBuildsQuery(t *testing.T) {
+	srv, seen := newMockSrv(t, `[{"id":"dj_1","code":"orders:sales:order:created","status":"FAILED","kind":"EVENT","targetUrl":"https://x","mode":"IMMEDIATE","createdAt":"2026-01-01T00:00:00Z","updatedAt":"2026-01-01T00:01:00Z","attemptCount":5}]`)
+	c := client.New(srv.URL)
+
+	jobs, err := c.DispatchJobs().List(context.Background(), &client.DispatchJobFilters{
+		Status:         "FAILED",
+		SubscriptionID: "sub_1",
+		Limit:          1000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/dispatch-jobs", seen.path)
+	assert.Equal(t, "FAILED", seen.query.Get("status"))
+	assert.Equal(t, "sub_1", seen.query.Get("subscriptionId"))
+	assert.Equal(t, "1000", seen.query.Get("limit"))
+	assert.False(t, seen.query.Has("offset"))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, int32(5), jobs[0].AttemptCount)
+}
+
+func TestDispatchJobsRequeuePostsIDs(t *testing.T) {
+	srv, seen := newMockSrv(t, `{"requeued":2}`)
+	c := client.New(srv.URL)
+
+	res, err := c.DispatchJobs().Requeue(context.Background(), []string{"dj_1", "dj_2"})
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, seen.method)
+	assert.Equal(t, "/api/dispatch-jobs/requeue", seen.path)
+	var body map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(seen.body), &body))
+	assert.Equal(t, []string{"dj_1", "dj_2"}, body["ids"])
+	assert.Equal(t, int64(2), res.Requeued)
+}
+
+func TestRouterRebuildProjection(t *testing.T) {
+	srv, seen := newMockSrv(t, `{"projection":"event_projection","requeued":42}`)
+	c := client.New("http://unused", client.WithRouterBaseURL(srv.URL))
+
+	res, err := c.Router().RebuildProjection(context.Background(), "event_projection")
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, seen.method)
+	assert.Equal(t, "/stream/rebuild", seen.path)
+	assert.Equal(t, "event_projection", seen.query.Get("projection"))
+	assert.Equal(t, int64(42), res.Requeued)
+}
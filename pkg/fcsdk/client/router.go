@@ -62,3 +62,22 @@ func (r *RouterResource) InPipelineBatch(ctx context.Context, messageIDs []strin
 	}
 	return out, nil
 }
+
+// StreamRebuildResponse — POST /stream/rebuild. Requeued is the number of
+// source rows the projector will replay.
+type StreamRebuildResponse struct {
+	Projection string `json:"projection"`
+	Requeued   int64  `json:"requeued"`
+}
+
+// RebuildProjection — POST /stream/rebuild?projection=...
+// Clears the projection's read table and re-queues every source row.
+// Requires the stream processor to be co-tenanted with the router.
+func (r *RouterResource) RebuildProjection(ctx context.Context, projection string) (*StreamRebuildResponse, error) {
+	q := NewQuery().String("projection", projection).Encode()
+	var out StreamRebuildResponse
+	if err := r.c.postRouter(ctx, "/stream/rebuild"+q, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
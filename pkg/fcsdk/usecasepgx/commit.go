@@ -10,6 +10,8 @@ import (
 
 // Commit upserts the aggregate via its repository, writes the domain
 // event and audit log via the configured Sink — all in one transaction.
+// When the sink is a ChangeAuditor the audit row also records the
+// aggregate's before-image (through a Finder repo) and after-image.
 //
 // This is one of the few public paths to a Success-valued
 // usecase.Result outside the SDK. The seal on usecase.Success is
@@ -22,6 +24,10 @@ func Commit[A usecase.HasID, E usecase.DomainEvent, C any](
 	event E,
 	command C,
 ) usecase.Result[E] {
+	change, err := changeFor(ctx, uow.sink, repo, aggregate)
+	if err != nil {
+		return usecase.Failure[E](usecase.Internal("AUDIT_SNAPSHOT", "could not load aggregate before-image", err))
+	}
 	tx, err := uow.pool.Begin(ctx)
 	if err != nil {
 		return usecase.Failure[E](usecase.Internal("TX_BEGIN", "could not open transaction", err))
@@ -35,7 +41,7 @@ func Commit[A usecase.HasID, E usecase.DomainEvent, C any](
 	if err := uow.sink.WriteEvent(ctx, dbTx, event); err != nil {
 		return usecase.Failure[E](usecase.Internal("EVENT_WRITE", "could not write domain event", err))
 	}
-	if err := uow.sink.WriteAudit(ctx, dbTx, event, command); err != nil {
+	if err := writeAudit(ctx, uow.sink, dbTx, event, command, change); err != nil {
 		return usecase.Failure[E](usecase.Internal("AUDIT_WRITE", "could not write audit log", err))
 	}
 	if err := tx.Commit(ctx); err != nil {
@@ -68,7 +74,7 @@ func CommitDelete[A usecase.HasID, E usecase.DomainEvent, C any](
 	if err := uow.sink.WriteEvent(ctx, dbTx, event); err != nil {
 		return usecase.Failure[E](usecase.Internal("EVENT_WRITE", "could not write domain event", err))
 	}
-	if err := uow.sink.WriteAudit(ctx, dbTx, event, command); err != nil {
+	if err := writeAudit(ctx, uow.sink, dbTx, event, command, deleteChange(uow.sink, aggregate)); err != nil {
 		return usecase.Failure[E](usecase.Internal("AUDIT_WRITE", "could not write audit log", err))
 	}
 	if err := tx.Commit(ctx); err != nil {
@@ -174,7 +180,8 @@ type SyncDeleteItem[A usecase.HasID] struct {
 // order, so downstream readers see per-row events before the rollup.
 //
 // All audit rows record the outer `command` (typically the bulk sync
-// command). Per-row context lives in the event payloads themselves.
+// command). Per-row context lives in the event payloads themselves, and
+// in each per-row audit row's Change when the sink records changes.
 func CommitSync[A usecase.HasID, RE usecase.DomainEvent, C any](
 	ctx context.Context,
 	uow *UnitOfWork,
@@ -193,13 +200,17 @@ func CommitSync[A usecase.HasID, RE usecase.DomainEvent, C any](
 	dbTx := newDbTx(tx)
 
 	for i := range saves {
+		change, err := changeFor(ctx, uow.sink, repo, saves[i].Aggregate)
+		if err != nil {
+			return usecase.Failure[RE](usecase.Internal("AUDIT_SNAPSHOT", fmt.Sprintf("before-image load failed at index %d", i), err))
+		}
 		if err := repo.Persist(ctx, saves[i].Aggregate, dbTx); err != nil {
 			return usecase.Failure[RE](usecase.Internal("PERSIST_BATCH", fmt.Sprintf("sync save failed at index %d", i), err))
 		}
 		if err := uow.sink.WriteEvent(ctx, dbTx, saves[i].Event); err != nil {
 			return usecase.Failure[RE](usecase.Internal("EVENT_WRITE", fmt.Sprintf("per-row save event write failed at index %d", i), err))
 		}
-		if err := uow.sink.WriteAudit(ctx, dbTx, saves[i].Event, command); err != nil {
+		if err := writeAudit(ctx, uow.sink, dbTx, saves[i].Event, command, change); err != nil {
 			return usecase.Failure[RE](usecase.Internal("AUDIT_WRITE", fmt.Sprintf("per-row save audit write failed at index %d", i), err))
 		}
 	}
@@ -211,7 +222,7 @@ func CommitSync[A usecase.HasID, RE usecase.DomainEvent, C any](
 		if err := uow.sink.WriteEvent(ctx, dbTx, deletes[i].Event); err != nil {
 			return usecase.Failure[RE](usecase.Internal("EVENT_WRITE", fmt.Sprintf("per-row delete event write failed at index %d", i), err))
 		}
-		if err := uow.sink.WriteAudit(ctx, dbTx, deletes[i].Event, command); err != nil {
+		if err := writeAudit(ctx, uow.sink, dbTx, deletes[i].Event, command, deleteChange(uow.sink, deletes[i].Aggregate)); err != nil {
 			return usecase.Failure[RE](usecase.Internal("AUDIT_WRITE", fmt.Sprintf("per-row delete audit write failed at index %d", i), err))
 		}
 	}
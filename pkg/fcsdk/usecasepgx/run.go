@@ -112,7 +112,9 @@ func RunErr(
 
 // CommitScoped is the Commit equivalent for a TxScopedUnitOfWork. It
 // appends the aggregate write + event + audit to the open transaction
-// but does NOT commit; the surrounding Run does.
+// but does NOT commit; the surrounding Run does. The before-image is read
+// outside the transaction, so an earlier write to the same aggregate in
+// this Run is not reflected in it.
 func CommitScoped[A usecase.HasID, E usecase.DomainEvent, C any](
 	ctx context.Context,
 	scoped *TxScopedUnitOfWork,
@@ -121,6 +123,10 @@ func CommitScoped[A usecase.HasID, E usecase.DomainEvent, C any](
 	event E,
 	command C,
 ) usecase.Result[E] {
+	change, err := changeFor(ctx, scoped.sink, repo, aggregate)
+	if err != nil {
+		return usecase.Failure[E](usecase.Internal("AUDIT_SNAPSHOT", "could not load aggregate before-image", err))
+	}
 	dbTx := newDbTx(scoped.tx)
 	if err := repo.Persist(ctx, aggregate, dbTx); err != nil {
 		return usecase.Failure[E](usecase.Internal("PERSIST", "repository persist failed", err))
@@ -128,7 +134,7 @@ func CommitScoped[A usecase.HasID, E usecase.DomainEvent, C any](
 	if err := scoped.sink.WriteEvent(ctx, dbTx, event); err != nil {
 		return usecase.Failure[E](usecase.Internal("EVENT_WRITE", "could not write domain event", err))
 	}
-	if err := scoped.sink.WriteAudit(ctx, dbTx, event, command); err != nil {
+	if err := writeAudit(ctx, scoped.sink, dbTx, event, command, change); err != nil {
 		return usecase.Failure[E](usecase.Internal("AUDIT_WRITE", "could not write audit log", err))
 	}
 	return usecase.Success[E](sealed.New(), event)
@@ -150,7 +156,7 @@ func CommitDeleteScoped[A usecase.HasID, E usecase.DomainEvent, C any](
 	if err := scoped.sink.WriteEvent(ctx, dbTx, event); err != nil {
 		return usecase.Failure[E](usecase.Internal("EVENT_WRITE", "could not write domain event", err))
 	}
-	if err := scoped.sink.WriteAudit(ctx, dbTx, event, command); err != nil {
+	if err := writeAudit(ctx, scoped.sink, dbTx, event, command, deleteChange(scoped.sink, aggregate)); err != nil {
 		return usecase.Failure[E](usecase.Internal("AUDIT_WRITE", "could not write audit log", err))
 	}
 	return usecase.Success[E](sealed.New(), event)
@@ -27,3 +27,66 @@ type Sink interface {
 	// logging is disabled.
 	WriteAudit(ctx context.Context, tx *DbTx, event usecase.DomainEvent, command any) error
 }
+
+// Change is the before/after pair of an audited aggregate write. Before is
+// nil for a create, After is nil for a delete. Both hold the aggregate
+// pointer as committed; the sink decides how to serialize and compare them.
+type Change struct {
+	Before any
+	After  any
+}
+
+// ChangeAuditor is an optional Sink extension for sinks that record what
+// an aggregate write changed, not just the command that caused it. When
+// the configured sink implements it, the aggregate commits (Commit,
+// CommitDelete, their Scoped variants and the per-row writes of
+// CommitSync) call WriteAuditChange in place of WriteAudit. CommitAll's
+// single summary row and EmitEvent carry no change.
+type ChangeAuditor interface {
+	WriteAuditChange(ctx context.Context, tx *DbTx, event usecase.DomainEvent, command any, change Change) error
+}
+
+// Finder is the optional read side of a Persist. A repository that can
+// load an aggregate by ID gets its before-image captured for the
+// ChangeAuditor; without it an update is recorded with After only.
+type Finder[A usecase.HasID] interface {
+	FindByID(ctx context.Context, id string) (*A, error)
+}
+
+// changeFor builds the Change for a save of agg, loading the stored
+// version through repo when it is a Finder. nil when the sink does not
+// record changes, so no read is spent on it.
+func changeFor[A usecase.HasID](ctx context.Context, sink Sink, repo Persist[A], agg *A) (*Change, error) {
+	if _, ok := sink.(ChangeAuditor); !ok {
+		return nil, nil
+	}
+	change := &Change{After: agg}
+	if f, ok := repo.(Finder[A]); ok {
+		before, err := f.FindByID(ctx, (*agg).IDStr())
+		if err != nil {
+			return nil, err
+		}
+		if before != nil {
+			change.Before = before
+		}
+	}
+	return change, nil
+}
+
+// deleteChange is the Change for a delete of agg.
+func deleteChange[A usecase.HasID](sink Sink, agg *A) *Change {
+	if _, ok := sink.(ChangeAuditor); !ok {
+		return nil
+	}
+	return &Change{Before: agg}
+}
+
+// writeAudit routes to WriteAuditChange when there is a change to record.
+func writeAudit(ctx context.Context, sink Sink, tx *DbTx, event usecase.DomainEvent, command any, change *Change) error {
+	if change != nil {
+		if ca, ok := sink.(ChangeAuditor); ok {
+			return ca.WriteAuditChange(ctx, tx, event, command, *change)
+		}
+	}
+	return sink.WriteAudit(ctx, tx, event, command)
+}
@@ -13,31 +13,39 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// TestOpenAPISpecLocked snapshots the huma-generated OpenAPI spec against
-// the committed api/openapi.lock.json. Run `make api-bump` to refresh
-// the lockfile when you intentionally change the wire shape.
+// TestOpenAPISpecLocked snapshots each huma-generated OpenAPI spec against
+// its committed lockfile under api/. Run `make api-bump` to refresh the
+// lockfiles when you intentionally change the wire shape.
 //
 // The CI parity-spec job also runs `make api-diff` for a textual diff
 // of the same comparison.
 func TestOpenAPISpecLocked(t *testing.T) {
 	repoRoot := findRepoRoot(t)
-	lockPath := filepath.Join(repoRoot, "api", "openapi.lock.json")
+	for _, c := range []struct{ api, lockfile string }{
+		{"platform", "openapi.lock.json"},
+		{"router", "router-openapi.lock.json"},
+		{"outbox", "outbox-openapi.lock.json"},
+	} {
+		t.Run(c.api, func(t *testing.T) {
+			lockPath := filepath.Join(repoRoot, "api", c.lockfile)
 
-	want, err := os.ReadFile(lockPath)
-	require.NoError(t, err, "read lockfile")
+			want, err := os.ReadFile(lockPath)
+			require.NoError(t, err, "read lockfile")
 
-	out, err := exec.Command("go", "run", filepath.Join(repoRoot, "tools", "dump-spec")).Output()
-	require.NoError(t, err, "dump-spec")
+			out, err := exec.Command("go", "run", filepath.Join(repoRoot, "tools", "dump-spec"), "-api", c.api).Output()
+			require.NoError(t, err, "dump-spec")
 
-	// Both are JSON; normalise whitespace before compare so a stray
-	// newline doesn't fail the snapshot.
-	wantNorm := normaliseJSON(t, want)
-	gotNorm := normaliseJSON(t, out)
-	if !bytes.Equal(wantNorm, gotNorm) {
-		t.Fatalf("openapi spec drift — committed lockfile no longer matches code.\n"+
-			"Run `make api-bump` and commit the diff (after verifying it's intentional).\n"+
-			"Lockfile:  %s\nGenerated: <stdout from `go run ./tools/dump-spec`>",
-			lockPath)
+			// Both are JSON; normalise whitespace before compare so a stray
+			// newline doesn't fail the snapshot.
+			wantNorm := normaliseJSON(t, want)
+			gotNorm := normaliseJSON(t, out)
+			if !bytes.Equal(wantNorm, gotNorm) {
+				t.Fatalf("openapi spec drift — committed lockfile no longer matches code.\n"+
+					"Run `make api-bump` and commit the diff (after verifying it's intentional).\n"+
+					"Lockfile:  %s\nGenerated: <stdout from `go run ./tools/dump-spec -api %s`>",
+					lockPath, c.api)
+			}
+		})
 	}
 }
 
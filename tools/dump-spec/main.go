@@ -1,7 +1,12 @@
-// dump-spec emits the platform's huma-generated OpenAPI spec to stdout
-// without booting a database. Used by `make api-bump` to refresh
-// api/openapi.lock.json and by the CI parity-spec job to diff against
-// the committed lockfile.
+// dump-spec emits a huma-generated OpenAPI spec to stdout without booting
+// a database. Used by `make api-bump` to refresh the lockfiles under api/
+// and by the CI parity-spec job to diff against them. -api picks the
+// surface:
+//
+//	platform  the platform API         → api/openapi.lock.json (default)
+//	router    the router's monitoring  → api/router-openapi.lock.json
+//	          and admin API, including the stream status endpoints
+//	outbox    the outbox admin API     → api/outbox-openapi.lock.json
 //
 // Routes are registered against nil-dep state objects — the spec
 // generator inspects the Input/Output struct types, not the handlers
@@ -10,6 +15,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 
@@ -17,6 +23,7 @@ import (
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/go-chi/chi/v5"
 
+	"github.com/flowcatalyst/flowcatalyst-go/internal/outbox"
 	applicationapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/application/api"
 	auditapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/audit/api"
 	authapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/auth/api"
@@ -28,10 +35,15 @@ import (
 	emaildomainapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/emaildomainmapping/api"
 	eventapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/event/api"
 	eventtypeapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/eventtype/api"
+	exportapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/export/api"
 	identityproviderapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/identityprovider/api"
+	ingestionapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/ingestion/api"
 	loginattemptapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/loginattempt/api"
+	maintenanceapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/maintenance/api"
+	meteringapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/metering/api"
 	platformconfigapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/platformconfig/api"
 	principalapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/principal/api"
+	privacyapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/privacy/api"
 	processapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/process/api"
 	resetapprovalapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/resetapproval/api"
 	roleapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/role/api"
@@ -41,11 +53,47 @@ import (
 	"github.com/flowcatalyst/flowcatalyst-go/internal/platform/shared/httpcompat"
 	subscriptionapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/subscription/api"
 	webauthnapi "github.com/flowcatalyst/flowcatalyst-go/internal/platform/webauthn/api"
+	routerapi "github.com/flowcatalyst/flowcatalyst-go/internal/router/api"
 )
 
 func main() {
+	which := flag.String("api", "platform", "spec to dump: platform, router or outbox")
+	flag.Parse()
+
+	// Process-wide (error model, non-nullable arrays): fc-server sets it
+	// before mounting any of the three surfaces.
 	httpcompat.Init()
 
+	var spec *huma.OpenAPI
+	switch *which {
+	case "platform":
+		spec = platformSpec()
+	case "router":
+		api := humachi.New(chi.NewMux(), routerapi.HumaConfig())
+		routerapi.Register(api, &routerapi.State{})
+		spec = api.OpenAPI()
+	case "outbox":
+		api := humachi.New(chi.NewMux(), outbox.AdminConfig())
+		outbox.RegisterAdmin(api, outbox.NewSources(outbox.Config{}, nil))
+		spec = api.OpenAPI()
+	default:
+		fmt.Fprintln(os.Stderr, "unknown -api:", *which)
+		os.Exit(2)
+	}
+
+	out, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "marshal spec:", err)
+		os.Exit(1)
+	}
+	if _, err := os.Stdout.Write(out); err != nil {
+		fmt.Fprintln(os.Stderr, "write spec:", err)
+		os.Exit(1)
+	}
+	fmt.Println()
+}
+
+func platformSpec() *huma.OpenAPI {
 	r := chi.NewMux()
 	api := humachi.New(r, huma.DefaultConfig("FlowCatalyst Platform API", "dev"))
 
@@ -57,6 +105,8 @@ func main() {
 	auditapi.Register(api, &auditapi.State{})
 	authapi.Register(api, &authapi.State{})
 	clientapi.Register(api, &clientapi.State{})
+	meteringapi.Register(api, &meteringapi.State{})
+	maintenanceapi.Register(api, &maintenanceapi.State{})
 	connectionapi.Register(api, &connectionapi.State{})
 	corsapi.Register(api, &corsapi.State{})
 	dispatchjobapi.Register(api, &dispatchjobapi.State{})
@@ -64,8 +114,11 @@ func main() {
 	emaildomainapi.Register(api, &emaildomainapi.State{})
 	eventapi.Register(api, &eventapi.State{})
 	eventtypeapi.Register(api, &eventtypeapi.State{})
+	exportapi.Register(api, &exportapi.State{})
 	identityproviderapi.Register(api, &identityproviderapi.State{})
+	ingestionapi.Register(api, &ingestionapi.State{})
 	platformconfigapi.Register(api, &platformconfigapi.State{})
+	privacyapi.Register(api, &privacyapi.State{})
 	principalapi.Register(api, &principalapi.State{})
 	processapi.Register(api, &processapi.State{})
 	resetapprovalapi.Register(api, &resetapprovalapi.State{})
@@ -79,6 +132,8 @@ func main() {
 	// omitted here, so they were missing from the committed lockfile.
 	loginattemptapi.Register(api, &loginattemptapi.State{})
 	sdksync.Register(api, &sdksync.State{})
+	sdksync.RegisterPermissions(api, &sdksync.State{})
+	sdksync.RegisterApply(api, &sdksync.State{})
 
 	// Accept-and-ignore unknown request-body fields (serde-style leniency).
 	// Keep in sync with WirePlatform so the lockfile matches the live spec.
@@ -88,15 +143,5 @@ func main() {
 	// mounted in the server). Keep in sync with WirePlatform.
 	httpcompat.StripBFFPaths(api)
 
-	spec := api.OpenAPI()
-	out, err := json.MarshalIndent(spec, "", "  ")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "marshal spec:", err)
-		os.Exit(1)
-	}
-	if _, err := os.Stdout.Write(out); err != nil {
-		fmt.Fprintln(os.Stderr, "write spec:", err)
-		os.Exit(1)
-	}
-	fmt.Println()
+	return api.OpenAPI()
 }